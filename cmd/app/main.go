@@ -3,32 +3,47 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
-	"api-core/config"
-	"api-core/internal/routes"
-	"api-core/internal/schedules"
-	"api-core/internal/wire"
-	"api-core/pkg/actionEvent"
-	"api-core/pkg/cache"
-	"api-core/pkg/cron"
-	"api-core/pkg/exception"
-	"api-core/pkg/fcm"
-	"api-core/pkg/i18n"
-	"api-core/pkg/logger"
-	middlewarePkg "api-core/pkg/middleware"
-	socketPkg "api-core/pkg/socket"
-	"api-core/pkg/utils"
-	"api-core/pkg/validator"
+	"github.com/anhnq996/go-api-core/internal/app/chat"
+	"github.com/anhnq996/go-api-core/internal/app/friend"
+	"github.com/anhnq996/go-api-core/internal/config"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/internal/outbox"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/internal/routes"
+	"github.com/anhnq996/go-api-core/internal/schedules"
+	"github.com/anhnq996/go-api-core/internal/wire"
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+	"github.com/anhnq996/go-api-core/pkg/exception"
+	"github.com/anhnq996/go-api-core/pkg/fcm"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+	middlewarePkg "github.com/anhnq996/go-api-core/pkg/middleware"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	socketPkg "github.com/anhnq996/go-api-core/pkg/socket"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/telemetry"
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/pkg/validator"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-redis/redis/v8"
 	"github.com/joho/godotenv"
 	"gorm.io/gorm"
 )
@@ -40,43 +55,161 @@ func main() {
 	// Initialize logger
 	initLogger()
 
-	logger.Info("Starting ApiCore application...")
+	// Process role: api | worker | scheduler | all
+	role, err := config.LoadAppRole()
+	if err != nil {
+		logger.Fatalf("Invalid app role: %v", err)
+	}
+
+	// Lệnh queue:work: chỉ chạy queue worker, có thể giới hạn queue và concurrency
+	var work *workOptions
+	if len(os.Args) > 1 {
+		if work, err = parseCommand(os.Args[1:]); err != nil {
+			logger.Fatalf("Invalid command: %v", err)
+		}
+		role = config.AppRoleWorker
+	}
+
+	logger.Infof("Starting ApiCore application (role: %s)...", role)
+
+	metricsConfig := config.LoadMetricsConfig()
+	if err := metricsConfig.Validate(); err != nil {
+		logger.Fatalf("Invalid metrics config: %v", err)
+	}
 
 	// Initialize i18n
 	initI18n()
 
-	// Initialize validation messages
-	initValidation()
-
 	// Initialize Loki events
 	initActionEvents()
 
-	// Connect to database
-	db := initDatabase()
+	// Initialize feature usage telemetry (opt-out via TELEMETRY_ENABLED=false)
+	initTelemetry()
+
+	// Initialize OpenTelemetry tracing (TRACING_ENABLED=true)
+	shutdownTracing := initTracing()
+
+	// Gửi panic, logger.Error và job lỗi tới Sentry (SENTRY_DSN)
+	initErrorReporting()
+
+	// Scheduler chỉ cần Redis lock, kết nối database khi ghi lịch sử chạy job (SCHEDULER_RUN_HISTORY)
+	// hoặc leader election bằng Postgres advisory lock
+	var db *gorm.DB
+	schedulerConfig := config.LoadSchedulerConfig()
+	schedulerNeedsDB := role.RunsScheduler() && (schedulerConfig.RunHistory || schedulerConfig.LeaderElection == config.LeaderElectionPostgres)
+	if role.RunsAPI() || role.RunsWorker() || schedulerNeedsDB {
+		// Connect to database
+		db = initDatabase()
+	}
+
+	// Runtime, DB pool, thời gian query và cache metrics (khi bật metrics)
+	initMetrics(metricsConfig, db)
 
-	// Connect to cache
-	cacheClient := initCache()
+	var server *http.Server
+	var socketHub *socketPkg.Hub
+	if role.RunsAPI() {
+		// Initialize validation messages
+		initValidation()
 
-	// Initialize dependencies
-	controllers := initDependencies(db, cacheClient)
+		// Connect to cache
+		cacheClient := initCache()
 
-	// Initialize schedule manager
-	scheduleManager := initScheduleManager()
+		// Initialize dependencies
+		controllers := initDependencies(db, cacheClient)
 
-	// Initialize socket hub
-	socketHub := initSocketHub()
+		// Initialize socket hub
+		socketHub = initSocketHub(db, cacheClient, controllers, metricsConfig)
 
-	// Initialize FCM client (only for test pages in development)
-	fcmClient := initFCM()
+		// Initialize FCM client (only for test pages in development)
+		fcmClient := initFCM()
 
-	// Setup router and routes
-	r := setupRouter(controllers, socketHub, fcmClient)
+		// Setup router and routes
+		r := setupRouter(controllers, socketHub, fcmClient, metricsConfig)
 
-	// Start schedule manager
-	startScheduleManager(scheduleManager)
+		// Start server
+		server = startServer(r)
 
-	// Start server
-	startServer(r)
+		// Health check định kỳ cho public status page
+		startStatusRecorder(controllers)
+
+		// Đồng bộ lifecycle rule (tự xóa file) của các storage bucket
+		go applyStorageLifecycles()
+	}
+
+	var workerManager *workers.WorkerManager
+	var outboxPublisher *outbox.Publisher
+	if role.RunsWorker() {
+		// Initialize and start queue workers
+		queueConfig, queueManager := initQueueManager()
+		workerManager = initWorkerManager(db, queueConfig, queueManager, metricsConfig, work)
+		startWorkerManager(workerManager)
+
+		// Publish message trong outbox sang queue backend
+		outboxPublisher = startOutboxPublisher(db, queueManager)
+	}
+
+	var scheduleManager *schedules.ScheduleManager
+	if role.RunsScheduler() {
+		// Initialize and start schedule manager
+		scheduleManager = initScheduleManager(db)
+		startScheduleManager(scheduleManager)
+	}
+
+	// Metrics listener riêng cho mọi role (cron jobs, queue lag)
+	metricsServer := startMetricsServer(metricsConfig)
+
+	// Chờ SIGINT/SIGTERM rồi dừng lần lượt các subsystem đã khởi tạo
+	waitForShutdown(server, socketHub, metricsServer, outboxPublisher, workerManager, scheduleManager)
+
+	// Flush span còn trong buffer sau khi request/job cuối cùng kết thúc
+	if err := shutdownTracing(context.Background()); err != nil {
+		logger.Errorf("Failed to flush traces: %v", err)
+	}
+
+	// Chờ gửi hết lỗi lên Sentry
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := errorreport.Flush(flushCtx); err != nil {
+		logger.Warnf("Failed to flush error reports: %v", err)
+	}
+
+	// Gửi nốt log còn trong buffer của CloudWatch/syslog
+	if err := logger.Close(flushCtx); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to flush log sinks: %v\n", err)
+	}
+}
+
+// workOptions tùy chọn của lệnh queue:work
+type workOptions struct {
+	queues      []string // Rỗng: tất cả queue đã đăng ký
+	concurrency int      // 0: theo WORKER_QUEUE_CONCURRENCY/WORKER_CONCURRENCY
+}
+
+// parseCommand parse lệnh CLI, hiện chỉ hỗ trợ:
+//
+//	app queue:work [-queues emails,default] [-concurrency 8]
+func parseCommand(args []string) (*workOptions, error) {
+	if args[0] != "queue:work" {
+		return nil, fmt.Errorf("unknown command %q (expected queue:work)", args[0])
+	}
+
+	fs := flag.NewFlagSet("queue:work", flag.ExitOnError)
+	queues := fs.String("queues", "", "Comma-separated queues to consume (default: all registered queues)")
+	concurrency := fs.Int("concurrency", 0, "Workers per queue (default: WORKER_QUEUE_CONCURRENCY / WORKER_CONCURRENCY)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return nil, err
+	}
+	if *concurrency < 0 {
+		return nil, fmt.Errorf("-concurrency must not be negative")
+	}
+
+	work := &workOptions{concurrency: *concurrency}
+	for _, name := range strings.Split(*queues, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			work.queues = append(work.queues, name)
+		}
+	}
+	return work, nil
 }
 
 // loadEnvironment loads environment variables from .env file
@@ -86,7 +219,7 @@ func loadEnvironment() {
 	}
 }
 
-// initLogger initializes the logger
+// initLogger initializes the logger, level từng module (LOG_LEVEL_<MODULE>) đổi được lúc chạy qua Redis
 func initLogger() {
 	// Load logger config từ environment variables
 	loggerConfig := config.LoadLoggerConfig()
@@ -100,6 +233,26 @@ func initLogger() {
 	if err := logger.Init(loggerConfig.ToLoggerConfig()); err != nil {
 		panic(err)
 	}
+
+	startLogLevelSync(loggerConfig)
+}
+
+// startLogLevelSync đồng bộ level đặt qua /api/v1/log-levels hoặc cmd/tools/loglevel cho mọi role,
+// không kết nối được Redis thì chỉ dùng level theo env
+func startLogLevelSync(loggerConfig *config.LoggerConfig) {
+	rdb, err := config.NewRedisClient(config.GetDefaultCacheConfig())
+	if err == nil {
+		err = rdb.Ping(context.Background()).Err()
+	}
+	if err != nil {
+		logger.Warnf("Failed to connect log level store to Redis: %v (runtime level changes disabled)", err)
+		if rdb != nil {
+			rdb.Close()
+		}
+		return
+	}
+
+	go logger.WatchLevels(context.Background(), logger.NewRedisLevelStore(rdb, ""), loggerConfig.LevelSyncInterval)
 }
 
 // initI18n initializes internationalization
@@ -140,6 +293,86 @@ func initActionEvents() {
 	logger.Info("Action events initialized successfully")
 }
 
+// initTelemetry initializes anonymized feature usage telemetry
+func initTelemetry() {
+	telemetryConfig := config.LoadTelemetryConfig()
+	if !telemetryConfig.Enabled {
+		logger.Info("Telemetry disabled (opt-out)")
+		return
+	}
+
+	collector := telemetry.NewCollector(telemetry.Config{
+		Environment:   telemetryConfig.Environment,
+		Version:       telemetryConfig.Version,
+		FlushInterval: telemetryConfig.FlushInterval,
+		Flags: map[string]bool{
+			"action_events": config.LoadActionEventConfig().Enabled,
+			"load_shedding": config.LoadLoadShedConfig().Enabled,
+			"rate_limit":    config.LoadRateLimitConfig().Enabled,
+			"storage_s3":    config.GetDefaultStorageConfig().Driver == "s3",
+			"social_google": config.LoadOAuthConfig().Google.Enabled(),
+			"social_github": config.LoadOAuthConfig().GitHub.Enabled(),
+		},
+	}, telemetry.NewLokiSink(telemetryConfig.LokiURL, telemetryConfig.Environment))
+
+	collector.Start(context.Background())
+	telemetry.Init(collector)
+	logger.Info("Telemetry initialized successfully")
+}
+
+// initErrorReporting initializes Sentry error reporting, tắt khi SENTRY_DSN rỗng
+func initErrorReporting() {
+	errorReportConfig := config.LoadErrorReportConfig()
+	if err := errorReportConfig.Validate(); err != nil {
+		logger.Fatalf("Invalid error report config: %v", err)
+	}
+	if !errorReportConfig.Enabled() {
+		return
+	}
+
+	reporter, err := errorreport.NewSentryReporter(errorReportConfig.ToSentryConfig())
+	if err != nil {
+		logger.Fatalf("Failed to initialize Sentry: %v", err)
+	}
+	errorreport.SetReporter(reporter)
+	logger.Infof("Error reporting initialized (Sentry, environment: %s, release: %s)", errorReportConfig.Environment, errorReportConfig.Release)
+}
+
+// initTracing initializes OpenTelemetry tracing, returns the shutdown func
+func initTracing() func(context.Context) error {
+	tracingConfig := config.LoadTracingConfig()
+	if err := tracingConfig.Validate(); err != nil {
+		logger.Fatalf("Invalid tracing config: %v", err)
+	}
+	shutdown, err := tracing.Init(context.Background(), tracingConfig.ToTracingConfig())
+	if err != nil {
+		logger.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	if tracingConfig.Enabled {
+		logger.Infof("Tracing initialized successfully (service: %s, sample ratio: %g)", tracingConfig.ServiceName, tracingConfig.SampleRatio)
+	}
+	return shutdown
+}
+
+// initMetrics registers runtime, database pool, query latency and cache metrics in the default registry
+func initMetrics(metricsConfig *config.MetricsConfig, db *gorm.DB) {
+	if !metricsConfig.Collecting() {
+		return
+	}
+	metrics.RegisterRuntime(metrics.Default())
+	// db nil khi role scheduler chạy không cần database
+	if db != nil {
+		if sqlDB, err := db.DB(); err == nil {
+			metrics.RegisterDBStats(metrics.Default(), "api_core_db", sqlDB)
+		}
+		// Thời gian query theo bảng
+		if err := metrics.InstrumentGORM(db, metrics.Default(), "api_core_db"); err != nil {
+			logger.Fatalf("Failed to instrument database metrics: %v", err)
+		}
+	}
+	cache.RegisterMetrics(metrics.Default(), "api_core_cache")
+}
+
 // initDatabase connects to the database
 func initDatabase() *gorm.DB {
 	dbConfig := config.GetDefaultDatabaseConfig()
@@ -147,6 +380,20 @@ func initDatabase() *gorm.DB {
 	if err != nil {
 		logger.Fatalf("Failed to connect to database: %v", err)
 	}
+	if err := model.RegisterIDGenerator(db, model.IDConfig{
+		DefaultVersion: dbConfig.UUIDVersion,
+		TableVersions:  dbConfig.UUIDVersions,
+	}); err != nil {
+		logger.Fatalf("Failed to register ID generator: %v", err)
+	}
+	// Model implement outbox.Recorder ghi message vào outbox cùng transaction khi được lưu
+	if err := outbox.RegisterCallbacks(db); err != nil {
+		logger.Fatalf("Failed to register outbox callbacks: %v", err)
+	}
+	// Span cho mỗi câu query (no-op khi tracing tắt)
+	if err := tracing.InstrumentGORM(db); err != nil {
+		logger.Fatalf("Failed to instrument database tracing: %v", err)
+	}
 	logger.Info("Database connected successfully")
 	return db
 }
@@ -176,47 +423,125 @@ func initDependencies(db *gorm.DB, cacheClient cache.Cache) *routes.Controllers
 }
 
 // initScheduleManager initializes the schedule manager
-func initScheduleManager() *schedules.ScheduleManager {
-	// Create Redis client for schedule manager
-	rdb := redis.NewClient(&redis.Options{
-		Addr: "localhost:6379",
-	})
+func initScheduleManager(db *gorm.DB) *schedules.ScheduleManager {
+	schedulerConfig := config.LoadSchedulerConfig()
+	if err := schedulerConfig.Validate(); err != nil {
+		logger.Fatalf("Invalid scheduler config: %v", err)
+	}
+
+	// Create Redis client for schedule manager (standalone, sentinel hoặc cluster theo REDIS_MODE)
+	rdb, err := config.NewRedisClient(config.GetDefaultCacheConfig())
 
 	// Test Redis connection
 	ctx := context.Background()
-	if err := rdb.Ping(ctx).Err(); err != nil {
+	var lockManager cron.LockManager
+	var control cron.Control
+	if err == nil {
+		err = rdb.Ping(ctx).Err()
+	}
+	if err != nil {
 		logger.Warnf("Failed to connect to Redis for schedule manager: %v", err)
 		logger.Info("Using memory lock manager for schedule manager")
 
 		// Close Redis connection if not available
-		rdb.Close()
+		if rdb != nil {
+			rdb.Close()
+		}
+		rdb = nil
 
 		// Use memory lock manager if Redis is not available
-		lockManager := cron.NewMemoryLockManager()
-		manager, err := schedules.InitScheduleManager(lockManager)
+		lockManager = cron.NewMemoryLockManager()
+	} else {
+		// Use Redis lock manager for multi-container deployment
+		lockManager = cron.NewRedisLockManager(rdb, schedules.LockPrefix)
+
+		// Pause/trigger job và trạng thái job dùng chung với admin API
+		control = cron.NewRedisControl(rdb, "")
+	}
+
+	// Leader election: chỉ 1 instance chạy cron loop thay vì mọi instance tranh lock theo từng job
+	var leaderElector cron.LeaderElector
+	switch schedulerConfig.LeaderElection {
+	case config.LeaderElectionRedis:
+		if rdb == nil {
+			logger.Fatalf("Redis leader election requires Redis")
+		}
+		leaderElector = cron.NewRedisLeaderElector(rdb, schedulerConfig.LeaderKey, schedulerConfig.InstanceID, schedulerConfig.LeaderTTL)
+	case config.LeaderElectionPostgres:
+		sqlDB, err := db.DB()
 		if err != nil {
-			logger.Warnf("Failed to initialize schedule manager: %v", err)
-			return nil
+			logger.Fatalf("Postgres leader election requires database: %v", err)
 		}
-		return manager
+		leaderElector = cron.NewPostgresLeaderElector(sqlDB, schedulerConfig.LeaderKey)
+	}
+
+	// Tắt lịch sử chạy job: scheduler không dùng database (bỏ qua cả các job dọn dữ liệu cần database)
+	if !schedulerConfig.RunHistory {
+		db = nil
 	}
 
-	// Use Redis lock manager for multi-container deployment
-	lockManager := cron.NewRedisLockManager(rdb, "api-core:cron:")
-	manager, err := schedules.InitScheduleManager(lockManager)
+	manager, err := schedules.InitScheduleManager(db, lockManager, leaderElector, schedulerConfig.LeaderRenewInterval, control)
 	if err != nil {
 		logger.Warnf("Failed to initialize schedule manager: %v", err)
-		rdb.Close()
+		if rdb != nil {
+			rdb.Close()
+		}
 		return nil
 	}
 
-	logger.Info("Schedule manager initialized successfully")
+	logger.Infof("Schedule manager initialized successfully (leader election: %s)", schedulerConfig.LeaderElection)
 	return manager
 }
 
-// initSocketHub initializes the WebSocket hub
-func initSocketHub() *socketPkg.Hub {
-	hub := socketPkg.NewHub()
+// initSocketHub initializes the WebSocket hub, resume state được bàn giao giữa các instance qua Redis,
+// kết nối xác thực bằng access token, room chat chỉ participant được join, online status chỉ bạn bè được theo dõi,
+// message bị lỡ khi mất mạng ngắn được gửi lại khi client kết nối lại với session_id, client gửi tin nhắn qua event chat.message,
+// số kết nối/room/message của hub export ở metrics listener khi METRICS_ENABLED
+func initSocketHub(db *gorm.DB, cacheClient cache.Cache, controllers *routes.Controllers, metricsConfig *config.MetricsConfig) *socketPkg.Hub {
+	// Level theo LOG_LEVEL_SOCKET
+	socketPkg.SetLogger(logger.Module(logger.ModuleSocket))
+
+	socketConfig := config.LoadSocketConfig()
+	hubConfig := socketPkg.HubConfig{
+		ResumeTTL:      socketConfig.ResumeTTL,
+		ReconnectDelay: socketConfig.ReconnectDelay,
+		Authorizer: socketPkg.RoomAuthorizers(
+			chat.RoomAuthorizer(repository.NewConversationParticipantRepository(db)),
+			friend.RoomAuthorizer(repository.NewFriendshipRepository(db)),
+		),
+		AllowClientBroadcast: socketConfig.AllowClientBroadcast,
+		Presence:             controllers.Presence,
+		PresenceTTL:          socketConfig.PresenceTTL,
+		ReplayBufferSize:     socketConfig.ReplayBufferSize,
+		ReplayWindow:         socketConfig.ReplayWindow,
+		MaxMessageSize:       int64(socketConfig.MaxMessageBytes),
+		MessagesPerSecond:    float64(socketConfig.MessagesPerSecond),
+		MessageBurst:         socketConfig.MessageBurst,
+		SendBufferSize:       socketConfig.SendBufferSize,
+		WriteTimeout:         socketConfig.WriteTimeout,
+		PingInterval:         socketConfig.PingInterval,
+		PongWait:             socketConfig.PongWait,
+		IdleTimeout:          socketConfig.IdleTimeout,
+	}
+	if socketConfig.AuthRequired {
+		hubConfig.Authenticator = socketPkg.JWTAuthenticator(controllers.JWTManager, controllers.JWTBlacklist)
+	} else {
+		logger.Warn("SOCKET_AUTH_REQUIRED=false: WebSocket user_id is taken from the query without authentication")
+	}
+	if socketConfig.ProtobufEnabled {
+		hubConfig.Codecs = append(hubConfig.Codecs, socketPkg.ProtobufCodec{})
+	}
+	if socketConfig.ResumeEnabled {
+		hubConfig.ResumeStore = socketPkg.NewCacheResumeStore(cacheClient)
+	}
+	hub := socketPkg.NewHubWithConfig(hubConfig)
+
+	// Typed events {event, data, id} từ client
+	chat.RegisterSocketEvents(hub, controllers.ChatHandler)
+
+	if metricsConfig.Collecting() {
+		socketPkg.NewMetrics(metrics.Default(), "api_core_socket").Watch(hub)
+	}
 
 	// Start the hub in a goroutine
 	go hub.Run()
@@ -254,34 +579,64 @@ func initFCM() *fcm.Client {
 		return nil
 	}
 
+	if telemetry.Global != nil {
+		telemetry.Global.SetFlag("fcm", true)
+	}
+
 	logger.Info("FCM client initialized successfully")
 	return client
 }
 
 // setupRouter sets up the router and all routes
-func setupRouter(controllers *routes.Controllers, socketHub *socketPkg.Hub, fcmClient *fcm.Client) *chi.Mux {
+func setupRouter(controllers *routes.Controllers, socketHub *socketPkg.Hub, fcmClient *fcm.Client, metricsConfig *config.MetricsConfig) *chi.Mux {
 	r := chi.NewRouter()
 
 	// Middleware
-	r.Use(middleware.RequestID) // Tạo unique ID cho mỗi request
-	r.Use(logger.Middleware())  // Log requests/responses với đầy đủ thông tin
-	r.Use(i18n.Middleware)      // Tự động detect và set language vào context
+	r.Use(middleware.RequestID)   // Tạo unique ID cho mỗi request
+	r.Use(tracing.Middleware)     // Span cho mỗi request, trả trace ID qua header X-Trace-Id
+	r.Use(errorreport.Middleware) // Request/user gửi kèm lỗi lên Sentry
+
+	// Số request và thời gian xử lý theo route/status
+	if metricsConfig.Collecting() {
+		r.Use(metrics.NewHTTPMetrics(metrics.Default(), "api_core_http").Middleware)
+	}
+	r.Use(logger.Middleware()) // Log requests/responses với đầy đủ thông tin
+	r.Use(i18n.Middleware)     // Tự động detect và set language vào context
+
+	// Security log - lỗi xác thực/phân quyền ghi ra stream "security", alert khi một IP/user vượt ngưỡng
+	r.Use(middlewarePkg.SecurityLog(config.LoadSecurityLogConfig().ToSecurityLogOptions()))
+
+	// Load shedding - reject low-priority traffic (503) khi service quá tải
+	r.Use(middlewarePkg.LoadShedding(context.Background(), config.LoadLoadShedConfig().ToLoadShedOptions()))
+
+	// Feature usage telemetry - đếm request theo endpoint class
+	if telemetry.Global != nil {
+		r.Use(telemetry.Middleware(telemetry.Global))
+	}
 
 	// Custom headers middleware
-	r.Use(middlewarePkg.CORSHeaders())     // CORS headers
-	r.Use(middlewarePkg.SecurityHeaders()) // Security headers
+	r.Use(middlewarePkg.CORSHeaders(config.LoadCORSConfig().ToCORSOptions())) // CORS headers
+	r.Use(middlewarePkg.SecurityHeaders())                                    // Security headers
+
+	// Custom headers (X-API-Version, X-Powered-By từ env)
+	r.Use(middlewarePkg.CustomHeaders(config.LoadResponseHeaders()))
+	formatConfig, err := config.LoadResponseFormatConfig().ToFormatConfig()
+	if err != nil {
+		logger.Fatalf("Invalid response format config: %v", err)
+	}
+	r.Use(middlewarePkg.ResponseFormat(formatConfig)) // Field case/envelope của JSON response theo API version hoặc header
+	r.Use(exception.RecoveryMiddleware)               // Recover từ panic với custom exception handling
 
-	// Custom headers for specific endpoints
-	r.Use(middlewarePkg.CustomHeaders(map[string]string{
-		// Headers will be set from environment variables
-	}))
-	r.Use(exception.RecoveryMiddleware) // Recover từ panic với custom exception handling
+	// Prometheus scrape qua API port, bảo vệ bằng METRICS_TOKEN
+	if metricsConfig.Route {
+		r.With(metrics.RequireToken(metricsConfig.Token)).Get(metricsConfig.Path, metrics.Default().Handler().ServeHTTP)
+	}
 
 	// Setup documentation routes
 	setupDocumentationRoutes(r)
 
 	// Setup static file routes
-	setupStaticFileRoutes(r)
+	setupStaticFileRoutes(r, controllers)
 
 	// Setup test pages (only in development)
 	initTestPages(r, fcmClient)
@@ -289,12 +644,39 @@ func setupRouter(controllers *routes.Controllers, socketHub *socketPkg.Hub, fcmC
 	// Register all API routes
 	routes.RegisterRoutes(r, controllers)
 
+	// pprof/expvar cho admin có permission debug.profile (DEBUG_ROUTES_ENABLED)
+	setupDebugRoutes(r, controllers)
+
 	// Register WebSocket routes
 	socketPkg.RegisterRoutes(r, socketHub)
 
+	// Internal API cho service khác và cron job đẩy event realtime, xác thực bằng client credentials
+	socketClients, err := socketPkg.ParseAPIClients(config.LoadSocketConfig().APIClients)
+	if err != nil {
+		logger.Fatalf("Invalid SOCKET_API_CLIENTS: %v", err)
+	}
+	controllers.Group(r, routes.GroupInternal, func(r chi.Router) {
+		socketPkg.RegisterInternalRoutes(r, socketHub, socketClients)
+	})
+
 	return r
 }
 
+// setupDebugRoutes mounts /debug/pprof and /debug/vars, giới hạn IP theo DEBUG_ALLOWED_IPS
+func setupDebugRoutes(r *chi.Mux, controllers *routes.Controllers) {
+	debugConfig := config.LoadDebugConfig()
+	if !debugConfig.Enabled {
+		return
+	}
+	if err := debugConfig.Validate(); err != nil {
+		logger.Fatalf("Invalid debug config: %v", err)
+	}
+
+	allowed, _ := debugConfig.AllowedNets()
+	routes.RegisterDebugRoutes(r, controllers, allowed, debugConfig.TrustProxy)
+	logger.Infof("Debug routes enabled at /debug (allowed IPs: %d)", len(allowed))
+}
+
 // setupDocumentationRoutes sets up documentation routes
 func setupDocumentationRoutes(r *chi.Mux) {
 	workDir, _ := os.Getwd()
@@ -328,13 +710,51 @@ func setupDocumentationRoutes(r *chi.Mux) {
 }
 
 // setupStaticFileRoutes sets up static file routes
-func setupStaticFileRoutes(r *chi.Mux) {
-	workDir, _ := os.Getwd()
+func setupStaticFileRoutes(r *chi.Mux, controllers *routes.Controllers) {
+	cfg := config.GetDefaultStorageConfig()
+	prefix := "/" + strings.Trim(cfg.Local.BaseURL, "/")
+
+	fileServerConfig := storage.FileServerConfig{
+		Root:            cfg.Local.BasePath,
+		Prefix:          prefix + "/",
+		MaxAge:          time.Duration(cfg.HTTP.MaxAge) * time.Second,
+		ImmutableMaxAge: time.Duration(cfg.HTTP.ImmutableMaxAge) * time.Second,
+		CDNURL:          cfg.HTTP.CDNURL,
+		SignedURLTTL:    time.Duration(cfg.HTTP.SignedURLTTL) * time.Second,
+		ImageVariants:   cfg.Image.Variants,
+	}
+	// Local storage mã hóa at-rest: giải mã khi phục vụ file
+	// Key sai mà vẫn chạy sẽ trả ciphertext cho client, dừng hẳn như các config không hợp lệ khác
+	cipher, err := storage.NewLocalCipher(cfg.Local)
+	if err != nil {
+		logger.Fatalf("Invalid local storage encryption keys: %v", err)
+	}
+	fileServerConfig.Cipher = cipher
+
+	// S3: luôn redirect sang signed URL ngắn hạn thay vì proxy nội dung qua API server.
+	// Có CDN thì file public vẫn qua CDN, signed URL chỉ dùng cho file private.
+	if cfg.HTTP.SignedURLs || cfg.Driver == "s3" {
+		storageManager, err := storage.NewStorageManager(cfg)
+		if err != nil {
+			logger.Errorf("Signed storage URLs disabled: %v", err)
+		} else {
+			fileServerConfig.SignURL = func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+				return storageManager.GetFileURL(ctx, key, true, int64(ttl.Seconds()))
+			}
+		}
+	}
 
-	// Static files for storages (avatars, etc.)
-	storageDir := http.Dir(filepath.Join(workDir, "storages/app"))
-	r.Get("/storages/*", func(w http.ResponseWriter, r *http.Request) {
-		http.StripPrefix("/storages/", http.FileServer(storageDir)).ServeHTTP(w, r)
+	// Static files for storages: avatar public, file đính kèm/upload cần đăng nhập và đúng quyền
+	if cfg.HTTP.Authorize {
+		fileServerConfig.Authorize = controllers.Downloads.Authorize
+	}
+	fileServer := storage.NewFileServer(fileServerConfig)
+	r.Group(func(r chi.Router) {
+		if cfg.HTTP.Authorize {
+			r.Use(controllers.JWTManager.OptionalMiddlewareWithBlacklist(controllers.JWTBlacklist))
+		}
+		r.Get(prefix+"/*", fileServer.ServeHTTP)
+		r.Head(prefix+"/*", fileServer.ServeHTTP)
 	})
 }
 
@@ -549,8 +969,107 @@ func startScheduleManager(manager *schedules.ScheduleManager) {
 	logger.Info("Schedule manager started successfully")
 }
 
-// startServer starts the HTTP server
-func startServer(r *chi.Mux) {
+// initQueueManager connects to the queue backend
+func initQueueManager() (*config.QueueConfig, queue.QueueManager) {
+	queueConfig := config.LoadQueueConfig()
+	if err := queueConfig.Validate(); err != nil {
+		logger.Fatalf("Invalid queue config: %v", err)
+	}
+
+	queueManager, err := queue.NewQueueManager(queueConfig.ToQueueConfig())
+	if err != nil {
+		logger.Fatalf("Failed to connect to queue backend: %v", err)
+	}
+	return queueConfig, queueManager
+}
+
+// initQueueControl connects queue pause/registry state, nil nếu không kết nối được Redis (pause từ admin API không có hiệu lực)
+func initQueueControl() queue.Control {
+	cacheClient, err := config.ConnectCache(config.GetDefaultCacheConfig())
+	if err != nil || cacheClient.GetRedisClient() == nil {
+		logger.Warnf("Failed to connect queue control to Redis: %v (pause/resume disabled)", err)
+		return nil
+	}
+	return queue.NewRedisControl(cacheClient.GetRedisClient(), "")
+}
+
+// initWorkerManager initializes queue consumers, work (queue:work) giới hạn queue và concurrency
+func initWorkerManager(db *gorm.DB, queueConfig *config.QueueConfig, queueManager queue.QueueManager, metricsConfig *config.MetricsConfig, work *workOptions) *workers.WorkerManager {
+	handlers, err := wire.InitializeWorkers(db)
+	if err != nil {
+		logger.Fatalf("Failed to initialize workers: %v", err)
+	}
+
+	options := queueConfig.ToConsumerOptions()
+	if metricsConfig.Collecting() {
+		options.Metrics = queue.NewMetrics(metrics.Default(), "api_core_queue")
+	}
+
+	// Trạng thái pause và danh sách queue dùng chung với admin API qua Redis
+	options.Control = initQueueControl()
+
+	manager := workers.NewWorkerManager(queueManager, options)
+	manager.RegisterAllHandlers(handlers)
+
+	for name, n := range queueConfig.QueueConcurrency {
+		manager.SetConcurrency(name, n)
+	}
+	for name, r := range queueConfig.QueueRateLimit {
+		manager.SetRateLimit(name, r)
+	}
+	for name, n := range queueConfig.QueueMaxInFlight {
+		manager.SetMaxInFlight(name, n)
+	}
+	if work != nil {
+		manager.Only(work.queues...)
+		if work.concurrency > 0 {
+			queues := work.queues
+			if len(queues) == 0 {
+				queues = manager.Queues()
+			}
+			for _, name := range queues {
+				manager.SetConcurrency(name, work.concurrency)
+			}
+		}
+	}
+
+	logger.Info("Worker manager initialized successfully")
+	return manager
+}
+
+// startWorkerManager starts consuming all registered queues
+func startWorkerManager(manager *workers.WorkerManager) {
+	if err := manager.Start(context.Background()); err != nil {
+		logger.Fatalf("Failed to start worker manager: %v", err)
+	}
+
+	logger.Infof("Worker manager started successfully (queues: %s)", strings.Join(manager.RunningQueues(), ", "))
+}
+
+// startOutboxPublisher starts publishing outbox messages, nil if OUTBOX_ENABLED=false
+func startOutboxPublisher(db *gorm.DB, queueManager queue.QueueManager) *outbox.Publisher {
+	outboxConfig := config.LoadOutboxConfig()
+	if err := outboxConfig.Validate(); err != nil {
+		logger.Fatalf("Invalid outbox config: %v", err)
+	}
+	if !outboxConfig.Enabled {
+		return nil
+	}
+
+	options := outbox.DefaultOptions()
+	options.PollInterval = outboxConfig.PollInterval
+	options.BatchSize = outboxConfig.BatchSize
+	options.Retry.Max = outboxConfig.RetryMaxDelay
+	options.Retention = outboxConfig.Retention
+
+	publisher := outbox.NewPublisher(db, queueManager, options)
+	publisher.Start(context.Background())
+	logger.Info("Outbox publisher started successfully")
+	return publisher
+}
+
+// startServer starts the HTTP server in background
+func startServer(r *chi.Mux) *http.Server {
 	logger.Info("Server starting on :3000")
 	logger.Info("Documentation: http://localhost:3000/docs")
 	logger.Info("Swagger UI: http://localhost:3000/swagger")
@@ -563,7 +1082,110 @@ func startServer(r *chi.Mux) {
 		logger.Info("FCM Test: http://localhost:3000/test-fcm")
 	}
 
-	if err := http.ListenAndServe(":3000", r); err != nil {
-		logger.Fatal("Failed to start server: " + err.Error())
+	server := &http.Server{Addr: ":3000", Handler: r}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Failed to start server: " + err.Error())
+		}
+	}()
+
+	return server
+}
+
+// startStatusRecorder chạy health check định kỳ cho /status (mỗi chu kỳ chỉ 1 API instance ghi kết quả)
+func startStatusRecorder(controllers *routes.Controllers) {
+	if !controllers.StatusService.Enabled() {
+		logger.Info("Status checks disabled")
+		return
+	}
+
+	go controllers.StatusService.RunRecorder(context.Background())
+	logger.Info("Status checks started")
+}
+
+// applyStorageLifecycles đồng bộ lifecycle rule lên các bucket có STORAGE_*_EXPIRE_DAYS
+func applyStorageLifecycles() {
+	storageManager, err := storage.NewStorageManager(config.GetDefaultStorageConfig())
+	if err != nil || !storageManager.HasLifecycles() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := storageManager.ApplyLifecycles(ctx); err != nil {
+		logger.Errorf("Failed to apply storage lifecycle rules: %v", err)
+		return
+	}
+	logger.Info("Storage lifecycle rules applied")
+}
+
+// startMetricsServer phục vụ metrics.Default() ở listener riêng, nil nếu tắt
+func startMetricsServer(metricsConfig *config.MetricsConfig) *http.Server {
+	if !metricsConfig.Enabled {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsConfig.Path, metrics.Default().Handler())
+	server := &http.Server{Addr: metricsConfig.Addr, Handler: mux}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatal("Failed to start metrics server: " + err.Error())
+		}
+	}()
+
+	logger.Infof("Metrics: http://localhost%s%s", metricsConfig.Addr, metricsConfig.Path)
+	return server
+}
+
+// waitForShutdown chờ signal rồi dừng server, workers và scheduler
+func waitForShutdown(server *http.Server, socketHub *socketPkg.Hub, metricsServer *http.Server, outboxPublisher *outbox.Publisher, workerManager *workers.WorkerManager, scheduleManager *schedules.ScheduleManager) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	sig := <-quit
+	logger.Infof("Received %s, shutting down...", sig)
+
+	timeout := time.Duration(utils.GetEnvInt("SHUTDOWN_TIMEOUT", 30)) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Yêu cầu WebSocket client kết nối lại sang instance khác kèm resume token (server.Shutdown không đóng connection đã hijack)
+	if socketHub != nil {
+		if err := socketHub.Shutdown(ctx); err != nil {
+			logger.Warnf("Failed to hand over WebSocket clients: %v", err)
+		}
+	}
+
+	// Ngừng nhận request trước, request đang xử lý có thể vẫn đẩy job vào queue
+	if server != nil {
+		if err := server.Shutdown(ctx); err != nil {
+			logger.Warnf("Failed to shutdown server: %v", err)
+		}
+	}
+
+	// Message chưa publish vẫn nằm trong outbox, instance khác hoặc lần chạy sau publish tiếp
+	if outboxPublisher != nil {
+		outboxPublisher.Stop()
 	}
+
+	if workerManager != nil {
+		if err := workerManager.Stop(); err != nil {
+			logger.Warnf("Failed to stop worker manager: %v", err)
+		}
+	}
+
+	if scheduleManager != nil {
+		if err := scheduleManager.Stop(); err != nil {
+			logger.Warnf("Failed to stop schedule manager: %v", err)
+		}
+	}
+
+	// Dừng metrics sau cùng để scrape cuối thấy trạng thái lúc shutdown
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(ctx); err != nil {
+			logger.Warnf("Failed to shutdown metrics server: %v", err)
+		}
+	}
+
+	logger.Info("Shutdown complete")
 }
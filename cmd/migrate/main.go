@@ -1,13 +1,16 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"os"
 
-	"api-core/config"
-	"api-core/database"
-	"api-core/database/seeders"
+	"github.com/anhnq996/go-api-core/database"
+	"github.com/anhnq996/go-api-core/database/importers"
+	"github.com/anhnq996/go-api-core/database/seeders"
+	"github.com/anhnq996/go-api-core/internal/config"
+	model "github.com/anhnq996/go-api-core/internal/models"
 
 	"gorm.io/gorm"
 )
@@ -21,6 +24,7 @@ func main() {
 	forceCmd := flag.NewFlagSet("force", flag.ExitOnError)
 	stepsCmd := flag.NewFlagSet("steps", flag.ExitOnError)
 	seedCmd := flag.NewFlagSet("seed", flag.ExitOnError)
+	importUsersCmd := flag.NewFlagSet("import-users", flag.ExitOnError)
 
 	// Force flags
 	forceVersion := forceCmd.Int("version", 0, "Version to force")
@@ -28,6 +32,19 @@ func main() {
 	// Steps flags
 	stepsN := stepsCmd.Int("n", 0, "Number of steps (positive=up, negative=down)")
 
+	// Import users flags
+	importSource := importUsersCmd.String("source", "", "Export source: firebase, auth0")
+	importFile := importUsersCmd.String("file", "", "Path to export file")
+	importFormat := importUsersCmd.String("format", "json", "Firebase export format: json, csv")
+	importDryRun := importUsersCmd.Bool("dry-run", false, "Validate and report without writing to database")
+	importPasswordMode := importUsersCmd.String("password-mode", string(importers.PasswordModePreserve), "Password hash mode: preserve, reset")
+	importRole := importUsersCmd.String("role", "user", "Role assigned to imported users")
+	importReport := importUsersCmd.String("report", "", "Write JSON report to this path")
+	firebaseSignerKey := importUsersCmd.String("firebase-signer-key", "", "Firebase hash config: base64_signer_key")
+	firebaseSaltSeparator := importUsersCmd.String("firebase-salt-separator", "", "Firebase hash config: base64_salt_separator")
+	firebaseRounds := importUsersCmd.Int("firebase-rounds", 8, "Firebase hash config: rounds")
+	firebaseMemCost := importUsersCmd.Int("firebase-mem-cost", 14, "Firebase hash config: mem_cost")
+
 	if len(os.Args) < 2 {
 		printUsage()
 		os.Exit(1)
@@ -40,6 +57,13 @@ func main() {
 		fmt.Printf("❌ Failed to connect to database: %v\n", err)
 		os.Exit(1)
 	}
+	if err := model.RegisterIDGenerator(db, model.IDConfig{
+		DefaultVersion: dbConfig.UUIDVersion,
+		TableVersions:  dbConfig.UUIDVersions,
+	}); err != nil {
+		fmt.Printf("❌ Failed to register ID generator: %v\n", err)
+		os.Exit(1)
+	}
 
 	// Parse subcommand
 	command := os.Args[1]
@@ -51,6 +75,26 @@ func main() {
 		return
 	}
 
+	// Import users cũng không cần migrator
+	if command == "import-users" {
+		importUsersCmd.Parse(os.Args[2:])
+		opts := importers.Options{
+			DryRun:       *importDryRun,
+			PasswordMode: importers.PasswordMode(*importPasswordMode),
+			RoleName:     *importRole,
+		}
+		if *firebaseSignerKey != "" {
+			opts.FirebaseScrypt = &importers.FirebaseScryptConfig{
+				SignerKey:     *firebaseSignerKey,
+				SaltSeparator: *firebaseSaltSeparator,
+				Rounds:        *firebaseRounds,
+				MemCost:       *firebaseMemCost,
+			}
+		}
+		runImportUsers(db, *importSource, *importFormat, *importFile, *importReport, opts)
+		return
+	}
+
 	// Create migrator cho migration commands
 	migrator, err := database.NewMigrator(db, "database/migrations")
 	if err != nil {
@@ -202,6 +246,76 @@ func runSeed(db *gorm.DB) {
 	fmt.Println("✅ Seeders completed successfully")
 }
 
+func runImportUsers(db *gorm.DB, sourceName, format, file, reportPath string, opts importers.Options) {
+	var source importers.Source
+	switch sourceName {
+	case "firebase":
+		source = importers.NewFirebaseSource(format)
+	case "auth0":
+		source = importers.NewAuth0Source()
+	default:
+		fmt.Println("❌ Please provide -source (firebase, auth0)")
+		os.Exit(1)
+	}
+
+	if file == "" {
+		fmt.Println("❌ Please provide export file with -file")
+		os.Exit(1)
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		fmt.Printf("❌ Failed to open file: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+
+	if opts.DryRun {
+		fmt.Println("🔍 Dry run: no users will be written")
+	}
+	fmt.Printf("Importing users from %s (%s)...\n", sourceName, file)
+
+	report, err := importers.ImportUsers(db, source, f, opts)
+	if report != nil {
+		printImportReport(report)
+		if reportPath != "" {
+			data, _ := json.MarshalIndent(report, "", "  ")
+			if werr := os.WriteFile(reportPath, data, 0o644); werr != nil {
+				fmt.Printf("❌ Failed to write report: %v\n", werr)
+			} else {
+				fmt.Printf("📄 Report written to %s\n", reportPath)
+			}
+		}
+	}
+	if err != nil {
+		fmt.Printf("❌ Failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	if opts.DryRun {
+		fmt.Println("✅ Dry run completed")
+		return
+	}
+	fmt.Println("✅ Import completed")
+}
+
+func printImportReport(report *importers.Report) {
+	created := "Created"
+	if report.DryRun {
+		created = "Would create"
+	}
+	fmt.Printf("\nTotal: %d | %s: %d | Skipped: %d | Failed: %d\n", report.Total, created, report.Created, report.Skipped, report.Failed)
+	fmt.Printf("Passwords preserved: %d | Passwords reset: %d\n", report.PasswordsPreserved, report.PasswordsReset)
+
+	// Chỉ in tối đa 20 lỗi, chi tiết đầy đủ xem trong file report
+	for i, e := range report.Errors {
+		if i == 20 {
+			fmt.Printf("  ... and %d more errors\n", len(report.Errors)-20)
+			break
+		}
+		fmt.Printf("  ⚠️  row %d %s: %s\n", e.Row, e.Email, e.Reason)
+	}
+}
+
 func printUsage() {
 	fmt.Print(`
 Database Tool - Quản lý migrations và seeders
@@ -217,6 +331,7 @@ Commands:
   force             Force set migration version (use when dirty)
   steps             Run N migration steps
   seed              Run database seeders
+  import-users      Import users from external export (Firebase Auth, Auth0)
 
 Examples:
   # Migrations
@@ -231,11 +346,18 @@ Examples:
   # Seeders
   go run cmd/migrate/main.go seed
 
+  # Import users
+  go run cmd/migrate/main.go import-users -source auth0 -file users.ndjson -dry-run
+  go run cmd/migrate/main.go import-users -source firebase -format csv -file users.csv \
+    -firebase-signer-key <key> -firebase-salt-separator <sep> -firebase-rounds 8 -firebase-mem-cost 14
+
   # Fresh setup (recommended)
   make fresh                                 # Drop all + migrate + seed
 
 Options:
   force -version <N>    Version number to force
   steps -n <N>          Number of steps (positive=up, negative=down)
+  import-users -source <firebase|auth0> -file <path> [-format json|csv] [-dry-run]
+               [-password-mode preserve|reset] [-role user] [-report report.json]
 `)
 }
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	// .env không bắt buộc, biến môi trường đã set vẫn được ưu tiên
+	_ = godotenv.Load()
+
+	cfg := config.GetDefaultCacheConfig()
+	client, err := config.ConnectCache(cfg)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(1)
+	}
+	defer client.Close()
+	namespaced := client.(*cache.NamespacedCache)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch os.Args[1] {
+	case "bump":
+		version, err := namespaced.BumpVersion(ctx)
+		if err != nil {
+			fmt.Printf("❌ Bump failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Cache version: %s (namespace %q)\n", version, cfg.Namespace)
+		fmt.Printf("   Instances switch to the new version within %s, old entries expire by TTL\n", cfg.VersionRefresh)
+
+	case "show":
+		fmt.Printf("%s (namespace %q)\n", namespaced.Version(ctx), cfg.Namespace)
+
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: cachever <command>")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  bump   Invalidate all cached payloads (Remember/RememberStale/Tags) on every instance")
+	fmt.Println("  show   Print the current cache version")
+	fmt.Println()
+	fmt.Println("Redis and namespace are read from REDIS_* and CACHE_NAMESPACE / CACHE_SCHEMA_VERSION")
+}
@@ -1,27 +1,37 @@
 package main
 
 import (
-	"crypto/rand"
-	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
 )
 
 func main() {
-	keysDir := filepath.Join("keys")
-	privPath := filepath.Join(keysDir, "private.pem")
-	pubPath := filepath.Join(keysDir, "public.pem")
+	defaultAlg := os.Getenv("JWT_ALGORITHM")
+	if defaultAlg == "" || defaultAlg == jwt.AlgHS256 {
+		defaultAlg = jwt.AlgRS256
+	}
+
+	alg := flag.String("alg", defaultAlg, "Signing algorithm: RS256, ES256, ES384, ES512, EdDSA (JWT_ALGORITHM)")
+	bits := flag.Int("bits", 2048, "RSA key size")
+	keysDir := flag.String("dir", "keys", "Output directory")
+	flag.Parse()
+
+	privPath := filepath.Join(*keysDir, "private.pem")
+	pubPath := filepath.Join(*keysDir, "public.pem")
 
-	if err := os.MkdirAll(keysDir, 0o755); err != nil {
+	if err := os.MkdirAll(*keysDir, 0o755); err != nil {
 		fmt.Fprintf(os.Stderr, "mkdir keys: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Generate RSA private key
-	privKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	// Generate private key (RSA, ECDSA hoặc Ed25519 theo thuật toán)
+	privKey, err := jwt.GenerateKey(*alg, *bits)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "generate key: %v\n", err)
 		os.Exit(1)
@@ -33,7 +43,7 @@ func main() {
 		fmt.Fprintf(os.Stderr, "marshal private key: %v\n", err)
 		os.Exit(1)
 	}
-	privFile, err := os.Create(privPath)
+	privFile, err := os.OpenFile(privPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "create private key: %v\n", err)
 		os.Exit(1)
@@ -45,7 +55,7 @@ func main() {
 	}
 
 	// Write public key (PKIX) PEM
-	pubDer, err := x509.MarshalPKIXPublicKey(&privKey.PublicKey)
+	pubDer, err := x509.MarshalPKIXPublicKey(privKey.Public())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "marshal public key: %v\n", err)
 		os.Exit(1)
@@ -61,7 +71,7 @@ func main() {
 		os.Exit(1)
 	}
 
-	fmt.Println("✅ Generated:")
+	fmt.Printf("✅ Generated %s keys:\n", *alg)
 	fmt.Println(" -", privPath)
 	fmt.Println(" -", pubPath)
 }
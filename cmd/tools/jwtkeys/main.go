@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	defaultDir := os.Getenv("JWT_KEYS_DIR")
+	if defaultDir == "" {
+		defaultDir = "keys/jwt"
+	}
+
+	defaultAlg := os.Getenv("JWT_ALGORITHM")
+	if defaultAlg == "" || defaultAlg == jwt.AlgHS256 {
+		defaultAlg = jwt.AlgRS256
+	}
+
+	rotateCmd := flag.NewFlagSet("rotate", flag.ExitOnError)
+	rotateDir := rotateCmd.String("dir", defaultDir, "Keys directory (JWT_KEYS_DIR)")
+	rotateAlg := rotateCmd.String("alg", defaultAlg, "Signing algorithm: RS256, ES256, ES384, ES512, EdDSA (JWT_ALGORITHM)")
+	rotateBits := rotateCmd.Int("bits", 2048, "RSA key size")
+
+	pruneCmd := flag.NewFlagSet("prune", flag.ExitOnError)
+	pruneDir := pruneCmd.String("dir", defaultDir, "Keys directory (JWT_KEYS_DIR)")
+	// Refresh token sống 7 ngày, giữ thêm 1 ngày cho instance chưa reload key
+	pruneRetention := pruneCmd.Duration("retention", 8*24*time.Hour, "Remove keys retired longer than this (must exceed refresh token lifetime)")
+
+	listCmd := flag.NewFlagSet("list", flag.ExitOnError)
+	listDir := listCmd.String("dir", defaultDir, "Keys directory (JWT_KEYS_DIR)")
+
+	now := time.Now()
+
+	switch os.Args[1] {
+	case "rotate":
+		rotateCmd.Parse(os.Args[2:])
+		key, err := jwt.RotateKey(*rotateDir, *rotateAlg, *rotateBits, now)
+		if err != nil {
+			fmt.Printf("❌ Rotate failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ New signing key: %s (%s)\n", key.ID, key.Algorithm)
+		fmt.Println("   Previous keys still verify issued tokens until pruned")
+
+	case "prune":
+		pruneCmd.Parse(os.Args[2:])
+		removed, err := jwt.PruneKeys(*pruneDir, *pruneRetention, now)
+		for _, kid := range removed {
+			fmt.Printf("🗑️  Removed key: %s\n", kid)
+		}
+		if err != nil {
+			fmt.Printf("❌ Prune failed: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✅ Pruned %d key(s)\n", len(removed))
+
+	case "list":
+		listCmd.Parse(os.Args[2:])
+		ks, err := jwt.LoadKeyDir(*listDir)
+		if err != nil {
+			fmt.Printf("❌ Load keys failed: %v\n", err)
+			os.Exit(1)
+		}
+		for _, key := range ks.Keys() {
+			status := "retired " + key.RotatedAt.Format(time.RFC3339)
+			if key == ks.Active() {
+				status = "active since " + key.RotatedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%s  %-5s  %s\n", key.ID, key.Algorithm, status)
+		}
+
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Println("Usage: go run ./cmd/tools/jwtkeys <command> [flags]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  rotate [-dir keys/jwt] [-alg RS256] [-bits 2048]   Generate a new signing key and make it active")
+	fmt.Println("  prune  [-dir keys/jwt] [-retention 192h]  Remove retired keys older than retention")
+	fmt.Println("  list   [-dir keys/jwt]                List keys (active first)")
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	// .env không bắt buộc, biến môi trường đã set vẫn được ưu tiên
+	_ = godotenv.Load()
+
+	command := os.Args[1]
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	ttl := fs.Duration("ttl", 0, "Revert to the configured level after this duration, e.g. 30m (set)")
+	_ = fs.Parse(os.Args[2:])
+
+	rdb, err := config.NewRedisClient(config.GetDefaultCacheConfig())
+	if err != nil {
+		fail("Failed to create Redis client: %v", err)
+	}
+	defer rdb.Close()
+	store := logger.NewRedisLevelStore(rdb, "")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	switch command {
+	case "list":
+		overrides, err := store.Load(ctx)
+		if err != nil {
+			fail("Failed to load log levels: %v", err)
+		}
+		configured := config.LoadLoggerConfig()
+		for _, module := range logger.Modules {
+			level := configured.ModuleLevels[module]
+			if module == logger.ModuleApp || level == "" {
+				level = configured.Level
+			}
+			line := fmt.Sprintf("  %-8s %s", module, level)
+			if override, ok := overrides[module]; ok {
+				line += fmt.Sprintf(" (runtime: %s)", override)
+			}
+			fmt.Println(line)
+		}
+
+	case "set":
+		if fs.NArg() != 2 {
+			printUsage()
+			os.Exit(1)
+		}
+		module := checkModule(fs.Arg(0))
+		level, err := logger.ParseLevel(fs.Arg(1))
+		if err != nil {
+			fail("%v", err)
+		}
+		if err := store.Set(ctx, module, level, *ttl); err != nil {
+			fail("%v", err)
+		}
+		fmt.Printf("✅ %s set to %s on all processes within LOG_LEVEL_SYNC_SECONDS", module, level)
+		if *ttl > 0 {
+			fmt.Printf(" (reverts after %s)", *ttl)
+		}
+		fmt.Println()
+
+	case "reset":
+		if fs.NArg() != 1 {
+			printUsage()
+			os.Exit(1)
+		}
+		module := checkModule(fs.Arg(0))
+		if err := store.Reset(ctx, module); err != nil {
+			fail("%v", err)
+		}
+		fmt.Printf("✅ %s reset to the configured level\n", module)
+
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+func checkModule(module string) string {
+	if !slices.Contains(logger.Modules, module) {
+		fail("Unknown module %q, must be one of %v", module, logger.Modules)
+	}
+	return module
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func printUsage() {
+	fmt.Println("Usage: loglevel <command> [flags] [module] [level]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list                             Show configured (LOG_LEVEL_*) and runtime levels of each module")
+	fmt.Println("  set [-ttl 30m] <module> <level>  Change the level of <module> on all processes without restarting")
+	fmt.Println("  reset <module>                   Revert <module> to its configured level")
+	fmt.Println()
+	fmt.Printf("Modules: %v, levels: debug, info, warn, error\n", logger.Modules)
+	fmt.Println("Levels are stored in Redis (REDIS_*), processes pick them up every LOG_LEVEL_SYNC_SECONDS.")
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(1)
+	}
+
+	// .env không bắt buộc, biến môi trường đã set vẫn được ưu tiên
+	_ = godotenv.Load()
+
+	command := os.Args[1]
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	limit := fs.Int("limit", 20, "Messages to show (list)")
+	offset := fs.Int("offset", 0, "Messages to skip (list)")
+	_ = fs.Parse(os.Args[2:])
+	if fs.NArg() < 1 {
+		printUsage()
+		os.Exit(1)
+	}
+	queueName, ids := fs.Arg(0), fs.Args()[1:]
+
+	cfg := config.LoadQueueConfig()
+	if err := cfg.Validate(); err != nil {
+		fail("Invalid queue config: %v", err)
+	}
+	manager, err := queue.NewQueueManager(cfg.ToQueueConfig())
+	if err != nil {
+		fail("Failed to connect to queue backend: %v", err)
+	}
+	defer manager.Close()
+	dead := queue.NewDeadLetterManager(manager)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch command {
+	case "list":
+		count, err := dead.Count(ctx, queueName)
+		if err != nil {
+			fail("Failed to count dead messages: %v", err)
+		}
+		messages, err := dead.List(ctx, queueName, *offset, *limit)
+		if err != nil {
+			fail("Failed to list dead messages: %v", err)
+		}
+		fmt.Printf("%s: %d dead message(s)\n", queue.DeadQueueName(queueName), count)
+		for _, message := range messages {
+			printMessage(message)
+		}
+
+	case "requeue":
+		moved, err := dead.Requeue(ctx, queueName, ids...)
+		if err != nil {
+			fail("Requeued %d message(s) before failing: %v", moved, err)
+		}
+		fmt.Printf("✅ Requeued %d message(s) to %s\n", moved, queueName)
+
+	case "purge":
+		removed, err := dead.Purge(ctx, queueName, ids...)
+		if err != nil {
+			fail("Purged %d message(s) before failing: %v", removed, err)
+		}
+		fmt.Printf("✅ Purged %d message(s) from %s\n", removed, queue.DeadQueueName(queueName))
+
+	default:
+		printUsage()
+		os.Exit(1)
+	}
+}
+
+// printMessage in 1 message, lý do lỗi chỉ lấy dòng đầu (panic kèm stack trace)
+func printMessage(message *queue.Message) {
+	reason, _, _ := strings.Cut(queue.DeadReason(message), "\n")
+	fmt.Println()
+	fmt.Printf("  ID:       %s\n", message.ID)
+	if jobType := message.Headers[queue.HeaderJobType]; jobType != "" {
+		fmt.Printf("  Type:     %s\n", jobType)
+	}
+	fmt.Printf("  Dead at:  %s (attempts: %s)\n", message.Headers[queue.HeaderDeadAt], message.Headers[queue.HeaderDeadAttempts])
+	fmt.Printf("  Reason:   %s\n", reason)
+	fmt.Printf("  Data:     %s\n", truncate(string(message.Data), 200))
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+func fail(format string, args ...interface{}) {
+	fmt.Printf("❌ "+format+"\n", args...)
+	os.Exit(1)
+}
+
+func printUsage() {
+	fmt.Println("Usage: queuedead <command> [flags] <queue> [message-id...]")
+	fmt.Println()
+	fmt.Println("Commands:")
+	fmt.Println("  list     Show dead messages of <queue> with failure reasons (-limit 20 -offset 0)")
+	fmt.Println("  requeue  Move dead messages back to <queue> (all, or only the given IDs)")
+	fmt.Println("  purge    Delete dead messages (all, or only the given IDs)")
+	fmt.Println()
+	fmt.Println("Queue backend is read from QUEUE_* / REDIS_*. Dead messages live in <queue>" + queue.DeadQueueSuffix)
+}
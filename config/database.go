@@ -1,60 +0,0 @@
-package config
-
-import (
-	"fmt"
-
-	"api-core/pkg/utils"
-
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
-)
-
-// DatabaseConfig cấu hình database
-type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
-	SSLMode  string
-}
-
-// GetDefaultDatabaseConfig trả về config mặc định từ env
-func GetDefaultDatabaseConfig() DatabaseConfig {
-	return DatabaseConfig{
-		Host:     utils.GetEnv("DB_HOST", "localhost"),
-		Port:     utils.GetEnv("DB_PORT", "5432"),
-		User:     utils.GetEnv("DB_USER", "postgres"),
-		Password: utils.GetEnv("DB_PASSWORD", "postgres"),
-		DBName:   utils.GetEnv("DB_NAME", "apicore"),
-		SSLMode:  utils.GetEnv("DB_SSLMODE", "disable"),
-	}
-}
-
-// ConnectDatabase kết nối đến database
-func ConnectDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
-	dsn := fmt.Sprintf(
-		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode,
-	)
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
-	}
-
-	// Get underlying SQL DB for connection pooling
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get underlying database: %w", err)
-	}
-
-	// Set connection pool settings
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-
-	return db, nil
-}
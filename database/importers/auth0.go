@@ -0,0 +1,138 @@
+package importers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// Auth0Source đọc file export users của Auth0 (NDJSON từ export job hoặc JSON array)
+// Password hash (bcrypt) chỉ có khi được Auth0 support export, field passwordHash
+type Auth0Source struct{}
+
+// NewAuth0Source tạo Auth0 source
+func NewAuth0Source() *Auth0Source {
+	return &Auth0Source{}
+}
+
+// Name tên source
+func (s *Auth0Source) Name() string {
+	return "auth0"
+}
+
+// auth0User một user trong file export
+type auth0User struct {
+	UserID        string `json:"user_id"`
+	LegacyID      string `json:"_id"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	Nickname      string `json:"nickname"`
+	Picture       string `json:"picture"`
+	Blocked       bool   `json:"blocked"`
+	CreatedAt     string `json:"created_at"`
+	LastLogin     string `json:"last_login"`
+	PasswordHash  string `json:"passwordHash"`
+}
+
+// Parse đọc file export, tự nhận biết JSON array hoặc NDJSON
+func (s *Auth0Source) Parse(r io.Reader) ([]Record, []RowError, error) {
+	reader := bufio.NewReader(r)
+	first, err := peekNonSpace(reader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	if first == '[' {
+		var users []auth0User
+		if err := json.NewDecoder(reader).Decode(&users); err != nil {
+			return nil, nil, err
+		}
+		records := make([]Record, 0, len(users))
+		for i, u := range users {
+			records = append(records, u.toRecord(i+1))
+		}
+		return records, nil, nil
+	}
+
+	var records []Record
+	var rowErrors []RowError
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for row := 1; scanner.Scan(); row++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var u auth0User
+		if err := json.Unmarshal([]byte(line), &u); err != nil {
+			rowErrors = append(rowErrors, RowError{Row: row, Reason: "invalid json: " + err.Error()})
+			continue
+		}
+		records = append(records, u.toRecord(row))
+	}
+	return records, rowErrors, scanner.Err()
+}
+
+// toRecord map user Auth0 sang Record
+func (u auth0User) toRecord(row int) Record {
+	externalID := u.UserID
+	if externalID == "" {
+		externalID = u.LegacyID
+	}
+	name := u.Name
+	if name == "" || name == u.Email {
+		// Auth0 mặc định name = email với database connection
+		name = u.Nickname
+	}
+
+	rec := Record{
+		Row:           row,
+		ExternalID:    externalID,
+		Email:         u.Email,
+		EmailVerified: u.EmailVerified,
+		Name:          name,
+		Avatar:        u.Picture,
+		Disabled:      u.Blocked,
+		CreatedAt:     parseRFC3339(u.CreatedAt),
+		LastLoginAt:   parseRFC3339(u.LastLogin),
+	}
+	if u.PasswordHash != "" {
+		rec.Password = &PasswordHash{Algorithm: AlgorithmBcrypt, Hash: u.PasswordHash}
+	}
+	return rec
+}
+
+// peekNonSpace trả về ký tự đầu tiên không phải khoảng trắng mà không tiêu thụ nó
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		b, err := r.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			r.ReadByte()
+		default:
+			return b[0], nil
+		}
+	}
+}
+
+// parseRFC3339 parse timestamp ISO 8601 của Auth0
+func parseRFC3339(value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,148 @@
+package importers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FirebaseSource đọc file export của Firebase Auth (firebase auth:export --format=json|csv)
+type FirebaseSource struct {
+	Format string // json hoặc csv
+}
+
+// NewFirebaseSource tạo Firebase source
+func NewFirebaseSource(format string) *FirebaseSource {
+	return &FirebaseSource{Format: strings.ToLower(format)}
+}
+
+// Name tên source
+func (s *FirebaseSource) Name() string {
+	return "firebase"
+}
+
+// firebaseUser một user trong file export JSON
+type firebaseUser struct {
+	LocalID        string `json:"localId"`
+	Email          string `json:"email"`
+	EmailVerified  bool   `json:"emailVerified"`
+	PasswordHash   string `json:"passwordHash"`
+	Salt           string `json:"salt"`
+	DisplayName    string `json:"displayName"`
+	PhotoURL       string `json:"photoUrl"`
+	Disabled       bool   `json:"disabled"`
+	CreatedAt      string `json:"createdAt"`
+	LastSignedInAt string `json:"lastSignedInAt"`
+}
+
+// Cột trong file export CSV của Firebase (không có header)
+const (
+	firebaseColUID            = 0
+	firebaseColEmail          = 1
+	firebaseColEmailVerified  = 2
+	firebaseColPasswordHash   = 3
+	firebaseColPasswordSalt   = 4
+	firebaseColName           = 5
+	firebaseColPhotoURL       = 6
+	firebaseColCreationTime   = 23
+	firebaseColLastSignInTime = 24
+)
+
+// Parse đọc file export
+func (s *FirebaseSource) Parse(r io.Reader) ([]Record, []RowError, error) {
+	switch s.Format {
+	case "", "json":
+		return s.parseJSON(r)
+	case "csv":
+		return s.parseCSV(r)
+	default:
+		return nil, nil, fmt.Errorf("unsupported firebase export format: %s", s.Format)
+	}
+}
+
+func (s *FirebaseSource) parseJSON(r io.Reader) ([]Record, []RowError, error) {
+	var export struct {
+		Users []firebaseUser `json:"users"`
+	}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, nil, err
+	}
+
+	records := make([]Record, 0, len(export.Users))
+	for i, u := range export.Users {
+		records = append(records, Record{
+			Row:           i + 1,
+			ExternalID:    u.LocalID,
+			Email:         u.Email,
+			EmailVerified: u.EmailVerified,
+			Name:          u.DisplayName,
+			Avatar:        u.PhotoURL,
+			Password:      firebasePassword(u.PasswordHash, u.Salt),
+			Disabled:      u.Disabled,
+			CreatedAt:     parseUnixMillis(u.CreatedAt),
+			LastLoginAt:   parseUnixMillis(u.LastSignedInAt),
+		})
+	}
+	return records, nil, nil
+}
+
+func (s *FirebaseSource) parseCSV(r io.Reader) ([]Record, []RowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	var records []Record
+	var rowErrors []RowError
+	for row := 1; ; row++ {
+		cols, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			rowErrors = append(rowErrors, RowError{Row: row, Reason: err.Error()})
+			continue
+		}
+		if len(cols) <= firebaseColPhotoURL {
+			rowErrors = append(rowErrors, RowError{Row: row, Reason: "not enough columns"})
+			continue
+		}
+
+		rec := Record{
+			Row:           row,
+			ExternalID:    cols[firebaseColUID],
+			Email:         cols[firebaseColEmail],
+			EmailVerified: cols[firebaseColEmailVerified] == "true",
+			Name:          cols[firebaseColName],
+			Avatar:        cols[firebaseColPhotoURL],
+			Password:      firebasePassword(cols[firebaseColPasswordHash], cols[firebaseColPasswordSalt]),
+		}
+		if len(cols) > firebaseColLastSignInTime {
+			rec.CreatedAt = parseUnixMillis(cols[firebaseColCreationTime])
+			rec.LastLoginAt = parseUnixMillis(cols[firebaseColLastSignInTime])
+		}
+		records = append(records, rec)
+	}
+	return records, rowErrors, nil
+}
+
+// firebasePassword tạo PasswordHash từ hash/salt base64 của Firebase
+func firebasePassword(hash, salt string) *PasswordHash {
+	if hash == "" {
+		return nil
+	}
+	return &PasswordHash{Algorithm: AlgorithmFirebaseScrypt, Hash: hash, Salt: salt}
+}
+
+// parseUnixMillis parse timestamp dạng milliseconds (Firebase lưu dạng string)
+func parseUnixMillis(value string) *time.Time {
+	ms, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || ms <= 0 {
+		return nil
+	}
+	t := time.UnixMilli(ms)
+	return &t
+}
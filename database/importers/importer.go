@@ -0,0 +1,263 @@
+package importers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// Thuật toán password hash của dữ liệu export
+const (
+	AlgorithmBcrypt         = "bcrypt"
+	AlgorithmFirebaseScrypt = "firebase-scrypt"
+)
+
+// PasswordMode cách xử lý password hash khi import
+type PasswordMode string
+
+const (
+	// PasswordModePreserve giữ hash gốc, user login bằng password cũ (hash legacy được bcrypt lại sau lần login đầu)
+	PasswordModePreserve PasswordMode = "preserve"
+	// PasswordModeReset bỏ hash, user phải đặt lại password hoặc dùng social login
+	PasswordModeReset PasswordMode = "reset"
+)
+
+// PasswordHash password hash gốc đọc từ file export
+type PasswordHash struct {
+	Algorithm string
+	Hash      string
+	Salt      string
+}
+
+// Record một user đọc từ file export, đã map sang field chung
+type Record struct {
+	Row           int // Số dòng/vị trí trong file, dùng cho report
+	ExternalID    string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Avatar        string
+	Password      *PasswordHash
+	Disabled      bool
+	CreatedAt     *time.Time
+	LastLoginAt   *time.Time
+}
+
+// Source đọc file export của một hệ thống bên ngoài
+type Source interface {
+	// Name tên source (firebase, auth0...)
+	Name() string
+	// Parse đọc toàn bộ records, lỗi từng dòng trả về trong []RowError thay vì dừng import
+	Parse(r io.Reader) ([]Record, []RowError, error)
+}
+
+// FirebaseScryptConfig hash config của Firebase project (Authentication > Users > Password hash parameters)
+type FirebaseScryptConfig struct {
+	SignerKey     string
+	SaltSeparator string
+	Rounds        int
+	MemCost       int
+}
+
+// Options cấu hình import
+type Options struct {
+	DryRun         bool
+	PasswordMode   PasswordMode
+	RoleName       string // Role gán cho user import, mặc định "user"
+	BatchSize      int
+	FirebaseScrypt *FirebaseScryptConfig
+}
+
+// RowError lỗi hoặc lý do bỏ qua của một record
+type RowError struct {
+	Row        int    `json:"row"`
+	ExternalID string `json:"external_id,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Reason     string `json:"reason"`
+}
+
+// Report kết quả import
+type Report struct {
+	Source             string     `json:"source"`
+	DryRun             bool       `json:"dry_run"`
+	Total              int        `json:"total"`
+	Created            int        `json:"created"`
+	Skipped            int        `json:"skipped"`
+	Failed             int        `json:"failed"`
+	PasswordsPreserved int        `json:"passwords_preserved"`
+	PasswordsReset     int        `json:"passwords_reset"`
+	Skips              []RowError `json:"skips,omitempty"`
+	Errors             []RowError `json:"errors,omitempty"`
+}
+
+// ImportUsers đọc file export qua source và tạo users mới
+// User có email đã tồn tại (kể cả đã soft delete) được bỏ qua, không ghi đè
+func ImportUsers(db *gorm.DB, source Source, r io.Reader, opts Options) (*Report, error) {
+	if opts.PasswordMode == "" {
+		opts.PasswordMode = PasswordModePreserve
+	}
+	if opts.PasswordMode != PasswordModePreserve && opts.PasswordMode != PasswordModeReset {
+		return nil, fmt.Errorf("invalid password mode: %s", opts.PasswordMode)
+	}
+	if opts.RoleName == "" {
+		opts.RoleName = "user"
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+
+	records, parseErrors, err := source.Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s export: %w", source.Name(), err)
+	}
+
+	report := &Report{
+		Source: source.Name(),
+		DryRun: opts.DryRun,
+		Total:  len(records) + len(parseErrors),
+		Errors: parseErrors,
+		Failed: len(parseErrors),
+	}
+
+	var role model.Role
+	if err := db.Where("name = ?", opts.RoleName).First(&role).Error; err != nil {
+		return nil, fmt.Errorf("role %q not found: %w", opts.RoleName, err)
+	}
+
+	for start := 0; start < len(records); start += opts.BatchSize {
+		end := start + opts.BatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		if err := importBatch(db, records[start:end], role, opts, report); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// importBatch validate, lọc trùng và tạo users cho một batch
+func importBatch(db *gorm.DB, records []Record, role model.Role, opts Options, report *Report) error {
+	emails := make([]string, 0, len(records))
+	for i := range records {
+		records[i].Email = strings.ToLower(strings.TrimSpace(records[i].Email))
+		emails = append(emails, records[i].Email)
+	}
+
+	// Email unique trên toàn bảng nên phải tính cả user đã soft delete
+	var existing []string
+	if err := db.Unscoped().Model(&model.User{}).Where("LOWER(email) IN ?", emails).Pluck("LOWER(email)", &existing).Error; err != nil {
+		return fmt.Errorf("query existing users: %w", err)
+	}
+	seen := make(map[string]bool, len(existing))
+	for _, email := range existing {
+		seen[email] = true
+	}
+
+	users := make([]model.User, 0, len(records))
+	for _, rec := range records {
+		rowErr := RowError{Row: rec.Row, ExternalID: rec.ExternalID, Email: rec.Email}
+
+		if !utils.IsEmail(rec.Email) {
+			rowErr.Reason = "invalid or missing email"
+			report.Errors = append(report.Errors, rowErr)
+			report.Failed++
+			continue
+		}
+		if seen[rec.Email] {
+			rowErr.Reason = "email already exists"
+			report.Skips = append(report.Skips, rowErr)
+			report.Skipped++
+			continue
+		}
+
+		password, err := encodePassword(rec.Password, opts)
+		if err != nil {
+			rowErr.Reason = err.Error()
+			report.Errors = append(report.Errors, rowErr)
+			report.Failed++
+			continue
+		}
+
+		seen[rec.Email] = true
+		users = append(users, buildUser(rec, password, role))
+		if password != "" {
+			report.PasswordsPreserved++
+		} else {
+			report.PasswordsReset++
+		}
+	}
+
+	if len(users) == 0 {
+		return nil
+	}
+	if !opts.DryRun {
+		if err := db.CreateInBatches(&users, len(users)).Error; err != nil {
+			return fmt.Errorf("create users: %w", err)
+		}
+	}
+	report.Created += len(users)
+	return nil
+}
+
+// buildUser map record sang model.User
+func buildUser(rec Record, password string, role model.Role) model.User {
+	name := strings.TrimSpace(rec.Name)
+	if name == "" {
+		name = strings.Split(rec.Email, "@")[0]
+	}
+
+	user := model.User{
+		Name:        name,
+		Email:       rec.Email,
+		Password:    password,
+		RoleID:      &role.ID,
+		IsActive:    !rec.Disabled,
+		LastLoginAt: rec.LastLoginAt,
+	}
+	if rec.Avatar != "" {
+		avatar := rec.Avatar
+		user.Avatar = &avatar
+	}
+	if rec.EmailVerified {
+		verifiedAt := time.Now()
+		if rec.CreatedAt != nil {
+			verifiedAt = *rec.CreatedAt
+		}
+		user.EmailVerifiedAt = &verifiedAt
+	}
+	if rec.CreatedAt != nil {
+		user.CreatedAt = *rec.CreatedAt
+	}
+	return user
+}
+
+// encodePassword chuyển hash gốc sang dạng lưu trong cột password, rỗng nghĩa là user phải đặt lại password
+func encodePassword(hash *PasswordHash, opts Options) (string, error) {
+	if opts.PasswordMode == PasswordModeReset || hash == nil || hash.Hash == "" {
+		return "", nil
+	}
+
+	switch hash.Algorithm {
+	case AlgorithmBcrypt:
+		if !strings.HasPrefix(hash.Hash, "$2a$") && !strings.HasPrefix(hash.Hash, "$2b$") && !strings.HasPrefix(hash.Hash, "$2y$") {
+			return "", fmt.Errorf("invalid bcrypt hash")
+		}
+		return hash.Hash, nil
+	case AlgorithmFirebaseScrypt:
+		cfg := opts.FirebaseScrypt
+		if cfg == nil || cfg.SignerKey == "" {
+			return "", fmt.Errorf("firebase scrypt hash config is required to preserve passwords")
+		}
+		return utils.EncodeFirebaseScryptHash(hash.Hash, hash.Salt, cfg.SignerKey, cfg.SaltSeparator, cfg.Rounds, cfg.MemCost), nil
+	default:
+		return "", fmt.Errorf("unsupported password hash algorithm: %s", hash.Algorithm)
+	}
+}
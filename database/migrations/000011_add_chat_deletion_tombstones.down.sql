@@ -0,0 +1,7 @@
+ALTER TABLE conversation_participants DROP COLUMN IF EXISTS cleared_at;
+
+DROP TABLE IF EXISTS message_deletions;
+
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS fk_messages_deleted_for_everyone_by;
+ALTER TABLE messages DROP COLUMN IF EXISTS deleted_for_everyone_by;
+ALTER TABLE messages DROP COLUMN IF EXISTS deleted_for_everyone_at;
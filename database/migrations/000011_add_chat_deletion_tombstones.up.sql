@@ -0,0 +1,21 @@
+-- Xóa tin nhắn cho mọi người: giữ lại row làm tombstone, ẩn nội dung khi trả về
+ALTER TABLE messages ADD COLUMN deleted_for_everyone_at TIMESTAMP;
+ALTER TABLE messages ADD COLUMN deleted_for_everyone_by UUID;
+ALTER TABLE messages ADD CONSTRAINT fk_messages_deleted_for_everyone_by
+    FOREIGN KEY (deleted_for_everyone_by) REFERENCES users(id) ON DELETE SET NULL;
+
+-- Xóa tin nhắn cho riêng mình: mỗi row là một tombstone theo participant
+CREATE TABLE IF NOT EXISTS message_deletions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    message_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (message_id) REFERENCES messages(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_message_deletions_message_user ON message_deletions(message_id, user_id);
+CREATE INDEX idx_message_deletions_user_id ON message_deletions(user_id);
+
+-- Xóa conversation cho riêng mình: ẩn toàn bộ tin nhắn trước cleared_at
+ALTER TABLE conversation_participants ADD COLUMN cleared_at TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Hash legacy import từ hệ thống khác (Firebase scrypt kèm tham số) dài hơn bcrypt
+ALTER TABLE users ALTER COLUMN password TYPE VARCHAR(512);
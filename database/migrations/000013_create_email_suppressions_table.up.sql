@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    email VARCHAR(255) NOT NULL,
+    reason VARCHAR(50) NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    details TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_email_suppressions_email ON email_suppressions(email);
+CREATE INDEX idx_email_suppressions_reason ON email_suppressions(reason);
@@ -0,0 +1,16 @@
+-- Append-only event log của chat, sequence tăng dần toàn cục dùng cho sync API (delta since sequence N)
+CREATE TABLE IF NOT EXISTS chat_events (
+    sequence BIGSERIAL PRIMARY KEY,
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    conversation_id UUID NOT NULL,
+    message_id UUID,
+    event_type VARCHAR(50) NOT NULL,
+    actor_id UUID NOT NULL,
+    visible_to UUID, -- NULL: mọi participant; khác NULL: chỉ user này (xóa/khôi phục phía mình)
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_chat_events_id ON chat_events(id);
+CREATE INDEX idx_chat_events_conversation_sequence ON chat_events(conversation_id, sequence);
+CREATE INDEX idx_chat_events_message_id ON chat_events(message_id);
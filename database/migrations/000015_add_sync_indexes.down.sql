@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_message_deletions_user_created_at;
+DROP INDEX IF EXISTS idx_friend_requests_updated_at;
+DROP INDEX IF EXISTS idx_friendships_user_changed_at;
+DROP INDEX IF EXISTS idx_conversation_participants_conversation_changed_at;
+DROP INDEX IF EXISTS idx_messages_conversation_changed_at;
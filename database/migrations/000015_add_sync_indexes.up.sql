@@ -0,0 +1,10 @@
+-- Index cho delta sync (GET /api/v1/sync): đọc thay đổi theo (changed_at, id),
+-- changed_at = GREATEST(updated_at, deleted_at) để bắt cả bản ghi bị xóa mềm
+CREATE INDEX IF NOT EXISTS idx_messages_conversation_changed_at
+    ON messages(conversation_id, (GREATEST(updated_at, deleted_at)), id);
+CREATE INDEX IF NOT EXISTS idx_conversation_participants_conversation_changed_at
+    ON conversation_participants(conversation_id, (GREATEST(updated_at, deleted_at)), id);
+CREATE INDEX IF NOT EXISTS idx_friendships_user_changed_at
+    ON friendships(user_id, (GREATEST(updated_at, deleted_at)), id);
+CREATE INDEX IF NOT EXISTS idx_friend_requests_updated_at ON friend_requests(updated_at, id);
+CREATE INDEX IF NOT EXISTS idx_message_deletions_user_created_at ON message_deletions(user_id, created_at, id);
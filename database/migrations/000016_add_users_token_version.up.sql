@@ -0,0 +1,2 @@
+-- token_version: tăng khi logout all / đổi password, access/refresh token mang version cũ bị từ chối
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
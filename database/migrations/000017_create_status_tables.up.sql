@@ -0,0 +1,29 @@
+-- Kết quả health check định kỳ của từng component (api, database, cache, push, storage) cho status page
+CREATE TABLE IF NOT EXISTS status_checks (
+    id BIGSERIAL PRIMARY KEY,
+    component VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT,
+    checked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_status_checks_component_checked_at ON status_checks(component, checked_at);
+CREATE INDEX idx_status_checks_checked_at ON status_checks(checked_at);
+
+-- Sự cố do admin ghi nhận, hiển thị trên status page cùng lịch sử health check
+CREATE TABLE IF NOT EXISTS status_incidents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    impact VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    components JSONB NOT NULL DEFAULT '[]',
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_status_incidents_started_at ON status_incidents(started_at);
+CREATE INDEX idx_status_incidents_resolved_at ON status_incidents(resolved_at);
@@ -0,0 +1,9 @@
+-- Dung lượng storage đã dùng theo owner (user), cập nhật khi upload/xóa file để giới hạn quota
+CREATE TABLE IF NOT EXISTS storage_usage (
+    owner_id VARCHAR(100) PRIMARY KEY,
+    used_bytes BIGINT NOT NULL DEFAULT 0,
+    file_count BIGINT NOT NULL DEFAULT 0,
+    quota_bytes BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,15 @@
+-- Transactional outbox: message được ghi cùng transaction với dữ liệu nghiệp vụ, publisher đẩy sang queue backend sau
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    queue VARCHAR(255) NOT NULL,
+    message JSONB NOT NULL, -- queue.Message
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    available_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP, -- Publish lại sau thời điểm này khi lỗi
+    published_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Publisher chỉ quét message chưa publish
+CREATE INDEX idx_outbox_messages_pending ON outbox_messages(available_at, created_at) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_messages_published_at ON outbox_messages(published_at) WHERE published_at IS NOT NULL;
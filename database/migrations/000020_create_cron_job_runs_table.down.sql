@@ -0,0 +1 @@
+DROP TABLE IF EXISTS cron_job_runs;
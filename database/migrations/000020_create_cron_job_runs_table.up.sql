@@ -0,0 +1,15 @@
+-- Lịch sử chạy cron job: mỗi lần chạy (gồm retry) 1 dòng, admin xem qua /api/v1/cron-jobs
+CREATE TABLE IF NOT EXISTS cron_job_runs (
+    id BIGSERIAL PRIMARY KEY,
+    job_name VARCHAR(100) NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    success BOOLEAN NOT NULL,
+    error TEXT,
+    retries INT NOT NULL DEFAULT 0,
+    host VARCHAR(255) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_cron_job_runs_job_name_started_at ON cron_job_runs(job_name, started_at);
+CREATE INDEX idx_cron_job_runs_started_at ON cron_job_runs(started_at);
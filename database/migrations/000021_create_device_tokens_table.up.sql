@@ -0,0 +1,14 @@
+-- FCM token của thiết bị, push notification tới user được gửi tới mọi thiết bị của user
+CREATE TABLE IF NOT EXISTS device_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    token VARCHAR(512) NOT NULL,
+    platform VARCHAR(20) NOT NULL,
+    last_seen TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX idx_device_tokens_token ON device_tokens(token);
+CREATE INDEX idx_device_tokens_user_id ON device_tokens(user_id);
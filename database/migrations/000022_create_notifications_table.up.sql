@@ -0,0 +1,17 @@
+-- Notification center: mỗi push gửi tới user được lưu lại, client hiển thị inbox kể cả khi push không tới được thiết bị
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body TEXT,
+    data JSONB,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Inbox của user, mới nhất trước
+CREATE INDEX idx_notifications_user_created ON notifications(user_id, created_at DESC);
+-- Đếm chưa đọc
+CREATE INDEX idx_notifications_user_unread ON notifications(user_id) WHERE read_at IS NULL;
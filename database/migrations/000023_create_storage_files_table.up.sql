@@ -0,0 +1,9 @@
+-- Owner của từng file upload (user), /storages chỉ cho owner tải file không gắn vào avatar/tin nhắn
+CREATE TABLE IF NOT EXISTS storage_files (
+    key VARCHAR(500) PRIMARY KEY,
+    owner_id VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_storage_files_owner ON storage_files(owner_id);
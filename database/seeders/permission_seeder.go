@@ -1,7 +1,7 @@
 package seeders
 
 import (
-	model "api-core/internal/models"
+	model "github.com/anhnq996/go-api-core/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -39,6 +39,13 @@ func SeedPermissions(db *gorm.DB) error {
 			Description: "Can delete users",
 			Module:      "users",
 		},
+		{
+			ID:          uuid.New(),
+			Name:        "users.impersonate",
+			DisplayName: "Impersonate Users",
+			Description: "Can sign in as another user for support and debugging",
+			Module:      "users",
+		},
 
 		// Role permissions
 		{
@@ -72,6 +79,72 @@ func SeedPermissions(db *gorm.DB) error {
 			Module:      "permissions",
 		},
 
+		// Status page permissions
+		{
+			ID:          uuid.New(),
+			Name:        "status.manage",
+			DisplayName: "Manage Status Incidents",
+			Description: "Can create, update, delete incidents on the public status page",
+			Module:      "status",
+		},
+
+		// Queue admin permissions
+		{
+			ID:          uuid.New(),
+			Name:        "queues.view",
+			DisplayName: "View Queues",
+			Description: "Can view queue depth, oldest message age and dead letters",
+			Module:      "queues",
+		},
+		{
+			ID:          uuid.New(),
+			Name:        "queues.manage",
+			DisplayName: "Manage Queues",
+			Description: "Can purge, pause, resume queues and requeue dead letters",
+			Module:      "queues",
+		},
+
+		// Cron job permissions
+		{
+			ID:          uuid.New(),
+			Name:        "cron.view",
+			DisplayName: "View Cron Jobs",
+			Description: "Can view cron job schedules, lock status, run history and errors",
+			Module:      "cron",
+		},
+		{
+			ID:          uuid.New(),
+			Name:        "cron.manage",
+			DisplayName: "Manage Cron Jobs",
+			Description: "Can trigger, pause and resume cron jobs",
+			Module:      "cron",
+		},
+
+		// Log level permissions
+		{
+			ID:          uuid.New(),
+			Name:        "logs.view",
+			DisplayName: "View Log Levels",
+			Description: "Can view configured and runtime log levels of each module",
+			Module:      "logs",
+		},
+		{
+			ID:          uuid.New(),
+			Name:        "logs.manage",
+			DisplayName: "Manage Log Levels",
+			Description: "Can change log levels of modules at runtime",
+			Module:      "logs",
+		},
+
+		// Debug permissions
+		{
+			ID:          uuid.New(),
+			Name:        "debug.profile",
+			DisplayName: "Capture Profiles",
+			Description: "Can capture CPU/heap profiles and runtime variables via /debug",
+			Module:      "debug",
+		},
+
 		// Profile permissions
 		{
 			ID:          uuid.New(),
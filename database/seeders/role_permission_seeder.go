@@ -1,7 +1,7 @@
 package seeders
 
 import (
-	model "api-core/internal/models"
+	model "github.com/anhnq996/go-api-core/internal/models"
 
 	"gorm.io/gorm"
 )
@@ -19,10 +19,19 @@ func SeedRolePermissions(db *gorm.DB) error {
 			"users.create",
 			"users.update",
 			"users.delete",
+			"users.impersonate",
 			"roles.view",
 			"roles.manage",
 			"permissions.view",
 			"permissions.manage",
+			"status.manage",
+			"queues.view",
+			"queues.manage",
+			"cron.view",
+			"cron.manage",
+			"logs.view",
+			"logs.manage",
+			"debug.profile",
 			"profile.view",
 			"profile.update",
 		},
@@ -1,7 +1,7 @@
 package seeders
 
 import (
-	model "api-core/internal/models"
+	model "github.com/anhnq996/go-api-core/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -1,10 +1,11 @@
 package seeders
 
 import (
-	model "api-core/internal/models"
-	"api-core/pkg/utils"
 	"fmt"
 
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
 	"gorm.io/gorm"
 )
 
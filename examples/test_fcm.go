@@ -9,7 +9,7 @@ import (
 	"strings"
 	"time"
 
-	"api-core/pkg/fcm"
+	"github.com/anhnq996/go-api-core/pkg/fcm"
 
 	"firebase.google.com/go/v4/messaging"
 )
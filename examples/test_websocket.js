@@ -4,8 +4,9 @@ const WebSocket = require('ws');
 console.log('🚀 WebSocket Test Script - ApiCore');
 console.log('===================================');
 
-// Create WebSocket connection
-const ws = new WebSocket('ws://localhost:3000/ws?user_id=test_user_123');
+// Create WebSocket connection (ACCESS_TOKEN from /api/v1/auth/login, user_id only works with SOCKET_AUTH_REQUIRED=false)
+const headers = process.env.ACCESS_TOKEN ? { Authorization: `Bearer ${process.env.ACCESS_TOKEN}` } : {};
+const ws = new WebSocket('ws://localhost:3000/ws?user_id=test_user_123', { headers });
 
 ws.on('open', function () {
   console.log('✅ Connected to WebSocket server');
@@ -29,7 +30,7 @@ ws.on('open', function () {
     }));
   }, 1000);
 
-  // Test 3: Send broadcast message
+  // Test 3: Send broadcast message (rejected with room_error unless SOCKET_ALLOW_CLIENT_BROADCAST=true)
   setTimeout(() => {
     console.log('\n🧪 Test 3: Send Broadcast Message');
     ws.send(JSON.stringify({
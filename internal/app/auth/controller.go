@@ -4,11 +4,13 @@ import (
 	"mime/multipart"
 	"net/http"
 
-	"api-core/pkg/i18n"
-	"api-core/pkg/jwt"
-	"api-core/pkg/response"
-	"api-core/pkg/validator"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/pkg/validator"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
@@ -87,6 +89,32 @@ func (h *Handler) LogoutAll(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, statusCode, *resp)
 }
 
+// ChangePassword - POST /auth/change-password
+func (h *Handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeTokenMissing)
+		return
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeInvalidInput, nil)
+		return
+	}
+
+	var input ChangePasswordRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.ChangePassword(r.Context(), id, input.CurrentPassword, input.NewPassword)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
 // GetMe - GET /auth/me
 func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
 	lang := i18n.GetLanguageFromContext(r.Context())
@@ -108,6 +136,40 @@ func (h *Handler) GetMe(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, statusCode, *resp)
 }
 
+// Impersonate - POST /auth/impersonate
+func (h *Handler) Impersonate(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	claims := jwt.GetClaimsFromContext(r.Context())
+	if claims == nil {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	var input ImpersonateRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.Impersonate(r.Context(), claims, input.UserID)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// StopImpersonation - POST /auth/stop-impersonation
+func (h *Handler) StopImpersonation(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	claims := jwt.GetClaimsFromContext(r.Context())
+	token := jwt.ExtractTokenFromHeader(r)
+	if claims == nil || token == "" {
+		response.Unauthorized(w, lang, response.CodeTokenMissing)
+		return
+	}
+
+	resp := h.service.StopImpersonation(r.Context(), claims, token)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
 // Register - POST /auth/register
 func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	var input RegisterRequest
@@ -128,3 +190,102 @@ func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
 	statusCode := response.GetHTTPStatusCode(resp.Code)
 	response.JSON(w, statusCode, *resp)
 }
+
+// socialVerifierCookie cookie HttpOnly giữ verifier của state cho client không gửi code_challenge
+const socialVerifierCookie = "social_login_verifier"
+
+// SocialRedirect - GET /auth/social/{provider}/redirect
+// Client tự tạo PKCE gửi ?code_challenge=base64url(sha256(verifier)) và gửi lại verifier khi callback,
+// còn lại verifier được tạo và giữ trong cookie HttpOnly của trình duyệt
+func (h *Handler) SocialRedirect(w http.ResponseWriter, r *http.Request) {
+	provider := chi.URLParam(r, "provider")
+
+	challenge := r.URL.Query().Get("code_challenge")
+	if challenge == "" {
+		verifier := NewSocialVerifier()
+		challenge = SocialChallenge(verifier)
+		http.SetCookie(w, &http.Cookie{
+			Name:     socialVerifierCookie,
+			Value:    verifier,
+			Path:     "/",
+			MaxAge:   int(socialStateTTL.Seconds()),
+			HttpOnly: true,
+			Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+			SameSite: http.SameSiteLaxMode, // Lax: cookie vẫn được gửi khi provider redirect về callback
+		})
+	}
+
+	resp := h.service.SocialRedirect(r.Context(), provider, challenge)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// SocialCallback - GET /auth/social/{provider}/callback (redirect từ provider)
+func (h *Handler) SocialCallback(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	provider := chi.URLParam(r, "provider")
+
+	code := r.URL.Query().Get("code")
+	state := r.URL.Query().Get("state")
+	if code == "" || state == "" {
+		response.BadRequest(w, lang, response.CodeInvalidInput, nil)
+		return
+	}
+
+	verifier := utils.GetCookie(r, socialVerifierCookie)
+	utils.DeleteCookie(w, socialVerifierCookie)
+
+	resp := h.service.SocialLogin(r.Context(), provider, code, state, verifier)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// SocialLogin - POST /auth/social/{provider}/callback (mobile/web client gửi code)
+func (h *Handler) SocialLogin(w http.ResponseWriter, r *http.Request) {
+	var input SocialLoginRequest
+
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	provider := chi.URLParam(r, "provider")
+
+	// Verifier của PKCE trong body, client dùng cookie thì lấy từ cookie
+	verifier := input.CodeVerifier
+	if verifier == "" {
+		verifier = utils.GetCookie(r, socialVerifierCookie)
+	}
+	utils.DeleteCookie(w, socialVerifierCookie)
+
+	resp := h.service.SocialLogin(r.Context(), provider, input.Code, input.State, verifier)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// SendMagicLink - POST /auth/magic-link
+func (h *Handler) SendMagicLink(w http.ResponseWriter, r *http.Request) {
+	var input MagicLinkRequest
+
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.SendMagicLink(r.Context(), input.Email)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// VerifyMagicLink - GET /auth/magic-link/verify?token=...
+func (h *Handler) VerifyMagicLink(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		response.BadRequest(w, lang, response.CodeInvalidInput, nil)
+		return
+	}
+
+	resp := h.service.VerifyMagicLink(r.Context(), token)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
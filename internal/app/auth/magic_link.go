@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/email"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrMagicLinkInvalid = errors.New("invalid magic link token")
+	ErrMagicLinkExpired = errors.New("magic link token expired")
+)
+
+// MagicLinkConfig cấu hình magic link
+type MagicLinkConfig struct {
+	Secret       string
+	TTL          time.Duration
+	VerifyURL    string
+	TemplatePath string
+}
+
+// MagicLink ký/verify token của magic link và gửi email
+// Token: base64url(user_id.nonce.expires_unix).base64url(hmac_sha256)
+type MagicLink struct {
+	secret       []byte
+	ttl          time.Duration
+	verifyURL    string
+	templatePath string
+	mailer       email.EmailService
+}
+
+// magicLinkClaims nội dung token sau khi verify chữ ký
+type magicLinkClaims struct {
+	UserID    uuid.UUID
+	Nonce     string
+	ExpiresAt time.Time
+}
+
+// NewMagicLink tạo magic link
+func NewMagicLink(cfg MagicLinkConfig, mailer email.EmailService) *MagicLink {
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		// Không có secret (chỉ dùng RSA cho JWT): sinh ngẫu nhiên, link mất hiệu lực khi restart
+		secret = make([]byte, 32)
+		rand.Read(secret)
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 15 * time.Minute
+	}
+
+	return &MagicLink{
+		secret:       secret,
+		ttl:          cfg.TTL,
+		verifyURL:    cfg.VerifyURL,
+		templatePath: cfg.TemplatePath,
+		mailer:       mailer,
+	}
+}
+
+// TTL thời gian sống của link
+func (m *MagicLink) TTL() time.Duration {
+	return m.ttl
+}
+
+// Sign tạo token cho user, nonce dùng để đảm bảo token chỉ dùng 1 lần
+func (m *MagicLink) Sign(userID uuid.UUID, nonce string, expiresAt time.Time) string {
+	payload := userID.String() + "." + nonce + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(m.sign(encoded))
+}
+
+// Parse verify chữ ký và thời hạn của token tại thời điểm now
+func (m *MagicLink) Parse(token string, now time.Time) (*magicLinkClaims, error) {
+	encoded, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	expectedSig, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(expectedSig, m.sign(encoded)) {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrMagicLinkInvalid
+	}
+	parts := strings.Split(string(payload), ".")
+	if len(parts) != 3 {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	userID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return nil, ErrMagicLinkInvalid
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return nil, ErrMagicLinkInvalid
+	}
+
+	claims := &magicLinkClaims{UserID: userID, Nonce: parts[1], ExpiresAt: time.Unix(expires, 0)}
+	if now.After(claims.ExpiresAt) {
+		return nil, ErrMagicLinkExpired
+	}
+	return claims, nil
+}
+
+// URL tạo link đầy đủ gửi trong email
+func (m *MagicLink) URL(token string) string {
+	separator := "?"
+	if strings.Contains(m.verifyURL, "?") {
+		separator = "&"
+	}
+	return m.verifyURL + separator + "token=" + url.QueryEscape(token)
+}
+
+// Send gửi email chứa magic link
+func (m *MagicLink) Send(to, name, link string) error {
+	message := &email.EmailMessage{
+		To:       []string{to},
+		Subject:  "Your sign-in link",
+		TextBody: "Use this link to sign in: " + link,
+	}
+	data := map[string]interface{}{
+		"Name":      name,
+		"Email":     to,
+		"LoginURL":  link,
+		"ExpiresIn": int(m.ttl.Minutes()),
+	}
+	return m.mailer.SendTemplate(message, m.templatePath, data)
+}
+
+func (m *MagicLink) sign(data string) []byte {
+	mac := hmac.New(sha256.New, m.secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
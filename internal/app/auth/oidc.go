@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/anhnq996/go-api-core/pkg/oidc"
+)
+
+// ProviderOIDC tên mặc định của OIDC provider (SSO doanh nghiệp: Keycloak, Azure AD...)
+const ProviderOIDC = "oidc"
+
+// OIDCConfig cấu hình OIDC relying party dùng như một social provider
+type OIDCConfig struct {
+	Name        string            // Tên provider trong URL /auth/social/{provider}, default: oidc
+	Provider    *oidc.Provider    // Relying party của issuer
+	Claims      oidc.ClaimMapping // Map claims của ID token sang user local
+	RoleMapping oidc.RoleMapping  // Map role/group phía issuer sang role local, rỗng: không quản lý role
+	TrustEmail  bool              // Coi email từ issuer là đã verify (issuer không gửi email_verified, vd. Azure AD)
+}
+
+// oidcProvider SocialProvider cho OIDC: đăng nhập bằng authorization code flow và xác thực ID token
+type oidcProvider struct {
+	config OIDCConfig
+}
+
+// NewOIDCProvider tạo OIDC provider
+func NewOIDCProvider(cfg OIDCConfig) SocialProvider {
+	if cfg.Name == "" {
+		cfg.Name = ProviderOIDC
+	}
+	return &oidcProvider{config: cfg}
+}
+
+// Name tên provider
+func (p *oidcProvider) Name() string {
+	return p.config.Name
+}
+
+// AuthCodeURL tạo URL đăng nhập của issuer, nonce gắn với state để chống replay ID token
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	return p.config.Provider.AuthCodeURL(ctx, state, oidcNonce(state))
+}
+
+// FetchUser đổi code lấy ID token, xác thực và map claims sang SocialUser
+func (p *oidcProvider) FetchUser(ctx context.Context, code, state string) (*SocialUser, error) {
+	idToken, err := p.config.Provider.Exchange(ctx, code, oidcNonce(state))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSocialExchangeFailed, err)
+	}
+
+	info := p.config.Claims.Map(idToken)
+	user := &SocialUser{
+		ID:            info.Subject,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified || (p.config.TrustEmail && info.Email != ""),
+		Name:          info.Name,
+		Role:          p.config.RoleMapping.Resolve(info.Roles),
+	}
+	if info.Picture != "" {
+		user.Avatar = &info.Picture
+	}
+	return user, nil
+}
+
+// oidcNonce nonce suy ra từ state (state ngẫu nhiên, chỉ dùng 1 lần và được lưu phía server)
+func oidcNonce(state string) string {
+	sum := sha256.Sum256([]byte("oidc-nonce:" + state))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
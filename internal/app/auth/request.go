@@ -18,6 +18,23 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+// SocialLoginRequest request cho social login callback
+type SocialLoginRequest struct {
+	Code         string `json:"code" validate:"required"`
+	State        string `json:"state" validate:"required"`
+	CodeVerifier string `json:"code_verifier"` // Verifier của code_challenge gửi lúc redirect, rỗng: dùng cookie
+}
+
+// MagicLinkRequest request cho gửi magic link
+type MagicLinkRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ImpersonateRequest request cho admin impersonate user
+type ImpersonateRequest struct {
+	UserID string `json:"user_id" validate:"required,uuid"`
+}
+
 // UpdateProfileRequest request cho update profile
 type UpdateProfileRequest struct {
 	Name   string  `json:"name" validate:"omitempty,min=2,max=100"`
@@ -1,25 +1,40 @@
 package auth
 
 import (
-	"api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
 
 	"github.com/go-chi/chi/v5"
 )
 
-// RegisterRoutes đăng ký auth routes
-func RegisterRoutes(r chi.Router, handler *Handler, jwtManager *jwt.Manager, blacklist *jwt.Blacklist) {
-	// Public routes
+// RegisterPublicRoutes đăng ký auth routes không cần đăng nhập
+func RegisterPublicRoutes(r chi.Router, handler *Handler) {
 	r.Post("/auth/login", handler.Login)
 	r.Post("/auth/register", handler.Register)
 	r.Post("/auth/refresh", handler.RefreshToken)
 
-	// Protected routes
-	r.Group(func(r chi.Router) {
-		// Apply JWT middleware với blacklist
-		r.Use(jwtManager.MiddlewareWithBlacklist(blacklist))
+	// Social login (OAuth2)
+	r.Get("/auth/social/{provider}/redirect", handler.SocialRedirect)
+	r.Get("/auth/social/{provider}/callback", handler.SocialCallback)
+	r.Post("/auth/social/{provider}/callback", handler.SocialLogin)
 
-		r.Get("/auth/me", handler.GetMe)
-		r.Post("/auth/logout", handler.Logout)
-		r.Post("/auth/logout-all", handler.LogoutAll)
-	})
+	// Passwordless login (magic link)
+	r.Post("/auth/magic-link", handler.SendMagicLink)
+	r.Get("/auth/magic-link/verify", handler.VerifyMagicLink)
+}
+
+// RegisterRoutes đăng ký auth routes cần đăng nhập (JWT middleware do route group áp dụng)
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Get("/auth/me", handler.GetMe)
+	r.Post("/auth/logout", handler.Logout)
+	r.Post("/auth/logout-all", handler.LogoutAll)
+	r.Post("/auth/change-password", handler.ChangePassword)
+
+	// Token impersonation gửi request tới đây để quay lại tài khoản admin
+	r.Post("/auth/stop-impersonation", handler.StopImpersonation)
+}
+
+// RegisterAdminRoutes đăng ký auth routes quản trị
+func RegisterAdminRoutes(r chi.Router, handler *Handler, perm *jwt.PermissionChecker) {
+	// Admin impersonation: token ngắn hạn, mọi request đều được log qua actionEvent
+	r.With(perm.Require("users.impersonate")).Post("/auth/impersonate", handler.Impersonate)
 }
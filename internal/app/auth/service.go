@@ -2,19 +2,30 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"mime/multipart"
 	"time"
 
-	model "api-core/internal/models"
-	repository "api-core/internal/repositories"
-	"api-core/pkg/i18n"
-	"api-core/pkg/jwt"
-	"api-core/pkg/response"
-	"api-core/pkg/storage"
-	"api-core/pkg/utils"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/securitylog"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/utils"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 var (
@@ -23,26 +34,57 @@ var (
 	ErrUserInactive       = errors.New("user is inactive")
 )
 
+// socialStateTTL thời gian sống của OAuth2 state (chống CSRF)
+const socialStateTTL = 10 * time.Minute
+
+const (
+	// impersonatePermission permission cho phép impersonate, user có permission này không thể bị impersonate
+	impersonatePermission = "users.impersonate"
+	// impersonationJob job action event cho bắt đầu/kết thúc impersonation
+	impersonationJob = "impersonation_events"
+)
+
 // Service xử lý business logic cho auth
 type Service struct {
-	userRepo       repository.UserRepository
-	jwtManager     *jwt.Manager
-	blacklist      *jwt.Blacklist
-	storageManager *storage.StorageManager
+	userRepo        repository.UserRepository
+	socialRepo      repository.SocialAccountRepository
+	jwtManager      *jwt.Manager
+	blacklist       *jwt.Blacklist
+	storageManager  *storage.StorageManager
+	cache           cache.Cache
+	socialProviders SocialProviders
+	magicLink       *MagicLink
+	roleRepo        repository.RoleRepository
+	permissions     *jwt.PermissionChecker
+	tokenVersions   *jwt.TokenVersions
 }
 
 // NewService tạo auth service mới
 func NewService(
 	userRepo repository.UserRepository,
+	socialRepo repository.SocialAccountRepository,
 	jwtManager *jwt.Manager,
 	blacklist *jwt.Blacklist,
 	storageManager *storage.StorageManager,
+	cacheClient cache.Cache,
+	socialProviders SocialProviders,
+	magicLink *MagicLink,
+	roleRepo repository.RoleRepository,
+	permissions *jwt.PermissionChecker,
+	tokenVersions *jwt.TokenVersions,
 ) *Service {
 	return &Service{
-		userRepo:       userRepo,
-		jwtManager:     jwtManager,
-		blacklist:      blacklist,
-		storageManager: storageManager,
+		userRepo:        userRepo,
+		socialRepo:      socialRepo,
+		jwtManager:      jwtManager,
+		blacklist:       blacklist,
+		storageManager:  storageManager,
+		cache:           cacheClient,
+		socialProviders: socialProviders,
+		magicLink:       magicLink,
+		roleRepo:        roleRepo,
+		permissions:     permissions,
+		tokenVersions:   tokenVersions,
 	}
 }
 
@@ -65,6 +107,15 @@ type UserResponse struct {
 	Permissions []string      `json:"permissions"`
 }
 
+// ImpersonationResponse response khi admin impersonate user, không có refresh token
+type ImpersonationResponse struct {
+	User         *UserResponse `json:"user"`
+	Impersonator uuid.UUID     `json:"impersonator"`
+	AccessToken  string        `json:"access_token"`
+	ExpiresAt    string        `json:"expires_at"`
+	TokenType    string        `json:"token_type"`
+}
+
 // RoleResponse role info trong response
 type RoleResponse struct {
 	ID          uuid.UUID `json:"id"`
@@ -75,6 +126,7 @@ type RoleResponse struct {
 // Login xử lý login
 func (s *Service) Login(ctx context.Context, email, password string) *response.Response {
 	lang := i18n.GetLanguageFromContext(ctx)
+	securitylog.SetUser(ctx, email) // Đăng nhập thất bại được ghi vào security log kèm email
 
 	// Get user by email
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
@@ -92,6 +144,13 @@ func (s *Service) Login(ctx context.Context, email, password string) *response.R
 		return response.UnauthorizedResponse(lang, response.CodeInvalidCredentials)
 	}
 
+	// Hash legacy (user import từ hệ thống khác) được chuyển sang bcrypt sau lần login đầu
+	if utils.NeedsRehash(user.Password) {
+		if hashed, err := utils.HashPassword(password); err == nil {
+			s.userRepo.UpdateWhere(ctx, "id = ?", map[string]interface{}{"password": hashed}, user.ID)
+		}
+	}
+
 	// Get user with role and permissions
 	userWithRole, err := s.userRepo.GetUserWithRole(ctx, user.ID)
 	if err != nil {
@@ -108,13 +167,12 @@ func (s *Service) Login(ctx context.Context, email, password string) *response.R
 	}
 
 	// Generate JWT tokens
-	tokenPair, err := s.jwtManager.GenerateTokenPair(
+	tokenPair, err := s.jwtManager.GenerateVersionedTokenPair(
 		user.ID.String(),
 		user.Email,
 		getRoleName(userWithRole.Role),
-		map[string]interface{}{
-			"name": user.Name,
-		},
+		user.TokenVersion,
+		tokenMetadata(user.Name, permissions),
 	)
 	if err != nil {
 		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
@@ -146,54 +204,50 @@ func (s *Service) Login(ctx context.Context, email, password string) *response.R
 func (s *Service) RefreshToken(ctx context.Context, refreshToken string) *response.Response {
 	lang := i18n.GetLanguageFromContext(ctx)
 
-	// Verify refresh token
-	userIDStr, err := s.jwtManager.VerifyRefreshToken(refreshToken)
-	if err != nil {
-		if err == jwt.ErrExpiredToken {
-			return response.UnauthorizedResponse(lang, response.CodeTokenExpired)
-		}
-		if err == jwt.ErrInvalidToken {
-			return response.UnauthorizedResponse(lang, response.CodeTokenInvalid)
+	// Role, email, permissions đọc lại từ database, không tin giá trị trong token cũ hay từ client
+	var user *model.User
+	var permissions []string
+	tokenPair, err := s.jwtManager.RefreshAccessToken(ctx, refreshToken, func(ctx context.Context, subject string) (*jwt.RefreshSubject, error) {
+		userID, err := uuid.Parse(subject)
+		if err != nil {
+			return nil, jwt.ErrUserNotFound
 		}
-		return response.UnauthorizedResponse(lang, response.CodeTokenInvalid)
-	}
 
-	userID, err := uuid.Parse(userIDStr)
-	if err != nil {
-		return response.UnauthorizedResponse(lang, response.CodeInvalidCredentials)
-	}
-
-	// Get user with role
-	user, err := s.userRepo.GetUserWithRole(ctx, userID)
-	if err != nil {
-		return response.NotFoundResponse(lang, response.CodeUserNotFound)
-	}
-
-	// Check if user is active
-	if !user.IsActive {
-		return response.ForbiddenResponse(lang, response.CodeAccountDisabled)
-	}
-
-	// Get permissions
-	var permissions []string
-	if user.RoleID != nil {
-		permissions, err = s.userRepo.GetUserPermissions(ctx, *user.RoleID)
+		user, err = s.userRepo.GetUserWithRole(ctx, userID)
 		if err != nil {
-			permissions = []string{}
+			return nil, jwt.ErrUserNotFound
 		}
-	}
 
-	// Generate new tokens
-	tokenPair, err := s.jwtManager.GenerateTokenPair(
-		user.ID.String(),
-		user.Email,
-		getRoleName(user.Role),
-		map[string]interface{}{
-			"name": user.Name,
-		},
-	)
+		permissions = []string{}
+		if user.RoleID != nil {
+			if loaded, err := s.userRepo.GetUserPermissions(ctx, *user.RoleID); err == nil {
+				permissions = loaded
+			}
+		}
+
+		return &jwt.RefreshSubject{
+			Email:        user.Email,
+			Role:         getRoleName(user.Role),
+			TokenVersion: user.TokenVersion,
+			Disabled:     !user.IsActive,
+			Metadata:     tokenMetadata(user.Name, permissions),
+		}, nil
+	})
 	if err != nil {
-		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+		switch {
+		case errors.Is(err, jwt.ErrExpiredToken):
+			return response.UnauthorizedResponse(lang, response.CodeTokenExpired)
+		case errors.Is(err, jwt.ErrTokenStoreUnavailable):
+			return response.ServiceUnavailableResponse(lang, response.CodeServiceUnavailable)
+		case errors.Is(err, jwt.ErrUserNotFound):
+			return response.NotFoundResponse(lang, response.CodeUserNotFound)
+		case errors.Is(err, jwt.ErrSubjectDisabled):
+			return response.ForbiddenResponse(lang, response.CodeAccountDisabled)
+		case errors.Is(err, jwt.ErrTokenRevoked):
+			// Refresh token cấp trước lần logout all / đổi password gần nhất
+			return response.UnauthorizedResponse(lang, response.CodeTokenRevoked)
+		}
+		return response.UnauthorizedResponse(lang, response.CodeTokenInvalid)
 	}
 
 	// Build response
@@ -215,35 +269,101 @@ func (s *Service) RefreshToken(ctx context.Context, refreshToken string) *respon
 	return response.SuccessResponse(lang, response.CodeTokenRefreshed, loginResp)
 }
 
-// Logout đăng xuất (blacklist token)
+// Logout đăng xuất (thu hồi access token hiện tại)
 func (s *Service) Logout(ctx context.Context, token string) *response.Response {
 	lang := i18n.GetLanguageFromContext(ctx)
 
-	// Get token expiry
+	if err := s.revokeAccessToken(token); err != nil {
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			return response.UnauthorizedResponse(lang, response.CodeTokenInvalid)
+		}
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeLogoutSuccess, nil)
+}
+
+// revokeAccessToken thu hồi access token: opaque token xóa khỏi store, JWT đưa vào blacklist đến khi hết hạn
+func (s *Service) revokeAccessToken(token string) error {
+	if s.jwtManager.IsOpaque(token) {
+		return s.jwtManager.RevokeOpaqueToken(token)
+	}
+
 	expiry, err := s.jwtManager.GetTokenExpiry(token)
 	if err != nil {
-		return response.UnauthorizedResponse(lang, response.CodeTokenInvalid)
+		return jwt.ErrInvalidToken
+	}
+	return s.blacklist.Add(token, expiry)
+}
+
+// LogoutAll đăng xuất tất cả devices
+func (s *Service) LogoutAll(ctx context.Context, userID uuid.UUID) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	// Phiên impersonation không được đăng xuất toàn bộ thiết bị của user thật
+	if jwt.GetImpersonatorFromContext(ctx) != "" {
+		return response.ForbiddenResponse(lang, response.CodeImpersonationNotAllowed)
 	}
 
-	// Add to blacklist
-	if err := s.blacklist.Add(token, expiry); err != nil {
+	// Tăng token_version: mọi access/refresh token đã cấp bị từ chối, đăng nhập lại vẫn được
+	if _, err := s.revokeTokens(ctx, userID); err != nil {
 		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
 	}
 
 	return response.SuccessResponse(lang, response.CodeLogoutSuccess, nil)
 }
 
-// LogoutAll đăng xuất tất cả devices
-func (s *Service) LogoutAll(ctx context.Context, userID uuid.UUID) *response.Response {
+// ChangePassword đổi password, thu hồi token trên mọi thiết bị và cấp token mới cho thiết bị hiện tại
+func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword, newPassword string) *response.Response {
 	lang := i18n.GetLanguageFromContext(ctx)
 
-	// Blacklist all user tokens (7 days - max refresh token duration)
-	expiry := utils.Now().Add(7 * 24 * time.Hour)
-	if err := s.blacklist.AddUserTokens(userID.String(), expiry); err != nil {
+	// Admin đang impersonate không được đổi password của user
+	if jwt.GetImpersonatorFromContext(ctx) != "" {
+		return response.ForbiddenResponse(lang, response.CodeImpersonationNotAllowed)
+	}
+
+	user, err := s.userRepo.GetUserWithRole(ctx, userID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeUserNotFound)
+	}
+
+	if !utils.CheckPassword(currentPassword, user.Password) {
+		return response.BadRequestResponse(lang, response.CodeInvalidCurrentPassword, nil)
+	}
+
+	hashed, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	if err := s.userRepo.UpdateWhere(ctx, "id = ?", map[string]interface{}{"password": hashed}, userID); err != nil {
 		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
 	}
 
-	return response.SuccessResponse(lang, response.CodeLogoutSuccess, nil)
+	version, err := s.revokeTokens(ctx, userID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	user.TokenVersion = version
+
+	loginResp, err := s.buildLoginResponse(ctx, user)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodePasswordChanged, loginResp)
+}
+
+// revokeTokens tăng token_version của user và cập nhật cache để token cũ bị từ chối ngay
+func (s *Service) revokeTokens(ctx context.Context, userID uuid.UUID) (int, error) {
+	version, err := s.userRepo.IncrementTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.tokenVersions.Set(ctx, userID.String(), version); err != nil {
+		// Cache cũ hết hạn sau TTL, token cũ vẫn dùng được đến lúc đó
+		logger.Errorf("Failed to cache token version of user %s: %v", userID, err)
+	}
+	return version, nil
 }
 
 // GetUserInfo lấy thông tin user hiện tại
@@ -308,7 +428,12 @@ func (s *Service) Register(ctx context.Context, name, email, password string, ro
 		uploadOptions.Path = "avatars"                               // Store in avatars folder
 
 		result, err := s.storageManager.UploadFile(ctx, avatarFile, uploadOptions)
-		if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrFileInfected):
+			return response.ErrorResponse(lang, response.CodeFileInfected, nil)
+		case errors.Is(err, storage.ErrScanFailed):
+			return response.ErrorResponse(lang, response.CodeFileScanFailed, nil)
+		case err != nil:
 			return response.InternalServerErrorResponse(lang, response.CodeFileUploadFailed)
 		}
 
@@ -327,8 +452,465 @@ func (s *Service) Register(ctx context.Context, name, email, password string, ro
 	return response.SuccessResponse(lang, response.CodeCreated, user)
 }
 
+// SocialRedirectResponse response chứa URL đăng nhập của provider
+type SocialRedirectResponse struct {
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+	State    string `json:"state"`
+}
+
+// socialState dữ liệu lưu trong cache cho mỗi state
+type socialState struct {
+	Provider  string `json:"provider"`
+	Challenge string `json:"challenge"` // SocialChallenge(verifier) của client đã tạo state
+}
+
+// SocialRedirect tạo URL đăng nhập với provider và lưu state vào cache.
+// State gắn với client qua challenge: callback phải gửi verifier tương ứng (cookie HttpOnly hoặc PKCE),
+// link callback chứa state/code của người khác không đăng nhập được vào trình duyệt khác (login CSRF).
+func (s *Service) SocialRedirect(ctx context.Context, providerName, challenge string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	provider, err := s.socialProviders.Get(providerName)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeSocialProviderNotSupported, nil)
+	}
+	if !validSocialChallenge(challenge) {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	state := uuid.NewString()
+	authURL, err := provider.AuthCodeURL(ctx, state)
+	if err != nil {
+		logger.Errorf("Failed to build %s login URL: %v", provider.Name(), err)
+		return response.ServiceUnavailableResponse(lang, response.CodeSocialProviderUnavailable)
+	}
+
+	data, err := json.Marshal(socialState{Provider: provider.Name(), Challenge: challenge})
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	if err := s.cache.Set(ctx, socialStateKey(state), string(data), socialStateTTL); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, &SocialRedirectResponse{
+		Provider: provider.Name(),
+		URL:      authURL,
+		State:    state,
+	})
+}
+
+// SocialLogin xử lý callback của provider: verify state và verifier của client đã tạo state,
+// lấy user info, liên kết tài khoản theo email và cấp token pair
+func (s *Service) SocialLogin(ctx context.Context, providerName, code, state, verifier string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	provider, err := s.socialProviders.Get(providerName)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeSocialProviderNotSupported, nil)
+	}
+
+	// State chỉ dùng được 1 lần, phải khớp provider và client đã tạo state
+	key := socialStateKey(state)
+	raw, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeSocialStateInvalid, nil)
+	}
+	s.cache.Del(ctx, key)
+
+	var stored socialState
+	if err := json.Unmarshal([]byte(raw), &stored); err != nil || stored.Provider != provider.Name() ||
+		verifier == "" || subtle.ConstantTimeCompare([]byte(SocialChallenge(verifier)), []byte(stored.Challenge)) != 1 {
+		return response.BadRequestResponse(lang, response.CodeSocialStateInvalid, nil)
+	}
+
+	socialUser, err := provider.FetchUser(ctx, code, state)
+	if err != nil || socialUser.ID == "" {
+		if err != nil {
+			logger.Warnf("Social login with %s failed: %v", provider.Name(), err)
+		}
+		return response.UnauthorizedResponse(lang, response.CodeSocialLoginFailed)
+	}
+
+	userID, resp := s.resolveSocialUser(ctx, lang, provider.Name(), socialUser)
+	if resp != nil {
+		return resp
+	}
+
+	if socialUser.Role != "" {
+		if err := s.syncSocialRole(ctx, userID, socialUser.Role); err != nil {
+			logger.Errorf("Failed to sync role %q of user %s from %s: %v", socialUser.Role, userID, provider.Name(), err)
+			return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+		}
+	}
+
+	user, err := s.userRepo.GetUserWithRole(ctx, userID)
+	if err != nil {
+		return response.ForbiddenResponse(lang, response.CodeAccountDisabled)
+	}
+
+	loginResp, err := s.buildLoginResponse(ctx, user)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	s.userRepo.UpdateLastLogin(ctx, user.ID)
+
+	return response.SuccessResponse(lang, response.CodeLoginSuccess, loginResp)
+}
+
+// resolveSocialUser tìm user đã liên kết với social account,
+// nếu chưa có thì liên kết theo email (tạo user mới nếu email chưa tồn tại)
+func (s *Service) resolveSocialUser(ctx context.Context, lang, provider string, socialUser *SocialUser) (uuid.UUID, *response.Response) {
+	account, err := s.socialRepo.FindByProvider(ctx, provider, socialUser.ID)
+	if err == nil {
+		user, err := s.userRepo.FindByID(ctx, account.UserID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && !user.IsActive) {
+			return uuid.Nil, response.ForbiddenResponse(lang, response.CodeAccountDisabled)
+		}
+		if err != nil {
+			return uuid.Nil, response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+		}
+		return user.ID, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+
+	// Chỉ liên kết tự động khi provider xác nhận email đã verify
+	if socialUser.Email == "" || !socialUser.EmailVerified {
+		return uuid.Nil, response.BadRequestResponse(lang, response.CodeSocialEmailUnverified, nil)
+	}
+
+	user, err := s.userRepo.FirstWhere(ctx, "email = ?", socialUser.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return uuid.Nil, response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+
+	if user == nil {
+		now := utils.Now()
+		name := socialUser.Name
+		if name == "" {
+			name = socialUser.Email
+		}
+		user = &model.User{
+			Name:            name,
+			Email:           socialUser.Email,
+			Avatar:          socialUser.Avatar,
+			EmailVerifiedAt: &now,
+			IsActive:        true,
+		}
+		if err := s.userRepo.Create(ctx, user); err != nil {
+			return uuid.Nil, response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+		}
+	} else if !user.IsActive {
+		return uuid.Nil, response.ForbiddenResponse(lang, response.CodeAccountDisabled)
+	}
+
+	email := socialUser.Email
+	if err := s.socialRepo.Create(ctx, &model.SocialAccount{
+		UserID:         user.ID,
+		Provider:       provider,
+		ProviderUserID: socialUser.ID,
+		Email:          &email,
+	}); err != nil {
+		return uuid.Nil, response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return user.ID, nil
+}
+
+// syncSocialRole gán role local do provider chỉ định (OIDC role mapping) nếu khác role hiện tại
+func (s *Service) syncSocialRole(ctx context.Context, userID uuid.UUID, roleName string) error {
+	role, err := s.roleRepo.FindByName(ctx, roleName)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Role mapping trỏ tới role chưa tạo: giữ role hiện tại
+		logger.Warnf("Social role %q does not exist, keeping current role of user %s", roleName, userID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.RoleID != nil && *user.RoleID == role.ID {
+		return nil
+	}
+
+	if err := s.userRepo.UpdateWhere(ctx, "id = ?", map[string]interface{}{"role_id": role.ID}, userID); err != nil {
+		return err
+	}
+	return s.permissions.Invalidate(ctx, userID.String())
+}
+
+// SendMagicLink tạo link đăng nhập 1 lần và gửi qua email
+// Luôn trả về thành công để không lộ email nào đã đăng ký
+func (s *Service) SendMagicLink(ctx context.Context, email string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil || !user.IsActive {
+		return response.SuccessResponse(lang, response.CodeMagicLinkSent, nil)
+	}
+
+	// Nonce lưu trong cache đến khi hết hạn, xóa khi link được dùng
+	nonce := uuid.NewString()
+	expiresAt := clock.FromContext(ctx).Now().Add(s.magicLink.TTL())
+	if err := s.cache.Set(ctx, magicLinkKey(nonce), user.ID.String(), s.magicLink.TTL()); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	link := s.magicLink.URL(s.magicLink.Sign(user.ID, nonce, expiresAt))
+	// Gửi email ở background để thời gian phản hồi không phụ thuộc email có tồn tại hay không
+	go func(to, name string) {
+		if err := s.magicLink.Send(to, name, link); err != nil {
+			logger.Errorf("Failed to send magic link email to user %s: %v", user.ID, err)
+		}
+	}(user.Email, user.Name)
+
+	return response.SuccessResponse(lang, response.CodeMagicLinkSent, nil)
+}
+
+// VerifyMagicLink đổi magic link token lấy token pair, mỗi link chỉ dùng được 1 lần
+func (s *Service) VerifyMagicLink(ctx context.Context, token string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	claims, err := s.magicLink.Parse(token, clock.FromContext(ctx).Now())
+	if errors.Is(err, ErrMagicLinkExpired) {
+		return response.UnauthorizedResponse(lang, response.CodeMagicLinkExpired)
+	}
+	if err != nil {
+		return response.UnauthorizedResponse(lang, response.CodeMagicLinkInvalid)
+	}
+
+	// Nonce phải còn trong cache và khớp user
+	key := magicLinkKey(claims.Nonce)
+	storedUserID, err := s.cache.Get(ctx, key)
+	if err != nil || storedUserID != claims.UserID.String() {
+		return response.UnauthorizedResponse(lang, response.CodeMagicLinkInvalid)
+	}
+
+	// Lock (SETNX) để 2 request đồng thời không dùng được cùng một link
+	acquired, err := s.cache.Lock(ctx, key+":used", time.Until(claims.ExpiresAt)+time.Minute)
+	if err != nil || !acquired {
+		return response.UnauthorizedResponse(lang, response.CodeMagicLinkInvalid)
+	}
+	s.cache.Del(ctx, key)
+
+	user, err := s.userRepo.GetUserWithRole(ctx, claims.UserID)
+	if err != nil || !user.IsActive {
+		return response.ForbiddenResponse(lang, response.CodeAccountDisabled)
+	}
+
+	// User mở được link trong email nghĩa là email đã được xác thực
+	if user.EmailVerifiedAt == nil {
+		s.userRepo.UpdateWhere(ctx, "id = ?", map[string]interface{}{"email_verified_at": utils.Now()}, user.ID)
+	}
+
+	loginResp, err := s.buildLoginResponse(ctx, user)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	s.userRepo.UpdateLastLogin(ctx, user.ID)
+
+	return response.SuccessResponse(lang, response.CodeLoginSuccess, loginResp)
+}
+
+// Impersonate cấp access token ngắn hạn để admin (claims hiện tại) đăng nhập dưới danh nghĩa user khác
+func (s *Service) Impersonate(ctx context.Context, claims *jwt.Claims, targetUserID string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	targetID, err := uuid.Parse(targetUserID)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	// Không cho impersonate lồng nhau hoặc impersonate chính mình
+	if claims.IsImpersonated() || claims.UserID == targetID.String() {
+		return response.ForbiddenResponse(lang, response.CodeImpersonationNotAllowed)
+	}
+
+	user, err := s.userRepo.GetUserWithRole(ctx, targetID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeUserNotFound)
+	}
+	if !user.IsActive {
+		return response.ForbiddenResponse(lang, response.CodeAccountDisabled)
+	}
+
+	var permissions []string
+	if user.RoleID != nil {
+		permissions, err = s.userRepo.GetUserPermissions(ctx, *user.RoleID)
+		if err != nil {
+			permissions = []string{}
+		}
+	}
+
+	// Không cho impersonate user có quyền impersonate (admin khác) để tránh leo thang đặc quyền
+	if jwt.HasPermission(permissions, impersonatePermission) {
+		return response.ForbiddenResponse(lang, response.CodeImpersonationNotAllowed)
+	}
+
+	accessToken, expiresAt, err := s.jwtManager.GenerateImpersonationToken(
+		user.ID.String(),
+		user.Email,
+		getRoleName(user.Role),
+		user.TokenVersion,
+		claims,
+		tokenMetadata(user.Name, permissions),
+	)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	impersonatorID, _ := uuid.Parse(claims.UserID)
+	s.logImpersonation(ctx, "impersonation_start", claims.UserID, user.ID.String(), map[string]interface{}{
+		"expires_at": expiresAt,
+	})
+
+	return response.SuccessResponse(lang, response.CodeImpersonationStarted, &ImpersonationResponse{
+		User: &UserResponse{
+			ID:          user.ID,
+			Name:        user.Name,
+			Email:       user.Email,
+			Avatar:      user.Avatar,
+			Role:        buildRoleResponse(user.Role),
+			Permissions: permissions,
+		},
+		Impersonator: impersonatorID,
+		AccessToken:  accessToken,
+		ExpiresAt:    expiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		TokenType:    "Bearer",
+	})
+}
+
+// StopImpersonation kết thúc phiên impersonation (thu hồi impersonation token).
+// Admin tiếp tục dùng access token gốc của mình.
+func (s *Service) StopImpersonation(ctx context.Context, claims *jwt.Claims, token string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	if !claims.IsImpersonated() {
+		return response.BadRequestResponse(lang, response.CodeNotImpersonating, nil)
+	}
+
+	if err := s.revokeAccessToken(token); err != nil {
+		if errors.Is(err, jwt.ErrInvalidToken) {
+			return response.UnauthorizedResponse(lang, response.CodeTokenInvalid)
+		}
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	s.logImpersonation(ctx, "impersonation_stop", claims.Impersonator, claims.UserID, nil)
+
+	return response.SuccessResponse(lang, response.CodeImpersonationStopped, nil)
+}
+
+// logImpersonation ghi action event bắt đầu/kết thúc impersonation
+func (s *Service) logImpersonation(ctx context.Context, action, impersonatorID, userID string, data map[string]interface{}) {
+	actionEvent.LogEventAsync(context.WithoutCancel(ctx), actionEvent.Event{
+		Action:         action,
+		Entity:         "user",
+		EntityID:       userID,
+		UserID:         impersonatorID,
+		ImpersonatorID: impersonatorID,
+		Data:           actionEvent.EventData{New: data},
+		Timestamp:      utils.Now(),
+		Job:            impersonationJob,
+	})
+}
+
+// IssueTokens cấp token pair cho user mà không cần đăng nhập (fixture user của E2E test data API)
+func (s *Service) IssueTokens(ctx context.Context, userID uuid.UUID) (*LoginResponse, error) {
+	user, err := s.userRepo.GetUserWithRole(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.buildLoginResponse(ctx, user)
+}
+
+// buildLoginResponse lấy permissions và tạo token pair cho user (đã preload role)
+func (s *Service) buildLoginResponse(ctx context.Context, user *model.User) (*LoginResponse, error) {
+	var permissions []string
+	if user.RoleID != nil {
+		var err error
+		permissions, err = s.userRepo.GetUserPermissions(ctx, *user.RoleID)
+		if err != nil {
+			permissions = []string{}
+		}
+	}
+
+	tokenPair, err := s.jwtManager.GenerateVersionedTokenPair(
+		user.ID.String(),
+		user.Email,
+		getRoleName(user.Role),
+		user.TokenVersion,
+		tokenMetadata(user.Name, permissions),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginResponse{
+		User: &UserResponse{
+			ID:          user.ID,
+			Name:        user.Name,
+			Email:       user.Email,
+			Avatar:      user.Avatar,
+			Role:        buildRoleResponse(user.Role),
+			Permissions: permissions,
+		},
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresAt:    tokenPair.ExpiresAt.Format("2006-01-02T15:04:05Z07:00"),
+		TokenType:    tokenPair.TokenType,
+	}, nil
+}
+
 // Helper functions
 
+// NewSocialVerifier tạo verifier ngẫu nhiên cho client không tự tạo PKCE (lưu trong cookie HttpOnly)
+func NewSocialVerifier() string {
+	buf := make([]byte, 32)
+	rand.Read(buf)
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// SocialChallenge challenge của verifier theo PKCE S256: base64url(sha256(verifier))
+func SocialChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// validSocialChallenge challenge S256 dài 43 ký tự base64url
+func validSocialChallenge(challenge string) bool {
+	decoded, err := base64.RawURLEncoding.DecodeString(challenge)
+	return err == nil && len(decoded) == sha256.Size
+}
+
+func socialStateKey(state string) string {
+	return "auth:social:state:" + state
+}
+
+func magicLinkKey(nonce string) string {
+	return "auth:magic_link:" + nonce
+}
+
+// tokenMetadata metadata gắn vào access token, permissions dùng cho jwt.PermissionChecker
+func tokenMetadata(name string, permissions []string) map[string]interface{} {
+	if permissions == nil {
+		permissions = []string{}
+	}
+	return map[string]interface{}{
+		"name":                  name,
+		jwt.MetadataPermissions: permissions,
+	}
+}
+
 func getRoleName(role *model.Role) string {
 	if role == nil {
 		return "user"
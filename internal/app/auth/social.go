@@ -0,0 +1,220 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/google"
+)
+
+// Tên các social provider được hỗ trợ
+const (
+	ProviderGoogle = "google"
+	ProviderGitHub = "github"
+)
+
+var (
+	ErrSocialProviderNotSupported = errors.New("social provider not supported")
+	ErrSocialExchangeFailed       = errors.New("failed to exchange authorization code")
+)
+
+// SocialUser thông tin user lấy từ OAuth2 provider
+type SocialUser struct {
+	ID            string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Avatar        *string
+	Role          string // Role local do provider chỉ định (OIDC role mapping), rỗng: giữ nguyên role hiện tại
+}
+
+// SocialProvider định nghĩa một OAuth2 provider dùng cho social login
+type SocialProvider interface {
+	// Name tên provider (google, github...)
+	Name() string
+	// AuthCodeURL tạo URL redirect user sang trang đăng nhập của provider
+	AuthCodeURL(ctx context.Context, state string) (string, error)
+	// FetchUser đổi authorization code lấy thông tin user, state đã được service xác thực
+	FetchUser(ctx context.Context, code, state string) (*SocialUser, error)
+}
+
+// SocialProviders registry các provider đã cấu hình, key là tên provider
+type SocialProviders map[string]SocialProvider
+
+// Get lấy provider theo tên
+func (p SocialProviders) Get(name string) (SocialProvider, error) {
+	provider, ok := p[name]
+	if !ok {
+		return nil, ErrSocialProviderNotSupported
+	}
+	return provider, nil
+}
+
+// SocialProviderConfig cấu hình OAuth2 client cho một provider
+type SocialProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// oauthProvider implementation chung dựa trên golang.org/x/oauth2
+type oauthProvider struct {
+	name      string
+	config    *oauth2.Config
+	fetchUser func(ctx context.Context, client *http.Client) (*SocialUser, error)
+}
+
+// NewGoogleProvider tạo Google provider
+func NewGoogleProvider(cfg SocialProviderConfig) SocialProvider {
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	return &oauthProvider{
+		name: ProviderGoogle,
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scopes,
+			Endpoint:     google.Endpoint,
+		},
+		fetchUser: fetchGoogleUser,
+	}
+}
+
+// NewGitHubProvider tạo GitHub provider
+func NewGitHubProvider(cfg SocialProviderConfig) SocialProvider {
+	scopes := cfg.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{"read:user", "user:email"}
+	}
+
+	return &oauthProvider{
+		name: ProviderGitHub,
+		config: &oauth2.Config{
+			ClientID:     cfg.ClientID,
+			ClientSecret: cfg.ClientSecret,
+			RedirectURL:  cfg.RedirectURL,
+			Scopes:       scopes,
+			Endpoint:     github.Endpoint,
+		},
+		fetchUser: fetchGitHubUser,
+	}
+}
+
+// Name tên provider
+func (p *oauthProvider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL tạo URL đăng nhập của provider
+func (p *oauthProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	return p.config.AuthCodeURL(state, oauth2.AccessTypeOnline), nil
+}
+
+// FetchUser đổi code lấy access token rồi gọi API lấy thông tin user
+func (p *oauthProvider) FetchUser(ctx context.Context, code, state string) (*SocialUser, error) {
+	token, err := p.config.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSocialExchangeFailed, err)
+	}
+
+	return p.fetchUser(ctx, p.config.Client(ctx, token))
+}
+
+// fetchGoogleUser lấy thông tin user từ Google OpenID userinfo endpoint
+func fetchGoogleUser(ctx context.Context, client *http.Client) (*SocialUser, error) {
+	var info struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		Name          string `json:"name"`
+		Picture       string `json:"picture"`
+	}
+	if err := getJSON(ctx, client, "https://openidconnect.googleapis.com/v1/userinfo", &info); err != nil {
+		return nil, err
+	}
+
+	user := &SocialUser{
+		ID:            info.Sub,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Name:          info.Name,
+	}
+	if info.Picture != "" {
+		user.Avatar = &info.Picture
+	}
+	return user, nil
+}
+
+// fetchGitHubUser lấy thông tin user và primary email đã verify từ GitHub API
+func fetchGitHubUser(ctx context.Context, client *http.Client) (*SocialUser, error) {
+	var info struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		Name      string `json:"name"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user", &info); err != nil {
+		return nil, err
+	}
+
+	// Email công khai trên profile có thể rỗng hoặc chưa verify, lấy từ /user/emails
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := getJSON(ctx, client, "https://api.github.com/user/emails", &emails); err != nil {
+		return nil, err
+	}
+
+	user := &SocialUser{
+		ID:   strconv.FormatInt(info.ID, 10),
+		Name: info.Name,
+	}
+	if user.Name == "" {
+		user.Name = info.Login
+	}
+	if info.AvatarURL != "" {
+		user.Avatar = &info.AvatarURL
+	}
+	for _, e := range emails {
+		if e.Primary {
+			user.Email = e.Email
+			user.EmailVerified = e.Verified
+			break
+		}
+	}
+	return user, nil
+}
+
+// getJSON gọi GET và decode JSON response
+func getJSON(ctx context.Context, client *http.Client, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, url)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
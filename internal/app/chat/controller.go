@@ -2,13 +2,13 @@ package chat
 
 import (
 	"net/http"
+	"strconv"
 
-	model "api-core/internal/models"
-	"api-core/pkg/i18n"
-	"api-core/pkg/jwt"
-	"api-core/pkg/response"
-	"api-core/pkg/utils"
-	"api-core/pkg/validator"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/pkg/validator"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
@@ -75,29 +75,12 @@ func (h *Handler) SendMessage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	conversationID, err := uuid.Parse(input.ConversationID)
+	conversationID, messageType, replyToID, err := input.Parse()
 	if err != nil {
 		response.BadRequest(w, lang, response.CodeBadRequest, nil)
 		return
 	}
 
-	// Parse message type
-	messageType := model.MessageTypeText
-	if input.MessageType != "" {
-		messageType = model.MessageType(input.MessageType)
-	}
-
-	// Parse reply_to_id
-	var replyToID *uuid.UUID
-	if input.ReplyToID != nil && *input.ReplyToID != "" {
-		id, err := uuid.Parse(*input.ReplyToID)
-		if err != nil {
-			response.BadRequest(w, lang, response.CodeBadRequest, nil)
-			return
-		}
-		replyToID = &id
-	}
-
 	resp := h.service.SendMessage(r.Context(), conversationID, senderID, input.Content, messageType, replyToID)
 	statusCode := response.GetHTTPStatusCode(resp.Code)
 	response.JSON(w, statusCode, *resp)
@@ -163,3 +146,169 @@ func (h *Handler) GetConversations(w http.ResponseWriter, r *http.Request) {
 	statusCode := response.GetHTTPStatusCode(resp.Code)
 	response.JSON(w, statusCode, *resp)
 }
+
+// DeleteMessage - DELETE /chats/messages/{id}?scope=me|everyone
+func (h *Handler) DeleteMessage(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	messageID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	resp := h.service.DeleteMessage(r.Context(), messageID, userUUID, deleteScope(r))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// RestoreMessage - POST /chats/messages/{id}/restore?scope=me|everyone
+func (h *Handler) RestoreMessage(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	messageID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	resp := h.service.RestoreMessage(r.Context(), messageID, userUUID, deleteScope(r))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// DeleteConversation - DELETE /chats/conversations/{id}?scope=me|everyone
+func (h *Handler) DeleteConversation(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	conversationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	resp := h.service.DeleteConversation(r.Context(), conversationID, userUUID, deleteScope(r))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// RestoreConversation - POST /chats/conversations/{id}/restore?scope=me|everyone
+func (h *Handler) RestoreConversation(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	conversationID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	resp := h.service.RestoreConversation(r.Context(), conversationID, userUUID, deleteScope(r))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// SyncEvents - GET /chats/sync?since=N&limit=100
+func (h *Handler) SyncEvents(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	var since int64
+	if v := r.URL.Query().Get("since"); v != "" {
+		since, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || since < 0 {
+			response.BadRequest(w, lang, response.CodeBadRequest, nil)
+			return
+		}
+	}
+
+	limit := 100
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			response.BadRequest(w, lang, response.CodeBadRequest, nil)
+			return
+		}
+	}
+	if limit > 500 {
+		limit = 500
+	}
+
+	resp := h.service.SyncEvents(r.Context(), userUUID, since, limit)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// LinkPreview - POST /chats/link-preview
+func (h *Handler) LinkPreview(w http.ResponseWriter, r *http.Request) {
+	var input LinkPreviewRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.GetLinkPreview(r.Context(), input.URL)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// deleteScope lấy scope từ query, mặc định là xóa cho riêng mình
+func deleteScope(r *http.Request) string {
+	scope := r.URL.Query().Get("scope")
+	if scope == "" {
+		return DeleteScopeMe
+	}
+	return scope
+}
@@ -0,0 +1,197 @@
+package chat
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// chatEventsLockKey key của advisory lock để các event được commit đúng thứ tự sequence,
+// tránh client sync bỏ sót event có sequence nhỏ hơn nhưng commit muộn hơn
+const chatEventsLockKey = 7_240_311
+
+// Config cấu hình chat module
+type Config struct {
+	EventSourcing bool // Ghi mọi thay đổi qua chat_events, các bảng messages... được build từ event (projection)
+}
+
+// EventStore append event vào chat_events và apply projection trong cùng transaction
+type EventStore struct {
+	db *gorm.DB
+}
+
+// NewEventStore tạo event store
+func NewEventStore(db *gorm.DB) *EventStore {
+	return &EventStore{db: db}
+}
+
+// Append ghi event và cập nhật projection, event.Sequence được gán sau khi ghi
+func (e *EventStore) Append(ctx context.Context, event *model.ChatEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = clock.FromContext(ctx).Now()
+	}
+	if event.Payload == nil {
+		event.Payload = map[string]interface{}{}
+	}
+
+	return e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chatEventsLockKey).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		return applyChatEvent(tx, event)
+	})
+}
+
+// messageSentPayload dữ liệu của event message.sent
+type messageSentPayload struct {
+	Content     string            `json:"content"`
+	MessageType model.MessageType `json:"message_type"`
+	ReplyToID   *uuid.UUID        `json:"reply_to_id,omitempty"`
+}
+
+// newMessageSentEvent tạo event message.sent từ message (message.ID phải được gán trước)
+func newMessageSentEvent(message *model.Message) *model.ChatEvent {
+	messageID := message.ID
+	payload := map[string]interface{}{
+		"content":      message.Content,
+		"message_type": message.MessageType,
+	}
+	if message.ReplyToID != nil {
+		payload["reply_to_id"] = message.ReplyToID.String()
+	}
+
+	return &model.ChatEvent{
+		ConversationID: message.ConversationID,
+		MessageID:      &messageID,
+		EventType:      model.ChatEventMessageSent,
+		ActorID:        message.SenderID,
+		Payload:        payload,
+		CreatedAt:      message.CreatedAt,
+	}
+}
+
+// newMessageEvent tạo event thay đổi trạng thái tin nhắn, visibleTo khác nil khi event chỉ áp dụng cho 1 user
+func newMessageEvent(eventType model.ChatEventType, message *model.Message, actorID uuid.UUID, visibleTo *uuid.UUID) *model.ChatEvent {
+	messageID := message.ID
+	return &model.ChatEvent{
+		ConversationID: message.ConversationID,
+		MessageID:      &messageID,
+		EventType:      eventType,
+		ActorID:        actorID,
+		VisibleTo:      visibleTo,
+	}
+}
+
+// newConversationEvent tạo event thay đổi trạng thái conversation
+func newConversationEvent(eventType model.ChatEventType, conversationID, actorID uuid.UUID, visibleTo *uuid.UUID) *model.ChatEvent {
+	return &model.ChatEvent{
+		ConversationID: conversationID,
+		EventType:      eventType,
+		ActorID:        actorID,
+		VisibleTo:      visibleTo,
+	}
+}
+
+// applyChatEvent cập nhật projection (messages, message_deletions, conversation_participants, conversations) theo event.
+// Các thao tác đều idempotent để có thể replay event.
+func applyChatEvent(tx *gorm.DB, event *model.ChatEvent) error {
+	switch event.EventType {
+	case model.ChatEventMessageSent:
+		var payload messageSentPayload
+		if err := decodePayload(event.Payload, &payload); err != nil {
+			return err
+		}
+		message := model.Message{
+			ID:             *event.MessageID,
+			ConversationID: event.ConversationID,
+			SenderID:       event.ActorID,
+			Content:        payload.Content,
+			MessageType:    payload.MessageType,
+			ReplyToID:      payload.ReplyToID,
+			CreatedAt:      event.CreatedAt,
+			UpdatedAt:      event.CreatedAt,
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.Conversation{}).
+			Where("id = ?", event.ConversationID).
+			Update("updated_at", event.CreatedAt).Error
+
+	case model.ChatEventMessageDeletedForEveryone:
+		return updateMessage(tx, event, map[string]interface{}{
+			"deleted_for_everyone_at": event.CreatedAt,
+			"deleted_for_everyone_by": event.ActorID,
+		})
+
+	case model.ChatEventMessageRestoredForEveryone:
+		return updateMessage(tx, event, map[string]interface{}{
+			"deleted_for_everyone_at": nil,
+			"deleted_for_everyone_by": nil,
+		})
+
+	case model.ChatEventMessageDeletedForUser:
+		deletion := model.MessageDeletion{
+			MessageID: *event.MessageID,
+			UserID:    event.ActorID,
+			CreatedAt: event.CreatedAt,
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&deletion).Error
+
+	case model.ChatEventMessageRestoredForUser:
+		return tx.Where("message_id = ? AND user_id = ?", *event.MessageID, event.ActorID).
+			Delete(&model.MessageDeletion{}).Error
+
+	case model.ChatEventConversationCleared:
+		return updateClearedAt(tx, event, &event.CreatedAt)
+
+	case model.ChatEventConversationUncleared:
+		return updateClearedAt(tx, event, nil)
+
+	case model.ChatEventConversationDeleted:
+		return tx.Unscoped().Model(&model.Conversation{}).
+			Where("id = ? AND deleted_at IS NULL", event.ConversationID).
+			Update("deleted_at", event.CreatedAt).Error
+
+	case model.ChatEventConversationRestored:
+		return tx.Unscoped().Model(&model.Conversation{}).
+			Where("id = ?", event.ConversationID).
+			Update("deleted_at", nil).Error
+	}
+
+	return fmt.Errorf("unknown chat event type: %s", event.EventType)
+}
+
+func updateMessage(tx *gorm.DB, event *model.ChatEvent, updates map[string]interface{}) error {
+	return tx.Model(&model.Message{}).Where("id = ?", *event.MessageID).Updates(updates).Error
+}
+
+func updateClearedAt(tx *gorm.DB, event *model.ChatEvent, clearedAt *time.Time) error {
+	return tx.Model(&model.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", event.ConversationID, event.ActorID).
+		Update("cleared_at", clearedAt).Error
+}
+
+func decodePayload(payload map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,123 @@
+package chat
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+
+	"golang.org/x/net/html"
+)
+
+// previewMaxBodySize chỉ cần phần <head> của trang để lấy title/Open Graph
+const previewMaxBodySize = 512 * 1024
+
+// LinkPreview thông tin preview của link trong tin nhắn
+type LinkPreview struct {
+	URL         string `json:"url"` // URL cuối cùng sau redirect
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Image       string `json:"image,omitempty"`
+	SiteName    string `json:"site_name,omitempty"`
+}
+
+// GetLinkPreview tải trang qua safehttp (chặn SSRF) và lấy title/description/ảnh từ Open Graph
+func (s *Service) GetLinkPreview(ctx context.Context, rawURL string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	resp, err := s.fetcher.WithTruncatedBody(previewMaxBodySize).Get(ctx, rawURL, "text/html,application/xhtml+xml")
+	if err != nil {
+		logger.Warnf("Failed to fetch link preview for %s: %v", rawURL, err)
+		if safehttp.IsNotAllowed(err) {
+			return response.BadRequestResponse(lang, response.CodeURLNotAllowed, nil)
+		}
+		return response.ErrorResponse(lang, response.CodeURLFetchFailed, nil)
+	}
+
+	preview := LinkPreview{URL: resp.URL}
+	switch {
+	case resp.ContentType == "text/html" || resp.ContentType == "application/xhtml+xml":
+		parseLinkPreview(resp.Body, &preview)
+	case strings.HasPrefix(resp.ContentType, "image/"):
+		preview.Image = resp.URL
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, preview)
+}
+
+// parseLinkPreview đọc <title> và thẻ meta Open Graph/Twitter trong <head>
+func parseLinkPreview(body []byte, preview *LinkPreview) {
+	meta := map[string]string{}
+	var title string
+
+	z := html.NewTokenizer(bytes.NewReader(body))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		token := z.Token()
+		if tt == html.EndTagToken && token.Data == "head" || tt == html.StartTagToken && token.Data == "body" {
+			break
+		}
+		if tt != html.StartTagToken && tt != html.SelfClosingTagToken {
+			continue
+		}
+
+		switch token.Data {
+		case "title":
+			if title == "" && z.Next() == html.TextToken {
+				title = strings.TrimSpace(string(z.Text()))
+			}
+		case "meta":
+			var key, content string
+			for _, attr := range token.Attr {
+				switch strings.ToLower(attr.Key) {
+				case "property", "name":
+					key = strings.ToLower(strings.TrimSpace(attr.Val))
+				case "content":
+					content = strings.TrimSpace(attr.Val)
+				}
+			}
+			if key != "" && content != "" {
+				if _, ok := meta[key]; !ok {
+					meta[key] = content
+				}
+			}
+		}
+	}
+
+	preview.Title = firstNonEmpty(meta["og:title"], meta["twitter:title"], title)
+	preview.Description = firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"])
+	preview.SiteName = meta["og:site_name"]
+	if image := firstNonEmpty(meta["og:image"], meta["og:image:url"], meta["twitter:image"]); image != "" {
+		preview.Image = resolveURL(preview.URL, image)
+	}
+}
+
+// resolveURL chuyển URL tương đối (ảnh) thành tuyệt đối theo trang, bỏ qua scheme không phải http(s)
+func resolveURL(base, ref string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return ""
+	}
+	u, err := b.Parse(ref)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+	return u.String()
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
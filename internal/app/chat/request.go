@@ -1,5 +1,17 @@
 package chat
 
+import (
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Scope khi xóa/khôi phục tin nhắn và conversation
+const (
+	DeleteScopeMe       = "me"       // Chỉ xóa phía user hiện tại
+	DeleteScopeEveryone = "everyone" // Xóa cho tất cả participants
+)
+
 // SendMessageRequest request cho gửi tin nhắn
 type SendMessageRequest struct {
 	ConversationID string  `json:"conversation_id" validate:"required,uuid"`
@@ -8,12 +20,39 @@ type SendMessageRequest struct {
 	ReplyToID      *string `json:"reply_to_id" validate:"omitempty,uuid"`
 }
 
+// Parse conversation, loại tin nhắn (mặc định text) và reply_to của request
+func (r SendMessageRequest) Parse() (conversationID uuid.UUID, messageType model.MessageType, replyToID *uuid.UUID, err error) {
+	if conversationID, err = uuid.Parse(r.ConversationID); err != nil {
+		return
+	}
+
+	messageType = model.MessageTypeText
+	if r.MessageType != "" {
+		messageType = model.MessageType(r.MessageType)
+	}
+
+	if r.ReplyToID != nil && *r.ReplyToID != "" {
+		id, parseErr := uuid.Parse(*r.ReplyToID)
+		if parseErr != nil {
+			err = parseErr
+			return
+		}
+		replyToID = &id
+	}
+	return
+}
+
 // GetMessagesRequest request cho lấy tin nhắn
 type GetMessagesRequest struct {
 	Page    int `json:"page" validate:"omitempty,min=1"`
 	PerPage int `json:"per_page" validate:"omitempty,min=1,max=100"`
 }
 
+// LinkPreviewRequest request cho lấy preview của link
+type LinkPreviewRequest struct {
+	URL string `json:"url" validate:"required,url,max=2048"`
+}
+
 // GetOrCreateConversationRequest request cho lấy/tạo conversation
 type GetOrCreateConversationRequest struct {
 	UserID string `json:"user_id" validate:"required,uuid"`
@@ -6,14 +6,24 @@ import "github.com/go-chi/chi/v5"
 // Prefix: /api/v1/chats
 func RegisterRoutes(r chi.Router, h *Handler) {
 	r.Route("/chats", func(r chi.Router) {
+		r.Get("/sync", h.SyncEvents) // GET /api/v1/chats/sync?since=N&limit=100 - Lấy event thay đổi từ sequence N (offline-first sync)
+
+		r.Post("/link-preview", h.LinkPreview) // POST /api/v1/chats/link-preview - Lấy title/description/ảnh của link trong tin nhắn
+
 		// Conversations
 		r.Route("/conversations", func(r chi.Router) {
-			r.Get("/", h.GetConversations)         // GET /api/v1/chats/conversations - Danh sách conversations
-			r.Post("/", h.GetOrCreateConversation) // POST /api/v1/chats/conversations - Lấy/tạo conversation
-			r.Get("/{id}/messages", h.GetMessages) // GET /api/v1/chats/conversations/{id}/messages - Lấy tin nhắn
+			r.Get("/", h.GetConversations)                 // GET /api/v1/chats/conversations - Danh sách conversations
+			r.Post("/", h.GetOrCreateConversation)         // POST /api/v1/chats/conversations - Lấy/tạo conversation
+			r.Get("/{id}/messages", h.GetMessages)         // GET /api/v1/chats/conversations/{id}/messages - Lấy tin nhắn
+			r.Delete("/{id}", h.DeleteConversation)        // DELETE /api/v1/chats/conversations/{id}?scope=me|everyone - Xóa conversation
+			r.Post("/{id}/restore", h.RestoreConversation) // POST /api/v1/chats/conversations/{id}/restore?scope=me|everyone - Khôi phục conversation
 		})
 
 		// Messages
-		r.Post("/messages", h.SendMessage) // POST /api/v1/chats/messages - Gửi tin nhắn
+		r.Route("/messages", func(r chi.Router) {
+			r.Post("/", h.SendMessage)                // POST /api/v1/chats/messages - Gửi tin nhắn
+			r.Delete("/{id}", h.DeleteMessage)        // DELETE /api/v1/chats/messages/{id}?scope=me|everyone - Xóa tin nhắn
+			r.Post("/{id}/restore", h.RestoreMessage) // POST /api/v1/chats/messages/{id}/restore?scope=me|everyone - Khôi phục tin nhắn
+		})
 	})
 }
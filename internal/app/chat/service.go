@@ -4,11 +4,14 @@ import (
 	"context"
 	"time"
 
-	model "api-core/internal/models"
-	repository "api-core/internal/repositories"
-	"api-core/pkg/i18n"
-	"api-core/pkg/response"
-	"api-core/pkg/utils"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+	"github.com/anhnq996/go-api-core/pkg/telemetry"
+	"github.com/anhnq996/go-api-core/pkg/utils"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -21,6 +24,9 @@ type Service struct {
 	messageRepo                 repository.MessageRepository
 	friendshipRepo              repository.FriendshipRepository
 	userRepo                    repository.UserRepository
+	chatEventRepo               repository.ChatEventRepository
+	events                      *EventStore // nil khi tắt event sourcing
+	fetcher                     *safehttp.Client
 	db                          *gorm.DB
 }
 
@@ -31,16 +37,33 @@ func NewService(
 	messageRepo repository.MessageRepository,
 	friendshipRepo repository.FriendshipRepository,
 	userRepo repository.UserRepository,
+	chatEventRepo repository.ChatEventRepository,
+	fetcher *safehttp.Client,
 	db *gorm.DB,
+	cfg Config,
 ) *Service {
-	return &Service{
+	s := &Service{
 		conversationRepo:            conversationRepo,
 		conversationParticipantRepo: conversationParticipantRepo,
 		messageRepo:                 messageRepo,
 		friendshipRepo:              friendshipRepo,
 		userRepo:                    userRepo,
+		chatEventRepo:               chatEventRepo,
+		fetcher:                     fetcher,
 		db:                          db,
 	}
+	if cfg.EventSourcing {
+		s.events = NewEventStore(db)
+	}
+	return s
+}
+
+// record ghi thay đổi qua event log khi bật event sourcing, ngược lại ghi thẳng vào bảng bằng direct
+func (s *Service) record(ctx context.Context, event *model.ChatEvent, direct func() error) error {
+	if s.events == nil {
+		return direct()
+	}
+	return s.events.Append(ctx, event)
 }
 
 // GetOrCreateDirectConversation lấy hoặc tạo direct conversation giữa 2 user
@@ -126,6 +149,8 @@ func (s *Service) createDirectConversation(ctx context.Context, user1ID, user2ID
 
 // SendMessage gửi tin nhắn
 func (s *Service) SendMessage(ctx context.Context, conversationID, senderID uuid.UUID, content string, messageType model.MessageType, replyToID *uuid.UUID) *response.Response {
+	telemetry.Track("chat.message")
+
 	lang := i18n.GetLanguageFromContext(ctx)
 
 	// Kiểm tra conversation có tồn tại không
@@ -155,6 +180,9 @@ func (s *Service) SendMessage(ctx context.Context, conversationID, senderID uuid
 		if replyTo.ConversationID != conversationID {
 			return response.BadRequestResponse(lang, response.CodeReplyMessageNotInConversation, nil)
 		}
+		if replyTo.IsDeletedForEveryone() {
+			return response.NotFoundResponse(lang, response.CodeMessageNotFound)
+		}
 	}
 
 	// Tạo message
@@ -166,7 +194,23 @@ func (s *Service) SendMessage(ctx context.Context, conversationID, senderID uuid
 		ReplyToID:      replyToID,
 	}
 
-	if err := s.messageRepo.Create(ctx, &message); err != nil {
+	if s.events != nil {
+		message.ID = utils.NewUUIDv7()
+		message.CreatedAt = clock.FromContext(ctx).Now()
+	}
+
+	err = s.record(ctx, newMessageSentEvent(&message), func() error {
+		if err := s.messageRepo.Create(ctx, &message); err != nil {
+			return err
+		}
+
+		// Cập nhật updated_at của conversation
+		now := clock.FromContext(ctx).Now()
+		return s.db.WithContext(ctx).Model(&model.Conversation{}).
+			Where("id = ?", conversationID).
+			Update("updated_at", now).Error
+	})
+	if err != nil {
 		return response.InternalServerErrorResponse(lang, response.CodeSendMessageFailed)
 	}
 
@@ -176,12 +220,6 @@ func (s *Service) SendMessage(ctx context.Context, conversationID, senderID uuid
 		message.ReplyTo, _ = s.messageRepo.FindByID(ctx, *replyToID)
 	}
 
-	// Cập nhật updated_at của conversation
-	now := time.Now()
-	s.db.WithContext(ctx).Model(&model.Conversation{}).
-		Where("id = ?", conversationID).
-		Update("updated_at", now)
-
 	return response.SuccessResponse(lang, response.CodeCreated, message)
 }
 
@@ -196,12 +234,12 @@ func (s *Service) GetMessages(ctx context.Context, conversationID, userID uuid.U
 	}
 
 	// Lấy messages
-	messages, total, err := s.messageRepo.FindByConversationID(ctx, conversationID, page, perPage)
+	messages, total, err := s.messageRepo.FindByConversationID(ctx, conversationID, userID, page, perPage)
 	if err != nil {
 		return response.InternalServerErrorResponse(lang, response.CodeGetMessagesFailed)
 	}
 
-	// Preload sender và reply_to
+	// Preload sender và reply_to (tin nhắn đã xóa cho mọi người được tombstone trong Message.AfterFind)
 	for i := range messages {
 		messages[i].Sender, _ = s.userRepo.FindByID(ctx, messages[i].SenderID)
 		if messages[i].ReplyToID != nil {
@@ -240,3 +278,292 @@ func (s *Service) GetConversations(ctx context.Context, userID uuid.UUID) *respo
 
 	return response.SuccessResponse(lang, response.CodeSuccess, conversations)
 }
+
+// DeleteMessage xóa tin nhắn cho riêng mình (scope=me) hoặc cho mọi người (scope=everyone)
+func (s *Service) DeleteMessage(ctx context.Context, messageID, userID uuid.UUID, scope string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	message, errResp := s.findParticipantMessage(ctx, messageID, userID, lang)
+	if errResp != nil {
+		return errResp
+	}
+
+	switch scope {
+	case DeleteScopeMe:
+		event := newMessageEvent(model.ChatEventMessageDeletedForUser, message, userID, &userID)
+		if err := s.record(ctx, event, func() error {
+			return s.messageRepo.DeleteForUser(ctx, messageID, userID)
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeDeleteMessageFailed)
+		}
+	case DeleteScopeEveryone:
+		// Chỉ người gửi mới được xóa tin nhắn cho mọi người
+		if message.SenderID != userID {
+			return response.ForbiddenResponse(lang, response.CodeNotMessageSender)
+		}
+		if message.IsDeletedForEveryone() {
+			return response.SuccessResponse(lang, response.CodeDeleted, nil)
+		}
+		event := newMessageEvent(model.ChatEventMessageDeletedForEveryone, message, userID, nil)
+		if err := s.record(ctx, event, func() error {
+			return s.messageRepo.DeleteForEveryone(ctx, messageID, userID)
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeDeleteMessageFailed)
+		}
+	default:
+		return response.BadRequestResponse(lang, response.CodeInvalidDeleteScope, nil)
+	}
+
+	return response.SuccessResponse(lang, response.CodeDeleted, nil)
+}
+
+// RestoreMessage khôi phục tin nhắn đã xóa theo scope
+func (s *Service) RestoreMessage(ctx context.Context, messageID, userID uuid.UUID, scope string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	message, errResp := s.findParticipantMessage(ctx, messageID, userID, lang)
+	if errResp != nil {
+		return errResp
+	}
+
+	switch scope {
+	case DeleteScopeMe:
+		deleted, err := s.messageRepo.IsDeletedForUser(ctx, messageID, userID)
+		if err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeRestoreMessageFailed)
+		}
+		if !deleted {
+			return response.BadRequestResponse(lang, response.CodeMessageNotDeleted, nil)
+		}
+		event := newMessageEvent(model.ChatEventMessageRestoredForUser, message, userID, &userID)
+		if err := s.record(ctx, event, func() error {
+			_, err := s.messageRepo.RestoreForUser(ctx, messageID, userID)
+			return err
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeRestoreMessageFailed)
+		}
+	case DeleteScopeEveryone:
+		if message.SenderID != userID {
+			return response.ForbiddenResponse(lang, response.CodeNotMessageSender)
+		}
+		if !message.IsDeletedForEveryone() {
+			return response.BadRequestResponse(lang, response.CodeMessageNotDeleted, nil)
+		}
+		event := newMessageEvent(model.ChatEventMessageRestoredForEveryone, message, userID, nil)
+		if err := s.record(ctx, event, func() error {
+			return s.messageRepo.RestoreForEveryone(ctx, messageID)
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeRestoreMessageFailed)
+		}
+	default:
+		return response.BadRequestResponse(lang, response.CodeInvalidDeleteScope, nil)
+	}
+
+	// Tin nhắn vẫn có thể đang bị xóa theo scope còn lại
+	deletedForMe, err := s.messageRepo.IsDeletedForUser(ctx, messageID, userID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeRestoreMessageFailed)
+	}
+	if deletedForMe {
+		return response.SuccessResponse(lang, response.CodeSuccess, nil)
+	}
+
+	// Đọc lại để có nội dung sau khi khôi phục (bản đã đọc trước đó bị tombstone)
+	message, err = s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeRestoreMessageFailed)
+	}
+	message.Sender, _ = s.userRepo.FindByID(ctx, message.SenderID)
+	return response.SuccessResponse(lang, response.CodeSuccess, message)
+}
+
+// DeleteConversation xóa conversation cho riêng mình (scope=me) hoặc cho mọi người (scope=everyone)
+func (s *Service) DeleteConversation(ctx context.Context, conversationID, userID uuid.UUID, scope string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	conversation, err := s.conversationRepo.FindByIDWithParticipants(ctx, conversationID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeConversationNotFound)
+	}
+	if !isConversationParticipant(conversation, userID) {
+		return response.ForbiddenResponse(lang, response.CodeNotParticipant)
+	}
+
+	switch scope {
+	case DeleteScopeMe:
+		// Ẩn toàn bộ lịch sử hiện tại, conversation hiện lại khi có tin nhắn mới
+		event := newConversationEvent(model.ChatEventConversationCleared, conversationID, userID, &userID)
+		if err := s.record(ctx, event, func() error {
+			now := clock.FromContext(ctx).Now()
+			return s.conversationParticipantRepo.UpdateClearedAt(ctx, conversationID, userID, &now)
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeDeleteConversationFailed)
+		}
+	case DeleteScopeEveryone:
+		if !canManageConversation(conversation, userID) {
+			return response.ForbiddenResponse(lang, response.CodeNotConversationOwner)
+		}
+		event := newConversationEvent(model.ChatEventConversationDeleted, conversationID, userID, nil)
+		if err := s.record(ctx, event, func() error {
+			return s.conversationRepo.Delete(ctx, conversationID)
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeDeleteConversationFailed)
+		}
+	default:
+		return response.BadRequestResponse(lang, response.CodeInvalidDeleteScope, nil)
+	}
+
+	return response.SuccessResponse(lang, response.CodeDeleted, nil)
+}
+
+// RestoreConversation khôi phục conversation đã xóa theo scope
+func (s *Service) RestoreConversation(ctx context.Context, conversationID, userID uuid.UUID, scope string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	switch scope {
+	case DeleteScopeMe:
+		participant, err := s.conversationParticipantRepo.FindByConversationAndUser(ctx, conversationID, userID)
+		if err != nil {
+			return response.ForbiddenResponse(lang, response.CodeNotParticipant)
+		}
+		if participant.ClearedAt == nil {
+			return response.BadRequestResponse(lang, response.CodeConversationNotDeleted, nil)
+		}
+		event := newConversationEvent(model.ChatEventConversationUncleared, conversationID, userID, &userID)
+		if err := s.record(ctx, event, func() error {
+			return s.conversationParticipantRepo.UpdateClearedAt(ctx, conversationID, userID, nil)
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeRestoreConversationFailed)
+		}
+	case DeleteScopeEveryone:
+		conversation, err := s.conversationRepo.FindDeletedByID(ctx, conversationID)
+		if err != nil {
+			return response.NotFoundResponse(lang, response.CodeConversationNotFound)
+		}
+		if !isConversationParticipant(conversation, userID) {
+			return response.ForbiddenResponse(lang, response.CodeNotParticipant)
+		}
+		if !canManageConversation(conversation, userID) {
+			return response.ForbiddenResponse(lang, response.CodeNotConversationOwner)
+		}
+		event := newConversationEvent(model.ChatEventConversationRestored, conversationID, userID, nil)
+		if err := s.record(ctx, event, func() error {
+			return s.conversationRepo.Restore(ctx, conversationID)
+		}); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeRestoreConversationFailed)
+		}
+	default:
+		return response.BadRequestResponse(lang, response.CodeInvalidDeleteScope, nil)
+	}
+
+	conversation, err := s.conversationRepo.FindByIDWithParticipants(ctx, conversationID)
+	if err != nil {
+		// Conversation có thể vẫn đang bị xóa cho mọi người
+		return response.SuccessResponse(lang, response.CodeSuccess, nil)
+	}
+	return response.SuccessResponse(lang, response.CodeSuccess, conversation)
+}
+
+// SyncResponse kết quả sync: client lưu next_since và gọi lại khi has_more = true
+type SyncResponse struct {
+	Events    []model.ChatEvent `json:"events"`
+	NextSince int64             `json:"next_since"`
+	HasMore   bool              `json:"has_more"`
+}
+
+// SyncEvents trả về các chat event có sequence > since của user (dùng cho client offline-first)
+func (s *Service) SyncEvents(ctx context.Context, userID uuid.UUID, since int64, limit int) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	if s.events == nil {
+		return response.BadRequestResponse(lang, response.CodeChatSyncDisabled, nil)
+	}
+
+	events, err := s.chatEventRepo.FindForUserSince(ctx, userID, since, limit+1)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeChatSyncFailed)
+	}
+
+	hasMore := len(events) > limit
+	if hasMore {
+		events = events[:limit]
+	}
+
+	if err := s.redactDeletedMessages(ctx, events); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeChatSyncFailed)
+	}
+
+	nextSince := since
+	if len(events) > 0 {
+		nextSince = events[len(events)-1].Sequence
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, SyncResponse{
+		Events:    events,
+		NextSince: nextSince,
+		HasMore:   hasMore,
+	})
+}
+
+// redactDeletedMessages ẩn nội dung trong event message.sent của tin nhắn hiện đang bị xóa cho mọi người,
+// client vẫn nhận event message.deleted_for_everyone phía sau để hiển thị tombstone
+func (s *Service) redactDeletedMessages(ctx context.Context, events []model.ChatEvent) error {
+	var messageIDs []uuid.UUID
+	for _, e := range events {
+		if e.EventType == model.ChatEventMessageSent && e.MessageID != nil {
+			messageIDs = append(messageIDs, *e.MessageID)
+		}
+	}
+	if len(messageIDs) == 0 {
+		return nil
+	}
+
+	deleted, err := s.messageRepo.FindWhere(ctx, "id IN ? AND deleted_for_everyone_at IS NOT NULL", messageIDs)
+	if err != nil {
+		return err
+	}
+	deletedIDs := make(map[uuid.UUID]bool, len(deleted))
+	for _, m := range deleted {
+		deletedIDs[m.ID] = true
+	}
+
+	for i := range events {
+		if events[i].EventType == model.ChatEventMessageSent && events[i].MessageID != nil && deletedIDs[*events[i].MessageID] {
+			events[i].Payload["content"] = ""
+		}
+	}
+	return nil
+}
+
+// findParticipantMessage tìm tin nhắn và kiểm tra user có tham gia conversation chứa tin nhắn không
+func (s *Service) findParticipantMessage(ctx context.Context, messageID, userID uuid.UUID, lang string) (*model.Message, *response.Response) {
+	message, err := s.messageRepo.FindByID(ctx, messageID)
+	if err != nil {
+		return nil, response.NotFoundResponse(lang, response.CodeMessageNotFound)
+	}
+
+	if _, err := s.conversationParticipantRepo.FindByConversationAndUser(ctx, message.ConversationID, userID); err != nil {
+		return nil, response.ForbiddenResponse(lang, response.CodeNotParticipant)
+	}
+
+	return message, nil
+}
+
+// isConversationParticipant kiểm tra user có trong danh sách participants không
+func isConversationParticipant(conversation *model.Conversation, userID uuid.UUID) bool {
+	for _, p := range conversation.Participants {
+		if p.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// canManageConversation kiểm tra quyền xóa/khôi phục conversation cho mọi người:
+// direct conversation thì participant nào cũng được, group thì chỉ người tạo
+func canManageConversation(conversation *model.Conversation, userID uuid.UUID) bool {
+	if conversation.Type == model.ConversationTypeDirect {
+		return true
+	}
+	return conversation.CreatedBy != nil && *conversation.CreatedBy == userID
+}
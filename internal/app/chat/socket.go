@@ -0,0 +1,83 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Room prefix trên socket hub
+const (
+	ConversationRoomPrefix = "conversation:" // conversation:<conversation_id>, chỉ participant được join/gửi
+	UserRoomPrefix         = "user:"         // user:<user_id>, room riêng của user
+)
+
+// EventChatMessage event socket gửi tin nhắn, payload như POST /api/v1/chats/messages.
+// Tin nhắn đã lưu được đẩy tới room của conversation với cùng tên event.
+const EventChatMessage = "chat.message"
+
+// RegisterSocketEvents đăng ký các event chat trên socket hub
+func RegisterSocketEvents(hub *socket.Hub, h *Handler) {
+	hub.On(EventChatMessage, socket.Handle(h.SendSocketMessage))
+}
+
+// SendSocketMessage - event chat.message
+func (h *Handler) SendSocketMessage(ctx context.Context, client *socket.Client, input SendMessageRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	senderID, err := uuid.Parse(client.UserID)
+	if err != nil {
+		return response.UnauthorizedResponse(lang, response.CodeUnauthorized)
+	}
+
+	conversationID, messageType, replyToID, err := input.Parse()
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeBadRequest, nil)
+	}
+
+	resp := h.service.SendMessage(ctx, conversationID, senderID, input.Content, messageType, replyToID)
+	if resp.Success {
+		client.Hub.EmitToRoom(ConversationRoom(conversationID), EventChatMessage, resp.Data)
+	}
+	return resp
+}
+
+// ConversationRoom tên room socket của conversation
+func ConversationRoom(conversationID uuid.UUID) string {
+	return ConversationRoomPrefix + conversationID.String()
+}
+
+// RoomAuthorizer kiểm tra quyền join/gửi vào room chat trên socket hub, các room khác (topic feed) không giới hạn
+func RoomAuthorizer(participantRepo repository.ConversationParticipantRepository) socket.RoomAuthorizer {
+	return func(ctx context.Context, client *socket.Client, room string, action socket.RoomAction) error {
+		switch {
+		case strings.HasPrefix(room, UserRoomPrefix):
+			if strings.TrimPrefix(room, UserRoomPrefix) != client.UserID {
+				return errors.New("private room of another user")
+			}
+		case strings.HasPrefix(room, ConversationRoomPrefix):
+			conversationID, err := uuid.Parse(strings.TrimPrefix(room, ConversationRoomPrefix))
+			if err != nil {
+				return errors.New("invalid conversation id")
+			}
+			userID, err := uuid.Parse(client.UserID)
+			if err != nil {
+				return errors.New("not a participant of the conversation")
+			}
+			if _, err := participantRepo.FindByConversationAndUser(ctx, conversationID, userID); err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return errors.New("not a participant of the conversation")
+				}
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,89 @@
+package cronjob
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler chứa service lịch sử cron job
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Index - GET /cron-jobs
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.LatestRuns(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Runs - GET /cron-jobs/runs?job=cleanup-logs&success=false&from=2025-01-01T00:00:00Z&to=...&page=1&per_page=20
+func (h *Handler) Runs(w http.ResponseWriter, r *http.Request) {
+	req, ok := parseListRunsRequest(r)
+	if !ok {
+		response.BadRequest(w, i18n.GetLanguageFromContext(r.Context()), response.CodeBadRequest, nil)
+		return
+	}
+
+	resp := h.service.ListRuns(r.Context(), req)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// ShowRun - GET /cron-jobs/runs/{id}
+func (h *Handler) ShowRun(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.NotFound(w, i18n.GetLanguageFromContext(r.Context()), response.CodeCronJobRunNotFound)
+		return
+	}
+
+	resp := h.service.GetRun(r.Context(), id)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Jobs - GET /cron-jobs/jobs
+func (h *Handler) Jobs(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.ListJobs(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// ShowJob - GET /cron-jobs/jobs/{name}
+func (h *Handler) ShowJob(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.GetJob(r.Context(), chi.URLParam(r, "name"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// TriggerJob - POST /cron-jobs/jobs/{name}/trigger
+func (h *Handler) TriggerJob(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.TriggerJob(r.Context(), chi.URLParam(r, "name"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// PauseJob - POST /cron-jobs/jobs/{name}/pause
+func (h *Handler) PauseJob(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.SetPaused(r.Context(), chi.URLParam(r, "name"), true)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// ResumeJob - POST /cron-jobs/jobs/{name}/resume
+func (h *Handler) ResumeJob(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.SetPaused(r.Context(), chi.URLParam(r, "name"), false)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
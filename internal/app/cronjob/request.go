@@ -0,0 +1,51 @@
+package cronjob
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// ListRunsRequest query params của GET /cron-jobs/runs
+type ListRunsRequest struct {
+	Page    int
+	PerPage int
+	Filter  repository.CronJobRunFilter
+}
+
+// parseListRunsRequest parse ?job=&success=true|false&from=&to=(RFC3339)&page=&per_page=, false nếu giá trị không hợp lệ
+func parseListRunsRequest(r *http.Request) (*ListRunsRequest, bool) {
+	params := utils.ParseQueryParams(r)
+	query := r.URL.Query()
+
+	req := &ListRunsRequest{
+		Page:    params.Page,
+		PerPage: params.PerPage,
+		Filter:  repository.CronJobRunFilter{JobName: query.Get("job")},
+	}
+
+	if v := query.Get("success"); v != "" {
+		success, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, false
+		}
+		req.Filter.Success = &success
+	}
+
+	for key, dst := range map[string]*time.Time{"from": &req.Filter.From, "to": &req.Filter.To} {
+		v := query.Get(key)
+		if v == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, false
+		}
+		*dst = t
+	}
+
+	return req, true
+}
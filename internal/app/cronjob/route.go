@@ -0,0 +1,24 @@
+package cronjob
+
+import (
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes đăng ký routes lịch sử và quản lý cron job (admin)
+// Prefix: /api/v1/cron-jobs
+func RegisterRoutes(r chi.Router, h *Handler, perm *jwt.PermissionChecker) {
+	r.Route("/cron-jobs", func(r chi.Router) {
+		r.With(perm.Require("cron.view")).Get("/", h.Index)            // GET /api/v1/cron-jobs - Lần chạy gần nhất của mỗi job
+		r.With(perm.Require("cron.view")).Get("/runs", h.Runs)         // GET /api/v1/cron-jobs/runs - Lịch sử chạy (lọc theo job, kết quả, thời gian)
+		r.With(perm.Require("cron.view")).Get("/runs/{id}", h.ShowRun) // GET /api/v1/cron-jobs/runs/{id} - Chi tiết 1 lần chạy
+
+		r.With(perm.Require("cron.view")).Get("/jobs", h.Jobs)           // GET /api/v1/cron-jobs/jobs - Job đang chạy theo lịch, lần chạy kế tiếp, lock
+		r.With(perm.Require("cron.view")).Get("/jobs/{name}", h.ShowJob) // GET /api/v1/cron-jobs/jobs/{name} - Trạng thái 1 job
+
+		r.With(perm.Require("cron.manage")).Post("/jobs/{name}/trigger", h.TriggerJob) // POST /api/v1/cron-jobs/jobs/{name}/trigger - Chạy job ngay
+		r.With(perm.Require("cron.manage")).Post("/jobs/{name}/pause", h.PauseJob)     // POST /api/v1/cron-jobs/jobs/{name}/pause - Tạm dừng chạy theo lịch
+		r.With(perm.Require("cron.manage")).Post("/jobs/{name}/resume", h.ResumeJob)   // POST /api/v1/cron-jobs/jobs/{name}/resume - Tiếp tục chạy theo lịch
+	})
+}
@@ -0,0 +1,205 @@
+package cronjob
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// Service xem lịch sử chạy cron job do scheduler ghi lại (cron.Config.ResultRecorder), dùng để debug job lỗi
+// mà không cần tìm log của process scheduler. Quản lý job (chạy ngay, tạm dừng/tiếp tục) qua cron.Control
+// dùng chung với scheduler, không cần deploy lại.
+type Service struct {
+	repo    repository.CronJobRunRepository
+	control cron.Control     // nil: không kết nối được Redis, không quản lý được job
+	locks   cron.LockManager // nil: không xem được trạng thái lock
+}
+
+// NewService tạo cron job service mới
+func NewService(repo repository.CronJobRunRepository, control cron.Control, locks cron.LockManager) *Service {
+	return &Service{repo: repo, control: control, locks: locks}
+}
+
+// LatestRuns lần chạy gần nhất của mỗi job
+func (s *Service) LatestRuns(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	runs, err := s.repo.LatestRuns(ctx)
+	if err != nil {
+		logger.Warnf("Cron jobs: failed to load latest runs: %v", err)
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, runs)
+}
+
+// ListRuns lịch sử chạy theo filter, mới nhất trước
+func (s *Service) ListRuns(ctx context.Context, req *ListRunsRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	runs, total, err := s.repo.FindRuns(ctx, req.Filter, req.Page, req.PerPage)
+	if err != nil {
+		logger.Warnf("Cron jobs: failed to load runs: %v", err)
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	pagination := utils.NewPagination(req.Page, req.PerPage, total)
+	meta := &response.Meta{
+		Page:       pagination.Page,
+		PerPage:    pagination.PerPage,
+		Total:      pagination.Total,
+		TotalPages: pagination.TotalPages,
+	}
+
+	return response.SuccessResponseWithMeta(lang, response.CodeSuccess, utils.PaginatedResponse(runs, pagination), meta)
+}
+
+// GetRun chi tiết 1 lần chạy
+func (s *Service) GetRun(ctx context.Context, id int64) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	run, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.NotFoundResponse(lang, response.CodeCronJobRunNotFound)
+	}
+	if err != nil {
+		logger.Warnf("Cron jobs: failed to load run %d: %v", id, err)
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, run)
+}
+
+// ListJobs các job đang được scheduler chạy, kèm lần chạy gần nhất/kế tiếp, trạng thái tạm dừng và lock
+func (s *Service) ListJobs(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	snapshot, resp := s.snapshot(ctx, lang)
+	if resp != nil {
+		return resp
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, snapshot)
+}
+
+// GetJob trạng thái 1 job
+func (s *Service) GetJob(ctx context.Context, name string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	job, resp := s.job(ctx, lang, name)
+	if resp != nil {
+		return resp
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, job)
+}
+
+// TriggerJob yêu cầu scheduler chạy job ngay (kể cả khi job đang tạm dừng), scheduler nhận yêu cầu trong khoảng 1 giây
+func (s *Service) TriggerJob(ctx context.Context, name string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	job, resp := s.job(ctx, lang, name)
+	if resp != nil {
+		return resp
+	}
+	if job.IsRunning || job.IsLocked {
+		return response.ConflictResponse(lang, response.CodeCronJobRunning)
+	}
+
+	if err := s.control.Trigger(ctx, name); err != nil {
+		logger.Warnf("Cron jobs: %v", err)
+		return response.ServiceUnavailableResponse(lang, response.CodeSchedulerUnavailable)
+	}
+
+	logger.Infof("Cron jobs: triggered job %s", name)
+	return response.SuccessResponse(lang, response.CodeCronJobTriggered, job)
+}
+
+// SetPaused tạm dừng/tiếp tục chạy job theo lịch trên mọi scheduler. Lần chạy đang diễn ra vẫn chạy tới khi xong.
+func (s *Service) SetPaused(ctx context.Context, name string, paused bool) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	job, resp := s.job(ctx, lang, name)
+	if resp != nil {
+		return resp
+	}
+
+	var err error
+	if paused {
+		err = s.control.Pause(ctx, name)
+	} else {
+		err = s.control.Resume(ctx, name)
+	}
+	if err != nil {
+		logger.Warnf("Cron jobs: %v", err)
+		return response.ServiceUnavailableResponse(lang, response.CodeSchedulerUnavailable)
+	}
+
+	logger.Infof("Cron jobs: job %s paused=%v", name, paused)
+	job.IsPaused = paused
+	return response.SuccessResponse(lang, response.CodeUpdated, job)
+}
+
+// snapshot trạng thái job do scheduler công bố, trạng thái tạm dừng và lock đọc trực tiếp từ Redis
+// để thay đổi qua API hiện ngay mà không chờ scheduler đồng bộ
+func (s *Service) snapshot(ctx context.Context, lang string) (*cron.Snapshot, *response.Response) {
+	if s.control == nil {
+		return nil, response.ServiceUnavailableResponse(lang, response.CodeSchedulerUnavailable)
+	}
+
+	snapshot, err := s.control.Snapshot(ctx)
+	if err != nil {
+		logger.Warnf("Cron jobs: %v", err)
+		return nil, response.ServiceUnavailableResponse(lang, response.CodeSchedulerUnavailable)
+	}
+	if snapshot == nil {
+		// Snapshot hết hạn: không có scheduler nào đang chạy cron loop
+		return nil, response.ServiceUnavailableResponse(lang, response.CodeSchedulerUnavailable)
+	}
+
+	paused, err := s.control.PausedJobs(ctx)
+	if err != nil {
+		logger.Warnf("Cron jobs: %v", err)
+		return nil, response.ServiceUnavailableResponse(lang, response.CodeSchedulerUnavailable)
+	}
+
+	for i := range snapshot.Jobs {
+		job := &snapshot.Jobs[i]
+		job.IsPaused = slices.Contains(paused, job.Name)
+		if s.locks == nil {
+			continue
+		}
+		locked, err := s.locks.IsLocked(ctx, job.Name)
+		if err != nil {
+			logger.Warnf("Cron jobs: failed to read lock of job %s: %v", job.Name, err)
+			continue
+		}
+		job.IsLocked = locked
+	}
+
+	return snapshot, nil
+}
+
+// job trạng thái 1 job, 404 nếu scheduler không chạy job này
+func (s *Service) job(ctx context.Context, lang, name string) (*cron.JobStatus, *response.Response) {
+	snapshot, resp := s.snapshot(ctx, lang)
+	if resp != nil {
+		return nil, resp
+	}
+
+	for i := range snapshot.Jobs {
+		if snapshot.Jobs[i].Name == name {
+			return &snapshot.Jobs[i], nil
+		}
+	}
+	return nil, response.NotFoundResponse(lang, response.CodeCronJobNotFound)
+}
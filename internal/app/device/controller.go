@@ -0,0 +1,78 @@
+package device
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Handler chứa service của device
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Index - GET /devices
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.service.List(r.Context(), userID)
+	response.JSON(w, response.GetHTTPStatusCode(resp.Code), *resp)
+}
+
+// Register - POST /devices
+func (h *Handler) Register(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var input RegisterDeviceRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.Register(r.Context(), userID, input)
+	response.JSON(w, response.GetHTTPStatusCode(resp.Code), *resp)
+}
+
+// Unregister - DELETE /devices/{token}
+func (h *Handler) Unregister(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.service.Unregister(r.Context(), userID, chi.URLParam(r, "token"))
+	response.JSON(w, response.GetHTTPStatusCode(resp.Code), *resp)
+}
+
+// currentUserID user đang đăng nhập, ghi 401/400 nếu không có
+func currentUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
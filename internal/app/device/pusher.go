@@ -0,0 +1,248 @@
+package device
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/fcm"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/push"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// QueueNotifications queue gửi push notification, worker xử lý job PushJob
+const QueueNotifications = "notifications"
+
+// deviceTokenJob job Loki của action event khi token FCM bị xóa
+const deviceTokenJob = "device_token_events"
+
+// ErrNoDevices user chưa đăng ký thiết bị nào (POST /api/v1/devices)
+var ErrNoDevices = errors.New("user has no registered devices")
+
+// ErrPushDisabled FCM chưa được cấu hình
+var ErrPushDisabled = errors.New("FCM client chưa được khởi tạo")
+
+// Push payload của job gửi push. Tokens rỗng: gửi tới mọi thiết bị đã đăng ký của UserID (provider theo platform
+// của từng thiết bị); có Tokens: gửi qua provider của Platform (rỗng là FCM)
+type Push struct {
+	UserID   uuid.UUID         `json:"user_id"`
+	Tokens   []string          `json:"tokens,omitempty"`
+	Platform string            `json:"platform,omitempty"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+// PushJob job gửi push qua queue, sống sót khi process restart và được retry, dead-letter như mọi job khác
+var PushJob = queue.DefineJob[Push]("notification.push", 1)
+
+// Pusher gửi push tới thiết bị qua FCM hoặc provider riêng theo platform (APNs cho ios),
+// token bị provider từ chối được xóa khỏi device_tokens
+type Pusher struct {
+	fcmClient  *fcm.Client              // nil nếu FCM chưa được cấu hình
+	providers  map[string]push.Notifier // Provider theo platform, platform không có ở đây dùng FCM
+	deviceRepo repository.DeviceTokenRepository
+}
+
+// NewPusher tạo pusher, fcmClient có thể nil
+func NewPusher(fcmClient *fcm.Client, deviceRepo repository.DeviceTokenRepository) *Pusher {
+	return &Pusher{fcmClient: fcmClient, providers: map[string]push.Notifier{}, deviceRepo: deviceRepo}
+}
+
+// Route gửi push tới thiết bị của platform qua notifier thay vì FCM, ví dụ APNs trực tiếp cho ios
+// (token app đăng ký cho platform đó phải là token của provider)
+func (p *Pusher) Route(platform string, notifier push.Notifier) *Pusher {
+	p.providers[platform] = notifier
+	return p
+}
+
+// Enabled có ít nhất một provider đã được cấu hình
+func (p *Pusher) Enabled() bool {
+	return p != nil && (p.fcmClient != nil || len(p.providers) > 0)
+}
+
+// notifier provider của platform, nil nếu chưa được cấu hình
+func (p *Pusher) notifier(platform string) push.Notifier {
+	if notifier, ok := p.providers[platform]; ok {
+		return notifier
+	}
+	if p.fcmClient == nil {
+		return nil
+	}
+	return p.fcmClient
+}
+
+// Send gửi push theo payload của PushJob. Trả lỗi (để worker retry) khi không thiết bị nào nhận được vì lỗi tạm thời;
+// token bị provider từ chối đã bị xóa nên không tính là lỗi
+func (p *Pusher) Send(ctx context.Context, payload Push) (int, int, error) {
+	if len(payload.Tokens) == 0 {
+		return p.SendToUser(ctx, payload.UserID, payload.Title, payload.Body, payload.Data)
+	}
+
+	notifier := p.notifier(payload.Platform)
+	if notifier == nil {
+		return 0, 0, ErrPushDisabled
+	}
+	message := &push.Message{Title: payload.Title, Body: payload.Body, Data: payload.Data}
+	result, err := p.sendToTokens(ctx, notifier, payload.Tokens, message)
+	if err != nil {
+		return 0, 0, err
+	}
+	if result.SuccessCount == 0 && result.FailureCount > len(result.Invalid) {
+		return 0, result.FailureCount, fmt.Errorf("failed to send push notification to %d tokens", result.FailureCount-len(result.Invalid))
+	}
+	return result.SuccessCount, result.FailureCount, nil
+}
+
+// SendToToken gửi tới một FCM token. Token không còn được đăng ký (UNREGISTERED) bị xóa khỏi device_tokens
+func (p *Pusher) SendToToken(ctx context.Context, token string, title, body string, data map[string]string) (string, error) {
+	if p == nil || p.fcmClient == nil {
+		return "", ErrPushDisabled
+	}
+	if token == "" {
+		return "", fmt.Errorf("FCM token không được để trống")
+	}
+
+	messageID, err := p.fcmClient.SendToToken(ctx, token, buildNotification(title, body), data)
+	if err != nil {
+		if fcm.IsUnregistered(err) {
+			p.removeInvalidTokens(ctx, []push.InvalidToken{{Token: token, Reason: push.InvalidReasonUnregistered}})
+		}
+		return "", fmt.Errorf("failed to send FCM notification: %w", err)
+	}
+	return messageID, nil
+}
+
+// SendToTokens gửi tới nhiều FCM token (multicast), trả về số thành công và thất bại.
+// Token bị FCM từ chối (UNREGISTERED, INVALID_ARGUMENT) bị xóa khỏi device_tokens
+func (p *Pusher) SendToTokens(ctx context.Context, tokens []string, title, body string, data map[string]string) (int, int, error) {
+	if p == nil || p.fcmClient == nil {
+		return 0, 0, ErrPushDisabled
+	}
+	if len(tokens) == 0 {
+		return 0, 0, fmt.Errorf("danh sách tokens không được để trống")
+	}
+
+	result, err := p.sendToTokens(ctx, p.fcmClient, tokens, &push.Message{Title: title, Body: body, Data: data})
+	if err != nil {
+		return 0, 0, err
+	}
+	return result.SuccessCount, result.FailureCount, nil
+}
+
+// sendToTokens gửi qua notifier, token bị từ chối được xóa khỏi device_tokens
+func (p *Pusher) sendToTokens(ctx context.Context, notifier push.Notifier, tokens []string, message *push.Message) (*push.Result, error) {
+	result, err := notifier.Send(ctx, tokens, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send push notifications: %w", err)
+	}
+
+	p.removeInvalidTokens(ctx, result.Invalid)
+	return result, nil
+}
+
+// SendToUser gửi tới mọi thiết bị user đã đăng ký, mỗi thiết bị qua provider của platform.
+// Trả ErrNoDevices nếu user chưa có thiết bị, lỗi nếu không thiết bị nào nhận được
+func (p *Pusher) SendToUser(ctx context.Context, userID uuid.UUID, title, body string, data map[string]string) (int, int, error) {
+	if !p.Enabled() {
+		return 0, 0, ErrPushDisabled
+	}
+
+	devices, err := p.deviceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load devices: %w", err)
+	}
+	if len(devices) == 0 {
+		return 0, 0, ErrNoDevices
+	}
+
+	// Nhóm token theo provider, thiết bị của platform chưa có provider tính là thất bại
+	var platforms []string
+	tokens := make(map[string][]string)
+	skipped := 0
+	for _, device := range devices {
+		if p.notifier(device.Platform) == nil {
+			skipped++
+			continue
+		}
+		if _, ok := tokens[device.Platform]; !ok {
+			platforms = append(platforms, device.Platform)
+		}
+		tokens[device.Platform] = append(tokens[device.Platform], device.Token)
+	}
+
+	message := &push.Message{Title: title, Body: body, Data: data}
+	success, failure := 0, skipped
+	var sendErr error
+	for _, platform := range platforms {
+		result, err := p.sendToTokens(ctx, p.notifier(platform), tokens[platform], message)
+		if err != nil {
+			failure += len(tokens[platform])
+			sendErr = err
+			continue
+		}
+		success += result.SuccessCount
+		failure += result.FailureCount
+	}
+
+	if success == 0 {
+		if sendErr != nil {
+			return 0, failure, sendErr
+		}
+		return 0, failure, fmt.Errorf("failed to send push notification to %d devices of user %s", failure, userID)
+	}
+	return success, failure, nil
+}
+
+// removeInvalidTokens xóa token FCM đã từ chối khỏi device_tokens để không gửi lại mãi,
+// ghi action event cho mỗi thiết bị bị xóa. Lỗi chỉ log, không ảnh hưởng kết quả gửi
+func (p *Pusher) removeInvalidTokens(ctx context.Context, invalid []push.InvalidToken) {
+	if p.deviceRepo == nil || len(invalid) == 0 {
+		return
+	}
+
+	reasons := make(map[string]string, len(invalid))
+	tokens := make([]string, len(invalid))
+	for i, token := range invalid {
+		reasons[token.Token] = token.Reason
+		tokens[i] = token.Token
+	}
+
+	devices, err := p.deviceRepo.DeleteByTokens(ctx, tokens)
+	if err != nil {
+		logger.Errorf("Failed to remove %d invalid FCM tokens: %v", len(tokens), err)
+		return
+	}
+
+	for _, device := range devices {
+		actionEvent.LogEventAsync(context.WithoutCancel(ctx), actionEvent.Event{
+			Action:   "device_token_invalidated",
+			Entity:   "device_token",
+			EntityID: device.ID.String(),
+			UserID:   device.UserID.String(),
+			Data: actionEvent.EventData{Old: map[string]interface{}{
+				"platform":  device.Platform,
+				"last_seen": device.LastSeen,
+				"reason":    reasons[device.Token],
+			}},
+			Timestamp: utils.Now(),
+			Job:       deviceTokenJob,
+		})
+	}
+	if len(devices) > 0 {
+		logger.Infof("Removed %d invalid FCM tokens", len(devices))
+	}
+}
+
+func buildNotification(title, body string) *fcm.Notification {
+	return fcm.NewNotificationBuilder().
+		SetTitle(title).
+		SetBody(body).
+		Build()
+}
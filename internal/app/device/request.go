@@ -0,0 +1,7 @@
+package device
+
+// RegisterDeviceRequest request đăng ký FCM token của thiết bị
+type RegisterDeviceRequest struct {
+	Token    string `json:"token" validate:"required,max=512"`
+	Platform string `json:"platform" validate:"required,oneof=android ios web"`
+}
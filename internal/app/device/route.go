@@ -0,0 +1,13 @@
+package device
+
+import "github.com/go-chi/chi/v5"
+
+// RegisterRoutes đăng ký routes quản lý thiết bị nhận push notification của user đang đăng nhập
+// Prefix: /api/v1/devices
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/devices", func(r chi.Router) {
+		r.Get("/", h.Index)                // GET /api/v1/devices - Thiết bị đã đăng ký
+		r.Post("/", h.Register)            // POST /api/v1/devices - Đăng ký token (mở app, token được cấp mới)
+		r.Delete("/{token}", h.Unregister) // DELETE /api/v1/devices/{token} - Gỡ token (đăng xuất)
+	})
+}
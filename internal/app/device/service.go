@@ -0,0 +1,76 @@
+package device
+
+import (
+	"context"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+// Service quản lý FCM token của thiết bị (user.Service.SendNotificationToUser gửi tới mọi thiết bị của user)
+type Service struct {
+	deviceRepo repository.DeviceTokenRepository
+}
+
+// NewService tạo device service mới
+func NewService(deviceRepo repository.DeviceTokenRepository) *Service {
+	return &Service{deviceRepo: deviceRepo}
+}
+
+// List thiết bị đã đăng ký của user
+func (s *Service) List(ctx context.Context, userID uuid.UUID) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	devices, err := s.deviceRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		logger.Errorf("Failed to list devices of user %s: %v", userID, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+	return response.SuccessResponse(lang, response.CodeSuccess, devices)
+}
+
+// Register đăng ký token của thiết bị. App gọi mỗi lần mở và khi FCM cấp token mới; token đang thuộc user khác
+// (đăng nhập tài khoản khác trên cùng thiết bị) được chuyển sang user này.
+func (s *Service) Register(ctx context.Context, userID uuid.UUID, req RegisterDeviceRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	device := &model.DeviceToken{
+		UserID:   userID,
+		Token:    req.Token,
+		Platform: req.Platform,
+		LastSeen: clock.FromContext(ctx).Now(),
+	}
+	if err := s.deviceRepo.Register(ctx, device); err != nil {
+		logger.Errorf("Failed to register device of user %s: %v", userID, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+
+	// Token đã có thì giữ id, created_at của bản ghi cũ
+	stored, err := s.deviceRepo.FirstWhere(ctx, "token = ?", req.Token)
+	if err != nil {
+		logger.Errorf("Failed to load device of user %s: %v", userID, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+	return response.SuccessResponse(lang, response.CodeSuccess, stored)
+}
+
+// Unregister gỡ token khỏi user, thiết bị không nhận push nữa
+func (s *Service) Unregister(ctx context.Context, userID uuid.UUID, token string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	deleted, err := s.deviceRepo.DeleteByToken(ctx, userID, token)
+	if err != nil {
+		logger.Errorf("Failed to unregister device of user %s: %v", userID, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+	if !deleted {
+		return response.NotFoundResponse(lang, response.CodeDeviceNotFound)
+	}
+	return response.SuccessResponse(lang, response.CodeDeleted, nil)
+}
@@ -0,0 +1,68 @@
+package e2e
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+)
+
+// Handler chứa service của test data API
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Enabled test data API có được đăng ký không
+func (h *Handler) Enabled() bool {
+	return h.service.Enabled()
+}
+
+// Reset - POST /__e2e/reset
+func (h *Handler) Reset(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.Reset(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// StoreUser - POST /__e2e/users
+func (h *Handler) StoreUser(w http.ResponseWriter, r *http.Request) {
+	var input CreateUserRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.CreateUser(r.Context(), input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Clock - GET /__e2e/clock
+func (h *Handler) Clock(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.GetClock(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// SetClock - POST /__e2e/clock
+func (h *Handler) SetClock(w http.ResponseWriter, r *http.Request) {
+	var input SetClockRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.SetClock(r.Context(), input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// ResetClock - DELETE /__e2e/clock
+func (h *Handler) ResetClock(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.ResetClock(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
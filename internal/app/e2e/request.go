@@ -0,0 +1,17 @@
+package e2e
+
+import "time"
+
+// CreateUserRequest request tạo fixture user, field bỏ trống dùng giá trị mặc định
+type CreateUserRequest struct {
+	Name     string `json:"name" validate:"omitempty,max=255"`   // Mặc định "E2E User"
+	Email    string `json:"email" validate:"omitempty,email"`    // Mặc định email ngẫu nhiên e2e-xxxx@example.test
+	Password string `json:"password" validate:"omitempty,min=8"` // Mặc định Password123!
+	Role     string `json:"role" validate:"omitempty,max=50"`    // Tên role, mặc định user
+}
+
+// SetClockRequest request điều chỉnh clock: tua thêm Advance hoặc đặt về Time
+type SetClockRequest struct {
+	Advance string     `json:"advance" validate:"required_without=Time"` // Go duration, vd. 90m, 24h, -1h
+	Time    *time.Time `json:"time" validate:"required_without=Advance"` // RFC3339
+}
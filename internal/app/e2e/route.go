@@ -0,0 +1,40 @@
+package e2e
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TokenHeader header chứa token của test data API (khi cấu hình E2E_API_TOKEN)
+const TokenHeader = "X-E2E-Token"
+
+// RegisterRoutes đăng ký routes điều phối dữ liệu cho E2E suite, chỉ gọi khi h.Enabled()
+// Prefix: /__e2e
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/__e2e", func(r chi.Router) {
+		r.Use(h.requireToken)
+
+		r.Post("/reset", h.Reset)        // POST /__e2e/reset - Reset database về dữ liệu seed, xóa cache, reset clock
+		r.Post("/users", h.StoreUser)    // POST /__e2e/users - Tạo fixture user kèm token
+		r.Get("/clock", h.Clock)         // GET /__e2e/clock - Thời gian hiện tại của server
+		r.Post("/clock", h.SetClock)     // POST /__e2e/clock - Tua hoặc đặt clock
+		r.Delete("/clock", h.ResetClock) // DELETE /__e2e/clock - Đưa clock về thời gian thật
+	})
+}
+
+// requireToken kiểm tra header X-E2E-Token khi có cấu hình token
+func (h *Handler) requireToken(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := h.service.config.Token
+		if token != "" && subtle.ConstantTimeCompare([]byte(r.Header.Get(TokenHeader)), []byte(token)) != 1 {
+			response.Unauthorized(w, i18n.GetLanguageFromContext(r.Context()), response.CodeUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
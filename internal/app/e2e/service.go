@@ -0,0 +1,245 @@
+package e2e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/database/seeders"
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultName     = "E2E User"
+	defaultPassword = "Password123!"
+	defaultRole     = "user"
+)
+
+// preservedTables bảng không bị xóa khi reset (trạng thái migration)
+var preservedTables = map[string]bool{"schema_migrations": true}
+
+// Config cấu hình test data API
+type Config struct {
+	Enabled bool
+	Token   string        // Rỗng: không kiểm tra header X-E2E-Token
+	Clock   *clock.Offset // Clock mặc định của process, endpoint /clock điều chỉnh clock này
+}
+
+// Service điều phối dữ liệu cho E2E suite: reset database, tạo fixture user, tua clock
+type Service struct {
+	db          *gorm.DB
+	cache       cache.Cache
+	userRepo    repository.UserRepository
+	roleRepo    repository.RoleRepository
+	authService *auth.Service
+	config      Config
+}
+
+// NewService tạo e2e service mới
+func NewService(
+	db *gorm.DB,
+	cacheClient cache.Cache,
+	userRepo repository.UserRepository,
+	roleRepo repository.RoleRepository,
+	authService *auth.Service,
+	config Config,
+) *Service {
+	return &Service{
+		db:          db,
+		cache:       cacheClient,
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		authService: authService,
+		config:      config,
+	}
+}
+
+// Enabled test data API có được bật không (chỉ khi APP_ENV=test)
+func (s *Service) Enabled() bool {
+	return s.config.Enabled
+}
+
+// ResetResponse kết quả reset
+type ResetResponse struct {
+	Tables []string  `json:"tables"`
+	Now    time.Time `json:"now"`
+}
+
+// Reset xóa dữ liệu mọi bảng, chạy lại seeders, xóa cache và đưa clock về thời gian thật
+func (s *Service) Reset(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	tables, err := s.truncateTables(ctx)
+	if err != nil {
+		logger.ErrorWithErr(err, "E2E reset: failed to truncate tables")
+		return response.InternalServerErrorResponse(lang, response.CodeTestDataResetFailed)
+	}
+	if err := seeders.RunSeeders(s.db.WithContext(ctx)); err != nil {
+		logger.ErrorWithErr(err, "E2E reset: failed to run seeders")
+		return response.InternalServerErrorResponse(lang, response.CodeTestDataResetFailed)
+	}
+	// Cache giữ permission, token version, rate limit... của dữ liệu cũ
+	if err := s.cache.FlushDB(ctx); err != nil {
+		logger.ErrorWithErr(err, "E2E reset: failed to flush cache")
+		return response.InternalServerErrorResponse(lang, response.CodeTestDataResetFailed)
+	}
+	s.config.Clock.Reset()
+
+	return response.SuccessResponse(lang, response.CodeSuccess, ResetResponse{
+		Tables: tables,
+		Now:    s.config.Clock.Now(),
+	})
+}
+
+// truncateTables xóa dữ liệu mọi bảng (giữ schema), trả về danh sách bảng đã xóa
+func (s *Service) truncateTables(ctx context.Context) ([]string, error) {
+	db := s.db.WithContext(ctx)
+
+	all, err := db.Migrator().GetTables()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+	tables := make([]string, 0, len(all))
+	for _, table := range all {
+		if !preservedTables[table] {
+			tables = append(tables, table)
+		}
+	}
+	if len(tables) == 0 {
+		return tables, nil
+	}
+
+	if db.Dialector.Name() == "postgres" {
+		quoted := make([]string, len(tables))
+		for i, table := range tables {
+			quoted[i] = db.Statement.Quote(table)
+		}
+		return tables, db.Exec("TRUNCATE TABLE " + strings.Join(quoted, ", ") + " RESTART IDENTITY CASCADE").Error
+	}
+
+	// Database khác (SQLite trong test): DELETE từng bảng trong 1 transaction
+	return tables, db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range tables {
+			if err := tx.Exec("DELETE FROM " + tx.Statement.Quote(table)).Error; err != nil {
+				return fmt.Errorf("failed to clear table %s: %w", table, err)
+			}
+		}
+		return nil
+	})
+}
+
+// FixtureResponse fixture user kèm token và mật khẩu để đăng nhập qua UI
+type FixtureResponse struct {
+	*auth.LoginResponse
+	Password string `json:"password"`
+}
+
+// CreateUser tạo fixture user với role chỉ định và cấp token cho user
+func (s *Service) CreateUser(ctx context.Context, input CreateUserRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	if input.Name == "" {
+		input.Name = defaultName
+	}
+	if input.Email == "" {
+		input.Email = fmt.Sprintf("e2e-%s@example.test", uuid.NewString()[:8])
+	}
+	if input.Password == "" {
+		input.Password = defaultPassword
+	}
+	if input.Role == "" {
+		input.Role = defaultRole
+	}
+
+	role, err := s.roleRepo.FindByName(ctx, input.Role)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFoundResponse(lang, response.CodeRoleNotFound)
+		}
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	if _, err := s.userRepo.GetUserByEmail(ctx, input.Email); err == nil {
+		return response.ConflictResponse(lang, response.CodeEmailAlreadyExists)
+	}
+
+	hashedPassword, err := utils.HashPassword(input.Password)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	user := &model.User{
+		Name:     input.Name,
+		Email:    input.Email,
+		Password: hashedPassword,
+		RoleID:   &role.ID,
+		IsActive: true,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	tokens, err := s.authService.IssueTokens(ctx, user.ID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeCreated, FixtureResponse{
+		LoginResponse: tokens,
+		Password:      input.Password,
+	})
+}
+
+// ClockResponse thời gian hiện tại của server và độ lệch so với thời gian thật
+type ClockResponse struct {
+	Now           time.Time `json:"now"`
+	OffsetSeconds float64   `json:"offset_seconds"`
+}
+
+// GetClock trả về thời gian hiện tại của server
+func (s *Service) GetClock(ctx context.Context) *response.Response {
+	return response.SuccessResponse(i18n.GetLanguageFromContext(ctx), response.CodeSuccess, s.clockResponse())
+}
+
+// SetClock tua clock thêm Advance hoặc đặt clock về Time (clock vẫn tiếp tục chạy)
+func (s *Service) SetClock(ctx context.Context, input SetClockRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	if input.Time != nil {
+		s.config.Clock.Set(*input.Time)
+	} else {
+		d, err := time.ParseDuration(input.Advance)
+		if err != nil {
+			return response.BadRequestResponse(lang, response.CodeInvalidInput, []response.ErrorDetail{
+				response.NewErrorDetail("advance", "must be a duration such as 90m or 24h"),
+			})
+		}
+		s.config.Clock.Advance(d)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, s.clockResponse())
+}
+
+// ResetClock đưa clock về thời gian thật
+func (s *Service) ResetClock(ctx context.Context) *response.Response {
+	s.config.Clock.Reset()
+	return response.SuccessResponse(i18n.GetLanguageFromContext(ctx), response.CodeSuccess, s.clockResponse())
+}
+
+func (s *Service) clockResponse() ClockResponse {
+	return ClockResponse{
+		Now:           s.config.Clock.Now(),
+		OffsetSeconds: s.config.Clock.Offset().Seconds(),
+	}
+}
@@ -3,10 +3,10 @@ package friend
 import (
 	"net/http"
 
-	"api-core/pkg/i18n"
-	"api-core/pkg/jwt"
-	"api-core/pkg/response"
-	"api-core/pkg/validator"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
 
 	"github.com/google/uuid"
 )
@@ -165,6 +165,26 @@ func (h *Handler) GetFriendsList(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, statusCode, *resp)
 }
 
+// GetFriendsPresence - GET /friends/presence
+func (h *Handler) GetFriendsPresence(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	resp := h.service.GetFriendsPresence(r.Context(), userUUID)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
 // GetPendingRequests - GET /friends/requests/pending
 func (h *Handler) GetPendingRequests(w http.ResponseWriter, r *http.Request) {
 	lang := i18n.GetLanguageFromContext(r.Context())
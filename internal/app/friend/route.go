@@ -9,6 +9,9 @@ func RegisterRoutes(r chi.Router, h *Handler) {
 		// Danh sách bạn bè
 		r.Get("/", h.GetFriendsList) // GET /api/v1/friends
 
+		// Trạng thái online (socket presence)
+		r.Get("/presence", h.GetFriendsPresence) // GET /api/v1/friends/presence
+
 		// Friend requests
 		r.Route("/requests", func(r chi.Router) {
 			r.Post("/", h.SendFriendRequest)         // POST /api/v1/friends/requests - Gửi lời mời
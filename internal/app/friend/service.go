@@ -3,10 +3,11 @@ package friend
 import (
 	"context"
 
-	model "api-core/internal/models"
-	repository "api-core/internal/repositories"
-	"api-core/pkg/i18n"
-	"api-core/pkg/response"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/socket"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -17,6 +18,7 @@ type Service struct {
 	friendRequestRepo repository.FriendRequestRepository
 	friendshipRepo    repository.FriendshipRepository
 	userRepo          repository.UserRepository
+	presence          socket.PresenceStore
 	db                *gorm.DB
 }
 
@@ -25,12 +27,14 @@ func NewService(
 	friendRequestRepo repository.FriendRequestRepository,
 	friendshipRepo repository.FriendshipRepository,
 	userRepo repository.UserRepository,
+	presence socket.PresenceStore,
 	db *gorm.DB,
 ) *Service {
 	return &Service{
 		friendRequestRepo: friendRequestRepo,
 		friendshipRepo:    friendshipRepo,
 		userRepo:          userRepo,
+		presence:          presence,
 		db:                db,
 	}
 }
@@ -210,22 +214,14 @@ func (s *Service) CancelFriendRequest(ctx context.Context, requestID, senderID u
 func (s *Service) GetFriendsList(ctx context.Context, userID uuid.UUID) *response.Response {
 	lang := i18n.GetLanguageFromContext(ctx)
 
-	// Lấy tất cả friendships
-	friendships, err := s.friendshipRepo.FindByUserID(ctx, userID)
+	friendIDs, err := s.friendIDs(ctx, userID)
 	if err != nil {
 		return response.InternalServerErrorResponse(lang, response.CodeGetFriendsListFailed)
 	}
 
 	// Lấy thông tin user của từng bạn
-	friends := make([]model.User, 0, len(friendships))
-	for _, friendship := range friendships {
-		var friendID uuid.UUID
-		if friendship.UserID == userID {
-			friendID = friendship.FriendID
-		} else {
-			friendID = friendship.UserID
-		}
-
+	friends := make([]model.User, 0, len(friendIDs))
+	for _, friendID := range friendIDs {
 		friend, err := s.userRepo.FindByID(ctx, friendID)
 		if err != nil {
 			continue // Bỏ qua nếu không tìm thấy
@@ -236,6 +232,49 @@ func (s *Service) GetFriendsList(ctx context.Context, userID uuid.UUID) *respons
 	return response.SuccessResponse(lang, response.CodeSuccess, friends)
 }
 
+// GetFriendsPresence lấy trạng thái online của từng bạn bè (có kết nối socket trên bất kỳ instance nào)
+func (s *Service) GetFriendsPresence(ctx context.Context, userID uuid.UUID) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	friendIDs, err := s.friendIDs(ctx, userID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeGetFriendsListFailed)
+	}
+
+	ids := make([]string, len(friendIDs))
+	for i, friendID := range friendIDs {
+		ids[i] = friendID.String()
+	}
+	online, err := s.presence.Online(ctx, ids)
+	if err != nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeServiceUnavailable)
+	}
+
+	presence := make([]socket.PresenceData, len(ids))
+	for i, id := range ids {
+		presence[i] = socket.PresenceData{UserID: id, Online: online[id]}
+	}
+	return response.SuccessResponse(lang, response.CodeSuccess, presence)
+}
+
+// friendIDs ID bạn bè của user
+func (s *Service) friendIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	friendships, err := s.friendshipRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	friendIDs := make([]uuid.UUID, len(friendships))
+	for i, friendship := range friendships {
+		if friendship.UserID == userID {
+			friendIDs[i] = friendship.FriendID
+		} else {
+			friendIDs[i] = friendship.UserID
+		}
+	}
+	return friendIDs, nil
+}
+
 // GetPendingRequests lấy danh sách lời mời đang chờ (nhận được)
 func (s *Service) GetPendingRequests(ctx context.Context, userID uuid.UUID) *response.Response {
 	lang := i18n.GetLanguageFromContext(ctx)
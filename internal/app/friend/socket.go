@@ -0,0 +1,46 @@
+package friend
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/google/uuid"
+)
+
+// RoomAuthorizer kiểm tra quyền theo dõi online status trên socket hub: room "presence:<user_id>" chỉ user đó
+// và bạn bè được join, chỉ server gửi vào room này. Các room khác không giới hạn.
+func RoomAuthorizer(friendshipRepo repository.FriendshipRepository) socket.RoomAuthorizer {
+	return func(ctx context.Context, client *socket.Client, room string, action socket.RoomAction) error {
+		if !strings.HasPrefix(room, socket.PresenceRoomPrefix) {
+			return nil
+		}
+		if action == socket.RoomActionPublish {
+			return errors.New("presence rooms are read only")
+		}
+
+		watched := strings.TrimPrefix(room, socket.PresenceRoomPrefix)
+		if watched == client.UserID {
+			return nil
+		}
+		userID, err := uuid.Parse(client.UserID)
+		if err != nil {
+			return errors.New("not a friend of the user")
+		}
+		friendID, err := uuid.Parse(watched)
+		if err != nil {
+			return errors.New("invalid user id")
+		}
+		isFriend, err := friendshipRepo.IsFriend(ctx, userID, friendID)
+		if err != nil {
+			return err
+		}
+		if !isFriend {
+			return errors.New("not a friend of the user")
+		}
+		return nil
+	}
+}
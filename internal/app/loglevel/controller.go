@@ -0,0 +1,48 @@
+package loglevel
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler chứa service log level
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Index - GET /log-levels
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.List(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Update - PUT /log-levels/{module}
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	var input SetLevelRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	ttl := time.Duration(input.TTLSeconds) * time.Second
+	resp := h.service.SetLevel(r.Context(), chi.URLParam(r, "module"), input.Level, ttl)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Reset - DELETE /log-levels/{module}
+func (h *Handler) Reset(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.Reset(r.Context(), chi.URLParam(r, "module"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
@@ -0,0 +1,7 @@
+package loglevel
+
+// SetLevelRequest request đổi level của module lúc chạy
+type SetLevelRequest struct {
+	Level      string `json:"level" validate:"required,oneof=debug info warn error"`
+	TTLSeconds int    `json:"ttl_seconds" validate:"omitempty,min=1,max=604800"` // 0: giữ tới khi reset, tối đa 7 ngày
+}
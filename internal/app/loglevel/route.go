@@ -0,0 +1,18 @@
+package loglevel
+
+import (
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes đăng ký routes đổi log level lúc chạy (admin)
+// Prefix: /api/v1/log-levels
+func RegisterRoutes(r chi.Router, h *Handler, perm *jwt.PermissionChecker) {
+	r.Route("/log-levels", func(r chi.Router) {
+		r.With(perm.Require("logs.view")).Get("/", h.Index) // GET /api/v1/log-levels - Level theo config, level đặt lúc chạy và level đang áp dụng của mỗi module
+
+		r.With(perm.Require("logs.manage")).Put("/{module}", h.Update)   // PUT /api/v1/log-levels/{module} - Đổi level cho mọi process (tùy chọn tự hết hạn)
+		r.With(perm.Require("logs.manage")).Delete("/{module}", h.Reset) // DELETE /api/v1/log-levels/{module} - Quay về level theo config
+	})
+}
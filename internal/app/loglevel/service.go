@@ -0,0 +1,84 @@
+package loglevel
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// Service đổi log level của từng module (app, request, gorm, cron, socket) lúc chạy, không cần restart.
+// Level lưu trong logger.LevelStore dùng chung, mọi process (api, worker, scheduler) đọc lại theo LOG_LEVEL_SYNC_SECONDS,
+// process đang xử lý request áp dụng ngay.
+type Service struct {
+	store logger.LevelStore // nil: không kết nối được Redis, chỉ xem được level
+}
+
+// NewService tạo log level service mới
+func NewService(store logger.LevelStore) *Service {
+	return &Service{store: store}
+}
+
+// List level của tất cả module trên process này
+func (s *Service) List(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	return response.SuccessResponse(lang, response.CodeSuccess, logger.Levels())
+}
+
+// SetLevel đặt level của module cho mọi process, ttl > 0: tự quay về level theo config sau ttl
+func (s *Service) SetLevel(ctx context.Context, module, levelName string, ttl time.Duration) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if !slices.Contains(logger.Modules, module) {
+		return response.NotFoundResponse(lang, response.CodeLogModuleNotFound)
+	}
+	if s.store == nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeLogLevelStoreUnavailable)
+	}
+
+	level, err := logger.ParseLevel(levelName)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeBadRequest, nil)
+	}
+	if err := s.store.Set(ctx, module, level, ttl); err != nil {
+		logger.Warnf("Log levels: %v", err)
+		return response.ServiceUnavailableResponse(lang, response.CodeLogLevelStoreUnavailable)
+	}
+	if err := logger.SetLevel(module, level); err != nil {
+		return response.NotFoundResponse(lang, response.CodeLogModuleNotFound)
+	}
+
+	logger.Infof("Log levels: %s set to %s (ttl: %s)", module, level, ttl)
+	return response.SuccessResponse(lang, response.CodeUpdated, s.find(module))
+}
+
+// Reset bỏ level đặt lúc chạy, module quay về level theo config trên mọi process
+func (s *Service) Reset(ctx context.Context, module string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if !slices.Contains(logger.Modules, module) {
+		return response.NotFoundResponse(lang, response.CodeLogModuleNotFound)
+	}
+	if s.store == nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeLogLevelStoreUnavailable)
+	}
+
+	if err := s.store.Reset(ctx, module); err != nil {
+		logger.Warnf("Log levels: %v", err)
+		return response.ServiceUnavailableResponse(lang, response.CodeLogLevelStoreUnavailable)
+	}
+	logger.ResetLevel(module)
+
+	logger.Infof("Log levels: %s reset to configured level", module)
+	return response.SuccessResponse(lang, response.CodeUpdated, s.find(module))
+}
+
+func (s *Service) find(module string) logger.ModuleLevel {
+	for _, level := range logger.Levels() {
+		if level.Module == module {
+			return level
+		}
+	}
+	return logger.ModuleLevel{Module: module}
+}
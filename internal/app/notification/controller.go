@@ -0,0 +1,95 @@
+package notification
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+// Handler chứa service của notification center
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Index - GET /notifications?page=1&per_page=20&unread=true
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	req, ok := parseListRequest(r)
+	if !ok {
+		response.BadRequest(w, i18n.GetLanguageFromContext(r.Context()), response.CodeBadRequest, nil)
+		return
+	}
+
+	resp := h.service.List(r.Context(), userID, req)
+	response.JSON(w, response.GetHTTPStatusCode(resp.Code), *resp)
+}
+
+// UnreadCount - GET /notifications/unread-count
+func (h *Handler) UnreadCount(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.service.UnreadCount(r.Context(), userID)
+	response.JSON(w, response.GetHTTPStatusCode(resp.Code), *resp)
+}
+
+// MarkRead - POST /notifications/{id}/read
+func (h *Handler) MarkRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.NotFound(w, i18n.GetLanguageFromContext(r.Context()), response.CodeNotificationNotFound)
+		return
+	}
+
+	resp := h.service.MarkRead(r.Context(), userID, id)
+	response.JSON(w, response.GetHTTPStatusCode(resp.Code), *resp)
+}
+
+// MarkAllRead - POST /notifications/read-all
+func (h *Handler) MarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID, ok := currentUserID(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.service.MarkAllRead(r.Context(), userID)
+	response.JSON(w, response.GetHTTPStatusCode(resp.Code), *resp)
+}
+
+// currentUserID user đang đăng nhập, ghi 401/400 nếu không có
+func currentUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,30 @@
+package notification
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// ListRequest query params của GET /notifications
+type ListRequest struct {
+	Page       int
+	PerPage    int
+	UnreadOnly bool
+}
+
+// parseListRequest parse ?page=&per_page=&unread=true|false, false nếu giá trị không hợp lệ
+func parseListRequest(r *http.Request) (*ListRequest, bool) {
+	params := utils.ParseQueryParams(r)
+	req := &ListRequest{Page: params.Page, PerPage: params.PerPage}
+
+	if v := r.URL.Query().Get("unread"); v != "" {
+		unread, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, false
+		}
+		req.UnreadOnly = unread
+	}
+	return req, true
+}
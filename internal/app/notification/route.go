@@ -0,0 +1,14 @@
+package notification
+
+import "github.com/go-chi/chi/v5"
+
+// RegisterRoutes đăng ký routes notification center của user đang đăng nhập
+// Prefix: /api/v1/notifications
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/notifications", func(r chi.Router) {
+		r.Get("/", h.Index)                   // GET /api/v1/notifications - Inbox, mới nhất trước (lọc chưa đọc)
+		r.Get("/unread-count", h.UnreadCount) // GET /api/v1/notifications/unread-count - Số chưa đọc (badge)
+		r.Post("/read-all", h.MarkAllRead)    // POST /api/v1/notifications/read-all - Đánh dấu tất cả đã đọc
+		r.Post("/{id}/read", h.MarkRead)      // POST /api/v1/notifications/{id}/read - Đánh dấu đã đọc
+	})
+}
@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"context"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// Service notification center của user. Thông báo được tạo khi gửi push (user.Service.SendNotificationToUser)
+type Service struct {
+	notificationRepo repository.NotificationRepository
+}
+
+// NewService tạo notification service mới
+func NewService(notificationRepo repository.NotificationRepository) *Service {
+	return &Service{notificationRepo: notificationRepo}
+}
+
+// List inbox của user, mới nhất trước
+func (s *Service) List(ctx context.Context, userID uuid.UUID, req *ListRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	notifications, total, err := s.notificationRepo.FindByUserID(ctx, userID, req.UnreadOnly, req.Page, req.PerPage)
+	if err != nil {
+		logger.Errorf("Failed to list notifications of user %s: %v", userID, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+
+	pagination := utils.NewPagination(req.Page, req.PerPage, total)
+	meta := &response.Meta{
+		Page:       pagination.Page,
+		PerPage:    pagination.PerPage,
+		Total:      pagination.Total,
+		TotalPages: pagination.TotalPages,
+	}
+
+	return response.SuccessResponseWithMeta(lang, response.CodeSuccess, utils.PaginatedResponse(notifications, pagination), meta)
+}
+
+// UnreadCount số thông báo chưa đọc (badge)
+func (s *Service) UnreadCount(ctx context.Context, userID uuid.UUID) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	count, err := s.notificationRepo.CountUnread(ctx, userID)
+	if err != nil {
+		logger.Errorf("Failed to count unread notifications of user %s: %v", userID, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+	return response.SuccessResponse(lang, response.CodeSuccess, map[string]int64{"unread_count": count})
+}
+
+// MarkRead đánh dấu một thông báo đã đọc
+func (s *Service) MarkRead(ctx context.Context, userID, id uuid.UUID) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	found, err := s.notificationRepo.MarkRead(ctx, userID, id, clock.FromContext(ctx).Now())
+	if err != nil {
+		logger.Errorf("Failed to mark notification %s as read: %v", id, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+	if !found {
+		return response.NotFoundResponse(lang, response.CodeNotificationNotFound)
+	}
+
+	notification, err := s.notificationRepo.FindByID(ctx, id)
+	if err != nil {
+		logger.Errorf("Failed to load notification %s: %v", id, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+	return response.SuccessResponse(lang, response.CodeSuccess, notification)
+}
+
+// MarkAllRead đánh dấu mọi thông báo của user đã đọc
+func (s *Service) MarkAllRead(ctx context.Context, userID uuid.UUID) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	updated, err := s.notificationRepo.MarkAllRead(ctx, userID, clock.FromContext(ctx).Now())
+	if err != nil {
+		logger.Errorf("Failed to mark notifications of user %s as read: %v", userID, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+	return response.SuccessResponse(lang, response.CodeSuccess, map[string]int64{"updated": updated})
+}
@@ -0,0 +1,117 @@
+package queueadmin
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	defaultDeadLimit = 50
+	maxDeadLimit     = 500
+)
+
+// Handler chứa service quản trị queue
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Index - GET /queues
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.ListQueues(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Show - GET /queues/{name}
+func (h *Handler) Show(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.GetQueue(r.Context(), chi.URLParam(r, "name"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Purge - DELETE /queues/{name}/messages
+func (h *Handler) Purge(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.Purge(r.Context(), chi.URLParam(r, "name"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Pause - POST /queues/{name}/pause
+func (h *Handler) Pause(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.SetPaused(r.Context(), chi.URLParam(r, "name"), true)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Resume - POST /queues/{name}/resume
+func (h *Handler) Resume(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.SetPaused(r.Context(), chi.URLParam(r, "name"), false)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// DeadMessages - GET /queues/{name}/dead?offset=0&limit=50
+func (h *Handler) DeadMessages(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+
+	var err error
+	offset := 0
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			response.BadRequest(w, lang, response.CodeBadRequest, nil)
+			return
+		}
+	}
+
+	limit := defaultDeadLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			response.BadRequest(w, lang, response.CodeBadRequest, nil)
+			return
+		}
+	}
+	if limit > maxDeadLimit {
+		limit = maxDeadLimit
+	}
+
+	resp := h.service.ListDead(r.Context(), chi.URLParam(r, "name"), offset, limit)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// RequeueDead - POST /queues/{name}/dead/requeue
+func (h *Handler) RequeueDead(w http.ResponseWriter, r *http.Request) {
+	var input DeadMessagesRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.RequeueDead(r.Context(), chi.URLParam(r, "name"), input.IDs)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// PurgeDead - POST /queues/{name}/dead/purge
+func (h *Handler) PurgeDead(w http.ResponseWriter, r *http.Request) {
+	var input DeadMessagesRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.PurgeDead(r.Context(), chi.URLParam(r, "name"), input.IDs)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
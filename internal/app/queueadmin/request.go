@@ -0,0 +1,6 @@
+package queueadmin
+
+// DeadMessagesRequest request cho requeue/xóa message trong dead queue
+type DeadMessagesRequest struct {
+	IDs []string `json:"ids" validate:"omitempty,max=1000,dive,required"` // Rỗng = tất cả message trong dead queue
+}
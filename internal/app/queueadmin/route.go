@@ -0,0 +1,23 @@
+package queueadmin
+
+import (
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes đăng ký routes quản trị queue (admin)
+// Prefix: /api/v1/queues
+func RegisterRoutes(r chi.Router, h *Handler, perm *jwt.PermissionChecker) {
+	r.Route("/queues", func(r chi.Router) {
+		r.With(perm.Require("queues.view")).Get("/", h.Index)                   // GET /api/v1/queues - Danh sách queue, số message, tuổi message cũ nhất
+		r.With(perm.Require("queues.view")).Get("/{name}", h.Show)              // GET /api/v1/queues/{name} - Chi tiết 1 queue
+		r.With(perm.Require("queues.view")).Get("/{name}/dead", h.DeadMessages) // GET /api/v1/queues/{name}/dead - Message trong dead queue
+
+		r.With(perm.Require("queues.manage")).Delete("/{name}/messages", h.Purge)         // DELETE /api/v1/queues/{name}/messages - Xóa message đang chờ
+		r.With(perm.Require("queues.manage")).Post("/{name}/pause", h.Pause)              // POST /api/v1/queues/{name}/pause - Tạm dừng consume
+		r.With(perm.Require("queues.manage")).Post("/{name}/resume", h.Resume)            // POST /api/v1/queues/{name}/resume - Tiếp tục consume
+		r.With(perm.Require("queues.manage")).Post("/{name}/dead/requeue", h.RequeueDead) // POST /api/v1/queues/{name}/dead/requeue - Đưa dead letter về queue
+		r.With(perm.Require("queues.manage")).Post("/{name}/dead/purge", h.PurgeDead)     // POST /api/v1/queues/{name}/dead/purge - Xóa dead letter
+	})
+}
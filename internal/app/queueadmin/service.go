@@ -0,0 +1,267 @@
+package queueadmin
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// QueueInfo trạng thái của 1 queue
+type QueueInfo struct {
+	Name                    string   `json:"name"`
+	Size                    int64    `json:"size"`                       // Số message đang chờ xử lý
+	OldestMessageAgeSeconds *float64 `json:"oldest_message_age_seconds"` // nil: backend không hỗ trợ (Kafka)
+	DeadSize                int64    `json:"dead_size"`                  // Số message trong dead queue
+	Paused                  bool     `json:"paused"`
+}
+
+// CountResult số message bị ảnh hưởng bởi thao tác
+type CountResult struct {
+	Count int `json:"count"`
+}
+
+// Service quản trị queue qua HTTP: xem số message, tạm dừng consume, xóa message và xử lý dead letter
+// mà không cần tool riêng của từng backend (redis-cli, RabbitMQ management, kafka-console).
+type Service struct {
+	manager     queue.QueueManager // nil: không kết nối được queue backend
+	control     queue.Control      // nil: không kết nối được Redis, không pause/resume được
+	deadLetters *queue.DeadLetterManager
+}
+
+// NewService tạo queue admin service mới
+func NewService(manager queue.QueueManager, control queue.Control) *Service {
+	s := &Service{manager: manager, control: control}
+	if manager != nil {
+		s.deadLetters = queue.NewDeadLetterManager(manager)
+	}
+	return s
+}
+
+// ListQueues danh sách queue (đã có worker consume hoặc đang có message trên backend)
+func (s *Service) ListQueues(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if s.manager == nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	names, err := s.knownQueues(ctx)
+	if err != nil {
+		logger.Warnf("Queue admin: failed to list queues: %v", err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	queues := make([]QueueInfo, 0, len(names))
+	for _, name := range names {
+		info, err := s.info(ctx, name)
+		if err != nil {
+			logger.Warnf("Queue admin: failed to read queue %s: %v", name, err)
+			return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+		}
+		queues = append(queues, *info)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, queues)
+}
+
+// GetQueue trạng thái của 1 queue
+func (s *Service) GetQueue(ctx context.Context, name string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if resp := s.checkQueue(ctx, name); resp != nil {
+		return resp
+	}
+
+	info, err := s.info(ctx, name)
+	if err != nil {
+		logger.Warnf("Queue admin: failed to read queue %s: %v", name, err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, info)
+}
+
+// Purge xóa toàn bộ message đang chờ của queue (không xóa dead queue)
+func (s *Service) Purge(ctx context.Context, name string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if resp := s.checkQueue(ctx, name); resp != nil {
+		return resp
+	}
+
+	q, err := s.manager.GetQueue(name)
+	if err != nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+	size, err := q.Size(ctx)
+	if err == nil {
+		err = q.Clear(ctx)
+	}
+	if err != nil {
+		logger.Warnf("Queue admin: failed to purge queue %s: %v", name, err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	logger.Infof("Queue admin: purged %d message(s) from queue %s", size, name)
+	return response.SuccessResponse(lang, response.CodeDeleted, CountResult{Count: int(size)})
+}
+
+// SetPaused tạm dừng/tiếp tục consume queue trên mọi worker. Message đang xử lý vẫn chạy tới khi xong,
+// worker nhận trạng thái mới trong khoảng 1 giây.
+func (s *Service) SetPaused(ctx context.Context, name string, paused bool) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if resp := s.checkQueue(ctx, name); resp != nil {
+		return resp
+	}
+	if s.control == nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	var err error
+	if paused {
+		err = s.control.Pause(ctx, name)
+	} else {
+		err = s.control.Resume(ctx, name)
+	}
+	if err != nil {
+		logger.Warnf("Queue admin: %v", err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	info, err := s.info(ctx, name)
+	if err != nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+	return response.SuccessResponse(lang, response.CodeUpdated, info)
+}
+
+// ListDead message trong dead queue, cũ nhất trước
+func (s *Service) ListDead(ctx context.Context, name string, offset, limit int) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if resp := s.checkQueue(ctx, name); resp != nil {
+		return resp
+	}
+
+	messages, err := s.deadLetters.List(ctx, name, offset, limit)
+	if errors.Is(err, queue.ErrBrowseNotSupported) {
+		return response.BadRequestResponse(lang, response.CodeQueueBrowseUnsupported, nil)
+	}
+	if err != nil {
+		logger.Warnf("Queue admin: failed to list dead letters of %s: %v", name, err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+	if messages == nil {
+		messages = []*queue.Message{}
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, messages)
+}
+
+// RequeueDead chuyển message trong dead queue về queue gốc, ids rỗng: tất cả
+func (s *Service) RequeueDead(ctx context.Context, name string, ids []string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if resp := s.checkQueue(ctx, name); resp != nil {
+		return resp
+	}
+
+	count, err := s.deadLetters.Requeue(ctx, name, ids...)
+	if err != nil {
+		logger.Warnf("Queue admin: failed to requeue dead letters of %s (%d requeued): %v", name, count, err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	logger.Infof("Queue admin: requeued %d dead letter(s) to queue %s", count, name)
+	return response.SuccessResponse(lang, response.CodeUpdated, CountResult{Count: count})
+}
+
+// PurgeDead xóa message trong dead queue, ids rỗng: tất cả
+func (s *Service) PurgeDead(ctx context.Context, name string, ids []string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if resp := s.checkQueue(ctx, name); resp != nil {
+		return resp
+	}
+
+	count, err := s.deadLetters.Purge(ctx, name, ids...)
+	if err != nil {
+		logger.Warnf("Queue admin: failed to purge dead letters of %s (%d purged): %v", name, count, err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	logger.Infof("Queue admin: purged %d dead letter(s) of queue %s", count, name)
+	return response.SuccessResponse(lang, response.CodeDeleted, CountResult{Count: count})
+}
+
+// checkQueue queue backend sẵn sàng và name là queue đã biết. Không thao tác trên tên tùy ý vì GetQueue
+// tạo queue mới (RabbitMQ declare queue, Kafka tạo topic).
+func (s *Service) checkQueue(ctx context.Context, name string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	if s.manager == nil {
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+
+	names, err := s.knownQueues(ctx)
+	if err != nil {
+		logger.Warnf("Queue admin: failed to list queues: %v", err)
+		return response.ServiceUnavailableResponse(lang, response.CodeQueueUnavailable)
+	}
+	if !slices.Contains(names, name) {
+		return response.NotFoundResponse(lang, response.CodeQueueNotFound)
+	}
+	return nil
+}
+
+// knownQueues queue do worker đăng ký (Control) và queue backend liệt kê được, bỏ dead queue, sắp xếp theo tên
+func (s *Service) knownQueues(ctx context.Context) ([]string, error) {
+	var names []string
+	if s.control != nil {
+		registered, err := s.control.Registered(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, registered...)
+	}
+
+	listed, err := s.manager.ListQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range listed {
+		if !queue.IsDeadQueue(name) {
+			names = append(names, name)
+		}
+	}
+
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+func (s *Service) info(ctx context.Context, name string) (*QueueInfo, error) {
+	q, err := s.manager.GetQueue(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &QueueInfo{Name: name}
+	if info.Size, err = q.Size(ctx); err != nil {
+		return nil, err
+	}
+	if reporter, ok := q.(queue.AgeReporter); ok {
+		age, err := reporter.OldestMessageAge(ctx)
+		if err != nil {
+			return nil, err
+		}
+		seconds := age.Seconds()
+		info.OldestMessageAgeSeconds = &seconds
+	}
+	if info.DeadSize, err = s.deadLetters.Count(ctx, name); err != nil {
+		return nil, err
+	}
+	if s.control != nil {
+		if info.Paused, err = s.control.IsPaused(ctx, name); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
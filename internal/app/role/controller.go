@@ -0,0 +1,106 @@
+package role
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler chứa service của role
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Index - GET /roles
+func (h *Handler) Index(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.GetAll(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Show - GET /roles/{id}
+func (h *Handler) Show(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp := h.service.GetByID(r.Context(), id)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Store - POST /roles
+func (h *Handler) Store(w http.ResponseWriter, r *http.Request) {
+	var input CreateRoleRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.Create(r.Context(), input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Update - PUT /roles/{id}
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input UpdateRoleRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.Update(r.Context(), id, input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Destroy - DELETE /roles/{id}
+func (h *Handler) Destroy(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp := h.service.Delete(r.Context(), id)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Permissions - GET /roles/permissions
+func (h *Handler) Permissions(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.GetPermissions(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// SyncPermissions - PUT /roles/{id}/permissions
+func (h *Handler) SyncPermissions(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input SyncPermissionsRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.SyncPermissions(r.Context(), id, input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// AssignUser - PUT /roles/{id}/users/{userId}
+func (h *Handler) AssignUser(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.AssignUser(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "userId"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// RemoveUser - DELETE /roles/{id}/users/{userId}
+func (h *Handler) RemoveUser(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.RemoveUser(r.Context(), chi.URLParam(r, "id"), chi.URLParam(r, "userId"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
@@ -0,0 +1,21 @@
+package role
+
+// CreateRoleRequest request cho tạo role
+type CreateRoleRequest struct {
+	Name          string   `json:"name" validate:"required,min=2,max=50"`
+	DisplayName   string   `json:"display_name" validate:"required,min=2,max=100"`
+	Description   string   `json:"description" validate:"omitempty,max=1000"`
+	PermissionIDs []string `json:"permission_ids" validate:"omitempty,dive,uuid"`
+}
+
+// UpdateRoleRequest request cho cập nhật role
+type UpdateRoleRequest struct {
+	Name        string  `json:"name" validate:"omitempty,min=2,max=50"`
+	DisplayName string  `json:"display_name" validate:"omitempty,min=2,max=100"`
+	Description *string `json:"description" validate:"omitempty,max=1000"`
+}
+
+// SyncPermissionsRequest request cho gán permissions của role (thay toàn bộ danh sách cũ)
+type SyncPermissionsRequest struct {
+	PermissionIDs []string `json:"permission_ids" validate:"omitempty,dive,uuid"`
+}
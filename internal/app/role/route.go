@@ -0,0 +1,24 @@
+package role
+
+import (
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes đăng ký tất cả routes cho module role
+// Prefix: /api/v1/roles
+func RegisterRoutes(r chi.Router, h *Handler, perm *jwt.PermissionChecker) {
+	r.Route("/roles", func(r chi.Router) {
+		r.With(perm.Require("roles.view")).Get("/", h.Index)                        // GET /api/v1/roles - Danh sách roles kèm permissions
+		r.With(perm.Require("roles.manage")).Post("/", h.Store)                     // POST /api/v1/roles - Tạo role
+		r.With(perm.Require("permissions.view")).Get("/permissions", h.Permissions) // GET /api/v1/roles/permissions - Danh sách permissions
+		r.With(perm.Require("roles.view")).Get("/{id}", h.Show)                     // GET /api/v1/roles/{id} - Chi tiết role
+		r.With(perm.Require("roles.manage")).Put("/{id}", h.Update)                 // PUT /api/v1/roles/{id} - Cập nhật role
+		r.With(perm.Require("roles.manage")).Delete("/{id}", h.Destroy)             // DELETE /api/v1/roles/{id} - Xóa role (không được gán cho user nào)
+
+		r.With(perm.Require("permissions.manage")).Put("/{id}/permissions", h.SyncPermissions)  // PUT /api/v1/roles/{id}/permissions - Gán lại permissions của role
+		r.With(perm.Require("permissions.manage")).Put("/{id}/users/{userId}", h.AssignUser)    // PUT /api/v1/roles/{id}/users/{userId} - Gán role cho user
+		r.With(perm.Require("permissions.manage")).Delete("/{id}/users/{userId}", h.RemoveUser) // DELETE /api/v1/roles/{id}/users/{userId} - Gỡ role khỏi user
+	})
+}
@@ -0,0 +1,319 @@
+package role
+
+import (
+	"context"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+// Service xử lý business logic cho role và permission
+type Service struct {
+	roleRepo       repository.RoleRepository
+	permissionRepo repository.PermissionRepository
+	userRepo       repository.UserRepository
+	permissions    *jwt.PermissionChecker
+}
+
+// NewService tạo role service mới
+func NewService(
+	roleRepo repository.RoleRepository,
+	permissionRepo repository.PermissionRepository,
+	userRepo repository.UserRepository,
+	permissions *jwt.PermissionChecker,
+) *Service {
+	return &Service{
+		roleRepo:       roleRepo,
+		permissionRepo: permissionRepo,
+		userRepo:       userRepo,
+		permissions:    permissions,
+	}
+}
+
+// GetAll lấy tất cả roles kèm permissions
+func (s *Service) GetAll(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	roles, err := s.roleRepo.FindAllWithPermissions(ctx)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, roles)
+}
+
+// GetByID lấy role theo ID kèm permissions
+func (s *Service) GetByID(ctx context.Context, id string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	roleID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	role, err := s.roleRepo.FindByIDWithPermissions(ctx, roleID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeRoleNotFound)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, role)
+}
+
+// Create tạo role mới, có thể kèm danh sách permissions
+func (s *Service) Create(ctx context.Context, input CreateRoleRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	if _, err := s.roleRepo.FindByName(ctx, input.Name); err == nil {
+		return response.ConflictResponse(lang, response.CodeRoleAlreadyExists)
+	}
+
+	permissionIDs, errResp := s.resolvePermissionIDs(ctx, input.PermissionIDs, lang)
+	if errResp != nil {
+		return errResp
+	}
+
+	role := model.Role{
+		Name:        input.Name,
+		DisplayName: input.DisplayName,
+		Description: input.Description,
+	}
+	if err := s.roleRepo.Create(ctx, &role); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	if len(permissionIDs) > 0 {
+		if err := s.roleRepo.SyncPermissions(ctx, role.ID, permissionIDs); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+		}
+	}
+
+	created, err := s.roleRepo.FindByIDWithPermissions(ctx, role.ID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeCreated, created)
+}
+
+// Update cập nhật thông tin role
+func (s *Service) Update(ctx context.Context, id string, input UpdateRoleRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	roleID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	role, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeRoleNotFound)
+	}
+
+	if input.Name != "" && input.Name != role.Name {
+		if role.IsSystem() {
+			return response.ConflictResponse(lang, response.CodeRoleProtected)
+		}
+		if _, err := s.roleRepo.FindByName(ctx, input.Name); err == nil {
+			return response.ConflictResponse(lang, response.CodeRoleAlreadyExists)
+		}
+		role.Name = input.Name
+	}
+	if input.DisplayName != "" {
+		role.DisplayName = input.DisplayName
+	}
+	if input.Description != nil {
+		role.Description = *input.Description
+	}
+
+	if err := s.roleRepo.Update(ctx, roleID, role); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	updated, err := s.roleRepo.FindByIDWithPermissions(ctx, roleID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeUpdated, updated)
+}
+
+// Delete xóa role, không cho xóa role hệ thống hoặc role đang được gán cho user
+func (s *Service) Delete(ctx context.Context, id string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	roleID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	role, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeRoleNotFound)
+	}
+	if role.IsSystem() {
+		return response.ConflictResponse(lang, response.CodeRoleProtected)
+	}
+
+	count, err := s.roleRepo.CountUsers(ctx, roleID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	if count > 0 {
+		return response.ConflictResponse(lang, response.CodeRoleInUse)
+	}
+
+	if err := s.roleRepo.Delete(ctx, roleID); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeDeleted, nil)
+}
+
+// GetPermissions lấy tất cả permissions
+func (s *Service) GetPermissions(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	permissions, err := s.permissionRepo.FindAllOrdered(ctx)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, permissions)
+}
+
+// SyncPermissions thay toàn bộ permissions của role và invalidate permission cache của các user có role
+func (s *Service) SyncPermissions(ctx context.Context, id string, input SyncPermissionsRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	roleID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	if _, err := s.roleRepo.FindByID(ctx, roleID); err != nil {
+		return response.NotFoundResponse(lang, response.CodeRoleNotFound)
+	}
+
+	permissionIDs, errResp := s.resolvePermissionIDs(ctx, input.PermissionIDs, lang)
+	if errResp != nil {
+		return errResp
+	}
+
+	if err := s.roleRepo.SyncPermissions(ctx, roleID, permissionIDs); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	userIDs, err := s.roleRepo.FindUserIDs(ctx, roleID)
+	if err != nil {
+		logger.Errorf("Failed to find users of role %s for permission invalidation: %v", roleID, err)
+	}
+	for _, userID := range userIDs {
+		s.invalidatePermissions(ctx, userID)
+	}
+
+	role, err := s.roleRepo.FindByIDWithPermissions(ctx, roleID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeUpdated, role)
+}
+
+// AssignUser gán role cho user (thay role hiện tại)
+func (s *Service) AssignUser(ctx context.Context, id, userIDParam string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	roleID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+	userID, err := uuid.Parse(userIDParam)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	if _, err := s.roleRepo.FindByID(ctx, roleID); err != nil {
+		return response.NotFoundResponse(lang, response.CodeRoleNotFound)
+	}
+	if _, err := s.userRepo.FindByID(ctx, userID); err != nil {
+		return response.NotFoundResponse(lang, response.CodeUserNotFound)
+	}
+
+	if err := s.userRepo.UpdateWhere(ctx, "id = ?", map[string]interface{}{"role_id": roleID}, userID); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	s.invalidatePermissions(ctx, userID)
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeUpdated, user)
+}
+
+// RemoveUser gỡ role khỏi user nếu user đang có role này
+func (s *Service) RemoveUser(ctx context.Context, id, userIDParam string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	roleID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+	userID, err := uuid.Parse(userIDParam)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeUserNotFound)
+	}
+	if user.RoleID == nil || *user.RoleID != roleID {
+		return response.BadRequestResponse(lang, response.CodeUserNotInRole, nil)
+	}
+
+	if err := s.userRepo.UpdateWhere(ctx, "id = ?", map[string]interface{}{"role_id": nil}, userID); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	s.invalidatePermissions(ctx, userID)
+
+	return response.SuccessResponse(lang, response.CodeDeleted, nil)
+}
+
+// resolvePermissionIDs parse và kiểm tra tất cả permission IDs đều tồn tại
+func (s *Service) resolvePermissionIDs(ctx context.Context, ids []string, lang string) ([]uuid.UUID, *response.Response) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[uuid.UUID]bool, len(ids))
+	permissionIDs := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		permissionID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, response.BadRequestResponse(lang, response.CodeInvalidPermissions, nil)
+		}
+		if !seen[permissionID] {
+			seen[permissionID] = true
+			permissionIDs = append(permissionIDs, permissionID)
+		}
+	}
+
+	permissions, err := s.permissionRepo.FindByIDs(ctx, permissionIDs)
+	if err != nil {
+		return nil, response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	if len(permissions) != len(permissionIDs) {
+		return nil, response.BadRequestResponse(lang, response.CodeInvalidPermissions, nil)
+	}
+
+	return permissionIDs, nil
+}
+
+// invalidatePermissions xóa permission cache của user để middleware đọc lại permissions mới
+func (s *Service) invalidatePermissions(ctx context.Context, userID uuid.UUID) {
+	if err := s.permissions.Invalidate(ctx, userID.String()); err != nil {
+		logger.Errorf("Failed to invalidate permissions of user %s: %v", userID, err)
+	}
+}
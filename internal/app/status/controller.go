@@ -0,0 +1,67 @@
+package status
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler chứa service của status page
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Show - GET /status
+func (h *Handler) Show(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.GetStatus(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Incidents - GET /status/incidents
+func (h *Handler) Incidents(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.ListIncidents(r.Context())
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// StoreIncident - POST /status/incidents
+func (h *Handler) StoreIncident(w http.ResponseWriter, r *http.Request) {
+	var input CreateIncidentRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.CreateIncident(r.Context(), input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// UpdateIncident - PUT /status/incidents/{id}
+func (h *Handler) UpdateIncident(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	var input UpdateIncidentRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.UpdateIncident(r.Context(), id, input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// DestroyIncident - DELETE /status/incidents/{id}
+func (h *Handler) DestroyIncident(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.DeleteIncident(r.Context(), chi.URLParam(r, "id"))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
@@ -0,0 +1,22 @@
+package status
+
+import "time"
+
+// CreateIncidentRequest request cho ghi nhận sự cố
+type CreateIncidentRequest struct {
+	Title      string     `json:"title" validate:"required,min=3,max=255"`
+	Message    string     `json:"message" validate:"omitempty,max=5000"`
+	Impact     string     `json:"impact" validate:"required,oneof=minor major critical"`
+	Status     string     `json:"status" validate:"omitempty,oneof=investigating identified monitoring resolved"` // Mặc định investigating
+	Components []string   `json:"components" validate:"omitempty,dive,oneof=api database cache push storage"`     // Rỗng = toàn hệ thống
+	StartedAt  *time.Time `json:"started_at"`                                                                     // Mặc định thời điểm tạo
+}
+
+// UpdateIncidentRequest request cho cập nhật sự cố (chỉ cập nhật field được gửi)
+type UpdateIncidentRequest struct {
+	Title      *string  `json:"title" validate:"omitempty,min=3,max=255"`
+	Message    *string  `json:"message" validate:"omitempty,max=5000"`
+	Impact     *string  `json:"impact" validate:"omitempty,oneof=minor major critical"`
+	Status     *string  `json:"status" validate:"omitempty,oneof=investigating identified monitoring resolved"`
+	Components []string `json:"components" validate:"omitempty,dive,oneof=api database cache push storage"`
+}
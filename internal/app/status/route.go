@@ -0,0 +1,20 @@
+package status
+
+import (
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes đăng ký routes quản lý sự cố (admin)
+// Prefix: /api/v1/status
+func RegisterRoutes(r chi.Router, h *Handler, perm *jwt.PermissionChecker) {
+	r.Route("/status/incidents", func(r chi.Router) {
+		r.Use(perm.Require("status.manage"))
+
+		r.Get("/", h.Incidents)              // GET /api/v1/status/incidents - Sự cố trong khoảng lịch sử
+		r.Post("/", h.StoreIncident)         // POST /api/v1/status/incidents - Ghi nhận sự cố
+		r.Put("/{id}", h.UpdateIncident)     // PUT /api/v1/status/incidents/{id} - Cập nhật/kết thúc sự cố
+		r.Delete("/{id}", h.DestroyIncident) // DELETE /api/v1/status/incidents/{id} - Xóa sự cố ghi nhầm
+	})
+}
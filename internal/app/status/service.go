@@ -0,0 +1,562 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Các component hiển thị trên status page
+const (
+	ComponentAPI      = "api"
+	ComponentDatabase = "database"
+	ComponentCache    = "cache"
+	ComponentPush     = "push"
+	ComponentStorage  = "storage"
+)
+
+// StatusUnknown component/ngày chưa có health check (hoặc health check đã quá cũ)
+const StatusUnknown = "unknown"
+
+const (
+	pageCacheKey    = "status:page"
+	pageCacheTTL    = 30 * time.Second
+	checkLockPrefix = "status:check:"
+
+	// staleChecks số chu kỳ không có health check mới thì coi trạng thái hiện tại là unknown
+	staleChecks = 3
+	// Ngưỡng đánh giá trạng thái của 1 ngày trong lịch sử
+	downUptimePercent     = 95.0
+	degradedUptimePercent = 99.9
+	degradedSampleRatio   = 0.05
+)
+
+// Checker health check của 1 component, trả error khi component không hoạt động
+type Checker struct {
+	Component string
+	Check     func(ctx context.Context) error
+}
+
+// Checkers danh sách health check theo thứ tự hiển thị (sau component api)
+type Checkers []Checker
+
+// Config cấu hình status page
+type Config struct {
+	Enabled         bool
+	CheckInterval   time.Duration
+	CheckTimeout    time.Duration
+	DegradedLatency time.Duration
+	HistoryDays     int
+}
+
+// Service tổng hợp health check và sự cố cho public status page
+type Service struct {
+	checkRepo    repository.StatusCheckRepository
+	incidentRepo repository.StatusIncidentRepository
+	cache        cache.Cache
+	checkers     Checkers
+	config       Config
+}
+
+// NewService tạo status service mới
+func NewService(
+	checkRepo repository.StatusCheckRepository,
+	incidentRepo repository.StatusIncidentRepository,
+	cacheClient cache.Cache,
+	checkers Checkers,
+	config Config,
+) *Service {
+	return &Service{
+		checkRepo:    checkRepo,
+		incidentRepo: incidentRepo,
+		cache:        cacheClient,
+		checkers:     checkers,
+		config:       config,
+	}
+}
+
+// Enabled health check định kỳ có được bật không
+func (s *Service) Enabled() bool {
+	return s.config.Enabled
+}
+
+// components danh sách component theo thứ tự hiển thị
+func (s *Service) components() []string {
+	components := []string{ComponentAPI}
+	for _, checker := range s.checkers {
+		components = append(components, checker.Component)
+	}
+	return components
+}
+
+// RunRecorder chạy health check định kỳ tới khi ctx bị hủy.
+// Mọi API instance đều chạy recorder nhưng mỗi chu kỳ chỉ 1 instance ghi kết quả (Redis lock theo chu kỳ).
+func (s *Service) RunRecorder(ctx context.Context) {
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.RecordSlot(ctx); err != nil {
+			logger.Errorf("Failed to record status checks: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RecordSlot chạy health check cho chu kỳ hiện tại nếu chưa instance nào nhận chu kỳ này.
+// Trả về true khi instance này đã ghi kết quả.
+func (s *Service) RecordSlot(ctx context.Context) (bool, error) {
+	slot := clock.FromContext(ctx).Now().Truncate(s.config.CheckInterval).Unix()
+
+	acquired, err := s.cache.Lock(ctx, fmt.Sprintf("%s%d", checkLockPrefix, slot), s.config.CheckInterval)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire status check lock: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	return true, s.RecordChecks(ctx)
+}
+
+// RecordChecks chạy song song health check của các component, lưu kết quả và xóa lịch sử cũ.
+// Component api luôn operational: có kết quả nghĩa là API đang chạy, chu kỳ thiếu kết quả được tính là downtime.
+func (s *Service) RecordChecks(ctx context.Context) error {
+	now := clock.FromContext(ctx).Now().UTC()
+
+	checks := make([]model.StatusCheck, len(s.checkers)+1)
+	checks[0] = model.StatusCheck{
+		Component: ComponentAPI,
+		Status:    model.ComponentStatusOperational,
+		CheckedAt: now,
+	}
+
+	var wg sync.WaitGroup
+	for i, checker := range s.checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checks[i+1] = s.runCheck(ctx, checker, now)
+		}()
+	}
+	wg.Wait()
+
+	if err := s.checkRepo.RecordChecks(ctx, checks); err != nil {
+		return fmt.Errorf("failed to save status checks: %w", err)
+	}
+
+	if err := s.checkRepo.PruneBefore(ctx, s.windowStart(now)); err != nil {
+		logger.Warnf("Failed to prune status checks: %v", err)
+	}
+	return nil
+}
+
+// runCheck chạy 1 health check với timeout và phân loại kết quả theo latency
+func (s *Service) runCheck(ctx context.Context, checker Checker, checkedAt time.Time) model.StatusCheck {
+	ctx, cancel := context.WithTimeout(ctx, s.config.CheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	latency := time.Since(start)
+
+	check := model.StatusCheck{
+		Component: checker.Component,
+		Status:    model.ComponentStatusOperational,
+		LatencyMs: latency.Milliseconds(),
+		CheckedAt: checkedAt,
+	}
+	switch {
+	case err != nil:
+		msg := err.Error()
+		check.Status = model.ComponentStatusDown
+		check.Error = &msg
+		logger.Warnf("Status check %s failed: %v", checker.Component, err)
+	case latency > s.config.DegradedLatency:
+		check.Status = model.ComponentStatusDegraded
+	}
+	return check
+}
+
+// windowStart đầu ngày (UTC) đầu tiên trong lịch sử hiển thị
+func (s *Service) windowStart(now time.Time) time.Time {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, -(s.config.HistoryDays - 1))
+}
+
+// Page dữ liệu của public status page
+type Page struct {
+	Status      string                 `json:"status"` // Trạng thái tệ nhất trong các component
+	UpdatedAt   time.Time              `json:"updated_at"`
+	HistoryDays int                    `json:"history_days"`
+	Components  []ComponentStatus      `json:"components"`
+	Incidents   []model.StatusIncident `json:"incidents"` // Sự cố chưa kết thúc hoặc kết thúc trong khoảng lịch sử
+}
+
+// ComponentStatus trạng thái hiện tại và lịch sử theo ngày của 1 component
+type ComponentStatus struct {
+	Name          string      `json:"name"`
+	Status        string      `json:"status"`
+	LatencyMs     *int64      `json:"latency_ms"`
+	CheckedAt     *time.Time  `json:"checked_at"`
+	UptimePercent *float64    `json:"uptime_percent"` // Trong toàn bộ khoảng lịch sử, null khi chưa có dữ liệu
+	History       []DayStatus `json:"history"`        // Cũ nhất trước, ngày cuối là hôm nay
+}
+
+// DayStatus trạng thái của component trong 1 ngày (UTC)
+type DayStatus struct {
+	Date          string      `json:"date"` // YYYY-MM-DD
+	Status        string      `json:"status"`
+	UptimePercent *float64    `json:"uptime_percent"`
+	AvgLatencyMs  *int64      `json:"avg_latency_ms"`
+	Incidents     []uuid.UUID `json:"incidents,omitempty"` // Sự cố ảnh hưởng component trong ngày
+}
+
+// GetStatus dữ liệu public status page, cache ngắn hạn vì endpoint không cần đăng nhập
+func (s *Service) GetStatus(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	page, err := cache.RememberAs(ctx, s.cache, pageCacheKey, pageCacheTTL, s.buildPage)
+	if err != nil {
+		logger.Errorf("Failed to build status page: %v", err)
+		return response.InternalServerErrorResponse(lang, response.CodeStatusUnavailable)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, page)
+}
+
+// buildPage tổng hợp health check và sự cố thành dữ liệu status page
+func (s *Service) buildPage(ctx context.Context) (*Page, error) {
+	now := clock.FromContext(ctx).Now().UTC()
+	windowStart := s.windowStart(now)
+
+	latest, err := s.checkRepo.LatestChecks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.checkRepo.DailySummaries(ctx, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	incidents, err := s.incidentRepo.FindVisible(ctx, windowStart)
+	if err != nil {
+		return nil, err
+	}
+	// Trước kết quả đầu tiên chưa triển khai status page, không tính là downtime của api
+	apiSince, err := s.checkRepo.FirstCheckedAt(ctx, ComponentAPI)
+	if err != nil {
+		return nil, err
+	}
+
+	latestByComponent := make(map[string]model.StatusCheck, len(latest))
+	for _, check := range latest {
+		latestByComponent[check.Component] = check
+	}
+	summariesByComponent := make(map[string]map[string]repository.StatusDailySummary)
+	for _, summary := range summaries {
+		if summariesByComponent[summary.Component] == nil {
+			summariesByComponent[summary.Component] = make(map[string]repository.StatusDailySummary)
+		}
+		summariesByComponent[summary.Component][summary.Day.Format(time.DateOnly)] = summary
+	}
+
+	page := &Page{
+		Status:      StatusUnknown,
+		UpdatedAt:   now,
+		HistoryDays: s.config.HistoryDays,
+		Components:  make([]ComponentStatus, 0, len(s.checkers)+1),
+		Incidents:   incidents,
+	}
+	for _, name := range s.components() {
+		component := s.buildComponent(name, now, windowStart, apiSince, latestByComponent, summariesByComponent[name], incidents)
+		page.Components = append(page.Components, component)
+		page.Status = worseStatus(page.Status, component.Status)
+	}
+	for i := range page.Incidents {
+		if page.Incidents[i].Components == nil {
+			page.Incidents[i].Components = []string{}
+		}
+	}
+
+	return page, nil
+}
+
+// buildComponent trạng thái hiện tại và lịch sử của 1 component
+func (s *Service) buildComponent(
+	name string,
+	now, windowStart, apiSince time.Time,
+	latest map[string]model.StatusCheck,
+	summaries map[string]repository.StatusDailySummary,
+	incidents []model.StatusIncident,
+) ComponentStatus {
+	component := ComponentStatus{
+		Name:    name,
+		Status:  StatusUnknown,
+		History: make([]DayStatus, 0, s.config.HistoryDays),
+	}
+
+	if check, ok := latest[name]; ok {
+		checkedAt := check.CheckedAt
+		latency := check.LatencyMs
+		component.CheckedAt = &checkedAt
+		component.LatencyMs = &latency
+		if now.Sub(checkedAt) <= staleChecks*s.config.CheckInterval {
+			component.Status = check.Status
+		}
+	}
+
+	var totalUp, totalExpected float64
+	for day := windowStart; !day.After(now); day = day.AddDate(0, 0, 1) {
+		date := day.Format(time.DateOnly)
+		dayStatus := DayStatus{
+			Date:      date,
+			Status:    StatusUnknown,
+			Incidents: incidentsOn(incidents, name, day, now),
+		}
+
+		if summary, ok := summaries[date]; ok && summary.Total > 0 {
+			up, expected := s.dayUptime(name, summary, day, now, apiSince)
+			uptime := roundPercent(up / expected * 100)
+			avgLatency := int64(math.Round(summary.AvgLatencyMs))
+			dayStatus.UptimePercent = &uptime
+			dayStatus.AvgLatencyMs = &avgLatency
+			dayStatus.Status = dayStatusFor(uptime, float64(summary.Degraded)/float64(summary.Total))
+
+			totalUp += up
+			totalExpected += expected
+		}
+
+		component.History = append(component.History, dayStatus)
+	}
+
+	if totalExpected > 0 {
+		uptime := roundPercent(totalUp / totalExpected * 100)
+		component.UptimePercent = &uptime
+	}
+
+	return component
+}
+
+// dayUptime số lần check thành công và số lần check kỳ vọng trong ngày.
+// Component api chỉ có kết quả khi API chạy nên số lần kỳ vọng tính theo chu kỳ health check;
+// các component khác tính trên số lần đã check.
+func (s *Service) dayUptime(name string, summary repository.StatusDailySummary, day, now, apiSince time.Time) (float64, float64) {
+	up := float64(summary.Operational + summary.Degraded)
+	if name != ComponentAPI {
+		return up, float64(summary.Total)
+	}
+
+	from := day
+	if apiSince.After(from) {
+		from = apiSince
+	}
+	to := day.Add(24 * time.Hour)
+	if now.Before(to) {
+		to = now
+	}
+
+	expected := math.Ceil(to.Sub(from).Seconds() / s.config.CheckInterval.Seconds())
+	if expected < float64(summary.Total) {
+		expected = float64(summary.Total)
+	}
+	return up, expected
+}
+
+// dayStatusFor trạng thái của 1 ngày theo uptime và tỷ lệ check chậm
+func dayStatusFor(uptime, degradedRatio float64) string {
+	switch {
+	case uptime < downUptimePercent:
+		return model.ComponentStatusDown
+	case uptime < degradedUptimePercent || degradedRatio > degradedSampleRatio:
+		return model.ComponentStatusDegraded
+	default:
+		return model.ComponentStatusOperational
+	}
+}
+
+// incidentsOn sự cố ảnh hưởng component (không chỉ định component = toàn hệ thống) trong ngày
+func incidentsOn(incidents []model.StatusIncident, component string, day, now time.Time) []uuid.UUID {
+	dayEnd := day.Add(24 * time.Hour)
+
+	var ids []uuid.UUID
+	for _, incident := range incidents {
+		if len(incident.Components) > 0 && !slices.Contains(incident.Components, component) {
+			continue
+		}
+		end := now
+		if incident.ResolvedAt != nil {
+			end = *incident.ResolvedAt
+		}
+		if incident.StartedAt.Before(dayEnd) && !end.Before(day) {
+			ids = append(ids, incident.ID)
+		}
+	}
+	return ids
+}
+
+// worseStatus trạng thái tệ hơn trong 2 trạng thái, unknown chỉ được chọn khi không có trạng thái nào khác
+func worseStatus(a, b string) string {
+	rank := map[string]int{
+		StatusUnknown:                    0,
+		model.ComponentStatusOperational: 1,
+		model.ComponentStatusDegraded:    2,
+		model.ComponentStatusDown:        3,
+	}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
+
+// roundPercent làm tròn 3 chữ số thập phân
+func roundPercent(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
+
+// invalidatePage xóa cache status page sau khi sự cố thay đổi
+func (s *Service) invalidatePage(ctx context.Context) {
+	if err := s.cache.Del(ctx, pageCacheKey); err != nil {
+		logger.Warnf("Failed to invalidate status page cache: %v", err)
+	}
+}
+
+// ListIncidents danh sách sự cố chưa kết thúc hoặc kết thúc trong khoảng lịch sử
+func (s *Service) ListIncidents(ctx context.Context) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	incidents, err := s.incidentRepo.FindVisible(ctx, s.windowStart(clock.FromContext(ctx).Now()))
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, incidents)
+}
+
+// CreateIncident ghi nhận sự cố mới
+func (s *Service) CreateIncident(ctx context.Context, input CreateIncidentRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	now := clock.FromContext(ctx).Now().UTC()
+
+	incident := model.StatusIncident{
+		Title:      input.Title,
+		Message:    input.Message,
+		Impact:     input.Impact,
+		Status:     input.Status,
+		Components: input.Components,
+		StartedAt:  now,
+	}
+	if incident.Status == "" {
+		incident.Status = model.IncidentStatusInvestigating
+	}
+	if incident.Components == nil {
+		incident.Components = []string{}
+	}
+	if input.StartedAt != nil {
+		incident.StartedAt = input.StartedAt.UTC()
+	}
+	if incident.Status == model.IncidentStatusResolved {
+		incident.ResolvedAt = &now
+	}
+
+	if err := s.incidentRepo.Create(ctx, &incident); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	s.invalidatePage(ctx)
+
+	return response.SuccessResponse(lang, response.CodeCreated, incident)
+}
+
+// UpdateIncident cập nhật sự cố, chuyển sang resolved thì ghi nhận thời điểm kết thúc
+func (s *Service) UpdateIncident(ctx context.Context, id string, input UpdateIncidentRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	incident, err := s.incidentRepo.FindByID(ctx, incidentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.NotFoundResponse(lang, response.CodeIncidentNotFound)
+	}
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+
+	if input.Title != nil {
+		incident.Title = *input.Title
+	}
+	if input.Message != nil {
+		incident.Message = *input.Message
+	}
+	if input.Impact != nil {
+		incident.Impact = *input.Impact
+	}
+	if input.Components != nil {
+		incident.Components = input.Components
+	}
+	reopened := false
+	if input.Status != nil {
+		incident.Status = *input.Status
+		switch {
+		case incident.Status == model.IncidentStatusResolved && !incident.IsResolved():
+			now := clock.FromContext(ctx).Now().UTC()
+			incident.ResolvedAt = &now
+		case incident.Status != model.IncidentStatusResolved && incident.IsResolved():
+			incident.ResolvedAt = nil
+			reopened = true
+		}
+	}
+
+	if err := s.incidentRepo.Update(ctx, incidentID, incident); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	// Updates bỏ qua field nil nên phải xóa resolved_at riêng khi mở lại sự cố
+	if reopened {
+		if err := s.incidentRepo.UpdateWhere(ctx, "id = ?", map[string]interface{}{"resolved_at": nil}, incidentID); err != nil {
+			return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+		}
+	}
+	s.invalidatePage(ctx)
+
+	return response.SuccessResponse(lang, response.CodeUpdated, incident)
+}
+
+// DeleteIncident xóa sự cố ghi nhận nhầm
+func (s *Service) DeleteIncident(ctx context.Context, id string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	incidentID, err := uuid.Parse(id)
+	if err != nil {
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+	}
+
+	if _, err := s.incidentRepo.FindByID(ctx, incidentID); err != nil {
+		return response.NotFoundResponse(lang, response.CodeIncidentNotFound)
+	}
+
+	if err := s.incidentRepo.Delete(ctx, incidentID); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	s.invalidatePage(ctx)
+
+	return response.SuccessResponse(lang, response.CodeDeleted, nil)
+}
@@ -0,0 +1,59 @@
+package sync
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPullLimit = 200
+	maxPullLimit     = 1000
+)
+
+// Handler chứa service của sync
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Pull - GET /sync?since_cursor=...&limit=200
+func (h *Handler) Pull(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := jwt.GetUserIDFromContext(r.Context())
+	if userID == "" {
+		response.Unauthorized(w, lang, response.CodeUnauthorized)
+		return
+	}
+
+	userUUID, err := uuid.Parse(userID)
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, nil)
+		return
+	}
+
+	limit := defaultPullLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 {
+			response.BadRequest(w, lang, response.CodeBadRequest, nil)
+			return
+		}
+	}
+	if limit > maxPullLimit {
+		limit = maxPullLimit
+	}
+
+	resp := h.service.Pull(r.Context(), userUUID, r.URL.Query().Get("since_cursor"), limit)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
@@ -0,0 +1,57 @@
+package sync
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor cursor client gửi lên không giải mã được
+var ErrInvalidCursor = errors.New("invalid sync cursor")
+
+// Cursor vị trí đồng bộ do server cấp, client lưu nguyên chuỗi đã mã hóa và gửi lại ở lần sync sau.
+//
+// Mỗi lần sync đọc các thay đổi trong cửa sổ (Since, Until]; Until cố định trong suốt các trang
+// để phân trang ổn định. Khi đọc hết, cursor mới chỉ còn Since = Until cũ.
+type Cursor struct {
+	Since   time.Time `json:"s"`           // Mốc đã đồng bộ xong
+	Until   time.Time `json:"u,omitempty"` // Snapshot của lần sync đang phân trang (zero: bắt đầu lần sync mới)
+	Stage   int       `json:"e,omitempty"` // Index entity đang đọc trong repository.SyncEntities
+	After   time.Time `json:"t,omitempty"` // (changed_at, id) của bản ghi cuối đã trả về trong entity hiện tại
+	AfterID uuid.UUID `json:"i,omitempty"`
+}
+
+// InProgress cursor đang ở giữa một lần sync nhiều trang
+func (c Cursor) InProgress() bool {
+	return !c.Until.IsZero()
+}
+
+// Encode mã hóa cursor thành chuỗi opaque (base64url JSON)
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor giải mã cursor, chuỗi rỗng là lần sync đầu tiên (đồng bộ toàn bộ)
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	if s == "" {
+		return c, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	if c.Stage < 0 || (c.InProgress() && c.Until.Before(c.Since)) {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,9 @@
+package sync
+
+import "github.com/go-chi/chi/v5"
+
+// RegisterRoutes đăng ký routes cho delta sync
+// Prefix: /api/v1/sync
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/sync", h.Pull) // GET /api/v1/sync?since_cursor=...&limit=200 - Thay đổi kể từ cursor (offline-first)
+}
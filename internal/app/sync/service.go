@@ -0,0 +1,99 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+)
+
+// settleDelay lùi mốc Until so với hiện tại để không bỏ sót bản ghi của transaction commit chậm
+// (updated_at được gán trước khi commit)
+const settleDelay = 2 * time.Second
+
+// Service xử lý delta sync cho client offline-first
+type Service struct {
+	syncRepo repository.SyncRepository
+}
+
+// NewService tạo sync service mới
+func NewService(syncRepo repository.SyncRepository) *Service {
+	return &Service{syncRepo: syncRepo}
+}
+
+// PullResponse một trang thay đổi
+type PullResponse struct {
+	Changes    []repository.SyncChange `json:"changes"`
+	NextCursor string                  `json:"next_cursor"`
+	HasMore    bool                    `json:"has_more"` // true: gọi tiếp ngay với next_cursor
+}
+
+// Pull lấy tối đa limit thay đổi (profile, bạn bè, conversations, messages) kể từ cursor.
+// Các entity được trả lần lượt theo repository.SyncEntities, trong mỗi entity theo (changed_at, id).
+func (s *Service) Pull(ctx context.Context, userID uuid.UUID, sinceCursor string, limit int) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	cursor, err := DecodeCursor(sinceCursor)
+	if err != nil || cursor.Stage > len(repository.SyncEntities) {
+		return response.BadRequestResponse(lang, response.CodeSyncCursorInvalid, nil)
+	}
+
+	// Bắt đầu lần sync mới: chốt snapshot Until
+	if !cursor.InProgress() {
+		cursor = Cursor{
+			Since: cursor.Since,
+			Until: clock.FromContext(ctx).Now().Add(-settleDelay),
+			After: cursor.Since,
+		}
+		if cursor.Until.Before(cursor.Since) {
+			cursor.Until = cursor.Since
+		}
+	}
+
+	changes := make([]repository.SyncChange, 0, limit)
+	hasMore := false
+	for cursor.Stage < len(repository.SyncEntities) {
+		remaining := limit - len(changes)
+		entity := repository.SyncEntities[cursor.Stage]
+
+		batch, err := s.syncRepo.FindChanges(ctx, entity, userID, repository.SyncKey{
+			ChangedAt: cursor.After,
+			ID:        cursor.AfterID,
+		}, cursor.Until, remaining)
+		if err != nil {
+			logger.Errorf("Failed to pull %s changes for user %s: %v", entity, userID, err)
+			return response.InternalServerErrorResponse(lang, response.CodeSyncFailed)
+		}
+		changes = append(changes, batch...)
+
+		// Đủ trang: có thể entity này vẫn còn, trang sau đọc tiếp từ bản ghi cuối
+		if len(batch) == remaining {
+			last := batch[len(batch)-1]
+			cursor.After = last.ChangedAt
+			cursor.AfterID = last.ID
+			hasMore = true
+			break
+		}
+
+		cursor.Stage++
+		cursor.After = cursor.Since
+		cursor.AfterID = uuid.Nil
+	}
+
+	// Đọc hết cửa sổ: lần sync sau bắt đầu từ Until
+	if !hasMore {
+		cursor = Cursor{Since: cursor.Until}
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, PullResponse{
+		Changes:    changes,
+		NextCursor: cursor.Encode(),
+		HasMore:    hasMore,
+	})
+}
@@ -0,0 +1,54 @@
+package upload
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler chứa service của presigned upload và dung lượng storage
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// Enabled presigned upload có được đăng ký không
+func (h *Handler) Enabled() bool {
+	return h.service.Enabled()
+}
+
+// Store - POST /uploads/presigned
+func (h *Handler) Store(w http.ResponseWriter, r *http.Request) {
+	var input CreatePresignedUploadRequest
+	if !validator.ValidateAndRespond(w, r, &input) {
+		return
+	}
+
+	resp := h.service.Create(r.Context(), jwt.GetUserIDFromContext(r.Context()), input)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Confirm - POST /uploads/presigned/{id}/confirm
+func (h *Handler) Confirm(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp := h.service.Confirm(r.Context(), jwt.GetUserIDFromContext(r.Context()), id)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// Usage - GET /uploads/usage
+func (h *Handler) Usage(w http.ResponseWriter, r *http.Request) {
+	resp := h.service.Usage(r.Context(), jwt.GetUserIDFromContext(r.Context()))
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
@@ -0,0 +1,86 @@
+package upload
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"github.com/google/uuid"
+)
+
+// Downloads kiểm tra quyền tải file qua /storages (storage.AuthorizeFunc):
+//   - avatar: public
+//   - file đính kèm tin nhắn: thành viên của conversation
+//   - file khác (presigned upload, file chưa gắn vào model): chỉ owner ghi trong bảng storage_files
+//     (hoặc metadata owner của S3), không xác định được owner thì từ chối
+type Downloads struct {
+	storageManager *storage.StorageManager
+	references     repository.FileReferenceRepository
+}
+
+// NewDownloads tạo download authorizer mới
+func NewDownloads(storageManager *storage.StorageManager, references repository.FileReferenceRepository) *Downloads {
+	return &Downloads{
+		storageManager: storageManager,
+		references:     references,
+	}
+}
+
+// Authorize implement storage.AuthorizeFunc, user lấy từ JWT (jwt.Manager.OptionalMiddlewareWithBlacklist)
+func (d *Downloads) Authorize(r *http.Request, key string) (storage.FileAccess, error) {
+	ctx := r.Context()
+
+	// Model lưu key hoặc URL đầy đủ của file
+	values := []string{key}
+	if url, err := d.storageManager.GetFileURL(ctx, key, false, 0); err == nil && url != key {
+		values = append(values, url)
+	}
+
+	avatar, err := d.references.IsAvatar(ctx, values)
+	if err != nil {
+		return storage.FileAccessPrivate, err
+	}
+	if avatar {
+		return storage.FileAccessPublic, nil
+	}
+
+	userID, err := uuid.Parse(jwt.GetUserIDFromContext(ctx))
+	if err != nil {
+		return storage.FileAccessPrivate, storage.ErrDownloadUnauthorized
+	}
+
+	attached, allowed, err := d.references.AttachmentAccess(ctx, values, userID)
+	if err != nil {
+		return storage.FileAccessPrivate, err
+	}
+	if attached {
+		if !allowed {
+			return storage.FileAccessPrivate, storage.ErrDownloadForbidden
+		}
+		return storage.FileAccessPrivate, nil
+	}
+
+	owner, err := d.storageManager.FileOwner(ctx, key)
+	if err != nil {
+		return storage.FileAccessPrivate, err
+	}
+	if owner == "" {
+		// File upload trước khi có bảng storage_files: S3 còn metadata owner, local thì không có
+		info, err := d.storageManager.GetFileInfo(ctx, key)
+		if err != nil {
+			if exists, existsErr := d.storageManager.FileExists(ctx, key); existsErr == nil && !exists {
+				return storage.FileAccessPrivate, fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+			}
+			return storage.FileAccessPrivate, err
+		}
+		owner = info.Metadata["owner"]
+	}
+	if owner == "" || owner != userID.String() {
+		return storage.FileAccessPrivate, storage.ErrDownloadForbidden
+	}
+	return storage.FileAccessPrivate, nil
+}
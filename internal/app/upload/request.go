@@ -0,0 +1,10 @@
+package upload
+
+// CreatePresignedUploadRequest request cho tạo presigned upload
+type CreatePresignedUploadRequest struct {
+	Filename    string `json:"filename" validate:"required,max=255"`
+	ContentType string `json:"content_type" validate:"required,max=100"`
+	Size        int64  `json:"size" validate:"required,min=1"`
+	Category    string `json:"category" validate:"omitempty,oneof=image document video audio archive"`
+	Method      string `json:"method" validate:"omitempty,oneof=PUT POST"` // PUT (mặc định) hoặc POST (form upload từ browser)
+}
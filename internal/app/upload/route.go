@@ -0,0 +1,17 @@
+package upload
+
+import "github.com/go-chi/chi/v5"
+
+// RegisterRoutes đăng ký route dung lượng storage và upload trực tiếp lên storage (chỉ khi h.Enabled())
+// Prefix: /api/v1/uploads
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/uploads/usage", h.Usage) // GET /api/v1/uploads/usage - Dung lượng đã dùng và quota của user
+
+	if !h.Enabled() {
+		return
+	}
+	r.Route("/uploads/presigned", func(r chi.Router) {
+		r.Post("/", h.Store)               // POST /api/v1/uploads/presigned - Ký URL/form để client upload thẳng lên S3
+		r.Post("/{id}/confirm", h.Confirm) // POST /api/v1/uploads/presigned/{id}/confirm - Kiểm tra object đã upload và hoàn tất
+	})
+}
@@ -0,0 +1,194 @@
+package upload
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// confirmGrace thời gian giữ presigned upload sau khi URL hết hạn, để client upload xong sát giờ vẫn xác nhận được
+const confirmGrace = time.Hour
+
+// Config cấu hình presigned upload
+type Config struct {
+	Enabled    bool
+	Expiration time.Duration // Thời gian hiệu lực của URL/form đã ký
+}
+
+// Service upload trực tiếp lên storage (S3) bằng presigned URL: API server chỉ ký request và kiểm tra object sau khi upload
+type Service struct {
+	storageManager *storage.StorageManager
+	cache          cache.Cache
+	config         Config
+}
+
+// NewService tạo presigned upload service mới
+func NewService(storageManager *storage.StorageManager, cacheClient cache.Cache, config Config) *Service {
+	return &Service{
+		storageManager: storageManager,
+		cache:          cacheClient,
+		config:         config,
+	}
+}
+
+// Enabled presigned upload có bật không
+func (s *Service) Enabled() bool {
+	return s.config.Enabled
+}
+
+// PresignedUploadResponse thông tin client dùng để upload thẳng lên storage
+type PresignedUploadResponse struct {
+	ID        string            `json:"id"`     // Dùng cho POST /uploads/presigned/{id}/confirm
+	Method    string            `json:"method"` // PUT hoặc POST
+	URL       string            `json:"url"`
+	Headers   map[string]string `json:"headers,omitempty"` // PUT: header phải gửi kèm
+	Fields    map[string]string `json:"fields,omitempty"`  // POST: form fields gửi trước field "file"
+	Path      string            `json:"path"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
+
+// pendingUpload presigned upload chờ client xác nhận
+type pendingUpload struct {
+	Owner  string                   `json:"owner"`
+	Upload *storage.PresignedUpload `json:"upload"`
+}
+
+func pendingKey(id string) string {
+	return "storage:presigned:" + id
+}
+
+// Create kiểm tra file khai báo và ký request upload trực tiếp
+func (s *Service) Create(ctx context.Context, owner string, input CreatePresignedUploadRequest) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	method := input.Method
+	if method == "" {
+		method = interfaces.PresignMethodPut
+	}
+	upload, err := s.storageManager.CreatePresignedUpload(storage.WithOwner(ctx, owner), &storage.PresignedUploadOptions{
+		Filename:    input.Filename,
+		ContentType: input.ContentType,
+		Size:        input.Size,
+		Category:    input.Category,
+		Path:        "uploads",
+		Method:      method,
+		ExpiresIn:   s.config.Expiration,
+		Metadata:    map[string]string{"owner": owner},
+	})
+	switch {
+	case errors.Is(err, storage.ErrFileRejected):
+		return response.ValidationErrorResponse(lang, response.CodeValidationFailed, err.Error())
+	case errors.Is(err, storage.ErrQuotaExceeded):
+		return response.ErrorResponse(lang, response.CodeStorageQuotaExceeded, nil)
+	case errors.Is(err, storage.ErrPresignNotSupported):
+		return response.BadRequestResponse(lang, response.CodePresignedUploadUnsupported, nil)
+	case err != nil:
+		logger.Errorf("Failed to create presigned upload: %v", err)
+		return response.InternalServerErrorResponse(lang, response.CodeFileUploadFailed)
+	}
+
+	id := strings.ReplaceAll(uuid.NewString(), "-", "")
+	data, err := json.Marshal(pendingUpload{Owner: owner, Upload: upload})
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
+	}
+	if err := s.cache.Set(ctx, pendingKey(id), string(data), time.Until(upload.ExpiresAt)+confirmGrace); err != nil {
+		logger.Errorf("Failed to save presigned upload: %v", err)
+		return response.InternalServerErrorResponse(lang, response.CodeCacheError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeCreated, PresignedUploadResponse{
+		ID:        id,
+		Method:    upload.Request.Method,
+		URL:       upload.Request.URL,
+		Headers:   upload.Request.Headers,
+		Fields:    upload.Request.Fields,
+		Path:      upload.Path,
+		ExpiresAt: upload.ExpiresAt,
+	})
+}
+
+// Confirm kiểm tra object client đã upload (tồn tại, đúng kích thước, đúng loại file) và hoàn tất upload.
+// Object không hợp lệ bị xóa khỏi storage.
+func (s *Service) Confirm(ctx context.Context, owner, id string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	raw, err := s.cache.Get(ctx, pendingKey(id))
+	if errors.Is(err, cache.ErrCacheMiss) || errors.Is(err, redis.Nil) {
+		return response.NotFoundResponse(lang, response.CodeUploadNotFound)
+	}
+	if err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeCacheError)
+	}
+
+	var pending pendingUpload
+	if err := json.Unmarshal([]byte(raw), &pending); err != nil || pending.Upload == nil {
+		return response.NotFoundResponse(lang, response.CodeUploadNotFound)
+	}
+	// Upload của user khác: trả như không tồn tại
+	if pending.Owner != owner {
+		return response.NotFoundResponse(lang, response.CodeUploadNotFound)
+	}
+
+	result, err := s.storageManager.ConfirmPresignedUpload(storage.WithOwner(ctx, owner), pending.Upload)
+	switch {
+	case errors.Is(err, storage.ErrUploadNotFound):
+		return response.ConflictResponse(lang, response.CodeUploadIncomplete)
+	case errors.Is(err, storage.ErrUploadMismatch):
+		_ = s.cache.Del(ctx, pendingKey(id))
+		return response.ErrorResponse(lang, response.CodeUploadVerificationFailed, err.Error())
+	case errors.Is(err, storage.ErrFileInfected):
+		_ = s.cache.Del(ctx, pendingKey(id))
+		return response.ErrorResponse(lang, response.CodeFileInfected, nil)
+	case errors.Is(err, storage.ErrScanFailed):
+		// Object còn trên storage: client xác nhận lại khi scanner hoạt động
+		return response.ErrorResponse(lang, response.CodeFileScanFailed, nil)
+	case errors.Is(err, storage.ErrQuotaExceeded):
+		_ = s.cache.Del(ctx, pendingKey(id))
+		return response.ErrorResponse(lang, response.CodeStorageQuotaExceeded, nil)
+	case err != nil:
+		logger.Errorf("Failed to confirm presigned upload %s: %v", id, err)
+		return response.InternalServerErrorResponse(lang, response.CodeFileUploadFailed)
+	}
+
+	_ = s.cache.Del(ctx, pendingKey(id))
+	return response.SuccessResponse(lang, response.CodeSuccess, result)
+}
+
+// UsageResponse dung lượng storage của user
+type UsageResponse struct {
+	UsedBytes      int64 `json:"used_bytes"`
+	FileCount      int64 `json:"file_count"`
+	QuotaBytes     int64 `json:"quota_bytes"`     // 0: không giới hạn
+	RemainingBytes int64 `json:"remaining_bytes"` // -1: không giới hạn
+}
+
+// Usage dung lượng đã dùng và quota của user
+func (s *Service) Usage(ctx context.Context, owner string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	usage, err := s.storageManager.Usage(ctx, owner)
+	if err != nil {
+		logger.Errorf("Failed to read storage usage of %s: %v", owner, err)
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, UsageResponse{
+		UsedBytes:      usage.UsedBytes,
+		FileCount:      usage.FileCount,
+		QuotaBytes:     usage.QuotaBytes,
+		RemainingBytes: usage.RemainingBytes(),
+	})
+}
@@ -6,12 +6,12 @@ import (
 	"net/http"
 	"time"
 
-	model "api-core/internal/models"
-	"api-core/pkg/excel"
-	"api-core/pkg/i18n"
-	"api-core/pkg/response"
-	"api-core/pkg/utils"
-	"api-core/pkg/validator"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/excel"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/pkg/validator"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -58,6 +58,15 @@ func (h *Handler) Show(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, statusCode, *resp)
 }
 
+// RemoveEmailSuppression - DELETE /users/{id}/email-suppression
+func (h *Handler) RemoveEmailSuppression(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	resp := h.service.RemoveEmailSuppression(r.Context(), id)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
 // Store - POST /users
 func (h *Handler) Store(w http.ResponseWriter, r *http.Request) {
 	var input CreateUserRequest
@@ -76,8 +85,9 @@ func (h *Handler) Store(w http.ResponseWriter, r *http.Request) {
 
 	// Convert to model
 	u := model.User{
-		Name:  input.Name,
-		Email: input.Email,
+		Name:   input.Name,
+		Email:  input.Email,
+		Avatar: input.AvatarURL, // URL, service tải về và lưu vào storage
 	}
 
 	// Get FCM token từ request nếu có
@@ -114,7 +124,7 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 	u := model.User{
 		Name:   input.Name,
 		Email:  input.Email,
-		Avatar: input.Avatar,
+		Avatar: input.AvatarURL, // URL, service tải về và lưu vào storage
 	}
 
 	resp := h.service.Update(r.Context(), id, u, avatarFile)
@@ -2,18 +2,19 @@ package user
 
 // CreateUserRequest request cho tạo user
 type CreateUserRequest struct {
-	Name     string  `json:"name" validate:"required,min=2,max=100"`
-	Email    string  `json:"email" validate:"required,email"`
-	Password string  `json:"password" validate:"omitempty,strongpassword"`
-	RoleID   *string `json:"role_id" validate:"omitempty,uuid"`
-	FCMToken *string `json:"fcm_token" validate:"omitempty"` // Optional: FCM token để gửi notification chào mừng
+	Name      string  `json:"name" validate:"required,min=2,max=100"`
+	Email     string  `json:"email" validate:"required,email"`
+	Password  string  `json:"password" validate:"omitempty,strongpassword"`
+	RoleID    *string `json:"role_id" validate:"omitempty,uuid"`
+	AvatarURL *string `json:"avatar_url" validate:"omitempty,url"` // Optional: URL ảnh, server tải về khi không upload file avatar
+	FCMToken  *string `json:"fcm_token" validate:"omitempty"`      // Optional: FCM token để gửi notification chào mừng
 }
 
 // UpdateUserRequest request cho update user
 type UpdateUserRequest struct {
-	Name   string  `json:"name" validate:"omitempty,min=2,max=100"`
-	Email  string  `json:"email" validate:"omitempty,email"`
-	Avatar *string `json:"avatar" validate:"omitempty,url"`
+	Name      string  `json:"name" validate:"omitempty,min=2,max=100"`
+	Email     string  `json:"email" validate:"omitempty,email"`
+	AvatarURL *string `json:"avatar_url" validate:"omitempty,url"` // URL ảnh, server tải về khi không upload file avatar
 }
 
 // ListUserRequest request cho list users với pagination và sort
@@ -1,16 +1,22 @@
 package user
 
-import "github.com/go-chi/chi/v5"
+import (
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/go-chi/chi/v5"
+)
 
 // RegisterRoutes đăng ký tất cả routes cho module user
 // Prefix: /api/v1/users
-func RegisterRoutes(r chi.Router, h *Handler) {
+func RegisterRoutes(r chi.Router, h *Handler, perm *jwt.PermissionChecker) {
 	r.Route("/users", func(r chi.Router) {
-		r.Get("/", h.Index)             // GET /api/v1/users - Lấy danh sách users
-		r.Post("/", h.Store)            // POST /api/v1/users - Tạo user mới (có thể kèm avatar)
-		r.Get("/export", h.ExportUsers) // GET /api/v1/users/export - Export users to Excel/CSV
-		r.Get("/{id}", h.Show)          // GET /api/v1/users/{id} - Lấy user theo ID
-		r.Put("/{id}", h.Update)        // PUT /api/v1/users/{id} - Cập nhật user (có thể kèm avatar)
-		r.Delete("/{id}", h.Destroy)    // DELETE /api/v1/users/{id} - Xóa user
+		r.With(perm.Require("users.view")).Get("/", h.Index)             // GET /api/v1/users - Lấy danh sách users
+		r.With(perm.Require("users.create")).Post("/", h.Store)          // POST /api/v1/users - Tạo user mới (có thể kèm avatar)
+		r.With(perm.Require("users.view")).Get("/export", h.ExportUsers) // GET /api/v1/users/export - Export users to Excel/CSV
+		r.With(perm.Require("users.view")).Get("/{id}", h.Show)          // GET /api/v1/users/{id} - Lấy user theo ID
+		r.With(perm.Require("users.update")).Put("/{id}", h.Update)      // PUT /api/v1/users/{id} - Cập nhật user (có thể kèm avatar)
+		r.With(perm.Require("users.delete")).Delete("/{id}", h.Destroy)  // DELETE /api/v1/users/{id} - Xóa user
+
+		r.With(perm.Require("users.update")).Delete("/{id}/email-suppression", h.RemoveEmailSuppression) // DELETE /api/v1/users/{id}/email-suppression - Gỡ email khỏi suppression list
 	})
 }
@@ -1,80 +1,119 @@
 package user
 
 import (
-	model "api-core/internal/models"
-	repository "api-core/internal/repositories"
-	"api-core/pkg/cache"
-	"api-core/pkg/fcm"
-	"api-core/pkg/i18n"
-	"api-core/pkg/logger"
-	"api-core/pkg/response"
-	"api-core/pkg/storage"
-	"api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/internal/outbox"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/utils"
 
 	"context"
+	"errors"
 	"fmt"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // Service xử lý business logic cho user
 type Service struct {
-	repo           repository.UserRepository
-	cache          cache.Cache
-	storageManager *storage.StorageManager
-	fcmClient      *fcm.Client // Optional: nil nếu FCM chưa được cấu hình
+	repo            repository.UserRepository
+	suppressionRepo repository.EmailSuppressionRepository
+	inboxRepo       repository.NotificationRepository // Notification center, lưu mọi push gửi tới user
+	cache           cache.Cache
+	storageManager  *storage.StorageManager
+	pusher          *device.Pusher // Push tới thiết bị của user, không gửi được nếu FCM chưa được cấu hình
+	fetcher         *safehttp.Client
 }
 
 const (
-	cacheKeyAll = "users:all"
-	cacheExpiry = 5 * time.Minute
+	cacheKeyAll   = "users:all"
+	cacheTagUsers = "users" // Mọi danh sách user (users:all, phân trang...)
+	cacheExpiry   = 5 * time.Minute
 )
 
+// cacheAllOptions users:all: quá hạn vẫn trả danh sách cũ trong lúc refresh, DB lỗi/chậm thì dùng bản cũ tối đa 1 giờ,
+// key nóng nên refresh sớm ở background trước khi hết TTL
+var cacheAllOptions = cache.RememberOptions{
+	TTL:                  cacheExpiry,
+	StaleWhileRevalidate: time.Minute,
+	StaleIfError:         time.Hour,
+	EarlyRefreshBeta:     1,
+}
+
+// ErrNoDevices user chưa đăng ký thiết bị nào (POST /api/v1/devices)
+var ErrNoDevices = device.ErrNoDevices
+
+// userCacheTag tag các entry cache của một user
+func userCacheTag(id string) string {
+	return fmt.Sprintf("user:%s", id)
+}
+
 // NewService tạo user service mới
 func NewService(
 	repo repository.UserRepository,
+	suppressionRepo repository.EmailSuppressionRepository,
+	inboxRepo repository.NotificationRepository,
 	cacheClient cache.Cache,
 	storageManager *storage.StorageManager,
-	fcmClient *fcm.Client, // Optional: có thể nil
+	pusher *device.Pusher,
+	fetcher *safehttp.Client,
 ) *Service {
 	return &Service{
-		repo:           repo,
-		cache:          cacheClient,
-		storageManager: storageManager,
-		fcmClient:      fcmClient,
+		repo:            repo,
+		suppressionRepo: suppressionRepo,
+		inboxRepo:       inboxRepo,
+		cache:           cacheClient,
+		storageManager:  storageManager,
+		pusher:          pusher,
+		fetcher:         fetcher,
 	}
 }
 
-// GetAll lấy tất cả users
-func (s *Service) GetAll() ([]model.User, error) {
-	ctx := context.Background()
-
-	// Try to get from cache first
-	cached, err := s.cache.Remember(ctx, cacheKeyAll, cacheExpiry, func() (interface{}, error) {
+// GetAll lấy tất cả users (cache với stale-while-revalidate / stale-if-error)
+func (s *Service) GetAll(ctx context.Context) ([]model.User, error) {
+	return cache.RememberStale(ctx, s.cache.Tags(cacheTagUsers), cacheKeyAll, cacheAllOptions, func(ctx context.Context) ([]model.User, error) {
 		return s.repo.FindAll(ctx)
 	})
+}
 
+// GetByID lấy user theo ID
+func (s *Service) GetByID(ctx context.Context, id string) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+	userID, err := uuid.Parse(id)
 	if err != nil {
-		// If cache fails, try directly from DB
-		return s.repo.FindAll(ctx)
+		return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
 	}
 
-	// Convert cached data to []model.User
-	users, ok := cached.([]model.User)
-	if !ok {
-		// Cache data invalid, fetch from DB
-		return s.repo.FindAll(ctx)
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return response.NotFoundResponse(lang, response.CodeUserNotFound)
+	}
+
+	// Convert avatar path to full URL
+	s.convertAvatarToFullURL(user)
+
+	// Trạng thái suppression để support biết vì sao user không nhận được email
+	if suppression, err := s.suppressionRepo.FindByEmail(ctx, user.Email); err == nil {
+		user.EmailSuppression = suppression
 	}
 
-	return users, nil
+	return response.SuccessResponse(lang, response.CodeSuccess, user)
 }
 
-// GetByID lấy user theo ID
-func (s *Service) GetByID(ctx context.Context, id string) *response.Response {
+// RemoveEmailSuppression gỡ email của user khỏi suppression list để gửi email lại
+func (s *Service) RemoveEmailSuppression(ctx context.Context, id string) *response.Response {
 	lang := i18n.GetLanguageFromContext(ctx)
 	userID, err := uuid.Parse(id)
 	if err != nil {
@@ -86,10 +125,15 @@ func (s *Service) GetByID(ctx context.Context, id string) *response.Response {
 		return response.NotFoundResponse(lang, response.CodeUserNotFound)
 	}
 
-	// Convert avatar path to full URL
-	s.convertAvatarToFullURL(user)
+	if _, err := s.suppressionRepo.FindByEmail(ctx, user.Email); err != nil {
+		return response.NotFoundResponse(lang, response.CodeEmailNotSuppressed)
+	}
 
-	return response.SuccessResponse(lang, response.CodeSuccess, user)
+	if err := s.suppressionRepo.DeleteByEmail(ctx, user.Email); err != nil {
+		return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+	}
+
+	return response.SuccessResponse(lang, response.CodeDeleted, nil)
 }
 
 // Create tạo user mới (có thể nhận FCM token để gửi notification)
@@ -103,10 +147,17 @@ func (s *Service) Create(ctx context.Context, user model.User, avatarFile *multi
 
 		result, err := s.storageManager.UploadFile(ctx, avatarFile, uploadOptions)
 		if err != nil {
-			return response.InternalServerErrorResponse(lang, response.CodeFileUploadFailed)
+			return response.ErrorResponse(lang, uploadErrorCode(err), nil)
 		}
 
 		user.Avatar = &result.Path
+	} else if user.Avatar != nil && *user.Avatar != "" {
+		// Avatar từ URL: tải về và lưu vào storage như file upload
+		path, code := s.fetchAvatar(ctx, *user.Avatar)
+		if code != "" {
+			return response.ErrorResponse(lang, code, nil)
+		}
+		user.Avatar = &path
 	}
 
 	if err := s.repo.Create(ctx, &user); err != nil {
@@ -118,17 +169,17 @@ func (s *Service) Create(ctx context.Context, user model.User, avatarFile *multi
 	}
 
 	// Invalidate cache
-	s.cache.Del(ctx, cacheKeyAll)
+	s.cache.Tags(cacheTagUsers).Flush(ctx)
 
 	// Convert avatar path to full URL
 	s.convertAvatarToFullURL(&user)
 
-	// Gửi FCM notification chào mừng user mới (background, không block response)
+	// Notification chào mừng user mới: lưu inbox, push được worker gửi qua queue (không block response)
 	var token string
 	if len(fcmToken) > 0 && fcmToken[0] != "" {
 		token = fcmToken[0]
 	}
-	go s.sendWelcomeNotification(context.Background(), &user, token)
+	s.queueWelcomeNotification(context.WithoutCancel(ctx), &user, token)
 
 	return response.SuccessResponse(lang, response.CodeCreated, user)
 }
@@ -147,29 +198,48 @@ func (s *Service) Update(ctx context.Context, id string, user model.User, avatar
 		return response.NotFoundResponse(lang, response.CodeUserNotFound)
 	}
 
+	// Avatar tính vào dung lượng storage của user được cập nhật
+	ctx = storage.WithOwner(ctx, userID.String())
+
 	// Upload avatar mới nếu có
+	newAvatar := false
 	if avatarFile != nil {
 		uploadOptions := storage.GetImageUploadOptions(300, 300, 90) // 300x300, quality 90
 		uploadOptions.Path = "avatars"                               // Store in avatars folder
 
 		result, err := s.storageManager.UploadFile(ctx, avatarFile, uploadOptions)
 		if err != nil {
-			return response.InternalServerErrorResponse(lang, response.CodeFileUploadFailed)
+			return response.ErrorResponse(lang, uploadErrorCode(err), nil)
 		}
 
 		user.Avatar = &result.Path
+		newAvatar = true
+	} else if user.Avatar != nil && *user.Avatar != "" {
+		current := *currentUser
+		s.convertAvatarToFullURL(&current)
+		if current.Avatar != nil && *current.Avatar == *user.Avatar {
+			// Client gửi lại URL avatar hiện tại: giữ nguyên
+			user.Avatar = nil
+		} else {
+			path, code := s.fetchAvatar(ctx, *user.Avatar)
+			if code != "" {
+				return response.ErrorResponse(lang, code, nil)
+			}
+			user.Avatar = &path
+			newAvatar = true
+		}
 	}
 
 	if err := s.repo.Update(ctx, userID, &user); err != nil {
 		// Nếu update thất bại, xóa avatar mới đã upload
-		if avatarFile != nil && user.Avatar != nil {
+		if newAvatar {
 			s.storageManager.DeleteFile(ctx, *user.Avatar)
 		}
 		return response.InternalServerErrorResponse(lang, response.CodeInternalServerError)
 	}
 
 	// Xóa avatar cũ nếu có avatar mới
-	if avatarFile != nil && currentUser.Avatar != nil && *currentUser.Avatar != "" {
+	if newAvatar && currentUser.Avatar != nil && *currentUser.Avatar != "" {
 		if err := s.storageManager.DeleteFile(ctx, *currentUser.Avatar); err != nil {
 			// Log error nhưng không fail operation
 			fmt.Printf("Warning: Failed to delete old avatar: %v\n", err)
@@ -183,7 +253,7 @@ func (s *Service) Update(ctx context.Context, id string, user model.User, avatar
 	}
 
 	// Invalidate cache
-	s.cache.Del(ctx, cacheKeyAll, fmt.Sprintf("user:%s", id))
+	s.cache.Tags(cacheTagUsers, userCacheTag(id)).Flush(ctx)
 
 	// Convert avatar path to full URL
 	s.convertAvatarToFullURL(updated)
@@ -204,7 +274,7 @@ func (s *Service) Delete(ctx context.Context, id string) *response.Response {
 	}
 
 	// Invalidate cache
-	s.cache.Del(ctx, cacheKeyAll, fmt.Sprintf("user:%s", id))
+	s.cache.Tags(cacheTagUsers, userCacheTag(id)).Flush(ctx)
 
 	return response.SuccessResponse(lang, response.CodeDeleted, nil)
 }
@@ -237,6 +307,69 @@ func (s *Service) GetListWithPagination(ctx context.Context, page, perPage int,
 	return response.SuccessResponseWithMeta(lang, response.CodeSuccess, responseData, meta)
 }
 
+// avatarExtensions định dạng ảnh nhận khi tải avatar từ URL
+var avatarExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// fetchAvatar tải ảnh từ URL do user cung cấp qua safehttp (chặn SSRF) và upload vào storage.
+// Trả về path đã lưu, hoặc response code lỗi.
+func (s *Service) fetchAvatar(ctx context.Context, avatarURL string) (string, string) {
+	resp, err := s.fetcher.Get(ctx, avatarURL, "image/*")
+	if err != nil {
+		logger.Warnf("Failed to fetch avatar from %s: %v", avatarURL, err)
+		switch {
+		case safehttp.IsNotAllowed(err):
+			return "", response.CodeURLNotAllowed
+		case errors.Is(err, safehttp.ErrTooLarge):
+			return "", response.CodeFileTooLarge
+		default:
+			return "", response.CodeURLFetchFailed
+		}
+	}
+
+	// Nhiều CDN trả application/octet-stream: nhận diện lại từ nội dung
+	contentType := resp.ContentType
+	if _, ok := avatarExtensions[contentType]; !ok {
+		contentType, _, _ = strings.Cut(http.DetectContentType(resp.Body), ";")
+	}
+	ext, ok := avatarExtensions[contentType]
+	if !ok {
+		return "", response.CodeInvalidFileType
+	}
+
+	uploadOptions := storage.GetImageUploadOptions(300, 300, 90) // 300x300, quality 90
+	uploadOptions.Path = "avatars"                               // Store in avatars folder
+
+	result, err := s.storageManager.UploadBytes(ctx, "avatar"+ext, resp.Body, contentType, uploadOptions)
+	if err != nil {
+		logger.Warnf("Failed to store avatar from %s: %v", avatarURL, err)
+		if errors.Is(err, storage.ErrFileRejected) {
+			// Magic bytes không khớp hoặc vượt giới hạn của category image
+			return "", response.CodeInvalidFileType
+		}
+		return "", uploadErrorCode(err)
+	}
+	return result.Path, ""
+}
+
+// uploadErrorCode response code cho lỗi lưu avatar: file nhiễm malware, không quét được, vượt quota hoặc lỗi storage
+func uploadErrorCode(err error) string {
+	switch {
+	case errors.Is(err, storage.ErrFileInfected):
+		return response.CodeFileInfected
+	case errors.Is(err, storage.ErrScanFailed):
+		return response.CodeFileScanFailed
+	case errors.Is(err, storage.ErrQuotaExceeded):
+		return response.CodeStorageQuotaExceeded
+	default:
+		return response.CodeFileUploadFailed
+	}
+}
+
 // convertAvatarToFullURL converts avatar path to full URL
 func (s *Service) convertAvatarToFullURL(user *model.User) {
 	if user.Avatar != nil && *user.Avatar != "" {
@@ -255,6 +388,11 @@ func (s *Service) convertAvatarToFullURL(user *model.User) {
 
 		// Create full URL using storage URL
 		fullURL := fmt.Sprintf("%s%s/%s", strings.TrimSuffix(serverURL, "/"), storageURL, avatarPath)
+
+		// Có CDN thì trỏ thẳng tới CDN, tránh thêm một lần redirect qua /storages
+		if cdnURL := os.Getenv("STORAGE_CDN_URL"); cdnURL != "" {
+			fullURL = fmt.Sprintf("%s/%s", strings.TrimSuffix(cdnURL, "/"), avatarPath)
+		}
 		user.Avatar = &fullURL
 	}
 }
@@ -273,119 +411,129 @@ func (s *Service) SendNotification(ctx context.Context, userID uuid.UUID, title,
 	return s.SendNotificationToUser(ctx, userID, title, body, data)
 }
 
-// SendNotificationToToken gửi FCM notification đến một token cụ thể
+// SendNotificationToToken gửi FCM notification đến một token cụ thể.
+// Token không còn được đăng ký (UNREGISTERED) bị xóa khỏi device_tokens
 func (s *Service) SendNotificationToToken(ctx context.Context, token string, title, body string, data map[string]string) (string, error) {
-	if s.fcmClient == nil {
-		return "", fmt.Errorf("FCM client chưa được khởi tạo")
-	}
-
-	if token == "" {
-		return "", fmt.Errorf("FCM token không được để trống")
-	}
+	return s.pusher.SendToToken(ctx, token, title, body, data)
+}
 
-	// Tạo notification
-	notification := fcm.NewNotificationBuilder().
-		SetTitle(title).
-		SetBody(body).
-		Build()
+// SendNotificationToUser lưu thông báo vào notification center của user rồi gửi FCM notification đến mọi thiết bị
+// user đã đăng ký (device_tokens), chờ FCM trả kết quả. Trả ErrNoDevices nếu user chưa có thiết bị, lỗi nếu không
+// thiết bị nào nhận được; thông báo vẫn nằm trong inbox khi push thất bại. Không cần kết quả: dùng QueueNotificationToUser
+func (s *Service) SendNotificationToUser(ctx context.Context, userID uuid.UUID, title, body string, data map[string]string) error {
+	data = s.saveToInbox(ctx, userID, title, body, data)
 
-	// Gửi notification
-	messageID, err := s.fcmClient.SendToToken(ctx, token, notification, data)
+	success, failure, err := s.pusher.SendToUser(ctx, userID, title, body, data)
 	if err != nil {
-		return "", fmt.Errorf("failed to send FCM notification: %w", err)
+		return err
 	}
 
-	return messageID, nil
+	logger.Infof("Notification sent to user %s: %d devices, %d failed", userID, success, failure)
+	return nil
 }
 
-// SendNotificationToUser gửi FCM notification đến user (cần có FCM token trong DB)
-// Cần implement getUserFCMToken để lấy token từ database
-func (s *Service) SendNotificationToUser(ctx context.Context, userID uuid.UUID, title, body string, data map[string]string) error {
-	if s.fcmClient == nil {
-		return fmt.Errorf("FCM client chưa được khởi tạo")
-	}
-
-	// TODO: Lấy FCM token từ database
-	// Ví dụ:
-	// user, err := s.repo.FindByID(ctx, userID)
-	// if err != nil {
-	// 	return fmt.Errorf("user not found: %w", err)
-	// }
-	// if user.FCMToken == nil || *user.FCMToken == "" {
-	// 	return fmt.Errorf("user does not have FCM token")
-	// }
-	// token := *user.FCMToken
-
-	// Tạm thời return error để nhắc implement
-	return fmt.Errorf("cần implement getUserFCMToken() để lấy token từ database")
+// QueueNotificationToUser lưu thông báo vào notification center và đẩy job gửi push tới mọi thiết bị của user vào queue
+// (qua outbox, cùng transaction). Worker gửi với retry, rate limit và dead-letter; job không mất khi process restart
+func (s *Service) QueueNotificationToUser(ctx context.Context, userID uuid.UUID, title, body string, data map[string]string) error {
+	return s.queueNotification(ctx, device.Push{UserID: userID, Title: title, Body: body, Data: data})
 }
 
-// SendNotificationToTokens gửi FCM notification đến nhiều tokens (multicast)
-func (s *Service) SendNotificationToTokens(ctx context.Context, tokens []string, title, body string, data map[string]string) (int, int, error) {
-	if s.fcmClient == nil {
-		return 0, 0, fmt.Errorf("FCM client chưa được khởi tạo")
+// queueNotification lưu push.Data vào inbox và ghi job gửi push vào outbox trong một transaction.
+// FCM chưa được cấu hình thì chỉ lưu inbox
+func (s *Service) queueNotification(ctx context.Context, push device.Push) error {
+	if s.inboxRepo == nil {
+		return fmt.Errorf("notification repository chưa được khởi tạo")
 	}
 
-	if len(tokens) == 0 {
-		return 0, 0, fmt.Errorf("danh sách tokens không được để trống")
-	}
+	return s.inboxRepo.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		notification := newInboxNotification(push.UserID, push.Title, push.Body, push.Data)
+		if err := tx.Create(notification).Error; err != nil {
+			return fmt.Errorf("failed to save notification: %w", err)
+		}
+		if !s.pusher.Enabled() {
+			return nil
+		}
 
-	// Tạo notification
-	notification := fcm.NewNotificationBuilder().
-		SetTitle(title).
-		SetBody(body).
-		Build()
+		push.Data = withNotificationID(push.Data, notification.ID)
+		message, err := device.PushJob.NewMessage(push)
+		if err != nil {
+			return err
+		}
+		return outbox.Enqueue(tx, device.QueueNotifications, message)
+	})
+}
 
-	// Gửi notification
-	response, err := s.fcmClient.SendToTokens(ctx, tokens, notification, data)
-	if err != nil {
-		return 0, 0, fmt.Errorf("failed to send FCM notifications: %w", err)
+// saveToInbox lưu thông báo vào notification center, trả data kèm notification_id để app đánh dấu đã đọc khi mở push.
+// Lỗi chỉ log, push vẫn được gửi
+func (s *Service) saveToInbox(ctx context.Context, userID uuid.UUID, title, body string, data map[string]string) map[string]string {
+	if s.inboxRepo == nil {
+		return data
 	}
 
-	return response.SuccessCount, response.FailureCount, nil
+	notification := newInboxNotification(userID, title, body, data)
+	if err := s.inboxRepo.Create(ctx, notification); err != nil {
+		logger.Errorf("Failed to save notification of user %s: %v", userID, err)
+		return data
+	}
+	return withNotificationID(data, notification.ID)
 }
 
-// sendWelcomeNotification gửi notification chào mừng user mới (background)
-func (s *Service) sendWelcomeNotification(ctx context.Context, user *model.User, fcmToken string) {
-	// Nếu không có FCM client hoặc không có token, bỏ qua
-	if s.fcmClient == nil {
-		return
+// newInboxNotification thông báo trong notification center, type lấy từ data["type"]
+func newInboxNotification(userID uuid.UUID, title, body string, data map[string]string) *model.Notification {
+	notificationType := data["type"]
+	if notificationType == "" {
+		notificationType = model.NotificationTypeGeneral
+	}
+	return &model.Notification{
+		UserID: userID,
+		Type:   notificationType,
+		Title:  title,
+		Body:   body,
+		Data:   data,
 	}
+}
 
-	// Nếu không có token, không gửi notification
-	if fcmToken == "" {
-		// Không log nếu không có token (bình thường khi client chưa cung cấp token)
-		return
+// withNotificationID bản sao data kèm notification_id
+func withNotificationID(data map[string]string, id uuid.UUID) map[string]string {
+	withID := make(map[string]string, len(data)+1)
+	for key, value := range data {
+		withID[key] = value
 	}
+	withID["notification_id"] = id.String()
+	return withID
+}
 
-	// Tạo notification
-	notification := fcm.NewNotificationBuilder().
-		SetTitle("Chào mừng đến với ApiCore!").
-		SetBody(fmt.Sprintf("Xin chào %s! Tài khoản của bạn đã được tạo thành công.", user.Name)).
-		Build()
+// SendNotificationToTokens gửi FCM notification đến nhiều tokens (multicast).
+// Token bị FCM từ chối (UNREGISTERED, INVALID_ARGUMENT) bị xóa khỏi device_tokens
+func (s *Service) SendNotificationToTokens(ctx context.Context, tokens []string, title, body string, data map[string]string) (int, int, error) {
+	return s.pusher.SendToTokens(ctx, tokens, title, body, data)
+}
 
-	// Prepare data
-	data := map[string]string{
-		"type":      "user_created",
-		"user_id":   user.ID.String(),
-		"email":     user.Email,
-		"action":    "view_profile",
-		"deep_link": fmt.Sprintf("app://users/%s", user.ID),
-		"timestamp": time.Now().Format(time.RFC3339),
+// queueWelcomeNotification lưu notification chào mừng user mới vào inbox và đẩy job gửi push tới fcmToken vào queue.
+// Lỗi chỉ log, không làm fail việc tạo user
+func (s *Service) queueWelcomeNotification(ctx context.Context, user *model.User, fcmToken string) {
+	push := device.Push{
+		UserID: user.ID,
+		Title:  "Chào mừng đến với ApiCore!",
+		Body:   fmt.Sprintf("Xin chào %s! Tài khoản của bạn đã được tạo thành công.", user.Name),
+		Data: map[string]string{
+			"type":      "user_created",
+			"user_id":   user.ID.String(),
+			"email":     user.Email,
+			"action":    "view_profile",
+			"deep_link": fmt.Sprintf("app://users/%s", user.ID),
+			"timestamp": time.Now().Format(time.RFC3339),
+		},
 	}
 
-	// Gửi notification trong goroutine riêng để có context timeout riêng
-	go func() {
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		messageID, err := s.fcmClient.SendToToken(ctx, fcmToken, notification, data)
-		if err != nil {
-			// Log error nhưng không fail operation
-			logger.Errorf("Failed to send welcome notification to user %s: %v", user.ID, err)
-			return
-		}
+	// Không có token (client chưa cung cấp): chỉ lưu vào notification center
+	if fcmToken == "" {
+		s.saveToInbox(ctx, user.ID, push.Title, push.Body, push.Data)
+		return
+	}
 
-		logger.Infof("Welcome notification sent to user %s: message_id=%s", user.ID, messageID)
-	}()
+	push.Tokens = []string{fcmToken}
+	if err := s.queueNotification(ctx, push); err != nil {
+		logger.Errorf("Failed to queue welcome notification to user %s: %v", user.ID, err)
+	}
 }
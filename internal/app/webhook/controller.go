@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// maxPayloadSize giới hạn kích thước body của webhook
+const maxPayloadSize = 1 << 20
+
+// Handler chứa service của webhook
+type Handler struct {
+	service *Service
+}
+
+// NewHandler tạo handler mới
+func NewHandler(svc *Service) *Handler {
+	return &Handler{service: svc}
+}
+
+// SESEmail - POST /webhooks/email/ses?token=... (Amazon SNS)
+func (h *Handler) SESEmail(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.readEmailWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.service.HandleSES(r.Context(), body)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// SendGridEmail - POST /webhooks/email/sendgrid?token=... (SendGrid Event Webhook)
+func (h *Handler) SendGridEmail(w http.ResponseWriter, r *http.Request) {
+	body, ok := h.readEmailWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	resp := h.service.HandleSendGrid(r.Context(), body)
+	statusCode := response.GetHTTPStatusCode(resp.Code)
+	response.JSON(w, statusCode, *resp)
+}
+
+// readEmailWebhook kiểm tra token và đọc body của email webhook
+func (h *Handler) readEmailWebhook(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+
+	if !h.service.AuthorizeEmail(r.URL.Query().Get("token")) {
+		response.Unauthorized(w, lang, response.CodeWebhookUnauthorized)
+		return nil, false
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeWebhookInvalidPayload, nil)
+		return nil, false
+	}
+	return body, true
+}
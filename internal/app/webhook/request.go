@@ -0,0 +1,39 @@
+package webhook
+
+// snsMessage envelope của Amazon SNS (SES gửi bounce/complaint qua SNS topic)
+type snsMessage struct {
+	Type         string `json:"Type"`
+	MessageID    string `json:"MessageId"`
+	TopicArn     string `json:"TopicArn"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+// sesNotification nội dung SES notification (field Message của SNS)
+type sesNotification struct {
+	NotificationType string `json:"notificationType"` // SES notifications
+	EventType        string `json:"eventType"`        // SES event publishing
+	Bounce           *struct {
+		BounceType        string `json:"bounceType"`
+		BounceSubType     string `json:"bounceSubType"`
+		BouncedRecipients []struct {
+			EmailAddress   string `json:"emailAddress"`
+			DiagnosticCode string `json:"diagnosticCode"`
+		} `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+		ComplainedRecipients  []struct {
+			EmailAddress string `json:"emailAddress"`
+		} `json:"complainedRecipients"`
+	} `json:"complaint"`
+}
+
+// sendGridEvent một event trong SendGrid Event Webhook (body là JSON array)
+type sendGridEvent struct {
+	Email  string `json:"email"`
+	Event  string `json:"event"`
+	Type   string `json:"type"` // bounce hoặc blocked (soft bounce) khi event = bounce
+	Reason string `json:"reason"`
+	Status string `json:"status"`
+}
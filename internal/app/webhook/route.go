@@ -0,0 +1,13 @@
+package webhook
+
+import "github.com/go-chi/chi/v5"
+
+// RegisterRoutes đăng ký tất cả routes cho module webhook
+// Prefix: /api/v1/webhooks
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/webhooks", func(r chi.Router) {
+		// Email bounce/complaint
+		r.Post("/email/ses", h.SESEmail)           // POST /api/v1/webhooks/email/ses?token= - Amazon SES qua SNS
+		r.Post("/email/sendgrid", h.SendGridEmail) // POST /api/v1/webhooks/email/sendgrid?token= - SendGrid Event Webhook
+	})
+}
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+)
+
+var (
+	errInvalidSubscribeURL      = errors.New("invalid SNS subscribe url")
+	errSubscriptionNotConfirmed = errors.New("SNS subscription not confirmed")
+)
+
+// snsHostPattern chỉ confirm subscription với endpoint thật của SNS (tránh SSRF)
+var snsHostPattern = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// Config cấu hình webhook
+type Config struct {
+	EmailToken string // Token bắt buộc trong query ?token= của email webhook, rỗng = tắt webhook
+}
+
+// Service xử lý webhook từ các dịch vụ bên ngoài
+type Service struct {
+	suppressionRepo repository.EmailSuppressionRepository
+	config          Config
+	httpClient      *http.Client
+}
+
+// NewService tạo webhook service mới
+func NewService(suppressionRepo repository.EmailSuppressionRepository, config Config, fetcher *safehttp.Client) *Service {
+	return &Service{
+		suppressionRepo: suppressionRepo,
+		config:          config,
+		httpClient:      fetcher.HTTPClient(), // Chỉ gọi ra IP public dù SubscribeURL đã qua kiểm tra host
+	}
+}
+
+// AuthorizeEmail kiểm tra token của email webhook
+func (s *Service) AuthorizeEmail(token string) bool {
+	if s.config.EmailToken == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.EmailToken)) == 1
+}
+
+// HandleSES xử lý SNS notification chứa bounce/complaint của SES
+func (s *Service) HandleSES(ctx context.Context, body []byte) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	var msg snsMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return response.BadRequestResponse(lang, response.CodeWebhookInvalidPayload, nil)
+	}
+
+	switch msg.Type {
+	case "SubscriptionConfirmation":
+		if err := s.confirmSNSSubscription(ctx, msg.SubscribeURL); err != nil {
+			logger.Errorf("Failed to confirm SNS subscription for %s: %v", msg.TopicArn, err)
+			return response.BadRequestResponse(lang, response.CodeWebhookInvalidPayload, nil)
+		}
+		return response.SuccessResponse(lang, response.CodeSuccess, nil)
+	case "Notification":
+	default:
+		return response.SuccessResponse(lang, response.CodeSuccess, nil)
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(msg.Message), &notification); err != nil {
+		return response.BadRequestResponse(lang, response.CodeWebhookInvalidPayload, nil)
+	}
+
+	var suppressions []model.EmailSuppression
+	// Chỉ hard bounce (Permanent) mới suppress, soft bounce để provider tự retry
+	if notification.Bounce != nil && notification.Bounce.BounceType == "Permanent" {
+		for _, r := range notification.Bounce.BouncedRecipients {
+			suppressions = append(suppressions, newSuppression(r.EmailAddress, model.SuppressionReasonHardBounce, model.SuppressionSourceSES,
+				notification.Bounce.BounceSubType+": "+r.DiagnosticCode))
+		}
+	}
+	if notification.Complaint != nil {
+		for _, r := range notification.Complaint.ComplainedRecipients {
+			suppressions = append(suppressions, newSuppression(r.EmailAddress, model.SuppressionReasonComplaint, model.SuppressionSourceSES,
+				notification.Complaint.ComplaintFeedbackType))
+		}
+	}
+
+	return s.suppress(ctx, lang, suppressions)
+}
+
+// HandleSendGrid xử lý SendGrid Event Webhook
+func (s *Service) HandleSendGrid(ctx context.Context, body []byte) *response.Response {
+	lang := i18n.GetLanguageFromContext(ctx)
+
+	var events []sendGridEvent
+	if err := json.Unmarshal(body, &events); err != nil {
+		return response.BadRequestResponse(lang, response.CodeWebhookInvalidPayload, nil)
+	}
+
+	var suppressions []model.EmailSuppression
+	for _, e := range events {
+		switch {
+		case e.Event == "bounce" && e.Type != "blocked":
+			suppressions = append(suppressions, newSuppression(e.Email, model.SuppressionReasonHardBounce, model.SuppressionSourceSendGrid,
+				strings.TrimSpace(e.Status+" "+e.Reason)))
+		case e.Event == "spamreport":
+			suppressions = append(suppressions, newSuppression(e.Email, model.SuppressionReasonComplaint, model.SuppressionSourceSendGrid, ""))
+		}
+	}
+
+	return s.suppress(ctx, lang, suppressions)
+}
+
+// suppress lưu danh sách suppression
+func (s *Service) suppress(ctx context.Context, lang string, suppressions []model.EmailSuppression) *response.Response {
+	suppressed := 0
+	for i := range suppressions {
+		if suppressions[i].Email == "" {
+			continue
+		}
+		if err := s.suppressionRepo.Suppress(ctx, &suppressions[i]); err != nil {
+			// Trả lỗi để provider gửi lại webhook
+			return response.InternalServerErrorResponse(lang, response.CodeDatabaseError)
+		}
+		suppressed++
+		logger.Infof("Email suppressed: %s (%s from %s)", suppressions[i].Email, suppressions[i].Reason, suppressions[i].Source)
+	}
+
+	return response.SuccessResponse(lang, response.CodeSuccess, map[string]int{"suppressed": suppressed})
+}
+
+// confirmSNSSubscription gọi SubscribeURL để xác nhận SNS subscription
+func (s *Service) confirmSNSSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !snsHostPattern.MatchString(u.Host) {
+		return errInvalidSubscribeURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return errSubscriptionNotConfirmed
+	}
+	return nil
+}
+
+// newSuppression tạo suppression record
+func newSuppression(email, reason, source, details string) model.EmailSuppression {
+	suppression := model.EmailSuppression{
+		Email:  email,
+		Reason: reason,
+		Source: source,
+	}
+	if details = strings.TrimSpace(details); details != "" {
+		suppression.Details = &details
+	}
+	return suppression
+}
@@ -1,7 +1,7 @@
 package config
 
 import (
-	"api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/pkg/utils"
 )
 
 // ActionEventConfig cấu hình cho action events
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// AppRole vai trò của process, cho phép cùng 1 binary chạy API pod, queue worker và cron scheduler riêng biệt
+type AppRole string
+
+const (
+	AppRoleAPI       AppRole = "api"       // HTTP API + WebSocket, stateless, scale theo traffic
+	AppRoleWorker    AppRole = "worker"    // Queue consumers
+	AppRoleScheduler AppRole = "scheduler" // Cron jobs
+	AppRoleAll       AppRole = "all"       // Chạy tất cả trong 1 process (development, deploy nhỏ)
+)
+
+// LoadAppRole đọc APP_ROLE (api|worker|scheduler|all), mặc định all
+func LoadAppRole() (AppRole, error) {
+	role := AppRole(strings.ToLower(strings.TrimSpace(utils.GetEnv("APP_ROLE", string(AppRoleAll)))))
+	switch role {
+	case AppRoleAPI, AppRoleWorker, AppRoleScheduler, AppRoleAll:
+		return role, nil
+	default:
+		return "", fmt.Errorf("invalid APP_ROLE %q (expected api, worker, scheduler or all)", role)
+	}
+}
+
+// RunsAPI process phục vụ HTTP API
+func (r AppRole) RunsAPI() bool {
+	return r == AppRoleAPI || r == AppRoleAll
+}
+
+// RunsWorker process chạy queue consumers
+func (r AppRole) RunsWorker() bool {
+	return r == AppRoleWorker || r == AppRoleAll
+}
+
+// RunsScheduler process chạy cron scheduler
+func (r AppRole) RunsScheduler() bool {
+	return r == AppRoleScheduler || r == AppRoleAll
+}
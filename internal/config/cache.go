@@ -0,0 +1,116 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// CacheConfig cấu hình cache, dùng chung cho mọi kết nối Redis (cache, queue, cron lock, rate limit)
+type CacheConfig struct {
+	Mode     string // standalone | sentinel | cluster
+	Host     string
+	Port     string
+	Addrs    []string // Sentinel: địa chỉ sentinel; cluster: các node seed
+	Username string
+	Password string
+	DB       int
+	PoolSize int
+
+	MasterName       string // Sentinel
+	SentinelPassword string // Sentinel
+
+	TLS                   bool
+	TLSServerName         string
+	TLSInsecureSkipVerify bool
+
+	// Namespace tiền tố mọi key cache của app, rỗng: không thêm namespace
+	Namespace string
+	// SchemaVersion version của dữ liệu cache, đổi khi deploy thay đổi shape của payload
+	SchemaVersion string
+	// VersionRefresh chu kỳ đọc lại generation sau BumpVersion từ instance khác
+	VersionRefresh time.Duration
+}
+
+// GetDefaultCacheConfig trả về config mặc định từ env
+func GetDefaultCacheConfig() CacheConfig {
+	return CacheConfig{
+		Mode:                  utils.GetEnv("REDIS_MODE", redisclient.ModeStandalone),
+		Host:                  utils.GetEnv("REDIS_HOST", "localhost"),
+		Port:                  utils.GetEnv("REDIS_PORT", "6379"),
+		Addrs:                 utils.GetEnvStringSlice("REDIS_ADDRS", nil),
+		Username:              utils.GetEnv("REDIS_USERNAME", ""),
+		Password:              utils.GetEnv("REDIS_PASSWORD", ""),
+		DB:                    utils.GetEnvInt("REDIS_DB", 0),
+		PoolSize:              utils.GetEnvInt("REDIS_POOL_SIZE", 10),
+		MasterName:            utils.GetEnv("REDIS_SENTINEL_MASTER", ""),
+		SentinelPassword:      utils.GetEnv("REDIS_SENTINEL_PASSWORD", ""),
+		TLS:                   utils.GetEnvBool("REDIS_TLS", false),
+		TLSServerName:         utils.GetEnv("REDIS_TLS_SERVER_NAME", ""),
+		TLSInsecureSkipVerify: utils.GetEnvBool("REDIS_TLS_INSECURE_SKIP_VERIFY", false),
+		Namespace:             utils.GetEnv("CACHE_NAMESPACE", "api-core"),
+		SchemaVersion:         utils.GetEnv("CACHE_SCHEMA_VERSION", "1"),
+		VersionRefresh:        time.Duration(utils.GetEnvInt("CACHE_VERSION_REFRESH_SECONDS", 5)) * time.Second,
+	}
+}
+
+// Validate kiểm tra cache config
+func (c CacheConfig) Validate() error {
+	if err := c.RedisConfig().Validate(); err != nil {
+		return fmt.Errorf("invalid REDIS_* config: %w", err)
+	}
+	return nil
+}
+
+// RedisConfig cấu hình kết nối cho pkg/redisclient
+func (c CacheConfig) RedisConfig() redisclient.Config {
+	var addrs []string
+	for _, addr := range c.Addrs {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	cfg := redisclient.Config{
+		Mode:             c.Mode,
+		Host:             c.Host,
+		Port:             c.Port,
+		Addrs:            addrs,
+		MasterName:       c.MasterName,
+		SentinelPassword: c.SentinelPassword,
+		Username:         c.Username,
+		Password:         c.Password,
+		DB:               c.DB,
+		PoolSize:         c.PoolSize,
+	}
+	if c.TLS {
+		cfg.TLS = &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			ServerName:         c.TLSServerName,
+			InsecureSkipVerify: c.TLSInsecureSkipVerify,
+		}
+	}
+	return cfg
+}
+
+// NewRedisClient tạo Redis client theo config (standalone, sentinel, cluster), chưa kết nối
+func NewRedisClient(cfg CacheConfig) (redis.UniversalClient, error) {
+	return redisclient.New(cfg.RedisConfig())
+}
+
+// ConnectCache kết nối đến Redis, key được thêm namespace và schema version (cache.NamespacedCache)
+func ConnectCache(cfg CacheConfig) (cache.Cache, error) {
+	cacheClient, err := cache.NewRedisCache(cfg.RedisConfig())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to cache: %w", err)
+	}
+
+	return cache.NewNamespacedCache(cacheClient, cfg.Namespace, cfg.SchemaVersion, cfg.VersionRefresh), nil
+}
@@ -0,0 +1,15 @@
+package config
+
+import "github.com/anhnq996/go-api-core/pkg/utils"
+
+// ChatConfig cấu hình chat module
+type ChatConfig struct {
+	EventSourcing bool // Ghi thay đổi qua chat_events và bật sync API cho client offline-first
+}
+
+// LoadChatConfig load chat config từ environment variables
+func LoadChatConfig() *ChatConfig {
+	return &ChatConfig{
+		EventSourcing: utils.GetEnvBool("CHAT_EVENT_SOURCING", false),
+	}
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/middleware"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// CORSConfig holds CORS configuration
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int
+}
+
+// LoadCORSConfig loads CORS configuration from environment variables
+func LoadCORSConfig() *CORSConfig {
+	return &CORSConfig{
+		AllowedOrigins:   utils.GetEnvStringSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
+		AllowedMethods:   utils.GetEnvStringSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"}),
+		AllowedHeaders:   utils.GetEnvStringSlice("CORS_ALLOWED_HEADERS", []string{"*"}),
+		ExposedHeaders:   utils.GetEnvStringSlice("CORS_EXPOSED_HEADERS", []string{"Link", "Location", "Upload-Offset", "Upload-Length", "Upload-Metadata", "Upload-Expires", "Tus-Resumable", "Tus-Version", "Tus-Extension", "Tus-Max-Size"}),
+		AllowCredentials: utils.GetEnvBool("CORS_ALLOW_CREDENTIALS", false),
+		MaxAge:           utils.GetEnvInt("CORS_MAX_AGE", 300),
+	}
+}
+
+// ToCORSOptions chuyển sang middleware.CORSOptions
+func (c *CORSConfig) ToCORSOptions() middleware.CORSOptions {
+	return middleware.CORSOptions{
+		AllowedOrigins:   trimAll(c.AllowedOrigins),
+		AllowedMethods:   trimAll(c.AllowedMethods),
+		AllowedHeaders:   trimAll(c.AllowedHeaders),
+		ExposedHeaders:   trimAll(c.ExposedHeaders),
+		AllowCredentials: c.AllowCredentials,
+		MaxAge:           c.MaxAge,
+	}
+}
+
+// LoadResponseHeaders header gắn vào mọi response (X-API-Version, X-Powered-By), bỏ qua giá trị rỗng
+func LoadResponseHeaders() map[string]string {
+	headers := map[string]string{}
+	if apiVersion := utils.GetEnv("API_VERSION", ""); apiVersion != "" {
+		headers["X-API-Version"] = apiVersion
+	}
+	if poweredBy := utils.GetEnv("API_POWERED_BY", ""); poweredBy != "" {
+		headers["X-Powered-By"] = poweredBy
+	}
+	return headers
+}
+
+// trimAll bỏ khoảng trắng và phần tử rỗng
+func trimAll(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			out = append(out, value)
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// DatabaseConfig cấu hình database
+type DatabaseConfig struct {
+	Host     string
+	Port     string
+	User     string
+	Password string
+	DBName   string
+	SSLMode  string
+
+	// UUIDVersion phiên bản UUID mặc định cho primary key (4 hoặc 7)
+	UUIDVersion int
+	// UUIDVersions override theo bảng, vd: DB_UUID_VERSIONS=messages:7,users:4
+	UUIDVersions map[string]int
+
+	// SlowQueryThreshold query chạy lâu hơn được log ở level warn (module gorm)
+	SlowQueryThreshold time.Duration
+	// LogQueryParams ghi SQL kèm giá trị tham số, mặc định chỉ giữ placeholder
+	LogQueryParams bool
+}
+
+// GetDefaultDatabaseConfig trả về config mặc định từ env
+func GetDefaultDatabaseConfig() DatabaseConfig {
+	return DatabaseConfig{
+		Host:     utils.GetEnv("DB_HOST", "localhost"),
+		Port:     utils.GetEnv("DB_PORT", "5432"),
+		User:     utils.GetEnv("DB_USER", "postgres"),
+		Password: utils.GetEnv("DB_PASSWORD", "postgres"),
+		DBName:   utils.GetEnv("DB_NAME", "apicore"),
+		SSLMode:  utils.GetEnv("DB_SSLMODE", "disable"),
+
+		UUIDVersion:  utils.GetEnvInt("DB_UUID_VERSION", 4),
+		UUIDVersions: parseUUIDVersions(utils.GetEnvStringSlice("DB_UUID_VERSIONS", nil)),
+
+		SlowQueryThreshold: time.Duration(utils.GetEnvInt("DB_SLOW_QUERY_MS", 200)) * time.Millisecond,
+		LogQueryParams:     utils.GetEnvBool("DB_LOG_QUERY_PARAMS", false),
+	}
+}
+
+// parseUUIDVersions parse danh sách "table:version", bỏ qua phần tử không hợp lệ
+func parseUUIDVersions(items []string) map[string]int {
+	versions := make(map[string]int)
+	for _, item := range items {
+		table, version, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			continue
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(version))
+		if err != nil || (v != 4 && v != 7) {
+			continue
+		}
+		versions[strings.TrimSpace(table)] = v
+	}
+	return versions
+}
+
+// ConnectDatabase kết nối đến database
+func ConnectDatabase(cfg DatabaseConfig) (*gorm.DB, error) {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		cfg.Host, cfg.User, cfg.Password, cfg.DBName, cfg.Port, cfg.SSLMode,
+	)
+
+	// Level theo LOG_LEVEL_GORM
+	gormLogger := logger.NewGormLogger(cfg.SlowQueryThreshold)
+	gormLogger.LogParams = cfg.LogQueryParams
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormLogger,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Get underlying SQL DB for connection pooling
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying database: %w", err)
+	}
+
+	// Set connection pool settings
+	sqlDB.SetMaxIdleConns(10)
+	sqlDB.SetMaxOpenConns(100)
+
+	return db, nil
+}
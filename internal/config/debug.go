@@ -0,0 +1,37 @@
+package config
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// DebugConfig cấu hình debug endpoints (/debug/pprof, /debug/vars) trên API port
+type DebugConfig struct {
+	Enabled    bool     // Mount /debug, yêu cầu access token + permission debug.profile
+	AllowedIPs []string // IP/CIDR được gọi /debug, rỗng: không giới hạn IP
+	TrustProxy bool     // Lấy IP client từ X-Forwarded-For/X-Real-IP (chỉ bật khi chạy sau reverse proxy tin cậy)
+}
+
+// LoadDebugConfig load debug config từ environment variables
+func LoadDebugConfig() *DebugConfig {
+	return &DebugConfig{
+		Enabled:    utils.GetEnvBool("DEBUG_ROUTES_ENABLED", false),
+		AllowedIPs: utils.GetEnvStringSlice("DEBUG_ALLOWED_IPS", nil),
+		TrustProxy: utils.GetEnvBool("DEBUG_TRUST_PROXY", false),
+	}
+}
+
+// Validate kiểm tra debug config
+func (c *DebugConfig) Validate() error {
+	if _, err := c.AllowedNets(); err != nil {
+		return fmt.Errorf("invalid DEBUG_ALLOWED_IPS: %w", err)
+	}
+	return nil
+}
+
+// AllowedNets danh sách IP/CIDR đã parse của DEBUG_ALLOWED_IPS
+func (c *DebugConfig) AllowedNets() ([]*net.IPNet, error) {
+	return utils.ParseIPNets(c.AllowedIPs)
+}
@@ -0,0 +1,19 @@
+package config
+
+import (
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// E2EConfig cấu hình test data API cho E2E suite bên ngoài (Playwright, mobile)
+type E2EConfig struct {
+	Enabled bool   // Chỉ bật khi APP_ENV=test, không có cách nào bật ở môi trường khác
+	Token   string // Nếu set, request phải gửi header X-E2E-Token khớp
+}
+
+// LoadE2EConfig load E2E config từ environment variables
+func LoadE2EConfig() *E2EConfig {
+	return &E2EConfig{
+		Enabled: utils.GetEnv("APP_ENV", "production") == "test" && utils.GetEnvBool("E2E_API_ENABLED", true),
+		Token:   utils.GetEnv("E2E_API_TOKEN", ""),
+	}
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/mailer"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// EmailConfig cấu hình cho email service
+type EmailConfig struct {
+	Driver       string // smtp (default) hoặc ses
+	SMTPHost     string // SMTP server host
+	SMTPPort     int    // SMTP server port
+	SMTPUsername string // SMTP username
+	SMTPPassword string // SMTP password
+	FromEmail    string // From email address
+	FromName     string // From name
+	UseTLS       bool   // Use TLS encryption
+	WebhookToken string // Token bảo vệ bounce/complaint webhook (SES/SendGrid), rỗng = tắt webhook
+
+	// AWS SES (MAIL_DRIVER=ses), access key rỗng: credentials theo chuỗi mặc định của AWS (env, IAM role)
+	SESRegion           string
+	SESAccessKeyID      string
+	SESSecretAccessKey  string
+	SESConfigurationSet string
+	SESEndpoint         string
+
+	TemplatesDir  string // Thư mục email template (<name>.html|.mjml|.txt, <locale>/<name>.*), rỗng = không load
+	DefaultLocale string // Ngôn ngữ mặc định của email
+	MJMLCommand   string // Đường dẫn mjml CLI để biên dịch template .mjml, rỗng = không hỗ trợ .mjml
+}
+
+// LoadEmailConfig load email config từ environment variables
+func LoadEmailConfig() *EmailConfig {
+	return &EmailConfig{
+		Driver:       utils.GetEnv("MAIL_DRIVER", "smtp"),
+		SMTPHost:     utils.GetEnv("SMTP_HOST", "localhost"),
+		SMTPPort:     utils.GetEnvInt("SMTP_PORT", 1025),
+		SMTPUsername: utils.GetEnv("SMTP_USERNAME", ""),
+		SMTPPassword: utils.GetEnv("SMTP_PASSWORD", ""),
+		FromEmail:    utils.GetEnv("EMAIL_FROM", "noreply@apicore.com"),
+		FromName:     utils.GetEnv("EMAIL_FROM_NAME", "ApiCore"),
+		UseTLS:       utils.GetEnvBool("SMTP_USE_TLS", false),
+		WebhookToken: utils.GetEnv("EMAIL_WEBHOOK_TOKEN", ""),
+
+		SESRegion:           utils.GetEnv("SES_REGION", utils.GetEnv("AWS_REGION", "")),
+		SESAccessKeyID:      utils.GetEnv("SES_ACCESS_KEY_ID", ""),
+		SESSecretAccessKey:  utils.GetEnv("SES_SECRET_ACCESS_KEY", ""),
+		SESConfigurationSet: utils.GetEnv("SES_CONFIGURATION_SET", ""),
+		SESEndpoint:         utils.GetEnv("SES_ENDPOINT", ""),
+
+		TemplatesDir:  utils.GetEnv("EMAIL_TEMPLATES_DIR", "internal/templates/emails"),
+		DefaultLocale: utils.GetEnv("EMAIL_DEFAULT_LOCALE", "en"),
+		MJMLCommand:   utils.GetEnv("MJML_COMMAND", ""),
+	}
+}
+
+// ValidateEmailConfig kiểm tra email config có hợp lệ không
+func (c *EmailConfig) Validate() error {
+	switch c.Driver {
+	case "", "smtp":
+		if c.SMTPHost == "" {
+			return fmt.Errorf("SMTP host is required")
+		}
+
+		if c.SMTPPort <= 0 || c.SMTPPort > 65535 {
+			return fmt.Errorf("SMTP port must be between 1 and 65535")
+		}
+	case "ses":
+		if c.SESRegion == "" {
+			return fmt.Errorf("SES region is required")
+		}
+	default:
+		return fmt.Errorf("invalid MAIL_DRIVER %q (smtp, ses)", c.Driver)
+	}
+
+	if c.FromEmail == "" {
+		return fmt.Errorf("from email is required")
+	}
+
+	return nil
+}
+
+// ToEmailConfig convert sang email.EmailConfig
+func (c *EmailConfig) ToEmailConfig() email.EmailConfig {
+	return email.EmailConfig{
+		SMTPHost:     c.SMTPHost,
+		SMTPPort:     c.SMTPPort,
+		SMTPUsername: c.SMTPUsername,
+		SMTPPassword: c.SMTPPassword,
+		FromEmail:    c.FromEmail,
+		FromName:     c.FromName,
+		UseTLS:       c.UseTLS,
+	}
+}
+
+// ToSMTPConfig convert sang mailer.SMTPConfig
+func (c *EmailConfig) ToSMTPConfig() mailer.SMTPConfig {
+	return mailer.SMTPConfig{
+		Host:     c.SMTPHost,
+		Port:     c.SMTPPort,
+		Username: c.SMTPUsername,
+		Password: c.SMTPPassword,
+		UseTLS:   c.UseTLS,
+	}
+}
+
+// ToSESConfig convert sang mailer.SESConfig
+func (c *EmailConfig) ToSESConfig() mailer.SESConfig {
+	return mailer.SESConfig{
+		Region:           c.SESRegion,
+		AccessKeyID:      c.SESAccessKeyID,
+		SecretAccessKey:  c.SESSecretAccessKey,
+		ConfigurationSet: c.SESConfigurationSet,
+		Endpoint:         c.SESEndpoint,
+	}
+}
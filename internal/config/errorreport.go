@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// ErrorReportConfig cấu hình gửi lỗi (panic, logger.Error, job queue/cron lỗi) tới Sentry
+type ErrorReportConfig struct {
+	SentryDSN   string // Rỗng: tắt
+	Environment string
+	Release     string
+	SampleRate  float64 // Tỉ lệ event được gửi (0-1]
+}
+
+// LoadErrorReportConfig load error report config từ environment variables
+func LoadErrorReportConfig() *ErrorReportConfig {
+	sampleRate, err := strconv.ParseFloat(utils.GetEnv("SENTRY_SAMPLE_RATE", "1"), 64)
+	if err != nil {
+		sampleRate = -1 // Validate báo lỗi
+	}
+
+	return &ErrorReportConfig{
+		SentryDSN:   utils.GetEnv("SENTRY_DSN", ""),
+		Environment: utils.GetEnv("SENTRY_ENVIRONMENT", utils.GetEnv("APP_ENV", "production")),
+		Release:     utils.GetEnv("SENTRY_RELEASE", utils.GetEnv("API_VERSION", "")),
+		SampleRate:  sampleRate,
+	}
+}
+
+// Enabled đã cấu hình SENTRY_DSN
+func (c *ErrorReportConfig) Enabled() bool {
+	return c.SentryDSN != ""
+}
+
+// Validate kiểm tra error report config
+func (c *ErrorReportConfig) Validate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if c.SampleRate <= 0 || c.SampleRate > 1 {
+		return fmt.Errorf("SENTRY_SAMPLE_RATE must be a number in (0, 1]")
+	}
+	return nil
+}
+
+// ToSentryConfig chuyển sang errorreport.SentryConfig
+func (c *ErrorReportConfig) ToSentryConfig() errorreport.SentryConfig {
+	return errorreport.SentryConfig{
+		DSN:         c.SentryDSN,
+		Environment: c.Environment,
+		Release:     c.Release,
+		SampleRate:  c.SampleRate,
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/loadshed"
+	"github.com/anhnq996/go-api-core/pkg/middleware"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// LoadShedConfig holds load shedding configuration
+type LoadShedConfig struct {
+	Enabled         bool
+	MaxP99Latency   time.Duration
+	MaxGoroutines   int
+	MaxCPUPercent   float64
+	SampleSize      int
+	SampleMaxAge    time.Duration
+	EvalInterval    time.Duration
+	RecoverFactor   float64
+	RetryAfter      time.Duration
+	LowRoutes       []string
+	CriticalRoutes  []string
+	DefaultPriority string
+}
+
+// LoadLoadShedConfig loads load shedding configuration from environment variables
+func LoadLoadShedConfig() *LoadShedConfig {
+	return &LoadShedConfig{
+		Enabled:         utils.GetEnvBool("LOAD_SHED_ENABLED", true),
+		MaxP99Latency:   time.Duration(utils.GetEnvInt("LOAD_SHED_MAX_P99_MS", 2000)) * time.Millisecond,
+		MaxGoroutines:   utils.GetEnvInt("LOAD_SHED_MAX_GOROUTINES", 10000),
+		MaxCPUPercent:   float64(utils.GetEnvInt("LOAD_SHED_MAX_CPU_PERCENT", 90)),
+		SampleSize:      utils.GetEnvInt("LOAD_SHED_SAMPLE_SIZE", 1000),
+		SampleMaxAge:    time.Duration(utils.GetEnvInt("LOAD_SHED_SAMPLE_MAX_AGE_SECONDS", 30)) * time.Second,
+		EvalInterval:    time.Duration(utils.GetEnvInt("LOAD_SHED_EVAL_INTERVAL_MS", 1000)) * time.Millisecond,
+		RecoverFactor:   float64(utils.GetEnvInt("LOAD_SHED_RECOVER_PERCENT", 80)) / 100,
+		RetryAfter:      time.Duration(utils.GetEnvInt("LOAD_SHED_RETRY_AFTER_SECONDS", 5)) * time.Second,
+		LowRoutes:       utils.GetEnvStringSlice("LOAD_SHED_LOW_PRIORITY_ROUTES", []string{}),
+		CriticalRoutes:  utils.GetEnvStringSlice("LOAD_SHED_CRITICAL_ROUTES", []string{"/api/v1/auth", "/ws", "/api/v1/health", "/healthz", "/readyz", "/status"}),
+		DefaultPriority: utils.GetEnv("LOAD_SHED_DEFAULT_PRIORITY", "normal"),
+	}
+}
+
+// ToShedderConfig chuyển sang loadshed.Config
+func (c *LoadShedConfig) ToShedderConfig() loadshed.Config {
+	classes := make(map[string]loadshed.Priority)
+	for _, route := range c.LowRoutes {
+		if route = strings.TrimSpace(route); route != "" {
+			classes[route] = loadshed.PriorityLow
+		}
+	}
+	for _, route := range c.CriticalRoutes {
+		if route = strings.TrimSpace(route); route != "" {
+			classes[route] = loadshed.PriorityCritical
+		}
+	}
+
+	return loadshed.Config{
+		MaxP99Latency:   c.MaxP99Latency,
+		MaxGoroutines:   c.MaxGoroutines,
+		MaxCPUPercent:   c.MaxCPUPercent,
+		SampleSize:      c.SampleSize,
+		SampleMaxAge:    c.SampleMaxAge,
+		EvalInterval:    c.EvalInterval,
+		RecoverFactor:   c.RecoverFactor,
+		RetryAfter:      c.RetryAfter,
+		RouteClasses:    classes,
+		DefaultPriority: loadshed.ParsePriority(c.DefaultPriority),
+	}
+}
+
+// ToLoadShedOptions chuyển sang middleware.LoadShedOptions
+func (c *LoadShedConfig) ToLoadShedOptions() middleware.LoadShedOptions {
+	return middleware.LoadShedOptions{
+		Enabled: c.Enabled,
+		Shedder: c.ToShedderConfig(),
+	}
+}
+
+// CreateLoadShedder creates a load shedder instance
+func CreateLoadShedder(config *LoadShedConfig) *loadshed.Shedder {
+	return loadshed.NewShedder(config.ToShedderConfig())
+}
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// LoggerConfig cấu hình cho logger
+type LoggerConfig struct {
+	Level         string            // debug, info, warn, error
+	ModuleLevels  map[string]string // LOG_LEVEL_<MODULE>, rỗng: theo Level
+	Output        string            // console, file, loki, cloudwatch, syslog (có thể kết hợp)
+	LogPath       string            // đường dẫn thư mục chứa logs
+	LokiURL       string            // Loki server URL
+	EnableCaller  bool              // hiển thị file:line
+	PrettyPrint   bool              // format đẹp cho console
+	DailyRotation bool              // bật daily rotation
+	MaxSizeMB     int               // rotate khi file vượt kích thước (MB), 0: không giới hạn
+	MaxBackups    int               // số file backup giữ lại, 0: giữ tất cả
+	MaxAgeDays    int               // xóa backup cũ hơn số ngày, 0: không xóa
+	Compress      bool              // nén gzip file backup
+	SampleRoutes  string            // tỷ lệ ghi request log thành công theo route, vd "GET /healthz=0.01,GET /api/v1/users=0.05"
+
+	CloudWatch logger.CloudWatchConfig // LOG_CLOUDWATCH_*, dùng khi Output có cloudwatch
+	Syslog     logger.SyslogConfig     // LOG_SYSLOG_*, dùng khi Output có syslog
+	SinkBatch  logger.BatchOptions     // LOG_SINK_*, batch và buffer của cloudwatch, syslog
+
+	LevelSyncInterval time.Duration // chu kỳ đọc level đặt lúc chạy (admin API/CLI) từ Redis
+}
+
+// LoadLoggerConfig load logger config từ environment variables
+func LoadLoggerConfig() *LoggerConfig {
+	return &LoggerConfig{
+		Level:         utils.GetEnv("LOG_LEVEL", "debug"),
+		ModuleLevels:  loadModuleLevels(),
+		Output:        utils.GetEnv("LOG_OUTPUT", "console,file"),
+		LogPath:       utils.GetEnv("LOG_PATH", "storages/logs"),
+		LokiURL:       utils.GetEnv("LOG_LOKI_URL", "http://localhost:3100"),
+		EnableCaller:  utils.GetEnvBool("LOG_ENABLE_CALLER", false),
+		PrettyPrint:   utils.GetEnvBool("LOG_PRETTY_PRINT", true),
+		DailyRotation: utils.GetEnvBool("LOG_DAILY_ROTATION", true),
+		MaxSizeMB:     utils.GetEnvInt("LOG_MAX_SIZE_MB", 100),
+		MaxBackups:    utils.GetEnvInt("LOG_MAX_BACKUPS", 0),
+		MaxAgeDays:    utils.GetEnvInt("LOG_MAX_AGE_DAYS", 30),
+		Compress:      utils.GetEnvBool("LOG_COMPRESS", true),
+		SampleRoutes:  utils.GetEnv("LOG_SAMPLE_ROUTES", ""),
+
+		CloudWatch: logger.CloudWatchConfig{
+			Region:          utils.GetEnv("LOG_CLOUDWATCH_REGION", ""),
+			LogGroup:        utils.GetEnv("LOG_CLOUDWATCH_GROUP", ""),
+			LogStream:       utils.GetEnv("LOG_CLOUDWATCH_STREAM", ""),
+			AccessKeyID:     utils.GetEnv("LOG_CLOUDWATCH_ACCESS_KEY_ID", ""),
+			SecretAccessKey: utils.GetEnv("LOG_CLOUDWATCH_SECRET_ACCESS_KEY", ""),
+			Endpoint:        utils.GetEnv("LOG_CLOUDWATCH_ENDPOINT", ""),
+		},
+		Syslog: logger.SyslogConfig{
+			Network:  strings.ToLower(utils.GetEnv("LOG_SYSLOG_NETWORK", "udp")),
+			Address:  utils.GetEnv("LOG_SYSLOG_ADDRESS", "localhost:514"),
+			Tag:      utils.GetEnv("LOG_SYSLOG_TAG", "apicore"),
+			Facility: strings.ToLower(utils.GetEnv("LOG_SYSLOG_FACILITY", "local0")),
+		},
+		SinkBatch: logger.BatchOptions{
+			BatchSize:     utils.GetEnvInt("LOG_SINK_BATCH_SIZE", 500),
+			FlushInterval: time.Duration(utils.GetEnvInt("LOG_SINK_FLUSH_MS", 1000)) * time.Millisecond,
+			BufferSize:    utils.GetEnvInt("LOG_SINK_BUFFER_SIZE", 10000),
+			BlockTimeout:  time.Duration(utils.GetEnvInt("LOG_SINK_BLOCK_MS", 0)) * time.Millisecond,
+			MaxRetries:    utils.GetEnvInt("LOG_SINK_MAX_RETRIES", 2),
+		},
+
+		LevelSyncInterval: time.Duration(utils.GetEnvInt("LOG_LEVEL_SYNC_SECONDS", 5)) * time.Second,
+	}
+}
+
+// loadModuleLevels đọc LOG_LEVEL_REQUEST, LOG_LEVEL_GORM, LOG_LEVEL_CRON, LOG_LEVEL_SOCKET
+func loadModuleLevels() map[string]string {
+	levels := make(map[string]string)
+	for _, module := range logger.Modules {
+		if module == logger.ModuleApp {
+			continue
+		}
+		if level := utils.GetEnv("LOG_LEVEL_"+strings.ToUpper(module), ""); level != "" {
+			levels[module] = strings.ToLower(level)
+		}
+	}
+	return levels
+}
+
+// ValidateLoggerConfig kiểm tra config có hợp lệ không
+func (c *LoggerConfig) Validate() error {
+	// Validate level
+	validLevels := []string{"debug", "info", "warn", "error"}
+	if !contains(validLevels, c.Level) {
+		return fmt.Errorf("invalid log level: %s, must be one of %v", c.Level, validLevels)
+	}
+
+	for module, level := range c.ModuleLevels {
+		if level != "" && !contains(validLevels, level) {
+			return fmt.Errorf("invalid log level for module %s: %s, must be one of %v", module, level, validLevels)
+		}
+	}
+
+	// Validate output
+	validOutputs := []string{"console", "file", "loki", "cloudwatch", "syslog"}
+	outputs := strings.Split(strings.ToLower(c.Output), ",")
+	for i, output := range outputs {
+		output = strings.TrimSpace(output)
+		outputs[i] = output
+		if output != "" && !contains(validOutputs, output) {
+			return fmt.Errorf("invalid log output: %s, must be one of %v", output, validOutputs)
+		}
+	}
+
+	// Validate rotation
+	if c.MaxSizeMB < 0 || c.MaxBackups < 0 || c.MaxAgeDays < 0 {
+		return fmt.Errorf("LOG_MAX_SIZE_MB, LOG_MAX_BACKUPS and LOG_MAX_AGE_DAYS must not be negative")
+	}
+
+	if _, err := logger.ParseSampleRules(c.SampleRoutes); err != nil {
+		return fmt.Errorf("invalid LOG_SAMPLE_ROUTES: %w", err)
+	}
+
+	if c.LevelSyncInterval <= 0 {
+		return fmt.Errorf("LOG_LEVEL_SYNC_SECONDS must be positive")
+	}
+
+	// Validate Loki URL if loki output is enabled
+	if strings.Contains(strings.ToLower(c.Output), "loki") {
+		if c.LokiURL == "" {
+			return fmt.Errorf("loki URL is required when loki output is enabled")
+		}
+	}
+
+	if contains(outputs, "cloudwatch") && (c.CloudWatch.Region == "" || c.CloudWatch.LogGroup == "") {
+		return fmt.Errorf("LOG_CLOUDWATCH_REGION and LOG_CLOUDWATCH_GROUP are required when cloudwatch output is enabled")
+	}
+	if contains(outputs, "syslog") {
+		if err := c.Syslog.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.SinkBatch.BatchSize < 0 || c.SinkBatch.BufferSize < 0 || c.SinkBatch.FlushInterval < 0 || c.SinkBatch.BlockTimeout < 0 || c.SinkBatch.MaxRetries < 0 {
+		return fmt.Errorf("LOG_SINK_* settings must not be negative")
+	}
+
+	return nil
+}
+
+// ToLoggerConfig convert sang logger.Config (rule sampling không hợp lệ bị bỏ qua, đã kiểm tra ở Validate)
+func (c *LoggerConfig) ToLoggerConfig() logger.Config {
+	sampleRules, _ := logger.ParseSampleRules(c.SampleRoutes)
+	return logger.Config{
+		Level:         c.Level,
+		ModuleLevels:  c.ModuleLevels,
+		Output:        c.Output,
+		LogPath:       c.LogPath,
+		LokiURL:       c.LokiURL,
+		EnableCaller:  c.EnableCaller,
+		PrettyPrint:   c.PrettyPrint,
+		DailyRotation: c.DailyRotation,
+		MaxSizeMB:     c.MaxSizeMB,
+		MaxBackups:    c.MaxBackups,
+		MaxAgeDays:    c.MaxAgeDays,
+		Compress:      c.Compress,
+		SampleRules:   sampleRules,
+		CloudWatch:    c.CloudWatch,
+		Syslog:        c.Syslog,
+		SinkBatch:     c.SinkBatch,
+	}
+}
+
+// contains kiểm tra slice có chứa string không
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,8 @@
 package config
 
 import (
-	"api-core/pkg/loki"
-	"api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/pkg/loki"
+	"github.com/anhnq996/go-api-core/pkg/utils"
 )
 
 // LokiConfig cấu hình cho Loki events
@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// MagicLinkConfig cấu hình passwordless login qua email
+type MagicLinkConfig struct {
+	Secret       string        // Key ký HMAC cho link, mặc định dùng JWT_SECRET_KEY
+	TTL          time.Duration // Thời gian sống của link
+	VerifyURL    string        // URL trong email, token được gắn vào query ?token=
+	TemplatePath string        // Email template
+}
+
+// LoadMagicLinkConfig load magic link config từ environment variables
+func LoadMagicLinkConfig() *MagicLinkConfig {
+	serverURL := utils.GetEnv("SERVER_URL", "http://localhost:3000")
+
+	return &MagicLinkConfig{
+		Secret:       utils.GetEnv("MAGIC_LINK_SECRET", utils.GetEnv("JWT_SECRET_KEY", "")),
+		TTL:          time.Duration(utils.GetEnvInt("MAGIC_LINK_TTL_MINUTES", 15)) * time.Minute,
+		VerifyURL:    utils.GetEnv("MAGIC_LINK_VERIFY_URL", serverURL+"/api/v1/auth/magic-link/verify"),
+		TemplatePath: utils.GetEnv("MAGIC_LINK_TEMPLATE", "internal/templates/emails/magic_link.html"),
+	}
+}
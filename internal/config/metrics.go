@@ -0,0 +1,47 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// MetricsConfig cấu hình endpoint OpenMetrics (HTTP, runtime, DB pool, cache, cron jobs, queues) cho Prometheus scrape
+type MetricsConfig struct {
+	Enabled bool   // Bật listener metrics riêng, không expose qua API port
+	Addr    string // Địa chỉ listen, vd :9090
+	Path    string // Đường dẫn scrape
+	Route   bool   // Expose thêm Path trên API port (khi không mở được port riêng)
+	Token   string // Bearer token bắt buộc khi scrape qua API port, rỗng: không xác thực
+}
+
+// LoadMetricsConfig load metrics config từ environment variables
+func LoadMetricsConfig() *MetricsConfig {
+	return &MetricsConfig{
+		Enabled: utils.GetEnvBool("METRICS_ENABLED", false),
+		Addr:    utils.GetEnv("METRICS_ADDR", ":9090"),
+		Path:    utils.GetEnv("METRICS_PATH", "/metrics"),
+		Route:   utils.GetEnvBool("METRICS_ROUTE_ENABLED", false),
+		Token:   utils.GetEnv("METRICS_TOKEN", ""),
+	}
+}
+
+// Validate kiểm tra metrics config
+func (c *MetricsConfig) Validate() error {
+	if !c.Enabled && !c.Route {
+		return nil
+	}
+	if c.Enabled && c.Addr == "" {
+		return fmt.Errorf("METRICS_ADDR is required when METRICS_ENABLED=true")
+	}
+	if !strings.HasPrefix(c.Path, "/") {
+		return fmt.Errorf("METRICS_PATH must start with /")
+	}
+	return nil
+}
+
+// Collecting có export metrics (listener riêng hoặc qua API port), false thì không cần đo
+func (c *MetricsConfig) Collecting() bool {
+	return c.Enabled || c.Route
+}
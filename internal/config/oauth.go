@@ -0,0 +1,88 @@
+package config
+
+import (
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// OAuthProviderConfig cấu hình OAuth2 client cho một social provider
+type OAuthProviderConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Enabled provider được bật khi có đủ client id và secret
+func (c OAuthProviderConfig) Enabled() bool {
+	return c.ClientID != "" && c.ClientSecret != ""
+}
+
+// OIDCConfig cấu hình OIDC single sign-on (Keycloak, Azure AD...)
+type OIDCConfig struct {
+	Name         string // Tên provider trong URL /auth/social/{provider}
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+
+	// Claim mapping, hỗ trợ path lồng nhau (vd. realm_access.roles)
+	ClaimSubject       string
+	ClaimEmail         string
+	ClaimEmailVerified string
+	ClaimName          string
+	ClaimPicture       string
+	ClaimRoles         string
+
+	RoleMapping string // "idp-role:local-role,...", rule khớp đầu tiên được dùng, "*" khớp mọi user
+	TrustEmail  bool   // Coi email từ issuer là đã verify
+}
+
+// Enabled OIDC được bật khi có issuer và client id
+func (c OIDCConfig) Enabled() bool {
+	return c.IssuerURL != "" && c.ClientID != ""
+}
+
+// OAuthConfig cấu hình social login
+type OAuthConfig struct {
+	Google OAuthProviderConfig
+	GitHub OAuthProviderConfig
+	OIDC   OIDCConfig
+}
+
+// LoadOAuthConfig load OAuth config từ environment variables
+func LoadOAuthConfig() *OAuthConfig {
+	serverURL := utils.GetEnv("SERVER_URL", "http://localhost:3000")
+	oidcName := utils.GetEnv("OIDC_PROVIDER_NAME", "oidc")
+
+	return &OAuthConfig{
+		Google: OAuthProviderConfig{
+			ClientID:     utils.GetEnv("OAUTH_GOOGLE_CLIENT_ID", ""),
+			ClientSecret: utils.GetEnv("OAUTH_GOOGLE_CLIENT_SECRET", ""),
+			RedirectURL:  utils.GetEnv("OAUTH_GOOGLE_REDIRECT_URL", serverURL+"/api/v1/auth/social/google/callback"),
+			Scopes:       utils.GetEnvStringSlice("OAUTH_GOOGLE_SCOPES", nil),
+		},
+		GitHub: OAuthProviderConfig{
+			ClientID:     utils.GetEnv("OAUTH_GITHUB_CLIENT_ID", ""),
+			ClientSecret: utils.GetEnv("OAUTH_GITHUB_CLIENT_SECRET", ""),
+			RedirectURL:  utils.GetEnv("OAUTH_GITHUB_REDIRECT_URL", serverURL+"/api/v1/auth/social/github/callback"),
+			Scopes:       utils.GetEnvStringSlice("OAUTH_GITHUB_SCOPES", nil),
+		},
+		OIDC: OIDCConfig{
+			Name:               oidcName,
+			IssuerURL:          utils.GetEnv("OIDC_ISSUER_URL", ""),
+			ClientID:           utils.GetEnv("OIDC_CLIENT_ID", ""),
+			ClientSecret:       utils.GetEnv("OIDC_CLIENT_SECRET", ""),
+			RedirectURL:        utils.GetEnv("OIDC_REDIRECT_URL", serverURL+"/api/v1/auth/social/"+oidcName+"/callback"),
+			Scopes:             utils.GetEnvStringSlice("OIDC_SCOPES", nil),
+			ClaimSubject:       utils.GetEnv("OIDC_CLAIM_SUBJECT", "sub"),
+			ClaimEmail:         utils.GetEnv("OIDC_CLAIM_EMAIL", "email"),
+			ClaimEmailVerified: utils.GetEnv("OIDC_CLAIM_EMAIL_VERIFIED", "email_verified"),
+			ClaimName:          utils.GetEnv("OIDC_CLAIM_NAME", "name"),
+			ClaimPicture:       utils.GetEnv("OIDC_CLAIM_PICTURE", "picture"),
+			ClaimRoles:         utils.GetEnv("OIDC_CLAIM_ROLES", ""),
+			RoleMapping:        utils.GetEnv("OIDC_ROLE_MAPPING", ""),
+			TrustEmail:         utils.GetEnvBool("OIDC_TRUST_EMAIL", false),
+		},
+	}
+}
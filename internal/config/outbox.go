@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// OutboxConfig cấu hình outbox publisher (chạy cùng queue worker)
+type OutboxConfig struct {
+	Enabled       bool
+	PollInterval  time.Duration // Chu kỳ quét bảng outbox_messages
+	BatchSize     int           // Số message tối đa mỗi lần quét
+	RetryMaxDelay time.Duration // Delay tối đa giữa các lần publish lại khi broker lỗi
+	Retention     time.Duration // Giữ message đã publish, 0: không xóa
+}
+
+// LoadOutboxConfig load outbox config từ environment variables
+func LoadOutboxConfig() *OutboxConfig {
+	return &OutboxConfig{
+		Enabled:       utils.GetEnvBool("OUTBOX_ENABLED", true),
+		PollInterval:  time.Duration(utils.GetEnvInt("OUTBOX_POLL_INTERVAL_MS", 1000)) * time.Millisecond,
+		BatchSize:     utils.GetEnvInt("OUTBOX_BATCH_SIZE", 100),
+		RetryMaxDelay: time.Duration(utils.GetEnvInt("OUTBOX_RETRY_MAX_DELAY", 300)) * time.Second,
+		Retention:     time.Duration(utils.GetEnvInt("OUTBOX_RETENTION_HOURS", 168)) * time.Hour,
+	}
+}
+
+// Validate kiểm tra outbox config
+func (c *OutboxConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL_MS must be greater than 0")
+	}
+
+	if c.BatchSize <= 0 {
+		return fmt.Errorf("OUTBOX_BATCH_SIZE must be greater than 0")
+	}
+
+	if c.RetryMaxDelay <= 0 {
+		return fmt.Errorf("OUTBOX_RETRY_MAX_DELAY must be greater than 0")
+	}
+
+	if c.Retention < 0 {
+		return fmt.Errorf("OUTBOX_RETENTION_HOURS must not be negative")
+	}
+
+	return nil
+}
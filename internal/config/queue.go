@@ -0,0 +1,275 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// QueueConfig cấu hình queue backend cho worker
+type QueueConfig struct {
+	Driver      string // redis | rabbitmq | kafka
+	Host        string
+	Port        int
+	Username    string // RabbitMQ
+	Password    string
+	DB          int    // Redis
+	VHost       string // RabbitMQ
+	Concurrency int    // Số goroutine xử lý mỗi queue
+	MaxRetries  int
+	RetryDelay  time.Duration
+	// Backoff giữa các lần retry khi handler không khai báo policy: constant | linear | exponential
+	RetryBackoff  string
+	RetryMaxDelay time.Duration // Giới hạn delay (0 = không giới hạn)
+	RetryJitter   bool          // Delay ngẫu nhiên trong [d/2, d]
+	// DelayedRetry retry qua delayed queue thay vì worker chờ tại chỗ
+	DelayedRetry bool
+	// RabbitMQDelay cách RabbitMQ giao delayed message: auto | plugin | ttl
+	RabbitMQDelay string
+	// QueueConcurrency số goroutine theo queue, ghi đè Concurrency (WORKER_QUEUE_CONCURRENCY="emails:8,default:2")
+	QueueConcurrency map[string]int
+	// QueueRateLimit số message/giây tối đa theo queue, mỗi worker process (WORKER_QUEUE_RATE_LIMIT="emails:10,push:200")
+	QueueRateLimit map[string]float64
+	// QueueMaxInFlight số message xử lý đồng thời tối đa theo queue (WORKER_QUEUE_MAX_IN_FLIGHT="emails:2")
+	QueueMaxInFlight map[string]int
+	// HandlerTimeout thời gian tối đa xử lý 1 message, khi shutdown worker chờ message đang xử lý tối đa chừng này
+	HandlerTimeout time.Duration
+	Redis          CacheConfig // Mode/addrs/TLS của Redis (REDIS_*), host/port/password/DB theo QUEUE_*
+	Kafka          KafkaConfig // Chỉ dùng khi Driver = kafka
+}
+
+// KafkaConfig cấu hình Kafka của queue (KAFKA_*)
+type KafkaConfig struct {
+	Brokers           []string
+	GroupID           string // Consumer group, mọi worker cùng group chia nhau partition
+	ClientID          string
+	TopicPrefix       string
+	Partitions        int // Số partition khi tạo topic mới
+	ReplicationFactor int
+	TLS               bool
+	TLSServerName     string
+}
+
+// LoadQueueConfig load queue config từ environment variables, mặc định dùng chung Redis với cache
+func LoadQueueConfig() *QueueConfig {
+	return &QueueConfig{
+		Driver:      utils.GetEnv("QUEUE_DRIVER", "redis"),
+		Host:        utils.GetEnv("QUEUE_HOST", utils.GetEnv("REDIS_HOST", "localhost")),
+		Port:        utils.GetEnvInt("QUEUE_PORT", utils.GetEnvInt("REDIS_PORT", 6379)),
+		Username:    utils.GetEnv("QUEUE_USERNAME", "guest"),
+		Password:    utils.GetEnv("QUEUE_PASSWORD", utils.GetEnv("REDIS_PASSWORD", "")),
+		DB:          utils.GetEnvInt("QUEUE_DB", utils.GetEnvInt("REDIS_DB", 0)),
+		VHost:       utils.GetEnv("QUEUE_VHOST", "/"),
+		Concurrency: utils.GetEnvInt("WORKER_CONCURRENCY", 4),
+		MaxRetries:  utils.GetEnvInt("WORKER_MAX_RETRIES", 3),
+		RetryDelay:  time.Duration(utils.GetEnvInt("WORKER_RETRY_DELAY", 5)) * time.Second,
+
+		RetryBackoff:  utils.GetEnv("WORKER_RETRY_BACKOFF", string(queue.BackoffExponential)),
+		RetryMaxDelay: time.Duration(utils.GetEnvInt("WORKER_RETRY_MAX_DELAY", 600)) * time.Second,
+		RetryJitter:   utils.GetEnvBool("WORKER_RETRY_JITTER", true),
+		DelayedRetry:  utils.GetEnvBool("WORKER_RETRY_DELAYED", true),
+		RabbitMQDelay: utils.GetEnv("QUEUE_RABBITMQ_DELAY", queue.RabbitMQDelayAuto),
+
+		QueueConcurrency: parseQueueConcurrency(utils.GetEnvStringSlice("WORKER_QUEUE_CONCURRENCY", nil)),
+		QueueRateLimit:   parseQueueRateLimit(utils.GetEnvStringSlice("WORKER_QUEUE_RATE_LIMIT", nil)),
+		QueueMaxInFlight: parseQueueConcurrency(utils.GetEnvStringSlice("WORKER_QUEUE_MAX_IN_FLIGHT", nil)),
+		HandlerTimeout:   time.Duration(utils.GetEnvInt("WORKER_HANDLER_TIMEOUT", 30)) * time.Second,
+
+		Redis: GetDefaultCacheConfig(),
+		Kafka: KafkaConfig{
+			Brokers:           utils.GetEnvStringSlice("KAFKA_BROKERS", []string{"localhost:9092"}),
+			GroupID:           utils.GetEnv("KAFKA_GROUP_ID", "api-core-workers"),
+			ClientID:          utils.GetEnv("KAFKA_CLIENT_ID", "api-core"),
+			TopicPrefix:       utils.GetEnv("KAFKA_TOPIC_PREFIX", "queue."),
+			Partitions:        utils.GetEnvInt("KAFKA_TOPIC_PARTITIONS", 6),
+			ReplicationFactor: utils.GetEnvInt("KAFKA_REPLICATION_FACTOR", 1),
+			TLS:               utils.GetEnvBool("KAFKA_TLS", false),
+			TLSServerName:     utils.GetEnv("KAFKA_TLS_SERVER_NAME", ""),
+		},
+	}
+}
+
+// parseQueueConcurrency parse danh sách "queue:concurrency", bỏ qua phần tử không hợp lệ
+func parseQueueConcurrency(items []string) map[string]int {
+	concurrency := make(map[string]int)
+	for _, item := range items {
+		name, value, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || n <= 0 {
+			continue
+		}
+		concurrency[strings.TrimSpace(name)] = n
+	}
+	return concurrency
+}
+
+// parseQueueRateLimit parse danh sách "queue:message/giây" (số thực), bỏ qua phần tử không hợp lệ
+func parseQueueRateLimit(items []string) map[string]float64 {
+	limits := make(map[string]float64)
+	for _, item := range items {
+		name, value, ok := strings.Cut(strings.TrimSpace(item), ":")
+		if !ok {
+			continue
+		}
+		r, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || r <= 0 {
+			continue
+		}
+		limits[strings.TrimSpace(name)] = r
+	}
+	return limits
+}
+
+// Validate kiểm tra queue config
+func (c *QueueConfig) Validate() error {
+	switch queue.QueueType(c.Driver) {
+	case queue.QueueTypeRedis, queue.QueueTypeRabbitMQ:
+		if c.Port <= 0 || c.Port > 65535 {
+			return fmt.Errorf("QUEUE_PORT must be between 1 and 65535")
+		}
+	case queue.QueueTypeKafka:
+		if err := c.Kafka.Validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("QUEUE_DRIVER must be redis, rabbitmq or kafka")
+	}
+
+	if c.Concurrency <= 0 {
+		return fmt.Errorf("WORKER_CONCURRENCY must be greater than 0")
+	}
+
+	switch queue.BackoffCurve(c.RetryBackoff) {
+	case queue.BackoffConstant, queue.BackoffLinear, queue.BackoffExponential:
+	default:
+		return fmt.Errorf("WORKER_RETRY_BACKOFF must be constant, linear or exponential")
+	}
+
+	if c.RetryMaxDelay < 0 {
+		return fmt.Errorf("WORKER_RETRY_MAX_DELAY must not be negative")
+	}
+
+	if c.HandlerTimeout <= 0 {
+		return fmt.Errorf("WORKER_HANDLER_TIMEOUT must be greater than 0")
+	}
+
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("WORKER_MAX_RETRIES must not be negative")
+	}
+
+	if c.Driver == string(queue.QueueTypeRabbitMQ) {
+		switch c.RabbitMQDelay {
+		case queue.RabbitMQDelayAuto, queue.RabbitMQDelayPlugin, queue.RabbitMQDelayTTL:
+		default:
+			return fmt.Errorf("QUEUE_RABBITMQ_DELAY must be auto, plugin or ttl")
+		}
+	}
+
+	if c.Driver == string(queue.QueueTypeRedis) {
+		if err := c.redisConfig().Validate(); err != nil {
+			return fmt.Errorf("invalid redis queue config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ToQueueConfig convert sang queue.QueueConfig
+func (c *QueueConfig) ToQueueConfig() *queue.QueueConfig {
+	return &queue.QueueConfig{
+		Type:           queue.QueueType(c.Driver),
+		Host:           c.Host,
+		Port:           c.Port,
+		Username:       c.Username,
+		Password:       c.Password,
+		Database:       c.DB,
+		VHost:          c.VHost,
+		RabbitMQDelay:  c.RabbitMQDelay,
+		MaxRetries:     3,
+		ConnectTimeout: 5 * time.Second,
+		ReadTimeout:    5 * time.Second,
+		WriteTimeout:   5 * time.Second,
+		IdleTimeout:    5 * time.Minute,
+		MaxActiveConns: c.Concurrency * 2,
+		Redis:          c.redisConfig(),
+		Kafka:          c.kafkaConfig(),
+	}
+}
+
+// Validate kiểm tra Kafka config
+func (c *KafkaConfig) Validate() error {
+	if len(c.Brokers) == 0 {
+		return fmt.Errorf("KAFKA_BROKERS is required")
+	}
+
+	if c.GroupID == "" {
+		return fmt.Errorf("KAFKA_GROUP_ID is required")
+	}
+
+	if c.Partitions <= 0 {
+		return fmt.Errorf("KAFKA_TOPIC_PARTITIONS must be greater than 0")
+	}
+
+	if c.ReplicationFactor <= 0 {
+		return fmt.Errorf("KAFKA_REPLICATION_FACTOR must be greater than 0")
+	}
+
+	return nil
+}
+
+// kafkaConfig kết nối Kafka của queue, nil nếu không dùng driver kafka
+func (c *QueueConfig) kafkaConfig() *queue.KafkaConfig {
+	if c.Driver != string(queue.QueueTypeKafka) {
+		return nil
+	}
+	cfg := &queue.KafkaConfig{
+		Brokers:           c.Kafka.Brokers,
+		GroupID:           c.Kafka.GroupID,
+		ClientID:          c.Kafka.ClientID,
+		TopicPrefix:       c.Kafka.TopicPrefix,
+		Partitions:        c.Kafka.Partitions,
+		ReplicationFactor: c.Kafka.ReplicationFactor,
+	}
+	if c.Kafka.TLS {
+		cfg.TLS = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: c.Kafka.TLSServerName,
+		}
+	}
+	return cfg
+}
+
+// redisConfig kết nối Redis của queue: dùng chung mode sentinel/cluster và TLS với cache
+func (c *QueueConfig) redisConfig() *redisclient.Config {
+	cfg := c.Redis.RedisConfig()
+	cfg.Host = c.Host
+	cfg.Port = strconv.Itoa(c.Port)
+	cfg.Password = c.Password
+	cfg.DB = c.DB
+	return &cfg
+}
+
+// ToConsumerOptions options cho consumer của từng queue
+func (c *QueueConfig) ToConsumerOptions() *queue.ConsumerOptions {
+	return &queue.ConsumerOptions{
+		Concurrency:    c.Concurrency,
+		HandlerTimeout: c.HandlerTimeout,
+		MaxRetries:     c.MaxRetries,
+		RetryDelay:     c.RetryDelay,
+		RetryBackoff: queue.Backoff{
+			Curve:  queue.BackoffCurve(c.RetryBackoff),
+			Max:    c.RetryMaxDelay,
+			Jitter: c.RetryJitter,
+		},
+		DelayedRetry: c.DelayedRetry,
+	}
+}
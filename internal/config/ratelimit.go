@@ -3,8 +3,9 @@ package config
 import (
 	"time"
 
-	"api-core/pkg/ratelimit"
-	"api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/pkg/middleware"
+	"github.com/anhnq996/go-api-core/pkg/ratelimit"
+	"github.com/anhnq996/go-api-core/pkg/utils"
 
 	"github.com/go-redis/redis/v8"
 )
@@ -50,8 +51,18 @@ func getIPRules() map[string]RateLimitRule {
 	return rules
 }
 
+// ToRateLimitOptions chuyển sang middleware.RateLimitOptions
+func (c *RateLimitConfig) ToRateLimitOptions() middleware.RateLimitOptions {
+	return middleware.RateLimitOptions{
+		Enabled:         c.Enabled,
+		KeyPrefix:       c.KeyPrefix,
+		DefaultRequests: c.DefaultRule.Requests,
+		DefaultDuration: c.DefaultRule.Duration,
+	}
+}
+
 // CreateRateLimiter creates a rate limiter instance
-func CreateRateLimiter(redisClient *redis.Client, config *RateLimitConfig) *ratelimit.RateLimiter {
+func CreateRateLimiter(redisClient redis.UniversalClient, config *RateLimitConfig) *ratelimit.RateLimiter {
 	return ratelimit.NewRateLimiter(ratelimit.RateLimitConfig{
 		Redis:     redisClient,
 		KeyPrefix: config.KeyPrefix,
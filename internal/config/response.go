@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// ResponseFormatConfig cấu hình serialize JSON response (field case, envelope)
+type ResponseFormatConfig struct {
+	FieldCase           string   // snake (mặc định) | camel
+	Envelope            string   // standard (mặc định) | bare
+	Routes              []string // Format theo route prefix (API version), dạng "/api/v2=camel:bare"
+	AllowHeaderOverride bool     // Client chọn format bằng X-Response-Case / X-Response-Envelope
+}
+
+// LoadResponseFormatConfig load response format config từ environment variables
+func LoadResponseFormatConfig() *ResponseFormatConfig {
+	return &ResponseFormatConfig{
+		FieldCase:           utils.GetEnv("RESPONSE_FIELD_CASE", string(response.FieldCaseSnake)),
+		Envelope:            utils.GetEnv("RESPONSE_ENVELOPE", string(response.EnvelopeStandard)),
+		Routes:              utils.GetEnvStringSlice("RESPONSE_FORMAT_ROUTES", nil),
+		AllowHeaderOverride: utils.GetEnvBool("RESPONSE_FORMAT_HEADER_OVERRIDE", true),
+	}
+}
+
+// ToFormatConfig chuyển sang response.FormatConfig, lỗi khi giá trị không hợp lệ
+func (c *ResponseFormatConfig) ToFormatConfig() (response.FormatConfig, error) {
+	fieldCase, err := response.ParseFieldCase(c.FieldCase)
+	if err != nil {
+		return response.FormatConfig{}, fmt.Errorf("RESPONSE_FIELD_CASE: %w", err)
+	}
+	envelope, err := response.ParseEnvelope(c.Envelope)
+	if err != nil {
+		return response.FormatConfig{}, fmt.Errorf("RESPONSE_ENVELOPE: %w", err)
+	}
+
+	cfg := response.FormatConfig{
+		Default:             response.Format{FieldCase: fieldCase, Envelope: envelope},
+		AllowHeaderOverride: c.AllowHeaderOverride,
+	}
+	for _, item := range c.Routes {
+		prefix, value, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok || !strings.HasPrefix(prefix, "/") {
+			return response.FormatConfig{}, fmt.Errorf("RESPONSE_FORMAT_ROUTES: invalid entry %q, expected /prefix=case[:envelope]", item)
+		}
+		format, err := response.ParseFormat(value)
+		if err != nil {
+			return response.FormatConfig{}, fmt.Errorf("RESPONSE_FORMAT_ROUTES %q: %w", prefix, err)
+		}
+		cfg.Routes = append(cfg.Routes, response.RouteFormat{Prefix: prefix, Format: format})
+	}
+	return cfg, nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// RouteGroupConfig middleware budget của một route group
+type RouteGroupConfig struct {
+	RateLimit  int           // Số request tối đa trong RateWindow cho mỗi user/IP (0: không giới hạn)
+	RateWindow time.Duration // Cửa sổ rate limit
+	LogLevel   string        // Mức request log: full, basic (không log body), none
+}
+
+// RoutesConfig cấu hình tập trung cho các route group (public, authenticated, admin, internal)
+type RoutesConfig struct {
+	Public        RouteGroupConfig
+	Authenticated RouteGroupConfig
+	Admin         RouteGroupConfig
+	Internal      RouteGroupConfig
+	AdminRoles    []string // Nếu set, mọi route admin yêu cầu thêm một trong các role này (ngoài permission của từng route)
+}
+
+// LoadRoutesConfig load route group config từ environment variables
+func LoadRoutesConfig() *RoutesConfig {
+	return &RoutesConfig{
+		Public:        loadRouteGroupConfig("PUBLIC", 150, 60, "basic"),
+		Authenticated: loadRouteGroupConfig("AUTHENTICATED", 200, 60, "full"),
+		Admin:         loadRouteGroupConfig("ADMIN", 150, 60, "full"),
+		Internal:      loadRouteGroupConfig("INTERNAL", 600, 60, "basic"),
+		AdminRoles:    utils.GetEnvStringSlice("ROUTES_ADMIN_ROLES", []string{}),
+	}
+}
+
+func loadRouteGroupConfig(group string, requests, windowSeconds int, logLevel string) RouteGroupConfig {
+	prefix := "ROUTES_" + group + "_"
+	return RouteGroupConfig{
+		RateLimit:  utils.GetEnvInt(prefix+"RATE_LIMIT", requests),
+		RateWindow: time.Duration(utils.GetEnvInt(prefix+"RATE_WINDOW_SECONDS", windowSeconds)) * time.Second,
+		LogLevel:   utils.GetEnv(prefix+"LOG", logLevel),
+	}
+}
+
+// Validate kiểm tra route group config
+func (c *RoutesConfig) Validate() error {
+	groups := map[string]RouteGroupConfig{
+		"public":        c.Public,
+		"authenticated": c.Authenticated,
+		"admin":         c.Admin,
+		"internal":      c.Internal,
+	}
+	for name, group := range groups {
+		if group.RateLimit < 0 {
+			return fmt.Errorf("routes %s: rate limit must not be negative", name)
+		}
+		if group.RateLimit > 0 && group.RateWindow <= 0 {
+			return fmt.Errorf("routes %s: rate window must be positive", name)
+		}
+		switch strings.ToLower(group.LogLevel) {
+		case "full", "basic", "none":
+		default:
+			return fmt.Errorf("routes %s: invalid log level %q (full, basic, none)", name, group.LogLevel)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// SafeHTTPConfig cấu hình outbound request tới URL do user cung cấp (link preview, avatar từ URL, webhook)
+type SafeHTTPConfig struct {
+	AllowedSchemes  []string // Scheme được phép gọi
+	AllowedPorts    []string // Port được phép gọi
+	AllowedNetworks []string // CIDR nội bộ vẫn được phép gọi, mặc định: không có
+	MaxBodySize     int      // Kích thước response tối đa (bytes)
+	TimeoutSeconds  int      // Timeout mỗi request (kể cả redirect)
+	MaxRedirects    int      // Số redirect tối đa, âm = không theo redirect
+}
+
+// LoadSafeHTTPConfig load safehttp config từ environment variables
+func LoadSafeHTTPConfig() *SafeHTTPConfig {
+	return &SafeHTTPConfig{
+		AllowedSchemes:  utils.GetEnvStringSlice("SAFEHTTP_ALLOWED_SCHEMES", []string{"https", "http"}),
+		AllowedPorts:    utils.GetEnvStringSlice("SAFEHTTP_ALLOWED_PORTS", []string{"80", "443"}),
+		AllowedNetworks: utils.GetEnvStringSlice("SAFEHTTP_ALLOWED_NETWORKS", []string{}),
+		MaxBodySize:     utils.GetEnvInt("SAFEHTTP_MAX_BODY_SIZE", 5*1024*1024), // 5MB
+		TimeoutSeconds:  utils.GetEnvInt("SAFEHTTP_TIMEOUT_SECONDS", 10),
+		MaxRedirects:    utils.GetEnvInt("SAFEHTTP_MAX_REDIRECTS", 5),
+	}
+}
+
+// ToClientConfig chuyển sang safehttp.Config
+func (c *SafeHTTPConfig) ToClientConfig() (safehttp.Config, error) {
+	cfg := safehttp.Config{
+		MaxBodySize:  int64(c.MaxBodySize),
+		Timeout:      time.Duration(c.TimeoutSeconds) * time.Second,
+		MaxRedirects: c.MaxRedirects,
+	}
+
+	for _, scheme := range c.AllowedSchemes {
+		if scheme = strings.ToLower(strings.TrimSpace(scheme)); scheme != "" {
+			if scheme != "http" && scheme != "https" {
+				return cfg, fmt.Errorf("SAFEHTTP_ALLOWED_SCHEMES: unsupported scheme %q", scheme)
+			}
+			cfg.AllowedSchemes = append(cfg.AllowedSchemes, scheme)
+		}
+	}
+	for _, port := range c.AllowedPorts {
+		if port = strings.TrimSpace(port); port != "" {
+			p, err := strconv.Atoi(port)
+			if err != nil || p <= 0 || p > 65535 {
+				return cfg, fmt.Errorf("SAFEHTTP_ALLOWED_PORTS: invalid port %q", port)
+			}
+			cfg.AllowedPorts = append(cfg.AllowedPorts, p)
+		}
+	}
+	for _, network := range c.AllowedNetworks {
+		if network = strings.TrimSpace(network); network != "" {
+			prefix, err := netip.ParsePrefix(network)
+			if err != nil {
+				return cfg, fmt.Errorf("SAFEHTTP_ALLOWED_NETWORKS: invalid CIDR %q: %w", network, err)
+			}
+			cfg.AllowedNetworks = append(cfg.AllowedNetworks, prefix.Masked())
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// Leader election modes cho scheduler
+const (
+	LeaderElectionNone     = "none"     // Mọi instance chạy cron loop, mỗi job lấy lock riêng
+	LeaderElectionRedis    = "redis"    // Redis key có TTL
+	LeaderElectionPostgres = "postgres" // Postgres advisory lock
+)
+
+// SchedulerConfig cấu hình cron scheduler
+type SchedulerConfig struct {
+	LeaderElection      string        // none | redis | postgres
+	LeaderKey           string        // Redis key / tên advisory lock
+	LeaderTTL           time.Duration // Thời gian giữ quyền leader nếu không gia hạn (redis)
+	LeaderRenewInterval time.Duration // Chu kỳ gia hạn, phải nhỏ hơn LeaderTTL
+	InstanceID          string        // ID instance lưu trong Redis key, mặc định hostname-pid
+	RunHistory          bool          // Ghi lịch sử chạy job vào database, false: role scheduler không kết nối database
+	RunHistoryDays      int           // Số ngày giữ lịch sử chạy job (bảng cron_job_runs)
+}
+
+// LoadSchedulerConfig load scheduler config từ environment variables
+func LoadSchedulerConfig() *SchedulerConfig {
+	hostname, _ := os.Hostname()
+	return &SchedulerConfig{
+		LeaderElection:      utils.GetEnv("SCHEDULER_LEADER_ELECTION", LeaderElectionNone),
+		LeaderKey:           utils.GetEnv("SCHEDULER_LEADER_KEY", "api-core:cron:leader"),
+		LeaderTTL:           time.Duration(utils.GetEnvInt("SCHEDULER_LEADER_TTL", 15)) * time.Second,
+		LeaderRenewInterval: time.Duration(utils.GetEnvInt("SCHEDULER_LEADER_RENEW_INTERVAL", 5)) * time.Second,
+		InstanceID:          utils.GetEnv("SCHEDULER_INSTANCE_ID", fmt.Sprintf("%s-%d", hostname, os.Getpid())),
+		RunHistory:          utils.GetEnvBool("SCHEDULER_RUN_HISTORY", true),
+		RunHistoryDays:      utils.GetEnvInt("SCHEDULER_RUN_HISTORY_DAYS", 30),
+	}
+}
+
+// Validate kiểm tra scheduler config
+func (c *SchedulerConfig) Validate() error {
+	switch c.LeaderElection {
+	case LeaderElectionNone, LeaderElectionRedis, LeaderElectionPostgres:
+	default:
+		return fmt.Errorf("SCHEDULER_LEADER_ELECTION must be none, redis or postgres")
+	}
+
+	if c.RunHistoryDays <= 0 {
+		return fmt.Errorf("SCHEDULER_RUN_HISTORY_DAYS must be greater than 0")
+	}
+
+	if c.LeaderElection == LeaderElectionNone {
+		return nil
+	}
+
+	if c.LeaderRenewInterval <= 0 {
+		return fmt.Errorf("SCHEDULER_LEADER_RENEW_INTERVAL must be greater than 0")
+	}
+
+	if c.LeaderElection == LeaderElectionRedis {
+		if c.LeaderRenewInterval >= c.LeaderTTL {
+			return fmt.Errorf("SCHEDULER_LEADER_RENEW_INTERVAL must be less than SCHEDULER_LEADER_TTL")
+		}
+		if c.InstanceID == "" {
+			return fmt.Errorf("SCHEDULER_INSTANCE_ID is required for redis leader election")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/middleware"
+	"github.com/anhnq996/go-api-core/pkg/securitylog"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// SecurityLogConfig cấu hình security log (lỗi xác thực/phân quyền ghi ra stream "security")
+type SecurityLogConfig struct {
+	Enabled        bool
+	AlertThreshold int           // Số event của một IP/user trong AlertWindow để phát alert, 0: tắt
+	AlertWindow    time.Duration // Cửa sổ đếm
+}
+
+// LoadSecurityLogConfig load security log config từ environment variables
+func LoadSecurityLogConfig() *SecurityLogConfig {
+	return &SecurityLogConfig{
+		Enabled:        utils.GetEnvBool("SECURITY_LOG_ENABLED", true),
+		AlertThreshold: utils.GetEnvInt("SECURITY_ALERT_THRESHOLD", 20),
+		AlertWindow:    time.Duration(utils.GetEnvInt("SECURITY_ALERT_WINDOW_SECONDS", 60)) * time.Second,
+	}
+}
+
+// ToSecurityLogConfig chuyển sang securitylog.Config
+func (c *SecurityLogConfig) ToSecurityLogConfig() securitylog.Config {
+	return securitylog.Config{
+		AlertThreshold: c.AlertThreshold,
+		AlertWindow:    c.AlertWindow,
+	}
+}
+
+// ToSecurityLogOptions chuyển sang middleware.SecurityLogOptions
+func (c *SecurityLogConfig) ToSecurityLogOptions() middleware.SecurityLogOptions {
+	return middleware.SecurityLogOptions{
+		Enabled: c.Enabled,
+		Config:  c.ToSecurityLogConfig(),
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// SocketConfig cấu hình WebSocket hub
+type SocketConfig struct {
+	AuthRequired bool // Bắt buộc access token khi kết nối /ws, tắt: lấy user_id từ query (chỉ dùng khi dev)
+
+	ResumeEnabled  bool          // Khi shutdown, bàn giao room và event chưa gửi cho instance mới qua Redis
+	ResumeTTL      time.Duration // Thời gian giữ resume state chờ client kết nối lại
+	ReconnectDelay time.Duration // Client chờ trước khi kết nối lại (retry_after_ms trong frame reconnect)
+
+	AllowClientBroadcast bool // Cho phép client gửi "broadcast" tới mọi connection (mặc định tắt, chỉ gửi trong room)
+
+	PresenceTTL time.Duration // Instance ngừng heartbeat (crash) quá thời gian này thì user của nó bị coi là offline
+
+	ReplayBufferSize int           // Số message chưa ack giữ lại mỗi connection để gửi lại khi mất mạng, 0: tắt seq/ack
+	ReplayWindow     time.Duration // Thời gian giữ session của connection bị rớt chờ client kết nối lại
+
+	MaxMessageBytes   int           // Message lớn hơn bị đóng connection (1009)
+	MessagesPerSecond int           // Số message client được gửi mỗi giây, 0: không giới hạn
+	MessageBurst      int           // Số message được gửi dồn vượt rate, 0: gấp đôi MessagesPerSecond
+	SendBufferSize    int           // Số message chờ gửi mỗi connection, client đọc chậm bị ngắt khi đầy
+	WriteTimeout      time.Duration // Ghi bị chặn quá thời gian này thì ngắt connection (client treo)
+
+	PingInterval time.Duration // Chu kỳ gửi ping, phải nhỏ hơn PongWait
+	PongWait     time.Duration // Không nhận được gì (kể cả pong) quá thời gian này thì connection bị coi là chết và đóng
+	IdleTimeout  time.Duration // Client không gửi message nào quá thời gian này thì bị đóng (4004), 0: không giới hạn
+
+	ProtobufEnabled bool // Cho phép client chọn frame nhị phân protobuf (/ws?codec=protobuf), JSON luôn bật
+
+	APIClients string // Client credentials "client_id:secret,..." của internal API /internal/socket/*, rỗng: tắt API
+}
+
+// LoadSocketConfig load socket config từ environment variables
+func LoadSocketConfig() *SocketConfig {
+	return &SocketConfig{
+		AuthRequired: utils.GetEnvBool("SOCKET_AUTH_REQUIRED", true),
+
+		ResumeEnabled:  utils.GetEnvBool("SOCKET_RESUME_ENABLED", true),
+		ResumeTTL:      time.Duration(utils.GetEnvInt("SOCKET_RESUME_TTL_SECONDS", 120)) * time.Second,
+		ReconnectDelay: time.Duration(utils.GetEnvInt("SOCKET_RECONNECT_DELAY_MS", 1000)) * time.Millisecond,
+
+		AllowClientBroadcast: utils.GetEnvBool("SOCKET_ALLOW_CLIENT_BROADCAST", false),
+
+		PresenceTTL: time.Duration(utils.GetEnvInt("SOCKET_PRESENCE_TTL_SECONDS", 30)) * time.Second,
+
+		ReplayBufferSize: utils.GetEnvInt("SOCKET_REPLAY_BUFFER_SIZE", 100),
+		ReplayWindow:     time.Duration(utils.GetEnvInt("SOCKET_REPLAY_WINDOW_SECONDS", 30)) * time.Second,
+
+		MaxMessageBytes:   utils.GetEnvInt("SOCKET_MAX_MESSAGE_BYTES", 65536),
+		MessagesPerSecond: utils.GetEnvInt("SOCKET_MESSAGES_PER_SECOND", 20),
+		MessageBurst:      utils.GetEnvInt("SOCKET_MESSAGE_BURST", 40),
+		SendBufferSize:    utils.GetEnvInt("SOCKET_SEND_BUFFER_SIZE", 256),
+		WriteTimeout:      time.Duration(utils.GetEnvInt("SOCKET_WRITE_TIMEOUT_SECONDS", 10)) * time.Second,
+
+		PingInterval: time.Duration(utils.GetEnvInt("SOCKET_PING_INTERVAL_SECONDS", 25)) * time.Second,
+		PongWait:     time.Duration(utils.GetEnvInt("SOCKET_PONG_WAIT_SECONDS", 60)) * time.Second,
+		IdleTimeout:  time.Duration(utils.GetEnvInt("SOCKET_IDLE_TIMEOUT_SECONDS", 0)) * time.Second,
+
+		ProtobufEnabled: utils.GetEnvBool("SOCKET_PROTOBUF_ENABLED", true),
+
+		APIClients: utils.GetEnv("SOCKET_API_CLIENTS", ""),
+	}
+}
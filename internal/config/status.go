@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// StatusConfig cấu hình health check định kỳ cho public status page (/status)
+type StatusConfig struct {
+	Enabled         bool          // Ghi health check định kỳ (API instance), tắt thì /status chỉ có incidents
+	CheckInterval   time.Duration // Chu kỳ health check, mọi API instance dùng chung 1 lượt mỗi chu kỳ (Redis lock)
+	CheckTimeout    time.Duration // Timeout mỗi check
+	DegradedLatency time.Duration // Check thành công nhưng chậm hơn ngưỡng là degraded
+	HistoryDays     int           // Số ngày lịch sử hiển thị và lưu trong database
+}
+
+// LoadStatusConfig load status config từ environment variables
+func LoadStatusConfig() *StatusConfig {
+	return &StatusConfig{
+		Enabled:         utils.GetEnvBool("STATUS_CHECKS_ENABLED", true),
+		CheckInterval:   time.Duration(utils.GetEnvInt("STATUS_CHECK_INTERVAL_SECONDS", 60)) * time.Second,
+		CheckTimeout:    time.Duration(utils.GetEnvInt("STATUS_CHECK_TIMEOUT_SECONDS", 5)) * time.Second,
+		DegradedLatency: time.Duration(utils.GetEnvInt("STATUS_DEGRADED_LATENCY_MS", 1000)) * time.Millisecond,
+		HistoryDays:     utils.GetEnvInt("STATUS_HISTORY_DAYS", 90),
+	}
+}
+
+// Validate kiểm tra status config
+func (c *StatusConfig) Validate() error {
+	if c.CheckInterval < 10*time.Second {
+		return fmt.Errorf("STATUS_CHECK_INTERVAL_SECONDS must be at least 10")
+	}
+	if c.CheckTimeout <= 0 || c.CheckTimeout >= c.CheckInterval {
+		return fmt.Errorf("STATUS_CHECK_TIMEOUT_SECONDS must be positive and shorter than the check interval")
+	}
+	if c.DegradedLatency <= 0 {
+		return fmt.Errorf("STATUS_DEGRADED_LATENCY_MS must be positive")
+	}
+	if c.HistoryDays < 1 || c.HistoryDays > 365 {
+		return fmt.Errorf("STATUS_HISTORY_DAYS must be between 1 and 365")
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// GetDefaultStorageConfig lấy cấu hình storage mặc định
+func GetDefaultStorageConfig() storage.Config {
+	cfg := storage.Config{
+		Driver: getEnvStorage("STORAGE_DRIVER", "local"),
+		Local: storage.LocalConfig{
+			BasePath:           getEnvStorage("STORAGE_LOCAL_PATH", "storages/app"),
+			BaseURL:            getEnvStorage("STORAGE_LOCAL_URL", "/storages"),
+			EncryptionKeys:     getEnvStorage("STORAGE_LOCAL_ENCRYPTION_KEYS", ""),
+			EncryptionKeysFile: getEnvStorage("STORAGE_LOCAL_ENCRYPTION_KEYS_FILE", ""),
+		},
+		S3: storage.S3Config{
+			Bucket:          getEnvStorage("STORAGE_S3_BUCKET", ""),
+			Region:          getEnvStorage("STORAGE_S3_REGION", "us-east-1"),
+			AccessKeyID:     getEnvStorage("STORAGE_S3_ACCESS_KEY_ID", ""),
+			SecretAccessKey: getEnvStorage("STORAGE_S3_SECRET_ACCESS_KEY", ""),
+			BaseURL:         getEnvStorage("STORAGE_S3_BASE_URL", ""),
+			Endpoint:        getEnvStorage("STORAGE_S3_ENDPOINT", ""),
+			ForcePathStyle:  getEnvStorage("STORAGE_S3_FORCE_PATH_STYLE", "false") == "true",
+			DisableSSL:      getEnvStorage("STORAGE_S3_DISABLE_SSL", "false") == "true",
+		},
+		Image: storage.ImageConfig{
+			Quality:     getEnvIntStorage("STORAGE_IMAGE_QUALITY", 90),
+			WebPQuality: getEnvIntStorage("STORAGE_IMAGE_WEBP_QUALITY", 80),
+			AVIFQuality: getEnvIntStorage("STORAGE_IMAGE_AVIF_QUALITY", 60),
+			Variants:    utils.GetEnvStringSlice("STORAGE_IMAGE_VARIANTS", nil),
+		},
+		Validation: storage.ValidationConfig{
+			MaxFileSize: getEnvInt64Storage("STORAGE_MAX_FILE_SIZE", 10*1024*1024), // 10MB
+		},
+		HTTP: storage.HTTPCacheConfig{
+			MaxAge:          getEnvIntStorage("STORAGE_CACHE_MAX_AGE", 3600),
+			ImmutableMaxAge: getEnvIntStorage("STORAGE_IMMUTABLE_MAX_AGE", 365*24*3600),
+			CDNURL:          getEnvStorage("STORAGE_CDN_URL", ""),
+			SignedURLs:      getEnvStorage("STORAGE_SIGNED_URLS", "false") == "true",
+			SignedURLTTL:    getEnvIntStorage("STORAGE_SIGNED_URL_TTL", 900),
+			Authorize:       getEnvStorage("STORAGE_AUTHORIZE_DOWNLOADS", "true") == "true",
+		},
+		Prefix: getEnvStorage("STORAGE_PREFIX", ""),
+		Lifecycle: storage.LifecycleConfig{
+			ExpireDays:   getEnvIntStorage("STORAGE_EXPIRE_DAYS", 0),
+			StorageClass: getEnvStorage("STORAGE_S3_STORAGE_CLASS", ""),
+			CacheControl: getEnvStorage("STORAGE_OBJECT_CACHE_CONTROL", ""),
+		},
+		Routes: utils.GetEnvStringSlice("STORAGE_ROUTES", nil),
+		Resumable: storage.ResumableConfig{
+			Enabled:         getEnvStorage("STORAGE_RESUMABLE_ENABLED", "true") == "true",
+			MaxSize:         getEnvInt64Storage("STORAGE_RESUMABLE_MAX_SIZE", 1024*1024*1024), // 1GB
+			ExpirationHours: getEnvIntStorage("STORAGE_RESUMABLE_EXPIRATION_HOURS", 24),
+			ChunkPrefix:     getEnvStorage("STORAGE_RESUMABLE_CHUNK_PREFIX", ".resumable"),
+		},
+		Presigned: storage.PresignedConfig{
+			Enabled:           getEnvStorage("STORAGE_PRESIGNED_ENABLED", "true") == "true",
+			ExpirationMinutes: getEnvIntStorage("STORAGE_PRESIGNED_EXPIRATION_MINUTES", 15),
+		},
+		Scan: storage.ScanConfig{
+			Driver:           getEnvStorage("STORAGE_SCAN_DRIVER", ""),
+			Address:          getEnvStorage("STORAGE_SCAN_ADDRESS", ""),
+			Policy:           getEnvStorage("STORAGE_SCAN_POLICY", "reject"),
+			QuarantinePrefix: getEnvStorage("STORAGE_SCAN_QUARANTINE_PREFIX", ".quarantine"),
+			FailOpen:         getEnvStorage("STORAGE_SCAN_FAIL_OPEN", "false") == "true",
+			TimeoutSeconds:   getEnvIntStorage("STORAGE_SCAN_TIMEOUT", 30),
+		},
+		QuotaBytes: getEnvInt64Storage("STORAGE_QUOTA_BYTES", 0),
+		Orphans: storage.OrphanConfig{
+			Enabled:       getEnvStorage("STORAGE_ORPHAN_ENABLED", "true") == "true",
+			Prefixes:      utils.GetEnvStringSlice("STORAGE_ORPHAN_PREFIXES", nil),
+			GraceHours:    getEnvIntStorage("STORAGE_ORPHAN_GRACE_HOURS", 24),
+			DryRun:        getEnvStorage("STORAGE_ORPHAN_DRY_RUN", "true") == "true",
+			ArchivePrefix: getEnvStorage("STORAGE_ORPHAN_ARCHIVE_PREFIX", ""),
+		},
+	}
+	cfg.Buckets = loadBucketConfigs(cfg)
+	return cfg
+}
+
+// loadBucketConfigs đọc các bucket trong STORAGE_BUCKETS, mỗi bucket cấu hình bằng STORAGE_BUCKET_<NAME>_*
+// (vd. bucket "acme-media" dùng STORAGE_BUCKET_ACME_MEDIA_S3_BUCKET). Giá trị thiếu lấy từ bucket mặc định.
+func loadBucketConfigs(defaults storage.Config) []storage.BucketConfig {
+	var buckets []storage.BucketConfig
+	for _, name := range utils.GetEnvStringSlice("STORAGE_BUCKETS", nil) {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		env := "STORAGE_BUCKET_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		buckets = append(buckets, storage.BucketConfig{
+			Name:   name,
+			Driver: getEnvStorage(env+"DRIVER", defaults.Driver),
+			Local: storage.LocalConfig{
+				BasePath:           getEnvStorage(env+"LOCAL_PATH", defaults.Local.BasePath),
+				BaseURL:            getEnvStorage(env+"LOCAL_URL", defaults.Local.BaseURL),
+				EncryptionKeys:     getEnvStorage(env+"LOCAL_ENCRYPTION_KEYS", defaults.Local.EncryptionKeys),
+				EncryptionKeysFile: getEnvStorage(env+"LOCAL_ENCRYPTION_KEYS_FILE", defaults.Local.EncryptionKeysFile),
+			},
+			S3: storage.S3Config{
+				Bucket:          getEnvStorage(env+"S3_BUCKET", ""),
+				Region:          getEnvStorage(env+"S3_REGION", defaults.S3.Region),
+				AccessKeyID:     getEnvStorage(env+"S3_ACCESS_KEY_ID", defaults.S3.AccessKeyID),
+				SecretAccessKey: getEnvStorage(env+"S3_SECRET_ACCESS_KEY", defaults.S3.SecretAccessKey),
+				BaseURL:         getEnvStorage(env+"S3_BASE_URL", ""),
+				Endpoint:        getEnvStorage(env+"S3_ENDPOINT", defaults.S3.Endpoint),
+				ForcePathStyle:  getEnvStorage(env+"S3_FORCE_PATH_STYLE", strconv.FormatBool(defaults.S3.ForcePathStyle)) == "true",
+				DisableSSL:      getEnvStorage(env+"S3_DISABLE_SSL", strconv.FormatBool(defaults.S3.DisableSSL)) == "true",
+			},
+			Prefix: getEnvStorage(env+"PREFIX", ""),
+			Lifecycle: storage.LifecycleConfig{
+				ExpireDays:   getEnvIntStorage(env+"EXPIRE_DAYS", 0),
+				StorageClass: getEnvStorage(env+"S3_STORAGE_CLASS", ""),
+				CacheControl: getEnvStorage(env+"OBJECT_CACHE_CONTROL", defaults.Lifecycle.CacheControl),
+			},
+		})
+	}
+	return buckets
+}
+
+// getEnvStorage lấy environment variable với default value
+func getEnvStorage(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvIntStorage lấy environment variable dạng int với default value
+func getEnvIntStorage(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.Atoi(value); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvInt64Storage lấy environment variable dạng int64 với default value
+func getEnvInt64Storage(key string, defaultValue int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		if intValue, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return intValue
+		}
+	}
+	return defaultValue
+}
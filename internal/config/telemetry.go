@@ -0,0 +1,27 @@
+package config
+
+import (
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// TelemetryConfig cấu hình feature usage telemetry
+type TelemetryConfig struct {
+	Enabled       bool          // Opt-out: TELEMETRY_ENABLED=false để tắt hoàn toàn
+	LokiURL       string        // Loki endpoint nhận report
+	Environment   string        // Môi trường (development, production...)
+	Version       string        // Version của ứng dụng
+	FlushInterval time.Duration // Chu kỳ gửi report
+}
+
+// LoadTelemetryConfig load telemetry config từ environment variables
+func LoadTelemetryConfig() *TelemetryConfig {
+	return &TelemetryConfig{
+		Enabled:       utils.GetEnvBool("TELEMETRY_ENABLED", true),
+		LokiURL:       utils.GetEnv("TELEMETRY_LOKI_URL", utils.GetEnv("LOKI_URL", "http://localhost:3100")),
+		Environment:   utils.GetEnv("APP_ENV", "production"),
+		Version:       utils.GetEnv("API_VERSION", ""),
+		FlushInterval: time.Duration(utils.GetEnvInt("TELEMETRY_FLUSH_INTERVAL_MINUTES", 60)) * time.Minute,
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// TracingConfig cấu hình OpenTelemetry tracing (HTTP, GORM, Redis, queue, outbound HTTP)
+type TracingConfig struct {
+	Enabled     bool
+	ServiceName string
+	Endpoint    string            // OTLP/HTTP collector, vd localhost:4318 hoặc https://otlp.example.com/v1/traces
+	Insecure    bool              // Gửi qua HTTP (collector nội bộ)
+	Headers     map[string]string // TRACING_HEADERS dạng key=value,key=value
+	SampleRatio float64
+	Environment string
+	Version     string
+}
+
+// LoadTracingConfig load tracing config từ environment variables
+func LoadTracingConfig() *TracingConfig {
+	sampleRatio, err := strconv.ParseFloat(utils.GetEnv("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil {
+		sampleRatio = -1 // Validate báo lỗi
+	}
+	headers := make(map[string]string)
+	for _, pair := range utils.GetEnvStringSlice("TRACING_HEADERS", nil) {
+		if key, value, ok := strings.Cut(pair, "="); ok {
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+
+	return &TracingConfig{
+		Enabled:     utils.GetEnvBool("TRACING_ENABLED", false),
+		ServiceName: utils.GetEnv("OTEL_SERVICE_NAME", "apicore"),
+		Endpoint:    utils.GetEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+		Insecure:    utils.GetEnvBool("TRACING_INSECURE", false),
+		Headers:     headers,
+		SampleRatio: sampleRatio,
+		Environment: utils.GetEnv("APP_ENV", "production"),
+		Version:     utils.GetEnv("API_VERSION", ""),
+	}
+}
+
+// Validate kiểm tra tracing config
+func (c *TracingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be a number between 0 and 1")
+	}
+	return nil
+}
+
+// ToTracingConfig chuyển sang tracing.Config
+func (c *TracingConfig) ToTracingConfig() tracing.Config {
+	return tracing.Config{
+		Enabled:     c.Enabled,
+		ServiceName: c.ServiceName,
+		Version:     c.Version,
+		Environment: c.Environment,
+		Endpoint:    c.Endpoint,
+		Insecure:    c.Insecure,
+		Headers:     c.Headers,
+		SampleRatio: c.SampleRatio,
+	}
+}
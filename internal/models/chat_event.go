@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ChatEventType loại event trong chat event log
+type ChatEventType string
+
+const (
+	ChatEventMessageSent                ChatEventType = "message.sent"
+	ChatEventMessageDeletedForEveryone  ChatEventType = "message.deleted_for_everyone"
+	ChatEventMessageRestoredForEveryone ChatEventType = "message.restored_for_everyone"
+	ChatEventMessageDeletedForUser      ChatEventType = "message.deleted_for_user"
+	ChatEventMessageRestoredForUser     ChatEventType = "message.restored_for_user"
+	ChatEventConversationCleared        ChatEventType = "conversation.cleared"
+	ChatEventConversationUncleared      ChatEventType = "conversation.uncleared"
+	ChatEventConversationDeleted        ChatEventType = "conversation.deleted"
+	ChatEventConversationRestored       ChatEventType = "conversation.restored"
+)
+
+// ChatEvent event append-only của chat, các bảng messages/message_deletions/conversation_participants là projection của log này
+type ChatEvent struct {
+	Sequence       int64                  `json:"sequence" gorm:"primaryKey;autoIncrement"`
+	ID             uuid.UUID              `json:"id" gorm:"type:uuid;uniqueIndex;not null"`
+	ConversationID uuid.UUID              `json:"conversation_id" gorm:"type:uuid;not null"`
+	MessageID      *uuid.UUID             `json:"message_id,omitempty" gorm:"type:uuid"`
+	EventType      ChatEventType          `json:"event_type" gorm:"type:varchar(50);not null"`
+	ActorID        uuid.UUID              `json:"actor_id" gorm:"type:uuid;not null"`
+	VisibleTo      *uuid.UUID             `json:"-" gorm:"type:uuid"`
+	Payload        map[string]interface{} `json:"payload" gorm:"type:jsonb;serializer:json"`
+	CreatedAt      time.Time              `json:"created_at"`
+}
+
+// TableName override tên bảng
+func (ChatEvent) TableName() string {
+	return "chat_events"
+}
@@ -15,6 +15,7 @@ type ConversationParticipant struct {
 	LastReadAt     *time.Time     `json:"last_read_at"`
 	JoinedAt       time.Time      `json:"joined_at" gorm:"autoCreateTime"`
 	LeftAt         *time.Time     `json:"left_at"`
+	ClearedAt      *time.Time     `json:"cleared_at"`
 	CreatedAt      time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt      time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// CronJobRun kết quả 1 lần chạy cron job (gồm các lần retry), dùng để xem lịch sử khi job lỗi
+type CronJobRun struct {
+	ID         int64     `json:"id" gorm:"primaryKey;autoIncrement"`
+	JobName    string    `json:"job_name" gorm:"type:varchar(100);not null;index:idx_cron_job_runs_job_name_started_at,priority:1"`
+	StartedAt  time.Time `json:"started_at" gorm:"not null;index:idx_cron_job_runs_job_name_started_at,priority:2;index"`
+	FinishedAt time.Time `json:"finished_at" gorm:"not null"`
+	DurationMs int64     `json:"duration_ms" gorm:"not null;default:0"`
+	Success    bool      `json:"success" gorm:"not null"`
+	Error      *string   `json:"error" gorm:"type:text"`
+	Retries    int       `json:"retries" gorm:"not null;default:0"` // Số lần retry trước khi thành công/bỏ cuộc
+	Host       string    `json:"host" gorm:"type:varchar(255);not null;default:''"`
+}
+
+// TableName override tên bảng
+func (CronJobRun) TableName() string {
+	return "cron_job_runs"
+}
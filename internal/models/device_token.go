@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Nền tảng của thiết bị nhận push notification
+const (
+	DevicePlatformAndroid = "android"
+	DevicePlatformIOS     = "ios"
+	DevicePlatformWeb     = "web"
+)
+
+// DeviceToken FCM registration token của một thiết bị, mỗi token thuộc đúng một user
+// (đăng nhập tài khoản khác trên cùng thiết bị thì token chuyển sang user mới)
+type DeviceToken struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Token     string    `json:"token" gorm:"type:varchar(512);uniqueIndex;not null"`
+	Platform  string    `json:"platform" gorm:"type:varchar(20);not null"`
+	LastSeen  time.Time `json:"last_seen" gorm:"not null"` // Lần cuối app đăng ký lại token (mở app)
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName override tên bảng
+func (DeviceToken) TableName() string {
+	return "device_tokens"
+}
@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Lý do email bị chặn gửi
+const (
+	SuppressionReasonHardBounce = "hard_bounce"
+	SuppressionReasonComplaint  = "complaint"
+	SuppressionReasonManual     = "manual"
+)
+
+// Nguồn ghi nhận suppression
+const (
+	SuppressionSourceSES      = "ses"
+	SuppressionSourceSendGrid = "sendgrid"
+	SuppressionSourceManual   = "manual"
+)
+
+// EmailSuppression email không được gửi tiếp (hard bounce, complaint...), email luôn lưu dạng lowercase
+type EmailSuppression struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Email     string    `json:"email" gorm:"type:varchar(255);uniqueIndex;not null"`
+	Reason    string    `json:"reason" gorm:"type:varchar(50);not null"`
+	Source    string    `json:"source" gorm:"type:varchar(50);not null"`
+	Details   *string   `json:"details" gorm:"type:text"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName override tên bảng
+func (EmailSuppression) TableName() string {
+	return "email_suppressions"
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"reflect"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// IDVersioner model implement để chọn phiên bản UUID cho primary key (utils.UUIDv4 hoặc utils.UUIDv7)
+type IDVersioner interface {
+	IDVersion() int
+}
+
+// IDConfig cấu hình sinh primary key UUID
+type IDConfig struct {
+	DefaultVersion int            // Version mặc định khi model không khai báo IDVersion (default: v4)
+	TableVersions  map[string]int // Override theo tên bảng, ưu tiên cao nhất
+}
+
+var uuidType = reflect.TypeOf(uuid.UUID{})
+
+// RegisterIDGenerator đăng ký callback sinh UUID cho primary key trước khi create.
+// UUIDv4 vẫn để database sinh qua DEFAULT gen_random_uuid() như cũ, chỉ UUIDv7 được sinh ở application.
+// ID đã được gán sẵn (khác uuid.Nil) luôn được giữ nguyên.
+func RegisterIDGenerator(db *gorm.DB, cfg IDConfig) error {
+	if cfg.DefaultVersion == 0 {
+		cfg.DefaultVersion = utils.UUIDv4
+	}
+
+	return db.Callback().Create().Before("gorm:create").Register("app:uuid_primary_key", func(tx *gorm.DB) {
+		if tx.Statement.Schema == nil {
+			return
+		}
+		field := tx.Statement.Schema.PrioritizedPrimaryField
+		if field == nil || field.FieldType != uuidType {
+			return
+		}
+
+		if cfg.versionFor(tx.Statement.Schema) != utils.UUIDv7 {
+			return
+		}
+
+		ctx := tx.Statement.Context
+		rv := tx.Statement.ReflectValue
+		assign := func(v reflect.Value) {
+			if _, zero := field.ValueOf(ctx, v); zero {
+				if err := field.Set(ctx, v, utils.NewUUIDv7()); err != nil {
+					_ = tx.AddError(err)
+				}
+			}
+		}
+
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Array:
+			for i := 0; i < rv.Len(); i++ {
+				assign(reflect.Indirect(rv.Index(i)))
+			}
+		case reflect.Struct:
+			assign(rv)
+		}
+	})
+}
+
+// versionFor chọn version: TableVersions > IDVersioner của model > DefaultVersion
+func (c IDConfig) versionFor(s *schema.Schema) int {
+	if version, ok := c.TableVersions[s.Table]; ok {
+		return version
+	}
+	if versioner, ok := reflect.New(s.ModelType).Interface().(IDVersioner); ok {
+		return versioner.IDVersion()
+	}
+	return c.DefaultVersion
+}
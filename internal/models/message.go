@@ -3,6 +3,8 @@ package model
 import (
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -22,19 +24,21 @@ const (
 
 // Message entity
 type Message struct {
-	ID             uuid.UUID              `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
-	ConversationID uuid.UUID              `json:"conversation_id" gorm:"type:uuid;not null"`
-	SenderID       uuid.UUID              `json:"sender_id" gorm:"type:uuid;not null"`
-	Content        string                 `json:"content" gorm:"type:text;not null"`
-	MessageType    MessageType            `json:"message_type" gorm:"type:message_type;default:'text'"`
-	ReplyToID      *uuid.UUID             `json:"reply_to_id" gorm:"type:uuid"`
-	FileURL        *string                `json:"file_url" gorm:"type:varchar(500)"`
-	FileName       *string                `json:"file_name" gorm:"type:varchar(255)"`
-	FileSize       *int64                 `json:"file_size" gorm:"type:bigint"`
-	Metadata       map[string]interface{} `json:"metadata" gorm:"type:jsonb"`
-	CreatedAt      time.Time              `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt      time.Time              `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt      gorm.DeletedAt         `json:"-" gorm:"index"`
+	ID                   uuid.UUID              `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	ConversationID       uuid.UUID              `json:"conversation_id" gorm:"type:uuid;not null"`
+	SenderID             uuid.UUID              `json:"sender_id" gorm:"type:uuid;not null"`
+	Content              string                 `json:"content" gorm:"type:text;not null"`
+	MessageType          MessageType            `json:"message_type" gorm:"type:message_type;default:'text'"`
+	ReplyToID            *uuid.UUID             `json:"reply_to_id" gorm:"type:uuid"`
+	FileURL              *string                `json:"file_url" gorm:"type:varchar(500)"`
+	FileName             *string                `json:"file_name" gorm:"type:varchar(255)"`
+	FileSize             *int64                 `json:"file_size" gorm:"type:bigint"`
+	Metadata             map[string]interface{} `json:"metadata" gorm:"type:jsonb;serializer:json"`
+	DeletedForEveryoneAt *time.Time             `json:"deleted_for_everyone_at"` // Tombstone: xóa cho mọi người
+	DeletedForEveryoneBy *uuid.UUID             `json:"deleted_for_everyone_by" gorm:"type:uuid"`
+	CreatedAt            time.Time              `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt            time.Time              `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt         `json:"-" gorm:"index"`
 
 	// Relations
 	Conversation *Conversation `json:"conversation,omitempty" gorm:"foreignKey:ConversationID"`
@@ -42,7 +46,36 @@ type Message struct {
 	ReplyTo      *Message      `json:"reply_to,omitempty" gorm:"foreignKey:ReplyToID"`
 }
 
+// IDVersion dùng UUIDv7 vì bảng insert nhiều và luôn truy vấn theo thứ tự thời gian
+func (Message) IDVersion() int {
+	return utils.UUIDv7
+}
+
 // TableName override tên bảng
 func (Message) TableName() string {
 	return "messages"
 }
+
+// IsDeletedForEveryone kiểm tra tin nhắn đã bị xóa cho mọi người chưa
+func (m *Message) IsDeletedForEveryone() bool {
+	return m.DeletedForEveryoneAt != nil
+}
+
+// AfterFind tombstone tin nhắn ngay khi đọc từ database (kể cả qua Preload),
+// không đường đọc nào trả về nội dung tin nhắn đã bị xóa cho mọi người
+func (m *Message) AfterFind(tx *gorm.DB) error {
+	m.Tombstone()
+	return nil
+}
+
+// Tombstone xóa nội dung tin nhắn đã bị xóa cho mọi người trước khi trả về client
+func (m *Message) Tombstone() {
+	if !m.IsDeletedForEveryone() {
+		return
+	}
+	m.Content = ""
+	m.FileURL = nil
+	m.FileName = nil
+	m.FileSize = nil
+	m.Metadata = nil
+}
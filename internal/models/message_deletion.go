@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// MessageDeletion tombstone đánh dấu user đã xóa tin nhắn cho riêng mình
+type MessageDeletion struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	MessageID uuid.UUID `json:"message_id" gorm:"type:uuid;not null"`
+	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// IDVersion tombstone được insert liên tục, dùng UUIDv7 để index primary key tăng dần
+func (MessageDeletion) IDVersion() int {
+	return utils.UUIDv7
+}
+
+// TableName override tên bảng
+func (MessageDeletion) TableName() string {
+	return "message_deletions"
+}
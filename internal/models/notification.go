@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationTypeGeneral type khi push không có data["type"]
+const NotificationTypeGeneral = "general"
+
+// Notification thông báo trong app (notification center), lưu mỗi khi gửi push tới user
+// để client hiển thị inbox kể cả khi push không tới được thiết bị
+type Notification struct {
+	ID        uuid.UUID         `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID    uuid.UUID         `json:"user_id" gorm:"type:uuid;not null;index"`
+	Type      string            `json:"type" gorm:"type:varchar(50);not null"`
+	Title     string            `json:"title" gorm:"type:varchar(255);not null"`
+	Body      string            `json:"body" gorm:"type:text"`
+	Data      map[string]string `json:"data,omitempty" gorm:"type:jsonb;serializer:json"` // Data của push (deep_link, action...)
+	ReadAt    *time.Time        `json:"read_at"`
+	CreatedAt time.Time         `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName override tên bảng
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage message chờ publish sang queue backend, ghi cùng transaction với dữ liệu nghiệp vụ
+type OutboxMessage struct {
+	ID          uuid.UUID     `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Queue       string        `json:"queue" gorm:"type:varchar(255);not null"`
+	Message     queue.Message `json:"message" gorm:"type:jsonb;serializer:json;not null"`
+	Attempts    int           `json:"attempts" gorm:"not null;default:0"`
+	LastError   *string       `json:"last_error" gorm:"type:text"`
+	AvailableAt time.Time     `json:"available_at" gorm:"not null"`
+	PublishedAt *time.Time    `json:"published_at"`
+	CreatedAt   time.Time     `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName override tên bảng
+func (OutboxMessage) TableName() string {
+	return "outbox_messages"
+}
@@ -17,6 +17,20 @@ type Role struct {
 	UpdatedAt   time.Time    `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
+// SystemRoles role được seed và dùng trong code (role mặc định của user, ROUTES_ADMIN_ROLES...),
+// không được xóa hoặc đổi tên
+var SystemRoles = []string{"admin", "moderator", "user"}
+
+// IsSystem role hệ thống
+func (r Role) IsSystem() bool {
+	for _, name := range SystemRoles {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // TableName chỉ định tên bảng
 func (Role) TableName() string {
 	return "roles"
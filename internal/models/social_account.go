@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SocialAccount liên kết user với tài khoản của OAuth2 provider (google, github...)
+type SocialAccount struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
+	Provider       string    `json:"provider" gorm:"type:varchar(50);not null"`
+	ProviderUserID string    `json:"provider_user_id" gorm:"type:varchar(255);not null"`
+	Email          *string   `json:"email" gorm:"type:varchar(255)"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// Relations
+	User *User `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// TableName override tên bảng
+func (SocialAccount) TableName() string {
+	return "social_accounts"
+}
@@ -0,0 +1,68 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trạng thái của component trên status page
+const (
+	ComponentStatusOperational = "operational"
+	ComponentStatusDegraded    = "degraded" // Vẫn hoạt động nhưng chậm hơn ngưỡng
+	ComponentStatusDown        = "down"
+)
+
+// Mức ảnh hưởng của sự cố
+const (
+	IncidentImpactMinor    = "minor"
+	IncidentImpactMajor    = "major"
+	IncidentImpactCritical = "critical"
+)
+
+// Trạng thái xử lý sự cố
+const (
+	IncidentStatusInvestigating = "investigating"
+	IncidentStatusIdentified    = "identified"
+	IncidentStatusMonitoring    = "monitoring"
+	IncidentStatusResolved      = "resolved"
+)
+
+// StatusCheck kết quả 1 lần health check của 1 component
+type StatusCheck struct {
+	ID        int64     `json:"-" gorm:"primaryKey;autoIncrement"`
+	Component string    `json:"component" gorm:"type:varchar(50);not null;index:idx_status_checks_component_checked_at,priority:1"`
+	Status    string    `json:"status" gorm:"type:varchar(20);not null"`
+	LatencyMs int64     `json:"latency_ms" gorm:"not null;default:0"`
+	Error     *string   `json:"-" gorm:"type:text"` // Chỉ dùng nội bộ, không công khai trên status page
+	CheckedAt time.Time `json:"checked_at" gorm:"not null;index:idx_status_checks_component_checked_at,priority:2;index"`
+}
+
+// TableName override tên bảng
+func (StatusCheck) TableName() string {
+	return "status_checks"
+}
+
+// StatusIncident sự cố hiển thị trên status page
+type StatusIncident struct {
+	ID         uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Title      string     `json:"title" gorm:"type:varchar(255);not null"`
+	Message    string     `json:"message" gorm:"type:text;not null;default:''"`
+	Impact     string     `json:"impact" gorm:"type:varchar(20);not null"`
+	Status     string     `json:"status" gorm:"type:varchar(20);not null"`
+	Components []string   `json:"components" gorm:"type:jsonb;serializer:json"`
+	StartedAt  time.Time  `json:"started_at" gorm:"not null;index"`
+	ResolvedAt *time.Time `json:"resolved_at" gorm:"index"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName override tên bảng
+func (StatusIncident) TableName() string {
+	return "status_incidents"
+}
+
+// IsResolved sự cố đã kết thúc
+func (i *StatusIncident) IsResolved() bool {
+	return i.ResolvedAt != nil
+}
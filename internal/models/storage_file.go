@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// StorageFile owner của file lưu qua StorageManager, dùng kiểm tra quyền tải file không gắn vào model
+type StorageFile struct {
+	Key       string    `json:"key" gorm:"type:varchar(500);primaryKey"`
+	OwnerID   string    `json:"owner_id" gorm:"type:varchar(100);not null;index"`
+	Size      int64     `json:"size" gorm:"not null;default:0"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName override tên bảng
+func (StorageFile) TableName() string {
+	return "storage_files"
+}
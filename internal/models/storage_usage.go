@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// StorageUsage dung lượng storage owner đã dùng, QuotaBytes nil: dùng quota mặc định (STORAGE_QUOTA_BYTES)
+type StorageUsage struct {
+	OwnerID    string    `json:"owner_id" gorm:"type:varchar(100);primaryKey"`
+	UsedBytes  int64     `json:"used_bytes" gorm:"not null;default:0"`
+	FileCount  int64     `json:"file_count" gorm:"not null;default:0"`
+	QuotaBytes *int64    `json:"quota_bytes"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// TableName override tên bảng
+func (StorageUsage) TableName() string {
+	return "storage_usage"
+}
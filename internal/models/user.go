@@ -12,16 +12,20 @@ type User struct {
 	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
 	Name            string         `json:"name" gorm:"type:varchar(255);not null"`
 	Email           string         `json:"email" gorm:"type:varchar(255);uniqueIndex;not null"`
-	Password        string         `json:"-" gorm:"type:varchar(255)"` // Không trả về trong JSON
+	Password        string         `json:"-" gorm:"type:varchar(512)"` // Không trả về trong JSON
 	Avatar          *string        `json:"avatar" gorm:"type:varchar(500)"`
 	RoleID          *uuid.UUID     `json:"role_id" gorm:"type:uuid"`
 	Role            *Role          `json:"role,omitempty" gorm:"foreignKey:RoleID"`
 	EmailVerifiedAt *time.Time     `json:"email_verified_at"`
 	IsActive        bool           `json:"is_active" gorm:"default:true"`
 	LastLoginAt     *time.Time     `json:"last_login_at"`
+	TokenVersion    int            `json:"-" gorm:"not null;default:0"` // Tăng khi logout all / đổi password để thu hồi token cũ
 	CreatedAt       time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt       time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"` // Soft delete
+
+	// Trạng thái suppression của email (chỉ load ở user detail để support tra cứu)
+	EmailSuppression *EmailSuppression `json:"email_suppression,omitempty" gorm:"-"`
 }
 
 // TableName override tên bảng
@@ -0,0 +1,307 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Entry message chờ ghi vào outbox
+type Entry struct {
+	Queue   string
+	Message *queue.Message
+}
+
+// Recorder model implement để message được ghi vào outbox trong cùng transaction khi model được create/update/delete
+// (callback đăng ký bằng RegisterCallbacks). Nhúng Events vào model là đủ:
+//
+//	type Order struct {
+//		outbox.Events `gorm:"-"`
+//		...
+//	}
+//
+//	order.Record("emails", message)
+//	db.Transaction(func(tx *gorm.DB) error { return tx.Create(&order).Error })
+type Recorder interface {
+	// PullOutboxEntries trả về message đã ghi nhận và xóa chúng khỏi model
+	PullOutboxEntries() []Entry
+}
+
+// Events ghi nhận message của model, implement Recorder
+type Events struct {
+	entries []Entry
+}
+
+// Record ghi nhận message, được ghi vào outbox khi model được lưu
+func (e *Events) Record(queueName string, message *queue.Message) {
+	e.entries = append(e.entries, Entry{Queue: queueName, Message: message})
+}
+
+// PullOutboxEntries implement Recorder
+func (e *Events) PullOutboxEntries() []Entry {
+	entries := e.entries
+	e.entries = nil
+	return entries
+}
+
+// Enqueue ghi message vào outbox bằng tx (transaction của thao tác nghiệp vụ): message chỉ được publish khi transaction
+// commit, broker tạm thời không kết nối được cũng không mất message. Message.Delay được tính từ lúc enqueue.
+func Enqueue(tx *gorm.DB, queueName string, message *queue.Message) error {
+	return enqueue(tx, []Entry{{Queue: queueName, Message: message}})
+}
+
+func enqueue(tx *gorm.DB, entries []Entry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]model.OutboxMessage, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Queue == "" || entry.Message == nil {
+			return errors.New("outbox: queue and message are required")
+		}
+		message := *entry.Message
+		// Worker tiếp tục trace và request ID của request đã ghi message (header traceparent, x-request-id)
+		if ctx := tx.Statement.Context; ctx != nil {
+			message.Headers = maps.Clone(message.Headers)
+			queue.InjectTraceContext(ctx, &message)
+			queue.InjectRequestID(ctx, &message)
+		}
+		if message.ID == "" {
+			message.ID = uuid.NewString()
+		}
+		if message.Timestamp.IsZero() {
+			message.Timestamp = now
+		}
+		// Delay được thực hiện bởi publisher (available_at), mọi backend đều hỗ trợ kể cả Kafka
+		availableAt := now.Add(message.Delay)
+		message.Delay = 0
+
+		rows = append(rows, model.OutboxMessage{
+			Queue:       entry.Queue,
+			Message:     message,
+			AvailableAt: availableAt,
+		})
+	}
+
+	if err := tx.Create(&rows).Error; err != nil {
+		return fmt.Errorf("failed to write outbox messages: %w", err)
+	}
+	return nil
+}
+
+// RegisterCallbacks đăng ký callback ghi message của model implement Recorder vào outbox sau khi create/update/delete,
+// cùng transaction với câu lệnh: lỗi ghi outbox làm transaction rollback.
+func RegisterCallbacks(db *gorm.DB) error {
+	if err := db.Callback().Create().After("gorm:create").Register("outbox:record", recordCallback); err != nil {
+		return err
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("outbox:record", recordCallback); err != nil {
+		return err
+	}
+	return db.Callback().Delete().After("gorm:delete").Register("outbox:record", recordCallback)
+}
+
+func recordCallback(tx *gorm.DB) {
+	if tx.Error != nil || !tx.Statement.ReflectValue.IsValid() {
+		return
+	}
+
+	var entries []Entry
+	collect := func(value reflect.Value) {
+		if value.Kind() != reflect.Ptr && value.CanAddr() {
+			value = value.Addr()
+		}
+		if recorder, ok := value.Interface().(Recorder); ok {
+			entries = append(entries, recorder.PullOutboxEntries()...)
+		}
+	}
+
+	value := tx.Statement.ReflectValue
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			collect(reflect.Indirect(value.Index(i)))
+		}
+	case reflect.Struct:
+		collect(value)
+	}
+
+	// Session mới trên cùng connection (transaction) của câu lệnh
+	if err := enqueue(tx.Session(&gorm.Session{NewDB: true}), entries); err != nil {
+		_ = tx.AddError(err)
+	}
+}
+
+// Options cấu hình publisher
+type Options struct {
+	PollInterval time.Duration // Chu kỳ quét outbox
+	BatchSize    int           // Số message tối đa mỗi lần quét
+	Retry        queue.Backoff // Delay trước khi publish lại message lỗi, theo số lần lỗi
+	Retention    time.Duration // Giữ message đã publish bao lâu trước khi xóa, 0: không xóa
+}
+
+// DefaultOptions options mặc định
+func DefaultOptions() Options {
+	return Options{
+		PollInterval: time.Second,
+		BatchSize:    100,
+		Retry:        queue.Backoff{Curve: queue.BackoffExponential, Delay: time.Second, Max: 5 * time.Minute, Jitter: true},
+		Retention:    7 * 24 * time.Hour,
+	}
+}
+
+// pruneInterval chu kỳ xóa message đã publish quá Retention
+const pruneInterval = time.Hour
+
+// Publisher đẩy message trong outbox sang queue backend. Nhiều instance chạy song song được: mỗi batch được khóa
+// bằng SELECT ... FOR UPDATE SKIP LOCKED. Message có thể bị publish lại nếu instance dừng giữa lúc push và commit
+// (at-least-once), handler cần idempotent theo Message.ID.
+type Publisher struct {
+	db      *gorm.DB
+	manager queue.QueueManager
+	options Options
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewPublisher tạo publisher
+func NewPublisher(db *gorm.DB, manager queue.QueueManager, options Options) *Publisher {
+	defaults := DefaultOptions()
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaults.PollInterval
+	}
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaults.BatchSize
+	}
+	if options.Retry.Delay <= 0 {
+		options.Retry = defaults.Retry
+	}
+	return &Publisher{db: db, manager: manager, options: options}
+}
+
+// Start chạy publisher trong background tới khi Stop hoặc ctx bị hủy
+func (p *Publisher) Start(ctx context.Context) {
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.done = make(chan struct{})
+	go p.run(ctx)
+}
+
+// Stop dừng publisher, chờ batch đang publish xong
+func (p *Publisher) Stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	<-p.done
+}
+
+func (p *Publisher) run(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.options.PollInterval)
+	defer ticker.Stop()
+	lastPrune := time.Time{}
+
+	for {
+		// Batch đầy: còn message, quét tiếp không chờ
+		for {
+			published, err := p.PublishPending(context.WithoutCancel(ctx))
+			if err != nil {
+				logger.Warnf("Outbox: failed to publish messages: %v", err)
+				break
+			}
+			if published < p.options.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+
+		if p.options.Retention > 0 && time.Since(lastPrune) >= pruneInterval {
+			lastPrune = time.Now()
+			if removed, err := p.Prune(ctx); err != nil {
+				logger.Warnf("Outbox: failed to prune published messages: %v", err)
+			} else if removed > 0 {
+				logger.Infof("Outbox: pruned %d published message(s)", removed)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PublishPending publish 1 batch message đến hạn, trả về số message đã publish. Message lỗi được hẹn publish lại
+// theo Retry và dừng batch (broker thường lỗi cho mọi message).
+func (p *Publisher) PublishPending(ctx context.Context) (int, error) {
+	published := 0
+	err := p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		var rows []model.OutboxMessage
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND available_at <= ?", now).
+			Order("available_at, created_at").
+			Limit(p.options.BatchSize).
+			Find(&rows).Error; err != nil {
+			return fmt.Errorf("failed to load outbox messages: %w", err)
+		}
+
+		for i := range rows {
+			row := &rows[i]
+			// Trùng UniqueKey: message tương đương đã có trong queue, coi như đã publish
+			if err := p.push(ctx, row); err != nil && !errors.Is(err, queue.ErrDuplicateMessage) {
+				lastError := err.Error()
+				return tx.Model(row).Updates(map[string]interface{}{
+					"attempts":     row.Attempts + 1,
+					"last_error":   lastError,
+					"available_at": now.Add(p.options.Retry.Duration(row.Attempts + 1)),
+				}).Error
+			}
+			if err := tx.Model(row).Update("published_at", time.Now()).Error; err != nil {
+				return fmt.Errorf("failed to mark outbox message %s published: %w", row.ID, err)
+			}
+			published++
+		}
+		return nil
+	})
+	return published, err
+}
+
+func (p *Publisher) push(ctx context.Context, row *model.OutboxMessage) error {
+	q, err := p.manager.GetQueue(row.Queue)
+	if err != nil {
+		return err
+	}
+	message := row.Message
+	return q.Push(ctx, &message)
+}
+
+// Prune xóa message đã publish quá Retention
+func (p *Publisher) Prune(ctx context.Context) (int64, error) {
+	result := p.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", time.Now().Add(-p.options.Retention)).
+		Delete(&model.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
+
+// Pending số message chưa publish
+func (p *Publisher) Pending(ctx context.Context) (int64, error) {
+	var count int64
+	err := p.db.WithContext(ctx).Model(&model.OutboxMessage{}).Where("published_at IS NULL").Count(&count).Error
+	return count, err
+}
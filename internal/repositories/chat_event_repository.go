@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ChatEventRepository interface
+type ChatEventRepository interface {
+	Repository[model.ChatEvent]
+
+	FindForUserSince(ctx context.Context, userID uuid.UUID, since int64, limit int) ([]model.ChatEvent, error)
+	LatestSequence(ctx context.Context) (int64, error)
+}
+
+// chatEventRepository implementation
+type chatEventRepository struct {
+	*BaseRepository[model.ChatEvent]
+}
+
+// NewChatEventRepository tạo chat event repository mới
+func NewChatEventRepository(db *gorm.DB) ChatEventRepository {
+	return &chatEventRepository{
+		BaseRepository: NewBaseRepository[model.ChatEvent](db, false),
+	}
+}
+
+// FindForUserSince lấy các event có sequence > since thuộc conversations user tham gia,
+// bỏ các event chỉ dành cho user khác (xóa/khôi phục phía họ)
+func (r *chatEventRepository) FindForUserSince(ctx context.Context, userID uuid.UUID, since int64, limit int) ([]model.ChatEvent, error) {
+	var events []model.ChatEvent
+	err := r.DB().WithContext(ctx).
+		Where("sequence > ?", since).
+		Where(`conversation_id IN (SELECT cp.conversation_id FROM conversation_participants cp
+			WHERE cp.user_id = ? AND cp.deleted_at IS NULL)`, userID).
+		Where("visible_to IS NULL OR visible_to = ?", userID).
+		Order("sequence ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// LatestSequence sequence lớn nhất hiện tại, 0 nếu chưa có event
+func (r *chatEventRepository) LatestSequence(ctx context.Context) (int64, error) {
+	var sequence int64
+	err := r.DB().WithContext(ctx).
+		Model(&model.ChatEvent{}).
+		Select("COALESCE(MAX(sequence), 0)").
+		Scan(&sequence).Error
+	return sequence, err
+}
@@ -4,10 +4,12 @@ import (
 	"context"
 	"time"
 
-	model "api-core/internal/models"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/clock"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ConversationRepository interface
@@ -17,6 +19,8 @@ type ConversationRepository interface {
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.Conversation, error)
 	FindDirectConversation(ctx context.Context, user1ID, user2ID uuid.UUID) (*model.Conversation, error)
 	FindByIDWithParticipants(ctx context.Context, id uuid.UUID) (*model.Conversation, error)
+	FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Conversation, error)
+	Restore(ctx context.Context, id uuid.UUID) error
 }
 
 // ConversationParticipantRepository interface
@@ -27,15 +31,33 @@ type ConversationParticipantRepository interface {
 	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.ConversationParticipant, error)
 	FindByConversationAndUser(ctx context.Context, conversationID, userID uuid.UUID) (*model.ConversationParticipant, error)
 	UpdateLastReadAt(ctx context.Context, conversationID, userID uuid.UUID) error
+	UpdateClearedAt(ctx context.Context, conversationID, userID uuid.UUID, clearedAt *time.Time) error
 }
 
 // MessageRepository interface
 type MessageRepository interface {
 	Repository[model.Message]
 
-	FindByConversationID(ctx context.Context, conversationID uuid.UUID, page, perPage int) ([]model.Message, int64, error)
-	FindLatestByConversationID(ctx context.Context, conversationID uuid.UUID, limit int) ([]model.Message, error)
+	FindByConversationID(ctx context.Context, conversationID, userID uuid.UUID, page, perPage int) ([]model.Message, int64, error)
+	FindLatestByConversationID(ctx context.Context, conversationID, userID uuid.UUID, limit int) ([]model.Message, error)
 	FindUnreadCount(ctx context.Context, conversationID, userID uuid.UUID) (int64, error)
+	DeleteForUser(ctx context.Context, messageID, userID uuid.UUID) error
+	RestoreForUser(ctx context.Context, messageID, userID uuid.UUID) (bool, error)
+	IsDeletedForUser(ctx context.Context, messageID, userID uuid.UUID) (bool, error)
+	DeleteForEveryone(ctx context.Context, messageID, deletedBy uuid.UUID) error
+	RestoreForEveryone(ctx context.Context, messageID uuid.UUID) error
+}
+
+// visibleMessagesFor scope lọc các tin nhắn user còn thấy:
+// bỏ tin nhắn user đã xóa cho riêng mình và tin nhắn trước thời điểm user xóa conversation
+func visibleMessagesFor(userID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("NOT EXISTS (SELECT 1 FROM message_deletions md WHERE md.message_id = messages.id AND md.user_id = ?)", userID).
+			Where(`NOT EXISTS (SELECT 1 FROM conversation_participants cp
+				WHERE cp.conversation_id = messages.conversation_id AND cp.user_id = ?
+				AND cp.deleted_at IS NULL AND cp.cleared_at IS NOT NULL AND messages.created_at <= cp.cleared_at)`, userID)
+	}
 }
 
 // conversationRepository implementation
@@ -56,9 +78,11 @@ func (r *conversationRepository) FindByUserID(ctx context.Context, userID uuid.U
 	err := r.DB().WithContext(ctx).
 		Joins("INNER JOIN conversation_participants ON conversations.id = conversation_participants.conversation_id").
 		Where("conversation_participants.user_id = ? AND conversation_participants.deleted_at IS NULL", userID).
+		// Conversation user đã xóa cho riêng mình chỉ hiện lại khi có tin nhắn mới
+		Where("conversation_participants.cleared_at IS NULL OR conversations.updated_at > conversation_participants.cleared_at").
 		Preload("Participants.User").
 		Preload("Messages", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at DESC").Limit(1)
+			return db.Scopes(visibleMessagesFor(userID)).Order("created_at DESC").Limit(1)
 		}).
 		Find(&conversations).Error
 	return conversations, err
@@ -96,6 +120,30 @@ func (r *conversationRepository) FindByIDWithParticipants(ctx context.Context, i
 	return &conversation, nil
 }
 
+// FindDeletedByID tìm conversation đã bị xóa (soft delete) kèm participants
+func (r *conversationRepository) FindDeletedByID(ctx context.Context, id uuid.UUID) (*model.Conversation, error) {
+	var conversation model.Conversation
+	err := r.DB().WithContext(ctx).
+		Unscoped().
+		Preload("Participants").
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		First(&conversation).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+// Restore khôi phục conversation đã bị xóa
+func (r *conversationRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	return r.DB().WithContext(ctx).
+		Unscoped().
+		Model(&model.Conversation{}).
+		Where("id = ?", id).
+		Update("deleted_at", nil).Error
+}
+
 // conversationParticipantRepository implementation
 type conversationParticipantRepository struct {
 	*BaseRepository[model.ConversationParticipant]
@@ -125,12 +173,19 @@ func (r *conversationParticipantRepository) FindByConversationAndUser(ctx contex
 
 // UpdateLastReadAt cập nhật thời gian đọc tin nhắn cuối
 func (r *conversationParticipantRepository) UpdateLastReadAt(ctx context.Context, conversationID, userID uuid.UUID) error {
-	now := time.Now()
+	now := clock.FromContext(ctx).Now()
 	return r.UpdateWhere(ctx, "conversation_id = ? AND user_id = ?", map[string]interface{}{
 		"last_read_at": now,
 	}, conversationID, userID)
 }
 
+// UpdateClearedAt cập nhật thời điểm user xóa conversation cho riêng mình (nil = khôi phục)
+func (r *conversationParticipantRepository) UpdateClearedAt(ctx context.Context, conversationID, userID uuid.UUID, clearedAt *time.Time) error {
+	return r.UpdateWhere(ctx, "conversation_id = ? AND user_id = ?", map[string]interface{}{
+		"cleared_at": clearedAt,
+	}, conversationID, userID)
+}
+
 // messageRepository implementation
 type messageRepository struct {
 	*BaseRepository[model.Message]
@@ -144,7 +199,8 @@ func NewMessageRepository(db *gorm.DB) MessageRepository {
 }
 
 // FindByConversationID tìm messages của conversation với pagination
-func (r *messageRepository) FindByConversationID(ctx context.Context, conversationID uuid.UUID, page, perPage int) ([]model.Message, int64, error) {
+// Tin nhắn user đã xóa cho riêng mình bị loại bỏ, tin nhắn xóa cho mọi người vẫn trả về dạng tombstone
+func (r *messageRepository) FindByConversationID(ctx context.Context, conversationID, userID uuid.UUID, page, perPage int) ([]model.Message, int64, error) {
 	var messages []model.Message
 	var total int64
 
@@ -163,6 +219,7 @@ func (r *messageRepository) FindByConversationID(ctx context.Context, conversati
 	if err := r.DB().WithContext(ctx).
 		Model(&model.Message{}).
 		Where("conversation_id = ?", conversationID).
+		Scopes(visibleMessagesFor(userID)).
 		Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
@@ -171,6 +228,7 @@ func (r *messageRepository) FindByConversationID(ctx context.Context, conversati
 	offset := (page - 1) * perPage
 	err := r.DB().WithContext(ctx).
 		Where("conversation_id = ?", conversationID).
+		Scopes(visibleMessagesFor(userID)).
 		Preload("Sender").
 		Preload("ReplyTo").
 		Order("created_at DESC").
@@ -187,7 +245,7 @@ func (r *messageRepository) FindByConversationID(ctx context.Context, conversati
 }
 
 // FindLatestByConversationID tìm tin nhắn mới nhất của conversation
-func (r *messageRepository) FindLatestByConversationID(ctx context.Context, conversationID uuid.UUID, limit int) ([]model.Message, error) {
+func (r *messageRepository) FindLatestByConversationID(ctx context.Context, conversationID, userID uuid.UUID, limit int) ([]model.Message, error) {
 	if limit < 1 {
 		limit = 10
 	}
@@ -198,6 +256,7 @@ func (r *messageRepository) FindLatestByConversationID(ctx context.Context, conv
 	var messages []model.Message
 	err := r.DB().WithContext(ctx).
 		Where("conversation_id = ?", conversationID).
+		Scopes(visibleMessagesFor(userID)).
 		Preload("Sender").
 		Order("created_at DESC").
 		Limit(limit).
@@ -221,17 +280,20 @@ func (r *messageRepository) FindUnreadCount(ctx context.Context, conversationID,
 			// Nếu chưa có participant, đếm tất cả messages
 			err := r.DB().WithContext(ctx).
 				Model(&model.Message{}).
-				Where("conversation_id = ?", conversationID).
+				Where("conversation_id = ? AND deleted_for_everyone_at IS NULL", conversationID).
+				Scopes(visibleMessagesFor(userID)).
 				Count(&count).Error
 			return count, err
 		}
 		return 0, err
 	}
 
-	// Đếm messages sau last_read_at
+	// Đếm messages sau last_read_at, bỏ qua tin nhắn đã bị xóa
 	query := r.DB().WithContext(ctx).
 		Model(&model.Message{}).
-		Where("conversation_id = ? AND sender_id != ?", conversationID, userID)
+		Where("conversation_id = ? AND sender_id != ?", conversationID, userID).
+		Where("deleted_for_everyone_at IS NULL").
+		Scopes(visibleMessagesFor(userID))
 
 	if participant.LastReadAt != nil {
 		query = query.Where("created_at > ?", participant.LastReadAt)
@@ -240,3 +302,49 @@ func (r *messageRepository) FindUnreadCount(ctx context.Context, conversationID,
 	err = query.Count(&count).Error
 	return count, err
 }
+
+// DeleteForUser tạo tombstone xóa tin nhắn cho riêng user (idempotent)
+func (r *messageRepository) DeleteForUser(ctx context.Context, messageID, userID uuid.UUID) error {
+	deletion := model.MessageDeletion{
+		MessageID: messageID,
+		UserID:    userID,
+	}
+	return r.DB().WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deletion).Error
+}
+
+// RestoreForUser xóa tombstone của user, trả về false nếu tin nhắn chưa bị xóa
+func (r *messageRepository) RestoreForUser(ctx context.Context, messageID, userID uuid.UUID) (bool, error) {
+	result := r.DB().WithContext(ctx).
+		Where("message_id = ? AND user_id = ?", messageID, userID).
+		Delete(&model.MessageDeletion{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// IsDeletedForUser kiểm tra user đã xóa tin nhắn cho riêng mình chưa
+func (r *messageRepository) IsDeletedForUser(ctx context.Context, messageID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.DB().WithContext(ctx).
+		Model(&model.MessageDeletion{}).
+		Where("message_id = ? AND user_id = ?", messageID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteForEveryone đánh dấu tin nhắn bị xóa cho mọi người, nội dung vẫn được giữ để có thể khôi phục
+func (r *messageRepository) DeleteForEveryone(ctx context.Context, messageID, deletedBy uuid.UUID) error {
+	now := clock.FromContext(ctx).Now()
+	return r.UpdateWhere(ctx, "id = ?", map[string]interface{}{
+		"deleted_for_everyone_at": now,
+		"deleted_for_everyone_by": deletedBy,
+	}, messageID)
+}
+
+// RestoreForEveryone khôi phục tin nhắn đã bị xóa cho mọi người
+func (r *messageRepository) RestoreForEveryone(ctx context.Context, messageID uuid.UUID) error {
+	return r.UpdateWhere(ctx, "id = ?", map[string]interface{}{
+		"deleted_for_everyone_at": nil,
+		"deleted_for_everyone_by": nil,
+	}, messageID)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+
+	"gorm.io/gorm"
+)
+
+// CronJobRunFilter điều kiện lọc lịch sử chạy cron job, giá trị rỗng: không lọc
+type CronJobRunFilter struct {
+	JobName string
+	Success *bool
+	From    time.Time // started_at >= From
+	To      time.Time // started_at < To
+}
+
+// CronJobRunRepository interface lưu và truy vấn lịch sử chạy cron job, implement cron.ResultRecorder
+type CronJobRunRepository interface {
+	RecordResult(ctx context.Context, result cron.JobResult) error
+	FindRuns(ctx context.Context, filter CronJobRunFilter, page, perPage int) ([]model.CronJobRun, int64, error)
+	FindByID(ctx context.Context, id int64) (*model.CronJobRun, error)
+	LatestRuns(ctx context.Context) ([]model.CronJobRun, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// cronJobRunRepository implementation
+type cronJobRunRepository struct {
+	db *gorm.DB
+}
+
+// NewCronJobRunRepository tạo cron job run repository mới
+func NewCronJobRunRepository(db *gorm.DB) CronJobRunRepository {
+	return &cronJobRunRepository{db: db}
+}
+
+// RecordResult lưu kết quả 1 lần chạy job
+func (r *cronJobRunRepository) RecordResult(ctx context.Context, result cron.JobResult) error {
+	run := model.CronJobRun{
+		JobName:    result.JobName,
+		StartedAt:  result.StartTime.UTC(),
+		FinishedAt: result.EndTime.UTC(),
+		DurationMs: result.Duration.Milliseconds(),
+		Success:    result.Success,
+		Retries:    result.RetryCount,
+		Host:       result.Host,
+	}
+	if result.Error != "" {
+		run.Error = &result.Error
+	}
+	return r.db.WithContext(ctx).Create(&run).Error
+}
+
+// FindRuns lịch sử chạy theo filter, mới nhất trước
+func (r *cronJobRunRepository) FindRuns(ctx context.Context, filter CronJobRunFilter, page, perPage int) ([]model.CronJobRun, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	query := r.db.WithContext(ctx).Model(&model.CronJobRun{})
+	if filter.JobName != "" {
+		query = query.Where("job_name = ?", filter.JobName)
+	}
+	if filter.Success != nil {
+		query = query.Where("success = ?", *filter.Success)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("started_at >= ?", filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("started_at < ?", filter.To.UTC())
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var runs []model.CronJobRun
+	err := query.
+		Order("started_at DESC, id DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&runs).Error
+	return runs, total, err
+}
+
+// FindByID 1 lần chạy, gorm.ErrRecordNotFound nếu không có
+func (r *cronJobRunRepository) FindByID(ctx context.Context, id int64) (*model.CronJobRun, error) {
+	var run model.CronJobRun
+	if err := r.db.WithContext(ctx).First(&run, id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+// LatestRuns lần chạy gần nhất của mỗi job
+func (r *cronJobRunRepository) LatestRuns(ctx context.Context) ([]model.CronJobRun, error) {
+	var runs []model.CronJobRun
+	latest := r.db.Model(&model.CronJobRun{}).
+		Select("MAX(id)").
+		Group("job_name")
+	err := r.db.WithContext(ctx).
+		Where("id IN (?)", latest).
+		Order("job_name").
+		Find(&runs).Error
+	return runs, err
+}
+
+// PruneBefore xóa lịch sử chạy cũ hơn thời gian lưu, trả về số dòng đã xóa
+func (r *cronJobRunRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("started_at < ?", before.UTC()).Delete(&model.CronJobRun{})
+	return result.RowsAffected, result.Error
+}
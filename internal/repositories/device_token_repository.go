@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeviceTokenRepository interface
+type DeviceTokenRepository interface {
+	Repository[model.DeviceToken]
+
+	Register(ctx context.Context, device *model.DeviceToken) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.DeviceToken, error)
+	DeleteByToken(ctx context.Context, userID uuid.UUID, token string) (bool, error)
+	DeleteByTokens(ctx context.Context, tokens []string) ([]model.DeviceToken, error)
+}
+
+// deviceTokenRepository implementation
+type deviceTokenRepository struct {
+	*BaseRepository[model.DeviceToken]
+}
+
+// NewDeviceTokenRepository tạo device token repository mới
+func NewDeviceTokenRepository(db *gorm.DB) DeviceTokenRepository {
+	return &deviceTokenRepository{
+		BaseRepository: NewBaseRepository[model.DeviceToken](db, false),
+	}
+}
+
+// Register lưu token của thiết bị, token đã có thì chuyển sang user hiện tại và cập nhật platform, last_seen
+func (r *deviceTokenRepository) Register(ctx context.Context, device *model.DeviceToken) error {
+	return r.DB().WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token"}},
+			DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "last_seen", "updated_at"}),
+		}).
+		Create(device).Error
+}
+
+// FindByUserID tìm tất cả thiết bị của user, thiết bị dùng gần nhất trước
+func (r *deviceTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.DeviceToken, error) {
+	var devices []model.DeviceToken
+	err := r.DB().WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("last_seen DESC").
+		Find(&devices).Error
+	return devices, err
+}
+
+// DeleteByToken gỡ token khỏi user, false nếu user không có token này
+func (r *deviceTokenRepository) DeleteByToken(ctx context.Context, userID uuid.UUID, token string) (bool, error) {
+	result := r.DB().WithContext(ctx).
+		Where("user_id = ? AND token = ?", userID, token).
+		Delete(&model.DeviceToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByTokens xóa các token (của bất kỳ user nào), trả về thiết bị đã xóa
+func (r *deviceTokenRepository) DeleteByTokens(ctx context.Context, tokens []string) ([]model.DeviceToken, error) {
+	var devices []model.DeviceToken
+	if len(tokens) == 0 {
+		return devices, nil
+	}
+
+	err := r.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("token IN ?", tokens).Find(&devices).Error; err != nil {
+			return err
+		}
+		if len(devices) == 0 {
+			return nil
+		}
+		return tx.Where("token IN ?", tokens).Delete(&model.DeviceToken{}).Error
+	})
+	return devices, err
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EmailSuppressionRepository interface
+type EmailSuppressionRepository interface {
+	Repository[model.EmailSuppression]
+
+	FindByEmail(ctx context.Context, email string) (*model.EmailSuppression, error)
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+	Suppress(ctx context.Context, suppression *model.EmailSuppression) error
+	DeleteByEmail(ctx context.Context, email string) error
+}
+
+// emailSuppressionRepository implementation
+type emailSuppressionRepository struct {
+	*BaseRepository[model.EmailSuppression]
+}
+
+// NewEmailSuppressionRepository tạo email suppression repository mới
+func NewEmailSuppressionRepository(db *gorm.DB) EmailSuppressionRepository {
+	return &emailSuppressionRepository{
+		BaseRepository: NewBaseRepository[model.EmailSuppression](db, false),
+	}
+}
+
+// FindByEmail tìm suppression theo email
+func (r *emailSuppressionRepository) FindByEmail(ctx context.Context, email string) (*model.EmailSuppression, error) {
+	return r.FirstWhere(ctx, "email = ?", normalizeEmail(email))
+}
+
+// IsSuppressed kiểm tra email có bị chặn gửi không
+func (r *emailSuppressionRepository) IsSuppressed(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.DB().WithContext(ctx).
+		Model(&model.EmailSuppression{}).
+		Where("email = ?", normalizeEmail(email)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// Suppress thêm email vào suppression list, nếu đã có thì cập nhật lý do mới nhất
+func (r *emailSuppressionRepository) Suppress(ctx context.Context, suppression *model.EmailSuppression) error {
+	suppression.Email = normalizeEmail(suppression.Email)
+	return r.DB().WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "email"}},
+			DoUpdates: clause.AssignmentColumns([]string{"reason", "source", "details", "updated_at"}),
+		}).
+		Create(suppression).Error
+}
+
+// DeleteByEmail gỡ email khỏi suppression list
+func (r *emailSuppressionRepository) DeleteByEmail(ctx context.Context, email string) error {
+	return r.DeleteWhere(ctx, "email = ?", normalizeEmail(email))
+}
+
+// normalizeEmail chuẩn hóa email để so sánh
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fileReference cột lưu key/URL file của một model
+type fileReference struct {
+	table  string
+	column string
+}
+
+// fileReferences các cột tham chiếu file trong storage: thêm model mới lưu file thì khai báo ở đây
+// để job cleanup-orphan-files không xóa file của model đó
+var fileReferences = []fileReference{
+	{table: "users", column: "avatar"},
+	{table: "messages", column: "file_url"},
+}
+
+// FileReferenceRepository interface
+type FileReferenceRepository interface {
+	// Referenced trả về các giá trị trong values đang được model tham chiếu (dùng làm storage.ReferenceChecker)
+	Referenced(ctx context.Context, values []string) (map[string]bool, error)
+	// IsAvatar file là avatar của user chưa bị xóa (values: key và URL của file)
+	IsAvatar(ctx context.Context, values []string) (bool, error)
+	// AttachmentAccess file có đính kèm trong message không, và userID có đang là thành viên conversation của message đó không
+	AttachmentAccess(ctx context.Context, values []string, userID uuid.UUID) (attached bool, allowed bool, err error)
+}
+
+// fileReferenceRepository implementation
+type fileReferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewFileReferenceRepository tạo file reference repository mới
+func NewFileReferenceRepository(db *gorm.DB) FileReferenceRepository {
+	return &fileReferenceRepository{db: db}
+}
+
+// Referenced query thẳng bảng (không qua model) để bản ghi đã soft delete vẫn giữ file, khôi phục được
+func (r *fileReferenceRepository) Referenced(ctx context.Context, values []string) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+	if len(values) == 0 {
+		return referenced, nil
+	}
+
+	for _, ref := range fileReferences {
+		var found []string
+		if err := r.db.WithContext(ctx).
+			Table(ref.table).
+			Where(ref.column+" IN ?", values).
+			Distinct().
+			Pluck(ref.column, &found).Error; err != nil {
+			return nil, err
+		}
+		for _, value := range found {
+			referenced[value] = true
+		}
+	}
+	return referenced, nil
+}
+
+// IsAvatar file là avatar của user chưa bị xóa
+func (r *fileReferenceRepository) IsAvatar(ctx context.Context, values []string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("users").
+		Where("avatar IN ? AND deleted_at IS NULL", values).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// AttachmentAccess message đã xóa (soft delete) không còn cấp quyền tải file
+func (r *fileReferenceRepository) AttachmentAccess(ctx context.Context, values []string, userID uuid.UUID) (bool, bool, error) {
+	var conversationIDs []string
+	if err := r.db.WithContext(ctx).
+		Table("messages").
+		Where("file_url IN ? AND deleted_at IS NULL", values).
+		Distinct().
+		Pluck("conversation_id", &conversationIDs).Error; err != nil {
+		return false, false, err
+	}
+	if len(conversationIDs) == 0 {
+		// Chỉ còn message đã xóa: vẫn là file đính kèm, không ai tải được
+		var deleted int64
+		err := r.db.WithContext(ctx).Table("messages").Where("file_url IN ?", values).Count(&deleted).Error
+		return deleted > 0, false, err
+	}
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("conversation_participants").
+		Where("conversation_id IN ? AND user_id = ? AND left_at IS NULL AND deleted_at IS NULL", conversationIDs, userID).
+		Count(&count).Error
+	return true, count > 0, err
+}
@@ -3,7 +3,7 @@ package repository
 import (
 	"context"
 
-	model "api-core/internal/models"
+	model "github.com/anhnq996/go-api-core/internal/models"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
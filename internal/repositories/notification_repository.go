@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// NotificationRepository interface
+type NotificationRepository interface {
+	Repository[model.Notification]
+
+	FindByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, perPage int) ([]model.Notification, int64, error)
+	CountUnread(ctx context.Context, userID uuid.UUID) (int64, error)
+	MarkRead(ctx context.Context, userID, id uuid.UUID, at time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) (int64, error)
+}
+
+// notificationRepository implementation
+type notificationRepository struct {
+	*BaseRepository[model.Notification]
+}
+
+// NewNotificationRepository tạo notification repository mới
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		BaseRepository: NewBaseRepository[model.Notification](db, false),
+	}
+}
+
+// FindByUserID inbox của user, mới nhất trước
+func (r *notificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, unreadOnly bool, page, perPage int) ([]model.Notification, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+	if perPage > 100 {
+		perPage = 100
+	}
+
+	query := r.DB().WithContext(ctx).Model(&model.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []model.Notification
+	err := query.
+		Order("created_at DESC, id DESC").
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+// CountUnread số thông báo chưa đọc của user
+func (r *notificationRepository) CountUnread(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.DB().WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkRead đánh dấu đã đọc (giữ read_at cũ nếu đã đọc), false nếu user không có thông báo này
+func (r *notificationRepository) MarkRead(ctx context.Context, userID, id uuid.UUID, at time.Time) (bool, error) {
+	result := r.DB().WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", at))
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkAllRead đánh dấu mọi thông báo chưa đọc của user là đã đọc, trả về số thông báo được cập nhật
+func (r *notificationRepository) MarkAllRead(ctx context.Context, userID uuid.UUID, at time.Time) (int64, error) {
+	result := r.DB().WithContext(ctx).
+		Model(&model.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Update("read_at", at)
+	return result.RowsAffected, result.Error
+}
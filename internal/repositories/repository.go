@@ -6,8 +6,8 @@ import (
 	"fmt"
 	"strings"
 
-	"api-core/pkg/actionEvent"
-	"api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -307,8 +307,11 @@ func (r *BaseRepository[T]) extractEntityID(entity *T) string {
 
 // extractUserIDFromContext extracts user ID from context
 func (r *BaseRepository[T]) extractUserIDFromContext(ctx context.Context) string {
-	// Use JWT package's function to get user ID from context
-	return jwt.GetUserIDFromContext(ctx)
+	// Principal do JWT middleware gắn vào context; job/worker không có principal thì không ghi user
+	if principal, ok := jwt.PrincipalFromContext(ctx); ok {
+		return principal.UserID
+	}
+	return ""
 }
 
 // convertEntityToMap converts entity to map[string]interface{}
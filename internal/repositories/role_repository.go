@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RoleRepository interface
+type RoleRepository interface {
+	Repository[model.Role]
+
+	FindAllWithPermissions(ctx context.Context) ([]model.Role, error)
+	FindByIDWithPermissions(ctx context.Context, id uuid.UUID) (*model.Role, error)
+	FindByName(ctx context.Context, name string) (*model.Role, error)
+	SyncPermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error
+	CountUsers(ctx context.Context, roleID uuid.UUID) (int64, error)
+	FindUserIDs(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error)
+}
+
+// PermissionRepository interface
+type PermissionRepository interface {
+	Repository[model.Permission]
+
+	FindAllOrdered(ctx context.Context) ([]model.Permission, error)
+	FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Permission, error)
+}
+
+// roleRepository implementation
+type roleRepository struct {
+	*BaseRepository[model.Role]
+}
+
+// NewRoleRepository tạo role repository mới
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{
+		BaseRepository: NewBaseRepository[model.Role](db, true),
+	}
+}
+
+// FindAllWithPermissions lấy tất cả roles kèm permissions
+func (r *roleRepository) FindAllWithPermissions(ctx context.Context) ([]model.Role, error) {
+	var roles []model.Role
+	err := r.DB().WithContext(ctx).
+		Preload("Permissions").
+		Order("name ASC").
+		Find(&roles).Error
+	return roles, err
+}
+
+// FindByIDWithPermissions tìm role theo ID kèm permissions
+func (r *roleRepository) FindByIDWithPermissions(ctx context.Context, id uuid.UUID) (*model.Role, error) {
+	var role model.Role
+	err := r.DB().WithContext(ctx).
+		Preload("Permissions").
+		Where("id = ?", id).
+		First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// FindByName tìm role theo name
+func (r *roleRepository) FindByName(ctx context.Context, name string) (*model.Role, error) {
+	return r.FirstWhere(ctx, "name = ?", name)
+}
+
+// SyncPermissions thay toàn bộ permissions của role bằng danh sách mới
+func (r *roleRepository) SyncPermissions(ctx context.Context, roleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	return r.DB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&model.RoleHasPermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissionIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]model.RoleHasPermission, len(permissionIDs))
+		for i, permissionID := range permissionIDs {
+			rows[i] = model.RoleHasPermission{RoleID: roleID, PermissionID: permissionID}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// CountUsers đếm số user đang có role
+func (r *roleRepository) CountUsers(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.DB().WithContext(ctx).
+		Model(&model.User{}).
+		Where("role_id = ?", roleID).
+		Count(&count).Error
+	return count, err
+}
+
+// FindUserIDs lấy ID các user đang có role (dùng để invalidate permission cache)
+func (r *roleRepository) FindUserIDs(ctx context.Context, roleID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.DB().WithContext(ctx).
+		Model(&model.User{}).
+		Where("role_id = ?", roleID).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// permissionRepository implementation
+type permissionRepository struct {
+	*BaseRepository[model.Permission]
+}
+
+// NewPermissionRepository tạo permission repository mới
+func NewPermissionRepository(db *gorm.DB) PermissionRepository {
+	return &permissionRepository{
+		BaseRepository: NewBaseRepository[model.Permission](db, false),
+	}
+}
+
+// FindAllOrdered lấy tất cả permissions, sắp xếp theo module
+func (r *permissionRepository) FindAllOrdered(ctx context.Context) ([]model.Permission, error) {
+	var permissions []model.Permission
+	err := r.DB().WithContext(ctx).
+		Order("module ASC, name ASC").
+		Find(&permissions).Error
+	return permissions, err
+}
+
+// FindByIDs tìm permissions theo danh sách ID
+func (r *permissionRepository) FindByIDs(ctx context.Context, ids []uuid.UUID) ([]model.Permission, error) {
+	var permissions []model.Permission
+	err := r.DB().WithContext(ctx).
+		Where("id IN ?", ids).
+		Find(&permissions).Error
+	return permissions, err
+}
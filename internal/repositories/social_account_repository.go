@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SocialAccountRepository interface
+type SocialAccountRepository interface {
+	Repository[model.SocialAccount]
+
+	FindByProvider(ctx context.Context, provider, providerUserID string) (*model.SocialAccount, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.SocialAccount, error)
+}
+
+// socialAccountRepository implementation
+type socialAccountRepository struct {
+	*BaseRepository[model.SocialAccount]
+}
+
+// NewSocialAccountRepository tạo social account repository mới
+func NewSocialAccountRepository(db *gorm.DB) SocialAccountRepository {
+	return &socialAccountRepository{
+		BaseRepository: NewBaseRepository[model.SocialAccount](db, true),
+	}
+}
+
+// FindByProvider tìm social account theo provider và id của user bên provider
+func (r *socialAccountRepository) FindByProvider(ctx context.Context, provider, providerUserID string) (*model.SocialAccount, error) {
+	return r.FirstWhere(ctx, "provider = ? AND provider_user_id = ?", provider, providerUserID)
+}
+
+// FindByUserID tìm tất cả social accounts đã liên kết với user
+func (r *socialAccountRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.SocialAccount, error) {
+	return r.FindWhere(ctx, "user_id = ?", userID)
+}
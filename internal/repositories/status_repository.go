@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// StatusDailySummary tổng hợp health check của 1 component trong 1 ngày (UTC)
+type StatusDailySummary struct {
+	Component    string
+	Day          time.Time
+	Total        int64
+	Operational  int64
+	Degraded     int64
+	Down         int64
+	AvgLatencyMs float64
+}
+
+// StatusCheckRepository interface lưu và tổng hợp kết quả health check
+type StatusCheckRepository interface {
+	RecordChecks(ctx context.Context, checks []model.StatusCheck) error
+	LatestChecks(ctx context.Context) ([]model.StatusCheck, error)
+	FirstCheckedAt(ctx context.Context, component string) (time.Time, error)
+	DailySummaries(ctx context.Context, since time.Time) ([]StatusDailySummary, error)
+	PruneBefore(ctx context.Context, before time.Time) error
+}
+
+// statusCheckRepository implementation
+type statusCheckRepository struct {
+	db *gorm.DB
+}
+
+// NewStatusCheckRepository tạo status check repository mới
+func NewStatusCheckRepository(db *gorm.DB) StatusCheckRepository {
+	return &statusCheckRepository{db: db}
+}
+
+// RecordChecks lưu kết quả 1 lượt health check
+func (r *statusCheckRepository) RecordChecks(ctx context.Context, checks []model.StatusCheck) error {
+	if len(checks) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&checks).Error
+}
+
+// LatestChecks kết quả mới nhất của mỗi component
+func (r *statusCheckRepository) LatestChecks(ctx context.Context) ([]model.StatusCheck, error) {
+	var checks []model.StatusCheck
+	latest := r.db.Model(&model.StatusCheck{}).
+		Select("component, MAX(checked_at)").
+		Group("component")
+	err := r.db.WithContext(ctx).
+		Where("(component, checked_at) IN (?)", latest).
+		Order("component").
+		Find(&checks).Error
+	return checks, err
+}
+
+// FirstCheckedAt thời điểm của kết quả cũ nhất còn lưu của component, zero khi chưa có kết quả
+func (r *statusCheckRepository) FirstCheckedAt(ctx context.Context, component string) (time.Time, error) {
+	var check model.StatusCheck
+	err := r.db.WithContext(ctx).
+		Where("component = ?", component).
+		Order("checked_at").
+		First(&check).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return time.Time{}, nil
+	}
+	return check.CheckedAt, err
+}
+
+// DailySummaries đếm kết quả theo component và ngày kể từ since
+func (r *statusCheckRepository) DailySummaries(ctx context.Context, since time.Time) ([]StatusDailySummary, error) {
+	var rows []struct {
+		Component    string
+		Day          string
+		Total        int64
+		Operational  int64
+		Degraded     int64
+		Down         int64
+		AvgLatencyMs float64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.StatusCheck{}).
+		Select(`component, DATE(checked_at) AS day, COUNT(*) AS total,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS operational,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS degraded,
+			SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) AS down,
+			AVG(latency_ms) AS avg_latency_ms`,
+			model.ComponentStatusOperational, model.ComponentStatusDegraded, model.ComponentStatusDown).
+		Where("checked_at >= ?", since).
+		Group("component, DATE(checked_at)").
+		Order("day, component").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]StatusDailySummary, 0, len(rows))
+	for _, row := range rows {
+		// Postgres trả về DATE dạng timestamp, SQLite dạng "2006-01-02"
+		day := row.Day
+		if len(day) > len(time.DateOnly) {
+			day = day[:len(time.DateOnly)]
+		}
+		parsed, err := time.Parse(time.DateOnly, day)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, StatusDailySummary{
+			Component:    row.Component,
+			Day:          parsed,
+			Total:        row.Total,
+			Operational:  row.Operational,
+			Degraded:     row.Degraded,
+			Down:         row.Down,
+			AvgLatencyMs: row.AvgLatencyMs,
+		})
+	}
+	return summaries, nil
+}
+
+// PruneBefore xóa kết quả cũ hơn thời gian lưu lịch sử
+func (r *statusCheckRepository) PruneBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("checked_at < ?", before).Delete(&model.StatusCheck{}).Error
+}
+
+// StatusIncidentRepository interface
+type StatusIncidentRepository interface {
+	Repository[model.StatusIncident]
+
+	FindVisible(ctx context.Context, since time.Time) ([]model.StatusIncident, error)
+}
+
+// statusIncidentRepository implementation
+type statusIncidentRepository struct {
+	*BaseRepository[model.StatusIncident]
+}
+
+// NewStatusIncidentRepository tạo status incident repository mới
+func NewStatusIncidentRepository(db *gorm.DB) StatusIncidentRepository {
+	return &statusIncidentRepository{
+		BaseRepository: NewBaseRepository[model.StatusIncident](db, true),
+	}
+}
+
+// FindVisible sự cố chưa kết thúc hoặc kết thúc sau since, mới nhất trước
+func (r *statusIncidentRepository) FindVisible(ctx context.Context, since time.Time) ([]model.StatusIncident, error) {
+	var incidents []model.StatusIncident
+	err := r.DB().WithContext(ctx).
+		Where("resolved_at IS NULL OR resolved_at >= ?", since).
+		Order("started_at DESC").
+		Find(&incidents).Error
+	return incidents, err
+}
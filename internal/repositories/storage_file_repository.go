@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StorageFileRepository interface, implement storage.FileOwnerStore để /storages kiểm tra owner của file
+type StorageFileRepository interface {
+	storage.FileOwnerStore
+}
+
+// storageFileRepository implementation
+type storageFileRepository struct {
+	*BaseRepository[model.StorageFile]
+}
+
+// NewStorageFileRepository tạo storage file repository mới
+func NewStorageFileRepository(db *gorm.DB) StorageFileRepository {
+	return &storageFileRepository{
+		BaseRepository: NewBaseRepository[model.StorageFile](db, false),
+	}
+}
+
+// SetOwner ghi owner của file, key đã có (ghi đè file) thì cập nhật owner mới
+func (r *storageFileRepository) SetOwner(ctx context.Context, key, owner string, size int64) error {
+	return r.DB().WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"owner_id", "size"}),
+		}).
+		Create(&model.StorageFile{Key: key, OwnerID: owner, Size: size}).Error
+}
+
+// Owner owner của file, rỗng nếu không có
+func (r *storageFileRepository) Owner(ctx context.Context, key string) (string, error) {
+	row, err := r.FirstWhere(ctx, "key = ?", key)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return row.OwnerID, nil
+}
+
+// DeleteOwner xóa owner khi file bị xóa
+func (r *storageFileRepository) DeleteOwner(ctx context.Context, key string) error {
+	return r.DB().WithContext(ctx).Where("key = ?", key).Delete(&model.StorageFile{}).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StorageUsageRepository interface, implement storage.UsageStore để StorageManager tính quota theo user
+type StorageUsageRepository interface {
+	storage.UsageStore
+}
+
+// storageUsageRepository implementation
+type storageUsageRepository struct {
+	*BaseRepository[model.StorageUsage]
+}
+
+// NewStorageUsageRepository tạo storage usage repository mới
+func NewStorageUsageRepository(db *gorm.DB) StorageUsageRepository {
+	return &storageUsageRepository{
+		BaseRepository: NewBaseRepository[model.StorageUsage](db, false),
+	}
+}
+
+// Reserve cộng dung lượng bằng một câu UPDATE có điều kiện quota nên các upload song song không vượt quota
+func (r *storageUsageRepository) Reserve(ctx context.Context, owner string, size, defaultQuota int64) error {
+	if err := r.ensure(ctx, owner); err != nil {
+		return err
+	}
+
+	result := r.DB().WithContext(ctx).
+		Model(&model.StorageUsage{}).
+		Where("owner_id = ?", owner).
+		Where("(COALESCE(quota_bytes, ?) <= 0 OR used_bytes + ? <= COALESCE(quota_bytes, ?))", defaultQuota, size, defaultQuota).
+		Updates(map[string]interface{}{
+			"used_bytes": gorm.Expr("used_bytes + ?", size),
+			"file_count": gorm.Expr("file_count + 1"),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return storage.ErrQuotaExceeded
+	}
+	return nil
+}
+
+// Release trừ dung lượng, không xuống dưới 0 (file upload trước khi bật quota không được tính)
+func (r *storageUsageRepository) Release(ctx context.Context, owner string, size int64) error {
+	return r.DB().WithContext(ctx).
+		Model(&model.StorageUsage{}).
+		Where("owner_id = ?", owner).
+		Updates(map[string]interface{}{
+			"used_bytes": gorm.Expr("CASE WHEN used_bytes > ? THEN used_bytes - ? ELSE 0 END", size, size),
+			"file_count": gorm.Expr("CASE WHEN file_count > 0 THEN file_count - 1 ELSE 0 END"),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// Usage dung lượng hiện tại, quota riêng của owner được ưu tiên hơn quota mặc định
+func (r *storageUsageRepository) Usage(ctx context.Context, owner string, defaultQuota int64) (*storage.Usage, error) {
+	usage := &storage.Usage{Owner: owner, QuotaBytes: defaultQuota}
+
+	row, err := r.FirstWhere(ctx, "owner_id = ?", owner)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return usage, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	usage.UsedBytes, usage.FileCount = row.UsedBytes, row.FileCount
+	if row.QuotaBytes != nil {
+		usage.QuotaBytes = *row.QuotaBytes
+	}
+	return usage, nil
+}
+
+// ensure tạo dòng usage của owner nếu chưa có
+func (r *storageUsageRepository) ensure(ctx context.Context, owner string) error {
+	return r.DB().WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.StorageUsage{OwnerID: owner}).Error
+}
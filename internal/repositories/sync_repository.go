@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Các loại entity trong delta sync, đồng bộ lần lượt theo thứ tự SyncEntities
+const (
+	SyncEntityProfile         = "profile"
+	SyncEntityFriendRequest   = "friend_request"
+	SyncEntityFriendship      = "friendship"
+	SyncEntityConversation    = "conversation"
+	SyncEntityParticipant     = "participant"
+	SyncEntityMessage         = "message"
+	SyncEntityMessageDeletion = "message_deletion"
+)
+
+// SyncEntities thứ tự đồng bộ: conversation trước message để client luôn có conversation khi nhận message
+var SyncEntities = []string{
+	SyncEntityProfile,
+	SyncEntityFriendRequest,
+	SyncEntityFriendship,
+	SyncEntityConversation,
+	SyncEntityParticipant,
+	SyncEntityMessage,
+	SyncEntityMessageDeletion,
+}
+
+// SyncKey vị trí trong luồng thay đổi của một entity, sắp xếp theo (changed_at, id)
+type SyncKey struct {
+	ChangedAt time.Time
+	ID        uuid.UUID // uuid.Nil: lấy các thay đổi có changed_at > ChangedAt
+}
+
+// SyncChange một bản ghi đã thay đổi (tạo, cập nhật hoặc xóa mềm)
+type SyncChange struct {
+	Entity    string      `json:"entity"`
+	ID        uuid.UUID   `json:"id"`
+	ChangedAt time.Time   `json:"changed_at"`
+	Deleted   bool        `json:"deleted"`
+	Data      interface{} `json:"data,omitempty"`
+}
+
+// SyncRepository interface đọc thay đổi của user qua nhiều bảng
+type SyncRepository interface {
+	FindChanges(ctx context.Context, entity string, userID uuid.UUID, after SyncKey, until time.Time, limit int) ([]SyncChange, error)
+}
+
+// syncRepository implementation
+type syncRepository struct {
+	db *gorm.DB
+}
+
+// NewSyncRepository tạo sync repository mới
+func NewSyncRepository(db *gorm.DB) SyncRepository {
+	return &syncRepository{db: db}
+}
+
+// syncSource mô tả cách đọc thay đổi của một entity
+type syncSource struct {
+	table     string
+	changedAt string // SQL expression thời điểm thay đổi, GREATEST bỏ qua deleted_at NULL
+	scope     func(q *gorm.DB, userID uuid.UUID) *gorm.DB
+	find      func(q *gorm.DB) ([]SyncChange, error)
+}
+
+var syncSources = map[string]syncSource{
+	SyncEntityProfile: {
+		table:     "users",
+		changedAt: "GREATEST(users.updated_at, users.deleted_at)",
+		scope: func(q *gorm.DB, userID uuid.UUID) *gorm.DB {
+			return q.Where("users.id = ?", userID).Preload("Role")
+		},
+		find: func(q *gorm.DB) ([]SyncChange, error) {
+			return findSyncChanges(q, func(u *model.User) SyncChange {
+				return softDeleteChange(SyncEntityProfile, u.ID, u.UpdatedAt, u.DeletedAt, u)
+			})
+		},
+	},
+	SyncEntityFriendRequest: {
+		table:     "friend_requests",
+		changedAt: "friend_requests.updated_at",
+		scope: func(q *gorm.DB, userID uuid.UUID) *gorm.DB {
+			return q.Where("friend_requests.sender_id = ? OR friend_requests.receiver_id = ?", userID, userID).
+				Preload("Sender").Preload("Receiver")
+		},
+		find: func(q *gorm.DB) ([]SyncChange, error) {
+			return findSyncChanges(q, func(fr *model.FriendRequest) SyncChange {
+				return SyncChange{Entity: SyncEntityFriendRequest, ID: fr.ID, ChangedAt: fr.UpdatedAt, Data: fr}
+			})
+		},
+	},
+	SyncEntityFriendship: {
+		table:     "friendships",
+		changedAt: "GREATEST(friendships.updated_at, friendships.deleted_at)",
+		scope: func(q *gorm.DB, userID uuid.UUID) *gorm.DB {
+			return q.Where("friendships.user_id = ?", userID).Preload("Friend")
+		},
+		find: func(q *gorm.DB) ([]SyncChange, error) {
+			return findSyncChanges(q, func(f *model.Friendship) SyncChange {
+				return softDeleteChange(SyncEntityFriendship, f.ID, f.UpdatedAt, f.DeletedAt, f)
+			})
+		},
+	},
+	SyncEntityConversation: {
+		table:     "conversations",
+		changedAt: "GREATEST(conversations.updated_at, conversations.deleted_at)",
+		scope: func(q *gorm.DB, userID uuid.UUID) *gorm.DB {
+			// Kể cả conversation user đã rời để client cập nhật trạng thái
+			return q.Where(`conversations.id IN (SELECT cp.conversation_id FROM conversation_participants cp
+				WHERE cp.user_id = ?)`, userID)
+		},
+		find: func(q *gorm.DB) ([]SyncChange, error) {
+			return findSyncChanges(q, func(c *model.Conversation) SyncChange {
+				return softDeleteChange(SyncEntityConversation, c.ID, c.UpdatedAt, c.DeletedAt, c)
+			})
+		},
+	},
+	SyncEntityParticipant: {
+		table:     "conversation_participants",
+		changedAt: "GREATEST(conversation_participants.updated_at, conversation_participants.deleted_at)",
+		scope: func(q *gorm.DB, userID uuid.UUID) *gorm.DB {
+			// Thành viên của conversations user đang tham gia + các bản ghi tham gia của chính user
+			return q.Where(`conversation_participants.user_id = ? OR conversation_participants.conversation_id IN
+				(SELECT cp.conversation_id FROM conversation_participants cp WHERE cp.user_id = ? AND cp.deleted_at IS NULL)`,
+				userID, userID).
+				Preload("User")
+		},
+		find: func(q *gorm.DB) ([]SyncChange, error) {
+			return findSyncChanges(q, func(p *model.ConversationParticipant) SyncChange {
+				return softDeleteChange(SyncEntityParticipant, p.ID, p.UpdatedAt, p.DeletedAt, p)
+			})
+		},
+	},
+	SyncEntityMessage: {
+		table:     "messages",
+		changedAt: "GREATEST(messages.updated_at, messages.deleted_at)",
+		scope: func(q *gorm.DB, userID uuid.UUID) *gorm.DB {
+			// Bỏ các tin nhắn user đã xóa cho riêng mình (client nhận qua message_deletion)
+			return q.Where(`messages.conversation_id IN (SELECT cp.conversation_id FROM conversation_participants cp
+				WHERE cp.user_id = ? AND cp.deleted_at IS NULL)`, userID).
+				Where(`NOT EXISTS (SELECT 1 FROM message_deletions md
+				WHERE md.message_id = messages.id AND md.user_id = ?)`, userID)
+		},
+		find: func(q *gorm.DB) ([]SyncChange, error) {
+			return findSyncChanges(q, func(m *model.Message) SyncChange {
+				change := softDeleteChange(SyncEntityMessage, m.ID, m.UpdatedAt, m.DeletedAt, m)
+				if change.Deleted {
+					change.Data = nil
+				}
+				return change
+			})
+		},
+	},
+	SyncEntityMessageDeletion: {
+		table:     "message_deletions",
+		changedAt: "message_deletions.created_at",
+		scope: func(q *gorm.DB, userID uuid.UUID) *gorm.DB {
+			return q.Where("message_deletions.user_id = ?", userID)
+		},
+		find: func(q *gorm.DB) ([]SyncChange, error) {
+			return findSyncChanges(q, func(d *model.MessageDeletion) SyncChange {
+				return SyncChange{Entity: SyncEntityMessageDeletion, ID: d.ID, ChangedAt: d.CreatedAt, Data: d}
+			})
+		},
+	},
+}
+
+// FindChanges lấy tối đa limit thay đổi của entity sau vị trí after và không muộn hơn until,
+// bao gồm cả bản ghi đã xóa mềm (Deleted = true)
+func (r *syncRepository) FindChanges(ctx context.Context, entity string, userID uuid.UUID, after SyncKey, until time.Time, limit int) ([]SyncChange, error) {
+	src, ok := syncSources[entity]
+	if !ok {
+		return nil, fmt.Errorf("unknown sync entity: %s", entity)
+	}
+
+	q := r.db.WithContext(ctx).Unscoped().
+		Table(src.table).
+		Where(src.changedAt+" <= ?", until)
+	if after.ID == uuid.Nil {
+		q = q.Where(src.changedAt+" > ?", after.ChangedAt)
+	} else {
+		q = q.Where("("+src.changedAt+", "+src.table+".id) > (?, ?)", after.ChangedAt, after.ID)
+	}
+
+	q = src.scope(q, userID).
+		Order(src.changedAt + " ASC").
+		Order(src.table + ".id ASC").
+		Limit(limit)
+
+	return src.find(q)
+}
+
+// findSyncChanges chạy query và chuyển từng bản ghi thành SyncChange
+func findSyncChanges[T any](q *gorm.DB, change func(*T) SyncChange) ([]SyncChange, error) {
+	var rows []T
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	changes := make([]SyncChange, len(rows))
+	for i := range rows {
+		changes[i] = change(&rows[i])
+	}
+	return changes, nil
+}
+
+// softDeleteChange changed_at là thời điểm muộn nhất giữa updated_at và deleted_at
+func softDeleteChange(entity string, id uuid.UUID, updatedAt time.Time, deletedAt gorm.DeletedAt, data interface{}) SyncChange {
+	changedAt := updatedAt
+	if deletedAt.Valid && deletedAt.Time.After(changedAt) {
+		changedAt = deletedAt.Time
+	}
+
+	return SyncChange{
+		Entity:    entity,
+		ID:        id,
+		ChangedAt: changedAt,
+		Deleted:   deletedAt.Valid,
+		Data:      data,
+	}
+}
@@ -2,12 +2,13 @@ package repository
 
 import (
 	"context"
-	"time"
 
-	model "api-core/internal/models"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/clock"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // UserRepository interface extends base repository với custom methods
@@ -25,6 +26,7 @@ type UserRepository interface {
 	GetUserWithRole(ctx context.Context, id uuid.UUID) (*model.User, error)
 	GetUserPermissions(ctx context.Context, roleID uuid.UUID) ([]string, error)
 	UpdateLastLogin(ctx context.Context, userID uuid.UUID) error
+	IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
 }
 
 // userRepository implementation
@@ -175,8 +177,25 @@ func (r *userRepository) GetUserPermissions(ctx context.Context, roleID uuid.UUI
 
 // UpdateLastLogin cập nhật thời gian login cuối
 func (r *userRepository) UpdateLastLogin(ctx context.Context, userID uuid.UUID) error {
-	now := time.Now()
+	now := clock.FromContext(ctx).Now()
 	return r.UpdateWhere(ctx, "id = ?", map[string]interface{}{
 		"last_login_at": now,
 	}, userID)
 }
+
+// IncrementTokenVersion tăng token_version (thu hồi mọi token đã cấp) và trả về version mới.
+// Dùng UpdateColumn để không đổi updated_at.
+func (r *userRepository) IncrementTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	var user model.User
+	result := r.DB().WithContext(ctx).Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "token_version"}}}).
+		Where("id = ?", userID).
+		UpdateColumn("token_version", gorm.Expr("token_version + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, gorm.ErrRecordNotFound
+	}
+	return user.TokenVersion, nil
+}
@@ -0,0 +1,44 @@
+package routes
+
+import (
+	"expvar"
+	"net"
+	"runtime"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	middlewarePkg "github.com/anhnq996/go-api-core/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// DebugPermission permission cần để gọi /debug/*
+const DebugPermission = "debug.profile"
+
+// RegisterDebugRoutes mount net/http/pprof (/debug/pprof/*) và expvar (/debug/vars) trong admin group,
+// yêu cầu permission debug.profile; allowed không rỗng thì chỉ IP thuộc allowed được gọi (kiểm tra trước JWT)
+func RegisterDebugRoutes(r chi.Router, c *Controllers, allowed []*net.IPNet, trustProxy bool) {
+	publishRuntimeVars()
+
+	r.Group(func(r chi.Router) {
+		r.Use(middlewarePkg.IPAllowlist(allowed, trustProxy))
+
+		c.Group(r, GroupAdmin, func(r chi.Router) {
+			// Profile là binary, CPU profile/trace giữ request nhiều giây: không log body
+			r.Use(logger.WithVerbosity(logger.VerbosityBasic))
+			r.Use(c.Permissions.Require(DebugPermission))
+
+			r.Mount("/debug", middleware.Profiler())
+		})
+	})
+}
+
+// publishRuntimeVars thêm số goroutine và Go version vào /debug/vars (cạnh cmdline, memstats mặc định của expvar)
+func publishRuntimeVars() {
+	if expvar.Get("goroutines") == nil {
+		expvar.Publish("goroutines", expvar.Func(func() any { return runtime.NumGoroutine() }))
+	}
+	if expvar.Get("go_version") == nil {
+		expvar.Publish("go_version", expvar.Func(func() any { return runtime.Version() }))
+	}
+}
@@ -0,0 +1,88 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	middlewarePkg "github.com/anhnq996/go-api-core/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RouteGroup nhóm route dùng chung một middleware stack
+type RouteGroup string
+
+const (
+	GroupPublic        RouteGroup = "public"        // Không cần đăng nhập (login, register, status page, jwks)
+	GroupAuthenticated RouteGroup = "authenticated" // Cần access token
+	GroupAdmin         RouteGroup = "admin"         // Cần access token + permission quản trị (và role nếu ROUTES_ADMIN_ROLES được set)
+	GroupInternal      RouteGroup = "internal"      // Service-to-service, xác thực bằng credential riêng (webhook token, client credentials)
+)
+
+// GroupPolicy middleware stack của một route group
+type GroupPolicy struct {
+	RequireAuth bool             // Áp dụng JWT middleware (kèm blacklist)
+	Roles       []string         // Yêu cầu một trong các role (rỗng: chỉ dùng permission của từng route)
+	RateLimit   int              // Số request tối đa trong RateWindow cho mỗi user/IP, counter riêng theo group (0: không giới hạn)
+	RateWindow  time.Duration    // Cửa sổ rate limit
+	Verbosity   logger.Verbosity // Mức request log của group
+}
+
+// Policies policy của tất cả route groups
+type Policies map[RouteGroup]GroupPolicy
+
+// NewPolicies tạo policies từ config tập trung
+func NewPolicies(cfg *config.RoutesConfig) Policies {
+	policy := func(group config.RouteGroupConfig, requireAuth bool) GroupPolicy {
+		return GroupPolicy{
+			RequireAuth: requireAuth,
+			RateLimit:   group.RateLimit,
+			RateWindow:  group.RateWindow,
+			Verbosity:   logger.ParseVerbosity(group.LogLevel),
+		}
+	}
+
+	admin := policy(cfg.Admin, true)
+	for _, role := range cfg.AdminRoles {
+		if role = strings.TrimSpace(role); role != "" {
+			admin.Roles = append(admin.Roles, role)
+		}
+	}
+
+	return Policies{
+		GroupPublic:        policy(cfg.Public, false),
+		GroupAuthenticated: policy(cfg.Authenticated, true),
+		GroupAdmin:         admin,
+		GroupInternal:      policy(cfg.Internal, false),
+	}
+}
+
+// Middlewares middleware stack của group theo thứ tự: log verbosity, JWT, role, rate limit
+// (rate limit chạy sau JWT để đếm theo user thay vì IP)
+func (c *Controllers) Middlewares(group RouteGroup) []func(http.Handler) http.Handler {
+	policy := c.Policies[group]
+
+	middlewares := []func(http.Handler) http.Handler{logger.WithVerbosity(policy.Verbosity)}
+	if policy.RequireAuth {
+		middlewares = append(middlewares, c.JWTManager.MiddlewareWithBlacklist(c.JWTBlacklist))
+	}
+	if len(policy.Roles) > 0 {
+		middlewares = append(middlewares, jwt.RequireAnyRole(policy.Roles...))
+	}
+	if policy.RateLimit > 0 {
+		middlewares = append(middlewares, middlewarePkg.RateLimitGroup(c.Cache.GetRedisClient(), config.LoadRateLimitConfig().ToRateLimitOptions(), string(group), policy.RateLimit, policy.RateWindow))
+	}
+	return middlewares
+}
+
+// Group đăng ký routes trong fn với middleware stack của group
+func (c *Controllers) Group(r chi.Router, group RouteGroup, fn func(r chi.Router)) {
+	r.Group(func(r chi.Router) {
+		r.Use(c.Middlewares(group)...)
+		fn(r)
+	})
+}
@@ -1,12 +1,24 @@
 package routes
 
 import (
-	"api-core/internal/app/auth"
-	"api-core/internal/app/chat"
-	"api-core/internal/app/friend"
-	"api-core/internal/app/user"
-	"api-core/pkg/jwt"
-	middlewarePkg "api-core/pkg/middleware"
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	"github.com/anhnq996/go-api-core/internal/app/chat"
+	"github.com/anhnq996/go-api-core/internal/app/cronjob"
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/internal/app/e2e"
+	"github.com/anhnq996/go-api-core/internal/app/friend"
+	"github.com/anhnq996/go-api-core/internal/app/loglevel"
+	"github.com/anhnq996/go-api-core/internal/app/notification"
+	"github.com/anhnq996/go-api-core/internal/app/queueadmin"
+	"github.com/anhnq996/go-api-core/internal/app/role"
+	"github.com/anhnq996/go-api-core/internal/app/status"
+	syncapp "github.com/anhnq996/go-api-core/internal/app/sync"
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/app/user"
+	"github.com/anhnq996/go-api-core/internal/app/webhook"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+	"github.com/anhnq996/go-api-core/pkg/storage/resumable"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-redis/redis/v8"
@@ -14,18 +26,36 @@ import (
 
 // Controllers chứa tất cả các handler của các module
 type Controllers struct {
-	UserHandler   *user.Handler
-	AuthHandler   *auth.Handler
-	FriendHandler *friend.Handler
-	ChatHandler   *chat.Handler
-	JWTManager    *jwt.Manager
-	JWTBlacklist  *jwt.Blacklist
-	Cache         CacheInterface
+	UserHandler         *user.Handler
+	AuthHandler         *auth.Handler
+	FriendHandler       *friend.Handler
+	RoleHandler         *role.Handler
+	SyncHandler         *syncapp.Handler
+	ChatHandler         *chat.Handler
+	WebhookHandler      *webhook.Handler
+	StatusHandler       *status.Handler
+	QueueHandler        *queueadmin.Handler
+	CronJobHandler      *cronjob.Handler
+	LogLevelHandler     *loglevel.Handler
+	DeviceHandler       *device.Handler
+	NotificationHandler *notification.Handler
+	StatusService       *status.Service      // Chạy health check định kỳ cho status page
+	E2EHandler          *e2e.Handler         // Test data API, chỉ đăng ký khi APP_ENV=test
+	UploadHandler       *resumable.Handler   // Resumable upload (tus), nil nếu tắt
+	PresignHandler      *upload.Handler      // Dung lượng storage và presigned upload thẳng lên S3 (chỉ đăng ký khi bật)
+	Downloads           *upload.Downloads    // Kiểm tra quyền tải file qua /storages
+	Presence            socket.PresenceStore // Online status, socket hub ghi vào
+	JWTManager          *jwt.Manager
+	JWTBlacklist        *jwt.Blacklist
+	Permissions         *jwt.PermissionChecker
+	Introspector        *jwt.Introspector // nil: không bật /oauth/introspect
+	Cache               CacheInterface
+	Policies            Policies // Middleware stack của từng route group (public, authenticated, admin, internal)
 }
 
 // CacheInterface defines cache interface for rate limiting
 type CacheInterface interface {
-	GetRedisClient() *redis.Client
+	GetRedisClient() redis.UniversalClient
 }
 
 // NewControllers tạo Controllers với tất cả handlers (dùng cho Wire DI)
@@ -33,63 +63,128 @@ func NewControllers(
 	userHandler *user.Handler,
 	authHandler *auth.Handler,
 	friendHandler *friend.Handler,
+	roleHandler *role.Handler,
+	syncHandler *syncapp.Handler,
 	chatHandler *chat.Handler,
+	webhookHandler *webhook.Handler,
+	statusHandler *status.Handler,
+	statusService *status.Service,
+	queueHandler *queueadmin.Handler,
+	cronJobHandler *cronjob.Handler,
+	logLevelHandler *loglevel.Handler,
+	deviceHandler *device.Handler,
+	notificationHandler *notification.Handler,
+	e2eHandler *e2e.Handler,
+	uploadHandler *resumable.Handler,
+	presignHandler *upload.Handler,
+	downloads *upload.Downloads,
+	presence socket.PresenceStore,
 	jwtManager *jwt.Manager,
 	jwtBlacklist *jwt.Blacklist,
+	permissions *jwt.PermissionChecker,
+	introspector *jwt.Introspector,
 	cache CacheInterface,
+	policies Policies,
 ) *Controllers {
 	return &Controllers{
-		UserHandler:   userHandler,
-		AuthHandler:   authHandler,
-		FriendHandler: friendHandler,
-		ChatHandler:   chatHandler,
-		JWTManager:    jwtManager,
-		JWTBlacklist:  jwtBlacklist,
-		Cache:         cache,
+		UserHandler:         userHandler,
+		AuthHandler:         authHandler,
+		FriendHandler:       friendHandler,
+		RoleHandler:         roleHandler,
+		SyncHandler:         syncHandler,
+		ChatHandler:         chatHandler,
+		WebhookHandler:      webhookHandler,
+		StatusHandler:       statusHandler,
+		StatusService:       statusService,
+		QueueHandler:        queueHandler,
+		CronJobHandler:      cronJobHandler,
+		LogLevelHandler:     logLevelHandler,
+		DeviceHandler:       deviceHandler,
+		NotificationHandler: notificationHandler,
+		E2EHandler:          e2eHandler,
+		UploadHandler:       uploadHandler,
+		PresignHandler:      presignHandler,
+		Downloads:           downloads,
+		Presence:            presence,
+		JWTManager:          jwtManager,
+		JWTBlacklist:        jwtBlacklist,
+		Permissions:         permissions,
+		Introspector:        introspector,
+		Cache:               cache,
+		Policies:            policies,
 	}
 }
 
 // RegisterRoutes đăng ký tất cả routes cho ứng dụng
-// Mỗi module sẽ có prefix riêng và quản lý routes của chính nó
+// Mỗi module sẽ có prefix riêng và quản lý routes của chính nó; middleware (auth, rate limit, log)
+// được áp dụng theo route group, cấu hình tập trung trong config.RoutesConfig
 func RegisterRoutes(r chi.Router, c *Controllers) {
+	// Public (root) - jwks, status page
+	c.Group(r, GroupPublic, func(r chi.Router) {
+		// Public keys để service khác verify access token (RS256)
+		r.Get("/.well-known/jwks.json", c.JWTManager.JWKSHandler)
+
+		// Public status page (không cần đăng nhập, dữ liệu cache ngắn hạn)
+		r.Get("/status", c.StatusHandler.Show)
+	})
+
+	// Internal (root) - introspection, E2E test data API
+	c.Group(r, GroupInternal, func(r chi.Router) {
+		// Token introspection (RFC 7662) cho resource server dùng opaque token, xác thực bằng client credentials
+		if c.Introspector != nil {
+			r.Post("/oauth/introspect", c.Introspector.Handler)
+		}
+
+		// Test data API cho E2E suite (reset database, fixture user, tua clock), chỉ có khi APP_ENV=test
+		if c.E2EHandler.Enabled() {
+			e2e.RegisterRoutes(r, c.E2EHandler)
+		}
+	})
+
 	// API v1 routes
 	r.Route("/api/v1", func(r chi.Router) {
-		// Auth routes - /api/v1/auth/* (with rate limiting)
-		r.Group(func(r chi.Router) {
-			// Rate limiting cho auth routes: 5 requests per 15 minutes by IP
-			r.Use(middlewarePkg.RateLimitByIP(c.Cache.GetRedisClient(), 150, 60))
-			auth.RegisterRoutes(r, c.AuthHandler, c.JWTManager, c.JWTBlacklist)
+		// Public - /api/v1/auth/* (login, register, refresh, social, magic link)
+		c.Group(r, GroupPublic, func(r chi.Router) {
+			auth.RegisterPublicRoutes(r, c.AuthHandler)
 		})
 
-		// User routes - /api/v1/users/* (Protected with rate limiting)
-		r.Group(func(r chi.Router) {
-			// Apply JWT middleware for user routes
-			r.Use(c.JWTManager.MiddlewareWithBlacklist(c.JWTBlacklist))
-			// Rate limiting cho user routes: 100 requests per minute by user or IP
-			r.Use(middlewarePkg.RateLimitByUserOrIP(c.Cache.GetRedisClient(), 150, 60))
-			user.RegisterRoutes(r, c.UserHandler)
+		// Authenticated - tài nguyên của chính user
+		c.Group(r, GroupAuthenticated, func(r chi.Router) {
+			auth.RegisterRoutes(r, c.AuthHandler)                 // /api/v1/auth/* (me, logout, change-password)
+			friend.RegisterRoutes(r, c.FriendHandler)             // /api/v1/friends/*
+			chat.RegisterRoutes(r, c.ChatHandler)                 // /api/v1/chats/*
+			syncapp.RegisterRoutes(r, c.SyncHandler)              // /api/v1/sync (delta pull cho client offline-first)
+			device.RegisterRoutes(r, c.DeviceHandler)             // /api/v1/devices/* (FCM token của thiết bị)
+			notification.RegisterRoutes(r, c.NotificationHandler) // /api/v1/notifications/* (notification center)
+
+			// Resumable upload (tus) - /api/v1/uploads/*, client tiếp tục upload file lớn sau khi mất mạng
+			if c.UploadHandler != nil {
+				r.Handle("/uploads", c.UploadHandler)
+				r.Handle("/uploads/*", c.UploadHandler)
+			}
+
+			// Dung lượng storage - /api/v1/uploads/usage
+			// Presigned upload - /api/v1/uploads/presigned/* (khi bật), client upload thẳng lên S3 rồi gọi confirm
+			upload.RegisterRoutes(r, c.PresignHandler)
 		})
 
-		// Friend routes - /api/v1/friends/* (Protected with rate limiting)
-		r.Group(func(r chi.Router) {
-			// Apply JWT middleware for friend routes
-			r.Use(c.JWTManager.MiddlewareWithBlacklist(c.JWTBlacklist))
-			// Rate limiting cho friend routes
-			r.Use(middlewarePkg.RateLimitByUserOrIP(c.Cache.GetRedisClient(), 150, 60))
-			friend.RegisterRoutes(r, c.FriendHandler)
+		// Admin - quản trị, từng route vẫn yêu cầu permission riêng
+		c.Group(r, GroupAdmin, func(r chi.Router) {
+			auth.RegisterAdminRoutes(r, c.AuthHandler, c.Permissions)    // /api/v1/auth/impersonate
+			user.RegisterRoutes(r, c.UserHandler, c.Permissions)         // /api/v1/users/*
+			role.RegisterRoutes(r, c.RoleHandler, c.Permissions)         // /api/v1/roles/* (roles.* / permissions.*)
+			status.RegisterRoutes(r, c.StatusHandler, c.Permissions)     // /api/v1/status/incidents/* (status.manage)
+			queueadmin.RegisterRoutes(r, c.QueueHandler, c.Permissions)  // /api/v1/queues/* (queues.view / queues.manage)
+			cronjob.RegisterRoutes(r, c.CronJobHandler, c.Permissions)   // /api/v1/cron-jobs/* (cron.view / cron.manage)
+			loglevel.RegisterRoutes(r, c.LogLevelHandler, c.Permissions) // /api/v1/log-levels/* (logs.view / logs.manage)
 		})
 
-		// Chat routes - /api/v1/chats/* (Protected with rate limiting)
-		r.Group(func(r chi.Router) {
-			// Apply JWT middleware for chat routes
-			r.Use(c.JWTManager.MiddlewareWithBlacklist(c.JWTBlacklist))
-			// Rate limiting cho chat routes
-			r.Use(middlewarePkg.RateLimitByUserOrIP(c.Cache.GetRedisClient(), 200, 60))
-			chat.RegisterRoutes(r, c.ChatHandler)
+		// Internal - /api/v1/webhooks/* (xác thực bằng token riêng của từng webhook)
+		c.Group(r, GroupInternal, func(r chi.Router) {
+			webhook.RegisterRoutes(r, c.WebhookHandler)
 		})
 
-		// Thêm các module khác ở đây
-		// order.RegisterRoutes(r, c.OrderHandler)
-		// product.RegisterRoutes(r, c.ProductHandler)
+		// Thêm các module khác ở đây, trong group phù hợp
+		// c.Group(r, GroupAuthenticated, func(r chi.Router) { order.RegisterRoutes(r, c.OrderHandler) })
 	})
 }
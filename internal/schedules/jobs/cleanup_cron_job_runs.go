@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
+	"gorm.io/gorm"
+)
+
+// CleanupCronJobRunsJob xóa lịch sử chạy cron job cũ hơn SCHEDULER_RUN_HISTORY_DAYS
+type CleanupCronJobRunsJob struct {
+	DB *gorm.DB
+}
+
+func (j *CleanupCronJobRunsJob) Name() string {
+	return "cleanup-cron-job-runs"
+}
+
+func (j *CleanupCronJobRunsJob) Run(ctx context.Context) error {
+	jobLogger := logger.GetJobLogger(j.Name())
+
+	days := config.LoadSchedulerConfig().RunHistoryDays
+	if days <= 0 {
+		return nil
+	}
+
+	before := clock.FromContext(ctx).Now().AddDate(0, 0, -days)
+	deleted, err := repository.NewCronJobRunRepository(j.DB).PruneBefore(ctx, before)
+	if err != nil {
+		jobLogger.Error().Err(err).Msg("Failed to cleanup cron job runs")
+		return err
+	}
+
+	jobLogger.Info().
+		Int("history_days", days).
+		Int64("deleted_count", deleted).
+		Msg("Cleanup cron job runs job completed")
+	return nil
+}
+
+func (j *CleanupCronJobRunsJob) Timeout() time.Duration {
+	return 5 * time.Minute
+}
+
+func (j *CleanupCronJobRunsJob) RetryCount() int {
+	return 1
+}
+
+func (j *CleanupCronJobRunsJob) RetryDelay() time.Duration {
+	return time.Minute
+}
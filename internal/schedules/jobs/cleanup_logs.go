@@ -6,7 +6,8 @@ import (
 	"path/filepath"
 	"time"
 
-	"api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/logger"
 )
 
 // CleanupLogsJob xóa log files cũ
@@ -23,8 +24,10 @@ func (j *CleanupLogsJob) Run(ctx context.Context) error {
 	// Đường dẫn thư mục logs
 	logsDir := "storages/logs"
 
+	now := clock.FromContext(ctx).Now()
+
 	// Xóa logs cũ hơn 30 ngày
-	cutoffTime := time.Now().AddDate(0, 0, -30)
+	cutoffTime := now.AddDate(0, 0, -30)
 
 	deletedCount := 0
 	err := filepath.Walk(logsDir, func(path string, info os.FileInfo, err error) error {
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"gorm.io/gorm"
+)
+
+// CleanupOrphanFilesJob xóa (hoặc chuyển vào archive) file trong storage không còn được model nào tham chiếu
+// (avatar đã thay, file đính kèm của tin nhắn đã xóa, presigned upload không được dùng)
+type CleanupOrphanFilesJob struct {
+	DB *gorm.DB
+}
+
+func (j *CleanupOrphanFilesJob) Name() string {
+	return "cleanup-orphan-files"
+}
+
+func (j *CleanupOrphanFilesJob) Run(ctx context.Context) error {
+	jobLogger := logger.GetJobLogger(j.Name())
+
+	storageConfig := config.GetDefaultStorageConfig()
+	orphans := storageConfig.Orphans
+	if !orphans.Enabled {
+		return nil
+	}
+
+	storageManager, err := storage.NewStorageManager(storageConfig)
+	if err != nil {
+		return err
+	}
+
+	report, err := storageManager.CleanupOrphans(ctx, storage.OrphanOptions{
+		Prefixes:      orphans.Prefixes,
+		GracePeriod:   time.Duration(orphans.GraceHours) * time.Hour,
+		DryRun:        orphans.DryRun,
+		ArchivePrefix: orphans.ArchivePrefix,
+		IsReferenced:  repository.NewFileReferenceRepository(j.DB).Referenced,
+	})
+	if err != nil {
+		jobLogger.Error().Err(err).Msg("Failed to cleanup orphan files")
+		return err
+	}
+
+	event := jobLogger.Info()
+	if orphans.DryRun {
+		event = event.Strs("orphans", report.Orphans)
+	}
+	event.
+		Bool("dry_run", orphans.DryRun).
+		Int("scanned_count", report.Scanned).
+		Int("orphan_count", len(report.Orphans)).
+		Int64("orphan_bytes", report.Bytes).
+		Int("deleted_count", report.Removed).
+		Int("failed_count", report.Failed).
+		Msg("Cleanup orphan files job completed")
+	return nil
+}
+
+func (j *CleanupOrphanFilesJob) Timeout() time.Duration {
+	return 30 * time.Minute
+}
+
+func (j *CleanupOrphanFilesJob) RetryCount() int {
+	return 1
+}
+
+func (j *CleanupOrphanFilesJob) RetryDelay() time.Duration {
+	return 10 * time.Minute
+}
@@ -7,7 +7,7 @@ import (
 	"strings"
 	"time"
 
-	"api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/logger"
 )
 
 // CleanupTempFilesJob xóa temp files
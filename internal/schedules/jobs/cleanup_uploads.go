@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/resumable"
+)
+
+// CleanupUploadsJob xóa resumable upload bị bỏ dở (chunk tạm và trạng thái) sau khi quá hạn
+type CleanupUploadsJob struct{}
+
+func (j *CleanupUploadsJob) Name() string {
+	return "cleanup-uploads"
+}
+
+func (j *CleanupUploadsJob) Run(ctx context.Context) error {
+	jobLogger := logger.GetJobLogger(j.Name())
+
+	storageConfig := config.GetDefaultStorageConfig()
+	if !storageConfig.Resumable.Enabled {
+		return nil
+	}
+
+	storageManager, err := storage.NewStorageManager(storageConfig)
+	if err != nil {
+		return err
+	}
+	cacheClient, err := config.ConnectCache(config.GetDefaultCacheConfig())
+	if err != nil {
+		return err
+	}
+	defer cacheClient.Close()
+
+	manager, err := resumable.NewFromConfig(storageManager, cacheClient, storageConfig.Resumable)
+	if err != nil {
+		return err
+	}
+
+	removed, err := manager.Cleanup(ctx)
+	if err != nil {
+		jobLogger.Error().Err(err).Int("deleted_count", removed).Msg("Failed to cleanup uploads")
+		return err
+	}
+
+	jobLogger.Info().Int("deleted_count", removed).Msg("Cleanup uploads job completed")
+	return nil
+}
+
+func (j *CleanupUploadsJob) Timeout() time.Duration {
+	return 10 * time.Minute
+}
+
+func (j *CleanupUploadsJob) RetryCount() int {
+	return 1
+}
+
+func (j *CleanupUploadsJob) RetryDelay() time.Duration {
+	return 5 * time.Minute
+}
@@ -7,7 +7,8 @@ import (
 	"path/filepath"
 	"time"
 
-	"api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/logger"
 )
 
 // GenerateReportsJob tạo reports định kỳ
@@ -28,9 +29,11 @@ func (j *GenerateReportsJob) Run(ctx context.Context) error {
 		return err
 	}
 
+	now := clock.FromContext(ctx).Now()
+
 	// Tạo timestamp cho reports
-	timestamp := time.Now().Format("20060102_150405")
-	dateRange := time.Now().AddDate(0, 0, -7).Format("2006-01-02") + "_to_" + time.Now().Format("2006-01-02")
+	timestamp := now.Format("20060102_150405")
+	dateRange := now.AddDate(0, 0, -7).Format("2006-01-02") + "_to_" + now.Format("2006-01-02")
 
 	// Danh sách các reports cần tạo
 	reports := []struct {
@@ -60,17 +63,17 @@ func (j *GenerateReportsJob) Run(ctx context.Context) error {
 		switch report.Type {
 		case "csv":
 			content = fmt.Sprintf("Date,Users,Activity\n%s,150,2500\n%s,155,2600\n",
-				time.Now().AddDate(0, 0, -1).Format("2006-01-02"),
-				time.Now().Format("2006-01-02"))
+				now.AddDate(0, 0, -1).Format("2006-01-02"),
+				now.Format("2006-01-02"))
 		case "excel":
 			content = fmt.Sprintf("Weekly Performance Report\nGenerated: %s\nDate Range: %s\n\nUsers: 150\nActivity: 2500\n",
-				time.Now().Format("2006-01-02 15:04:05"), dateRange)
+				now.Format("2006-01-02 15:04:05"), dateRange)
 		case "text":
 			content = fmt.Sprintf("Error Log Summary\nGenerated: %s\n\nTotal Errors: 5\nCritical: 1\nWarning: 4\n",
-				time.Now().Format("2006-01-02 15:04:05"))
+				now.Format("2006-01-02 15:04:05"))
 		case "json":
 			content = fmt.Sprintf(`{"report_type": "weekly_stats", "generated_at": "%s", "date_range": "%s", "total_users": 150, "total_activity": 2500}`,
-				time.Now().Format(time.RFC3339), dateRange)
+				now.Format(time.RFC3339), dateRange)
 		}
 
 		// Ghi file report
@@ -95,7 +98,7 @@ func (j *GenerateReportsJob) Run(ctx context.Context) error {
 	}
 
 	// Xóa reports cũ hơn 30 ngày
-	cutoffTime := time.Now().AddDate(0, 0, -30)
+	cutoffTime := now.AddDate(0, 0, -30)
 	deletedCount := 0
 
 	err := filepath.Walk(reportsDir, func(path string, info os.FileInfo, err error) error {
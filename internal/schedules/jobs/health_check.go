@@ -6,7 +6,7 @@ import (
 	"net/http"
 	"time"
 
-	"api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/logger"
 )
 
 // HealthCheckJob kiểm tra health của các services
@@ -5,7 +5,8 @@ import (
 	"time"
 )
 
-// Job interface cho các jobs - không cần Schedule() method nữa
+// Job interface cho các jobs - không cần Schedule() method nữa.
+// Job có thể implement thêm cron.TimeZoneProvider (chạy theo giờ địa phương) và cron.JitterProvider (chờ ngẫu nhiên trước khi chạy).
 type Job interface {
 	// Name returns the unique name of the job
 	Name() string
@@ -4,7 +4,7 @@ import (
 	"context"
 	"time"
 
-	"api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/logger"
 )
 
 // SendNotificationsJob gửi notifications
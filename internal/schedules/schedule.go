@@ -3,11 +3,14 @@ package schedules
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
-	"api-core/internal/schedules/jobs"
-	"api-core/pkg/cron"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/internal/schedules/jobs"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
+	"gorm.io/gorm"
 )
 
 // JobWrapper wraps jobs.Job to implement cron.Job interface
@@ -40,6 +43,25 @@ func (jw *JobWrapper) RetryDelay() time.Duration {
 	return jw.job.RetryDelay()
 }
 
+// TimeZone implements cron.TimeZoneProvider nếu job khai báo timezone riêng
+func (jw *JobWrapper) TimeZone() string {
+	if provider, ok := jw.job.(cron.TimeZoneProvider); ok {
+		return provider.TimeZone()
+	}
+	return ""
+}
+
+// Jitter implements cron.JitterProvider nếu job khai báo jitter
+func (jw *JobWrapper) Jitter() time.Duration {
+	if provider, ok := jw.job.(cron.JitterProvider); ok {
+		return provider.Jitter()
+	}
+	return 0
+}
+
+// LockPrefix tiền tố Redis key lock theo job, admin API đọc cùng key để xem trạng thái lock
+const LockPrefix = "api-core:cron:"
+
 // JobConfig cấu hình cho job
 type JobConfig struct {
 	Name     string
@@ -51,10 +73,12 @@ type JobConfig struct {
 type ScheduleManager struct {
 	scheduler   cron.Scheduler
 	lockManager cron.LockManager
+	db          *gorm.DB // Jobs cần database (cleanup-orphan-files), nil: bỏ qua các job đó
 }
 
-// NewScheduleManager tạo schedule manager mới
-func NewScheduleManager(lockManager cron.LockManager) *ScheduleManager {
+// NewScheduleManager tạo schedule manager mới, leaderElector nil thì mọi instance chạy cron và dùng lock theo job.
+// control nil: không quản lý được job qua admin API (/api/v1/cron-jobs/jobs)
+func NewScheduleManager(db *gorm.DB, lockManager cron.LockManager, leaderElector cron.LeaderElector, leaderRenewInterval time.Duration, control cron.Control) *ScheduleManager {
 	config := cron.Config{
 		TimeZone:       "UTC",
 		LockTTL:        5 * time.Minute,
@@ -62,7 +86,19 @@ func NewScheduleManager(lockManager cron.LockManager) *ScheduleManager {
 		MaxLockRetries: 3,
 		JobTimeout:     1 * time.Minute,
 		EnableMetrics:  true,
-		MetricsPrefix:  "api_core",
+		MetricsPrefix:  "api_core_cron",
+
+		LeaderElector:       leaderElector,
+		LeaderRenewInterval: leaderRenewInterval,
+
+		Control: control,
+
+		// Level theo LOG_LEVEL_CRON
+		Logger: logger.Module(logger.ModuleCron),
+	}
+	// Lịch sử chạy job lưu vào bảng cron_job_runs, xem qua /api/v1/cron-jobs
+	if db != nil {
+		config.ResultRecorder = repository.NewCronJobRunRepository(db)
 	}
 
 	scheduler := cron.NewScheduler(lockManager, config)
@@ -70,6 +106,7 @@ func NewScheduleManager(lockManager cron.LockManager) *ScheduleManager {
 	return &ScheduleManager{
 		scheduler:   scheduler,
 		lockManager: lockManager,
+		db:          db,
 	}
 }
 
@@ -77,9 +114,12 @@ func NewScheduleManager(lockManager cron.LockManager) *ScheduleManager {
 func (sm *ScheduleManager) RegisterAllJobs() error {
 	// Cron expression cho các jobs
 	jobCron := map[string]string{
-		"cleanup-logs":       "0 0 * * *", // Mỗi ngày lúc 0h
-		"cleanup-temp-files": "0 0 * * *", // Mỗi ngày lúc 0h
-		"health-check":       "0 * * * *", // Mỗi giờ
+		"cleanup-logs":          "0 0 * * *",  // Mỗi ngày lúc 0h
+		"cleanup-temp-files":    "0 0 * * *",  // Mỗi ngày lúc 0h
+		"health-check":          "0 * * * *",  // Mỗi giờ
+		"cleanup-uploads":       "0 * * * *",  // Mỗi giờ
+		"cleanup-orphan-files":  "30 3 * * *", // Mỗi ngày lúc 3h30, ngoài giờ cao điểm
+		"cleanup-cron-job-runs": "15 0 * * *", // Mỗi ngày lúc 0h15
 	}
 
 	// Đăng ký các jobs
@@ -99,6 +139,22 @@ func (sm *ScheduleManager) RegisterAllJobs() error {
 			Schedule: jobCron["health-check"], // Mỗi 10 phút
 			Job:      &JobWrapper{job: &jobs.HealthCheckJob{}, schedule: jobCron["health-check"]},
 		},
+		{
+			Name:     "cleanup-uploads",
+			Schedule: jobCron["cleanup-uploads"],
+			Job:      &JobWrapper{job: &jobs.CleanupUploadsJob{}, schedule: jobCron["cleanup-uploads"]},
+		},
+	}
+	if sm.db != nil {
+		jobsToRegister = append(jobsToRegister, JobConfig{
+			Name:     "cleanup-orphan-files",
+			Schedule: jobCron["cleanup-orphan-files"],
+			Job:      &JobWrapper{job: &jobs.CleanupOrphanFilesJob{DB: sm.db}, schedule: jobCron["cleanup-orphan-files"]},
+		}, JobConfig{
+			Name:     "cleanup-cron-job-runs",
+			Schedule: jobCron["cleanup-cron-job-runs"],
+			Job:      &JobWrapper{job: &jobs.CleanupCronJobRunsJob{DB: sm.db}, schedule: jobCron["cleanup-cron-job-runs"]},
+		})
 	}
 
 	// Đăng ký từng job
@@ -106,7 +162,7 @@ func (sm *ScheduleManager) RegisterAllJobs() error {
 		if err := sm.scheduler.AddJob(jobConfig.Job); err != nil {
 			return fmt.Errorf("failed to register job %s: %w", jobConfig.Name, err)
 		}
-		log.Printf("Registered job: %s with schedule: %s", jobConfig.Name, jobConfig.Schedule)
+		logger.Module(logger.ModuleCron).Debugf("Registered job: %s with schedule: %s", jobConfig.Name, jobConfig.Schedule)
 	}
 
 	return nil
@@ -114,18 +170,18 @@ func (sm *ScheduleManager) RegisterAllJobs() error {
 
 // Start bắt đầu scheduler
 func (sm *ScheduleManager) Start(ctx context.Context) error {
-	log.Println("Starting schedule manager...")
+	logger.Module(logger.ModuleCron).Infof("Starting schedule manager...")
 
 	if err := sm.scheduler.Start(ctx); err != nil {
 		return fmt.Errorf("failed to start scheduler: %w", err)
 	}
 
-	log.Println("Schedule manager started successfully")
+	logger.Module(logger.ModuleCron).Infof("Schedule manager started successfully")
 
 	// Log job statuses
 	statuses := sm.scheduler.GetJobStatuses()
 	for name, status := range statuses {
-		log.Printf("Job %s: %s - Running: %v", name, status.Schedule, status.IsRunning)
+		logger.Module(logger.ModuleCron).Debugf("Job %s: %s - Running: %v", name, status.Schedule, status.IsRunning)
 	}
 
 	return nil
@@ -137,7 +193,7 @@ func (sm *ScheduleManager) Stop() error {
 		return fmt.Errorf("failed to stop scheduler: %w", err)
 	}
 
-	log.Println("Schedule manager stopped")
+	logger.Module(logger.ModuleCron).Infof("Schedule manager stopped")
 	return nil
 }
 
@@ -151,18 +207,48 @@ func (sm *ScheduleManager) GetJobStatus(jobName string) (*cron.JobStatus, error)
 	return sm.scheduler.GetJobStatus(jobName)
 }
 
+// RemoveJob gỡ job khỏi scheduler
+func (sm *ScheduleManager) RemoveJob(jobName string) error {
+	return sm.scheduler.RemoveJob(jobName)
+}
+
+// UpdateSchedule đổi lịch chạy của job lúc runtime
+func (sm *ScheduleManager) UpdateSchedule(jobName, schedule string) error {
+	return sm.scheduler.UpdateSchedule(jobName, schedule)
+}
+
+// PauseJob tạm dừng job trên mọi scheduler
+func (sm *ScheduleManager) PauseJob(ctx context.Context, jobName string) error {
+	return sm.scheduler.PauseJob(ctx, jobName)
+}
+
+// ResumeJob tiếp tục chạy job theo lịch
+func (sm *ScheduleManager) ResumeJob(ctx context.Context, jobName string) error {
+	return sm.scheduler.ResumeJob(ctx, jobName)
+}
+
+// TriggerJob chạy job ngay trên instance này
+func (sm *ScheduleManager) TriggerJob(jobName string) error {
+	return sm.scheduler.TriggerJob(jobName)
+}
+
 // IsRunning kiểm tra scheduler có đang chạy không
 func (sm *ScheduleManager) IsRunning() bool {
 	return sm.scheduler.IsRunning()
 }
 
+// IsLeader kiểm tra instance này có đang chạy cron loop không
+func (sm *ScheduleManager) IsLeader() bool {
+	return sm.scheduler.IsLeader()
+}
+
 // InitScheduleManager khởi tạo schedule manager với logger
-func InitScheduleManager(lockManager cron.LockManager) (*ScheduleManager, error) {
+func InitScheduleManager(db *gorm.DB, lockManager cron.LockManager, leaderElector cron.LeaderElector, leaderRenewInterval time.Duration, control cron.Control) (*ScheduleManager, error) {
 	// Schedule manager sử dụng logger đã được khởi tạo từ main
 	// Không cần khởi tạo lại logger ở đây để tránh ghi đè RequestLogger
 
 	// Tạo schedule manager
-	manager := NewScheduleManager(lockManager)
+	manager := NewScheduleManager(db, lockManager, leaderElector, leaderRenewInterval, control)
 
 	// Đăng ký tất cả jobs
 	if err := manager.RegisterAllJobs(); err != nil {
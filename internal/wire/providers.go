@@ -1,42 +1,446 @@
 package wire
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"time"
 
-	"api-core/config"
-	"api-core/pkg/cache"
-	"api-core/pkg/fcm"
-	"api-core/pkg/jwt"
-	"api-core/pkg/storage"
-	"api-core/pkg/utils"
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	"github.com/anhnq996/go-api-core/internal/app/chat"
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/internal/app/e2e"
+	"github.com/anhnq996/go-api-core/internal/app/status"
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/app/webhook"
+	"github.com/anhnq996/go-api-core/internal/config"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/internal/routes"
+	"github.com/anhnq996/go-api-core/internal/schedules"
+	"github.com/anhnq996/go-api-core/pkg/apns"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/fcm"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/mailer"
+	"github.com/anhnq996/go-api-core/pkg/oidc"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+	"github.com/anhnq996/go-api-core/pkg/sms"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/resumable"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
-// ProvideJWTManager provides JWT manager
-func ProvideJWTManager() *jwt.Manager {
-	// Ưu tiên dùng RSA keys nếu có; fallback sang HMAC nếu thiếu
+// ProvideJWTManager provides JWT manager, JWT_TOKEN_MODE=opaque cấp opaque token lưu trong Redis
+func ProvideJWTManager(cacheClient cache.Cache) *jwt.Manager {
+	// Ưu tiên dùng keys (RSA/EC/Ed25519) nếu có; fallback sang HMAC nếu thiếu
 	privatePath := getEnv("JWT_PRIVATE_KEY_PATH", "keys/private.pem")
 	publicPath := getEnv("JWT_PUBLIC_KEY_PATH", "keys/public.pem")
+	// Impersonation token (admin đăng nhập dưới danh nghĩa user) luôn ngắn hạn
+	impersonationTTL := time.Duration(utils.GetEnvInt("JWT_IMPERSONATION_TOKEN_MINUTES", 15)) * time.Minute
+	// Sai cấu hình validation thì dừng hẳn thay vì âm thầm bỏ kiểm tra
+	subjectPattern, err := jwt.ParseSubjectFormat(getEnv("JWT_SUBJECT_FORMAT", ""))
+	if err != nil {
+		logger.Fatalf("Invalid JWT_SUBJECT_FORMAT: %v", err)
+	}
+	// Sai lệch đồng hồ giữa các node khi verify exp/nbf/iat
+	leewaySeconds := utils.GetEnvInt("JWT_LEEWAY_SECONDS", 0)
+	if leewaySeconds < 0 {
+		logger.Fatalf("Invalid JWT_LEEWAY_SECONDS %d: must not be negative", leewaySeconds)
+	}
+
+	var opaqueStore *jwt.OpaqueStore
+	switch mode := getEnv("JWT_TOKEN_MODE", "jwt"); mode {
+	case "jwt":
+	case "opaque":
+		opaqueStore = jwt.NewOpaqueStore(cacheClient)
+	default:
+		logger.Fatalf("Invalid JWT_TOKEN_MODE %q: expected jwt or opaque", mode)
+	}
 
 	return jwt.NewManager(jwt.Config{
-		SecretKey:            getEnv("JWT_SECRET_KEY", ""),
-		PrivateKeyPath:       privatePath,
-		PublicKeyPath:        publicPath,
-		AccessTokenDuration:  15 * time.Minute,
-		RefreshTokenDuration: 7 * 24 * time.Hour,
-		Issuer:               "apicore",
+		SecretKey:                  getEnv("JWT_SECRET_KEY", ""),
+		Algorithm:                  getEnv("JWT_ALGORITHM", ""),
+		PrivateKeyPath:             privatePath,
+		PublicKeyPath:              publicPath,
+		KeysDir:                    getEnv("JWT_KEYS_DIR", ""),
+		AccessTokenDuration:        15 * time.Minute,
+		RefreshTokenDuration:       7 * 24 * time.Hour,
+		ImpersonationTokenDuration: impersonationTTL,
+		Issuer:                     getEnv("JWT_ISSUER", "apicore"),
+		Audience:                   utils.GetEnvStringSlice("JWT_AUDIENCE", nil),
+		AcceptedAudiences:          utils.GetEnvStringSlice("JWT_ACCEPTED_AUDIENCES", nil),
+		AcceptedIssuers:            utils.GetEnvStringSlice("JWT_ACCEPTED_ISSUERS", nil),
+		SubjectPattern:             subjectPattern,
+		OpaqueStore:                opaqueStore,
+		Leeway:                     time.Duration(leewaySeconds) * time.Second,
 	})
 }
 
-// ProvideJWTBlacklist provides JWT blacklist
-func ProvideJWTBlacklist(cacheClient cache.Cache) *jwt.Blacklist {
-	return jwt.NewBlacklist(cacheClient)
+// ProvideIntrospector provides token introspection (RFC 7662), nil khi chưa cấu hình OAUTH_INTROSPECTION_CLIENTS
+func ProvideIntrospector(manager *jwt.Manager, blacklist *jwt.Blacklist) *jwt.Introspector {
+	clients, err := jwt.ParseIntrospectionClients(getEnv("OAUTH_INTROSPECTION_CLIENTS", ""))
+	if err != nil {
+		logger.Fatalf("Invalid OAUTH_INTROSPECTION_CLIENTS: %v", err)
+	}
+	if len(clients) == 0 {
+		return nil
+	}
+	return jwt.NewIntrospector(manager, blacklist, clients)
+}
+
+// ProvideTokenVersions provides token version store, đọc token_version của user từ database
+func ProvideTokenVersions(cacheClient cache.Cache, userRepo repository.UserRepository) *jwt.TokenVersions {
+	loader := func(ctx context.Context, userID string) (int, error) {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return 0, err
+		}
+		user, err := userRepo.FirstWhere(ctx, "id = ?", id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, jwt.ErrUserNotFound
+		}
+		if err != nil {
+			return 0, err
+		}
+		return user.TokenVersion, nil
+	}
+
+	return jwt.NewTokenVersions(cacheClient, loader, 10*time.Minute)
+}
+
+// ProvideJWTBlacklist provides JWT blacklist, kiểm tra cả token_version
+func ProvideJWTBlacklist(cacheClient cache.Cache, versions *jwt.TokenVersions) *jwt.Blacklist {
+	blacklist := jwt.NewBlacklist(cacheClient)
+	blacklist.SetTokenVersions(versions)
+	return blacklist
 }
 
-// ProvideStorageManager provides storage manager
-func ProvideStorageManager() (*storage.StorageManager, error) {
+// ProvidePermissionChecker provides permission checker, fallback lấy permissions theo role của user khi token không chứa permissions.
+// Permissions trong token bị bỏ qua sau Invalidate cho tới khi mọi access token cấp trước đó hết hạn.
+func ProvidePermissionChecker(cacheClient cache.Cache, userRepo repository.UserRepository, jwtManager *jwt.Manager) *jwt.PermissionChecker {
+	loader := func(ctx context.Context, userID string) ([]string, error) {
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return nil, err
+		}
+		user, err := userRepo.FindByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if user.RoleID == nil {
+			return []string{}, nil
+		}
+		return userRepo.GetUserPermissions(ctx, *user.RoleID)
+	}
+
+	checker := jwt.NewPermissionChecker(cacheClient, loader, 5*time.Minute)
+	checker.SetStaleClaimsTTL(jwtManager.MaxAccessTokenLifetime())
+	return checker
+}
+
+// ProvideStorageManager provides storage manager (tính dung lượng và quota theo user qua bảng storage_usage,
+// owner của từng file qua bảng storage_files)
+func ProvideStorageManager(usage repository.StorageUsageRepository, files repository.StorageFileRepository) (*storage.StorageManager, error) {
 	cfg := config.GetDefaultStorageConfig()
-	return storage.NewStorageManager(cfg)
+	manager, err := storage.NewStorageManager(cfg)
+	if err != nil {
+		return nil, err
+	}
+	manager.SetUsageStore(usage)
+	manager.SetFileOwnerStore(files)
+	if len(manager.ImageVariants()) < len(cfg.Image.Variants) {
+		logger.Warnf("Image variants %v configured but only %v have an encoder installed (cwebp, avifenc)", cfg.Image.Variants, manager.ImageVariants())
+	}
+	return manager, nil
+}
+
+// ProvideResumableUploads provides tus handler cho resumable upload (nil nếu STORAGE_RESUMABLE_ENABLED=false)
+func ProvideResumableUploads(storageManager *storage.StorageManager, cacheClient cache.Cache) (*resumable.Handler, error) {
+	cfg := config.GetDefaultStorageConfig().Resumable
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	manager, err := resumable.NewFromConfig(storageManager, cacheClient, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return resumable.NewHandler(manager, resumable.HandlerConfig{
+		BasePath: "/api/v1/uploads",
+		Owner: func(r *http.Request) string {
+			return jwt.GetUserIDFromContext(r.Context())
+		},
+	}), nil
+}
+
+// ProvidePresignedUploadConfig provides presigned upload config (client upload thẳng lên S3)
+func ProvidePresignedUploadConfig() upload.Config {
+	cfg := config.GetDefaultStorageConfig().Presigned
+	return upload.Config{
+		Enabled:    cfg.Enabled,
+		Expiration: time.Duration(cfg.ExpirationMinutes) * time.Minute,
+	}
+}
+
+// ProvideSocialProviders provides OAuth2 social login providers (chỉ các provider đã cấu hình)
+func ProvideSocialProviders() auth.SocialProviders {
+	cfg := config.LoadOAuthConfig()
+	providers := auth.SocialProviders{}
+
+	if cfg.Google.Enabled() {
+		providers[auth.ProviderGoogle] = auth.NewGoogleProvider(auth.SocialProviderConfig{
+			ClientID:     cfg.Google.ClientID,
+			ClientSecret: cfg.Google.ClientSecret,
+			RedirectURL:  cfg.Google.RedirectURL,
+			Scopes:       cfg.Google.Scopes,
+		})
+	}
+	if cfg.GitHub.Enabled() {
+		providers[auth.ProviderGitHub] = auth.NewGitHubProvider(auth.SocialProviderConfig{
+			ClientID:     cfg.GitHub.ClientID,
+			ClientSecret: cfg.GitHub.ClientSecret,
+			RedirectURL:  cfg.GitHub.RedirectURL,
+			Scopes:       cfg.GitHub.Scopes,
+		})
+	}
+	if cfg.OIDC.Enabled() {
+		roleMapping, err := oidc.ParseRoleMapping(cfg.OIDC.RoleMapping)
+		if err != nil {
+			// Role mapping sai cấu hình: vẫn cho đăng nhập SSO nhưng không quản lý role
+			logger.Warnf("OIDC role mapping ignored: %v", err)
+		}
+		providers[cfg.OIDC.Name] = auth.NewOIDCProvider(auth.OIDCConfig{
+			Name: cfg.OIDC.Name,
+			Provider: oidc.NewProvider(oidc.Config{
+				IssuerURL:    cfg.OIDC.IssuerURL,
+				ClientID:     cfg.OIDC.ClientID,
+				ClientSecret: cfg.OIDC.ClientSecret,
+				RedirectURL:  cfg.OIDC.RedirectURL,
+				Scopes:       cfg.OIDC.Scopes,
+			}),
+			Claims: oidc.ClaimMapping{
+				Subject:       cfg.OIDC.ClaimSubject,
+				Email:         cfg.OIDC.ClaimEmail,
+				EmailVerified: cfg.OIDC.ClaimEmailVerified,
+				Name:          cfg.OIDC.ClaimName,
+				Picture:       cfg.OIDC.ClaimPicture,
+				Roles:         cfg.OIDC.ClaimRoles,
+			},
+			RoleMapping: roleMapping,
+			TrustEmail:  cfg.OIDC.TrustEmail,
+		})
+	}
+
+	return providers
+}
+
+// ProvideMailer provides mailer gửi qua SMTP hoặc SES (MAIL_DRIVER), template theo ngôn ngữ trong EMAIL_TEMPLATES_DIR
+func ProvideMailer() (*mailer.Mailer, error) {
+	cfg := config.LoadEmailConfig()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	var driver mailer.Driver
+	if cfg.Driver == "ses" {
+		sesDriver, err := mailer.NewSESDriver(ctx, cfg.ToSESConfig())
+		if err != nil {
+			return nil, err
+		}
+		driver = sesDriver
+	} else {
+		driver = mailer.NewSMTPDriver(cfg.ToSMTPConfig())
+	}
+
+	var templates *mailer.Templates
+	if _, err := os.Stat(cfg.TemplatesDir); cfg.TemplatesDir != "" && err == nil {
+		templateConfig := mailer.TemplateConfig{Dir: cfg.TemplatesDir, DefaultLocale: cfg.DefaultLocale}
+		if cfg.MJMLCommand != "" {
+			templateConfig.MJML = mailer.MJMLCommand{Path: cfg.MJMLCommand}
+		}
+		if templates, err = mailer.LoadTemplates(ctx, templateConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	return mailer.New(driver, templates, cfg.FromEmail, cfg.FromName), nil
+}
+
+// ProvideEmailService provides email service gửi qua mailer, không gửi tới địa chỉ trong suppression list
+func ProvideEmailService(m *mailer.Mailer, suppressionRepo repository.EmailSuppressionRepository) email.EmailService {
+	return email.NewSuppressionAwareService(mailer.NewEmailService(m), suppressionRepo)
+}
+
+// ProvideWebhookConfig provides webhook config
+func ProvideWebhookConfig() webhook.Config {
+	return webhook.Config{
+		EmailToken: config.LoadEmailConfig().WebhookToken,
+	}
+}
+
+// ProvideSafeHTTPClient provides HTTP client cho URL do user cung cấp (chặn SSRF)
+func ProvideSafeHTTPClient() (*safehttp.Client, error) {
+	cfg, err := config.LoadSafeHTTPConfig().ToClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	return safehttp.New(cfg), nil
+}
+
+// ProvideChatConfig provides chat config
+func ProvideChatConfig() chat.Config {
+	return chat.Config{
+		EventSourcing: config.LoadChatConfig().EventSourcing,
+	}
+}
+
+// ProvideStatusConfig provides status page config
+func ProvideStatusConfig() status.Config {
+	cfg := config.LoadStatusConfig()
+	if err := cfg.Validate(); err != nil {
+		logger.Fatalf("Invalid status config: %v", err)
+	}
+
+	return status.Config{
+		Enabled:         cfg.Enabled,
+		CheckInterval:   cfg.CheckInterval,
+		CheckTimeout:    cfg.CheckTimeout,
+		DegradedLatency: cfg.DegradedLatency,
+		HistoryDays:     cfg.HistoryDays,
+	}
+}
+
+// ProvideStatusCheckers provides health check của từng component cho status page, bỏ qua push khi chưa cấu hình FCM
+func ProvideStatusCheckers(db *gorm.DB, cacheClient cache.Cache, storageManager *storage.StorageManager, fcmClient *fcm.Client) status.Checkers {
+	checkers := status.Checkers{
+		{Component: status.ComponentDatabase, Check: func(ctx context.Context) error {
+			sqlDB, err := db.DB()
+			if err != nil {
+				return err
+			}
+			return sqlDB.PingContext(ctx)
+		}},
+		{Component: status.ComponentCache, Check: cacheClient.Ping},
+	}
+
+	if fcmClient != nil {
+		checkers = append(checkers, status.Checker{Component: status.ComponentPush, Check: fcmClient.Ping})
+	}
+
+	// File thăm dò không cần tồn tại, chỉ cần storage trả lời được
+	checkers = append(checkers, status.Checker{Component: status.ComponentStorage, Check: func(ctx context.Context) error {
+		if _, err := storageManager.FileExists(ctx, ".status-probe"); err != nil {
+			return fmt.Errorf("storage unavailable: %w", err)
+		}
+		return nil
+	}})
+
+	return checkers
+}
+
+// ProvideE2EConfig provides test data API config. Khi bật (APP_ENV=test), clock mặc định của process
+// được thay bằng clock có thể tua để E2E suite kiểm tra logic phụ thuộc thời gian.
+func ProvideE2EConfig() e2e.Config {
+	cfg := config.LoadE2EConfig()
+	offset := clock.NewOffset(clock.New())
+	if cfg.Enabled {
+		clock.SetDefault(offset)
+		logger.Warn("E2E test data API enabled at /__e2e (APP_ENV=test)")
+	}
+
+	return e2e.Config{
+		Enabled: cfg.Enabled,
+		Token:   cfg.Token,
+		Clock:   offset,
+	}
+}
+
+// ProvideQueueManager provides queue backend cho queue admin API, nil nếu không kết nối được (API trả 503)
+func ProvideQueueManager() queue.QueueManager {
+	cfg := config.LoadQueueConfig()
+	if err := cfg.Validate(); err != nil {
+		logger.Warnf("Queue admin API disabled: %v", err)
+		return nil
+	}
+
+	manager, err := queue.NewQueueManager(cfg.ToQueueConfig())
+	if err != nil {
+		logger.Warnf("Queue admin API disabled: failed to connect to queue backend: %v", err)
+		return nil
+	}
+	return manager
+}
+
+// ProvideQueueControl provides trạng thái pause/danh sách queue dùng chung với worker, nil nếu cache không phải Redis
+func ProvideQueueControl(cacheClient cache.Cache) queue.Control {
+	client := cacheClient.GetRedisClient()
+	if client == nil {
+		return nil
+	}
+	return queue.NewRedisControl(client, "")
+}
+
+// ProvideLogLevelStore provides log level đặt lúc chạy dùng chung với mọi process, nil nếu cache không phải Redis
+func ProvideLogLevelStore(cacheClient cache.Cache) logger.LevelStore {
+	client := cacheClient.GetRedisClient()
+	if client == nil {
+		return nil
+	}
+	return logger.NewRedisLevelStore(client, "")
+}
+
+// ProvideCronControl provides trạng thái job/pause/trigger dùng chung với scheduler, nil nếu cache không phải Redis
+func ProvideCronControl(cacheClient cache.Cache) cron.Control {
+	client := cacheClient.GetRedisClient()
+	if client == nil {
+		return nil
+	}
+	return cron.NewRedisControl(client, "")
+}
+
+// ProvideCronLockManager provides lock theo job của scheduler (cùng key prefix), nil nếu cache không phải Redis
+func ProvideCronLockManager(cacheClient cache.Cache) cron.LockManager {
+	client := cacheClient.GetRedisClient()
+	if client == nil {
+		return nil
+	}
+	return cron.NewRedisLockManager(client, schedules.LockPrefix)
+}
+
+// ProvideRoutePolicies provides middleware stack (rate limit, log verbosity, role) của từng route group
+func ProvideRoutePolicies() routes.Policies {
+	cfg := config.LoadRoutesConfig()
+	if err := cfg.Validate(); err != nil {
+		logger.Fatalf("Invalid routes config: %v", err)
+	}
+
+	return routes.NewPolicies(cfg)
+}
+
+// ProvideMagicLink provides magic link signer/mailer cho passwordless login
+func ProvideMagicLink(mailer email.EmailService) *auth.MagicLink {
+	cfg := config.LoadMagicLinkConfig()
+
+	return auth.NewMagicLink(auth.MagicLinkConfig{
+		Secret:       cfg.Secret,
+		TTL:          cfg.TTL,
+		VerifyURL:    cfg.VerifyURL,
+		TemplatePath: cfg.TemplatePath,
+	}, mailer)
 }
 
 // ProvideFCMClient provides FCM client (optional, returns nil if not configured)
@@ -51,8 +455,9 @@ func ProvideFCMClient() (*fcm.Client, error) {
 	}
 
 	config := &fcm.Config{
-		CredentialsFile: credentialsFile,
-		Timeout:         time.Duration(timeoutSeconds) * time.Second,
+		CredentialsFile:  credentialsFile,
+		Timeout:          time.Duration(timeoutSeconds) * time.Second,
+		BatchConcurrency: utils.GetEnvInt("FCM_BATCH_CONCURRENCY", 4),
 	}
 
 	client, err := fcm.NewClient(config)
@@ -64,9 +469,85 @@ func ProvideFCMClient() (*fcm.Client, error) {
 	return client, nil
 }
 
+// ProvideAPNsClient provides APNs client gửi push trực tiếp (optional, returns nil if key file not found)
+func ProvideAPNsClient() (*apns.Client, error) {
+	keyFile := utils.GetEnv("APNS_KEY_FILE", "keys/apns-auth-key.p8")
+	if _, err := os.Stat(keyFile); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return apns.NewClient(&apns.Config{
+		KeyFile:    keyFile,
+		KeyID:      utils.GetEnv("APNS_KEY_ID", ""),
+		TeamID:     utils.GetEnv("APNS_TEAM_ID", ""),
+		Topic:      utils.GetEnv("APNS_TOPIC", ""),
+		Production: utils.GetEnvBool("APNS_PRODUCTION", false),
+		Timeout:    time.Duration(utils.GetEnvInt("APNS_TIMEOUT", 10)) * time.Second,
+	})
+}
+
+// ProvidePusher provides pusher gửi push tới thiết bị: mặc định qua FCM,
+// PUSH_PROVIDER_IOS=apns gửi tới thiết bị ios trực tiếp qua APNs (app đăng ký APNs device token)
+func ProvidePusher(fcmClient *fcm.Client, apnsClient *apns.Client, deviceRepo repository.DeviceTokenRepository) (*device.Pusher, error) {
+	pusher := device.NewPusher(fcmClient, deviceRepo)
+
+	switch provider := utils.GetEnv("PUSH_PROVIDER_IOS", "fcm"); provider {
+	case "fcm":
+	case "apns":
+		if apnsClient == nil {
+			return nil, fmt.Errorf("PUSH_PROVIDER_IOS=apns nhưng chưa cấu hình APNs (APNS_KEY_FILE)")
+		}
+		pusher.Route(model.DevicePlatformIOS, apnsClient)
+	default:
+		return nil, fmt.Errorf("PUSH_PROVIDER_IOS không hợp lệ: %s (fcm, apns)", provider)
+	}
+	return pusher, nil
+}
+
+// ProvideSMSSender provides SMS sender theo SMS_DRIVER (twilio, sns), nil khi chưa cấu hình (worker bỏ qua job SMS)
+func ProvideSMSSender() (sms.Sender, error) {
+	timeout := time.Duration(utils.GetEnvInt("SMS_TIMEOUT", 10)) * time.Second
+
+	switch driver := utils.GetEnv("SMS_DRIVER", ""); driver {
+	case "":
+		return nil, nil
+	case "twilio":
+		return sms.NewTwilioDriver(sms.TwilioConfig{
+			AccountSID:          utils.GetEnv("TWILIO_ACCOUNT_SID", ""),
+			AuthToken:           utils.GetEnv("TWILIO_AUTH_TOKEN", ""),
+			From:                utils.GetEnv("TWILIO_FROM", ""),
+			MessagingServiceSID: utils.GetEnv("TWILIO_MESSAGING_SERVICE_SID", ""),
+			Timeout:             timeout,
+		})
+	case "sns":
+		return sms.NewSNSDriver(context.Background(), sms.SNSConfig{
+			Region:          utils.GetEnv("SNS_REGION", utils.GetEnv("AWS_REGION", "")),
+			AccessKeyID:     utils.GetEnv("SNS_ACCESS_KEY_ID", ""),
+			SecretAccessKey: utils.GetEnv("SNS_SECRET_ACCESS_KEY", ""),
+			SenderID:        utils.GetEnv("SNS_SENDER_ID", ""),
+			From:            utils.GetEnv("SNS_ORIGINATION_NUMBER", ""),
+			SMSType:         utils.GetEnv("SNS_SMS_TYPE", "Transactional"),
+			MaxPrice:        utils.GetEnv("SNS_MAX_PRICE", ""),
+			Timeout:         timeout,
+		})
+	default:
+		return nil, fmt.Errorf("SMS_DRIVER không hợp lệ: %s (twilio, sns)", driver)
+	}
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultValue
 }
+
+// ProvidePresenceStore provides online status của user (socket hub ghi, friend module đọc), Redis để các instance
+// dùng chung, in-memory khi cache không phải Redis
+func ProvidePresenceStore(cacheClient cache.Cache) socket.PresenceStore {
+	client := cacheClient.GetRedisClient()
+	if client == nil {
+		return socket.NewMemoryPresenceStore()
+	}
+	return socket.NewRedisPresenceStore(client, "")
+}
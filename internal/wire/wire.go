@@ -4,13 +4,25 @@
 package wire
 
 import (
-	"api-core/internal/app/auth"
-	"api-core/internal/app/chat"
-	"api-core/internal/app/friend"
-	"api-core/internal/app/user"
-	repository "api-core/internal/repositories"
-	"api-core/internal/routes"
-	"api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	"github.com/anhnq996/go-api-core/internal/app/chat"
+	"github.com/anhnq996/go-api-core/internal/app/cronjob"
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/internal/app/e2e"
+	"github.com/anhnq996/go-api-core/internal/app/friend"
+	"github.com/anhnq996/go-api-core/internal/app/loglevel"
+	"github.com/anhnq996/go-api-core/internal/app/notification"
+	"github.com/anhnq996/go-api-core/internal/app/queueadmin"
+	"github.com/anhnq996/go-api-core/internal/app/role"
+	"github.com/anhnq996/go-api-core/internal/app/status"
+	syncapp "github.com/anhnq996/go-api-core/internal/app/sync"
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/app/user"
+	"github.com/anhnq996/go-api-core/internal/app/webhook"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/internal/routes"
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/cache"
 
 	"github.com/google/wire"
 	"gorm.io/gorm"
@@ -21,33 +33,118 @@ func InitializeApp(db *gorm.DB, cacheClient cache.Cache) (*routes.Controllers, e
 	wire.Build(
 		// JWT
 		ProvideJWTManager,
+		ProvideTokenVersions,
 		ProvideJWTBlacklist,
+		ProvidePermissionChecker,
+		ProvideIntrospector,
 
 		// Storage
 		ProvideStorageManager,
+		ProvideResumableUploads,
+		ProvidePresignedUploadConfig,
 
 		// FCM (optional)
 		ProvideFCMClient,
 
+		// Social login providers
+		ProvideSocialProviders,
+
+		// Email (suppression-aware) + magic link (passwordless login)
+		ProvideMailer,
+		ProvideEmailService,
+		ProvideMagicLink,
+
+		// Webhooks
+		ProvideWebhookConfig,
+		ProvideChatConfig,
+
+		// Outbound request tới URL do user cung cấp (link preview, avatar từ URL, webhook)
+		ProvideSafeHTTPClient,
+
+		// Status page (health check của từng component)
+		ProvideStatusConfig,
+		ProvideStatusCheckers,
+
+		// E2E test data API (APP_ENV=test)
+		ProvideE2EConfig,
+
+		// Queue admin API (queue backend + trạng thái pause dùng chung với worker)
+		ProvideQueueManager,
+		ProvideQueueControl,
+
+		// Cron job admin API (trạng thái job, pause/trigger và lock dùng chung với scheduler)
+		ProvideCronControl,
+		ProvideCronLockManager,
+
+		// Log level admin API (level đặt lúc chạy dùng chung với mọi process)
+		ProvideLogLevelStore,
+
+		// Online status dùng chung với socket hub của mọi instance
+		ProvidePresenceStore,
+
+		// Route groups (public, authenticated, admin, internal)
+		ProvideRoutePolicies,
+
 		// Repositories (cần DB)
 		repository.NewUserRepository,
+		repository.NewSocialAccountRepository,
 		repository.NewFriendRequestRepository,
 		repository.NewFriendshipRepository,
 		repository.NewConversationRepository,
 		repository.NewConversationParticipantRepository,
 		repository.NewMessageRepository,
+		repository.NewEmailSuppressionRepository,
+		repository.NewChatEventRepository,
+		repository.NewRoleRepository,
+		repository.NewPermissionRepository,
+		repository.NewSyncRepository,
+		repository.NewStatusCheckRepository,
+		repository.NewStatusIncidentRepository,
+		repository.NewStorageUsageRepository,
+		repository.NewStorageFileRepository,
+		repository.NewFileReferenceRepository,
+		repository.NewCronJobRunRepository,
+		repository.NewDeviceTokenRepository,
+		repository.NewNotificationRepository,
+
+		// Push notification tới thiết bị (FCM, APNs cho ios nếu PUSH_PROVIDER_IOS=apns)
+		ProvideAPNsClient,
+		ProvidePusher,
 
 		// Services (cần Repo + Cache + Storage + FCM)
 		user.NewService,
 		auth.NewService,
 		friend.NewService,
 		chat.NewService,
+		role.NewService,
+		syncapp.NewService,
+		webhook.NewService,
+		status.NewService,
+		queueadmin.NewService,
+		cronjob.NewService,
+		loglevel.NewService,
+		device.NewService,
+		notification.NewService,
+		e2e.NewService,
+		upload.NewService,
+		upload.NewDownloads,
 
 		// Handlers
 		user.NewHandler,
 		auth.NewHandler,
 		friend.NewHandler,
 		chat.NewHandler,
+		role.NewHandler,
+		syncapp.NewHandler,
+		webhook.NewHandler,
+		status.NewHandler,
+		queueadmin.NewHandler,
+		cronjob.NewHandler,
+		loglevel.NewHandler,
+		device.NewHandler,
+		notification.NewHandler,
+		e2e.NewHandler,
+		upload.NewHandler,
 
 		// Controllers
 		routes.NewControllers,
@@ -59,6 +156,33 @@ func InitializeApp(db *gorm.DB, cacheClient cache.Cache) (*routes.Controllers, e
 	return nil, nil // Wire sẽ thay thế dòng này
 }
 
+// InitializeWorkers khởi tạo message handlers cho process chạy queue worker
+func InitializeWorkers(db *gorm.DB) (*workers.Handlers, error) {
+	wire.Build(
+		// Email (suppression-aware)
+		repository.NewEmailSuppressionRepository,
+		ProvideMailer,
+		ProvideEmailService,
+
+		// Push notification (FCM/APNs optional, token lỗi bị xóa khỏi device_tokens)
+		ProvideFCMClient,
+		ProvideAPNsClient,
+		repository.NewDeviceTokenRepository,
+		ProvidePusher,
+
+		// SMS (Twilio/SNS optional)
+		ProvideSMSSender,
+
+		// Handlers
+		workers.NewEmailHandler,
+		workers.NewPushHandler,
+		workers.NewSMSHandler,
+		workers.NewHandlers,
+	)
+
+	return nil, nil // Wire sẽ thay thế dòng này
+}
+
 // ProvideCacheInterface provides cache interface for rate limiting
 func ProvideCacheInterface(cacheClient cache.Cache) routes.CacheInterface {
 	return cacheClient
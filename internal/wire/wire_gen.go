@@ -7,13 +7,25 @@
 package wire
 
 import (
-	"api-core/internal/app/auth"
-	"api-core/internal/app/chat"
-	"api-core/internal/app/friend"
-	"api-core/internal/app/user"
-	"api-core/internal/repositories"
-	"api-core/internal/routes"
-	"api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	"github.com/anhnq996/go-api-core/internal/app/chat"
+	"github.com/anhnq996/go-api-core/internal/app/cronjob"
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/internal/app/e2e"
+	"github.com/anhnq996/go-api-core/internal/app/friend"
+	"github.com/anhnq996/go-api-core/internal/app/loglevel"
+	"github.com/anhnq996/go-api-core/internal/app/notification"
+	"github.com/anhnq996/go-api-core/internal/app/queueadmin"
+	"github.com/anhnq996/go-api-core/internal/app/role"
+	"github.com/anhnq996/go-api-core/internal/app/status"
+	"github.com/anhnq996/go-api-core/internal/app/sync"
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/app/user"
+	"github.com/anhnq996/go-api-core/internal/app/webhook"
+	"github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/internal/routes"
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/cache"
 	"gorm.io/gorm"
 )
 
@@ -22,7 +34,10 @@ import (
 // InitializeApp khởi tạo toàn bộ ứng dụng với database và cache
 func InitializeApp(db *gorm.DB, cacheClient cache.Cache) (*routes.Controllers, error) {
 	userRepository := repository.NewUserRepository(db)
-	storageManager, err := ProvideStorageManager()
+	emailSuppressionRepository := repository.NewEmailSuppressionRepository(db)
+	storageUsageRepository := repository.NewStorageUsageRepository(db)
+	storageFileRepository := repository.NewStorageFileRepository(db)
+	storageManager, err := ProvideStorageManager(storageUsageRepository, storageFileRepository)
 	if err != nil {
 		return nil, err
 	}
@@ -30,26 +45,131 @@ func InitializeApp(db *gorm.DB, cacheClient cache.Cache) (*routes.Controllers, e
 	if err != nil {
 		return nil, err
 	}
-	service := user.NewService(userRepository, cacheClient, storageManager, client)
+	safehttpClient, err := ProvideSafeHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+	deviceTokenRepository := repository.NewDeviceTokenRepository(db)
+	notificationRepository := repository.NewNotificationRepository(db)
+	apnsClient, err := ProvideAPNsClient()
+	if err != nil {
+		return nil, err
+	}
+	pusher, err := ProvidePusher(client, apnsClient, deviceTokenRepository)
+	if err != nil {
+		return nil, err
+	}
+	service := user.NewService(userRepository, emailSuppressionRepository, notificationRepository, cacheClient, storageManager, pusher, safehttpClient)
 	handler := user.NewHandler(service)
-	manager := ProvideJWTManager()
-	blacklist := ProvideJWTBlacklist(cacheClient)
-	authService := auth.NewService(userRepository, manager, blacklist, storageManager)
+	socialAccountRepository := repository.NewSocialAccountRepository(db)
+	manager := ProvideJWTManager(cacheClient)
+	tokenVersions := ProvideTokenVersions(cacheClient, userRepository)
+	blacklist := ProvideJWTBlacklist(cacheClient, tokenVersions)
+	socialProviders := ProvideSocialProviders()
+	mailerMailer, err := ProvideMailer()
+	if err != nil {
+		return nil, err
+	}
+	emailService := ProvideEmailService(mailerMailer, emailSuppressionRepository)
+	magicLink := ProvideMagicLink(emailService)
+	roleRepository := repository.NewRoleRepository(db)
+	permissionChecker := ProvidePermissionChecker(cacheClient, userRepository, manager)
+	authService := auth.NewService(userRepository, socialAccountRepository, manager, blacklist, storageManager, cacheClient, socialProviders, magicLink, roleRepository, permissionChecker, tokenVersions)
 	authHandler := auth.NewHandler(authService)
 	friendRequestRepository := repository.NewFriendRequestRepository(db)
 	friendshipRepository := repository.NewFriendshipRepository(db)
-	friendService := friend.NewService(friendRequestRepository, friendshipRepository, userRepository, db)
+	presenceStore := ProvidePresenceStore(cacheClient)
+	friendService := friend.NewService(friendRequestRepository, friendshipRepository, userRepository, presenceStore, db)
 	friendHandler := friend.NewHandler(friendService)
+	permissionRepository := repository.NewPermissionRepository(db)
+	roleService := role.NewService(roleRepository, permissionRepository, userRepository, permissionChecker)
+	roleHandler := role.NewHandler(roleService)
+	syncRepository := repository.NewSyncRepository(db)
+	syncService := sync.NewService(syncRepository)
+	syncHandler := sync.NewHandler(syncService)
 	conversationRepository := repository.NewConversationRepository(db)
 	conversationParticipantRepository := repository.NewConversationParticipantRepository(db)
 	messageRepository := repository.NewMessageRepository(db)
-	chatService := chat.NewService(conversationRepository, conversationParticipantRepository, messageRepository, friendshipRepository, userRepository, db)
+	chatEventRepository := repository.NewChatEventRepository(db)
+	config := ProvideChatConfig()
+	chatService := chat.NewService(conversationRepository, conversationParticipantRepository, messageRepository, friendshipRepository, userRepository, chatEventRepository, safehttpClient, db, config)
 	chatHandler := chat.NewHandler(chatService)
+	webhookConfig := ProvideWebhookConfig()
+	webhookService := webhook.NewService(emailSuppressionRepository, webhookConfig, safehttpClient)
+	webhookHandler := webhook.NewHandler(webhookService)
+	statusCheckRepository := repository.NewStatusCheckRepository(db)
+	statusIncidentRepository := repository.NewStatusIncidentRepository(db)
+	checkers := ProvideStatusCheckers(db, cacheClient, storageManager, client)
+	statusConfig := ProvideStatusConfig()
+	statusService := status.NewService(statusCheckRepository, statusIncidentRepository, cacheClient, checkers, statusConfig)
+	statusHandler := status.NewHandler(statusService)
+	queueManager := ProvideQueueManager()
+	control := ProvideQueueControl(cacheClient)
+	queueadminService := queueadmin.NewService(queueManager, control)
+	queueadminHandler := queueadmin.NewHandler(queueadminService)
+	cronJobRunRepository := repository.NewCronJobRunRepository(db)
+	cronControl := ProvideCronControl(cacheClient)
+	lockManager := ProvideCronLockManager(cacheClient)
+	cronjobService := cronjob.NewService(cronJobRunRepository, cronControl, lockManager)
+	cronjobHandler := cronjob.NewHandler(cronjobService)
+	levelStore := ProvideLogLevelStore(cacheClient)
+	loglevelService := loglevel.NewService(levelStore)
+	loglevelHandler := loglevel.NewHandler(loglevelService)
+	deviceService := device.NewService(deviceTokenRepository)
+	deviceHandler := device.NewHandler(deviceService)
+	notificationService := notification.NewService(notificationRepository)
+	notificationHandler := notification.NewHandler(notificationService)
+	e2eConfig := ProvideE2EConfig()
+	e2eService := e2e.NewService(db, cacheClient, userRepository, roleRepository, authService, e2eConfig)
+	e2eHandler := e2e.NewHandler(e2eService)
+	resumableHandler, err := ProvideResumableUploads(storageManager, cacheClient)
+	if err != nil {
+		return nil, err
+	}
+	uploadConfig := ProvidePresignedUploadConfig()
+	uploadService := upload.NewService(storageManager, cacheClient, uploadConfig)
+	uploadHandler := upload.NewHandler(uploadService)
+	fileReferenceRepository := repository.NewFileReferenceRepository(db)
+	downloads := upload.NewDownloads(storageManager, fileReferenceRepository)
+	introspector := ProvideIntrospector(manager, blacklist)
 	cacheInterface := ProvideCacheInterface(cacheClient)
-	controllers := routes.NewControllers(handler, authHandler, friendHandler, chatHandler, manager, blacklist, cacheInterface)
+	policies := ProvideRoutePolicies()
+	controllers := routes.NewControllers(handler, authHandler, friendHandler, roleHandler, syncHandler, chatHandler, webhookHandler, statusHandler, statusService, queueadminHandler, cronjobHandler, loglevelHandler, deviceHandler, notificationHandler, e2eHandler, resumableHandler, uploadHandler, downloads, presenceStore, manager, blacklist, permissionChecker, introspector, cacheInterface, policies)
 	return controllers, nil
 }
 
+// InitializeWorkers khởi tạo message handlers cho process chạy queue worker
+func InitializeWorkers(db *gorm.DB) (*workers.Handlers, error) {
+	emailSuppressionRepository := repository.NewEmailSuppressionRepository(db)
+	mailerMailer, err := ProvideMailer()
+	if err != nil {
+		return nil, err
+	}
+	emailService := ProvideEmailService(mailerMailer, emailSuppressionRepository)
+	emailHandler := workers.NewEmailHandler(emailService)
+	client, err := ProvideFCMClient()
+	if err != nil {
+		return nil, err
+	}
+	apnsClient, err := ProvideAPNsClient()
+	if err != nil {
+		return nil, err
+	}
+	deviceTokenRepository := repository.NewDeviceTokenRepository(db)
+	pusher, err := ProvidePusher(client, apnsClient, deviceTokenRepository)
+	if err != nil {
+		return nil, err
+	}
+	pushHandler := workers.NewPushHandler(pusher)
+	sender, err := ProvideSMSSender()
+	if err != nil {
+		return nil, err
+	}
+	smsHandler := workers.NewSMSHandler(sender)
+	handlers := workers.NewHandlers(emailHandler, pushHandler, smsHandler)
+	return handlers, nil
+}
+
 // wire.go:
 
 // ProvideCacheInterface provides cache interface for rate limiting
@@ -0,0 +1,62 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+)
+
+// QueueEmails queue gửi email, message data là email.EmailMessage dạng JSON
+const QueueEmails = "emails"
+
+// errInvalidMessage message không đọc được, retry cũng không thành công
+var errInvalidMessage = errors.New("invalid message")
+
+// Handlers tất cả message handlers của worker (wire inject dependencies)
+type Handlers struct {
+	Email *EmailHandler
+	Push  *PushHandler
+	SMS   *SMSHandler
+}
+
+// NewHandlers tạo handlers
+func NewHandlers(emailHandler *EmailHandler, pushHandler *PushHandler, smsHandler *SMSHandler) *Handlers {
+	return &Handlers{Email: emailHandler, Push: pushHandler, SMS: smsHandler}
+}
+
+// EmailHandler gửi email từ queue.
+// Lỗi SMTP thường tạm thời nên retry với backoff tăng dần, delay gốc và số lần lấy từ WORKER_RETRY_DELAY/WORKER_MAX_RETRIES.
+type EmailHandler struct {
+	_ struct{} `retry:"backoff=exponential,max=5m,jitter"`
+
+	emailService email.EmailService
+}
+
+// NewEmailHandler tạo email handler
+func NewEmailHandler(emailService email.EmailService) *EmailHandler {
+	return &EmailHandler{emailService: emailService}
+}
+
+// Handle implement queue.MessageHandler
+func (h *EmailHandler) Handle(ctx context.Context, message *queue.Message) error {
+	var msg email.EmailMessage
+	if err := json.Unmarshal(message.Data, &msg); err != nil {
+		return queue.Permanent(fmt.Errorf("%w: %v", errInvalidMessage, err))
+	}
+	if len(msg.To) == 0 {
+		return queue.Permanent(fmt.Errorf("%w: no recipients", errInvalidMessage))
+	}
+
+	return h.emailService.Send(&msg)
+}
+
+// OnError implement queue.MessageHandler, message không hợp lệ được đánh dấu queue.Permanent nên không retry
+func (h *EmailHandler) OnError(ctx context.Context, message *queue.Message, err error) error {
+	logger.Errorf("Failed to process email message %s (retry %d): %v", message.ID, message.RetryCount, err)
+	return nil
+}
@@ -0,0 +1,61 @@
+package workers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+)
+
+// Giới hạn mặc định của queue notifications (mỗi worker process), ghi đè bằng WORKER_QUEUE_RATE_LIMIT
+const (
+	pushRateLimit = 100 // message/giây
+	pushRateBurst = 20
+)
+
+// PushHandler gửi push notification từ queue (job device.PushJob).
+// Lỗi FCM tạm thời được retry với backoff tăng dần, hết lượt retry message vào "notifications:dead".
+type PushHandler struct {
+	_ struct{} `retry:"backoff=exponential,max=5m,jitter"`
+
+	pusher *device.Pusher
+}
+
+// NewPushHandler tạo push handler
+func NewPushHandler(pusher *device.Pusher) *PushHandler {
+	return &PushHandler{pusher: pusher}
+}
+
+// Handle implement queue.MessageHandler
+func (h *PushHandler) Handle(ctx context.Context, message *queue.Message) error {
+	job, err := device.PushJob.Decode(message)
+	if err != nil {
+		return err
+	}
+
+	// Worker chưa cấu hình FCM: bỏ qua, thông báo vẫn nằm trong notification center
+	if !h.pusher.Enabled() {
+		logger.Warnf("FCM is not configured, dropping push message %s", message.ID)
+		return nil
+	}
+
+	push := job.Payload
+	success, failure, err := h.pusher.Send(ctx, push)
+	if errors.Is(err, device.ErrNoDevices) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Infof("Push notification sent to user %s: %d devices, %d failed", push.UserID, success, failure)
+	return nil
+}
+
+// OnError implement queue.MessageHandler, message không hợp lệ được đánh dấu queue.Permanent nên không retry
+func (h *PushHandler) OnError(ctx context.Context, message *queue.Message, err error) error {
+	logger.Errorf("Failed to process push message %s (retry %d): %v", message.ID, message.RetryCount, err)
+	return nil
+}
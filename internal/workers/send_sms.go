@@ -0,0 +1,65 @@
+package workers
+
+import (
+	"context"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/sms"
+)
+
+// QueueSMS queue gửi SMS (thông báo, OTP)
+const QueueSMS = "sms"
+
+// Giới hạn mặc định của queue sms (mỗi worker process), ghi đè bằng WORKER_QUEUE_RATE_LIMIT
+const (
+	smsRateLimit = 10 // message/giây
+	smsRateBurst = 5
+)
+
+// SMSJob job gửi một SMS, đưa vào QueueSMS:
+//
+//	msg, err := workers.SMSJob.NewMessage(sms.Message{To: phone, Body: body})
+//	err = outbox.Enqueue(tx, workers.QueueSMS, msg)
+var SMSJob = queue.DefineJob[sms.Message]("sms.send", 1)
+
+// SMSHandler gửi SMS từ queue (job SMSJob).
+// Lỗi provider tạm thời được retry với backoff tăng dần, số không hợp lệ hoặc bị provider từ chối không retry.
+type SMSHandler struct {
+	_ struct{} `retry:"backoff=exponential,max=5m,jitter"`
+
+	sender sms.Sender
+}
+
+// NewSMSHandler tạo SMS handler, sender nil khi chưa cấu hình SMS_DRIVER
+func NewSMSHandler(sender sms.Sender) *SMSHandler {
+	return &SMSHandler{sender: sender}
+}
+
+// Handle implement queue.MessageHandler
+func (h *SMSHandler) Handle(ctx context.Context, message *queue.Message) error {
+	job, err := SMSJob.Decode(message)
+	if err != nil {
+		return err
+	}
+
+	if h.sender == nil {
+		logger.Warnf("SMS is not configured, dropping sms message %s", message.ID)
+		return nil
+	}
+
+	msg := job.Payload
+	if err := h.sender.Send(ctx, &msg); err != nil {
+		if sms.IsPermanent(err) {
+			return queue.Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
+
+// OnError implement queue.MessageHandler
+func (h *SMSHandler) OnError(ctx context.Context, message *queue.Message, err error) error {
+	logger.Errorf("Failed to process sms message %s (retry %d): %v", message.ID, message.RetryCount, err)
+	return nil
+}
@@ -0,0 +1,345 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+)
+
+// HandlerConfig handler xử lý message của 1 queue
+type HandlerConfig struct {
+	Queue   string
+	Handler queue.MessageHandler
+	// RetryPolicy ghi đè policy handler tự khai báo (nil = theo handler, xem queue.ResolveRetryPolicy)
+	RetryPolicy *queue.RetryPolicy
+	// Concurrency số worker của queue (0 = theo ConsumerOptions.Concurrency)
+	Concurrency int
+	// RateLimit số message/giây tối đa (0 = không giới hạn), RateBurst số message được vượt tức thời (0 = 1)
+	RateLimit float64
+	RateBurst int
+	// MaxInFlight số message xử lý đồng thời tối đa (0 = không giới hạn ngoài Concurrency)
+	MaxInFlight int
+	// Limiter dùng chung giữa các queue, ưu tiên hơn RateLimit/MaxInFlight
+	Limiter *queue.Limiter
+}
+
+// RegisterOption tùy chọn khi đăng ký handler
+type RegisterOption func(*HandlerConfig)
+
+// WithRetryPolicy đặt retry policy cho queue, ưu tiên hơn policy khai báo trên handler
+func WithRetryPolicy(policy queue.RetryPolicy) RegisterOption {
+	return func(h *HandlerConfig) {
+		h.RetryPolicy = &policy
+	}
+}
+
+// WithConcurrency đặt số worker xử lý song song của queue
+func WithConcurrency(n int) RegisterOption {
+	return func(h *HandlerConfig) {
+		h.Concurrency = n
+	}
+}
+
+// WithRateLimit giới hạn số message/giây của queue (mỗi worker process), burst <= 0 là 1
+func WithRateLimit(perSecond float64, burst int) RegisterOption {
+	return func(h *HandlerConfig) {
+		h.RateLimit = perSecond
+		h.RateBurst = burst
+	}
+}
+
+// WithMaxInFlight giới hạn số message của queue xử lý đồng thời (mỗi worker process)
+func WithMaxInFlight(n int) RegisterOption {
+	return func(h *HandlerConfig) {
+		h.MaxInFlight = n
+	}
+}
+
+// WithLimiter dùng chung limiter giữa các queue gọi cùng provider, ví dụ queue "push" và "push-bulk" cùng gọi FCM:
+//
+//	fcm := queue.NewLimiter(500, 50, 20)
+//	wm.RegisterHandler("push.send", sendPush, workers.OnQueue("push"), workers.WithLimiter(fcm))
+//	wm.RegisterHandler("push.broadcast", broadcast, workers.OnQueue("push-bulk"), workers.WithLimiter(fcm))
+func WithLimiter(limiter *queue.Limiter) RegisterOption {
+	return func(h *HandlerConfig) {
+		h.Limiter = limiter
+	}
+}
+
+// OnQueue queue của job đăng ký bằng RegisterHandler (mặc định DefaultQueue)
+func OnQueue(name string) RegisterOption {
+	return func(h *HandlerConfig) {
+		h.Queue = name
+	}
+}
+
+// DefaultQueue queue mặc định của job đăng ký bằng RegisterHandler
+const DefaultQueue = "default"
+
+// WorkerManager quản lý consumers của tất cả queues, chạy trong process có APP_ROLE=worker|all
+type WorkerManager struct {
+	manager   queue.QueueManager
+	options   *queue.ConsumerOptions
+	handlers  []HandlerConfig
+	consumers []queue.Consumer
+
+	dispatchers map[string]*queue.Dispatcher // Dispatcher theo queue của RegisterHandler
+	concurrency map[string]int               // Ghi đè concurrency theo queue (WORKER_QUEUE_CONCURRENCY, queue:work)
+	rateLimit   map[string]float64           // Ghi đè rate limit theo queue (WORKER_QUEUE_RATE_LIMIT)
+	maxInFlight map[string]int               // Ghi đè max in-flight theo queue (WORKER_QUEUE_MAX_IN_FLIGHT)
+	only        []string                     // Chỉ chạy các queue này (rỗng = tất cả)
+}
+
+// NewWorkerManager tạo worker manager mới
+func NewWorkerManager(manager queue.QueueManager, options *queue.ConsumerOptions) *WorkerManager {
+	return &WorkerManager{
+		manager:     manager,
+		options:     options,
+		dispatchers: make(map[string]*queue.Dispatcher),
+		concurrency: make(map[string]int),
+		rateLimit:   make(map[string]float64),
+		maxInFlight: make(map[string]int),
+	}
+}
+
+// Register đăng ký handler cho queue, gọi trước Start
+func (wm *WorkerManager) Register(queueName string, handler queue.MessageHandler, opts ...RegisterOption) {
+	h := HandlerConfig{Queue: queueName, Handler: handler}
+	for _, opt := range opts {
+		opt(&h)
+	}
+	wm.handlers = append(wm.handlers, h)
+}
+
+// RegisterHandler đăng ký handler cho loại job (header "type" của message, xem queue.NewJobMessage).
+// Các job cùng queue dùng chung một queue.Dispatcher, option (retry, concurrency) áp dụng cho cả queue:
+//
+//	wm.RegisterHandler("email.send", sendEmail, workers.OnQueue("emails"), workers.WithConcurrency(8))
+func (wm *WorkerManager) RegisterHandler(jobType string, fn queue.HandlerFunc, opts ...RegisterOption) {
+	h := HandlerConfig{Queue: DefaultQueue}
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	dispatcher, ok := wm.dispatchers[h.Queue]
+	if !ok {
+		dispatcher = queue.NewDispatcher()
+		wm.dispatchers[h.Queue] = dispatcher
+		h.Handler = dispatcher
+		wm.handlers = append(wm.handlers, h)
+	} else {
+		for i := range wm.handlers {
+			if wm.handlers[i].Handler == queue.MessageHandler(dispatcher) {
+				mergeHandlerConfig(&wm.handlers[i], h)
+			}
+		}
+	}
+	dispatcher.RegisterHandler(jobType, fn)
+}
+
+// RegisterJob đăng ký handler nhận payload đã decode của loại job có version (queue.DefineJob):
+//
+//	workers.RegisterJob(wm, jobs.ExportReport, exportReport, workers.OnQueue("reports"))
+func RegisterJob[T any](wm *WorkerManager, job *queue.JobType[T], fn func(ctx context.Context, job *queue.TypedJob[T]) error, opts ...RegisterOption) {
+	wm.RegisterHandler(job.Name(), job.Handler(fn), opts...)
+}
+
+// mergeHandlerConfig option của lần đăng ký sau ghi đè lần trước (nếu có đặt)
+func mergeHandlerConfig(dst *HandlerConfig, src HandlerConfig) {
+	if src.RetryPolicy != nil {
+		dst.RetryPolicy = src.RetryPolicy
+	}
+	if src.Concurrency > 0 {
+		dst.Concurrency = src.Concurrency
+	}
+	if src.RateLimit > 0 {
+		dst.RateLimit = src.RateLimit
+		dst.RateBurst = src.RateBurst
+	}
+	if src.MaxInFlight > 0 {
+		dst.MaxInFlight = src.MaxInFlight
+	}
+	if src.Limiter != nil {
+		dst.Limiter = src.Limiter
+	}
+}
+
+// SetConcurrency ghi đè số worker của queue (ưu tiên hơn WithConcurrency), gọi trước Start
+func (wm *WorkerManager) SetConcurrency(queueName string, n int) {
+	wm.concurrency[queueName] = n
+}
+
+// SetRateLimit ghi đè số message/giây của queue (ưu tiên hơn WithRateLimit, không áp dụng cho WithLimiter), gọi trước Start
+func (wm *WorkerManager) SetRateLimit(queueName string, perSecond float64) {
+	wm.rateLimit[queueName] = perSecond
+}
+
+// SetMaxInFlight ghi đè số message xử lý đồng thời của queue (ưu tiên hơn WithMaxInFlight, không áp dụng cho WithLimiter), gọi trước Start
+func (wm *WorkerManager) SetMaxInFlight(queueName string, n int) {
+	wm.maxInFlight[queueName] = n
+}
+
+// Only chỉ chạy consumer của các queue này, gọi trước Start. Queue chưa đăng ký handler: Start trả lỗi.
+func (wm *WorkerManager) Only(queues ...string) {
+	wm.only = queues
+}
+
+// RegisterAllHandlers đăng ký tất cả handlers
+func (wm *WorkerManager) RegisterAllHandlers(handlers *Handlers) {
+	wm.Register(QueueEmails, handlers.Email)
+	wm.Register(device.QueueNotifications, handlers.Push, WithRateLimit(pushRateLimit, pushRateBurst))
+	wm.Register(QueueSMS, handlers.SMS, WithRateLimit(smsRateLimit, smsRateBurst))
+}
+
+// Queues danh sách queue đã đăng ký handler
+func (wm *WorkerManager) Queues() []string {
+	names := make([]string, 0, len(wm.handlers))
+	for _, h := range wm.handlers {
+		names = append(names, h.Queue)
+	}
+	return names
+}
+
+// RunningQueues queue đang có consumer (sau Start)
+func (wm *WorkerManager) RunningQueues() []string {
+	names := make([]string, 0, len(wm.consumers))
+	for _, consumer := range wm.consumers {
+		names = append(names, consumer.GetQueue().GetName())
+	}
+	return names
+}
+
+// Start tạo queue và bắt đầu consumer cho từng handler
+func (wm *WorkerManager) Start(ctx context.Context) error {
+	registered := wm.Queues()
+	for _, name := range wm.only {
+		if !slices.Contains(registered, name) {
+			return fmt.Errorf("no handler registered for queue %s", name)
+		}
+	}
+
+	for _, h := range wm.handlers {
+		if len(wm.only) > 0 && !slices.Contains(wm.only, h.Queue) {
+			continue
+		}
+
+		q, err := wm.manager.CreateQueue(ctx, h.Queue, nil)
+		if err != nil {
+			wm.Stop()
+			return fmt.Errorf("failed to create queue %s: %w", h.Queue, err)
+		}
+
+		if wm.options != nil && wm.options.Metrics != nil {
+			wm.options.Metrics.Watch(q)
+		}
+
+		// Message hết lượt retry hoặc lỗi Permanent được chuyển vào "<queue>:dead", xem queue.DeadLetterManager
+		dead, err := wm.manager.CreateQueue(ctx, queue.DeadQueueName(h.Queue), nil)
+		if err != nil {
+			wm.Stop()
+			return fmt.Errorf("failed to create dead queue for %s: %w", h.Queue, err)
+		}
+
+		options := wm.consumerOptions(h)
+		options.DeadLetterQueue = dead
+		consumer := queue.NewConsumer(q, h.Handler, options)
+		if err := consumer.Start(ctx); err != nil {
+			wm.Stop()
+			return fmt.Errorf("failed to start consumer for queue %s: %w", h.Queue, err)
+		}
+		wm.consumers = append(wm.consumers, consumer)
+		if options.Limiter != nil {
+			logger.Infof("Worker consuming queue: %s (concurrency %d, %s)", h.Queue, options.Concurrency, options.Limiter)
+		} else {
+			logger.Infof("Worker consuming queue: %s (concurrency %d)", h.Queue, options.Concurrency)
+		}
+	}
+
+	// Admin API liệt kê queue theo danh sách worker đã ghi nhận
+	if wm.options != nil && wm.options.Control != nil {
+		if err := wm.options.Control.Register(ctx, wm.RunningQueues()...); err != nil {
+			logger.Warnf("Failed to register queues for admin API: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// consumerOptions options của consumer cho 1 handler, gắn retry policy và concurrency đăng ký riêng (nếu có)
+func (wm *WorkerManager) consumerOptions(h HandlerConfig) *queue.ConsumerOptions {
+	base := wm.options
+	if base == nil {
+		base = queue.DefaultConsumerOptions()
+	}
+
+	options := *base
+	if h.RetryPolicy != nil {
+		options.RetryPolicy = h.RetryPolicy
+	}
+	options.Concurrency = wm.concurrencyOf(h)
+	if limiter := wm.limiterOf(h); limiter != nil {
+		options.Limiter = limiter
+	}
+	return &options
+}
+
+// limiterOf limiter của queue: WithLimiter > SetRateLimit/SetMaxInFlight > WithRateLimit/WithMaxInFlight, nil nếu không giới hạn
+func (wm *WorkerManager) limiterOf(h HandlerConfig) *queue.Limiter {
+	if h.Limiter != nil {
+		return h.Limiter
+	}
+	perSecond, maxInFlight := h.RateLimit, h.MaxInFlight
+	if r := wm.rateLimit[h.Queue]; r > 0 {
+		perSecond = r
+	}
+	if n := wm.maxInFlight[h.Queue]; n > 0 {
+		maxInFlight = n
+	}
+	if perSecond <= 0 && maxInFlight <= 0 {
+		return nil
+	}
+	return queue.NewLimiter(perSecond, h.RateBurst, maxInFlight)
+}
+
+// concurrencyOf số worker của queue: SetConcurrency > WithConcurrency > ConsumerOptions.Concurrency > 1
+func (wm *WorkerManager) concurrencyOf(h HandlerConfig) int {
+	if n := wm.concurrency[h.Queue]; n > 0 {
+		return n
+	}
+	if h.Concurrency > 0 {
+		return h.Concurrency
+	}
+	if wm.options != nil && wm.options.Concurrency > 0 {
+		return wm.options.Concurrency
+	}
+	return 1
+}
+
+// Stop ngừng lấy message mới ở tất cả queue, chờ message đang xử lý hoàn tất
+// (tối đa ConsumerOptions.HandlerTimeout) rồi đóng kết nối queue
+func (wm *WorkerManager) Stop() error {
+	var wg sync.WaitGroup
+	for _, consumer := range wm.consumers {
+		wg.Add(1)
+		go func(consumer queue.Consumer) {
+			defer wg.Done()
+			if err := consumer.Stop(); err != nil {
+				logger.Warnf("Failed to stop consumer for queue %s: %v", consumer.GetQueue().GetName(), err)
+			}
+		}(consumer)
+	}
+	wg.Wait()
+	wm.consumers = nil
+
+	if err := wm.manager.Close(); err != nil {
+		return fmt.Errorf("failed to close queue manager: %w", err)
+	}
+
+	logger.Info("Worker manager stopped")
+	return nil
+}
@@ -3,6 +3,8 @@ package actionEvent
 import (
 	"context"
 	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/correlation"
 )
 
 // EventData represents the data structure for action events
@@ -13,15 +15,17 @@ type EventData struct {
 
 // Event represents a structured action event
 type Event struct {
-	Action    string    `json:"action"`    // create, update, delete, login, logout, etc.
-	Entity    string    `json:"entity"`    // user, product, order, etc.
-	EntityID  string    `json:"entity_id"` // UUID of the entity
-	UserID    string    `json:"user_id"`   // ID of user performing action
-	Data      EventData `json:"data"`      // Old and new data
-	Timestamp time.Time `json:"timestamp"`
-	IP        string    `json:"ip,omitempty"`
-	UserAgent string    `json:"user_agent,omitempty"`
-	Job       string    `json:"job"` // Dynamic job name
+	Action         string    `json:"action"`                    // create, update, delete, login, logout, etc.
+	Entity         string    `json:"entity"`                    // user, product, order, etc.
+	EntityID       string    `json:"entity_id"`                 // UUID of the entity
+	UserID         string    `json:"user_id"`                   // ID of user performing action
+	ImpersonatorID string    `json:"impersonator_id,omitempty"` // ID of admin acting as UserID (impersonation)
+	Data           EventData `json:"data"`                      // Old and new data
+	Timestamp      time.Time `json:"timestamp"`
+	IP             string    `json:"ip,omitempty"`
+	UserAgent      string    `json:"user_agent,omitempty"`
+	Job            string    `json:"job"`                  // Dynamic job name
+	RequestID      string    `json:"request_id,omitempty"` // Request ID (hoặc trace ID) của request/job ghi event
 }
 
 // EventLogger interface for logging action events
@@ -56,6 +60,12 @@ func NewService(lokiClient LokiClient) *Service {
 
 // LogEvent logs an event to Loki
 func (s *Service) LogEvent(ctx context.Context, event Event) error {
+	if event.ImpersonatorID == "" {
+		event.ImpersonatorID = ImpersonatorFromContext(ctx)
+	}
+	if event.RequestID == "" {
+		event.RequestID = correlation.FromContext(ctx)
+	}
 	return s.lokiClient.PushEventAsync(ctx, event.Job, event)
 }
 
@@ -156,6 +166,20 @@ func (s *Service) LogLogout(ctx context.Context, job, userID, ip, userAgent stri
 	return s.LogEvent(ctx, event)
 }
 
+// impersonatorKey context key for impersonator ID
+type impersonatorKey struct{}
+
+// WithImpersonator attaches impersonator ID to context, every event logged with this context carries it
+func WithImpersonator(ctx context.Context, impersonatorID string) context.Context {
+	return context.WithValue(ctx, impersonatorKey{}, impersonatorID)
+}
+
+// ImpersonatorFromContext returns impersonator ID from context (empty if none)
+func ImpersonatorFromContext(ctx context.Context) string {
+	impersonatorID, _ := ctx.Value(impersonatorKey{}).(string)
+	return impersonatorID
+}
+
 // Global service instance
 var GlobalService EventLogger
 
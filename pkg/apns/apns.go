@@ -0,0 +1,246 @@
+package apns
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/push"
+	"github.com/anhnq996/go-api-core/pkg/telemetry"
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Endpoint của APNs
+const (
+	EndpointProduction  = "https://api.push.apple.com"
+	EndpointDevelopment = "https://api.sandbox.push.apple.com"
+)
+
+// tokenTTL thời gian dùng lại provider token. Apple từ chối token cũ hơn 1 giờ (ExpiredProviderToken)
+// và token làm mới quá 20 phút một lần (TooManyProviderTokenUpdates)
+const tokenTTL = 50 * time.Minute
+
+// sendConcurrency số request gửi đồng thời của Send (HTTP/2 multiplex trên một kết nối)
+const sendConcurrency = 16
+
+var _ push.Notifier = (*Client)(nil)
+
+// Client gửi push trực tiếp tới APNs, xác thực bằng provider token (key .p8)
+type Client struct {
+	config     *Config
+	key        *ecdsa.PrivateKey
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// Config cấu hình cho APNs client
+type Config struct {
+	KeyFile    string        // Đường dẫn tới file AuthKey_<KeyID>.p8 tải từ Apple Developer
+	KeyID      string        // Key ID của key .p8
+	TeamID     string        // Team ID của tài khoản Apple Developer
+	Topic      string        // Bundle ID của app
+	Production bool          // true: api.push.apple.com, false: sandbox (bản build development)
+	Timeout    time.Duration // Timeout cho mỗi request
+	Endpoint   string        // Optional: URL APNs thay thế (test), bỏ trống theo Production
+}
+
+// NewClient tạo APNs client mới
+func NewClient(cfg *Config) (*Client, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config không được để trống")
+	}
+	if cfg.KeyFile == "" || cfg.KeyID == "" || cfg.TeamID == "" || cfg.Topic == "" {
+		return nil, fmt.Errorf("key file, key ID, team ID và topic không được để trống")
+	}
+
+	raw, err := os.ReadFile(cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("không thể đọc key file: %w", err)
+	}
+	key, err := parsePrivateKey(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	// Set default timeout nếu không có
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = EndpointDevelopment
+		if cfg.Production {
+			cfg.Endpoint = EndpointProduction
+		}
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+
+	// APNs chỉ nhận HTTP/2
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ForceAttemptHTTP2 = true
+
+	return &Client{
+		config:     cfg,
+		key:        key,
+		httpClient: &http.Client{Transport: tracing.Transport(transport), Timeout: cfg.Timeout},
+	}, nil
+}
+
+// parsePrivateKey đọc key .p8 (PKCS#8 PEM, ECDSA P-256)
+func parsePrivateKey(raw []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("key file không phải PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("không thể đọc private key: %w", err)
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("key file không phải ECDSA key")
+	}
+	return key, nil
+}
+
+// SendToToken gửi push tới một device token (hoặc push token của Live Activity), trả về apns-id
+func (c *Client) SendToToken(ctx context.Context, token string, message *push.Message) (string, error) {
+	telemetry.Track("apns.send")
+
+	if token == "" {
+		return "", fmt.Errorf("token không được để trống")
+	}
+
+	body, err := json.Marshal(buildPayload(message))
+	if err != nil {
+		return "", fmt.Errorf("không thể tạo payload: %w", err)
+	}
+	if len(body) > maxPayload {
+		return "", &Error{StatusCode: http.StatusRequestEntityTooLarge, Reason: ReasonPayloadTooLarge}
+	}
+
+	providerToken, err := c.providerToken()
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Endpoint+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	setHeaders(req.Header, c.config.Topic, message)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("không thể gửi message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return resp.Header.Get("apns-id"), nil
+	}
+
+	apnsErr := &Error{StatusCode: resp.StatusCode}
+	var errBody struct {
+		Reason    string `json:"reason"`
+		Timestamp int64  `json:"timestamp"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&errBody); err == nil {
+		apnsErr.Reason = errBody.Reason
+		if errBody.Timestamp > 0 {
+			apnsErr.Timestamp = time.UnixMilli(errBody.Timestamp)
+		}
+	}
+	// Provider token bị từ chối: lần gửi sau ký token mới
+	if apnsErr.Reason == ReasonExpiredProviderToken || apnsErr.Reason == ReasonInvalidProviderToken {
+		c.resetProviderToken()
+	}
+	return "", fmt.Errorf("không thể gửi message: %w", apnsErr)
+}
+
+// Send implement push.Notifier: gửi tới từng token (đồng thời, cùng kết nối HTTP/2).
+// Token Unregistered, BadDeviceToken, DeviceTokenNotForTopic nằm trong Result.Invalid
+func (c *Client) Send(ctx context.Context, tokens []string, message *push.Message) (*push.Result, error) {
+	telemetry.Track("apns.send_multicast")
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("danh sách tokens không được để trống")
+	}
+	// Key lỗi thì mọi token đều lỗi, trả lỗi thay vì gửi từng token
+	if _, err := c.providerToken(); err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(tokens))
+	sem := make(chan struct{}, sendConcurrency)
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, token string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, errs[i] = c.SendToToken(ctx, token, message)
+		}(i, token)
+	}
+	wg.Wait()
+
+	result := &push.Result{}
+	for i, err := range errs {
+		if err == nil {
+			result.SuccessCount++
+			continue
+		}
+		result.FailureCount++
+		if reason, ok := invalidReason(err); ok {
+			result.Invalid = append(result.Invalid, push.InvalidToken{Token: tokens[i], Reason: reason})
+		}
+	}
+	return result, nil
+}
+
+// providerToken JWT (ES256) xác thực với APNs, dùng lại tới khi gần hết hạn
+func (c *Client) providerToken() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if c.token != "" && now.Sub(c.issuedAt) < tokenTTL {
+		return c.token, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": c.config.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = c.config.KeyID
+
+	signed, err := token.SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("không thể ký provider token: %w", err)
+	}
+	c.token, c.issuedAt = signed, now
+	return signed, nil
+}
+
+func (c *Client) resetProviderToken() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = ""
+}
@@ -0,0 +1,61 @@
+package apns
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/push"
+)
+
+// Lý do lỗi APNs trả về (https://developer.apple.com/documentation/usernotifications/handling-notification-responses-from-apns)
+const (
+	ReasonBadDeviceToken              = "BadDeviceToken"
+	ReasonDeviceTokenNotForTopic      = "DeviceTokenNotForTopic"
+	ReasonUnregistered                = "Unregistered"
+	ReasonPayloadTooLarge             = "PayloadTooLarge"
+	ReasonExpiredProviderToken        = "ExpiredProviderToken"
+	ReasonInvalidProviderToken        = "InvalidProviderToken"
+	ReasonTooManyRequests             = "TooManyRequests"
+	ReasonBadTopic                    = "BadTopic"
+	ReasonTopicDisallowed             = "TopicDisallowed"
+	ReasonServiceUnavailable          = "ServiceUnavailable"
+	ReasonInternalServerError         = "InternalServerError"
+	ReasonTooManyProviderTokenUpdates = "TooManyProviderTokenUpdates"
+)
+
+// Error lỗi APNs trả về cho một token
+type Error struct {
+	StatusCode int
+	Reason     string
+	Timestamp  time.Time // Với 410 Unregistered: thời điểm token không còn hợp lệ
+}
+
+func (e *Error) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("apns: status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("apns: %s (status %d)", e.Reason, e.StatusCode)
+}
+
+// IsUnregistered lỗi do token không còn được đăng ký (app đã gỡ, kể cả lỗi đã wrap bởi SendToToken)
+func IsUnregistered(err error) bool {
+	var apnsErr *Error
+	return errors.As(err, &apnsErr) && (apnsErr.StatusCode == http.StatusGone || apnsErr.Reason == ReasonUnregistered)
+}
+
+// invalidReason token không còn dùng được (nên xóa khỏi database), lý do theo push.InvalidReason*
+func invalidReason(err error) (string, bool) {
+	var apnsErr *Error
+	if !errors.As(err, &apnsErr) {
+		return "", false
+	}
+	switch {
+	case IsUnregistered(err):
+		return push.InvalidReasonUnregistered, true
+	case apnsErr.Reason == ReasonBadDeviceToken, apnsErr.Reason == ReasonDeviceTokenNotForTopic:
+		return push.InvalidReasonInvalidArgument, true
+	}
+	return "", false
+}
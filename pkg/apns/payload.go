@@ -0,0 +1,157 @@
+package apns
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/push"
+)
+
+// maxPayload kích thước payload tối đa APNs nhận (byte)
+const maxPayload = 4096
+
+// buildPayload payload JSON gửi APNs: "aps" cộng các key của message.Data ở cấp ngoài cùng
+func buildPayload(message *push.Message) map[string]interface{} {
+	payload := make(map[string]interface{}, len(message.Data)+1)
+	for key, value := range message.Data {
+		payload[key] = value
+	}
+
+	aps := map[string]interface{}{}
+	if message.Title != "" || message.Body != "" {
+		alert := map[string]string{}
+		if message.Title != "" {
+			alert["title"] = message.Title
+		}
+		if message.Body != "" {
+			alert["body"] = message.Body
+		}
+		aps["alert"] = alert
+	}
+
+	if opts := message.APNS; opts != nil {
+		if opts.Badge != nil {
+			aps["badge"] = *opts.Badge
+		}
+		if opts.Critical {
+			// Critical alert: sound là dictionary, phát kể cả khi tắt tiếng
+			volume := opts.CriticalVolume
+			if volume <= 0 || volume > 1 {
+				volume = 1
+			}
+			name := opts.Sound
+			if name == "" {
+				name = "default"
+			}
+			aps["sound"] = map[string]interface{}{"critical": 1, "name": name, "volume": volume}
+			aps["interruption-level"] = push.InterruptionCritical
+		} else {
+			if opts.Sound != "" {
+				aps["sound"] = opts.Sound
+			}
+			if opts.InterruptionLevel != "" {
+				aps["interruption-level"] = opts.InterruptionLevel
+			}
+		}
+		if opts.ThreadID != "" {
+			aps["thread-id"] = opts.ThreadID
+		}
+		if opts.Category != "" {
+			aps["category"] = opts.Category
+		}
+		if opts.ContentAvailable {
+			aps["content-available"] = 1
+		}
+		if opts.MutableContent {
+			aps["mutable-content"] = 1
+		}
+		if activity := opts.LiveActivity; activity != nil {
+			addLiveActivity(aps, activity)
+		}
+	}
+
+	// APNs không tự hiển thị ảnh: notification service extension của app tải ảnh từ "image_url"
+	if message.ImageURL != "" {
+		aps["mutable-content"] = 1
+		payload["image_url"] = message.ImageURL
+	}
+
+	payload["aps"] = aps
+	return payload
+}
+
+// addLiveActivity trường aps của Live Activity
+func addLiveActivity(aps map[string]interface{}, activity *push.LiveActivity) {
+	event := activity.Event
+	if event == "" {
+		event = push.LiveActivityUpdate
+	}
+	aps["event"] = event
+
+	timestamp := activity.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	aps["timestamp"] = timestamp.Unix()
+
+	contentState := activity.ContentState
+	if contentState == nil {
+		contentState = map[string]interface{}{}
+	}
+	aps["content-state"] = contentState
+
+	if !activity.StaleDate.IsZero() {
+		aps["stale-date"] = activity.StaleDate.Unix()
+	}
+	if !activity.DismissalDate.IsZero() {
+		aps["dismissal-date"] = activity.DismissalDate.Unix()
+	}
+	if event == push.LiveActivityStart {
+		aps["attributes-type"] = activity.AttributesType
+		attributes := activity.Attributes
+		if attributes == nil {
+			attributes = map[string]interface{}{}
+		}
+		aps["attributes"] = attributes
+	}
+}
+
+// setHeaders header APNs theo loại push: apns-topic, apns-push-type, apns-priority, apns-expiration, apns-collapse-id
+func setHeaders(header http.Header, topic string, message *push.Message) {
+	pushType := push.APNSPushTypeAlert
+	priority := 10
+	opts := message.APNS
+	if opts != nil {
+		switch {
+		case opts.LiveActivity != nil:
+			pushType = push.APNSPushTypeLiveActivity
+		case opts.PushType != "":
+			pushType = opts.PushType
+		case opts.ContentAvailable && message.Title == "" && message.Body == "":
+			pushType = push.APNSPushTypeBackground
+		}
+		if opts.Priority > 0 {
+			priority = opts.Priority
+		}
+		if !opts.Expiration.IsZero() {
+			header.Set("apns-expiration", strconv.FormatInt(opts.Expiration.Unix(), 10))
+		}
+		if opts.CollapseID != "" {
+			header.Set("apns-collapse-id", opts.CollapseID)
+		}
+	}
+
+	switch pushType {
+	case push.APNSPushTypeLiveActivity:
+		// Live Activity dùng topic riêng của app
+		topic += ".push-type.liveactivity"
+	case push.APNSPushTypeBackground:
+		// APNs từ chối background push có priority 10
+		priority = 5
+	}
+
+	header.Set("apns-topic", topic)
+	header.Set("apns-push-type", pushType)
+	header.Set("apns-priority", strconv.Itoa(priority))
+}
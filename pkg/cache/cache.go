@@ -26,6 +26,9 @@ type Cache interface {
 	// Remember pattern
 	Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error)
 
+	// Tagged entries: key ghi qua Tags(...) được invalidate cả nhóm bằng Flush
+	Tags(names ...string) *TaggedCache
+
 	// Hash operations
 	HSet(ctx context.Context, key string, field string, value interface{}) error
 	HGet(ctx context.Context, key string, field string) (string, error)
@@ -34,7 +37,7 @@ type Cache interface {
 	HExists(ctx context.Context, key string, field string) (bool, error)
 
 	// Redis client access for rate limiting
-	GetRedisClient() *redis.Client
+	GetRedisClient() redis.UniversalClient
 
 	// Set operations
 	SAdd(ctx context.Context, key string, members ...interface{}) error
@@ -63,7 +66,7 @@ type Cache interface {
 
 // redisCache implements Cache interface
 type redisCache struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
 var _ Cache = (*redisCache)(nil)
@@ -30,7 +30,9 @@ func (c *redisCache) HSet(ctx context.Context, key string, field string, value i
 
 // HGet get hash field value
 func (c *redisCache) HGet(ctx context.Context, key string, field string) (string, error) {
-	return c.client.HGet(ctx, key, field).Result()
+	value, err := c.client.HGet(ctx, key, field).Result()
+	recordLookup(err)
+	return value, err
 }
 
 // HGetAll lấy tất cả fields trong hash
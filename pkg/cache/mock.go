@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
@@ -128,6 +129,11 @@ func (m *MockCache) TTL(ctx context.Context, key string) (time.Duration, error)
 	return -2, ErrCacheMiss // Key doesn't exist
 }
 
+// Tags returns a tagged view of the mock cache
+func (m *MockCache) Tags(names ...string) *TaggedCache {
+	return newTaggedCache(m, names)
+}
+
 // Remember executes a function and caches the result
 func (m *MockCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
 	// Try to get from cache first
@@ -135,18 +141,25 @@ func (m *MockCache) Remember(ctx context.Context, key string, ttl time.Duration,
 		return value, nil
 	}
 
-	// Execute callback
-	result, err := callback()
-	if err != nil {
-		return nil, err
-	}
+	// Concurrent misses share one callback call, like the Redis cache
+	return coalesce(m, "remember", key, func() (interface{}, error) {
+		if value, err := m.Get(ctx, key); err == nil {
+			return value, nil
+		}
 
-	// Cache the result
-	if err := m.Set(ctx, key, result, ttl); err != nil {
-		return result, err // Return result even if caching fails
-	}
+		// Execute callback
+		result, err := callback()
+		if err != nil {
+			return nil, err
+		}
+
+		// Cache the result
+		if err := m.Set(ctx, key, result, ttl); err != nil {
+			return result, err // Return result even if caching fails
+		}
 
-	return result, nil
+		return result, nil
+	})
 }
 
 // Hash operations - simplified implementations
@@ -175,25 +188,61 @@ func (m *MockCache) HExists(ctx context.Context, key, field string) (bool, error
 	return err == nil, nil
 }
 
-// Set operations - simplified implementations
+// Set operations
 func (m *MockCache) SAdd(ctx context.Context, key string, members ...interface{}) error {
-	return nil // Simplified
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	set, _ := m.data[key].(map[string]struct{})
+	if set == nil {
+		set = make(map[string]struct{})
+		m.data[key] = set
+	}
+	for _, member := range members {
+		set[fmt.Sprint(member)] = struct{}{}
+	}
+	return nil
 }
 
 func (m *MockCache) SRem(ctx context.Context, key string, members ...interface{}) error {
-	return nil // Simplified
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if set, ok := m.data[key].(map[string]struct{}); ok {
+		for _, member := range members {
+			delete(set, fmt.Sprint(member))
+		}
+	}
+	return nil
 }
 
 func (m *MockCache) SMembers(ctx context.Context, key string) ([]string, error) {
-	return []string{}, nil // Simplified
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set, _ := m.data[key].(map[string]struct{})
+	members := make([]string, 0, len(set))
+	for member := range set {
+		members = append(members, member)
+	}
+	return members, nil
 }
 
 func (m *MockCache) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
-	return false, nil // Simplified
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set, _ := m.data[key].(map[string]struct{})
+	_, ok := set[fmt.Sprint(member)]
+	return ok, nil
 }
 
 func (m *MockCache) SCard(ctx context.Context, key string) (int64, error) {
-	return 0, nil // Simplified
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	set, _ := m.data[key].(map[string]struct{})
+	return int64(len(set)), nil
 }
 
 // List operations - simplified implementations
@@ -244,7 +293,7 @@ func (m *MockCache) FlushDB(ctx context.Context) error {
 	return nil
 }
 
-func (m *MockCache) GetRedisClient() *redis.Client {
+func (m *MockCache) GetRedisClient() redis.UniversalClient {
 	return nil
 }
 
@@ -0,0 +1,259 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NamespacedCache thêm namespace cho mọi key ("<namespace>:<key>"), riêng dữ liệu cache (Remember, RememberAs,
+// RememberStale, Tags) có thêm version: "<namespace>:v<schema>[.<generation>]:<key>".
+//   - schema (CACHE_SCHEMA_VERSION): đổi khi deploy thay đổi shape của dữ liệu cache, instance cũ và mới
+//     trong lúc rolling deploy dùng key riêng, không đọc nhầm payload của nhau
+//   - generation: BumpVersion đổi generation lưu trong Redis, mọi instance chuyển sang key mới
+//     sau tối đa chu kỳ refresh (key cũ tự hết hạn theo TTL)
+//
+// Dữ liệu trạng thái ghi bằng Get/Set (JWT blacklist, token, OAuth state, upload đang chờ...) và lock
+// không theo version: bump version không làm mất token đã thu hồi hay để 2 instance cùng giữ một lock.
+type NamespacedCache struct {
+	Cache
+	state     *versionState
+	versioned bool
+	payload   *NamespacedCache // View có version, dùng chung state
+}
+
+// versionState version dùng chung giữa các view của một NamespacedCache
+type versionState struct {
+	namespace string
+	schema    string
+	refresh   time.Duration
+
+	mu         sync.Mutex
+	generation string
+	checkedAt  time.Time
+}
+
+var _ Cache = (*NamespacedCache)(nil)
+
+// NewNamespacedCache bọc c với namespace và schema version, refresh: chu kỳ đọc lại generation từ Redis (mặc định 5s)
+func NewNamespacedCache(c Cache, namespace, schema string, refresh time.Duration) *NamespacedCache {
+	if refresh <= 0 {
+		refresh = 5 * time.Second
+	}
+	state := &versionState{namespace: namespace, schema: schema, refresh: refresh}
+	n := &NamespacedCache{Cache: c, state: state}
+	n.payload = &NamespacedCache{Cache: c, state: state, versioned: true}
+	n.payload.payload = n.payload
+	return n
+}
+
+// Versioned view có version: mọi key (kể cả Get/Set) đều theo version hiện tại
+func (n *NamespacedCache) Versioned() *NamespacedCache {
+	return n.payload
+}
+
+// Version version hiện tại của dữ liệu cache: "v<schema>" hoặc "v<schema>.<generation>" sau khi bump
+func (n *NamespacedCache) Version(ctx context.Context) string {
+	state := n.state
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	if time.Since(state.checkedAt) >= state.refresh {
+		// Redis lỗi: giữ generation đã biết
+		if generation, err := n.Cache.Get(ctx, n.generationKey()); err == nil {
+			state.generation = generation
+		} else if isMiss(err) {
+			state.generation = ""
+		}
+		state.checkedAt = time.Now()
+	}
+
+	version := "v" + state.schema
+	if state.generation != "" {
+		version += "." + state.generation
+	}
+	return version
+}
+
+// BumpVersion đổi generation: toàn bộ dữ liệu cache (mọi instance, mọi shape) không còn được đọc.
+// Instance khác nhận generation mới sau tối đa chu kỳ refresh.
+func (n *NamespacedCache) BumpVersion(ctx context.Context) (string, error) {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := n.Cache.Set(ctx, n.generationKey(), generation, 0); err != nil {
+		return "", err
+	}
+
+	n.state.mu.Lock()
+	n.state.generation, n.state.checkedAt = generation, time.Now()
+	n.state.mu.Unlock()
+	return n.Version(ctx), nil
+}
+
+// isMiss key không tồn tại (khác Redis lỗi)
+func isMiss(err error) bool {
+	return errors.Is(err, redis.Nil) || errors.Is(err, ErrCacheMiss) || errors.Is(err, ErrCacheNotAvailable)
+}
+
+// Key key thật trong Redis của key
+func (n *NamespacedCache) Key(ctx context.Context, key string) string {
+	if !n.versioned {
+		return n.join("", key)
+	}
+	return n.join(n.Version(ctx), key)
+}
+
+func (n *NamespacedCache) keys(ctx context.Context, keys []string) []string {
+	prefixed := make([]string, len(keys))
+	for i, key := range keys {
+		prefixed[i] = n.Key(ctx, key)
+	}
+	return prefixed
+}
+
+// lockKey lock theo namespace, không theo version
+func (n *NamespacedCache) lockKey(key string) string {
+	return n.join("", key)
+}
+
+func (n *NamespacedCache) generationKey() string {
+	return n.join("", "cache-generation")
+}
+
+func (n *NamespacedCache) join(version, key string) string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{n.state.namespace, version, key} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// payloadCache Cache dùng cho dữ liệu cache: NamespacedCache chuyển sang view có version
+func payloadCache(c Cache) Cache {
+	if n, ok := c.(*NamespacedCache); ok {
+		return n.Versioned()
+	}
+	return c
+}
+
+// Basic operations
+
+func (n *NamespacedCache) Get(ctx context.Context, key string) (string, error) {
+	return n.Cache.Get(ctx, n.Key(ctx, key))
+}
+
+func (n *NamespacedCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return n.Cache.Set(ctx, n.Key(ctx, key), value, ttl)
+}
+
+func (n *NamespacedCache) Del(ctx context.Context, keys ...string) error {
+	return n.Cache.Del(ctx, n.keys(ctx, keys)...)
+}
+
+func (n *NamespacedCache) Exists(ctx context.Context, keys ...string) (int64, error) {
+	return n.Cache.Exists(ctx, n.keys(ctx, keys)...)
+}
+
+func (n *NamespacedCache) Expire(ctx context.Context, key string, ttl time.Duration) error {
+	return n.Cache.Expire(ctx, n.Key(ctx, key), ttl)
+}
+
+func (n *NamespacedCache) TTL(ctx context.Context, key string) (time.Duration, error) {
+	return n.Cache.TTL(ctx, n.Key(ctx, key))
+}
+
+// Remember dữ liệu cache: luôn theo version
+func (n *NamespacedCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
+	return n.Cache.Remember(ctx, n.payload.Key(ctx, key), ttl, callback)
+}
+
+// Tags dữ liệu cache gắn tag: key và set của tag đều theo version
+func (n *NamespacedCache) Tags(names ...string) *TaggedCache {
+	return newTaggedCache(n.payload, names)
+}
+
+// Hash operations
+
+func (n *NamespacedCache) HSet(ctx context.Context, key string, field string, value interface{}) error {
+	return n.Cache.HSet(ctx, n.Key(ctx, key), field, value)
+}
+
+func (n *NamespacedCache) HGet(ctx context.Context, key string, field string) (string, error) {
+	return n.Cache.HGet(ctx, n.Key(ctx, key), field)
+}
+
+func (n *NamespacedCache) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return n.Cache.HGetAll(ctx, n.Key(ctx, key))
+}
+
+func (n *NamespacedCache) HDel(ctx context.Context, key string, fields ...string) error {
+	return n.Cache.HDel(ctx, n.Key(ctx, key), fields...)
+}
+
+func (n *NamespacedCache) HExists(ctx context.Context, key string, field string) (bool, error) {
+	return n.Cache.HExists(ctx, n.Key(ctx, key), field)
+}
+
+// Set operations
+
+func (n *NamespacedCache) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	return n.Cache.SAdd(ctx, n.Key(ctx, key), members...)
+}
+
+func (n *NamespacedCache) SRem(ctx context.Context, key string, members ...interface{}) error {
+	return n.Cache.SRem(ctx, n.Key(ctx, key), members...)
+}
+
+func (n *NamespacedCache) SMembers(ctx context.Context, key string) ([]string, error) {
+	return n.Cache.SMembers(ctx, n.Key(ctx, key))
+}
+
+func (n *NamespacedCache) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	return n.Cache.SIsMember(ctx, n.Key(ctx, key), member)
+}
+
+func (n *NamespacedCache) SCard(ctx context.Context, key string) (int64, error) {
+	return n.Cache.SCard(ctx, n.Key(ctx, key))
+}
+
+// List operations
+
+func (n *NamespacedCache) LPush(ctx context.Context, key string, values ...interface{}) error {
+	return n.Cache.LPush(ctx, n.Key(ctx, key), values...)
+}
+
+func (n *NamespacedCache) RPush(ctx context.Context, key string, values ...interface{}) error {
+	return n.Cache.RPush(ctx, n.Key(ctx, key), values...)
+}
+
+func (n *NamespacedCache) LPop(ctx context.Context, key string) (string, error) {
+	return n.Cache.LPop(ctx, n.Key(ctx, key))
+}
+
+func (n *NamespacedCache) RPop(ctx context.Context, key string) (string, error) {
+	return n.Cache.RPop(ctx, n.Key(ctx, key))
+}
+
+func (n *NamespacedCache) LRange(ctx context.Context, key string, start, stop int64) ([]string, error) {
+	return n.Cache.LRange(ctx, n.Key(ctx, key), start, stop)
+}
+
+// Distributed lock
+
+func (n *NamespacedCache) Lock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return n.Cache.Lock(ctx, n.lockKey(key), ttl)
+}
+
+func (n *NamespacedCache) Unlock(ctx context.Context, key string) error {
+	return n.Cache.Unlock(ctx, n.lockKey(key))
+}
+
+func (n *NamespacedCache) LockAndWait(ctx context.Context, key string, ttl time.Duration, maxWait time.Duration) (bool, error) {
+	return n.Cache.LockAndWait(ctx, n.lockKey(key), ttl, maxWait)
+}
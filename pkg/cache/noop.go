@@ -18,7 +18,7 @@ func NewNoopCache() Cache {
 }
 
 // GetRedisClient returns nil for noop cache
-func (c *noopCache) GetRedisClient() *redis.Client {
+func (c *noopCache) GetRedisClient() redis.UniversalClient {
 	return nil
 }
 
@@ -49,6 +49,10 @@ func (c *noopCache) TTL(ctx context.Context, key string) (time.Duration, error)
 	return 0, nil
 }
 
+func (c *noopCache) Tags(names ...string) *TaggedCache {
+	return newTaggedCache(c, names)
+}
+
 func (c *noopCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
 	// Always execute callback (no caching)
 	return callback()
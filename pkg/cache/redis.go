@@ -6,32 +6,27 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
+
 	"github.com/go-redis/redis/v8"
 )
 
-// Config cấu hình Redis
-type Config struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
-	PoolSize int
-}
+// Config cấu hình Redis (standalone, sentinel, cluster)
+type Config = redisclient.Config
 
 // NewRedisCache tạo Redis cache instance
 func NewRedisCache(cfg Config) (Cache, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-		PoolSize: cfg.PoolSize,
-	})
+	client, err := redisclient.New(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
@@ -39,7 +34,7 @@ func NewRedisCache(cfg Config) (Cache, error) {
 }
 
 // GetRedisClient returns the underlying Redis client
-func (c *redisCache) GetRedisClient() *redis.Client {
+func (c *redisCache) GetRedisClient() redis.UniversalClient {
 	return c.client
 }
 
@@ -47,7 +42,9 @@ func (c *redisCache) GetRedisClient() *redis.Client {
 
 // Get lấy giá trị từ key
 func (c *redisCache) Get(ctx context.Context, key string) (string, error) {
-	return c.client.Get(ctx, key).Result()
+	value, err := c.client.Get(ctx, key).Result()
+	recordLookup(err)
+	return value, err
 }
 
 // Set lưu giá trị với TTL
@@ -73,11 +70,37 @@ func (c *redisCache) Set(ctx context.Context, key string, value interface{}, ttl
 
 // Del xóa keys
 func (c *redisCache) Del(ctx context.Context, keys ...string) error {
+	if len(keys) > 1 && redisclient.IsCluster(c.client) {
+		// Cluster: các key thường khác hash slot (CROSSSLOT), xóa từng key trong pipeline
+		_, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Del(ctx, key)
+			}
+			return nil
+		})
+		return err
+	}
 	return c.client.Del(ctx, keys...).Err()
 }
 
 // Exists kiểm tra key tồn tại
 func (c *redisCache) Exists(ctx context.Context, keys ...string) (int64, error) {
+	if len(keys) > 1 && redisclient.IsCluster(c.client) {
+		cmds, err := c.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, key := range keys {
+				pipe.Exists(ctx, key)
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+		var count int64
+		for _, cmd := range cmds {
+			count += cmd.(*redis.IntCmd).Val()
+		}
+		return count, nil
+	}
 	return c.client.Exists(ctx, keys...).Result()
 }
 
@@ -91,35 +114,57 @@ func (c *redisCache) TTL(ctx context.Context, key string) (time.Duration, error)
 	return c.client.TTL(ctx, key).Result()
 }
 
-// Remember pattern - Get from cache or execute callback
+// Tags trả về cache gắn tag, Flush để xóa mọi key của tag
+func (c *redisCache) Tags(names ...string) *TaggedCache {
+	return newTaggedCache(c, names)
+}
+
+// Remember pattern - Get from cache or execute callback.
+// Các request đồng thời cùng miss một key chỉ gọi callback một lần (singleflight).
 func (c *redisCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
 	// Try get from cache
-	val, err := c.Get(ctx, key)
-	if err == nil {
-		// Cache hit - decode JSON
-		var result interface{}
-		if err := json.Unmarshal([]byte(val), &result); err != nil {
-			// If not JSON, return as string
-			return val, nil
+	result, hit, err := c.remembered(ctx, key)
+	if hit || err != nil {
+		return result, err
+	}
+
+	// Cache miss - execute callback (1 lần cho các request đồng thời)
+	return coalesce(c, "remember", key, func() (interface{}, error) {
+		// Request trước có thể vừa ghi cache xong
+		if result, hit, err := c.remembered(ctx, key); hit || err != nil {
+			return result, err
+		}
+
+		result, err := callback()
+		if err != nil {
+			return nil, err
 		}
+
+		// Save to cache
+		if err := c.Set(ctx, key, result, ttl); err != nil {
+			// Don't fail if cache set fails - just log and return result
+			fmt.Printf("Warning: failed to set cache: %v\n", err)
+		}
+
 		return result, nil
-	}
+	})
+}
 
-	// Cache miss - execute callback
-	if err != redis.Nil {
-		return nil, fmt.Errorf("cache error: %w", err)
+// remembered đọc giá trị của Remember, hit=false khi cache miss
+func (c *redisCache) remembered(ctx context.Context, key string) (interface{}, bool, error) {
+	val, err := c.Get(ctx, key)
+	if err == redis.Nil {
+		return nil, false, nil
 	}
-
-	result, err := callback()
 	if err != nil {
-		return nil, err
+		return nil, false, fmt.Errorf("cache error: %w", err)
 	}
 
-	// Save to cache
-	if err := c.Set(ctx, key, result, ttl); err != nil {
-		// Don't fail if cache set fails - just log and return result
-		fmt.Printf("Warning: failed to set cache: %v\n", err)
+	// Cache hit - decode JSON
+	var result interface{}
+	if err := json.Unmarshal([]byte(val), &result); err != nil {
+		// If not JSON, return as string
+		return val, true, nil
 	}
-
-	return result, nil
+	return result, true, nil
 }
@@ -0,0 +1,45 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// RememberAs giống Remember nhưng có kiểu: giá trị lưu dạng JSON và decode về T, không cần type assert interface{}.
+// Cache lỗi hoặc dữ liệu cũ không decode được (đổi struct) thì gọi fn như cache miss; lỗi khi ghi cache không làm fail.
+func RememberAs[T any](ctx context.Context, c Cache, key string, ttl time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	c = payloadCache(c)
+	if value, ok := getAs[T](ctx, c, key); ok {
+		return value, nil
+	}
+
+	// Các request đồng thời cùng miss chỉ gọi fn một lần
+	return coalesce(c, "as", key, func() (T, error) {
+		if value, ok := getAs[T](ctx, c, key); ok {
+			return value, nil
+		}
+
+		value, err := fn(ctx)
+		if err != nil {
+			return value, err
+		}
+		if raw, err := json.Marshal(value); err == nil {
+			_ = c.Set(ctx, key, string(raw), ttl)
+		}
+		return value, nil
+	})
+}
+
+// getAs đọc và decode giá trị, false nếu miss hoặc không decode được
+func getAs[T any](ctx context.Context, c Cache, key string) (T, bool) {
+	var value T
+	raw, err := c.Get(ctx, key)
+	if err != nil {
+		return value, false
+	}
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return value, false
+	}
+	return value, true
+}
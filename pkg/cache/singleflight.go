@@ -0,0 +1,41 @@
+package cache
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// loads gộp các lần load đồng thời cùng key (cache stampede): chỉ 1 goroutine gọi loader/DB,
+// các goroutine khác chờ và dùng chung kết quả. Chỉ trong 1 process, giữa các instance vẫn mỗi instance load 1 lần.
+var loads singleflight.Group
+
+// coalesce chạy load một lần cho các lời gọi đồng thời cùng kind/key trên cùng Cache.
+// load chạy với context của lời gọi đầu tiên: lời gọi đó bị hủy thì các lời gọi đang chờ cũng nhận lỗi.
+func coalesce[T any](c Cache, kind, key string, load func() (T, error)) (T, error) {
+	v, err, _ := loads.Do(loadKey(c, kind, key), func() (interface{}, error) {
+		return load()
+	})
+	if v == nil {
+		var zero T
+		return zero, err
+	}
+	value, ok := v.(T)
+	if !ok {
+		// Cùng key nhưng khác kiểu giá trị: không dùng chung kết quả
+		return load()
+	}
+	return value, err
+}
+
+// loadKey key singleflight theo Cache gốc (TaggedCache dùng chung với Cache nó bọc)
+func loadKey(c Cache, kind, key string) string {
+	for {
+		tagged, ok := c.(*TaggedCache)
+		if !ok {
+			break
+		}
+		c = tagged.Cache
+	}
+	return fmt.Sprintf("%p|%s|%s", c, kind, key)
+}
@@ -0,0 +1,145 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+)
+
+// RememberOptions thời gian sống của giá trị cache
+type RememberOptions struct {
+	// TTL giá trị còn mới, trả thẳng từ cache
+	TTL time.Duration
+	// StaleWhileRevalidate sau TTL vẫn trả giá trị cũ trong khoảng này, đồng thời refresh ở background
+	StaleWhileRevalidate time.Duration
+	// StaleIfError sau TTL, nếu loader lỗi (DB chậm/lỗi) vẫn trả giá trị cũ trong khoảng này
+	StaleIfError time.Duration
+	// RefreshTimeout thời gian tối đa của một lần refresh background (mặc định 30s)
+	RefreshTimeout time.Duration
+	// EarlyRefreshBeta refresh sớm theo xác suất (XFetch) trước khi hết TTL, xác suất tăng dần khi gần hết hạn
+	// và khi loader càng chậm. 0: tắt, 1: mức khuyến nghị, lớn hơn 1: refresh sớm hơn
+	EarlyRefreshBeta float64
+}
+
+// staleEntry giá trị lưu trong cache kèm mốc hết hạn (unix ms) theo clock của request
+type staleEntry struct {
+	Value      json.RawMessage `json:"v"`
+	FreshUntil int64           `json:"fresh_until"`
+	Delta      int64           `json:"delta,omitempty"` // Thời gian loader chạy (ms), dùng cho refresh sớm
+}
+
+// RememberStale giống Remember nhưng có kiểu và hỗ trợ stale-while-revalidate / stale-if-error:
+//
+//   - còn TTL: trả giá trị cache (EarlyRefreshBeta > 0: có thể refresh sớm ở background)
+//   - quá TTL, trong StaleWhileRevalidate: trả giá trị cũ, refresh ở background (1 instance refresh nhờ Lock)
+//   - quá TTL, trong StaleIfError: gọi loader, lỗi thì trả giá trị cũ
+//   - cache miss hoặc cache lỗi: gọi loader, các request đồng thời cùng key chỉ gọi loader một lần
+func RememberStale[T any](ctx context.Context, c Cache, key string, opts RememberOptions, loader func(ctx context.Context) (T, error)) (T, error) {
+	c = payloadCache(c)
+	now := clock.FromContext(ctx).Now()
+
+	entry, value, ok := getStale[T](ctx, c, key)
+	if !ok {
+		return loadStale(ctx, c, key, opts, loader)
+	}
+
+	freshUntil := time.UnixMilli(entry.FreshUntil)
+	switch {
+	case now.Before(freshUntil):
+		if refreshEarly(now, freshUntil, time.Duration(entry.Delta)*time.Millisecond, opts.EarlyRefreshBeta) {
+			go revalidateStale(context.WithoutCancel(ctx), c, key, opts, loader)
+		}
+		return value, nil
+	case now.Before(freshUntil.Add(opts.StaleWhileRevalidate)):
+		go revalidateStale(context.WithoutCancel(ctx), c, key, opts, loader)
+		return value, nil
+	}
+
+	fresh, err := loadStale(ctx, c, key, opts, loader)
+	if err != nil && now.Before(freshUntil.Add(opts.StaleIfError)) {
+		return value, nil
+	}
+	return fresh, err
+}
+
+// getStale đọc và decode entry, false nếu miss hoặc dữ liệu không hợp lệ
+func getStale[T any](ctx context.Context, c Cache, key string) (staleEntry, T, bool) {
+	var entry staleEntry
+	var value T
+
+	raw, err := c.Get(ctx, key)
+	if err != nil {
+		return entry, value, false
+	}
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil || entry.Value == nil {
+		return entry, value, false
+	}
+	if err := json.Unmarshal(entry.Value, &value); err != nil {
+		return entry, value, false
+	}
+	return entry, value, true
+}
+
+// loadStale refreshStale gộp các request đồng thời cùng key (singleflight)
+func loadStale[T any](ctx context.Context, c Cache, key string, opts RememberOptions, loader func(ctx context.Context) (T, error)) (T, error) {
+	return coalesce(c, "stale", key, func() (T, error) {
+		// Request trước có thể vừa refresh xong
+		if entry, value, ok := getStale[T](ctx, c, key); ok && clock.FromContext(ctx).Now().Before(time.UnixMilli(entry.FreshUntil)) {
+			return value, nil
+		}
+		return refreshStale(ctx, c, key, opts, loader)
+	})
+}
+
+// refreshEarly XFetch: now - delta*beta*ln(rand) >= freshUntil
+func refreshEarly(now, freshUntil time.Time, delta time.Duration, beta float64) bool {
+	if beta <= 0 || delta <= 0 {
+		return false
+	}
+	gap := time.Duration(float64(delta) * beta * -math.Log(1-rand.Float64()))
+	return !now.Add(gap).Before(freshUntil)
+}
+
+// refreshStale gọi loader và lưu kết quả; key giữ thêm khoảng stale dài nhất để còn giá trị cũ khi cần
+func refreshStale[T any](ctx context.Context, c Cache, key string, opts RememberOptions, loader func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	value, err := loader(ctx)
+	if err != nil {
+		return value, err
+	}
+	delta := time.Since(start)
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return value, nil
+	}
+	entry := staleEntry{
+		Value:      raw,
+		FreshUntil: clock.FromContext(ctx).Now().Add(opts.TTL).UnixMilli(),
+		Delta:      delta.Milliseconds(),
+	}
+	_ = c.Set(ctx, key, entry, opts.TTL+max(opts.StaleWhileRevalidate, opts.StaleIfError))
+	return value, nil
+}
+
+// revalidateStale refresh ở background, Lock tránh nhiều request/instance cùng refresh một key
+func revalidateStale[T any](ctx context.Context, c Cache, key string, opts RememberOptions, loader func(ctx context.Context) (T, error)) {
+	timeout := opts.RefreshTimeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	lockKey := key + ":revalidate"
+	if locked, err := c.Lock(ctx, lockKey, timeout); err != nil || !locked {
+		return
+	}
+	defer c.Unlock(ctx, lockKey)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	_, _ = refreshStale(ctx, c, key, opts, loader)
+}
@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// LookupStats số lần đọc key (Get, HGet) theo kết quả, cộng dồn cho mọi Redis cache trong process.
+// Remember, RememberAs và namespace đều đọc qua Get nên được tính.
+type LookupStats struct {
+	Hits   uint64
+	Misses uint64
+	Errors uint64 // Redis lỗi (timeout, mất kết nối)
+}
+
+var lookupStats struct {
+	hits, misses, errors atomic.Uint64
+}
+
+// Stats số lần đọc cache từ khi process chạy
+func Stats() LookupStats {
+	return LookupStats{
+		Hits:   lookupStats.hits.Load(),
+		Misses: lookupStats.misses.Load(),
+		Errors: lookupStats.errors.Load(),
+	}
+}
+
+// recordLookup ghi kết quả một lần đọc key
+func recordLookup(err error) {
+	switch {
+	case err == nil:
+		lookupStats.hits.Add(1)
+	case err == redis.Nil:
+		lookupStats.misses.Add(1)
+	default:
+		lookupStats.errors.Add(1)
+	}
+}
+
+// RegisterMetrics export Stats vào registry: <prefix>_lookups_total{result="hit|miss|error"}, prefix rỗng dùng "cache".
+// Hit rate: rate(<prefix>_lookups_total{result="hit"}[5m]) / rate(<prefix>_lookups_total{result=~"hit|miss"}[5m])
+func RegisterMetrics(registry *metrics.Registry, prefix string) {
+	if prefix == "" {
+		prefix = "cache"
+	}
+	lookups := registry.NewCounter(prefix+"_lookups", "Cache key lookups by result: hit, miss or error.", "result")
+
+	var (
+		mu   sync.Mutex
+		last LookupStats
+	)
+	registry.RegisterCollector(metrics.CollectorFunc(func(ctx context.Context) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		current := Stats()
+		lookups.Add(float64(current.Hits-last.Hits), "hit")
+		lookups.Add(float64(current.Misses-last.Misses), "miss")
+		lookups.Add(float64(current.Errors-last.Errors), "error")
+		last = current
+	}))
+}
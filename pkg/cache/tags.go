@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// tagKeyPrefix set lưu danh sách key thuộc một tag
+const tagKeyPrefix = "cache:tags:"
+
+// TaggedCache Cache gắn tag: mọi key ghi qua Set/Remember (kể cả RememberStale) được đăng ký vào set của
+// từng tag, Flush xóa toàn bộ key của các tag đó. Dùng để invalidate cả nhóm (users:all, user:{id},
+// danh sách phân trang...) thay vì liệt kê từng key.
+//
+// Các thao tác khác (Get, Del, hash, list...) đi thẳng xuống Cache gốc, không đăng ký tag.
+// Set của tag không có TTL: key hết hạn tự nhiên chỉ để lại member thừa, được dọn ở lần Flush kế tiếp.
+type TaggedCache struct {
+	Cache
+	tags []string
+}
+
+var _ Cache = (*TaggedCache)(nil)
+
+// newTaggedCache gắn tag cho c, bỏ tag rỗng và trùng
+func newTaggedCache(c Cache, names []string) *TaggedCache {
+	tags := make([]string, 0, len(names))
+	for _, name := range names {
+		if name != "" && !slices.Contains(tags, name) {
+			tags = append(tags, name)
+		}
+	}
+	return &TaggedCache{Cache: c, tags: tags}
+}
+
+// Tags thêm tag, trả về TaggedCache mới trên cùng Cache gốc
+func (t *TaggedCache) Tags(names ...string) *TaggedCache {
+	return newTaggedCache(t.Cache, append(slices.Clone(t.tags), names...))
+}
+
+// Set đăng ký key vào các tag trước khi ghi: ghi tag lỗi thì không ghi giá trị, tránh key không Flush được
+func (t *TaggedCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := t.tag(ctx, key); err != nil {
+		return err
+	}
+	return t.Cache.Set(ctx, key, value, ttl)
+}
+
+// Remember giống Cache.Remember, key được đăng ký vào các tag khi cache miss
+func (t *TaggedCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error)) (interface{}, error) {
+	return t.Cache.Remember(ctx, key, ttl, func() (interface{}, error) {
+		result, err := callback()
+		if err != nil {
+			return nil, err
+		}
+		if err := t.tag(ctx, key); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+}
+
+// Flush xóa mọi key thuộc ít nhất một trong các tag và set của tag
+func (t *TaggedCache) Flush(ctx context.Context) error {
+	var errs []error
+	for _, name := range t.tags {
+		keys, err := t.Cache.SMembers(ctx, tagKey(name))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := t.Cache.Del(ctx, append(keys, tagKey(name))...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// tag đăng ký key vào set của từng tag
+func (t *TaggedCache) tag(ctx context.Context, key string) error {
+	for _, name := range t.tags {
+		if err := t.Cache.SAdd(ctx, tagKey(name), key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func tagKey(name string) string {
+	return tagKeyPrefix + name
+}
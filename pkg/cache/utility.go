@@ -1,6 +1,12 @@
 package cache
 
-import "context"
+import (
+	"context"
+
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
+
+	"github.com/go-redis/redis/v8"
+)
 
 // Utility Operations
 
@@ -9,9 +15,11 @@ func (c *redisCache) Ping(ctx context.Context) error {
 	return c.client.Ping(ctx).Err()
 }
 
-// FlushDB xóa tất cả keys trong DB hiện tại
+// FlushDB xóa tất cả keys trong DB hiện tại (cluster: trên mọi master)
 func (c *redisCache) FlushDB(ctx context.Context) error {
-	return c.client.FlushDB(ctx).Err()
+	return redisclient.ForEachMaster(ctx, c.client, func(ctx context.Context, client redis.UniversalClient) error {
+		return client.FlushDB(ctx).Err()
+	})
 }
 
 // Close đóng Redis connection
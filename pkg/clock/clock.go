@@ -0,0 +1,89 @@
+// Package clock cung cấp abstraction cho thời gian hiện tại để logic phụ thuộc thời gian
+// (token expiry, quiet hours, cron...) có thể test với clock cố định.
+//
+// Business logic nên lấy thời gian qua clock.FromContext(ctx).Now() (hoặc clock.Now() khi không có ctx)
+// thay vì gọi time.Now() trực tiếp. Đo latency/timeout của hạ tầng vẫn dùng time.Now().
+package clock
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Clock nguồn thời gian
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	Until(t time.Time) time.Duration
+}
+
+// realClock dùng thời gian hệ thống
+type realClock struct{}
+
+// New tạo clock dùng thời gian hệ thống
+func New() Clock {
+	return realClock{}
+}
+
+func (realClock) Now() time.Time                  { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration { return time.Since(t) }
+func (realClock) Until(t time.Time) time.Duration { return time.Until(t) }
+
+var (
+	mu           sync.RWMutex
+	defaultClock Clock = realClock{}
+)
+
+// Default trả về clock mặc định của process
+func Default() Clock {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultClock
+}
+
+// SetDefault thay clock mặc định (dùng trong test), trả về hàm khôi phục clock cũ
+func SetDefault(c Clock) (restore func()) {
+	mu.Lock()
+	previous := defaultClock
+	defaultClock = c
+	mu.Unlock()
+
+	return func() {
+		mu.Lock()
+		defaultClock = previous
+		mu.Unlock()
+	}
+}
+
+// Now thời gian hiện tại theo clock mặc định
+func Now() time.Time {
+	return Default().Now()
+}
+
+type contextKey struct{}
+
+// WithContext gắn clock vào context
+func WithContext(ctx context.Context, c Clock) context.Context {
+	return context.WithValue(ctx, contextKey{}, c)
+}
+
+// FromContext lấy clock từ context, fallback về clock mặc định
+func FromContext(ctx context.Context) Clock {
+	if ctx != nil {
+		if c, ok := ctx.Value(contextKey{}).(Clock); ok && c != nil {
+			return c
+		}
+	}
+	return Default()
+}
+
+// Middleware gắn clock vào context của mọi request (dùng trong integration test với router)
+func Middleware(c Clock) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(WithContext(r.Context(), c)))
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Frozen clock đứng yên, chỉ thay đổi khi gọi Set/Advance (dùng cho test)
+type Frozen struct {
+	mu  sync.RWMutex
+	now time.Time
+}
+
+// NewFrozen tạo clock cố định tại thời điểm t
+func NewFrozen(t time.Time) *Frozen {
+	return &Frozen{now: t}
+}
+
+// Now thời gian hiện tại của clock
+func (f *Frozen) Now() time.Time {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.now
+}
+
+// Since khoảng thời gian từ t đến thời điểm của clock
+func (f *Frozen) Since(t time.Time) time.Duration {
+	return f.Now().Sub(t)
+}
+
+// Until khoảng thời gian từ thời điểm của clock đến t
+func (f *Frozen) Until(t time.Time) time.Duration {
+	return t.Sub(f.Now())
+}
+
+// Set đặt clock về thời điểm t
+func (f *Frozen) Set(t time.Time) {
+	f.mu.Lock()
+	f.now = t
+	f.mu.Unlock()
+}
+
+// Advance tiến clock thêm d
+func (f *Frozen) Advance(d time.Duration) {
+	f.mu.Lock()
+	f.now = f.now.Add(d)
+	f.mu.Unlock()
+}
@@ -0,0 +1,63 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Offset clock chạy theo clock gốc nhưng lệch một khoảng có thể điều chỉnh lúc runtime
+// (dùng cho môi trường E2E: tua nhanh thời gian mà không dừng đồng hồ)
+type Offset struct {
+	base Clock
+
+	mu     sync.RWMutex
+	offset time.Duration
+}
+
+// NewOffset tạo clock lệch 0 so với base
+func NewOffset(base Clock) *Offset {
+	return &Offset{base: base}
+}
+
+// Now thời gian của clock gốc cộng offset
+func (o *Offset) Now() time.Time {
+	return o.base.Now().Add(o.Offset())
+}
+
+// Since khoảng thời gian từ t đến thời điểm của clock
+func (o *Offset) Since(t time.Time) time.Duration {
+	return o.Now().Sub(t)
+}
+
+// Until khoảng thời gian từ thời điểm của clock đến t
+func (o *Offset) Until(t time.Time) time.Duration {
+	return t.Sub(o.Now())
+}
+
+// Offset độ lệch hiện tại so với clock gốc
+func (o *Offset) Offset() time.Duration {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.offset
+}
+
+// Advance tiến clock thêm d
+func (o *Offset) Advance(d time.Duration) {
+	o.mu.Lock()
+	o.offset += d
+	o.mu.Unlock()
+}
+
+// Set đặt clock về thời điểm t, sau đó clock tiếp tục chạy theo clock gốc
+func (o *Offset) Set(t time.Time) {
+	o.mu.Lock()
+	o.offset = t.Sub(o.base.Now())
+	o.mu.Unlock()
+}
+
+// Reset bỏ độ lệch, clock trùng với clock gốc
+func (o *Offset) Reset() {
+	o.mu.Lock()
+	o.offset = 0
+	o.mu.Unlock()
+}
@@ -0,0 +1,68 @@
+package correlation
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Header header HTTP mang correlation ID, trùng header middleware.RequestID của chi đọc từ request đến
+// nên service nhận (cũng dùng chi) giữ nguyên ID của service gọi
+const Header = "X-Request-Id"
+
+// FromContext correlation ID của ctx: request ID (middleware.RequestID hoặc WithID), không có thì trace ID của span hiện tại
+func FromContext(ctx context.Context) string {
+	if id := middleware.GetReqID(ctx); id != "" {
+		return id
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+	return ""
+}
+
+// WithID gắn correlation ID vào ctx (vd khi worker nhận message), log và lời gọi ra ngoài sau đó dùng lại ID này.
+// id rỗng: trả về ctx
+func WithID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, middleware.RequestIDKey, id)
+}
+
+// Transport bọc base (nil: http.DefaultTransport) để request ra ngoài mang header X-Request-Id theo context của request,
+// request đã tự đặt header thì giữ nguyên
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+type transport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if id := FromContext(req.Context()); id != "" && req.Header.Get(Header) == "" {
+		// RoundTripper không được sửa request của caller
+		req = req.Clone(req.Context())
+		req.Header.Set(Header, id)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// NewHTTPClient HTTP client gọi service nội bộ: mang X-Request-Id và traceparent, mỗi request có span riêng.
+// Gửi request bằng http.NewRequestWithContext(ctx, ...) với ctx của request/job hiện tại.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Transport: Transport(tracing.Transport(nil)),
+		Timeout:   timeout,
+	}
+}
@@ -0,0 +1,144 @@
+package cron
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Control trạng thái scheduler dùng chung giữa các process: admin API tạm dừng/tiếp tục job, yêu cầu chạy job ngay,
+// scheduler (Config.Control) đọc các thay đổi đó và công bố trạng thái job để API hiển thị.
+type Control interface {
+	// Pause tạm dừng job: scheduler bỏ qua các lần chạy theo lịch cho tới khi Resume
+	Pause(ctx context.Context, jobName string) error
+	// Resume tiếp tục chạy job theo lịch
+	Resume(ctx context.Context, jobName string) error
+	// PausedJobs các job đang bị tạm dừng, sắp xếp theo tên
+	PausedJobs(ctx context.Context) ([]string, error)
+	// Trigger yêu cầu scheduler chạy job ngay (kể cả khi job đang bị tạm dừng)
+	Trigger(ctx context.Context, jobName string) error
+	// TakeTriggers lấy và xóa các yêu cầu chạy ngay đang chờ
+	TakeTriggers(ctx context.Context) ([]string, error)
+	// PublishSnapshot ghi trạng thái job của scheduler, hết hạn sau ttl nếu scheduler không còn ghi
+	PublishSnapshot(ctx context.Context, snapshot Snapshot, ttl time.Duration) error
+	// Snapshot trạng thái job scheduler công bố gần nhất, nil nếu không có scheduler nào đang chạy
+	Snapshot(ctx context.Context) (*Snapshot, error)
+}
+
+// Snapshot trạng thái job do scheduler công bố qua Control
+type Snapshot struct {
+	Host      string      `json:"host"`       // Instance đang chạy cron loop
+	UpdatedAt time.Time   `json:"updated_at"` // Thời điểm ghi snapshot
+	Jobs      []JobStatus `json:"jobs"`       // Sắp xếp theo tên
+}
+
+// DefaultControlPrefix tiền tố key Redis của RedisControl
+const DefaultControlPrefix = "cron-control:"
+
+// RedisControl implements Control bằng Redis: set job bị tạm dừng, list yêu cầu chạy ngay và key snapshot có TTL
+type RedisControl struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisControl tạo Control trên Redis, prefix rỗng: DefaultControlPrefix
+func NewRedisControl(client redis.UniversalClient, prefix string) *RedisControl {
+	if prefix == "" {
+		prefix = DefaultControlPrefix
+	}
+	return &RedisControl{client: client, prefix: prefix}
+}
+
+// Pause implements Control
+func (r *RedisControl) Pause(ctx context.Context, jobName string) error {
+	if err := r.client.SAdd(ctx, r.pausedKey(), jobName).Err(); err != nil {
+		return fmt.Errorf("failed to pause job %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// Resume implements Control
+func (r *RedisControl) Resume(ctx context.Context, jobName string) error {
+	if err := r.client.SRem(ctx, r.pausedKey(), jobName).Err(); err != nil {
+		return fmt.Errorf("failed to resume job %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// PausedJobs implements Control
+func (r *RedisControl) PausedJobs(ctx context.Context) ([]string, error) {
+	names, err := r.client.SMembers(ctx, r.pausedKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list paused jobs: %w", err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Trigger implements Control
+func (r *RedisControl) Trigger(ctx context.Context, jobName string) error {
+	if err := r.client.RPush(ctx, r.triggersKey(), jobName).Err(); err != nil {
+		return fmt.Errorf("failed to trigger job %s: %w", jobName, err)
+	}
+	return nil
+}
+
+// TakeTriggers implements Control, LRANGE + DEL trong 1 transaction để mỗi yêu cầu chỉ được 1 scheduler nhận
+func (r *RedisControl) TakeTriggers(ctx context.Context) ([]string, error) {
+	var names *redis.StringSliceCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		names = pipe.LRange(ctx, r.triggersKey(), 0, -1)
+		pipe.Del(ctx, r.triggersKey())
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to take job triggers: %w", err)
+	}
+	return names.Val(), nil
+}
+
+// PublishSnapshot implements Control
+func (r *RedisControl) PublishSnapshot(ctx context.Context, snapshot Snapshot, ttl time.Duration) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduler snapshot: %w", err)
+	}
+	if err := r.client.Set(ctx, r.snapshotKey(), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to publish scheduler snapshot: %w", err)
+	}
+	return nil
+}
+
+// Snapshot implements Control
+func (r *RedisControl) Snapshot(ctx context.Context) (*Snapshot, error) {
+	data, err := r.client.Get(ctx, r.snapshotKey()).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scheduler snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode scheduler snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+func (r *RedisControl) pausedKey() string {
+	return r.prefix + "paused"
+}
+
+func (r *RedisControl) triggersKey() string {
+	return r.prefix + "triggers"
+}
+
+func (r *RedisControl) snapshotKey() string {
+	return r.prefix + "snapshot"
+}
@@ -2,7 +2,11 @@ package cron
 
 import (
 	"context"
+	"errors"
 	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/metrics"
 )
 
 // Job represents a cron job
@@ -26,6 +30,19 @@ type Job interface {
 	RetryDelay() time.Duration
 }
 
+// TimeZoneProvider job chạy theo timezone riêng thay vì Config.TimeZone, vd job của từng region chạy theo giờ địa phương
+type TimeZoneProvider interface {
+	// TimeZone returns an IANA timezone such as "Asia/Tokyo", "" uses Config.TimeZone
+	TimeZone() string
+}
+
+// JitterProvider job chờ ngẫu nhiên trong [0, Jitter()) trước mỗi lần chạy theo lịch, tránh nhiều instance/job
+// cùng chạy đúng 1 thời điểm. Jitter nên nhỏ hơn chu kỳ của job; không áp dụng cho TriggerJob.
+type JitterProvider interface {
+	// Jitter returns the random delay window, 0 disables jitter
+	Jitter() time.Duration
+}
+
 // LockManager handles distributed locking for cron jobs
 type LockManager interface {
 	// AcquireLock attempts to acquire a lock for the given job
@@ -46,9 +63,16 @@ type Scheduler interface {
 	// AddJob adds a job to the scheduler
 	AddJob(job Job) error
 
-	// RemoveJob removes a job from the scheduler
+	// RemoveJob removes a job from the scheduler, a run in progress still finishes
 	RemoveJob(jobName string) error
 
+	// ReplaceJob swaps a registered job for one with the same name (new schedule or implementation) at runtime,
+	// keeping its status and counters
+	ReplaceJob(job Job) error
+
+	// UpdateSchedule changes the cron expression of a registered job at runtime
+	UpdateSchedule(jobName, schedule string) error
+
 	// Start starts the scheduler
 	Start(ctx context.Context) error
 
@@ -58,21 +82,43 @@ type Scheduler interface {
 	// IsRunning returns true if the scheduler is running
 	IsRunning() bool
 
+	// IsLeader returns true if this instance runs the scheduler loop (always true when running without leader election)
+	IsLeader() bool
+
 	// GetJobStatus returns the status of a specific job
 	GetJobStatus(jobName string) (*JobStatus, error)
 
 	// GetJobStatuses returns the status of all jobs
 	GetJobStatuses() map[string]*JobStatus
+
+	// PauseJob skips scheduled runs of a job until ResumeJob (shared through Config.Control when set)
+	PauseJob(ctx context.Context, jobName string) error
+
+	// ResumeJob runs a paused job on its schedule again
+	ResumeJob(ctx context.Context, jobName string) error
+
+	// TriggerJob runs a job immediately in the background, even when it is paused
+	TriggerJob(jobName string) error
 }
 
+// Errors returned by RemoveJob, ReplaceJob, UpdateSchedule, PauseJob, ResumeJob and TriggerJob
+var (
+	ErrJobNotFound         = errors.New("job does not exist")
+	ErrJobRunning          = errors.New("job is already running")
+	ErrSchedulerNotRunning = errors.New("scheduler is not running")
+	ErrNotLeader           = errors.New("scheduler is not the leader")
+)
+
 // JobStatus represents the status of a cron job
 type JobStatus struct {
 	Name         string    `json:"name"`
 	Schedule     string    `json:"schedule"`
+	TimeZone     string    `json:"time_zone"`
 	LastRun      time.Time `json:"last_run"`
 	NextRun      time.Time `json:"next_run"`
 	IsRunning    bool      `json:"is_running"`
 	IsLocked     bool      `json:"is_locked"`
+	IsPaused     bool      `json:"is_paused"`
 	RunCount     int64     `json:"run_count"`
 	SuccessCount int64     `json:"success_count"`
 	ErrorCount   int64     `json:"error_count"`
@@ -89,6 +135,13 @@ type JobResult struct {
 	Success    bool          `json:"success"`
 	Error      string        `json:"error,omitempty"`
 	RetryCount int           `json:"retry_count"`
+	Host       string        `json:"host"`
+}
+
+// ResultRecorder lưu kết quả mỗi lần chạy job (lịch sử chạy để debug job lỗi)
+type ResultRecorder interface {
+	// RecordResult lưu kết quả 1 lần chạy, lỗi chỉ được log và không ảnh hưởng job
+	RecordResult(ctx context.Context, result JobResult) error
 }
 
 // Config represents the configuration for the cron scheduler
@@ -113,4 +166,40 @@ type Config struct {
 
 	// MetricsPrefix specifies the prefix for metrics
 	MetricsPrefix string `json:"metrics_prefix"`
+
+	// Metrics specifies the registry job metrics are exported to when EnableMetrics is set (default: metrics.Default())
+	Metrics *metrics.Registry `json:"-"`
+
+	// Clock specifies the time source for job statuses and job contexts (default: clock.Default())
+	Clock clock.Clock `json:"-"`
+
+	// LeaderElector enables leader election: only the leader runs the scheduler loop and
+	// per-job locks are skipped (default: nil, every instance runs the loop and jobs take per-job locks)
+	LeaderElector LeaderElector `json:"-"`
+
+	// LeaderRenewInterval specifies how often to campaign/renew leadership, must be shorter than the elector TTL (default: 5s)
+	LeaderRenewInterval time.Duration `json:"leader_renew_interval"`
+
+	// ResultRecorder stores the result of every job run (default: nil, results are only kept in job statuses and metrics)
+	ResultRecorder ResultRecorder `json:"-"`
+
+	// Host identifies this instance in recorded job results (default: os.Hostname())
+	Host string `json:"host"`
+
+	// Control shares pause state, trigger requests and job statuses with other processes, e.g. the admin API
+	// (default: nil, PauseJob/TriggerJob only affect this instance)
+	Control Control `json:"-"`
+
+	// ControlSyncInterval specifies how often Control is polled and the job snapshot is published (default: 1s)
+	ControlSyncInterval time.Duration `json:"control_sync_interval"`
+
+	// Logger receives scheduler logs: lock, leadership, triggers and job starts (default: stdout)
+	Logger Logger `json:"-"`
+}
+
+// Logger receives scheduler logs by level, e.g. logger.Module(logger.ModuleCron)
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
 }
@@ -0,0 +1,160 @@
+package cron
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// LeaderElector bầu 1 instance chạy scheduler loop, các instance khác đứng chờ
+type LeaderElector interface {
+	// Campaign giành hoặc gia hạn quyền leader, true nếu instance này đang là leader.
+	// Được gọi định kỳ, lỗi được coi là mất quyền leader.
+	Campaign(ctx context.Context) (bool, error)
+
+	// Resign từ bỏ quyền leader để instance khác tiếp quản ngay
+	Resign(ctx context.Context) error
+}
+
+// campaignScript giành key nếu trống, gia hạn nếu đang giữ
+var campaignScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if current == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if current == false then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+return 0
+`)
+
+// resignScript chỉ xóa key khi instance này đang giữ
+var resignScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// RedisLeaderElector leader election bằng Redis key có TTL.
+// Leader mất kết nối thì key hết hạn sau ttl và instance khác lên thay.
+type RedisLeaderElector struct {
+	client     redis.UniversalClient
+	key        string
+	instanceID string
+	ttl        time.Duration
+}
+
+// NewRedisLeaderElector tạo Redis leader elector, ttl phải lớn hơn chu kỳ gọi Campaign
+func NewRedisLeaderElector(client redis.UniversalClient, key, instanceID string, ttl time.Duration) *RedisLeaderElector {
+	if key == "" {
+		key = "cron:leader"
+	}
+	return &RedisLeaderElector{
+		client:     client,
+		key:        key,
+		instanceID: instanceID,
+		ttl:        ttl,
+	}
+}
+
+// Campaign implements LeaderElector
+func (r *RedisLeaderElector) Campaign(ctx context.Context) (bool, error) {
+	result, err := campaignScript.Run(ctx, r.client, []string{r.key}, r.instanceID, r.ttl.Milliseconds()).Int()
+	if err != nil {
+		return false, fmt.Errorf("failed to campaign for leader: %w", err)
+	}
+	return result == 1, nil
+}
+
+// Resign implements LeaderElector
+func (r *RedisLeaderElector) Resign(ctx context.Context) error {
+	if err := resignScript.Run(ctx, r.client, []string{r.key}, r.instanceID).Err(); err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to resign leader: %w", err)
+	}
+	return nil
+}
+
+// PostgresLeaderElector leader election bằng Postgres session advisory lock.
+// Lock gắn với 1 connection riêng, connection đứt thì Postgres tự nhả lock.
+type PostgresLeaderElector struct {
+	db      *sql.DB
+	lockKey int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+// NewPostgresLeaderElector tạo Postgres leader elector, name được hash thành advisory lock key
+func NewPostgresLeaderElector(db *sql.DB, name string) *PostgresLeaderElector {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	return &PostgresLeaderElector{db: db, lockKey: int64(h.Sum64())}
+}
+
+// Campaign implements LeaderElector
+func (p *PostgresLeaderElector) Campaign(ctx context.Context) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	// Đang giữ lock: kiểm tra connection còn sống
+	if p.conn != nil {
+		if err := p.conn.PingContext(ctx); err != nil {
+			p.closeConn()
+			return false, fmt.Errorf("leader connection lost: %w", err)
+		}
+		return true, nil
+	}
+
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to get connection: %w", err)
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", p.lockKey).Scan(&acquired); err != nil {
+		conn.Close()
+		return false, fmt.Errorf("failed to campaign for leader: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return false, nil
+	}
+
+	p.conn = conn
+	return true, nil
+}
+
+// Resign implements LeaderElector
+func (p *PostgresLeaderElector) Resign(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conn == nil {
+		return nil
+	}
+	_, err := p.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", p.lockKey)
+	p.closeConn()
+	if err != nil {
+		return fmt.Errorf("failed to resign leader: %w", err)
+	}
+	return nil
+}
+
+// closeConn đóng hẳn connection thay vì trả về pool: nếu unlock lỗi, connection trong pool vẫn giữ lock
+func (p *PostgresLeaderElector) closeConn() {
+	// ErrBadConn làm database/sql loại connection khỏi pool
+	_ = p.conn.Raw(func(driverConn interface{}) error {
+		return driver.ErrBadConn
+	})
+	p.conn.Close()
+	p.conn = nil
+}
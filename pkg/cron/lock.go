@@ -3,6 +3,7 @@ package cron
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
@@ -10,12 +11,12 @@ import (
 
 // RedisLockManager implements LockManager using Redis
 type RedisLockManager struct {
-	client *redis.Client
+	client redis.UniversalClient
 	prefix string
 }
 
 // NewRedisLockManager creates a new Redis lock manager
-func NewRedisLockManager(client *redis.Client, prefix string) *RedisLockManager {
+func NewRedisLockManager(client redis.UniversalClient, prefix string) *RedisLockManager {
 	if prefix == "" {
 		prefix = "cron:lock:"
 	}
@@ -86,6 +87,7 @@ func (r *RedisLockManager) getLockKey(jobName string) string {
 
 // MemoryLockManager implements LockManager using in-memory locks (for single instance)
 type MemoryLockManager struct {
+	mu    sync.Mutex
 	locks map[string]*memoryLock
 }
 
@@ -103,6 +105,9 @@ func NewMemoryLockManager() *MemoryLockManager {
 
 // AcquireLock attempts to acquire a lock for the given job
 func (m *MemoryLockManager) AcquireLock(ctx context.Context, jobName string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	// Check if lock exists and is still valid
 	if lock, exists := m.locks[jobName]; exists {
 		if time.Since(lock.acquiredAt) < lock.ttl {
@@ -123,12 +128,18 @@ func (m *MemoryLockManager) AcquireLock(ctx context.Context, jobName string, ttl
 
 // ReleaseLock releases the lock for the given job
 func (m *MemoryLockManager) ReleaseLock(ctx context.Context, jobName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	delete(m.locks, jobName)
 	return nil
 }
 
 // ExtendLock extends the lock TTL for the given job
 func (m *MemoryLockManager) ExtendLock(ctx context.Context, jobName string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if lock, exists := m.locks[jobName]; exists {
 		lock.ttl = ttl
 		return nil
@@ -138,6 +149,9 @@ func (m *MemoryLockManager) ExtendLock(ctx context.Context, jobName string, ttl
 
 // IsLocked checks if a job is currently locked
 func (m *MemoryLockManager) IsLocked(ctx context.Context, jobName string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	if lock, exists := m.locks[jobName]; exists {
 		if time.Since(lock.acquiredAt) < lock.ttl {
 			return true, nil
@@ -150,6 +164,9 @@ func (m *MemoryLockManager) IsLocked(ctx context.Context, jobName string) (bool,
 
 // CleanupExpiredLocks removes expired locks from memory
 func (m *MemoryLockManager) CleanupExpiredLocks() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	now := time.Now()
 	for jobName, lock := range m.locks {
 		if now.Sub(lock.acquiredAt) >= lock.ttl {
@@ -0,0 +1,110 @@
+package cron
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+)
+
+// Giá trị label status của job runs
+const (
+	runStatusSuccess = "success"
+	runStatusFailure = "failure"
+)
+
+// schedulerMetrics metrics của scheduler, label job là tên job.
+// Alert gợi ý: increase(<prefix>_job_runs_total{status="failure"}[1h]) > 0 và
+// min by (job) (<prefix>_job_seconds_since_last_success) > chu kỳ job.
+type schedulerMetrics struct {
+	runs        *metrics.Counter
+	retries     *metrics.Counter
+	skipped     *metrics.Counter
+	duration    *metrics.Histogram
+	lastRun     *metrics.Gauge
+	lastSuccess *metrics.Gauge
+	running     *metrics.Gauge
+	leader      *metrics.Gauge
+
+	sinceSuccess *metrics.Gauge
+	mu           sync.Mutex
+	succeededAt  map[string]time.Time // Lần thành công gần nhất, trước đó là lúc đăng ký job
+}
+
+func newSchedulerMetrics(registry *metrics.Registry, prefix string) *schedulerMetrics {
+	return &schedulerMetrics{
+		runs:        registry.NewCounter(prefix+"_job_runs", "Cron job runs by final status (after retries).", "job", "status"),
+		retries:     registry.NewCounter(prefix+"_job_retries", "Cron job attempts retried after an error.", "job"),
+		skipped:     registry.NewCounter(prefix+"_job_skipped", "Cron job runs skipped because the lock was not acquired or the job is paused.", "job", "reason"),
+		duration:    registry.NewHistogram(prefix+"_job_duration_seconds", "Cron job run duration including retries.", nil, "job"),
+		lastRun:     registry.NewGauge(prefix+"_job_last_run_timestamp_seconds", "Unix time the job last finished.", "job"),
+		lastSuccess: registry.NewGauge(prefix+"_job_last_success_timestamp_seconds", "Unix time the job last succeeded.", "job"),
+		running:     registry.NewGauge(prefix+"_job_running", "Whether the job is running on this instance.", "job"),
+		leader:      registry.NewGauge(prefix+"_leader", "Whether this instance runs the scheduler loop (1) or stands by (0)."),
+
+		sinceSuccess: registry.NewGauge(prefix+"_job_seconds_since_last_success", "Seconds since the job last succeeded, or since it was registered if it has not succeeded yet.", "job"),
+		succeededAt:  make(map[string]time.Time),
+	}
+}
+
+// register khởi tạo series = 0 cho job mới, để rate()/increase() có giá trị gốc ngay từ lần chạy đầu.
+// Job chưa thành công lần nào tính seconds_since_last_success từ lúc đăng ký, nên job không bao giờ chạy được vẫn bị alert.
+func (m *schedulerMetrics) register(job string, registeredAt time.Time) {
+	m.runs.Add(0, job, runStatusSuccess)
+	m.runs.Add(0, job, runStatusFailure)
+	m.retries.Add(0, job)
+	m.running.Set(0, job)
+
+	m.mu.Lock()
+	if _, exists := m.succeededAt[job]; !exists {
+		m.succeededAt[job] = registeredAt
+	}
+	m.mu.Unlock()
+}
+
+// unregister xóa series theo trạng thái hiện tại của job đã bị gỡ, counter được giữ lại
+func (m *schedulerMetrics) unregister(job string) {
+	m.running.Delete(job)
+	m.sinceSuccess.Delete(job)
+
+	m.mu.Lock()
+	delete(m.succeededAt, job)
+	m.mu.Unlock()
+}
+
+// observe ghi nhận kết quả 1 lần chạy job
+func (m *schedulerMetrics) observe(job string, finishedAt time.Time, duration time.Duration, success bool) {
+	status := runStatusFailure
+	if success {
+		status = runStatusSuccess
+		m.lastSuccess.Set(float64(finishedAt.Unix()), job)
+
+		m.mu.Lock()
+		if _, exists := m.succeededAt[job]; exists {
+			m.succeededAt[job] = finishedAt
+		}
+		m.mu.Unlock()
+	}
+	m.runs.Inc(job, status)
+	m.duration.Observe(duration.Seconds(), job)
+	m.lastRun.Set(float64(finishedAt.Unix()), job)
+}
+
+// collector cập nhật trạng thái leader và thời gian từ lần thành công gần nhất lúc scrape
+func (m *schedulerMetrics) collector(s *SchedulerImpl) metrics.Collector {
+	return metrics.CollectorFunc(func(ctx context.Context) {
+		leader := 0.0
+		if s.IsLeader() {
+			leader = 1
+		}
+		m.leader.Set(leader)
+
+		now := s.config.Clock.Now()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for job, at := range m.succeededAt {
+			m.sinceSuccess.Set(now.Sub(at).Seconds(), job)
+		}
+	})
+}
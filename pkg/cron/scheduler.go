@@ -3,9 +3,16 @@ package cron
 import (
 	"context"
 	"fmt"
+	"math/rand/v2"
+	"os"
+	"sort"
 	"sync"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+
 	"github.com/robfig/cron/v3"
 )
 
@@ -14,10 +21,15 @@ type SchedulerImpl struct {
 	cron        *cron.Cron
 	jobs        map[string]Job
 	jobStatuses map[string]*JobStatus
+	entries     map[string]cron.EntryID // Entry của job trong robfig/cron, dùng cho NextRun và RemoveJob
+	paused      map[string]bool
 	lockManager LockManager
 	config      Config
 	mu          sync.RWMutex
 	running     bool
+	leader      bool
+	loopDone    chan struct{}
+	metrics     *schedulerMetrics
 	ctx         context.Context
 	cancel      context.CancelFunc
 }
@@ -42,6 +54,21 @@ func NewScheduler(lockManager LockManager, config Config) *SchedulerImpl {
 	if config.MetricsPrefix == "" {
 		config.MetricsPrefix = "cron"
 	}
+	if config.Clock == nil {
+		config.Clock = clock.Default()
+	}
+	if config.LeaderRenewInterval == 0 {
+		config.LeaderRenewInterval = 5 * time.Second
+	}
+	if config.Host == "" {
+		config.Host, _ = os.Hostname()
+	}
+	if config.ControlSyncInterval == 0 {
+		config.ControlSyncInterval = time.Second
+	}
+	if config.Logger == nil {
+		config.Logger = stdoutLogger{}
+	}
 
 	// Create cron scheduler with timezone
 	location, err := time.LoadLocation(config.TimeZone)
@@ -54,13 +81,26 @@ func NewScheduler(lockManager LockManager, config Config) *SchedulerImpl {
 		cron.WithLogger(cron.DefaultLogger),
 	)
 
-	return &SchedulerImpl{
+	s := &SchedulerImpl{
 		cron:        c,
 		jobs:        make(map[string]Job),
 		jobStatuses: make(map[string]*JobStatus),
+		entries:     make(map[string]cron.EntryID),
+		paused:      make(map[string]bool),
 		lockManager: lockManager,
 		config:      config,
 	}
+
+	if config.EnableMetrics {
+		if config.Metrics == nil {
+			config.Metrics = metrics.Default()
+			s.config.Metrics = config.Metrics
+		}
+		s.metrics = newSchedulerMetrics(config.Metrics, config.MetricsPrefix)
+		config.Metrics.RegisterCollector(s.metrics.collector(s))
+	}
+
+	return s
 }
 
 // AddJob adds a job to the scheduler
@@ -74,21 +114,30 @@ func (s *SchedulerImpl) AddJob(job Job) error {
 	}
 
 	// Add job to cron scheduler
-	_, err := s.cron.AddFunc(job.Schedule(), s.createJobWrapper(job))
+	spec, timeZone, err := s.scheduleSpec(job)
+	if err != nil {
+		return fmt.Errorf("failed to add job %s: %w", job.Name(), err)
+	}
+	entryID, err := s.cron.AddFunc(spec, s.createJobWrapper(job))
 	if err != nil {
 		return fmt.Errorf("failed to add job %s: %w", job.Name(), err)
 	}
 
 	// Store job and initialize status
 	s.jobs[job.Name()] = job
+	s.entries[job.Name()] = entryID
 	s.jobStatuses[job.Name()] = &JobStatus{
 		Name:      job.Name(),
 		Schedule:  job.Schedule(),
-		CreatedAt: time.Now(),
+		TimeZone:  timeZone,
+		CreatedAt: s.config.Clock.Now(),
+	}
+	if s.metrics != nil {
+		s.metrics.register(job.Name(), s.config.Clock.Now())
 	}
 
 	// If scheduler is running, start the job immediately
-	if s.running {
+	if s.running && s.leader {
 		s.cron.Start()
 	}
 
@@ -101,30 +150,99 @@ func (s *SchedulerImpl) RemoveJob(jobName string) error {
 	defer s.mu.Unlock()
 
 	// Check if job exists
-	_, exists := s.jobs[jobName]
+	entryID, exists := s.entries[jobName]
 	if !exists {
-		return fmt.Errorf("job %s does not exist", jobName)
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
 	}
 
-	// Remove from cron scheduler
-	entries := s.cron.Entries()
-	for _, entry := range entries {
-		// We need to find the entry by comparing the job name
-		// This is a limitation of the robfig/cron library
-		// In a real implementation, you might want to track entry IDs
-		_ = entry
-	}
+	// Remove from cron scheduler, lần chạy đang diễn ra vẫn chạy tới khi xong và tự nhả lock của nó
+	s.cron.Remove(entryID)
 
 	// Remove from maps
 	delete(s.jobs, jobName)
 	delete(s.jobStatuses, jobName)
+	delete(s.entries, jobName)
+	if s.metrics != nil {
+		s.metrics.unregister(jobName)
+	}
+
+	return nil
+}
+
+// ReplaceJob thay job cùng tên (lịch và implementation mới) lúc runtime, giữ nguyên trạng thái và số lần chạy.
+// Lịch không hợp lệ thì job cũ vẫn chạy như trước. Lần chạy đang diễn ra không bị hủy.
+func (s *SchedulerImpl) ReplaceJob(job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.replaceJob(job)
+}
+
+// UpdateSchedule đổi lịch chạy của job lúc runtime, xem ReplaceJob
+func (s *SchedulerImpl) UpdateSchedule(jobName, schedule string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, exists := s.jobs[jobName]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
+	}
+	if rescheduled, ok := job.(*rescheduledJob); ok {
+		job = rescheduled.Job
+	}
+	return s.replaceJob(&rescheduledJob{Job: job, schedule: schedule})
+}
 
-	// Release any existing lock
-	_ = s.lockManager.ReleaseLock(context.Background(), jobName)
+// replaceJob thêm entry mới trước rồi mới xóa entry cũ. Caller giữ s.mu.
+func (s *SchedulerImpl) replaceJob(job Job) error {
+	oldEntryID, exists := s.entries[job.Name()]
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, job.Name())
+	}
+
+	spec, timeZone, err := s.scheduleSpec(job)
+	if err != nil {
+		return fmt.Errorf("failed to replace job %s: %w", job.Name(), err)
+	}
+	entryID, err := s.cron.AddFunc(spec, s.createJobWrapper(job))
+	if err != nil {
+		return fmt.Errorf("failed to replace job %s: %w", job.Name(), err)
+	}
+	s.cron.Remove(oldEntryID)
+
+	s.jobs[job.Name()] = job
+	s.entries[job.Name()] = entryID
+	s.jobStatuses[job.Name()].Schedule = job.Schedule()
+	s.jobStatuses[job.Name()].TimeZone = timeZone
 
 	return nil
 }
 
+// scheduleSpec cron spec của job kèm timezone riêng (TimeZoneProvider) dạng "CRON_TZ=<tz> <schedule>",
+// trả về timezone áp dụng cho job
+func (s *SchedulerImpl) scheduleSpec(job Job) (string, string, error) {
+	provider, ok := job.(TimeZoneProvider)
+	if !ok || provider.TimeZone() == "" {
+		return job.Schedule(), s.config.TimeZone, nil
+	}
+
+	timeZone := provider.TimeZone()
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return "", "", fmt.Errorf("invalid timezone %q: %w", timeZone, err)
+	}
+	return "CRON_TZ=" + timeZone + " " + job.Schedule(), timeZone, nil
+}
+
+// rescheduledJob job chạy theo lịch khác với Job.Schedule() (UpdateSchedule)
+type rescheduledJob struct {
+	Job
+	schedule string
+}
+
+// Schedule implements Job
+func (j *rescheduledJob) Schedule() string {
+	return j.schedule
+}
+
 // Start starts the scheduler
 func (s *SchedulerImpl) Start(ctx context.Context) error {
 	s.mu.Lock()
@@ -136,30 +254,54 @@ func (s *SchedulerImpl) Start(ctx context.Context) error {
 
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	s.running = true
-	s.cron.Start()
+
+	if s.config.LeaderElector != nil {
+		// Chỉ leader chạy cron loop, instance khác đứng chờ đến khi leader mất quyền
+		s.loopDone = make(chan struct{})
+		go s.leaderLoop(s.ctx, s.loopDone)
+	} else {
+		s.leader = true
+		s.cron.Start()
+	}
 
 	// Start cleanup goroutine for memory locks
 	if _, ok := s.lockManager.(*MemoryLockManager); ok {
 		go s.cleanupExpiredLocks()
 	}
 
+	// Đồng bộ pause/trigger/trạng thái job với admin API
+	if s.config.Control != nil {
+		go s.controlLoop(s.ctx)
+	}
+
 	return nil
 }
 
 // Stop stops the scheduler
 func (s *SchedulerImpl) Stop() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if !s.running {
+		s.mu.Unlock()
 		return fmt.Errorf("scheduler is not running")
 	}
 
-	s.cron.Stop()
+	s.running = false
 	if s.cancel != nil {
 		s.cancel()
 	}
-	s.running = false
+	loopDone := s.loopDone
+	s.loopDone = nil
+	s.mu.Unlock()
+
+	// Leader loop dừng cron và nhả quyền leader trước khi thoát
+	if loopDone != nil {
+		<-loopDone
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cron.Stop()
+	s.leader = false
 
 	return nil
 }
@@ -171,6 +313,81 @@ func (s *SchedulerImpl) IsRunning() bool {
 	return s.running
 }
 
+// IsLeader returns true if this instance runs the scheduler loop
+func (s *SchedulerImpl) IsLeader() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.leader
+}
+
+// leaderLoop định kỳ giành/gia hạn quyền leader, start cron khi thành leader và stop khi mất quyền
+func (s *SchedulerImpl) leaderLoop(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(s.config.LeaderRenewInterval)
+	defer ticker.Stop()
+
+	for {
+		campaignCtx, cancel := context.WithTimeout(ctx, s.config.LeaderRenewInterval)
+		isLeader, err := s.config.LeaderElector.Campaign(campaignCtx)
+		cancel()
+		if err != nil {
+			// Không xác nhận được quyền leader thì dừng, tránh 2 instance cùng chạy job
+			s.config.Logger.Errorf("Scheduler: leader election failed: %v", err)
+			isLeader = false
+		}
+		s.setLeader(ctx, isLeader)
+
+		select {
+		case <-ctx.Done():
+			s.stepDown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// setLeader chuyển trạng thái leader, start/stop cron khi trạng thái thay đổi
+func (s *SchedulerImpl) setLeader(ctx context.Context, isLeader bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ctx.Err() != nil || isLeader == s.leader {
+		return
+	}
+
+	s.leader = isLeader
+	if isLeader {
+		fmt.Println("Scheduler: became leader, starting cron loop")
+		s.cron.Start()
+		return
+	}
+
+	fmt.Println("Scheduler: lost leadership, stopping cron loop")
+	s.cron.Stop()
+}
+
+// stepDown dừng cron và nhả quyền leader để instance khác tiếp quản ngay
+func (s *SchedulerImpl) stepDown() {
+	s.mu.Lock()
+	wasLeader := s.leader
+	s.leader = false
+	s.mu.Unlock()
+
+	if !wasLeader {
+		return
+	}
+
+	// Chờ job đang chạy kết thúc trước khi nhả quyền leader
+	<-s.cron.Stop().Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.config.LeaderElector.Resign(ctx); err != nil {
+		s.config.Logger.Errorf("Scheduler: failed to resign leadership: %v", err)
+	}
+}
+
 // GetJobStatus returns the status of a specific job
 func (s *SchedulerImpl) GetJobStatus(jobName string) (*JobStatus, error) {
 	s.mu.RLock()
@@ -181,21 +398,9 @@ func (s *SchedulerImpl) GetJobStatus(jobName string) (*JobStatus, error) {
 		return nil, fmt.Errorf("job %s does not exist", jobName)
 	}
 
-	// Update next run time
-	_ = s.jobs[jobName]
-	entries := s.cron.Entries()
-	for _, entry := range entries {
-		// Find the entry for this job and update next run time
-		// This is a simplified approach - in practice you'd track entry IDs
-		status.NextRun = entry.Next
-		break
-	}
-
-	// Check if job is locked
-	isLocked, _ := s.lockManager.IsLocked(context.Background(), jobName)
-	status.IsLocked = isLocked
-
-	return status, nil
+	// Create a copy to avoid race conditions
+	statusCopy := s.statusOf(jobName, status)
+	return &statusCopy, nil
 }
 
 // GetJobStatuses returns the status of all jobs
@@ -206,65 +411,267 @@ func (s *SchedulerImpl) GetJobStatuses() map[string]*JobStatus {
 	statuses := make(map[string]*JobStatus)
 	for name, status := range s.jobStatuses {
 		// Create a copy to avoid race conditions
-		statusCopy := *status
+		statusCopy := s.statusOf(name, status)
+		statuses[name] = &statusCopy
+	}
 
-		// Update next run time
-		entries := s.cron.Entries()
-		for _, entry := range entries {
-			statusCopy.NextRun = entry.Next
-			break
+	return statuses
+}
+
+// statusOf bản sao trạng thái job kèm lần chạy kế tiếp (zero khi cron loop không chạy trên instance này),
+// trạng thái lock và tạm dừng. Caller giữ s.mu.
+func (s *SchedulerImpl) statusOf(jobName string, status *JobStatus) JobStatus {
+	statusCopy := *status
+	if entryID, ok := s.entries[jobName]; ok {
+		statusCopy.NextRun = s.cron.Entry(entryID).Next
+	}
+
+	// Check if job is locked
+	isLocked, _ := s.lockManager.IsLocked(context.Background(), jobName)
+	statusCopy.IsLocked = isLocked
+	statusCopy.IsPaused = s.paused[jobName]
+
+	return statusCopy
+}
+
+// PauseJob implements Scheduler
+func (s *SchedulerImpl) PauseJob(ctx context.Context, jobName string) error {
+	return s.setPaused(ctx, jobName, true)
+}
+
+// ResumeJob implements Scheduler
+func (s *SchedulerImpl) ResumeJob(ctx context.Context, jobName string) error {
+	return s.setPaused(ctx, jobName, false)
+}
+
+func (s *SchedulerImpl) setPaused(ctx context.Context, jobName string, paused bool) error {
+	s.mu.RLock()
+	_, exists := s.jobs[jobName]
+	s.mu.RUnlock()
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
+	}
+
+	if control := s.config.Control; control != nil {
+		var err error
+		if paused {
+			err = control.Pause(ctx, jobName)
+		} else {
+			err = control.Resume(ctx, jobName)
 		}
+		if err != nil {
+			return err
+		}
+	}
 
-		// Check if job is locked
-		isLocked, _ := s.lockManager.IsLocked(context.Background(), name)
-		statusCopy.IsLocked = isLocked
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if paused {
+		s.paused[jobName] = true
+	} else {
+		delete(s.paused, jobName)
+	}
+	return nil
+}
 
-		statuses[name] = &statusCopy
+// TriggerJob implements Scheduler, job chạy ngoài lịch vẫn lấy lock (hoặc yêu cầu quyền leader) như lần chạy theo lịch
+func (s *SchedulerImpl) TriggerJob(jobName string) error {
+	s.mu.RLock()
+	job, exists := s.jobs[jobName]
+	running, leader := s.running, s.leader
+	isRunning := exists && s.jobStatuses[jobName].IsRunning
+	s.mu.RUnlock()
+
+	switch {
+	case !exists:
+		return fmt.Errorf("%w: %s", ErrJobNotFound, jobName)
+	case !running:
+		return ErrSchedulerNotRunning
+	case !leader:
+		return ErrNotLeader
+	case isRunning:
+		return fmt.Errorf("%w: %s", ErrJobRunning, jobName)
 	}
 
-	return statuses
+	s.config.Logger.Infof("Job %s: triggered manually", jobName)
+	go s.runJob(job, true)
+	return nil
+}
+
+// controlLoop đồng bộ với Config.Control mỗi ControlSyncInterval cho tới khi scheduler dừng
+func (s *SchedulerImpl) controlLoop(ctx context.Context) {
+	ticker := time.NewTicker(s.config.ControlSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		s.syncControl(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncControl đọc danh sách job bị tạm dừng; nếu đang chạy cron loop thì chạy các job được yêu cầu chạy ngay
+// và công bố trạng thái job. Snapshot hết hạn sau 5 chu kỳ để API biết khi không còn scheduler nào chạy.
+func (s *SchedulerImpl) syncControl(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.ControlSyncInterval)
+	defer cancel()
+	control := s.config.Control
+
+	names, err := control.PausedJobs(ctx)
+	if err != nil {
+		s.config.Logger.Errorf("Scheduler: failed to read paused jobs: %v", err)
+	} else {
+		paused := make(map[string]bool, len(names))
+		for _, name := range names {
+			paused[name] = true
+		}
+		s.mu.Lock()
+		s.paused = paused
+		s.mu.Unlock()
+	}
+
+	// Instance đang đứng chờ quyền leader không nhận yêu cầu chạy ngay
+	if !s.IsLeader() {
+		return
+	}
+
+	triggers, err := control.TakeTriggers(ctx)
+	if err != nil {
+		s.config.Logger.Errorf("Scheduler: failed to read job triggers: %v", err)
+	}
+	seen := make(map[string]bool, len(triggers))
+	for _, name := range triggers {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if err := s.TriggerJob(name); err != nil {
+			s.config.Logger.Errorf("Scheduler: failed to trigger job %s: %v", name, err)
+		}
+	}
+
+	if err := control.PublishSnapshot(ctx, s.snapshot(), 5*s.config.ControlSyncInterval); err != nil {
+		s.config.Logger.Errorf("Scheduler: %v", err)
+	}
+}
+
+// snapshot trạng thái các job, sắp xếp theo tên
+func (s *SchedulerImpl) snapshot() Snapshot {
+	statuses := s.GetJobStatuses()
+	jobs := make([]JobStatus, 0, len(statuses))
+	for _, status := range statuses {
+		jobs = append(jobs, *status)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+
+	return Snapshot{
+		Host:      s.config.Host,
+		UpdatedAt: s.config.Clock.Now(),
+		Jobs:      jobs,
+	}
 }
 
 // createJobWrapper creates a wrapper function for a job that handles locking and execution
 func (s *SchedulerImpl) createJobWrapper(job Job) func() {
 	return func() {
-		// Check if scheduler context is still valid
-		if s.ctx == nil {
-			fmt.Printf("Job %s: scheduler context is nil\n", job.Name())
-			return
-		}
+		s.runJob(job, false)
+	}
+}
 
-		select {
-		case <-s.ctx.Done():
-			fmt.Printf("Job %s: scheduler context cancelled: %v\n", job.Name(), s.ctx.Err())
-			return
-		default:
+// runJob chạy 1 lần job, manual: chạy từ TriggerJob nên không bị bỏ qua khi job đang tạm dừng
+func (s *SchedulerImpl) runJob(job Job, manual bool) {
+	// Check if scheduler context is still valid
+	if s.ctx == nil {
+		s.config.Logger.Errorf("Job %s: scheduler context is nil", job.Name())
+		return
+	}
+
+	select {
+	case <-s.ctx.Done():
+		s.config.Logger.Infof("Job %s: scheduler context cancelled: %v", job.Name(), s.ctx.Err())
+		return
+	default:
+	}
+
+	if !manual && s.isPaused(job.Name()) {
+		s.config.Logger.Infof("Job %s: paused, skipping scheduled run", job.Name())
+		if s.metrics != nil {
+			s.metrics.skipped.Inc(job.Name(), "paused")
 		}
+		return
+	}
 
-		ctx, cancel := context.WithTimeout(s.ctx, job.Timeout())
-		defer cancel()
+	// Jitter chờ trước khi lấy lock và không tính vào timeout của job
+	if !manual && !s.waitJitter(job) {
+		return
+	}
 
-		fmt.Printf("Job %s: starting execution\n", job.Name())
+	ctx, cancel := context.WithTimeout(s.ctx, job.Timeout())
+	defer cancel()
+	// Job lấy thời gian qua clock.FromContext(ctx) để test được với frozen clock
+	ctx = clock.WithContext(ctx, s.config.Clock)
 
-		// Try to acquire lock
-		acquired, err := s.acquireLockWithRetry(ctx, job.Name())
-		if err != nil {
-			fmt.Printf("Job %s: failed to acquire lock: %v\n", job.Name(), err)
-			s.updateJobStatus(job.Name(), false, fmt.Sprintf("failed to acquire lock: %v", err))
+	s.config.Logger.Debugf("Job %s: starting execution", job.Name())
+	// Leader election: chỉ leader chạy cron loop nên không cần lock theo job
+	if s.config.LeaderElector != nil {
+		if !s.IsLeader() {
 			return
 		}
+		s.executeJobWithRetry(ctx, job, false)
+		return
+	}
 
-		if !acquired {
-			// Another instance is running this job
-			fmt.Printf("Job %s: lock not acquired, another instance running\n", job.Name())
-			return
+	// Try to acquire lock
+	acquired, err := s.acquireLockWithRetry(ctx, job.Name())
+	if err != nil {
+		s.config.Logger.Errorf("Job %s: failed to acquire lock: %v", job.Name(), err)
+		s.updateJobStatus(job.Name(), false, fmt.Sprintf("failed to acquire lock: %v", err))
+		if s.metrics != nil {
+			s.metrics.skipped.Inc(job.Name(), "lock_error")
 		}
+		return
+	}
+
+	if !acquired {
+		// Another instance is running this job
+		s.config.Logger.Debugf("Job %s: lock not acquired, another instance running", job.Name())
+		if s.metrics != nil {
+			s.metrics.skipped.Inc(job.Name(), "lock_not_acquired")
+		}
+		return
+	}
 
-		// Execute job with retries
-		s.executeJobWithRetry(ctx, job)
+	// Execute job with retries
+	s.executeJobWithRetry(ctx, job, true)
+}
+
+// waitJitter chờ ngẫu nhiên trong khoảng Jitter() của job, false nếu scheduler dừng trong lúc chờ
+func (s *SchedulerImpl) waitJitter(job Job) bool {
+	provider, ok := job.(JitterProvider)
+	if !ok || provider.Jitter() <= 0 {
+		return true
+	}
+
+	delay := time.Duration(rand.Int64N(int64(provider.Jitter())))
+	select {
+	case <-s.ctx.Done():
+		return false
+	case <-time.After(delay):
+		return true
 	}
 }
 
+// isPaused job đang bị tạm dừng
+func (s *SchedulerImpl) isPaused(jobName string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.paused[jobName]
+}
+
 // acquireLockWithRetry attempts to acquire a lock with retries
 func (s *SchedulerImpl) acquireLockWithRetry(ctx context.Context, jobName string) (bool, error) {
 	for i := 0; i < s.config.MaxLockRetries; i++ {
@@ -289,33 +696,40 @@ func (s *SchedulerImpl) acquireLockWithRetry(ctx context.Context, jobName string
 }
 
 // executeJobWithRetry executes a job with retries
-func (s *SchedulerImpl) executeJobWithRetry(ctx context.Context, job Job) {
+func (s *SchedulerImpl) executeJobWithRetry(ctx context.Context, job Job, locked bool) {
 	var lastErr error
 	retryCount := 0
 
 	// Ensure lock is released when function exits
 	defer func() {
+		if !locked {
+			return
+		}
 		// Release lock after job completion
 		if err := s.lockManager.ReleaseLock(ctx, job.Name()); err != nil {
-			fmt.Printf("Job %s: failed to release lock: %v\n", job.Name(), err)
+			s.config.Logger.Errorf("Job %s: failed to release lock: %v", job.Name(), err)
 		} else {
-			fmt.Printf("Job %s: lock released successfully\n", job.Name())
+			s.config.Logger.Debugf("Job %s: lock released successfully", job.Name())
 		}
 	}()
 
+	runStart := s.config.Clock.Now()
+	if s.metrics != nil {
+		s.metrics.running.Set(1, job.Name())
+		defer s.metrics.running.Set(0, job.Name())
+	}
+
 	for retryCount <= job.RetryCount() {
 		// Update job status
 		s.updateJobStatus(job.Name(), true, "")
 
 		// Execute job
-		startTime := time.Now()
 		err := job.Run(ctx)
-		duration := time.Since(startTime)
 
 		if err == nil {
 			// Job succeeded
 			s.updateJobStatus(job.Name(), false, "")
-			s.recordJobResult(job.Name(), startTime, duration, true, "", retryCount)
+			s.recordJobResult(job.Name(), runStart, s.config.Clock.Since(runStart), true, "", retryCount)
 			return
 		}
 
@@ -324,9 +738,13 @@ func (s *SchedulerImpl) executeJobWithRetry(ctx context.Context, job Job) {
 
 		// If we have retries left, wait before retrying
 		if retryCount <= job.RetryCount() {
+			if s.metrics != nil {
+				s.metrics.retries.Inc(job.Name())
+			}
 			select {
 			case <-ctx.Done():
 				s.updateJobStatus(job.Name(), false, fmt.Sprintf("job cancelled: %v", ctx.Err()))
+				s.recordJobResult(job.Name(), runStart, s.config.Clock.Since(runStart), false, ctx.Err().Error(), retryCount-1)
 				return
 			case <-time.After(job.RetryDelay()):
 				continue
@@ -335,8 +753,12 @@ func (s *SchedulerImpl) executeJobWithRetry(ctx context.Context, job Job) {
 	}
 
 	// Job failed after all retries
+	errorreport.Capture(ctx, lastErr, map[string]string{"cron_job": job.Name()}, map[string]interface{}{
+		"retry_count": retryCount - 1,
+		"host":        s.config.Host,
+	})
 	s.updateJobStatus(job.Name(), false, lastErr.Error())
-	s.recordJobResult(job.Name(), time.Now(), 0, false, lastErr.Error(), retryCount-1)
+	s.recordJobResult(job.Name(), runStart, s.config.Clock.Since(runStart), false, lastErr.Error(), retryCount-1)
 }
 
 // updateJobStatus updates the status of a job
@@ -358,15 +780,21 @@ func (s *SchedulerImpl) updateJobStatus(jobName string, isRunning bool, lastErro
 	}
 
 	if !isRunning {
-		status.LastRun = time.Now()
+		status.LastRun = s.config.Clock.Now()
 		status.RunCount++
 	}
 }
 
 // recordJobResult records the result of a job execution
 func (s *SchedulerImpl) recordJobResult(jobName string, startTime time.Time, duration time.Duration, success bool, error string, retryCount int) {
-	// This could be extended to store job results in a database or metrics system
-	_ = JobResult{
+	if s.metrics != nil {
+		s.metrics.observe(jobName, startTime.Add(duration), duration, success)
+	}
+
+	if s.config.ResultRecorder == nil {
+		return
+	}
+	result := JobResult{
 		JobName:    jobName,
 		StartTime:  startTime,
 		EndTime:    startTime.Add(duration),
@@ -374,6 +802,14 @@ func (s *SchedulerImpl) recordJobResult(jobName string, startTime time.Time, dur
 		Success:    success,
 		Error:      error,
 		RetryCount: retryCount,
+		Host:       s.config.Host,
+	}
+
+	// Không theo context của job: job hết timeout/bị hủy vẫn phải được ghi lại
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.config.ResultRecorder.RecordResult(ctx, result); err != nil {
+		s.config.Logger.Errorf("Job %s: failed to record result: %v", jobName, err)
 	}
 }
 
@@ -393,3 +829,10 @@ func (s *SchedulerImpl) cleanupExpiredLocks() {
 		}
 	}
 }
+
+// stdoutLogger default Logger, prints every level to stdout
+type stdoutLogger struct{}
+
+func (stdoutLogger) Debugf(format string, args ...interface{}) { fmt.Printf(format+"\n", args...) }
+func (stdoutLogger) Infof(format string, args ...interface{})  { fmt.Printf(format+"\n", args...) }
+func (stdoutLogger) Errorf(format string, args ...interface{}) { fmt.Printf(format+"\n", args...) }
@@ -0,0 +1,74 @@
+package email
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrAllRecipientsSuppressed tất cả người nhận đều nằm trong suppression list
+var ErrAllRecipientsSuppressed = errors.New("all recipients are suppressed")
+
+// SuppressionChecker kiểm tra địa chỉ email có bị chặn gửi không (hard bounce, complaint...)
+type SuppressionChecker interface {
+	IsSuppressed(ctx context.Context, email string) (bool, error)
+}
+
+// suppressionService bọc EmailService, loại bỏ người nhận bị suppress trước khi gửi
+type suppressionService struct {
+	service EmailService
+	checker SuppressionChecker
+}
+
+// NewSuppressionAwareService tạo EmailService không gửi tới địa chỉ bị suppress
+func NewSuppressionAwareService(service EmailService, checker SuppressionChecker) EmailService {
+	return &suppressionService{
+		service: service,
+		checker: checker,
+	}
+}
+
+// Send gửi email tới các người nhận chưa bị suppress
+func (s *suppressionService) Send(message *EmailMessage) error {
+	if err := s.filter(message); err != nil {
+		return err
+	}
+	return s.service.Send(message)
+}
+
+// SendTemplate gửi email template tới các người nhận chưa bị suppress
+func (s *suppressionService) SendTemplate(message *EmailMessage, templatePath string, data interface{}) error {
+	if err := s.filter(message); err != nil {
+		return err
+	}
+	return s.service.SendTemplate(message, templatePath, data)
+}
+
+// filter loại bỏ địa chỉ bị suppress khỏi To/CC/BCC
+func (s *suppressionService) filter(message *EmailMessage) error {
+	message.To = s.allowed(message.To)
+	message.CC = s.allowed(message.CC)
+	message.BCC = s.allowed(message.BCC)
+
+	if len(message.To) == 0 && len(message.CC) == 0 && len(message.BCC) == 0 {
+		return ErrAllRecipientsSuppressed
+	}
+	return nil
+}
+
+// allowed trả về các địa chỉ được phép gửi
+// Lỗi khi kiểm tra (DB down...) không chặn gửi để email giao dịch vẫn đi được
+func (s *suppressionService) allowed(addresses []string) []string {
+	if len(addresses) == 0 {
+		return addresses
+	}
+
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		suppressed, err := s.checker.IsSuppressed(context.Background(), address)
+		if err == nil && suppressed {
+			continue
+		}
+		result = append(result, address)
+	}
+	return result
+}
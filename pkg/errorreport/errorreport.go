@@ -0,0 +1,254 @@
+package errorreport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Level mức độ của event
+type Level string
+
+const (
+	LevelFatal   Level = "fatal"
+	LevelError   Level = "error"
+	LevelWarning Level = "warning"
+)
+
+// Event lỗi gửi tới Reporter
+type Event struct {
+	Level     Level
+	Message   string                 // Message của logger.Error, rỗng khi có Err
+	Err       error                  // Lỗi gốc (handler, job), nil khi chỉ có Message
+	Panic     bool                   // Err là giá trị recover() được
+	Stack     []uintptr              // Call stack lúc capture (runtime.Callers)
+	Tags      map[string]string      // Tag để lọc/nhóm (queue, job, route...)
+	Extra     map[string]interface{} // Dữ liệu thêm (message_id, attempts...)
+	User      string                 // User ID từ JWT của request
+	Request   *Request               // nil: lỗi ngoài HTTP request (worker, cron)
+	TraceID   string                 // Trace ID của OpenTelemetry, nối event với trace
+	SpanID    string
+	Timestamp time.Time
+}
+
+// Request thông tin request khi lỗi xảy ra trong HTTP handler
+type Request struct {
+	Method    string
+	URL       string // Không gồm query string (có thể chứa token)
+	Route     string // Route pattern của chi, vd /api/v1/users/{id}
+	Headers   map[string]string
+	IP        string
+	RequestID string
+}
+
+// Reporter gửi event lỗi tới một dịch vụ (Sentry...). Report không được chặn caller.
+type Reporter interface {
+	Report(event *Event)
+	// Flush chờ các event đang gửi hoàn tất (gọi trước khi process thoát)
+	Flush(ctx context.Context) error
+}
+
+type reporterHolder struct {
+	reporter Reporter
+}
+
+var current atomic.Pointer[reporterHolder]
+
+// SetReporter đặt Reporter dùng chung, nil: tắt báo lỗi
+func SetReporter(r Reporter) {
+	if r == nil {
+		current.Store(nil)
+		return
+	}
+	current.Store(&reporterHolder{reporter: r})
+}
+
+// Enabled đã có Reporter
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// Flush chờ Reporter gửi hết event
+func Flush(ctx context.Context) error {
+	holder := current.Load()
+	if holder == nil {
+		return nil
+	}
+	return holder.reporter.Flush(ctx)
+}
+
+// Capture báo lỗi err kèm user/request/tag của ctx
+func Capture(ctx context.Context, err error, tags map[string]string, extra map[string]interface{}) {
+	if err == nil || !Enabled() {
+		return
+	}
+	report(ctx, &Event{Level: LevelError, Err: err, Tags: tags, Extra: extra}, 3)
+}
+
+// CapturePanic báo giá trị recover() được, gọi trong defer để stack chỉ tới chỗ panic
+func CapturePanic(ctx context.Context, recovered interface{}, tags map[string]string) {
+	if recovered == nil || !Enabled() {
+		return
+	}
+	err, ok := recovered.(error)
+	if !ok {
+		err = fmt.Errorf("%v", recovered)
+	}
+	report(ctx, &Event{Level: LevelFatal, Err: err, Panic: true, Tags: tags}, 3)
+}
+
+// CaptureMessage báo một message lỗi (logger.Error) không có error gốc
+func CaptureMessage(ctx context.Context, level Level, message string) {
+	if message == "" || !Enabled() {
+		return
+	}
+	report(ctx, &Event{Level: level, Message: message}, 3)
+}
+
+// report điền stack, scope của ctx rồi gửi, skip: số frame bỏ qua tính từ runtime.Callers
+func report(ctx context.Context, event *Event, skip int) {
+	holder := current.Load()
+	if holder == nil {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	pcs := make([]uintptr, 64)
+	event.Stack = pcs[:runtime.Callers(skip, pcs)]
+	event.Timestamp = time.Now()
+
+	if s, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		s.apply(event)
+	}
+	if event.Request != nil {
+		if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+			event.Request.Route = rctx.RoutePattern()
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		event.TraceID = sc.TraceID().String()
+		event.SpanID = sc.SpanID().String()
+	}
+
+	holder.reporter.Report(event)
+}
+
+type scopeKey struct{}
+
+// scope user/request/tag của request hoặc job, được handler/middleware bên trong điền vào
+type scope struct {
+	mu      sync.Mutex
+	user    string
+	request *Request
+	tags    map[string]string
+}
+
+func (s *scope) apply(event *Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.User == "" {
+		event.User = s.user
+	}
+	if s.request != nil && event.Request == nil {
+		request := *s.request
+		event.Request = &request
+	}
+	if len(s.tags) > 0 {
+		tags := make(map[string]string, len(s.tags)+len(event.Tags))
+		for k, v := range s.tags {
+			tags[k] = v
+		}
+		for k, v := range event.Tags {
+			tags[k] = v
+		}
+		event.Tags = tags
+	}
+}
+
+// WithTags context với tag gắn vào mọi event capture từ context đó (queue, job...)
+func WithTags(ctx context.Context, tags map[string]string) context.Context {
+	next := &scope{tags: make(map[string]string, len(tags))}
+	if parent, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		parent.mu.Lock()
+		next.user, next.request = parent.user, parent.request
+		for k, v := range parent.tags {
+			next.tags[k] = v
+		}
+		parent.mu.Unlock()
+	}
+	for k, v := range tags {
+		next.tags[k] = v
+	}
+	return context.WithValue(ctx, scopeKey{}, next)
+}
+
+// SetUser gắn user ID cho event của request hiện tại (JWT middleware gọi sau khi verify token)
+func SetUser(ctx context.Context, userID string) {
+	if s, ok := ctx.Value(scopeKey{}).(*scope); ok && userID != "" {
+		s.mu.Lock()
+		s.user = userID
+		s.mu.Unlock()
+	}
+}
+
+// sensitiveHeaders header không gửi giá trị
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+	"X-Auth-Token":        true,
+	"X-Csrf-Token":        true,
+}
+
+// Middleware gắn thông tin request vào context, đặt trước RecoveryMiddleware và JWT middleware
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		s := &scope{request: newRequest(r)}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), scopeKey{}, s)))
+	})
+}
+
+func newRequest(r *http.Request) *Request {
+	headers := make(map[string]string, len(r.Header))
+	for name, values := range r.Header {
+		if sensitiveHeaders[name] {
+			headers[name] = "[Filtered]"
+			continue
+		}
+		headers[name] = strings.Join(values, ", ")
+	}
+
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+
+	return &Request{
+		Method:    r.Method,
+		URL:       scheme + "://" + r.Host + r.URL.Path,
+		Headers:   headers,
+		IP:        utils.GetClientIP(r),
+		RequestID: chimiddleware.GetReqID(r.Context()),
+	}
+}
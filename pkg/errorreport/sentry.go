@@ -0,0 +1,296 @@
+package errorreport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SentryConfig cấu hình Sentry driver
+type SentryConfig struct {
+	DSN         string        // https://<key>@<host>/<project_id>
+	Environment string        // Tag environment (production, staging)
+	Release     string        // Tag release (phiên bản đang chạy)
+	ServerName  string        // Rỗng: os.Hostname()
+	SampleRate  float64       // Tỉ lệ event được gửi (0-1], 0: 1
+	Timeout     time.Duration // Timeout mỗi request, 0: 5s
+	BufferSize  int           // Số event chờ gửi, đầy thì bỏ event mới, 0: 100
+}
+
+// SentryReporter gửi event tới Sentry qua envelope API (https://develop.sentry.dev/sdk/envelopes/),
+// event được gửi trong goroutine nền để không chặn request/job.
+type SentryReporter struct {
+	config     SentryConfig
+	endpoint   string
+	auth       string
+	module     string // Module path của ứng dụng, frame thuộc module được đánh dấu in_app
+	httpClient *http.Client
+
+	events  chan *Event
+	pending sync.WaitGroup
+}
+
+var _ Reporter = (*SentryReporter)(nil)
+
+// NewSentryReporter tạo Sentry driver và goroutine gửi event
+func NewSentryReporter(cfg SentryConfig) (*SentryReporter, error) {
+	dsn, err := url.Parse(cfg.DSN)
+	if err != nil || dsn.User == nil || dsn.User.Username() == "" || dsn.Host == "" {
+		return nil, fmt.Errorf("errorreport: invalid Sentry DSN")
+	}
+	path := strings.TrimSuffix(dsn.Path, "/")
+	i := strings.LastIndex(path, "/")
+	projectID := path[i+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("errorreport: Sentry DSN has no project ID")
+	}
+
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = 1
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 100
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName, _ = os.Hostname()
+	}
+
+	r := &SentryReporter{
+		config:     cfg,
+		endpoint:   fmt.Sprintf("%s://%s%s/api/%s/envelope/", dsn.Scheme, dsn.Host, path[:i], projectID),
+		auth:       fmt.Sprintf("Sentry sentry_version=7, sentry_client=go-api-core/1.0, sentry_key=%s", dsn.User.Username()),
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		events:     make(chan *Event, cfg.BufferSize),
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		r.module = info.Main.Path
+	}
+
+	go r.run()
+	return r, nil
+}
+
+// Report implements Reporter
+func (r *SentryReporter) Report(event *Event) {
+	if r.config.SampleRate < 1 && rand.Float64() >= r.config.SampleRate {
+		return
+	}
+
+	r.pending.Add(1)
+	select {
+	case r.events <- event:
+	default:
+		r.pending.Done()
+	}
+}
+
+// Flush implements Reporter
+func (r *SentryReporter) Flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		r.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *SentryReporter) run() {
+	for event := range r.events {
+		// Không dùng pkg/logger: logger.Error báo lỗi qua Reporter, lỗi gửi sẽ lặp vô hạn
+		if err := r.send(event); err != nil {
+			log.Printf("errorreport: failed to send event to Sentry: %v", err)
+		}
+		r.pending.Done()
+	}
+}
+
+func (r *SentryReporter) send(event *Event) error {
+	eventID := strings.ReplaceAll(uuid.NewString(), "-", "")
+	payload, err := json.Marshal(r.payload(eventID, event))
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	header, _ := json.Marshal(map[string]string{"event_id": eventID, "sent_at": time.Now().UTC().Format(time.RFC3339)})
+	item, _ := json.Marshal(map[string]interface{}{"type": "event", "length": len(payload)})
+	body.Write(header)
+	body.WriteByte('\n')
+	body.Write(item)
+	body.WriteByte('\n')
+	body.Write(payload)
+	body.WriteByte('\n')
+
+	req, err := http.NewRequest(http.MethodPost, r.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", r.auth)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sentryFrame frame của stack trace theo format Sentry
+type sentryFrame struct {
+	Function string `json:"function"`
+	Module   string `json:"module,omitempty"`
+	AbsPath  string `json:"abs_path"`
+	Lineno   int    `json:"lineno"`
+	InApp    bool   `json:"in_app"`
+}
+
+// payload event theo format Sentry (https://develop.sentry.dev/sdk/event-payloads/)
+func (r *SentryReporter) payload(eventID string, event *Event) map[string]interface{} {
+	tags := map[string]string{}
+	for k, v := range event.Tags {
+		tags[k] = v
+	}
+
+	p := map[string]interface{}{
+		"event_id":    eventID,
+		"timestamp":   event.Timestamp.UTC().Format(time.RFC3339Nano),
+		"level":       string(event.Level),
+		"platform":    "go",
+		"logger":      "go-api-core",
+		"server_name": r.config.ServerName,
+		"environment": r.config.Environment,
+		"release":     r.config.Release,
+		"tags":        tags,
+	}
+	if len(event.Extra) > 0 {
+		p["extra"] = event.Extra
+	}
+
+	frames := r.frames(event.Stack, event.Panic)
+	if event.Err != nil {
+		errType := reflect.TypeOf(event.Err).String()
+		if event.Panic {
+			errType = "panic"
+		}
+		p["exception"] = map[string]interface{}{
+			"values": []map[string]interface{}{{
+				"type":       errType,
+				"value":      event.Err.Error(),
+				"stacktrace": map[string]interface{}{"frames": frames},
+				"mechanism":  map[string]interface{}{"type": "generic", "handled": !event.Panic},
+			}},
+		}
+	} else {
+		p["message"] = map[string]string{"formatted": event.Message}
+		p["threads"] = map[string]interface{}{
+			"values": []map[string]interface{}{{"current": true, "stacktrace": map[string]interface{}{"frames": frames}}},
+		}
+	}
+
+	if event.User != "" || event.Request != nil {
+		user := map[string]string{}
+		if event.User != "" {
+			user["id"] = event.User
+		}
+		if event.Request != nil && event.Request.IP != "" {
+			user["ip_address"] = event.Request.IP
+		}
+		p["user"] = user
+	}
+	if event.Request != nil {
+		p["request"] = map[string]interface{}{
+			"method":  event.Request.Method,
+			"url":     event.Request.URL,
+			"headers": event.Request.Headers,
+		}
+		if event.Request.Route != "" {
+			p["transaction"] = event.Request.Method + " " + event.Request.Route
+			tags["route"] = event.Request.Route
+		}
+		if event.Request.RequestID != "" {
+			tags["request_id"] = event.Request.RequestID
+		}
+	}
+	if event.TraceID != "" {
+		p["contexts"] = map[string]interface{}{
+			"trace": map[string]string{"trace_id": event.TraceID, "span_id": event.SpanID},
+		}
+	}
+	return p
+}
+
+// frames stack trace cũ nhất trước (theo format Sentry), bỏ frame của runtime và package errorreport.
+// Panic: bỏ các frame của đoạn code recover (trên runtime.gopanic), frame mới nhất là nơi panic.
+func (r *SentryReporter) frames(stack []uintptr, panicked bool) []sentryFrame {
+	var all []runtime.Frame
+	callers := runtime.CallersFrames(stack)
+	for len(stack) > 0 {
+		frame, more := callers.Next()
+		if panicked && frame.Function == "runtime.gopanic" {
+			all = all[:0]
+		} else {
+			all = append(all, frame)
+		}
+		if !more {
+			break
+		}
+	}
+
+	frames := []sentryFrame{}
+	for i := len(all) - 1; i >= 0; i-- {
+		frame := all[i]
+		if frame.Function == "" || strings.HasPrefix(frame.Function, "runtime.") ||
+			strings.Contains(frame.Function, "/pkg/errorreport.") {
+			continue
+		}
+		module, function := splitFunction(frame.Function)
+		frames = append(frames, sentryFrame{
+			Function: function,
+			Module:   module,
+			AbsPath:  frame.File,
+			Lineno:   frame.Line,
+			InApp:    r.module != "" && strings.HasPrefix(frame.Function, r.module+"/"),
+		})
+	}
+	return frames
+}
+
+// splitFunction tách "github.com/a/b/pkg.(*T).Method" thành package và tên hàm
+func splitFunction(name string) (string, string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return "", name
+	}
+	return name[:slash+1+dot], name[slash+1+dot+1:]
+}
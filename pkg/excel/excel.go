@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/telemetry"
+
 	"github.com/xuri/excelize/v2"
 )
 
@@ -43,6 +45,8 @@ func NewExcelManagerFromFile(file *multipart.FileHeader) (*ExcelManager, error)
 
 // ExportToExcel exports data to Excel file
 func (em *ExcelManager) ExportToExcel(data interface{}, sheetName string, headers []string) error {
+	telemetry.Track("excel.export")
+
 	// Create new sheet
 	index, err := em.file.NewSheet(sheetName)
 	if err != nil {
@@ -91,6 +95,8 @@ func (em *ExcelManager) ExportToExcel(data interface{}, sheetName string, header
 
 // ImportFromExcel imports data from Excel file
 func (em *ExcelManager) ImportFromExcel(sheetName string, targetType reflect.Type) (interface{}, error) {
+	telemetry.Track("excel.import")
+
 	rows, err := em.file.GetRows(sheetName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get rows: %w", err)
@@ -138,6 +144,8 @@ func (em *ExcelManager) ImportFromExcel(sheetName string, targetType reflect.Typ
 
 // ExportToCSV exports data to CSV format
 func (em *ExcelManager) ExportToCSV(data interface{}, headers []string, writer io.Writer) error {
+	telemetry.Track("excel.export_csv")
+
 	csvWriter := csv.NewWriter(writer)
 	defer csvWriter.Flush()
 
@@ -181,6 +189,8 @@ func (em *ExcelManager) ExportToCSV(data interface{}, headers []string, writer i
 
 // ImportFromCSV imports data from CSV format
 func (em *ExcelManager) ImportFromCSV(reader io.Reader, targetType reflect.Type) (interface{}, error) {
+	telemetry.Track("excel.import_csv")
+
 	csvReader := csv.NewReader(reader)
 
 	// Read headers
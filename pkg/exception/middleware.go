@@ -1,13 +1,15 @@
 package exception
 
 import (
-	"api-core/pkg/i18n"
-	"api-core/pkg/logger"
-	"api-core/pkg/response"
 	"fmt"
 	"net/http"
 	"runtime/debug"
 
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
 	"github.com/go-chi/chi/v5/middleware"
 )
 
@@ -23,7 +25,9 @@ func RecoveryMiddleware(next http.Handler) http.Handler {
 				// Log the panic
 				stack := debug.Stack()
 
-				logger.Errorf("PANIC [%s]: %v\n%s", requestID, panicErr, stack)
+				// Báo Sentry kèm request/user, ghi log qua Logger (không qua logger.Errorf) để không báo trùng
+				errorreport.CapturePanic(r.Context(), panicErr, nil)
+				logger.Logger.Error().Msgf("PANIC [%s]: %v\n%s", requestID, panicErr, stack)
 
 				// Use the already initialized logger
 				jobLogger.Error().Msgf("PANIC [%s]: %v\n%s", requestID, panicErr, stack)
@@ -89,12 +93,15 @@ func HandleException(w http.ResponseWriter, r *http.Request, err error) {
 		case "CONFLICT":
 			response.Conflict(w, lang, responseCode)
 		case "TIMEOUT":
+			errorreport.Capture(r.Context(), err, nil, nil)
 			response.InternalServerError(w, lang, responseCode)
 		default:
+			errorreport.Capture(r.Context(), err, nil, nil)
 			response.InternalServerError(w, lang, responseCode)
 		}
 	} else {
 		// Handle regular error
+		errorreport.Capture(r.Context(), err, nil, nil)
 		response.InternalServerError(w, lang, response.CodeInternalServerError)
 	}
 }
@@ -110,6 +117,7 @@ func PanicHandler(handler func(http.ResponseWriter, *http.Request)) http.Handler
 				// Log the panic
 				stack := debug.Stack()
 				fmt.Printf("PANIC in handler [%s]: %v\n%s", requestID, panicErr, stack)
+				errorreport.CapturePanic(r.Context(), panicErr, nil)
 
 				// Determine response code
 				var responseCode string
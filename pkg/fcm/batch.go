@@ -0,0 +1,68 @@
+package fcm
+
+import (
+	"context"
+	"sync"
+
+	"firebase.google.com/go/v4/messaging"
+)
+
+// MaxBatchSize số token (message) tối đa FCM nhận trong một lần gửi multicast
+const MaxBatchSize = 500
+
+// sendBatches chia n token thành các batch MaxBatchSize, gửi tối đa Config.BatchConcurrency batch đồng thời
+// (mỗi batch một timeout riêng) và gộp kết quả, Responses cùng thứ tự với token.
+// Batch gửi lỗi (mạng, quota) tính là thất bại với lỗi đó cho mọi token của batch;
+// chỉ trả lỗi khi mọi batch đều lỗi.
+func (c *Client) sendBatches(ctx context.Context, n int, send func(ctx context.Context, start, end int) (*messaging.BatchResponse, error)) (*messaging.BatchResponse, error) {
+	batches := (n + MaxBatchSize - 1) / MaxBatchSize
+	results := make([]*messaging.BatchResponse, batches)
+	errs := make([]error, batches)
+
+	concurrency := c.config.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < batches; i++ {
+		start, end := i*MaxBatchSize, min((i+1)*MaxBatchSize, n)
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i, start, end int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			batchCtx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+			defer cancel()
+			results[i], errs[i] = send(batchCtx, start, end)
+		}(i, start, end)
+	}
+	wg.Wait()
+
+	if batches == 1 {
+		return results[0], errs[0]
+	}
+
+	response := &messaging.BatchResponse{Responses: make([]*messaging.SendResponse, 0, n)}
+	failedBatches := 0
+	for i, result := range results {
+		size := min((i+1)*MaxBatchSize, n) - i*MaxBatchSize
+		if errs[i] != nil || result == nil {
+			failedBatches++
+			for j := 0; j < size; j++ {
+				response.Responses = append(response.Responses, &messaging.SendResponse{Error: errs[i]})
+			}
+			response.FailureCount += size
+			continue
+		}
+		response.Responses = append(response.Responses, result.Responses...)
+		response.SuccessCount += result.SuccessCount
+		response.FailureCount += result.FailureCount
+	}
+	if failedBatches == batches {
+		return nil, errs[0]
+	}
+	return response, nil
+}
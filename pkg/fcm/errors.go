@@ -0,0 +1,69 @@
+package fcm
+
+import (
+	"errors"
+
+	"github.com/anhnq996/go-api-core/pkg/push"
+
+	"firebase.google.com/go/v4/messaging"
+)
+
+// Lý do token bị coi là không còn dùng được
+const (
+	InvalidReasonUnregistered    = push.InvalidReasonUnregistered    // App đã gỡ hoặc token hết hạn
+	InvalidReasonInvalidArgument = push.InvalidReasonInvalidArgument // Token sai định dạng
+)
+
+// InvalidToken token bị FCM từ chối, nên xóa khỏi database để không gửi lại mãi
+type InvalidToken = push.InvalidToken
+
+// IsUnregistered lỗi do token không còn được đăng ký (kể cả lỗi đã wrap bởi SendToToken)
+func IsUnregistered(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if messaging.IsUnregistered(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// isInvalidArgument lỗi INVALID_ARGUMENT (kể cả lỗi đã wrap)
+func isInvalidArgument(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if messaging.IsInvalidArgument(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// InvalidTokens token bị từ chối trong kết quả SendToTokens (responses cùng thứ tự với tokens).
+// UNREGISTERED luôn tính; INVALID_ARGUMENT chỉ tính khi message hợp lệ với token khác trong batch,
+// vì message sai (payload quá lớn...) cũng trả INVALID_ARGUMENT cho mọi token.
+func InvalidTokens(tokens []string, batch *messaging.BatchResponse) []InvalidToken {
+	if batch == nil {
+		return nil
+	}
+
+	messageValid := false
+	for _, resp := range batch.Responses {
+		if resp.Success || !isInvalidArgument(resp.Error) {
+			messageValid = true
+			break
+		}
+	}
+
+	var invalid []InvalidToken
+	for i, resp := range batch.Responses {
+		if i >= len(tokens) || resp.Success {
+			continue
+		}
+		switch {
+		case IsUnregistered(resp.Error):
+			invalid = append(invalid, InvalidToken{Token: tokens[i], Reason: InvalidReasonUnregistered})
+		case messageValid && isInvalidArgument(resp.Error):
+			invalid = append(invalid, InvalidToken{Token: tokens[i], Reason: InvalidReasonInvalidArgument})
+		}
+	}
+	return invalid
+}
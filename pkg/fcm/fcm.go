@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/correlation"
+	"github.com/anhnq996/go-api-core/pkg/telemetry"
+
 	firebase "firebase.google.com/go/v4"
 	"firebase.google.com/go/v4/messaging"
 	"google.golang.org/api/option"
@@ -22,6 +25,9 @@ type Config struct {
 	CredentialsFile string        // Đường dẫn tới file credentials JSON của Firebase
 	Timeout         time.Duration // Timeout cho mỗi request
 	ProjectID       string        // Firebase project ID (optional, có thể lấy từ credentials)
+	Endpoint        string        // Optional: URL FCM API thay thế (emulator, test), bỏ trống dùng Google
+	// BatchConcurrency số batch 500 tokens gửi đồng thời khi SendToTokens/SendAll nhận hơn 500 (default 4)
+	BatchConcurrency int
 }
 
 // NewClient tạo FCM client mới
@@ -30,7 +36,7 @@ func NewClient(cfg *Config) (*Client, error) {
 		return nil, fmt.Errorf("config không được để trống")
 	}
 
-	if cfg.CredentialsFile == "" {
+	if cfg.CredentialsFile == "" && cfg.Endpoint == "" {
 		return nil, fmt.Errorf("credentials file không được để trống")
 	}
 
@@ -38,14 +44,30 @@ func NewClient(cfg *Config) (*Client, error) {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 10 * time.Second
 	}
+	if cfg.BatchConcurrency <= 0 {
+		cfg.BatchConcurrency = 4
+	}
 
 	// Khởi tạo Firebase app
-	opt := option.WithCredentialsFile(cfg.CredentialsFile)
+	var opts []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsFile))
+	} else {
+		opts = append(opts, option.WithoutAuthentication())
+	}
+	if cfg.Endpoint != "" {
+		opts = append(opts, option.WithEndpoint(cfg.Endpoint))
+	}
+
+	var appConfig *firebase.Config
+	if cfg.ProjectID != "" {
+		appConfig = &firebase.Config{ProjectID: cfg.ProjectID}
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
 	defer cancel()
 
-	app, err := firebase.NewApp(ctx, nil, opt)
+	app, err := firebase.NewApp(ctx, appConfig, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("không thể khởi tạo Firebase app: %w", err)
 	}
@@ -65,13 +87,15 @@ func NewClient(cfg *Config) (*Client, error) {
 
 // SendToToken gửi notification đến một device token cụ thể
 func (c *Client) SendToToken(ctx context.Context, token string, notification *Notification, data map[string]string) (string, error) {
+	telemetry.Track("fcm.send")
+
 	if token == "" {
 		return "", fmt.Errorf("token không được để trống")
 	}
 
 	message := &messaging.Message{
 		Token: token,
-		Data:  data,
+		Data:  withRequestID(ctx, data),
 	}
 
 	if notification != nil {
@@ -107,19 +131,18 @@ func (c *Client) SendToToken(ctx context.Context, token string, notification *No
 	return messageID, nil
 }
 
-// SendToTokens gửi notification đến nhiều device tokens (tối đa 500 tokens)
+// SendToTokens gửi notification đến nhiều device tokens.
+// Hơn 500 tokens được chia thành nhiều batch gửi đồng thời (Config.BatchConcurrency), kết quả gộp lại
+// với Responses cùng thứ tự với tokens (xem sendBatches)
 func (c *Client) SendToTokens(ctx context.Context, tokens []string, notification *Notification, data map[string]string) (*messaging.BatchResponse, error) {
+	telemetry.Track("fcm.send_multicast")
+
 	if len(tokens) == 0 {
 		return nil, fmt.Errorf("danh sách tokens không được để trống")
 	}
 
-	if len(tokens) > 500 {
-		return nil, fmt.Errorf("chỉ được gửi tối đa 500 tokens mỗi lần")
-	}
-
 	message := &messaging.MulticastMessage{
-		Tokens: tokens,
-		Data:   data,
+		Data: withRequestID(ctx, data),
 	}
 
 	if notification != nil {
@@ -143,11 +166,12 @@ func (c *Client) SendToTokens(ctx context.Context, tokens []string, notification
 		message.Webpush = notification.Webpush
 	}
 
-	// Gửi multicast message
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
-	response, err := c.messagingClient.SendEachForMulticast(ctx, message)
+	// Gửi multicast message, mỗi batch dùng bản copy của message với tokens của batch
+	response, err := c.sendBatches(ctx, len(tokens), func(ctx context.Context, start, end int) (*messaging.BatchResponse, error) {
+		batch := *message
+		batch.Tokens = tokens[start:end]
+		return c.messagingClient.SendEachForMulticast(ctx, &batch)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("không thể gửi multicast message: %w", err)
 	}
@@ -157,13 +181,15 @@ func (c *Client) SendToTokens(ctx context.Context, tokens []string, notification
 
 // SendToTopic gửi notification đến một topic
 func (c *Client) SendToTopic(ctx context.Context, topic string, notification *Notification, data map[string]string) (string, error) {
+	telemetry.Track("fcm.send_topic")
+
 	if topic == "" {
 		return "", fmt.Errorf("topic không được để trống")
 	}
 
 	message := &messaging.Message{
 		Topic: topic,
-		Data:  data,
+		Data:  withRequestID(ctx, data),
 	}
 
 	if notification != nil {
@@ -201,13 +227,15 @@ func (c *Client) SendToTopic(ctx context.Context, topic string, notification *No
 
 // SendToCondition gửi notification dựa trên điều kiện topic
 func (c *Client) SendToCondition(ctx context.Context, condition string, notification *Notification, data map[string]string) (string, error) {
+	telemetry.Track("fcm.send_condition")
+
 	if condition == "" {
 		return "", fmt.Errorf("condition không được để trống")
 	}
 
 	message := &messaging.Message{
 		Condition: condition,
-		Data:      data,
+		Data:      withRequestID(ctx, data),
 	}
 
 	if notification != nil {
@@ -285,20 +313,17 @@ func (c *Client) UnsubscribeFromTopic(ctx context.Context, tokens []string, topi
 	return response, nil
 }
 
-// SendAll gửi nhiều messages khác nhau (tối đa 500 messages)
+// SendAll gửi nhiều messages khác nhau, hơn 500 messages được chia batch như SendToTokens
 func (c *Client) SendAll(ctx context.Context, messages []*messaging.Message) (*messaging.BatchResponse, error) {
+	telemetry.Track("fcm.send_all")
+
 	if len(messages) == 0 {
 		return nil, fmt.Errorf("danh sách messages không được để trống")
 	}
 
-	if len(messages) > 500 {
-		return nil, fmt.Errorf("chỉ được gửi tối đa 500 messages mỗi lần")
-	}
-
-	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
-	defer cancel()
-
-	response, err := c.messagingClient.SendEach(ctx, messages)
+	response, err := c.sendBatches(ctx, len(messages), func(ctx context.Context, start, end int) (*messaging.BatchResponse, error) {
+		return c.messagingClient.SendEach(ctx, messages[start:end])
+	})
 	if err != nil {
 		return nil, fmt.Errorf("không thể gửi batch messages: %w", err)
 	}
@@ -314,7 +339,7 @@ func (c *Client) SendDryRun(ctx context.Context, token string, notification *Not
 
 	message := &messaging.Message{
 		Token: token,
-		Data:  data,
+		Data:  withRequestID(ctx, data),
 	}
 
 	if notification != nil {
@@ -335,3 +360,34 @@ func (c *Client) SendDryRun(ctx context.Context, token string, notification *Not
 
 	return messageID, nil
 }
+
+// Ping kiểm tra kết nối tới FCM bằng dry-run message tới topic thăm dò (không gửi tới thiết bị nào)
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	if _, err := c.messagingClient.SendDryRun(ctx, &messaging.Message{Topic: "status-probe"}); err != nil {
+		return fmt.Errorf("không thể kết nối FCM: %w", err)
+	}
+	return nil
+}
+
+// DataRequestID key trong data payload chứa request ID đã gửi notification, client gửi lại khi báo lỗi để nối log
+const DataRequestID = "request_id"
+
+// withRequestID bản copy của data kèm request ID của ctx, data đã có key thì giữ nguyên
+func withRequestID(ctx context.Context, data map[string]string) map[string]string {
+	id := correlation.FromContext(ctx)
+	if id == "" {
+		return data
+	}
+	if _, exists := data[DataRequestID]; exists {
+		return data
+	}
+	withID := make(map[string]string, len(data)+1)
+	for k, v := range data {
+		withID[k] = v
+	}
+	withID[DataRequestID] = id
+	return withID
+}
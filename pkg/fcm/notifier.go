@@ -0,0 +1,30 @@
+package fcm
+
+import (
+	"context"
+
+	"github.com/anhnq996/go-api-core/pkg/push"
+)
+
+var _ push.Notifier = (*Client)(nil)
+
+// Send implement push.Notifier: gửi multicast, token bị từ chối nằm trong Result.Invalid (xem InvalidTokens).
+// message.APNS bị bỏ qua, dùng NotificationBuilder.SetAPNSConfig với SendToTokens nếu cần cấu hình APNs qua FCM
+func (c *Client) Send(ctx context.Context, tokens []string, message *push.Message) (*push.Result, error) {
+	notification := NewNotificationBuilder().
+		SetTitle(message.Title).
+		SetBody(message.Body).
+		SetImageURL(message.ImageURL).
+		Build()
+
+	batch, err := c.SendToTokens(ctx, tokens, notification, message.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &push.Result{
+		SuccessCount: batch.SuccessCount,
+		FailureCount: batch.FailureCount,
+		Invalid:      InvalidTokens(tokens, batch),
+	}, nil
+}
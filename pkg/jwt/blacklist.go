@@ -2,19 +2,22 @@ package jwt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
-	"api-core/pkg/cache"
-	"api-core/pkg/i18n"
-	"api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/securitylog"
 )
 
 // Blacklist quản lý danh sách tokens bị blacklist (logout)
 type Blacklist struct {
-	cache  cache.Cache
-	prefix string
+	cache    cache.Cache
+	prefix   string
+	versions *TokenVersions // Thu hồi toàn bộ token của user theo token_version (nil: không kiểm tra)
 }
 
 // NewBlacklist tạo blacklist mới
@@ -25,6 +28,11 @@ func NewBlacklist(c cache.Cache) *Blacklist {
 	}
 }
 
+// SetTokenVersions bật kiểm tra token_version trong MiddlewareWithBlacklist
+func (b *Blacklist) SetTokenVersions(versions *TokenVersions) {
+	b.versions = versions
+}
+
 // Add thêm token vào blacklist
 func (b *Blacklist) Add(token string, expiry time.Time) error {
 	key := b.prefix + token
@@ -51,7 +59,10 @@ func (b *Blacklist) Remove(token string) error {
 	return b.cache.Del(context.Background(), key)
 }
 
-// AddUserTokens blacklist tất cả tokens của user (logout all devices)
+// AddUserTokens blacklist tất cả tokens của user (logout all devices).
+//
+// Deprecated: chặn cả token cấp sau đó (user không đăng nhập lại được đến khi hết hạn),
+// dùng TokenVersions để thu hồi token.
 func (b *Blacklist) AddUserTokens(userID string, expiry time.Time) error {
 	key := fmt.Sprintf("jwt:user:blacklist:%s", userID)
 	ttl := time.Until(expiry)
@@ -70,6 +81,59 @@ func (b *Blacklist) IsUserBlacklisted(userID string) bool {
 	return err == nil
 }
 
+// OptionalMiddlewareWithBlacklist như MiddlewareWithBlacklist nhưng cho request không có token đi qua
+// (route public có nội dung riêng theo user, vd. /storages). Token có gửi kèm mà sai/bị thu hồi vẫn trả 401.
+func (m *Manager) OptionalMiddlewareWithBlacklist(blacklist *Blacklist) func(http.Handler) http.Handler {
+	required := m.MiddlewareWithBlacklist(blacklist)
+	return func(next http.Handler) http.Handler {
+		authenticated := required(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if ExtractTokenFromHeader(r) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
+// VerifyTokenWithBlacklist verify access token rồi kiểm tra blacklist, token_version và user blacklist.
+// Lỗi: ErrInvalidToken (sai hoặc bị blacklist), ErrExpiredToken, ErrTokenRevoked (token_version cũ),
+// ErrTokenStoreUnavailable (không kiểm tra được). Token hợp lệ nhưng bị thu hồi vẫn trả về claims kèm lỗi.
+func (m *Manager) VerifyTokenWithBlacklist(ctx context.Context, token string, blacklist *Blacklist) (*Claims, error) {
+	if blacklist.IsBlacklisted(token) {
+		return nil, ErrInvalidToken
+	}
+
+	claims, err := m.VerifyToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Token cấp trước lần logout all / đổi password gần nhất
+	if blacklist.versions != nil {
+		stale, err := blacklist.versions.IsStale(ctx, claims)
+		if err != nil {
+			return claims, fmt.Errorf("%w: %w", ErrTokenStoreUnavailable, err)
+		}
+		if stale {
+			return claims, ErrTokenRevoked
+		}
+	}
+
+	// Kiểm tra user có bị blacklist không (AddUserTokens, giữ để tương thích)
+	if blacklist.IsUserBlacklisted(claims.UserID) {
+		return claims, ErrInvalidToken
+	}
+
+	// Impersonation token bị thu hồi khi admin logout all
+	if claims.IsImpersonated() && blacklist.IsUserBlacklisted(claims.Impersonator) {
+		return claims, ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
 // MiddlewareWithBlacklist middleware kết hợp JWT verification và blacklist check
 func (m *Manager) MiddlewareWithBlacklist(blacklist *Blacklist) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -83,34 +147,44 @@ func (m *Manager) MiddlewareWithBlacklist(blacklist *Blacklist) func(http.Handle
 				return
 			}
 
-			// Kiểm tra blacklist
-			if blacklist.IsBlacklisted(token) {
-				response.Unauthorized(w, lang, response.CodeTokenInvalid)
-				return
+			claims, err := m.VerifyTokenWithBlacklist(r.Context(), token, blacklist)
+			if claims != nil {
+				securitylog.SetUser(r.Context(), claims.UserID)
+			} else if errors.Is(err, ErrExpiredToken) {
+				securitylog.SetUser(r.Context(), m.ExtractUserID(token))
 			}
-
-			// Verify token
-			claims, err := m.VerifyToken(token)
 			if err != nil {
-				if err == ErrExpiredToken {
-					response.Unauthorized(w, lang, response.CodeTokenExpired)
-					return
-				}
-				response.Unauthorized(w, lang, response.CodeTokenInvalid)
-				return
-			}
-
-			// Kiểm tra user có bị blacklist không
-			if blacklist.IsUserBlacklisted(claims.UserID) {
-				response.Unauthorized(w, lang, response.CodeTokenInvalid)
+				writeTokenError(w, lang, err)
 				return
 			}
 
 			// Lưu claims vào context
-			ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
-			ctx = context.WithValue(ctx, UserIDContextKey, claims.UserID)
-
-			next.ServeHTTP(w, r.WithContext(ctx))
+			next.ServeHTTP(w, withClaims(r, claims))
 		})
 	}
 }
+
+// TokenErrorCode response code cho lỗi của VerifyToken/VerifyTokenWithBlacklist
+func TokenErrorCode(err error) string {
+	switch {
+	case errors.Is(err, ErrExpiredToken):
+		return response.CodeTokenExpired
+	case errors.Is(err, ErrTokenRevoked):
+		return response.CodeTokenRevoked
+	// Opaque mode/token_version: Redis lỗi không có nghĩa token sai, client retry thay vì đăng nhập lại
+	case errors.Is(err, ErrTokenStoreUnavailable):
+		return response.CodeServiceUnavailable
+	default:
+		return response.CodeTokenInvalid
+	}
+}
+
+// writeTokenError trả 401 (503 khi không kiểm tra được token) theo TokenErrorCode
+func writeTokenError(w http.ResponseWriter, lang string, err error) {
+	code := TokenErrorCode(err)
+	if code == response.CodeServiceUnavailable {
+		response.ServiceUnavailable(w, lang, code)
+		return
+	}
+	response.Unauthorized(w, lang, code)
+}
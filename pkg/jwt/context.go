@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// PrincipalContextKey là key để lưu principal trong context
+const PrincipalContextKey contextKey = "jwt_principal"
+
+// Principal thông tin user đã xác thực của request, được middleware gắn vào context
+type Principal struct {
+	UserID string
+	Email  string
+	Role   string
+	// Impersonator ID admin đang impersonate (rỗng nếu không phải impersonation token)
+	Impersonator string
+	// Claims claims gốc của token (custom claims, metadata, ...)
+	Claims *Claims
+}
+
+// NewPrincipal tạo principal từ claims đã verify
+func NewPrincipal(claims *Claims) Principal {
+	return Principal{
+		UserID:       claims.UserID,
+		Email:        claims.Email,
+		Role:         claims.Role,
+		Impersonator: claims.Impersonator,
+		Claims:       claims,
+	}
+}
+
+// IsImpersonated request đang dùng impersonation token
+func (p Principal) IsImpersonated() bool {
+	return p.Impersonator != ""
+}
+
+// HasAnyRole principal có một trong các role
+func (p Principal) HasAnyRole(roles ...string) bool {
+	for _, role := range roles {
+		if p.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+// ContextWithClaims gắn principal (và các key cũ ClaimsContextKey, UserIDContextKey) vào context.
+// Dùng cho middleware, worker hoặc test cần chạy code dưới danh nghĩa một user.
+func ContextWithClaims(ctx context.Context, claims *Claims) context.Context {
+	ctx = context.WithValue(ctx, PrincipalContextKey, NewPrincipal(claims))
+	ctx = context.WithValue(ctx, ClaimsContextKey, claims)
+	return context.WithValue(ctx, UserIDContextKey, claims.UserID)
+}
+
+// PrincipalFromContext lấy principal từ context.
+// Context chỉ có claims (gắn trực tiếp bằng ClaimsContextKey) cũng được chuyển thành principal.
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	if principal, ok := ctx.Value(PrincipalContextKey).(Principal); ok {
+		return principal, true
+	}
+	if claims, ok := ctx.Value(ClaimsContextKey).(*Claims); ok && claims != nil {
+		return NewPrincipal(claims), true
+	}
+	return Principal{}, false
+}
+
+// ClaimsFromContext lấy claims của token từ context
+func ClaimsFromContext(ctx context.Context) (*Claims, bool) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.Claims == nil {
+		return nil, false
+	}
+	return principal.Claims, true
+}
+
+// RequireRole middleware chỉ cho phép user có đúng role
+func RequireRole(role string) func(http.Handler) http.Handler {
+	return RequireAnyRole(role)
+}
+
+// RequireAnyRole middleware chỉ cho phép user có một trong các role.
+// Đặt sau Middleware: chưa xác thực trả 401, sai role trả 403.
+func RequireAnyRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lang := i18n.GetLanguageFromContext(r.Context())
+
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok {
+				response.Unauthorized(w, lang, response.CodeTokenMissing)
+				return
+			}
+			if !principal.HasAnyRole(roles...) {
+				response.Forbidden(w, lang, response.CodePermissionDenied)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// GenerateTokenWith tạo access token kèm custom claims có kiểu T (lưu ở claim "custom"),
+// thay cho Metadata map khi service cần claims có cấu trúc:
+//
+//	type TenantClaims struct {
+//		TenantID string   `json:"tenant_id"`
+//		Scopes   []string `json:"scopes"`
+//	}
+//
+//	token, err := jwt.GenerateTokenWith(manager, userID, email, role, TenantClaims{TenantID: "acme"})
+func GenerateTokenWith[T any](m *Manager, userID, email, role string, custom T) (string, error) {
+	return GenerateVersionedTokenWith(m, userID, email, role, 0, custom)
+}
+
+// GenerateVersionedTokenWith như GenerateTokenWith, gắn token_version hiện tại của user
+func GenerateVersionedTokenWith[T any](m *Manager, userID, email, role string, tokenVersion int, custom T) (string, error) {
+	raw, err := json.Marshal(custom)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal custom claims: %w", err)
+	}
+
+	now := m.now()
+	return m.signAccessToken(Claims{
+		UserID:       userID,
+		Email:        email,
+		Role:         role,
+		Custom:       raw,
+		TokenVersion: tokenVersion,
+	}, now, now.Add(m.config.AccessTokenDuration))
+}
+
+// VerifyTokenInto xác thực token như VerifyToken và decode custom claims vào T.
+// Token không có custom claims trả về zero value của T.
+func VerifyTokenInto[T any](m *Manager, tokenString string) (*Claims, T, error) {
+	var custom T
+
+	claims, err := m.VerifyToken(tokenString)
+	if err != nil {
+		return nil, custom, err
+	}
+
+	custom, err = CustomClaims[T](claims)
+	if err != nil {
+		return nil, custom, err
+	}
+	return claims, custom, nil
+}
+
+// CustomClaims decode custom claims của claims đã xác thực vào T
+func CustomClaims[T any](claims *Claims) (T, error) {
+	var custom T
+	if claims == nil || len(claims.Custom) == 0 {
+		return custom, nil
+	}
+	if err := json.Unmarshal(claims.Custom, &custom); err != nil {
+		return custom, fmt.Errorf("%w: %w", ErrInvalidToken, ErrInvalidCustomClaims)
+	}
+	return custom, nil
+}
+
+// GetCustomClaimsFromContext lấy custom claims có kiểu T từ claims middleware đã lưu trong context.
+// ok = false nếu request chưa xác thực hoặc custom claims không khớp kiểu T.
+func GetCustomClaimsFromContext[T any](ctx context.Context) (T, bool) {
+	claims := GetClaimsFromContext(ctx)
+	if claims == nil {
+		var zero T
+		return zero, false
+	}
+
+	custom, err := CustomClaims[T](claims)
+	if err != nil {
+		return custom, false
+	}
+	return custom, true
+}
@@ -0,0 +1,60 @@
+package jwt
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+	"github.com/anhnq996/go-api-core/pkg/securitylog"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// impersonationJob job action event cho các request dùng impersonation token
+const impersonationJob = "impersonation_events"
+
+// IsImpersonated token được cấp cho admin đăng nhập dưới danh nghĩa user
+func (c *Claims) IsImpersonated() bool {
+	return c != nil && c.Impersonator != ""
+}
+
+// GetImpersonatorFromContext lấy ID admin đang impersonate từ context (rỗng nếu không impersonate)
+func GetImpersonatorFromContext(ctx context.Context) string {
+	claims := GetClaimsFromContext(ctx)
+	if claims == nil {
+		return ""
+	}
+	return claims.Impersonator
+}
+
+// withClaims lưu claims vào context của request.
+// Với impersonation token: gắn impersonator vào context (để mọi action event, kể cả CRUD của repository, ghi nhận admin thật)
+// và log request qua actionEvent.
+func withClaims(r *http.Request, claims *Claims) *http.Request {
+	ctx := ContextWithClaims(r.Context(), claims)
+	securitylog.SetUser(ctx, claims.UserID)
+	errorreport.SetUser(ctx, claims.UserID)
+
+	if claims.IsImpersonated() {
+		ctx = actionEvent.WithImpersonator(ctx, claims.Impersonator)
+		actionEvent.LogEventAsync(context.WithoutCancel(ctx), actionEvent.Event{
+			Action:         "impersonated_request",
+			Entity:         "user",
+			EntityID:       claims.UserID,
+			UserID:         claims.UserID,
+			ImpersonatorID: claims.Impersonator,
+			Data: actionEvent.EventData{
+				New: map[string]interface{}{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				},
+			},
+			Timestamp: utils.Now(),
+			IP:        utils.GetClientIP(r),
+			UserAgent: r.UserAgent(),
+			Job:       impersonationJob,
+		})
+	}
+
+	return r.WithContext(ctx)
+}
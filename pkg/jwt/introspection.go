@@ -0,0 +1,218 @@
+package jwt
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Giá trị token_type_hint (RFC 7009, RFC 7662)
+const (
+	TokenTypeHintAccessToken  = "access_token"
+	TokenTypeHintRefreshToken = "refresh_token"
+)
+
+// IntrospectionResponse response của introspection endpoint (RFC 7662 section 2.2).
+// Token không hợp lệ, hết hạn hoặc đã thu hồi chỉ trả {"active": false}.
+type IntrospectionResponse struct {
+	Active    bool     `json:"active"`
+	Scope     string   `json:"scope,omitempty"`
+	Username  string   `json:"username,omitempty"`
+	TokenType string   `json:"token_type,omitempty"`
+	Exp       int64    `json:"exp,omitempty"`
+	Iat       int64    `json:"iat,omitempty"`
+	Nbf       int64    `json:"nbf,omitempty"`
+	Sub       string   `json:"sub,omitempty"`
+	Aud       []string `json:"aud,omitempty"`
+	Iss       string   `json:"iss,omitempty"`
+	// Mở rộng: role và admin đang impersonate (access token)
+	Role         string `json:"role,omitempty"`
+	Impersonator string `json:"impersonator,omitempty"`
+}
+
+// Introspector phục vụ token introspection cho resource server khác (opaque token hoặc JWT),
+// kiểm tra cả blacklist và token_version nên kết quả phản ánh thu hồi ngay lập tức
+type Introspector struct {
+	manager   *Manager
+	blacklist *Blacklist        // nil: không kiểm tra blacklist/token_version
+	clients   map[string]string // client_id -> client_secret được gọi endpoint
+}
+
+// NewIntrospector tạo introspector, clients là danh sách client_id -> client_secret (HTTP Basic)
+func NewIntrospector(manager *Manager, blacklist *Blacklist, clients map[string]string) *Introspector {
+	return &Introspector{
+		manager:   manager,
+		blacklist: blacklist,
+		clients:   clients,
+	}
+}
+
+// ParseIntrospectionClients parse cấu hình "client_id:secret,client_id2:secret2"
+func ParseIntrospectionClients(value string) (map[string]string, error) {
+	clients := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("invalid introspection client %q: expected client_id:secret", pair)
+		}
+		clients[id] = secret
+	}
+	return clients, nil
+}
+
+// Introspect trạng thái token, hint chọn loại token thử trước (token_type_hint)
+func (i *Introspector) Introspect(ctx context.Context, token, hint string) (IntrospectionResponse, error) {
+	if hint == TokenTypeHintRefreshToken {
+		if resp, ok, err := i.introspectRefresh(ctx, token); ok || err != nil {
+			return resp, err
+		}
+		return i.introspectAccess(ctx, token)
+	}
+
+	if resp, err := i.introspectAccess(ctx, token); resp.Active || err != nil {
+		return resp, err
+	}
+	resp, _, err := i.introspectRefresh(ctx, token)
+	return resp, err
+}
+
+func (i *Introspector) introspectAccess(ctx context.Context, token string) (IntrospectionResponse, error) {
+	inactive := IntrospectionResponse{}
+
+	claims, err := i.manager.VerifyToken(token)
+	if errors.Is(err, ErrTokenStoreUnavailable) {
+		return inactive, err
+	}
+	// JWT refresh token cũng parse được thành Claims nhưng không có user_id
+	if err != nil || claims.UserID == "" {
+		return inactive, nil
+	}
+
+	if i.blacklist != nil {
+		if i.blacklist.IsBlacklisted(token) || i.blacklist.IsUserBlacklisted(claims.UserID) {
+			return inactive, nil
+		}
+		if claims.IsImpersonated() && i.blacklist.IsUserBlacklisted(claims.Impersonator) {
+			return inactive, nil
+		}
+		if i.blacklist.versions != nil {
+			stale, err := i.blacklist.versions.IsStale(ctx, claims)
+			if err != nil || stale {
+				return inactive, err
+			}
+		}
+	}
+
+	resp := registeredClaimsResponse(&claims.RegisteredClaims)
+	resp.TokenType = "Bearer"
+	resp.Username = claims.Email
+	resp.Role = claims.Role
+	resp.Impersonator = claims.Impersonator
+	if permissions, ok := PermissionsFromClaims(claims); ok {
+		resp.Scope = strings.Join(permissions, " ")
+	}
+	return resp, nil
+}
+
+// introspectRefresh ok = true nếu token là refresh token hợp lệ về chữ ký/hạn (có thể đã bị thu hồi)
+func (i *Introspector) introspectRefresh(ctx context.Context, token string) (IntrospectionResponse, bool, error) {
+	inactive := IntrospectionResponse{}
+
+	claims, err := i.manager.VerifyRefreshTokenClaims(token)
+	if errors.Is(err, ErrTokenStoreUnavailable) {
+		return inactive, false, err
+	}
+	if err != nil {
+		return inactive, false, nil
+	}
+
+	if i.blacklist != nil && i.blacklist.versions != nil {
+		stale, err := i.blacklist.versions.isStale(ctx, claims.Subject, claims.TokenVersion)
+		if err != nil || stale {
+			return inactive, true, err
+		}
+	}
+
+	resp := registeredClaimsResponse(&claims.RegisteredClaims)
+	resp.TokenType = TokenTypeHintRefreshToken
+	return resp, true, nil
+}
+
+func registeredClaimsResponse(claims *jwt.RegisteredClaims) IntrospectionResponse {
+	resp := IntrospectionResponse{
+		Active: true,
+		Sub:    claims.Subject,
+		Aud:    claims.Audience,
+		Iss:    claims.Issuer,
+	}
+	if claims.ExpiresAt != nil {
+		resp.Exp = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		resp.Iat = claims.IssuedAt.Unix()
+	}
+	if claims.NotBefore != nil {
+		resp.Nbf = claims.NotBefore.Unix()
+	}
+	return resp
+}
+
+// authenticate kiểm tra client credentials (HTTP Basic hoặc client_id/client_secret trong form)
+func (i *Introspector) authenticate(r *http.Request) bool {
+	id, secret, ok := r.BasicAuth()
+	if !ok {
+		id, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	expected, found := i.clients[id]
+	if !found || secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) == 1
+}
+
+// Handler phục vụ POST /oauth/introspect (RFC 7662), response theo chuẩn OAuth thay vì response envelope của API
+func (i *Introspector) Handler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "no-store")
+
+	if err := r.ParseForm(); err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	if !i.authenticate(r) {
+		w.Header().Set("WWW-Authenticate", `Basic realm="introspection"`)
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+
+	token := r.PostForm.Get("token")
+	if token == "" {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+
+	resp, err := i.Introspect(r.Context(), token, r.PostForm.Get("token_type_hint"))
+	if err != nil {
+		// Không xác định được trạng thái token: không trả active=false vì resource server có thể cache kết quả
+		writeOAuthError(w, http.StatusServiceUnavailable, "temporarily_unavailable")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func writeOAuthError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
@@ -1,25 +1,42 @@
 package jwt
 
 import (
-	"crypto/rsa"
+	"context"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"regexp"
+	"sync"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/clock"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
 // Config cấu hình cho JWT
 type Config struct {
-	SecretKey            string        // Secret key để sign token
-	PrivateKeyPath       string        // Đường dẫn private key (PEM) cho RS256
-	PublicKeyPath        string        // Đường dẫn public key (PEM) cho RS256
-	AccessTokenDuration  time.Duration // Thời gian hết hạn access token (default: 15 phút)
-	RefreshTokenDuration time.Duration // Thời gian hết hạn refresh token (default: 7 ngày)
-	Issuer               string        // Issuer của token (default: "apicore")
+	SecretKey                  string         // Secret key để sign token
+	Algorithm                  string         // Thuật toán ký: HS256, RS256, ES256, EdDSA (rỗng = theo loại key, HS256 = bỏ qua keys)
+	PrivateKeyPath             string         // Đường dẫn private key (PEM) RSA, EC hoặc Ed25519
+	PublicKeyPath              string         // Đường dẫn public key (PEM)
+	KeysDir                    string         // Thư mục keys cho key rotation (xem LoadKeyDir), ưu tiên hơn PrivateKeyPath
+	KeyReloadInterval          time.Duration  // Chu kỳ đọc lại KeysDir để nhận key mới (default: 1 phút)
+	AccessTokenDuration        time.Duration  // Thời gian hết hạn access token (default: 15 phút)
+	RefreshTokenDuration       time.Duration  // Thời gian hết hạn refresh token (default: 7 ngày)
+	ImpersonationTokenDuration time.Duration  // Thời gian hết hạn impersonation token (default: 15 phút)
+	Issuer                     string         // Issuer của token (default: "apicore")
+	Audience                   []string       // aud gắn vào token khi ký (rỗng = không có aud)
+	AcceptedAudiences          []string       // Verify yêu cầu aud chứa ít nhất 1 giá trị (rỗng = không kiểm tra)
+	AcceptedIssuers            []string       // Verify yêu cầu iss thuộc danh sách (rỗng = không kiểm tra)
+	SubjectPattern             *regexp.Regexp // Verify yêu cầu sub khớp toàn bộ pattern (nil = không kiểm tra), xem ParseSubjectFormat
+	OpaqueStore                *OpaqueStore   // Khác nil: cấp opaque token lưu trong Redis thay cho JWT, JWT cấp trước đó vẫn verify được
+	Leeway                     time.Duration  // Sai lệch đồng hồ cho phép khi verify exp/nbf/iat (default: 0)
+	Clock                      clock.Clock    // Nguồn thời gian cho iat/exp và verify (default: clock.Default())
 }
 
 // Claims chứa thông tin trong JWT token
@@ -28,9 +45,32 @@ type Claims struct {
 	Email    string                 `json:"email"`
 	Role     string                 `json:"role"`
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	// Custom claims có kiểu do service định nghĩa (xem GenerateTokenWith, VerifyTokenInto)
+	Custom json.RawMessage `json:"custom,omitempty"`
+	// TokenVersion token_version của user lúc cấp token, token có version cũ hơn bị từ chối (xem TokenVersions)
+	TokenVersion int `json:"token_version,omitempty"`
+	// Impersonator ID của admin đang đăng nhập dưới danh nghĩa user (rỗng nếu không phải impersonation token)
+	Impersonator string `json:"impersonator,omitempty"`
+	// ImpersonatorTokenVersion token_version của admin, admin logout all thì impersonation token cũng bị thu hồi
+	ImpersonatorTokenVersion int `json:"impersonator_token_version,omitempty"`
+	// Type loại token ("access"), chặn dùng access token thay refresh token và ngược lại
+	Type string `json:"typ,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// RefreshClaims claims của refresh token
+type RefreshClaims struct {
+	TokenVersion int `json:"token_version,omitempty"`
+	// Type loại token, phải là "refresh"
+	Type string `json:"typ,omitempty"`
+	// Impersonator chỉ có trong impersonation access token, refresh token mang claim này bị từ chối
+	Impersonator string `json:"impersonator,omitempty"`
 	jwt.RegisteredClaims
 }
 
+func (c *Claims) tokenType() string        { return c.Type }
+func (c *RefreshClaims) tokenType() string { return c.Type }
+
 // TokenPair chứa cả access token và refresh token
 type TokenPair struct {
 	AccessToken  string    `json:"access_token"`
@@ -42,18 +82,50 @@ type TokenPair struct {
 // Manager quản lý JWT tokens
 type Manager struct {
 	config Config
-	// Hỗ trợ cả HMAC và RSA; ưu tiên RSA nếu có khóa
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	// Hỗ trợ HMAC và key bất đối xứng (RSA/ECDSA/Ed25519); ưu tiên key nếu có. nil = HMAC
+	keys *KeySet
+	// legacy key từ PrivateKeyPath, verify token không có kid header
+	legacy *SigningKey
+
+	mu           sync.RWMutex
+	keysLoadedAt time.Time
 }
 
+// unknownKidReloadInterval khoảng cách tối thiểu giữa 2 lần đọc lại KeysDir khi gặp kid chưa biết
+const unknownKidReloadInterval = 5 * time.Second
+
 var (
 	ErrInvalidToken     = errors.New("invalid token")
 	ErrExpiredToken     = errors.New("token has expired")
 	ErrInvalidSignature = errors.New("invalid token signature")
 	ErrTokenNotFound    = errors.New("token not found")
+	// Lỗi claim được bọc cùng ErrInvalidToken: errors.Is(err, ErrInvalidToken) vẫn đúng
+	ErrInvalidAudience     = errors.New("token audience not accepted")
+	ErrInvalidIssuer       = errors.New("token issuer not accepted")
+	ErrInvalidSubject      = errors.New("token subject has invalid format")
+	ErrInvalidCustomClaims = errors.New("token custom claims do not match the expected type")
+	ErrInvalidTokenType    = errors.New("token type not accepted")
 )
 
+// SubjectUUID pattern cho sub là UUID (user ID của hệ thống)
+var SubjectUUID = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ParseSubjectFormat chuyển cấu hình subject format thành pattern: rỗng = không kiểm tra, "uuid" = SubjectUUID,
+// còn lại là regular expression phải khớp toàn bộ sub
+func ParseSubjectFormat(format string) (*regexp.Regexp, error) {
+	switch format {
+	case "":
+		return nil, nil
+	case "uuid":
+		return SubjectUUID, nil
+	}
+	pattern, err := regexp.Compile(`^(?:` + format + `)$`)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subject format: %w", err)
+	}
+	return pattern, nil
+}
+
 // NewManager tạo JWT manager mới
 func NewManager(config Config) *Manager {
 	// Set defaults
@@ -63,148 +135,370 @@ func NewManager(config Config) *Manager {
 	if config.RefreshTokenDuration == 0 {
 		config.RefreshTokenDuration = 7 * 24 * time.Hour
 	}
+	if config.ImpersonationTokenDuration == 0 {
+		config.ImpersonationTokenDuration = 15 * time.Minute
+	}
 	if config.Issuer == "" {
 		config.Issuer = "apicore"
 	}
+	if config.KeyReloadInterval == 0 {
+		config.KeyReloadInterval = time.Minute
+	}
 
 	m := &Manager{config: config}
 
-	// Ưu tiên load RSA nếu có cung cấp đường dẫn khóa
+	switch config.Algorithm {
+	case "", AlgRS256, AlgES256, AlgES384, AlgES512, AlgEdDSA:
+	case AlgHS256:
+		// Chọn HMAC tường minh, không load keys
+		m.config.KeysDir = ""
+		return m
+	default:
+		fmt.Printf("[JWT] Warning: Thuật toán %s không được hỗ trợ, dùng thuật toán theo loại key.\n", config.Algorithm)
+		m.config.Algorithm = ""
+	}
+
+	// Ưu tiên load key bất đối xứng nếu có cung cấp đường dẫn khóa
 	if config.PrivateKeyPath != "" && config.PublicKeyPath != "" {
-		if priv, pub, err := loadRSAKeys(config.PrivateKeyPath, config.PublicKeyPath); err == nil {
-			m.privateKey = priv
-			m.publicKey = pub
-		} else {
+		if key, err := loadKeyPair(config.PrivateKeyPath, config.PublicKeyPath); err == nil {
+			m.legacy = key
+			m.keys = NewKeySet(m.legacy)
+			m.keys.fallback = m.legacy
+		} else if config.KeysDir == "" {
 			// Fallback: giữ nguyên HMAC nếu có SecretKey; nếu không, vẫn để nil và sẽ báo lỗi khi dùng
-			fmt.Printf("[JWT] Warning: Không thể load RSA keys (%v). Đang fallback sang HMAC nếu có SecretKey.\n", err)
+			fmt.Printf("[JWT] Warning: Không thể load keys (%v). Đang fallback sang HMAC nếu có SecretKey.\n", err)
 		}
 	}
 
+	if config.KeysDir != "" {
+		if err := m.reloadKeys(); err != nil {
+			fmt.Printf("[JWT] Warning: Không thể load keys từ %s (%v).\n", config.KeysDir, err)
+		}
+	}
+
+	if m.keys != nil && m.config.Algorithm != "" && m.keys.Active().Algorithm != m.config.Algorithm {
+		// Vẫn ký bằng active key: token sai thuật toán tốt hơn không cấp được token
+		fmt.Printf("[JWT] Warning: Active key %s dùng %s, khác JWT algorithm %s. Hãy rotate key với thuật toán đúng.\n",
+			m.keys.Active().ID, m.keys.Active().Algorithm, m.config.Algorithm)
+	}
+
 	return m
 }
 
-// loadRSAKeys đọc và parse khóa RSA từ file PEM
-func loadRSAKeys(privateKeyPath, publicKeyPath string) (*rsa.PrivateKey, *rsa.PublicKey, error) {
-	privPemBytes, err := os.ReadFile(privateKeyPath)
+// reloadKeys đọc lại KeysDir, legacy key (nếu có) vẫn verify được token cũ không có kid
+func (m *Manager) reloadKeys() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.keysLoadedAt = m.now()
+
+	ks, err := LoadKeyDir(m.config.KeysDir)
 	if err != nil {
-		return nil, nil, fmt.Errorf("read private key: %w", err)
+		return err
 	}
-	pubPemBytes, err := os.ReadFile(publicKeyPath)
-	if err != nil {
-		return nil, nil, fmt.Errorf("read public key: %w", err)
+	if m.legacy != nil {
+		if _, ok := ks.keys[m.legacy.ID]; !ok {
+			ks.keys[m.legacy.ID] = m.legacy
+		}
+		ks.fallback = m.legacy
 	}
+	m.keys = ks
+	return nil
+}
 
-	// Parse private key
-	privBlock, _ := pem.Decode(privPemBytes)
-	if privBlock == nil {
-		return nil, nil, errors.New("invalid private key PEM")
+// keySet keys hiện tại, đọc lại KeysDir sau mỗi KeyReloadInterval để các instance nhận key đã rotate
+func (m *Manager) keySet() *KeySet {
+	return m.keySetWithin(m.config.KeyReloadInterval)
+}
+
+// keySetWithin đọc lại KeysDir nếu lần đọc trước cũ hơn maxAge
+func (m *Manager) keySetWithin(maxAge time.Duration) *KeySet {
+	m.mu.RLock()
+	ks, loadedAt := m.keys, m.keysLoadedAt
+	m.mu.RUnlock()
+
+	if m.config.KeysDir == "" || m.now().Sub(loadedAt) < maxAge {
+		return ks
 	}
-	var privKey *rsa.PrivateKey
-	switch privBlock.Type {
-	case "RSA PRIVATE KEY":
-		k, err := x509.ParsePKCS1PrivateKey(privBlock.Bytes)
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse PKCS1 private key: %w", err)
-		}
-		privKey = k
-	case "PRIVATE KEY":
-		key, err := x509.ParsePKCS8PrivateKey(privBlock.Bytes)
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse PKCS8 private key: %w", err)
+	if err := m.reloadKeys(); err != nil {
+		// Giữ keys cũ, thư mục keys đang được ghi hoặc tạm thời không đọc được
+		fmt.Printf("[JWT] Warning: Không thể reload keys từ %s (%v).\n", m.config.KeysDir, err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.keys
+}
+
+// JWKS public keys để client/service khác verify token (rỗng khi dùng HMAC)
+func (m *Manager) JWKS() JWKS {
+	if ks := m.keySet(); ks != nil {
+		return ks.JWKS()
+	}
+	return JWKS{Keys: []JWK{}}
+}
+
+// JWKSHandler phục vụ /.well-known/jwks.json
+func (m *Manager) JWKSHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	// Verifier tải lại JWKS khi gặp kid mới nên chỉ cần cache ngắn
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	_ = json.NewEncoder(w).Encode(m.JWKS())
+}
+
+// sign ký claims bằng active key (kèm kid header) hoặc HMAC
+func (m *Manager) sign(claims jwt.Claims) (string, error) {
+	if ks := m.keySet(); ks != nil {
+		key := ks.Active()
+		token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+		token.Header["kid"] = key.ID
+		return token.SignedString(key.PrivateKey)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(m.config.SecretKey))
+}
+
+// keyFunc chọn key verify theo signing method và kid header
+func (m *Manager) keyFunc(token *jwt.Token) (interface{}, error) {
+	ks := m.keySet()
+	if ks == nil {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidSignature
 		}
-		rk, ok := key.(*rsa.PrivateKey)
-		if !ok {
-			return nil, nil, errors.New("private key is not RSA")
+		return []byte(m.config.SecretKey), nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.Lookup(kid)
+	if !ok && kid != "" {
+		// Instance khác đã rotate sang key mới: đọc lại KeysDir ngay, giới hạn để kid giả không gây đọc file liên tục
+		if ks = m.keySetWithin(unknownKidReloadInterval); ks != nil {
+			key, ok = ks.Lookup(kid)
 		}
-		privKey = rk
-	default:
-		return nil, nil, fmt.Errorf("unsupported private key type: %s", privBlock.Type)
+	}
+	// alg header phải khớp loại key, chặn token giả dùng thuật toán khác với cùng kid
+	if !ok || token.Method.Alg() != key.Algorithm {
+		return nil, ErrInvalidSignature
+	}
+	return key.PublicKey(), nil
+}
+
+// loadKeyPair đọc private key và kiểm tra public key PEM (legacy PrivateKeyPath/PublicKeyPath)
+func loadKeyPair(privateKeyPath, publicKeyPath string) (*SigningKey, error) {
+	privPemBytes, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+	pubPemBytes, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read public key: %w", err)
+	}
+
+	privKey, err := parsePrivateKey(privPemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parse private key: %w", err)
 	}
 
 	// Parse public key
 	pubBlock, _ := pem.Decode(pubPemBytes)
 	if pubBlock == nil {
-		return nil, nil, errors.New("invalid public key PEM")
+		return nil, errors.New("invalid public key PEM")
 	}
-	var pubKey *rsa.PublicKey
-	if pubBlock.Type == "PUBLIC KEY" {
-		iface, err := x509.ParsePKIXPublicKey(pubBlock.Bytes)
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse PKIX public key: %w", err)
-		}
-		rk, ok := iface.(*rsa.PublicKey)
-		if !ok {
-			return nil, nil, errors.New("public key is not RSA")
+	switch pubBlock.Type {
+	case "PUBLIC KEY":
+		if _, err := x509.ParsePKIXPublicKey(pubBlock.Bytes); err != nil {
+			return nil, fmt.Errorf("parse PKIX public key: %w", err)
 		}
-		pubKey = rk
-	} else if pubBlock.Type == "RSA PUBLIC KEY" {
-		rk, err := x509.ParsePKCS1PublicKey(pubBlock.Bytes)
-		if err != nil {
-			return nil, nil, fmt.Errorf("parse PKCS1 public key: %w", err)
+	case "RSA PUBLIC KEY":
+		if _, err := x509.ParsePKCS1PublicKey(pubBlock.Bytes); err != nil {
+			return nil, fmt.Errorf("parse PKCS1 public key: %w", err)
 		}
-		pubKey = rk
-	} else {
-		return nil, nil, fmt.Errorf("unsupported public key type: %s", pubBlock.Type)
+	default:
+		return nil, fmt.Errorf("unsupported public key type: %s", pubBlock.Type)
 	}
 
-	return privKey, pubKey, nil
+	return NewSigningKey(privKey)
+}
+
+// parserOptions options verify thời gian: clock cấu hình, leeway cho exp/nbf và kiểm tra iat không ở tương lai
+func (m *Manager) parserOptions() []jwt.ParserOption {
+	return []jwt.ParserOption{
+		jwt.WithTimeFunc(m.now),
+		jwt.WithLeeway(m.config.Leeway),
+		jwt.WithIssuedAt(),
+	}
+}
+
+// MaxAccessTokenLifetime thời gian tối đa một access token (kể cả impersonation token) còn được chấp nhận kể từ lúc cấp
+func (m *Manager) MaxAccessTokenLifetime() time.Duration {
+	lifetime := m.config.AccessTokenDuration
+	if m.config.ImpersonationTokenDuration > lifetime {
+		lifetime = m.config.ImpersonationTokenDuration
+	}
+	return lifetime + m.config.Leeway
+}
+
+// now thời gian hiện tại theo clock cấu hình
+func (m *Manager) now() time.Time {
+	if m.config.Clock != nil {
+		return m.config.Clock.Now()
+	}
+	return clock.Now()
 }
 
 // GenerateToken tạo access token
 func (m *Manager) GenerateToken(userID, email, role string, metadata map[string]interface{}) (string, error) {
-	now := time.Now()
-	expiresAt := now.Add(m.config.AccessTokenDuration)
-
-	claims := Claims{
-		UserID:   userID,
-		Email:    email,
-		Role:     role,
-		Metadata: metadata,
+	return m.generateToken(userID, email, role, 0, metadata)
+}
+
+func (m *Manager) generateToken(userID, email, role string, tokenVersion int, metadata map[string]interface{}) (string, error) {
+	now := m.now()
+
+	return m.signAccessToken(Claims{
+		UserID:       userID,
+		Email:        email,
+		Role:         role,
+		Metadata:     metadata,
+		TokenVersion: tokenVersion,
+	}, now, now.Add(m.config.AccessTokenDuration))
+}
+
+// GenerateImpersonationToken tạo access token ngắn hạn cho admin (claims của impersonator) đăng nhập dưới danh nghĩa user.
+// Không có refresh token đi kèm, hết hạn thì admin phải impersonate lại.
+func (m *Manager) GenerateImpersonationToken(userID, email, role string, tokenVersion int, impersonator *Claims, metadata map[string]interface{}) (string, time.Time, error) {
+	now := m.now()
+	expiresAt := now.Add(m.config.ImpersonationTokenDuration)
+
+	token, err := m.signAccessToken(Claims{
+		UserID:                   userID,
+		Email:                    email,
+		Role:                     role,
+		Metadata:                 metadata,
+		TokenVersion:             tokenVersion,
+		Impersonator:             impersonator.UserID,
+		ImpersonatorTokenVersion: impersonator.TokenVersion,
+	}, now, expiresAt)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, expiresAt, nil
+}
+
+// signAccessToken gắn registered claims và ký access token
+func (m *Manager) signAccessToken(claims Claims, now, expiresAt time.Time) (string, error) {
+	claims.Type = tokenTypeAccess
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		Issuer:    m.config.Issuer,
+		Subject:   claims.UserID,
+		Audience:  m.config.Audience,
+	}
+
+	return m.issue(tokenTypeAccess, claims, now, expiresAt)
+}
+
+// GenerateRefreshToken tạo refresh token
+func (m *Manager) GenerateRefreshToken(userID string) (string, error) {
+	return m.generateRefreshToken(userID, 0)
+}
+
+func (m *Manager) generateRefreshToken(userID string, tokenVersion int) (string, error) {
+	now := m.now()
+	expiresAt := now.Add(m.config.RefreshTokenDuration)
+
+	claims := RefreshClaims{
+		TokenVersion: tokenVersion,
+		Type:         tokenTypeRefresh,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
-			NotBefore: jwt.NewNumericDate(now),
 			Issuer:    m.config.Issuer,
 			Subject:   userID,
+			Audience:  m.config.Audience,
 		},
 	}
 
-	if m.privateKey != nil {
-		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-		return token.SignedString(m.privateKey)
+	return m.issue(tokenTypeRefresh, claims, now, expiresAt)
+}
+
+// issue ký JWT, hoặc cấp opaque token khi cấu hình OpaqueStore
+func (m *Manager) issue(typ string, claims jwt.Claims, now, expiresAt time.Time) (string, error) {
+	if m.config.OpaqueStore != nil {
+		return m.config.OpaqueStore.issue(context.Background(), typ, claims, expiresAt.Sub(now))
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.config.SecretKey))
+	return m.sign(claims)
 }
 
-// GenerateRefreshToken tạo refresh token
-func (m *Manager) GenerateRefreshToken(userID string) (string, error) {
-	now := time.Now()
-	expiresAt := now.Add(m.config.RefreshTokenDuration)
+// IsOpaque token là opaque token do OpaqueStore cấp (Manager đang ở opaque mode)
+func (m *Manager) IsOpaque(tokenString string) bool {
+	return m.config.OpaqueStore != nil && IsOpaqueToken(tokenString)
+}
 
-	claims := jwt.RegisteredClaims{
-		ExpiresAt: jwt.NewNumericDate(expiresAt),
-		IssuedAt:  jwt.NewNumericDate(now),
-		Issuer:    m.config.Issuer,
-		Subject:   userID,
+// RevokeOpaqueToken thu hồi opaque token ngay lập tức, JWT thu hồi bằng Blacklist
+func (m *Manager) RevokeOpaqueToken(tokenString string) error {
+	if !m.IsOpaque(tokenString) {
+		return ErrInvalidToken
 	}
+	return m.config.OpaqueStore.Revoke(context.Background(), tokenString)
+}
 
-	if m.privateKey != nil {
-		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-		return token.SignedString(m.privateKey)
+// parse xác thực token (opaque hoặc JWT) và đọc claims, typ là loại token yêu cầu (rỗng = không kiểm tra)
+func (m *Manager) parse(tokenString, typ string, claims jwt.Claims) error {
+	if m.IsOpaque(tokenString) {
+		if err := m.config.OpaqueStore.load(context.Background(), tokenString, typ, claims); err != nil {
+			return err
+		}
+		exp, err := claims.GetExpirationTime()
+		if err != nil || exp == nil || !m.now().Before(exp.Time.Add(m.config.Leeway)) {
+			return ErrExpiredToken
+		}
+		return nil
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, m.keyFunc, m.parserOptions()...)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return ErrExpiredToken
+		}
+		return ErrInvalidToken
+	}
+	if !token.Valid {
+		return ErrInvalidToken
+	}
+	if typed, ok := claims.(interface{ tokenType() string }); ok && !tokenTypeAccepted(typed.tokenType(), typ) {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, ErrInvalidTokenType)
+	}
+	return nil
+}
+
+// tokenTypeAccepted kiểm tra claim typ của JWT. Access token cấp trước khi có claim typ (rỗng) vẫn được chấp nhận
+// cho tới khi hết hạn; refresh token bắt buộc có typ "refresh"
+func tokenTypeAccepted(actual, required string) bool {
+	switch {
+	case required == "" || actual == required:
+		return true
+	case required == tokenTypeAccess:
+		return actual == ""
+	default:
+		return false
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(m.config.SecretKey))
 }
 
 // GenerateTokenPair tạo cả access token và refresh token
 func (m *Manager) GenerateTokenPair(userID, email, role string, metadata map[string]interface{}) (*TokenPair, error) {
-	accessToken, err := m.GenerateToken(userID, email, role, metadata)
+	return m.GenerateVersionedTokenPair(userID, email, role, 0, metadata)
+}
+
+// GenerateVersionedTokenPair tạo token pair gắn token_version hiện tại của user
+func (m *Manager) GenerateVersionedTokenPair(userID, email, role string, tokenVersion int, metadata map[string]interface{}) (*TokenPair, error) {
+	accessToken, err := m.generateToken(userID, email, role, tokenVersion, metadata)
 	if err != nil {
 		return nil, err
 	}
 
-	refreshToken, err := m.GenerateRefreshToken(userID)
+	refreshToken, err := m.generateRefreshToken(userID, tokenVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -212,41 +506,19 @@ func (m *Manager) GenerateTokenPair(userID, email, role string, metadata map[str
 	return &TokenPair{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
-		ExpiresAt:    time.Now().Add(m.config.AccessTokenDuration),
+		ExpiresAt:    m.now().Add(m.config.AccessTokenDuration),
 		TokenType:    "Bearer",
 	}, nil
 }
 
 // VerifyToken xác thực và parse token
 func (m *Manager) VerifyToken(tokenString string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		// Kiểm tra signing method theo cấu hình
-		if m.publicKey != nil {
-			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, ErrInvalidSignature
-			}
-			return m.publicKey, nil
-		}
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidSignature
-		}
-		return []byte(m.config.SecretKey), nil
-	})
-
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return nil, ErrExpiredToken
-		}
-		return nil, ErrInvalidToken
-	}
-
-	if !token.Valid {
-		return nil, ErrInvalidToken
+	claims := &Claims{}
+	if err := m.parse(tokenString, tokenTypeAccess, claims); err != nil {
+		return nil, err
 	}
-
-	claims, ok := token.Claims.(*Claims)
-	if !ok {
-		return nil, ErrInvalidToken
+	if err := m.validateRegisteredClaims(&claims.RegisteredClaims); err != nil {
+		return nil, err
 	}
 
 	return claims, nil
@@ -254,58 +526,67 @@ func (m *Manager) VerifyToken(tokenString string) (*Claims, error) {
 
 // VerifyRefreshToken xác thực refresh token
 func (m *Manager) VerifyRefreshToken(tokenString string) (string, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &jwt.RegisteredClaims{}, func(token *jwt.Token) (interface{}, error) {
-		if m.publicKey != nil {
-			if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
-				return nil, ErrInvalidSignature
-			}
-			return m.publicKey, nil
-		}
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, ErrInvalidSignature
-		}
-		return []byte(m.config.SecretKey), nil
-	})
-
+	claims, err := m.VerifyRefreshTokenClaims(tokenString)
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			return "", ErrExpiredToken
-		}
-		return "", ErrInvalidToken
+		return "", err
 	}
+	return claims.Subject, nil
+}
 
-	if !token.Valid {
-		return "", ErrInvalidToken
+// VerifyRefreshTokenClaims xác thực refresh token và trả về claims (kèm token_version)
+func (m *Manager) VerifyRefreshTokenClaims(tokenString string) (*RefreshClaims, error) {
+	claims := &RefreshClaims{}
+	if err := m.parse(tokenString, tokenTypeRefresh, claims); err != nil {
+		return nil, err
 	}
-
-	claims, ok := token.Claims.(*jwt.RegisteredClaims)
-	if !ok {
-		return "", ErrInvalidToken
+	// Impersonation token không được đổi thành phiên dài hạn của user
+	if claims.Impersonator != "" {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, ErrInvalidTokenType)
+	}
+	if err := m.validateRegisteredClaims(&claims.RegisteredClaims); err != nil {
+		return nil, err
 	}
 
-	return claims.Subject, nil
+	return claims, nil
 }
 
-// RefreshAccessToken tạo access token mới từ refresh token
-func (m *Manager) RefreshAccessToken(refreshToken, email, role string, metadata map[string]interface{}) (*TokenPair, error) {
-	// Verify refresh token
-	userID, err := m.VerifyRefreshToken(refreshToken)
-	if err != nil {
-		return nil, err
+// validateRegisteredClaims kiểm tra aud, iss, sub theo cấu hình, chặn token cấp cho service khác dù cùng key
+func (m *Manager) validateRegisteredClaims(claims *jwt.RegisteredClaims) error {
+	if len(m.config.AcceptedAudiences) > 0 && !containsAny(claims.Audience, m.config.AcceptedAudiences) {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, ErrInvalidAudience)
+	}
+	if len(m.config.AcceptedIssuers) > 0 && !containsAny([]string{claims.Issuer}, m.config.AcceptedIssuers) {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, ErrInvalidIssuer)
 	}
+	if m.config.SubjectPattern != nil && !m.config.SubjectPattern.MatchString(claims.Subject) {
+		return fmt.Errorf("%w: %w", ErrInvalidToken, ErrInvalidSubject)
+	}
+	return nil
+}
 
-	// Generate new token pair
-	return m.GenerateTokenPair(userID, email, role, metadata)
+// containsAny true nếu values có ít nhất 1 phần tử thuộc accepted
+func containsAny(values, accepted []string) bool {
+	for _, v := range values {
+		for _, a := range accepted {
+			if v == a {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // ExtractUserID extract user ID từ token mà không verify (dùng cho logging)
 func (m *Manager) ExtractUserID(tokenString string) string {
-	token, _ := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if m.publicKey != nil {
-			return m.publicKey, nil
+	if m.IsOpaque(tokenString) {
+		claims := &Claims{}
+		if err := m.config.OpaqueStore.load(context.Background(), tokenString, tokenTypeAccess, claims); err != nil {
+			return ""
 		}
-		return []byte(m.config.SecretKey), nil
-	})
+		return claims.UserID
+	}
+
+	token, _ := jwt.ParseWithClaims(tokenString, &Claims{}, m.keyFunc, m.parserOptions()...)
 
 	if claims, ok := token.Claims.(*Claims); ok {
 		return claims.UserID
@@ -316,12 +597,15 @@ func (m *Manager) ExtractUserID(tokenString string) string {
 
 // GetTokenExpiry lấy thời gian hết hạn của token
 func (m *Manager) GetTokenExpiry(tokenString string) (time.Time, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if m.publicKey != nil {
-			return m.publicKey, nil
+	if m.IsOpaque(tokenString) {
+		claims := &Claims{}
+		if err := m.parse(tokenString, "", claims); err != nil {
+			return time.Time{}, err
 		}
-		return []byte(m.config.SecretKey), nil
-	})
+		return claims.ExpiresAt.Time, nil
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, m.keyFunc, m.parserOptions()...)
 
 	if err != nil {
 		return time.Time{}, err
@@ -334,11 +618,11 @@ func (m *Manager) GetTokenExpiry(tokenString string) (time.Time, error) {
 	return time.Time{}, ErrInvalidToken
 }
 
-// IsTokenExpired kiểm tra token đã hết hạn chưa
+// IsTokenExpired kiểm tra token đã hết hạn chưa (tính cả leeway)
 func (m *Manager) IsTokenExpired(tokenString string) bool {
 	expiry, err := m.GetTokenExpiry(tokenString)
 	if err != nil {
 		return true
 	}
-	return time.Now().After(expiry)
+	return !m.now().Before(expiry.Add(m.config.Leeway))
 }
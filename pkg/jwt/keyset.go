@@ -0,0 +1,393 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// activeKeyFile file trong thư mục keys chứa kid đang dùng để ký
+const activeKeyFile = "active"
+
+// Thuật toán ký hỗ trợ, key bất đối xứng chọn thuật toán theo loại key
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgES384 = "ES384"
+	AlgES512 = "ES512"
+	AlgEdDSA = "EdDSA"
+)
+
+// SigningKey key ký token (RSA, ECDSA hoặc Ed25519), kid là RFC 7638 thumbprint của public key
+type SigningKey struct {
+	ID         string
+	PrivateKey crypto.Signer
+	// Algorithm thuật toán JWS suy ra từ loại key (RS256, ES256/384/512, EdDSA)
+	Algorithm string
+	// RotatedAt thời điểm key được tạo hoặc bị thay bằng key mới (mtime của file), dùng để prune
+	RotatedAt time.Time
+	path      string
+}
+
+// NewSigningKey tạo signing key từ private key, kid và thuật toán tính từ public key
+func NewSigningKey(privateKey crypto.Signer) (*SigningKey, error) {
+	alg, err := keyAlgorithm(privateKey.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &SigningKey{ID: KeyThumbprint(privateKey.Public()), PrivateKey: privateKey, Algorithm: alg}, nil
+}
+
+// PublicKey public key để verify
+func (k *SigningKey) PublicKey() crypto.PublicKey {
+	return k.PrivateKey.Public()
+}
+
+// keyAlgorithm thuật toán JWS tương ứng loại public key
+func keyAlgorithm(pub crypto.PublicKey) (string, error) {
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return AlgRS256, nil
+	case *ecdsa.PublicKey:
+		switch key.Curve {
+		case elliptic.P256():
+			return AlgES256, nil
+		case elliptic.P384():
+			return AlgES384, nil
+		case elliptic.P521():
+			return AlgES512, nil
+		}
+		return "", fmt.Errorf("unsupported EC curve: %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return AlgEdDSA, nil
+	default:
+		return "", fmt.Errorf("unsupported key type: %T", pub)
+	}
+}
+
+// GenerateKey tạo private key cho thuật toán, bits chỉ dùng cho RS256
+func GenerateKey(alg string, bits int) (crypto.Signer, error) {
+	switch alg {
+	case AlgRS256:
+		return rsa.GenerateKey(rand.Reader, bits)
+	case AlgES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case AlgES384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case AlgES512:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case AlgEdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	default:
+		return nil, fmt.Errorf("unsupported algorithm: %s", alg)
+	}
+}
+
+// KeySet tập keys theo kid: 1 key active để ký, các key cũ vẫn verify được token đã cấp cho đến khi bị prune
+type KeySet struct {
+	keys   map[string]*SigningKey
+	active *SigningKey
+	// fallback verify token không có kid header (cấp trước khi bật key rotation)
+	fallback *SigningKey
+}
+
+// NewKeySet tạo key set, key đầu tiên là key active
+func NewKeySet(active *SigningKey, others ...*SigningKey) *KeySet {
+	ks := &KeySet{keys: map[string]*SigningKey{active.ID: active}, active: active}
+	for _, key := range others {
+		ks.keys[key.ID] = key
+	}
+	return ks
+}
+
+// Active key đang dùng để ký
+func (ks *KeySet) Active() *SigningKey {
+	return ks.active
+}
+
+// Lookup tìm key theo kid, kid rỗng dùng fallback key
+func (ks *KeySet) Lookup(kid string) (*SigningKey, bool) {
+	if kid == "" {
+		return ks.fallback, ks.fallback != nil
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// Keys tất cả keys, mới nhất trước
+func (ks *KeySet) Keys() []*SigningKey {
+	keys := make([]*SigningKey, 0, len(ks.keys))
+	for _, key := range ks.keys {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == ks.active || keys[j] == ks.active {
+			return keys[i] == ks.active
+		}
+		return keys[i].RotatedAt.After(keys[j].RotatedAt)
+	})
+	return keys
+}
+
+// JWK public key dạng JSON Web Key (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+	// EC (crv, x, y) và OKP/Ed25519 (crv, x)
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS JSON Web Key Set, phục vụ ở /.well-known/jwks.json
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS public keys của tất cả keys trong set
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range ks.Keys() {
+		members := publicKeyMembers(key.PublicKey())
+		set.Keys = append(set.Keys, JWK{
+			Kty: members["kty"],
+			Use: "sig",
+			Alg: key.Algorithm,
+			Kid: key.ID,
+			N:   members["n"],
+			E:   members["e"],
+			Crv: members["crv"],
+			X:   members["x"],
+			Y:   members["y"],
+		})
+	}
+	return set
+}
+
+// publicKeyMembers các member bắt buộc của JWK theo loại key (RFC 7518, RFC 8037)
+func publicKeyMembers(pub crypto.PublicKey) map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	switch key := pub.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{"kty": "RSA", "n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes())}
+	case *ecdsa.PublicKey:
+		ecdhKey, err := key.ECDH()
+		if err != nil {
+			return nil
+		}
+		// Uncompressed point: 0x04 || X || Y, X và Y có độ dài cố định theo curve
+		point := ecdhKey.Bytes()[1:]
+		size := len(point) / 2
+		return map[string]string{"kty": "EC", "crv": key.Curve.Params().Name, "x": b64(point[:size]), "y": b64(point[size:])}
+	case ed25519.PublicKey:
+		return map[string]string{"kty": "OKP", "crv": "Ed25519", "x": b64(key)}
+	default:
+		return nil
+	}
+}
+
+// KeyThumbprint RFC 7638 thumbprint (SHA-256, base64url) của public key
+func KeyThumbprint(pub crypto.PublicKey) string {
+	// json.Marshal sắp xếp key của map, đúng thứ tự member theo RFC 7638
+	canonical, _ := json.Marshal(publicKeyMembers(pub))
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// LoadKeyDir đọc keys từ thư mục: mỗi key là 1 file *.pem (RotateKey đặt tên <kid>.pem), file "active" chứa kid đang dùng để ký.
+// Không có file active thì key mới nhất được dùng.
+func LoadKeyDir(dir string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no keys in %s", dir)
+	}
+
+	keys := make(map[string]*SigningKey, len(paths))
+	var newest *SigningKey
+	for _, path := range paths {
+		key, err := loadSigningKey(path)
+		if err != nil {
+			return nil, err
+		}
+		keys[key.ID] = key
+		if newest == nil || key.RotatedAt.After(newest.RotatedAt) {
+			newest = key
+		}
+	}
+
+	active := newest
+	if raw, err := os.ReadFile(filepath.Join(dir, activeKeyFile)); err == nil {
+		kid := strings.TrimSpace(string(raw))
+		key, ok := keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("active key %s not found in %s", kid, dir)
+		}
+		active = key
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read active key: %w", err)
+	}
+
+	return &KeySet{keys: keys, active: active}, nil
+}
+
+// loadSigningKey đọc private key PEM, kid tính từ public key (không phụ thuộc tên file)
+func loadSigningKey(path string) (*SigningKey, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key %s: %w", path, err)
+	}
+	privateKey, err := parsePrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", path, err)
+	}
+	key, err := NewSigningKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("parse key %s: %w", path, err)
+	}
+	key.RotatedAt = info.ModTime()
+	key.path = path
+	return key, nil
+}
+
+// parsePrivateKey parse private key PEM: PKCS1 (RSA), SEC1 (EC) hoặc PKCS8 (RSA, EC, Ed25519)
+func parsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("invalid private key PEM")
+	}
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported private key: %T", key)
+		}
+		return signer, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key type: %s", block.Type)
+	}
+}
+
+// RotateKey tạo key mới trong thư mục và chuyển sang ký bằng key mới.
+// Key cũ được giữ lại (mtime cập nhật thành thời điểm rotate) để verify token đã cấp,
+// kể cả khi đổi thuật toán (vd RS256 sang ES256).
+func RotateKey(dir, alg string, bits int, now time.Time) (*SigningKey, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("mkdir %s: %w", dir, err)
+	}
+
+	privateKey, err := GenerateKey(alg, bits)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshal key: %w", err)
+	}
+	key, err := NewSigningKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	key.RotatedAt = now
+
+	// Đánh dấu thời điểm key cũ ngừng ký, prune tính tuổi key từ thời điểm này
+	if current, err := LoadKeyDir(dir); err == nil {
+		if err := os.Chtimes(current.Active().path, now, now); err != nil {
+			return nil, fmt.Errorf("mark retired key: %w", err)
+		}
+	}
+
+	path := filepath.Join(dir, key.ID+".pem")
+	if err := writeFileAtomic(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, err
+	}
+	if err := os.Chtimes(path, now, now); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(filepath.Join(dir, activeKeyFile), []byte(key.ID+"\n"), 0o600); err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// PruneKeys xóa key không còn active đã ngừng ký lâu hơn retention.
+// Retention phải lớn hơn thời hạn refresh token, nếu không token còn hạn sẽ không verify được.
+func PruneKeys(dir string, retention time.Duration, now time.Time) ([]string, error) {
+	ks, err := LoadKeyDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, key := range ks.Keys() {
+		if key == ks.Active() || now.Sub(key.RotatedAt) < retention {
+			continue
+		}
+		if err := os.Remove(key.path); err != nil {
+			return removed, fmt.Errorf("remove key %s: %w", key.ID, err)
+		}
+		removed = append(removed, key.ID)
+	}
+	return removed, nil
+}
+
+// writeFileAtomic ghi file tạm rồi rename để process khác không đọc phải file ghi dở
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -2,10 +2,12 @@ package jwt
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
-	"api-core/pkg/i18n"
-	"api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/securitylog"
 )
 
 // contextKey là kiểu để lưu claims vào context
@@ -35,19 +37,21 @@ func (m *Manager) Middleware(next http.Handler) http.Handler {
 		claims, err := m.VerifyToken(token)
 		if err != nil {
 			if err == ErrExpiredToken {
+				securitylog.SetUser(r.Context(), m.ExtractUserID(token))
 				response.Unauthorized(w, lang, response.CodeTokenExpired)
 				return
 			}
+			// Opaque mode: Redis lỗi không có nghĩa token sai, client retry thay vì đăng nhập lại
+			if errors.Is(err, ErrTokenStoreUnavailable) {
+				response.ServiceUnavailable(w, lang, response.CodeServiceUnavailable)
+				return
+			}
 			response.Unauthorized(w, lang, response.CodeTokenInvalid)
 			return
 		}
 
-		// Lưu claims vào context
-		ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
-		ctx = context.WithValue(ctx, UserIDContextKey, claims.UserID)
-
-		// Tiếp tục với request có context mới
-		next.ServeHTTP(w, r.WithContext(ctx))
+		// Lưu claims vào context, tiếp tục với request có context mới
+		next.ServeHTTP(w, withClaims(r, claims))
 	})
 }
 
@@ -59,9 +63,7 @@ func (m *Manager) OptionalMiddleware(next http.Handler) http.Handler {
 		if token != "" {
 			claims, err := m.VerifyToken(token)
 			if err == nil {
-				ctx := context.WithValue(r.Context(), ClaimsContextKey, claims)
-				ctx = context.WithValue(ctx, UserIDContextKey, claims.UserID)
-				r = r.WithContext(ctx)
+				r = withClaims(r, claims)
 			}
 		}
 
@@ -69,52 +71,25 @@ func (m *Manager) OptionalMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-// RequireRole middleware kiểm tra role của user
+// RequireRole middleware kiểm tra role của user (user có một trong các role)
+//
+// Deprecated: dùng RequireAnyRole (không cần Manager).
 func (m *Manager) RequireRole(roles ...string) func(http.Handler) http.Handler {
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			lang := i18n.GetLanguageFromContext(r.Context())
-
-			claims := GetClaimsFromContext(r.Context())
-			if claims == nil {
-				response.Unauthorized(w, lang, response.CodeTokenMissing)
-				return
-			}
-
-			// Kiểm tra role
-			hasRole := false
-			for _, role := range roles {
-				if claims.Role == role {
-					hasRole = true
-					break
-				}
-			}
-
-			if !hasRole {
-				response.Forbidden(w, lang, response.CodePermissionDenied)
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
+	return RequireAnyRole(roles...)
 }
 
 // GetClaimsFromContext lấy claims từ context
 func GetClaimsFromContext(ctx context.Context) *Claims {
-	claims, ok := ctx.Value(ClaimsContextKey).(*Claims)
-	if !ok {
-		return nil
-	}
+	claims, _ := ClaimsFromContext(ctx)
 	return claims
 }
 
-// GetUserIDFromContext lấy user ID từ context
+// GetUserIDFromContext lấy user ID từ context (ưu tiên principal, sau đó UserIDContextKey)
 func GetUserIDFromContext(ctx context.Context) string {
-	userID, ok := ctx.Value(UserIDContextKey).(string)
-	if !ok {
-		return ""
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal.UserID
 	}
+	userID, _ := ctx.Value(UserIDContextKey).(string)
 	return userID
 }
 
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ErrTokenStoreUnavailable không đọc được opaque token store (Redis lỗi), middleware trả 503 thay vì 401
+var ErrTokenStoreUnavailable = errors.New("token store unavailable")
+
+// Loại token lưu trong opaque record
+const (
+	tokenTypeAccess  = "access"
+	tokenTypeRefresh = "refresh"
+)
+
+const (
+	// opaqueTokenBytes số byte ngẫu nhiên của opaque token (256 bit)
+	opaqueTokenBytes = 32
+	// opaqueExpiredRetention giữ record sau khi hết hạn để trả ErrExpiredToken (client biết cần refresh) thay vì ErrInvalidToken
+	opaqueExpiredRetention = time.Hour
+)
+
+// OpaqueStore lưu opaque token (chuỗi ngẫu nhiên, không chứa claims) kèm claims trong Redis.
+// Thu hồi có hiệu lực ngay khi xóa record, đổi lại mỗi request phải đọc Redis.
+// Key là SHA-256 của token nên dump Redis không lộ token dùng được.
+type OpaqueStore struct {
+	cache  cache.Cache
+	prefix string
+}
+
+// NewOpaqueStore tạo opaque token store
+func NewOpaqueStore(c cache.Cache) *OpaqueStore {
+	return &OpaqueStore{
+		cache:  c,
+		prefix: "jwt:opaque:",
+	}
+}
+
+// opaqueRecord giá trị lưu trong Redis cho 1 token
+type opaqueRecord struct {
+	Type   string          `json:"typ"`
+	Claims json.RawMessage `json:"claims"`
+}
+
+// IsOpaqueToken token không có dạng JWT (header.payload.signature)
+func IsOpaqueToken(token string) bool {
+	return token != "" && !strings.Contains(token, ".")
+}
+
+func (s *OpaqueStore) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return s.prefix + hex.EncodeToString(sum[:])
+}
+
+// issue tạo token ngẫu nhiên và lưu claims đến hết hạn (cộng thời gian giữ record đã hết hạn)
+func (s *OpaqueStore) issue(ctx context.Context, typ string, claims interface{}, ttl time.Duration) (string, error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate opaque token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal claims: %w", err)
+	}
+	record, err := json.Marshal(opaqueRecord{Type: typ, Claims: raw})
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.cache.Set(ctx, s.key(token), string(record), ttl+opaqueExpiredRetention); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTokenStoreUnavailable, err)
+	}
+	return token, nil
+}
+
+// load đọc claims của token vào claims, typ rỗng = không kiểm tra loại token
+func (s *OpaqueStore) load(ctx context.Context, token, typ string, claims interface{}) error {
+	value, err := s.cache.Get(ctx, s.key(token))
+	if errors.Is(err, cache.ErrCacheMiss) || errors.Is(err, redis.Nil) {
+		return ErrInvalidToken
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTokenStoreUnavailable, err)
+	}
+
+	var record opaqueRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return ErrInvalidToken
+	}
+	// Refresh token không dùng được như access token và ngược lại
+	if typ != "" && record.Type != typ {
+		return ErrInvalidToken
+	}
+	if err := json.Unmarshal(record.Claims, claims); err != nil {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// Revoke xóa token, các request sau dùng token bị từ chối ngay
+func (s *OpaqueStore) Revoke(ctx context.Context, token string) error {
+	return s.cache.Del(ctx, s.key(token))
+}
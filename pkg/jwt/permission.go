@@ -0,0 +1,230 @@
+package jwt
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// MetadataPermissions key trong Claims.Metadata chứa danh sách permissions của user
+const MetadataPermissions = "permissions"
+
+// defaultStaleClaimsTTL thời gian bỏ qua permissions trong token sau khi Invalidate khi chưa gọi SetStaleClaimsTTL
+const defaultStaleClaimsTTL = 24 * time.Hour
+
+// PermissionLoader lấy danh sách permissions của user khi token không chứa permissions
+type PermissionLoader func(ctx context.Context, userID string) ([]string, error)
+
+// PermissionChecker kiểm tra permissions của user, hỗ trợ wildcard ("users.*", "*")
+type PermissionChecker struct {
+	cache  cache.Cache
+	loader PermissionLoader
+	prefix string
+	ttl    time.Duration
+	// staleTTL thời gian bỏ qua permissions trong token sau khi Invalidate, phải >= thời hạn access token
+	staleTTL time.Duration
+}
+
+// NewPermissionChecker tạo permission checker mới, kết quả của loader được cache trong ttl
+func NewPermissionChecker(c cache.Cache, loader PermissionLoader, ttl time.Duration) *PermissionChecker {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &PermissionChecker{
+		cache:    c,
+		loader:   loader,
+		prefix:   "jwt:permissions:",
+		ttl:      ttl,
+		staleTTL: defaultStaleClaimsTTL,
+	}
+}
+
+// SetStaleClaimsTTL đặt thời gian bỏ qua permissions trong token cũ sau Invalidate,
+// truyền Manager.MaxAccessTokenLifetime() để token cấp trước khi đổi role không lấy lại quyền cũ khi chưa hết hạn
+func (p *PermissionChecker) SetStaleClaimsTTL(ttl time.Duration) {
+	if ttl > 0 {
+		p.staleTTL = ttl
+	}
+}
+
+// Require middleware yêu cầu user có TẤT CẢ permissions
+func (p *PermissionChecker) Require(permissions ...string) func(http.Handler) http.Handler {
+	return p.middleware(func(granted []string) bool {
+		for _, required := range permissions {
+			if !HasPermission(granted, required) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// RequirePermission alias của Require, ví dụ perm.RequirePermission("users.*")
+func (p *PermissionChecker) RequirePermission(permissions ...string) func(http.Handler) http.Handler {
+	return p.Require(permissions...)
+}
+
+// RequireAny middleware yêu cầu user có ÍT NHẤT MỘT trong các permissions
+func (p *PermissionChecker) RequireAny(permissions ...string) func(http.Handler) http.Handler {
+	return p.middleware(func(granted []string) bool {
+		for _, required := range permissions {
+			if HasPermission(granted, required) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+func (p *PermissionChecker) middleware(allowed func(granted []string) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lang := i18n.GetLanguageFromContext(r.Context())
+
+			claims := GetClaimsFromContext(r.Context())
+			if claims == nil {
+				response.Unauthorized(w, lang, response.CodeTokenMissing)
+				return
+			}
+
+			granted, err := p.Permissions(r.Context(), claims)
+			if err != nil {
+				response.InternalServerError(w, lang, response.CodeInternalServerError)
+				return
+			}
+
+			if !allowed(granted) {
+				response.Forbidden(w, lang, response.CodePermissionDenied)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Permissions lấy permissions của user: ưu tiên từ claims, fallback sang loader (có cache).
+// Sau khi Invalidate, permissions trong token cũ bị bỏ qua để thay đổi role có hiệu lực ngay.
+func (p *PermissionChecker) Permissions(ctx context.Context, claims *Claims) ([]string, error) {
+	if permissions, ok := PermissionsFromClaims(claims); ok && (p.loader == nil || !p.claimsStale(ctx, claims.UserID)) {
+		return permissions, nil
+	}
+	if p.loader == nil {
+		return nil, nil
+	}
+
+	key := p.prefix + claims.UserID
+	if p.cache != nil {
+		if cached, err := p.cache.Get(ctx, key); err == nil {
+			var permissions []string
+			if err := json.Unmarshal([]byte(cached), &permissions); err == nil {
+				return permissions, nil
+			}
+		}
+	}
+
+	permissions, err := p.loader(ctx, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if permissions == nil {
+		permissions = []string{}
+	}
+
+	if p.cache != nil {
+		// Lỗi cache không làm fail request
+		_ = p.cache.Set(ctx, key, permissions, p.ttl)
+	}
+	return permissions, nil
+}
+
+// Invalidate xóa permissions đã cache của user và đánh dấu permissions trong token hiện tại đã cũ
+// (gọi khi đổi role/permissions)
+func (p *PermissionChecker) Invalidate(ctx context.Context, userID string) error {
+	if p.cache == nil {
+		return nil
+	}
+	if err := p.cache.Set(ctx, p.staleKey(userID), "1", p.staleTTL); err != nil {
+		return err
+	}
+	return p.cache.Del(ctx, p.prefix+userID)
+}
+
+func (p *PermissionChecker) claimsStale(ctx context.Context, userID string) bool {
+	if p.cache == nil {
+		return false
+	}
+	_, err := p.cache.Get(ctx, p.staleKey(userID))
+	return err == nil
+}
+
+func (p *PermissionChecker) staleKey(userID string) string {
+	return p.prefix + "stale:" + userID
+}
+
+// PermissionsFromClaims đọc permissions từ metadata của token
+func PermissionsFromClaims(claims *Claims) ([]string, bool) {
+	if claims == nil || claims.Metadata == nil {
+		return nil, false
+	}
+
+	switch v := claims.Metadata[MetadataPermissions].(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		// Metadata decode từ JSON nên là []interface{}
+		permissions := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				permissions = append(permissions, s)
+			}
+		}
+		return permissions, true
+	default:
+		return nil, false
+	}
+}
+
+// HasPermission kiểm tra danh sách granted có permission required không
+func HasPermission(granted []string, required string) bool {
+	for _, g := range granted {
+		if MatchPermission(g, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPermission kiểm tra permission granted có bao hàm required không
+// Các segment phân tách bằng ".": "*" khớp 1 segment bất kỳ, "*" ở cuối khớp toàn bộ phần còn lại.
+// Ví dụ: "users.*" khớp "users.view" và "users.*"; "*" khớp mọi permission; "users.view" không khớp "users.*".
+func MatchPermission(granted, required string) bool {
+	if granted == "" || required == "" {
+		return false
+	}
+	if granted == "*" || granted == required {
+		return true
+	}
+
+	gParts := strings.Split(granted, ".")
+	rParts := strings.Split(required, ".")
+
+	for i, g := range gParts {
+		if g == "*" && i == len(gParts)-1 {
+			return len(rParts) >= len(gParts)
+		}
+		if i >= len(rParts) {
+			return false
+		}
+		if g != "*" && g != rParts[i] {
+			return false
+		}
+	}
+	return len(gParts) == len(rParts)
+}
@@ -0,0 +1,63 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+)
+
+var (
+	// ErrTokenRevoked token (refresh hoặc access) cấp trước lần logout all / đổi password gần nhất của user
+	ErrTokenRevoked = errors.New("token has been revoked")
+	// ErrSubjectDisabled user của token đã bị khóa
+	ErrSubjectDisabled = errors.New("token subject is disabled")
+)
+
+// RefreshSubject thông tin hiện tại của user (đọc từ database lúc refresh), không lấy từ token hay client
+type RefreshSubject struct {
+	Email        string
+	Role         string
+	TokenVersion int
+	Disabled     bool
+	Metadata     map[string]interface{} // Permissions (MetadataPermissions), tên, ...
+}
+
+// RefreshSubjectLoader tải thông tin hiện tại của user, trả về ErrUserNotFound nếu user không còn tồn tại
+type RefreshSubjectLoader func(ctx context.Context, userID string) (*RefreshSubject, error)
+
+// RefreshAccessToken cấp cặp token mới từ refresh token.
+// Email, role, permissions được loader đọc lại server-side, nên đổi role/thu hồi quyền có hiệu lực ngay lần refresh kế tiếp
+// (client không thể giữ role cũ hoặc tự khai role cao hơn). Refresh token có token_version cũ hơn của user bị từ chối.
+// Opaque mode: refresh token đã dùng bị thu hồi (rotation).
+func (m *Manager) RefreshAccessToken(ctx context.Context, refreshToken string, loader RefreshSubjectLoader) (*TokenPair, error) {
+	claims, err := m.VerifyRefreshTokenClaims(refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := loader(ctx, claims.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load token subject: %w", err)
+	}
+	if subject.Disabled {
+		return nil, ErrSubjectDisabled
+	}
+	if claims.TokenVersion < subject.TokenVersion {
+		return nil, ErrTokenRevoked
+	}
+
+	tokenPair, err := m.GenerateVersionedTokenPair(claims.Subject, subject.Email, subject.Role, subject.TokenVersion, subject.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.IsOpaque(refreshToken) {
+		if err := m.RevokeOpaqueToken(refreshToken); err != nil {
+			logger.Warnf("Failed to revoke used refresh token: %v", err)
+		}
+	}
+
+	return tokenPair, nil
+}
@@ -0,0 +1,93 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+)
+
+// ErrUserNotFound loader trả về khi user không còn tồn tại, mọi token của user bị coi là đã thu hồi
+var ErrUserNotFound = errors.New("user not found")
+
+// TokenVersionLoader lấy token_version hiện tại của user (từ database)
+type TokenVersionLoader func(ctx context.Context, userID string) (int, error)
+
+// TokenVersions thu hồi mọi token của user bằng token_version: logout all / đổi password tăng version,
+// token mang version cũ hơn version hiện tại bị từ chối, token cấp sau đó vẫn dùng được.
+type TokenVersions struct {
+	cache  cache.Cache
+	loader TokenVersionLoader
+	prefix string
+	ttl    time.Duration
+}
+
+// NewTokenVersions tạo token version store, version đọc từ loader được cache trong ttl
+func NewTokenVersions(c cache.Cache, loader TokenVersionLoader, ttl time.Duration) *TokenVersions {
+	if ttl <= 0 {
+		ttl = 10 * time.Minute
+	}
+	return &TokenVersions{
+		cache:  c,
+		loader: loader,
+		prefix: "jwt:token_version:",
+		ttl:    ttl,
+	}
+}
+
+// Current token_version hiện tại của user
+func (v *TokenVersions) Current(ctx context.Context, userID string) (int, error) {
+	key := v.prefix + userID
+	if v.cache != nil {
+		if cached, err := v.cache.Get(ctx, key); err == nil {
+			if version, err := strconv.Atoi(cached); err == nil {
+				return version, nil
+			}
+		}
+	}
+
+	version, err := v.loader(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	if v.cache != nil {
+		// Lỗi cache không làm fail request
+		_ = v.cache.Set(ctx, key, strconv.Itoa(version), v.ttl)
+	}
+	return version, nil
+}
+
+// Set ghi version mới vào cache ngay sau khi tăng version trong database để thu hồi có hiệu lực ngay
+func (v *TokenVersions) Set(ctx context.Context, userID string, version int) error {
+	if v.cache == nil {
+		return nil
+	}
+	return v.cache.Set(ctx, v.prefix+userID, strconv.Itoa(version), v.ttl)
+}
+
+// IsStale kiểm tra token đã bị thu hồi: version của user (và của admin với impersonation token) đã tăng
+func (v *TokenVersions) IsStale(ctx context.Context, claims *Claims) (bool, error) {
+	stale, err := v.isStale(ctx, claims.UserID, claims.TokenVersion)
+	if err != nil || stale {
+		return stale, err
+	}
+
+	if claims.IsImpersonated() {
+		return v.isStale(ctx, claims.Impersonator, claims.ImpersonatorTokenVersion)
+	}
+	return false, nil
+}
+
+func (v *TokenVersions) isStale(ctx context.Context, userID string, version int) (bool, error) {
+	current, err := v.Current(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return version < current, nil
+}
@@ -0,0 +1,340 @@
+package loadshed
+
+import (
+	"context"
+	"runtime"
+	"runtime/metrics"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Priority defines the priority class of a route
+type Priority int
+
+const (
+	// PriorityLow routes are shed first when the service is overloaded
+	PriorityLow Priority = iota
+	// PriorityNormal routes are shed only under severe overload
+	PriorityNormal
+	// PriorityCritical routes are never shed (health checks, auth...)
+	PriorityCritical
+)
+
+// ParsePriority converts a string (low, normal, critical) to Priority
+func ParsePriority(s string) Priority {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "low":
+		return PriorityLow
+	case "critical":
+		return PriorityCritical
+	default:
+		return PriorityNormal
+	}
+}
+
+// Config holds load shedding configuration
+type Config struct {
+	MaxP99Latency   time.Duration       // p99 latency threshold (0 = disabled)
+	MaxGoroutines   int                 // goroutine count threshold (0 = disabled)
+	MaxCPUPercent   float64             // CPU usage threshold in percent of GOMAXPROCS (0 = disabled)
+	SampleSize      int                 // Number of recent requests used to compute p99
+	SampleMaxAge    time.Duration       // Samples older than this are ignored, so p99 recovers while requests are shed
+	EvalInterval    time.Duration       // How often the overload level is recomputed
+	SevereFactor    float64             // Multiplier over thresholds that triggers shedding of normal routes
+	RecoverFactor   float64             // A level is left only when signals drop below RecoverFactor × its threshold
+	RetryAfter      time.Duration       // Value of Retry-After header for rejected requests
+	RouteClasses    map[string]Priority // Path prefix -> priority
+	DefaultPriority Priority            // Priority for unmatched routes
+}
+
+// Level describes the current overload level
+type Level int32
+
+const (
+	// LevelNormal no shedding
+	LevelNormal Level = iota
+	// LevelOverloaded low priority traffic is rejected
+	LevelOverloaded
+	// LevelSevere low and normal priority traffic is rejected
+	LevelSevere
+)
+
+// Stats snapshot of the signals used by the shedder
+type Stats struct {
+	Level         Level         `json:"level"`
+	P99Latency    time.Duration `json:"p99_latency"`
+	Goroutines    int           `json:"goroutines"`
+	CPUPercent    float64       `json:"cpu_percent"`
+	ShedRequests  int64         `json:"shed_requests"`
+	SampledWindow int           `json:"sampled_window"`
+}
+
+// Shedder monitors latency, goroutines and CPU to decide whether to reject traffic
+type Shedder struct {
+	config Config
+
+	mu      sync.Mutex
+	samples []sample
+	next    int
+	filled  bool
+
+	level    atomic.Int32
+	shed     atomic.Int64
+	statsMu  sync.RWMutex
+	stats    Stats
+	prefixes []string
+
+	lastCPU  float64
+	lastWall time.Time
+}
+
+// sample latency of one request and when it completed
+type sample struct {
+	latency time.Duration
+	at      time.Time
+}
+
+// NewShedder creates a new load shedder
+func NewShedder(config Config) *Shedder {
+	if config.SampleSize <= 0 {
+		config.SampleSize = 1000
+	}
+	if config.EvalInterval <= 0 {
+		config.EvalInterval = time.Second
+	}
+	if config.SampleMaxAge <= 0 {
+		config.SampleMaxAge = 30 * time.Second
+	}
+	if config.SevereFactor <= 1 {
+		config.SevereFactor = 1.5
+	}
+	if config.RecoverFactor <= 0 || config.RecoverFactor > 1 {
+		config.RecoverFactor = 0.8
+	}
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = 5 * time.Second
+	}
+
+	// Sort prefixes longest first so that the most specific class wins
+	prefixes := make([]string, 0, len(config.RouteClasses))
+	for prefix := range config.RouteClasses {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool {
+		return len(prefixes[i]) > len(prefixes[j])
+	})
+
+	return &Shedder{
+		config:   config,
+		samples:  make([]sample, config.SampleSize),
+		prefixes: prefixes,
+		lastWall: time.Now(),
+		lastCPU:  readCPUSeconds(),
+	}
+}
+
+// Start chạy vòng lặp đánh giá overload level cho đến khi ctx bị huỷ
+func (s *Shedder) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.config.EvalInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.evaluate()
+			}
+		}
+	}()
+}
+
+// Observe records the latency of a completed request
+func (s *Shedder) Observe(d time.Duration) {
+	s.mu.Lock()
+	s.samples[s.next] = sample{latency: d, at: time.Now()}
+	s.next++
+	if s.next >= len(s.samples) {
+		s.next = 0
+		s.filled = true
+	}
+	s.mu.Unlock()
+}
+
+// PriorityFor returns the priority class of a path
+func (s *Shedder) PriorityFor(path string) Priority {
+	for _, prefix := range s.prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return s.config.RouteClasses[prefix]
+		}
+	}
+	return s.config.DefaultPriority
+}
+
+// ShouldShed reports whether a request with the given priority must be rejected
+func (s *Shedder) ShouldShed(priority Priority) bool {
+	if priority == PriorityCritical {
+		return false
+	}
+
+	level := Level(s.level.Load())
+	shed := false
+	switch level {
+	case LevelOverloaded:
+		shed = priority == PriorityLow
+	case LevelSevere:
+		shed = priority <= PriorityNormal
+	}
+
+	if shed {
+		s.shed.Add(1)
+	}
+	return shed
+}
+
+// RetryAfter returns the configured Retry-After duration
+func (s *Shedder) RetryAfter() time.Duration {
+	return s.config.RetryAfter
+}
+
+// Stats returns the latest snapshot of shedder signals
+func (s *Shedder) Stats() Stats {
+	s.statsMu.RLock()
+	defer s.statsMu.RUnlock()
+	stats := s.stats
+	stats.ShedRequests = s.shed.Load()
+	return stats
+}
+
+// evaluate recomputes the overload level from current signals
+func (s *Shedder) evaluate() {
+	p99, window := s.p99()
+	goroutines := runtime.NumGoroutine()
+	cpu := s.cpuPercent()
+
+	maxRatio := 0.0
+	for _, ratio := range []float64{
+		ratioOf(float64(p99), float64(s.config.MaxP99Latency)),
+		ratioOf(float64(goroutines), float64(s.config.MaxGoroutines)),
+		ratioOf(cpu, s.config.MaxCPUPercent),
+	} {
+		if ratio > maxRatio {
+			maxRatio = ratio
+		}
+	}
+
+	level := s.levelFor(maxRatio, Level(s.level.Load()))
+	s.level.Store(int32(level))
+
+	s.statsMu.Lock()
+	s.stats = Stats{
+		Level:         level,
+		P99Latency:    p99,
+		Goroutines:    goroutines,
+		CPUPercent:    cpu,
+		SampledWindow: window,
+	}
+	s.statsMu.Unlock()
+}
+
+// levelFor maps the highest signal ratio to a level. Levels are entered at 1 and SevereFactor
+// but only left below RecoverFactor × those thresholds, so the level does not flap around them.
+func (s *Shedder) levelFor(ratio float64, current Level) Level {
+	level := LevelNormal
+	switch {
+	case ratio >= s.config.SevereFactor:
+		level = LevelSevere
+	case ratio >= 1:
+		level = LevelOverloaded
+	}
+	if level >= current {
+		return level
+	}
+
+	if current == LevelSevere && ratio >= s.config.SevereFactor*s.config.RecoverFactor {
+		return LevelSevere
+	}
+	if ratio >= s.config.RecoverFactor {
+		return LevelOverloaded
+	}
+	return level
+}
+
+// p99 computes the 99th percentile latency over the samples younger than SampleMaxAge.
+// Shed requests are not observed, so without the age limit the window would keep the
+// latencies that triggered shedding and the level would never recover.
+func (s *Shedder) p99() (time.Duration, int) {
+	cutoff := time.Now().Add(-s.config.SampleMaxAge)
+
+	s.mu.Lock()
+	n := s.next
+	if s.filled {
+		n = len(s.samples)
+	}
+	window := make([]time.Duration, 0, n)
+	for _, entry := range s.samples[:n] {
+		if entry.at.After(cutoff) {
+			window = append(window, entry.latency)
+		}
+	}
+	s.mu.Unlock()
+
+	n = len(window)
+	if n == 0 {
+		return 0, 0
+	}
+
+	sort.Slice(window, func(i, j int) bool { return window[i] < window[j] })
+	idx := int(float64(n)*0.99+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= n {
+		idx = n - 1
+	}
+	return window[idx], n
+}
+
+// cpuPercent computes CPU usage since the last evaluation, relative to GOMAXPROCS
+func (s *Shedder) cpuPercent() float64 {
+	now := time.Now()
+	cpu := readCPUSeconds()
+
+	wall := now.Sub(s.lastWall).Seconds()
+	used := cpu - s.lastCPU
+	s.lastWall = now
+	s.lastCPU = cpu
+
+	if wall <= 0 || used < 0 {
+		return 0
+	}
+	return used / (wall * float64(runtime.GOMAXPROCS(0))) * 100
+}
+
+// readCPUSeconds reads total CPU time consumed by the Go runtime
+func readCPUSeconds() float64 {
+	sample := []metrics.Sample{{Name: "/cpu/classes/total:cpu-seconds"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	// total bao gồm cả idle, trừ đi để lấy CPU thực sự sử dụng
+	idle := []metrics.Sample{{Name: "/cpu/classes/idle:cpu-seconds"}}
+	metrics.Read(idle)
+	total := sample[0].Value.Float64()
+	if idle[0].Value.Kind() == metrics.KindFloat64 {
+		total -= idle[0].Value.Float64()
+	}
+	return total
+}
+
+// ratioOf returns value/threshold, or 0 when the threshold is disabled
+func ratioOf(value, threshold float64) float64 {
+	if threshold <= 0 {
+		return 0
+	}
+	return value / threshold
+}
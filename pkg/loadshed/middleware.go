@@ -0,0 +1,36 @@
+package loadshed
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// Middleware rejects low priority requests with 503 when the service is overloaded
+func Middleware(shedder *Shedder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			priority := shedder.PriorityFor(r.URL.Path)
+
+			if shedder.ShouldShed(priority) {
+				lang := i18n.GetLanguageFromContext(r.Context())
+				w.Header().Set("Retry-After", strconv.FormatInt(int64(shedder.RetryAfter().Seconds()), 10))
+				response.ServiceUnavailable(w, lang, response.CodeServerOverloaded)
+				return
+			}
+
+			// WebSocket connections sống lâu, không tính vào latency
+			if r.Header.Get("Upgrade") == "websocket" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			next.ServeHTTP(w, r)
+			shedder.Observe(time.Since(start))
+		})
+	}
+}
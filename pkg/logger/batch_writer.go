@@ -0,0 +1,247 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// BatchOptions cấu hình gom batch của sink gửi qua mạng (cloudwatch, syslog)
+type BatchOptions struct {
+	BatchSize     int           // số dòng tối đa mỗi lần gửi, 0: 500
+	FlushInterval time.Duration // gửi batch chưa đầy sau khoảng này, 0: 1s
+	BufferSize    int           // số dòng tối đa chờ gửi, 0: 10000
+	BlockTimeout  time.Duration // buffer đầy: chờ tối đa khoảng này rồi bỏ dòng log, 0: bỏ ngay
+	MaxRetries    int           // số lần gửi lại batch lỗi trước khi bỏ
+	SendTimeout   time.Duration // timeout mỗi lần gửi, 0: 10s
+}
+
+func (o BatchOptions) withDefaults() BatchOptions {
+	if o.BatchSize <= 0 {
+		o.BatchSize = 500
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = time.Second
+	}
+	if o.BufferSize <= 0 {
+		o.BufferSize = 10000
+	}
+	if o.BufferSize < o.BatchSize {
+		o.BufferSize = o.BatchSize
+	}
+	if o.MaxRetries < 0 {
+		o.MaxRetries = 0
+	}
+	if o.SendTimeout <= 0 {
+		o.SendTimeout = 10 * time.Second
+	}
+	return o
+}
+
+// logEntry một dòng log chờ gửi
+type logEntry struct {
+	Time  time.Time
+	Level zerolog.Level
+	Data  []byte // JSON của zerolog, không có newline cuối
+}
+
+// sendFunc gửi một batch, entries theo thứ tự ghi
+type sendFunc func(ctx context.Context, entries []logEntry) error
+
+// BatchWriter ghi log bất đồng bộ: Write chỉ đưa dòng log vào buffer, goroutine nền gom batch
+// theo BatchSize/FlushInterval rồi gửi. Sink chậm hoặc lỗi không chặn request, buffer đầy thì
+// bỏ dòng log (sau BlockTimeout) và báo số dòng bị bỏ ra stderr.
+type BatchWriter struct {
+	name    string
+	send    sendFunc
+	close   func() error
+	options BatchOptions
+
+	entries  chan logEntry
+	flushReq chan chan struct{}
+	quit     chan struct{}
+	done     chan struct{}
+
+	closed    atomic.Bool
+	closeOnce sync.Once
+	dropped   atomic.Uint64
+	failed    atomic.Uint64
+	reported  uint64 // số dòng bị bỏ đã báo ra stderr, chỉ goroutine gửi đọc/ghi
+}
+
+// newBatchWriter tạo writer và chạy goroutine gửi, closeFn (có thể nil) được gọi khi dừng
+func newBatchWriter(name string, send sendFunc, closeFn func() error, options BatchOptions) *BatchWriter {
+	options = options.withDefaults()
+	w := &BatchWriter{
+		name:     name,
+		send:     send,
+		close:    closeFn,
+		options:  options,
+		entries:  make(chan logEntry, options.BufferSize),
+		flushReq: make(chan chan struct{}),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write implements io.Writer
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter, level dùng cho severity của syslog
+func (w *BatchWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if w.closed.Load() {
+		w.dropped.Add(1)
+		return len(p), nil
+	}
+
+	// zerolog dùng lại buffer sau khi Write trả về nên phải copy
+	data := make([]byte, len(p))
+	copy(data, p)
+	for len(data) > 0 && (data[len(data)-1] == '\n' || data[len(data)-1] == '\r') {
+		data = data[:len(data)-1]
+	}
+	entry := logEntry{Time: time.Now(), Level: level, Data: data}
+
+	select {
+	case w.entries <- entry:
+		return len(p), nil
+	default:
+	}
+	if w.options.BlockTimeout > 0 {
+		timer := time.NewTimer(w.options.BlockTimeout)
+		defer timer.Stop()
+		select {
+		case w.entries <- entry:
+			return len(p), nil
+		case <-timer.C:
+		case <-w.quit:
+		}
+	}
+	w.dropped.Add(1)
+	return len(p), nil
+}
+
+// Flush chờ gửi hết các dòng log đã ghi trước đó
+func (w *BatchWriter) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case w.flushReq <- ack:
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close gửi nốt buffer rồi dừng writer, các lần Write sau đó bị bỏ
+func (w *BatchWriter) Close(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		w.closed.Store(true)
+		close(w.quit)
+	})
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%s: %w", w.name, ctx.Err())
+	}
+}
+
+// Dropped số dòng log bị bỏ do buffer đầy hoặc writer đã đóng
+func (w *BatchWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Failed số dòng log bị bỏ do gửi lỗi sau khi đã retry
+func (w *BatchWriter) Failed() uint64 {
+	return w.failed.Load()
+}
+
+func (w *BatchWriter) run() {
+	defer close(w.done)
+	if w.close != nil {
+		defer w.close()
+	}
+
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]logEntry, 0, w.options.BatchSize)
+	for {
+		select {
+		case entry := <-w.entries:
+			batch = append(batch, entry)
+			if len(batch) >= w.options.BatchSize {
+				batch = w.flush(batch)
+			}
+		case <-ticker.C:
+			batch = w.flush(batch)
+		case ack := <-w.flushReq:
+			batch = w.drain(batch)
+			close(ack)
+		case <-w.quit:
+			w.drain(batch)
+			return
+		}
+	}
+}
+
+// drain gửi batch hiện tại và mọi dòng đang chờ trong buffer
+func (w *BatchWriter) drain(batch []logEntry) []logEntry {
+	for {
+		select {
+		case entry := <-w.entries:
+			batch = append(batch, entry)
+			if len(batch) >= w.options.BatchSize {
+				batch = w.flush(batch)
+			}
+		default:
+			return w.flush(batch)
+		}
+	}
+}
+
+// flush gửi batch (retry theo MaxRetries), trả về batch rỗng để dùng lại
+func (w *BatchWriter) flush(batch []logEntry) []logEntry {
+	if dropped := w.dropped.Load(); dropped > w.reported {
+		fmt.Fprintf(os.Stderr, "%s: dropped %d log entries (buffer full)\n", w.name, dropped-w.reported)
+		w.reported = dropped
+	}
+	if len(batch) == 0 {
+		return batch
+	}
+
+	var err error
+	for attempt := 0; attempt <= w.options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * 200 * time.Millisecond):
+			case <-w.quit:
+			}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), w.options.SendTimeout)
+		err = w.send(ctx, batch)
+		cancel()
+		if err == nil {
+			return batch[:0]
+		}
+	}
+	w.failed.Add(uint64(len(batch)))
+	fmt.Fprintf(os.Stderr, "%s: failed to send %d log entries: %v\n", w.name, len(batch), err)
+	return batch[:0]
+}
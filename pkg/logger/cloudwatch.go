@@ -0,0 +1,210 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// Giới hạn của PutLogEvents
+const (
+	cloudWatchMaxBatchEvents = 10000
+	cloudWatchMaxBatchBytes  = 1048576
+	cloudWatchEventOverhead  = 26
+	cloudWatchMaxEventBytes  = 256*1024 - cloudWatchEventOverhead
+)
+
+// CloudWatchConfig cấu hình sink CloudWatch Logs. AccessKeyID rỗng: lấy credentials theo chuỗi mặc định của AWS (env, IAM role)
+type CloudWatchConfig struct {
+	Region          string
+	LogGroup        string // log group phải tạo sẵn (retention quản lý ngoài app)
+	LogStream       string // tiền tố log stream, rỗng: hostname; stream thực tế là <LogStream>/<job>
+	AccessKeyID     string
+	SecretAccessKey string
+	Endpoint        string // Optional: URL thay thế (test, VPC endpoint), rỗng dùng https://logs.<region>.amazonaws.com
+}
+
+// cloudWatchClient gọi CloudWatch Logs API (JSON 1.1, ký SigV4)
+type cloudWatchClient struct {
+	config        CloudWatchConfig
+	stream        string
+	credentials   aws.CredentialsProvider
+	signer        *v4.Signer
+	httpClient    *http.Client
+	streamCreated bool // chỉ goroutine gửi của BatchWriter đọc/ghi
+}
+
+// NewCloudWatchWriter tạo writer gửi log lên log stream <LogStream>/<job>, log stream được tạo ở lần gửi đầu
+func NewCloudWatchWriter(ctx context.Context, cfg CloudWatchConfig, job string, options BatchOptions) (*BatchWriter, error) {
+	if cfg.Region == "" || cfg.LogGroup == "" {
+		return nil, fmt.Errorf("cloudwatch region and log group are required")
+	}
+
+	awsOptions := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" {
+		awsOptions = append(awsOptions, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, awsOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://logs.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.LogStream == "" {
+		cfg.LogStream, _ = os.Hostname()
+		if cfg.LogStream == "" {
+			cfg.LogStream = "unknown"
+		}
+	}
+
+	client := &cloudWatchClient{
+		config:      cfg,
+		stream:      cfg.LogStream + "/" + job,
+		credentials: awsConfig.Credentials,
+		signer:      v4.NewSigner(),
+		httpClient:  &http.Client{Timeout: 10 * time.Second},
+	}
+
+	// Batch của PutLogEvents tối đa 10000 event
+	if options.BatchSize <= 0 || options.BatchSize > cloudWatchMaxBatchEvents {
+		options.BatchSize = options.withDefaults().BatchSize
+	}
+	return newBatchWriter("cloudwatch("+client.stream+")", client.putLogEvents, nil, options), nil
+}
+
+// cloudWatchEvent InputLogEvent của PutLogEvents
+type cloudWatchEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Message   string `json:"message"`
+}
+
+// putLogEvents gửi entries, tách thành nhiều request nếu vượt 1MB
+func (c *cloudWatchClient) putLogEvents(ctx context.Context, entries []logEntry) error {
+	if !c.streamCreated {
+		if err := c.createLogStream(ctx); err != nil {
+			return err
+		}
+		c.streamCreated = true
+	}
+
+	events := make([]cloudWatchEvent, 0, len(entries))
+	size := 0
+	for _, entry := range entries {
+		message := entry.Data
+		if len(message) > cloudWatchMaxEventBytes {
+			message = message[:cloudWatchMaxEventBytes]
+		}
+		if len(message) == 0 {
+			continue
+		}
+		if size+len(message)+cloudWatchEventOverhead > cloudWatchMaxBatchBytes {
+			if err := c.putEvents(ctx, events); err != nil {
+				return err
+			}
+			events, size = events[:0], 0
+		}
+		events = append(events, cloudWatchEvent{Timestamp: entry.Time.UnixMilli(), Message: string(message)})
+		size += len(message) + cloudWatchEventOverhead
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	return c.putEvents(ctx, events)
+}
+
+func (c *cloudWatchClient) putEvents(ctx context.Context, events []cloudWatchEvent) error {
+	err := c.call(ctx, "PutLogEvents", map[string]interface{}{
+		"logGroupName":  c.config.LogGroup,
+		"logStreamName": c.stream,
+		"logEvents":     events,
+	})
+	// Stream bị xóa trong lúc chạy: tạo lại ở lần gửi sau
+	if apiErr, ok := err.(*CloudWatchError); ok && apiErr.Type == "ResourceNotFoundException" {
+		c.streamCreated = false
+	}
+	return err
+}
+
+func (c *cloudWatchClient) createLogStream(ctx context.Context) error {
+	err := c.call(ctx, "CreateLogStream", map[string]interface{}{
+		"logGroupName":  c.config.LogGroup,
+		"logStreamName": c.stream,
+	})
+	if apiErr, ok := err.(*CloudWatchError); ok && apiErr.Type == "ResourceAlreadyExistsException" {
+		return nil
+	}
+	return err
+}
+
+// call gọi action của CloudWatch Logs API
+func (c *cloudWatchClient) call(ctx context.Context, action string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.Endpoint+"/", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "Logs_20140328."+action)
+
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "logs", c.config.Region, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign CloudWatch request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("CloudWatch %s failed: %w", action, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	var errBody struct {
+		Type    string `json:"__type"`
+		Message string `json:"message"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&errBody)
+	// __type có dạng "com.amazonaws.logs#ResourceNotFoundException"
+	if i := strings.LastIndex(errBody.Type, "#"); i >= 0 {
+		errBody.Type = errBody.Type[i+1:]
+	}
+	return &CloudWatchError{StatusCode: resp.StatusCode, Type: errBody.Type, Message: errBody.Message}
+}
+
+// CloudWatchError lỗi CloudWatch Logs API trả về
+type CloudWatchError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *CloudWatchError) Error() string {
+	return fmt.Sprintf("CloudWatch %s (status %d): %s", e.Type, e.StatusCode, e.Message)
+}
@@ -85,32 +85,14 @@ func (dl *DynamicLogger) createLogger(config Config) zerolog.Logger {
 			writers = append(writers, getConsoleWriter(config.PrettyPrint))
 		case "file":
 			if config.LogPath != "" {
-				var fileWriter io.Writer
-				var err error
-
-				if config.DailyRotation {
-					fileWriter, err = getDailyFileWriter(config.LogPath)
-				} else {
-					fileWriter, err = getFileWriter(config.LogPath)
-				}
-
+				fileWriter, err := getFileWriter(config.LogPath, config.rotateOptions())
 				if err == nil {
 					writers = append(writers, fileWriter)
 				}
 			}
 		case "loki":
 			if config.LokiURL != "" {
-				// Extract job name from log path or use default
-				jobName := "apicore"
-				if config.LogPath != "" {
-					// Extract job name from filename (e.g., "exception.log" -> "exception")
-					filename := filepath.Base(config.LogPath)
-					if strings.HasSuffix(filename, ".log") {
-						jobName = strings.TrimSuffix(filename, ".log")
-					}
-				}
-
-				lokiWriter, err := getLokiWriterWithJob(config.LokiURL, jobName)
+				lokiWriter, err := getLokiWriterWithJob(config.LokiURL, jobNameFromPath(config.LogPath))
 				if err == nil {
 					writers = append(writers, lokiWriter)
 				}
@@ -122,14 +104,29 @@ func (dl *DynamicLogger) createLogger(config Config) zerolog.Logger {
 		writers = append(writers, os.Stdout)
 	}
 
+	// Theo level của ModuleApp (đổi được lúc chạy), trừ khi SetJobLogger đặt level riêng
 	multi := zerolog.MultiLevelWriter(writers...)
-	logger := zerolog.New(multi).With().Timestamp().Logger()
+	logger := zerolog.New(newModuleWriter(ModuleApp, multi)).With().Timestamp().Logger()
 
 	if config.EnableCaller {
 		logger = logger.With().Caller().Logger()
 	}
 
-	return logger.Level(level)
+	if config.Level != dl.defaultConfig.Level {
+		logger = logger.Level(level)
+	}
+	return logger
+}
+
+// jobNameFromPath lấy job name từ tên file log (e.g., "exception.log" -> "exception"), mặc định "apicore"
+func jobNameFromPath(logPath string) string {
+	if logPath != "" {
+		filename := filepath.Base(logPath)
+		if strings.HasSuffix(filename, ".log") {
+			return strings.TrimSuffix(filename, ".log")
+		}
+	}
+	return "apicore"
 }
 
 // Global dynamic logger instance
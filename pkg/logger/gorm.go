@@ -0,0 +1,105 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/utils"
+)
+
+// DefaultSlowQueryThreshold query chậm hơn được log ở level warn
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger ghi log của GORM qua module ModuleGORM: câu SQL ở level debug, query chậm ở warn, lỗi ở error.
+// Level theo LOG_LEVEL_GORM nên bật log SQL lúc chạy được mà không restart.
+// Giá trị tham số (email, token, password hash...) không được ghi, SQL giữ nguyên placeholder ($1, ?) trừ khi LogParams.
+type GormLogger struct {
+	SlowThreshold time.Duration
+	LogParams     bool // Ghi SQL kèm giá trị tham số, chỉ dùng ở môi trường dev
+}
+
+var (
+	_ gormlogger.Interface = (*GormLogger)(nil)
+	_ gorm.ParamsFilter    = (*GormLogger)(nil)
+)
+
+// NewGormLogger tạo GORM logger, slowThreshold 0 dùng DefaultSlowQueryThreshold
+func NewGormLogger(slowThreshold time.Duration) *GormLogger {
+	if slowThreshold <= 0 {
+		slowThreshold = DefaultSlowQueryThreshold
+	}
+	return &GormLogger{SlowThreshold: slowThreshold}
+}
+
+// LogMode implements gormlogger.Interface, level theo module ModuleGORM nên bỏ qua level của GORM
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+// ParamsFilter implements gorm.ParamsFilter, bỏ tham số khỏi SQL được log khi không bật LogParams
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.LogParams {
+		return sql, params
+	}
+	return sql, nil
+}
+
+// Info implements gormlogger.Interface
+func (l *GormLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	Module(ModuleGORM).Infof(format, args...)
+}
+
+// Warn implements gormlogger.Interface
+func (l *GormLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	Module(ModuleGORM).Warnf(format, args...)
+}
+
+// Error implements gormlogger.Interface
+func (l *GormLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	Module(ModuleGORM).Errorf(format, args...)
+}
+
+// Trace implements gormlogger.Interface
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+
+	level := zerolog.DebugLevel
+	msg := "query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, msg = zerolog.ErrorLevel, "query failed"
+	case elapsed > l.SlowThreshold:
+		level, msg = zerolog.WarnLevel, "slow query"
+	}
+	if !Enabled(ModuleGORM, level) {
+		return
+	}
+
+	sql, rows := fc()
+	logger := Module(ModuleGORM).Logger()
+	event := logger.WithLevel(level).
+		Str("sql", sql).
+		Int64("rows", rows).
+		Float64("duration_ms", float64(elapsed.Microseconds())/1000)
+	if err != nil && level == zerolog.ErrorLevel {
+		event = event.Err(err)
+	}
+	if reqID := middleware.GetReqID(ctx); reqID != "" {
+		event = event.Str("request_id", reqID)
+	}
+	// Query chậm: route và dòng code gọi query để tìm endpoint thiếu index
+	if level == zerolog.WarnLevel {
+		if rctx := chi.RouteContext(ctx); rctx != nil && rctx.RoutePattern() != "" {
+			event = event.Str("route", rctx.RoutePattern())
+		}
+		event = event.Str("caller", utils.FileWithLineNum()).
+			Float64("threshold_ms", float64(l.SlowThreshold.Microseconds())/1000)
+	}
+	event.Msg(msg)
+}
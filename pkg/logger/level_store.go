@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+)
+
+// DefaultLevelPrefix tiền tố key Redis của RedisLevelStore
+const DefaultLevelPrefix = "log-level:"
+
+// LevelStore level đặt lúc chạy dùng chung giữa các process: admin API/CLI ghi, mọi process đọc qua WatchLevels
+type LevelStore interface {
+	// Set đặt level của module, ttl > 0: tự quay về level theo config sau ttl
+	Set(ctx context.Context, module string, level zerolog.Level, ttl time.Duration) error
+	// Reset bỏ level đặt lúc chạy của module
+	Reset(ctx context.Context, module string) error
+	// Load level đặt lúc chạy của các module (chưa hết hạn)
+	Load(ctx context.Context) (map[string]zerolog.Level, error)
+}
+
+// RedisLevelStore implements LevelStore bằng Redis: mỗi module một key, TTL của key là thời hạn của level
+type RedisLevelStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisLevelStore tạo LevelStore trên Redis, prefix rỗng: DefaultLevelPrefix
+func NewRedisLevelStore(client redis.UniversalClient, prefix string) *RedisLevelStore {
+	if prefix == "" {
+		prefix = DefaultLevelPrefix
+	}
+	return &RedisLevelStore{client: client, prefix: prefix}
+}
+
+// Set implements LevelStore
+func (r *RedisLevelStore) Set(ctx context.Context, module string, level zerolog.Level, ttl time.Duration) error {
+	if err := r.client.Set(ctx, r.prefix+module, level.String(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set log level of %s: %w", module, err)
+	}
+	return nil
+}
+
+// Reset implements LevelStore
+func (r *RedisLevelStore) Reset(ctx context.Context, module string) error {
+	if err := r.client.Del(ctx, r.prefix+module).Err(); err != nil {
+		return fmt.Errorf("failed to reset log level of %s: %w", module, err)
+	}
+	return nil
+}
+
+// Load implements LevelStore, GET từng key vì MGET không chạy được trên Redis Cluster khi key khác slot
+func (r *RedisLevelStore) Load(ctx context.Context) (map[string]zerolog.Level, error) {
+	levels := make(map[string]zerolog.Level)
+	for _, module := range Modules {
+		value, err := r.client.Get(ctx, r.prefix+module).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load log level of %s: %w", module, err)
+		}
+		level, err := ParseLevel(value)
+		if err != nil {
+			continue
+		}
+		levels[module] = level
+	}
+	return levels, nil
+}
+
+// SyncLevels áp dụng level đặt lúc chạy từ store cho process này
+func SyncLevels(ctx context.Context, store LevelStore) error {
+	levels, err := store.Load(ctx)
+	if err != nil {
+		return err
+	}
+	ReplaceOverrides(levels)
+	return nil
+}
+
+// WatchLevels đồng bộ level từ store mỗi interval tới khi ctx bị hủy, lỗi đọc store giữ nguyên level hiện tại
+func WatchLevels(ctx context.Context, store LevelStore, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := SyncLevels(ctx, store); err != nil && ctx.Err() == nil {
+			Module(ModuleApp).Warnf("Failed to sync log levels: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
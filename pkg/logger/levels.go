@@ -0,0 +1,275 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// Module có level riêng (LOG_LEVEL_<MODULE>), chưa cấu hình thì theo level của ModuleApp (LOG_LEVEL)
+const (
+	ModuleApp     = "app"     // Logger, Info/Warnf..., GetJobLogger
+	ModuleRequest = "request" // RequestLogger (HTTP request/response)
+	ModuleGORM    = "gorm"    // Câu SQL, query chậm, lỗi query
+	ModuleCron    = "cron"    // Scheduler (lock, leader, trigger)
+	ModuleSocket  = "socket"  // WebSocket hub (kết nối, room, presence)
+)
+
+// Modules các module có thể đặt level
+var Modules = []string{ModuleApp, ModuleRequest, ModuleGORM, ModuleCron, ModuleSocket}
+
+// ErrUnknownModule module không nằm trong Modules
+var ErrUnknownModule = fmt.Errorf("logger: unknown module, must be one of %v", Modules)
+
+// levelNames level đặt được qua config và API
+var levelNames = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel parse debug, info, warn, error
+func ParseLevel(s string) (zerolog.Level, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if !slices.Contains(levelNames, s) {
+		return zerolog.NoLevel, fmt.Errorf("logger: invalid level %q, must be one of %v", s, levelNames)
+	}
+	return zerolog.ParseLevel(s)
+}
+
+// ModuleLevel level của một module
+type ModuleLevel struct {
+	Module     string `json:"module"`
+	Level      string `json:"level"`                // Level đang áp dụng
+	Configured string `json:"configured,omitempty"` // Theo env, rỗng: theo app
+	Override   string `json:"override,omitempty"`   // Đặt lúc chạy (API/CLI), rỗng: không có
+}
+
+// levelState level cấu hình (env) và level đặt lúc chạy, effective được tính lại mỗi lần thay đổi
+// để Enabled (gọi mỗi dòng log) chỉ đọc một map bất biến
+var levelState = struct {
+	mu         sync.Mutex
+	configured map[string]zerolog.Level
+	overrides  map[string]zerolog.Level
+	effective  atomic.Pointer[map[string]zerolog.Level]
+}{
+	configured: map[string]zerolog.Level{ModuleApp: zerolog.InfoLevel},
+	overrides:  map[string]zerolog.Level{},
+}
+
+func init() {
+	recomputeLevels()
+}
+
+// configureLevels đặt level theo config (Init), giữ level đặt lúc chạy
+func configureLevels(appLevel zerolog.Level, modules map[string]string) error {
+	configured := map[string]zerolog.Level{ModuleApp: appLevel}
+	for module, name := range modules {
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(Modules, module) {
+			return ErrUnknownModule
+		}
+		level, err := ParseLevel(name)
+		if err != nil {
+			return err
+		}
+		configured[module] = level
+	}
+
+	levelState.mu.Lock()
+	levelState.configured = configured
+	levelState.mu.Unlock()
+	recomputeLevels()
+	return nil
+}
+
+// SetLevel đặt level của module lúc chạy, ghi đè config tới khi ResetLevel
+func SetLevel(module string, level zerolog.Level) error {
+	if !slices.Contains(Modules, module) {
+		return ErrUnknownModule
+	}
+	levelState.mu.Lock()
+	levelState.overrides[module] = level
+	levelState.mu.Unlock()
+	recomputeLevels()
+	return nil
+}
+
+// ResetLevel bỏ level đặt lúc chạy, module quay về level theo config
+func ResetLevel(module string) {
+	levelState.mu.Lock()
+	delete(levelState.overrides, module)
+	levelState.mu.Unlock()
+	recomputeLevels()
+}
+
+// ReplaceOverrides thay toàn bộ level đặt lúc chạy (đồng bộ từ nơi lưu chung giữa các process), bỏ module không hợp lệ
+func ReplaceOverrides(overrides map[string]zerolog.Level) {
+	next := make(map[string]zerolog.Level, len(overrides))
+	for module, level := range overrides {
+		if slices.Contains(Modules, module) {
+			next[module] = level
+		}
+	}
+	levelState.mu.Lock()
+	levelState.overrides = next
+	levelState.mu.Unlock()
+	recomputeLevels()
+}
+
+// GetLevel level đang áp dụng của module
+func GetLevel(module string) zerolog.Level {
+	effective := *levelState.effective.Load()
+	if level, ok := effective[module]; ok {
+		return level
+	}
+	return effective[ModuleApp]
+}
+
+// Enabled module có ghi log ở level này không
+func Enabled(module string, level zerolog.Level) bool {
+	return level >= GetLevel(module)
+}
+
+// Levels level của tất cả module
+func Levels() []ModuleLevel {
+	levelState.mu.Lock()
+	defer levelState.mu.Unlock()
+
+	levels := make([]ModuleLevel, 0, len(Modules))
+	for _, module := range Modules {
+		item := ModuleLevel{Module: module, Level: GetLevel(module).String()}
+		if level, ok := levelState.configured[module]; ok {
+			item.Configured = level.String()
+		}
+		if level, ok := levelState.overrides[module]; ok {
+			item.Override = level.String()
+		}
+		levels = append(levels, item)
+	}
+	return levels
+}
+
+// recomputeLevels tính level áp dụng cho mỗi module. Global level của zerolog là level thấp nhất
+// để event của module đang debug không bị chặn trước khi tới moduleWriter.
+func recomputeLevels() {
+	levelState.mu.Lock()
+	defer levelState.mu.Unlock()
+
+	effective := make(map[string]zerolog.Level, len(Modules))
+	app := levelState.configured[ModuleApp]
+	if level, ok := levelState.overrides[ModuleApp]; ok {
+		app = level
+	}
+	lowest := app
+	for _, module := range Modules {
+		level := app
+		if configured, ok := levelState.configured[module]; ok && module != ModuleApp {
+			level = configured
+		}
+		if override, ok := levelState.overrides[module]; ok {
+			level = override
+		}
+		effective[module] = level
+		lowest = min(lowest, level)
+	}
+	levelState.effective.Store(&effective)
+	zerolog.SetGlobalLevel(lowest)
+}
+
+// moduleWriter bỏ event dưới level của module
+type moduleWriter struct {
+	module string
+	w      zerolog.LevelWriter
+}
+
+func newModuleWriter(module string, w io.Writer) moduleWriter {
+	lw, ok := w.(zerolog.LevelWriter)
+	if !ok {
+		lw = zerolog.LevelWriterAdapter{Writer: w}
+	}
+	return moduleWriter{module: module, w: lw}
+}
+
+// Write implements io.Writer
+func (mw moduleWriter) Write(p []byte) (int, error) {
+	return mw.w.Write(p)
+}
+
+// WriteLevel implements zerolog.LevelWriter
+func (mw moduleWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level != zerolog.NoLevel && !Enabled(mw.module, level) {
+		return len(p), nil
+	}
+	return mw.w.WriteLevel(level, p)
+}
+
+// moduleLoggers logger của từng module, tạo lại mỗi lần Init (cùng output với Logger)
+var moduleLoggers atomic.Pointer[map[string]zerolog.Logger]
+
+// buildModuleLoggers tạo logger cho các module ghi ra w
+func buildModuleLoggers(w io.Writer, caller bool) {
+	loggers := make(map[string]zerolog.Logger, len(Modules))
+	for _, module := range Modules {
+		logger := zerolog.New(newModuleWriter(module, w)).With().Timestamp().Str("module", module).Logger()
+		if caller {
+			logger = logger.With().Caller().Logger()
+		}
+		loggers[module] = logger
+	}
+	moduleLoggers.Store(&loggers)
+}
+
+// ModuleLogger log của một module, level theo GetLevel(module) nên đổi được lúc chạy.
+// Implement logger interface của pkg/cron và pkg/socket.
+type ModuleLogger struct {
+	module string
+}
+
+// Module logger của module (ModuleGORM, ModuleCron, ModuleSocket...)
+func Module(name string) ModuleLogger {
+	return ModuleLogger{module: name}
+}
+
+// Logger zerolog logger của module, field "module" là tên module
+func (m ModuleLogger) Logger() zerolog.Logger {
+	if loggers := moduleLoggers.Load(); loggers != nil {
+		if logger, ok := (*loggers)[m.module]; ok {
+			return logger
+		}
+	}
+	// Chưa Init: ghi ra stdout
+	return zerolog.New(newModuleWriter(m.module, os.Stdout)).With().Timestamp().Str("module", m.module).Logger()
+}
+
+func (m ModuleLogger) logf(level zerolog.Level, format string, args ...interface{}) {
+	if !Enabled(m.module, level) {
+		return
+	}
+	logger := m.Logger()
+	logger.WithLevel(level).Msgf(format, args...)
+}
+
+// Debugf log level debug
+func (m ModuleLogger) Debugf(format string, args ...interface{}) {
+	m.logf(zerolog.DebugLevel, format, args...)
+}
+
+// Infof log level info
+func (m ModuleLogger) Infof(format string, args ...interface{}) {
+	m.logf(zerolog.InfoLevel, format, args...)
+}
+
+// Warnf log level warn
+func (m ModuleLogger) Warnf(format string, args ...interface{}) {
+	m.logf(zerolog.WarnLevel, format, args...)
+}
+
+// Errorf log level error
+func (m ModuleLogger) Errorf(format string, args ...interface{}) {
+	m.logf(zerolog.ErrorLevel, format, args...)
+}
@@ -2,7 +2,9 @@ package logger
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +15,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/pkgerrors"
 )
@@ -32,13 +36,33 @@ var Manager *LoggerManager
 
 // Config cấu hình cho logger
 type Config struct {
-	Level         string // debug, info, warn, error
-	Output        string // console, file, loki (có thể kết hợp: "console,file,loki")
-	LogPath       string // đường dẫn thư mục chứa logs
-	LokiURL       string // Loki server URL (ví dụ: http://localhost:3100)
-	EnableCaller  bool   // hiển thị file:line
-	PrettyPrint   bool   // format đẹp cho console
-	DailyRotation bool   // bật daily rotation cho file logs
+	Level         string            // debug, info, warn, error
+	ModuleLevels  map[string]string // level riêng theo module (request, gorm, cron, socket), rỗng: theo Level
+	Output        string            // console, file, loki, cloudwatch, syslog (có thể kết hợp: "console,file,loki")
+	LogPath       string            // đường dẫn thư mục chứa logs
+	LokiURL       string            // Loki server URL (ví dụ: http://localhost:3100)
+	EnableCaller  bool              // hiển thị file:line
+	PrettyPrint   bool              // format đẹp cho console
+	DailyRotation bool              // bật daily rotation cho file logs
+	MaxSizeMB     int               // rotate file log khi vượt kích thước (MB), 0: không giới hạn
+	MaxBackups    int               // số file backup giữ lại, 0: giữ tất cả
+	MaxAgeDays    int               // xóa backup cũ hơn số ngày, 0: không xóa
+	Compress      bool              // nén gzip file backup
+	SampleRules   []SampleRule      // tỷ lệ ghi request log thành công theo route, rỗng: ghi tất cả
+	CloudWatch    CloudWatchConfig  // dùng khi Output có cloudwatch
+	Syslog        SyslogConfig      // dùng khi Output có syslog
+	SinkBatch     BatchOptions      // batch và buffer của cloudwatch, syslog
+}
+
+// rotateOptions cấu hình rotation của file writers theo Config
+func (c Config) rotateOptions() RotateOptions {
+	return RotateOptions{
+		Daily:      c.DailyRotation,
+		MaxSize:    int64(c.MaxSizeMB) * 1024 * 1024,
+		MaxBackups: c.MaxBackups,
+		MaxAge:     time.Duration(c.MaxAgeDays) * 24 * time.Hour,
+		Compress:   c.Compress,
+	}
 }
 
 // Init khởi tạo logger với config
@@ -54,7 +78,14 @@ func Init(cfg Config) error {
 	if err != nil {
 		level = zerolog.InfoLevel
 	}
-	zerolog.SetGlobalLevel(level)
+	if err := configureLevels(level, cfg.ModuleLevels); err != nil {
+		return err
+	}
+	setSampleRules(cfg.SampleRules)
+
+	// Sink của lần Init trước gửi nốt buffer trước khi bị thay
+	previousSinks := takeSinks()
+	defer closeSinks(previousSinks)
 
 	// Setup output writers - parse comma-separated outputs
 	var writers []io.Writer
@@ -67,16 +98,7 @@ func Init(cfg Config) error {
 		case "console":
 			writers = append(writers, getConsoleWriter(cfg.PrettyPrint))
 		case "file":
-			var fileWriter io.Writer
-			var err error
-
-			appLogPath := cfg.LogPath + "/app.log"
-			if cfg.DailyRotation {
-				fileWriter, err = getDailyFileWriter(appLogPath)
-			} else {
-				fileWriter, err = getFileWriter(appLogPath)
-			}
-
+			fileWriter, err := getFileWriter(cfg.LogPath+"/app.log", cfg.rotateOptions())
 			if err != nil {
 				return fmt.Errorf("failed to create file writer: %w", err)
 			}
@@ -90,6 +112,12 @@ func Init(cfg Config) error {
 				return fmt.Errorf("failed to create loki writer: %w", err)
 			}
 			writers = append(writers, lokiWriter)
+		case "cloudwatch", "syslog":
+			sink, err := newSinkWriter(cfg, output, "apicore")
+			if err != nil {
+				return err
+			}
+			writers = append(writers, sink)
 		}
 	}
 
@@ -101,13 +129,16 @@ func Init(cfg Config) error {
 	multi := zerolog.MultiLevelWriter(writers...)
 
 	// Create logger
-	Logger = zerolog.New(multi).With().Timestamp().Logger()
+	Logger = zerolog.New(newModuleWriter(ModuleApp, multi)).With().Timestamp().Logger()
 
 	// Enable caller if needed
 	if cfg.EnableCaller {
 		Logger = Logger.With().Caller().Logger()
 	}
 
+	// Logger của gorm, cron, socket ghi cùng output, level riêng
+	buildModuleLoggers(multi, cfg.EnableCaller)
+
 	// Create request logger with separate Loki writer (job="request")
 	var requestWriters []io.Writer
 	for _, output := range outputs {
@@ -125,17 +156,9 @@ func Init(cfg Config) error {
 				requestLogPath = filepath.Join(dir, "request.log")
 			}
 
-			var fileWriter io.Writer
-			var err error
-
 			fmt.Printf("🔍 Creating RequestLogger file writer: path=%s, dailyRotation=%v\n", requestLogPath, cfg.DailyRotation)
 
-			if cfg.DailyRotation {
-				fileWriter, err = getDailyFileWriter(requestLogPath)
-			} else {
-				fileWriter, err = getFileWriter(requestLogPath)
-			}
-
+			fileWriter, err := getFileWriter(requestLogPath, cfg.rotateOptions())
 			if err != nil {
 				return fmt.Errorf("failed to create request file writer: %w", err)
 			}
@@ -150,6 +173,12 @@ func Init(cfg Config) error {
 				return fmt.Errorf("failed to create request loki writer: %w", err)
 			}
 			requestWriters = append(requestWriters, lokiWriter)
+		case "cloudwatch", "syslog":
+			sink, err := newSinkWriter(cfg, output, "request")
+			if err != nil {
+				return err
+			}
+			requestWriters = append(requestWriters, sink)
 		}
 	}
 
@@ -158,7 +187,7 @@ func Init(cfg Config) error {
 	}
 
 	multiRequest := zerolog.MultiLevelWriter(requestWriters...)
-	RequestLogger = zerolog.New(multiRequest).With().Timestamp().Logger()
+	RequestLogger = zerolog.New(newModuleWriter(ModuleRequest, multiRequest)).With().Timestamp().Logger()
 
 	if cfg.EnableCaller {
 		RequestLogger = RequestLogger.With().Caller().Logger()
@@ -182,25 +211,12 @@ func getConsoleWriter(prettyPrint bool) io.Writer {
 	return os.Stdout
 }
 
-// getFileWriter tạo file writer
-func getFileWriter(filePath string) (io.Writer, error) {
+// getFileWriter tạo file writer, rotate theo ngày/kích thước theo options
+func getFileWriter(filePath string, options RotateOptions) (io.Writer, error) {
 	if filePath == "" {
 		filePath = "storages/logs/app.log"
 	}
-
-	// Tạo directory nếu chưa tồn tại
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return nil, err
-	}
-
-	// Mở file log
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		return nil, err
-	}
-
-	return file, nil
+	return NewRotatingWriter(filePath, options)
 }
 
 // getLokiWriter tạo Loki writer với job="apicore"
@@ -228,6 +244,66 @@ func getLokiWriterWithJob(lokiURL, job string) (io.Writer, error) {
 	}, nil
 }
 
+// sinks các BatchWriter đang chạy (cloudwatch, syslog), Close gửi nốt buffer khi shutdown
+var sinks struct {
+	mu      sync.Mutex
+	writers []*BatchWriter
+}
+
+// newSinkWriter tạo writer cloudwatch/syslog cho job và đăng ký để Close
+func newSinkWriter(cfg Config, output, job string) (*BatchWriter, error) {
+	var (
+		writer *BatchWriter
+		err    error
+	)
+	switch output {
+	case "cloudwatch":
+		writer, err = NewCloudWatchWriter(context.Background(), cfg.CloudWatch, job, cfg.SinkBatch)
+	case "syslog":
+		writer, err = NewSyslogWriter(cfg.Syslog, job, cfg.SinkBatch)
+	default:
+		return nil, fmt.Errorf("unknown log sink: %s", output)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s writer: %w", output, err)
+	}
+
+	sinks.mu.Lock()
+	sinks.writers = append(sinks.writers, writer)
+	sinks.mu.Unlock()
+	return writer, nil
+}
+
+func takeSinks() []*BatchWriter {
+	sinks.mu.Lock()
+	defer sinks.mu.Unlock()
+	writers := sinks.writers
+	sinks.writers = nil
+	return writers
+}
+
+func closeSinks(writers []*BatchWriter) {
+	if len(writers) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for _, writer := range writers {
+		writer.Close(ctx)
+	}
+}
+
+// Close gửi nốt log còn trong buffer của cloudwatch/syslog rồi dừng các sink, gọi sau cùng khi shutdown
+func Close(ctx context.Context) error {
+	var errs []error
+	for _, writer := range takeSinks() {
+		if err := writer.Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // lokiWriter implements io.Writer interface for Loki
 type lokiWriter struct {
 	lokiURL    string
@@ -327,29 +403,47 @@ func Warnf(format string, v ...interface{}) {
 	Logger.Warn().Msgf(format, v...)
 }
 
-// Error log error message
+// Error log error message, gửi kèm tới error reporter (Sentry) nếu có
 func Error(msg string) {
 	Logger.Error().Msg(msg)
+	errorreport.CaptureMessage(context.Background(), errorreport.LevelError, msg)
 }
 
-// Errorf log error message with format
+// Errorf log error message with format, gửi kèm tới error reporter (Sentry) nếu có
 func Errorf(format string, v ...interface{}) {
-	Logger.Error().Msgf(format, v...)
+	msg := fmt.Sprintf(format, v...)
+	Logger.Error().Msg(msg)
+	errorreport.CaptureMessage(context.Background(), errorreport.LevelError, msg)
 }
 
-// ErrorWithErr log error with error object
+// ErrorWithErr log error with error object, gửi kèm tới error reporter (Sentry) nếu có
 func ErrorWithErr(err error, msg string) {
 	Logger.Error().Err(err).Msg(msg)
+	errorreport.Capture(context.Background(), err, nil, map[string]interface{}{"message": msg})
 }
 
 // Fatal log fatal message and exit
 func Fatal(msg string) {
+	reportFatal(msg)
 	Logger.Fatal().Msg(msg)
 }
 
 // Fatalf log fatal message with format and exit
 func Fatalf(format string, v ...interface{}) {
-	Logger.Fatal().Msgf(format, v...)
+	msg := fmt.Sprintf(format, v...)
+	reportFatal(msg)
+	Logger.Fatal().Msg(msg)
+}
+
+// reportFatal gửi lỗi tới error reporter và chờ gửi xong trước khi process thoát
+func reportFatal(msg string) {
+	if !errorreport.Enabled() {
+		return
+	}
+	errorreport.CaptureMessage(context.Background(), errorreport.LevelFatal, msg)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = errorreport.Flush(ctx)
 }
 
 // WithFields tạo logger với fields
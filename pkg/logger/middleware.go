@@ -1,40 +1,145 @@
 package logger
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/rs/zerolog"
 )
 
-// responseWriter wraps http.ResponseWriter để capture response
+// maxLoggedBodySize body lớn hơn không được log (chỉ log size)
+const maxLoggedBodySize = 10000
+
+// responseWriter wraps http.ResponseWriter để capture status, size và body (khi cần log).
+// Body chỉ được giữ khi captureBody trả true lúc ghi byte đầu tiên (content type dạng text, Content-Length không vượt
+// maxBody) và bị bỏ khi vượt maxBody, nên file download/export/stream đi thẳng xuống writer gốc không tốn thêm bộ nhớ.
 type responseWriter struct {
 	http.ResponseWriter
-	statusCode int
-	body       *bytes.Buffer
+	statusCode  int
+	wroteHeader bool
+	size        int64
+	body        *bytes.Buffer // nil: không capture (hoặc đã vượt maxBody)
+	truncated   bool          // body vượt maxBody
+	maxBody     int
+	captureBody func() bool // middleware quyết định theo verbosity/config, nil: luôn capture nếu content type phù hợp
+	decided     bool
 }
 
-func newResponseWriter(w http.ResponseWriter) *responseWriter {
+// newResponseWriter tạo writer capture tối đa maxBody byte body, maxBody <= 0: không capture body
+func newResponseWriter(w http.ResponseWriter, maxBody int, captureBody func() bool) *responseWriter {
 	return &responseWriter{
 		ResponseWriter: w,
 		statusCode:     http.StatusOK,
-		body:           &bytes.Buffer{},
+		maxBody:        maxBody,
+		captureBody:    captureBody,
+	}
+}
+
+// decide chọn có capture body không, chạy một lần trước khi header được gửi (Content-Type đã được handler đặt)
+func (rw *responseWriter) decide() {
+	if rw.decided {
+		return
+	}
+	rw.decided = true
+
+	if rw.maxBody <= 0 || (rw.captureBody != nil && !rw.captureBody()) {
+		return
+	}
+	header := rw.Header()
+	if skipBodyCapture(header.Get("Content-Type")) {
+		return
 	}
+	if length, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil && length > int64(rw.maxBody) {
+		rw.truncated = true
+		return
+	}
+	rw.body = &bytes.Buffer{}
 }
 
 func (rw *responseWriter) WriteHeader(code int) {
-	rw.statusCode = code
+	rw.decide()
+	if !rw.wroteHeader {
+		rw.statusCode = code
+		rw.wroteHeader = true
+	}
 	rw.ResponseWriter.WriteHeader(code)
 }
 
 func (rw *responseWriter) Write(b []byte) (int, error) {
-	// Write to buffer for logging
+	rw.decide()
+	rw.wroteHeader = true
+	rw.capture(b)
+
+	n, err := rw.ResponseWriter.Write(b)
+	rw.size += int64(n)
+	return n, err
+}
+
+// capture ghi b vào body, vượt maxBody thì bỏ body đã giữ
+func (rw *responseWriter) capture(b []byte) {
+	if rw.body == nil {
+		return
+	}
+	if rw.body.Len()+len(b) > rw.maxBody {
+		rw.body, rw.truncated = nil, true
+		return
+	}
 	rw.body.Write(b)
-	// Write to actual response
-	return rw.ResponseWriter.Write(b)
+}
+
+// ReadFrom giữ được sendfile của writer gốc (http.ServeContent, io.Copy) khi không capture body
+func (rw *responseWriter) ReadFrom(src io.Reader) (int64, error) {
+	rw.decide()
+	rw.wroteHeader = true
+	if readerFrom, ok := rw.ResponseWriter.(io.ReaderFrom); ok && rw.body == nil {
+		n, err := readerFrom.ReadFrom(src)
+		rw.size += n
+		return n, err
+	}
+	return io.Copy(writerOnly{rw}, src)
+}
+
+// writerOnly ẩn ReadFrom để io.Copy không gọi lại responseWriter.ReadFrom
+type writerOnly struct {
+	io.Writer
+}
+
+// Unwrap cho http.ResponseController truy cập writer gốc
+func (rw *responseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
+
+// Flush hỗ trợ streaming response (SSE, export ghi từng phần)
+func (rw *responseWriter) Flush() {
+	rw.decide()
+	rw.wroteHeader = true
+	if flusher, ok := rw.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hỗ trợ WebSocket upgrade
+func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rw.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// bodyLogged body đã capture đầy đủ để ghi vào log
+func (rw *responseWriter) bodyLogged() bool {
+	return rw.body != nil && rw.body.Len() > 0
 }
 
 // Middleware tạo HTTP logging middleware với đầy đủ request/response
@@ -52,25 +157,40 @@ func Middleware() func(next http.Handler) http.Handler {
 			// Get request ID
 			reqID := middleware.GetReqID(r.Context())
 
-			// Read request body
+			// Read request body (không đọc body nhị phân như chunk upload, có thể rất lớn)
 			var requestBody []byte
-			if r.Body != nil {
+			binaryRequest := isBinaryContent(r.Header.Get("Content-Type"))
+			if r.Body != nil && !binaryRequest {
 				requestBody, _ = io.ReadAll(r.Body)
 				r.Body = io.NopCloser(bytes.NewBuffer(requestBody))
 			}
 
-			// Wrap response writer
-			ww := newResponseWriter(w)
+			// Route group có thể hạ mức log (basic/none) qua WithVerbosity
+			holder := &verbosityHolder{value: VerbosityFull}
+			r = r.WithContext(context.WithValue(r.Context(), verbosityKey{}, holder))
+
+			// Wrap response writer, chỉ giữ body khi verbosity full (group đã đặt verbosity trước khi handler ghi response)
+			ww := newResponseWriter(w, maxLoggedBodySize, func() bool { return holder.value == VerbosityFull })
 
 			// Process request
 			next.ServeHTTP(ww, r)
 
+			if holder.value == VerbosityNone {
+				return
+			}
+
+			// Sampling theo route (LOG_SAMPLE_ROUTES), request lỗi luôn được ghi
+			statusCode := ww.statusCode
+			keep, sampleRate := sampled(r.Method, routePattern(r), statusCode)
+			if !keep {
+				return
+			}
+
 			// Calculate duration
 			duration := time.Since(start)
 
 			// Determine log level based on status code
 			var logEvent *zerolog.Event
-			statusCode := ww.statusCode
 
 			if statusCode >= 500 {
 				logEvent = RequestLogger.Error()
@@ -98,8 +218,17 @@ func Middleware() func(next http.Handler) http.Handler {
 				Str("accept", r.Header.Get("Accept")).
 				Str("referer", r.Header.Get("Referer"))
 
+			// Mỗi dòng đại diện cho 1/sample_rate request khi đếm trên Loki
+			if sampleRate < 1 {
+				logEvent = logEvent.Float64("sample_rate", sampleRate)
+			}
+
 			// Add request body if present and not too large
-			if len(requestBody) > 0 && len(requestBody) < 10000 {
+			if holder.value == VerbosityBasic {
+				// Basic: không log body
+			} else if binaryRequest {
+				logEvent = logEvent.Int64("request_size", r.ContentLength)
+			} else if len(requestBody) > 0 && len(requestBody) < 10000 {
 				logEvent = logEvent.
 					Str("request_body", string(requestBody)).
 					Int("request_size", len(requestBody))
@@ -107,18 +236,12 @@ func Middleware() func(next http.Handler) http.Handler {
 				logEvent = logEvent.Int("request_size", len(requestBody))
 			}
 
-			// Add response body if present and not too large and not binary
-			responseContentType := w.Header().Get("Content-Type")
-			if ww.body.Len() > 0 && !isBinaryContent(responseContentType) {
-				if ww.body.Len() < 10000 {
-					logEvent = logEvent.
-						Str("response_body", ww.body.String()).
-						Int("response_size", ww.body.Len())
-				} else {
-					logEvent = logEvent.Int("response_size", ww.body.Len())
-				}
-			} else if ww.body.Len() > 0 {
-				logEvent = logEvent.Int("response_size", ww.body.Len())
+			// Add response body if captured (text, không quá maxLoggedBodySize), còn lại chỉ log size
+			if ww.bodyLogged() {
+				logEvent = logEvent.Str("response_body", ww.body.String())
+			}
+			if ww.size > 0 || holder.value == VerbosityBasic {
+				logEvent = logEvent.Int64("response_size", ww.size)
 			}
 
 			// Add response content type
@@ -137,6 +260,14 @@ func Middleware() func(next http.Handler) http.Handler {
 	}
 }
 
+// routePattern route pattern của chi (vd /api/v1/users/{id}), path nếu request không khớp route nào
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		return rctx.RoutePattern()
+	}
+	return r.URL.Path
+}
+
 // SimpleMiddleware tạo middleware đơn giản hơn (không log body)
 func SimpleMiddleware() func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -146,8 +277,8 @@ func SimpleMiddleware() func(next http.Handler) http.Handler {
 			// Get request ID
 			reqID := middleware.GetReqID(r.Context())
 
-			// Wrap response writer
-			ww := newResponseWriter(w)
+			// Wrap response writer (không capture body)
+			ww := newResponseWriter(w, 0, nil)
 
 			// Process request
 			next.ServeHTTP(ww, r)
@@ -238,8 +369,12 @@ func MiddlewareWithConfig(config MiddlewareConfig) func(next http.Handler) http.
 				r.Body = io.NopCloser(bytes.NewBuffer(requestBody))
 			}
 
-			// Wrap response writer
-			ww := newResponseWriter(w)
+			// Wrap response writer, chỉ giữ body khi cần log
+			maxBody := 0
+			if config.LogResponseBody {
+				maxBody = config.MaxBodySize
+			}
+			ww := newResponseWriter(w, maxBody, nil)
 
 			// Process request
 			next.ServeHTTP(ww, r)
@@ -292,20 +427,14 @@ func MiddlewareWithConfig(config MiddlewareConfig) func(next http.Handler) http.
 				}
 			}
 
-			// Add response body if configured and not binary
-			responseContentType := w.Header().Get("Content-Type")
-			if config.LogResponseBody && ww.body.Len() > 0 && !isBinaryContent(responseContentType) {
-				if ww.body.Len() < config.MaxBodySize {
-					logEvent = logEvent.
-						Str("response_body", ww.body.String()).
-						Int("response_size", ww.body.Len())
-				} else {
-					logEvent = logEvent.
-						Int("response_size", ww.body.Len()).
-						Str("response_body", "Body too large to log")
+			// Add response body if configured and captured (không phải binary/stream)
+			if config.LogResponseBody && ww.size > 0 {
+				if ww.bodyLogged() {
+					logEvent = logEvent.Str("response_body", ww.body.String())
+				} else if ww.truncated {
+					logEvent = logEvent.Str("response_body", "Body too large to log")
 				}
-			} else if config.LogResponseBody && ww.body.Len() > 0 {
-				logEvent = logEvent.Int("response_size", ww.body.Len())
+				logEvent = logEvent.Int64("response_size", ww.size)
 			}
 
 			// Add response content type
@@ -324,8 +453,20 @@ func MiddlewareWithConfig(config MiddlewareConfig) func(next http.Handler) http.
 	}
 }
 
+// skipBodyCapture response không giữ body để log: binary (file download, export) hoặc stream (SSE)
+func skipBodyCapture(contentType string) bool {
+	return isBinaryContent(contentType) || mediaType(contentType) == "text/event-stream"
+}
+
+// mediaType bỏ tham số (charset, boundary) của Content-Type
+func mediaType(contentType string) string {
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
 // isBinaryContent checks if content type is binary
 func isBinaryContent(contentType string) bool {
+	contentType = mediaType(contentType)
 	binaryTypes := []string{
 		"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", // Excel
 		"application/vnd.ms-excel", // Excel
@@ -337,6 +478,8 @@ func isBinaryContent(contentType string) bool {
 		"application/octet-stream", // Binary
 		"application/x-",           // Binary applications
 		"text/csv",                 // CSV (can be large)
+
+		"application/offset+octet-stream", // Resumable upload chunk (tus)
 	}
 
 	for _, binaryType := range binaryTypes {
@@ -0,0 +1,283 @@
+package logger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RotateOptions cấu hình rotation của file log
+type RotateOptions struct {
+	Daily      bool          // Mỗi ngày một file: app-2006-01-02.log
+	MaxSize    int64         // Bytes, file vượt quá thì đổi tên thành backup (app.1.log) và mở file mới, 0: không giới hạn
+	MaxBackups int           // Số file backup giữ lại (mới nhất), 0: giữ tất cả
+	MaxAge     time.Duration // Xóa backup cũ hơn, 0: không xóa theo tuổi
+	Compress   bool          // Nén gzip file backup (app.1.log.gz)
+}
+
+// RotatingWriter ghi log vào file, rotate theo ngày và/hoặc kích thước.
+// Backup gồm file đã rotate theo kích thước và file của các ngày trước, được nén và dọn theo
+// MaxBackups/MaxAge trong goroutine nền (không chặn Write).
+type RotatingWriter struct {
+	basePath string
+	options  RotateOptions
+
+	mu      sync.Mutex
+	current *os.File
+	path    string // File đang ghi
+	date    string // Ngày của file đang ghi (Daily)
+	size    int64
+
+	millMu sync.Mutex // Một lượt nén/dọn tại một thời điểm
+	millWg sync.WaitGroup
+}
+
+// DailyWriter writes logs to daily rotated files.
+//
+// Deprecated: dùng RotatingWriter với RotateOptions.Daily.
+type DailyWriter = RotatingWriter
+
+// NewDailyWriter creates a new daily writer
+func NewDailyWriter(basePath string) (*RotatingWriter, error) {
+	return NewRotatingWriter(basePath, RotateOptions{Daily: true})
+}
+
+// NewRotatingWriter tạo writer ghi vào basePath (Daily: thêm ngày vào tên file)
+func NewRotatingWriter(basePath string, options RotateOptions) (*RotatingWriter, error) {
+	// Tạo directory nếu chưa tồn tại
+	if err := os.MkdirAll(filepath.Dir(basePath), 0755); err != nil {
+		return nil, err
+	}
+
+	rw := &RotatingWriter{basePath: basePath, options: options}
+	if err := rw.open(); err != nil {
+		return nil, err
+	}
+	// Dọn backup còn lại từ lần chạy trước
+	rw.mill()
+	return rw, nil
+}
+
+// Write implements io.Writer interface
+func (rw *RotatingWriter) Write(p []byte) (n int, err error) {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+
+	if rw.current == nil {
+		return 0, os.ErrClosed
+	}
+
+	// Sang ngày mới: file của ngày cũ trở thành backup
+	if rw.options.Daily && rw.date != time.Now().Format("2006-01-02") {
+		if err := rw.open(); err != nil {
+			return 0, err
+		}
+		rw.mill()
+	}
+	if rw.options.MaxSize > 0 && rw.size > 0 && rw.size+int64(len(p)) > rw.options.MaxSize {
+		if err := rw.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = rw.current.Write(p)
+	rw.size += int64(n)
+	return n, err
+}
+
+// Close đóng file đang ghi, chờ lượt nén/dọn backup đang chạy
+func (rw *RotatingWriter) Close() error {
+	rw.mu.Lock()
+	var err error
+	if rw.current != nil {
+		err = rw.current.Close()
+		rw.current = nil
+	}
+	rw.mu.Unlock()
+
+	rw.millWg.Wait()
+	return err
+}
+
+// Rotate đổi file đang ghi thành backup ngay (vd khi nhận SIGHUP)
+func (rw *RotatingWriter) Rotate() error {
+	rw.mu.Lock()
+	defer rw.mu.Unlock()
+	return rw.rotate()
+}
+
+// open mở (append) file của ngày hiện tại
+func (rw *RotatingWriter) open() error {
+	if rw.current != nil {
+		rw.current.Close()
+		rw.current = nil
+	}
+
+	path := rw.basePath
+	if rw.options.Daily {
+		rw.date = time.Now().Format("2006-01-02")
+		ext := filepath.Ext(rw.basePath)
+		path = fmt.Sprintf("%s-%s%s", strings.TrimSuffix(rw.basePath, ext), rw.date, ext)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	rw.current, rw.path, rw.size = file, path, info.Size()
+	return nil
+}
+
+// rotate đổi tên file đang ghi thành <tên>.<n><ext> (n lớn hơn là mới hơn) rồi mở file mới
+func (rw *RotatingWriter) rotate() error {
+	if rw.current != nil {
+		rw.current.Close()
+		rw.current = nil
+	}
+
+	ext := filepath.Ext(rw.path)
+	stem := strings.TrimSuffix(rw.path, ext)
+	index := 1
+	for _, backup := range rw.backups() {
+		if backup.stem == filepath.Base(stem) && backup.index >= index {
+			index = backup.index + 1
+		}
+	}
+	if err := os.Rename(rw.path, fmt.Sprintf("%s.%d%s", stem, index, ext)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	if err := rw.open(); err != nil {
+		return err
+	}
+	rw.mill()
+	return nil
+}
+
+// backupFile file log không còn được ghi
+type backupFile struct {
+	path    string
+	stem    string // Tên file gốc không có phần mở rộng: app hoặc app-2006-01-02
+	index   int    // Số thứ tự rotate theo kích thước, 0: file của ngày trước
+	gzipped bool
+	modTime time.Time
+}
+
+// backups các backup của basePath, mới nhất trước
+func (rw *RotatingWriter) backups() []backupFile {
+	dir := filepath.Dir(rw.basePath)
+	ext := filepath.Ext(rw.basePath)
+	name := strings.TrimSuffix(filepath.Base(rw.basePath), ext)
+	pattern := regexp.MustCompile(`^(` + regexp.QuoteMeta(name) + `(?:-\d{4}-\d{2}-\d{2})?)(?:\.(\d+))?` + regexp.QuoteMeta(ext) + `(\.gz)?$`)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var files []backupFile
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		match := pattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil || path == rw.path {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		index, _ := strconv.Atoi(match[2])
+		files = append(files, backupFile{
+			path:    path,
+			stem:    match[1],
+			index:   index,
+			gzipped: match[3] != "",
+			modTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.After(files[j].modTime)
+	})
+	return files
+}
+
+// mill nén và dọn backup trong goroutine nền
+func (rw *RotatingWriter) mill() {
+	if !rw.options.Compress && rw.options.MaxBackups == 0 && rw.options.MaxAge == 0 {
+		return
+	}
+
+	rw.millWg.Add(1)
+	go func() {
+		defer rw.millWg.Done()
+		rw.millMu.Lock()
+		defer rw.millMu.Unlock()
+
+		rw.mu.Lock()
+		files := rw.backups()
+		rw.mu.Unlock()
+
+		for i, file := range files {
+			expired := rw.options.MaxAge > 0 && time.Since(file.modTime) > rw.options.MaxAge
+			if (rw.options.MaxBackups > 0 && i >= rw.options.MaxBackups) || expired {
+				if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+					fmt.Fprintf(os.Stderr, "logger: failed to remove log backup %s: %v\n", file.path, err)
+				}
+				continue
+			}
+			if rw.options.Compress && !file.gzipped {
+				if err := compressFile(file.path, file.modTime); err != nil {
+					fmt.Fprintf(os.Stderr, "logger: failed to compress log backup %s: %v\n", file.path, err)
+				}
+			}
+		}
+	}()
+}
+
+// compressFile nén path thành path.gz (giữ thời gian sửa đổi để retention tính đúng) rồi xóa file gốc
+func compressFile(path string, modTime time.Time) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		dst.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+
+	os.Chtimes(path+".gz", modTime, modTime)
+	src.Close()
+	return os.Remove(path)
+}
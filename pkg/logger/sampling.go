@@ -0,0 +1,123 @@
+package logger
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// SampleRule tỷ lệ ghi log request thành công (status < 400) của một route, request lỗi luôn được ghi
+type SampleRule struct {
+	Method string  // GET, POST... rỗng: mọi method
+	Route  string  // Route pattern của chi (vd /api/v1/users/{id}), kết thúc bằng * thì khớp theo prefix
+	Rate   float64 // 0-1, 0: không ghi
+}
+
+// matches route khớp rule: khớp chính xác hoặc theo prefix với rule kết thúc bằng *
+func (r SampleRule) matches(method, route string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Route, "*"); ok {
+		return strings.HasPrefix(route, prefix)
+	}
+	return r.Route == route
+}
+
+// ParseSampleRules parse danh sách rule dạng "[METHOD ]ROUTE=RATE" phân cách bằng dấu phẩy,
+// vd "GET /healthz=0.01,GET /api/v1/users=0.05,/status=0.1"
+func ParseSampleRules(s string) ([]SampleRule, error) {
+	var rules []SampleRule
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		target, rateStr, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid sample rule %q, expected [METHOD ]ROUTE=RATE", item)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("invalid sample rate in %q, must be between 0 and 1", item)
+		}
+
+		rule := SampleRule{Rate: rate}
+		fields := strings.Fields(target)
+		switch len(fields) {
+		case 1:
+			rule.Route = fields[0]
+		case 2:
+			rule.Method, rule.Route = strings.ToUpper(fields[0]), fields[1]
+		default:
+			return nil, fmt.Errorf("invalid sample rule %q, expected [METHOD ]ROUTE=RATE", item)
+		}
+		if !strings.HasPrefix(rule.Route, "/") {
+			return nil, fmt.Errorf("invalid sample rule %q, route must start with /", item)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// sampler chọn rule cho request, rule khớp chính xác ưu tiên hơn prefix, prefix dài ưu tiên hơn
+type sampler struct {
+	rules []SampleRule
+}
+
+// requestSampler sampler của Middleware, nil: ghi mọi request
+var requestSampler atomic.Pointer[sampler]
+
+// setSampleRules thay rules của request log (Init gọi), rỗng: ghi mọi request
+func setSampleRules(rules []SampleRule) {
+	if len(rules) == 0 {
+		requestSampler.Store(nil)
+		return
+	}
+	requestSampler.Store(&sampler{rules: rules})
+}
+
+// rate tỷ lệ ghi log của request thành công, 1 nếu không có rule nào khớp
+func (s *sampler) rate(method, route string) float64 {
+	best, bestLen, exact := 1.0, -1, false
+	for _, rule := range s.rules {
+		if !rule.matches(method, route) {
+			continue
+		}
+		isExact := !strings.HasSuffix(rule.Route, "*")
+		length := len(rule.Route)
+		if rule.Method != "" {
+			length++ // Rule có method cụ thể hơn rule mọi method cùng route
+		}
+		if (isExact && !exact) || (isExact == exact && length > bestLen) {
+			best, bestLen, exact = rule.Rate, length, isExact
+		}
+	}
+	return best
+}
+
+// SampleRate tỷ lệ ghi request log của route theo rules hiện tại (status < 400), 1: ghi tất cả
+func SampleRate(method, route string) float64 {
+	s := requestSampler.Load()
+	if s == nil {
+		return 1
+	}
+	return s.rate(method, route)
+}
+
+// sampled quyết định có ghi log request không: lỗi (>= 400) luôn ghi, còn lại theo tỷ lệ của route
+func sampled(method, route string, status int) (bool, float64) {
+	if status >= 400 {
+		return true, 1
+	}
+	rate := SampleRate(method, route)
+	switch {
+	case rate >= 1:
+		return true, 1
+	case rate <= 0:
+		return false, 0
+	}
+	return rand.Float64() < rate, rate
+}
@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// SyslogConfig cấu hình sink syslog (RFC 5424)
+type SyslogConfig struct {
+	Network  string // udp, tcp, unix (unixgram, vd /dev/log)
+	Address  string // host:port hoặc đường dẫn socket
+	Tag      string // APP-NAME, rỗng: apicore
+	Facility string // kern, user, daemon, local0..local7, rỗng: local0
+}
+
+// syslogFacilities mã facility theo RFC 5424
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// Validate kiểm tra network và facility
+func (c SyslogConfig) Validate() error {
+	switch c.Network {
+	case "udp", "tcp", "unix":
+	default:
+		return fmt.Errorf("invalid syslog network: %q, must be one of udp, tcp, unix", c.Network)
+	}
+	if c.Address == "" {
+		return fmt.Errorf("syslog address is required")
+	}
+	if _, ok := syslogFacilities[strings.ToLower(c.Facility)]; c.Facility != "" && !ok {
+		return fmt.Errorf("invalid syslog facility: %q", c.Facility)
+	}
+	return nil
+}
+
+// syslogTimeFormat RFC 5424 cho phép tối đa 6 chữ số phần giây
+const syslogTimeFormat = "2006-01-02T15:04:05.000000Z07:00"
+
+// syslogSeverity map level zerolog sang severity syslog
+func syslogSeverity(level zerolog.Level) int {
+	switch level {
+	case zerolog.PanicLevel:
+		return 1 // alert
+	case zerolog.FatalLevel:
+		return 2 // crit
+	case zerolog.ErrorLevel:
+		return 3 // err
+	case zerolog.WarnLevel:
+		return 4 // warning
+	case zerolog.DebugLevel, zerolog.TraceLevel:
+		return 7 // debug
+	default:
+		return 6 // info
+	}
+}
+
+// syslogClient gửi message qua một kết nối, kết nối lại khi lỗi
+type syslogClient struct {
+	config   SyslogConfig
+	facility int
+	hostname string
+	msgID    string
+	conn     net.Conn // chỉ goroutine gửi của BatchWriter đọc/ghi
+}
+
+// NewSyslogWriter tạo writer gửi log tới syslog server, MSGID là job (apicore, request, ...).
+// TCP dùng octet-counting framing (RFC 6587), UDP/unix gửi mỗi dòng một datagram.
+func NewSyslogWriter(cfg SyslogConfig, job string, options BatchOptions) (*BatchWriter, error) {
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = "apicore"
+	}
+	if cfg.Facility == "" {
+		cfg.Facility = "local0"
+	}
+	hostname, _ := os.Hostname()
+	if hostname == "" {
+		hostname = "-"
+	}
+
+	client := &syslogClient{
+		config:   cfg,
+		facility: syslogFacilities[strings.ToLower(cfg.Facility)],
+		hostname: hostname,
+		msgID:    job,
+	}
+	return newBatchWriter("syslog("+cfg.Address+")", client.send, client.close, options), nil
+}
+
+// format tạo message RFC 5424: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID - MSG
+func (c *syslogClient) format(entry logEntry) []byte {
+	var b bytes.Buffer
+	b.WriteByte('<')
+	b.WriteString(strconv.Itoa(c.facility*8 + syslogSeverity(entry.Level)))
+	b.WriteString(">1 ")
+	b.WriteString(entry.Time.UTC().Format(syslogTimeFormat))
+	b.WriteByte(' ')
+	b.WriteString(c.hostname)
+	b.WriteByte(' ')
+	b.WriteString(c.config.Tag)
+	b.WriteByte(' ')
+	b.WriteString(strconv.Itoa(os.Getpid()))
+	b.WriteByte(' ')
+	b.WriteString(c.msgID)
+	b.WriteString(" - ")
+	b.Write(entry.Data)
+	return b.Bytes()
+}
+
+// send gửi batch, mỗi message được kết nối lại một lần khi lỗi ghi.
+// BatchWriter retry cả batch nên message đã gửi trước chỗ lỗi có thể bị trùng.
+func (c *syslogClient) send(ctx context.Context, entries []logEntry) error {
+	for i, entry := range entries {
+		message := c.format(entry)
+		if c.config.Network == "tcp" {
+			message = append([]byte(strconv.Itoa(len(message))+" "), message...)
+		}
+		if err := c.write(ctx, message); err != nil {
+			return fmt.Errorf("sent %d/%d entries: %w", i, len(entries), err)
+		}
+	}
+	return nil
+}
+
+func (c *syslogClient) write(ctx context.Context, message []byte) error {
+	for attempt := 0; attempt < 2; attempt++ {
+		if c.conn == nil {
+			network := c.config.Network
+			if network == "unix" {
+				network = "unixgram"
+			}
+			var dialer net.Dialer
+			conn, err := dialer.DialContext(ctx, network, c.config.Address)
+			if err != nil {
+				return err
+			}
+			c.conn = conn
+		}
+		if deadline, ok := ctx.Deadline(); ok {
+			c.conn.SetWriteDeadline(deadline)
+		}
+		if _, err := c.conn.Write(message); err != nil {
+			c.close()
+			if attempt == 1 {
+				return err
+			}
+			continue
+		}
+		return nil
+	}
+	return nil
+}
+
+func (c *syslogClient) close() error {
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
@@ -0,0 +1,68 @@
+package logger
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Verbosity mức độ chi tiết của request log
+type Verbosity int
+
+const (
+	VerbosityFull  Verbosity = iota // Log đầy đủ request/response body (mặc định)
+	VerbosityBasic                  // Chỉ log method, path, status, duration (không log body)
+	VerbosityNone                   // Không log request
+)
+
+// ParseVerbosity chuyển string (full, basic, none) sang Verbosity, giá trị không hợp lệ trả về VerbosityFull
+func ParseVerbosity(s string) Verbosity {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "basic":
+		return VerbosityBasic
+	case "none", "off":
+		return VerbosityNone
+	default:
+		return VerbosityFull
+	}
+}
+
+// String tên của verbosity
+func (v Verbosity) String() string {
+	switch v {
+	case VerbosityBasic:
+		return "basic"
+	case VerbosityNone:
+		return "none"
+	default:
+		return "full"
+	}
+}
+
+type verbosityKey struct{}
+
+// verbosityHolder được Middleware đặt vào context, middleware của route group ghi đè giá trị
+// (route group chạy sau logging middleware nên không thể truyền ngược qua context mới)
+type verbosityHolder struct {
+	value Verbosity
+}
+
+// WithVerbosity middleware đặt mức log cho các route trong group (dùng sau logger.Middleware)
+func WithVerbosity(v Verbosity) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if holder, ok := r.Context().Value(verbosityKey{}).(*verbosityHolder); ok {
+				holder.value = v
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// VerbosityFromContext mức log hiện tại của request (VerbosityFull nếu không qua logger.Middleware)
+func VerbosityFromContext(ctx context.Context) Verbosity {
+	if holder, ok := ctx.Value(verbosityKey{}).(*verbosityHolder); ok {
+		return holder.value
+	}
+	return VerbosityFull
+}
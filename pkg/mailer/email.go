@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html/template"
+	"path/filepath"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/email"
+)
+
+// emailService dùng Mailer làm email.EmailService (queue worker, magic link, suppression list)
+type emailService struct {
+	mailer *Mailer
+}
+
+// NewEmailService email.EmailService gửi qua mailer (SMTP hoặc SES tùy driver).
+// SendTemplate với đường dẫn file: template cùng tên đã load trong mailer được dùng (ngôn ngữ mặc định),
+// không có thì parse file như email.NewEmailService
+func NewEmailService(m *Mailer) email.EmailService {
+	return &emailService{mailer: m}
+}
+
+// Send implement email.EmailService
+func (s *emailService) Send(message *email.EmailMessage) error {
+	return s.mailer.Send(context.Background(), fromEmailMessage(message))
+}
+
+// SendTemplate implement email.EmailService
+func (s *emailService) SendTemplate(message *email.EmailMessage, templatePath string, data interface{}) error {
+	name := strings.TrimSuffix(filepath.Base(templatePath), filepath.Ext(templatePath))
+	if templates := s.mailer.Templates(); templates != nil && templates.Has(name) {
+		msg := fromEmailMessage(message)
+		msg.HTML = ""
+		return s.mailer.SendTemplate(context.Background(), msg, name, "", data)
+	}
+
+	tmpl, err := template.ParseFiles(templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to parse template: %w", err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, data); err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+	message.Body = body.String()
+	return s.Send(message)
+}
+
+func fromEmailMessage(message *email.EmailMessage) *Message {
+	msg := &Message{
+		To:      message.To,
+		CC:      message.CC,
+		BCC:     message.BCC,
+		Subject: message.Subject,
+		HTML:    message.Body,
+		Text:    message.TextBody,
+	}
+	for _, attachment := range message.Attachments {
+		msg.Attachments = append(msg.Attachments, Attachment{
+			Filename:    attachment.Filename,
+			Content:     attachment.Content,
+			ContentType: attachment.MimeType,
+		})
+	}
+	return msg
+}
@@ -0,0 +1,106 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoRecipients message không có người nhận
+var ErrNoRecipients = errors.New("mailer: no recipients")
+
+// Message một email, gửi bằng Driver bất kỳ (SMTP, SES)
+type Message struct {
+	From        string            // Địa chỉ gửi, rỗng dùng Config.From
+	FromName    string            // Tên người gửi, rỗng dùng Config.FromName
+	To          []string          // Danh sách người nhận
+	CC          []string          // Danh sách CC
+	BCC         []string          // Danh sách BCC
+	ReplyTo     []string          // Địa chỉ nhận reply
+	Subject     string            // Tiêu đề email
+	HTML        string            // Nội dung HTML
+	Text        string            // Nội dung text (client không hiển thị HTML)
+	Attachments []Attachment      // File đính kèm
+	Headers     map[string]string // Header thêm (List-Unsubscribe, X-...)
+}
+
+// Attachment file đính kèm, có ContentID thì nhúng inline (HTML tham chiếu bằng src="cid:<ContentID>")
+type Attachment struct {
+	Filename    string
+	Content     []byte
+	ContentType string // Rỗng: đoán theo đuôi file
+	ContentID   string
+}
+
+// Driver gửi email đã được dựng đầy đủ
+type Driver interface {
+	Name() string
+	Send(ctx context.Context, message *Message) error
+}
+
+// Mailer gửi email qua driver, dựng nội dung từ template theo ngôn ngữ người nhận
+type Mailer struct {
+	driver    Driver
+	templates *Templates // nil: không dùng template
+	from      string
+	fromName  string
+}
+
+// New tạo mailer, templates có thể nil
+func New(driver Driver, templates *Templates, from, fromName string) *Mailer {
+	return &Mailer{driver: driver, templates: templates, from: from, fromName: fromName}
+}
+
+// Driver driver đang dùng
+func (m *Mailer) Driver() Driver {
+	return m.driver
+}
+
+// Templates template đã load, nil nếu không cấu hình
+func (m *Mailer) Templates() *Templates {
+	return m.templates
+}
+
+// Send gửi message, From rỗng dùng địa chỉ mặc định
+func (m *Mailer) Send(ctx context.Context, message *Message) error {
+	if len(message.To)+len(message.CC)+len(message.BCC) == 0 {
+		return ErrNoRecipients
+	}
+
+	msg := *message
+	if msg.From == "" {
+		msg.From = m.from
+		if msg.FromName == "" {
+			msg.FromName = m.fromName
+		}
+	}
+	if msg.From == "" {
+		return fmt.Errorf("mailer: from address is required")
+	}
+	return m.driver.Send(ctx, &msg)
+}
+
+// SendTemplate dựng subject, HTML, text của message từ template name theo locale rồi gửi.
+// Subject/HTML/Text đã đặt sẵn trong message được giữ nguyên
+func (m *Mailer) SendTemplate(ctx context.Context, message *Message, name, locale string, data interface{}) error {
+	if m.templates == nil {
+		return fmt.Errorf("mailer: templates are not configured")
+	}
+
+	rendered, err := m.templates.Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+
+	msg := *message
+	if msg.Subject == "" {
+		msg.Subject = rendered.Subject
+	}
+	if msg.HTML == "" {
+		msg.HTML = rendered.HTML
+	}
+	if msg.Text == "" {
+		msg.Text = rendered.Text
+	}
+	return m.Send(ctx, &msg)
+}
@@ -0,0 +1,83 @@
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+
+	"gopkg.in/gomail.v2"
+)
+
+// buildMIME dựng email MIME (multipart khi có text + HTML hoặc file đính kèm)
+func buildMIME(message *Message) *gomail.Message {
+	m := gomail.NewMessage(gomail.SetCharset("UTF-8"))
+
+	if message.FromName != "" {
+		m.SetAddressHeader("From", message.From, message.FromName)
+	} else {
+		m.SetHeader("From", message.From)
+	}
+	if len(message.To) > 0 {
+		m.SetHeader("To", message.To...)
+	}
+	if len(message.CC) > 0 {
+		m.SetHeader("Cc", message.CC...)
+	}
+	if len(message.BCC) > 0 {
+		m.SetHeader("Bcc", message.BCC...)
+	}
+	if len(message.ReplyTo) > 0 {
+		m.SetHeader("Reply-To", message.ReplyTo...)
+	}
+	m.SetHeader("Subject", message.Subject)
+	for key, value := range message.Headers {
+		m.SetHeader(key, value)
+	}
+
+	switch {
+	case message.Text != "" && message.HTML != "":
+		m.SetBody("text/plain", message.Text)
+		m.AddAlternative("text/html", message.HTML)
+	case message.HTML != "":
+		m.SetBody("text/html", message.HTML)
+	default:
+		m.SetBody("text/plain", message.Text)
+	}
+
+	for _, attachment := range message.Attachments {
+		content := attachment.Content
+		header := map[string][]string{"Content-Type": {contentType(attachment)}}
+		copyContent := gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(content)
+			return err
+		})
+		if attachment.ContentID != "" {
+			header["Content-ID"] = []string{"<" + attachment.ContentID + ">"}
+			m.Embed(attachment.Filename, copyContent, gomail.SetHeader(header))
+			continue
+		}
+		m.Attach(attachment.Filename, copyContent, gomail.SetHeader(header))
+	}
+	return m
+}
+
+// rawMIME email MIME dạng byte (SES raw message)
+func rawMIME(message *Message) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := buildMIME(message).WriteTo(&buf); err != nil {
+		return nil, fmt.Errorf("mailer: failed to build message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func contentType(attachment Attachment) string {
+	if attachment.ContentType != "" {
+		return attachment.ContentType
+	}
+	if byExt := mime.TypeByExtension(filepath.Ext(attachment.Filename)); byExt != "" {
+		return byExt
+	}
+	return "application/octet-stream"
+}
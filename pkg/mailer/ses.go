@@ -0,0 +1,163 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// SESConfig cấu hình SES driver. AccessKeyID rỗng: lấy credentials theo chuỗi mặc định của AWS (env, IAM role)
+type SESConfig struct {
+	Region           string
+	AccessKeyID      string
+	SecretAccessKey  string
+	ConfigurationSet string        // Optional: configuration set (event publishing bounce/complaint)
+	Endpoint         string        // Optional: URL thay thế (test, VPC endpoint), rỗng dùng https://email.<region>.amazonaws.com
+	Timeout          time.Duration // Timeout cho mỗi request
+}
+
+// SESDriver gửi email qua AWS SES API v2 (raw MIME, hỗ trợ file đính kèm)
+type SESDriver struct {
+	config      SESConfig
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	httpClient  *http.Client
+}
+
+// NewSESDriver tạo SES driver
+func NewSESDriver(ctx context.Context, cfg SESConfig) (*SESDriver, error) {
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("mailer: SES region is required")
+	}
+
+	options := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" {
+		options = append(options, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: failed to load AWS config: %w", err)
+	}
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://email.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &SESDriver{
+		config:      cfg,
+		credentials: awsConfig.Credentials,
+		signer:      v4.NewSigner(),
+		httpClient:  &http.Client{Transport: tracing.Transport(nil), Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Name implement Driver
+func (d *SESDriver) Name() string {
+	return "ses"
+}
+
+// sesSendEmailRequest body của SendEmail (API v2)
+type sesSendEmailRequest struct {
+	FromEmailAddress     string         `json:"FromEmailAddress"`
+	Destination          sesDestination `json:"Destination"`
+	Content              sesContent     `json:"Content"`
+	ConfigurationSetName string         `json:"ConfigurationSetName,omitempty"`
+}
+
+type sesDestination struct {
+	ToAddresses  []string `json:"ToAddresses,omitempty"`
+	CcAddresses  []string `json:"CcAddresses,omitempty"`
+	BccAddresses []string `json:"BccAddresses,omitempty"`
+}
+
+type sesContent struct {
+	Raw struct {
+		Data []byte `json:"Data"` // encoding/json mã hóa base64 như SES yêu cầu
+	} `json:"Raw"`
+}
+
+// Send implement Driver
+func (d *SESDriver) Send(ctx context.Context, message *Message) error {
+	raw, err := rawMIME(message)
+	if err != nil {
+		return err
+	}
+
+	request := sesSendEmailRequest{
+		FromEmailAddress: message.From,
+		Destination: sesDestination{
+			ToAddresses:  message.To,
+			CcAddresses:  message.CC,
+			BccAddresses: message.BCC,
+		},
+		ConfigurationSetName: d.config.ConfigurationSet,
+	}
+	request.Content.Raw.Data = raw
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.config.Endpoint+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	creds, err := d.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := d.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "ses", d.config.Region, time.Now()); err != nil {
+		return fmt.Errorf("mailer: failed to sign SES request: %w", err)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("mailer: SES request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var errBody struct {
+		Message string `json:"message"`
+	}
+	json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&errBody)
+	// X-Amzn-ErrorType dạng "MessageRejected:http://internal.amazon.com/..."
+	errorType, _, _ := strings.Cut(resp.Header.Get("X-Amzn-ErrorType"), ":")
+	return &SESError{StatusCode: resp.StatusCode, Type: errorType, Message: errBody.Message}
+}
+
+// SESError lỗi SES trả về. 4xx (trừ 429) là lỗi của message (địa chỉ chưa verify, message bị từ chối), retry không thành công
+type SESError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+func (e *SESError) Error() string {
+	return fmt.Sprintf("mailer: SES %s (status %d): %s", e.Type, e.StatusCode, e.Message)
+}
@@ -0,0 +1,44 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+
+	"gopkg.in/gomail.v2"
+)
+
+// SMTPConfig cấu hình SMTP driver
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	UseTLS   bool // Verify certificate theo Host khi STARTTLS (port 465 luôn dùng TLS ngay khi kết nối)
+}
+
+// SMTPDriver gửi email qua SMTP server
+type SMTPDriver struct {
+	dialer *gomail.Dialer
+}
+
+// NewSMTPDriver tạo SMTP driver
+func NewSMTPDriver(cfg SMTPConfig) *SMTPDriver {
+	dialer := gomail.NewDialer(cfg.Host, cfg.Port, cfg.Username, cfg.Password)
+	if cfg.UseTLS {
+		dialer.TLSConfig = &tls.Config{ServerName: cfg.Host}
+	}
+	return &SMTPDriver{dialer: dialer}
+}
+
+// Name implement Driver
+func (d *SMTPDriver) Name() string {
+	return "smtp"
+}
+
+// Send implement Driver, mỗi lần gửi mở một kết nối SMTP
+func (d *SMTPDriver) Send(ctx context.Context, message *Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return d.dialer.DialAndSend(buildMIME(message))
+}
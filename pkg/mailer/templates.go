@@ -0,0 +1,306 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+)
+
+// MJMLCompiler biên dịch MJML sang HTML
+type MJMLCompiler interface {
+	Compile(ctx context.Context, source string) (string, error)
+}
+
+// MJMLFunc hàm implement MJMLCompiler
+type MJMLFunc func(ctx context.Context, source string) (string, error)
+
+// Compile implement MJMLCompiler
+func (f MJMLFunc) Compile(ctx context.Context, source string) (string, error) {
+	return f(ctx, source)
+}
+
+// MJMLCommand biên dịch bằng mjml CLI (npm install -g mjml), Path rỗng là "mjml" trong PATH
+type MJMLCommand struct {
+	Path string
+}
+
+// Compile implement MJMLCompiler
+func (c MJMLCommand) Compile(ctx context.Context, source string) (string, error) {
+	path := c.Path
+	if path == "" {
+		path = "mjml"
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, "-i", "-s")
+	cmd.Stdin = strings.NewReader(source)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("mjml: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// TemplateConfig cấu hình load template
+type TemplateConfig struct {
+	Dir           string                                             // Thư mục template
+	DefaultLocale string                                             // Ngôn ngữ khi không có template/bản dịch của locale yêu cầu
+	MJML          MJMLCompiler                                       // nil: không hỗ trợ file .mjml
+	Translate     func(lang, key string, args ...interface{}) string // nil: i18n.T
+}
+
+// Rendered nội dung email đã dựng từ template
+type Rendered struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Templates email template theo tên và ngôn ngữ.
+//
+// Cấu trúc thư mục: <dir>/<name>.html (hoặc .mjml) và <dir>/<name>.txt (optional) là bản mặc định,
+// <dir>/<locale>/<name>.* ghi đè cho một ngôn ngữ. Subject lấy từ block {{define "subject"}} của file .txt,
+// không có thì từ bản dịch "emails.<name>.subject" (cũng là template, dùng được {{.Name}}).
+// Trong template, {{t "emails.x"}} dịch theo ngôn ngữ của email, {{locale}} là ngôn ngữ đó
+type Templates struct {
+	defaultLocale string
+	translate     func(lang, key string, args ...interface{}) string
+	entries       map[string]map[string]*templateEntry // name -> locale ("" = mặc định) -> template
+}
+
+type templateEntry struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// LoadTemplates load toàn bộ template trong cfg.Dir, file .mjml được biên dịch một lần lúc load
+func LoadTemplates(ctx context.Context, cfg TemplateConfig) (*Templates, error) {
+	t := &Templates{
+		defaultLocale: strings.ToLower(cfg.DefaultLocale),
+		translate:     cfg.Translate,
+		entries:       make(map[string]map[string]*templateEntry),
+	}
+	if t.translate == nil {
+		t.translate = i18n.T
+	}
+
+	if err := t.loadDir(ctx, cfg, cfg.Dir, ""); err != nil {
+		return nil, err
+	}
+
+	dirs, err := os.ReadDir(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("mailer: failed to read template dir: %w", err)
+	}
+	for _, dir := range dirs {
+		if dir.IsDir() {
+			if err := t.loadDir(ctx, cfg, filepath.Join(cfg.Dir, dir.Name()), strings.ToLower(dir.Name())); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return t, nil
+}
+
+func (t *Templates) loadDir(ctx context.Context, cfg TemplateConfig, dir, locale string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to read template dir: %w", err)
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(file.Name())
+		name := strings.TrimSuffix(file.Name(), ext)
+		path := filepath.Join(dir, file.Name())
+		if ext != ".html" && ext != ".mjml" && ext != ".txt" {
+			continue
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("mailer: failed to read template %s: %w", path, err)
+		}
+		source := string(raw)
+
+		entry := t.entry(name, locale)
+		switch ext {
+		case ".txt":
+			entry.text, err = texttemplate.New(name).Funcs(t.funcs(locale)).Parse(source)
+		case ".mjml":
+			if cfg.MJML == nil {
+				return fmt.Errorf("mailer: %s needs an MJML compiler", path)
+			}
+			if source, err = cfg.MJML.Compile(ctx, source); err != nil {
+				return fmt.Errorf("mailer: failed to compile %s: %w", path, err)
+			}
+			fallthrough
+		case ".html":
+			if entry.html != nil {
+				return fmt.Errorf("mailer: duplicate template %s", path)
+			}
+			entry.html, err = htmltemplate.New(name).Funcs(htmltemplate.FuncMap(t.funcs(locale))).Parse(source)
+		}
+		if err != nil {
+			return fmt.Errorf("mailer: failed to parse template %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+func (t *Templates) entry(name, locale string) *templateEntry {
+	if t.entries[name] == nil {
+		t.entries[name] = make(map[string]*templateEntry)
+	}
+	entry, ok := t.entries[name][locale]
+	if !ok {
+		entry = &templateEntry{}
+		t.entries[name][locale] = entry
+	}
+	return entry
+}
+
+// funcs hàm dùng trong template, dịch theo locale
+func (t *Templates) funcs(locale string) map[string]interface{} {
+	return map[string]interface{}{
+		"t": func(key string, args ...interface{}) string {
+			return t.tr(locale, key, args...)
+		},
+		"locale": func() string { return locale },
+	}
+}
+
+// Has có template name
+func (t *Templates) Has(name string) bool {
+	_, ok := t.entries[name]
+	return ok
+}
+
+// Render dựng subject, HTML, text của template name theo locale (vi-VN -> vi -> mặc định)
+func (t *Templates) Render(name, locale string, data interface{}) (*Rendered, error) {
+	locales, ok := t.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("mailer: template %q not found", name)
+	}
+
+	locale = t.resolveLocale(locale)
+	html, text := t.lookup(locales, locale)
+	if html == nil && text == nil {
+		return nil, fmt.Errorf("mailer: template %q has no content", name)
+	}
+
+	rendered := &Rendered{}
+	funcs := t.funcs(locale)
+	var buf bytes.Buffer
+	if html != nil {
+		clone, err := html.Clone()
+		if err != nil {
+			return nil, err
+		}
+		if err := clone.Funcs(htmltemplate.FuncMap(funcs)).Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("mailer: failed to render %s: %w", name, err)
+		}
+		rendered.HTML = buf.String()
+	}
+	if text != nil {
+		clone, err := text.Clone()
+		if err != nil {
+			return nil, err
+		}
+		clone.Funcs(funcs)
+		buf.Reset()
+		if err := clone.Execute(&buf, data); err != nil {
+			return nil, fmt.Errorf("mailer: failed to render %s: %w", name, err)
+		}
+		rendered.Text = strings.TrimSpace(buf.String())
+
+		if subject := clone.Lookup("subject"); subject != nil {
+			buf.Reset()
+			if err := subject.Execute(&buf, data); err != nil {
+				return nil, fmt.Errorf("mailer: failed to render subject of %s: %w", name, err)
+			}
+			rendered.Subject = strings.TrimSpace(buf.String())
+		}
+	}
+
+	if rendered.Subject == "" {
+		subject, err := t.translatedSubject(name, locale, data)
+		if err != nil {
+			return nil, err
+		}
+		rendered.Subject = subject
+	}
+	return rendered, nil
+}
+
+// lookup template HTML và text của locale, phần thiếu lấy từ bản mặc định
+func (t *Templates) lookup(locales map[string]*templateEntry, locale string) (*htmltemplate.Template, *texttemplate.Template) {
+	var html *htmltemplate.Template
+	var text *texttemplate.Template
+	for _, candidate := range []string{locale, baseLocale(locale), t.defaultLocale, ""} {
+		entry, ok := locales[candidate]
+		if !ok {
+			continue
+		}
+		if html == nil {
+			html = entry.html
+		}
+		if text == nil {
+			text = entry.text
+		}
+	}
+	return html, text
+}
+
+// translatedSubject subject từ bản dịch "emails.<name>.subject", rỗng nếu không có bản dịch
+func (t *Templates) translatedSubject(name, locale string, data interface{}) (string, error) {
+	key := "emails." + name + ".subject"
+	translated := t.tr(locale, key)
+	if translated == key {
+		return "", nil
+	}
+
+	tmpl, err := texttemplate.New("subject").Parse(translated)
+	if err != nil {
+		return "", fmt.Errorf("mailer: invalid subject translation %s: %w", key, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("mailer: failed to render subject of %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// tr dịch key theo locale (vi-VN -> vi -> mặc định), trả về key nếu không có bản dịch
+func (t *Templates) tr(locale, key string, args ...interface{}) string {
+	for _, candidate := range []string{locale, baseLocale(locale), t.defaultLocale} {
+		if translated := t.translate(candidate, key, args...); translated != key {
+			return translated
+		}
+	}
+	return key
+}
+
+func (t *Templates) resolveLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if locale == "" {
+		return t.defaultLocale
+	}
+	return locale
+}
+
+// baseLocale ngôn ngữ gốc của locale có vùng (vi-vn -> vi)
+func baseLocale(locale string) string {
+	base, _, _ := strings.Cut(strings.ReplaceAll(locale, "_", "-"), "-")
+	return base
+}
@@ -0,0 +1,151 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Content types: OpenMetrics khi scraper hỗ trợ, Prometheus text format cho client cũ
+const (
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	ContentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// collectTimeout giới hạn thời gian collectors (gọi Redis/RabbitMQ) trong 1 lần scrape
+const collectTimeout = 5 * time.Second
+
+// Handler phục vụ /metrics, chọn format theo Accept header
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		openMetrics := strings.Contains(req.Header.Get("Accept"), "application/openmetrics-text")
+		if openMetrics {
+			w.Header().Set("Content-Type", ContentTypeOpenMetrics)
+		} else {
+			w.Header().Set("Content-Type", ContentTypeText)
+		}
+
+		ctx, cancel := context.WithTimeout(req.Context(), collectTimeout)
+		defer cancel()
+		r.Collect(ctx)
+		_ = r.Write(w, openMetrics)
+	})
+}
+
+// Collect chạy tất cả collectors để cập nhật gauges
+func (r *Registry) Collect(ctx context.Context) {
+	r.mu.Lock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.Unlock()
+
+	for _, c := range collectors {
+		c.Collect(ctx)
+	}
+}
+
+// Write export metrics dạng OpenMetrics (openMetrics=true) hoặc Prometheus text format
+func (r *Registry) Write(w io.Writer, openMetrics bool) error {
+	r.mu.Lock()
+	families := append([]*family(nil), r.families...)
+	r.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.write(bw, openMetrics)
+	}
+	if openMetrics {
+		bw.WriteString("# EOF\n")
+	}
+	return bw.Flush()
+}
+
+func (f *family) write(w *bufio.Writer, openMetrics bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(f.series) == 0 {
+		return
+	}
+
+	// Prometheus text format khai báo counter bằng tên có _total, OpenMetrics dùng tên family
+	typeName := f.name
+	if f.typ == TypeCounter && !openMetrics {
+		typeName = f.name + "_total"
+	}
+	w.WriteString("# HELP " + typeName + " " + escapeHelp(f.help) + "\n")
+	w.WriteString("# TYPE " + typeName + " " + f.typ + "\n")
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		switch f.typ {
+		case TypeCounter:
+			f.writeSample(w, "_total", s.labelValues, "", "", s.value)
+		case TypeGauge:
+			f.writeSample(w, "", s.labelValues, "", "", s.value)
+		case TypeHistogram:
+			var cumulative uint64
+			for i, upper := range f.buckets {
+				cumulative += s.bucketCounts[i]
+				f.writeSample(w, "_bucket", s.labelValues, "le", formatFloat(upper), float64(cumulative))
+			}
+			f.writeSample(w, "_bucket", s.labelValues, "le", "+Inf", float64(s.count))
+			f.writeSample(w, "_sum", s.labelValues, "", "", s.sum)
+			f.writeSample(w, "_count", s.labelValues, "", "", float64(s.count))
+		}
+	}
+}
+
+// writeSample ghi 1 dòng sample, extraName/extraValue là label phụ (le của histogram)
+func (f *family) writeSample(w *bufio.Writer, suffix string, labelValues []string, extraName, extraValue string, value float64) {
+	w.WriteString(f.name + suffix)
+
+	pairs := make([]string, 0, len(labelValues)+1)
+	for i, name := range f.labelNames {
+		pairs = append(pairs, name+`="`+escapeLabel(labelValues[i])+`"`)
+	}
+	if extraName != "" {
+		pairs = append(pairs, extraName+`="`+extraValue+`"`)
+	}
+	if len(pairs) > 0 {
+		w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	w.WriteString(" " + formatFloat(value) + "\n")
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func escapeHelp(v string) string {
+	return helpEscaper.Replace(v)
+}
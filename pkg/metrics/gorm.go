@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DBQueryBuckets buckets (giây) cho thời gian chạy câu lệnh SQL: từ 1ms đến 5 giây
+var DBQueryBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// gormStartKey thời điểm bắt đầu câu lệnh, lưu trong instance của statement
+const gormStartKey = "metrics:start"
+
+// tableUnknown label table của câu lệnh không xác định được bảng (Raw, Exec)
+const tableUnknown = "unknown"
+
+// InstrumentGORM đăng ký callback đo thời gian mỗi câu lệnh (query, create, update, delete, row, raw) theo bảng và loại câu lệnh.
+// p95 của <prefix>_query_duration_seconds{operation="query"} cao ở một bảng thường là thiếu index cho list endpoint.
+func InstrumentGORM(db *gorm.DB, registry *Registry, prefix string) error {
+	if prefix == "" {
+		prefix = "db"
+	}
+	duration := registry.NewHistogram(prefix+"_query_duration_seconds", "SQL statement duration by table and operation.", DBQueryBuckets, "table", "operation")
+	failures := registry.NewCounter(prefix+"_query_errors", "Failed SQL statements by table and operation, record not found excluded.", "table", "operation")
+
+	type register func(name string, fn func(*gorm.DB)) error
+	cb := db.Callback()
+	operations := []struct {
+		name          string
+		before, after register
+	}{
+		{"create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+	for _, op := range operations {
+		operation := op.name
+		if err := op.before("metrics:before_"+operation, func(tx *gorm.DB) {
+			tx.InstanceSet(gormStartKey, time.Now())
+		}); err != nil {
+			return err
+		}
+		if err := op.after("metrics:after_"+operation, func(tx *gorm.DB) {
+			start, ok := tx.InstanceGet(gormStartKey)
+			if !ok {
+				return
+			}
+			table := tx.Statement.Table
+			if table == "" {
+				table = tableUnknown
+			}
+			duration.Observe(time.Since(start.(time.Time)).Seconds(), table, operation)
+			if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+				failures.Inc(table, operation)
+			}
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package metrics
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// HTTPBuckets buckets (giây) cho thời gian xử lý HTTP request: từ 5ms đến 10 giây
+var HTTPBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// routeUnmatched label route của request không khớp route nào (404), tránh mỗi URL lạ thành 1 series
+const routeUnmatched = "unmatched"
+
+// HTTPMetrics số request và thời gian xử lý theo method, route pattern và status
+type HTTPMetrics struct {
+	duration *Histogram
+	requests *Counter
+	inFlight *Gauge
+}
+
+// NewHTTPMetrics đăng ký HTTP metrics vào registry, prefix rỗng dùng "http"
+func NewHTTPMetrics(registry *Registry, prefix string) *HTTPMetrics {
+	if prefix == "" {
+		prefix = "http"
+	}
+	return &HTTPMetrics{
+		duration: registry.NewHistogram(prefix+"_request_duration_seconds", "HTTP request duration by method, route and status.", HTTPBuckets, "method", "route", "status"),
+		requests: registry.NewCounter(prefix+"_requests", "HTTP requests by method, route and status.", "method", "route", "status"),
+		inFlight: registry.NewGauge(prefix+"_requests_in_flight", "HTTP requests being served."),
+	}
+}
+
+// Middleware đo mỗi request. Label route là route pattern của chi ("/api/v1/users/{id}"), không phải URL.
+// WebSocket không được đo (request kéo dài cả kết nối).
+func (m *HTTPMetrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") == "websocket" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		start := time.Now()
+		m.inFlight.Add(1)
+		defer m.inFlight.Add(-1)
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		route := routeUnmatched
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		labels := []string{r.Method, route, strconv.Itoa(status)}
+		m.duration.Observe(time.Since(start).Seconds(), labels...)
+		m.requests.Inc(labels...)
+	})
+}
+
+// RequireToken middleware bảo vệ endpoint metrics bằng bearer token (bearer_token trong scrape config
+// của Prometheus), token rỗng thì không kiểm tra
+func RequireToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if token == "" {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Loại metric theo OpenMetrics
+const (
+	TypeCounter   = "counter"
+	TypeGauge     = "gauge"
+	TypeHistogram = "histogram"
+)
+
+// DefBuckets buckets mặc định (giây) cho histogram thời gian xử lý: từ 5ms đến 10 phút
+var DefBuckets = []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 5, 10, 30, 60, 300, 600}
+
+// Collector cập nhật gauges ngay trước khi export (queue depth, oldest message age...)
+type Collector interface {
+	Collect(ctx context.Context)
+}
+
+// CollectorFunc adapter cho Collector
+type CollectorFunc func(ctx context.Context)
+
+// Collect implements Collector
+func (f CollectorFunc) Collect(ctx context.Context) {
+	f(ctx)
+}
+
+// Registry tập metric families, export theo thứ tự đăng ký
+type Registry struct {
+	mu         sync.Mutex
+	families   []*family
+	byName     map[string]*family
+	collectors []Collector
+}
+
+var defaultRegistry = NewRegistry()
+
+// NewRegistry tạo registry rỗng
+func NewRegistry() *Registry {
+	return &Registry{byName: make(map[string]*family)}
+}
+
+// Default registry dùng chung trong process, được export ở METRICS_ADDR
+func Default() *Registry {
+	return defaultRegistry
+}
+
+// family 1 metric với các series theo label values
+type family struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	buckets    []float64
+
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+type series struct {
+	labelValues []string
+	value       float64
+	// histogram: số observation cộng dồn theo bucket, sum và count
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+// register trả về family đã có nếu cùng tên (nhiều component dùng chung registry), panic nếu khác loại hoặc labels
+func (r *Registry) register(name, help, typ string, buckets []float64, labelNames []string) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f, ok := r.byName[name]; ok {
+		if f.typ != typ || strings.Join(f.labelNames, ",") != strings.Join(labelNames, ",") {
+			panic(fmt.Sprintf("metrics: %s already registered as %s%v", name, f.typ, f.labelNames))
+		}
+		return f
+	}
+
+	f := &family{
+		name:       name,
+		help:       help,
+		typ:        typ,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+	r.families = append(r.families, f)
+	r.byName[name] = f
+	return f
+}
+
+// RegisterCollector thêm collector chạy mỗi lần export
+func (r *Registry) RegisterCollector(c Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, c)
+}
+
+// get lấy hoặc tạo series theo label values, số label values phải khớp labelNames
+func (f *family) get(labelValues []string) *series {
+	if len(labelValues) != len(f.labelNames) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if f.typ == TypeHistogram {
+			s.bucketCounts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Counter counter chỉ tăng, export với hậu tố _total
+type Counter struct {
+	f *family
+}
+
+// NewCounter đăng ký counter, name không gồm hậu tố _total
+func (r *Registry) NewCounter(name, help string, labelNames ...string) *Counter {
+	return &Counter{f: r.register(name, help, TypeCounter, nil, labelNames)}
+}
+
+// Inc tăng 1
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add cộng v (v >= 0)
+func (c *Counter) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		return
+	}
+	c.f.mu.Lock()
+	c.f.get(labelValues).value += v
+	c.f.mu.Unlock()
+}
+
+// setTotal gán giá trị cộng dồn đọc từ nguồn khác (runtime.MemStats, sql.DBStats), bỏ qua nếu nhỏ hơn giá trị hiện tại
+func (c *Counter) setTotal(v float64, labelValues ...string) {
+	c.f.mu.Lock()
+	if s := c.f.get(labelValues); v > s.value {
+		s.value = v
+	}
+	c.f.mu.Unlock()
+}
+
+// Gauge giá trị tăng giảm tùy ý
+type Gauge struct {
+	f *family
+}
+
+// NewGauge đăng ký gauge
+func (r *Registry) NewGauge(name, help string, labelNames ...string) *Gauge {
+	return &Gauge{f: r.register(name, help, TypeGauge, nil, labelNames)}
+}
+
+// Set gán giá trị
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	g.f.get(labelValues).value = v
+	g.f.mu.Unlock()
+}
+
+// Add cộng v (có thể âm)
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.f.mu.Lock()
+	g.f.get(labelValues).value += v
+	g.f.mu.Unlock()
+}
+
+// Delete xóa series, dùng khi không còn đo được (vd queue không đọc được size)
+func (g *Gauge) Delete(labelValues ...string) {
+	g.f.mu.Lock()
+	delete(g.f.series, strings.Join(labelValues, "\xff"))
+	g.f.mu.Unlock()
+}
+
+// Histogram phân bố giá trị theo buckets
+type Histogram struct {
+	f *family
+}
+
+// NewHistogram đăng ký histogram, buckets nil dùng DefBuckets
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Histogram{f: r.register(name, help, TypeHistogram, buckets, labelNames)}
+}
+
+// Observe ghi nhận 1 giá trị
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	h.f.mu.Lock()
+	defer h.f.mu.Unlock()
+
+	s := h.f.get(labelValues)
+	for i, upper := range h.f.buckets {
+		if v <= upper {
+			s.bucketCounts[i]++
+			break
+		}
+	}
+	s.sum += v
+	s.count++
+}
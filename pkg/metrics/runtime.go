@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"context"
+	"runtime"
+	"time"
+)
+
+// RegisterRuntime đăng ký metrics của Go runtime (goroutines, heap, GC) theo tên chuẩn của Prometheus Go client,
+// dashboard có sẵn (go_goroutines, go_memstats_*) dùng được ngay. Giá trị được đọc mỗi lần scrape.
+func RegisterRuntime(registry *Registry) {
+	info := registry.NewGauge("go_info", "Information about the Go environment.", "version")
+	goroutines := registry.NewGauge("go_goroutines", "Number of goroutines that currently exist.")
+	gomaxprocs := registry.NewGauge("go_sched_gomaxprocs_threads", "The current runtime.GOMAXPROCS setting.")
+	heapAlloc := registry.NewGauge("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.")
+	heapInuse := registry.NewGauge("go_memstats_heap_inuse_bytes", "Number of heap bytes that are in use.")
+	heapObjects := registry.NewGauge("go_memstats_heap_objects", "Number of allocated objects.")
+	sys := registry.NewGauge("go_memstats_sys_bytes", "Number of bytes obtained from system.")
+	nextGC := registry.NewGauge("go_memstats_next_gc_bytes", "Number of heap bytes when next garbage collection will take place.")
+	allocated := registry.NewCounter("go_memstats_alloc_bytes", "Total number of bytes allocated, even if freed.")
+	gcCycles := registry.NewCounter("go_gc_cycles", "Number of completed GC cycles.")
+	gcPause := registry.NewCounter("go_gc_pause_seconds", "Total stop-the-world pause time of GC.")
+	startTime := registry.NewGauge("process_start_time_seconds", "Start time of the process since unix epoch in seconds.")
+
+	info.Set(1, runtime.Version())
+	startTime.Set(float64(time.Now().Unix()))
+
+	registry.RegisterCollector(CollectorFunc(func(ctx context.Context) {
+		var stats runtime.MemStats
+		runtime.ReadMemStats(&stats)
+
+		goroutines.Set(float64(runtime.NumGoroutine()))
+		gomaxprocs.Set(float64(runtime.GOMAXPROCS(0)))
+		heapAlloc.Set(float64(stats.HeapAlloc))
+		heapInuse.Set(float64(stats.HeapInuse))
+		heapObjects.Set(float64(stats.HeapObjects))
+		sys.Set(float64(stats.Sys))
+		nextGC.Set(float64(stats.NextGC))
+		allocated.setTotal(float64(stats.TotalAlloc))
+		gcCycles.setTotal(float64(stats.NumGC))
+		gcPause.setTotal(time.Duration(stats.PauseTotalNs).Seconds())
+	}))
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+)
+
+// RegisterDBStats đăng ký metrics connection pool của db (sql.DB của GORM: db.DB()), đọc mỗi lần scrape.
+// Pool cạn: <prefix>_connections{state="in_use"} bằng <prefix>_max_open_connections và rate(<prefix>_wait_total[5m]) tăng.
+func RegisterDBStats(registry *Registry, prefix string, db *sql.DB) {
+	if prefix == "" {
+		prefix = "db"
+	}
+	connections := registry.NewGauge(prefix+"_connections", "Connections in the pool by state: in_use or idle.", "state")
+	maxOpen := registry.NewGauge(prefix+"_max_open_connections", "Maximum number of open connections, 0 is unlimited.")
+	waits := registry.NewCounter(prefix+"_wait", "Connections waited for because the pool was exhausted.")
+	waitDuration := registry.NewCounter(prefix+"_wait_duration_seconds", "Total time blocked waiting for a connection.")
+	closed := registry.NewCounter(prefix+"_connections_closed", "Connections closed by reason: max_idle, max_idle_time or max_lifetime.", "reason")
+
+	registry.RegisterCollector(CollectorFunc(func(ctx context.Context) {
+		stats := db.Stats()
+		connections.Set(float64(stats.InUse), "in_use")
+		connections.Set(float64(stats.Idle), "idle")
+		maxOpen.Set(float64(stats.MaxOpenConnections))
+		waits.setTotal(float64(stats.WaitCount))
+		waitDuration.setTotal(stats.WaitDuration.Seconds())
+		closed.setTotal(float64(stats.MaxIdleClosed), "max_idle")
+		closed.setTotal(float64(stats.MaxIdleTimeClosed), "max_idle_time")
+		closed.setTotal(float64(stats.MaxLifetimeClosed), "max_lifetime")
+	}))
+}
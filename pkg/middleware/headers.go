@@ -2,10 +2,20 @@ package middleware
 
 import (
 	"net/http"
-
-	"api-core/pkg/utils"
+	"strconv"
+	"strings"
 )
 
+// CORSOptions configures CORSHeaders (the app fills it from environment variables)
+type CORSOptions struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	MaxAge           int // Seconds
+}
+
 // CustomHeaders middleware adds custom headers to responses
 func CustomHeaders(headers map[string]string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -15,14 +25,6 @@ func CustomHeaders(headers map[string]string) func(http.Handler) http.Handler {
 				w.Header().Set(key, value)
 			}
 
-			// Set additional headers from environment variables
-			if apiVersion := utils.GetEnv("API_VERSION", ""); apiVersion != "" {
-				w.Header().Set("X-API-Version", apiVersion)
-			}
-			if poweredBy := utils.GetEnv("API_POWERED_BY", ""); poweredBy != "" {
-				w.Header().Set("X-Powered-By", poweredBy)
-			}
-
 			// Call next handler
 			next.ServeHTTP(w, r)
 		})
@@ -30,18 +32,23 @@ func CustomHeaders(headers map[string]string) func(http.Handler) http.Handler {
 }
 
 // CORSHeaders middleware adds CORS headers manually
-func CORSHeaders() func(http.Handler) http.Handler {
+func CORSHeaders(options CORSOptions) func(http.Handler) http.Handler {
+	allowOrigin := strings.Join(options.AllowedOrigins, ", ")
+	allowMethods := strings.Join(options.AllowedMethods, ", ")
+	allowHeaders := strings.Join(options.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(options.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(options.MaxAge)
+
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			// Set CORS headers from environment variables
-			w.Header().Set("Access-Control-Allow-Origin", utils.GetEnv("CORS_ALLOWED_ORIGINS", "*"))
-			w.Header().Set("Access-Control-Allow-Methods", utils.GetEnv("CORS_ALLOWED_METHODS", "GET, POST, PUT, DELETE, OPTIONS, PATCH"))
-			w.Header().Set("Access-Control-Allow-Headers", utils.GetEnv("CORS_ALLOWED_HEADERS", "*"))
-			w.Header().Set("Access-Control-Expose-Headers", utils.GetEnv("CORS_EXPOSED_HEADERS", "Link"))
-			w.Header().Set("Access-Control-Max-Age", utils.GetEnv("CORS_MAX_AGE", "300"))
+			w.Header().Set("Access-Control-Allow-Origin", allowOrigin)
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", allowHeaders)
+			w.Header().Set("Access-Control-Expose-Headers", exposeHeaders)
+			w.Header().Set("Access-Control-Max-Age", maxAge)
 
 			// Set Allow-Credentials if enabled
-			if utils.GetEnvBool("CORS_ALLOW_CREDENTIALS", false) {
+			if options.AllowCredentials {
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 			}
 
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// IPAllowlist chỉ cho request từ IP thuộc allowed đi tiếp, còn lại trả 403 IP_NOT_ALLOWED.
+// allowed rỗng: không giới hạn. trustProxy=false lấy IP từ kết nối TCP (X-Forwarded-For do client tự đặt được)
+func IPAllowlist(allowed []*net.IPNet, trustProxy bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if len(allowed) == 0 {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			clientIP := utils.GetRemoteIP(r)
+			if trustProxy {
+				clientIP = utils.GetClientIP(r)
+			}
+
+			if !ipAllowed(net.ParseIP(clientIP), allowed) {
+				lang := i18n.GetLanguageFromContext(r.Context())
+				response.Forbidden(w, lang, response.CodeIPNotAllowed)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ipAllowed kiểm tra ip thuộc một trong các dải allowed
+func ipAllowed(ip net.IP, allowed []*net.IPNet) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range allowed {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
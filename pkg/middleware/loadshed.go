@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/loadshed"
+)
+
+// LoadShedOptions configures the load shedding middleware (the app fills it from environment variables)
+type LoadShedOptions struct {
+	Enabled bool
+	Shedder loadshed.Config
+}
+
+// LoadShedding creates load shedding middleware
+// Shedder chạy background evaluation cho đến khi ctx bị huỷ
+func LoadShedding(ctx context.Context, options LoadShedOptions) func(http.Handler) http.Handler {
+	if !options.Enabled {
+		// Return no-op middleware if load shedding is disabled
+		return noop
+	}
+
+	shedder := loadshed.NewShedder(options.Shedder)
+	shedder.Start(ctx)
+
+	return loadshed.Middleware(shedder)
+}
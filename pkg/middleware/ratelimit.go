@@ -4,142 +4,129 @@ import (
 	"net/http"
 	"time"
 
-	"api-core/config"
-	"api-core/pkg/ratelimit"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/ratelimit"
 
 	"github.com/go-redis/redis/v8"
 )
 
-// RateLimitMiddleware creates rate limiting middleware with default configuration
-func RateLimitMiddleware(redisClient *redis.Client) func(http.Handler) http.Handler {
-	// Load rate limit configuration
-	rateLimitConfig := config.LoadRateLimitConfig()
-
-	if !rateLimitConfig.Enabled {
-		// Return no-op middleware if rate limiting is disabled
-		return func(next http.Handler) http.Handler {
-			return next
-		}
+// RateLimitOptions configures the rate limiting middlewares (the app fills it from environment variables)
+type RateLimitOptions struct {
+	Enabled         bool
+	KeyPrefix       string        // Redis key prefix
+	DefaultRequests int           // Requests per DefaultDuration for RateLimitMiddleware
+	DefaultDuration time.Duration // Window for RateLimitMiddleware
+}
+
+// newRateLimiter creates a rate limiter, nil when rate limiting is disabled
+func newRateLimiter(redisClient redis.UniversalClient, options RateLimitOptions) *ratelimit.RateLimiter {
+	if !options.Enabled {
+		return nil
 	}
+	return ratelimit.NewRateLimiter(ratelimit.RateLimitConfig{
+		Redis:     redisClient,
+		KeyPrefix: options.KeyPrefix,
+	})
+}
 
-	// Create rate limiter
-	rateLimiter := config.CreateRateLimiter(redisClient, rateLimitConfig)
+// noop middleware used when rate limiting is disabled
+func noop(next http.Handler) http.Handler {
+	return next
+}
+
+// RateLimitMiddleware creates rate limiting middleware with default configuration
+func RateLimitMiddleware(redisClient redis.UniversalClient, options RateLimitOptions) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil {
+		return noop
+	}
 
 	// Use default configuration
-	return ratelimit.RateLimitByUserOrIP(
-		rateLimiter,
-		rateLimitConfig.DefaultRule.Requests,
-		rateLimitConfig.DefaultRule.Duration,
-	)
+	return ratelimit.RateLimitByUserOrIP(rateLimiter, options.DefaultRequests, options.DefaultDuration)
 }
 
 // AuthRateLimitMiddleware creates rate limiting middleware for auth routes
-func AuthRateLimitMiddleware(redisClient *redis.Client) func(http.Handler) http.Handler {
-	// Load rate limit configuration
-	rateLimitConfig := config.LoadRateLimitConfig()
-
-	if !rateLimitConfig.Enabled {
-		// Return no-op middleware if rate limiting is disabled
-		return func(next http.Handler) http.Handler {
-			return next
-		}
+func AuthRateLimitMiddleware(redisClient redis.UniversalClient, options RateLimitOptions) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil {
+		return noop
 	}
 
-	// Create rate limiter
-	rateLimiter := config.CreateRateLimiter(redisClient, rateLimitConfig)
-
 	// More restrictive rules for auth routes
 	return ratelimit.RateLimitByIP(rateLimiter, 5, 15*60*time.Second) // 5 requests per 15 minutes
 }
 
 // UploadRateLimitMiddleware creates rate limiting middleware for upload routes
-func UploadRateLimitMiddleware(redisClient *redis.Client) func(http.Handler) http.Handler {
-	// Load rate limit configuration
-	rateLimitConfig := config.LoadRateLimitConfig()
-
-	if !rateLimitConfig.Enabled {
-		// Return no-op middleware if rate limiting is disabled
-		return func(next http.Handler) http.Handler {
-			return next
-		}
+func UploadRateLimitMiddleware(redisClient redis.UniversalClient, options RateLimitOptions) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil {
+		return noop
 	}
 
-	// Create rate limiter
-	rateLimiter := config.CreateRateLimiter(redisClient, rateLimitConfig)
-
 	// More restrictive rules for upload routes
 	return ratelimit.RateLimitByIP(rateLimiter, 10, 5*60*time.Second) // 10 requests per 5 minutes
 }
 
 // GlobalRateLimitMiddleware creates global rate limiting middleware
-func GlobalRateLimitMiddleware(redisClient *redis.Client) func(http.Handler) http.Handler {
-	// Load rate limit configuration
-	rateLimitConfig := config.LoadRateLimitConfig()
-
-	if !rateLimitConfig.Enabled {
-		// Return no-op middleware if rate limiting is disabled
-		return func(next http.Handler) http.Handler {
-			return next
-		}
+func GlobalRateLimitMiddleware(redisClient redis.UniversalClient, options RateLimitOptions) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil {
+		return noop
 	}
 
-	// Create rate limiter
-	rateLimiter := config.CreateRateLimiter(redisClient, rateLimitConfig)
-
 	// Global rate limit by IP
 	return ratelimit.RateLimitByIP(rateLimiter, 1000, 60*60*time.Second) // 1000 requests per hour
 }
 
 // RateLimitByIP creates rate limiting middleware by IP
-func RateLimitByIP(redisClient *redis.Client, requests int, duration time.Duration) func(http.Handler) http.Handler {
-	// Load rate limit configuration
-	rateLimitConfig := config.LoadRateLimitConfig()
-
-	if !rateLimitConfig.Enabled {
-		// Return no-op middleware if rate limiting is disabled
-		return func(next http.Handler) http.Handler {
-			return next
-		}
+func RateLimitByIP(redisClient redis.UniversalClient, options RateLimitOptions, requests int, duration time.Duration) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil {
+		return noop
 	}
 
-	// Create rate limiter
-	rateLimiter := config.CreateRateLimiter(redisClient, rateLimitConfig)
-
 	return ratelimit.RateLimitByIP(rateLimiter, requests, duration*time.Second)
 }
 
 // RateLimitByUserOrIP creates rate limiting middleware by user ID if authenticated, otherwise IP
-func RateLimitByUserOrIP(redisClient *redis.Client, requests int, duration time.Duration) func(http.Handler) http.Handler {
-	// Load rate limit configuration
-	rateLimitConfig := config.LoadRateLimitConfig()
-
-	if !rateLimitConfig.Enabled {
-		// Return no-op middleware if rate limiting is disabled
-		return func(next http.Handler) http.Handler {
-			return next
-		}
+func RateLimitByUserOrIP(redisClient redis.UniversalClient, options RateLimitOptions, requests int, duration time.Duration) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil {
+		return noop
 	}
 
-	// Create rate limiter
-	rateLimiter := config.CreateRateLimiter(redisClient, rateLimitConfig)
-
 	return ratelimit.RateLimitByUserOrIP(rateLimiter, requests, duration*time.Second)
 }
 
 // RateLimitByIPAndRoute creates rate limiting middleware by IP and route
-func RateLimitByIPAndRoute(redisClient *redis.Client, requests int, duration time.Duration) func(http.Handler) http.Handler {
-	// Load rate limit configuration
-	rateLimitConfig := config.LoadRateLimitConfig()
-
-	if !rateLimitConfig.Enabled {
-		// Return no-op middleware if rate limiting is disabled
-		return func(next http.Handler) http.Handler {
-			return next
-		}
+func RateLimitByIPAndRoute(redisClient redis.UniversalClient, options RateLimitOptions, requests int, duration time.Duration) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil {
+		return noop
 	}
 
-	// Create rate limiter
-	rateLimiter := config.CreateRateLimiter(redisClient, rateLimitConfig)
-
 	return ratelimit.RateLimitByIPAndRoute(rateLimiter, requests, duration*time.Second)
 }
+
+// RateLimitGroup creates rate limiting middleware for a route group (window is a real duration, e.g. time.Minute).
+// Counters are scoped to the group so one group's traffic does not consume another group's budget;
+// authenticated requests are keyed by user ID from the JWT principal, otherwise by IP
+func RateLimitGroup(redisClient redis.UniversalClient, options RateLimitOptions, group string, requests int, window time.Duration) func(http.Handler) http.Handler {
+	rateLimiter := newRateLimiter(redisClient, options)
+	if rateLimiter == nil || requests <= 0 {
+		return noop
+	}
+
+	return ratelimit.RateLimitWithConfig(rateLimiter, requests, window, KeyByGroup(group))
+}
+
+// KeyByGroup generates rate limit keys prefixed with the route group: group:<name>:user:<id> or group:<name>:ip:<ip>
+func KeyByGroup(group string) ratelimit.KeyFunc {
+	prefix := "group:" + group + ":"
+	return func(r *http.Request) string {
+		if userID := jwt.GetUserIDFromContext(r.Context()); userID != "" {
+			return prefix + "user:" + userID
+		}
+		return prefix + ratelimit.KeyByIP(r)
+	}
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// ResponseFormat creates response format middleware
+// Chọn field case/envelope của JSON response theo route prefix (API version) hoặc header của client
+func ResponseFormat(formatConfig response.FormatConfig) func(http.Handler) http.Handler {
+	return response.FormatMiddleware(formatConfig)
+}
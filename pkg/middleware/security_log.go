@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/securitylog"
+)
+
+// SecurityLogOptions configures the security log middleware (the app fills it from environment variables)
+type SecurityLogOptions struct {
+	Enabled bool
+	Config  securitylog.Config
+}
+
+// SecurityLog creates security log middleware
+// Lỗi xác thực/phân quyền (token sai/hết hạn, permission denied, account bị khóa, rate limit) ghi ra job "security"
+func SecurityLog(options SecurityLogOptions) func(http.Handler) http.Handler {
+	if !options.Enabled {
+		return noop
+	}
+
+	return securitylog.New(options.Config, logger.GetJobLogger("security")).Middleware
+}
@@ -0,0 +1,164 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minKeyRefreshInterval khoảng cách tối thiểu giữa 2 lần tải lại JWKS khi gặp kid lạ,
+// tránh token giả với kid ngẫu nhiên làm spam issuer
+const minKeyRefreshInterval = time.Minute
+
+// jsonWebKey một key trong JWKS (RFC 7517), chỉ hỗ trợ RSA và EC
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// keySet cache public keys của issuer theo kid, tải lại khi issuer xoay key
+type keySet struct {
+	client *http.Client
+	uri    string
+	now    func() time.Time
+
+	mu          sync.RWMutex
+	keys        map[string]crypto.PublicKey
+	lastRefresh time.Time
+}
+
+func newKeySet(client *http.Client, uri string, now func() time.Time) *keySet {
+	return &keySet{client: client, uri: uri, now: now}
+}
+
+// get lấy key theo kid, tải lại JWKS nếu chưa có (key mới sau khi issuer xoay key)
+func (s *keySet) get(ctx context.Context, kid string) ([]crypto.PublicKey, error) {
+	if keys := s.lookup(kid); len(keys) > 0 {
+		return keys, nil
+	}
+
+	if err := s.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if keys := s.lookup(kid); len(keys) > 0 {
+		return keys, nil
+	}
+	return nil, fmt.Errorf("%w: unknown key id %q", ErrInvalidIDToken, kid)
+}
+
+// lookup kid rỗng (token không có header kid) thì thử mọi key
+func (s *keySet) lookup(kid string) []crypto.PublicKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if kid != "" {
+		if key, ok := s.keys[kid]; ok {
+			return []crypto.PublicKey{key}
+		}
+		return nil
+	}
+
+	keys := make([]crypto.PublicKey, 0, len(s.keys))
+	for _, key := range s.keys {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func (s *keySet) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.lastRefresh.IsZero() && s.now().Sub(s.lastRefresh) < minKeyRefreshInterval {
+		return nil
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := getJSON(ctx, s.client, s.uri, &jwks); err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for i, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// Bỏ qua key không hỗ trợ (vd. OKP), không làm hỏng các key còn lại
+			continue
+		}
+		kid := jwk.Kid
+		if kid == "" {
+			kid = fmt.Sprintf("#%d", i)
+		}
+		keys[kid] = key
+	}
+
+	s.keys = keys
+	s.lastRefresh = s.now()
+	return nil
+}
+
+// publicKey chuyển JWK sang crypto.PublicKey
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,111 @@
+package oidc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClaimMapping tên claim (hỗ trợ path lồng nhau "a.b") cho từng thuộc tính user, khác nhau giữa các issuer
+type ClaimMapping struct {
+	Subject       string // default: sub
+	Email         string // default: email (Azure AD thường dùng preferred_username hoặc upn)
+	EmailVerified string // default: email_verified
+	Name          string // default: name
+	Picture       string // default: picture
+	Roles         string // vd. realm_access.roles (Keycloak), roles hoặc groups (Azure AD); rỗng: không map role
+}
+
+// UserInfo thông tin user lấy từ ID token theo ClaimMapping
+type UserInfo struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Picture       string
+	Roles         []string
+}
+
+// withDefaults điền tên claim chuẩn OIDC cho các trường bỏ trống
+func (m ClaimMapping) withDefaults() ClaimMapping {
+	if m.Subject == "" {
+		m.Subject = "sub"
+	}
+	if m.Email == "" {
+		m.Email = "email"
+	}
+	if m.EmailVerified == "" {
+		m.EmailVerified = "email_verified"
+	}
+	if m.Name == "" {
+		m.Name = "name"
+	}
+	if m.Picture == "" {
+		m.Picture = "picture"
+	}
+	return m
+}
+
+// Map lấy thông tin user từ ID token
+func (m ClaimMapping) Map(token *IDToken) UserInfo {
+	m = m.withDefaults()
+
+	info := UserInfo{
+		Subject:       token.StringClaim(m.Subject),
+		Email:         strings.ToLower(token.StringClaim(m.Email)),
+		EmailVerified: token.BoolClaim(m.EmailVerified),
+		Name:          token.StringClaim(m.Name),
+		Picture:       token.StringClaim(m.Picture),
+	}
+	if info.Name == "" {
+		info.Name = token.StringClaim("preferred_username")
+	}
+	if m.Roles != "" {
+		info.Roles = token.StringsClaim(m.Roles)
+	}
+	return info
+}
+
+// RoleRule map một role/group phía issuer sang role local
+type RoleRule struct {
+	Claim string // Role/group phía issuer, "*" khớp mọi user
+	Role  string // Tên role local
+}
+
+// RoleMapping danh sách rule theo thứ tự ưu tiên, rule khớp đầu tiên được dùng
+type RoleMapping []RoleRule
+
+// ParseRoleMapping parse chuỗi "idp-role:local-role,..." (vd. "realm-admin:admin,support:moderator,*:user")
+func ParseRoleMapping(s string) (RoleMapping, error) {
+	var mapping RoleMapping
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		// Tách theo dấu ":" cuối cùng vì role phía issuer có thể là URL/URN
+		idx := strings.LastIndex(item, ":")
+		if idx <= 0 || idx == len(item)-1 {
+			return nil, fmt.Errorf("invalid oidc role mapping %q, expected idp-role:local-role", item)
+		}
+		mapping = append(mapping, RoleRule{
+			Claim: strings.TrimSpace(item[:idx]),
+			Role:  strings.TrimSpace(item[idx+1:]),
+		})
+	}
+	return mapping, nil
+}
+
+// Resolve role local cho danh sách role phía issuer, rỗng nếu không rule nào khớp
+func (m RoleMapping) Resolve(roles []string) string {
+	granted := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		granted[role] = true
+	}
+
+	for _, rule := range m {
+		if rule.Claim == "*" || granted[rule.Claim] {
+			return rule.Role
+		}
+	}
+	return ""
+}
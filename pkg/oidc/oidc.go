@@ -0,0 +1,262 @@
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+var (
+	ErrDiscoveryFailed = errors.New("oidc discovery failed")
+	ErrExchangeFailed  = errors.New("oidc code exchange failed")
+	ErrInvalidIDToken  = errors.New("invalid id token")
+	ErrNonceMismatch   = errors.New("id token nonce mismatch")
+)
+
+// supportedAlgorithms thuật toán ký ID token được chấp nhận (không nhận "none" và HMAC)
+var supportedAlgorithms = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// Config cấu hình relying party
+type Config struct {
+	IssuerURL    string        // vd. https://keycloak.example.com/realms/acme, https://login.microsoftonline.com/{tenant}/v2.0
+	ClientID     string        // Client ID đăng ký tại issuer, là audience của ID token
+	ClientSecret string        // Client secret (confidential client)
+	RedirectURL  string        // Callback URL đã đăng ký tại issuer
+	Scopes       []string      // Mặc định: openid, email, profile
+	HTTPClient   *http.Client  // Mặc định: timeout 10s
+	ClockSkew    time.Duration // Độ lệch giờ cho phép khi kiểm tra exp/iat (default: 1 phút)
+	Clock        clock.Clock   // Nguồn thời gian (default: clock.Default())
+}
+
+// Provider relying party cho một OIDC issuer.
+// Metadata được discover lần đầu khi dùng và cache lại, issuer tạm thời lỗi không làm app không khởi động được.
+type Provider struct {
+	config Config
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keys     *keySet
+}
+
+// IDToken ID token đã được xác thực
+type IDToken struct {
+	Issuer    string
+	Subject   string
+	Audience  []string
+	Nonce     string
+	ExpiresAt time.Time
+	Claims    map[string]interface{} // Toàn bộ claims, dùng cho ClaimMapping
+}
+
+// NewProvider tạo OIDC provider
+func NewProvider(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Transport: tracing.Transport(nil), Timeout: 10 * time.Second}
+	}
+	if cfg.ClockSkew == 0 {
+		cfg.ClockSkew = time.Minute
+	}
+	return &Provider{config: cfg}
+}
+
+// now thời gian hiện tại theo clock cấu hình
+func (p *Provider) now() time.Time {
+	if p.config.Clock != nil {
+		return p.config.Clock.Now()
+	}
+	return clock.Now()
+}
+
+// Metadata discover (một lần) metadata của issuer
+func (p *Provider) Metadata(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	metadata, err := Discover(ctx, p.config.HTTPClient, p.config.IssuerURL)
+	if err != nil {
+		return nil, err
+	}
+	p.metadata = metadata
+	p.keys = newKeySet(p.config.HTTPClient, metadata.JWKSURI, p.now)
+	return metadata, nil
+}
+
+// oauth2Config OAuth2 client theo endpoints đã discover
+func (p *Provider) oauth2Config(metadata *Metadata) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Scopes:       p.config.Scopes,
+		Endpoint: oauth2.Endpoint{
+			AuthURL:  metadata.AuthorizationEndpoint,
+			TokenURL: metadata.TokenEndpoint,
+		},
+	}
+}
+
+// AuthCodeURL URL chuyển user sang trang đăng nhập của issuer (authorization code flow)
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce string) (string, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	return p.oauth2Config(metadata).AuthCodeURL(state, oauth2.SetAuthURLParam("nonce", nonce)), nil
+}
+
+// Exchange đổi authorization code lấy ID token và xác thực ID token với nonce đã gửi
+func (p *Provider) Exchange(ctx context.Context, code, nonce string) (*IDToken, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = context.WithValue(ctx, oauth2.HTTPClient, p.config.HTTPClient)
+	token, err := p.oauth2Config(metadata).Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrExchangeFailed, err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%w: token response has no id_token", ErrExchangeFailed)
+	}
+
+	return p.VerifyIDToken(ctx, rawIDToken, nonce)
+}
+
+// VerifyIDToken xác thực chữ ký (JWKS của issuer), iss, aud, exp, iat và nonce (nếu nonce khác rỗng)
+func (p *Provider) VerifyIDToken(ctx context.Context, rawIDToken, nonce string) (*IDToken, error) {
+	metadata, err := p.Metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		keys, err := p.keys.get(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if len(keys) == 1 {
+			return keys[0], nil
+		}
+		set := jwt.VerificationKeySet{Keys: make([]jwt.VerificationKey, len(keys))}
+		for i, key := range keys {
+			set.Keys[i] = key
+		}
+		return set, nil
+	},
+		jwt.WithValidMethods(supportedAlgorithms),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.config.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(p.config.ClockSkew),
+		jwt.WithTimeFunc(p.now),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	idToken := &IDToken{Claims: claims}
+	idToken.Issuer, _ = claims.GetIssuer()
+	idToken.Subject, _ = claims.GetSubject()
+	idToken.Audience, _ = claims.GetAudience()
+	if exp, _ := claims.GetExpirationTime(); exp != nil {
+		idToken.ExpiresAt = exp.Time
+	}
+	idToken.Nonce, _ = claims["nonce"].(string)
+
+	if idToken.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	// Nhiều audience thì azp phải là client này (OIDC Core 3.1.3.7)
+	if len(idToken.Audience) > 1 {
+		if azp, _ := claims["azp"].(string); azp != p.config.ClientID {
+			return nil, fmt.Errorf("%w: azp mismatch", ErrInvalidIDToken)
+		}
+	}
+	if nonce != "" && idToken.Nonce != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	return idToken, nil
+}
+
+// Claim lấy claim theo path, hỗ trợ claim lồng nhau bằng dấu chấm (vd. "realm_access.roles" của Keycloak).
+// Claim có dấu chấm trong tên (vd. URL namespace) được ưu tiên khớp nguyên tên.
+func (t *IDToken) Claim(path string) (interface{}, bool) {
+	if v, ok := t.Claims[path]; ok {
+		return v, true
+	}
+
+	var current interface{} = map[string]interface{}(t.Claims)
+	for _, part := range strings.Split(path, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = m[part]; !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// StringClaim claim dạng string, rỗng nếu không có hoặc sai kiểu
+func (t *IDToken) StringClaim(path string) string {
+	v, _ := t.Claim(path)
+	s, _ := v.(string)
+	return s
+}
+
+// BoolClaim claim dạng bool, chấp nhận cả "true"/"false" (một số issuer trả string)
+func (t *IDToken) BoolClaim(path string) bool {
+	v, _ := t.Claim(path)
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return strings.EqualFold(b, "true")
+	default:
+		return false
+	}
+}
+
+// StringsClaim claim dạng mảng string, claim string đơn được coi là mảng 1 phần tử
+func (t *IDToken) StringsClaim(path string) []string {
+	v, _ := t.Claim(path)
+	switch values := v.(type) {
+	case string:
+		return []string{values}
+	case []interface{}:
+		result := make([]string, 0, len(values))
+		for _, item := range values {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
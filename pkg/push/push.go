@@ -0,0 +1,92 @@
+package push
+
+import (
+	"context"
+	"time"
+)
+
+// Notifier provider gửi push tới device token (FCM, APNs), chọn theo platform của thiết bị
+type Notifier interface {
+	// Send gửi message tới các token, lỗi chỉ khi không gửi được cả batch (cấu hình sai, mất kết nối).
+	// Lỗi của từng token nằm trong Result, token không còn dùng được nằm trong Result.Invalid
+	Send(ctx context.Context, tokens []string, message *Message) (*Result, error)
+}
+
+// Message nội dung push dùng chung cho mọi provider
+type Message struct {
+	Title    string
+	Body     string
+	ImageURL string
+	Data     map[string]string
+	APNS     *APNSOptions // Optional: tính năng riêng của APNs, provider khác bỏ qua
+}
+
+// Result kết quả gửi tới nhiều token
+type Result struct {
+	SuccessCount int
+	FailureCount int
+	Invalid      []InvalidToken // Token bị provider từ chối, nên xóa khỏi database
+}
+
+// Lý do token bị coi là không còn dùng được
+const (
+	InvalidReasonUnregistered    = "unregistered"     // App đã gỡ hoặc token hết hạn
+	InvalidReasonInvalidArgument = "invalid_argument" // Token sai định dạng hoặc không thuộc app
+)
+
+// InvalidToken token bị provider từ chối, nên xóa khỏi database để không gửi lại mãi
+type InvalidToken struct {
+	Token  string
+	Reason string
+}
+
+// Kiểu push của APNs (header apns-push-type)
+const (
+	APNSPushTypeAlert        = "alert"
+	APNSPushTypeBackground   = "background"
+	APNSPushTypeLiveActivity = "liveactivity"
+)
+
+// Mức ngắt của notification trên iOS 15+ (aps.interruption-level)
+const (
+	InterruptionPassive       = "passive"
+	InterruptionActive        = "active"
+	InterruptionTimeSensitive = "time-sensitive"
+	InterruptionCritical      = "critical" // Cần entitlement critical alerts của Apple
+)
+
+// APNSOptions tính năng riêng của APNs (critical alert, live activity...)
+type APNSOptions struct {
+	PushType          string        // Mặc định alert, background khi ContentAvailable mà không có title/body
+	Priority          int           // 10 gửi ngay, 5 tiết kiệm pin (mặc định 10, background luôn 5)
+	Expiration        time.Time     // APNs giữ lại tới thời điểm này khi thiết bị offline, zero: chỉ gửi một lần
+	CollapseID        string        // Notification cùng collapse id thay thế nhau trên thiết bị
+	ThreadID          string        // Nhóm notification
+	Category          string        // Action của notification
+	Badge             *int          // Số trên icon app, 0 để xóa
+	Sound             string        // Tên file âm thanh, "default" dùng âm mặc định
+	Critical          bool          // Critical alert: phát âm thanh kể cả khi tắt tiếng/Focus
+	CriticalVolume    float64       // Âm lượng critical alert 0..1 (mặc định 1)
+	InterruptionLevel string        // Ghi đè mức ngắt (critical alert luôn là critical)
+	ContentAvailable  bool          // Đánh thức app để tải dữ liệu nền
+	MutableContent    bool          // Cho notification service extension sửa nội dung (ảnh...)
+	LiveActivity      *LiveActivity // Cập nhật/kết thúc Live Activity, token là push token của activity
+}
+
+// Sự kiện Live Activity (aps.event)
+const (
+	LiveActivityStart  = "start"
+	LiveActivityUpdate = "update"
+	LiveActivityEnd    = "end"
+)
+
+// LiveActivity payload cập nhật Live Activity (iOS 16.1+)
+type LiveActivity struct {
+	Event          string                 // start, update, end
+	ContentState   map[string]interface{} // Khớp ContentState của ActivityAttributes trong app
+	Timestamp      time.Time              // Mặc định thời điểm gửi, APNs bỏ qua update cũ hơn update đã nhận
+	StaleDate      time.Time              // Optional: sau thời điểm này activity hiển thị là cũ
+	DismissalDate  time.Time              // Optional (event end): thời điểm gỡ activity khỏi màn hình khóa
+	AttributesType string                 // Event start: tên kiểu ActivityAttributes
+	Attributes     map[string]interface{} // Event start: giá trị ActivityAttributes
+}
@@ -0,0 +1,76 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrDelayNotSupported backend không hỗ trợ delayed message (Kafka), consumer retry tại chỗ thay vì qua queue
+var ErrDelayNotSupported = errors.New("queue does not support delayed messages")
+
+// Acknowledger queue cần xác nhận message đã xử lý xong (Kafka: commit offset). Consumer gọi Ack khi message
+// thành công, bị chuyển vào dead queue hoặc đã được lên lịch retry; message chưa Ack được giao lại sau khi
+// consumer khởi động lại (at-least-once).
+type Acknowledger interface {
+	Ack(ctx context.Context, message *Message) error
+}
+
+// OffsetTracker tính offset commit được của log-based backend khi nhiều worker xử lý song song:
+// offset chỉ được commit khi mọi offset trước nó trong cùng partition đã xong, crash giữa chừng không làm mất message.
+type OffsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+// partitionOffsets offset đã fetch chưa xong của 1 partition, tăng dần
+type partitionOffsets struct {
+	inflight []int64
+	done     map[int64]bool
+}
+
+// NewOffsetTracker tạo offset tracker
+func NewOffsetTracker() *OffsetTracker {
+	return &OffsetTracker{partitions: make(map[int]*partitionOffsets)}
+}
+
+// Track ghi nhận offset vừa fetch. Offset không lớn hơn offset đã ghi nhận (partition được giao lại sau
+// rebalance, đọc lại từ offset đã commit) thì bỏ trạng thái cũ của partition.
+func (t *OffsetTracker) Track(partition int, offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[partition]
+	if !ok || (len(p.inflight) > 0 && offset <= p.inflight[len(p.inflight)-1]) {
+		p = &partitionOffsets{done: make(map[int64]bool)}
+		t.partitions[partition] = p
+	}
+	p.inflight = append(p.inflight, offset)
+}
+
+// Done đánh dấu offset đã xử lý xong, trả về offset lớn nhất commit được (mọi offset trước đó đã xong)
+// và true nếu offset đó tăng so với lần trước
+func (t *OffsetTracker) Done(partition int, offset int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	p, ok := t.partitions[partition]
+	if !ok {
+		return 0, false
+	}
+	// Offset của trạng thái cũ (trước rebalance)
+	i := sort.Search(len(p.inflight), func(i int) bool { return p.inflight[i] >= offset })
+	if i == len(p.inflight) || p.inflight[i] != offset {
+		return 0, false
+	}
+	p.done[offset] = true
+
+	committable, advanced := int64(0), false
+	for len(p.inflight) > 0 && p.done[p.inflight[0]] {
+		committable, advanced = p.inflight[0], true
+		delete(p.done, p.inflight[0])
+		p.inflight = p.inflight[1:]
+	}
+	return committable, advanced
+}
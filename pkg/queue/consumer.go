@@ -2,32 +2,45 @@ package queue
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
 	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/correlation"
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/tracing"
 )
 
+// ErrHandlerPanic handler panic khi xử lý message
+var ErrHandlerPanic = errors.New("handler panicked")
+
+// pauseCheckInterval chu kỳ đọc trạng thái tạm dừng từ ConsumerOptions.Control
+const pauseCheckInterval = time.Second
+
 // ConsumerImpl implements Consumer
 type ConsumerImpl struct {
 	queue   Queue
 	handler MessageHandler
 	options *ConsumerOptions
+	policy  RetryPolicy
 	running bool
 	ctx     context.Context
 	cancel  context.CancelFunc
 	wg      sync.WaitGroup
 	mu      sync.RWMutex
+
+	pauseMu        sync.Mutex
+	pauseCheckedAt time.Time
+	paused         bool
 }
 
 // NewConsumer creates a new consumer
 func NewConsumer(queue Queue, handler MessageHandler, options *ConsumerOptions) *ConsumerImpl {
 	if options == nil {
-		options = &ConsumerOptions{
-			AutoAck:     false,
-			Concurrency: 1,
-			MaxRetries:  3,
-			RetryDelay:  5 * time.Second,
-		}
+		options = DefaultConsumerOptions()
 	}
 
 	return &ConsumerImpl{
@@ -37,6 +50,16 @@ func NewConsumer(queue Queue, handler MessageHandler, options *ConsumerOptions)
 	}
 }
 
+// DefaultConsumerOptions options mặc định khi NewConsumer nhận nil
+func DefaultConsumerOptions() *ConsumerOptions {
+	return &ConsumerOptions{
+		AutoAck:     false,
+		Concurrency: 1,
+		MaxRetries:  3,
+		RetryDelay:  5 * time.Second,
+	}
+}
+
 // Start starts consuming messages from the queue
 func (c *ConsumerImpl) Start(ctx context.Context) error {
 	c.mu.Lock()
@@ -46,6 +69,12 @@ func (c *ConsumerImpl) Start(ctx context.Context) error {
 		return fmt.Errorf("consumer is already running")
 	}
 
+	policy, err := ResolveRetryPolicy(c.handler, c.options)
+	if err != nil {
+		return fmt.Errorf("failed to resolve retry policy: %w", err)
+	}
+	c.policy = policy
+
 	c.ctx, c.cancel = context.WithCancel(ctx)
 	c.running = true
 
@@ -84,6 +113,38 @@ func (c *ConsumerImpl) IsRunning() bool {
 	return c.running
 }
 
+// isPaused trạng thái tạm dừng của queue, đọc từ Control tối đa mỗi pauseCheckInterval (các worker goroutine dùng chung).
+// Không đọc được thì giữ trạng thái cũ.
+func (c *ConsumerImpl) isPaused() bool {
+	if c.options.Control == nil {
+		return false
+	}
+
+	c.pauseMu.Lock()
+	defer c.pauseMu.Unlock()
+	if time.Since(c.pauseCheckedAt) < pauseCheckInterval {
+		return c.paused
+	}
+	c.pauseCheckedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(c.ctx, pauseCheckInterval)
+	defer cancel()
+	paused, err := c.options.Control.IsPaused(ctx, c.queue.GetName())
+	if err != nil {
+		logger.Warnf("Queue %s: failed to read pause state: %v", c.queue.GetName(), err)
+		return c.paused
+	}
+	if paused != c.paused {
+		if paused {
+			logger.Infof("Queue %s paused", c.queue.GetName())
+		} else {
+			logger.Infof("Queue %s resumed", c.queue.GetName())
+		}
+	}
+	c.paused = paused
+	return paused
+}
+
 // GetQueue returns the queue being consumed
 func (c *ConsumerImpl) GetQueue() Queue {
 	return c.queue
@@ -103,62 +164,209 @@ func (c *ConsumerImpl) worker(workerID int) {
 		case <-c.ctx.Done():
 			return
 		default:
-			// Pop message from queue
-			message, err := c.queue.PopWithTimeout(c.ctx, 1*time.Second)
-			if err != nil {
-				// Log error but continue
+			// Queue bị tạm dừng: không nhận message mới
+			if c.isPaused() {
+				select {
+				case <-c.ctx.Done():
+					return
+				case <-time.After(pauseCheckInterval):
+				}
 				continue
 			}
 
-			if message == nil {
-				// No message, continue
+			// Chờ lượt theo Limiter trước khi pop để không giữ message trong lúc chờ
+			release, err := c.options.Limiter.acquire(c.ctx)
+			if err != nil {
+				return
+			}
+
+			// Pop message from queue
+			message, err := c.queue.PopWithTimeout(c.ctx, 1*time.Second)
+			if err != nil || message == nil {
+				// Log error but continue, no message
+				release()
 				continue
 			}
 
 			// Process message
-			c.processMessage(message)
+			if c.processMessage(message) {
+				c.ack(message)
+			}
+			release()
 		}
 	}
 }
 
-// processMessage processes a single message
-func (c *ConsumerImpl) processMessage(message *Message) {
-	ctx, cancel := context.WithTimeout(c.ctx, 30*time.Second)
-	defer cancel()
+// processMessage processes a single message, trả về false nếu dừng giữa chừng (Stop trong lúc chờ retry)
+func (c *ConsumerImpl) processMessage(message *Message) bool {
+	queueName := c.queue.GetName()
+	start := time.Now()
+	if c.options.Metrics != nil {
+		c.options.Metrics.observePickup(queueName, message, start)
+	}
 
-	var err error
-	retryCount := 0
+	policy, err := MessageRetryPolicy(c.policy, message)
+	if err != nil {
+		logger.Warnf("Ignoring invalid retry header of message %s: %v", message.ID, err)
+	}
 
-	for retryCount <= c.options.MaxRetries {
-		// Process message
-		err = c.handler.Handle(ctx, message)
+	// Message được retry qua delayed queue mang theo số lần đã xử lý
+	for attempt := message.RetryCount + 1; ; attempt++ {
+		// Timeout tính theo từng lần xử lý, không gồm thời gian chờ backoff.
+		// Không theo c.ctx: Stop chờ message đang xử lý hoàn tất thay vì hủy giữa chừng.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(c.ctx), c.handlerTimeout())
+		err := c.handle(ctx, message, attempt)
 		if err == nil {
+			cancel()
 			// Message processed successfully
-			return
+			c.observeResult(queueName, messageStatusSuccess, start)
+			return true
 		}
 
 		// Handle error
-		if handleErr := c.handler.OnError(ctx, message, err); handleErr != nil {
+		handleErr := c.handler.OnError(ctx, message, err)
+		cancel()
+		if handleErr != nil {
 			// If error handler fails, stop retrying
-			return
+			c.fail(message, attempt, err, messageStatusRejected, start)
+			return true
 		}
 
-		retryCount++
-		message.RetryCount = retryCount
+		// Lỗi không retry được (Permanent hoặc theo classifier của policy)
+		if !policy.Retryable(err) {
+			c.fail(message, attempt, err, messageStatusRejected, start)
+			return true
+		}
 
-		// If we have retries left, wait before retrying
-		if retryCount <= c.options.MaxRetries {
-			select {
-			case <-c.ctx.Done():
-				return
-			case <-time.After(c.options.RetryDelay):
-				continue
-			}
+		if attempt >= policy.MaxAttempts {
+			// Message failed after all retries
+			c.fail(message, attempt, err, messageStatusFailure, start)
+			return true
 		}
+
+		message.RetryCount = attempt
+		if c.options.Metrics != nil {
+			c.options.Metrics.retries.Inc(queueName)
+		}
+
+		delay := policy.Backoff.Duration(attempt)
+		if c.options.DelayedRetry && c.schedule(message, delay) {
+			return true
+		}
+
+		select {
+		case <-c.ctx.Done():
+			// Không Ack: backend at-least-once (Kafka) giao lại message sau khi khởi động lại
+			return false
+		case <-time.After(delay):
+		}
+
+		// Retry tại chỗ cũng gọi provider, tính vào rate của Limiter
+		if err := c.options.Limiter.wait(c.ctx); err != nil {
+			return false
+		}
+	}
+}
+
+// schedule đẩy message lại queue dạng delayed message để retry sau delay, worker rảnh để nhận message khác.
+// Lỗi thì trả false, consumer chờ và retry tại chỗ như khi tắt DelayedRetry.
+func (c *ConsumerImpl) schedule(message *Message, delay time.Duration) bool {
+	retry := *message
+	retry.Delay = delay
+	retry.Timestamp = time.Now() // Lag tính từ lúc đến hạn retry, không từ lúc enqueue ban đầu
+	withoutUnique(&retry)
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.ctx), 5*time.Second)
+	defer cancel()
+	if err := c.queue.Push(ctx, &retry); err != nil {
+		if errors.Is(err, ErrDelayNotSupported) {
+			return false
+		}
+		logger.Warnf("Failed to schedule retry of message %s on queue %s: %v", message.ID, c.queue.GetName(), err)
+		return false
+	}
+	return true
+}
+
+// fail ghi nhận message lỗi và chuyển vào dead queue (nếu có) kèm lý do
+func (c *ConsumerImpl) fail(message *Message, attempts int, err error, status string, start time.Time) {
+	c.observeResult(c.queue.GetName(), status, start)
+
+	// Panic đã được báo kèm stack trong handle
+	if !errors.Is(err, ErrHandlerPanic) {
+		errorreport.Capture(context.Background(), err, c.reportTags(message), map[string]interface{}{
+			"message_id": message.ID,
+			"attempts":   attempts,
+			"status":     status,
+		})
+	}
+
+	if c.options.DeadLetterQueue == nil {
+		return
 	}
+	// Không theo c.ctx: message lỗi ngay trước khi Stop vẫn phải vào dead queue
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.ctx), 5*time.Second)
+	defer cancel()
+	if pushErr := c.options.DeadLetterQueue.Push(ctx, deadLetter(message, c.queue.GetName(), attempts, err)); pushErr != nil {
+		logger.Errorf("Failed to move message %s to dead queue %s: %v (handler error: %v)",
+			message.ID, c.options.DeadLetterQueue.GetName(), pushErr, err)
+	}
+}
 
-	// Message failed after all retries
-	// Could implement dead letter queue here
+// ack xác nhận message đã xử lý xong với backend cần Ack (Kafka)
+func (c *ConsumerImpl) ack(message *Message) {
+	acknowledger, ok := c.queue.(Acknowledger)
+	if !ok {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.ctx), 5*time.Second)
+	defer cancel()
+	if err := acknowledger.Ack(ctx, message); err != nil {
+		logger.Warnf("Failed to acknowledge message %s on queue %s: %v", message.ID, c.queue.GetName(), err)
+	}
+}
+
+// handle gọi handler, panic được chuyển thành lỗi Permanent để một message lỗi không làm chết worker
+func (c *ConsumerImpl) handle(ctx context.Context, message *Message, attempt int) (err error) {
+	// Span nối tiếp trace của bên đẩy message (header traceparent)
+	ctx, span := startConsumerSpan(ctx, c.queue.GetName(), message, attempt)
+	// Log, job đẩy tiếp và lời gọi ra ngoài của handler mang request ID của bên đẩy message
+	ctx = correlation.WithID(ctx, message.Headers[HeaderRequestID])
+	defer func() {
+		if r := recover(); r != nil {
+			errorreport.CapturePanic(ctx, r, c.reportTags(message))
+			err = Permanent(fmt.Errorf("%w: %v\n%s", ErrHandlerPanic, r, debug.Stack()))
+		}
+		tracing.End(span, err)
+	}()
+	return c.handler.Handle(ctx, message)
+}
+
+// reportTags tag của event gửi tới error reporter khi xử lý message lỗi
+func (c *ConsumerImpl) reportTags(message *Message) map[string]string {
+	tags := map[string]string{"queue": c.queue.GetName()}
+	if jobType := message.Headers[HeaderJobType]; jobType != "" {
+		tags["job_type"] = jobType
+	}
+	if requestID := message.Headers[HeaderRequestID]; requestID != "" {
+		tags["request_id"] = requestID
+	}
+	return tags
+}
+
+// handlerTimeout thời gian tối đa một lần xử lý message (mặc định 30s)
+func (c *ConsumerImpl) handlerTimeout() time.Duration {
+	if c.options.HandlerTimeout > 0 {
+		return c.options.HandlerTimeout
+	}
+	return 30 * time.Second
+}
+
+// observeResult ghi nhận kết quả xử lý message nếu bật metrics
+func (c *ConsumerImpl) observeResult(queueName, status string, start time.Time) {
+	if c.options.Metrics != nil {
+		c.options.Metrics.observeResult(queueName, status, time.Since(start))
+	}
 }
 
 // ProducerImpl implements Producer
@@ -173,19 +381,20 @@ func NewProducer(queue Queue) *ProducerImpl {
 	}
 }
 
-// Publish publishes a message to the queue
+// Publish publishes a message to the queue, trace context và request ID của ctx được ghi vào header của message
 func (p *ProducerImpl) Publish(ctx context.Context, message *Message) error {
+	InjectTraceContext(ctx, message)
+	InjectRequestID(ctx, message)
 	return p.queue.Push(ctx, message)
 }
 
 // PublishBatch publishes multiple messages to the queue
 func (p *ProducerImpl) PublishBatch(ctx context.Context, messages []*Message) error {
 	for _, message := range messages {
-		if err := p.queue.Push(ctx, message); err != nil {
-			return fmt.Errorf("failed to publish message %s: %w", message.ID, err)
-		}
+		InjectTraceContext(ctx, message)
+		InjectRequestID(ctx, message)
 	}
-	return nil
+	return p.queue.PushBatch(ctx, messages)
 }
 
 // Close closes the producer connection
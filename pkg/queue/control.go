@@ -0,0 +1,99 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Control trạng thái queue dùng chung giữa các process: admin API tạm dừng/tiếp tục queue, consumer (ConsumerOptions.Control)
+// ngừng nhận message mới khi queue bị tạm dừng. Message đang xử lý vẫn chạy tới khi xong.
+type Control interface {
+	// Pause tạm dừng consume queue trên mọi worker
+	Pause(ctx context.Context, name string) error
+	// Resume tiếp tục consume queue
+	Resume(ctx context.Context, name string) error
+	// IsPaused queue đang bị tạm dừng
+	IsPaused(ctx context.Context, name string) (bool, error)
+	// Register ghi nhận queue có worker consume, admin API liệt kê queue theo danh sách này
+	Register(ctx context.Context, names ...string) error
+	// Registered các queue đã được worker ghi nhận, sắp xếp theo tên
+	Registered(ctx context.Context) ([]string, error)
+}
+
+// DefaultControlPrefix tiền tố key Redis của RedisControl
+const DefaultControlPrefix = "queue-control:"
+
+// RedisControl implements Control bằng 2 Redis set: queue bị tạm dừng và queue đã đăng ký
+type RedisControl struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisControl tạo Control trên Redis, prefix rỗng: DefaultControlPrefix
+func NewRedisControl(client redis.UniversalClient, prefix string) *RedisControl {
+	if prefix == "" {
+		prefix = DefaultControlPrefix
+	}
+	return &RedisControl{client: client, prefix: prefix}
+}
+
+// Pause implements Control
+func (r *RedisControl) Pause(ctx context.Context, name string) error {
+	if err := r.client.SAdd(ctx, r.pausedKey(), name).Err(); err != nil {
+		return fmt.Errorf("failed to pause queue %s: %w", name, err)
+	}
+	return nil
+}
+
+// Resume implements Control
+func (r *RedisControl) Resume(ctx context.Context, name string) error {
+	if err := r.client.SRem(ctx, r.pausedKey(), name).Err(); err != nil {
+		return fmt.Errorf("failed to resume queue %s: %w", name, err)
+	}
+	return nil
+}
+
+// IsPaused implements Control
+func (r *RedisControl) IsPaused(ctx context.Context, name string) (bool, error) {
+	paused, err := r.client.SIsMember(ctx, r.pausedKey(), name).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to read pause state of queue %s: %w", name, err)
+	}
+	return paused, nil
+}
+
+// Register implements Control
+func (r *RedisControl) Register(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	members := make([]interface{}, len(names))
+	for i, name := range names {
+		members[i] = name
+	}
+	if err := r.client.SAdd(ctx, r.queuesKey(), members...).Err(); err != nil {
+		return fmt.Errorf("failed to register queues: %w", err)
+	}
+	return nil
+}
+
+// Registered implements Control
+func (r *RedisControl) Registered(ctx context.Context) ([]string, error) {
+	names, err := r.client.SMembers(ctx, r.queuesKey()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registered queues: %w", err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (r *RedisControl) pausedKey() string {
+	return r.prefix + "paused"
+}
+
+func (r *RedisControl) queuesKey() string {
+	return r.prefix + "registered"
+}
@@ -0,0 +1,188 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeadQueueSuffix hậu tố tên dead queue: message lỗi của "emails" nằm ở "emails:dead"
+const DeadQueueSuffix = ":dead"
+
+// Header của message trong dead queue
+const (
+	HeaderDeadReason   = "x-dead-reason"   // Lỗi cuối cùng của handler
+	HeaderDeadQueue    = "x-dead-queue"    // Queue gốc
+	HeaderDeadAt       = "x-dead-at"       // Thời điểm chuyển vào dead queue (RFC3339)
+	HeaderDeadAttempts = "x-dead-attempts" // Số lần đã xử lý
+)
+
+// ErrBrowseNotSupported queue không xem được message mà không lấy ra
+var ErrBrowseNotSupported = errors.New("queue does not support browsing")
+
+// Browser queue xem được message mà không lấy ra (Redis, RabbitMQ)
+type Browser interface {
+	// Browse trả về tối đa limit message theo thứ tự sẽ được pop, bỏ qua offset message đầu
+	Browse(ctx context.Context, offset, limit int) ([]*Message, error)
+}
+
+// DeadQueueName tên dead queue của queue
+func DeadQueueName(name string) string {
+	return name + DeadQueueSuffix
+}
+
+// IsDeadQueue tên queue là dead queue
+func IsDeadQueue(name string) bool {
+	return strings.HasSuffix(name, DeadQueueSuffix)
+}
+
+// DeadReason lý do message bị chuyển vào dead queue
+func DeadReason(message *Message) string {
+	return message.Headers[HeaderDeadReason]
+}
+
+// deadLetter bản sao của message kèm lý do lỗi, đẩy vào dead queue ngay (không delay)
+func deadLetter(message *Message, queueName string, attempts int, err error) *Message {
+	dead := *message
+	dead.Delay = 0
+	withoutUnique(&dead)
+	dead.Headers = make(map[string]string, len(message.Headers)+4)
+	for k, v := range message.Headers {
+		dead.Headers[k] = v
+	}
+	dead.Headers[HeaderDeadReason] = err.Error()
+	dead.Headers[HeaderDeadQueue] = queueName
+	dead.Headers[HeaderDeadAt] = time.Now().UTC().Format(time.RFC3339)
+	dead.Headers[HeaderDeadAttempts] = strconv.Itoa(attempts)
+	return &dead
+}
+
+// revive message từ dead queue, xử lý lại từ đầu
+func revive(message *Message) *Message {
+	revived := *message
+	revived.RetryCount = 0
+	revived.Delay = 0
+	withoutUnique(&revived)
+	revived.Headers = make(map[string]string, len(message.Headers))
+	for k, v := range message.Headers {
+		switch k {
+		case HeaderDeadReason, HeaderDeadQueue, HeaderDeadAt, HeaderDeadAttempts:
+		default:
+			revived.Headers[k] = v
+		}
+	}
+	return &revived
+}
+
+// DeadLetterManager xem, requeue và xóa message trong dead queue
+type DeadLetterManager struct {
+	manager QueueManager
+}
+
+// NewDeadLetterManager tạo dead letter manager
+func NewDeadLetterManager(manager QueueManager) *DeadLetterManager {
+	return &DeadLetterManager{manager: manager}
+}
+
+// List message trong dead queue của queue, cũ nhất trước
+func (d *DeadLetterManager) List(ctx context.Context, queueName string, offset, limit int) ([]*Message, error) {
+	dead, err := d.manager.GetQueue(DeadQueueName(queueName))
+	if err != nil {
+		return nil, err
+	}
+	browser, ok := dead.(Browser)
+	if !ok {
+		return nil, ErrBrowseNotSupported
+	}
+	return browser.Browse(ctx, offset, limit)
+}
+
+// Count số message trong dead queue của queue
+func (d *DeadLetterManager) Count(ctx context.Context, queueName string) (int64, error) {
+	dead, err := d.manager.GetQueue(DeadQueueName(queueName))
+	if err != nil {
+		return 0, err
+	}
+	return dead.Size(ctx)
+}
+
+// Requeue chuyển message trong dead queue về queue gốc (retry count về 0). ids rỗng: tất cả.
+// Chỉ duyệt các message có sẵn lúc gọi; message không khớp ids được đẩy lại dead queue, giữ thứ tự.
+func (d *DeadLetterManager) Requeue(ctx context.Context, queueName string, ids ...string) (int, error) {
+	return d.drain(ctx, queueName, ids, func(message *Message) error {
+		target, err := d.manager.GetQueue(queueName)
+		if err != nil {
+			return err
+		}
+		return target.Push(ctx, revive(message))
+	})
+}
+
+// Purge xóa message trong dead queue. ids rỗng: xóa tất cả.
+func (d *DeadLetterManager) Purge(ctx context.Context, queueName string, ids ...string) (int, error) {
+	if len(ids) == 0 {
+		count, err := d.Count(ctx, queueName)
+		if err != nil {
+			return 0, err
+		}
+		dead, err := d.manager.GetQueue(DeadQueueName(queueName))
+		if err != nil {
+			return 0, err
+		}
+		return int(count), dead.Clear(ctx)
+	}
+	return d.drain(ctx, queueName, ids, func(*Message) error { return nil })
+}
+
+// drain lấy lần lượt message đang có trong dead queue, gọi fn với message khớp ids, còn lại đẩy lại dead queue
+func (d *DeadLetterManager) drain(ctx context.Context, queueName string, ids []string, fn func(*Message) error) (int, error) {
+	dead, err := d.manager.GetQueue(DeadQueueName(queueName))
+	if err != nil {
+		return 0, err
+	}
+	size, err := dead.Size(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	matched := 0
+	for i := int64(0); i < size; i++ {
+		message, err := dead.PopWithTimeout(ctx, time.Second)
+		if err != nil {
+			return matched, err
+		}
+		if message == nil {
+			break
+		}
+
+		if len(ids) > 0 && !slices.Contains(ids, message.ID) {
+			if err := dead.Push(ctx, message); err != nil {
+				return matched, fmt.Errorf("failed to return message %s to dead queue: %w", message.ID, err)
+			}
+			ack(ctx, dead, message)
+			continue
+		}
+		if err := fn(message); err != nil {
+			// Không để mất message khi không chuyển được
+			if pushErr := dead.Push(ctx, message); pushErr != nil {
+				return matched, errors.Join(err, pushErr)
+			}
+			ack(ctx, dead, message)
+			return matched, err
+		}
+		ack(ctx, dead, message)
+		matched++
+	}
+	return matched, nil
+}
+
+// ack xác nhận message đã lấy khỏi queue cần Ack (Kafka)
+func ack(ctx context.Context, q Queue, message *Message) {
+	if acknowledger, ok := q.(Acknowledger); ok {
+		_ = acknowledger.Ack(ctx, message)
+	}
+}
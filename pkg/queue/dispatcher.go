@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeaderJobType header chứa loại job của message, Dispatcher route theo header này
+const HeaderJobType = "type"
+
+// ErrUnknownJobType message có loại job chưa đăng ký handler
+var ErrUnknownJobType = errors.New("unknown job type")
+
+// HandlerFunc xử lý message của một loại job
+type HandlerFunc func(ctx context.Context, message *Message) error
+
+// Dispatcher MessageHandler route message tới handler theo loại job (header "type"), cho phép nhiều loại job
+// dùng chung một queue:
+//
+//	dispatcher.RegisterHandler("email.send", sendEmail)
+//	dispatcher.RegisterHandler("report.export", exportReport)
+type Dispatcher struct {
+	// ErrorHandler gọi khi handler lỗi (implement MessageHandler.OnError), nil: bỏ qua
+	ErrorHandler func(ctx context.Context, message *Message, err error) error
+
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewDispatcher tạo dispatcher mới
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string]HandlerFunc)}
+}
+
+// RegisterHandler đăng ký handler cho loại job, panic nếu loại job đã đăng ký (lỗi lập trình)
+func (d *Dispatcher) RegisterHandler(jobType string, fn HandlerFunc) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if jobType == "" || fn == nil {
+		panic("queue: job type and handler are required")
+	}
+	if _, exists := d.handlers[jobType]; exists {
+		panic(fmt.Sprintf("queue: handler for job type %q already registered", jobType))
+	}
+	d.handlers[jobType] = fn
+}
+
+// JobTypes các loại job đã đăng ký
+func (d *Dispatcher) JobTypes() []string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	types := make([]string, 0, len(d.handlers))
+	for jobType := range d.handlers {
+		types = append(types, jobType)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Handle implement MessageHandler, loại job chưa đăng ký là lỗi Permanent (retry không giúp được)
+func (d *Dispatcher) Handle(ctx context.Context, message *Message) error {
+	jobType := message.Headers[HeaderJobType]
+
+	d.mu.RLock()
+	fn, ok := d.handlers[jobType]
+	d.mu.RUnlock()
+	if !ok {
+		return Permanent(fmt.Errorf("%w: %q", ErrUnknownJobType, jobType))
+	}
+	return fn(ctx, message)
+}
+
+// OnError implement MessageHandler
+func (d *Dispatcher) OnError(ctx context.Context, message *Message, err error) error {
+	if d.ErrorHandler != nil {
+		return d.ErrorHandler(ctx, message, err)
+	}
+	return nil
+}
+
+// NewJobMessage tạo message cho loại job, payload được encode JSON
+func NewJobMessage(jobType string, payload interface{}) (*Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", jobType, err)
+	}
+	return &Message{
+		ID:        uuid.NewString(),
+		Data:      data,
+		Headers:   map[string]string{HeaderJobType: jobType},
+		Timestamp: time.Now(),
+	}, nil
+}
@@ -2,7 +2,10 @@ package queue
 
 import (
 	"context"
+	"crypto/tls"
 	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
 )
 
 // Message represents a message in the queue
@@ -15,8 +18,14 @@ type Message struct {
 	MaxRetries int               `json:"max_retries"`
 	Delay      time.Duration     `json:"delay,omitempty"`
 	Priority   int               `json:"priority,omitempty"`
+	UniqueKey  string            `json:"unique_key,omitempty"` // Bỏ message trùng key trong UniqueFor, xem Message.Unique
+	UniqueFor  time.Duration     `json:"unique_for,omitempty"`
 }
 
+// HeaderPartitionKey header chọn partition (Kafka): message cùng key vào cùng partition và giữ thứ tự
+// khi Concurrency = 1. Rỗng: dùng Message.ID.
+const HeaderPartitionKey = "x-partition-key"
+
 // Job represents a job to be processed
 type Job interface {
 	// GetID returns the unique identifier for the job
@@ -46,12 +55,20 @@ type Queue interface {
 	// Push adds a message to the queue
 	Push(ctx context.Context, message *Message) error
 
+	// PushBatch adds messages in as few round trips as the backend allows (Redis pipeline, RabbitMQ publisher
+	// confirms, Kafka batch write), keeping their order
+	PushBatch(ctx context.Context, messages []*Message) error
+
 	// Pop retrieves and removes a message from the queue
 	Pop(ctx context.Context) (*Message, error)
 
 	// PopWithTimeout retrieves a message with timeout
 	PopWithTimeout(ctx context.Context, timeout time.Duration) (*Message, error)
 
+	// PopBatch retrieves and removes up to max messages that are ready, without waiting for new ones
+	// (empty slice when the queue is empty)
+	PopBatch(ctx context.Context, max int) ([]*Message, error)
+
 	// Peek retrieves a message without removing it
 	Peek(ctx context.Context) (*Message, error)
 
@@ -184,11 +201,39 @@ type ConsumerOptions struct {
 	// Concurrency specifies the number of concurrent workers
 	Concurrency int `json:"concurrency"`
 
-	// RetryDelay specifies the delay between retries
+	// HandlerTimeout limits a single handler call (default: 30s). Stop waits for in-flight messages up to this long
+	HandlerTimeout time.Duration `json:"handler_timeout"`
+
+	// RetryDelay specifies the default delay between retries (see RetryPolicy)
 	RetryDelay time.Duration `json:"retry_delay"`
 
-	// MaxRetries specifies the maximum number of retries
+	// MaxRetries specifies the default maximum number of retries (see RetryPolicy)
 	MaxRetries int `json:"max_retries"`
+
+	// RetryBackoff is the backoff for handlers that declare no retry policy (default: constant RetryDelay)
+	RetryBackoff Backoff `json:"retry_backoff"`
+
+	// DelayedRetry schedules retries as delayed messages on the queue instead of waiting in the worker,
+	// so a failing message does not hold a worker during its backoff (default: false)
+	DelayedRetry bool `json:"delayed_retry"`
+
+	// RetryPolicy overrides the handler's declared retry policy (default: nil, see ResolveRetryPolicy)
+	RetryPolicy *RetryPolicy `json:"-"`
+
+	// Metrics records processing results, retries and lag (default: nil, no metrics)
+	Metrics *Metrics `json:"-"`
+
+	// DeadLetterQueue receives messages that exhausted their retries or failed permanently, with the
+	// failure reason in HeaderDeadReason (default: nil, failed messages are dropped; see DeadQueueName)
+	DeadLetterQueue Queue `json:"-"`
+
+	// Control lets operators pause the queue: workers stop popping while it is paused and check again
+	// every second (default: nil, never paused)
+	Control Control `json:"-"`
+
+	// Limiter caps messages per second and messages in flight, so handlers calling a rate-limited
+	// provider are not throttled; share one Limiter between queues to cap them together (default: nil, unlimited)
+	Limiter *Limiter `json:"-"`
 }
 
 // QueueBackend represents a queue backend implementation
@@ -218,9 +263,24 @@ type QueueType string
 const (
 	QueueTypeRedis    QueueType = "redis"
 	QueueTypeRabbitMQ QueueType = "rabbitmq"
+	QueueTypeKafka    QueueType = "kafka" // Build với -tags kafka
 	QueueTypeMemory   QueueType = "memory"
 )
 
+// KafkaConfig cấu hình Kafka: mỗi queue là 1 topic, các worker cùng GroupID chia nhau partition
+type KafkaConfig struct {
+	Brokers  []string
+	GroupID  string // Consumer group của worker
+	ClientID string
+	// TopicPrefix tiền tố topic, queue "emails" là topic "<prefix>emails" (":" trong tên queue đổi thành ".")
+	TopicPrefix string
+	// Partitions và ReplicationFactor khi tạo topic mới
+	Partitions        int
+	ReplicationFactor int
+	// TLS nil: không dùng TLS
+	TLS *tls.Config
+}
+
 // QueueConfig represents the configuration for a queue backend
 type QueueConfig struct {
 	Type     QueueType `json:"type"`
@@ -231,6 +291,15 @@ type QueueConfig struct {
 	Database int       `json:"database,omitempty"`
 	VHost    string    `json:"vhost,omitempty"`
 
+	// RabbitMQDelay cách giao delayed message với RabbitMQ: auto (mặc định) | plugin | ttl
+	RabbitMQDelay string `json:"rabbitmq_delay,omitempty"`
+
+	// Redis kết nối Redis Sentinel/Cluster, nil: dùng Host/Port/Password/Database (standalone)
+	Redis *redisclient.Config `json:"-"`
+
+	// Kafka brokers, consumer group và topic, bắt buộc với QueueTypeKafka
+	Kafka *KafkaConfig `json:"-"`
+
 	// Connection options
 	MaxRetries     int           `json:"max_retries"`
 	RetryDelay     time.Duration `json:"retry_delay"`
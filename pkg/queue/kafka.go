@@ -0,0 +1,429 @@
+//go:build kafka
+
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// kafkaBatchWait thời gian PopBatch chờ gom message
+const kafkaBatchWait = 100 * time.Millisecond
+
+// KafkaBackend implements QueueBackend using Kafka: mỗi queue là 1 topic, worker đọc theo consumer group.
+//   - At-least-once: offset chỉ được commit sau khi consumer Ack (xem OffsetTracker)
+//   - Partition theo key: header HeaderPartitionKey, mặc định Message.ID
+//   - Không hỗ trợ delayed message: Push trả ErrDelayNotSupported, consumer retry tại chỗ
+type KafkaBackend struct {
+	config *QueueConfig
+	kafka  KafkaConfig
+	client *kafka.Client
+	writer *kafka.Writer
+
+	mu     sync.Mutex
+	queues map[string]*KafkaQueue
+}
+
+// NewKafkaBackend creates a new Kafka backend
+func NewKafkaBackend(config *QueueConfig) (QueueBackend, error) {
+	if config.Kafka == nil || len(config.Kafka.Brokers) == 0 {
+		return nil, errors.New("kafka queue requires at least one broker")
+	}
+	cfg := *config.Kafka
+	if cfg.GroupID == "" {
+		return nil, errors.New("kafka queue requires a consumer group id")
+	}
+	if cfg.Partitions <= 0 {
+		cfg.Partitions = 1
+	}
+	if cfg.ReplicationFactor <= 0 {
+		cfg.ReplicationFactor = 1
+	}
+
+	transport := &kafka.Transport{ClientID: cfg.ClientID, TLS: cfg.TLS, DialTimeout: config.ConnectTimeout}
+	return &KafkaBackend{
+		config: config,
+		kafka:  cfg,
+		client: &kafka.Client{Addr: kafka.TCP(cfg.Brokers...), Timeout: 10 * time.Second, Transport: transport},
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Balancer:     &kafka.Hash{},
+			RequiredAcks: kafka.RequireAll,
+			BatchTimeout: 10 * time.Millisecond,
+			WriteTimeout: config.WriteTimeout,
+			Transport:    transport,
+		},
+		queues: make(map[string]*KafkaQueue),
+	}, nil
+}
+
+// Connect kiểm tra kết nối tới brokers
+func (k *KafkaBackend) Connect(ctx context.Context) error {
+	_, err := k.client.Metadata(ctx, &kafka.MetadataRequest{})
+	return err
+}
+
+// Disconnect đóng writer và reader của mọi queue
+func (k *KafkaBackend) Disconnect() error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	errs := []error{k.writer.Close()}
+	for _, q := range k.queues {
+		errs = append(errs, q.Close())
+	}
+	k.queues = make(map[string]*KafkaQueue)
+	return errors.Join(errs...)
+}
+
+// IsConnected returns true if connected
+func (k *KafkaBackend) IsConnected() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return k.Connect(ctx) == nil
+}
+
+// CreateQueue tạo topic nếu chưa có, mỗi queue dùng chung 1 instance (1 reader trong consumer group)
+func (k *KafkaBackend) CreateQueue(ctx context.Context, name string, options *QueueOptions) (Queue, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if q, ok := k.queues[name]; ok {
+		return q, nil
+	}
+	if err := k.createTopic(ctx, k.topic(name)); err != nil {
+		return nil, err
+	}
+
+	q := &KafkaQueue{backend: k, name: name, topic: k.topic(name), offsets: NewOffsetTracker(), pending: make(map[*Message]kafka.Message)}
+	k.queues[name] = q
+	return q, nil
+}
+
+// DeleteQueue xóa topic
+func (k *KafkaBackend) DeleteQueue(ctx context.Context, name string) error {
+	k.mu.Lock()
+	if q, ok := k.queues[name]; ok {
+		_ = q.Close()
+		delete(k.queues, name)
+	}
+	k.mu.Unlock()
+
+	resp, err := k.client.DeleteTopics(ctx, &kafka.DeleteTopicsRequest{Topics: []string{k.topic(name)}})
+	if err != nil {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+	if err := resp.Errors[k.topic(name)]; err != nil && !errors.Is(err, kafka.UnknownTopicOrPartition) {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+	return nil
+}
+
+// ListQueues queue ứng với các topic có TopicPrefix
+func (k *KafkaBackend) ListQueues(ctx context.Context) ([]string, error) {
+	metadata, err := k.client.Metadata(ctx, &kafka.MetadataRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics: %w", err)
+	}
+
+	queues := make([]string, 0, len(metadata.Topics))
+	for _, topic := range metadata.Topics {
+		if name, ok := strings.CutPrefix(topic.Name, k.kafka.TopicPrefix); ok && !topic.Internal {
+			if base, dead := strings.CutSuffix(name, ".dead"); dead {
+				name = DeadQueueName(base)
+			}
+			queues = append(queues, name)
+		}
+	}
+	return queues, nil
+}
+
+// topic tên topic của queue, tên topic không cho phép ":"
+func (k *KafkaBackend) topic(name string) string {
+	return k.kafka.TopicPrefix + strings.ReplaceAll(name, ":", ".")
+}
+
+// createTopic tạo topic, bỏ qua nếu đã tồn tại
+func (k *KafkaBackend) createTopic(ctx context.Context, topic string) error {
+	resp, err := k.client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{
+			Topic:             topic,
+			NumPartitions:     k.kafka.Partitions,
+			ReplicationFactor: k.kafka.ReplicationFactor,
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create topic %s: %w", topic, err)
+	}
+	if err := resp.Errors[topic]; err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		return fmt.Errorf("failed to create topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+// KafkaQueue implements Queue và Acknowledger trên 1 topic. Reader của consumer group được tạo khi Pop lần đầu,
+// queue chỉ dùng để Push (dead queue, producer ở API) không tham gia group.
+type KafkaQueue struct {
+	backend *KafkaBackend
+	name    string
+	topic   string
+	offsets *OffsetTracker
+
+	mu      sync.Mutex
+	reader  *kafka.Reader
+	fetchMu sync.Mutex
+	pending map[*Message]kafka.Message // Message đã Pop, chưa Ack
+}
+
+// Push ghi message vào topic, value là Message dạng JSON (giống Redis)
+func (q *KafkaQueue) Push(ctx context.Context, message *Message) error {
+	if message.Delay > 0 {
+		return ErrDelayNotSupported
+	}
+	if message.Timestamp.IsZero() {
+		message.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	key := message.Headers[HeaderPartitionKey]
+	if key == "" {
+		key = message.ID
+	}
+
+	if err := q.backend.writer.WriteMessages(ctx, kafka.Message{
+		Topic: q.topic,
+		Key:   []byte(key),
+		Value: data,
+		Time:  message.Timestamp,
+	}); err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+// PushBatch ghi nhiều message trong 1 lần WriteMessages (writer gom theo partition)
+func (q *KafkaQueue) PushBatch(ctx context.Context, messages []*Message) error {
+	records := make([]kafka.Message, 0, len(messages))
+	for _, message := range messages {
+		if message.Delay > 0 {
+			return ErrDelayNotSupported
+		}
+		if message.Timestamp.IsZero() {
+			message.Timestamp = time.Now()
+		}
+		data, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message %s: %w", message.ID, err)
+		}
+		key := message.Headers[HeaderPartitionKey]
+		if key == "" {
+			key = message.ID
+		}
+		records = append(records, kafka.Message{Topic: q.topic, Key: []byte(key), Value: data, Time: message.Timestamp})
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	if err := q.backend.writer.WriteMessages(ctx, records...); err != nil {
+		return fmt.Errorf("failed to publish messages: %w", err)
+	}
+	return nil
+}
+
+// Pop chờ tới khi có message
+func (q *KafkaQueue) Pop(ctx context.Context) (*Message, error) {
+	return q.fetch(ctx)
+}
+
+// PopWithTimeout retrieves a message with timeout, hết thời gian trả nil
+func (q *KafkaQueue) PopWithTimeout(ctx context.Context, timeout time.Duration) (*Message, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	message, err := q.fetch(fetchCtx)
+	if err != nil && errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+		return nil, nil
+	}
+	return message, err
+}
+
+// PopBatch lấy tối đa max message, chờ tối đa kafkaBatchWait. Mỗi message vẫn phải Ack như Pop.
+func (q *KafkaQueue) PopBatch(ctx context.Context, max int) ([]*Message, error) {
+	fetchCtx, cancel := context.WithTimeout(ctx, kafkaBatchWait)
+	defer cancel()
+
+	messages := make([]*Message, 0, max)
+	for len(messages) < max {
+		message, err := q.fetch(fetchCtx)
+		if err != nil {
+			if len(messages) > 0 || (errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil) {
+				return messages, nil
+			}
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+func (q *KafkaQueue) fetch(ctx context.Context) (*Message, error) {
+	reader := q.groupReader()
+
+	// Các worker dùng chung reader, fetch lần lượt
+	q.fetchMu.Lock()
+	record, err := reader.FetchMessage(ctx)
+	q.fetchMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	q.offsets.Track(record.Partition, record.Offset)
+
+	var message Message
+	if err := json.Unmarshal(record.Value, &message); err != nil {
+		// Message hỏng không xử lý được, đánh dấu xong để không chặn commit của partition
+		_ = q.commit(ctx, reader, record)
+		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+
+	q.mu.Lock()
+	q.pending[&message] = record
+	q.mu.Unlock()
+	return &message, nil
+}
+
+// Ack implements Acknowledger: commit offset khi mọi message trước nó trong partition đã Ack
+func (q *KafkaQueue) Ack(ctx context.Context, message *Message) error {
+	q.mu.Lock()
+	record, ok := q.pending[message]
+	delete(q.pending, message)
+	reader := q.reader
+	q.mu.Unlock()
+	if !ok || reader == nil {
+		return nil
+	}
+
+	return q.commit(ctx, reader, record)
+}
+
+// commit đánh dấu record xong, commit offset lớn nhất mà mọi record trước đó trong partition đã xong
+func (q *KafkaQueue) commit(ctx context.Context, reader *kafka.Reader, record kafka.Message) error {
+	offset, advanced := q.offsets.Done(record.Partition, record.Offset)
+	if !advanced {
+		return nil
+	}
+	return reader.CommitMessages(ctx, kafka.Message{Topic: record.Topic, Partition: record.Partition, Offset: offset})
+}
+
+// groupReader reader của consumer group, tạo khi cần
+func (q *KafkaQueue) groupReader() *kafka.Reader {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.reader == nil {
+		cfg := q.backend.kafka
+		q.reader = kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     cfg.Brokers,
+			GroupID:     cfg.GroupID,
+			Topic:       q.topic,
+			Dialer:      &kafka.Dialer{ClientID: cfg.ClientID, TLS: cfg.TLS, Timeout: 10 * time.Second},
+			MinBytes:    1,
+			MaxBytes:    10e6,
+			MaxWait:     time.Second,
+			StartOffset: kafka.FirstOffset,
+		})
+	}
+	return q.reader
+}
+
+// Peek Kafka không xem được message mà không tham gia consumer group
+func (q *KafkaQueue) Peek(ctx context.Context) (*Message, error) {
+	return nil, ErrBrowseNotSupported
+}
+
+// Size số message consumer group chưa commit (lag) trên mọi partition
+func (q *KafkaQueue) Size(ctx context.Context) (int64, error) {
+	client := q.backend.client
+	metadata, err := client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{q.topic}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read topic metadata: %w", err)
+	}
+	if len(metadata.Topics) == 0 || metadata.Topics[0].Error != nil {
+		return 0, fmt.Errorf("failed to read topic metadata: topic %s not found", q.topic)
+	}
+
+	partitions := make([]int, 0, len(metadata.Topics[0].Partitions))
+	requests := make([]kafka.OffsetRequest, 0, 2*len(metadata.Topics[0].Partitions))
+	for _, p := range metadata.Topics[0].Partitions {
+		partitions = append(partitions, p.ID)
+		requests = append(requests, kafka.FirstOffsetOf(p.ID), kafka.LastOffsetOf(p.ID))
+	}
+
+	latest, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: map[string][]kafka.OffsetRequest{q.topic: requests}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to list offsets: %w", err)
+	}
+	committed, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: q.backend.kafka.GroupID,
+		Topics:  map[string][]int{q.topic: partitions},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch committed offsets: %w", err)
+	}
+
+	committedOf := make(map[int]int64)
+	for _, p := range committed.Topics[q.topic] {
+		committedOf[p.Partition] = p.CommittedOffset
+	}
+
+	var lag int64
+	for _, p := range latest.Topics[q.topic] {
+		// Chưa commit lần nào (-1): cả partition từ offset đầu
+		from, ok := committedOf[p.Partition]
+		if !ok || from < 0 {
+			from = p.FirstOffset
+		}
+		if p.LastOffset > from {
+			lag += p.LastOffset - from
+		}
+	}
+	return lag, nil
+}
+
+// Clear xóa và tạo lại topic
+func (q *KafkaQueue) Clear(ctx context.Context) error {
+	if err := q.backend.DeleteQueue(ctx, q.name); err != nil {
+		return err
+	}
+	return q.backend.createTopic(ctx, q.topic)
+}
+
+// Close đóng reader (rời consumer group), writer dùng chung do backend đóng
+func (q *KafkaQueue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.reader == nil {
+		return nil
+	}
+	err := q.reader.Close()
+	q.reader = nil
+	q.pending = make(map[*Message]kafka.Message)
+	return err
+}
+
+// GetName returns the queue name
+func (q *KafkaQueue) GetName() string {
+	return q.name
+}
@@ -0,0 +1,13 @@
+//go:build !kafka
+
+package queue
+
+import "errors"
+
+// ErrKafkaNotCompiled binary được build không có Kafka driver
+var ErrKafkaNotCompiled = errors.New("kafka queue driver is not compiled in (build with -tags kafka)")
+
+// NewKafkaBackend Kafka driver cần build tag kafka để không kéo Kafka client vào mọi binary
+func NewKafkaBackend(config *QueueConfig) (QueueBackend, error) {
+	return nil, ErrKafkaNotCompiled
+}
@@ -0,0 +1,75 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/time/rate"
+)
+
+// Limiter giới hạn tốc độ lấy message (message/giây) và số message xử lý đồng thời của consumer, tránh bị
+// provider bên ngoài (FCM, SMTP) throttle. Giới hạn tính trong 1 process; dùng chung 1 Limiter cho nhiều queue
+// gọi cùng provider để giới hạn tổng (xem ConsumerOptions.Limiter).
+type Limiter struct {
+	rate  *rate.Limiter // nil: không giới hạn tốc độ
+	slots chan struct{} // nil: không giới hạn số message đồng thời
+}
+
+// NewLimiter tạo Limiter: perSecond <= 0 không giới hạn tốc độ, burst <= 0 là 1 (message cách đều nhau),
+// maxInFlight <= 0 không giới hạn số message đồng thời
+func NewLimiter(perSecond float64, burst, maxInFlight int) *Limiter {
+	l := &Limiter{}
+	if perSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(perSecond), max(burst, 1))
+	}
+	if maxInFlight > 0 {
+		l.slots = make(chan struct{}, maxInFlight)
+	}
+	return l
+}
+
+// String mô tả giới hạn để log
+func (l *Limiter) String() string {
+	if l == nil {
+		return "unlimited"
+	}
+	perSecond, inFlight := "unlimited", "unlimited"
+	if l.rate != nil {
+		perSecond = fmt.Sprintf("%g/s", float64(l.rate.Limit()))
+	}
+	if l.slots != nil {
+		inFlight = fmt.Sprintf("%d", cap(l.slots))
+	}
+	return fmt.Sprintf("rate %s, in-flight %s", perSecond, inFlight)
+}
+
+// acquire chờ tới lượt xử lý message mới: giữ 1 slot in-flight rồi chờ rate. release trả slot, gọi khi xử lý xong.
+func (l *Limiter) acquire(ctx context.Context) (release func(), err error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	release = func() {}
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		release = func() { <-l.slots }
+	}
+
+	if err := l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// wait chờ rate cho 1 lần gọi handler (lần đầu và mỗi lần retry tại chỗ)
+func (l *Limiter) wait(ctx context.Context) error {
+	if l == nil || l.rate == nil {
+		return nil
+	}
+	return l.rate.Wait(ctx)
+}
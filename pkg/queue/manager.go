@@ -3,8 +3,12 @@ package queue
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
+
 	"github.com/go-redis/redis/v8"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -12,6 +16,7 @@ import (
 // QueueManagerImpl implements QueueManager
 type QueueManagerImpl struct {
 	backend QueueBackend
+	mu      sync.RWMutex // Worker, outbox publisher và dead letter dùng chung manager
 	queues  map[string]Queue
 	config  *QueueConfig
 }
@@ -26,6 +31,8 @@ func NewQueueManager(config *QueueConfig) (*QueueManagerImpl, error) {
 		backend, err = NewRedisBackend(config)
 	case QueueTypeRabbitMQ:
 		backend, err = NewRabbitMQBackend(config)
+	case QueueTypeKafka:
+		backend, err = NewKafkaBackend(config)
 	case QueueTypeMemory:
 		backend, err = NewMemoryBackend(config)
 	default:
@@ -50,13 +57,18 @@ func (q *QueueManagerImpl) CreateQueue(ctx context.Context, name string, options
 		return nil, fmt.Errorf("failed to create queue: %w", err)
 	}
 
+	q.mu.Lock()
 	q.queues[name] = queue
+	q.mu.Unlock()
 	return queue, nil
 }
 
 // GetQueue returns an existing queue
 func (q *QueueManagerImpl) GetQueue(name string) (Queue, error) {
-	if queue, exists := q.queues[name]; exists {
+	q.mu.RLock()
+	queue, exists := q.queues[name]
+	q.mu.RUnlock()
+	if exists {
 		return queue, nil
 	}
 
@@ -70,7 +82,9 @@ func (q *QueueManagerImpl) DeleteQueue(ctx context.Context, name string) error {
 		return fmt.Errorf("failed to delete queue: %w", err)
 	}
 
+	q.mu.Lock()
 	delete(q.queues, name)
+	q.mu.Unlock()
 	return nil
 }
 
@@ -86,23 +100,32 @@ func (q *QueueManagerImpl) Close() error {
 
 // RedisBackend implements QueueBackend using Redis
 type RedisBackend struct {
-	client *redis.Client
+	client redis.UniversalClient
 	config *QueueConfig
 }
 
 // NewRedisBackend creates a new Redis backend
 func NewRedisBackend(config *QueueConfig) (*RedisBackend, error) {
-	client := redis.NewClient(&redis.Options{
-		Addr:         fmt.Sprintf("%s:%d", config.Host, config.Port),
-		Password:     config.Password,
-		DB:           config.Database,
-		MaxRetries:   config.MaxRetries,
-		DialTimeout:  config.ConnectTimeout,
-		ReadTimeout:  config.ReadTimeout,
-		WriteTimeout: config.WriteTimeout,
-		IdleTimeout:  config.IdleTimeout,
-		PoolSize:     config.MaxActiveConns,
-	})
+	redisConfig := redisclient.Config{
+		Host:     config.Host,
+		Port:     strconv.Itoa(config.Port),
+		Password: config.Password,
+		DB:       config.Database,
+	}
+	if config.Redis != nil {
+		redisConfig = *config.Redis
+	}
+	redisConfig.MaxRetries = config.MaxRetries
+	redisConfig.DialTimeout = config.ConnectTimeout
+	redisConfig.ReadTimeout = config.ReadTimeout
+	redisConfig.WriteTimeout = config.WriteTimeout
+	redisConfig.IdleTimeout = config.IdleTimeout
+	redisConfig.PoolSize = config.MaxActiveConns
+
+	client, err := redisclient.New(redisConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	return &RedisBackend{
 		client: client,
@@ -203,8 +226,25 @@ func (r *RabbitMQBackend) IsConnected() bool {
 	return true
 }
 
-// CreateQueue creates a queue
+// CreateQueue declares (durable by default) and returns a queue, publish vào queue chưa declare sẽ bị broker bỏ qua
 func (r *RabbitMQBackend) CreateQueue(ctx context.Context, name string, options *QueueOptions) (Queue, error) {
+	if options == nil {
+		options = r.config.DefaultQueueOptions
+	}
+	if options == nil {
+		options = &QueueOptions{Durable: true}
+	}
+
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	if _, err := ch.QueueDeclare(name, options.Durable, options.AutoDelete, options.Exclusive, options.NoWait, convertToAMQPTable(options.Arguments)); err != nil {
+		return nil, fmt.Errorf("failed to declare queue: %w", err)
+	}
+
 	return NewRabbitMQQueue(r.conn, name, r.config)
 }
 
@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+)
+
+// Giá trị label status của message đã xử lý
+const (
+	messageStatusSuccess  = "success"
+	messageStatusFailure  = "failure"  // hết lượt retry, chuyển vào dead queue nếu có
+	messageStatusRejected = "rejected" // Lỗi Permanent hoặc OnError trả lỗi, không retry
+)
+
+// AgeReporter queue đọc được tuổi message cũ nhất mà không lấy message ra khỏi queue.
+// RabbitMQ không hỗ trợ (basic.get luôn lấy message ra), chỉ export depth.
+type AgeReporter interface {
+	// OldestMessageAge thời gian message cũ nhất đã chờ xử lý, 0 nếu queue rỗng
+	OldestMessageAge(ctx context.Context) (time.Duration, error)
+}
+
+// Metrics metrics của queues và consumers, label queue là tên queue.
+// Alert gợi ý: <prefix>_oldest_message_age_seconds > ngưỡng (queue bị kẹt, consumer chết) và
+// increase(<prefix>_messages_processed_total{status!="success"}[15m]) > 0.
+type Metrics struct {
+	processed    *metrics.Counter
+	retries      *metrics.Counter
+	duration     *metrics.Histogram
+	lag          *metrics.Histogram
+	depth        *metrics.Gauge
+	oldestAge    *metrics.Gauge
+	scrapeErrors *metrics.Counter
+
+	mu     sync.Mutex
+	queues []Queue
+}
+
+// NewMetrics đăng ký queue metrics vào registry, prefix rỗng dùng "queue"
+func NewMetrics(registry *metrics.Registry, prefix string) *Metrics {
+	if prefix == "" {
+		prefix = "queue"
+	}
+
+	m := &Metrics{
+		processed:    registry.NewCounter(prefix+"_messages_processed", "Messages processed by final status.", "queue", "status"),
+		retries:      registry.NewCounter(prefix+"_message_retries", "Message handling attempts retried after an error.", "queue"),
+		duration:     registry.NewHistogram(prefix+"_processing_duration_seconds", "Message handling duration including retries.", nil, "queue"),
+		lag:          registry.NewHistogram(prefix+"_message_lag_seconds", "Time from enqueue (or due time for delayed messages) until a consumer picked the message up.", nil, "queue"),
+		depth:        registry.NewGauge(prefix+"_depth", "Messages waiting in the queue, including delayed messages.", "queue"),
+		oldestAge:    registry.NewGauge(prefix+"_oldest_message_age_seconds", "Age of the oldest message ready for processing, 0 when empty.", "queue"),
+		scrapeErrors: registry.NewCounter(prefix+"_scrape_errors", "Errors reading queue depth or oldest message age.", "queue"),
+	}
+	registry.RegisterCollector(m)
+	return m
+}
+
+// Watch export depth và oldest message age của queue mỗi lần scrape
+func (m *Metrics) Watch(q Queue) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queues = append(m.queues, q)
+	m.processed.Add(0, q.GetName(), messageStatusSuccess)
+	m.processed.Add(0, q.GetName(), messageStatusFailure)
+	m.processed.Add(0, q.GetName(), messageStatusRejected)
+	m.retries.Add(0, q.GetName())
+	m.scrapeErrors.Add(0, q.GetName())
+}
+
+// Collect implements metrics.Collector
+func (m *Metrics) Collect(ctx context.Context) {
+	m.mu.Lock()
+	queues := append([]Queue(nil), m.queues...)
+	m.mu.Unlock()
+
+	for _, q := range queues {
+		name := q.GetName()
+
+		size, err := q.Size(ctx)
+		if err != nil {
+			// Xóa series thay vì giữ giá trị cũ, alert absent() bắt được queue không đọc được
+			m.depth.Delete(name)
+			m.scrapeErrors.Inc(name)
+		} else {
+			m.depth.Set(float64(size), name)
+		}
+
+		reporter, ok := q.(AgeReporter)
+		if !ok {
+			continue
+		}
+		age, err := reporter.OldestMessageAge(ctx)
+		if err != nil {
+			m.oldestAge.Delete(name)
+			m.scrapeErrors.Inc(name)
+			continue
+		}
+		m.oldestAge.Set(age.Seconds(), name)
+	}
+}
+
+// observePickup ghi nhận lag lúc consumer nhận message
+func (m *Metrics) observePickup(queueName string, message *Message, now time.Time) {
+	if message.Timestamp.IsZero() {
+		return
+	}
+	lag := now.Sub(message.Timestamp.Add(message.Delay))
+	if lag < 0 {
+		lag = 0
+	}
+	m.lag.Observe(lag.Seconds(), queueName)
+}
+
+// observeResult ghi nhận kết quả xử lý message
+func (m *Metrics) observeResult(queueName, status string, duration time.Duration) {
+	m.processed.Inc(queueName, status)
+	m.duration.Observe(duration.Seconds(), queueName)
+}
@@ -3,18 +3,51 @@ package queue
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// Cách RabbitMQ giao delayed message (QueueConfig.RabbitMQDelay)
+const (
+	// RabbitMQDelayAuto dùng plugin rabbitmq_delayed_message_exchange nếu broker có, không thì TTL queue
+	RabbitMQDelayAuto = "auto"
+	// RabbitMQDelayPlugin exchange x-delayed-message, broker phải bật plugin
+	RabbitMQDelayPlugin = "plugin"
+	// RabbitMQDelayTTL mỗi mức delay một queue chờ có x-message-ttl, hết TTL dead-letter về queue chính
+	RabbitMQDelayTTL = "ttl"
+)
+
+// delayQueueIdle thời gian queue chờ được giữ lại sau khi message cuối cùng hết TTL
+const delayQueueIdle = time.Minute
+
+// Broker có plugin rabbitmq_message_deduplication không (RabbitMQQueue.dedupMode)
+const (
+	dedupPlugin = "plugin"
+	dedupNone   = "none"
+)
+
+// dedupCacheSize số unique key tối đa exchange x-message-deduplication giữ (x-cache-size), key cũ nhất bị bỏ khi đầy
+const dedupCacheSize = 100000
+
 // RabbitMQQueue implements Queue using RabbitMQ
 type RabbitMQQueue struct {
 	conn   *amqp.Connection
 	ch     *amqp.Channel
 	name   string
 	config *QueueConfig
+
+	mu        sync.Mutex
+	delayMode string // plugin | ttl, rỗng: chưa xác định
+	dedupMode string // plugin | none, rỗng: chưa xác định
+
+	confirmMu sync.Mutex
+	confirmCh *amqp.Channel // Channel publisher confirms của PushBatch
 }
 
 // NewRabbitMQQueue creates a new RabbitMQ queue
@@ -34,6 +67,96 @@ func NewRabbitMQQueue(conn *amqp.Connection, name string, config *QueueConfig) (
 
 // Push adds a message to the queue
 func (r *RabbitMQQueue) Push(ctx context.Context, message *Message) error {
+	exchange, routingKey, publishing, err := r.publishing(message)
+	if err != nil {
+		return err
+	}
+
+	// Publish message
+	err = r.ch.Publish(
+		exchange,
+		routingKey,
+		false, // mandatory
+		false, // immediate
+		publishing,
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	return nil
+}
+
+// PushBatch publish nhiều message trên channel publisher confirms rồi chờ broker xác nhận cả batch một lần.
+// Broker nack hoặc hết ctx trả lỗi, message trong batch có thể đã được ghi một phần.
+func (r *RabbitMQQueue) PushBatch(ctx context.Context, messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	r.confirmMu.Lock()
+	defer r.confirmMu.Unlock()
+	ch, err := r.confirmChannel()
+	if err != nil {
+		return err
+	}
+
+	confirms := make([]*amqp.DeferredConfirmation, 0, len(messages))
+	for _, message := range messages {
+		exchange, routingKey, publishing, err := r.publishing(message)
+		if err != nil {
+			return err
+		}
+		confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, false, false, publishing)
+		if err != nil {
+			r.resetConfirmChannel()
+			return fmt.Errorf("failed to publish message %s: %w", message.ID, err)
+		}
+		confirms = append(confirms, confirm)
+	}
+
+	for i, confirm := range confirms {
+		acked, err := confirm.WaitContext(ctx)
+		if err != nil {
+			// Confirm còn lại của channel không còn khớp với batch sau
+			r.resetConfirmChannel()
+			return fmt.Errorf("failed to confirm message %s: %w", messages[i].ID, err)
+		}
+		if !acked {
+			return fmt.Errorf("broker rejected message %s", messages[i].ID)
+		}
+	}
+	return nil
+}
+
+// confirmChannel channel ở chế độ publisher confirms dùng cho PushBatch, tạo khi cần. Gọi khi giữ confirmMu.
+func (r *RabbitMQQueue) confirmChannel() (*amqp.Channel, error) {
+	if r.confirmCh != nil && !r.confirmCh.IsClosed() {
+		return r.confirmCh, nil
+	}
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+	r.confirmCh = ch
+	return ch, nil
+}
+
+// resetConfirmChannel đóng confirm channel, batch sau mở channel mới. Gọi khi giữ confirmMu.
+func (r *RabbitMQQueue) resetConfirmChannel() {
+	if r.confirmCh != nil {
+		r.confirmCh.Close()
+		r.confirmCh = nil
+	}
+}
+
+// publishing exchange, routing key và nội dung AMQP của message (delay, dedup theo UniqueKey)
+func (r *RabbitMQQueue) publishing(message *Message) (string, string, amqp.Publishing, error) {
 	// Set message timestamp if not set
 	if message.Timestamp.IsZero() {
 		message.Timestamp = time.Now()
@@ -42,7 +165,7 @@ func (r *RabbitMQQueue) Push(ctx context.Context, message *Message) error {
 	// Serialize message
 	data, err := json.Marshal(message)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+		return "", "", amqp.Publishing{}, fmt.Errorf("failed to marshal message: %w", err)
 	}
 
 	// Prepare headers
@@ -63,26 +186,177 @@ func (r *RabbitMQQueue) Push(ctx context.Context, message *Message) error {
 		DeliveryMode: amqp.Persistent, // Make message persistent
 	}
 
-	// Add delay if specified
-	if message.Delay > 0 {
-		// Use delayed message plugin or dead letter exchange
-		publishing.Expiration = fmt.Sprintf("%d", message.Delay.Milliseconds())
+	exchange, routingKey := "", r.name
+	switch {
+	case message.Delay > 0:
+		// Delayed message đi qua exchange/queue chờ, không dedup được
+		if exchange, routingKey, err = r.delayRoute(message.Delay, &publishing); err != nil {
+			return "", "", amqp.Publishing{}, err
+		}
+	case message.UniqueKey != "":
+		dedup, err := r.resolveDedupMode()
+		if err != nil {
+			return "", "", amqp.Publishing{}, err
+		}
+		if dedup {
+			// Exchange bỏ message có header trùng trong x-cache-ttl, publish không báo lỗi khi bị bỏ
+			publishing.Headers["x-deduplication-header"] = message.UniqueKey
+			publishing.Headers["x-cache-ttl"] = message.uniqueTTL().Milliseconds()
+			exchange = r.dedupExchangeName()
+		}
 	}
+	return exchange, routingKey, publishing, nil
+}
 
-	// Publish message
-	err = r.ch.Publish(
-		"",     // exchange
-		r.name, // routing key (queue name)
-		false,  // mandatory
-		false,  // immediate
-		publishing,
-	)
+// delayRoute exchange và routing key cho delayed message
+func (r *RabbitMQQueue) delayRoute(delay time.Duration, publishing *amqp.Publishing) (string, string, error) {
+	mode, err := r.resolveDelayMode()
+	if err != nil {
+		return "", "", err
+	}
 
+	if mode == RabbitMQDelayPlugin {
+		// Plugin giữ message tới hạn rồi route về queue chính, message delay ngắn không phải chờ message delay dài
+		publishing.Headers["x-delay"] = delay.Milliseconds()
+		return r.delayExchangeName(), r.name, nil
+	}
+
+	// Mỗi queue chờ chỉ có 1 TTL nên message hết hạn đúng thứ tự vào queue. Khai báo lại mỗi lần publish để gia hạn
+	// x-expires: queue chờ không dùng nữa tự bị xóa sau khi message cuối cùng đã chuyển về queue chính.
+	ttl := delayBucket(delay)
+	name := r.delayQueueName(ttl)
+	_, err = r.ch.QueueDeclare(name, true, false, false, false, amqp.Table{
+		"x-message-ttl":             ttl.Milliseconds(),
+		"x-expires":                 (ttl + delayQueueIdle).Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": r.name,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to publish message: %w", err)
+		return "", "", fmt.Errorf("failed to declare delay queue: %w", err)
 	}
+	return "", name, nil
+}
 
-	return nil
+// delayBucket làm tròn delay để giới hạn số queue chờ: 100ms dưới 1 giây, 1 giây dưới 1 giờ, 1 phút từ 1 giờ
+func delayBucket(delay time.Duration) time.Duration {
+	step := time.Minute
+	switch {
+	case delay < time.Second:
+		step = 100 * time.Millisecond
+	case delay < time.Hour:
+		step = time.Second
+	}
+	bucket := delay.Round(step)
+	if bucket < step {
+		bucket = step
+	}
+	return bucket
+}
+
+// delayQueueName queue chờ của 1 mức delay: "emails:delayed:30000"
+func (r *RabbitMQQueue) delayQueueName(ttl time.Duration) string {
+	return fmt.Sprintf("%s:delayed:%d", r.name, ttl.Milliseconds())
+}
+
+// delayExchangeName exchange x-delayed-message của queue
+func (r *RabbitMQQueue) delayExchangeName() string {
+	return r.name + ":delayed"
+}
+
+// resolveDelayMode xác định cách giao delayed message (1 lần). Chế độ auto thử khai báo exchange x-delayed-message
+// trên channel riêng: broker không có plugin đóng channel đó với lỗi "unknown exchange type".
+func (r *RabbitMQQueue) resolveDelayMode() (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.delayMode != "" {
+		return r.delayMode, nil
+	}
+
+	mode := r.config.RabbitMQDelay
+	if mode == RabbitMQDelayTTL {
+		r.delayMode = mode
+		return mode, nil
+	}
+
+	err := r.declareDelayExchange()
+	switch {
+	case err == nil:
+		r.delayMode = RabbitMQDelayPlugin
+	case mode == RabbitMQDelayPlugin:
+		return "", fmt.Errorf("failed to declare delayed exchange (is rabbitmq_delayed_message_exchange enabled?): %w", err)
+	case isUnknownExchangeType(err):
+		r.delayMode = RabbitMQDelayTTL
+	default:
+		return "", fmt.Errorf("failed to declare delayed exchange: %w", err)
+	}
+	return r.delayMode, nil
+}
+
+// declareDelayExchange khai báo exchange x-delayed-message và bind queue chính vào
+func (r *RabbitMQQueue) declareDelayExchange() error {
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	exchange := r.delayExchangeName()
+	if err := ch.ExchangeDeclare(exchange, "x-delayed-message", true, false, false, false, amqp.Table{
+		"x-delayed-type": "direct",
+	}); err != nil {
+		return err
+	}
+	return ch.QueueBind(r.name, r.name, exchange, false, nil)
+}
+
+// dedupExchangeName exchange x-message-deduplication của queue
+func (r *RabbitMQQueue) dedupExchangeName() string {
+	return r.name + ":unique"
+}
+
+// resolveDedupMode broker có plugin rabbitmq_message_deduplication không (xác định 1 lần). Không có plugin thì
+// message có UniqueKey được publish bình thường (không dedup).
+func (r *RabbitMQQueue) resolveDedupMode() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.dedupMode != "" {
+		return r.dedupMode == dedupPlugin, nil
+	}
+
+	err := r.declareDedupExchange()
+	switch {
+	case err == nil:
+		r.dedupMode = dedupPlugin
+	case isUnknownExchangeType(err):
+		r.dedupMode = dedupNone
+		logger.Warnf("RabbitMQ queue %s: rabbitmq_message_deduplication plugin is not enabled, unique messages are not deduplicated", r.name)
+	default:
+		return false, fmt.Errorf("failed to declare deduplication exchange: %w", err)
+	}
+	return r.dedupMode == dedupPlugin, nil
+}
+
+// declareDedupExchange khai báo exchange x-message-deduplication và bind queue chính vào
+func (r *RabbitMQQueue) declareDedupExchange() error {
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	exchange := r.dedupExchangeName()
+	if err := ch.ExchangeDeclare(exchange, "x-message-deduplication", true, false, false, false, amqp.Table{
+		"x-cache-size": dedupCacheSize,
+	}); err != nil {
+		return err
+	}
+	return ch.QueueBind(r.name, r.name, exchange, false, nil)
+}
+
+// isUnknownExchangeType broker từ chối exchange type của plugin chưa bật (x-delayed-message, x-message-deduplication)
+func isUnknownExchangeType(err error) bool {
+	var amqpErr *amqp.Error
+	return errors.As(err, &amqpErr) && amqpErr.Code == amqp.CommandInvalid
 }
 
 // Pop retrieves and removes a message from the queue
@@ -96,11 +370,78 @@ func (r *RabbitMQQueue) Pop(ctx context.Context) (*Message, error) {
 		return nil, nil // No messages
 	}
 
-	// Parse message
-	var message Message
-	if err := json.Unmarshal(delivery.Body, &message); err != nil {
+	message, err := decodeDelivery(delivery)
+	if err != nil {
 		// Acknowledge the message even if parsing fails
 		delivery.Ack(false)
+		return nil, err
+	}
+
+	// Acknowledge the message
+	if err := delivery.Ack(false); err != nil {
+		return nil, fmt.Errorf("failed to acknowledge message: %w", err)
+	}
+
+	return message, nil
+}
+
+// PopBatch lấy tối đa max message đang có, không chờ message mới. AMQP không có basic.get nhiều message
+// nên mỗi message vẫn là 1 lần Get (channel dùng chung với Pop nên không ack gộp được).
+func (r *RabbitMQQueue) PopBatch(ctx context.Context, max int) ([]*Message, error) {
+	messages := make([]*Message, 0, max)
+	for len(messages) < max && ctx.Err() == nil {
+		message, err := r.Pop(ctx)
+		if err != nil {
+			// Message đã lấy (đã ack) phải được trả về, lỗi lặp lại ở lần gọi sau
+			if len(messages) > 0 {
+				return messages, nil
+			}
+			return nil, err
+		}
+		if message == nil {
+			break
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// Browse implements Browser: lấy message không ack trên channel riêng rồi đóng channel để broker trả lại queue
+func (r *RabbitMQQueue) Browse(ctx context.Context, offset, limit int) ([]*Message, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	ch, err := r.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer ch.Close()
+
+	messages := make([]*Message, 0, limit)
+	for i := 0; i < offset+limit; i++ {
+		delivery, ok, err := ch.Get(r.name, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to browse queue: %w", err)
+		}
+		if !ok {
+			break
+		}
+		if i < offset {
+			continue
+		}
+		message, err := decodeDelivery(delivery)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
+	}
+	return messages, nil
+}
+
+// decodeDelivery parse message và headers từ delivery
+func decodeDelivery(delivery amqp.Delivery) (*Message, error) {
+	var message Message
+	if err := json.Unmarshal(delivery.Body, &message); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
 	}
 
@@ -127,11 +468,6 @@ func (r *RabbitMQQueue) Pop(ctx context.Context) (*Message, error) {
 		}
 	}
 
-	// Acknowledge the message
-	if err := delivery.Ack(false); err != nil {
-		return nil, fmt.Errorf("failed to acknowledge message: %w", err)
-	}
-
 	return &message, nil
 }
 
@@ -216,6 +552,9 @@ func (r *RabbitMQQueue) Clear(ctx context.Context) error {
 
 // Close closes the queue connection
 func (r *RabbitMQQueue) Close() error {
+	r.confirmMu.Lock()
+	r.resetConfirmChannel()
+	r.confirmMu.Unlock()
 	if r.ch != nil {
 		return r.ch.Close()
 	}
@@ -4,20 +4,30 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
+
 	"github.com/go-redis/redis/v8"
 )
 
+// RedisMaxPriority mức Message.Priority cao nhất RedisQueue phân biệt, cao hơn được tính là RedisMaxPriority.
+// Priority <= 0 vào list mặc định, mỗi mức 1..RedisMaxPriority có list riêng và được pop trước list mức thấp hơn.
+const RedisMaxPriority = 9
+
 // RedisQueue implements Queue using Redis
 type RedisQueue struct {
-	client *redis.Client
+	client redis.UniversalClient
 	name   string
 	config *QueueConfig
 }
 
 // NewRedisQueue creates a new Redis queue
-func NewRedisQueue(client *redis.Client, name string, config *QueueConfig) *RedisQueue {
+func NewRedisQueue(client redis.UniversalClient, name string, config *QueueConfig) *RedisQueue {
 	return &RedisQueue{
 		client: client,
 		name:   name,
@@ -38,8 +48,29 @@ func (r *RedisQueue) Push(ctx context.Context, message *Message) error {
 		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	// Add to Redis list
-	key := r.getQueueKey()
+	// Unique key giữ bằng SETNX, trùng key trong TTL thì bỏ message
+	if message.UniqueKey != "" {
+		uniqueKey := r.getUniqueKey(message.UniqueKey)
+		acquired, err := r.client.SetNX(ctx, uniqueKey, message.ID, message.uniqueTTL()).Result()
+		if err != nil {
+			return fmt.Errorf("failed to check unique key: %w", err)
+		}
+		if !acquired {
+			return fmt.Errorf("%w: %s", ErrDuplicateMessage, message.UniqueKey)
+		}
+		if err := r.push(ctx, message, data); err != nil {
+			// Message chưa vào queue: nhả key để caller đẩy lại được
+			r.client.Del(context.WithoutCancel(ctx), uniqueKey)
+			return err
+		}
+		return nil
+	}
+
+	return r.push(ctx, message, data)
+}
+
+// push đẩy message đã serialize vào list (hoặc delayed set nếu có Delay)
+func (r *RedisQueue) push(ctx context.Context, message *Message, data []byte) error {
 	if message.Delay > 0 {
 		// Use delayed queue
 		score := float64(time.Now().Add(message.Delay).Unix())
@@ -49,7 +80,100 @@ func (r *RedisQueue) Push(ctx context.Context, message *Message) error {
 		}).Err()
 	}
 
-	return r.client.LPush(ctx, key, data).Err()
+	return r.client.LPush(ctx, r.getPriorityQueueKey(message.Priority), data).Err()
+}
+
+// PushBatch đẩy nhiều message trong 1 pipeline (1 round trip, thêm 1 round trip SETNX nếu có message UniqueKey).
+// Message trùng UniqueKey bị bỏ, các message khác vẫn được đẩy và lỗi trả về wrap ErrDuplicateMessage.
+func (r *RedisQueue) PushBatch(ctx context.Context, messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	accepted := messages
+	var uniqueKeys, duplicates []string
+	if slices.ContainsFunc(messages, func(m *Message) bool { return m.UniqueKey != "" }) {
+		cmds := make([]*redis.BoolCmd, len(messages))
+		if _, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			for i, message := range messages {
+				if message.UniqueKey != "" {
+					cmds[i] = pipe.SetNX(ctx, r.getUniqueKey(message.UniqueKey), message.ID, message.uniqueTTL())
+				}
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("failed to check unique keys: %w", err)
+		}
+
+		accepted = make([]*Message, 0, len(messages))
+		for i, message := range messages {
+			switch {
+			case cmds[i] == nil:
+				accepted = append(accepted, message)
+			case cmds[i].Val():
+				accepted = append(accepted, message)
+				uniqueKeys = append(uniqueKeys, r.getUniqueKey(message.UniqueKey))
+			default:
+				duplicates = append(duplicates, message.UniqueKey)
+			}
+		}
+	}
+
+	if err := r.pushBatch(ctx, accepted); err != nil {
+		// Message chưa vào queue: nhả key để caller đẩy lại được
+		if len(uniqueKeys) > 0 {
+			r.client.Del(context.WithoutCancel(ctx), uniqueKeys...)
+		}
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("%w: %s", ErrDuplicateMessage, strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// pushBatch đẩy message vào list/delayed set bằng 1 pipeline, giữ thứ tự pop theo thứ tự trong batch
+func (r *RedisQueue) pushBatch(ctx context.Context, messages []*Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	ready := make(map[string][]interface{})
+	var readyKeys []string
+	var delayed []*redis.Z
+	for _, message := range messages {
+		if message.Timestamp.IsZero() {
+			message.Timestamp = now
+		}
+		data, err := json.Marshal(message)
+		if err != nil {
+			return fmt.Errorf("failed to marshal message %s: %w", message.ID, err)
+		}
+		if message.Delay > 0 {
+			delayed = append(delayed, &redis.Z{Score: float64(now.Add(message.Delay).Unix()), Member: data})
+			continue
+		}
+		key := r.getPriorityQueueKey(message.Priority)
+		if _, ok := ready[key]; !ok {
+			readyKeys = append(readyKeys, key)
+		}
+		ready[key] = append(ready[key], data)
+	}
+
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range readyKeys {
+			pipe.LPush(ctx, key, ready[key]...)
+		}
+		if len(delayed) > 0 {
+			pipe.ZAdd(ctx, r.getDelayedQueueKey(), delayed...)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to push messages: %w", err)
+	}
+	return nil
 }
 
 // Pop retrieves and removes a message from the queue
@@ -83,9 +207,8 @@ func (r *RedisQueue) Pop(ctx context.Context) (*Message, error) {
 		return &message, nil
 	}
 
-	// Check regular queue
-	key := r.getQueueKey()
-	result := r.client.BRPop(ctx, 0, key)
+	// Check regular queue, list priority cao trước
+	result := r.client.BRPop(ctx, 0, r.getReadyKeys()...)
 	if result.Err() != nil {
 		if result.Err() == redis.Nil {
 			return nil, nil // No messages
@@ -133,9 +256,8 @@ func (r *RedisQueue) PopWithTimeout(ctx context.Context, timeout time.Duration)
 		return &message, nil
 	}
 
-	// Check regular queue with timeout
-	key := r.getQueueKey()
-	result := r.client.BRPop(ctx, timeout, key)
+	// Check regular queue with timeout, list priority cao trước
+	result := r.client.BRPop(ctx, timeout, r.getReadyKeys()...)
 	if result.Err() != nil {
 		if result.Err() == redis.Nil {
 			return nil, nil // No messages
@@ -152,35 +274,208 @@ func (r *RedisQueue) PopWithTimeout(ctx context.Context, timeout time.Duration)
 	return &message, nil
 }
 
-// Peek retrieves a message without removing it
+// PopBatch lấy tối đa max message đang có, không chờ message mới: delayed message đến hạn trước, sau đó lấy
+// từ các list theo priority cao trước bằng LRANGE + LTRIM trong 1 transaction (chạy được trên Redis cũ không có RPOP count).
+func (r *RedisQueue) PopBatch(ctx context.Context, max int) ([]*Message, error) {
+	if max <= 0 {
+		return nil, nil
+	}
+
+	messages, err := r.popDueBatch(ctx, max)
+	if err != nil {
+		return nil, err
+	}
+	remaining := max - len(messages)
+	if remaining == 0 {
+		return messages, nil
+	}
+
+	keys := r.getReadyKeys()
+	lengths, err := r.listLengths(ctx, keys)
+	if err != nil {
+		return messages, err
+	}
+
+	// Chia số message cần lấy cho các list theo thứ tự priority; list ngắn đi do worker khác pop cùng lúc
+	// thì LRANGE/LTRIM chỉ lấy phần còn lại
+	var items []*redis.StringSliceCmd
+	if _, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			if remaining == 0 {
+				break
+			}
+			n := int(min(lengths[i], int64(remaining)))
+			if n == 0 {
+				continue
+			}
+			items = append(items, pipe.LRange(ctx, key, int64(-n), -1))
+			pipe.LTrim(ctx, key, 0, int64(-n-1))
+			remaining -= n
+		}
+		return nil
+	}); err != nil {
+		return messages, fmt.Errorf("failed to pop messages: %w", err)
+	}
+
+	// Cuối list là message cũ nhất (pop trước)
+	for _, cmd := range items {
+		values := cmd.Val()
+		for i := len(values) - 1; i >= 0; i-- {
+			var message Message
+			if err := json.Unmarshal([]byte(values[i]), &message); err != nil {
+				logger.Warnf("Queue %s: dropped invalid message: %v", r.name, err)
+				continue
+			}
+			messages = append(messages, &message)
+		}
+	}
+	return messages, nil
+}
+
+// popDueBatch lấy tối đa max delayed message đã đến hạn. Chỉ nhận message ZREM thành công để worker khác
+// lấy cùng lúc không nhận trùng.
+func (r *RedisQueue) popDueBatch(ctx context.Context, max int) ([]*Message, error) {
+	delayedKey := r.getDelayedQueueKey()
+	members, err := r.client.ZRangeByScore(ctx, delayedKey, &redis.ZRangeBy{
+		Min:   "0",
+		Max:   fmt.Sprintf("%f", float64(time.Now().Unix())),
+		Count: int64(max),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check delayed queue: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+
+	removed := make([]*redis.IntCmd, len(members))
+	if _, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, member := range members {
+			removed[i] = pipe.ZRem(ctx, delayedKey, member)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to take delayed messages: %w", err)
+	}
+
+	messages := make([]*Message, 0, len(members))
+	for i, member := range members {
+		if removed[i].Val() == 0 {
+			continue
+		}
+		var message Message
+		if err := json.Unmarshal([]byte(member), &message); err != nil {
+			logger.Warnf("Queue %s: dropped invalid delayed message: %v", r.name, err)
+			continue
+		}
+		messages = append(messages, &message)
+	}
+	return messages, nil
+}
+
+// Peek retrieves a message without removing it (message được pop tiếp theo, không gồm delayed message)
 func (r *RedisQueue) Peek(ctx context.Context) (*Message, error) {
-	key := r.getQueueKey()
-	result := r.client.LIndex(ctx, key, -1)
-	if result.Err() != nil {
-		if result.Err() == redis.Nil {
-			return nil, nil // No messages
+	tails, err := r.listTails(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tail := range tails {
+		if tail != nil {
+			return tail, nil
 		}
-		return nil, fmt.Errorf("failed to peek message: %w", result.Err())
 	}
+	return nil, nil // No messages
+}
 
-	// Parse message
-	var message Message
-	if err := json.Unmarshal([]byte(result.Val()), &message); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+// Browse implements Browser: message theo thứ tự pop (priority cao trước, cuối list trước), không gồm delayed message
+func (r *RedisQueue) Browse(ctx context.Context, offset, limit int) ([]*Message, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	keys := r.getReadyKeys()
+	lengths, err := r.listLengths(ctx, keys)
+	if err != nil {
+		return nil, err
 	}
 
-	return &message, nil
+	messages := make([]*Message, 0, limit)
+	for i, key := range keys {
+		if len(messages) == limit {
+			break
+		}
+		// offset tính trên toàn bộ queue, bỏ qua cả list nếu nằm trước offset
+		if int64(offset) >= lengths[i] {
+			offset -= int(lengths[i])
+			continue
+		}
+
+		n := limit - len(messages)
+		items, err := r.client.LRange(ctx, key, -int64(offset+n), -int64(offset+1)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to browse queue: %w", err)
+		}
+		offset = 0
+
+		for j := len(items) - 1; j >= 0; j-- {
+			var message Message
+			if err := json.Unmarshal([]byte(items[j]), &message); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+			}
+			messages = append(messages, &message)
+		}
+	}
+	return messages, nil
+}
+
+// OldestMessageAge implements AgeReporter: message cũ nhất trong các list priority hoặc delayed message quá hạn lâu nhất
+func (r *RedisQueue) OldestMessageAge(ctx context.Context) (time.Duration, error) {
+	now := time.Now()
+	var age time.Duration
+
+	tails, err := r.listTails(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for _, oldest := range tails {
+		if oldest != nil && !oldest.Timestamp.IsZero() && now.Sub(oldest.Timestamp) > age {
+			age = now.Sub(oldest.Timestamp)
+		}
+	}
+
+	// Delayed message tính tuổi từ thời điểm đến hạn
+	due, err := r.client.ZRangeByScoreWithScores(ctx, r.getDelayedQueueKey(), &redis.ZRangeBy{
+		Min:   "0",
+		Max:   fmt.Sprintf("%d", now.Unix()),
+		Count: 1,
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check delayed queue: %w", err)
+	}
+	if len(due) > 0 {
+		if overdue := now.Sub(time.Unix(int64(due[0].Score), 0)); overdue > age {
+			age = overdue
+		}
+	}
+
+	if age < 0 {
+		age = 0
+	}
+	return age, nil
 }
 
 // Size returns the number of messages in the queue
 func (r *RedisQueue) Size(ctx context.Context) (int64, error) {
-	key := r.getQueueKey()
 	delayedKey := r.getDelayedQueueKey()
 
-	// Get size of regular queue
-	regularSize, err := r.client.LLen(ctx, key).Result()
+	// Get size of regular queue (mọi mức priority)
+	lengths, err := r.listLengths(ctx, r.getReadyKeys())
 	if err != nil {
-		return 0, fmt.Errorf("failed to get regular queue size: %w", err)
+		return 0, err
+	}
+	var regularSize int64
+	for _, length := range lengths {
+		regularSize += length
 	}
 
 	// Get size of delayed queue
@@ -194,11 +489,10 @@ func (r *RedisQueue) Size(ctx context.Context) (int64, error) {
 
 // Clear removes all messages from the queue
 func (r *RedisQueue) Clear(ctx context.Context) error {
-	key := r.getQueueKey()
 	delayedKey := r.getDelayedQueueKey()
 
-	// Clear regular queue
-	if err := r.client.Del(ctx, key).Err(); err != nil {
+	// Clear regular queue (các list priority cùng hash slot nên xóa được bằng 1 lệnh trên cluster)
+	if err := r.client.Del(ctx, r.getReadyKeys()...).Err(); err != nil {
 		return fmt.Errorf("failed to clear regular queue: %w", err)
 	}
 
@@ -226,20 +520,93 @@ func (r *RedisQueue) getQueueKey() string {
 	return fmt.Sprintf("queue:%s", r.name)
 }
 
+// getPriorityQueueKey returns the Redis key for the list holding messages of a priority. Priority <= 0 dùng list
+// mặc định (tương thích message đẩy trước khi có priority); list priority có hash tag "{queue:<name>}" trùng hash
+// slot với list mặc định để BRPOP nhiều key chạy được trên Redis Cluster.
+func (r *RedisQueue) getPriorityQueueKey(priority int) string {
+	if priority <= 0 {
+		return r.getQueueKey()
+	}
+	return fmt.Sprintf("{queue:%s}:priority:%d", r.name, min(priority, RedisMaxPriority))
+}
+
+// getReadyKeys returns the priority lists in pop order: RedisMaxPriority trước, list mặc định cuối
+func (r *RedisQueue) getReadyKeys() []string {
+	keys := make([]string, 0, RedisMaxPriority+1)
+	for priority := RedisMaxPriority; priority >= 0; priority-- {
+		keys = append(keys, r.getPriorityQueueKey(priority))
+	}
+	return keys
+}
+
+// listLengths độ dài các list trong 1 pipeline
+func (r *RedisQueue) listLengths(ctx context.Context, keys []string) ([]int64, error) {
+	cmds := make([]*redis.IntCmd, len(keys))
+	if _, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.LLen(ctx, key)
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get regular queue size: %w", err)
+	}
+
+	lengths := make([]int64, len(keys))
+	for i, cmd := range cmds {
+		lengths[i] = cmd.Val()
+	}
+	return lengths, nil
+}
+
+// listTails message cuối (pop tiếp theo) của từng list theo thứ tự getReadyKeys, nil nếu list rỗng
+func (r *RedisQueue) listTails(ctx context.Context) ([]*Message, error) {
+	keys := r.getReadyKeys()
+	cmds := make([]*redis.StringCmd, len(keys))
+	if _, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.LIndex(ctx, key, -1)
+		}
+		return nil
+	}); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to peek message: %w", err)
+	}
+
+	tails := make([]*Message, len(keys))
+	for i, cmd := range cmds {
+		if cmd.Err() == redis.Nil {
+			continue
+		}
+		if cmd.Err() != nil {
+			return nil, fmt.Errorf("failed to peek message: %w", cmd.Err())
+		}
+		var message Message
+		if err := json.Unmarshal([]byte(cmd.Val()), &message); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal message: %w", err)
+		}
+		tails[i] = &message
+	}
+	return tails, nil
+}
+
 // getDelayedQueueKey returns the Redis key for the delayed queue
 func (r *RedisQueue) getDelayedQueueKey() string {
 	return fmt.Sprintf("queue:%s:delayed", r.name)
 }
 
+// getUniqueKey returns the Redis key holding a unique key, ngoài prefix "queue:" để ListQueues không coi là queue
+func (r *RedisQueue) getUniqueKey(key string) string {
+	return fmt.Sprintf("queue-unique:%s:%s", r.name, key)
+}
+
 // RedisQueueManager implements QueueManager using Redis
 type RedisQueueManager struct {
-	client *redis.Client
+	client redis.UniversalClient
 	config *QueueConfig
 	queues map[string]Queue
 }
 
 // NewRedisQueueManager creates a new Redis queue manager
-func NewRedisQueueManager(client *redis.Client, config *QueueConfig) *RedisQueueManager {
+func NewRedisQueueManager(client redis.UniversalClient, config *QueueConfig) *RedisQueueManager {
 	return &RedisQueueManager{
 		client: client,
 		config: config,
@@ -282,8 +649,22 @@ func (r *RedisQueueManager) DeleteQueue(ctx context.Context, name string) error
 
 // ListQueues returns a list of all queues
 func (r *RedisQueueManager) ListQueues(ctx context.Context) ([]string, error) {
-	pattern := "queue:*"
-	keys, err := r.client.Keys(ctx, pattern).Result()
+	var keys []string
+	var mu sync.Mutex
+	// Cluster: mỗi master giữ một phần keyspace
+	err := redisclient.ForEachMaster(ctx, r.client, func(ctx context.Context, client redis.UniversalClient) error {
+		// List priority có dạng "{queue:<name>}:priority:<n>"
+		for _, pattern := range []string{"queue:*", "{queue:*"} {
+			nodeKeys, err := client.Keys(ctx, pattern).Result()
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			keys = append(keys, nodeKeys...)
+			mu.Unlock()
+		}
+		return nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to list queue keys: %w", err)
 	}
@@ -293,12 +674,14 @@ func (r *RedisQueueManager) ListQueues(ctx context.Context) ([]string, error) {
 
 	for _, key := range keys {
 		// Extract queue name from key
+		if strings.HasPrefix(key, "{queue:") {
+			if end := strings.LastIndex(key, "}:priority:"); end > len("{queue:") {
+				key = "queue:" + key[len("{queue:"):end]
+			}
+		}
 		if len(key) > 6 && key[:6] == "queue:" {
-			queueName := key[6:]
 			// Remove delayed queue suffix
-			if len(queueName) > 9 && queueName[len(queueName)-9:] == ":delayed" {
-				queueName = queueName[:len(queueName)-9]
-			}
+			queueName := strings.TrimSuffix(key[6:], ":delayed")
 
 			if !seen[queueName] {
 				queues = append(queues, queueName)
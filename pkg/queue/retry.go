@@ -0,0 +1,253 @@
+package queue
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HeaderRetry header ghi đè retry policy cho từng message, xem MessageRetryPolicy
+const HeaderRetry = "x-retry"
+
+// BackoffCurve cách tăng thời gian chờ giữa các lần retry
+type BackoffCurve string
+
+const (
+	BackoffConstant    BackoffCurve = "constant"    // delay, delay, delay...
+	BackoffLinear      BackoffCurve = "linear"      // delay, 2*delay, 3*delay...
+	BackoffExponential BackoffCurve = "exponential" // delay, 2*delay, 4*delay...
+)
+
+// Backoff thời gian chờ trước mỗi lần retry
+type Backoff struct {
+	Curve BackoffCurve  // Rỗng = constant
+	Delay time.Duration // Delay gốc, 0 = ConsumerOptions.RetryDelay
+	Max   time.Duration // Giới hạn trên, 0 = không giới hạn
+	// Jitter chờ ngẫu nhiên trong [d/2, d] để các worker không retry cùng lúc
+	Jitter bool
+}
+
+// Duration thời gian chờ trước lần retry thứ retry (bắt đầu từ 1)
+func (b Backoff) Duration(retry int) time.Duration {
+	if retry < 1 {
+		retry = 1
+	}
+
+	d := b.Delay
+	switch b.Curve {
+	case BackoffLinear:
+		d = b.Delay * time.Duration(retry)
+	case BackoffExponential:
+		// Giới hạn số mũ để không tràn số
+		shift := retry - 1
+		if shift > 30 {
+			shift = 30
+		}
+		d = b.Delay << shift
+	}
+	if d < 0 || (b.Max > 0 && d > b.Max) {
+		d = b.Max
+	}
+
+	if b.Jitter && d > 0 {
+		half := d / 2
+		d = half + time.Duration(rand.Int64N(int64(d-half)+1))
+	}
+	return d
+}
+
+// RetryPolicy cách retry message lỗi của 1 handler
+type RetryPolicy struct {
+	// MaxAttempts tổng số lần xử lý gồm lần đầu (1 = không retry), 0 = ConsumerOptions.MaxRetries + 1
+	MaxAttempts int
+	Backoff     Backoff
+	// Retryable phân loại lỗi có nên retry không, nil = mọi lỗi trừ lỗi bọc bằng Permanent
+	Retryable func(err error) bool
+}
+
+// RetryPolicyProvider handler tự khai báo retry policy
+type RetryPolicyProvider interface {
+	RetryPolicy() RetryPolicy
+}
+
+// permanentError lỗi không retry được (message sai định dạng, dữ liệu không tồn tại...)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent đánh dấu lỗi không retry, message bị bỏ ngay
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent kiểm tra lỗi đã được đánh dấu Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// DefaultRetryable retry mọi lỗi trừ lỗi Permanent
+func DefaultRetryable(err error) bool {
+	return !IsPermanent(err)
+}
+
+// ResolveRetryPolicy chọn retry policy cho handler theo thứ tự ưu tiên:
+// options.RetryPolicy (đăng ký), handler implement RetryPolicyProvider, struct tag `retry`, rồi options.RetryBackoff.
+// Giá trị bỏ trống lấy từ options.MaxRetries và options.RetryDelay. Message có thể ghi đè thêm, xem MessageRetryPolicy.
+func ResolveRetryPolicy(handler MessageHandler, options *ConsumerOptions) (RetryPolicy, error) {
+	var policy RetryPolicy
+	switch {
+	case options != nil && options.RetryPolicy != nil:
+		policy = *options.RetryPolicy
+	case implementsProvider(handler):
+		policy = handler.(RetryPolicyProvider).RetryPolicy()
+	default:
+		tagged, ok, err := RetryPolicyFromTag(handler)
+		if err != nil {
+			return RetryPolicy{}, err
+		}
+		if ok {
+			policy = tagged
+		} else if options != nil {
+			// Handler không khai báo: backoff mặc định của worker (WORKER_RETRY_*)
+			policy.Backoff = options.RetryBackoff
+		}
+	}
+
+	if policy.MaxAttempts <= 0 {
+		policy.MaxAttempts = 1
+		if options != nil {
+			policy.MaxAttempts = options.MaxRetries + 1
+		}
+	}
+	if policy.Backoff.Delay == 0 && options != nil {
+		policy.Backoff.Delay = options.RetryDelay
+	}
+	if policy.Backoff.Curve == "" {
+		policy.Backoff.Curve = BackoffConstant
+	}
+	if policy.Retryable == nil {
+		policy.Retryable = DefaultRetryable
+	}
+	return policy, nil
+}
+
+// MessageRetryPolicy policy áp dụng cho 1 message: Message.MaxRetries (> 0) và header HeaderRetry
+// (cú pháp như tag `retry`, vd "attempts=8,backoff=exponential,delay=1s,max=10m,jitter") ghi đè policy của handler.
+// Header không hợp lệ trả lỗi kèm policy của handler.
+func MessageRetryPolicy(policy RetryPolicy, message *Message) (RetryPolicy, error) {
+	if message.MaxRetries > 0 {
+		policy.MaxAttempts = message.MaxRetries + 1
+	}
+
+	tag, ok := message.Headers[HeaderRetry]
+	if !ok || strings.TrimSpace(tag) == "" {
+		return policy, nil
+	}
+	override, err := ParseRetryTag(tag)
+	if err != nil {
+		return policy, err
+	}
+	if override.MaxAttempts > 0 {
+		policy.MaxAttempts = override.MaxAttempts
+	}
+	if override.Backoff.Curve != "" {
+		policy.Backoff.Curve = override.Backoff.Curve
+	}
+	if override.Backoff.Delay > 0 {
+		policy.Backoff.Delay = override.Backoff.Delay
+	}
+	if override.Backoff.Max > 0 {
+		policy.Backoff.Max = override.Backoff.Max
+	}
+	if strings.Contains(tag, "jitter") {
+		policy.Backoff.Jitter = override.Backoff.Jitter
+	}
+	return policy, nil
+}
+
+func implementsProvider(handler MessageHandler) bool {
+	_, ok := handler.(RetryPolicyProvider)
+	return ok
+}
+
+// RetryPolicyFromTag đọc policy từ field có tag `retry` của handler struct, thường là field marker:
+//
+//	type EmailHandler struct {
+//		_ struct{} `retry:"attempts=5,backoff=exponential,delay=2s,max=5m,jitter"`
+//	}
+func RetryPolicyFromTag(handler interface{}) (RetryPolicy, bool, error) {
+	t := reflect.TypeOf(handler)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return RetryPolicy{}, false, nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("retry")
+		if !ok {
+			continue
+		}
+		policy, err := ParseRetryTag(tag)
+		if err != nil {
+			return RetryPolicy{}, false, fmt.Errorf("%s: %w", t.Name(), err)
+		}
+		return policy, true, nil
+	}
+	return RetryPolicy{}, false, nil
+}
+
+// ParseRetryTag parse tag dạng "attempts=5,backoff=exponential,delay=2s,max=5m,jitter"
+func ParseRetryTag(tag string) (RetryPolicy, error) {
+	var policy RetryPolicy
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, _ := strings.Cut(part, "=")
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case "attempts":
+			policy.MaxAttempts, err = strconv.Atoi(value)
+			if err == nil && policy.MaxAttempts < 1 {
+				err = errors.New("must be at least 1")
+			}
+		case "backoff":
+			switch curve := BackoffCurve(value); curve {
+			case BackoffConstant, BackoffLinear, BackoffExponential:
+				policy.Backoff.Curve = curve
+			default:
+				err = errors.New("must be constant, linear or exponential")
+			}
+		case "delay":
+			policy.Backoff.Delay, err = time.ParseDuration(value)
+		case "max":
+			policy.Backoff.Max, err = time.ParseDuration(value)
+		case "jitter":
+			policy.Backoff.Jitter = true
+			if value != "" {
+				policy.Backoff.Jitter, err = strconv.ParseBool(value)
+			}
+		default:
+			err = errors.New("unknown option")
+		}
+		if err != nil {
+			return RetryPolicy{}, fmt.Errorf("invalid retry tag %q: %s: %w", tag, key, err)
+		}
+	}
+	return policy, nil
+}
@@ -0,0 +1,61 @@
+package queue
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/anhnq996/go-api-core/pkg/correlation"
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InjectTraceContext ghi trace context của ctx (traceparent, tracestate) vào header của message,
+// worker xử lý message tiếp tục cùng trace. Không có span trong ctx thì không ghi gì.
+func InjectTraceContext(ctx context.Context, message *Message) {
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return
+	}
+	if message.Headers == nil {
+		message.Headers = make(map[string]string)
+	}
+	tracing.Inject(ctx, message.Headers)
+}
+
+// HeaderRequestID header chứa correlation ID (request ID) của request đã đẩy message
+const HeaderRequestID = "x-request-id"
+
+// InjectRequestID ghi correlation ID của ctx (request ID, không có thì trace ID) vào header của message,
+// log của worker khi xử lý message mang cùng request_id. Message đã có header thì giữ nguyên.
+func InjectRequestID(ctx context.Context, message *Message) {
+	id := correlation.FromContext(ctx)
+	if id == "" || message.Headers[HeaderRequestID] != "" {
+		return
+	}
+	if message.Headers == nil {
+		message.Headers = make(map[string]string)
+	}
+	message.Headers[HeaderRequestID] = id
+}
+
+// startConsumerSpan span xử lý message, là con của span đã đẩy message
+func startConsumerSpan(ctx context.Context, queueName string, message *Message, attempt int) (context.Context, trace.Span) {
+	ctx = tracing.Extract(ctx, message.Headers)
+	attributes := []attribute.KeyValue{
+		attribute.String("messaging.destination.name", queueName),
+		attribute.String("messaging.message.id", message.ID),
+		attribute.Int("messaging.delivery.attempt", attempt),
+	}
+	name := "queue.process " + queueName
+	if jobType := message.Headers[HeaderJobType]; jobType != "" {
+		attributes = append(attributes, attribute.String("messaging.job.type", jobType))
+		if version := message.Headers[HeaderJobVersion]; version != "" {
+			if v, err := strconv.Atoi(version); err == nil {
+				attributes = append(attributes, attribute.Int("messaging.job.version", v))
+			}
+		}
+		name += " " + jobType
+	}
+	return tracing.Start(ctx, name, trace.WithSpanKind(trace.SpanKindConsumer), trace.WithAttributes(attributes...))
+}
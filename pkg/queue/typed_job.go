@@ -0,0 +1,126 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// HeaderJobVersion header chứa schema version của payload, message không có header này là version 1
+const HeaderJobVersion = "x-job-version"
+
+// ErrUnsupportedJobVersion payload có version không decode được
+var ErrUnsupportedJobVersion = errors.New("unsupported job version")
+
+// TypedJob job đã decode: loại job, schema version của message và payload kiểu T (đã chuyển sang version hiện tại)
+type TypedJob[T any] struct {
+	Type    string
+	Version int // Version của message lúc được đẩy vào queue
+	Payload T
+	Message *Message // Message gốc (ID, RetryCount, headers)
+}
+
+// VersionDecoder decode payload của một version cũ sang kiểu hiện tại
+type VersionDecoder[T any] func(data []byte) (T, error)
+
+// JobType định nghĩa loại job có payload kiểu T và version hiện tại. Khi đổi cấu trúc payload: tăng version và đăng ký
+// decoder cho version cũ, message đang nằm trong queue (hoặc dead queue) vẫn đọc được:
+//
+//	var ExportReport = queue.DefineJob[ExportReportV2]("report.export", 2).
+//		Decoder(1, func(data []byte) (ExportReportV2, error) { ... })
+//
+//	message, _ := ExportReport.NewMessage(ExportReportV2{UserID: id, Format: "xlsx"})
+//	dispatcher.RegisterHandler(ExportReport.Name(), ExportReport.Handler(exportReport))
+type JobType[T any] struct {
+	name     string
+	version  int
+	decoders map[int]VersionDecoder[T]
+}
+
+// DefineJob định nghĩa loại job, version bắt đầu từ 1. Payload version hiện tại decode bằng JSON.
+func DefineJob[T any](name string, version int) *JobType[T] {
+	if name == "" || version < 1 {
+		panic("queue: job name is required and version must be at least 1")
+	}
+	return &JobType[T]{name: name, version: version, decoders: make(map[int]VersionDecoder[T])}
+}
+
+// Decoder đăng ký decoder cho version cũ (nhỏ hơn version hiện tại), gọi lúc khởi tạo
+func (j *JobType[T]) Decoder(version int, decode VersionDecoder[T]) *JobType[T] {
+	if version < 1 || version >= j.version || decode == nil {
+		panic(fmt.Sprintf("queue: decoder of job %q must be for a version between 1 and %d", j.name, j.version-1))
+	}
+	j.decoders[version] = decode
+	return j
+}
+
+// Name loại job (header "type")
+func (j *JobType[T]) Name() string {
+	return j.name
+}
+
+// Version version hiện tại của payload
+func (j *JobType[T]) Version() int {
+	return j.version
+}
+
+// NewMessage tạo message với payload version hiện tại
+func (j *JobType[T]) NewMessage(payload T) (*Message, error) {
+	message, err := NewJobMessage(j.name, payload)
+	if err != nil {
+		return nil, err
+	}
+	message.Headers[HeaderJobVersion] = strconv.Itoa(j.version)
+	return message, nil
+}
+
+// Decode đọc payload của message theo version trong header. Message của version mới hơn (producer đã deploy bản mới,
+// worker chưa) trả lỗi retry được để worker bản mới xử lý; version cũ không có decoder là lỗi Permanent.
+func (j *JobType[T]) Decode(message *Message) (*TypedJob[T], error) {
+	if jobType := message.Headers[HeaderJobType]; jobType != j.name {
+		return nil, Permanent(fmt.Errorf("%w: %q, expected %q", ErrUnknownJobType, jobType, j.name))
+	}
+
+	version := 1
+	if raw, ok := message.Headers[HeaderJobVersion]; ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil || v < 1 {
+			return nil, Permanent(fmt.Errorf("%w: invalid version %q of job %s", ErrUnsupportedJobVersion, raw, j.name))
+		}
+		version = v
+	}
+
+	job := &TypedJob[T]{Type: j.name, Version: version, Message: message}
+	switch {
+	case version == j.version:
+		if err := json.Unmarshal(message.Data, &job.Payload); err != nil {
+			return nil, Permanent(fmt.Errorf("failed to decode %s v%d payload: %w", j.name, version, err))
+		}
+	case version > j.version:
+		return nil, fmt.Errorf("%w: %s v%d is newer than v%d", ErrUnsupportedJobVersion, j.name, version, j.version)
+	default:
+		decode, ok := j.decoders[version]
+		if !ok {
+			return nil, Permanent(fmt.Errorf("%w: no decoder for %s v%d", ErrUnsupportedJobVersion, j.name, version))
+		}
+		payload, err := decode(message.Data)
+		if err != nil {
+			return nil, Permanent(fmt.Errorf("failed to decode %s v%d payload: %w", j.name, version, err))
+		}
+		job.Payload = payload
+	}
+	return job, nil
+}
+
+// Handler HandlerFunc decode payload rồi gọi fn, dùng với Dispatcher.RegisterHandler
+func (j *JobType[T]) Handler(fn func(ctx context.Context, job *TypedJob[T]) error) HandlerFunc {
+	return func(ctx context.Context, message *Message) error {
+		job, err := j.Decode(message)
+		if err != nil {
+			return err
+		}
+		return fn(ctx, job)
+	}
+}
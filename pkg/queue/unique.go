@@ -0,0 +1,40 @@
+package queue
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultUniqueFor thời gian giữ unique key khi Message.UniqueFor = 0
+const DefaultUniqueFor = 5 * time.Minute
+
+// ErrDuplicateMessage message có cùng UniqueKey đã được đẩy vào queue trong UniqueFor, message bị bỏ.
+// Không phải lỗi của backend: caller thường coi như đã enqueue thành công.
+var ErrDuplicateMessage = errors.New("duplicate message")
+
+// Unique đặt unique key cho message: các lần đẩy message cùng key vào cùng queue trong ttl (0: DefaultUniqueFor)
+// bị bỏ. Key hết hạn theo ttl, không phụ thuộc message đã được xử lý hay chưa.
+//
+//	message, _ := queue.NewJobMessage("report.export", payload)
+//	err := q.Push(ctx, message.Unique("report.export:"+userID, time.Minute))
+//	if errors.Is(err, queue.ErrDuplicateMessage) { /* đã có job đang chờ */ }
+func (m *Message) Unique(key string, ttl time.Duration) *Message {
+	m.UniqueKey = key
+	m.UniqueFor = ttl
+	return m
+}
+
+// uniqueTTL thời gian giữ unique key của message
+func (m *Message) uniqueTTL() time.Duration {
+	if m.UniqueFor > 0 {
+		return m.UniqueFor
+	}
+	return DefaultUniqueFor
+}
+
+// withoutUnique bỏ unique key của bản sao message được đẩy lại (retry, dead queue, requeue): không phải enqueue mới
+// nên không bị coi là trùng với chính nó
+func withoutUnique(m *Message) {
+	m.UniqueKey = ""
+	m.UniqueFor = 0
+}
@@ -6,7 +6,7 @@ import (
 	"strings"
 	"time"
 
-	"api-core/pkg/exception"
+	"github.com/anhnq996/go-api-core/pkg/exception"
 
 	"github.com/go-chi/chi/v5"
 )
@@ -10,7 +10,7 @@ import (
 
 // RateLimitConfig holds rate limiting configuration
 type RateLimitConfig struct {
-	Redis     *redis.Client
+	Redis     redis.UniversalClient
 	KeyPrefix string
 }
 
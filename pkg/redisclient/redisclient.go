@@ -0,0 +1,162 @@
+package redisclient
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Mode kiểu triển khai Redis
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
+)
+
+// Config cấu hình kết nối Redis dùng chung cho cache, queue, cron lock, rate limit
+type Config struct {
+	Mode string // standalone (mặc định) | sentinel | cluster
+
+	// Standalone: Host/Port, hoặc Addrs[0] nếu có
+	Host string
+	Port string
+	// Addrs sentinel: địa chỉ các sentinel; cluster: các node seed (host:port)
+	Addrs []string
+	// MasterName tên master được sentinel giám sát (bắt buộc với sentinel)
+	MasterName string
+	// SentinelPassword mật khẩu của sentinel (khác mật khẩu Redis)
+	SentinelPassword string
+
+	Username string // Redis ACL (Redis 6+)
+	Password string
+	DB       int // Cluster chỉ hỗ trợ DB 0
+	PoolSize int
+
+	// TLS nil: không dùng TLS
+	TLS *tls.Config
+
+	// Timeout và retry, 0: mặc định của go-redis
+	MaxRetries   int
+	DialTimeout  time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+}
+
+// Validate kiểm tra cấu hình theo mode
+func (c Config) Validate() error {
+	switch c.mode() {
+	case ModeStandalone:
+	case ModeSentinel:
+		if c.MasterName == "" {
+			return fmt.Errorf("redis sentinel requires a master name")
+		}
+		if len(c.Addrs) == 0 {
+			return fmt.Errorf("redis sentinel requires at least one sentinel address")
+		}
+	case ModeCluster:
+		if len(c.Addrs) == 0 {
+			return fmt.Errorf("redis cluster requires at least one node address")
+		}
+		if c.DB != 0 {
+			return fmt.Errorf("redis cluster only supports DB 0")
+		}
+	default:
+		return fmt.Errorf("unsupported redis mode %q (standalone, sentinel, cluster)", c.Mode)
+	}
+	return nil
+}
+
+// New tạo client theo mode: *redis.Client, failover client (sentinel) hoặc *redis.ClusterClient.
+// Chưa kết nối, gọi Ping để kiểm tra. Mỗi lệnh có span tracing (tracing.RedisHook).
+func New(cfg Config) (redis.UniversalClient, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	client := newClient(cfg)
+	client.AddHook(tracing.RedisHook())
+	return client, nil
+}
+
+func newClient(cfg Config) redis.UniversalClient {
+	switch cfg.mode() {
+	case ModeSentinel:
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    cfg.Addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Username:         cfg.Username,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+			PoolSize:         cfg.PoolSize,
+			TLSConfig:        cfg.TLS,
+			MaxRetries:       cfg.MaxRetries,
+			DialTimeout:      cfg.DialTimeout,
+			ReadTimeout:      cfg.ReadTimeout,
+			WriteTimeout:     cfg.WriteTimeout,
+			IdleTimeout:      cfg.IdleTimeout,
+		})
+	case ModeCluster:
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:        cfg.Addrs,
+			Username:     cfg.Username,
+			Password:     cfg.Password,
+			PoolSize:     cfg.PoolSize,
+			TLSConfig:    cfg.TLS,
+			MaxRetries:   cfg.MaxRetries,
+			DialTimeout:  cfg.DialTimeout,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			IdleTimeout:  cfg.IdleTimeout,
+		})
+	}
+
+	addr := net.JoinHostPort(cfg.Host, cfg.Port)
+	if len(cfg.Addrs) > 0 {
+		addr = cfg.Addrs[0]
+	}
+	return redis.NewClient(&redis.Options{
+		Addr:         addr,
+		Username:     cfg.Username,
+		Password:     cfg.Password,
+		DB:           cfg.DB,
+		PoolSize:     cfg.PoolSize,
+		TLSConfig:    cfg.TLS,
+		MaxRetries:   cfg.MaxRetries,
+		DialTimeout:  cfg.DialTimeout,
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	})
+}
+
+// ForEachMaster chạy fn trên từng master của cluster, client khác chạy fn trên chính client đó.
+// Dùng cho lệnh không theo key (KEYS, SCAN, FLUSHDB) vì trong cluster mỗi node chỉ giữ một phần keyspace.
+func ForEachMaster(ctx context.Context, client redis.UniversalClient, fn func(ctx context.Context, client redis.UniversalClient) error) error {
+	if cluster, ok := client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, node *redis.Client) error {
+			return fn(ctx, node)
+		})
+	}
+	return fn(ctx, client)
+}
+
+// IsCluster client là Redis Cluster: lệnh nhiều key phải cùng hash slot
+func IsCluster(client redis.UniversalClient) bool {
+	_, ok := client.(*redis.ClusterClient)
+	return ok
+}
+
+func (c Config) mode() string {
+	if c.Mode == "" {
+		return ModeStandalone
+	}
+	return c.Mode
+}
@@ -65,9 +65,37 @@ const (
 	CodeLogoutSuccess  = "LOGOUT_SUCCESS"
 	CodeTokenRefreshed = "TOKEN_REFRESHED"
 
+	// Token revocation (token_version) & password
+	CodeTokenRevoked           = "TOKEN_REVOKED"
+	CodePasswordChanged        = "PASSWORD_CHANGED"
+	CodeInvalidCurrentPassword = "INVALID_CURRENT_PASSWORD"
+
+	// Social login
+	CodeSocialProviderNotSupported = "SOCIAL_PROVIDER_NOT_SUPPORTED"
+	CodeSocialStateInvalid         = "SOCIAL_STATE_INVALID"
+	CodeSocialLoginFailed          = "SOCIAL_LOGIN_FAILED"
+	CodeSocialEmailUnverified      = "SOCIAL_EMAIL_UNVERIFIED"
+	CodeSocialProviderUnavailable  = "SOCIAL_PROVIDER_UNAVAILABLE"
+
+	// Email suppression & webhooks
+	CodeEmailNotSuppressed    = "EMAIL_NOT_SUPPRESSED"
+	CodeWebhookUnauthorized   = "WEBHOOK_UNAUTHORIZED"
+	CodeWebhookInvalidPayload = "WEBHOOK_INVALID_PAYLOAD"
+
+	// Magic link (passwordless login)
+	CodeMagicLinkSent    = "MAGIC_LINK_SENT"
+	CodeMagicLinkInvalid = "MAGIC_LINK_INVALID"
+	CodeMagicLinkExpired = "MAGIC_LINK_EXPIRED"
+
 	// Rate limit
 	CodeRateLimitExceeded = "RATE_LIMIT_EXCEEDED"
 
+	// Load shedding
+	CodeServerOverloaded = "SERVER_OVERLOADED"
+
+	// IP allowlist (debug endpoints)
+	CodeIPNotAllowed = "IP_NOT_ALLOWED"
+
 	// Friend errors
 	CodeCannotSendRequestToSelf       = "CANNOT_SEND_REQUEST_TO_SELF"
 	CodeUserInactive                  = "USER_INACTIVE"
@@ -99,6 +127,81 @@ const (
 	CodeGetConversationsFailed        = "GET_CONVERSATIONS_FAILED"
 	CodeCreateConversationFailed      = "CREATE_CONVERSATION_FAILED"
 	CodeGetConversationFailed         = "GET_CONVERSATION_FAILED"
+	CodeInvalidDeleteScope            = "INVALID_DELETE_SCOPE"
+	CodeNotMessageSender              = "NOT_MESSAGE_SENDER"
+	CodeNotConversationOwner          = "NOT_CONVERSATION_OWNER"
+	CodeMessageNotDeleted             = "MESSAGE_NOT_DELETED"
+	CodeConversationNotDeleted        = "CONVERSATION_NOT_DELETED"
+	CodeDeleteMessageFailed           = "DELETE_MESSAGE_FAILED"
+	CodeRestoreMessageFailed          = "RESTORE_MESSAGE_FAILED"
+	CodeDeleteConversationFailed      = "DELETE_CONVERSATION_FAILED"
+	CodeRestoreConversationFailed     = "RESTORE_CONVERSATION_FAILED"
+	CodeChatSyncDisabled              = "CHAT_SYNC_DISABLED"
+	CodeChatSyncFailed                = "CHAT_SYNC_FAILED"
+
+	// Role errors
+	CodeRoleNotFound       = "ROLE_NOT_FOUND"
+	CodeRoleAlreadyExists  = "ROLE_ALREADY_EXISTS"
+	CodeRoleInUse          = "ROLE_IN_USE"
+	CodeRoleProtected      = "ROLE_PROTECTED"
+	CodeInvalidPermissions = "INVALID_PERMISSIONS"
+	CodeUserNotInRole      = "USER_NOT_IN_ROLE"
+
+	// Impersonation
+	CodeImpersonationStarted    = "IMPERSONATION_STARTED"
+	CodeImpersonationStopped    = "IMPERSONATION_STOPPED"
+	CodeImpersonationNotAllowed = "IMPERSONATION_NOT_ALLOWED"
+	CodeNotImpersonating        = "NOT_IMPERSONATING"
+
+	// Delta sync
+	CodeSyncCursorInvalid = "SYNC_CURSOR_INVALID"
+	CodeSyncFailed        = "SYNC_FAILED"
+
+	// Status page
+	CodeStatusUnavailable = "STATUS_UNAVAILABLE"
+	CodeIncidentNotFound  = "INCIDENT_NOT_FOUND"
+
+	// E2E test data
+	CodeTestDataResetFailed = "TEST_DATA_RESET_FAILED"
+
+	// URL do user cung cấp (link preview, avatar từ URL, webhook)
+	CodeURLNotAllowed  = "URL_NOT_ALLOWED"
+	CodeURLFetchFailed = "URL_FETCH_FAILED"
+
+	// Presigned upload (client upload thẳng lên S3)
+	CodePresignedUploadUnsupported = "PRESIGNED_UPLOAD_UNSUPPORTED"
+	CodeUploadNotFound             = "UPLOAD_NOT_FOUND"
+	CodeUploadIncomplete           = "UPLOAD_INCOMPLETE"
+	CodeUploadVerificationFailed   = "UPLOAD_VERIFICATION_FAILED"
+
+	// Malware scan
+	CodeFileInfected   = "FILE_INFECTED"
+	CodeFileScanFailed = "FILE_SCAN_FAILED"
+
+	// Storage quota
+	CodeStorageQuotaExceeded = "STORAGE_QUOTA_EXCEEDED"
+
+	// Queue admin
+	CodeQueueNotFound          = "QUEUE_NOT_FOUND"
+	CodeQueueUnavailable       = "QUEUE_UNAVAILABLE"
+	CodeQueueBrowseUnsupported = "QUEUE_BROWSE_UNSUPPORTED"
+
+	// Cron jobs
+	CodeCronJobRunNotFound   = "CRON_JOB_RUN_NOT_FOUND"
+	CodeCronJobNotFound      = "CRON_JOB_NOT_FOUND"
+	CodeCronJobRunning       = "CRON_JOB_RUNNING"
+	CodeCronJobTriggered     = "CRON_JOB_TRIGGERED"
+	CodeSchedulerUnavailable = "SCHEDULER_UNAVAILABLE"
+
+	// Log levels
+	CodeLogModuleNotFound        = "LOG_MODULE_NOT_FOUND"
+	CodeLogLevelStoreUnavailable = "LOG_LEVEL_STORE_UNAVAILABLE"
+
+	// Devices (FCM token)
+	CodeDeviceNotFound = "DEVICE_NOT_FOUND"
+
+	// Notification center
+	CodeNotificationNotFound = "NOTIFICATION_NOT_FOUND"
 )
 
 // GetHTTPStatusCode trả về HTTP status code tương ứng với response code
@@ -132,6 +235,11 @@ func GetHTTPStatusCode(code string) int {
 		CodeAccountDisabled:    403,
 		CodeAccountNotVerified: 403,
 
+		// Token revocation & password
+		CodeTokenRevoked:           401,
+		CodePasswordChanged:        200,
+		CodeInvalidCurrentPassword: 400,
+
 		// Server errors
 		CodeInternalServerError: 500,
 		CodeServiceUnavailable:  503,
@@ -160,9 +268,32 @@ func GetHTTPStatusCode(code string) int {
 		CodeInvalidPage:     400,
 		CodeInvalidPageSize: 400,
 
+		// Social login
+		CodeSocialProviderNotSupported: 400,
+		CodeSocialStateInvalid:         400,
+		CodeSocialLoginFailed:          401,
+		CodeSocialEmailUnverified:      400,
+		CodeSocialProviderUnavailable:  503,
+
+		// Email suppression & webhooks
+		CodeEmailNotSuppressed:    404,
+		CodeWebhookUnauthorized:   401,
+		CodeWebhookInvalidPayload: 400,
+
+		// Magic link (passwordless login)
+		CodeMagicLinkSent:    200,
+		CodeMagicLinkInvalid: 401,
+		CodeMagicLinkExpired: 401,
+
 		// Rate limit
 		CodeRateLimitExceeded: 429,
 
+		// Load shedding
+		CodeServerOverloaded: 503,
+
+		// IP allowlist (debug endpoints)
+		CodeIPNotAllowed: 403,
+
 		// Friend errors
 		CodeCannotSendRequestToSelf:       400,
 		CodeUserInactive:                  403,
@@ -194,6 +325,81 @@ func GetHTTPStatusCode(code string) int {
 		CodeGetConversationsFailed:        500,
 		CodeCreateConversationFailed:      500,
 		CodeGetConversationFailed:         500,
+		CodeInvalidDeleteScope:            400,
+		CodeNotMessageSender:              403,
+		CodeNotConversationOwner:          403,
+		CodeMessageNotDeleted:             400,
+		CodeConversationNotDeleted:        400,
+		CodeDeleteMessageFailed:           500,
+		CodeRestoreMessageFailed:          500,
+		CodeDeleteConversationFailed:      500,
+		CodeRestoreConversationFailed:     500,
+		CodeChatSyncDisabled:              400,
+		CodeChatSyncFailed:                500,
+
+		// Role errors
+		CodeRoleNotFound:       404,
+		CodeRoleAlreadyExists:  409,
+		CodeRoleInUse:          409,
+		CodeRoleProtected:      409,
+		CodeInvalidPermissions: 400,
+		CodeUserNotInRole:      400,
+
+		// Impersonation
+		CodeImpersonationStarted:    200,
+		CodeImpersonationStopped:    200,
+		CodeImpersonationNotAllowed: 403,
+		CodeNotImpersonating:        400,
+
+		// Delta sync
+		CodeSyncCursorInvalid: 400,
+		CodeSyncFailed:        500,
+
+		// Status page
+		CodeStatusUnavailable: 500,
+		CodeIncidentNotFound:  404,
+
+		// E2E test data
+		CodeTestDataResetFailed: 500,
+
+		// URL do user cung cấp
+		CodeURLNotAllowed:  400,
+		CodeURLFetchFailed: 422,
+
+		// Presigned upload
+		CodePresignedUploadUnsupported: 400,
+		CodeUploadNotFound:             404,
+		CodeUploadIncomplete:           409,
+		CodeUploadVerificationFailed:   422,
+
+		// Malware scan
+		CodeFileInfected:   422,
+		CodeFileScanFailed: 503,
+
+		// Storage quota
+		CodeStorageQuotaExceeded: 413,
+
+		// Queue admin
+		CodeQueueNotFound:          404,
+		CodeQueueUnavailable:       503,
+		CodeQueueBrowseUnsupported: 400,
+
+		// Cron jobs
+		CodeCronJobRunNotFound:   404,
+		CodeCronJobNotFound:      404,
+		CodeCronJobRunning:       409,
+		CodeCronJobTriggered:     202,
+		CodeSchedulerUnavailable: 503,
+
+		// Log levels
+		CodeLogModuleNotFound:        404,
+		CodeLogLevelStoreUnavailable: 503,
+
+		// Devices (FCM token)
+		CodeDeviceNotFound: 404,
+
+		// Notification center
+		CodeNotificationNotFound: 404,
 	}
 
 	if status, ok := statusMap[code]; ok {
@@ -0,0 +1,263 @@
+package response
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// FieldCase kiểu đặt tên field trong JSON response
+type FieldCase string
+
+const (
+	FieldCaseSnake FieldCase = "snake" // Giữ nguyên json tag (mặc định), vd: per_page
+	FieldCaseCamel FieldCase = "camel" // Chuyển key snake_case thành camelCase, vd: perPage
+)
+
+// Envelope dạng bọc response
+type Envelope string
+
+const (
+	EnvelopeStandard Envelope = "standard" // {success, code, message, data, errors, meta} (mặc định)
+	EnvelopeBare     Envelope = "bare"     // Thành công: data trực tiếp, meta trong header; lỗi: {code, message, errors}
+)
+
+// Header client dùng để chọn format (khi cho phép override)
+const (
+	HeaderResponseCase     = "X-Response-Case"
+	HeaderResponseEnvelope = "X-Response-Envelope"
+)
+
+// Format cách serialize Response
+type Format struct {
+	FieldCase FieldCase
+	Envelope  Envelope
+}
+
+// DefaultFormat format hiện tại của API: snake_case, envelope chuẩn
+var DefaultFormat = Format{FieldCase: FieldCaseSnake, Envelope: EnvelopeStandard}
+
+// ParseFieldCase parse field case từ config/header
+func ParseFieldCase(s string) (FieldCase, error) {
+	switch c := FieldCase(strings.ToLower(strings.TrimSpace(s))); c {
+	case FieldCaseSnake, FieldCaseCamel:
+		return c, nil
+	}
+	return "", fmt.Errorf("unsupported field case %q: expected snake or camel", s)
+}
+
+// ParseEnvelope parse envelope từ config/header
+func ParseEnvelope(s string) (Envelope, error) {
+	switch e := Envelope(strings.ToLower(strings.TrimSpace(s))); e {
+	case EnvelopeStandard, EnvelopeBare:
+		return e, nil
+	}
+	return "", fmt.Errorf("unsupported envelope %q: expected standard or bare", s)
+}
+
+// ParseFormat parse format dạng "case:envelope", vd: "camel:bare", "camel" (envelope chuẩn)
+func ParseFormat(s string) (Format, error) {
+	caseValue, envelopeValue, hasEnvelope := strings.Cut(s, ":")
+
+	format := DefaultFormat
+	fieldCase, err := ParseFieldCase(caseValue)
+	if err != nil {
+		return Format{}, err
+	}
+	format.FieldCase = fieldCase
+
+	if hasEnvelope {
+		envelope, err := ParseEnvelope(envelopeValue)
+		if err != nil {
+			return Format{}, err
+		}
+		format.Envelope = envelope
+	}
+	return format, nil
+}
+
+// RouteFormat format áp dụng cho các path bắt đầu bằng Prefix (vd: theo API version /api/v2)
+type RouteFormat struct {
+	Prefix string
+	Format Format
+}
+
+// FormatConfig cấu hình chọn format cho từng request
+type FormatConfig struct {
+	Default             Format
+	Routes              []RouteFormat // Prefix dài nhất khớp được ưu tiên
+	AllowHeaderOverride bool          // Cho phép client chọn format bằng X-Response-Case / X-Response-Envelope
+}
+
+// Resolve chọn format cho request: header của client > route prefix > default
+func (c FormatConfig) Resolve(r *http.Request) Format {
+	format := c.Default
+	matched := -1
+	for _, route := range c.Routes {
+		if strings.HasPrefix(r.URL.Path, route.Prefix) && len(route.Prefix) > matched {
+			format = route.Format
+			matched = len(route.Prefix)
+		}
+	}
+
+	if c.AllowHeaderOverride {
+		if fieldCase, err := ParseFieldCase(r.Header.Get(HeaderResponseCase)); err == nil {
+			format.FieldCase = fieldCase
+		}
+		if envelope, err := ParseEnvelope(r.Header.Get(HeaderResponseEnvelope)); err == nil {
+			format.Envelope = envelope
+		}
+	}
+	return format
+}
+
+// FormatMiddleware gắn format đã chọn vào ResponseWriter để JSON serialize theo format đó
+func FormatMiddleware(cfg FormatConfig) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if cfg.AllowHeaderOverride {
+				w.Header().Add("Vary", HeaderResponseCase+", "+HeaderResponseEnvelope)
+			}
+			next.ServeHTTP(WithFormat(w, cfg.Resolve(r)), r)
+		})
+	}
+}
+
+// formatWriter ResponseWriter mang theo format của request
+type formatWriter struct {
+	http.ResponseWriter
+	format Format
+}
+
+// Unwrap cho http.ResponseController truy cập writer gốc
+func (w *formatWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Flush hỗ trợ streaming response
+func (w *formatWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hỗ trợ WebSocket upgrade
+func (w *formatWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
+
+// WithFormat gắn format vào ResponseWriter
+func WithFormat(w http.ResponseWriter, format Format) http.ResponseWriter {
+	return &formatWriter{ResponseWriter: w, format: format}
+}
+
+// FormatFromWriter lấy format gắn với ResponseWriter (kể cả khi bị middleware khác bọc ngoài), mặc định DefaultFormat
+func FormatFromWriter(w http.ResponseWriter) Format {
+	for w != nil {
+		if fw, ok := w.(*formatWriter); ok {
+			return fw.format
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		w = unwrapper.Unwrap()
+	}
+	return DefaultFormat
+}
+
+// CodeRecorder ResponseWriter muốn biết response code đã gửi (vd. middleware ghi security log)
+type CodeRecorder interface {
+	RecordCode(code string)
+}
+
+// recordCode báo response code cho mọi CodeRecorder trong chuỗi writer
+func recordCode(w http.ResponseWriter, code string) {
+	for w != nil {
+		if recorder, ok := w.(CodeRecorder); ok {
+			recorder.RecordCode(code)
+		}
+		unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = unwrapper.Unwrap()
+	}
+}
+
+// formatBody chuyển Response theo envelope và field case của format
+func formatBody(w http.ResponseWriter, response Response, format Format) (interface{}, error) {
+	var body interface{} = response
+	if format.Envelope == EnvelopeBare {
+		body = bareBody(w, response)
+	}
+
+	if format.FieldCase == FieldCaseCamel {
+		return camelizeJSON(body)
+	}
+	return body, nil
+}
+
+// bareBody body không có envelope: thành công trả data (pagination chuyển sang header), lỗi trả code/message/errors
+func bareBody(w http.ResponseWriter, response Response) interface{} {
+	if !response.Success || response.Data == nil {
+		return struct {
+			Code    string      `json:"code"`
+			Message string      `json:"message"`
+			Errors  interface{} `json:"errors,omitempty"`
+		}{response.Code, response.Message, response.Errors}
+	}
+
+	if meta := response.Meta; meta != nil {
+		w.Header().Set("X-Total-Count", strconv.FormatInt(meta.Total, 10))
+		w.Header().Set("X-Page", strconv.Itoa(meta.Page))
+		w.Header().Set("X-Per-Page", strconv.Itoa(meta.PerPage))
+		w.Header().Set("X-Total-Pages", strconv.Itoa(meta.TotalPages))
+	}
+	return response.Data
+}
+
+// camelizeJSON đổi mọi key của JSON object (kể cả object lồng nhau và map) từ snake_case sang camelCase
+func camelizeJSON(body interface{}) (interface{}, error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber() // Giữ nguyên số lớn (int64) khi encode lại
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return camelizeValue(value), nil
+}
+
+// camelizeValue đổi key của object đã decode, đệ quy vào object/array lồng nhau
+func camelizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[utils.SnakeToLowerCamel(key)] = camelizeValue(item)
+		}
+		return result
+	case []interface{}:
+		for i, item := range v {
+			v[i] = camelizeValue(item)
+		}
+		return v
+	}
+	return value
+}
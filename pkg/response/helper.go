@@ -3,7 +3,7 @@ package response
 import (
 	"net/http"
 
-	"api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
 )
 
 // Helper functions cho các use cases phổ biến
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
 )
 
 // Response là cấu trúc chuẩn cho API response
@@ -31,11 +31,21 @@ type ErrorDetail struct {
 	Message string `json:"message"`
 }
 
-// JSON gửi JSON response
+// JSON gửi JSON response, serialize theo format gắn với request (xem FormatMiddleware)
 func JSON(w http.ResponseWriter, statusCode int, response Response) {
+	recordCode(w, response.Code)
+
+	var body interface{} = response
+	if format := FormatFromWriter(w); format != DefaultFormat {
+		formatted, err := formatBody(w, response, format)
+		if err == nil {
+			body = formatted
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(body)
 }
 
 // SuccessResponse tạo success response struct (dùng trong service)
@@ -0,0 +1,313 @@
+package safehttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+)
+
+var (
+	ErrInvalidURL       = errors.New("safehttp: invalid url")
+	ErrSchemeNotAllowed = errors.New("safehttp: scheme not allowed")
+	ErrPortNotAllowed   = errors.New("safehttp: port not allowed")
+	ErrBlockedAddress   = errors.New("safehttp: destination address is not public")
+	ErrTooManyRedirects = errors.New("safehttp: too many redirects")
+	ErrTooLarge         = errors.New("safehttp: response body too large")
+	ErrUnexpectedStatus = errors.New("safehttp: unexpected status code")
+	ErrUnresolvable     = errors.New("safehttp: host cannot be resolved")
+	errCredentialsInURL = fmt.Errorf("%w: credentials are not allowed", ErrInvalidURL)
+)
+
+// Config cấu hình Client gọi tới URL do user cung cấp
+type Config struct {
+	AllowedSchemes  []string       // Mặc định: https, http
+	AllowedPorts    []int          // Mặc định: 80, 443
+	AllowedNetworks []netip.Prefix // Dải không public vẫn được phép gọi (vd. service nội bộ), mặc định: không có
+	MaxBodySize     int64          // Kích thước body tối đa (bytes), mặc định: 5MB
+	Timeout         time.Duration  // Timeout toàn bộ request kể cả redirect, mặc định: 10s
+	MaxRedirects    int            // Mặc định: 5, âm = không theo redirect
+	UserAgent       string         // Mặc định: api-core-safehttp/1.0
+}
+
+// Response kết quả của Get
+type Response struct {
+	URL         string // URL cuối cùng sau redirect
+	StatusCode  int
+	Header      http.Header
+	ContentType string // Media type, không kèm tham số (charset...)
+	Body        []byte
+	Truncated   bool // Body bị cắt ở MaxBodySize (chỉ với WithTruncatedBody)
+}
+
+// Client HTTP client chặn SSRF: chỉ gọi tới IP public với scheme/port cho phép, giới hạn redirect và kích thước body.
+// IP được kiểm tra tại thời điểm dial (sau khi resolve DNS) nên không bị bypass bằng DNS rebinding hay redirect.
+type Client struct {
+	config   Config
+	client   *http.Client
+	truncate bool
+}
+
+// New tạo Client mới
+func New(config Config) *Client {
+	if len(config.AllowedSchemes) == 0 {
+		config.AllowedSchemes = []string{"https", "http"}
+	}
+	if len(config.AllowedPorts) == 0 {
+		config.AllowedPorts = []int{80, 443}
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = 5 * 1024 * 1024
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = 10 * time.Second
+	}
+	if config.MaxRedirects == 0 {
+		config.MaxRedirects = 5
+	}
+	if config.UserAgent == "" {
+		config.UserAgent = "api-core-safehttp/1.0"
+	}
+
+	c := &Client{config: config}
+
+	dialer := &net.Dialer{
+		Timeout:   config.Timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   c.control,
+	}
+	transport := &http.Transport{
+		Proxy:                 nil, // Proxy sẽ dial thay, làm mất kiểm tra IP
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   config.Timeout,
+		ResponseHeaderTimeout: config.Timeout,
+	}
+	c.client = &http.Client{
+		Transport: tracing.Transport(transport),
+		Timeout:   config.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) > config.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			return c.checkURL(req.URL)
+		},
+	}
+	return c
+}
+
+// WithMaxBodySize trả về Client dùng chung transport nhưng giới hạn body khác
+func (c *Client) WithMaxBodySize(size int64) *Client {
+	config := c.config
+	if size > 0 {
+		config.MaxBodySize = size
+	}
+	return &Client{config: config, client: c.client, truncate: c.truncate}
+}
+
+// WithTruncatedBody trả về Client chỉ đọc tối đa size bytes đầu của body thay vì báo ErrTooLarge
+// (vd. link preview chỉ cần phần <head>)
+func (c *Client) WithTruncatedBody(size int64) *Client {
+	truncated := c.WithMaxBodySize(size)
+	truncated.truncate = true
+	return truncated
+}
+
+// MaxBodySize kích thước body tối đa của Client
+func (c *Client) MaxBodySize() int64 {
+	return c.config.MaxBodySize
+}
+
+// HTTPClient http.Client đã gắn kiểm tra IP/scheme/port/redirect, dùng khi cần stream body (không giới hạn kích thước)
+func (c *Client) HTTPClient() *http.Client {
+	return c.client
+}
+
+// Validate kiểm tra URL có được phép gọi không: scheme, port và mọi IP mà host resolve ra phải public.
+// Dùng khi nhận URL để gọi sau (vd. đăng ký webhook); lúc gọi thật Client vẫn kiểm tra lại.
+func (c *Client) Validate(ctx context.Context, rawURL string) error {
+	u, err := c.parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return c.checkIP(ip)
+	}
+
+	ips, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil || len(ips) == 0 {
+		return fmt.Errorf("%w: %s", ErrUnresolvable, host)
+	}
+	for _, ip := range ips {
+		if err := c.checkIP(ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get gọi GET tới rawURL và đọc body (tối đa MaxBodySize, vượt quá trả ErrTooLarge). Status ngoài 2xx trả ErrUnexpectedStatus.
+// accept là giá trị header Accept, rỗng = */*
+func (c *Client) Get(ctx context.Context, rawURL, accept string) (*Response, error) {
+	u, err := c.parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if accept == "" {
+		accept = "*/*"
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("User-Agent", c.config.UserAgent)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+	if resp.ContentLength > c.config.MaxBodySize && !c.truncate {
+		return nil, ErrTooLarge
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, c.config.MaxBodySize+1))
+	if err != nil {
+		return nil, err
+	}
+	truncated := int64(len(body)) > c.config.MaxBodySize
+	if truncated {
+		if !c.truncate {
+			return nil, ErrTooLarge
+		}
+		body = body[:c.config.MaxBodySize]
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if i := strings.IndexByte(contentType, ';'); i >= 0 {
+		contentType = contentType[:i]
+	}
+
+	return &Response{
+		URL:         resp.Request.URL.String(),
+		StatusCode:  resp.StatusCode,
+		Header:      resp.Header,
+		ContentType: strings.ToLower(strings.TrimSpace(contentType)),
+		Body:        body,
+		Truncated:   truncated,
+	}, nil
+}
+
+// IsNotAllowed kiểm tra lỗi do URL bị chặn (scheme, port, địa chỉ không public, URL sai) chứ không phải do phía remote
+func IsNotAllowed(err error) bool {
+	return errors.Is(err, ErrInvalidURL) ||
+		errors.Is(err, ErrSchemeNotAllowed) ||
+		errors.Is(err, ErrPortNotAllowed) ||
+		errors.Is(err, ErrBlockedAddress)
+}
+
+// parse parse và kiểm tra scheme/port/host của URL
+func (c *Client) parse(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidURL, err)
+	}
+	if err := c.checkURL(u); err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+// checkURL kiểm tra scheme, port và host của URL (cả URL redirect)
+func (c *Client) checkURL(u *url.URL) error {
+	if u.Host == "" || u.Hostname() == "" {
+		return ErrInvalidURL
+	}
+	if u.User != nil {
+		return errCredentialsInURL
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	if !slices.Contains(c.config.AllowedSchemes, scheme) {
+		return fmt.Errorf("%w: %s", ErrSchemeNotAllowed, u.Scheme)
+	}
+
+	port := u.Port()
+	if port == "" {
+		switch scheme {
+		case "https":
+			port = "443"
+		case "http":
+			port = "80"
+		}
+	}
+	if err := c.checkPort(port); err != nil {
+		return err
+	}
+
+	// IP literal kiểm tra ngay, hostname được kiểm tra lúc dial
+	if ip, err := netip.ParseAddr(u.Hostname()); err == nil {
+		return c.checkIP(ip)
+	}
+	return nil
+}
+
+// checkPort kiểm tra port nằm trong allowlist
+func (c *Client) checkPort(port string) error {
+	p, err := strconv.Atoi(port)
+	if err != nil || !slices.Contains(c.config.AllowedPorts, p) {
+		return fmt.Errorf("%w: %s", ErrPortNotAllowed, port)
+	}
+	return nil
+}
+
+// checkIP kiểm tra IP là public hoặc nằm trong AllowedNetworks
+func (c *Client) checkIP(ip netip.Addr) error {
+	ip = ip.Unmap()
+	for _, prefix := range c.config.AllowedNetworks {
+		if prefix.Contains(ip) {
+			return nil
+		}
+	}
+	if !IsPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, ip)
+	}
+	return nil
+}
+
+// control chạy trước khi mở kết nối, address là IP:port đã resolve
+func (c *Client) control(network, address string, _ syscall.RawConn) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	if err := c.checkPort(port); err != nil {
+		return err
+	}
+	return c.checkIP(ip)
+}
@@ -0,0 +1,108 @@
+package securitylog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+type subjectContextKey struct{}
+
+// subject user của request, được handler/middleware bên trong điền vào
+type subject struct {
+	user string
+}
+
+// SetUser gắn user (ID hoặc email đăng nhập) cho security event của request hiện tại
+func SetUser(ctx context.Context, user string) {
+	if s, ok := ctx.Value(subjectContextKey{}).(*subject); ok && user != "" {
+		s.user = user
+	}
+}
+
+// Middleware ghi security event cho mọi response lỗi xác thực/phân quyền (401, 403, 423, 429 hoặc code tương ứng)
+func (l *Logger) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sub := &subject{}
+		ctx := context.WithValue(r.Context(), subjectContextKey{}, sub)
+		rec := &recorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		reason, ok := ReasonFor(rec.code, rec.status)
+		if !ok || rec.status < http.StatusBadRequest {
+			return
+		}
+
+		route := r.URL.Path
+		if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+			route = rctx.RoutePattern()
+		}
+		l.Record(ctx, Event{
+			Reason:    reason,
+			Code:      rec.code,
+			Status:    rec.status,
+			Method:    r.Method,
+			Route:     route,
+			Path:      r.URL.Path,
+			IP:        utils.GetClientIP(r),
+			UserAgent: r.UserAgent(),
+			User:      sub.user,
+			RequestID: chimiddleware.GetReqID(r.Context()),
+		})
+	})
+}
+
+// recorder ghi lại status và response code của response
+type recorder struct {
+	http.ResponseWriter
+	status      int
+	code        string
+	wroteHeader bool
+}
+
+// RecordCode implement response.CodeRecorder
+func (r *recorder) RecordCode(code string) {
+	r.code = code
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap cho http.ResponseController và response.JSON truy cập writer gốc
+func (r *recorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Flush hỗ trợ streaming response
+func (r *recorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack hỗ trợ WebSocket upgrade
+func (r *recorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return hijacker.Hijack()
+}
@@ -0,0 +1,200 @@
+package securitylog
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/rs/zerolog"
+)
+
+// Reason lý do của security event
+type Reason string
+
+const (
+	ReasonTokenMissing        Reason = "token_missing"
+	ReasonTokenInvalid        Reason = "token_invalid"
+	ReasonTokenExpired        Reason = "token_expired"
+	ReasonTokenRevoked        Reason = "token_revoked"
+	ReasonInvalidCredentials  Reason = "invalid_credentials"
+	ReasonUnauthorized        Reason = "unauthorized"
+	ReasonPermissionDenied    Reason = "permission_denied"
+	ReasonAccountLocked       Reason = "account_locked"
+	ReasonRateLimited         Reason = "rate_limited"
+	ReasonWebhookUnauthorized Reason = "webhook_unauthorized"
+)
+
+// codeReasons response code -> reason
+var codeReasons = map[string]Reason{
+	response.CodeTokenMissing:        ReasonTokenMissing,
+	response.CodeTokenInvalid:        ReasonTokenInvalid,
+	response.CodeTokenExpired:        ReasonTokenExpired,
+	response.CodeTokenRevoked:        ReasonTokenRevoked,
+	response.CodeInvalidCredentials:  ReasonInvalidCredentials,
+	response.CodeUnauthorized:        ReasonUnauthorized,
+	response.CodePermissionDenied:    ReasonPermissionDenied,
+	response.CodeForbidden:           ReasonPermissionDenied,
+	response.CodeAccountDisabled:     ReasonAccountLocked,
+	response.CodeRateLimitExceeded:   ReasonRateLimited,
+	response.CodeTooManyRequests:     ReasonRateLimited,
+	response.CodeWebhookUnauthorized: ReasonWebhookUnauthorized,
+}
+
+// ReasonFor phân loại response: theo response code, không có code (http.Error) thì theo status.
+// false nếu không phải lỗi xác thực/phân quyền.
+func ReasonFor(code string, status int) (Reason, bool) {
+	if reason, ok := codeReasons[code]; ok {
+		return reason, true
+	}
+	switch status {
+	case http.StatusUnauthorized:
+		return ReasonUnauthorized, true
+	case http.StatusForbidden:
+		return ReasonPermissionDenied, true
+	case http.StatusLocked:
+		return ReasonAccountLocked, true
+	case http.StatusTooManyRequests:
+		return ReasonRateLimited, true
+	}
+	return "", false
+}
+
+// Event lỗi xác thực/phân quyền của một request
+type Event struct {
+	Reason    Reason
+	Code      string // Response code (rỗng nếu response không qua pkg/response)
+	Status    int
+	Method    string
+	Route     string // Route pattern của chi, vd. /api/v1/users/{id}
+	Path      string
+	IP        string
+	UserAgent string
+	User      string // User ID hoặc identifier đăng nhập (email), rỗng nếu chưa biết
+	RequestID string
+}
+
+// Alert số security event của một IP/user vượt ngưỡng trong cửa sổ thời gian
+type Alert struct {
+	Key    string // "ip:<ip>" hoặc "user:<user>"
+	Count  int
+	Window time.Duration
+	Last   Event // Event làm vượt ngưỡng
+}
+
+// Config cấu hình security log
+type Config struct {
+	AlertThreshold int           // Số event của một IP/user trong AlertWindow để phát alert, 0: tắt alert
+	AlertWindow    time.Duration // Cửa sổ đếm (fixed window)
+	OnAlert        func(Alert)   // Hook tùy chọn (gửi Slack, webhook...), gọi ngoài lock
+}
+
+// Logger ghi security event ra stream riêng (job "security") và phát alert theo tần suất
+type Logger struct {
+	config Config
+	log    zerolog.Logger
+
+	mu      sync.Mutex
+	windows map[string]*window
+}
+
+// window bộ đếm của một key trong cửa sổ hiện tại
+type window struct {
+	start time.Time
+	count int
+}
+
+// maxWindows số key tối đa trước khi dọn các cửa sổ đã hết hạn
+const maxWindows = 10000
+
+// New tạo security logger ghi ra log
+func New(cfg Config, log zerolog.Logger) *Logger {
+	if cfg.AlertWindow <= 0 {
+		cfg.AlertWindow = time.Minute
+	}
+	return &Logger{config: cfg, log: log, windows: make(map[string]*window)}
+}
+
+// Record ghi security event và kiểm tra ngưỡng alert theo IP và user
+func (l *Logger) Record(ctx context.Context, event Event) {
+	l.log.Warn().
+		Str("event", "security_event").
+		Str("reason", string(event.Reason)).
+		Str("code", event.Code).
+		Int("status", event.Status).
+		Str("method", event.Method).
+		Str("route", event.Route).
+		Str("path", event.Path).
+		Str("ip", event.IP).
+		Str("user_agent", event.UserAgent).
+		Str("user", event.User).
+		Str("request_id", event.RequestID).
+		Msg("security event")
+
+	if l.config.AlertThreshold <= 0 {
+		return
+	}
+
+	now := clock.FromContext(ctx).Now()
+	var alerts []Alert
+	for _, key := range alertKeys(event) {
+		if count, fired := l.observe(key, now); fired {
+			alerts = append(alerts, Alert{Key: key, Count: count, Window: l.config.AlertWindow, Last: event})
+		}
+	}
+
+	for _, alert := range alerts {
+		l.log.Error().
+			Str("event", "security_anomaly").
+			Str("key", alert.Key).
+			Int("count", alert.Count).
+			Dur("window", alert.Window).
+			Str("reason", string(event.Reason)).
+			Str("route", event.Route).
+			Msg("security event rate exceeded threshold")
+		if l.config.OnAlert != nil {
+			l.config.OnAlert(alert)
+		}
+	}
+}
+
+// alertKeys key đếm tần suất của event
+func alertKeys(event Event) []string {
+	var keys []string
+	if event.IP != "" {
+		keys = append(keys, "ip:"+event.IP)
+	}
+	if event.User != "" {
+		keys = append(keys, "user:"+event.User)
+	}
+	return keys
+}
+
+// observe tăng bộ đếm của key, true đúng một lần khi chạm ngưỡng trong cửa sổ (tránh spam alert)
+func (l *Logger) observe(key string, now time.Time) (int, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.config.AlertWindow {
+		if !ok && len(l.windows) >= maxWindows {
+			l.evictExpired(now)
+		}
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+	w.count++
+	return w.count, w.count == l.config.AlertThreshold
+}
+
+// evictExpired xóa các cửa sổ đã hết hạn
+func (l *Logger) evictExpired(now time.Time) {
+	for key, w := range l.windows {
+		if now.Sub(w.start) >= l.config.AlertWindow {
+			delete(l.windows, key)
+		}
+	}
+}
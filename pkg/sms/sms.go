@@ -0,0 +1,88 @@
+package sms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+)
+
+var (
+	// ErrInvalidPhone số điện thoại không đúng định dạng E.164 (+84901234567)
+	ErrInvalidPhone = errors.New("sms: invalid phone number")
+	// ErrEmptyBody tin nhắn không có nội dung
+	ErrEmptyBody = errors.New("sms: empty body")
+)
+
+var e164 = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+// Message một tin nhắn SMS
+type Message struct {
+	To   string `json:"to"`             // Số nhận dạng E.164
+	Body string `json:"body"`           // Nội dung, quá 160 ký tự GSM (70 ký tự Unicode) bị chia thành nhiều tin
+	From string `json:"from,omitempty"` // Số/sender ID gửi, rỗng dùng cấu hình của driver
+}
+
+// Sender gửi SMS qua một provider (Twilio, SNS)
+type Sender interface {
+	Name() string
+	Send(ctx context.Context, message *Message) error
+}
+
+// NormalizePhone chuẩn hóa số điện thoại về E.164: bỏ khoảng trắng, dấu chấm, gạch, ngoặc; "00" đầu đổi thành "+"
+func NormalizePhone(phone string) (string, error) {
+	phone = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '-', '(', ')':
+			return -1
+		}
+		return r
+	}, phone)
+	if strings.HasPrefix(phone, "00") {
+		phone = "+" + phone[2:]
+	}
+	if !e164.MatchString(phone) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPhone, phone)
+	}
+	return phone, nil
+}
+
+// Validate chuẩn hóa số nhận và kiểm tra nội dung, driver gọi trước khi gửi
+func (m *Message) Validate() error {
+	to, err := NormalizePhone(m.To)
+	if err != nil {
+		return err
+	}
+	m.To = to
+	if strings.TrimSpace(m.Body) == "" {
+		return ErrEmptyBody
+	}
+	return nil
+}
+
+// OTPMessage tin nhắn mã OTP (2FA, đăng nhập không mật khẩu) theo ngôn ngữ người nhận,
+// nội dung từ bản dịch "messages.sms_otp" (%s: mã, %d: số phút hiệu lực)
+func OTPMessage(to, code, locale string, ttl time.Duration) *Message {
+	return &Message{
+		To:   to,
+		Body: i18n.T(locale, "messages.sms_otp", code, int(ttl.Minutes())),
+	}
+}
+
+// PermanentError lỗi do tin nhắn (số không hợp lệ, số bị chặn), retry không thành công
+type PermanentError interface {
+	Permanent() bool
+}
+
+// IsPermanent lỗi không nên retry: tin nhắn không hợp lệ hoặc provider từ chối tin nhắn
+func IsPermanent(err error) bool {
+	if errors.Is(err, ErrInvalidPhone) || errors.Is(err, ErrEmptyBody) {
+		return true
+	}
+	var permanent PermanentError
+	return errors.As(err, &permanent) && permanent.Permanent()
+}
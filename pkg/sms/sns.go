@@ -0,0 +1,179 @@
+package sms
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+)
+
+// SNSConfig cấu hình SNS driver. AccessKeyID rỗng: lấy credentials theo chuỗi mặc định của AWS (env, IAM role)
+type SNSConfig struct {
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SenderID        string        // Optional: alphanumeric sender ID (quốc gia hỗ trợ)
+	From            string        // Optional: origination number (E.164) đã đăng ký trong SNS
+	SMSType         string        // Transactional (default, ưu tiên OTP) hoặc Promotional
+	MaxPrice        string        // Optional: giá tối đa (USD) cho một tin
+	Endpoint        string        // Optional: URL thay thế (test, VPC endpoint), rỗng dùng https://sns.<region>.amazonaws.com
+	Timeout         time.Duration // Timeout cho mỗi request
+}
+
+// SNSDriver gửi SMS qua AWS SNS Publish (gửi thẳng tới số điện thoại, không qua topic)
+type SNSDriver struct {
+	config      SNSConfig
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	httpClient  *http.Client
+}
+
+// NewSNSDriver tạo SNS driver
+func NewSNSDriver(ctx context.Context, cfg SNSConfig) (*SNSDriver, error) {
+	if cfg.Region == "" {
+		return nil, fmt.Errorf("sms: SNS region is required")
+	}
+
+	options := []func(*awsconfig.LoadOptions) error{awsconfig.WithRegion(cfg.Region)}
+	if cfg.AccessKeyID != "" {
+		options = append(options, awsconfig.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		))
+	}
+	awsConfig, err := awsconfig.LoadDefaultConfig(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("sms: failed to load AWS config: %w", err)
+	}
+
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = fmt.Sprintf("https://sns.%s.amazonaws.com", cfg.Region)
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.SMSType == "" {
+		cfg.SMSType = "Transactional"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &SNSDriver{
+		config:      cfg,
+		credentials: awsConfig.Credentials,
+		signer:      v4.NewSigner(),
+		httpClient:  &http.Client{Transport: tracing.Transport(nil), Timeout: cfg.Timeout},
+	}, nil
+}
+
+// Name implement Sender
+func (d *SNSDriver) Name() string {
+	return "sns"
+}
+
+// Send implement Sender
+func (d *SNSDriver) Send(ctx context.Context, message *Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"Action":      {"Publish"},
+		"Version":     {"2010-03-31"},
+		"PhoneNumber": {message.To},
+		"Message":     {message.Body},
+	}
+	attributes := [][2]string{{"AWS.SNS.SMS.SMSType", d.config.SMSType}}
+	if d.config.SenderID != "" {
+		attributes = append(attributes, [2]string{"AWS.SNS.SMS.SenderID", d.config.SenderID})
+	}
+	from := message.From
+	if from == "" {
+		from = d.config.From
+	}
+	if from != "" {
+		attributes = append(attributes, [2]string{"AWS.MM.SMS.OriginationNumber", from})
+	}
+	if d.config.MaxPrice != "" {
+		attributes = append(attributes, [2]string{"AWS.SNS.SMS.MaxPrice", d.config.MaxPrice})
+	}
+	for i, attribute := range attributes {
+		prefix := "MessageAttributes.entry." + strconv.Itoa(i+1) + "."
+		form.Set(prefix+"Name", attribute[0])
+		form.Set(prefix+"Value.DataType", "String")
+		form.Set(prefix+"Value.StringValue", attribute[1])
+	}
+
+	body := form.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.config.Endpoint+"/", strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	creds, err := d.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("sms: failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256([]byte(body))
+	if err := d.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), "sns", d.config.Region, time.Now()); err != nil {
+		return fmt.Errorf("sms: failed to sign SNS request: %w", err)
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms: SNS request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	var errBody struct {
+		Error struct {
+			Type    string `xml:"Type"`
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		} `xml:"Error"`
+	}
+	xml.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(&errBody)
+	return &SNSError{
+		StatusCode: resp.StatusCode,
+		Type:       errBody.Error.Type,
+		Code:       errBody.Error.Code,
+		Message:    errBody.Error.Message,
+	}
+}
+
+// SNSError lỗi SNS trả về, Type "Sender" là lỗi của request (InvalidParameter, OptedOut), "Receiver" là lỗi phía AWS
+type SNSError struct {
+	StatusCode int
+	Type       string
+	Code       string
+	Message    string
+}
+
+func (e *SNSError) Error() string {
+	return fmt.Sprintf("sms: SNS %s (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Permanent implement PermanentError: lỗi Sender trừ throttling và credentials
+func (e *SNSError) Permanent() bool {
+	switch e.Code {
+	case "Throttling", "ThrottledException", "AuthorizationError", "InvalidClientTokenId", "SignatureDoesNotMatch", "ExpiredToken":
+		return false
+	}
+	return e.Type == "Sender" && e.StatusCode >= 400 && e.StatusCode < 500
+}
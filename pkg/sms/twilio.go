@@ -0,0 +1,108 @@
+package sms
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+)
+
+// TwilioConfig cấu hình Twilio driver
+type TwilioConfig struct {
+	AccountSID          string
+	AuthToken           string
+	From                string        // Số gửi mặc định (E.164) hoặc alphanumeric sender ID
+	MessagingServiceSID string        // Optional: gửi qua Messaging Service (Twilio chọn số gửi), ưu tiên hơn From
+	Endpoint            string        // Optional: URL thay thế (test), rỗng dùng https://api.twilio.com
+	Timeout             time.Duration // Timeout cho mỗi request
+}
+
+// TwilioDriver gửi SMS qua Twilio Programmable Messaging API
+type TwilioDriver struct {
+	config     TwilioConfig
+	httpClient *http.Client
+}
+
+// NewTwilioDriver tạo Twilio driver
+func NewTwilioDriver(cfg TwilioConfig) (*TwilioDriver, error) {
+	if cfg.AccountSID == "" || cfg.AuthToken == "" {
+		return nil, fmt.Errorf("sms: Twilio account SID and auth token are required")
+	}
+	if cfg.From == "" && cfg.MessagingServiceSID == "" {
+		return nil, fmt.Errorf("sms: Twilio from number or messaging service SID is required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://api.twilio.com"
+	}
+	cfg.Endpoint = strings.TrimRight(cfg.Endpoint, "/")
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &TwilioDriver{config: cfg, httpClient: &http.Client{Transport: tracing.Transport(nil), Timeout: cfg.Timeout}}, nil
+}
+
+// Name implement Sender
+func (d *TwilioDriver) Name() string {
+	return "twilio"
+}
+
+// Send implement Sender
+func (d *TwilioDriver) Send(ctx context.Context, message *Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	form := url.Values{"To": {message.To}, "Body": {message.Body}}
+	switch {
+	case message.From != "":
+		form.Set("From", message.From)
+	case d.config.MessagingServiceSID != "":
+		form.Set("MessagingServiceSid", d.config.MessagingServiceSID)
+	default:
+		form.Set("From", d.config.From)
+	}
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", d.config.Endpoint, url.PathEscape(d.config.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(d.config.AccountSID, d.config.AuthToken)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sms: Twilio request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusCreated || resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	twilioErr := &TwilioError{StatusCode: resp.StatusCode}
+	json.NewDecoder(io.LimitReader(resp.Body, 4096)).Decode(twilioErr)
+	return twilioErr
+}
+
+// TwilioError lỗi Twilio trả về, Code là mã lỗi Twilio (21211: số không hợp lệ, 21610: người nhận đã STOP)
+type TwilioError struct {
+	StatusCode int    `json:"status"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+}
+
+func (e *TwilioError) Error() string {
+	return fmt.Sprintf("sms: Twilio error %d (status %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// Permanent implement PermanentError: 4xx trừ 401 (sai credentials), 429 (rate limit) là lỗi của tin nhắn
+func (e *TwilioError) Permanent() bool {
+	return e.StatusCode >= 400 && e.StatusCode < 500 &&
+		e.StatusCode != http.StatusUnauthorized && e.StatusCode != http.StatusTooManyRequests
+}
@@ -0,0 +1,194 @@
+package socket
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxAPIBodySize largest request body accepted by the internal API
+const maxAPIBodySize = 1 << 20
+
+// protocolTypes message types reserved for the hub protocol, not accepted from the internal API
+var protocolTypes = map[string]bool{
+	MessageTypeJoinRoom: true, MessageTypeLeaveRoom: true, MessageTypeBroadcast: true,
+	MessageTypeJoined: true, MessageTypeLeft: true, MessageTypeRoomError: true,
+	MessageTypeReconnect: true, MessageTypeResumed: true, MessageTypeResumeFailed: true,
+	MessageTypeSession: true, MessageTypeAck: true, MessageTypePresence: true, MessageTypeTokenExpired: true,
+	MessageTypeRateLimited: true, MessageTypeReply: true, MessageTypeError: true, MessageTypeEvent: true,
+}
+
+// BroadcastRequest body of POST /internal/socket/broadcast
+type BroadcastRequest struct {
+	Type string      `json:"type"`           // Event type, e.g. "notification"
+	Room string      `json:"room,omitempty"` // Members of the room only, empty: every client
+	Data interface{} `json:"data"`
+}
+
+// SendRequest body of POST /internal/socket/users/{id}/send
+type SendRequest struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// DeliveryData response of the internal API
+type DeliveryData struct {
+	Type        string `json:"type"`
+	Room        string `json:"room,omitempty"`
+	UserID      string `json:"user_id,omitempty"`
+	Connections int    `json:"connections"` // Connections of this instance the event was queued for
+}
+
+// ParseAPIClients parses "client_id:secret,client_id2:secret2" (HTTP Basic credentials of the internal API)
+func ParseAPIClients(value string) (map[string]string, error) {
+	clients := map[string]string{}
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("invalid socket API client %q: expected client_id:secret", pair)
+		}
+		clients[id] = secret
+	}
+	return clients, nil
+}
+
+// RegisterInternalRoutes registers the internal API used by backend services and cron jobs to push events
+// without importing the hub. Every request must authenticate with one of clients (HTTP Basic); nothing is
+// registered when clients is empty.
+func RegisterInternalRoutes(r chi.Router, hub *Hub, clients map[string]string) {
+	if len(clients) == 0 {
+		return
+	}
+	handler := NewHandler(hub)
+
+	r.Route("/internal/socket", func(r chi.Router) {
+		r.Use(requireClient(clients))
+
+		r.Post("/broadcast", handler.Broadcast)        // Every client or the members of a room
+		r.Post("/users/{id}/send", handler.SendToUser) // Every connection of a user
+		r.Get("/metrics", handler.Metrics)             // Hub figures for capacity planning
+	})
+}
+
+// requireClient checks the HTTP Basic credentials against clients
+func requireClient(clients map[string]string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, secret, ok := r.BasicAuth()
+			expected, known := clients[id]
+			if !ok || !known || subtle.ConstantTimeCompare([]byte(secret), []byte(expected)) != 1 {
+				response.Unauthorized(w, i18n.GetLanguageFromContext(r.Context()), response.CodeUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Broadcast pushes an event to every client, or to the members of req.Room
+func (h *Handler) Broadcast(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+
+	var req BroadcastRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if errs := validateEventType(req.Type); errs != nil {
+		response.ValidationError(w, lang, response.CodeValidationFailed, errs)
+		return
+	}
+	if req.Room != "" && len(req.Room) > maxRoomNameLength {
+		response.ValidationError(w, lang, response.CodeValidationFailed, map[string]string{"room": ErrInvalidRoom.Error()})
+		return
+	}
+
+	message := Message{Type: req.Type, Data: req.Data, Timestamp: time.Now().Unix()}
+	data := DeliveryData{Type: req.Type, Room: req.Room}
+	if req.Room == "" {
+		data.Connections = h.hub.GetClientCount()
+		h.hub.BroadcastToAll(message)
+	} else {
+		data.Connections = h.hub.roomMembers(req.Room)
+		h.hub.BroadcastToRoom(req.Room, message)
+	}
+
+	logs.Debugf("Internal API broadcast %q to %q (%d connections)", req.Type, req.Room, data.Connections)
+	response.Success(w, lang, response.CodeSuccess, data)
+}
+
+// SendToUser pushes an event to every connection of the user {id}
+func (h *Handler) SendToUser(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	userID := chi.URLParam(r, "id")
+
+	var req SendRequest
+	if !decodeAPIRequest(w, r, &req) {
+		return
+	}
+	if errs := validateEventType(req.Type); errs != nil {
+		response.ValidationError(w, lang, response.CodeValidationFailed, errs)
+		return
+	}
+
+	data := DeliveryData{Type: req.Type, UserID: userID, Connections: h.hub.userConnections(userID)}
+	h.hub.BroadcastToUser(userID, Message{Type: req.Type, Data: req.Data, Timestamp: time.Now().Unix()})
+
+	logs.Debugf("Internal API sent %q to user %s (%d connections)", req.Type, userID, data.Connections)
+	response.Success(w, lang, response.CodeSuccess, data)
+}
+
+// Metrics returns connection counts, room membership by channel, traffic counters and goroutines of this instance
+func (h *Handler) Metrics(w http.ResponseWriter, r *http.Request) {
+	response.Success(w, i18n.GetLanguageFromContext(r.Context()), response.CodeSuccess, h.hub.Stats())
+}
+
+// decodeAPIRequest decodes the JSON body into v, writing 400 when it is invalid
+func decodeAPIRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAPIBodySize)).Decode(v); err != nil {
+		response.BadRequest(w, i18n.GetLanguageFromContext(r.Context()), response.CodeInvalidInput, nil)
+		return false
+	}
+	return true
+}
+
+// validateEventType rejects empty and protocol message types
+func validateEventType(messageType string) map[string]string {
+	switch {
+	case messageType == "":
+		return map[string]string{"type": "type is required"}
+	case protocolTypes[messageType]:
+		return map[string]string{"type": fmt.Sprintf("%q is reserved for the socket protocol", messageType)}
+	}
+	return nil
+}
+
+// userConnections number of connections of userID on this instance, including dropped ones waiting to resume
+func (h *Hub) userConnections(userID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := 0
+	for client := range h.clients {
+		if client.UserID == userID {
+			count++
+		}
+	}
+	for _, client := range h.detached {
+		if client.UserID == userID {
+			count++
+		}
+	}
+	return count
+}
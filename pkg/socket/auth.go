@@ -0,0 +1,94 @@
+package socket
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+)
+
+// MessageTypeTokenExpired is sent before the connection is closed because the access token expired
+const MessageTypeTokenExpired = "token_expired"
+
+// TokenSubprotocol Sec-WebSocket-Protocol name carrying the access token: browsers cannot set the
+// Authorization header, so they connect with new WebSocket(url, ["access_token", token])
+const TokenSubprotocol = "access_token"
+
+// CloseTokenExpired close code sent when the access token expires mid-session (4000-4999: application codes)
+const CloseTokenExpired = 4001
+
+// Authenticator verifies the upgrade request and returns the claims of its access token.
+// The error is mapped to a response code with jwt.TokenErrorCode.
+type Authenticator func(r *http.Request) (*jwt.Claims, error)
+
+// JWTAuthenticator verifies the access token of the upgrade request with manager, rejecting blacklisted
+// and revoked tokens (see jwt.Manager.VerifyTokenWithBlacklist)
+func JWTAuthenticator(manager *jwt.Manager, blacklist *jwt.Blacklist) Authenticator {
+	return func(r *http.Request) (*jwt.Claims, error) {
+		token := TokenFromRequest(r)
+		if token == "" {
+			return nil, errTokenMissing
+		}
+		return manager.VerifyTokenWithBlacklist(r.Context(), token, blacklist)
+	}
+}
+
+var errTokenMissing = errors.New("access token is missing")
+
+// TokenFromRequest access token of an upgrade request: Authorization header, Sec-WebSocket-Protocol
+// ("access_token", "<token>") or the access_token query parameter
+func TokenFromRequest(r *http.Request) string {
+	if token := jwt.ExtractTokenFromHeader(r); token != "" {
+		return token
+	}
+
+	protocols := websocketProtocols(r)
+	for i, protocol := range protocols {
+		if protocol == TokenSubprotocol && i+1 < len(protocols) {
+			return protocols[i+1]
+		}
+	}
+
+	return r.URL.Query().Get("access_token")
+}
+
+// websocketProtocols subprotocols requested by the client, in order
+func websocketProtocols(r *http.Request) []string {
+	var protocols []string
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			if protocol = strings.TrimSpace(protocol); protocol != "" {
+				protocols = append(protocols, protocol)
+			}
+		}
+	}
+	return protocols
+}
+
+// authenticate runs HubConfig.Authenticator, writing 401 (503 when the token cannot be checked) on failure.
+// Returns nil claims and true when no authenticator is configured.
+func (h *Hub) authenticate(w http.ResponseWriter, r *http.Request) (*jwt.Claims, bool) {
+	if h.config.Authenticator == nil {
+		return nil, true
+	}
+
+	claims, err := h.config.Authenticator(r)
+	if err == nil {
+		return claims, true
+	}
+
+	lang := i18n.GetLanguageFromContext(r.Context())
+	code := jwt.TokenErrorCode(err)
+	switch {
+	case errors.Is(err, errTokenMissing):
+		response.Unauthorized(w, lang, response.CodeTokenMissing)
+	case code == response.CodeServiceUnavailable:
+		response.ServiceUnavailable(w, lang, code)
+	default:
+		response.Unauthorized(w, lang, code)
+	}
+	return nil, false
+}
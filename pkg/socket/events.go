@@ -0,0 +1,134 @@
+package socket
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"runtime/debug"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/validator"
+)
+
+// Frames of typed events. Replies carry the API response envelope {success, code, message, data, errors}.
+const (
+	MessageTypeReply = "reply" // Event handled, data.data is the handler result
+	MessageTypeError = "error" // Event rejected: unknown event, invalid payload, validation or handler error
+	MessageTypeEvent = "event" // Typed event pushed by the server, "event" holds its name
+)
+
+// eventTimeout timeout of an event handler call from the read loop
+const eventTimeout = 10 * time.Second
+
+// Event envelope of a typed event sent by a client: {"event": "chat.message", "id": "1", "data": {...}}.
+// The id is echoed in the reply so the client can match it with the request.
+type Event struct {
+	Event string          `json:"event"`
+	ID    string          `json:"id,omitempty"`
+	Data  json.RawMessage `json:"data,omitempty"`
+}
+
+// EventHandler handles an event sent by client. The returned response is sent back in a "reply" frame
+// (an "error" frame when it is not successful); nil sends nothing.
+type EventHandler func(ctx context.Context, client *Client, event Event) *response.Response
+
+// On registers handler for events named event, replacing the previous handler
+func (h *Hub) On(event string, handler EventHandler) {
+	h.handlersMu.Lock()
+	defer h.handlersMu.Unlock()
+	h.handlers[event] = handler
+}
+
+// EmitToRoom pushes the typed event to the members of room
+func (h *Hub) EmitToRoom(room, event string, data interface{}) {
+	h.BroadcastToRoom(room, Message{Type: MessageTypeEvent, Event: event, Data: data, Timestamp: time.Now().Unix()})
+}
+
+// EmitToUser pushes the typed event to every connection of userID
+func (h *Hub) EmitToUser(userID, event string, data interface{}) {
+	h.BroadcastToUser(userID, Message{Type: MessageTypeEvent, Event: event, Data: data, Timestamp: time.Now().Unix()})
+}
+
+// Handle adapts a handler of a typed payload: event data is decoded into T and validated with
+// pkg/validator (validate tags) before fn is called, failures are answered with INVALID_INPUT or
+// VALIDATION_FAILED.
+func Handle[T any](fn func(ctx context.Context, client *Client, payload T) *response.Response) EventHandler {
+	return func(ctx context.Context, client *Client, event Event) *response.Response {
+		lang := i18n.GetLanguageFromContext(ctx)
+
+		var payload T
+		if len(event.Data) > 0 {
+			if err := json.Unmarshal(event.Data, &payload); err != nil {
+				return response.BadRequestResponse(lang, response.CodeInvalidInput, nil)
+			}
+		}
+		if reflect.Indirect(reflect.ValueOf(&payload)).Kind() == reflect.Struct {
+			if err := validator.Validate(&payload); err != nil {
+				return response.ValidationErrorResponse(lang, response.CodeValidationFailed, validator.ParseValidationErrors(lang, err))
+			}
+		}
+		return fn(ctx, client, payload)
+	}
+}
+
+// handleEvent routes a typed event to its handler and sends the reply
+func (c *Client) handleEvent(message Message) {
+	lang := c.Lang
+	event := Event{Event: message.Event, ID: message.ID}
+	if message.Data != nil {
+		data, err := json.Marshal(message.Data)
+		if err != nil {
+			c.replyEvent(event, response.BadRequestResponse(lang, response.CodeInvalidInput, nil))
+			return
+		}
+		event.Data = data
+	}
+
+	c.Hub.handlersMu.RLock()
+	handler, ok := c.Hub.handlers[event.Event]
+	c.Hub.handlersMu.RUnlock()
+	if !ok {
+		c.replyEvent(event, response.NotFoundResponse(lang, response.CodeNotFound))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.context(), eventTimeout)
+	defer cancel()
+	c.replyEvent(event, c.runHandler(ctx, handler, event))
+}
+
+// runHandler calls handler, a panic is answered with INTERNAL_SERVER_ERROR
+func (c *Client) runHandler(ctx context.Context, handler EventHandler, event Event) (resp *response.Response) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			logs.Errorf("Client %s event %q handler panic: %v\n%s", c.ID, event.Event, recovered, debug.Stack())
+			resp = response.InternalServerErrorResponse(c.Lang, response.CodeInternalServerError)
+		}
+	}()
+	return handler(ctx, c, event)
+}
+
+// replyEvent sends resp in a "reply" or "error" frame carrying the event name and id
+func (c *Client) replyEvent(event Event, resp *response.Response) {
+	if resp == nil {
+		return
+	}
+	messageType := MessageTypeReply
+	if !resp.Success {
+		messageType = MessageTypeError
+	}
+	c.reply(Message{Type: messageType, Event: event.Event, ID: event.ID, Data: resp})
+}
+
+// context of event handlers: language of the upgrade request and the authenticated user, so services
+// read them as in an HTTP request (i18n.GetLanguageFromContext, jwt.GetUserIDFromContext)
+func (c *Client) context() context.Context {
+	ctx := context.WithValue(context.Background(), i18n.LanguageContextKey, c.Lang)
+	if c.claims != nil {
+		ctx = jwt.ContextWithClaims(ctx, c.claims)
+	}
+	return ctx
+}
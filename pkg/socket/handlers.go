@@ -28,6 +28,9 @@ func (h *Handler) GetStats(w http.ResponseWriter, r *http.Request) {
 	stats := map[string]interface{}{
 		"total_clients": h.hub.GetClientCount(),
 		"total_rooms":   h.hub.GetRoomCount(),
+		"online_users":  len(h.hub.localOnline()), // Users connected to this instance
+		"detached":      h.hub.detachedCount(),    // Dropped connections waiting to resume
+		"liveness":      h.hub.Liveness(),         // Alive, stale (missed a pong) and idle connections
 		"timestamp":     time.Now().Unix(),
 	}
 
@@ -0,0 +1,134 @@
+package socket
+
+import (
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// CloseIdleTimeout close code of a connection that sent no message for HubConfig.IdleTimeout
+const CloseIdleTimeout = 4004
+
+// Defaults of the heartbeat
+const (
+	defaultPongWait = 60 * time.Second
+)
+
+// ConnectionInfo liveness of a connection (Hub.Connections, stats)
+type ConnectionInfo struct {
+	ID          string        `json:"id"`
+	UserID      string        `json:"user_id"`
+	ConnectedAt time.Time     `json:"connected_at"`
+	LastSeen    time.Time     `json:"last_seen"`    // Last frame received, pongs included
+	LastMessage time.Time     `json:"last_message"` // Last application message received
+	RTT         time.Duration `json:"rtt"`          // Round trip of the last ping
+	Stale       bool          `json:"stale"`        // Missed a pong, closed at PongWait if nothing arrives
+	Idle        bool          `json:"idle"`         // No message for half of IdleTimeout
+}
+
+// LivenessStats aggregated liveness of the connections of this instance
+type LivenessStats struct {
+	Alive int `json:"alive"`
+	Stale int `json:"stale"`
+	Idle  int `json:"idle"`
+}
+
+// startHeartbeat sets the read deadline and pong handler of a new connection: every frame received
+// extends the deadline by PongWait, a connection that stops answering pings fails its read
+func (c *Client) startHeartbeat() {
+	now := time.Now()
+	c.connectedAt = now
+	c.lastSeen.Store(now.UnixNano())
+	c.lastMessage.Store(now.UnixNano())
+
+	c.Conn.SetReadDeadline(now.Add(c.Hub.config.PongWait))
+	c.Conn.SetPongHandler(func(data string) error {
+		if sent, err := strconv.ParseInt(data, 10, 64); err == nil {
+			c.rtt.Store(time.Now().UnixNano() - sent)
+		}
+		c.seen(false)
+		return nil
+	})
+}
+
+// seen records a frame from the client and extends the read deadline
+func (c *Client) seen(message bool) {
+	now := time.Now()
+	c.lastSeen.Store(now.UnixNano())
+	if message {
+		c.lastMessage.Store(now.UnixNano())
+	}
+	c.Conn.SetReadDeadline(now.Add(c.Hub.config.PongWait))
+}
+
+// ping sends a ping carrying the send time, the pong echoes it to measure the round trip
+func (c *Client) ping() error {
+	c.Conn.SetWriteDeadline(time.Now().Add(c.Hub.config.WriteTimeout))
+	return c.Conn.WriteMessage(websocket.PingMessage, []byte(strconv.FormatInt(time.Now().UnixNano(), 10)))
+}
+
+// idleFor time since the last application message
+func (c *Client) idleFor() time.Duration {
+	return time.Since(time.Unix(0, c.lastMessage.Load()))
+}
+
+// closeIdle closes the connection when it sent no message for IdleTimeout, otherwise returns the time
+// left before it becomes idle
+func (c *Client) closeIdle() (time.Duration, bool) {
+	idle := c.idleFor()
+	if idle < c.Hub.config.IdleTimeout {
+		return c.Hub.config.IdleTimeout - idle, false
+	}
+	logs.Infof("Client %s idle for %s, closing", c.ID, idle.Round(time.Second))
+	c.closeWith(CloseIdleTimeout, "idle timeout")
+	return 0, true
+}
+
+// info liveness of the connection
+func (c *Client) info() ConnectionInfo {
+	config := c.Hub.config
+	lastSeen := time.Unix(0, c.lastSeen.Load())
+	lastMessage := time.Unix(0, c.lastMessage.Load())
+	return ConnectionInfo{
+		ID:          c.ID,
+		UserID:      c.UserID,
+		ConnectedAt: c.connectedAt,
+		LastSeen:    lastSeen,
+		LastMessage: lastMessage,
+		RTT:         time.Duration(c.rtt.Load()),
+		// A pong is due every PingInterval: stale halfway between a missed pong and the PongWait close
+		Stale: time.Since(lastSeen) > config.PingInterval+(config.PongWait-config.PingInterval)/2,
+		Idle:  config.IdleTimeout > 0 && time.Since(lastMessage) > config.IdleTimeout/2,
+	}
+}
+
+// Connections liveness of the connections of this instance, oldest first
+func (h *Hub) Connections() []ConnectionInfo {
+	h.mu.RLock()
+	connections := make([]ConnectionInfo, 0, len(h.clients))
+	for client := range h.clients {
+		connections = append(connections, client.info())
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(connections, func(i, j int) bool { return connections[i].ConnectedAt.Before(connections[j].ConnectedAt) })
+	return connections
+}
+
+// Liveness counts alive, stale and idle connections
+func (h *Hub) Liveness() LivenessStats {
+	var stats LivenessStats
+	for _, connection := range h.Connections() {
+		switch {
+		case connection.Stale:
+			stats.Stale++
+		case connection.Idle:
+			stats.Idle++
+		default:
+			stats.Alive++
+		}
+	}
+	return stats
+}
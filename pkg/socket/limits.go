@@ -0,0 +1,95 @@
+package socket
+
+import (
+	"math"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
+)
+
+// MessageTypeRateLimited is sent when an inbound message is dropped because the client exceeds
+// HubConfig.MessagesPerSecond
+const MessageTypeRateLimited = "rate_limited"
+
+// Close codes of abusive or stuck clients (4000-4999: application codes). Messages larger than
+// HubConfig.MaxMessageSize are closed with 1009 (message too big).
+const (
+	CloseSlowConsumer = 4002 // The send buffer filled up, the client does not read fast enough
+	CloseRateLimited  = 4003 // The client kept sending above the rate limit
+)
+
+// Defaults of the connection limits
+const (
+	defaultMaxMessageSize = 64 << 10
+	defaultSendBufferSize = 256
+	defaultWriteTimeout   = 10 * time.Second
+)
+
+// maxRateStrikes consecutive rate limited messages before the connection is closed
+const maxRateStrikes = 10
+
+// RateLimitedData payload of the "rate_limited" frame
+type RateLimitedData struct {
+	Limit        float64 `json:"limit"`          // Messages per second
+	RetryAfterMs int64   `json:"retry_after_ms"` // Wait before sending again
+}
+
+// newLimiter inbound rate limiter of a connection, nil when HubConfig.MessagesPerSecond is 0
+func (h *Hub) newLimiter() *rate.Limiter {
+	if h.config.MessagesPerSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(h.config.MessagesPerSecond), h.config.MessageBurst)
+}
+
+// allowMessage reports whether an inbound message is within the rate limit. A message over the limit is
+// answered with "rate_limited"; after maxRateStrikes in a row the connection is closed with CloseRateLimited.
+func (c *Client) allowMessage() bool {
+	if c.limiter == nil || c.limiter.Allow() {
+		c.strikes = 0
+		return true
+	}
+
+	c.strikes++
+	c.Hub.counters.dropped.inc(dropRateLimited)
+	if c.strikes >= maxRateStrikes {
+		logs.Warnf("Client %s exceeded the rate limit, closing", c.ID)
+		c.closeWith(CloseRateLimited, "rate limit exceeded")
+		return false
+	}
+
+	limit := c.Hub.config.MessagesPerSecond
+	c.reply(Message{Type: MessageTypeRateLimited, Data: RateLimitedData{
+		Limit:        limit,
+		RetryAfterMs: int64(math.Ceil(1000 / limit)),
+	}})
+	return false
+}
+
+// disconnectSlow closes the send channel of a client whose buffer is full, writePump then closes the
+// connection with CloseSlowConsumer (caller holds client.mu)
+func (h *Hub) disconnectSlow(client *Client) {
+	if client.closed {
+		return
+	}
+	logs.Warnf("Client %s is not reading fast enough, closing", client.ID)
+	client.slow = true
+	client.closing = true
+	client.closedBy(closeSlowConsumer)
+	client.closed = true
+	close(client.Send)
+}
+
+// closeWith sends a close frame and closes the connection, the session is not kept for replay.
+// Safe to call from any goroutine (control frames do not conflict with writePump).
+func (c *Client) closeWith(code int, reason string) {
+	c.mu.Lock()
+	c.closing = true
+	c.closedBy(closeReasons[code])
+	c.mu.Unlock()
+
+	deadline := time.Now().Add(c.Hub.config.WriteTimeout)
+	c.Conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, reason), deadline)
+	c.Conn.Close()
+}
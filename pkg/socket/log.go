@@ -0,0 +1,30 @@
+package socket
+
+import "log"
+
+// Logger receives hub logs by level, e.g. logger.Module(logger.ModuleSocket)
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+	Errorf(format string, args ...interface{})
+}
+
+// logs receives every log of the package, stdLogger until SetLogger is called
+var logs Logger = stdLogger{}
+
+// SetLogger replaces the logger of the package, call before starting hubs
+func SetLogger(l Logger) {
+	if l == nil {
+		l = stdLogger{}
+	}
+	logs = l
+}
+
+// stdLogger prints every level with the standard log package
+type stdLogger struct{}
+
+func (stdLogger) Debugf(format string, args ...interface{}) { log.Printf(format, args...) }
+func (stdLogger) Infof(format string, args ...interface{})  { log.Printf(format, args...) }
+func (stdLogger) Warnf(format string, args ...interface{})  { log.Printf(format, args...) }
+func (stdLogger) Errorf(format string, args ...interface{}) { log.Printf(format, args...) }
@@ -0,0 +1,125 @@
+package socket
+
+import (
+	"context"
+	"sync"
+
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+)
+
+// Metrics Prometheus metrics of a hub, read from Hub.Stats at every scrape.
+// Capacity planning: <prefix>_connections and <prefix>_goroutines per instance, rate(<prefix>_messages_delivered_total[5m]);
+// suggested alert: increase(<prefix>_messages_dropped_total{reason="slow_consumer"}[15m]) > 0.
+type Metrics struct {
+	connections *metrics.Gauge
+	detached    *metrics.Gauge
+	onlineUsers *metrics.Gauge
+	rooms       *metrics.Gauge
+	members     *metrics.Gauge
+	liveness    *metrics.Gauge
+	goroutines  *metrics.Gauge
+	opened      *metrics.Counter
+	closed      *metrics.Counter
+	received    *metrics.Counter
+	delivered   *metrics.Counter
+	broadcasts  *metrics.Counter
+	dropped     *metrics.Counter
+
+	mu       sync.Mutex
+	hub      *Hub
+	last     HubStats // Counters exported at the previous scrape
+	channels map[string]bool
+}
+
+// NewMetrics registers the hub metrics in registry, an empty prefix uses "socket"
+func NewMetrics(registry *metrics.Registry, prefix string) *Metrics {
+	if prefix == "" {
+		prefix = "socket"
+	}
+
+	m := &Metrics{
+		connections: registry.NewGauge(prefix+"_connections", "Open WebSocket connections on this instance."),
+		detached:    registry.NewGauge(prefix+"_detached_sessions", "Dropped connections whose session is kept for replay."),
+		onlineUsers: registry.NewGauge(prefix+"_online_users", "Users with at least one connection on this instance."),
+		rooms:       registry.NewGauge(prefix+"_rooms", "Rooms with at least one member."),
+		members:     registry.NewGauge(prefix+"_room_members", "Room memberships by channel (room name prefix before \":\").", "channel"),
+		liveness:    registry.NewGauge(prefix+"_connections_liveness", "Connections by liveness: alive, stale (missed a pong) or idle.", "state"),
+		goroutines:  registry.NewGauge(prefix+"_goroutines", "Goroutines of the process, each connection runs two."),
+		opened:      registry.NewCounter(prefix+"_connections_opened", "Connections accepted."),
+		closed:      registry.NewCounter(prefix+"_connections_closed", "Connections closed by reason.", "reason"),
+		received:    registry.NewCounter(prefix+"_messages_received", "Messages received from clients."),
+		delivered:   registry.NewCounter(prefix+"_messages_delivered", "Messages queued to a connection."),
+		broadcasts:  registry.NewCounter(prefix+"_broadcasts", "Broadcasts by target: all, room or user.", "target"),
+		dropped:     registry.NewCounter(prefix+"_messages_dropped", "Messages not delivered by reason.", "reason"),
+		channels:    make(map[string]bool),
+	}
+	registry.RegisterCollector(m)
+	return m
+}
+
+// Watch exports the figures of hub at every scrape
+func (m *Metrics) Watch(hub *Hub) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.hub = hub
+	m.last = hub.Stats()
+	m.opened.Add(float64(m.last.Opened))
+	m.received.Add(float64(m.last.Received))
+	m.delivered.Add(float64(m.last.Delivered))
+	for reason, value := range m.last.Closed {
+		m.closed.Add(float64(value), reason)
+	}
+	for target, value := range m.last.Broadcasts {
+		m.broadcasts.Add(float64(value), target)
+	}
+	for reason, value := range m.last.Dropped {
+		m.dropped.Add(float64(value), reason)
+	}
+}
+
+// Collect implements metrics.Collector
+func (m *Metrics) Collect(ctx context.Context) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.hub == nil {
+		return
+	}
+
+	stats := m.hub.Stats()
+	m.connections.Set(float64(stats.Connections))
+	m.detached.Set(float64(stats.Detached))
+	m.onlineUsers.Set(float64(stats.OnlineUsers))
+	m.rooms.Set(float64(stats.Rooms))
+	m.goroutines.Set(float64(stats.Goroutines))
+	m.liveness.Set(float64(stats.Liveness.Alive), "alive")
+	m.liveness.Set(float64(stats.Liveness.Stale), "stale")
+	m.liveness.Set(float64(stats.Liveness.Idle), "idle")
+
+	// Channels without members are deleted instead of exported as 0
+	for channel := range m.channels {
+		if _, ok := stats.Channels[channel]; !ok {
+			m.members.Delete(channel)
+			delete(m.channels, channel)
+		}
+	}
+	for channel, members := range stats.Channels {
+		m.members.Set(float64(members), channel)
+		m.channels[channel] = true
+	}
+
+	// Counters only move forward: export the increase since the previous scrape
+	m.opened.Add(float64(stats.Opened - m.last.Opened))
+	m.received.Add(float64(stats.Received - m.last.Received))
+	m.delivered.Add(float64(stats.Delivered - m.last.Delivered))
+	for reason, value := range stats.Closed {
+		m.closed.Add(float64(value-m.last.Closed[reason]), reason)
+	}
+	for target, value := range stats.Broadcasts {
+		m.broadcasts.Add(float64(value-m.last.Broadcasts[target]), target)
+	}
+	for reason, value := range stats.Dropped {
+		m.dropped.Add(float64(value-m.last.Dropped[reason]), reason)
+	}
+	m.last = stats
+}
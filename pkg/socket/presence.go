@@ -0,0 +1,626 @@
+package socket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// MessageTypePresence is sent to the "presence:<user_id>" room when the user comes online or goes offline
+const MessageTypePresence = "presence"
+
+// PresenceRoomPrefix clients join "presence:<user_id>" to watch a user's online status
+const PresenceRoomPrefix = "presence:"
+
+// PresenceRoom room receiving the presence events of userID
+func PresenceRoom(userID string) string {
+	return PresenceRoomPrefix + userID
+}
+
+// anonymousUserID user of connections without user_id, not tracked for presence
+const anonymousUserID = "anonymous"
+
+// presenceQueueSize pending presence changes before new ones are dropped (Sync repairs the store)
+const presenceQueueSize = 1024
+
+// PresenceData payload of the "presence" frame, also the event shared between instances
+type PresenceData struct {
+	UserID string `json:"user_id"`
+	Online bool   `json:"online"`
+}
+
+// PresenceStore shares online users between instances (Redis in production). Each instance records the
+// users connected to it; a user is online while a live instance has them.
+type PresenceStore interface {
+	// SetOnline records that userID has connections on instance
+	SetOnline(ctx context.Context, instance, userID string) error
+	// SetOffline records that userID has no connection left on instance
+	SetOffline(ctx context.Context, instance, userID string) error
+	// Sync replaces the users of instance and keeps the instance alive for ttl. Users of an instance
+	// that stops syncing (crashed) go offline after ttl.
+	Sync(ctx context.Context, instance string, userIDs []string, ttl time.Duration) error
+	// Online reports which of userIDs are online on a live instance
+	Online(ctx context.Context, userIDs []string) (map[string]bool, error)
+	// OnlineElsewhere reports whether userID is online on a live instance other than instance
+	OnlineElsewhere(ctx context.Context, instance, userID string) (bool, error)
+	// ListOnline users online on a live instance, sorted
+	ListOnline(ctx context.Context) ([]string, error)
+	// Publish sends a presence change to every instance
+	Publish(ctx context.Context, event PresenceData) error
+	// Subscribe calls handler for every published change until ctx is done
+	Subscribe(ctx context.Context, handler func(PresenceData)) error
+}
+
+type presenceUpdate struct {
+	userID string
+	online bool
+	silent bool // Store only, no event (connection handed over to another instance)
+}
+
+// IsOnline reports whether userID has a connection on this or (with HubConfig.Presence) any instance
+func (h *Hub) IsOnline(ctx context.Context, userID string) (bool, error) {
+	h.mu.RLock()
+	local := h.online[userID] > 0
+	h.mu.RUnlock()
+	if local || h.config.Presence == nil {
+		return local, nil
+	}
+
+	online, err := h.config.Presence.Online(ctx, []string{userID})
+	if err != nil {
+		return false, err
+	}
+	return online[userID], nil
+}
+
+// ListOnline users connected to this or (with HubConfig.Presence) any instance, sorted
+func (h *Hub) ListOnline(ctx context.Context) ([]string, error) {
+	users := h.localOnline()
+	if h.config.Presence == nil {
+		return users, nil
+	}
+
+	shared, err := h.config.Presence.ListOnline(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(shared))
+	for _, userID := range shared {
+		seen[userID] = true
+	}
+	for _, userID := range users {
+		if !seen[userID] {
+			shared = append(shared, userID)
+		}
+	}
+	sort.Strings(shared)
+	return shared, nil
+}
+
+// localOnline users connected to this instance, sorted
+func (h *Hub) localOnline() []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	users := make([]string, 0, len(h.online))
+	for userID := range h.online {
+		users = append(users, userID)
+	}
+	sort.Strings(users)
+	return users
+}
+
+// trackPresence counts the client's connection (caller holds h.mu)
+func (h *Hub) trackPresence(client *Client) {
+	if client.UserID == "" || client.UserID == anonymousUserID || client.tracked {
+		return
+	}
+	client.tracked = true
+	h.online[client.UserID]++
+	if h.online[client.UserID] == 1 {
+		h.queuePresence(presenceUpdate{userID: client.UserID, online: true})
+	}
+}
+
+// untrackPresence removes the client's connection (caller holds h.mu)
+func (h *Hub) untrackPresence(client *Client) {
+	if !client.tracked {
+		return
+	}
+	client.tracked = false
+	h.online[client.UserID]--
+	if h.online[client.UserID] > 0 {
+		return
+	}
+	delete(h.online, client.UserID)
+
+	client.mu.Lock()
+	resuming := client.resumeToken != ""
+	client.mu.Unlock()
+	h.queuePresence(presenceUpdate{userID: client.UserID, online: false, silent: resuming})
+}
+
+// queuePresence hands the change to runPresence without blocking the hub loop
+func (h *Hub) queuePresence(update presenceUpdate) {
+	select {
+	case h.presenceUpdates <- update:
+	default:
+		logs.Warnf("WebSocket presence queue full, dropped update of user %s", update.userID)
+	}
+}
+
+// startPresence runs the presence loop (and the subscription to other instances) until Shutdown
+func (h *Hub) startPresence() {
+	h.presenceOnce.Do(func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		h.stopPresence = cancel
+		h.presenceDone = make(chan struct{})
+		go h.runPresence(ctx)
+		if h.config.Presence != nil {
+			go h.subscribePresence(ctx)
+		}
+	})
+}
+
+// runPresence applies presence changes in order and keeps this instance alive in the store
+func (h *Hub) runPresence(ctx context.Context) {
+	defer close(h.presenceDone)
+
+	var heartbeat <-chan time.Time
+	if h.config.Presence != nil {
+		h.syncPresence(ctx, h.localOnline())
+		ticker := time.NewTicker(h.config.PresenceTTL / 3)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+
+	for {
+		select {
+		case update := <-h.presenceUpdates:
+			h.applyPresence(ctx, update)
+		case <-heartbeat:
+			h.syncPresence(ctx, h.localOnline())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// applyPresence records the change and sends the event when the user's status changed on every instance
+func (h *Hub) applyPresence(ctx context.Context, update presenceUpdate) {
+	event := PresenceData{UserID: update.userID, Online: update.online}
+	store := h.config.Presence
+	if store == nil {
+		if !update.silent {
+			h.BroadcastToRoom(PresenceRoom(event.UserID), presenceMessage(event))
+		}
+		return
+	}
+
+	// Online on another instance: status unchanged for watchers
+	elsewhere, err := store.OnlineElsewhere(ctx, h.config.InstanceID, event.UserID)
+	if err != nil {
+		logs.Errorf("WebSocket presence lookup error: %v", err)
+		return
+	}
+	if update.online {
+		err = store.SetOnline(ctx, h.config.InstanceID, event.UserID)
+	} else {
+		err = store.SetOffline(ctx, h.config.InstanceID, event.UserID)
+	}
+	if err != nil {
+		logs.Errorf("WebSocket presence update error: %v", err)
+		return
+	}
+	if update.silent || elsewhere {
+		return
+	}
+	if err := store.Publish(ctx, event); err != nil {
+		logs.Errorf("WebSocket presence publish error: %v", err)
+	}
+}
+
+// syncPresence writes the users of this instance and refreshes its TTL
+func (h *Hub) syncPresence(ctx context.Context, users []string) {
+	if err := h.config.Presence.Sync(ctx, h.config.InstanceID, users, h.config.PresenceTTL); err != nil {
+		logs.Errorf("WebSocket presence sync error: %v", err)
+	}
+}
+
+// subscribePresence forwards presence events of every instance to the local presence rooms
+func (h *Hub) subscribePresence(ctx context.Context) {
+	for ctx.Err() == nil {
+		err := h.config.Presence.Subscribe(ctx, func(event PresenceData) {
+			h.BroadcastToRoom(PresenceRoom(event.UserID), presenceMessage(event))
+		})
+		if err != nil && ctx.Err() == nil {
+			logs.Errorf("WebSocket presence subscription error: %v", err)
+			select {
+			case <-time.After(time.Second):
+			case <-ctx.Done():
+			}
+		}
+	}
+}
+
+// shutdownPresence stops the presence loop and removes this instance's users from the store
+func (h *Hub) shutdownPresence() {
+	if h.stopPresence == nil {
+		return
+	}
+	h.stopPresence()
+	<-h.presenceDone
+
+	if h.config.Presence != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		h.syncPresence(ctx, nil)
+	}
+}
+
+func presenceMessage(event PresenceData) Message {
+	return Message{Type: MessageTypePresence, Data: event, Timestamp: time.Now().Unix()}
+}
+
+// MemoryPresenceStore PresenceStore for a single process (hubs in the same process share it)
+type MemoryPresenceStore struct {
+	mu          sync.Mutex
+	instances   map[string]*memoryPresenceInstance
+	subscribers map[int]func(PresenceData)
+	nextID      int
+}
+
+type memoryPresenceInstance struct {
+	users     map[string]bool
+	expiresAt time.Time // Zero until the first Sync
+}
+
+// NewMemoryPresenceStore creates an in-process PresenceStore
+func NewMemoryPresenceStore() *MemoryPresenceStore {
+	return &MemoryPresenceStore{
+		instances:   make(map[string]*memoryPresenceInstance),
+		subscribers: make(map[int]func(PresenceData)),
+	}
+}
+
+func (s *MemoryPresenceStore) instance(id string) *memoryPresenceInstance {
+	instance, ok := s.instances[id]
+	if !ok {
+		instance = &memoryPresenceInstance{users: make(map[string]bool)}
+		s.instances[id] = instance
+	}
+	return instance
+}
+
+// live instances that synced within their TTL, except exclude (caller holds s.mu)
+func (s *MemoryPresenceStore) live(exclude string) []*memoryPresenceInstance {
+	now := time.Now()
+	var live []*memoryPresenceInstance
+	for id, instance := range s.instances {
+		if id != exclude && (instance.expiresAt.IsZero() || instance.expiresAt.After(now)) {
+			live = append(live, instance)
+		}
+	}
+	return live
+}
+
+// SetOnline implements PresenceStore
+func (s *MemoryPresenceStore) SetOnline(ctx context.Context, instance, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.instance(instance).users[userID] = true
+	return nil
+}
+
+// SetOffline implements PresenceStore
+func (s *MemoryPresenceStore) SetOffline(ctx context.Context, instance, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.instance(instance).users, userID)
+	return nil
+}
+
+// Sync implements PresenceStore
+func (s *MemoryPresenceStore) Sync(ctx context.Context, instance string, userIDs []string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		users[userID] = true
+	}
+	s.instances[instance] = &memoryPresenceInstance{users: users, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Online implements PresenceStore
+func (s *MemoryPresenceStore) Online(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	online := make(map[string]bool, len(userIDs))
+	for _, instance := range s.live("") {
+		for _, userID := range userIDs {
+			if instance.users[userID] {
+				online[userID] = true
+			}
+		}
+	}
+	return online, nil
+}
+
+// OnlineElsewhere implements PresenceStore
+func (s *MemoryPresenceStore) OnlineElsewhere(ctx context.Context, instance, userID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, other := range s.live(instance) {
+		if other.users[userID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListOnline implements PresenceStore
+func (s *MemoryPresenceStore) ListOnline(ctx context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool)
+	users := []string{}
+	for _, instance := range s.live("") {
+		for userID := range instance.users {
+			if !seen[userID] {
+				seen[userID] = true
+				users = append(users, userID)
+			}
+		}
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// Publish implements PresenceStore
+func (s *MemoryPresenceStore) Publish(ctx context.Context, event PresenceData) error {
+	s.mu.Lock()
+	handlers := make([]func(PresenceData), 0, len(s.subscribers))
+	for _, handler := range s.subscribers {
+		handlers = append(handlers, handler)
+	}
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+	return nil
+}
+
+// Subscribe implements PresenceStore
+func (s *MemoryPresenceStore) Subscribe(ctx context.Context, handler func(PresenceData)) error {
+	s.mu.Lock()
+	id := s.nextID
+	s.nextID++
+	s.subscribers[id] = handler
+	s.mu.Unlock()
+
+	<-ctx.Done()
+
+	s.mu.Lock()
+	delete(s.subscribers, id)
+	s.mu.Unlock()
+	return nil
+}
+
+// DefaultPresencePrefix key prefix of RedisPresenceStore
+const DefaultPresencePrefix = "socket:presence:"
+
+// RedisPresenceStore implements PresenceStore with Redis: a set of users per instance (expiring after the
+// instance's TTL), a sorted set of instances scored by expiry and a pub/sub channel for presence events
+type RedisPresenceStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedisPresenceStore creates a PresenceStore on Redis, empty prefix: DefaultPresencePrefix
+func NewRedisPresenceStore(client redis.UniversalClient, prefix string) *RedisPresenceStore {
+	if prefix == "" {
+		prefix = DefaultPresencePrefix
+	}
+	return &RedisPresenceStore{client: client, prefix: prefix}
+}
+
+// SetOnline implements PresenceStore
+func (r *RedisPresenceStore) SetOnline(ctx context.Context, instance, userID string) error {
+	if err := r.client.SAdd(ctx, r.instanceKey(instance), userID).Err(); err != nil {
+		return fmt.Errorf("failed to set user %s online: %w", userID, err)
+	}
+	return nil
+}
+
+// SetOffline implements PresenceStore
+func (r *RedisPresenceStore) SetOffline(ctx context.Context, instance, userID string) error {
+	if err := r.client.SRem(ctx, r.instanceKey(instance), userID).Err(); err != nil {
+		return fmt.Errorf("failed to set user %s offline: %w", userID, err)
+	}
+	return nil
+}
+
+// Sync implements PresenceStore
+func (r *RedisPresenceStore) Sync(ctx context.Context, instance string, userIDs []string, ttl time.Duration) error {
+	now := time.Now()
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		key := r.instanceKey(instance)
+		pipe.Del(ctx, key)
+		if len(userIDs) > 0 {
+			members := make([]interface{}, len(userIDs))
+			for i, userID := range userIDs {
+				members[i] = userID
+			}
+			pipe.SAdd(ctx, key, members...)
+			pipe.PExpire(ctx, key, ttl)
+		}
+		pipe.ZAdd(ctx, r.instancesKey(), &redis.Z{Score: float64(now.Add(ttl).UnixMilli()), Member: instance})
+		// Instances that stopped syncing
+		pipe.ZRemRangeByScore(ctx, r.instancesKey(), "-inf", fmt.Sprintf("(%d", now.UnixMilli()))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sync presence of instance %s: %w", instance, err)
+	}
+	return nil
+}
+
+// live keys of the user sets of live instances, except exclude
+func (r *RedisPresenceStore) live(ctx context.Context, exclude string) ([]string, error) {
+	instances, err := r.client.ZRangeByScore(ctx, r.instancesKey(), &redis.ZRangeBy{
+		Min: fmt.Sprintf("(%d", time.Now().UnixMilli()),
+		Max: "+inf",
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presence instances: %w", err)
+	}
+
+	keys := make([]string, 0, len(instances))
+	for _, instance := range instances {
+		if instance != exclude {
+			keys = append(keys, r.instanceKey(instance))
+		}
+	}
+	return keys, nil
+}
+
+// Online implements PresenceStore
+func (r *RedisPresenceStore) Online(ctx context.Context, userIDs []string) (map[string]bool, error) {
+	online := make(map[string]bool, len(userIDs))
+	keys, err := r.live(ctx, "")
+	if err != nil || len(keys) == 0 || len(userIDs) == 0 {
+		return online, err
+	}
+
+	members := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = userID
+	}
+	cmds := make([]*redis.BoolSliceCmd, len(keys))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.SMIsMember(ctx, key, members...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read presence: %w", err)
+	}
+
+	for _, cmd := range cmds {
+		for i, member := range cmd.Val() {
+			if member {
+				online[userIDs[i]] = true
+			}
+		}
+	}
+	return online, nil
+}
+
+// OnlineElsewhere implements PresenceStore
+func (r *RedisPresenceStore) OnlineElsewhere(ctx context.Context, instance, userID string) (bool, error) {
+	keys, err := r.live(ctx, instance)
+	if err != nil {
+		return false, err
+	}
+
+	cmds := make([]*redis.BoolCmd, len(keys))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.SIsMember(ctx, key, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to read presence of user %s: %w", userID, err)
+	}
+	for _, cmd := range cmds {
+		if cmd.Val() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ListOnline implements PresenceStore
+func (r *RedisPresenceStore) ListOnline(ctx context.Context) ([]string, error) {
+	keys, err := r.live(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []string{}, nil
+	}
+
+	users, err := r.client.SUnion(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list online users: %w", err)
+	}
+	sort.Strings(users)
+	return users, nil
+}
+
+// Publish implements PresenceStore
+func (r *RedisPresenceStore) Publish(ctx context.Context, event PresenceData) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode presence event: %w", err)
+	}
+	if err := r.client.Publish(ctx, r.eventsChannel(), data).Err(); err != nil {
+		return fmt.Errorf("failed to publish presence event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe implements PresenceStore
+func (r *RedisPresenceStore) Subscribe(ctx context.Context, handler func(PresenceData)) error {
+	pubsub := r.client.Subscribe(ctx, r.eventsChannel())
+	defer pubsub.Close()
+	if _, err := pubsub.Receive(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to subscribe to presence events: %w", err)
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case message, ok := <-messages:
+			if !ok {
+				return fmt.Errorf("presence subscription closed")
+			}
+			var event PresenceData
+			if err := json.Unmarshal([]byte(message.Payload), &event); err != nil {
+				logs.Errorf("WebSocket presence event decode error: %v", err)
+				continue
+			}
+			handler(event)
+		}
+	}
+}
+
+func (r *RedisPresenceStore) instancesKey() string {
+	return r.prefix + "instances"
+}
+
+func (r *RedisPresenceStore) instanceKey(instance string) string {
+	return r.prefix + "instance:" + instance
+}
+
+func (r *RedisPresenceStore) eventsChannel() string {
+	return r.prefix + "events"
+}
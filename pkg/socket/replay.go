@@ -0,0 +1,179 @@
+package socket
+
+import (
+	"sort"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/utils"
+)
+
+// Message types of acknowledgement/replay (HubConfig.ReplayBufferSize > 0)
+const (
+	MessageTypeSession = "session" // First frame of a new connection, data: {session_id, replay_window_ms}
+	MessageTypeAck     = "ack"     // Client acknowledges every message up to "seq"
+)
+
+// SessionData payload of the "session" frame
+type SessionData struct {
+	SessionID      string `json:"session_id"`
+	ReplayWindowMs int64  `json:"replay_window_ms"` // Reconnect within this window to get missed messages
+}
+
+// startSession assigns a session to a new connection and queues the "session" frame
+func (h *Hub) startSession(client *Client) {
+	client.SessionID = utils.RandomString(resumeTokenLength)
+	client.Send <- Message{
+		Type:      MessageTypeSession,
+		Data:      SessionData{SessionID: client.SessionID, ReplayWindowMs: h.config.ReplayWindow.Milliseconds()},
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// numberMessage assigns the next sequence number and keeps the message until acknowledged (caller holds client.mu)
+func (h *Hub) numberMessage(client *Client, message *Message) {
+	if client.SessionID == "" {
+		return
+	}
+	client.seq++
+	message.Seq = client.seq
+	client.unacked = append(client.unacked, *message)
+	if len(client.unacked) > h.config.ReplayBufferSize {
+		// Oldest message can no longer be replayed
+		client.unacked = client.unacked[1:]
+		if client.detached {
+			h.counters.dropped.inc(dropReplayOverflow)
+		}
+	}
+}
+
+// ack drops the messages acknowledged by the client
+func (c *Client) ack(seq uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for n < len(c.unacked) && c.unacked[n].Seq <= seq {
+		n++
+	}
+	c.unacked = c.unacked[n:]
+}
+
+// detach keeps the session of a dropped connection for HubConfig.ReplayWindow: the client stays in its rooms
+// (and online) and messages sent meanwhile are buffered for replay (caller holds h.mu)
+func (h *Hub) detach(client *Client) bool {
+	client.mu.Lock()
+	keep := client.SessionID != "" && client.dropped && client.resumeToken == "" && !h.draining
+	if keep {
+		client.detached = true
+	}
+	client.mu.Unlock()
+	if !keep {
+		return false
+	}
+
+	h.detached[client.SessionID] = client
+	client.expiry = time.AfterFunc(h.config.ReplayWindow, func() { h.expireSession(client) })
+	logs.Debugf("Client %s dropped, session kept for %s", client.ID, h.config.ReplayWindow)
+	return true
+}
+
+// expireSession removes a detached session that was not resumed in time
+func (h *Hub) expireSession(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dropSession(client)
+}
+
+// dropSession removes a detached session from its rooms and presence (caller holds h.mu)
+func (h *Hub) dropSession(client *Client) {
+	if h.detached[client.SessionID] != client {
+		return
+	}
+	delete(h.detached, client.SessionID)
+	client.expiry.Stop()
+	for room := range client.Rooms {
+		h.removeClientFromRoom(client, room)
+	}
+	h.untrackPresence(client)
+	logs.Debugf("Client %s session expired", client.ID)
+}
+
+// dropSessions removes every detached session (Shutdown: clients resume through ResumeStore instead)
+func (h *Hub) dropSessions() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, client := range h.detached {
+		h.dropSession(client)
+	}
+}
+
+// detachedCount number of dropped connections whose session is kept
+func (h *Hub) detachedCount() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.detached)
+}
+
+// reattach moves the detached session sessionID to client and queues the "resumed" frame followed by the
+// messages after lastSeq. Returns false if the session is unknown, expired or belongs to another user.
+func (h *Hub) reattach(client *Client, sessionID string, lastSeq uint64) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	old, ok := h.detached[sessionID]
+	if !ok || old.UserID != client.UserID {
+		return false
+	}
+	delete(h.detached, sessionID)
+	old.expiry.Stop()
+
+	// Take over rooms and presence of the old connection
+	for room := range old.Rooms {
+		delete(h.rooms[room], old)
+		h.rooms[room][client] = true
+		client.Rooms[room] = true
+	}
+	client.tracked, old.tracked = old.tracked, false
+
+	old.mu.Lock()
+	defer old.mu.Unlock()
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	client.SessionID = sessionID
+	client.seq = old.seq
+	client.unacked = old.unacked
+
+	var replay []Message
+	for _, message := range old.unacked {
+		if message.Seq > lastSeq {
+			replay = append(replay, message)
+		}
+	}
+	// Messages between lastSeq and the oldest buffered one were dropped from the full buffer
+	oldest := old.seq + 1
+	if len(old.unacked) > 0 {
+		oldest = old.unacked[0].Seq
+	}
+	missed := oldest > lastSeq+1
+
+	rooms := make([]string, 0, len(client.Rooms))
+	for room := range client.Rooms {
+		rooms = append(rooms, room)
+	}
+	sort.Strings(rooms)
+	if len(replay) >= cap(client.Send) {
+		client.Send = make(chan Message, len(replay)+cap(client.Send))
+	}
+	client.Send <- Message{
+		Type:      MessageTypeResumed,
+		Data:      ResumedData{Rooms: rooms, Replayed: len(replay), SessionID: sessionID, Missed: missed},
+		Timestamp: time.Now().Unix(),
+	}
+	for _, message := range replay {
+		client.Send <- message
+	}
+
+	logs.Infof("Client %s resumed session of %s, replayed %d messages", client.ID, old.ID, len(replay))
+	return true
+}
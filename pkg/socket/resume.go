@@ -0,0 +1,295 @@
+package socket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Message types used for reconnect/resume between deployments
+const (
+	MessageTypeReconnect    = "reconnect"     // Server is shutting down, reconnect with resume_token
+	MessageTypeResumed      = "resumed"       // Session restored from resume_token
+	MessageTypeResumeFailed = "resume_failed" // resume_token unknown or expired, client starts a fresh session
+)
+
+// resumeTokenLength length of generated resume tokens
+const resumeTokenLength = 43
+
+// ReconnectData payload of the "reconnect" frame
+type ReconnectData struct {
+	ResumeToken  string `json:"resume_token,omitempty"` // Empty when resume is disabled
+	RetryAfterMs int64  `json:"retry_after_ms"`         // Client should wait this long before reconnecting
+}
+
+// ResumedData payload of the "resumed" frame
+type ResumedData struct {
+	Rooms     []string `json:"rooms"`
+	Replayed  int      `json:"replayed"`             // Number of undelivered events replayed after this frame
+	SessionID string   `json:"session_id,omitempty"` // Session resumed with session_id (ack/replay)
+	Missed    bool     `json:"missed,omitempty"`     // Some messages after last_seq were no longer buffered
+}
+
+// ResumeState session of a client handed over from a shutting down instance
+type ResumeState struct {
+	UserID string    `json:"user_id"`
+	Rooms  []string  `json:"rooms"`
+	Events []Message `json:"events"` // Events not delivered before the connection closed, in order
+}
+
+// ResumeStore persists resume state shared between instances (Redis in production)
+type ResumeStore interface {
+	Save(ctx context.Context, token string, state ResumeState, ttl time.Duration) error
+	// Take returns and deletes the state, nil if the token is unknown or expired
+	Take(ctx context.Context, token string) (*ResumeState, error)
+}
+
+// cacheResumeStore ResumeStore backed by pkg/cache
+type cacheResumeStore struct {
+	cache cache.Cache
+}
+
+// NewCacheResumeStore creates a ResumeStore backed by cache (shared Redis between instances)
+func NewCacheResumeStore(c cache.Cache) ResumeStore {
+	return &cacheResumeStore{cache: c}
+}
+
+func resumeKey(token string) string {
+	return "socket:resume:" + token
+}
+
+func (s *cacheResumeStore) Save(ctx context.Context, token string, state ResumeState, ttl time.Duration) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode resume state: %w", err)
+	}
+	return s.cache.Set(ctx, resumeKey(token), string(data), ttl)
+}
+
+func (s *cacheResumeStore) Take(ctx context.Context, token string) (*ResumeState, error) {
+	raw, err := s.cache.Get(ctx, resumeKey(token))
+	if errors.Is(err, cache.ErrCacheMiss) || errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = s.cache.Del(ctx, resumeKey(token))
+
+	var state ResumeState
+	if err := json.Unmarshal([]byte(raw), &state); err != nil {
+		return nil, fmt.Errorf("failed to decode resume state: %w", err)
+	}
+	return &state, nil
+}
+
+// HubConfig configures authentication, reconnect/resume, room authorization and presence of a hub
+type HubConfig struct {
+	ResumeStore    ResumeStore   // nil: shutdown only asks clients to reconnect, no state is handed over
+	ResumeTTL      time.Duration // How long resume state is kept (default: 2 minutes)
+	ReconnectDelay time.Duration // retry_after_ms sent in the reconnect frame (default: 1s)
+
+	Authenticator        Authenticator  // Verifies the access token of /ws, nil: user_id query parameter (development only)
+	Authorizer           RoomAuthorizer // Checks join_room and room_message, nil: every room is allowed
+	AllowClientBroadcast bool           // Let clients send "broadcast" to every connected client
+
+	Presence    PresenceStore // Shares online users and presence events between instances, nil: this instance only
+	InstanceID  string        // Identifies this instance in Presence (default: hostname + random suffix)
+	PresenceTTL time.Duration // Users of an instance that stops syncing go offline after this (default: 30s)
+
+	ReplayBufferSize int           // Unacknowledged messages kept per connection for replay, 0: no seq/ack/replay
+	ReplayWindow     time.Duration // How long the session of a dropped connection is kept (default: 30s)
+
+	// Connection limits against abusive or stuck clients (see limits.go)
+	MaxMessageSize    int64         // Largest inbound message in bytes, larger ones close the connection (default: 64KB)
+	MessagesPerSecond float64       // Inbound messages per second per connection, 0: unlimited
+	MessageBurst      int           // Messages accepted at once above the rate (default: 2x MessagesPerSecond)
+	SendBufferSize    int           // Outbound messages queued per connection, a client that falls behind is closed (default: 256)
+	WriteTimeout      time.Duration // A write blocked longer than this closes the connection (default: 10s)
+
+	// Heartbeat (see heartbeat.go)
+	PingInterval time.Duration // Ping sent every interval, must be below PongWait (default: 9/10 of PongWait)
+	PongWait     time.Duration // A connection that sends nothing, not even a pong, for this long is closed (default: 60s)
+	IdleTimeout  time.Duration // A connection that sends no message for this long is closed with 4004, 0: never
+
+	// Wire formats clients may choose with ?codec= besides JSON, e.g. ProtobufCodec{} (see codec.go)
+	Codecs []Codec
+}
+
+// Shutdown asks every client to reconnect (to another instance) with a resume token.
+// Events sent to those clients afterwards are kept and persisted with their rooms when the
+// connection closes, so the new instance can replay them. Shutdown waits until all clients
+// disconnected; when ctx is done the remaining connections are closed.
+func (h *Hub) Shutdown(ctx context.Context) error {
+	h.dropSessions()
+
+	h.mu.Lock()
+	h.draining = true
+	for client := range h.clients {
+		h.startResume(client)
+	}
+	h.mu.Unlock()
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		if h.GetClientCount() == 0 {
+			h.saving.Wait()
+			h.shutdownPresence()
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			h.mu.RLock()
+			for client := range h.clients {
+				client.Conn.Close()
+			}
+			h.mu.RUnlock()
+			h.shutdownPresence()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// startResume assigns a resume token and queues the reconnect frame (caller holds h.mu)
+func (h *Hub) startResume(client *Client) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if client.resuming {
+		return
+	}
+	client.resuming = true
+
+	data := ReconnectData{RetryAfterMs: h.config.ReconnectDelay.Milliseconds()}
+	if h.config.ResumeStore != nil {
+		client.resumeToken = utils.RandomString(resumeTokenLength)
+		data.ResumeToken = client.resumeToken
+	}
+
+	select {
+	case client.Send <- Message{Type: MessageTypeReconnect, Data: data, Timestamp: time.Now().Unix()}:
+	default:
+		// Send buffer full: the connection is closed when Shutdown times out, client reconnects without resume
+	}
+}
+
+// deliver sends message to client, or keeps it for the next instance when the client is being handed over.
+// A client whose buffer is full is disconnected as a slow consumer.
+func (h *Hub) deliver(client *Client, message Message) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.resuming {
+		if client.resumeToken != "" {
+			client.pending = append(client.pending, message)
+		}
+		return
+	}
+
+	h.numberMessage(client, &message)
+	if client.detached {
+		// Kept in unacked for replay
+		return
+	}
+	if client.closed {
+		h.counters.dropped.inc(dropClosed)
+		return
+	}
+
+	select {
+	case client.Send <- message:
+		h.counters.delivered.Add(1)
+	default:
+		h.counters.dropped.inc(dropSlowConsumer)
+		h.disconnectSlow(client)
+	}
+}
+
+// takeResumeState collects rooms and undelivered events of a client being handed over (caller holds h.mu,
+// before the client leaves its rooms). Returns an empty token if the client is not being handed over.
+func (h *Hub) takeResumeState(client *Client) (string, ResumeState) {
+	client.mu.Lock()
+	token := client.resumeToken
+	pending := client.pending
+	client.mu.Unlock()
+	if token == "" {
+		return "", ResumeState{}
+	}
+
+	// Events still buffered were queued before the reconnect frame, so they come first
+	var events []Message
+	for buffered := true; buffered; {
+		select {
+		case message := <-client.Send:
+			if message.Type != MessageTypeReconnect {
+				events = append(events, message)
+			}
+		default:
+			buffered = false
+		}
+	}
+
+	state := ResumeState{UserID: client.UserID, Rooms: make([]string, 0, len(client.Rooms)), Events: append(events, pending...)}
+	for room := range client.Rooms {
+		state.Rooms = append(state.Rooms, room)
+	}
+	return token, state
+}
+
+// saveResumeState persists the state for the next instance
+func (h *Hub) saveResumeState(token string, state ResumeState) {
+	defer h.saving.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := h.config.ResumeStore.Save(ctx, token, state, h.config.ResumeTTL); err != nil {
+		logs.Errorf("WebSocket resume state save error: %v", err)
+	}
+}
+
+// resume restores the session of token into client, returning the frame to send before replayed events
+func (h *Hub) resume(ctx context.Context, client *Client, token string) (Message, []Message) {
+	failed := Message{Type: MessageTypeResumeFailed, Timestamp: time.Now().Unix()}
+	if h.config.ResumeStore == nil {
+		return failed, nil
+	}
+
+	state, err := h.config.ResumeStore.Take(ctx, token)
+	if err != nil {
+		logs.Errorf("WebSocket resume state load error: %v", err)
+		return failed, nil
+	}
+	if state == nil {
+		return failed, nil
+	}
+	// Authenticated connections only resume sessions of the same user
+	if h.config.Authenticator != nil && state.UserID != client.UserID {
+		logs.Warnf("Client %s (user %s) cannot resume session of user %s", client.ID, client.UserID, state.UserID)
+		return failed, nil
+	}
+
+	// Access may have been revoked since the rooms were joined, so they are authorized again
+	client.UserID = state.UserID
+	rooms := make([]string, 0, len(state.Rooms))
+	for _, room := range state.Rooms {
+		if err := h.Join(ctx, client, room); err != nil {
+			logs.Warnf("Client %s not resumed in room %q: %v", client.ID, room, err)
+			continue
+		}
+		rooms = append(rooms, room)
+	}
+
+	return Message{
+		Type:      MessageTypeResumed,
+		Data:      ResumedData{Rooms: rooms, Replayed: len(state.Events)},
+		Timestamp: time.Now().Unix(),
+	}, state.Events
+}
@@ -0,0 +1,187 @@
+package socket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Message types of the room (channel) protocol
+const (
+	MessageTypeJoinRoom    = "join_room"    // Client joins a room, data: room name
+	MessageTypeLeaveRoom   = "leave_room"   // Client leaves a room, data: room name
+	MessageTypeRoomMessage = "room_message" // Message to the members of a room, data: {room, message}
+	MessageTypeBroadcast   = "broadcast"    // Message to every client, only when HubConfig.AllowClientBroadcast
+	MessageTypeJoined      = "joined"       // Join accepted
+	MessageTypeLeft        = "left"         // Leave accepted
+	MessageTypeRoomError   = "room_error"   // Join, publish or broadcast rejected
+)
+
+// RoomAction action a client performs on a room, checked by RoomAuthorizer
+type RoomAction string
+
+const (
+	RoomActionJoin    RoomAction = "join"    // Receive messages of the room
+	RoomActionPublish RoomAction = "publish" // Send room_message to the room
+)
+
+// maxRoomNameLength longest room name accepted from clients
+const maxRoomNameLength = 128
+
+// authorizeTimeout timeout of a RoomAuthorizer call from the read loop
+const authorizeTimeout = 5 * time.Second
+
+var (
+	// ErrInvalidRoom room name is empty or too long
+	ErrInvalidRoom = errors.New("invalid room name")
+	// ErrRoomForbidden client is not allowed to join or publish to the room
+	ErrRoomForbidden = errors.New("room access denied")
+	// ErrNotInRoom client publishes to a room it has not joined
+	ErrNotInRoom = errors.New("not a member of the room")
+	// ErrBroadcastDisabled client broadcast is disabled (HubConfig.AllowClientBroadcast)
+	ErrBroadcastDisabled = errors.New("client broadcast is disabled")
+)
+
+// RoomAuthorizer decides whether client may perform action on room. Returning an error rejects the
+// request, the error message is sent to the client in the room_error frame. A nil authorizer allows every room.
+type RoomAuthorizer func(ctx context.Context, client *Client, room string, action RoomAction) error
+
+// RoomAuthorizers combines authorizers, the request is allowed only when every authorizer allows it
+func RoomAuthorizers(authorizers ...RoomAuthorizer) RoomAuthorizer {
+	return func(ctx context.Context, client *Client, room string, action RoomAction) error {
+		for _, authorize := range authorizers {
+			if err := authorize(ctx, client, room, action); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// RoomData payload of the "joined" and "left" frames
+type RoomData struct {
+	Room    string `json:"room"`
+	Members int    `json:"members"` // Members after the join/leave
+}
+
+// RoomErrorData payload of the "room_error" frame
+type RoomErrorData struct {
+	Room   string `json:"room,omitempty"`
+	Action string `json:"action"` // join, publish or broadcast
+	Error  string `json:"error"`
+}
+
+// Join adds client to room after validating the name and checking HubConfig.Authorizer
+func (h *Hub) Join(ctx context.Context, client *Client, room string) error {
+	if err := h.authorize(ctx, client, room, RoomActionJoin); err != nil {
+		return err
+	}
+	h.JoinRoom(client, room)
+	return nil
+}
+
+// Publish sends message from client to the members of room. The client must have joined the room and be
+// allowed to publish; user_id and timestamp are set by the server.
+func (h *Hub) Publish(ctx context.Context, client *Client, room string, message Message) error {
+	if !h.IsInRoom(client, room) {
+		return ErrNotInRoom
+	}
+	if err := h.authorize(ctx, client, room, RoomActionPublish); err != nil {
+		return err
+	}
+
+	message.Type = MessageTypeRoomMessage
+	message.UserID = client.UserID
+	message.Timestamp = time.Now().Unix()
+	h.BroadcastToRoom(room, message)
+	return nil
+}
+
+// IsInRoom reports whether client has joined room
+func (h *Hub) IsInRoom(client *Client, room string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.rooms[room][client]
+}
+
+// roomMembers number of clients in room
+func (h *Hub) roomMembers(room string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.rooms[room])
+}
+
+// authorize validates the room name and runs HubConfig.Authorizer
+func (h *Hub) authorize(ctx context.Context, client *Client, room string, action RoomAction) error {
+	if room == "" || len(room) > maxRoomNameLength {
+		return ErrInvalidRoom
+	}
+	if h.config.Authorizer == nil {
+		return nil
+	}
+	if err := h.config.Authorizer(ctx, client, room, action); err != nil {
+		return fmt.Errorf("%w: %s", ErrRoomForbidden, err.Error())
+	}
+	return nil
+}
+
+// handleRoomMessage handles the room protocol messages sent by client, other types are ignored
+func (c *Client) handleRoomMessage(message Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), authorizeTimeout)
+	defer cancel()
+
+	switch message.Type {
+	case MessageTypeJoinRoom:
+		room := roomOf(message)
+		if err := c.Hub.Join(ctx, c, room); err != nil {
+			c.rejectRoom(room, string(RoomActionJoin), err)
+			return
+		}
+		c.reply(Message{Type: MessageTypeJoined, Room: room, Data: RoomData{Room: room, Members: c.Hub.roomMembers(room)}})
+	case MessageTypeLeaveRoom:
+		room := roomOf(message)
+		c.Hub.LeaveRoom(c, room)
+		c.reply(Message{Type: MessageTypeLeft, Room: room, Data: RoomData{Room: room, Members: c.Hub.roomMembers(room)}})
+	case MessageTypeRoomMessage:
+		room := roomOf(message)
+		if err := c.Hub.Publish(ctx, c, room, message); err != nil {
+			c.rejectRoom(room, string(RoomActionPublish), err)
+		}
+	case MessageTypeBroadcast:
+		if !c.Hub.config.AllowClientBroadcast {
+			c.rejectRoom("", MessageTypeBroadcast, ErrBroadcastDisabled)
+			return
+		}
+		message.UserID = c.UserID
+		message.Timestamp = time.Now().Unix()
+		c.Hub.BroadcastToAll(message)
+	}
+}
+
+// rejectRoom sends the room_error frame to the client
+func (c *Client) rejectRoom(room, action string, err error) {
+	logs.Infof("Client %s %s room %q rejected: %v", c.ID, action, room, err)
+	c.reply(Message{Type: MessageTypeRoomError, Room: room, Data: RoomErrorData{Room: room, Action: action, Error: err.Error()}})
+}
+
+// reply sends a protocol frame to the client, dropped if the send buffer is full
+func (c *Client) reply(message Message) {
+	message.Timestamp = time.Now().Unix()
+	c.Hub.deliver(c, message)
+}
+
+// roomOf room name of a join/leave/room_message: the room field, a string data or data.room
+func roomOf(message Message) string {
+	if message.Room != "" {
+		return message.Room
+	}
+	switch data := message.Data.(type) {
+	case string:
+		return data
+	case map[string]interface{}:
+		room, _ := data["room"].(string)
+		return room
+	}
+	return ""
+}
@@ -1,17 +1,33 @@
 package socket
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"math"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
 
 	"github.com/gorilla/websocket"
+	"golang.org/x/time/rate"
 )
 
 // Message represents a WebSocket message
 type Message struct {
 	Type      string                 `json:"type"`
+	Seq       uint64                 `json:"seq,omitempty"`   // Outbound sequence number (ack/replay), ack: last received
+	Event     string                 `json:"event,omitempty"` // Typed event name (see events.go)
+	ID        string                 `json:"id,omitempty"`    // Id of the typed event a reply answers
 	Data      interface{}            `json:"data"`
 	Room      string                 `json:"room,omitempty"`
 	UserID    string                 `json:"user_id,omitempty"`
@@ -28,6 +44,58 @@ type Client struct {
 	Rooms  map[string]bool
 	Hub    *Hub
 	mu     sync.RWMutex
+
+	// Authenticated user (zero when HubConfig.Authenticator is nil), the connection is closed at ExpiresAt
+	Principal jwt.Principal
+	ExpiresAt time.Time
+	claims    *jwt.Claims
+
+	// Language of the upgrade request, used by event replies
+	Lang string
+
+	// Wire format negotiated at the upgrade (see codec.go)
+	codec Codec
+
+	// Handover to another instance (see Hub.Shutdown)
+	resuming    bool
+	resumeToken string
+	pending     []Message // Events sent after the reconnect frame
+
+	closed  bool // Send is closed, protocol replies are dropped
+	tracked bool // Counted in Hub.online (presence)
+
+	// Acknowledgement/replay after network drops (see replay.go)
+	SessionID string
+	seq       uint64      // Last sequence number sent
+	unacked   []Message   // Sent but not acknowledged, at most HubConfig.ReplayBufferSize
+	dropped   bool        // Connection lost without a close frame
+	closing   bool        // Closed by the server, the session is not kept
+	detached  bool        // Connection lost, session kept until expiry
+	expiry    *time.Timer // Removes the detached session
+
+	// Connection limits (see limits.go)
+	limiter *rate.Limiter // Inbound messages, nil: unlimited
+	strikes int           // Consecutive rate limited messages (read loop only)
+	slow    bool          // Disconnected because the send buffer filled up
+
+	// Liveness (see heartbeat.go), unix nanoseconds
+	connectedAt time.Time
+	lastSeen    atomic.Int64 // Last frame received, pongs included
+	lastMessage atomic.Int64 // Last application message received
+	rtt         atomic.Int64 // Round trip of the last ping
+
+	// Why the connection closed (see stats.go), counted when it unregisters
+	closeReason string
+}
+
+// close closes the send channel once
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
+	}
 }
 
 // Hub maintains the set of active clients and broadcasts messages
@@ -52,22 +120,96 @@ type Hub struct {
 
 	// Mutex for thread safety
 	mu sync.RWMutex
+
+	// Reconnect/resume between deployments
+	config   HubConfig
+	draining bool
+	saving   sync.WaitGroup
+
+	// Sessions of dropped connections waiting for the client to reconnect
+	detached map[string]*Client
+
+	// Typed event handlers registered with On
+	handlers   map[string]EventHandler
+	handlersMu sync.RWMutex
+
+	// Presence: connections per user on this instance, changes applied in order by runPresence
+	online          map[string]int
+	presenceUpdates chan presenceUpdate
+	presenceOnce    sync.Once
+	stopPresence    context.CancelFunc
+	presenceDone    chan struct{}
+
+	// Traffic counters (see stats.go)
+	counters  *hubCounters
+	startedAt time.Time
 }
 
 // NewHub creates a new WebSocket hub
 func NewHub() *Hub {
+	return NewHubWithConfig(HubConfig{})
+}
+
+// NewHubWithConfig creates a new WebSocket hub with reconnect/resume configuration
+func NewHubWithConfig(config HubConfig) *Hub {
+	if config.ResumeTTL <= 0 {
+		config.ResumeTTL = 2 * time.Minute
+	}
+	if config.ReconnectDelay <= 0 {
+		config.ReconnectDelay = time.Second
+	}
+	if config.ReplayWindow <= 0 {
+		config.ReplayWindow = 30 * time.Second
+	}
+	if config.MaxMessageSize <= 0 {
+		config.MaxMessageSize = defaultMaxMessageSize
+	}
+	if config.MessagesPerSecond > 0 && config.MessageBurst <= 0 {
+		config.MessageBurst = int(math.Ceil(2 * config.MessagesPerSecond))
+	}
+	if config.SendBufferSize <= 0 {
+		config.SendBufferSize = defaultSendBufferSize
+	}
+	if config.WriteTimeout <= 0 {
+		config.WriteTimeout = defaultWriteTimeout
+	}
+	if config.PongWait <= 0 {
+		config.PongWait = defaultPongWait
+	}
+	if config.PingInterval <= 0 || config.PingInterval >= config.PongWait {
+		// A ping must be answered before the read deadline
+		config.PingInterval = config.PongWait * 9 / 10
+	}
+	if config.PresenceTTL <= 0 {
+		config.PresenceTTL = 30 * time.Second
+	}
+	if config.InstanceID == "" {
+		host, _ := os.Hostname()
+		config.InstanceID = host + "-" + utils.RandomString(8)
+	}
+
 	return &Hub{
+		config:        config,
 		clients:       make(map[*Client]bool),
 		rooms:         make(map[string]map[*Client]bool),
 		register:      make(chan *Client),
 		unregister:    make(chan *Client),
 		broadcast:     make(chan Message),
 		roomBroadcast: make(chan Message),
+		detached:      make(map[string]*Client),
+		handlers:      make(map[string]EventHandler),
+		counters:      newHubCounters(),
+		startedAt:     time.Now(),
+
+		online:          make(map[string]int),
+		presenceUpdates: make(chan presenceUpdate, presenceQueueSize),
 	}
 }
 
 // Run starts the hub
 func (h *Hub) Run() {
+	h.startPresence()
+
 	for {
 		select {
 		case client := <-h.register:
@@ -91,7 +233,12 @@ func (h *Hub) registerClient(client *Client) {
 	defer h.mu.Unlock()
 
 	h.clients[client] = true
-	log.Printf("Client %s connected. Total clients: %d", client.ID, len(h.clients))
+	h.counters.opened.Add(1)
+	h.trackPresence(client)
+	if h.draining {
+		h.startResume(client)
+	}
+	logs.Debugf("Client %s connected. Total clients: %d", client.ID, len(h.clients))
 }
 
 // unregisterClient unregisters a client
@@ -101,15 +248,26 @@ func (h *Hub) unregisterClient(client *Client) {
 
 	if _, ok := h.clients[client]; ok {
 		delete(h.clients, client)
-		close(client.Send)
+		h.countClose(client)
+		if token, state := h.takeResumeState(client); token != "" && h.config.ResumeStore != nil {
+			h.saving.Add(1)
+			go h.saveResumeState(token, state)
+		}
+		client.close()
+
+		// Network drop: keep rooms and buffer messages until the client reconnects
+		if h.detach(client) {
+			return
+		}
 
 		// Remove client from all rooms
 		for room := range client.Rooms {
 			h.removeClientFromRoom(client, room)
 		}
 
-		log.Printf("Client %s disconnected. Total clients: %d", client.ID, len(h.clients))
+		logs.Debugf("Client %s disconnected. Total clients: %d", client.ID, len(h.clients))
 	}
+	h.untrackPresence(client)
 }
 
 // broadcastToAll broadcasts message to all clients
@@ -117,13 +275,12 @@ func (h *Hub) broadcastToAll(message Message) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.counters.broadcasts.inc(targetAll)
 	for client := range h.clients {
-		select {
-		case client.Send <- message:
-		default:
-			close(client.Send)
-			delete(h.clients, client)
-		}
+		h.deliver(client, message)
+	}
+	for _, client := range h.detached {
+		h.deliver(client, message)
 	}
 }
 
@@ -132,15 +289,10 @@ func (h *Hub) broadcastToRoom(message Message) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.counters.broadcasts.inc(targetRoom)
 	if room, exists := h.rooms[message.Room]; exists {
 		for client := range room {
-			select {
-			case client.Send <- message:
-			default:
-				close(client.Send)
-				delete(h.clients, client)
-				delete(room, client)
-			}
+			h.deliver(client, message)
 		}
 	}
 }
@@ -156,7 +308,7 @@ func (h *Hub) JoinRoom(client *Client, room string) {
 	h.rooms[room][client] = true
 	client.Rooms[room] = true
 
-	log.Printf("Client %s joined room %s", client.ID, room)
+	logs.Debugf("Client %s joined room %s", client.ID, room)
 }
 
 // LeaveRoom removes client from a room
@@ -178,7 +330,7 @@ func (h *Hub) removeClientFromRoom(client *Client, room string) {
 			delete(h.rooms, room)
 		}
 
-		log.Printf("Client %s left room %s", client.ID, room)
+		logs.Debugf("Client %s left room %s", client.ID, room)
 	}
 }
 
@@ -226,15 +378,16 @@ func (h *Hub) BroadcastToUser(userID string, message Message) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
+	h.counters.broadcasts.inc(targetUser)
 	message.UserID = userID
 	for client := range h.clients {
 		if client.UserID == userID {
-			select {
-			case client.Send <- message:
-			default:
-				close(client.Send)
-				delete(h.clients, client)
-			}
+			h.deliver(client, message)
+		}
+	}
+	for _, client := range h.detached {
+		if client.UserID == userID {
+			h.deliver(client, message)
 		}
 	}
 }
@@ -247,32 +400,49 @@ func (c *Client) readPump() {
 	}()
 
 	for {
-		var message Message
-		err := c.Conn.ReadJSON(&message)
+		_, raw, err := c.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("WebSocket error: %v", err)
+				logs.Warnf("WebSocket error: %v", err)
+			}
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				logs.Infof("Client %s missed the heartbeat, nothing received for %s", c.ID, c.Hub.config.PongWait)
 			}
+			// Closed without a close frame (network drop): the session can be resumed
+			c.mu.Lock()
+			c.dropped = !c.closing && !errors.Is(err, websocket.ErrReadLimit) &&
+				!websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway)
+			c.closedBy(readCloseReason(err))
+			c.mu.Unlock()
 			break
 		}
+		c.seen(true)
+		c.Hub.counters.received.Add(1)
 
-		// Handle different message types
-		switch message.Type {
-		case "join_room":
-			if room, ok := message.Data.(string); ok {
-				c.Hub.JoinRoom(c, room)
-			}
-		case "leave_room":
-			if room, ok := message.Data.(string); ok {
-				c.Hub.LeaveRoom(c, room)
-			}
-		case "broadcast":
-			c.Hub.BroadcastToAll(message)
-		case "room_message":
-			if room, ok := message.Data.(map[string]interface{})["room"].(string); ok {
-				c.Hub.BroadcastToRoom(room, message)
-			}
+		message, err := c.codec.Decode(raw)
+		if err != nil {
+			c.replyEvent(Event{}, response.BadRequestResponse(c.Lang, response.CodeInvalidInput, nil))
+			continue
+		}
+
+		if message.Type == MessageTypeAck {
+			c.ack(message.Seq)
+			continue
+		}
+
+		if !c.allowMessage() {
+			continue
+		}
+
+		// Typed events registered with Hub.On (see events.go)
+		if message.Event != "" {
+			c.handleEvent(message)
+			continue
 		}
+
+		// Room protocol: join_room, leave_room, room_message, broadcast (see rooms.go)
+		c.handleRoomMessage(message)
 	}
 }
 
@@ -280,16 +450,77 @@ func (c *Client) readPump() {
 func (c *Client) writePump() {
 	defer c.Conn.Close()
 
+	var expired <-chan time.Time
+	if !c.ExpiresAt.IsZero() {
+		timer := time.NewTimer(time.Until(c.ExpiresAt))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	// Heartbeat: a client that stops answering pings fails the read deadline (PongWait)
+	ticker := time.NewTicker(c.Hub.config.PingInterval)
+	defer ticker.Stop()
+
+	// Idle timeout: checked when it would expire, rescheduled from the last message
+	var idle <-chan time.Time
+	var idleTimer *time.Timer
+	if c.Hub.config.IdleTimeout > 0 {
+		idleTimer = time.NewTimer(c.Hub.config.IdleTimeout)
+		defer idleTimer.Stop()
+		idle = idleTimer.C
+	}
+
 	for {
 		select {
+		case <-ticker.C:
+			if err := c.ping(); err != nil {
+				return
+			}
+
+		case <-idle:
+			left, closed := c.closeIdle()
+			if closed {
+				return
+			}
+			idleTimer.Reset(left)
+
+		case <-expired:
+			// Access token expired mid-session: client reconnects with a refreshed token
+			c.mu.Lock()
+			c.closing = true
+			c.closedBy(closeTokenExpired)
+			c.mu.Unlock()
+			c.Conn.SetWriteDeadline(time.Now().Add(c.Hub.config.WriteTimeout))
+			c.write(Message{Type: MessageTypeTokenExpired, Timestamp: time.Now().Unix()})
+			c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(CloseTokenExpired, "token expired"))
+			return
+
 		case message, ok := <-c.Send:
+			// A client that stops reading blocks the write until the deadline, then it is disconnected
+			c.Conn.SetWriteDeadline(time.Now().Add(c.Hub.config.WriteTimeout))
 			if !ok {
-				c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				c.mu.RLock()
+				slow := c.slow
+				c.mu.RUnlock()
+				if slow {
+					c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(CloseSlowConsumer, "send buffer full"))
+				} else {
+					c.Conn.WriteMessage(websocket.CloseMessage, []byte{})
+				}
+				return
+			}
+
+			if err := c.write(message); err != nil {
+				logs.Warnf("WebSocket write error: %v", err)
 				return
 			}
 
-			if err := c.Conn.WriteJSON(message); err != nil {
-				log.Printf("WebSocket write error: %v", err)
+			// Instance is shutting down: close so the client reconnects to another instance
+			if message.Type == MessageTypeReconnect {
+				c.mu.Lock()
+				c.closedBy(closeShutdown)
+				c.mu.Unlock()
+				c.Conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server restarting"))
 				return
 			}
 		}
@@ -298,7 +529,20 @@ func (c *Client) writePump() {
 
 // ServeWS handles websocket requests from clients
 func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
+	// Reject before upgrading so the client gets a 401 with the response code
+	claims, ok := hub.authenticate(w, r)
+	if !ok {
+		return
+	}
+	codec, err := hub.codec(r.URL.Query().Get("codec"))
+	if err != nil {
+		response.BadRequest(w, i18n.GetLanguageFromContext(r.Context()), response.CodeInvalidInput, map[string]string{"codec": err.Error()})
+		return
+	}
+
 	upgrader := websocket.Upgrader{
+		// Echo the token subprotocol, browsers fail the handshake otherwise
+		Subprotocols: []string{TokenSubprotocol},
 		CheckOrigin: func(r *http.Request) bool {
 			// Allow connections from any origin (configure as needed)
 			return true
@@ -307,25 +551,68 @@ func ServeWS(hub *Hub, w http.ResponseWriter, r *http.Request) {
 
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("WebSocket upgrade error: %v", err)
+		logs.Warnf("WebSocket upgrade error: %v", err)
 		return
 	}
+	// Larger messages fail the read and close the connection with 1009 (message too big)
+	conn.SetReadLimit(hub.config.MaxMessageSize)
 
-	// Extract user ID from request (implement your authentication logic)
+	// Without an authenticator the user ID comes from the query (development only)
 	userID := r.URL.Query().Get("user_id")
-	if userID == "" {
-		userID = "anonymous"
+	if claims != nil {
+		userID = claims.UserID
+	} else if userID == "" {
+		userID = anonymousUserID
 	}
 
 	client := &Client{
 		ID:     fmt.Sprintf("client_%d", len(hub.clients)),
 		UserID: userID,
 		Conn:   conn,
-		Send:   make(chan Message, 256),
+		Send:   make(chan Message, hub.config.SendBufferSize),
 		Rooms:  make(map[string]bool),
 		Hub:    hub,
+		Lang:   i18n.GetLanguageFromContext(r.Context()),
+		codec:  codec,
+
+		limiter: hub.newLimiter(),
+	}
+	if claims != nil {
+		client.claims = claims
+		client.Principal = jwt.NewPrincipal(claims)
+		if claims.ExpiresAt != nil {
+			client.ExpiresAt = claims.ExpiresAt.Time
+		}
+	}
+
+	// Acknowledgement/replay: resume the session of a dropped connection or start a new one
+	if hub.config.ReplayBufferSize > 0 {
+		if cap(client.Send) <= hub.config.ReplayBufferSize {
+			client.Send = make(chan Message, hub.config.ReplayBufferSize+cap(client.Send))
+		}
+		sessionID := r.URL.Query().Get("session_id")
+		lastSeq, _ := strconv.ParseUint(r.URL.Query().Get("last_seq"), 10, 64)
+		if sessionID == "" || !hub.reattach(client, sessionID, lastSeq) {
+			if sessionID != "" {
+				client.Send <- Message{Type: MessageTypeResumeFailed, Timestamp: time.Now().Unix()}
+			}
+			hub.startSession(client)
+		}
+	}
+
+	// Resume session handed over by a shutting down instance
+	if token := r.URL.Query().Get("resume_token"); token != "" {
+		frame, events := hub.resume(r.Context(), client, token)
+		if len(events) >= cap(client.Send) {
+			client.Send = make(chan Message, len(events)+cap(client.Send))
+		}
+		client.Send <- frame
+		for _, event := range events {
+			client.Send <- event
+		}
 	}
 
+	client.startHeartbeat()
 	client.Hub.register <- client
 
 	// Start goroutines for reading and writing
@@ -0,0 +1,214 @@
+package socket
+
+import (
+	"errors"
+	"net"
+	"runtime"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Broadcast targets
+const (
+	targetAll  = "all"
+	targetRoom = "room"
+	targetUser = "user"
+)
+
+// Reasons a message was not delivered
+const (
+	dropSlowConsumer   = "slow_consumer"   // Send buffer full, the client is disconnected
+	dropClosed         = "closed"          // Connection already closing
+	dropRateLimited    = "rate_limited"    // Inbound message over the rate limit
+	dropReplayOverflow = "replay_overflow" // Buffered for a dropped connection, evicted before it resumed
+)
+
+// Reasons a connection was closed
+const (
+	closeNormal       = "normal"          // Client closed (1000, 1001)
+	closeDropped      = "dropped"         // Closed without a close frame (network loss)
+	closeHeartbeat    = "heartbeat"       // Nothing received for PongWait
+	closeIdle         = "idle"            // No message for IdleTimeout
+	closeSlowConsumer = "slow_consumer"   // Send buffer full
+	closeRateLimited  = "rate_limited"    // Kept sending above the rate limit
+	closeTooBig       = "message_too_big" // Message larger than MaxMessageSize
+	closeTokenExpired = "token_expired"   // Access token expired
+	closeShutdown     = "shutdown"        // Handed over to another instance (Hub.Shutdown)
+)
+
+// closeReasons close reason of the close codes sent by the server (Client.closeWith)
+var closeReasons = map[int]string{
+	websocket.CloseMessageTooBig:  closeTooBig,
+	websocket.CloseServiceRestart: closeShutdown,
+	CloseTokenExpired:             closeTokenExpired,
+	CloseSlowConsumer:             closeSlowConsumer,
+	CloseRateLimited:              closeRateLimited,
+	CloseIdleTimeout:              closeIdle,
+}
+
+// Limits of the per room figures
+const (
+	topRoomsLimit = 20 // Rooms listed in HubStats.TopRooms
+	channelsLimit = 32 // Channels in HubStats.Channels, the others are counted as "other"
+)
+
+// counterSet monotonic counters by a fixed set of labels, safe for concurrent use
+type counterSet map[string]*atomic.Uint64
+
+func newCounterSet(labels ...string) counterSet {
+	set := make(counterSet, len(labels))
+	for _, label := range labels {
+		set[label] = new(atomic.Uint64)
+	}
+	return set
+}
+
+func (s counterSet) inc(label string) {
+	if counter, ok := s[label]; ok {
+		counter.Add(1)
+	}
+}
+
+func (s counterSet) snapshot() map[string]uint64 {
+	values := make(map[string]uint64, len(s))
+	for label, counter := range s {
+		values[label] = counter.Load()
+	}
+	return values
+}
+
+// hubCounters traffic counters of a hub since it started
+type hubCounters struct {
+	opened     atomic.Uint64
+	received   atomic.Uint64
+	delivered  atomic.Uint64
+	broadcasts counterSet
+	dropped    counterSet
+	closed     counterSet
+}
+
+func newHubCounters() *hubCounters {
+	return &hubCounters{
+		broadcasts: newCounterSet(targetAll, targetRoom, targetUser),
+		dropped:    newCounterSet(dropSlowConsumer, dropClosed, dropRateLimited, dropReplayOverflow),
+		closed: newCounterSet(closeNormal, closeDropped, closeHeartbeat, closeIdle, closeSlowConsumer,
+			closeRateLimited, closeTooBig, closeTokenExpired, closeShutdown),
+	}
+}
+
+// closedBy records why the connection closes, the first reason wins (caller holds c.mu)
+func (c *Client) closedBy(reason string) {
+	if c.closeReason == "" {
+		c.closeReason = reason
+	}
+}
+
+// readCloseReason close reason of the error that ended the read loop
+func readCloseReason(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, websocket.ErrReadLimit):
+		return closeTooBig
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return closeHeartbeat
+	case websocket.IsCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+		return closeNormal
+	}
+	return closeDropped
+}
+
+// countClose counts a connection that unregistered by its close reason
+func (h *Hub) countClose(client *Client) {
+	client.mu.RLock()
+	reason := client.closeReason
+	client.mu.RUnlock()
+	if reason == "" {
+		reason = closeDropped
+	}
+	h.counters.closed.inc(reason)
+}
+
+// RoomStats members of a room
+type RoomStats struct {
+	Room    string `json:"room"`
+	Members int    `json:"members"`
+}
+
+// HubStats snapshot of a hub for capacity planning (GET /internal/socket/metrics, Prometheus)
+type HubStats struct {
+	Connections int            `json:"connections"`
+	Detached    int            `json:"detached"` // Dropped connections waiting to resume
+	OnlineUsers int            `json:"online_users"`
+	Rooms       int            `json:"rooms"`
+	Channels    map[string]int `json:"channels"`  // Room memberships by channel (room name prefix before ":")
+	TopRooms    []RoomStats    `json:"top_rooms"` // Largest rooms
+	Liveness    LivenessStats  `json:"liveness"`
+	Goroutines  int            `json:"goroutines"` // Goroutines of the process, 2 per connection
+
+	// Counters since the hub started
+	Opened     uint64            `json:"connections_opened"`
+	Closed     map[string]uint64 `json:"connections_closed"` // By reason
+	Received   uint64            `json:"messages_received"`
+	Delivered  uint64            `json:"messages_delivered"` // Queued to a connection
+	Broadcasts map[string]uint64 `json:"broadcasts"`         // By target: all, room, user
+	Dropped    map[string]uint64 `json:"messages_dropped"`   // By reason
+	Uptime     float64           `json:"uptime_seconds"`
+}
+
+// Stats snapshot of the hub
+func (h *Hub) Stats() HubStats {
+	h.mu.RLock()
+	stats := HubStats{
+		Connections: len(h.clients),
+		Detached:    len(h.detached),
+		Rooms:       len(h.rooms),
+		Channels:    make(map[string]int),
+	}
+	rooms := make([]RoomStats, 0, len(h.rooms))
+	for room, members := range h.rooms {
+		channel := channelOf(room)
+		if _, ok := stats.Channels[channel]; !ok && len(stats.Channels) >= channelsLimit {
+			channel = "other"
+		}
+		stats.Channels[channel] += len(members)
+		rooms = append(rooms, RoomStats{Room: room, Members: len(members)})
+	}
+	h.mu.RUnlock()
+
+	sort.Slice(rooms, func(i, j int) bool {
+		if rooms[i].Members != rooms[j].Members {
+			return rooms[i].Members > rooms[j].Members
+		}
+		return rooms[i].Room < rooms[j].Room
+	})
+	if len(rooms) > topRoomsLimit {
+		rooms = rooms[:topRoomsLimit]
+	}
+	stats.TopRooms = rooms
+
+	stats.OnlineUsers = len(h.localOnline())
+	stats.Liveness = h.Liveness()
+	stats.Goroutines = runtime.NumGoroutine()
+
+	stats.Opened = h.counters.opened.Load()
+	stats.Closed = h.counters.closed.snapshot()
+	stats.Received = h.counters.received.Load()
+	stats.Delivered = h.counters.delivered.Load()
+	stats.Broadcasts = h.counters.broadcasts.snapshot()
+	stats.Dropped = h.counters.dropped.snapshot()
+	stats.Uptime = time.Since(h.startedAt).Seconds()
+	return stats
+}
+
+// channelOf channel of a room: the name prefix before ":" (conversation, user, presence), "other" without one.
+// Keeps metric labels bounded whatever room ids clients use.
+func channelOf(room string) string {
+	if channel, _, ok := strings.Cut(room, ":"); ok && channel != "" {
+		return channel
+	}
+	return "other"
+}
@@ -2,6 +2,7 @@ package aws
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
@@ -9,13 +10,14 @@ import (
 	"strings"
 	"time"
 
-	"api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 )
 
 // S3Storage implementation cho AWS S3
@@ -34,6 +36,12 @@ type S3Config struct {
 	AccessKeyID     string `json:"access_key_id"`
 	SecretAccessKey string `json:"secret_access_key"`
 	BaseURL         string `json:"base_url"` // Custom base URL (optional)
+	// Endpoint của provider tương thích S3 (MinIO, DigitalOcean Spaces...), vd. minio:9000, https://sgp1.digitaloceanspaces.com
+	Endpoint string `json:"endpoint"`
+	// ForcePathStyle dùng URL dạng endpoint/bucket/key thay vì bucket.endpoint/key (MinIO cần bật)
+	ForcePathStyle bool `json:"force_path_style"`
+	// DisableSSL gọi endpoint bằng http (MinIO trong mạng nội bộ)
+	DisableSSL bool `json:"disable_ssl"`
 }
 
 // NewS3Storage tạo instance mới của S3Storage
@@ -51,14 +59,27 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	endpoint, err := normalizeEndpoint(cfg.Endpoint, cfg.DisableSSL)
+	if err != nil {
+		return nil, err
+	}
+
 	// Tạo S3 client
-	client := s3.NewFromConfig(awsConfig)
+	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		o.UsePathStyle = cfg.ForcePathStyle
+		if endpoint != nil {
+			o.BaseEndpoint = aws.String(endpoint.String())
+			// Nhiều provider tương thích S3 chưa hỗ trợ checksum mặc định (CRC32) của SDK mới
+			o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+			o.ResponseChecksumValidation = aws.ResponseChecksumValidationWhenRequired
+		}
+	})
 	presignClient := s3.NewPresignClient(client)
 
 	// Generate base URL nếu không được cung cấp
 	baseURL := cfg.BaseURL
 	if baseURL == "" {
-		baseURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+		baseURL = defaultBaseURL(cfg, endpoint)
 	}
 
 	return &S3Storage{
@@ -70,6 +91,38 @@ func NewS3Storage(cfg S3Config) (*S3Storage, error) {
 	}, nil
 }
 
+// normalizeEndpoint parse endpoint, thêm scheme nếu thiếu (http khi DisableSSL), nil nếu dùng AWS S3
+func normalizeEndpoint(endpoint string, disableSSL bool) (*url.URL, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if endpoint == "" {
+		return nil, nil
+	}
+	if !strings.Contains(endpoint, "://") {
+		endpoint = "https://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	if disableSSL {
+		u.Scheme = "http"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	return u, nil
+}
+
+// defaultBaseURL URL public của bucket theo endpoint và kiểu addressing
+func defaultBaseURL(cfg S3Config, endpoint *url.URL) string {
+	if endpoint == nil {
+		return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+	}
+	if cfg.ForcePathStyle {
+		return endpoint.String() + "/" + cfg.Bucket
+	}
+	return fmt.Sprintf("%s://%s.%s%s", endpoint.Scheme, cfg.Bucket, endpoint.Host, endpoint.Path)
+}
+
 // Upload file từ io.Reader
 func (s *S3Storage) Upload(ctx context.Context, key string, reader io.Reader, options *interfaces.UploadOptions) (*interfaces.FileInfo, error) {
 	// Prepare input
@@ -96,6 +149,11 @@ func (s *S3Storage) Upload(ctx context.Context, key string, reader io.Reader, op
 		input.CacheControl = aws.String(options.CacheControl)
 	}
 
+	// Set storage class
+	if options != nil && options.StorageClass != "" {
+		input.StorageClass = types.StorageClass(options.StorageClass)
+	}
+
 	// Set metadata
 	if options != nil && options.Metadata != nil {
 		input.Metadata = options.Metadata
@@ -327,6 +385,74 @@ func (s *S3Storage) GetSignedURL(ctx context.Context, key string, expiresIn int6
 	return presignResult.URL, nil
 }
 
+// PresignUpload tạo presigned PUT URL hoặc POST policy để client upload thẳng lên bucket.
+// S3 từ chối request sai Content-Type hoặc kích thước (PUT ký Content-Length, POST có content-length-range).
+func (s *S3Storage) PresignUpload(ctx context.Context, key string, options *interfaces.PresignOptions) (*interfaces.PresignedRequest, error) {
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(options.ContentType),
+		Metadata:    options.Metadata,
+	}
+	if options.CacheControl != "" {
+		input.CacheControl = aws.String(options.CacheControl)
+	}
+	if options.StorageClass != "" {
+		input.StorageClass = types.StorageClass(options.StorageClass)
+	}
+	expires := time.Duration(options.ExpiresIn) * time.Second
+
+	if options.Method == interfaces.PresignMethodPost {
+		fields := map[string]string{"Content-Type": options.ContentType}
+		conditions := []interface{}{
+			map[string]string{"Content-Type": options.ContentType},
+			[]interface{}{"content-length-range", 1, options.Size},
+		}
+		if options.CacheControl != "" {
+			fields["Cache-Control"] = options.CacheControl
+			conditions = append(conditions, map[string]string{"Cache-Control": options.CacheControl})
+		}
+		if options.StorageClass != "" {
+			fields["x-amz-storage-class"] = options.StorageClass
+			conditions = append(conditions, map[string]string{"x-amz-storage-class": options.StorageClass})
+		}
+		for k, v := range options.Metadata {
+			fields["x-amz-meta-"+k] = v
+			conditions = append(conditions, map[string]string{"x-amz-meta-" + k: v})
+		}
+
+		result, err := s.presignClient.PresignPostObject(ctx, input, func(opts *s3.PresignPostOptions) {
+			opts.Expires = expires
+			opts.Conditions = conditions
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to presign upload: %w", err)
+		}
+		for k, v := range result.Values {
+			fields[k] = v
+		}
+		return &interfaces.PresignedRequest{Method: interfaces.PresignMethodPost, URL: result.URL, Fields: fields}, nil
+	}
+
+	input.ContentLength = aws.Int64(options.Size)
+	result, err := s.presignClient.PresignPutObject(ctx, input, func(opts *s3.PresignOptions) {
+		opts.Expires = expires
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign upload: %w", err)
+	}
+
+	headers := make(map[string]string, len(result.SignedHeader))
+	for name, values := range result.SignedHeader {
+		// Host và Content-Length do HTTP client tự gửi
+		if strings.EqualFold(name, "Host") || strings.EqualFold(name, "Content-Length") || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+	return &interfaces.PresignedRequest{Method: interfaces.PresignMethodPut, URL: result.URL, Headers: headers}, nil
+}
+
 // Copy copy file
 func (s *S3Storage) Copy(ctx context.Context, srcKey, dstKey string) error {
 	source := fmt.Sprintf("%s/%s", s.bucket, srcKey)
@@ -354,6 +480,43 @@ func (s *S3Storage) Move(ctx context.Context, srcKey, dstKey string) error {
 	return s.Delete(ctx, srcKey)
 }
 
+// ApplyLifecycle thêm hoặc cập nhật lifecycle rule (theo ID) của bucket, giữ nguyên các rule khác
+func (s *S3Storage) ApplyLifecycle(ctx context.Context, rule interfaces.LifecycleRule) error {
+	var rules []types.LifecycleRule
+	current, err := s.client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(s.bucket),
+	})
+	if err != nil {
+		// Bucket chưa có lifecycle configuration
+		var apiErr smithy.APIError
+		if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "NoSuchLifecycleConfiguration" {
+			return fmt.Errorf("failed to get lifecycle configuration: %w", err)
+		}
+	} else {
+		for _, existing := range current.Rules {
+			if aws.ToString(existing.ID) != rule.ID {
+				rules = append(rules, existing)
+			}
+		}
+	}
+
+	rules = append(rules, types.LifecycleRule{
+		ID:         aws.String(rule.ID),
+		Status:     types.ExpirationStatusEnabled,
+		Filter:     &types.LifecycleRuleFilter{Prefix: aws.String(rule.Prefix)},
+		Expiration: &types.LifecycleExpiration{Days: aws.Int32(int32(rule.ExpireDays))},
+	})
+
+	_, err = s.client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(s.bucket),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{Rules: rules},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put lifecycle configuration: %w", err)
+	}
+	return nil
+}
+
 // generateURL tạo URL cho file
 func (s *S3Storage) generateURL(key string) string {
 	if !strings.HasSuffix(s.baseURL, "/") {
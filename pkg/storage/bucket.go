@@ -0,0 +1,232 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/aws"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/local"
+)
+
+// DefaultBucket tên bucket mặc định (cấu hình STORAGE_DRIVER, STORAGE_S3_*, STORAGE_LOCAL_*)
+const DefaultBucket = "default"
+
+// sharedTenant giá trị thay cho {tenant} trong prefix khi context không có tenant
+const sharedTenant = "shared"
+
+type bucketContextKey struct{}
+type tenantContextKey struct{}
+
+// WithTenant gắn tenant vào context để StorageManager route file tới bucket/prefix của tenant
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext lấy tenant từ context (rỗng nếu không có)
+func TenantFromContext(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantContextKey{}).(string)
+	return tenant
+}
+
+// WithBucket chỉ định bucket cho mọi thao tác trong context, bỏ qua luật route.
+// Dùng khi thao tác với file đã upload (xóa, lấy URL) bằng UploadResult.Bucket.
+func WithBucket(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, bucketContextKey{}, name)
+}
+
+// BucketFromContext lấy bucket được chỉ định trong context (rỗng nếu không có)
+func BucketFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(bucketContextKey{}).(string)
+	return name
+}
+
+// Route luật chọn bucket: điều kiện rỗng khớp mọi giá trị
+type Route struct {
+	Tenant   string
+	Category string
+	Bucket   string
+}
+
+// ParseRoute parse luật dạng "tenant:acme=acme", "category:video=media", "tenant:acme+category:video=acme-media"
+func ParseRoute(s string) (Route, error) {
+	conditions, bucketName, ok := cutLast(strings.TrimSpace(s), "=")
+	if !ok || bucketName == "" || conditions == "" {
+		return Route{}, fmt.Errorf("invalid storage route %q, expected key:value[+key:value]=bucket", s)
+	}
+
+	route := Route{Bucket: bucketName}
+	for _, condition := range strings.Split(conditions, "+") {
+		key, value, ok := strings.Cut(condition, ":")
+		if !ok || value == "" {
+			return Route{}, fmt.Errorf("invalid storage route condition %q in %q", condition, s)
+		}
+		switch key {
+		case "tenant":
+			route.Tenant = value
+		case "category":
+			route.Category = value
+		default:
+			return Route{}, fmt.Errorf("unsupported storage route key %q in %q: expected tenant or category", key, s)
+		}
+	}
+	return route, nil
+}
+
+// matches luật khớp tenant/category, trả về số điều kiện khớp (luật cụ thể hơn được ưu tiên)
+func (r Route) matches(tenant, category string) (int, bool) {
+	score := 0
+	if r.Tenant != "" {
+		if r.Tenant != tenant {
+			return 0, false
+		}
+		score++
+	}
+	if r.Category != "" {
+		if r.Category != category {
+			return 0, false
+		}
+		score++
+	}
+	return score, true
+}
+
+// bucket storage backend cùng prefix và vòng đời file của nó
+type bucket struct {
+	name      string
+	storage   interfaces.Storage
+	prefix    string
+	lifecycle LifecycleConfig
+}
+
+// key thêm prefix của bucket (đã thay {tenant}, {category}) vào path
+func (b *bucket) key(ctx context.Context, category, path string) string {
+	if b.prefix == "" {
+		return path
+	}
+
+	tenant := TenantFromContext(ctx)
+	if tenant == "" {
+		tenant = sharedTenant
+	}
+	if category == "" {
+		category = "file"
+	}
+	prefix := strings.NewReplacer("{tenant}", tenant, "{category}", category).Replace(b.prefix)
+	return strings.TrimSuffix(prefix, "/") + "/" + strings.TrimPrefix(path, "/")
+}
+
+// staticPrefix phần prefix cố định (trước placeholder đầu tiên), dùng làm filter cho lifecycle rule
+func (b *bucket) staticPrefix() string {
+	prefix, _, _ := strings.Cut(b.prefix, "{")
+	return prefix
+}
+
+// NewLocalCipher cipher mã hóa at-rest của local storage theo EncryptionKeysFile/EncryptionKeys, nil nếu không bật
+func NewLocalCipher(cfg LocalConfig) (*local.Cipher, error) {
+	keys := cfg.EncryptionKeys
+	if cfg.EncryptionKeysFile != "" {
+		data, err := os.ReadFile(cfg.EncryptionKeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read local storage encryption keys: %w", err)
+		}
+		keys = strings.TrimSpace(string(data))
+	}
+	cipher, err := local.ParseCipher(keys)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local storage encryption keys: %w", err)
+	}
+	return cipher, nil
+}
+
+// newDriverStorage tạo storage theo driver
+func newDriverStorage(driver string, localCfg LocalConfig, s3Cfg S3Config) (interfaces.Storage, error) {
+	switch driver {
+	case "local":
+		cipher, err := NewLocalCipher(localCfg)
+		if err != nil {
+			return nil, err
+		}
+		if cipher != nil {
+			return local.NewEncryptedLocalStorage(localCfg.BasePath, localCfg.BaseURL, cipher)
+		}
+		return local.NewLocalStorage(localCfg.BasePath, localCfg.BaseURL)
+	case "s3":
+		return aws.NewS3Storage(aws.S3Config{
+			Bucket:          s3Cfg.Bucket,
+			Region:          s3Cfg.Region,
+			AccessKeyID:     s3Cfg.AccessKeyID,
+			SecretAccessKey: s3Cfg.SecretAccessKey,
+			BaseURL:         s3Cfg.BaseURL,
+			Endpoint:        s3Cfg.Endpoint,
+			ForcePathStyle:  s3Cfg.ForcePathStyle,
+			DisableSSL:      s3Cfg.DisableSSL,
+		})
+	default:
+		return nil, fmt.Errorf("unsupported storage driver: %s", driver)
+	}
+}
+
+// resolveBucket chọn bucket cho thao tác: bucket chỉ định trong context > luật route khớp nhất > bucket mặc định.
+// category rỗng (xóa, lấy URL theo path) chỉ khớp luật không có điều kiện category.
+func (sm *StorageManager) resolveBucket(ctx context.Context, category string) (*bucket, error) {
+	if name := BucketFromContext(ctx); name != "" {
+		b, ok := sm.buckets[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown storage bucket: %s", name)
+		}
+		return b, nil
+	}
+
+	tenant := TenantFromContext(ctx)
+	name, best := DefaultBucket, -1
+	for _, route := range sm.routes {
+		if score, ok := route.matches(tenant, category); ok && score > best {
+			name, best = route.Bucket, score
+		}
+	}
+	return sm.buckets[name], nil
+}
+
+// ApplyLifecycles đồng bộ lifecycle rule (tự xóa file sau ExpireDays) lên các bucket có cấu hình.
+// Chỉ thêm/cập nhật rule do app quản lý, rule khác của bucket được giữ nguyên.
+func (sm *StorageManager) ApplyLifecycles(ctx context.Context) error {
+	for _, b := range sm.buckets {
+		if b.lifecycle.ExpireDays <= 0 {
+			continue
+		}
+		manager, ok := b.storage.(interfaces.LifecycleManager)
+		if !ok {
+			return fmt.Errorf("storage bucket %s: driver does not support lifecycle rules", b.name)
+		}
+		rule := interfaces.LifecycleRule{
+			ID:         "api-core-" + b.name,
+			Prefix:     b.staticPrefix(),
+			ExpireDays: b.lifecycle.ExpireDays,
+		}
+		if err := manager.ApplyLifecycle(ctx, rule); err != nil {
+			return fmt.Errorf("storage bucket %s: %w", b.name, err)
+		}
+	}
+	return nil
+}
+
+// HasLifecycles có bucket nào cấu hình ExpireDays không
+func (sm *StorageManager) HasLifecycles() bool {
+	for _, b := range sm.buckets {
+		if b.lifecycle.ExpireDays > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// cutLast tách s tại lần xuất hiện cuối của sep
+func cutLast(s, sep string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
@@ -0,0 +1,232 @@
+package storage
+
+import "fmt"
+
+// Config cấu hình cho storage (internal/config đọc từ biến môi trường, service dùng lại tự điền)
+type Config struct {
+	Driver     string           `json:"driver"` // local, s3
+	Local      LocalConfig      `json:"local"`
+	S3         S3Config         `json:"s3"`
+	Image      ImageConfig      `json:"image"`
+	Validation ValidationConfig `json:"validation"`
+	HTTP       HTTPCacheConfig  `json:"http"`
+	// Prefix thêm vào trước path của bucket mặc định, hỗ trợ placeholder {tenant}, {category}
+	Prefix    string          `json:"prefix"`
+	Lifecycle LifecycleConfig `json:"lifecycle"`
+	// Buckets bucket bổ sung ngoài bucket mặc định, upload được route tới theo Routes
+	Buckets []BucketConfig `json:"buckets"`
+	// Routes luật chọn bucket theo tenant/category, dạng "tenant:acme=acme", "tenant:acme+category:video=acme-media"
+	Routes []string `json:"routes"`
+	// Resumable upload (tus) cho file lớn trên mạng chập chờn
+	Resumable ResumableConfig `json:"resumable"`
+	// Presigned upload: client upload thẳng lên S3, không qua API server
+	Presigned PresignedConfig `json:"presigned"`
+	// Scan quét malware file upload trước khi lưu
+	Scan ScanConfig `json:"scan"`
+	// QuotaBytes dung lượng mặc định mỗi user (bytes), 0: không giới hạn (vẫn tính dung lượng đã dùng)
+	QuotaBytes int64 `json:"quota_bytes"`
+	// Orphans dọn file không còn được model nào tham chiếu (cron cleanup-orphan-files)
+	Orphans OrphanConfig `json:"orphans"`
+}
+
+// ResumableConfig cấu hình resumable upload (tus protocol) tại /api/v1/uploads
+type ResumableConfig struct {
+	Enabled         bool   `json:"enabled"`
+	MaxSize         int64  `json:"max_size"`         // Kích thước tối đa một upload (bytes), giới hạn theo category vẫn áp dụng
+	ExpirationHours int    `json:"expiration_hours"` // Upload không được ghi thêm sau N giờ bị xóa
+	ChunkPrefix     string `json:"chunk_prefix"`     // Prefix lưu chunk tạm trong bucket mặc định
+}
+
+// PresignedConfig cấu hình presigned upload tại /api/v1/uploads/presigned (chỉ bucket dùng driver s3)
+type PresignedConfig struct {
+	Enabled           bool `json:"enabled"`
+	ExpirationMinutes int  `json:"expiration_minutes"` // Thời gian hiệu lực của URL/form đã ký
+}
+
+// ScanConfig cấu hình quét malware khi upload (ClamAV hoặc ICAP)
+type ScanConfig struct {
+	Driver           string `json:"driver"`            // "" (tắt), clamav, icap
+	Address          string `json:"address"`           // clamav: tcp://clamav:3310 hoặc unix:///path; icap: icap://host:1344/service
+	Policy           string `json:"policy"`            // reject: từ chối file; quarantine: lưu vào QuarantinePrefix rồi từ chối
+	QuarantinePrefix string `json:"quarantine_prefix"` // Prefix lưu file nhiễm trong bucket của file (dotfile, không phục vụ qua /storages)
+	FailOpen         bool   `json:"fail_open"`         // Scanner lỗi/không kết nối được: true cho upload qua, false từ chối
+	TimeoutSeconds   int    `json:"timeout_seconds"`   // Thời gian tối đa quét một file
+}
+
+// OrphanConfig cấu hình job dọn file mồ côi (avatar, file đính kèm tin nhắn không còn được tham chiếu)
+type OrphanConfig struct {
+	Enabled       bool     `json:"enabled"`
+	Prefixes      []string `json:"prefixes"`       // Chỉ quét các prefix này, rỗng: cả bucket
+	GraceHours    int      `json:"grace_hours"`    // Bỏ qua file mới hơn N giờ (upload chưa kịp gắn vào model)
+	DryRun        bool     `json:"dry_run"`        // Chỉ log file mồ côi, không xóa
+	ArchivePrefix string   `json:"archive_prefix"` // Chuyển file mồ côi vào prefix này thay vì xóa, rỗng: xóa
+}
+
+// BucketConfig cấu hình một bucket có tên (driver và credentials riêng, thiếu thì dùng của bucket mặc định)
+type BucketConfig struct {
+	Name      string          `json:"name"`
+	Driver    string          `json:"driver"`
+	Local     LocalConfig     `json:"local"`
+	S3        S3Config        `json:"s3"`
+	Prefix    string          `json:"prefix"`
+	Lifecycle LifecycleConfig `json:"lifecycle"`
+}
+
+// LifecycleConfig vòng đời file trong bucket
+type LifecycleConfig struct {
+	ExpireDays   int    `json:"expire_days"`   // Tự xóa file sau N ngày (S3 lifecycle rule theo prefix), 0: giữ vĩnh viễn
+	StorageClass string `json:"storage_class"` // S3 storage class khi upload, vd. STANDARD_IA, GLACIER_IR
+	CacheControl string `json:"cache_control"` // Cache-Control gắn vào object khi upload
+}
+
+// LocalConfig cấu hình cho local storage
+type LocalConfig struct {
+	BasePath string `json:"base_path"`
+	BaseURL  string `json:"base_url"`
+	// EncryptionKeys master key mã hóa file at-rest, dạng "id:base64(32 byte),id2:..." (key đầu tiên mã hóa file mới,
+	// các key sau chỉ để giải mã file cũ khi xoay vòng key), rỗng: không mã hóa
+	EncryptionKeys string `json:"-"`
+	// EncryptionKeysFile đọc EncryptionKeys từ file (Docker/Kubernetes secret, file do Vault agent render)
+	// để key không nằm trong env của process, ưu tiên hơn EncryptionKeys
+	EncryptionKeysFile string `json:"encryption_keys_file"`
+}
+
+// S3Config cấu hình cho S3 storage
+type S3Config struct {
+	Bucket          string `json:"bucket"`
+	Region          string `json:"region"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	BaseURL         string `json:"base_url"`
+	Endpoint        string `json:"endpoint"`         // Provider tương thích S3 (MinIO, DigitalOcean Spaces), rỗng: AWS S3
+	ForcePathStyle  bool   `json:"force_path_style"` // URL dạng endpoint/bucket/key (MinIO)
+	DisableSSL      bool   `json:"disable_ssl"`      // Gọi endpoint bằng http
+}
+
+// ImageConfig cấu hình cho image processing
+type ImageConfig struct {
+	Quality     int      `json:"quality"`
+	WebPQuality int      `json:"webp_quality"` // 0: dùng Quality
+	AVIFQuality int      `json:"avif_quality"` // 0: dùng Quality
+	Variants    []string `json:"variants"`     // Lưu thêm bản webp/avif của ảnh upload, phục vụ theo header Accept (thứ tự = ưu tiên)
+}
+
+// ValidationConfig cấu hình cho file validation
+type ValidationConfig struct {
+	MaxFileSize int64 `json:"max_file_size"`
+}
+
+// HTTPCacheConfig cấu hình phục vụ file qua /storages
+type HTTPCacheConfig struct {
+	MaxAge          int    `json:"max_age"`           // Cache-Control max-age (giây) cho file thường
+	ImmutableMaxAge int    `json:"immutable_max_age"` // max-age (giây) cho file có fingerprint trong tên, không bao giờ bị ghi đè
+	CDNURL          string `json:"cdn_url"`           // Redirect sang CDN thay vì phục vụ trực tiếp
+	SignedURLs      bool   `json:"signed_urls"`       // Redirect sang signed URL của driver (S3 bucket private)
+	SignedURLTTL    int    `json:"signed_url_ttl"`    // Thời hạn signed URL (giây)
+	Authorize       bool   `json:"authorize"`         // Kiểm tra quyền tải file (avatar public, file khác cần đăng nhập), false: mọi file public
+}
+
+// Validate validate storage config
+func (config Config) Validate() error {
+	if err := validateStorageDriver(config.Driver, config.Local, config.S3, config.Lifecycle); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, bucket := range config.Buckets {
+		if bucket.Name == "" || bucket.Name == "default" || names[bucket.Name] {
+			return fmt.Errorf("storage bucket name %q is empty, reserved or duplicated", bucket.Name)
+		}
+		names[bucket.Name] = true
+		if err := validateStorageDriver(bucket.Driver, bucket.Local, bucket.S3, bucket.Lifecycle); err != nil {
+			return fmt.Errorf("storage bucket %s: %w", bucket.Name, err)
+		}
+	}
+
+	if config.Image.Quality < 1 || config.Image.Quality > 100 {
+		return fmt.Errorf("image quality must be between 1 and 100")
+	}
+	// 0: dùng STORAGE_IMAGE_QUALITY
+	if config.Image.WebPQuality < 0 || config.Image.WebPQuality > 100 || config.Image.AVIFQuality < 0 || config.Image.AVIFQuality > 100 {
+		return fmt.Errorf("webp/avif quality must be between 1 and 100")
+	}
+	for _, format := range config.Image.Variants {
+		if format != "webp" && format != "avif" {
+			return fmt.Errorf("unsupported image variant %q (webp, avif)", format)
+		}
+	}
+
+	if config.Validation.MaxFileSize <= 0 {
+		return fmt.Errorf("max file size must be greater than 0")
+	}
+
+	if config.HTTP.MaxAge < 0 || config.HTTP.ImmutableMaxAge < 0 {
+		return fmt.Errorf("storage cache max age must not be negative")
+	}
+
+	if (config.HTTP.SignedURLs || config.Driver == "s3") && config.HTTP.SignedURLTTL <= 0 {
+		return fmt.Errorf("signed URL TTL must be greater than 0")
+	}
+
+	if config.QuotaBytes < 0 {
+		return fmt.Errorf("storage quota must not be negative")
+	}
+
+	if config.Orphans.GraceHours < 0 {
+		return fmt.Errorf("storage orphan grace hours must not be negative")
+	}
+
+	switch config.Scan.Driver {
+	case "":
+	case "clamav", "icap":
+		if config.Scan.Address == "" {
+			return fmt.Errorf("storage scan address is required for driver %s", config.Scan.Driver)
+		}
+		if config.Scan.Policy != "reject" && config.Scan.Policy != "quarantine" {
+			return fmt.Errorf("unsupported storage scan policy %q (reject, quarantine)", config.Scan.Policy)
+		}
+		if config.Scan.Policy == "quarantine" && config.Scan.QuarantinePrefix == "" {
+			return fmt.Errorf("storage scan quarantine prefix is required")
+		}
+		if config.Scan.TimeoutSeconds <= 0 {
+			return fmt.Errorf("storage scan timeout must be greater than 0")
+		}
+	default:
+		return fmt.Errorf("unsupported storage scan driver: %s (clamav, icap)", config.Scan.Driver)
+	}
+
+	return nil
+}
+
+// validateStorageDriver validate cấu hình driver của một bucket
+func validateStorageDriver(driver string, local LocalConfig, s3 S3Config, lifecycle LifecycleConfig) error {
+	switch driver {
+	case "local":
+		if local.BasePath == "" {
+			return fmt.Errorf("local storage base path is required")
+		}
+		if lifecycle.ExpireDays > 0 || lifecycle.StorageClass != "" {
+			return fmt.Errorf("expire days and storage class are only supported by the s3 driver")
+		}
+	case "s3":
+		if s3.Bucket == "" {
+			return fmt.Errorf("S3 bucket is required")
+		}
+		if s3.Region == "" {
+			return fmt.Errorf("S3 region is required")
+		}
+		if s3.AccessKeyID == "" {
+			return fmt.Errorf("S3 access key ID is required")
+		}
+		if s3.SecretAccessKey == "" {
+			return fmt.Errorf("S3 secret access key is required")
+		}
+	default:
+		return fmt.Errorf("unsupported storage driver: %s", driver)
+	}
+
+	if lifecycle.ExpireDays < 0 {
+		return fmt.Errorf("storage expire days must not be negative")
+	}
+	return nil
+}
@@ -3,12 +3,9 @@ package storage
 import (
 	"fmt"
 
-	"api-core/config"
-	"api-core/pkg/storage/aws"
-	"api-core/pkg/storage/image"
-	"api-core/pkg/storage/interfaces"
-	"api-core/pkg/storage/local"
-	"api-core/pkg/storage/validator"
+	"github.com/anhnq996/go-api-core/pkg/storage/image"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/validator"
 )
 
 // StorageFactory factory để tạo storage instances
@@ -20,30 +17,17 @@ func NewStorageFactory() *StorageFactory {
 }
 
 // CreateStorage tạo storage instance dựa trên config
-func (f *StorageFactory) CreateStorage(cfg config.StorageConfig) (interfaces.Storage, error) {
-	switch cfg.Driver {
-	case "local":
-		return local.NewLocalStorage(cfg.Local.BasePath, cfg.Local.BaseURL)
-	case "s3":
-		return aws.NewS3Storage(aws.S3Config{
-			Bucket:          cfg.S3.Bucket,
-			Region:          cfg.S3.Region,
-			AccessKeyID:     cfg.S3.AccessKeyID,
-			SecretAccessKey: cfg.S3.SecretAccessKey,
-			BaseURL:         cfg.S3.BaseURL,
-		})
-	default:
-		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
-	}
+func (f *StorageFactory) CreateStorage(cfg Config) (interfaces.Storage, error) {
+	return newDriverStorage(cfg.Driver, cfg.Local, cfg.S3)
 }
 
 // CreateImageProcessor tạo image processor
-func (f *StorageFactory) CreateImageProcessor(cfg config.StorageConfig) interfaces.ImageProcessor {
+func (f *StorageFactory) CreateImageProcessor(cfg Config) interfaces.ImageProcessor {
 	return image.NewImageProcessor(cfg.Image.Quality)
 }
 
 // CreateFileValidator tạo file validator
-func (f *StorageFactory) CreateFileValidator(cfg config.StorageConfig) interfaces.FileValidator {
+func (f *StorageFactory) CreateFileValidator(cfg Config) interfaces.FileValidator {
 	validator := validator.NewFileValidator()
 
 	// Set max file size
@@ -53,7 +37,7 @@ func (f *StorageFactory) CreateFileValidator(cfg config.StorageConfig) interface
 }
 
 // CreateStorageComponents tạo tất cả storage components
-func (f *StorageFactory) CreateStorageComponents(cfg config.StorageConfig) (
+func (f *StorageFactory) CreateStorageComponents(cfg Config) (
 	interfaces.Storage,
 	interfaces.ImageProcessor,
 	interfaces.FileValidator,
@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/image"
+	"github.com/anhnq996/go-api-core/pkg/storage/local"
+)
+
+// fingerprintPattern tên file do generateFilename tạo: <name>_<uuid><ext>.
+// Mỗi lần upload sinh path mới nên nội dung tại path đó không bao giờ thay đổi.
+var fingerprintPattern = regexp.MustCompile(`_[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}(\.[^/.]+)?$`)
+
+// IsFingerprinted kiểm tra path có fingerprint (có thể cache vĩnh viễn với immutable)
+func IsFingerprinted(key string) bool {
+	return fingerprintPattern.MatchString(key)
+}
+
+// SignURLFunc tạo signed URL có thời hạn cho file
+type SignURLFunc func(ctx context.Context, key string, ttl time.Duration) (string, error)
+
+var (
+	// ErrDownloadUnauthorized file cần đăng nhập mới tải được (401)
+	ErrDownloadUnauthorized = errors.New("authentication required to download file")
+	// ErrDownloadForbidden user không có quyền tải file (403)
+	ErrDownloadForbidden = errors.New("file access denied")
+)
+
+// FileAccess phạm vi truy cập của file sau khi kiểm tra quyền
+type FileAccess int
+
+const (
+	// FileAccessPublic ai cũng tải được, cache public và redirect qua CDN như bình thường
+	FileAccessPublic FileAccess = iota
+	// FileAccessPrivate chỉ user được phép: cache private, không redirect qua CDN
+	FileAccessPrivate
+)
+
+// AuthorizeFunc kiểm tra quyền tải file, trả ErrDownloadUnauthorized/ErrDownloadForbidden khi từ chối,
+// lỗi bọc fs.ErrNotExist trả 404
+type AuthorizeFunc func(r *http.Request, key string) (FileAccess, error)
+
+// FileServerConfig cấu hình phục vụ file storage qua HTTP
+type FileServerConfig struct {
+	Root            string        // Thư mục gốc của local storage
+	Prefix          string        // URL prefix, vd. /storages/
+	MaxAge          time.Duration // max-age cho file thường (0: no-cache, luôn revalidate bằng ETag)
+	ImmutableMaxAge time.Duration // max-age cho file có fingerprint
+	CDNURL          string        // Redirect sang CDN, vd. https://cdn.example.com/storages
+	SignURL         SignURLFunc   // Redirect sang signed URL (S3 private), ưu tiên sau CDNURL
+	SignedURLTTL    time.Duration // Thời hạn signed URL
+	ImageVariants   []string      // Định dạng thay thế theo thứ tự ưu tiên (avif, webp), phục vụ khi client khai báo trong Accept
+	Authorize       AuthorizeFunc // Kiểm tra quyền trước khi phục vụ file, nil: mọi file public
+	Cipher          *local.Cipher // Giải mã file local được mã hóa at-rest (STORAGE_LOCAL_ENCRYPTION_KEYS)
+}
+
+// FileServer phục vụ file storage với Cache-Control, ETag, Last-Modified và Range.
+// Không liệt kê thư mục và không phục vụ dotfiles. File trên S3 được redirect sang signed URL (SignURL) thay vì proxy.
+type FileServer struct {
+	config FileServerConfig
+	root   http.Dir
+}
+
+// NewFileServer tạo file server
+func NewFileServer(cfg FileServerConfig) *FileServer {
+	return &FileServer{config: cfg, root: http.Dir(cfg.Root)}
+}
+
+// ServeHTTP implement http.Handler
+func (s *FileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	key, ok := s.key(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	access := FileAccessPublic
+	if s.config.Authorize != nil {
+		var err error
+		if access, err = s.config.Authorize(r, key); err != nil {
+			s.denied(w, r, err)
+			return
+		}
+		// Response phụ thuộc user: cache không được dùng chung giữa các user
+		w.Header().Add("Vary", "Authorization")
+	}
+
+	// Cho phép app ở origin khác (web, mobile webview) prefetch và dùng lại từ cache
+	w.Header().Set("Cross-Origin-Resource-Policy", "cross-origin")
+
+	switch {
+	case s.config.CDNURL != "" && access == FileAccessPublic:
+		w.Header().Set("Cache-Control", s.cacheControl(key, access))
+		http.Redirect(w, r, strings.TrimSuffix(s.config.CDNURL, "/")+"/"+key, http.StatusFound)
+	case s.config.SignURL != nil:
+		s.serveSigned(w, r, key)
+	default:
+		s.serveLocal(w, r, key, access)
+	}
+}
+
+// denied trả lỗi khi Authorize từ chối
+func (s *FileServer) denied(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrDownloadUnauthorized):
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	case errors.Is(err, ErrDownloadForbidden):
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	case errors.Is(err, fs.ErrNotExist):
+		http.NotFound(w, r)
+	default:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
+
+// key path tương đối trong storage, false nếu path không hợp lệ
+func (s *FileServer) key(urlPath string) (string, bool) {
+	key := strings.TrimPrefix(path.Clean("/"+strings.TrimPrefix(urlPath, s.config.Prefix)), "/")
+	if key == "" || key == "." {
+		return "", false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return "", false
+		}
+	}
+	return key, true
+}
+
+// cacheControl file có fingerprint được cache vĩnh viễn, file khác cache theo MaxAge rồi revalidate.
+// File private chỉ được cache ở client, không cache ở proxy/CDN.
+func (s *FileServer) cacheControl(key string, access FileAccess) string {
+	scope := "public"
+	if access == FileAccessPrivate {
+		scope = "private"
+	}
+	if IsFingerprinted(key) && s.config.ImmutableMaxAge > 0 {
+		return fmt.Sprintf("%s, max-age=%d, immutable", scope, int(s.config.ImmutableMaxAge.Seconds()))
+	}
+	if s.config.MaxAge > 0 {
+		return fmt.Sprintf("%s, max-age=%d", scope, int(s.config.MaxAge.Seconds()))
+	}
+	if access == FileAccessPrivate {
+		return "private, no-cache"
+	}
+	return "no-cache"
+}
+
+// serveSigned redirect sang signed URL, client chỉ cache redirect trong nửa thời hạn để không dùng URL đã hết hạn
+func (s *FileServer) serveSigned(w http.ResponseWriter, r *http.Request, key string) {
+	signedURL, err := s.config.SignURL(r.Context(), key, s.config.SignedURLTTL)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusBadGateway), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", int(s.config.SignedURLTTL.Seconds()/2)))
+	http.Redirect(w, r, signedURL, http.StatusFound)
+}
+
+// serveLocal phục vụ file từ local storage, http.ServeContent xử lý If-None-Match, If-Modified-Since và Range
+func (s *FileServer) serveLocal(w http.ResponseWriter, r *http.Request, key string, access FileAccess) {
+	if variant := s.negotiateVariant(w, r, key); variant != "" {
+		key = variant
+	}
+
+	file, err := s.root.Open("/" + key)
+	if err != nil {
+		if os.IsNotExist(err) || os.IsPermission(err) {
+			http.NotFound(w, r)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	// File mã hóa: giải mã từng chunk theo Range, file lưu trước khi bật mã hóa phục vụ nguyên bản
+	var content io.ReadSeeker = file
+	if readerAt, ok := file.(io.ReaderAt); ok && s.config.Cipher != nil {
+		reader, err := s.config.Cipher.Open(readerAt, info.Size())
+		switch {
+		case err == nil:
+			content = reader
+		case !errors.Is(err, local.ErrNotEncrypted):
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	w.Header().Set("Cache-Control", s.cacheControl(key, access))
+	w.Header().Set("ETag", fileETag(info))
+	http.ServeContent(w, r, info.Name(), info.ModTime(), content)
+}
+
+// negotiateVariant bản WebP/AVIF đã lưu của ảnh JPEG/PNG mà client nhận được, "" nếu phục vụ ảnh gốc
+func (s *FileServer) negotiateVariant(w http.ResponseWriter, r *http.Request, key string) string {
+	if len(s.config.ImageVariants) == 0 || !hasVariants(key) {
+		return ""
+	}
+	// Response phụ thuộc Accept: cache của browser/proxy phải tách theo Accept
+	w.Header().Add("Vary", "Accept")
+
+	var available []string
+	for _, format := range s.config.ImageVariants {
+		if info, err := os.Stat(filepath.Join(s.config.Root, filepath.FromSlash(VariantPath(key, format)))); err == nil && !info.IsDir() {
+			available = append(available, format)
+		}
+	}
+	if format := image.NegotiateFormat(r.Header.Get("Accept"), available...); format != "" {
+		return VariantPath(key, format)
+	}
+	return ""
+}
+
+// fileETag ETag từ thời điểm sửa và kích thước, không cần đọc nội dung file
+func fileETag(info os.FileInfo) string {
+	return `"` + strconv.FormatInt(info.ModTime().UnixNano(), 36) + "-" + strconv.FormatInt(info.Size(), 36) + `"`
+}
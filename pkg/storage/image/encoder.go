@@ -0,0 +1,188 @@
+package image
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	_ "golang.org/x/image/webp" // Decode ảnh WebP đầu vào
+)
+
+// ErrUnsupportedFormat không có encoder cho định dạng (hoặc thiếu binary của encoder ngoài)
+var ErrUnsupportedFormat = errors.New("unsupported image format")
+
+// Encoder encode ảnh sang một định dạng, quality 1-100 (định dạng lossless bỏ qua quality)
+type Encoder interface {
+	Encode(w io.Writer, img image.Image, quality int) error
+}
+
+// EncoderFunc adapter cho hàm encode
+type EncoderFunc func(w io.Writer, img image.Image, quality int) error
+
+// Encode implement Encoder
+func (f EncoderFunc) Encode(w io.Writer, img image.Image, quality int) error {
+	return f(w, img, quality)
+}
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]Encoder{
+		"jpeg": EncoderFunc(func(w io.Writer, img image.Image, quality int) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		}),
+		"png": EncoderFunc(func(w io.Writer, img image.Image, _ int) error {
+			return png.Encode(w, img)
+		}),
+		"gif": EncoderFunc(func(w io.Writer, img image.Image, _ int) error {
+			// Convert to palette for GIF
+			paletted := image.NewPaletted(img.Bounds(), nil)
+			draw.Draw(paletted, paletted.Bounds(), img, img.Bounds().Min, draw.Src)
+			return gif.Encode(w, paletted, nil)
+		}),
+		// Go chưa có encoder WebP/AVIF thuần Go: dùng encoder tham chiếu (apk add libwebp-tools libavif-apps)
+		"webp": &CommandEncoder{Command: "cwebp", Extension: ".webp", Args: []string{"-quiet", "-metadata", "none", "-q", "{quality}", "{in}", "-o", "{out}"}},
+		"avif": &CommandEncoder{Command: "avifenc", Extension: ".avif", Args: []string{"-q", "{quality}", "-s", "6", "{in}", "{out}"}},
+	}
+)
+
+// RegisterEncoder đăng ký hoặc thay encoder cho định dạng, vd. dùng thư viện cgo thay cho cwebp
+func RegisterEncoder(format string, encoder Encoder) {
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+	encoders[NormalizeFormat(format)] = encoder
+}
+
+// LookupEncoder encoder đang đăng ký cho định dạng
+func LookupEncoder(format string) (Encoder, bool) {
+	encodersMu.RLock()
+	defer encodersMu.RUnlock()
+	encoder, ok := encoders[NormalizeFormat(format)]
+	return encoder, ok
+}
+
+// Supported định dạng có encoder dùng được không (encoder ngoài cần binary trong PATH)
+func Supported(format string) bool {
+	encoder, ok := LookupEncoder(format)
+	if !ok {
+		return false
+	}
+	if a, ok := encoder.(interface{ Available() bool }); ok {
+		return a.Available()
+	}
+	return true
+}
+
+// Encode encode ảnh sang định dạng với quality
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	encoder, ok := LookupEncoder(format)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnsupportedFormat, format)
+	}
+	return encoder.Encode(w, img, quality)
+}
+
+// NormalizeFormat tên định dạng chuẩn: jpg -> jpeg, chữ thường
+func NormalizeFormat(format string) string {
+	format = strings.ToLower(strings.TrimPrefix(format, "."))
+	if format == "jpg" {
+		return "jpeg"
+	}
+	return format
+}
+
+// Extension phần mở rộng file của định dạng (.jpg, .webp...)
+func Extension(format string) string {
+	format = NormalizeFormat(format)
+	if format == "jpeg" {
+		return ".jpg"
+	}
+	return "." + format
+}
+
+// ContentType MIME type của định dạng
+func ContentType(format string) string {
+	return "image/" + NormalizeFormat(format)
+}
+
+// CommandEncoder encode bằng lệnh ngoài: ảnh được ghi ra file PNG tạm rồi chạy lệnh.
+// Args hỗ trợ placeholder {in}, {out}, {quality}.
+type CommandEncoder struct {
+	Command   string
+	Args      []string
+	Extension string        // Phần mở rộng file output, vd. .avif (một số encoder chọn định dạng theo tên file)
+	Timeout   time.Duration // Mặc định: 30 giây
+}
+
+// Available binary có trong PATH không
+func (e *CommandEncoder) Available() bool {
+	_, err := exec.LookPath(e.Command)
+	return err == nil
+}
+
+// Encode implement Encoder
+func (e *CommandEncoder) Encode(w io.Writer, img image.Image, quality int) error {
+	if !e.Available() {
+		return fmt.Errorf("%w: %s is not installed", ErrUnsupportedFormat, e.Command)
+	}
+
+	dir, err := os.MkdirTemp("", "image-encode-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "in.png"), filepath.Join(dir, "out"+e.Extension)
+	file, err := os.Create(in)
+	if err != nil {
+		return err
+	}
+	err = png.Encode(file, img)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	replacer := strings.NewReplacer("{in}", in, "{out}", out, "{quality}", strconv.Itoa(quality))
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = replacer.Replace(arg)
+	}
+
+	timeout := e.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.Command, args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w: %s", e.Command, err, strings.TrimSpace(stderr.String()))
+	}
+
+	result, err := os.Open(out)
+	if err != nil {
+		return fmt.Errorf("%s: no output: %w", e.Command, err)
+	}
+	defer result.Close()
+	_, err = io.Copy(w, result)
+	return err
+}
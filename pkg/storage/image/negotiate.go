@@ -0,0 +1,40 @@
+package image
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NegotiateFormat chọn định dạng trong formats mà client khai báo trong Accept, q cao nhất, bằng nhau thì theo thứ tự formats
+// (vd. "avif", "webp"). Chỉ tính media type khai báo rõ: client gửi image/* hay */* chưa chắc decode được AVIF/WebP.
+// Trả "" nếu không có định dạng phù hợp, khi đó phục vụ ảnh gốc.
+func NegotiateFormat(accept string, formats ...string) string {
+	best, bestQ := "", 0.0
+	for _, format := range formats {
+		if q := acceptQuality(accept, ContentType(format)); q > bestQ {
+			best, bestQ = NormalizeFormat(format), q
+		}
+	}
+	return best
+}
+
+// acceptQuality giá trị q của media type trong Accept, 0 nếu không có
+func acceptQuality(accept, mediaType string) float64 {
+	for _, part := range strings.Split(accept, ",") {
+		params := strings.Split(part, ";")
+		if !strings.EqualFold(strings.TrimSpace(params[0]), mediaType) {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if ok && strings.TrimSpace(key) == "q" {
+				if v, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+					q = v
+				}
+			}
+		}
+		return q
+	}
+	return 0
+}
@@ -5,21 +5,18 @@ import (
 	"fmt"
 	"image"
 	"image/color"
-	"image/draw"
-	"image/gif"
-	"image/jpeg"
-	"image/png"
 	"io"
 	"strings"
 
-	"api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
 
 	"github.com/disintegration/imaging"
 )
 
 // ImageProcessor implementation cho xử lý ảnh
 type ImageProcessor struct {
-	quality int // JPEG quality (1-100)
+	quality   int            // JPEG quality (1-100)
+	qualities map[string]int // Quality riêng theo định dạng (webp, avif)
 }
 
 // NewImageProcessor tạo instance mới của ImageProcessor
@@ -29,10 +26,26 @@ func NewImageProcessor(quality int) *ImageProcessor {
 	}
 
 	return &ImageProcessor{
-		quality: quality,
+		quality:   quality,
+		qualities: map[string]int{},
 	}
 }
 
+// SetQuality đặt quality riêng cho định dạng, vd. WebP/AVIF cho ảnh tương đương JPEG ở quality thấp hơn
+func (p *ImageProcessor) SetQuality(format string, quality int) {
+	if quality > 0 && quality <= 100 {
+		p.qualities[NormalizeFormat(format)] = quality
+	}
+}
+
+// Quality quality dùng khi encode định dạng
+func (p *ImageProcessor) Quality(format string) int {
+	if quality, ok := p.qualities[NormalizeFormat(format)]; ok {
+		return quality
+	}
+	return p.quality
+}
+
 // Resize ảnh
 func (p *ImageProcessor) Resize(ctx context.Context, reader io.Reader, width, height int) (io.Reader, error) {
 	// Decode image
@@ -139,7 +152,7 @@ func (p *ImageProcessor) Convert(ctx context.Context, reader io.Reader, format s
 	}
 
 	// Encode to new format
-	return p.encodeImage(img, format) 
+	return p.encodeImage(img, format)
 }
 
 // GetInfo lấy thông tin ảnh
@@ -186,43 +199,17 @@ func (p *ImageProcessor) GetInfo(ctx context.Context, reader io.Reader) (*interf
 
 // encodeImage encode image to reader
 func (p *ImageProcessor) encodeImage(img image.Image, format string) (io.Reader, error) {
+	format = NormalizeFormat(format)
+	if _, ok := LookupEncoder(format); !ok {
+		format = "jpeg" // Default to JPEG
+	}
+	quality := p.Quality(format)
+
 	// Create pipe for streaming
 	reader, writer := io.Pipe()
 
 	go func() {
-		defer writer.Close()
-
-		switch strings.ToLower(format) {
-		case "jpeg", "jpg":
-			err := jpeg.Encode(writer, img, &jpeg.Options{Quality: p.quality})
-			if err != nil {
-				writer.CloseWithError(err)
-				return
-			}
-		case "png":
-			err := png.Encode(writer, img)
-			if err != nil {
-				writer.CloseWithError(err)
-				return
-			}
-		case "gif":
-			// Convert to palette for GIF
-			paletted := image.NewPaletted(img.Bounds(), nil)
-			draw.Draw(paletted, paletted.Bounds(), img, img.Bounds().Min, draw.Src)
-
-			err := gif.Encode(writer, paletted, nil)
-			if err != nil {
-				writer.CloseWithError(err)
-				return
-			}
-		default:
-			// Default to JPEG
-			err := jpeg.Encode(writer, img, &jpeg.Options{Quality: p.quality})
-			if err != nil {
-				writer.CloseWithError(err)
-				return
-			}
-		}
+		writer.CloseWithError(Encode(writer, img, format, quality))
 	}()
 
 	return reader, nil
@@ -25,6 +25,50 @@ type UploadOptions struct {
 	Metadata     map[string]string `json:"metadata"`      // Metadata tùy chỉnh
 	ACL          string            `json:"acl"`           // Access Control List (cho S3)
 	CacheControl string            `json:"cache_control"` // Cache control header
+	StorageClass string            `json:"storage_class"` // Storage class (cho S3), vd. STANDARD_IA
+}
+
+// LifecycleRule rule tự xóa file theo prefix
+type LifecycleRule struct {
+	ID         string `json:"id"`          // ID rule, dùng để cập nhật đúng rule do app quản lý
+	Prefix     string `json:"prefix"`      // Chỉ áp dụng cho file có prefix này (rỗng: cả bucket)
+	ExpireDays int    `json:"expire_days"` // Xóa file sau N ngày
+}
+
+// LifecycleManager storage hỗ trợ lifecycle rule ở phía backend (S3)
+type LifecycleManager interface {
+	// Thêm hoặc cập nhật rule theo ID, giữ nguyên các rule khác
+	ApplyLifecycle(ctx context.Context, rule LifecycleRule) error
+}
+
+// PresignMethod cách client upload thẳng lên storage
+const (
+	PresignMethodPut  = "PUT"  // PUT body là nội dung file, gửi kèm Headers
+	PresignMethodPost = "POST" // Form multipart gồm Fields rồi tới field "file" (upload từ browser)
+)
+
+// PresignOptions tùy chọn khi tạo presigned upload
+type PresignOptions struct {
+	Method       string            `json:"method"`        // PUT (mặc định) hoặc POST
+	ContentType  string            `json:"content_type"`  // Client phải upload đúng MIME type này
+	Size         int64             `json:"size"`          // PUT: kích thước chính xác; POST: kích thước tối đa
+	ExpiresIn    int64             `json:"expires_in"`    // Thời gian hiệu lực (giây)
+	CacheControl string            `json:"cache_control"` // Cache control header
+	StorageClass string            `json:"storage_class"` // Storage class (cho S3)
+	Metadata     map[string]string `json:"metadata"`      // Metadata tùy chỉnh
+}
+
+// PresignedRequest request client gửi thẳng lên storage
+type PresignedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"` // PUT: header bắt buộc gửi kèm
+	Fields  map[string]string `json:"fields,omitempty"`  // POST: form fields gửi trước field file
+}
+
+// PresignedUploader storage cho phép client upload trực tiếp không qua API server (S3)
+type PresignedUploader interface {
+	PresignUpload(ctx context.Context, key string, options *PresignOptions) (*PresignedRequest, error)
 }
 
 // ListOptions tùy chọn khi list files
@@ -129,3 +173,15 @@ type FileValidator interface {
 	// Check if file is image
 	IsImage(contentType string) bool
 }
+
+// ScanResult kết quả quét malware
+type ScanResult struct {
+	Infected  bool   `json:"infected"`  // File chứa malware
+	Signature string `json:"signature"` // Tên malware scanner phát hiện (vd. Eicar-Test-Signature)
+}
+
+// Scanner interface quét malware nội dung file trước khi lưu (ClamAV, ICAP...)
+type Scanner interface {
+	// Scan đọc hết reader; trả error khi không quét được (scanner không kết nối được, timeout...)
+	Scan(ctx context.Context, filename string, reader io.Reader) (*ScanResult, error)
+}
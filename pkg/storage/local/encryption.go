@@ -0,0 +1,313 @@
+package local
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Định dạng file mã hóa (envelope encryption, AES-256-GCM):
+//
+//	magic | len(keyID) | keyID | nonce(12) | data key đã wrap bằng master key (32+16) | chunk...
+//
+// Mỗi file có data key ngẫu nhiên riêng. Nội dung chia chunk chunkSize byte, mỗi chunk seal riêng với
+// nonce = số thứ tự chunk + cờ chunk cuối, nên đọc được từng đoạn (Range) và phát hiện file bị cắt/đảo chunk.
+const (
+	chunkSize    = 64 * 1024
+	dataKeySize  = 32
+	gcmNonceSize = 12
+	gcmTagSize   = 16
+)
+
+// encryptionMagic đánh dấu file đã mã hóa, file không có magic (lưu trước khi bật mã hóa) được đọc nguyên bản
+var encryptionMagic = []byte("\x00LSE1")
+
+var (
+	// ErrNotEncrypted file không có header mã hóa
+	ErrNotEncrypted = errors.New("file is not encrypted")
+	// ErrUnknownEncryptionKey file được mã hóa bằng master key không còn trong cấu hình
+	ErrUnknownEncryptionKey = errors.New("unknown encryption key")
+)
+
+// Cipher mã hóa file at-rest cho LocalStorage. Key đầu tiên dùng để mã hóa file mới,
+// các key còn lại chỉ để giải mã file cũ (xoay vòng key).
+type Cipher struct {
+	current string
+	keys    map[string]cipher.AEAD
+}
+
+// NewCipher tạo cipher từ master key (32 byte) theo ID, current là ID key dùng để mã hóa
+func NewCipher(keys map[string][]byte, current string) (*Cipher, error) {
+	c := &Cipher{current: current, keys: make(map[string]cipher.AEAD, len(keys))}
+	for id, key := range keys {
+		if id == "" || len(id) > 255 {
+			return nil, fmt.Errorf("invalid encryption key id %q", id)
+		}
+		if len(key) != dataKeySize {
+			return nil, fmt.Errorf("encryption key %s must be %d bytes, got %d", id, dataKeySize, len(key))
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[id] = aead
+	}
+	if _, ok := c.keys[current]; !ok {
+		return nil, fmt.Errorf("current encryption key %q not found", current)
+	}
+	return c, nil
+}
+
+// ParseCipher parse danh sách key dạng "id:base64,id2:base64" (key đầu tiên là key hiện tại,
+// key không có id được gán id "default"). Chuỗi rỗng trả về nil: không mã hóa.
+func ParseCipher(spec string) (*Cipher, error) {
+	keys := map[string][]byte{}
+	current := ""
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		if !ok {
+			id, encoded = "default", entry
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s is not valid base64: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("duplicated encryption key id %q", id)
+		}
+		keys[id] = key
+		if current == "" {
+			current = id
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return NewCipher(keys, current)
+}
+
+// Encrypt mã hóa src vào dst, trả về kích thước nội dung gốc
+func (c *Cipher) Encrypt(dst io.Writer, src io.Reader) (int64, error) {
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return 0, err
+	}
+	header, err := c.header(dataKey)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := dst.Write(header); err != nil {
+		return 0, err
+	}
+
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return 0, err
+	}
+
+	var size int64
+	buf, next := make([]byte, chunkSize), make([]byte, chunkSize)
+	sealed := make([]byte, 0, chunkSize+gcmTagSize)
+	n, err := io.ReadFull(src, buf)
+	for index := uint64(0); ; index++ {
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return size, err
+		}
+
+		// Đọc trước chunk kế tiếp để biết chunk hiện tại có phải chunk cuối không
+		final := n < chunkSize
+		var m int
+		var nextErr error
+		if !final {
+			m, nextErr = io.ReadFull(src, next)
+			final = m == 0 && nextErr == io.EOF
+		}
+
+		sealed = aead.Seal(sealed[:0], chunkNonce(index, final), buf[:n], nil)
+		if _, err := dst.Write(sealed); err != nil {
+			return size, err
+		}
+		size += int64(n)
+		if final {
+			return size, nil
+		}
+		buf, next = next, buf
+		n, err = m, nextErr
+	}
+}
+
+// Open mở file đã mã hóa để đọc (hỗ trợ Seek, dùng được với http.ServeContent).
+// Trả ErrNotEncrypted nếu file không có header mã hóa.
+func (c *Cipher) Open(file io.ReaderAt, fileSize int64) (*DecryptReader, error) {
+	// magic | len(keyID) | keyID (tối đa 255) | wrapped data key
+	head := make([]byte, len(encryptionMagic)+1+255+gcmNonceSize+dataKeySize+gcmTagSize)
+	n, err := file.ReadAt(head, 0)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	head = head[:n]
+	if !bytes.HasPrefix(head, encryptionMagic) || len(head) <= len(encryptionMagic) {
+		return nil, ErrNotEncrypted
+	}
+
+	idEnd := len(encryptionMagic) + 1 + int(head[len(encryptionMagic)])
+	headerSize := idEnd + gcmNonceSize + dataKeySize + gcmTagSize
+	if len(head) < headerSize {
+		return nil, fmt.Errorf("encrypted file header is truncated")
+	}
+	keyID := string(head[len(encryptionMagic)+1 : idEnd])
+	master, ok := c.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEncryptionKey, keyID)
+	}
+
+	nonce := head[idEnd : idEnd+gcmNonceSize]
+	dataKey, err := master.Open(nil, nonce, head[idEnd+gcmNonceSize:headerSize], head[:idEnd])
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap data key: %w", err)
+	}
+	aead, err := newGCM(dataKey)
+	if err != nil {
+		return nil, err
+	}
+
+	body := fileSize - int64(headerSize)
+	chunks := (body + chunkSize + gcmTagSize - 1) / (chunkSize + gcmTagSize)
+	last := body - (chunks-1)*(chunkSize+gcmTagSize)
+	if chunks == 0 || last < gcmTagSize {
+		return nil, fmt.Errorf("encrypted file is truncated")
+	}
+
+	return &DecryptReader{
+		file:   file,
+		aead:   aead,
+		offset: int64(headerSize),
+		chunks: chunks,
+		size:   (chunks-1)*chunkSize + last - gcmTagSize,
+		cached: -1,
+	}, nil
+}
+
+// header magic, key ID và data key được wrap bằng master key hiện tại (header làm AAD)
+func (c *Cipher) header(dataKey []byte) ([]byte, error) {
+	header := append([]byte{}, encryptionMagic...)
+	header = append(header, byte(len(c.current)))
+	header = append(header, c.current...)
+
+	nonce := make([]byte, gcmNonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	wrapped := c.keys[c.current].Seal(nil, nonce, dataKey, header)
+	return append(append(header, nonce...), wrapped...), nil
+}
+
+// DecryptReader đọc nội dung gốc của file đã mã hóa, giải mã từng chunk khi cần
+type DecryptReader struct {
+	file   io.ReaderAt
+	aead   cipher.AEAD
+	offset int64 // Vị trí chunk đầu tiên trong file
+	chunks int64
+	size   int64 // Kích thước nội dung gốc
+	pos    int64
+
+	cached int64 // Chunk đang giữ trong plain, -1: chưa có
+	plain  []byte
+	buf    []byte
+}
+
+// Size kích thước nội dung gốc
+func (r *DecryptReader) Size() int64 {
+	return r.size
+}
+
+// Read implement io.Reader
+func (r *DecryptReader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	index := r.pos / chunkSize
+	if err := r.load(index); err != nil {
+		return 0, err
+	}
+	n := copy(p, r.plain[r.pos-index*chunkSize:])
+	r.pos += int64(n)
+	return n, nil
+}
+
+// Seek implement io.Seeker
+func (r *DecryptReader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.pos
+	case io.SeekEnd:
+		offset += r.size
+	default:
+		return 0, errors.New("invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("negative position")
+	}
+	r.pos = offset
+	return offset, nil
+}
+
+// load đọc và giải mã chunk index
+func (r *DecryptReader) load(index int64) error {
+	if r.cached == index {
+		return nil
+	}
+	if r.buf == nil {
+		r.buf = make([]byte, chunkSize+gcmTagSize)
+	}
+
+	length := int64(chunkSize + gcmTagSize)
+	if index == r.chunks-1 {
+		length = r.size - index*chunkSize + gcmTagSize
+	}
+	n, err := r.file.ReadAt(r.buf[:length], r.offset+index*(chunkSize+gcmTagSize))
+	if int64(n) < length {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+
+	plain, err := r.aead.Open(r.plain[:0], chunkNonce(uint64(index), index == r.chunks-1), r.buf[:length], nil)
+	if err != nil {
+		r.cached = -1
+		return fmt.Errorf("failed to decrypt chunk %d: %w", index, err)
+	}
+	r.plain, r.cached = plain, index
+	return nil
+}
+
+// chunkNonce 11 byte số thứ tự chunk + 1 byte cờ chunk cuối
+func chunkNonce(index uint64, final bool) []byte {
+	nonce := make([]byte, gcmNonceSize)
+	binary.BigEndian.PutUint64(nonce[3:11], index)
+	if final {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -3,6 +3,7 @@ package local
 import (
 	"context"
 	"crypto/md5"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -11,13 +12,14 @@ import (
 	"strings"
 	"time"
 
-	"api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
 )
 
 // LocalStorage implementation cho local file system
 type LocalStorage struct {
-	basePath string // Đường dẫn gốc để lưu files
-	baseURL  string // Base URL để truy cập files
+	basePath string  // Đường dẫn gốc để lưu files
+	baseURL  string  // Base URL để truy cập files
+	cipher   *Cipher // Mã hóa file trên đĩa, nil: lưu nguyên bản
 }
 
 // NewLocalStorage tạo instance mới của LocalStorage
@@ -33,6 +35,17 @@ func NewLocalStorage(basePath, baseURL string) (*LocalStorage, error) {
 	}, nil
 }
 
+// NewEncryptedLocalStorage tạo LocalStorage mã hóa file at-rest bằng cipher (AES-256-GCM envelope).
+// File lưu trước khi bật mã hóa vẫn đọc được bình thường.
+func NewEncryptedLocalStorage(basePath, baseURL string, cipher *Cipher) (*LocalStorage, error) {
+	s, err := NewLocalStorage(basePath, baseURL)
+	if err != nil {
+		return nil, err
+	}
+	s.cipher = cipher
+	return s, nil
+}
+
 // Upload file từ io.Reader
 func (s *LocalStorage) Upload(ctx context.Context, key string, reader io.Reader, options *interfaces.UploadOptions) (*interfaces.FileInfo, error) {
 	// Tạo đường dẫn đầy đủ
@@ -51,8 +64,13 @@ func (s *LocalStorage) Upload(ctx context.Context, key string, reader io.Reader,
 	}
 	defer file.Close()
 
-	// Copy data từ reader vào file
-	size, err := io.Copy(file, reader)
+	// Copy data từ reader vào file (mã hóa nếu bật)
+	var size int64
+	if s.cipher != nil {
+		size, err = s.cipher.Encrypt(file, reader)
+	} else {
+		size, err = io.Copy(file, reader)
+	}
 	if err != nil {
 		os.Remove(fullPath) // Cleanup nếu có lỗi
 		return nil, fmt.Errorf("failed to write file: %w", err)
@@ -105,7 +123,54 @@ func (s *LocalStorage) Download(ctx context.Context, key string) (io.ReadCloser,
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 
-	return file, nil
+	if s.cipher == nil {
+		return file, nil
+	}
+	reader, err := s.decrypt(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return reader, nil
+}
+
+// decryptedFile nội dung đã giải mã của file, Close đóng file gốc
+type decryptedFile struct {
+	*DecryptReader
+	io.Closer
+}
+
+// decrypt mở file để đọc nội dung gốc, file chưa mã hóa được trả nguyên bản
+func (s *LocalStorage) decrypt(file *os.File) (io.ReadCloser, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+	reader, err := s.cipher.Open(file, info.Size())
+	if errors.Is(err, ErrNotEncrypted) {
+		return file, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decryptedFile{DecryptReader: reader, Closer: file}, nil
+}
+
+// contentSize kích thước nội dung gốc của file (file mã hóa lớn hơn do header và tag của từng chunk)
+func (s *LocalStorage) contentSize(fullPath string, info os.FileInfo) int64 {
+	if s.cipher == nil {
+		return info.Size()
+	}
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return info.Size()
+	}
+	defer file.Close()
+	reader, err := s.cipher.Open(file, info.Size())
+	if err != nil {
+		return info.Size()
+	}
+	return reader.Size()
 }
 
 // DownloadBytes download file về bytes
@@ -138,7 +203,7 @@ func (s *LocalStorage) GetInfo(ctx context.Context, key string) (*interfaces.Fil
 
 	return &interfaces.FileInfo{
 		Name:         filepath.Base(key),
-		Size:         info.Size(),
+		Size:         s.contentSize(fullPath, info),
 		ContentType:  contentType,
 		Path:         key,
 		URL:          s.generateURL(key),
@@ -193,6 +258,9 @@ func (s *LocalStorage) DeleteMultiple(ctx context.Context, keys []string) error
 	return nil
 }
 
+// errListFull dừng Walk khi đã đủ MaxKeys file
+var errListFull = errors.New("list is full")
+
 // List list files
 func (s *LocalStorage) List(ctx context.Context, options *interfaces.ListOptions) (*interfaces.ListResult, error) {
 	searchPath := s.basePath
@@ -205,6 +273,11 @@ func (s *LocalStorage) List(ctx context.Context, options *interfaces.ListOptions
 	var nextMarker string
 	isTruncated := false
 
+	// Prefix chưa có file nào
+	if _, err := os.Stat(searchPath); os.IsNotExist(err) {
+		return &interfaces.ListResult{}, nil
+	}
+
 	err := filepath.Walk(searchPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
@@ -220,17 +293,23 @@ func (s *LocalStorage) List(ctx context.Context, options *interfaces.ListOptions
 		if err != nil {
 			return err
 		}
+		relPath = filepath.ToSlash(relPath)
 
 		// Apply prefix filter
 		if options.Prefix != "" && !strings.HasPrefix(relPath, options.Prefix) {
 			return nil
 		}
 
+		// Marker là file cuối của trang trước (file đó có thể đã bị xóa)
+		if !info.IsDir() && options.Marker != "" && !walksAfter(relPath, options.Marker) {
+			return nil
+		}
+
 		// Check max keys limit
-		if options.MaxKeys > 0 && len(files) >= options.MaxKeys {
+		if !info.IsDir() && options.MaxKeys > 0 && len(files) >= options.MaxKeys {
 			isTruncated = true
-			nextMarker = relPath
-			return filepath.SkipDir
+			nextMarker = files[len(files)-1].Path
+			return errListFull
 		}
 
 		if info.IsDir() {
@@ -252,7 +331,7 @@ func (s *LocalStorage) List(ctx context.Context, options *interfaces.ListOptions
 
 			files = append(files, interfaces.FileInfo{
 				Name:         info.Name(),
-				Size:         info.Size(),
+				Size:         s.contentSize(path, info),
 				ContentType:  contentType,
 				Path:         relPath,
 				URL:          s.generateURL(relPath),
@@ -265,7 +344,7 @@ func (s *LocalStorage) List(ctx context.Context, options *interfaces.ListOptions
 		return nil
 	})
 
-	if err != nil {
+	if err != nil && !errors.Is(err, errListFull) {
 		return nil, fmt.Errorf("failed to list files: %w", err)
 	}
 
@@ -277,6 +356,18 @@ func (s *LocalStorage) List(ctx context.Context, options *interfaces.ListOptions
 	}, nil
 }
 
+// walksAfter filepath.Walk duyệt key a sau key b: so từng segment vì Walk sắp xếp tên trong từng thư mục
+// (a/b được duyệt trước a.txt dù "a/b" > "a.txt" khi so cả chuỗi)
+func walksAfter(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] > bs[i]
+		}
+	}
+	return len(as) > len(bs)
+}
+
 // GetURL lấy public URL
 func (s *LocalStorage) GetURL(ctx context.Context, key string) (string, error) {
 	return s.generateURL(key), nil
@@ -0,0 +1,181 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+)
+
+// defaultOrphanBatchSize số file mỗi lần List và kiểm tra tham chiếu
+const defaultOrphanBatchSize = 500
+
+// ReferenceChecker trả về các giá trị (key hoặc URL) đang được model tham chiếu trong values
+type ReferenceChecker func(ctx context.Context, values []string) (map[string]bool, error)
+
+// OrphanOptions tùy chọn dọn file mồ côi
+type OrphanOptions struct {
+	Prefixes      []string         // Chỉ quét các prefix này, rỗng: cả bucket
+	GracePeriod   time.Duration    // Bỏ qua file mới hơn khoảng này (upload chưa kịp gắn vào model)
+	DryRun        bool             // Chỉ báo cáo, không xóa/chuyển file
+	ArchivePrefix string           // Chuyển file mồ côi vào prefix này thay vì xóa, rỗng: xóa
+	IsReferenced  ReferenceChecker // Bắt buộc
+	BatchSize     int              // 0: 500
+}
+
+// OrphanReport kết quả một lần dọn
+type OrphanReport struct {
+	Scanned int      `json:"scanned"` // Số file đã kiểm tra (không tính file trong grace period)
+	Orphans []string `json:"orphans"` // Key file mồ côi, dạng bucket:key với bucket khác mặc định
+	Bytes   int64    `json:"bytes"`   // Tổng dung lượng file mồ côi
+	Removed int      `json:"removed"` // Số file đã xóa/chuyển vào archive
+	Failed  int      `json:"failed"`  // Số file xóa/chuyển lỗi
+}
+
+// CleanupOrphans quét mọi bucket, xóa (hoặc chuyển vào ArchivePrefix) file không được model nào tham chiếu.
+// File trong prefix bắt đầu bằng "." (chunk resumable, quarantine, archive) không bị quét.
+func (sm *StorageManager) CleanupOrphans(ctx context.Context, opts OrphanOptions) (*OrphanReport, error) {
+	if opts.IsReferenced == nil {
+		return nil, errors.New("orphan cleanup requires a reference checker")
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultOrphanBatchSize
+	}
+	prefixes := opts.Prefixes
+	if len(prefixes) == 0 {
+		prefixes = []string{""}
+	}
+
+	report := &OrphanReport{}
+	cutoff := time.Now().Add(-opts.GracePeriod).Unix()
+
+	names := make([]string, 0, len(sm.buckets))
+	for name := range sm.buckets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		b := sm.buckets[name]
+		for _, prefix := range prefixes {
+			if err := sm.cleanupOrphanPrefix(ctx, b, strings.TrimSpace(prefix), cutoff, opts, report); err != nil {
+				return report, fmt.Errorf("bucket %s: %w", name, err)
+			}
+		}
+	}
+	return report, nil
+}
+
+// cleanupOrphanPrefix dọn một prefix của bucket theo từng trang List
+func (sm *StorageManager) cleanupOrphanPrefix(ctx context.Context, b *bucket, prefix string, cutoff int64, opts OrphanOptions, report *OrphanReport) error {
+	marker := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		page, err := b.storage.List(ctx, &interfaces.ListOptions{Prefix: prefix, MaxKeys: opts.BatchSize, Marker: marker})
+		if err != nil {
+			return err
+		}
+
+		var files []interfaces.FileInfo
+		for _, file := range page.Files {
+			if hiddenKey(file.Path) || file.LastModified > cutoff ||
+				(opts.ArchivePrefix != "" && strings.HasPrefix(file.Path, strings.TrimSuffix(opts.ArchivePrefix, "/")+"/")) {
+				continue
+			}
+			files = append(files, file)
+		}
+		if err := sm.cleanupOrphanFiles(ctx, b, files, opts, report); err != nil {
+			return err
+		}
+
+		if !page.IsTruncated || page.NextMarker == "" {
+			return nil
+		}
+		marker = page.NextMarker
+	}
+}
+
+// cleanupOrphanFiles kiểm tra tham chiếu của một trang file rồi xóa/chuyển file mồ côi
+func (sm *StorageManager) cleanupOrphanFiles(ctx context.Context, b *bucket, files []interfaces.FileInfo, opts OrphanOptions, report *OrphanReport) error {
+	if len(files) == 0 {
+		return nil
+	}
+	report.Scanned += len(files)
+
+	// Model có thể lưu key hoặc URL đầy đủ của file
+	candidates := make(map[string][]string, len(files))
+	var values []string
+	for _, file := range files {
+		for _, key := range referenceKeys(file.Path) {
+			candidates[file.Path] = append(candidates[file.Path], key)
+			if url, err := b.storage.GetURL(ctx, key); err == nil && url != key {
+				candidates[file.Path] = append(candidates[file.Path], url)
+			}
+		}
+		values = append(values, candidates[file.Path]...)
+	}
+
+	referenced, err := opts.IsReferenced(ctx, values)
+	if err != nil {
+		return fmt.Errorf("failed to check file references: %w", err)
+	}
+
+	for _, file := range files {
+		if slices.ContainsFunc(candidates[file.Path], func(value string) bool { return referenced[value] }) {
+			continue
+		}
+
+		name := file.Path
+		if b.name != DefaultBucket {
+			name = b.name + ":" + file.Path
+		}
+		report.Orphans = append(report.Orphans, name)
+		report.Bytes += file.Size
+		if opts.DryRun {
+			continue
+		}
+
+		if opts.ArchivePrefix != "" {
+			err = b.storage.Move(ctx, file.Path, path.Join(opts.ArchivePrefix, file.Path))
+		} else {
+			err = b.storage.Delete(ctx, file.Path)
+		}
+		if err != nil {
+			logger.Warnf("Failed to remove orphan file %s: %v", name, err)
+			report.Failed++
+			continue
+		}
+		report.Removed++
+	}
+	return nil
+}
+
+// referenceKeys các key mà model có thể lưu để tham chiếu tới file: chính nó,
+// với bản WebP/AVIF sinh khi upload thì thêm ảnh gốc (model chỉ lưu ảnh gốc)
+func referenceKeys(key string) []string {
+	keys := []string{key}
+	ext := strings.ToLower(path.Ext(key))
+	if (ext == ".webp" || ext == ".avif") && IsFingerprinted(key) {
+		base := strings.TrimSuffix(key, path.Ext(key))
+		keys = append(keys, base+".jpg", base+".jpeg", base+".png")
+	}
+	return keys
+}
+
+// hiddenKey key nằm trong thư mục/tên bắt đầu bằng "." (chunk tạm, quarantine, archive)
+func hiddenKey(key string) bool {
+	for _, segment := range strings.Split(key, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+)
+
+// FileOwnerStore lưu owner của từng file phía server (bảng storage_files), dùng kiểm tra quyền tải
+// file không gắn vào model. Không dựa vào metadata của driver vì local storage không lưu metadata.
+type FileOwnerStore interface {
+	// SetOwner ghi owner của file vừa lưu
+	SetOwner(ctx context.Context, key, owner string, size int64) error
+	// Owner owner của file, rỗng nếu file không có owner
+	Owner(ctx context.Context, key string) (string, error)
+	// DeleteOwner xóa owner khi file bị xóa
+	DeleteOwner(ctx context.Context, key string) error
+}
+
+// SetFileOwnerStore bật lưu owner theo file khi upload có owner (WithOwner)
+func (sm *StorageManager) SetFileOwnerStore(store FileOwnerStore) {
+	sm.owners = store
+}
+
+// FileOwner owner của file đã lưu qua StorageManager, rỗng nếu không có hoặc chưa gắn FileOwnerStore
+func (sm *StorageManager) FileOwner(ctx context.Context, key string) (string, error) {
+	if sm.owners == nil {
+		return "", nil
+	}
+	return sm.owners.Owner(ctx, key)
+}
+
+// recordOwner ghi owner trong context cho file vừa lưu, lỗi chỉ ghi log (file đã lưu xong,
+// download bị từ chối cho tới khi có owner)
+func (sm *StorageManager) recordOwner(ctx context.Context, key string, size int64) {
+	owner := OwnerFromContext(ctx)
+	if sm.owners == nil || owner == "" {
+		return
+	}
+	if err := sm.owners.SetOwner(context.WithoutCancel(ctx), key, owner, size); err != nil {
+		logger.Errorf("Failed to record owner %s of file %s: %v", owner, key, err)
+	}
+}
+
+// forgetOwner xóa owner của file đã xóa
+func (sm *StorageManager) forgetOwner(ctx context.Context, key string) {
+	if sm.owners == nil {
+		return
+	}
+	if err := sm.owners.DeleteOwner(ctx, key); err != nil {
+		logger.Errorf("Failed to delete owner of file %s: %v", key, err)
+	}
+}
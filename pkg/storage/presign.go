@@ -0,0 +1,184 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+)
+
+var (
+	// ErrFileRejected loại file hoặc kích thước không được phép theo category
+	ErrFileRejected = errors.New("file rejected")
+	// ErrPresignNotSupported storage của bucket không cho upload trực tiếp (local)
+	ErrPresignNotSupported = errors.New("storage does not support presigned uploads")
+	// ErrUploadNotFound client chưa upload object lên storage
+	ErrUploadNotFound = errors.New("uploaded object not found")
+	// ErrUploadMismatch object đã upload không khớp kích thước/loại file đã khai báo (object đã bị xóa)
+	ErrUploadMismatch = errors.New("uploaded object does not match the declared file")
+)
+
+// PresignedUploadOptions thông tin file client khai báo trước khi upload trực tiếp
+type PresignedUploadOptions struct {
+	Filename    string            `json:"filename"`
+	ContentType string            `json:"content_type"`
+	Size        int64             `json:"size"`       // Kích thước chính xác (bytes)
+	Category    string            `json:"category"`   // image, document, video, audio, archive
+	Path        string            `json:"path"`       // Custom path (prefix của bucket được thêm phía trước)
+	Method      string            `json:"method"`     // PUT (mặc định) hoặc POST (form upload từ browser)
+	ExpiresIn   time.Duration     `json:"expires_in"` // Mặc định: 15 phút
+	Metadata    map[string]string `json:"metadata"`   // Custom metadata
+}
+
+// PresignedUpload upload trực tiếp đã ký, lưu lại để xác nhận sau khi client upload xong
+type PresignedUpload struct {
+	Bucket      string                       `json:"bucket"`
+	Path        string                       `json:"path"`
+	Filename    string                       `json:"filename"`
+	ContentType string                       `json:"content_type"`
+	Size        int64                        `json:"size"`
+	Category    string                       `json:"category"`
+	ExpiresAt   time.Time                    `json:"expires_at"`
+	Request     *interfaces.PresignedRequest `json:"request"`
+}
+
+// CreatePresignedUpload kiểm tra loại file/kích thước theo category rồi ký request để client upload thẳng lên storage (S3),
+// không đi qua API server. Sau khi upload client gọi xác nhận để server kiểm tra object (ConfirmPresignedUpload).
+func (sm *StorageManager) CreatePresignedUpload(ctx context.Context, options *PresignedUploadOptions) (*PresignedUpload, error) {
+	if err := sm.validator.ValidateFile(options.Filename, options.ContentType, options.Size, nil, options.Category); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrFileRejected, err)
+	}
+	if err := sm.CheckQuota(ctx, options.Size); err != nil {
+		return nil, err
+	}
+
+	b, err := sm.resolveBucket(ctx, options.Category)
+	if err != nil {
+		return nil, err
+	}
+	uploader, ok := b.storage.(interfaces.PresignedUploader)
+	if !ok {
+		return nil, ErrPresignNotSupported
+	}
+
+	method := options.Method
+	if method == "" {
+		method = interfaces.PresignMethodPut
+	}
+	if method != interfaces.PresignMethodPut && method != interfaces.PresignMethodPost {
+		return nil, fmt.Errorf("unsupported presign method: %s", method)
+	}
+	expires := options.ExpiresIn
+	if expires <= 0 {
+		expires = 15 * time.Minute
+	}
+
+	path := b.key(ctx, options.Category, sm.generatePath(options.Path, sm.generateFilename(options.Filename)))
+	request, err := uploader.PresignUpload(ctx, path, &interfaces.PresignOptions{
+		Method:       method,
+		ContentType:  options.ContentType,
+		Size:         options.Size,
+		ExpiresIn:    int64(expires / time.Second),
+		CacheControl: b.lifecycle.CacheControl,
+		StorageClass: b.lifecycle.StorageClass,
+		Metadata:     options.Metadata,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PresignedUpload{
+		Bucket:      b.name,
+		Path:        path,
+		Filename:    options.Filename,
+		ContentType: options.ContentType,
+		Size:        options.Size,
+		Category:    options.Category,
+		ExpiresAt:   time.Now().Add(expires),
+		Request:     request,
+	}, nil
+}
+
+// ConfirmPresignedUpload kiểm tra object client đã upload: tồn tại, đúng kích thước và magic bytes khớp loại file đã khai báo.
+// Object không hợp lệ bị xóa và trả ErrUploadMismatch; object nhiễm malware trả ErrFileInfected;
+// object vượt quota của owner trong context bị xóa và trả ErrQuotaExceeded.
+func (sm *StorageManager) ConfirmPresignedUpload(ctx context.Context, upload *PresignedUpload) (*UploadResult, error) {
+	s, err := sm.Storage(upload.Bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := s.Exists(ctx, upload.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUploadNotFound
+	}
+	info, err := s.GetInfo(ctx, upload.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	mismatch, err := sm.verifyUploadedObject(ctx, s, upload, info)
+	if err != nil {
+		return nil, err
+	}
+	if mismatch != nil {
+		if err := s.Delete(ctx, upload.Path); err != nil {
+			return nil, fmt.Errorf("failed to delete rejected upload: %w", err)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrUploadMismatch, mismatch)
+	}
+	if err := sm.scanStoredFile(ctx, s, upload.Path, upload.Filename); err != nil {
+		return nil, err
+	}
+	if _, err := sm.reserve(ctx, info.Size); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			// Client upload nhiều file song song vượt quota: object không được giữ lại
+			if err := s.Delete(ctx, upload.Path); err != nil {
+				return nil, fmt.Errorf("failed to delete rejected upload: %w", err)
+			}
+		}
+		return nil, err
+	}
+	sm.recordOwner(ctx, upload.Path, info.Size)
+
+	url, err := s.GetURL(ctx, upload.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &UploadResult{
+		Path:        upload.Path,
+		Bucket:      upload.Bucket,
+		URL:         url,
+		Size:        info.Size,
+		ContentType: upload.ContentType,
+		ETag:        info.ETag,
+	}, nil
+}
+
+// verifyUploadedObject so object trên storage với file đã khai báo.
+// Trả về mismatch khi object không hợp lệ, err khi không đọc được object (không xóa object).
+func (sm *StorageManager) verifyUploadedObject(ctx context.Context, s interfaces.Storage, upload *PresignedUpload, info *interfaces.FileInfo) (mismatch error, err error) {
+	if info.Size != upload.Size {
+		return fmt.Errorf("size %d bytes, declared %d bytes", info.Size, upload.Size), nil
+	}
+
+	reader, err := s.Download(ctx, upload.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// Chỉ cần 512 byte đầu để kiểm tra magic bytes
+	head, err := io.ReadAll(io.LimitReader(reader, 512))
+	if err != nil {
+		return nil, err
+	}
+	return sm.validator.ValidateFile(upload.Filename, upload.ContentType, info.Size, bytes.NewReader(head), upload.Category), nil
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+)
+
+var (
+	// ErrQuotaExceeded file vượt dung lượng còn lại của owner
+	ErrQuotaExceeded = errors.New("storage quota exceeded")
+	// ErrUsageNotTracked StorageManager chưa gắn UsageStore
+	ErrUsageNotTracked = errors.New("storage usage is not tracked")
+)
+
+type ownerContextKey struct{}
+
+// WithOwner gắn owner (user sở hữu file) vào context để StorageManager tính dung lượng và quota cho owner đó.
+// Upload/xóa không có owner không được tính.
+func WithOwner(ctx context.Context, owner string) context.Context {
+	return context.WithValue(ctx, ownerContextKey{}, owner)
+}
+
+// OwnerFromContext lấy owner từ context (rỗng nếu không có)
+func OwnerFromContext(ctx context.Context) string {
+	owner, _ := ctx.Value(ownerContextKey{}).(string)
+	return owner
+}
+
+// Usage dung lượng owner đã dùng
+type Usage struct {
+	Owner      string `json:"owner"`
+	UsedBytes  int64  `json:"used_bytes"`
+	FileCount  int64  `json:"file_count"`
+	QuotaBytes int64  `json:"quota_bytes"` // 0: không giới hạn
+}
+
+// RemainingBytes dung lượng còn lại, -1 nếu không giới hạn
+func (u *Usage) RemainingBytes() int64 {
+	if u.QuotaBytes <= 0 {
+		return -1
+	}
+	return max(u.QuotaBytes-u.UsedBytes, 0)
+}
+
+// UsageStore lưu dung lượng đã dùng theo owner (bảng storage_usage).
+// defaultQuota áp dụng cho owner không có quota riêng, <= 0: không giới hạn.
+type UsageStore interface {
+	// Reserve cộng size vào dung lượng của owner, trả ErrQuotaExceeded (không thay đổi gì) nếu vượt quota
+	Reserve(ctx context.Context, owner string, size, defaultQuota int64) error
+	// Release trừ size khi file bị xóa hoặc lưu thất bại
+	Release(ctx context.Context, owner string, size int64) error
+	// Usage dung lượng hiện tại của owner (owner chưa upload: 0 byte)
+	Usage(ctx context.Context, owner string, defaultQuota int64) (*Usage, error)
+}
+
+// SetUsageStore bật theo dõi dung lượng và quota theo owner
+func (sm *StorageManager) SetUsageStore(store UsageStore) {
+	sm.usage = store
+}
+
+// Usage dung lượng đã dùng và quota của owner
+func (sm *StorageManager) Usage(ctx context.Context, owner string) (*Usage, error) {
+	if sm.usage == nil {
+		return nil, ErrUsageNotTracked
+	}
+	return sm.usage.Usage(ctx, owner, sm.quota)
+}
+
+// CheckQuota kiểm tra owner trong context còn đủ dung lượng cho size bytes mà không giữ chỗ,
+// dùng trước khi nhận nội dung (presigned, resumable) để client không upload vô ích
+func (sm *StorageManager) CheckQuota(ctx context.Context, size int64) error {
+	owner := OwnerFromContext(ctx)
+	if sm.usage == nil || owner == "" {
+		return nil
+	}
+	usage, err := sm.usage.Usage(ctx, owner, sm.quota)
+	if err != nil {
+		return fmt.Errorf("failed to read storage usage: %w", err)
+	}
+	if usage.QuotaBytes > 0 && usage.UsedBytes+size > usage.QuotaBytes {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// reserve giữ dung lượng cho file sắp lưu của owner trong context.
+// Trả hàm hoàn lại dung lượng, gọi khi lưu file thất bại.
+func (sm *StorageManager) reserve(ctx context.Context, size int64) (func(), error) {
+	owner := OwnerFromContext(ctx)
+	if sm.usage == nil || owner == "" {
+		return func() {}, nil
+	}
+	if err := sm.usage.Reserve(ctx, owner, size, sm.quota); err != nil {
+		if errors.Is(err, ErrQuotaExceeded) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to reserve storage usage: %w", err)
+	}
+	return func() { sm.release(context.WithoutCancel(ctx), owner, size) }, nil
+}
+
+// release trừ dung lượng của owner, lỗi chỉ ghi log (file đã lưu/xóa xong)
+func (sm *StorageManager) release(ctx context.Context, owner string, size int64) {
+	if sm.usage == nil || owner == "" || size <= 0 {
+		return
+	}
+	if err := sm.usage.Release(ctx, owner, size); err != nil {
+		logger.Errorf("Failed to release %d bytes of storage usage for %s: %v", size, owner, err)
+	}
+}
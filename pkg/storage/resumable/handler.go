@@ -0,0 +1,296 @@
+package resumable
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+)
+
+// Tus protocol (https://tus.io/protocols/resumable-upload) version và extension được hỗ trợ
+const (
+	TusVersion    = "1.0.0"
+	TusExtensions = "creation,creation-with-upload,expiration,termination"
+
+	offsetContentType = "application/offset+octet-stream"
+)
+
+// HandlerConfig cấu hình HTTP handler
+type HandlerConfig struct {
+	BasePath string                       // URL prefix của endpoint, vd. /api/v1/uploads
+	Owner    func(r *http.Request) string // User của request (thường lấy từ JWT), rỗng: không giới hạn theo owner
+}
+
+// Handler HTTP handler theo tus 1.0.0:
+//
+//	POST   {base}      tạo upload (Upload-Length, Upload-Metadata), có thể kèm chunk đầu tiên
+//	HEAD   {base}/{id} offset hiện tại để tiếp tục
+//	PATCH  {base}/{id} ghi chunk tại Upload-Offset
+//	DELETE {base}/{id} hủy upload
+//	GET    {base}/{id} trạng thái upload dạng JSON (kèm file đã hoàn tất), không thuộc tus
+type Handler struct {
+	manager *Manager
+	config  HandlerConfig
+}
+
+// NewHandler tạo tus handler
+func NewHandler(manager *Manager, cfg HandlerConfig) *Handler {
+	cfg.BasePath = "/" + strings.Trim(cfg.BasePath, "/")
+	return &Handler{manager: manager, config: cfg}
+}
+
+// ServeHTTP implement http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Tus-Resumable", TusVersion)
+
+	// Client không gửi được PATCH/DELETE (proxy cũ) dùng POST kèm X-HTTP-Method-Override
+	method := r.Method
+	if override := r.Header.Get("X-HTTP-Method-Override"); override != "" && method == http.MethodPost {
+		method = strings.ToUpper(override)
+	}
+
+	id, ok := h.uploadID(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if method == http.MethodOptions {
+		h.options(w)
+		return
+	}
+	if method != http.MethodGet && r.Header.Get("Tus-Resumable") != TusVersion {
+		w.Header().Set("Tus-Version", TusVersion)
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+
+	switch {
+	case id == "" && method == http.MethodPost:
+		h.create(w, r)
+	case id != "" && method == http.MethodHead:
+		h.head(w, r, id)
+	case id != "" && method == http.MethodPatch:
+		h.patch(w, r, id)
+	case id != "" && method == http.MethodDelete:
+		h.terminate(w, r, id)
+	case id != "" && method == http.MethodGet:
+		h.show(w, r, id)
+	default:
+		w.Header().Set("Allow", "OPTIONS, POST, HEAD, PATCH, DELETE, GET")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// uploadID ID trong path (rỗng với base path), false nếu path không thuộc handler
+func (h *Handler) uploadID(urlPath string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSuffix(urlPath, "/"), h.config.BasePath)
+	if !ok {
+		return "", false
+	}
+	rest = strings.TrimPrefix(rest, "/")
+	if strings.Contains(rest, "/") {
+		return "", false
+	}
+	return rest, true
+}
+
+func (h *Handler) owner(r *http.Request) string {
+	if h.config.Owner == nil {
+		return ""
+	}
+	return h.config.Owner(r)
+}
+
+func (h *Handler) options(w http.ResponseWriter) {
+	w.Header().Set("Tus-Version", TusVersion)
+	w.Header().Set("Tus-Extension", TusExtensions)
+	if maxSize := h.manager.MaxSize(); maxSize > 0 {
+		w.Header().Set("Tus-Max-Size", strconv.FormatInt(maxSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+
+	if r.Header.Get("Upload-Defer-Length") != "" {
+		response.BadRequest(w, lang, response.CodeBadRequest, "Upload-Defer-Length is not supported")
+		return
+	}
+	length, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || length < 0 {
+		response.BadRequest(w, lang, response.CodeBadRequest, "invalid Upload-Length")
+		return
+	}
+	metadata, err := ParseMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		response.BadRequest(w, lang, response.CodeBadRequest, err.Error())
+		return
+	}
+
+	upload, err := h.manager.Create(r.Context(), h.owner(r), length, metadata)
+	if err != nil {
+		h.error(w, r, err)
+		return
+	}
+	w.Header().Set("Location", h.config.BasePath+"/"+upload.ID)
+
+	// creation-with-upload: chunk đầu tiên gửi kèm request tạo
+	if r.Header.Get("Content-Type") == offsetContentType && !upload.Completed() {
+		upload, err = h.manager.Write(r.Context(), upload.ID, upload.Owner, 0, r.Body)
+		if err != nil && upload == nil {
+			h.error(w, r, err)
+			return
+		}
+	}
+
+	h.setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (h *Handler) head(w http.ResponseWriter, r *http.Request, id string) {
+	upload, err := h.manager.Get(r.Context(), id, h.owner(r))
+	if err != nil {
+		w.WriteHeader(errorStatus(err))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Length", strconv.FormatInt(upload.Length, 10))
+	if len(upload.Metadata) > 0 {
+		w.Header().Set("Upload-Metadata", EncodeMetadata(upload.Metadata))
+	}
+	h.setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) patch(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("Content-Type") != offsetContentType {
+		w.WriteHeader(http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		response.BadRequest(w, i18n.GetLanguageFromContext(r.Context()), response.CodeBadRequest, "invalid Upload-Offset")
+		return
+	}
+
+	upload, err := h.manager.Write(r.Context(), id, h.owner(r), offset, r.Body)
+	if err != nil && upload == nil {
+		h.error(w, r, err)
+		return
+	}
+
+	h.setUploadHeaders(w, upload)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) terminate(w http.ResponseWriter, r *http.Request, id string) {
+	if err := h.manager.Terminate(r.Context(), id, h.owner(r)); err != nil {
+		h.error(w, r, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// uploadStatus trạng thái upload trả về cho GET
+type uploadStatus struct {
+	ID        string                `json:"id"`
+	Length    int64                 `json:"length"`
+	Offset    int64                 `json:"offset"`
+	Metadata  map[string]string     `json:"metadata,omitempty"`
+	Completed bool                  `json:"completed"`
+	ExpiresAt time.Time             `json:"expires_at"`
+	File      *storage.UploadResult `json:"file,omitempty"` // File đã lưu khi upload hoàn tất
+}
+
+func (h *Handler) show(w http.ResponseWriter, r *http.Request, id string) {
+	upload, err := h.manager.Get(r.Context(), id, h.owner(r))
+	if err != nil {
+		h.error(w, r, err)
+		return
+	}
+
+	response.Success(w, i18n.GetLanguageFromContext(r.Context()), response.CodeSuccess, uploadStatus{
+		ID:        upload.ID,
+		Length:    upload.Length,
+		Offset:    upload.Offset,
+		Metadata:  upload.Metadata,
+		Completed: upload.Completed(),
+		ExpiresAt: upload.ExpiresAt,
+		File:      upload.Result,
+	})
+}
+
+func (h *Handler) setUploadHeaders(w http.ResponseWriter, upload *Upload) {
+	w.Header().Set("Upload-Offset", strconv.FormatInt(upload.Offset, 10))
+	if !upload.ExpiresAt.IsZero() && !upload.Completed() {
+		w.Header().Set("Upload-Expires", upload.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// errorStatus HTTP status theo tus cho lỗi của Manager
+func errorStatus(err error) int {
+	var completeErr *CompleteError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrExpired):
+		return http.StatusGone
+	case errors.Is(err, ErrOffsetMismatch):
+		return http.StatusConflict
+	case errors.Is(err, ErrTooLarge), errors.Is(err, ErrExceedsLength), errors.Is(err, storage.ErrQuotaExceeded):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, ErrLocked):
+		return http.StatusLocked
+	case errors.Is(err, ErrInvalidLength):
+		return http.StatusBadRequest
+	case errors.Is(err, storage.ErrScanFailed):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, ErrRejected), errors.As(err, &completeErr):
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func (h *Handler) error(w http.ResponseWriter, r *http.Request, err error) {
+	lang := i18n.GetLanguageFromContext(r.Context())
+	status := errorStatus(err)
+
+	code := response.CodeFileUploadFailed
+	switch status {
+	case http.StatusNotFound, http.StatusGone:
+		code = response.CodeFileNotFound
+	case http.StatusConflict, http.StatusLocked:
+		code = response.CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		code = response.CodeFileTooLarge
+		if errors.Is(err, storage.ErrQuotaExceeded) {
+			code = response.CodeStorageQuotaExceeded
+		}
+	case http.StatusBadRequest:
+		code = response.CodeBadRequest
+	case http.StatusUnprocessableEntity:
+		if errors.Is(err, ErrRejected) {
+			code = response.CodeValidationFailed
+		}
+		if errors.Is(err, storage.ErrFileInfected) {
+			code = response.CodeFileInfected
+		}
+	case http.StatusServiceUnavailable:
+		response.Error(w, lang, response.CodeFileScanFailed, nil, status)
+		return
+	case http.StatusInternalServerError:
+		// Không trả chi tiết lỗi nội bộ
+		response.Error(w, lang, response.CodeInternalServerError, nil, status)
+		return
+	}
+	response.Error(w, lang, code, err.Error(), status)
+}
@@ -0,0 +1,308 @@
+package resumable
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+
+	"github.com/google/uuid"
+)
+
+// lockTTL thời gian giữ lock khi ghi chunk (gồm cả ghép file ở chunk cuối)
+const lockTTL = 15 * time.Minute
+
+// ValidateFunc kiểm tra upload ngay khi tạo (loại file, kích thước theo category) để client không phải gửi hết dữ liệu mới biết bị từ chối
+type ValidateFunc func(ctx context.Context, upload *Upload) error
+
+// CompleteFunc lưu file hoàn chỉnh sau khi nhận đủ dữ liệu, content là file tạm đã ghép từ các chunk
+type CompleteFunc func(ctx context.Context, upload *Upload, content io.ReadSeeker) (*storage.UploadResult, error)
+
+// Config cấu hình resumable upload
+type Config struct {
+	MaxSize     int64         // Kích thước tối đa của một upload (Tus-Max-Size), 0: không giới hạn
+	Expiration  time.Duration // Upload không được ghi thêm trong khoảng này bị coi là bỏ dở (default: 24h)
+	ChunkPrefix string        // Prefix lưu chunk trong storage (default: .resumable, không phục vụ qua /storages)
+	Validate    ValidateFunc
+	Complete    CompleteFunc
+}
+
+// CompleteError lỗi khi ghép/lưu file ở chunk cuối, upload đã bị hủy và client cần upload lại
+type CompleteError struct {
+	Err error
+}
+
+func (e *CompleteError) Error() string {
+	return "upload could not be completed: " + e.Err.Error()
+}
+
+func (e *CompleteError) Unwrap() error {
+	return e.Err
+}
+
+// Manager quản lý vòng đời upload: tạo, ghi chunk, ghép file, hủy và dọn upload bỏ dở.
+// Mỗi PATCH được lưu thành một chunk trong storage nên upload tiếp tục được trên instance khác.
+type Manager struct {
+	config Config
+	chunks interfaces.Storage
+	store  Store
+}
+
+// NewManager tạo manager, chunks là storage lưu dữ liệu tạm (thường là bucket mặc định)
+func NewManager(chunks interfaces.Storage, store Store, cfg Config) *Manager {
+	if cfg.Expiration <= 0 {
+		cfg.Expiration = 24 * time.Hour
+	}
+	if cfg.ChunkPrefix == "" {
+		cfg.ChunkPrefix = ".resumable"
+	}
+	cfg.ChunkPrefix = strings.Trim(cfg.ChunkPrefix, "/")
+
+	return &Manager{config: cfg, chunks: chunks, store: store}
+}
+
+// MaxSize kích thước tối đa của một upload (0: không giới hạn)
+func (m *Manager) MaxSize() int64 {
+	return m.config.MaxSize
+}
+
+// Create tạo upload mới cho owner
+func (m *Manager) Create(ctx context.Context, owner string, length int64, metadata map[string]string) (*Upload, error) {
+	if length < 0 {
+		return nil, ErrInvalidLength
+	}
+	if m.config.MaxSize > 0 && length > m.config.MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	now := clock.FromContext(ctx).Now()
+	upload := &Upload{
+		ID:        strings.ReplaceAll(uuid.NewString(), "-", ""),
+		Owner:     owner,
+		Length:    length,
+		Metadata:  metadata,
+		CreatedAt: now,
+		ExpiresAt: now.Add(m.config.Expiration),
+	}
+	if m.config.Validate != nil {
+		if err := m.config.Validate(ctx, upload); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrRejected, err)
+		}
+	}
+
+	// Upload rỗng hoàn tất ngay khi tạo
+	if length == 0 {
+		if err := m.complete(ctx, upload); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.store.Save(ctx, upload); err != nil {
+		return nil, fmt.Errorf("failed to save upload: %w", err)
+	}
+	return upload, nil
+}
+
+// Get lấy upload của owner (owner rỗng: không kiểm tra). Upload của user khác trả về ErrNotFound.
+func (m *Manager) Get(ctx context.Context, id, owner string) (*Upload, error) {
+	upload, err := m.store.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if owner != "" && upload.Owner != owner {
+		return nil, ErrNotFound
+	}
+	if upload.Expired(clock.FromContext(ctx).Now()) {
+		return nil, ErrExpired
+	}
+	return upload, nil
+}
+
+// Write ghi dữ liệu từ offset. Dữ liệu nhận được trước khi kết nối bị ngắt vẫn được lưu,
+// client HEAD để lấy offset mới rồi gửi tiếp. Nhận đủ Length thì ghép chunk thành file.
+func (m *Manager) Write(ctx context.Context, id, owner string, offset int64, body io.Reader) (*Upload, error) {
+	locked, err := m.store.Lock(ctx, id, lockTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !locked {
+		return nil, ErrLocked
+	}
+	defer m.store.Unlock(context.WithoutCancel(ctx), id)
+
+	upload, err := m.Get(ctx, id, owner)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Offset != offset {
+		return nil, ErrOffsetMismatch
+	}
+	if upload.Completed() {
+		return upload, nil
+	}
+
+	// Spool vào file tạm: body có thể lớn và storage (S3) cần reader seek được
+	tmp, err := os.CreateTemp("", "resumable-chunk-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	remaining := upload.Length - upload.Offset
+	size, readErr := io.Copy(tmp, io.LimitReader(body, remaining+1))
+	if size > remaining {
+		return nil, ErrExceedsLength
+	}
+	if size == 0 && readErr != nil {
+		return nil, readErr
+	}
+
+	// Client mất kết nối giữa chừng: vẫn lưu phần đã nhận
+	ctx = context.WithoutCancel(ctx)
+	if size > 0 {
+		if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		key := m.chunkKey(upload.ID, upload.Offset)
+		if _, err := m.chunks.Upload(ctx, key, tmp, &interfaces.UploadOptions{Path: key, ContentType: "application/octet-stream"}); err != nil {
+			return nil, fmt.Errorf("failed to store chunk: %w", err)
+		}
+		upload.Chunks = append(upload.Chunks, Chunk{Key: key, Offset: upload.Offset, Size: size})
+		upload.Offset += size
+	}
+	upload.ExpiresAt = clock.FromContext(ctx).Now().Add(m.config.Expiration)
+
+	if upload.Offset == upload.Length {
+		if err := m.complete(ctx, upload); err != nil {
+			m.remove(ctx, upload)
+			return nil, err
+		}
+	}
+
+	if err := m.store.Save(ctx, upload); err != nil {
+		return nil, fmt.Errorf("failed to save upload: %w", err)
+	}
+	return upload, readErr
+}
+
+// Terminate hủy upload và xóa dữ liệu đã nhận (file đã hoàn tất được giữ nguyên)
+func (m *Manager) Terminate(ctx context.Context, id, owner string) error {
+	upload, err := m.store.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if owner != "" && upload.Owner != owner {
+		return ErrNotFound
+	}
+	return m.remove(ctx, upload)
+}
+
+// Cleanup xóa các upload quá hạn (bỏ dở hoặc đã hoàn tất từ lâu), trả về số upload đã xóa
+func (m *Manager) Cleanup(ctx context.Context) (int, error) {
+	ids, err := m.store.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := clock.FromContext(ctx).Now()
+	removed := 0
+	for _, id := range ids {
+		upload, err := m.store.Get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			// Trạng thái đã hết TTL, chỉ còn trong index
+			_ = m.store.Delete(ctx, id)
+			continue
+		}
+		if err != nil {
+			return removed, err
+		}
+		if !upload.Expired(now) {
+			continue
+		}
+		if err := m.remove(ctx, upload); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// complete ghép chunk thành file tạm rồi giao cho CompleteFunc, sau đó xóa chunk
+func (m *Manager) complete(ctx context.Context, upload *Upload) error {
+	if m.config.Complete == nil {
+		return &CompleteError{Err: errors.New("no complete handler configured")}
+	}
+
+	content, err := os.CreateTemp("", "resumable-upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(content.Name())
+	defer content.Close()
+
+	for _, chunk := range upload.Chunks {
+		if err := m.appendChunk(ctx, content, chunk); err != nil {
+			return &CompleteError{Err: err}
+		}
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	result, err := m.config.Complete(ctx, upload, content)
+	if err != nil {
+		return &CompleteError{Err: err}
+	}
+
+	m.deleteChunks(ctx, upload)
+	upload.Result = result
+	upload.Chunks = nil
+	return nil
+}
+
+func (m *Manager) appendChunk(ctx context.Context, w io.Writer, chunk Chunk) error {
+	reader, err := m.chunks.Download(ctx, chunk.Key)
+	if err != nil {
+		return fmt.Errorf("failed to read chunk %s: %w", chunk.Key, err)
+	}
+	defer reader.Close()
+
+	n, err := io.Copy(w, reader)
+	if err != nil {
+		return fmt.Errorf("failed to read chunk %s: %w", chunk.Key, err)
+	}
+	if n != chunk.Size {
+		return fmt.Errorf("chunk %s has %d bytes, expected %d", chunk.Key, n, chunk.Size)
+	}
+	return nil
+}
+
+// remove xóa chunk và trạng thái của upload
+func (m *Manager) remove(ctx context.Context, upload *Upload) error {
+	m.deleteChunks(ctx, upload)
+	return m.store.Delete(ctx, upload.ID)
+}
+
+func (m *Manager) deleteChunks(ctx context.Context, upload *Upload) {
+	if len(upload.Chunks) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(upload.Chunks))
+	for _, chunk := range upload.Chunks {
+		keys = append(keys, chunk.Key)
+	}
+	_ = m.chunks.DeleteMultiple(ctx, keys)
+}
+
+// chunkKey key của chunk trong storage, offset cố định độ dài để sắp xếp đúng thứ tự
+func (m *Manager) chunkKey(id string, offset int64) string {
+	return fmt.Sprintf("%s/%s/%020d", m.config.ChunkPrefix, id, offset)
+}
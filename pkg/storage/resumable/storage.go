@@ -0,0 +1,44 @@
+package resumable
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+)
+
+// StorageValidate kiểm tra loại file, kích thước theo category (metadata filetype, category) và quota của owner khi tạo upload
+func StorageValidate(sm *storage.StorageManager) ValidateFunc {
+	return func(ctx context.Context, upload *Upload) error {
+		if err := sm.ValidateUpload(upload.Filename(), upload.ContentType(), upload.Length, upload.Category()); err != nil {
+			return err
+		}
+		return sm.CheckQuota(storage.WithOwner(ctx, upload.Owner), upload.Length)
+	}
+}
+
+// StorageComplete lưu file hoàn chỉnh qua StorageManager (bucket theo tenant/category như upload thường, tính vào quota của owner)
+func StorageComplete(sm *storage.StorageManager) CompleteFunc {
+	return func(ctx context.Context, upload *Upload, content io.ReadSeeker) (*storage.UploadResult, error) {
+		options := storage.GetDefaultUploadOptions(upload.Category())
+		return sm.UploadReader(storage.WithOwner(ctx, upload.Owner), upload.Filename(), content, upload.Length, upload.ContentType(), options)
+	}
+}
+
+// NewFromConfig tạo manager lưu chunk trong bucket mặc định, trạng thái trong cache và file hoàn chỉnh qua StorageManager
+func NewFromConfig(sm *storage.StorageManager, c cache.Cache, cfg storage.ResumableConfig) (*Manager, error) {
+	chunks, err := sm.Storage(storage.DefaultBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewManager(chunks, NewCacheStore(c), Config{
+		MaxSize:     cfg.MaxSize,
+		Expiration:  time.Duration(cfg.ExpirationHours) * time.Hour,
+		ChunkPrefix: cfg.ChunkPrefix,
+		Validate:    StorageValidate(sm),
+		Complete:    StorageComplete(sm),
+	}), nil
+}
@@ -0,0 +1,97 @@
+package resumable
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Store lưu trạng thái upload, dùng chung giữa các instance (Redis trong production)
+type Store interface {
+	Save(ctx context.Context, upload *Upload) error
+	// Get trả về ErrNotFound nếu upload không tồn tại
+	Get(ctx context.Context, id string) (*Upload, error)
+	Delete(ctx context.Context, id string) error
+	// List ID các upload đang được theo dõi (để dọn upload bỏ dở)
+	List(ctx context.Context) ([]string, error)
+	// Lock đảm bảo mỗi upload chỉ có một request ghi tại một thời điểm
+	Lock(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	Unlock(ctx context.Context, id string) error
+}
+
+// stateRetention thời gian giữ trạng thái sau ExpiresAt để job cleanup còn tìm được chunk cần xóa
+const stateRetention = 24 * time.Hour
+
+const indexKey = "resumable:uploads"
+
+// cacheStore Store dùng pkg/cache
+type cacheStore struct {
+	cache cache.Cache
+}
+
+// NewCacheStore tạo Store dùng cache (Redis dùng chung giữa các instance)
+func NewCacheStore(c cache.Cache) Store {
+	return &cacheStore{cache: c}
+}
+
+func uploadKey(id string) string {
+	return "resumable:upload:" + id
+}
+
+func lockKey(id string) string {
+	return "resumable:lock:" + id
+}
+
+func (s *cacheStore) Save(ctx context.Context, upload *Upload) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return fmt.Errorf("failed to encode upload: %w", err)
+	}
+
+	ttl := time.Until(upload.ExpiresAt) + stateRetention
+	if err := s.cache.Set(ctx, uploadKey(upload.ID), string(data), ttl); err != nil {
+		return err
+	}
+	return s.cache.SAdd(ctx, indexKey, upload.ID)
+}
+
+func (s *cacheStore) Get(ctx context.Context, id string) (*Upload, error) {
+	raw, err := s.cache.Get(ctx, uploadKey(id))
+	if errors.Is(err, cache.ErrCacheMiss) || errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var upload Upload
+	if err := json.Unmarshal([]byte(raw), &upload); err != nil {
+		return nil, fmt.Errorf("failed to decode upload: %w", err)
+	}
+	return &upload, nil
+}
+
+func (s *cacheStore) Delete(ctx context.Context, id string) error {
+	if err := s.cache.Del(ctx, uploadKey(id)); err != nil {
+		return err
+	}
+	return s.cache.SRem(ctx, indexKey, id)
+}
+
+func (s *cacheStore) List(ctx context.Context) ([]string, error) {
+	return s.cache.SMembers(ctx, indexKey)
+}
+
+func (s *cacheStore) Lock(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	return s.cache.Lock(ctx, lockKey(id), ttl)
+}
+
+func (s *cacheStore) Unlock(ctx context.Context, id string) error {
+	return s.cache.Unlock(ctx, lockKey(id))
+}
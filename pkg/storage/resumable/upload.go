@@ -0,0 +1,110 @@
+package resumable
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/storage"
+)
+
+var (
+	ErrNotFound       = errors.New("upload not found")
+	ErrExpired        = errors.New("upload expired")
+	ErrOffsetMismatch = errors.New("upload offset does not match")
+	ErrTooLarge       = errors.New("upload length exceeds maximum size")
+	ErrExceedsLength  = errors.New("chunk exceeds upload length")
+	ErrLocked         = errors.New("upload is locked by another request")
+	ErrInvalidLength  = errors.New("invalid upload length")
+	ErrRejected       = errors.New("upload rejected")
+)
+
+// Upload trạng thái một upload resumable
+type Upload struct {
+	ID        string                `json:"id"`
+	Owner     string                `json:"owner,omitempty"` // User tạo upload, chỉ user này được ghi/xem
+	Length    int64                 `json:"length"`          // Tổng kích thước file (Upload-Length)
+	Offset    int64                 `json:"offset"`          // Số byte đã nhận (Upload-Offset)
+	Metadata  map[string]string     `json:"metadata,omitempty"`
+	Chunks    []Chunk               `json:"chunks,omitempty"` // Chunk đã lưu theo thứ tự offset
+	CreatedAt time.Time             `json:"created_at"`
+	ExpiresAt time.Time             `json:"expires_at"`       // Gia hạn sau mỗi lần ghi, quá hạn thì bị dọn
+	Result    *storage.UploadResult `json:"result,omitempty"` // File hoàn chỉnh sau khi ghép chunk
+}
+
+// Chunk một phần dữ liệu đã lưu trong storage
+type Chunk struct {
+	Key    string `json:"key"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"`
+}
+
+// Completed upload đã nhận đủ dữ liệu và được ghép thành file
+func (u *Upload) Completed() bool {
+	return u.Result != nil
+}
+
+// Expired upload đã quá hạn tại thời điểm now
+func (u *Upload) Expired(now time.Time) bool {
+	return !u.ExpiresAt.IsZero() && now.After(u.ExpiresAt)
+}
+
+// Filename tên file từ metadata (filename hoặc name, theo tus-js-client / Uppy)
+func (u *Upload) Filename() string {
+	if name := u.Metadata["filename"]; name != "" {
+		return name
+	}
+	return u.Metadata["name"]
+}
+
+// ContentType MIME type từ metadata (filetype hoặc type)
+func (u *Upload) ContentType() string {
+	if contentType := u.Metadata["filetype"]; contentType != "" {
+		return contentType
+	}
+	return u.Metadata["type"]
+}
+
+// Category storage category từ metadata (image, video, document...)
+func (u *Upload) Category() string {
+	return u.Metadata["category"]
+}
+
+// ParseMetadata parse header Upload-Metadata: các cặp "key base64(value)" cách nhau bởi dấu phẩy
+func ParseMetadata(header string) (map[string]string, error) {
+	metadata := map[string]string{}
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, encoded, _ := strings.Cut(pair, " ")
+		if key == "" {
+			return nil, fmt.Errorf("invalid upload metadata %q", pair)
+		}
+		value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return nil, fmt.Errorf("invalid upload metadata value for %q: %w", key, err)
+		}
+		metadata[key] = string(value)
+	}
+	return metadata, nil
+}
+
+// EncodeMetadata tạo header Upload-Metadata từ metadata (key sắp xếp để output ổn định)
+func EncodeMetadata(metadata map[string]string) string {
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+" "+base64.StdEncoding.EncodeToString([]byte(metadata[key])))
+	}
+	return strings.Join(pairs, ",")
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// ScanPolicyReject từ chối file nhiễm, không lưu lại
+	ScanPolicyReject = "reject"
+	// ScanPolicyQuarantine lưu file nhiễm vào QuarantinePrefix để điều tra rồi từ chối
+	ScanPolicyQuarantine = "quarantine"
+)
+
+var (
+	// ErrFileInfected scanner phát hiện malware trong file
+	ErrFileInfected = errors.New("file infected")
+	// ErrScanFailed không quét được file (scanner lỗi hoặc không kết nối được) và STORAGE_SCAN_FAIL_OPEN=false
+	ErrScanFailed = errors.New("malware scan failed")
+)
+
+// SetScanner thay scanner tạo từ STORAGE_SCAN_DRIVER (vd. dịch vụ quét riêng), nil để tắt quét
+func (sm *StorageManager) SetScanner(scanner interfaces.Scanner) {
+	sm.scanner = scanner
+}
+
+// scanFile quét file trước khi lưu, content được seek về đầu sau khi quét.
+// File nhiễm trả ErrFileInfected (policy quarantine: lưu bản sao vào QuarantinePrefix của bucket trước).
+func (sm *StorageManager) scanFile(ctx context.Context, b *bucket, filename, contentType string, content io.ReadSeeker) error {
+	if sm.scanner == nil {
+		return nil
+	}
+
+	result, err := sm.scanner.Scan(ctx, filename, content)
+	if _, seekErr := content.Seek(0, io.SeekStart); seekErr != nil {
+		return fmt.Errorf("failed to read file: %w", seekErr)
+	}
+	if err != nil {
+		return sm.scanError(filename, err)
+	}
+	if !result.Infected {
+		return nil
+	}
+
+	if sm.scanConfig.Policy == ScanPolicyQuarantine {
+		key := sm.quarantineKey(filename)
+		_, err := b.storage.Upload(ctx, key, content, &interfaces.UploadOptions{
+			Path:        key,
+			ContentType: contentType,
+			Metadata:    map[string]string{"original-filename": filename, "signature": result.Signature},
+		})
+		sm.logQuarantine(filename, key, result.Signature, err)
+	} else {
+		logger.Warnf("Rejected infected upload %s: %s", filename, result.Signature)
+	}
+	return fmt.Errorf("%w: %s", ErrFileInfected, result.Signature)
+}
+
+// scanStoredFile quét object đã nằm trên storage (presigned upload). File nhiễm bị chuyển vào
+// QuarantinePrefix hoặc xóa theo policy rồi trả ErrFileInfected.
+func (sm *StorageManager) scanStoredFile(ctx context.Context, s interfaces.Storage, key, filename string) error {
+	if sm.scanner == nil {
+		return nil
+	}
+
+	reader, err := s.Download(ctx, key)
+	if err != nil {
+		return err
+	}
+	result, err := sm.scanner.Scan(ctx, filename, reader)
+	reader.Close()
+	if err != nil {
+		return sm.scanError(filename, err)
+	}
+	if !result.Infected {
+		return nil
+	}
+
+	if sm.scanConfig.Policy == ScanPolicyQuarantine {
+		quarantineKey := sm.quarantineKey(filename)
+		err := s.Move(ctx, key, quarantineKey)
+		sm.logQuarantine(filename, quarantineKey, result.Signature, err)
+		if err == nil {
+			return fmt.Errorf("%w: %s", ErrFileInfected, result.Signature)
+		}
+	} else {
+		logger.Warnf("Rejected infected upload %s: %s", filename, result.Signature)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		return fmt.Errorf("failed to delete infected upload: %w", err)
+	}
+	return fmt.Errorf("%w: %s", ErrFileInfected, result.Signature)
+}
+
+// scanError scanner lỗi: fail-open cho file qua (có log), ngược lại từ chối
+func (sm *StorageManager) scanError(filename string, err error) error {
+	if sm.scanConfig.FailOpen {
+		logger.Warnf("Malware scan failed for %s, accepting file (STORAGE_SCAN_FAIL_OPEN): %v", filename, err)
+		return nil
+	}
+	return fmt.Errorf("%w: %v", ErrScanFailed, err)
+}
+
+// quarantineKey .quarantine/2006-01-02/<uuid>_<tên file>
+func (sm *StorageManager) quarantineKey(filename string) string {
+	return path.Join(sm.scanConfig.QuarantinePrefix, time.Now().UTC().Format("2006-01-02"), uuid.New().String()+"_"+path.Base(filename))
+}
+
+// logQuarantine ghi log file bị cách ly để điều tra
+func (sm *StorageManager) logQuarantine(filename, key, signature string, err error) {
+	if err != nil {
+		logger.Errorf("Failed to quarantine infected upload %s (%s): %v", filename, signature, err)
+		return
+	}
+	logger.Warnf("Quarantined infected upload %s (%s) at %s", filename, signature, key)
+}
+
+// newScanConfig chuẩn hóa cấu hình quét: policy mặc định reject, prefix cách ly mặc định .quarantine
+func newScanConfig(cfg ScanConfig) ScanConfig {
+	if cfg.Policy == "" {
+		cfg.Policy = ScanPolicyReject
+	}
+	if cfg.QuarantinePrefix == "" {
+		cfg.QuarantinePrefix = ".quarantine"
+	}
+	return cfg
+}
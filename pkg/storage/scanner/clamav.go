@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+)
+
+// clamavChunkSize kích thước mỗi chunk INSTREAM, nhỏ hơn StreamMaxLength mặc định của clamd
+const clamavChunkSize = 64 * 1024
+
+// ClamAV scanner gửi file tới clamd bằng lệnh INSTREAM
+type ClamAV struct {
+	network string // tcp, unix
+	address string
+	timeout time.Duration
+}
+
+// NewClamAV tạo scanner ClamAV. Address dạng tcp://clamav:3310, clamav:3310 hoặc unix:///run/clamav/clamd.sock
+func NewClamAV(address string, timeout time.Duration) (*ClamAV, error) {
+	network := "tcp"
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		network, address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+	if address == "" {
+		return nil, errors.New("clamav address is required")
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &ClamAV{network: network, address: address, timeout: timeout}, nil
+}
+
+// Scan implement interfaces.Scanner
+func (c *ClamAV) Scan(ctx context.Context, filename string, reader io.Reader) (*interfaces.ScanResult, error) {
+	conn, err := dial(ctx, c.network, c.address, c.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("clamav: %w", err)
+	}
+	defer conn.Close()
+
+	// zINSTREAM: từng chunk <độ dài 4 byte big-endian><dữ liệu>, kết thúc bằng chunk độ dài 0
+	writeErr := c.stream(conn, reader)
+
+	// clamd đóng kết nối sớm khi file vượt StreamMaxLength: vẫn đọc reply để lấy lý do
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		if writeErr != nil {
+			return nil, fmt.Errorf("clamav: %w", writeErr)
+		}
+		return nil, fmt.Errorf("clamav: read reply: %w", err)
+	}
+	return parseClamAVReply(reply)
+}
+
+// stream gửi nội dung file theo INSTREAM
+func (c *ClamAV) stream(conn net.Conn, reader io.Reader) error {
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return err
+	}
+	buf := make([]byte, 4+clamavChunkSize)
+	for {
+		n, err := reader.Read(buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, werr := conn.Write(buf[:4+n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := conn.Write([]byte{0, 0, 0, 0})
+	return err
+}
+
+// parseClamAVReply "stream: OK", "stream: Eicar-Test-Signature FOUND" hoặc "INSTREAM size limit exceeded. ERROR"
+func parseClamAVReply(reply string) (*interfaces.ScanResult, error) {
+	reply = strings.TrimSpace(strings.TrimRight(reply, "\x00"))
+	status := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case status == "OK":
+		return &interfaces.ScanResult{}, nil
+	case strings.HasSuffix(status, " FOUND"):
+		return &interfaces.ScanResult{Infected: true, Signature: strings.TrimSuffix(status, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamav: %s", reply)
+	}
+}
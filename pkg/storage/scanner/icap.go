@@ -0,0 +1,141 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+)
+
+// icapInfectionHeaders header ICAP server dùng để báo malware (draft-stecher-icap-subid)
+var icapInfectionHeaders = []string{"X-Infection-Found", "X-Virus-ID", "X-Violations-Found"}
+
+// ICAP scanner gửi file tới ICAP server bằng RESPMOD (RFC 3507)
+type ICAP struct {
+	url     *url.URL
+	timeout time.Duration
+}
+
+// NewICAP tạo scanner ICAP. Address là URL service, vd. icap://icap:1344/avscan
+func NewICAP(address string, timeout time.Duration) (*ICAP, error) {
+	u, err := url.Parse(address)
+	if err != nil || u.Scheme != "icap" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid icap address %q (icap://host:1344/service)", address)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(u.Hostname(), "1344")
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &ICAP{url: u, timeout: timeout}, nil
+}
+
+// Scan implement interfaces.Scanner
+func (c *ICAP) Scan(ctx context.Context, filename string, reader io.Reader) (*interfaces.ScanResult, error) {
+	conn, err := dial(ctx, "tcp", c.url.Host, c.timeout)
+	if err != nil {
+		return nil, fmt.Errorf("icap: %w", err)
+	}
+	defer conn.Close()
+
+	if err := c.writeRequest(conn, filename, reader); err != nil {
+		return nil, fmt.Errorf("icap: %w", err)
+	}
+	return readICAPResponse(bufio.NewReader(conn))
+}
+
+// writeRequest gửi RESPMOD với HTTP request/response giả lập việc tải file, body dạng chunked
+func (c *ICAP) writeRequest(conn net.Conn, filename string, reader io.Reader) error {
+	reqHdr := "GET /" + url.PathEscape(filename) + " HTTP/1.1\r\nHost: upload\r\n\r\n"
+	resHdr := "HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\n\r\n"
+
+	w := bufio.NewWriter(conn)
+	fmt.Fprintf(w, "RESPMOD %s ICAP/1.0\r\n", c.url.String())
+	fmt.Fprintf(w, "Host: %s\r\n", c.url.Host)
+	fmt.Fprintf(w, "Allow: 204\r\n")
+	fmt.Fprintf(w, "Encapsulated: req-hdr=0, res-hdr=%d, res-body=%d\r\n\r\n", len(reqHdr), len(reqHdr)+len(resHdr))
+	w.WriteString(reqHdr)
+	w.WriteString(resHdr)
+
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := reader.Read(buf)
+		if n > 0 {
+			fmt.Fprintf(w, "%x\r\n", n)
+			w.Write(buf[:n])
+			w.WriteString("\r\n")
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	w.WriteString("0\r\n\r\n")
+	return w.Flush()
+}
+
+// readICAPResponse 204: file sạch. 200: server sửa response, có header báo malware hoặc
+// HTTP status bên trong khác 2xx (trang chặn) nghĩa là file bị nhiễm
+func readICAPResponse(r *bufio.Reader) (*interfaces.ScanResult, error) {
+	tp := textproto.NewReader(r)
+	statusLine, err := tp.ReadLine()
+	if err != nil {
+		return nil, fmt.Errorf("icap: read response: %w", err)
+	}
+	var proto string
+	var status int
+	if _, err := fmt.Sscanf(statusLine, "%s %d", &proto, &status); err != nil || !strings.HasPrefix(proto, "ICAP/") {
+		return nil, fmt.Errorf("icap: malformed status line %q", statusLine)
+	}
+	header, err := tp.ReadMIMEHeader()
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("icap: read headers: %w", err)
+	}
+
+	switch status {
+	case 204:
+		return &interfaces.ScanResult{}, nil
+	case 200:
+	default:
+		return nil, fmt.Errorf("icap: server returned %s", statusLine)
+	}
+
+	for _, name := range icapInfectionHeaders {
+		if value := header.Get(name); value != "" {
+			return &interfaces.ScanResult{Infected: true, Signature: icapThreat(value)}, nil
+		}
+	}
+
+	// Không có header: xem HTTP response được đóng gói, server chặn file thì thay bằng 403/trang cảnh báo
+	if strings.Contains(header.Get("Encapsulated"), "res-hdr") {
+		if line, err := tp.ReadLine(); err == nil {
+			var httpProto string
+			var httpStatus int
+			if _, err := fmt.Sscanf(line, "%s %d", &httpProto, &httpStatus); err == nil && (httpStatus < 200 || httpStatus > 299) {
+				return &interfaces.ScanResult{Infected: true, Signature: "blocked by icap server"}, nil
+			}
+		}
+	}
+	return &interfaces.ScanResult{}, nil
+}
+
+// icapThreat lấy tên malware từ "Type=0; Resolution=2; Threat=Eicar-Test-Signature;"
+func icapThreat(value string) string {
+	for _, part := range strings.Split(value, ";") {
+		if key, threat, ok := strings.Cut(strings.TrimSpace(part), "="); ok && strings.EqualFold(key, "Threat") {
+			return strings.TrimSpace(threat)
+		}
+	}
+	return strings.TrimSpace(value)
+}
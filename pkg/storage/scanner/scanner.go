@@ -0,0 +1,46 @@
+// Package scanner quét malware file upload qua ClamAV (clamd) hoặc ICAP server (c-icap, Kaspersky, Sophos...)
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+)
+
+// DefaultTimeout thời gian tối đa cho một lần quét
+const DefaultTimeout = 30 * time.Second
+
+// New tạo scanner theo driver: clamav, icap. Driver rỗng trả nil (không quét)
+func New(driver, address string, timeout time.Duration) (interfaces.Scanner, error) {
+	switch driver {
+	case "":
+		return nil, nil
+	case "clamav":
+		return NewClamAV(address, timeout)
+	case "icap":
+		return NewICAP(address, timeout)
+	default:
+		return nil, fmt.Errorf("unsupported scanner driver: %s", driver)
+	}
+}
+
+// dial kết nối tới scanner, deadline theo context hoặc timeout
+func dial(ctx context.Context, network, address string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	dialer := net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return nil, err
+	}
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
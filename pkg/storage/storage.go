@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
@@ -9,36 +10,43 @@ import (
 	"strings"
 	"time"
 
-	"api-core/config"
-	"api-core/pkg/storage/aws"
-	"api-core/pkg/storage/image"
-	"api-core/pkg/storage/interfaces"
-	"api-core/pkg/storage/local"
-	"api-core/pkg/storage/validator"
+	"github.com/anhnq996/go-api-core/pkg/storage/image"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/scanner"
+	"github.com/anhnq996/go-api-core/pkg/storage/validator"
 
 	"github.com/google/uuid"
 )
 
 // StorageManager quản lý storage operations
 type StorageManager struct {
-	storage        interfaces.Storage
+	buckets        map[string]*bucket // Bucket theo tên, luôn có DefaultBucket
+	routes         []Route
 	imageProcessor interfaces.ImageProcessor
+	imageVariants  []string // Định dạng lưu thêm khi upload ảnh (webp, avif)
 	validator      interfaces.FileValidator
+	scanner        interfaces.Scanner // Quét malware trước khi lưu, nil: không quét
+	scanConfig     ScanConfig
+	usage          UsageStore     // Dung lượng theo owner (WithOwner), nil: không tính quota
+	quota          int64          // Quota mặc định mỗi owner (bytes), 0: không giới hạn
+	owners         FileOwnerStore // Owner theo file (WithOwner), nil: không lưu
 }
 
 // UploadResult kết quả upload file
 type UploadResult struct {
-	Path        string `json:"path"`         // Đường dẫn file
-	URL         string `json:"url"`          // URL để truy cập file
-	Size        int64  `json:"size"`         // Kích thước file
-	ContentType string `json:"content_type"` // MIME type
-	ETag        string `json:"etag"`         // ETag
+	Path        string            `json:"path"`               // Đường dẫn file
+	Bucket      string            `json:"bucket"`             // Bucket chứa file, dùng với WithBucket khi xóa/lấy URL
+	URL         string            `json:"url"`                // URL để truy cập file
+	Size        int64             `json:"size"`               // Kích thước file
+	ContentType string            `json:"content_type"`       // MIME type
+	ETag        string            `json:"etag"`               // ETag
+	Variants    map[string]string `json:"variants,omitempty"` // Path bản WebP/AVIF theo định dạng
 }
 
 // UploadOptions tùy chọn upload
 type UploadOptions struct {
 	Category     string            `json:"category"`      // image, document, video, audio, archive
-	Path         string            `json:"path"`          // Custom path
+	Path         string            `json:"path"`          // Custom path (prefix của bucket được thêm phía trước)
 	Public       bool              `json:"public"`        // Public access
 	ProcessImage bool              `json:"process_image"` // Process image (resize, etc.)
 	ImageOptions *ImageOptions     `json:"image_options"` // Image processing options
@@ -75,41 +83,71 @@ type WatermarkOptions struct {
 }
 
 // NewStorageManager tạo instance mới của StorageManager
-func NewStorageManager(cfg config.StorageConfig) (*StorageManager, error) {
-	// Tạo storage
-	var storage interfaces.Storage
-	var err error
-
-	switch cfg.Driver {
-	case "local":
-		storage, err = local.NewLocalStorage(cfg.Local.BasePath, cfg.Local.BaseURL)
-	case "s3":
-		storage, err = aws.NewS3Storage(aws.S3Config{
-			Bucket:          cfg.S3.Bucket,
-			Region:          cfg.S3.Region,
-			AccessKeyID:     cfg.S3.AccessKeyID,
-			SecretAccessKey: cfg.S3.SecretAccessKey,
-			BaseURL:         cfg.S3.BaseURL,
-		})
-	default:
-		return nil, fmt.Errorf("unsupported storage driver: %s", cfg.Driver)
-	}
-
+func NewStorageManager(cfg Config) (*StorageManager, error) {
+	// Tạo bucket mặc định và các bucket bổ sung
+	defaultStorage, err := newDriverStorage(cfg.Driver, cfg.Local, cfg.S3)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create storage: %w", err)
 	}
+	buckets := map[string]*bucket{
+		DefaultBucket: {name: DefaultBucket, storage: defaultStorage, prefix: cfg.Prefix, lifecycle: cfg.Lifecycle},
+	}
+	for _, bucketCfg := range cfg.Buckets {
+		if _, exists := buckets[bucketCfg.Name]; exists {
+			return nil, fmt.Errorf("duplicate storage bucket: %s", bucketCfg.Name)
+		}
+		bucketStorage, err := newDriverStorage(bucketCfg.Driver, bucketCfg.Local, bucketCfg.S3)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create storage bucket %s: %w", bucketCfg.Name, err)
+		}
+		buckets[bucketCfg.Name] = &bucket{name: bucketCfg.Name, storage: bucketStorage, prefix: bucketCfg.Prefix, lifecycle: bucketCfg.Lifecycle}
+	}
+
+	// Luật route upload theo tenant/category
+	routes := make([]Route, 0, len(cfg.Routes))
+	for _, item := range cfg.Routes {
+		route, err := ParseRoute(item)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := buckets[route.Bucket]; !ok {
+			return nil, fmt.Errorf("storage route %q: unknown bucket %s", item, route.Bucket)
+		}
+		routes = append(routes, route)
+	}
 
 	// Tạo image processor
 	imageProcessor := image.NewImageProcessor(cfg.Image.Quality)
+	imageProcessor.SetQuality("webp", cfg.Image.WebPQuality)
+	imageProcessor.SetQuality("avif", cfg.Image.AVIFQuality)
+
+	// Bỏ định dạng chưa cài encoder (cwebp, avifenc): upload vẫn chạy, chỉ không có bản thay thế
+	var imageVariants []string
+	for _, format := range cfg.Image.Variants {
+		if image.Supported(format) {
+			imageVariants = append(imageVariants, image.NormalizeFormat(format))
+		}
+	}
 
 	// Tạo file validator
 	fileValidator := validator.NewFileValidator()
 	fileValidator.SetMaxSize("default", cfg.Validation.MaxFileSize)
 
+	// Tạo malware scanner (STORAGE_SCAN_DRIVER rỗng: không quét)
+	fileScanner, err := scanner.New(cfg.Scan.Driver, cfg.Scan.Address, time.Duration(cfg.Scan.TimeoutSeconds)*time.Second)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create scanner: %w", err)
+	}
+
 	return &StorageManager{
-		storage:        storage,
+		buckets:        buckets,
+		routes:         routes,
 		imageProcessor: imageProcessor,
+		imageVariants:  imageVariants,
 		validator:      fileValidator,
+		scanner:        fileScanner,
+		scanConfig:     newScanConfig(cfg.Scan),
+		quota:          cfg.QuotaBytes,
 	}, nil
 }
 
@@ -136,44 +174,74 @@ func (sm *StorageManager) UploadFile(ctx context.Context, fileHeader *multipart.
 		strings.NewReader(string(content)),
 		options.Category,
 	); err != nil {
-		return nil, fmt.Errorf("file validation failed: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrFileRejected, err)
 	}
 
 	// Generate unique filename
+	b, err := sm.resolveBucket(ctx, options.Category)
+	if err != nil {
+		return nil, err
+	}
 	filename := sm.generateFilename(fileHeader.Filename)
-	path := sm.generatePath(options.Path, filename)
+	path := b.key(ctx, options.Category, sm.generatePath(options.Path, filename))
+
+	// Quét malware trước khi lưu
+	contentType := fileHeader.Header.Get("Content-Type")
+	if err := sm.scanFile(ctx, b, fileHeader.Filename, contentType, bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
 
 	// Process image if needed
+	processImage := options.ProcessImage && sm.validator.IsImage(contentType)
 	var processedContent []byte
-	if options.ProcessImage && sm.validator.IsImage(fileHeader.Header.Get("Content-Type")) {
+	if processImage {
 		processedContent, err = sm.processImage(content, options.ImageOptions)
 		if err != nil {
 			return nil, fmt.Errorf("image processing failed: %w", err)
 		}
+		path, contentType = convertedPath(path, contentType, options.ImageOptions)
 	} else {
 		processedContent = content
 	}
 
 	// Prepare upload options
 	uploadOptions := &interfaces.UploadOptions{
-		Path:        path,
-		ContentType: fileHeader.Header.Get("Content-Type"),
-		Public:      options.Public,
-		Metadata:    options.Metadata,
+		Path:         path,
+		ContentType:  contentType,
+		Public:       options.Public,
+		Metadata:     options.Metadata,
+		CacheControl: b.lifecycle.CacheControl,
+		StorageClass: b.lifecycle.StorageClass,
+	}
+
+	// Giữ dung lượng trong quota của owner
+	release, err := sm.reserve(ctx, int64(len(processedContent)))
+	if err != nil {
+		return nil, err
 	}
 
 	// Upload to storage
-	fileInfo, err := sm.storage.UploadBytes(ctx, path, processedContent, uploadOptions)
+	fileInfo, err := b.storage.UploadBytes(ctx, path, processedContent, uploadOptions)
 	if err != nil {
+		release()
 		return nil, fmt.Errorf("failed to upload file: %w", err)
 	}
 
+	sm.recordOwner(ctx, fileInfo.Path, fileInfo.Size)
+
+	var variants map[string]string
+	if processImage {
+		variants = sm.uploadImageVariants(ctx, b, path, processedContent, uploadOptions)
+	}
+
 	return &UploadResult{
 		Path:        fileInfo.Path,
+		Bucket:      b.name,
 		URL:         fileInfo.URL,
 		Size:        fileInfo.Size,
 		ContentType: fileInfo.ContentType,
 		ETag:        fileInfo.ETag,
+		Variants:    variants,
 	}, nil
 }
 
@@ -187,41 +255,122 @@ func (sm *StorageManager) UploadBytes(ctx context.Context, filename string, cont
 		strings.NewReader(string(content)),
 		options.Category,
 	); err != nil {
-		return nil, fmt.Errorf("file validation failed: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrFileRejected, err)
 	}
 
 	// Generate unique filename
+	b, err := sm.resolveBucket(ctx, options.Category)
+	if err != nil {
+		return nil, err
+	}
 	uniqueFilename := sm.generateFilename(filename)
-	path := sm.generatePath(options.Path, uniqueFilename)
+	path := b.key(ctx, options.Category, sm.generatePath(options.Path, uniqueFilename))
+
+	// Quét malware trước khi lưu
+	if err := sm.scanFile(ctx, b, filename, contentType, bytes.NewReader(content)); err != nil {
+		return nil, err
+	}
 
 	// Process image if needed
+	processImage := options.ProcessImage && sm.validator.IsImage(contentType)
 	var processedContent []byte
-	var err error
-	if options.ProcessImage && sm.validator.IsImage(contentType) {
+	if processImage {
 		processedContent, err = sm.processImage(content, options.ImageOptions)
 		if err != nil {
 			return nil, fmt.Errorf("image processing failed: %w", err)
 		}
+		path, contentType = convertedPath(path, contentType, options.ImageOptions)
 	} else {
 		processedContent = content
 	}
 
 	// Prepare upload options
 	uploadOptions := &interfaces.UploadOptions{
-		Path:        path,
-		ContentType: contentType,
-		Public:      options.Public,
-		Metadata:    options.Metadata,
+		Path:         path,
+		ContentType:  contentType,
+		Public:       options.Public,
+		Metadata:     options.Metadata,
+		CacheControl: b.lifecycle.CacheControl,
+		StorageClass: b.lifecycle.StorageClass,
+	}
+
+	// Giữ dung lượng trong quota của owner
+	release, err := sm.reserve(ctx, int64(len(processedContent)))
+	if err != nil {
+		return nil, err
 	}
 
 	// Upload to storage
-	fileInfo, err := sm.storage.UploadBytes(ctx, path, processedContent, uploadOptions)
+	fileInfo, err := b.storage.UploadBytes(ctx, path, processedContent, uploadOptions)
+	if err != nil {
+		release()
+		return nil, fmt.Errorf("failed to upload file: %w", err)
+	}
+
+	sm.recordOwner(ctx, fileInfo.Path, fileInfo.Size)
+
+	var variants map[string]string
+	if processImage {
+		variants = sm.uploadImageVariants(ctx, b, path, processedContent, uploadOptions)
+	}
+
+	return &UploadResult{
+		Path:        fileInfo.Path,
+		Bucket:      b.name,
+		URL:         fileInfo.URL,
+		Size:        fileInfo.Size,
+		ContentType: fileInfo.ContentType,
+		ETag:        fileInfo.ETag,
+		Variants:    variants,
+	}, nil
+}
+
+// UploadReader upload file từ reader seek được (file tạm của upload lớn) mà không đọc hết vào bộ nhớ.
+// Không xử lý ảnh; loại file được kiểm tra qua 512 byte đầu.
+func (sm *StorageManager) UploadReader(ctx context.Context, filename string, content io.ReadSeeker, size int64, contentType string, options *UploadOptions) (*UploadResult, error) {
+	// Validate file
+	if err := sm.validator.ValidateFile(filename, contentType, size, content, options.Category); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFileRejected, err)
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	// Generate unique filename
+	b, err := sm.resolveBucket(ctx, options.Category)
 	if err != nil {
+		return nil, err
+	}
+	path := b.key(ctx, options.Category, sm.generatePath(options.Path, sm.generateFilename(filename)))
+
+	// Quét malware trước khi lưu
+	if err := sm.scanFile(ctx, b, filename, contentType, content); err != nil {
+		return nil, err
+	}
+
+	// Giữ dung lượng trong quota của owner
+	release, err := sm.reserve(ctx, size)
+	if err != nil {
+		return nil, err
+	}
+
+	fileInfo, err := b.storage.Upload(ctx, path, content, &interfaces.UploadOptions{
+		Path:         path,
+		ContentType:  contentType,
+		Public:       options.Public,
+		Metadata:     options.Metadata,
+		CacheControl: b.lifecycle.CacheControl,
+		StorageClass: b.lifecycle.StorageClass,
+	})
+	if err != nil {
+		release()
 		return nil, fmt.Errorf("failed to upload file: %w", err)
 	}
+	sm.recordOwner(ctx, fileInfo.Path, fileInfo.Size)
 
 	return &UploadResult{
 		Path:        fileInfo.Path,
+		Bucket:      b.name,
 		URL:         fileInfo.URL,
 		Size:        fileInfo.Size,
 		ContentType: fileInfo.ContentType,
@@ -229,37 +378,90 @@ func (sm *StorageManager) UploadBytes(ctx context.Context, filename string, cont
 	}, nil
 }
 
-// DeleteFile xóa file
+// ValidateUpload kiểm tra loại file và kích thước theo category trước khi nhận nội dung (upload resumable)
+func (sm *StorageManager) ValidateUpload(filename, contentType string, size int64, category string) error {
+	return sm.validator.ValidateFile(filename, contentType, size, nil, category)
+}
+
+// Storage storage backend của bucket (DefaultBucket cho bucket mặc định)
+func (sm *StorageManager) Storage(name string) (interfaces.Storage, error) {
+	b, ok := sm.buckets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown storage bucket: %s", name)
+	}
+	return b.storage, nil
+}
+
+// DeleteFile xóa file (kèm bản WebP/AVIF nếu là ảnh), trả lại dung lượng cho owner trong context
 func (sm *StorageManager) DeleteFile(ctx context.Context, path string) error {
-	return sm.storage.Delete(ctx, path)
+	b, err := sm.resolveBucket(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	owner := OwnerFromContext(ctx)
+	if sm.usage != nil && owner != "" {
+		if info, err := b.storage.GetInfo(ctx, path); err == nil {
+			size = info.Size
+		}
+	}
+
+	if err := b.storage.Delete(ctx, path); err != nil {
+		return err
+	}
+	sm.deleteImageVariants(ctx, b, path)
+	sm.release(ctx, owner, size)
+	sm.forgetOwner(ctx, path)
+	return nil
 }
 
 // GetFileURL lấy URL của file
 func (sm *StorageManager) GetFileURL(ctx context.Context, path string, signed bool, expiresIn int64) (string, error) {
+	b, err := sm.resolveBucket(ctx, "")
+	if err != nil {
+		return "", err
+	}
 	if signed {
-		return sm.storage.GetSignedURL(ctx, path, expiresIn)
+		return b.storage.GetSignedURL(ctx, path, expiresIn)
 	}
-	return sm.storage.GetURL(ctx, path)
+	return b.storage.GetURL(ctx, path)
 }
 
-// CopyFile copy file
+// CopyFile copy file (trong cùng bucket)
 func (sm *StorageManager) CopyFile(ctx context.Context, srcPath, dstPath string) error {
-	return sm.storage.Copy(ctx, srcPath, dstPath)
+	b, err := sm.resolveBucket(ctx, "")
+	if err != nil {
+		return err
+	}
+	return b.storage.Copy(ctx, srcPath, dstPath)
 }
 
-// MoveFile move file (copy + delete)
+// MoveFile move file (copy + delete, trong cùng bucket)
 func (sm *StorageManager) MoveFile(ctx context.Context, srcPath, dstPath string) error {
-	return sm.storage.Move(ctx, srcPath, dstPath)
+	b, err := sm.resolveBucket(ctx, "")
+	if err != nil {
+		return err
+	}
+	return b.storage.Move(ctx, srcPath, dstPath)
 }
 
 // FileExists kiểm tra file có tồn tại không
 func (sm *StorageManager) FileExists(ctx context.Context, path string) (bool, error) {
-	return sm.storage.Exists(ctx, path)
+	b, err := sm.resolveBucket(ctx, "")
+	if err != nil {
+		return false, err
+	}
+	return b.storage.Exists(ctx, path)
 }
 
 // GetFileInfo lấy thông tin file
 func (sm *StorageManager) GetFileInfo(ctx context.Context, path string) (*interfaces.FileInfo, error) {
-	return sm.storage.GetInfo(ctx, path)
+	b, err := sm.resolveBucket(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return b.storage.GetInfo(ctx, path)
 }
 
 // processImage xử lý ảnh
@@ -318,7 +520,7 @@ func (sm *StorageManager) processImage(content []byte, options *ImageOptions) ([
 	return processedContent, nil
 }
 
-// generateFilename tạo filename unique
+// generateFilename tạo filename unique, uuid là fingerprint để /storages cache file với immutable (xem IsFingerprinted)
 func (sm *StorageManager) generateFilename(originalFilename string) string {
 	ext := filepath.Ext(originalFilename)
 	name := strings.TrimSuffix(strings.ReplaceAll(originalFilename, " ", "-"), ext)
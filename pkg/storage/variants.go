@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/image"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+)
+
+// variantFormats định dạng của bản ảnh thay thế (STORAGE_IMAGE_VARIANTS)
+var variantFormats = []string{"avif", "webp"}
+
+// VariantPath path bản ảnh định dạng khác: cùng tên, đổi phần mở rộng (avatars/me_<uuid>.png -> avatars/me_<uuid>.webp)
+func VariantPath(key, format string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + image.Extension(format)
+}
+
+// convertedPath đổi phần mở rộng và content type khi ảnh được convert sang định dạng khác (ImageOptions.Format)
+func convertedPath(key, contentType string, options *ImageOptions) (string, string) {
+	if options == nil || options.Format == "" || !image.Supported(options.Format) {
+		return key, contentType // Định dạng không hỗ trợ: encodeImage giữ mặc định JPEG như trước
+	}
+	return VariantPath(key, options.Format), image.ContentType(options.Format)
+}
+
+// hasVariants chỉ ảnh JPEG/PNG mới có bản WebP/AVIF (GIF động sẽ mất animation)
+func hasVariants(key string) bool {
+	switch strings.ToLower(path.Ext(key)) {
+	case ".jpg", ".jpeg", ".png":
+		return true
+	}
+	return false
+}
+
+// ImageVariants định dạng ảnh thay thế được lưu khi upload (đã bỏ định dạng thiếu encoder)
+func (sm *StorageManager) ImageVariants() []string {
+	return sm.imageVariants
+}
+
+// uploadImageVariants lưu thêm bản WebP/AVIF cạnh ảnh để FileServer phục vụ theo Accept.
+// Variant chỉ để giảm băng thông: encode/upload lỗi thì bỏ qua, client nhận ảnh gốc.
+func (sm *StorageManager) uploadImageVariants(ctx context.Context, b *bucket, key string, content []byte, options *interfaces.UploadOptions) map[string]string {
+	if !hasVariants(key) {
+		return nil
+	}
+
+	var variants map[string]string
+	for _, format := range sm.imageVariants {
+		reader, err := sm.imageProcessor.Convert(ctx, bytes.NewReader(content), format)
+		if err != nil {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			continue
+		}
+
+		variantOptions := *options
+		variantOptions.Path = VariantPath(key, format)
+		variantOptions.ContentType = image.ContentType(format)
+		if _, err := b.storage.UploadBytes(ctx, variantOptions.Path, data, &variantOptions); err != nil {
+			continue
+		}
+		if variants == nil {
+			variants = map[string]string{}
+		}
+		variants[format] = variantOptions.Path
+	}
+	return variants
+}
+
+// deleteImageVariants xóa các bản WebP/AVIF của ảnh, kể cả định dạng đã bỏ khỏi cấu hình
+func (sm *StorageManager) deleteImageVariants(ctx context.Context, b *bucket, key string) {
+	if !hasVariants(key) || !IsFingerprinted(key) {
+		return
+	}
+	for _, format := range variantFormats {
+		variant := VariantPath(key, format)
+		if exists, err := b.storage.Exists(ctx, variant); err == nil && exists {
+			_ = b.storage.Delete(ctx, variant)
+		}
+	}
+}
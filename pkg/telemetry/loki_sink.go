@@ -0,0 +1,48 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/anhnq996/go-api-core/pkg/loki"
+)
+
+// LokiSink gửi report lên Loki (job="telemetry")
+type LokiSink struct {
+	client *loki.Client
+}
+
+// NewLokiSink tạo Loki sink mới
+func NewLokiSink(url, environment string) *LokiSink {
+	return &LokiSink{
+		client: loki.NewClient(loki.Config{
+			URL:         url,
+			Job:         "telemetry",
+			Environment: environment,
+			Labels: map[string]string{
+				// Ghi đè label host mặc định để không lộ hostname
+				"host": anonymousInstanceID(),
+			},
+		}),
+	}
+}
+
+// Send gửi report dưới dạng event "feature_usage"
+func (s *LokiSink) Send(ctx context.Context, report Report) error {
+	data := make(map[string]interface{})
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+
+	return s.client.SendEvent(ctx, loki.Event{
+		Action:    "feature_usage",
+		Entity:    "telemetry",
+		EntityID:  report.InstanceID,
+		Data:      data,
+		Timestamp: report.PeriodEnd,
+	})
+}
@@ -0,0 +1,23 @@
+package telemetry
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Middleware đếm request theo endpoint class (method + route pattern của chi)
+// Dùng route pattern thay vì path thực tế để không lộ ID trong URL
+func Middleware(collector *Collector) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			class := "unmatched"
+			if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+				class = rctx.RoutePattern()
+			}
+			collector.TrackEndpoint(r.Method + " " + class)
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+)
+
+// Report là bản tổng hợp feature usage đã ẩn danh hoá, gửi định kỳ lên analytics pipeline
+// Không chứa user ID, IP hay dữ liệu request - chỉ có counters
+type Report struct {
+	InstanceID  string           `json:"instance_id"`
+	Environment string           `json:"environment"`
+	Version     string           `json:"version,omitempty"`
+	PeriodStart time.Time        `json:"period_start"`
+	PeriodEnd   time.Time        `json:"period_end"`
+	Features    map[string]int64 `json:"features"`
+	Endpoints   map[string]int64 `json:"endpoints"`
+	Flags       map[string]bool  `json:"flags"`
+}
+
+// Sink định nghĩa nơi nhận report (Loki, metrics pipeline...)
+type Sink interface {
+	Send(ctx context.Context, report Report) error
+}
+
+// Config cấu hình cho telemetry collector
+type Config struct {
+	Environment   string
+	Version       string
+	FlushInterval time.Duration   // Chu kỳ gửi report (default: 1 giờ)
+	Flags         map[string]bool // Feature flags đang bật (fcm, s3, social login...)
+}
+
+// Collector thu thập counters trong bộ nhớ và flush định kỳ qua Sink
+type Collector struct {
+	config     Config
+	sink       Sink
+	instanceID string
+
+	mu          sync.Mutex
+	features    map[string]int64
+	endpoints   map[string]int64
+	periodStart time.Time
+}
+
+// NewCollector tạo collector mới
+func NewCollector(config Config, sink Sink) *Collector {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Hour
+	}
+	if config.Flags == nil {
+		config.Flags = make(map[string]bool)
+	}
+
+	return &Collector{
+		config:      config,
+		sink:        sink,
+		instanceID:  anonymousInstanceID(),
+		features:    make(map[string]int64),
+		endpoints:   make(map[string]int64),
+		periodStart: time.Now(),
+	}
+}
+
+// Track tăng counter cho một feature (ví dụ: "excel.export", "fcm.send", "chat.message")
+func (c *Collector) Track(feature string) {
+	c.mu.Lock()
+	c.features[feature]++
+	c.mu.Unlock()
+}
+
+// TrackEndpoint tăng counter cho một endpoint class (ví dụ: "GET /api/v1/users/{id}")
+func (c *Collector) TrackEndpoint(class string) {
+	c.mu.Lock()
+	c.endpoints[class]++
+	c.mu.Unlock()
+}
+
+// SetFlag đánh dấu một feature flag đang bật/tắt
+func (c *Collector) SetFlag(name string, enabled bool) {
+	c.mu.Lock()
+	c.config.Flags[name] = enabled
+	c.mu.Unlock()
+}
+
+// Start chạy vòng lặp flush cho đến khi ctx bị huỷ (flush lần cuối trước khi dừng)
+func (c *Collector) Start(ctx context.Context) {
+	ticker := time.NewTicker(c.config.FlushInterval)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				c.flushAndLog(context.Background())
+				return
+			case <-ticker.C:
+				c.flushAndLog(ctx)
+			}
+		}
+	}()
+}
+
+// Flush gửi report hiện tại và reset counters
+func (c *Collector) Flush(ctx context.Context) error {
+	report := c.snapshot()
+	if len(report.Features) == 0 && len(report.Endpoints) == 0 {
+		return nil
+	}
+	return c.sink.Send(ctx, report)
+}
+
+// flushAndLog flush trong vòng lặp nền, lỗi chỉ được log (counters của chu kỳ đó bị bỏ)
+func (c *Collector) flushAndLog(ctx context.Context) {
+	if err := c.Flush(ctx); err != nil {
+		logger.Errorf("Failed to flush telemetry report: %v", err)
+	}
+}
+
+// snapshot lấy report và reset counters cho chu kỳ tiếp theo
+func (c *Collector) snapshot() Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	flags := make(map[string]bool, len(c.config.Flags))
+	for k, v := range c.config.Flags {
+		flags[k] = v
+	}
+
+	report := Report{
+		InstanceID:  c.instanceID,
+		Environment: c.config.Environment,
+		Version:     c.config.Version,
+		PeriodStart: c.periodStart,
+		PeriodEnd:   now,
+		Features:    c.features,
+		Endpoints:   c.endpoints,
+		Flags:       flags,
+	}
+
+	c.features = make(map[string]int64)
+	c.endpoints = make(map[string]int64)
+	c.periodStart = now
+
+	return report
+}
+
+// anonymousInstanceID tạo ID ổn định cho instance mà không lộ hostname
+func anonymousInstanceID() string {
+	hostname, _ := os.Hostname()
+	sum := sha256.Sum256([]byte("apicore-telemetry:" + hostname))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Global collector instance (nil = telemetry tắt hoặc opt-out)
+var Global *Collector
+
+// Init khởi tạo global collector
+func Init(collector *Collector) {
+	Global = collector
+}
+
+// Track tăng counter feature dùng global collector
+func Track(feature string) {
+	if Global == nil {
+		return // Silently ignore if not initialized
+	}
+	Global.Track(feature)
+}
@@ -0,0 +1,80 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// gormParentKey context trước khi tạo span, khôi phục sau câu lệnh để lệnh tiếp theo của cùng tx không thành span con
+const gormParentKey = "tracing:parent_context"
+
+// InstrumentGORM đăng ký callback tạo span cho mỗi câu lệnh (query, create, update, delete, row, raw).
+// Span là con của span trong context của câu lệnh (db.WithContext(ctx)), SQL ghi vào span chỉ có placeholder, không có giá trị.
+func InstrumentGORM(db *gorm.DB) error {
+	type register func(name string, fn func(*gorm.DB)) error
+	cb := db.Callback()
+	operations := []struct {
+		name          string
+		before, after register
+	}{
+		{"create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+	for _, op := range operations {
+		if err := op.before("tracing:before_"+op.name, beforeStatement(op.name)); err != nil {
+			return err
+		}
+		if err := op.after("tracing:after_"+op.name, afterStatement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func beforeStatement(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		ctx, _ := Start(parent, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", tx.Dialector.Name()),
+				attribute.String("db.operation.name", operation),
+			),
+		)
+		tx.InstanceSet(gormParentKey, parent)
+		tx.Statement.Context = ctx
+	}
+}
+
+func afterStatement(tx *gorm.DB) {
+	span := trace.SpanFromContext(tx.Statement.Context)
+	if parent, ok := tx.InstanceGet(gormParentKey); ok {
+		tx.Statement.Context = parent.(context.Context)
+	}
+	if !span.IsRecording() {
+		span.End()
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("db.query.text", tx.Statement.SQL.String()),
+		attribute.String("db.collection.name", tx.Statement.Table),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	err := tx.Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = nil
+	}
+	End(span, err)
+}
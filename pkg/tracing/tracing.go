@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName tên tracer của các span do ApiCore tạo
+const instrumentationName = "github.com/anhnq996/go-api-core"
+
+// Config cấu hình tracing
+type Config struct {
+	Enabled     bool              // false: span không được ghi (tracer no-op), trace context vẫn được truyền tiếp
+	ServiceName string            // service.name của trace
+	Version     string            // service.version
+	Environment string            // deployment.environment
+	Endpoint    string            // OTLP/HTTP collector: host:port (path /v1/traces) hoặc URL đầy đủ, rỗng theo OTEL_EXPORTER_OTLP_*
+	Insecure    bool              // Endpoint dạng host:port gửi qua HTTP thay vì HTTPS
+	Headers     map[string]string // Header gửi kèm (API key của backend tracing)
+	SampleRatio float64           // Tỷ lệ trace được ghi (0-1), trace đã được upstream sample thì theo upstream
+}
+
+// Init cấu hình TracerProvider và propagator (W3C traceparent + baggage) toàn cục.
+// Trả về hàm shutdown flush span còn trong buffer, gọi khi tắt ứng dụng.
+func Init(ctx context.Context, cfg Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithHeaders(cfg.Headers)}
+	switch {
+	case strings.HasPrefix(cfg.Endpoint, "http://") || strings.HasPrefix(cfg.Endpoint, "https://"):
+		options = append(options, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	case cfg.Endpoint != "":
+		options = append(options, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		if cfg.Insecure {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("tracing: failed to create OTLP exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.version", cfg.Version),
+		attribute.String("deployment.environment", cfg.Environment),
+	)
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// Tracer tracer của ApiCore (theo TracerProvider toàn cục)
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start tạo span con của span trong ctx
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// End kết thúc span, err khác nil được ghi vào span và đánh dấu lỗi
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Go chạy fn trong goroutine với span con của span trong ctx.
+// Context của goroutine không bị hủy theo ctx (request đã trả response), vẫn giữ trace và các value khác.
+func Go(ctx context.Context, name string, fn func(ctx context.Context)) {
+	ctx, span := Start(context.WithoutCancel(ctx), name)
+	go func() {
+		defer span.End()
+		fn(ctx)
+	}()
+}
+
+// Inject ghi trace context của ctx vào carrier (header của queue message, HTTP header)
+func Inject(ctx context.Context, carrier map[string]string) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(carrier))
+}
+
+// Extract đọc trace context từ carrier, span tạo từ context trả về là con của span bên gửi
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(carrier))
+}
+
+// TraceID trace ID của span trong ctx, rỗng nếu không có span được ghi (ghi vào log để tra trace)
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.HasTraceID() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
@@ -1,13 +1,24 @@
 package utils
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
 	"crypto/md5"
 	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
 	"encoding/hex"
+	"fmt"
+	"strings"
 
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
 )
 
+// FirebaseScryptPrefix prefix của password hash import từ Firebase Auth (modified scrypt)
+// Format: $firebase-scrypt$<rounds>$<mem_cost>$<salt_separator>$<signer_key>$<salt>$<hash> (base64)
+const FirebaseScryptPrefix = "$firebase-scrypt$"
+
 // HashPassword hash password với bcrypt
 func HashPassword(password string) (string, error) {
 	bytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -18,11 +29,63 @@ func HashPassword(password string) (string, error) {
 }
 
 // CheckPassword kiểm tra password với hash
+// Hỗ trợ bcrypt và các hash legacy được import từ hệ thống khác (xem NeedsRehash)
 func CheckPassword(password, hash string) bool {
+	if strings.HasPrefix(hash, FirebaseScryptPrefix) {
+		return checkFirebaseScrypt(password, hash)
+	}
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err == nil
 }
 
+// NeedsRehash kiểm tra hash có phải dạng legacy cần hash lại bằng bcrypt sau khi login thành công
+func NeedsRehash(hash string) bool {
+	return strings.HasPrefix(hash, FirebaseScryptPrefix)
+}
+
+// EncodeFirebaseScryptHash đóng gói hash Firebase kèm tham số để lưu vào cột password
+// Tất cả giá trị bytes là base64 chuẩn như trong file export và hash config của Firebase
+func EncodeFirebaseScryptHash(hash, salt, signerKey, saltSeparator string, rounds, memCost int) string {
+	return fmt.Sprintf("%s%d$%d$%s$%s$%s$%s", FirebaseScryptPrefix, rounds, memCost, saltSeparator, signerKey, salt, hash)
+}
+
+// checkFirebaseScrypt verify password theo thuật toán modified scrypt của Firebase:
+// key = scrypt(password, salt+separator, 2^mem_cost, rounds, 1, 32), hash = AES-256-CTR(key, signer_key)
+func checkFirebaseScrypt(password, encoded string) bool {
+	var rounds, memCost int
+	parts := strings.Split(strings.TrimPrefix(encoded, FirebaseScryptPrefix), "$")
+	if len(parts) != 6 {
+		return false
+	}
+	if _, err := fmt.Sscanf(parts[0]+" "+parts[1], "%d %d", &rounds, &memCost); err != nil {
+		return false
+	}
+
+	decoded := make([][]byte, 4)
+	for i, part := range parts[2:] {
+		b, err := base64.StdEncoding.DecodeString(part)
+		if err != nil {
+			return false
+		}
+		decoded[i] = b
+	}
+	saltSeparator, signerKey, salt, expected := decoded[0], decoded[1], decoded[2], decoded[3]
+
+	key, err := scrypt.Key([]byte(password), append(salt, saltSeparator...), 1<<memCost, rounds, 1, 32)
+	if err != nil {
+		return false
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return false
+	}
+	actual := make([]byte, len(signerKey))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(actual, signerKey)
+
+	return subtle.ConstantTimeCompare(actual, expected) == 1
+}
+
 // MD5Hash tạo MD5 hash
 func MD5Hash(text string) string {
 	hash := md5.Sum([]byte(text))
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"net"
 	"net/http"
 	"strings"
@@ -31,6 +32,44 @@ func GetClientIP(r *http.Request) string {
 	return ip
 }
 
+// GetRemoteIP lấy IP từ kết nối TCP, bỏ qua X-Forwarded-For/X-Real-IP do client tự đặt được
+func GetRemoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return ip
+}
+
+// ParseIPNets parse danh sách IP hoặc CIDR (vd 10.0.0.0/8, 127.0.0.1, ::1), IP đơn được coi là /32 (/128 với IPv6)
+func ParseIPNets(entries []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if strings.Contains(entry, "/") {
+			_, ipNet, err := net.ParseCIDR(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", entry, err)
+			}
+			nets = append(nets, ipNet)
+			continue
+		}
+		ip := net.ParseIP(entry)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP %q", entry)
+		}
+		bits := 128
+		if ip4 := ip.To4(); ip4 != nil {
+			ip, bits = ip4, 32
+		}
+		nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+	}
+	return nets, nil
+}
+
 // GetUserAgent lấy User-Agent string
 func GetUserAgent(r *http.Request) string {
 	return r.Header.Get("User-Agent")
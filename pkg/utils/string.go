@@ -85,6 +85,27 @@ func SnakeToCamel(s string) string {
 	return strings.Join(parts, "")
 }
 
+// SnakeToLowerCamel chuyển snake_case sang camelCase (chữ đầu viết thường), vd: per_page -> perPage
+func SnakeToLowerCamel(s string) string {
+	if !strings.Contains(s, "_") {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, part := range strings.Split(s, "_") {
+		if part == "" {
+			continue
+		}
+		if b.Len() == 0 {
+			b.WriteString(part)
+			continue
+		}
+		b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return b.String()
+}
+
 // Contains kiểm tra string có trong slice không
 func Contains(slice []string, item string) bool {
 	for _, s := range slice {
@@ -3,16 +3,18 @@ package utils
 import (
 	"fmt"
 	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
 )
 
-// Now trả về thời gian hiện tại
+// Now trả về thời gian hiện tại theo clock mặc định (có thể freeze trong test qua clock.SetDefault)
 func Now() time.Time {
-	return time.Now()
+	return clock.Now()
 }
 
 // Today trả về ngày hôm nay (00:00:00)
 func Today() time.Time {
-	now := time.Now()
+	now := Now()
 	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 }
 
@@ -91,12 +93,12 @@ func IsToday(t time.Time) bool {
 
 // IsPast kiểm tra có phải trong quá khứ không
 func IsPast(t time.Time) bool {
-	return t.Before(time.Now())
+	return t.Before(Now())
 }
 
 // IsFuture kiểm tra có phải trong tương lai không
 func IsFuture(t time.Time) bool {
-	return t.After(time.Now())
+	return t.After(Now())
 }
 
 // AddDays thêm số ngày
@@ -116,7 +118,7 @@ func AddYears(t time.Time, years int) time.Time {
 
 // Age tính tuổi từ ngày sinh
 func Age(birthDate time.Time) int {
-	now := time.Now()
+	now := Now()
 	age := now.Year() - birthDate.Year()
 
 	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
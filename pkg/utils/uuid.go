@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Các phiên bản UUID hỗ trợ cho primary key
+const (
+	UUIDv4 = 4 // Random
+	UUIDv7 = 7 // Time-ordered, tăng dần theo thời gian tạo nên index B-tree ít bị phân mảnh
+)
+
+// NewUUID tạo UUID theo version (4 hoặc 7), version khác fallback về v4
+func NewUUID(version int) uuid.UUID {
+	if version == UUIDv7 {
+		return NewUUIDv7()
+	}
+	return uuid.New()
+}
+
+// NewUUIDv7 tạo UUIDv7 (time-ordered), fallback về v4 nếu không đọc được random source
+func NewUUIDv7() uuid.UUID {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.New()
+	}
+	return id
+}
+
+// IsUUIDv7 kiểm tra UUID có phải v7 không
+func IsUUIDv7(id uuid.UUID) bool {
+	return id.Version() == UUIDv7
+}
+
+// UUIDTime lấy thời điểm tạo từ UUIDv7 (độ chính xác millisecond), false nếu không phải v7
+func UUIDTime(id uuid.UUID) (time.Time, bool) {
+	if !IsUUIDv7(id) {
+		return time.Time{}, false
+	}
+	sec, nsec := id.Time().UnixTime()
+	return time.Unix(sec, nsec), true
+}
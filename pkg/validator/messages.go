@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
 
 	"github.com/go-playground/validator/v10"
 )
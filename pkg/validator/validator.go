@@ -9,8 +9,8 @@ import (
 	"strconv"
 	"strings"
 
-	"api-core/pkg/i18n"
-	"api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/i18n"
+	"github.com/anhnq996/go-api-core/pkg/response"
 
 	"github.com/go-playground/validator/v10"
 )
@@ -0,0 +1,195 @@
+package test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/apns"
+	"github.com/anhnq996/go-api-core/pkg/push"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// apnsRequest request APNs giả nhận được
+type apnsRequest struct {
+	Token   string
+	Header  http.Header
+	Payload map[string]interface{}
+}
+
+// fakeAPNs APNs giả: token "gone" trả 410 Unregistered, "bad" trả 400 BadDeviceToken, "busy" trả 429, còn lại thành công.
+// Provider token phải ký bằng key của test
+type fakeAPNs struct {
+	mu       sync.Mutex
+	requests []apnsRequest
+}
+
+func (f *fakeAPNs) Requests() []apnsRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]apnsRequest(nil), f.requests...)
+}
+
+func startFakeAPNs(t *testing.T) (*apns.Client, *fakeAPNs) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "AuthKey_KEY123.p8")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+
+	fake := &fakeAPNs{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reject := func(status int, reason string) {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]interface{}{"reason": reason, "timestamp": time.Now().UnixMilli()})
+		}
+
+		providerToken, err := jwt.Parse(strings.TrimPrefix(r.Header.Get("Authorization"), "bearer "), func(token *jwt.Token) (interface{}, error) {
+			assert.Equal(t, "KEY123", token.Header["kid"])
+			return &key.PublicKey, nil
+		}, jwt.WithValidMethods([]string{"ES256"}))
+		if err != nil {
+			reject(http.StatusForbidden, apns.ReasonInvalidProviderToken)
+			return
+		}
+		issuer, _ := providerToken.Claims.GetIssuer()
+		assert.Equal(t, "TEAM123", issuer)
+
+		req := apnsRequest{Token: strings.TrimPrefix(r.URL.Path, "/3/device/"), Header: r.Header.Clone()}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req.Payload))
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, req)
+		fake.mu.Unlock()
+
+		switch req.Token {
+		case "gone":
+			reject(http.StatusGone, apns.ReasonUnregistered)
+		case "bad":
+			reject(http.StatusBadRequest, apns.ReasonBadDeviceToken)
+		case "busy":
+			reject(http.StatusTooManyRequests, apns.ReasonTooManyRequests)
+		default:
+			w.Header().Set("apns-id", "apns-"+req.Token)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := apns.NewClient(&apns.Config{KeyFile: keyFile, KeyID: "KEY123", TeamID: "TEAM123", Topic: "com.example.app", Endpoint: server.URL})
+	require.NoError(t, err)
+	return client, fake
+}
+
+func TestAPNsClientPayloads(t *testing.T) {
+	ctx := context.Background()
+	client, fake := startFakeAPNs(t)
+
+	// Critical alert
+	apnsID, err := client.SendToToken(ctx, "phone", &push.Message{
+		Title: "Cảnh báo",
+		Body:  "Nhiệt độ vượt ngưỡng",
+		Data:  map[string]string{"sensor_id": "7"},
+		APNS:  &push.APNSOptions{Critical: true, Sound: "alarm.caf", CriticalVolume: 0.5, CollapseID: "sensor-7"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "apns-phone", apnsID)
+
+	// Live Activity
+	_, err = client.SendToToken(ctx, "activity", &push.Message{APNS: &push.APNSOptions{LiveActivity: &push.LiveActivity{
+		Event:        push.LiveActivityUpdate,
+		ContentState: map[string]interface{}{"status": "delivering"},
+		Timestamp:    time.Unix(1700000000, 0),
+	}}})
+	require.NoError(t, err)
+
+	requests := fake.Requests()
+	require.Len(t, requests, 2)
+
+	critical := requests[0]
+	assert.Equal(t, "com.example.app", critical.Header.Get("apns-topic"))
+	assert.Equal(t, "alert", critical.Header.Get("apns-push-type"))
+	assert.Equal(t, "10", critical.Header.Get("apns-priority"))
+	assert.Equal(t, "sensor-7", critical.Header.Get("apns-collapse-id"))
+	assert.Equal(t, "7", critical.Payload["sensor_id"])
+	aps := critical.Payload["aps"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"title": "Cảnh báo", "body": "Nhiệt độ vượt ngưỡng"}, aps["alert"])
+	assert.Equal(t, map[string]interface{}{"critical": float64(1), "name": "alarm.caf", "volume": 0.5}, aps["sound"])
+	assert.Equal(t, "critical", aps["interruption-level"])
+
+	activity := requests[1]
+	assert.Equal(t, "com.example.app.push-type.liveactivity", activity.Header.Get("apns-topic"))
+	assert.Equal(t, "liveactivity", activity.Header.Get("apns-push-type"))
+	aps = activity.Payload["aps"].(map[string]interface{})
+	assert.Equal(t, "update", aps["event"])
+	assert.Equal(t, float64(1700000000), aps["timestamp"])
+	assert.Equal(t, map[string]interface{}{"status": "delivering"}, aps["content-state"])
+
+	// Lỗi của từng token, token không còn dùng được nằm trong Invalid
+	_, err = client.SendToToken(ctx, "gone", &push.Message{Title: "Hi"})
+	assert.True(t, apns.IsUnregistered(err))
+
+	result, err := client.Send(ctx, []string{"ok", "gone", "bad", "busy"}, &push.Message{Title: "Hi"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 3, result.FailureCount)
+	assert.ElementsMatch(t, []push.InvalidToken{
+		{Token: "gone", Reason: push.InvalidReasonUnregistered},
+		{Token: "bad", Reason: push.InvalidReasonInvalidArgument},
+	}, result.Invalid)
+}
+
+func TestPusherRoutesIOSDevicesToAPNs(t *testing.T) {
+	ctx := context.Background()
+	devices := setupDeviceTokens(t)
+	apnsClient, fake := startFakeAPNs(t)
+	pusher := device.NewPusher(startFakeFCM(t), devices).Route(model.DevicePlatformIOS, apnsClient)
+
+	userID := uuid.New()
+	for token, platform := range map[string]string{"alive": model.DevicePlatformAndroid, "iphone": model.DevicePlatformIOS, "gone": model.DevicePlatformIOS} {
+		require.NoError(t, devices.Register(ctx, &model.DeviceToken{UserID: userID, Token: token, Platform: platform, LastSeen: time.Now()}))
+	}
+
+	success, failure, err := pusher.SendToUser(ctx, userID, "Hi", "There", map[string]string{"type": "greeting"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, success)
+	assert.Equal(t, 1, failure)
+
+	// Chỉ thiết bị ios đi qua APNs
+	var apnsTokens []string
+	for _, req := range fake.Requests() {
+		apnsTokens = append(apnsTokens, req.Token)
+	}
+	assert.ElementsMatch(t, []string{"iphone", "gone"}, apnsTokens)
+
+	// Token APNs Unregistered bị xóa như token FCM
+	remaining, err := devices.FindByUserID(ctx, userID)
+	require.NoError(t, err)
+	var tokens []string
+	for _, d := range remaining {
+		tokens = append(tokens, d.Token)
+	}
+	assert.ElementsMatch(t, []string{"alive", "iphone"}, tokens)
+
+	// Job có token của platform ios cũng gửi qua APNs
+	_, _, err = pusher.Send(ctx, device.Push{UserID: userID, Tokens: []string{"iphone"}, Platform: model.DevicePlatformIOS, Title: "Hi"})
+	require.NoError(t, err)
+	assert.Len(t, fake.Requests(), 3)
+}
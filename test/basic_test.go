@@ -3,7 +3,7 @@ package test
 import (
 	"testing"
 
-	"api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/cache"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNamespacedCacheKeys(t *testing.T) {
+	ctx := context.Background()
+	backend := cache.NewMockCache()
+	c := cache.NewNamespacedCache(backend, "app", "3", time.Hour)
+
+	// Dữ liệu trạng thái: chỉ namespace
+	require.NoError(t, c.Set(ctx, "blacklist:abc", "1", time.Minute))
+	raw, err := backend.Get(ctx, "app:blacklist:abc")
+	require.NoError(t, err)
+	assert.Equal(t, "1", raw)
+
+	// Dữ liệu cache: namespace + schema version
+	_, err = cache.RememberAs(ctx, c, "users:all", time.Minute, func(context.Context) ([]string, error) {
+		return []string{"alice"}, nil
+	})
+	require.NoError(t, err)
+	exists, err := backend.Exists(ctx, "app:v3:users:all")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
+	assert.Equal(t, "app:v3:users:all", c.Versioned().Key(ctx, "users:all"))
+
+	require.NoError(t, c.Tags("users").Set(ctx, "users:page:1", "page", time.Minute))
+	exists, err = backend.Exists(ctx, "app:v3:users:page:1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
+	require.NoError(t, c.Tags("users").Flush(ctx))
+	exists, err = backend.Exists(ctx, "app:v3:users:page:1")
+	require.NoError(t, err)
+	assert.Zero(t, exists)
+
+	// Schema version khác (deploy mới): không đọc payload của version cũ
+	next := cache.NewNamespacedCache(backend, "app", "4", time.Hour)
+	calls := 0
+	value, err := cache.RememberAs(ctx, next, "users:all", time.Minute, func(context.Context) ([]string, error) {
+		calls++
+		return []string{"alice", "bob"}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "bob"}, value)
+	assert.Equal(t, 1, calls)
+	raw, err = next.Get(ctx, "blacklist:abc")
+	require.NoError(t, err, "dữ liệu trạng thái không phụ thuộc schema version")
+	assert.Equal(t, "1", raw)
+}
+
+func TestNamespacedCacheBumpVersion(t *testing.T) {
+	ctx := context.Background()
+	backend := cache.NewMockCache()
+	first := cache.NewNamespacedCache(backend, "app", "1", time.Hour)
+	second := cache.NewNamespacedCache(backend, "app", "1", 10*time.Millisecond)
+
+	calls := 0
+	load := func(c cache.Cache) []string {
+		value, err := cache.RememberStale(ctx, c, "users:all", staleTestOptions, func(context.Context) ([]string, error) {
+			calls++
+			return []string{"v", string(rune('0' + calls))}, nil
+		})
+		require.NoError(t, err)
+		return value
+	}
+	assert.Equal(t, []string{"v", "1"}, load(first))
+	assert.Equal(t, []string{"v", "1"}, load(second))
+	require.NoError(t, first.Set(ctx, "oauth:state", "pending", time.Minute))
+
+	version, err := first.BumpVersion(ctx)
+	require.NoError(t, err)
+	assert.Regexp(t, `^v1\.[0-9a-z]+$`, version)
+	assert.Equal(t, version, first.Version(ctx))
+
+	// Instance bump: load lại ngay
+	assert.Equal(t, []string{"v", "2"}, load(first))
+
+	// Instance khác: nhận generation mới sau chu kỳ refresh
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, version, second.Version(ctx))
+	assert.Equal(t, []string{"v", "2"}, load(second))
+	assert.Equal(t, 2, calls)
+
+	raw, err := second.Get(ctx, "oauth:state")
+	require.NoError(t, err, "bump version không xóa dữ liệu trạng thái")
+	assert.Equal(t, "pending", raw)
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var staleTestOptions = cache.RememberOptions{
+	TTL:                  time.Minute,
+	StaleWhileRevalidate: 30 * time.Second,
+	StaleIfError:         10 * time.Minute,
+}
+
+func TestRememberStaleWhileRevalidate(t *testing.T) {
+	c := clock.NewFrozen(time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC))
+	ctx := clock.WithContext(context.Background(), c)
+	mockCache := cache.NewMockCache()
+
+	var version atomic.Int32
+	loader := func(ctx context.Context) ([]string, error) {
+		v := version.Add(1)
+		return []string{"user", string(rune('0' + v))}, nil
+	}
+
+	value, err := cache.RememberStale(ctx, mockCache, "users:all", staleTestOptions, loader)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user", "1"}, value)
+
+	// Còn TTL: không gọi loader
+	c.Advance(30 * time.Second)
+	value, err = cache.RememberStale(ctx, mockCache, "users:all", staleTestOptions, loader)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user", "1"}, value)
+	assert.Equal(t, int32(1), version.Load())
+
+	// Quá TTL, trong cửa sổ stale-while-revalidate: trả giá trị cũ ngay, refresh ở background
+	c.Advance(45 * time.Second)
+	value, err = cache.RememberStale(ctx, mockCache, "users:all", staleTestOptions, loader)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user", "1"}, value)
+	require.Eventually(t, func() bool {
+		raw, err := mockCache.Get(ctx, "users:all")
+		return err == nil && strings.Contains(raw, `"2"`)
+	}, time.Second, 10*time.Millisecond, "background refresh ghi giá trị mới vào cache")
+
+	value, err = cache.RememberStale(ctx, mockCache, "users:all", staleTestOptions, loader)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user", "2"}, value)
+	assert.Equal(t, int32(2), version.Load(), "giá trị mới còn TTL, không refresh lại")
+}
+
+func TestRememberStaleIfError(t *testing.T) {
+	c := clock.NewFrozen(time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC))
+	ctx := clock.WithContext(context.Background(), c)
+	mockCache := cache.NewMockCache()
+
+	dbErr := errors.New("database timeout")
+	failing := func(ctx context.Context) (int, error) { return 0, dbErr }
+
+	// Cache miss: lỗi của loader được trả về
+	_, err := cache.RememberStale(ctx, mockCache, "count", staleTestOptions, failing)
+	assert.ErrorIs(t, err, dbErr)
+
+	_, err = cache.RememberStale(ctx, mockCache, "count", staleTestOptions, func(ctx context.Context) (int, error) { return 42, nil })
+	require.NoError(t, err)
+
+	// Quá cửa sổ revalidate nhưng còn stale-if-error: DB lỗi thì dùng bản cũ
+	c.Advance(5 * time.Minute)
+	value, err := cache.RememberStale(ctx, mockCache, "count", staleTestOptions, failing)
+	require.NoError(t, err)
+	assert.Equal(t, 42, value)
+
+	// Hết stale-if-error: trả lỗi
+	c.Advance(10 * time.Minute)
+	_, err = cache.RememberStale(ctx, mockCache, "count", staleTestOptions, failing)
+	assert.ErrorIs(t, err, dbErr)
+}
+
+func TestRememberAsTyped(t *testing.T) {
+	ctx := context.Background()
+	mockCache := cache.NewMockCache()
+
+	type entry struct {
+		ID   int      `json:"id"`
+		Tags []string `json:"tags"`
+	}
+	calls := 0
+	loader := func(ctx context.Context) ([]entry, error) {
+		calls++
+		return []entry{{ID: 1, Tags: []string{"a"}}, {ID: 2}}, nil
+	}
+
+	for range 2 {
+		value, err := cache.RememberAs(ctx, mockCache, "entries", time.Minute, loader)
+		require.NoError(t, err)
+		assert.Equal(t, []entry{{ID: 1, Tags: []string{"a"}}, {ID: 2}}, value)
+	}
+	assert.Equal(t, 1, calls, "lần 2 decode từ cache, không gọi loader")
+
+	// Dữ liệu cũ không decode được về kiểu mới: coi như cache miss
+	require.NoError(t, mockCache.Set(ctx, "entries", `{"legacy":true}`, time.Minute))
+	value, err := cache.RememberAs(ctx, mockCache, "entries", time.Minute, loader)
+	require.NoError(t, err)
+	assert.Len(t, value, 2)
+	assert.Equal(t, 2, calls)
+
+	// Loader lỗi: không ghi cache
+	_, err = cache.RememberAs(ctx, mockCache, "broken", time.Minute, func(ctx context.Context) (int, error) {
+		return 0, errors.New("db down")
+	})
+	assert.Error(t, err)
+	exists, _ := mockCache.Exists(ctx, "broken")
+	assert.Zero(t, exists)
+}
+
+// TestRememberCoalescesConcurrentMisses nhiều request cùng miss một key chỉ load 1 lần
+func TestRememberCoalescesConcurrentMisses(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(t *testing.T, remember func(loader func() ([]string, error)) ([]string, error)) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		loader := func() ([]string, error) {
+			calls.Add(1)
+			<-release
+			return []string{"alice"}, nil
+		}
+
+		const n = 20
+		results := make(chan []string, n)
+		for range n {
+			go func() {
+				value, err := remember(loader)
+				assert.NoError(t, err)
+				results <- value
+			}()
+		}
+		require.Eventually(t, func() bool { return calls.Load() == 1 }, time.Second, time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+
+		for range n {
+			assert.Equal(t, []string{"alice"}, <-results)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	}
+
+	t.Run("RememberAs", func(t *testing.T) {
+		mockCache := cache.NewMockCache()
+		run(t, func(loader func() ([]string, error)) ([]string, error) {
+			return cache.RememberAs(ctx, mockCache, "users:all", time.Minute, func(context.Context) ([]string, error) { return loader() })
+		})
+	})
+	t.Run("RememberStale", func(t *testing.T) {
+		mockCache := cache.NewMockCache()
+		run(t, func(loader func() ([]string, error)) ([]string, error) {
+			// TaggedCache tạo mới mỗi lần vẫn dùng chung singleflight với Cache gốc
+			return cache.RememberStale(ctx, mockCache.Tags("users"), "users:all", staleTestOptions, func(context.Context) ([]string, error) { return loader() })
+		})
+	})
+	t.Run("Remember", func(t *testing.T) {
+		mockCache := cache.NewMockCache()
+		run(t, func(loader func() ([]string, error)) ([]string, error) {
+			value, err := mockCache.Remember(ctx, "users:all", time.Minute, func() (interface{}, error) { return loader() })
+			users, _ := value.([]string)
+			return users, err
+		})
+	})
+}
+
+func TestRememberStaleEarlyRefresh(t *testing.T) {
+	c := clock.NewFrozen(time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC))
+	ctx := clock.WithContext(context.Background(), c)
+	mockCache := cache.NewMockCache()
+
+	var version atomic.Int32
+	loader := func(ctx context.Context) (int32, error) {
+		time.Sleep(5 * time.Millisecond) // Loader chậm: delta > 0
+		return version.Add(1), nil
+	}
+
+	// Tắt refresh sớm: còn TTL thì không refresh
+	value, err := cache.RememberStale(ctx, mockCache, "hot", staleTestOptions, loader)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), value)
+	c.Advance(50 * time.Second)
+	value, err = cache.RememberStale(ctx, mockCache, "hot", staleTestOptions, loader)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), value)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, int32(1), version.Load())
+
+	// Beta lớn: gần như chắc chắn refresh sớm ở background, request vẫn nhận giá trị còn hạn
+	early := staleTestOptions
+	early.EarlyRefreshBeta = 1e6
+	value, err = cache.RememberStale(ctx, mockCache, "hot", early, loader)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), value)
+	require.Eventually(t, func() bool { return version.Load() == 2 }, time.Second, 5*time.Millisecond)
+}
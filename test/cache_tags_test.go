@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaggedCacheFlush(t *testing.T) {
+	ctx := context.Background()
+	mockCache := cache.NewMockCache()
+
+	calls := 0
+	loader := func() (interface{}, error) {
+		calls++
+		return "users", nil
+	}
+	_, err := mockCache.Tags("users").Remember(ctx, "users:all", time.Minute, loader)
+	require.NoError(t, err)
+	require.NoError(t, mockCache.Tags("users").Set(ctx, "users:page:1", "page 1", time.Minute))
+	require.NoError(t, mockCache.Tags("users", "user:1").Set(ctx, "user:1:profile", "profile", time.Minute))
+	require.NoError(t, mockCache.Tags("user:2").Set(ctx, "user:2:profile", "profile", time.Minute))
+	require.NoError(t, mockCache.Set(ctx, "roles:all", "roles", time.Minute))
+
+	// Cache hit: không đăng ký lại, không gọi loader
+	_, err = mockCache.Tags("users").Remember(ctx, "users:all", time.Minute, loader)
+	require.NoError(t, err)
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, mockCache.Tags("users").Flush(ctx))
+	count, err := mockCache.Exists(ctx, "users:all", "users:page:1", "user:1:profile")
+	require.NoError(t, err)
+	assert.Zero(t, count, "mọi key gắn tag users bị xóa")
+	count, err = mockCache.Exists(ctx, "user:2:profile", "roles:all")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count, "key không gắn tag users vẫn còn")
+
+	_, err = mockCache.Tags("users").Remember(ctx, "users:all", time.Minute, loader)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	// Flush nhiều tag: hợp các key
+	require.NoError(t, mockCache.Tags("users").Tags("user:2").Flush(ctx))
+	count, err = mockCache.Exists(ctx, "users:all", "user:2:profile", "roles:all")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestTaggedCacheRememberStale(t *testing.T) {
+	ctx := context.Background()
+	mockCache := cache.NewMockCache()
+
+	calls := 0
+	loader := func(ctx context.Context) ([]string, error) {
+		calls++
+		return []string{"alice", "bob"}, nil
+	}
+	for range 2 {
+		value, err := cache.RememberStale(ctx, mockCache.Tags("users"), "users:all", staleTestOptions, loader)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob"}, value)
+	}
+	assert.Equal(t, 1, calls)
+
+	require.NoError(t, mockCache.Tags("users").Flush(ctx))
+	_, err := cache.RememberStale(ctx, mockCache.Tags("users"), "users:all", staleTestOptions, loader)
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls, "sau Flush phải load lại từ loader")
+
+	// Noop cache (không có Redis): Flush không lỗi
+	assert.NoError(t, cache.NewNoopCache().Tags("users").Flush(ctx))
+}
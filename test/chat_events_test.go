@@ -0,0 +1,196 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/app/chat"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (f *chatFixture) sync(t *testing.T, userID uuid.UUID, since int64, limit int) chat.SyncResponse {
+	t.Helper()
+	resp := f.service.SyncEvents(context.Background(), userID, since, limit)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	return resp.Data.(chat.SyncResponse)
+}
+
+func (f *chatFixture) sequences(t *testing.T) []int64 {
+	t.Helper()
+	var sequences []int64
+	require.NoError(t, f.db.Model(&model.ChatEvent{}).Order("sequence ASC").Pluck("sequence", &sequences).Error)
+	return sequences
+}
+
+func TestChatEventsProjectIntoMessages(t *testing.T) {
+	f := newChatFixture(t, chat.Config{EventSourcing: true})
+	alice := createAuthTestUser(t, f.db, "alice@example.com", true)
+	bob := createAuthTestUser(t, f.db, "bob@example.com", true)
+	conversationID := f.conversation(t, alice, bob)
+
+	sent := f.send(t, conversationID, alice, "hello")
+
+	// message.sent được ghi vào log và projection messages trong cùng transaction
+	var event model.ChatEvent
+	require.NoError(t, f.db.Where("message_id = ?", sent.ID).First(&event).Error)
+	assert.Equal(t, model.ChatEventMessageSent, event.EventType)
+	assert.Equal(t, "hello", event.Payload["content"])
+
+	stored, err := f.messages.FindByID(context.Background(), sent.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", stored.Content)
+	assert.Equal(t, alice.ID, stored.SenderID)
+
+	// Xóa phía mình và xóa cho mọi người cũng đi qua event
+	require.Equal(t, response.CodeDeleted, f.service.DeleteMessage(context.Background(), sent.ID, bob.ID, chat.DeleteScopeMe).Code)
+	deleted, err := f.messages.IsDeletedForUser(context.Background(), sent.ID, bob.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	require.Equal(t, response.CodeDeleted, f.service.DeleteMessage(context.Background(), sent.ID, alice.ID, chat.DeleteScopeEveryone).Code)
+	stored, err = f.messages.FindByID(context.Background(), sent.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.IsDeletedForEveryone())
+
+	require.Equal(t, response.CodeSuccess, f.service.RestoreMessage(context.Background(), sent.ID, alice.ID, chat.DeleteScopeEveryone).Code)
+	stored, err = f.messages.FindByID(context.Background(), sent.ID)
+	require.NoError(t, err)
+	assert.False(t, stored.IsDeletedForEveryone())
+	assert.Equal(t, "hello", stored.Content)
+
+	var count int64
+	require.NoError(t, f.db.Model(&model.ChatEvent{}).Where("message_id = ?", sent.ID).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+}
+
+func TestChatEventSequencesGapFree(t *testing.T) {
+	f := newChatFixture(t, chat.Config{EventSourcing: true})
+	alice := createAuthTestUser(t, f.db, "alice@example.com", true)
+	bob := createAuthTestUser(t, f.db, "bob@example.com", true)
+	conversationID := f.conversation(t, alice, bob)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(sender *model.User) {
+			defer wg.Done()
+			resp := f.service.SendMessage(context.Background(), conversationID, sender.ID, "hi", model.MessageTypeText, nil)
+			assert.Equal(t, response.CodeCreated, resp.Code)
+		}([]*model.User{alice, bob}[i%2])
+	}
+	wg.Wait()
+
+	sequences := f.sequences(t)
+	require.Len(t, sequences, 20)
+	for i := 1; i < len(sequences); i++ {
+		assert.Equal(t, sequences[i-1]+1, sequences[i])
+	}
+
+	// Sync theo từng trang không bỏ sót hay lặp event
+	var seen []int64
+	since := int64(0)
+	for {
+		page := f.sync(t, alice.ID, since, 7)
+		for _, e := range page.Events {
+			seen = append(seen, e.Sequence)
+		}
+		since = page.NextSince
+		if !page.HasMore {
+			break
+		}
+	}
+	assert.Equal(t, sequences, seen)
+	assert.Empty(t, f.sync(t, alice.ID, since, 7).Events)
+}
+
+func TestChatSyncReturnsOnlyCallerConversations(t *testing.T) {
+	f := newChatFixture(t, chat.Config{EventSourcing: true})
+	alice := createAuthTestUser(t, f.db, "alice@example.com", true)
+	bob := createAuthTestUser(t, f.db, "bob@example.com", true)
+	carol := createAuthTestUser(t, f.db, "carol@example.com", true)
+	ours := f.conversation(t, alice, bob)
+	theirs := f.conversation(t, bob, carol)
+
+	first := f.send(t, ours, alice, "for bob")
+	f.send(t, theirs, carol, "not for alice")
+	checkpoint := f.sync(t, alice.ID, 0, 100).NextSince
+
+	// Xóa phía bob chỉ bob nhận được
+	require.Equal(t, response.CodeDeleted, f.service.DeleteMessage(context.Background(), first.ID, bob.ID, chat.DeleteScopeMe).Code)
+	second := f.send(t, ours, bob, "reply")
+
+	all := f.sync(t, alice.ID, 0, 100)
+	require.Len(t, all.Events, 2)
+	for _, e := range all.Events {
+		assert.Equal(t, ours, e.ConversationID)
+		assert.Equal(t, model.ChatEventMessageSent, e.EventType)
+	}
+
+	// Delta từ checkpoint chỉ có event mới
+	delta := f.sync(t, alice.ID, checkpoint, 100)
+	require.Len(t, delta.Events, 1)
+	assert.Equal(t, second.ID, *delta.Events[0].MessageID)
+	assert.Greater(t, delta.NextSince, checkpoint)
+
+	// Bob thấy event xóa phía mình và cả 2 conversation
+	types := map[model.ChatEventType]int{}
+	conversations := map[uuid.UUID]bool{}
+	for _, e := range f.sync(t, bob.ID, 0, 100).Events {
+		types[e.EventType]++
+		conversations[e.ConversationID] = true
+	}
+	assert.Equal(t, 1, types[model.ChatEventMessageDeletedForUser])
+	assert.Len(t, conversations, 2)
+
+	// Carol không thấy conversation của alice và bob
+	for _, e := range f.sync(t, carol.ID, 0, 100).Events {
+		assert.Equal(t, theirs, e.ConversationID)
+	}
+
+	// Sync bị tắt khi không dùng event sourcing
+	disabled := newChatFixture(t, chat.Config{})
+	assert.Equal(t, response.CodeChatSyncDisabled, disabled.service.SyncEvents(context.Background(), alice.ID, 0, 10).Code)
+}
+
+func TestChatSyncRedactsMessagesDeletedForEveryone(t *testing.T) {
+	f := newChatFixture(t, chat.Config{EventSourcing: true})
+	alice := createAuthTestUser(t, f.db, "alice@example.com", true)
+	bob := createAuthTestUser(t, f.db, "bob@example.com", true)
+	conversationID := f.conversation(t, alice, bob)
+
+	secret := f.send(t, conversationID, alice, "secret")
+	kept := f.send(t, conversationID, alice, "kept")
+	require.Equal(t, response.CodeDeleted, f.service.DeleteMessage(context.Background(), secret.ID, alice.ID, chat.DeleteScopeEveryone).Code)
+
+	contents := map[uuid.UUID]interface{}{}
+	deletedEvent := false
+	for _, e := range f.sync(t, bob.ID, 0, 100).Events {
+		switch e.EventType {
+		case model.ChatEventMessageSent:
+			contents[*e.MessageID] = e.Payload["content"]
+		case model.ChatEventMessageDeletedForEveryone:
+			deletedEvent = *e.MessageID == secret.ID
+		}
+	}
+	assert.Equal(t, "", contents[secret.ID])
+	assert.Equal(t, "kept", contents[kept.ID])
+	assert.True(t, deletedEvent)
+
+	// Log gốc vẫn giữ nội dung để khôi phục được
+	var event model.ChatEvent
+	require.NoError(t, f.db.Where("message_id = ? AND event_type = ?", secret.ID, model.ChatEventMessageSent).First(&event).Error)
+	assert.Equal(t, "secret", event.Payload["content"])
+
+	require.Equal(t, response.CodeSuccess, f.service.RestoreMessage(context.Background(), secret.ID, alice.ID, chat.DeleteScopeEveryone).Code)
+	for _, e := range f.sync(t, bob.ID, 0, 100).Events {
+		if e.EventType == model.ChatEventMessageSent && *e.MessageID == secret.ID {
+			assert.Equal(t, "secret", e.Payload["content"])
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/chat"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newChatTestDB SQLite với schema tương đương migration của chat (users, conversations, messages, chat_events...)
+func newChatTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := newAuthTestDB(t)
+	// GetMessages cập nhật last_read_at trong goroutine, dùng 1 connection để mọi query thấy cùng database :memory:
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	for _, ddl := range []string{
+		`CREATE TABLE friendships (id TEXT PRIMARY KEY, user_id TEXT NOT NULL, friend_id TEXT NOT NULL,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE conversations (id TEXT PRIMARY KEY, type TEXT NOT NULL DEFAULT 'direct', name TEXT, avatar TEXT,
+			created_by TEXT, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE conversation_participants (id TEXT PRIMARY KEY, conversation_id TEXT NOT NULL, user_id TEXT NOT NULL,
+			last_read_at DATETIME, joined_at DATETIME, left_at DATETIME, cleared_at DATETIME,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE messages (id TEXT PRIMARY KEY, conversation_id TEXT NOT NULL, sender_id TEXT NOT NULL,
+			content TEXT NOT NULL, message_type TEXT DEFAULT 'text', reply_to_id TEXT, file_url TEXT, file_name TEXT,
+			file_size INTEGER, metadata TEXT, deleted_for_everyone_at DATETIME, deleted_for_everyone_by TEXT,
+			created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE message_deletions (id TEXT PRIMARY KEY, message_id TEXT NOT NULL, user_id TEXT NOT NULL,
+			created_at DATETIME, UNIQUE (message_id, user_id))`,
+		`CREATE TABLE chat_events (sequence INTEGER PRIMARY KEY AUTOINCREMENT, id TEXT NOT NULL UNIQUE,
+			conversation_id TEXT NOT NULL, message_id TEXT, event_type TEXT NOT NULL, actor_id TEXT NOT NULL,
+			visible_to TEXT, payload TEXT NOT NULL DEFAULT '{}', created_at DATETIME NOT NULL)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db
+}
+
+type chatFixture struct {
+	db       *gorm.DB
+	service  *chat.Service
+	messages repository.MessageRepository
+}
+
+func newChatFixture(t *testing.T, cfg chat.Config) *chatFixture {
+	t.Helper()
+	db := newChatTestDB(t)
+	messages := repository.NewMessageRepository(db)
+	service := chat.NewService(
+		repository.NewConversationRepository(db),
+		repository.NewConversationParticipantRepository(db),
+		messages,
+		repository.NewFriendshipRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewChatEventRepository(db),
+		nil, db, cfg,
+	)
+	return &chatFixture{db: db, service: service, messages: messages}
+}
+
+// conversation tạo direct conversation giữa các user
+func (f *chatFixture) conversation(t *testing.T, users ...*model.User) uuid.UUID {
+	t.Helper()
+	conversation := &model.Conversation{Type: model.ConversationTypeDirect, CreatedBy: &users[0].ID}
+	require.NoError(t, f.db.Create(conversation).Error)
+	for _, user := range users {
+		require.NoError(t, f.db.Create(&model.ConversationParticipant{ConversationID: conversation.ID, UserID: user.ID}).Error)
+	}
+	return conversation.ID
+}
+
+func (f *chatFixture) send(t *testing.T, conversationID uuid.UUID, sender *model.User, content string) *model.Message {
+	t.Helper()
+	resp := f.service.SendMessage(context.Background(), conversationID, sender.ID, content, model.MessageTypeText, nil)
+	require.Equal(t, response.CodeCreated, resp.Code)
+	message := resp.Data.(model.Message)
+	return &message
+}
+
+func (f *chatFixture) history(t *testing.T, conversationID, userID uuid.UUID) map[uuid.UUID]model.Message {
+	t.Helper()
+	resp := f.service.GetMessages(context.Background(), conversationID, userID, 1, 50)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	items := resp.Data.(map[string]interface{})["items"].([]model.Message)
+	byID := make(map[uuid.UUID]model.Message, len(items))
+	for _, m := range items {
+		byID[m.ID] = m
+	}
+	return byID
+}
+
+func (f *chatFixture) unread(t *testing.T, conversationID, userID uuid.UUID) int64 {
+	t.Helper()
+	count, err := f.messages.FindUnreadCount(context.Background(), conversationID, userID)
+	require.NoError(t, err)
+	return count
+}
+
+// lastMessage tin nhắn preview của conversation trong danh sách conversations của user
+func (f *chatFixture) lastMessage(t *testing.T, conversationID, userID uuid.UUID) *model.Message {
+	t.Helper()
+	resp := f.service.GetConversations(context.Background(), userID)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	for _, c := range resp.Data.([]model.Conversation) {
+		if c.ID == conversationID {
+			if len(c.Messages) == 0 {
+				return nil
+			}
+			return &c.Messages[0]
+		}
+	}
+	t.Fatalf("conversation %s not listed", conversationID)
+	return nil
+}
+
+func TestChatDeleteForMeVsEveryone(t *testing.T) {
+	f := newChatFixture(t, chat.Config{})
+	alice := createAuthTestUser(t, f.db, "alice@example.com", true)
+	bob := createAuthTestUser(t, f.db, "bob@example.com", true)
+	conversationID := f.conversation(t, alice, bob)
+
+	first := f.send(t, conversationID, alice, "hello")
+	time.Sleep(5 * time.Millisecond)
+	secret := f.send(t, conversationID, alice, "secret")
+	require.NoError(t, f.db.Model(&model.Message{}).Where("id = ?", secret.ID).Updates(map[string]interface{}{
+		"file_url":  "https://cdn.example.com/secret.pdf",
+		"file_name": "secret.pdf",
+		"file_size": 1024,
+	}).Error)
+	require.Equal(t, int64(2), f.unread(t, conversationID, bob.ID))
+
+	// Xóa cho riêng mình: chỉ ẩn phía alice
+	resp := f.service.DeleteMessage(context.Background(), first.ID, alice.ID, chat.DeleteScopeMe)
+	require.Equal(t, response.CodeDeleted, resp.Code)
+	assert.NotContains(t, f.history(t, conversationID, alice.ID), first.ID)
+	assert.Equal(t, "hello", f.history(t, conversationID, bob.ID)[first.ID].Content)
+	assert.Equal(t, int64(2), f.unread(t, conversationID, bob.ID))
+
+	// Chỉ người gửi được xóa cho mọi người
+	resp = f.service.DeleteMessage(context.Background(), secret.ID, bob.ID, chat.DeleteScopeEveryone)
+	assert.Equal(t, response.CodeNotMessageSender, resp.Code)
+
+	resp = f.service.DeleteMessage(context.Background(), secret.ID, alice.ID, chat.DeleteScopeEveryone)
+	require.Equal(t, response.CodeDeleted, resp.Code)
+	require.NoError(t, f.db.Model(&model.ConversationParticipant{}).
+		Where("conversation_id = ? AND user_id = ?", conversationID, bob.ID).Update("last_read_at", nil).Error)
+	assert.Equal(t, int64(1), f.unread(t, conversationID, bob.ID))
+
+	// Lịch sử của cả 2 bên, preview trong danh sách conversations và đọc trực tiếp đều là tombstone
+	for _, user := range []*model.User{alice, bob} {
+		tombstone, ok := f.history(t, conversationID, user.ID)[secret.ID]
+		require.True(t, ok, user.Email)
+		assert.NotNil(t, tombstone.DeletedForEveryoneAt)
+		assert.Empty(t, tombstone.Content)
+		assert.Nil(t, tombstone.FileURL)
+		assert.Nil(t, tombstone.FileName)
+		assert.Nil(t, tombstone.FileSize)
+
+		preview := f.lastMessage(t, conversationID, user.ID)
+		require.NotNil(t, preview, user.Email)
+		assert.Equal(t, secret.ID, preview.ID)
+		assert.Empty(t, preview.Content)
+		assert.Nil(t, preview.FileURL)
+		assert.Nil(t, preview.FileName)
+	}
+	stored, err := f.messages.FindByID(context.Background(), secret.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.Content)
+
+	// Khôi phục cho mọi người trả lại nội dung
+	resp = f.service.RestoreMessage(context.Background(), secret.ID, alice.ID, chat.DeleteScopeEveryone)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	assert.Equal(t, "secret", resp.Data.(*model.Message).Content)
+	assert.Equal(t, "secret", f.lastMessage(t, conversationID, bob.ID).Content)
+}
+
+func TestChatReplyToDeletedMessage(t *testing.T) {
+	f := newChatFixture(t, chat.Config{})
+	alice := createAuthTestUser(t, f.db, "alice@example.com", true)
+	bob := createAuthTestUser(t, f.db, "bob@example.com", true)
+	conversationID := f.conversation(t, alice, bob)
+
+	original := f.send(t, conversationID, alice, "original")
+	resp := f.service.SendMessage(context.Background(), conversationID, bob.ID, "reply", model.MessageTypeText, &original.ID)
+	require.Equal(t, response.CodeCreated, resp.Code)
+	reply := resp.Data.(model.Message)
+
+	require.Equal(t, response.CodeDeleted, f.service.DeleteMessage(context.Background(), original.ID, alice.ID, chat.DeleteScopeEveryone).Code)
+
+	// Tin nhắn reply vẫn hiện, phần trích dẫn là tombstone
+	quoted := f.history(t, conversationID, bob.ID)[reply.ID].ReplyTo
+	require.NotNil(t, quoted)
+	assert.Empty(t, quoted.Content)
+
+	// Không reply được tin nhắn đã xóa cho mọi người
+	resp = f.service.SendMessage(context.Background(), conversationID, bob.ID, "again", model.MessageTypeText, &original.ID)
+	assert.Equal(t, response.CodeMessageNotFound, resp.Code)
+}
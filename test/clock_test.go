@@ -0,0 +1,90 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrozenClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 22, 0, 0, 0, time.UTC)
+	c := clock.NewFrozen(start)
+
+	assert.Equal(t, start, c.Now())
+
+	c.Advance(90 * time.Minute)
+	assert.Equal(t, start.Add(90*time.Minute), c.Now())
+	assert.Equal(t, 90*time.Minute, c.Since(start))
+	assert.Equal(t, -90*time.Minute, c.Until(start))
+
+	c.Set(start)
+	assert.Equal(t, start, c.Now())
+}
+
+func TestClockContextAndDefault(t *testing.T) {
+	frozen := clock.NewFrozen(time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC))
+
+	// Context không có clock thì dùng clock mặc định
+	assert.WithinDuration(t, time.Now(), clock.FromContext(context.Background()).Now(), time.Second)
+
+	ctx := clock.WithContext(context.Background(), frozen)
+	assert.Equal(t, frozen.Now(), clock.FromContext(ctx).Now())
+
+	restore := clock.SetDefault(frozen)
+	assert.Equal(t, frozen.Now(), utils.Now())
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), utils.Today())
+	restore()
+
+	assert.WithinDuration(t, time.Now(), utils.Now(), time.Second)
+}
+
+func TestJWTExpiryWithFrozenClock(t *testing.T) {
+	c := clock.NewFrozen(time.Now())
+	manager := jwt.NewManager(jwt.Config{
+		SecretKey:           "test-secret",
+		AccessTokenDuration: 15 * time.Minute,
+		Clock:               c,
+	})
+
+	token, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+
+	_, err = manager.VerifyToken(token)
+	require.NoError(t, err)
+	assert.False(t, manager.IsTokenExpired(token))
+
+	c.Advance(16 * time.Minute)
+	_, err = manager.VerifyToken(token)
+	assert.ErrorIs(t, err, jwt.ErrExpiredToken)
+	assert.True(t, manager.IsTokenExpired(token))
+}
+
+func TestOffsetClock(t *testing.T) {
+	base := clock.NewFrozen(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := clock.NewOffset(base)
+	assert.Equal(t, base.Now(), c.Now())
+
+	c.Advance(24 * time.Hour)
+	assert.Equal(t, base.Now().Add(24*time.Hour), c.Now())
+
+	// Clock gốc tiếp tục chạy, độ lệch được giữ nguyên
+	base.Advance(time.Minute)
+	assert.Equal(t, base.Now().Add(24*time.Hour), c.Now())
+
+	target := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	c.Set(target)
+	assert.Equal(t, target, c.Now())
+	base.Advance(time.Second)
+	assert.Equal(t, target.Add(time.Second), c.Now())
+
+	c.Reset()
+	assert.Equal(t, base.Now(), c.Now())
+	assert.Zero(t, c.Offset())
+}
@@ -4,10 +4,10 @@ import (
 	"os"
 	"testing"
 
-	"api-core/config"
-	"api-core/pkg/cache"
-	"api-core/pkg/jwt"
-	"api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/storage"
 
 	"github.com/glebarez/sqlite"
 	"github.com/joho/godotenv"
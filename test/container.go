@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"testing"
 
-	"api-core/config"
-	"api-core/database"
-	"api-core/database/seeders"
-	"api-core/pkg/cache"
-	"api-core/pkg/jwt"
-	"api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/database"
+	"github.com/anhnq996/go-api-core/database/seeders"
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/storage"
 
 	"github.com/glebarez/sqlite"
 	postgrescontainer "github.com/testcontainers/testcontainers-go/modules/postgres"
@@ -168,7 +168,7 @@ func setupSQLiteFallback(t *testing.T, enableMigrate, enableSeeder bool) *TestCo
 		t.Log("⚠️  SQLite: Using GORM AutoMigrate instead of file-based migrations")
 		t.Log("⚠️  Note: Add your models here to enable auto-migration")
 		// Example:
-		// import model "api-core/internal/models"
+		// import model "github.com/anhnq996/go-api-core/internal/models"
 		// db.AutoMigrate(&model.User{}, &model.Role{}, &model.Permission{})
 	}
 
@@ -3,8 +3,8 @@ package test
 import (
 	"testing"
 
-	model "api-core/internal/models"
-	repository "api-core/internal/repositories"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
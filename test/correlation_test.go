@@ -0,0 +1,123 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/correlation"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// capturingLokiClient giữ event thay vì gửi lên Loki
+type capturingLokiClient struct {
+	events []actionEvent.Event
+}
+
+func (c *capturingLokiClient) PushEventAsync(ctx context.Context, job string, event actionEvent.Event) error {
+	c.events = append(c.events, event)
+	return nil
+}
+
+func TestCorrelationIDFromContext(t *testing.T) {
+	assert.Empty(t, correlation.FromContext(context.Background()))
+
+	// Request đi qua middleware.RequestID giữ ID của service gọi
+	var fromRequest string
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) { fromRequest = correlation.FromContext(r.Context()) })
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(correlation.Header, "upstream-42")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "upstream-42", fromRequest)
+
+	// Không có request ID: trace ID của span
+	traceID := trace.TraceID{0x0a, 0x0b}
+	spanCtx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{0x01},
+	}))
+	assert.Equal(t, traceID.String(), correlation.FromContext(spanCtx))
+	assert.Equal(t, "job-1", correlation.FromContext(correlation.WithID(spanCtx, "job-1")))
+	assert.Equal(t, spanCtx, correlation.WithID(spanCtx, ""))
+}
+
+func TestCorrelationHTTPClient(t *testing.T) {
+	received := make(chan string, 2)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get(correlation.Header)
+	}))
+	defer server.Close()
+
+	client := correlation.NewHTTPClient(5 * time.Second)
+	ctx := correlation.WithID(context.Background(), "req-1")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "req-1", <-received)
+	assert.Empty(t, req.Header.Get(correlation.Header)) // Request của caller không bị sửa
+
+	// Header tự đặt được giữ nguyên
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(correlation.Header, "explicit")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "explicit", <-received)
+}
+
+func TestCorrelationIDPropagatesToJobs(t *testing.T) {
+	queues := &listQueueManager{queues: map[string]*listQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1})
+
+	handled := make(chan string, 1)
+	manager.RegisterHandler("report.export", func(ctx context.Context, message *queue.Message) error {
+		handled <- correlation.FromContext(ctx)
+		return nil
+	}, workers.OnQueue("reports"))
+	require.NoError(t, manager.Start(context.Background()))
+	defer manager.Stop()
+
+	message, err := queue.NewJobMessage("report.export", map[string]string{"format": "xlsx"})
+	require.NoError(t, err)
+	reports, _ := queues.GetQueue("reports")
+	ctx := correlation.WithID(context.Background(), "req-7")
+	require.NoError(t, queue.NewProducer(reports).Publish(ctx, message))
+	assert.Equal(t, "req-7", message.Headers[queue.HeaderRequestID])
+
+	select {
+	case id := <-handled:
+		assert.Equal(t, "req-7", id)
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not handled")
+	}
+
+	// Header đã có (message đẩy tiếp từ job khác) được giữ nguyên
+	queue.InjectRequestID(correlation.WithID(context.Background(), "other"), message)
+	assert.Equal(t, "req-7", message.Headers[queue.HeaderRequestID])
+}
+
+func TestActionEventRequestID(t *testing.T) {
+	client := &capturingLokiClient{}
+	service := actionEvent.NewService(client)
+
+	ctx := correlation.WithID(context.Background(), "req-9")
+	require.NoError(t, service.LogCreate(ctx, "users", "user", "u1", "admin-1", map[string]interface{}{"name": "A"}))
+	require.Len(t, client.events, 1)
+	assert.Equal(t, "req-9", client.events[0].RequestID)
+}
@@ -0,0 +1,174 @@
+package test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/cronjob"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryCronControl cron.Control in-memory (RedisControl dùng chung giữa scheduler và API)
+type memoryCronControl struct {
+	mu       sync.Mutex
+	paused   map[string]bool
+	triggers []string
+	snapshot *cron.Snapshot
+}
+
+func newMemoryCronControl() *memoryCronControl {
+	return &memoryCronControl{paused: map[string]bool{}}
+}
+
+func (c *memoryCronControl) Pause(ctx context.Context, jobName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused[jobName] = true
+	return nil
+}
+func (c *memoryCronControl) Resume(ctx context.Context, jobName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.paused, jobName)
+	return nil
+}
+func (c *memoryCronControl) PausedJobs(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var names []string
+	for name := range c.paused {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+func (c *memoryCronControl) Trigger(ctx context.Context, jobName string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.triggers = append(c.triggers, jobName)
+	return nil
+}
+func (c *memoryCronControl) TakeTriggers(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	triggers := c.triggers
+	c.triggers = nil
+	return triggers, nil
+}
+func (c *memoryCronControl) PublishSnapshot(ctx context.Context, snapshot cron.Snapshot, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.snapshot = &snapshot
+	return nil
+}
+func (c *memoryCronControl) Snapshot(ctx context.Context) (*cron.Snapshot, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.snapshot == nil {
+		return nil, nil
+	}
+	snapshot := *c.snapshot
+	snapshot.Jobs = slices.Clone(c.snapshot.Jobs)
+	return &snapshot, nil
+}
+
+// nightlyJob chạy lúc 3h mỗi ngày, trong test chỉ chạy khi được trigger
+type nightlyJob struct {
+	runs atomic.Int64
+}
+
+func (j *nightlyJob) Name() string              { return "nightly" }
+func (j *nightlyJob) Schedule() string          { return "0 3 * * *" }
+func (j *nightlyJob) Timeout() time.Duration    { return time.Second }
+func (j *nightlyJob) RetryCount() int           { return 0 }
+func (j *nightlyJob) RetryDelay() time.Duration { return 0 }
+func (j *nightlyJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+	return nil
+}
+
+func TestCronJobAdminAPI(t *testing.T) {
+	ctx := context.Background()
+	control := newMemoryCronControl()
+	locks := cron.NewMemoryLockManager()
+
+	scheduler := cron.NewScheduler(locks, cron.Config{
+		Control:             control,
+		ControlSyncInterval: 20 * time.Millisecond,
+		Host:                "scheduler-1",
+	})
+	nightly, counting := &nightlyJob{}, &countingJob{}
+	require.NoError(t, scheduler.AddJob(nightly))
+	require.NoError(t, scheduler.AddJob(counting))
+
+	svc := cronjob.NewService(nil, control, locks)
+	assert.Equal(t, response.CodeSchedulerUnavailable, svc.ListJobs(ctx).Code, "chưa có scheduler công bố trạng thái")
+
+	require.NoError(t, scheduler.Start(ctx))
+	defer scheduler.Stop()
+
+	var resp *response.Response
+	require.Eventually(t, func() bool {
+		resp = svc.ListJobs(ctx)
+		return resp.Code == response.CodeSuccess
+	}, time.Second, 10*time.Millisecond)
+	snapshot := resp.Data.(*cron.Snapshot)
+	assert.Equal(t, "scheduler-1", snapshot.Host)
+	require.Len(t, snapshot.Jobs, 2)
+	assert.Equal(t, "counting", snapshot.Jobs[0].Name)
+	assert.Equal(t, "nightly", snapshot.Jobs[1].Name)
+	// Lần chạy kế tiếp theo lịch của từng job
+	assert.WithinDuration(t, time.Now(), snapshot.Jobs[0].NextRun, 2*time.Second)
+	next := snapshot.Jobs[1].NextRun.UTC()
+	assert.Equal(t, 3, next.Hour())
+	assert.Equal(t, 0, next.Minute())
+
+	assert.Equal(t, response.CodeCronJobNotFound, svc.GetJob(ctx, "missing").Code)
+	assert.Equal(t, 404, response.GetHTTPStatusCode(svc.TriggerJob(ctx, "missing").Code))
+
+	// Chạy ngay ngoài lịch
+	resp = svc.TriggerJob(ctx, "nightly")
+	require.Equal(t, response.CodeCronJobTriggered, resp.Code)
+	assert.Equal(t, 202, response.GetHTTPStatusCode(resp.Code))
+	require.Eventually(t, func() bool { return nightly.runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		job := svc.GetJob(ctx, "nightly").Data.(*cron.JobStatus)
+		return job.RunCount == 1 && !job.LastRun.IsZero()
+	}, time.Second, 10*time.Millisecond)
+
+	// Tạm dừng: bỏ qua lần chạy theo lịch, trigger vẫn chạy
+	resp = svc.SetPaused(ctx, "counting", true)
+	require.Equal(t, response.CodeUpdated, resp.Code)
+	assert.True(t, resp.Data.(*cron.JobStatus).IsPaused)
+	require.Eventually(t, func() bool {
+		status, err := scheduler.GetJobStatus("counting")
+		return err == nil && status.IsPaused
+	}, time.Second, 10*time.Millisecond)
+	runs := counting.runs.Load()
+	time.Sleep(1500 * time.Millisecond)
+	assert.Equal(t, runs, counting.runs.Load(), "job tạm dừng không chạy theo lịch")
+
+	require.Equal(t, response.CodeCronJobTriggered, svc.TriggerJob(ctx, "counting").Code)
+	require.Eventually(t, func() bool { return counting.runs.Load() == runs+1 }, time.Second, 10*time.Millisecond)
+
+	require.Equal(t, response.CodeUpdated, svc.SetPaused(ctx, "counting", false).Code)
+	require.Eventually(t, func() bool { return counting.runs.Load() > runs+1 }, 3*time.Second, 50*time.Millisecond)
+
+	// Job đang giữ lock (instance khác đang chạy) không trigger được
+	_, err := locks.AcquireLock(ctx, "nightly", time.Minute)
+	require.NoError(t, err)
+	job := svc.GetJob(ctx, "nightly").Data.(*cron.JobStatus)
+	assert.True(t, job.IsLocked)
+	assert.Equal(t, response.CodeCronJobRunning, svc.TriggerJob(ctx, "nightly").Code)
+
+	assert.ErrorIs(t, scheduler.TriggerJob("missing"), cron.ErrJobNotFound)
+	assert.ErrorIs(t, scheduler.PauseJob(ctx, "missing"), cron.ErrJobNotFound)
+}
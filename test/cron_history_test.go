@@ -0,0 +1,118 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/cronjob"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupCronJobRuns(t *testing.T) repository.CronJobRunRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	// Schema tương đương migration 000020
+	require.NoError(t, db.Exec(`CREATE TABLE cron_job_runs (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, job_name TEXT NOT NULL, started_at DATETIME NOT NULL,
+		finished_at DATETIME NOT NULL, duration_ms INTEGER NOT NULL DEFAULT 0, success BOOLEAN NOT NULL,
+		error TEXT, retries INTEGER NOT NULL DEFAULT 0, host TEXT NOT NULL DEFAULT '')`).Error)
+	return repository.NewCronJobRunRepository(db)
+}
+
+func TestSchedulerRecordsJobRuns(t *testing.T) {
+	runs := setupCronJobRuns(t)
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{ResultRecorder: runs, Host: "scheduler-1"})
+	require.NoError(t, scheduler.AddJob(&countingJob{}))
+	require.NoError(t, scheduler.AddJob(&failingJob{}))
+	require.NoError(t, scheduler.Start(context.Background()))
+
+	ctx := context.Background()
+	require.Eventually(t, func() bool {
+		latest, err := runs.LatestRuns(ctx)
+		return err == nil && len(latest) == 2
+	}, 3*time.Second, 50*time.Millisecond)
+	require.NoError(t, scheduler.Stop())
+
+	failed := false
+	history, _, err := runs.FindRuns(ctx, repository.CronJobRunFilter{JobName: "failing", Success: &failed}, 1, 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	run := history[0]
+	assert.False(t, run.Success)
+	require.NotNil(t, run.Error)
+	assert.Equal(t, "boom", *run.Error)
+	assert.Equal(t, 1, run.Retries)
+	assert.Equal(t, "scheduler-1", run.Host)
+	assert.False(t, run.FinishedAt.Before(run.StartedAt))
+}
+
+func TestCronJobRunHistoryAPI(t *testing.T) {
+	ctx := context.Background()
+	runs := setupCronJobRuns(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	record := func(job string, startedAgo time.Duration, success bool, errMsg string) {
+		require.NoError(t, runs.RecordResult(ctx, cron.JobResult{
+			JobName:   job,
+			StartTime: now.Add(-startedAgo),
+			EndTime:   now.Add(-startedAgo + 2*time.Second),
+			Duration:  2 * time.Second,
+			Success:   success,
+			Error:     errMsg,
+			Host:      "scheduler-1",
+		}))
+	}
+	record("cleanup-logs", 72*time.Hour, true, "")
+	record("cleanup-logs", 48*time.Hour, false, "disk full")
+	record("cleanup-logs", 24*time.Hour, true, "")
+	record("health-check", time.Hour, true, "")
+
+	svc := cronjob.NewService(runs, nil, nil)
+
+	resp := svc.LatestRuns(ctx)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	latest := resp.Data.([]model.CronJobRun)
+	require.Len(t, latest, 2)
+	assert.Equal(t, "cleanup-logs", latest[0].JobName)
+	assert.True(t, latest[0].Success, "lần chạy gần nhất")
+	assert.Equal(t, int64(2000), latest[0].DurationMs)
+
+	// Lần chạy lỗi của cleanup-logs trong 3 ngày gần đây
+	failed := false
+	resp = svc.ListRuns(ctx, &cronjob.ListRunsRequest{Page: 1, PerPage: 10, Filter: repository.CronJobRunFilter{
+		JobName: "cleanup-logs",
+		Success: &failed,
+		From:    now.Add(-60 * time.Hour),
+	}})
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	assert.Equal(t, int64(1), resp.Meta.Total)
+	items := resp.Data.(map[string]interface{})["items"].([]model.CronJobRun)
+	require.Len(t, items, 1)
+	assert.Equal(t, "disk full", *items[0].Error)
+
+	resp = svc.GetRun(ctx, items[0].ID)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	resp = svc.GetRun(ctx, 9999)
+	assert.Equal(t, response.CodeCronJobRunNotFound, resp.Code)
+	assert.Equal(t, 404, response.GetHTTPStatusCode(resp.Code))
+
+	// Mới nhất trước, phân trang
+	resp = svc.ListRuns(ctx, &cronjob.ListRunsRequest{Page: 2, PerPage: 3})
+	assert.Equal(t, int64(4), resp.Meta.Total)
+	items = resp.Data.(map[string]interface{})["items"].([]model.CronJobRun)
+	require.Len(t, items, 1)
+	assert.Equal(t, now.Add(-72*time.Hour), items[0].StartedAt.UTC())
+
+	deleted, err := runs.PruneBefore(ctx, now.Add(-36*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
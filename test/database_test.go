@@ -3,7 +3,7 @@ package test
 import (
 	"testing"
 
-	model "api-core/internal/models"
+	model "github.com/anhnq996/go-api-core/internal/models"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
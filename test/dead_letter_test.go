@@ -0,0 +1,259 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listQueue queue in-memory xem được message (queue.Browser), pop từ đầu slice
+type listQueue struct {
+	name     string
+	mu       sync.Mutex
+	messages []*queue.Message
+}
+
+func (q *listQueue) Push(ctx context.Context, m *queue.Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.messages = append(q.messages, m)
+	return nil
+}
+func (q *listQueue) Pop(ctx context.Context) (*queue.Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.messages) == 0 {
+		return nil, nil
+	}
+	m := q.messages[0]
+	q.messages = q.messages[1:]
+	return m, nil
+}
+func (q *listQueue) PushBatch(ctx context.Context, messages []*queue.Message) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.messages = append(q.messages, messages...)
+	return nil
+}
+func (q *listQueue) PopBatch(ctx context.Context, max int) ([]*queue.Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	n := min(max, len(q.messages))
+	messages := q.messages[:n:n]
+	q.messages = q.messages[n:]
+	return messages, nil
+}
+func (q *listQueue) PopWithTimeout(ctx context.Context, timeout time.Duration) (*queue.Message, error) {
+	if m, _ := q.Pop(ctx); m != nil {
+		return m, nil
+	}
+	select {
+	case <-time.After(10 * time.Millisecond):
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+func (q *listQueue) Browse(ctx context.Context, offset, limit int) ([]*queue.Message, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if offset >= len(q.messages) {
+		return nil, nil
+	}
+	return append([]*queue.Message(nil), q.messages[offset:min(offset+limit, len(q.messages))]...), nil
+}
+func (q *listQueue) Peek(ctx context.Context) (*queue.Message, error) { return nil, nil }
+func (q *listQueue) Size(ctx context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(len(q.messages)), nil
+}
+func (q *listQueue) Clear(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.messages = nil
+	return nil
+}
+func (q *listQueue) Close() error    { return nil }
+func (q *listQueue) GetName() string { return q.name }
+
+type listQueueManager struct {
+	mu     sync.Mutex
+	queues map[string]*listQueue
+}
+
+func (m *listQueueManager) CreateQueue(ctx context.Context, name string, options *queue.QueueOptions) (queue.Queue, error) {
+	return m.GetQueue(name)
+}
+func (m *listQueueManager) GetQueue(name string) (queue.Queue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if q, ok := m.queues[name]; ok {
+		return q, nil
+	}
+	q := &listQueue{name: name}
+	m.queues[name] = q
+	return q, nil
+}
+func (m *listQueueManager) DeleteQueue(ctx context.Context, name string) error { return nil }
+func (m *listQueueManager) ListQueues(ctx context.Context) ([]string, error)   { return nil, nil }
+func (m *listQueueManager) Close() error                                       { return nil }
+
+func TestWorkerMovesFailedMessagesToDeadQueue(t *testing.T) {
+	queues := &listQueueManager{queues: map[string]*listQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, MaxRetries: 2, RetryDelay: time.Millisecond})
+
+	var mu sync.Mutex
+	attempts := map[string]int{}
+	fail := true
+	manager.RegisterHandler("report.export", func(ctx context.Context, message *queue.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts[message.ID]++
+		if !fail {
+			return nil
+		}
+		if string(message.Data) == `"bad"` {
+			return queue.Permanent(errors.New("invalid payload"))
+		}
+		return errors.New("storage unavailable")
+	}, workers.OnQueue("reports"))
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+
+	flaky, err := queue.NewJobMessage("report.export", "flaky")
+	require.NoError(t, err)
+	bad, err := queue.NewJobMessage("report.export", "bad")
+	require.NoError(t, err)
+	reports, _ := queues.GetQueue("reports")
+	require.NoError(t, reports.Push(ctx, flaky))
+	require.NoError(t, reports.Push(ctx, bad))
+
+	dead := queue.NewDeadLetterManager(queues)
+	assert.Eventually(t, func() bool {
+		count, _ := dead.Count(ctx, "reports")
+		return count == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Hết lượt retry và lỗi Permanent đều vào dead queue, giữ lý do lỗi
+	messages, err := dead.List(ctx, "reports", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+	byID := map[string]*queue.Message{messages[0].ID: messages[0], messages[1].ID: messages[1]}
+	assert.Equal(t, "storage unavailable", queue.DeadReason(byID[flaky.ID]))
+	assert.Equal(t, "3", byID[flaky.ID].Headers[queue.HeaderDeadAttempts])
+	assert.Equal(t, "reports", byID[flaky.ID].Headers[queue.HeaderDeadQueue])
+	assert.Equal(t, "report.export", byID[flaky.ID].Headers[queue.HeaderJobType])
+	assert.True(t, strings.Contains(queue.DeadReason(byID[bad.ID]), "invalid payload"))
+	assert.Equal(t, "1", byID[bad.ID].Headers[queue.HeaderDeadAttempts])
+
+	page, err := dead.List(ctx, "reports", 1, 10)
+	require.NoError(t, err)
+	assert.Len(t, page, 1)
+
+	// Requeue theo ID: xử lý lại từ đầu, message còn lại giữ nguyên trong dead queue
+	mu.Lock()
+	fail = false
+	mu.Unlock()
+	moved, err := dead.Requeue(ctx, "reports", flaky.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts[flaky.ID] == 4
+	}, 2*time.Second, 10*time.Millisecond)
+
+	remaining, err := dead.List(ctx, "reports", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, bad.ID, remaining[0].ID)
+
+	removed, err := dead.Purge(ctx, "reports")
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	count, err := dead.Count(ctx, "reports")
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	require.NoError(t, manager.Stop())
+}
+
+func TestDeadLetterRequeueRevivesMessage(t *testing.T) {
+	queues := &listQueueManager{queues: map[string]*listQueue{}}
+	ctx := context.Background()
+	deadQueue, _ := queues.GetQueue(queue.DeadQueueName("emails"))
+	assert.Equal(t, "emails:dead", deadQueue.GetName())
+	assert.True(t, queue.IsDeadQueue(deadQueue.GetName()))
+
+	require.NoError(t, deadQueue.Push(ctx, &queue.Message{ID: "a", RetryCount: 4, Headers: map[string]string{
+		queue.HeaderJobType: "email.send", queue.HeaderDeadReason: "smtp down", queue.HeaderDeadAttempts: "5",
+	}}))
+	require.NoError(t, deadQueue.Push(ctx, &queue.Message{ID: "b"}))
+
+	dead := queue.NewDeadLetterManager(queues)
+	moved, err := dead.Requeue(ctx, "emails")
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	emails, _ := queues.GetQueue("emails")
+	message, err := emails.Pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "a", message.ID)
+	assert.Zero(t, message.RetryCount)
+	assert.Equal(t, map[string]string{queue.HeaderJobType: "email.send"}, message.Headers)
+
+	_, err = queue.NewDeadLetterManager(&chanQueueManager{queues: map[string]*chanQueue{}}).List(ctx, "emails", 0, 10)
+	assert.ErrorIs(t, err, queue.ErrBrowseNotSupported)
+}
+
+// batchQueue listQueue đếm số lần gọi backend
+type batchQueue struct {
+	*listQueue
+	pushes, batches int
+}
+
+func (q *batchQueue) Push(ctx context.Context, m *queue.Message) error {
+	q.pushes++
+	return q.listQueue.Push(ctx, m)
+}
+func (q *batchQueue) PushBatch(ctx context.Context, messages []*queue.Message) error {
+	q.batches++
+	return q.listQueue.PushBatch(ctx, messages)
+}
+
+func TestProducerPublishBatchUsesSingleRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	q := &batchQueue{listQueue: &listQueue{name: "events"}}
+
+	messages := make([]*queue.Message, 0, 5)
+	for i := range 5 {
+		messages = append(messages, &queue.Message{ID: fmt.Sprintf("m%d", i)})
+	}
+	require.NoError(t, queue.NewProducer(q).PublishBatch(ctx, messages))
+	assert.Equal(t, 1, q.batches)
+	assert.Zero(t, q.pushes)
+
+	// PopBatch giữ thứ tự, không chờ khi queue hết message
+	batch, err := q.PopBatch(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, batch, 3)
+	assert.Equal(t, []string{"m0", "m1", "m2"}, []string{batch[0].ID, batch[1].ID, batch[2].ID})
+	batch, err = q.PopBatch(ctx, 3)
+	require.NoError(t, err)
+	assert.Len(t, batch, 2)
+	batch, err = q.PopBatch(ctx, 3)
+	require.NoError(t, err)
+	assert.Empty(t, batch)
+}
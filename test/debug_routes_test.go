@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/internal/routes"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	middlewarePkg "github.com/anhnq996/go-api-core/pkg/middleware"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIPNets(t *testing.T) {
+	nets, err := utils.ParseIPNets([]string{"10.0.0.0/8", " 127.0.0.1 ", "", "::1"})
+	require.NoError(t, err)
+	require.Len(t, nets, 3)
+	assert.Equal(t, "127.0.0.1/32", nets[1].String())
+	assert.Equal(t, "::1/128", nets[2].String())
+
+	_, err = utils.ParseIPNets([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = utils.ParseIPNets([]string{"localhost"})
+	assert.Error(t, err)
+
+	cfg := &config.DebugConfig{AllowedIPs: []string{"not-an-ip"}}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestIPAllowlist(t *testing.T) {
+	nets, err := utils.ParseIPNets([]string{"10.0.0.0/8", "192.168.1.5"})
+	require.NoError(t, err)
+
+	serve := func(mw func(http.Handler) http.Handler, remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/debug/vars", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	direct := middlewarePkg.IPAllowlist(nets, false)
+	assert.Equal(t, http.StatusOK, serve(direct, "10.1.2.3:5000", ""))
+	assert.Equal(t, http.StatusOK, serve(direct, "192.168.1.5:5000", ""))
+	assert.Equal(t, http.StatusForbidden, serve(direct, "192.168.1.6:5000", ""))
+	// Không trust proxy: X-Forwarded-For do client đặt bị bỏ qua
+	assert.Equal(t, http.StatusForbidden, serve(direct, "203.0.113.7:5000", "10.0.0.1"))
+
+	proxied := middlewarePkg.IPAllowlist(nets, true)
+	assert.Equal(t, http.StatusOK, serve(proxied, "172.16.0.2:5000", "10.0.0.1, 172.16.0.2"))
+	assert.Equal(t, http.StatusForbidden, serve(proxied, "10.0.0.2:5000", "203.0.113.7"))
+
+	// Danh sách rỗng: không giới hạn
+	assert.Equal(t, http.StatusOK, serve(middlewarePkg.IPAllowlist(nil, false), "203.0.113.7:5000", ""))
+}
+
+func TestDebugRoutesRequirePermission(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret", AccessTokenDuration: time.Hour})
+	c := &routes.Controllers{
+		JWTManager:   manager,
+		JWTBlacklist: jwt.NewBlacklist(cache.NewMockCache()),
+		Permissions: jwt.NewPermissionChecker(cache.NewMockCache(), func(ctx context.Context, userID string) ([]string, error) {
+			if userID == "admin-1" {
+				return []string{routes.DebugPermission}, nil
+			}
+			return []string{"users.view"}, nil
+		}, 0),
+		Policies: routes.Policies{routes.GroupAdmin: {RequireAuth: true}},
+	}
+
+	nets, err := utils.ParseIPNets([]string{"127.0.0.1"})
+	require.NoError(t, err)
+	r := chi.NewRouter()
+	routes.RegisterDebugRoutes(r, c, nets, false)
+
+	adminToken, err := manager.GenerateToken("admin-1", "admin@example.com", "admin", nil)
+	require.NoError(t, err)
+	userToken, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+
+	do := func(path, token, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, do("/debug/vars", "", "127.0.0.1:4000").Code)
+	assert.Equal(t, http.StatusForbidden, do("/debug/vars", userToken, "127.0.0.1:4000").Code)
+	assert.Equal(t, http.StatusForbidden, do("/debug/vars", adminToken, "10.0.0.1:4000").Code)
+
+	rec := do("/debug/vars", adminToken, "127.0.0.1:4000")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"memstats"`)
+	assert.Contains(t, rec.Body.String(), `"goroutines"`)
+
+	rec = do("/debug/pprof/heap?debug=1", adminToken, "127.0.0.1:4000")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "heap profile")
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupDeviceTokens(t *testing.T) repository.DeviceTokenRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	// Schema tương đương migration 000021 (sqlite không có gen_random_uuid)
+	require.NoError(t, db.Exec(`CREATE TABLE device_tokens (
+		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))), user_id TEXT NOT NULL, token TEXT NOT NULL UNIQUE,
+		platform TEXT NOT NULL, last_seen DATETIME NOT NULL, created_at DATETIME, updated_at DATETIME)`).Error)
+	return repository.NewDeviceTokenRepository(db)
+}
+
+func TestDeviceTokenRegisterAndUnregister(t *testing.T) {
+	ctx := context.Background()
+	repo := setupDeviceTokens(t)
+	svc := device.NewService(repo)
+	alice, bob := uuid.New(), uuid.New()
+
+	resp := svc.Register(ctx, alice, device.RegisterDeviceRequest{Token: "token-1", Platform: model.DevicePlatformAndroid})
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	resp = svc.Register(ctx, alice, device.RegisterDeviceRequest{Token: "token-2", Platform: model.DevicePlatformIOS})
+	require.Equal(t, response.CodeSuccess, resp.Code)
+
+	devices, err := repo.FindByUserID(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	assert.Equal(t, "token-2", devices[0].Token, "thiết bị dùng gần nhất trước")
+
+	// Đăng nhập tài khoản khác trên cùng thiết bị: token chuyển sang bob, không tạo bản ghi mới
+	resp = svc.Register(ctx, bob, device.RegisterDeviceRequest{Token: "token-1", Platform: model.DevicePlatformAndroid})
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	devices, err = repo.FindByUserID(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, devices, 1)
+	count, err := repo.Count(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	// Chỉ gỡ được token của chính mình
+	resp = svc.Unregister(ctx, alice, "token-1")
+	assert.Equal(t, response.CodeDeviceNotFound, resp.Code)
+	assert.Equal(t, 404, response.GetHTTPStatusCode(resp.Code))
+	resp = svc.Unregister(ctx, bob, "token-1")
+	assert.Equal(t, response.CodeDeleted, resp.Code)
+	devices, err = repo.FindByUserID(ctx, bob)
+	require.NoError(t, err)
+	assert.Empty(t, devices)
+}
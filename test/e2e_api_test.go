@@ -0,0 +1,145 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	"github.com/anhnq996/go-api-core/internal/app/e2e"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupE2ERouter(t *testing.T, token string) (http.Handler, *gorm.DB, *clock.Offset, *jwt.Manager) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, model.RegisterIDGenerator(db, model.IDConfig{DefaultVersion: utils.UUIDv7}))
+	// Schema tương đương migration 000001-000004 (SQLite không có gen_random_uuid, ID sinh ở callback)
+	for _, ddl := range []string{
+		`CREATE TABLE roles (id TEXT PRIMARY KEY, name TEXT NOT NULL UNIQUE, display_name TEXT NOT NULL,
+			description TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE permissions (id TEXT PRIMARY KEY, name TEXT NOT NULL UNIQUE, display_name TEXT NOT NULL,
+			description TEXT, module TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE role_has_permissions (role_id TEXT NOT NULL, permission_id TEXT NOT NULL, created_at DATETIME,
+			PRIMARY KEY (role_id, permission_id))`,
+		`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL UNIQUE, password TEXT,
+			avatar TEXT, role_id TEXT, email_verified_at DATETIME, is_active BOOLEAN DEFAULT true, last_login_at DATETIME,
+			token_version INTEGER NOT NULL DEFAULT 0, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+
+	cacheClient := cache.NewMockCache()
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret-key-min-32-chars-long"})
+	userRepo := repository.NewUserRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	authService := auth.NewService(userRepo, nil, manager, nil, nil, cacheClient, nil, nil, roleRepo, nil, nil)
+
+	offset := clock.NewOffset(clock.New())
+	svc := e2e.NewService(db, cacheClient, userRepo, roleRepo, authService, e2e.Config{Enabled: true, Token: token, Clock: offset})
+
+	r := chi.NewRouter()
+	e2e.RegisterRoutes(r, e2e.NewHandler(svc))
+	return r, db, offset, manager
+}
+
+func e2eRequest(t *testing.T, h http.Handler, method, path, token string, body interface{}) (*httptest.ResponseRecorder, map[string]interface{}) {
+	var buf bytes.Buffer
+	if body != nil {
+		require.NoError(t, json.NewEncoder(&buf).Encode(body))
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set(e2e.TokenHeader, token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var out map[string]interface{}
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec, out
+}
+
+func TestE2EResetSeedsDatabase(t *testing.T) {
+	h, db, offset, _ := setupE2ERouter(t, "")
+
+	require.NoError(t, db.Create(&model.User{Name: "Leftover", Email: "leftover@example.com", Password: "x", IsActive: true}).Error)
+	offset.Advance(48 * time.Hour)
+
+	rec, _ := e2eRequest(t, h, http.MethodPost, "/__e2e/reset", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var count int64
+	db.Model(&model.User{}).Where("email = ?", "leftover@example.com").Count(&count)
+	assert.Zero(t, count)
+	db.Model(&model.User{}).Where("email = ?", "admin@example.com").Count(&count)
+	assert.EqualValues(t, 1, count)
+	assert.Zero(t, offset.Offset())
+}
+
+func TestE2ECreateFixtureUser(t *testing.T) {
+	h, _, _, manager := setupE2ERouter(t, "")
+
+	rec, _ := e2eRequest(t, h, http.MethodPost, "/__e2e/reset", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec, body := e2eRequest(t, h, http.MethodPost, "/__e2e/users", "", map[string]string{"role": "moderator"})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, "Password123!", data["password"])
+	claims, err := manager.VerifyToken(data["access_token"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, "moderator", claims.Role)
+	assert.Contains(t, claims.Email, "@example.test")
+
+	rec, _ = e2eRequest(t, h, http.MethodPost, "/__e2e/users", "", map[string]string{"role": "ghost"})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestE2EClockFastForward(t *testing.T) {
+	h, _, offset, _ := setupE2ERouter(t, "")
+
+	rec, _ := e2eRequest(t, h, http.MethodPost, "/__e2e/clock", "", map[string]string{"advance": "24h"})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), offset.Now(), time.Second)
+
+	target := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	rec, _ = e2eRequest(t, h, http.MethodPost, "/__e2e/clock", "", map[string]interface{}{"time": target})
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.WithinDuration(t, target, offset.Now(), time.Second)
+
+	rec, _ = e2eRequest(t, h, http.MethodPost, "/__e2e/clock", "", map[string]string{"advance": "soon"})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec, _ = e2eRequest(t, h, http.MethodDelete, "/__e2e/clock", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Zero(t, offset.Offset())
+}
+
+func TestE2ERequiresToken(t *testing.T) {
+	h, _, _, _ := setupE2ERouter(t, "e2e-secret")
+
+	rec, _ := e2eRequest(t, h, http.MethodGet, "/__e2e/clock", "", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec, _ = e2eRequest(t, h, http.MethodGet, "/__e2e/clock", "wrong", nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec, _ = e2eRequest(t, h, http.MethodGet, "/__e2e/clock", "e2e-secret", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
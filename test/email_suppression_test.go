@@ -0,0 +1,310 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/app/webhook"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const emailWebhookToken = "email-webhook-token"
+
+type emailWebhookFixture struct {
+	router http.Handler
+	repo   repository.EmailSuppressionRepository
+}
+
+func newEmailWebhookFixture(t *testing.T, token string) *emailWebhookFixture {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE email_suppressions (id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+		email TEXT NOT NULL UNIQUE, reason TEXT NOT NULL, source TEXT NOT NULL, details TEXT,
+		created_at DATETIME, updated_at DATETIME)`).Error)
+
+	repo := repository.NewEmailSuppressionRepository(db)
+	r := chi.NewRouter()
+	webhook.RegisterRoutes(r, webhook.NewHandler(webhook.NewService(repo, webhook.Config{EmailToken: token}, safehttp.New(safehttp.Config{}))))
+	return &emailWebhookFixture{router: r, repo: repo}
+}
+
+func (f *emailWebhookFixture) post(path, token, body string) *httptest.ResponseRecorder {
+	target := path
+	if token != "" {
+		target += "?token=" + token
+	}
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, target, strings.NewReader(body)))
+	return rec
+}
+
+// suppression lý do suppress của email, rỗng nếu không bị suppress
+func (f *emailWebhookFixture) suppression(t *testing.T, address string) string {
+	t.Helper()
+	found, err := f.repo.FindByEmail(context.Background(), address)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ""
+	}
+	require.NoError(t, err)
+	return found.Reason
+}
+
+// snsNotification bọc SES notification trong SNS envelope
+func snsNotification(t *testing.T, notification map[string]interface{}) string {
+	t.Helper()
+	message, err := json.Marshal(notification)
+	require.NoError(t, err)
+	body, err := json.Marshal(map[string]string{"Type": "Notification", "Message": string(message)})
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestEmailWebhookSES(t *testing.T) {
+	cases := []struct {
+		name         string
+		notification map[string]interface{}
+		want         map[string]string // email -> reason, "" = không suppress
+	}{
+		{
+			name: "permanent bounce",
+			notification: map[string]interface{}{
+				"notificationType": "Bounce",
+				"bounce": map[string]interface{}{
+					"bounceType": "Permanent", "bounceSubType": "General",
+					"bouncedRecipients": []map[string]string{
+						{"emailAddress": "Gone@Example.com", "diagnosticCode": "550 5.1.1 user unknown"},
+						{"emailAddress": "also-gone@example.com"},
+					},
+				},
+			},
+			want: map[string]string{"gone@example.com": model.SuppressionReasonHardBounce, "also-gone@example.com": model.SuppressionReasonHardBounce},
+		},
+		{
+			name: "transient bounce",
+			notification: map[string]interface{}{
+				"notificationType": "Bounce",
+				"bounce": map[string]interface{}{
+					"bounceType": "Transient", "bounceSubType": "MailboxFull",
+					"bouncedRecipients": []map[string]string{{"emailAddress": "full@example.com"}},
+				},
+			},
+			want: map[string]string{"full@example.com": ""},
+		},
+		{
+			name: "complaint",
+			notification: map[string]interface{}{
+				"notificationType": "Complaint",
+				"complaint": map[string]interface{}{
+					"complaintFeedbackType": "abuse",
+					"complainedRecipients":  []map[string]string{{"emailAddress": "angry@example.com"}},
+				},
+			},
+			want: map[string]string{"angry@example.com": model.SuppressionReasonComplaint},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newEmailWebhookFixture(t, emailWebhookToken)
+			rec := f.post("/webhooks/email/ses", emailWebhookToken, snsNotification(t, c.notification))
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			for address, reason := range c.want {
+				assert.Equal(t, reason, f.suppression(t, address), address)
+			}
+		})
+	}
+}
+
+func TestEmailWebhookSendGrid(t *testing.T) {
+	cases := []struct {
+		name  string
+		event map[string]string
+		want  string
+	}{
+		{"bounce", map[string]string{"email": "bounce@example.com", "event": "bounce", "type": "bounce", "status": "5.1.1"}, model.SuppressionReasonHardBounce},
+		{"blocked", map[string]string{"email": "blocked@example.com", "event": "bounce", "type": "blocked", "status": "4.0.0"}, ""},
+		{"spamreport", map[string]string{"email": "spam@example.com", "event": "spamreport"}, model.SuppressionReasonComplaint},
+		{"delivered", map[string]string{"email": "ok@example.com", "event": "delivered"}, ""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newEmailWebhookFixture(t, emailWebhookToken)
+			body, err := json.Marshal([]map[string]string{c.event})
+			require.NoError(t, err)
+			rec := f.post("/webhooks/email/sendgrid", emailWebhookToken, string(body))
+			require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+			assert.Equal(t, c.want, f.suppression(t, c.event["email"]))
+		})
+	}
+}
+
+func TestEmailWebhookToken(t *testing.T) {
+	body := `[{"email":"spam@example.com","event":"spamreport"}]`
+	cases := []struct {
+		name       string
+		configured string
+		token      string
+	}{
+		{"missing token", emailWebhookToken, ""},
+		{"wrong token", emailWebhookToken, "guess"},
+		{"webhook disabled", "", ""},
+		{"webhook disabled with token", "", "anything"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := newEmailWebhookFixture(t, c.configured)
+			for _, path := range []string{"/webhooks/email/ses", "/webhooks/email/sendgrid"} {
+				rec := f.post(path, c.token, body)
+				assert.Equal(t, http.StatusUnauthorized, rec.Code, path)
+				assert.Contains(t, rec.Body.String(), response.CodeWebhookUnauthorized, path)
+			}
+			assert.Empty(t, f.suppression(t, "spam@example.com"))
+		})
+	}
+}
+
+func TestEmailWebhookSNSSubscribeURLAllowlist(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	f := newEmailWebhookFixture(t, emailWebhookToken)
+	for _, subscribeURL := range []string{
+		server.URL + "/confirm",
+		"http://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription",
+		"https://sns.us-east-1.amazonaws.com.evil.example/?Action=ConfirmSubscription",
+		"https://evil.example/sns.us-east-1.amazonaws.com",
+		"https://sns.us-east-1.amazonaws.com:8443/?Action=ConfirmSubscription",
+		"https://user@169.254.169.254/latest/meta-data",
+		"not a url",
+	} {
+		body, err := json.Marshal(map[string]string{"Type": "SubscriptionConfirmation", "SubscribeURL": subscribeURL})
+		require.NoError(t, err)
+		rec := f.post("/webhooks/email/ses", emailWebhookToken, string(body))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, subscribeURL)
+		assert.Contains(t, rec.Body.String(), response.CodeWebhookInvalidPayload, subscribeURL)
+	}
+	assert.Zero(t, calls.Load())
+}
+
+// suppressedChecker suppression list cố định, failing = lỗi khi kiểm tra
+type suppressedChecker struct {
+	suppressed map[string]bool
+	failing    bool
+}
+
+func (c *suppressedChecker) IsSuppressed(ctx context.Context, address string) (bool, error) {
+	if c.failing {
+		return false, errors.New("database unavailable")
+	}
+	return c.suppressed[address], nil
+}
+
+// capturingEmailService ghi lại message đã gửi
+type capturingEmailService struct {
+	sent []*email.EmailMessage
+}
+
+func (s *capturingEmailService) Send(message *email.EmailMessage) error {
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func (s *capturingEmailService) SendTemplate(message *email.EmailMessage, templatePath string, data interface{}) error {
+	return s.Send(message)
+}
+
+func TestSuppressionAwareEmailService(t *testing.T) {
+	checker := &suppressedChecker{suppressed: map[string]bool{
+		"to-bad@example.com": true, "cc-bad@example.com": true, "bcc-bad@example.com": true,
+	}}
+
+	cases := []struct {
+		name    string
+		message email.EmailMessage
+		want    *email.EmailMessage // nil = không gửi
+	}{
+		{
+			name: "drops suppressed to cc bcc",
+			message: email.EmailMessage{
+				To:  []string{"to-ok@example.com", "to-bad@example.com"},
+				CC:  []string{"cc-bad@example.com", "cc-ok@example.com"},
+				BCC: []string{"bcc-bad@example.com"},
+			},
+			want: &email.EmailMessage{
+				To:  []string{"to-ok@example.com"},
+				CC:  []string{"cc-ok@example.com"},
+				BCC: []string{},
+			},
+		},
+		{
+			name:    "only cc left",
+			message: email.EmailMessage{To: []string{"to-bad@example.com"}, CC: []string{"cc-ok@example.com"}},
+			want:    &email.EmailMessage{To: []string{}, CC: []string{"cc-ok@example.com"}},
+		},
+		{
+			name: "all suppressed",
+			message: email.EmailMessage{
+				To:  []string{"to-bad@example.com"},
+				CC:  []string{"cc-bad@example.com"},
+				BCC: []string{"bcc-bad@example.com"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for _, template := range []bool{false, true} {
+				inner := &capturingEmailService{}
+				service := email.NewSuppressionAwareService(inner, checker)
+				message := c.message
+
+				var err error
+				if template {
+					err = service.SendTemplate(&message, "welcome.html", nil)
+				} else {
+					err = service.Send(&message)
+				}
+
+				if c.want == nil {
+					assert.ErrorIs(t, err, email.ErrAllRecipientsSuppressed)
+					assert.Empty(t, inner.sent)
+					continue
+				}
+				require.NoError(t, err)
+				require.Len(t, inner.sent, 1)
+				assert.Equal(t, c.want.To, inner.sent[0].To)
+				assert.Equal(t, c.want.CC, inner.sent[0].CC)
+				assert.Equal(t, c.want.BCC, inner.sent[0].BCC)
+			}
+		})
+	}
+
+	// Lỗi khi kiểm tra suppression không chặn email giao dịch
+	inner := &capturingEmailService{}
+	service := email.NewSuppressionAwareService(inner, &suppressedChecker{failing: true})
+	require.NoError(t, service.Send(&email.EmailMessage{To: []string{"to-bad@example.com"}}))
+	assert.Len(t, inner.sent, 1)
+}
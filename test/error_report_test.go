@@ -0,0 +1,196 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/errorreport"
+	"github.com/anhnq996/go-api-core/pkg/exception"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/go-chi/chi/v5"
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter errorreport.Reporter ghi lại event
+type recordingReporter struct {
+	mu     sync.Mutex
+	events []*errorreport.Event
+}
+
+func (r *recordingReporter) Report(event *errorreport.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+func (r *recordingReporter) Flush(ctx context.Context) error { return nil }
+
+// withTag event có tag key=value
+func (r *recordingReporter) withTag(key, value string) []*errorreport.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []*errorreport.Event
+	for _, event := range r.events {
+		if event.Tags[key] == value {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+func TestSentryReporterReportsHandlerPanic(t *testing.T) {
+	envelopes := make(chan []string, 4)
+	var auth string
+	sentry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/42/envelope/", r.URL.Path)
+		auth = r.Header.Get("X-Sentry-Auth")
+		var lines []string
+		scanner := bufio.NewScanner(r.Body)
+		scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		envelopes <- lines
+	}))
+	defer sentry.Close()
+
+	reporter, err := errorreport.NewSentryReporter(errorreport.SentryConfig{
+		DSN:         strings.Replace(sentry.URL, "http://", "http://publickey@", 1) + "/42",
+		Environment: "staging",
+		Release:     "v1.2.3",
+	})
+	require.NoError(t, err)
+	errorreport.SetReporter(reporter)
+	defer errorreport.SetReporter(nil)
+
+	r := chi.NewRouter()
+	r.Use(chimiddleware.RequestID)
+	r.Use(errorreport.Middleware)
+	r.Use(exception.RecoveryMiddleware)
+	r.With(func(next http.Handler) http.Handler {
+		// Như JWT middleware sau khi verify token
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			errorreport.SetUser(r.Context(), "user-7")
+			next.ServeHTTP(w, r)
+		})
+	}).Get("/api/v1/orders/{id}", func(w http.ResponseWriter, r *http.Request) {
+		panic("order total is nil")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/9?token=secret", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, errorreport.Flush(ctx))
+
+	var lines []string
+	select {
+	case lines = <-envelopes:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event sent to Sentry")
+	}
+	assert.Contains(t, auth, "sentry_key=publickey")
+	require.Len(t, lines, 3) // Envelope header, item header, event
+
+	var event struct {
+		Level       string            `json:"level"`
+		Environment string            `json:"environment"`
+		Release     string            `json:"release"`
+		Transaction string            `json:"transaction"`
+		Tags        map[string]string `json:"tags"`
+		User        map[string]string `json:"user"`
+		Request     struct {
+			Method  string            `json:"method"`
+			URL     string            `json:"url"`
+			Headers map[string]string `json:"headers"`
+		} `json:"request"`
+		Exception struct {
+			Values []struct {
+				Type       string `json:"type"`
+				Value      string `json:"value"`
+				Stacktrace struct {
+					Frames []struct {
+						Function string `json:"function"`
+						InApp    bool   `json:"in_app"`
+					} `json:"frames"`
+				} `json:"stacktrace"`
+			} `json:"values"`
+		} `json:"exception"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[2]), &event))
+	assert.Equal(t, "fatal", event.Level)
+	assert.Equal(t, "staging", event.Environment)
+	assert.Equal(t, "v1.2.3", event.Release)
+	assert.Equal(t, "GET /api/v1/orders/{id}", event.Transaction)
+	assert.NotEmpty(t, event.Tags["request_id"])
+	assert.Equal(t, "user-7", event.User["id"])
+	assert.Equal(t, "http://example.com/api/v1/orders/9", event.Request.URL)
+	assert.Equal(t, "[Filtered]", event.Request.Headers["Authorization"])
+	require.Len(t, event.Exception.Values, 1)
+	assert.Equal(t, "panic", event.Exception.Values[0].Type)
+	assert.Equal(t, "order total is nil", event.Exception.Values[0].Value)
+
+	// Frame cuối (mới nhất) là handler gây panic
+	frames := event.Exception.Values[0].Stacktrace.Frames
+	require.NotEmpty(t, frames)
+	assert.Contains(t, frames[len(frames)-1].Function, "TestSentryReporterReportsHandlerPanic")
+	assert.True(t, frames[len(frames)-1].InApp)
+}
+
+func TestQueueFailuresAreReported(t *testing.T) {
+	reporter := &recordingReporter{}
+	errorreport.SetReporter(reporter)
+	defer errorreport.SetReporter(nil)
+
+	queues := &listQueueManager{queues: map[string]*listQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, MaxRetries: 1, RetryDelay: time.Millisecond})
+	manager.RegisterHandler("invoice.send", func(ctx context.Context, message *queue.Message) error {
+		if string(message.Data) == `"panic"` {
+			panic("template missing")
+		}
+		return errors.New("smtp unavailable")
+	}, workers.OnQueue("invoices"))
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	failing, err := queue.NewJobMessage("invoice.send", "fail")
+	require.NoError(t, err)
+	panicking, err := queue.NewJobMessage("invoice.send", "panic")
+	require.NoError(t, err)
+	invoices, _ := queues.GetQueue("invoices")
+	require.NoError(t, invoices.Push(ctx, failing))
+	require.NoError(t, invoices.Push(ctx, panicking))
+
+	assert.Eventually(t, func() bool {
+		return len(reporter.withTag("queue", "invoices")) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Mỗi message chỉ báo 1 lần: khi hết retry, hoặc khi panic (không báo lại lúc vào dead queue)
+	events := reporter.withTag("job_type", "invoice.send")
+	require.Len(t, events, 2)
+	byID := map[bool]*errorreport.Event{events[0].Panic: events[0], events[1].Panic: events[1]}
+	require.NotNil(t, byID[false])
+	require.NotNil(t, byID[true])
+	assert.Equal(t, "smtp unavailable", byID[false].Err.Error())
+	assert.Equal(t, failing.ID, byID[false].Extra["message_id"])
+	assert.Equal(t, 2, byID[false].Extra["attempts"])
+	assert.Equal(t, "template missing", byID[true].Err.Error())
+	assert.NotEmpty(t, byID[true].Stack)
+}
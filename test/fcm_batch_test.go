@@ -0,0 +1,40 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/fcm"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendToTokensSplitsIntoBatches(t *testing.T) {
+	client := startFakeFCM(t)
+
+	tokens := make([]string, 1201)
+	for i := range tokens {
+		tokens[i] = fmt.Sprintf("token-%d", i)
+	}
+	tokens[700] = "dead"
+	tokens[1100] = "malformed"
+
+	notification := fcm.NewNotificationBuilder().SetTitle("Khuyến mãi").SetBody("Giảm 50%").Build()
+	response, err := client.SendToTokens(context.Background(), tokens, notification, nil)
+	require.NoError(t, err)
+
+	// 3 batch gộp lại, Responses cùng thứ tự với tokens
+	require.Len(t, response.Responses, len(tokens))
+	assert.Equal(t, 1199, response.SuccessCount)
+	assert.Equal(t, 2, response.FailureCount)
+	assert.False(t, response.Responses[700].Success)
+	assert.False(t, response.Responses[1100].Success)
+	assert.True(t, response.Responses[1200].Success)
+
+	assert.ElementsMatch(t, []fcm.InvalidToken{
+		{Token: "dead", Reason: fcm.InvalidReasonUnregistered},
+		{Token: "malformed", Reason: fcm.InvalidReasonInvalidArgument},
+	}, fcm.InvalidTokens(tokens, response))
+}
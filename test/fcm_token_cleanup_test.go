@@ -0,0 +1,128 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/internal/app/user"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/fcm"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingLoki ghi lại action event thay vì đẩy lên Loki
+type recordingLoki struct {
+	mu     sync.Mutex
+	events []actionEvent.Event
+}
+
+func (l *recordingLoki) PushEventAsync(ctx context.Context, job string, event actionEvent.Event) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+	return nil
+}
+
+func (l *recordingLoki) Events() []actionEvent.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]actionEvent.Event(nil), l.events...)
+}
+
+// startFakeFCM FCM API giả: token "dead" trả UNREGISTERED, "malformed" trả INVALID_ARGUMENT, còn lại thành công
+func startFakeFCM(t *testing.T) *fcm.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Message struct {
+				Token string `json:"token"`
+			} `json:"message"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		fail := func(status int, code, errorCode string) {
+			w.WriteHeader(status)
+			json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]interface{}{
+				"code": status, "status": code, "message": errorCode,
+				"details": []map[string]string{{"@type": "type.googleapis.com/google.firebase.fcm.v1.FcmError", "errorCode": errorCode}},
+			}})
+		}
+		switch req.Message.Token {
+		case "dead":
+			fail(http.StatusNotFound, "NOT_FOUND", "UNREGISTERED")
+		case "malformed":
+			fail(http.StatusBadRequest, "INVALID_ARGUMENT", "INVALID_ARGUMENT")
+		default:
+			json.NewEncoder(w).Encode(map[string]string{"name": "projects/test/messages/1"})
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client, err := fcm.NewClient(&fcm.Config{Endpoint: server.URL, ProjectID: "test", Timeout: 5 * time.Second})
+	require.NoError(t, err)
+	return client
+}
+
+func TestSendNotificationRemovesInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	loki := &recordingLoki{}
+	previous := actionEvent.GlobalService
+	actionEvent.Init(loki)
+	t.Cleanup(func() { actionEvent.GlobalService = previous })
+
+	devices := setupDeviceTokens(t)
+	svc := user.NewService(nil, nil, nil, nil, nil, device.NewPusher(startFakeFCM(t), devices), nil)
+	userID := uuid.New()
+	for _, token := range []string{"alive", "dead", "malformed"} {
+		require.NoError(t, devices.Register(ctx, &model.DeviceToken{UserID: userID, Token: token, Platform: model.DevicePlatformAndroid, LastSeen: time.Now()}))
+	}
+
+	require.NoError(t, svc.SendNotificationToUser(ctx, userID, "Hi", "There", nil))
+
+	remaining, err := devices.FindByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "alive", remaining[0].Token)
+
+	require.Eventually(t, func() bool { return len(loki.Events()) == 2 }, time.Second, 10*time.Millisecond)
+	reasons := map[string]bool{}
+	for _, event := range loki.Events() {
+		assert.Equal(t, "device_token_invalidated", event.Action)
+		assert.Equal(t, "device_token", event.Entity)
+		assert.Equal(t, userID.String(), event.UserID)
+		reasons[event.Data.Old["reason"].(string)] = true
+	}
+	assert.Equal(t, map[string]bool{fcm.InvalidReasonUnregistered: true, fcm.InvalidReasonInvalidArgument: true}, reasons)
+}
+
+func TestSendNotificationKeepsTokensWhenMessageInvalid(t *testing.T) {
+	ctx := context.Background()
+	devices := setupDeviceTokens(t)
+	svc := user.NewService(nil, nil, nil, nil, nil, device.NewPusher(startFakeFCM(t), devices), nil)
+	userID := uuid.New()
+	require.NoError(t, devices.Register(ctx, &model.DeviceToken{UserID: userID, Token: "malformed", Platform: model.DevicePlatformIOS, LastSeen: time.Now()}))
+
+	// Mọi token đều INVALID_ARGUMENT: có thể do message sai, không xóa token
+	assert.Error(t, svc.SendNotificationToUser(ctx, userID, "Hi", "There", nil))
+	remaining, err := devices.FindByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+
+	// Gửi lẻ tới token UNREGISTERED cũng xóa token
+	require.NoError(t, devices.Register(ctx, &model.DeviceToken{UserID: userID, Token: "dead", Platform: model.DevicePlatformAndroid, LastSeen: time.Now()}))
+	_, err = svc.SendNotificationToToken(ctx, "dead", "Hi", "There", nil)
+	require.Error(t, err)
+	assert.True(t, fcm.IsUnregistered(err))
+	remaining, err = devices.FindByUserID(ctx, userID)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	goimage "image"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/image"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEncoder thay cwebp/avifenc trong test: ghi marker + PNG để kiểm tra file nào được phục vụ
+func fakeEncoder(t *testing.T, format string) {
+	t.Helper()
+	original, _ := image.LookupEncoder(format)
+	image.RegisterEncoder(format, image.EncoderFunc(func(w io.Writer, img goimage.Image, quality int) error {
+		if _, err := io.WriteString(w, format+":"); err != nil {
+			return err
+		}
+		return png.Encode(w, img)
+	}))
+	t.Cleanup(func() { image.RegisterEncoder(format, original) })
+}
+
+func TestNegotiateImageFormat(t *testing.T) {
+	cases := map[string]string{
+		"image/avif,image/webp,image/apng,image/*,*/*;q=0.8": "avif", // Chrome
+		"image/webp,*/*":                                  "webp",
+		"image/avif;q=0.5,image/webp":                     "webp",
+		"image/png,image/svg+xml,image/*;q=0.8,*/*;q=0.5": "", // Wildcard không tính
+		"IMAGE/AVIF":                                      "avif",
+		"image/avif;q=0,image/webp;q=0":                   "",
+		"":                                                "",
+	}
+	for accept, want := range cases {
+		assert.Equal(t, want, image.NegotiateFormat(accept, "avif", "webp"), accept)
+	}
+	assert.Equal(t, "webp", image.NegotiateFormat("image/avif,image/webp", "webp"))
+}
+
+func TestImageCommandEncoder(t *testing.T) {
+	img := goimage.NewRGBA(goimage.Rect(0, 0, 4, 4))
+	var want bytes.Buffer
+	require.NoError(t, png.Encode(&want, img))
+
+	// cp {in} {out}: kiểm tra placeholder và đọc lại output
+	encoder := &image.CommandEncoder{Command: "cp", Args: []string{"{in}", "{out}"}, Extension: ".webp"}
+	var out bytes.Buffer
+	require.NoError(t, encoder.Encode(&out, img, 80))
+	assert.Equal(t, want.Bytes(), out.Bytes())
+
+	missing := &image.CommandEncoder{Command: "definitely-not-an-encoder"}
+	assert.False(t, missing.Available())
+	assert.ErrorIs(t, missing.Encode(io.Discard, img, 80), image.ErrUnsupportedFormat)
+
+	assert.True(t, image.Supported("jpg"))
+	assert.False(t, image.Supported("bmp"))
+	assert.Equal(t, ".jpg", image.Extension("jpeg"))
+	assert.Equal(t, "image/avif", image.ContentType("AVIF"))
+}
+
+func TestStorageImageVariants(t *testing.T) {
+	fakeEncoder(t, "webp")
+	fakeEncoder(t, "avif")
+
+	root := t.TempDir()
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	cfg.Image.Variants = []string{"avif", "webp"}
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"avif", "webp"}, manager.ImageVariants())
+
+	ctx := context.Background()
+	options := storage.GetImageUploadOptions(300, 300, 90)
+	options.Path = "avatars"
+	result, err := manager.UploadBytes(ctx, "me.png", samplePNG(t), "image/png", options)
+	require.NoError(t, err)
+	require.Len(t, result.Variants, 2)
+	assert.Equal(t, storage.VariantPath(result.Path, "webp"), result.Variants["webp"])
+	assert.Regexp(t, `^avatars/me_.+\.avif$`, result.Variants["avif"])
+
+	server := storage.NewFileServer(storage.FileServerConfig{Root: root, Prefix: "/storages/", ImageVariants: cfg.Image.Variants})
+	serve := func(accept string) (string, string) {
+		rec := serveStorage(server, http.MethodGet, "/storages/"+result.Path, http.Header{"Accept": {accept}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+		return rec.Header().Get("Content-Type"), rec.Body.String()[:4]
+	}
+	contentType, body := serve("image/avif,image/webp,*/*")
+	assert.Equal(t, "image/avif", contentType)
+	assert.Equal(t, "avif", body)
+	contentType, body = serve("image/webp,*/*")
+	assert.Equal(t, "image/webp", contentType)
+	assert.Equal(t, "webp", body)
+	contentType, body = serve("*/*")
+	assert.Equal(t, "image/png", contentType)
+	assert.Equal(t, "\x89PNG", body)
+
+	// Xóa ảnh gốc xóa luôn các bản thay thế
+	require.NoError(t, manager.DeleteFile(ctx, result.Path))
+	for _, variant := range result.Variants {
+		_, err := os.Stat(filepath.Join(root, variant))
+		assert.True(t, os.IsNotExist(err), variant)
+	}
+}
+
+func TestStorageImageConvertFormat(t *testing.T) {
+	fakeEncoder(t, "webp")
+
+	root := t.TempDir()
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+
+	options := storage.GetImageUploadOptions(300, 300, 90)
+	options.ImageOptions.Format = "webp"
+	result, err := manager.UploadBytes(context.Background(), "photo.png", samplePNG(t), "image/png", options)
+	require.NoError(t, err)
+	assert.Regexp(t, `photo_.+\.webp$`, result.Path)
+	assert.Equal(t, "image/webp", result.ContentType)
+	assert.Empty(t, result.Variants)
+}
@@ -0,0 +1,136 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/actionEvent"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImpersonationToken(t *testing.T) {
+	c := clock.NewFrozen(time.Now())
+	manager := jwt.NewManager(jwt.Config{
+		SecretKey:                  "test-secret",
+		AccessTokenDuration:        time.Hour,
+		ImpersonationTokenDuration: 10 * time.Minute,
+		Clock:                      c,
+	})
+
+	token, expiresAt, err := manager.GenerateImpersonationToken("user-1", "user@example.com", "user", 0, &jwt.Claims{UserID: "admin-1"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, c.Now().Add(10*time.Minute), expiresAt)
+
+	claims, err := manager.VerifyToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, "admin-1", claims.Impersonator)
+	assert.True(t, claims.IsImpersonated())
+
+	// Token thường không có impersonator
+	normal, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	normalClaims, err := manager.VerifyToken(normal)
+	require.NoError(t, err)
+	assert.False(t, normalClaims.IsImpersonated())
+
+	// Impersonation token hết hạn theo ImpersonationTokenDuration, không theo AccessTokenDuration
+	c.Advance(11 * time.Minute)
+	_, err = manager.VerifyToken(token)
+	assert.ErrorIs(t, err, jwt.ErrExpiredToken)
+}
+
+func TestImpersonationMiddleware(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret"})
+	blacklist := jwt.NewBlacklist(cache.NewMockCache())
+	versions := jwt.NewTokenVersions(cache.NewMockCache(), func(ctx context.Context, userID string) (int, error) {
+		return 0, nil
+	}, time.Minute)
+	blacklist.SetTokenVersions(versions)
+
+	token, _, err := manager.GenerateImpersonationToken("user-1", "user@example.com", "user", 0, &jwt.Claims{UserID: "admin-1"}, nil)
+	require.NoError(t, err)
+
+	serve := func() (int, context.Context) {
+		var ctx context.Context
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		manager.MiddlewareWithBlacklist(blacklist)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx = r.Context()
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		return rec.Code, ctx
+	}
+
+	code, ctx := serve()
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "user-1", jwt.GetUserIDFromContext(ctx))
+	assert.Equal(t, "admin-1", jwt.GetImpersonatorFromContext(ctx))
+	// Action events ghi trong request (kể cả CRUD của repository) mang impersonator
+	assert.Equal(t, "admin-1", actionEvent.ImpersonatorFromContext(ctx))
+
+	// Admin logout all (tăng token_version) thì impersonation token cũng bị thu hồi
+	require.NoError(t, versions.Set(context.Background(), "admin-1", 1))
+	code, _ = serve()
+	assert.Equal(t, http.StatusUnauthorized, code)
+}
+
+func TestImpersonationTokenRejectedByRefresh(t *testing.T) {
+	db := newAuthTestDB(t)
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret-key-min-32-chars-long", AccessTokenDuration: time.Hour})
+	service := auth.NewService(
+		repository.NewUserRepository(db), nil, manager,
+		nil, nil, cache.NewMockCache(), nil, nil,
+		repository.NewRoleRepository(db), nil, nil,
+	)
+	r := chi.NewRouter()
+	auth.RegisterPublicRoutes(r, auth.NewHandler(service))
+
+	admin := createAuthTestUser(t, db, "admin@example.com", true)
+	victim := createAuthTestUser(t, db, "victim@example.com", true)
+
+	refresh := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(`{"refresh_token":"`+token+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Impersonation token không đổi được thành phiên dài hạn của user
+	impersonation, _, err := manager.GenerateImpersonationToken(victim.ID.String(), victim.Email, "user", 0,
+		&jwt.Claims{UserID: admin.ID.String()}, nil)
+	require.NoError(t, err)
+	rec := refresh(impersonation)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.CodeTokenInvalid)
+
+	// Access token thường cũng không dùng thay refresh token
+	pair, err := manager.GenerateTokenPair(victim.ID.String(), victim.Email, "user", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, refresh(pair.AccessToken).Code)
+	_, err = manager.VerifyRefreshTokenClaims(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidTokenType)
+
+	// Refresh token không dùng được như access token
+	_, err = manager.VerifyToken(pair.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidTokenType)
+
+	// Refresh token hợp lệ
+	rec = refresh(pair.RefreshToken)
+	assert.Contains(t, rec.Body.String(), response.CodeTokenRefreshed)
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeLegacyKeys tạo keys/private.pem + keys/public.pem như make gen-keys
+func writeLegacyKeys(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privDer, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pubDer, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	privPath := filepath.Join(dir, "private.pem")
+	pubPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDer}), 0600))
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}), 0644))
+	return privPath, pubPath
+}
+
+func tokenKid(t *testing.T, raw string) string {
+	t.Helper()
+	token, _, err := gojwt.NewParser().ParseUnverified(raw, &gojwt.RegisteredClaims{})
+	require.NoError(t, err)
+	kid, _ := token.Header["kid"].(string)
+	return kid
+}
+
+func TestJWTKeyRotation(t *testing.T) {
+	keysDir := filepath.Join(t.TempDir(), "jwt")
+	c := clock.NewFrozen(time.Now())
+
+	first, err := jwt.RotateKey(keysDir, jwt.AlgRS256, 2048, c.Now())
+	require.NoError(t, err)
+
+	manager := jwt.NewManager(jwt.Config{KeysDir: keysDir, Clock: c, AccessTokenDuration: 48 * time.Hour})
+	oldToken, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, tokenKid(t, oldToken))
+
+	// Instance khác rotate key, instance này nhận key mới khi gặp kid chưa biết
+	other := jwt.NewManager(jwt.Config{KeysDir: keysDir, Clock: c, AccessTokenDuration: 48 * time.Hour})
+	c.Advance(time.Hour)
+	second, err := jwt.RotateKey(keysDir, jwt.AlgRS256, 2048, c.Now())
+	require.NoError(t, err)
+	c.Advance(10 * time.Second)
+
+	newToken, err := other.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	assert.Equal(t, second.ID, tokenKid(t, newToken))
+
+	claims, err := manager.VerifyToken(newToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	// Token ký bằng key cũ vẫn hợp lệ
+	_, err = manager.VerifyToken(oldToken)
+	require.NoError(t, err)
+
+	jwks := manager.JWKS()
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, second.ID, jwks.Keys[0].Kid)
+	assert.Equal(t, "RS256", jwks.Keys[0].Alg)
+
+	// Prune chỉ xóa key đã ngừng ký lâu hơn retention
+	removed, err := jwt.PruneKeys(keysDir, 24*time.Hour, c.Now())
+	require.NoError(t, err)
+	assert.Empty(t, removed)
+	removed, err = jwt.PruneKeys(keysDir, 24*time.Hour, c.Now().Add(25*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, []string{first.ID}, removed)
+
+	c.Advance(2 * time.Minute)
+	_, err = manager.VerifyToken(oldToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	_, err = manager.VerifyToken(newToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTLegacyKeyMigration(t *testing.T) {
+	dir := t.TempDir()
+	privPath, pubPath := writeLegacyKeys(t, dir)
+
+	// Token cấp trước khi có kid header
+	legacy := jwt.NewManager(jwt.Config{PrivateKeyPath: privPath, PublicKeyPath: pubPath})
+	legacyToken, err := legacy.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	parsed, _, err := gojwt.NewParser().ParseUnverified(legacyToken, &jwt.Claims{})
+	require.NoError(t, err)
+	delete(parsed.Header, "kid")
+	key, err := os.ReadFile(privPath)
+	require.NoError(t, err)
+	rsaKey, err := gojwt.ParseRSAPrivateKeyFromPEM(key)
+	require.NoError(t, err)
+	kidlessToken, err := parsed.SignedString(rsaKey)
+	require.NoError(t, err)
+
+	keysDir := filepath.Join(dir, "jwt")
+	_, err = jwt.RotateKey(keysDir, jwt.AlgRS256, 2048, time.Now())
+	require.NoError(t, err)
+	manager := jwt.NewManager(jwt.Config{PrivateKeyPath: privPath, PublicKeyPath: pubPath, KeysDir: keysDir})
+
+	for _, raw := range []string{legacyToken, kidlessToken} {
+		claims, err := manager.VerifyToken(raw)
+		require.NoError(t, err)
+		assert.Equal(t, "user-1", claims.UserID)
+	}
+	assert.Len(t, manager.JWKS().Keys, 2)
+
+	// HS256 token không được chấp nhận khi server dùng RSA
+	hmac := jwt.NewManager(jwt.Config{SecretKey: "test-secret"})
+	hmacToken, err := hmac.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, err = manager.VerifyToken(hmacToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	assert.Empty(t, hmac.JWKS().Keys)
+}
+
+func TestJWKSHandler(t *testing.T) {
+	keysDir := filepath.Join(t.TempDir(), "jwt")
+	key, err := jwt.RotateKey(keysDir, jwt.AlgRS256, 2048, time.Now())
+	require.NoError(t, err)
+	manager := jwt.NewManager(jwt.Config{KeysDir: keysDir})
+
+	rec := httptest.NewRecorder()
+	manager.JWKSHandler(rec, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var body jwt.JWKS
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Keys, 1)
+	assert.Equal(t, key.ID, body.Keys[0].Kid)
+	assert.Equal(t, "AQAB", body.Keys[0].E)
+	assert.Equal(t, jwt.KeyThumbprint(key.PublicKey()), body.Keys[0].Kid)
+}
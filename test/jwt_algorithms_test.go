@@ -0,0 +1,161 @@
+package test
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tokenAlg(t *testing.T, raw string) string {
+	t.Helper()
+	token, _, err := gojwt.NewParser().ParseUnverified(raw, &gojwt.RegisteredClaims{})
+	require.NoError(t, err)
+	return token.Method.Alg()
+}
+
+func TestJWTSigningAlgorithms(t *testing.T) {
+	cases := []struct {
+		alg string
+		kty string
+		crv string
+	}{
+		{jwt.AlgRS256, "RSA", ""},
+		{jwt.AlgES256, "EC", "P-256"},
+		{jwt.AlgES384, "EC", "P-384"},
+		{jwt.AlgEdDSA, "OKP", "Ed25519"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.alg, func(t *testing.T) {
+			keysDir := filepath.Join(t.TempDir(), "jwt")
+			key, err := jwt.RotateKey(keysDir, tc.alg, 2048, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, tc.alg, key.Algorithm)
+
+			manager := jwt.NewManager(jwt.Config{Algorithm: tc.alg, KeysDir: keysDir})
+			pair, err := manager.GenerateTokenPair("user-1", "user@example.com", "user", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.alg, tokenAlg(t, pair.AccessToken))
+			assert.Equal(t, key.ID, tokenKid(t, pair.AccessToken))
+
+			claims, err := manager.VerifyToken(pair.AccessToken)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.UserID)
+			userID, err := manager.VerifyRefreshToken(pair.RefreshToken)
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", userID)
+
+			jwks := manager.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, tc.kty, jwks.Keys[0].Kty)
+			assert.Equal(t, tc.crv, jwks.Keys[0].Crv)
+			assert.Equal(t, tc.alg, jwks.Keys[0].Alg)
+			assert.Equal(t, key.ID, jwks.Keys[0].Kid)
+		})
+	}
+}
+
+func TestJWTRotateAcrossAlgorithms(t *testing.T) {
+	keysDir := filepath.Join(t.TempDir(), "jwt")
+	c := clock.NewFrozen(time.Now())
+
+	_, err := jwt.RotateKey(keysDir, jwt.AlgRS256, 2048, c.Now())
+	require.NoError(t, err)
+	manager := jwt.NewManager(jwt.Config{KeysDir: keysDir, Clock: c, AccessTokenDuration: 48 * time.Hour})
+	rsaToken, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+
+	c.Advance(time.Hour)
+	ecKey, err := jwt.RotateKey(keysDir, jwt.AlgES256, 0, c.Now())
+	require.NoError(t, err)
+	c.Advance(2 * time.Minute)
+
+	ecToken, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	assert.Equal(t, jwt.AlgES256, tokenAlg(t, ecToken))
+	assert.Equal(t, ecKey.ID, tokenKid(t, ecToken))
+
+	// Token RS256 đã cấp vẫn verify được bằng key cũ
+	for _, raw := range []string{rsaToken, ecToken} {
+		_, err := manager.VerifyToken(raw)
+		require.NoError(t, err)
+	}
+}
+
+func TestJWTRejectsAlgorithmMismatch(t *testing.T) {
+	keysDir := filepath.Join(t.TempDir(), "jwt")
+	key, err := jwt.RotateKey(keysDir, jwt.AlgES256, 0, time.Now())
+	require.NoError(t, err)
+	manager := jwt.NewManager(jwt.Config{KeysDir: keysDir})
+
+	// Cùng kid nhưng alg khác loại key (ES384 với P-256 key) bị từ chối
+	claims := jwt.Claims{
+		UserID: "user-1",
+		RegisteredClaims: gojwt.RegisteredClaims{
+			ExpiresAt: gojwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	other, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	token := gojwt.NewWithClaims(gojwt.SigningMethodES384, claims)
+	token.Header["kid"] = key.ID
+	forged, err := token.SignedString(other)
+	require.NoError(t, err)
+
+	_, err = manager.VerifyToken(forged)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+}
+
+func TestJWTLegacyECKeyPair(t *testing.T) {
+	dir := t.TempDir()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	// SEC1 "EC PRIVATE KEY" như openssl ecparam -genkey
+	privDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	pubDer, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+
+	privPath := filepath.Join(dir, "private.pem")
+	pubPath := filepath.Join(dir, "public.pem")
+	require.NoError(t, os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: privDer}), 0600))
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDer}), 0644))
+
+	manager := jwt.NewManager(jwt.Config{PrivateKeyPath: privPath, PublicKeyPath: pubPath})
+	raw, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	assert.Equal(t, jwt.AlgES256, tokenAlg(t, raw))
+
+	claims, err := manager.VerifyToken(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+
+	// JWT_ALGORITHM=HS256 bỏ qua keys
+	hmac := jwt.NewManager(jwt.Config{Algorithm: jwt.AlgHS256, SecretKey: "test-secret", PrivateKeyPath: privPath, PublicKeyPath: pubPath})
+	raw, err = hmac.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	assert.Equal(t, jwt.AlgHS256, tokenAlg(t, raw))
+	assert.Empty(t, hmac.JWKS().Keys)
+}
+
+func TestEd25519KeyThumbprint(t *testing.T) {
+	// Test vector RFC 8037 Appendix A.3
+	x, err := base64.RawURLEncoding.DecodeString("11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo")
+	require.NoError(t, err)
+	assert.Equal(t, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k", jwt.KeyThumbprint(ed25519.PublicKey(x)))
+}
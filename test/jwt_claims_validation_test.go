@@ -0,0 +1,100 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTAudienceValidation(t *testing.T) {
+	billing := jwt.NewManager(jwt.Config{SecretKey: "shared-secret", Issuer: "apicore", Audience: []string{"billing-api"}})
+	core := jwt.NewManager(jwt.Config{
+		SecretKey:         "shared-secret",
+		Issuer:            "apicore",
+		Audience:          []string{"api-core"},
+		AcceptedAudiences: []string{"api-core", "api-core-admin"},
+	})
+	userID := uuid.NewString()
+
+	pair, err := core.GenerateTokenPair(userID, "user@example.com", "user", nil)
+	require.NoError(t, err)
+	claims, err := core.VerifyToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api-core"}, []string(claims.Audience))
+	_, err = core.VerifyRefreshToken(pair.RefreshToken)
+	require.NoError(t, err)
+
+	// Cùng secret nhưng token cấp cho service khác bị từ chối
+	other, err := billing.GenerateTokenPair(userID, "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, err = core.VerifyToken(other.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidAudience)
+	_, err = core.VerifyRefreshToken(other.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidAudience)
+
+	// Token không có aud (cấp trước khi bật audience) bị từ chối khi đã yêu cầu aud
+	legacy := jwt.NewManager(jwt.Config{SecretKey: "shared-secret", Issuer: "apicore"})
+	raw, err := legacy.GenerateToken(userID, "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, err = core.VerifyToken(raw)
+	assert.ErrorIs(t, err, jwt.ErrInvalidAudience)
+
+	// Không cấu hình AcceptedAudiences thì không kiểm tra
+	_, err = legacy.VerifyToken(other.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestJWTIssuerValidation(t *testing.T) {
+	partner := jwt.NewManager(jwt.Config{SecretKey: "shared-secret", Issuer: "partner"})
+	core := jwt.NewManager(jwt.Config{SecretKey: "shared-secret", Issuer: "apicore", AcceptedIssuers: []string{"apicore", "apicore-legacy"}})
+
+	raw, err := partner.GenerateToken(uuid.NewString(), "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, err = core.VerifyToken(raw)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidIssuer)
+
+	raw, err = core.GenerateToken(uuid.NewString(), "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, err = core.VerifyToken(raw)
+	assert.NoError(t, err)
+}
+
+func TestJWTSubjectFormatValidation(t *testing.T) {
+	pattern, err := jwt.ParseSubjectFormat("uuid")
+	require.NoError(t, err)
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret", SubjectPattern: pattern})
+
+	raw, err := manager.GenerateToken(uuid.NewString(), "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, err = manager.VerifyToken(raw)
+	assert.NoError(t, err)
+
+	raw, err = manager.GenerateToken("service:reporting", "", "service", nil)
+	require.NoError(t, err)
+	_, err = manager.VerifyToken(raw)
+	assert.ErrorIs(t, err, jwt.ErrInvalidSubject)
+
+	refresh, err := manager.GenerateRefreshToken("42")
+	require.NoError(t, err)
+	_, err = manager.VerifyRefreshToken(refresh)
+	assert.ErrorIs(t, err, jwt.ErrInvalidSubject)
+
+	// Regular expression phải khớp toàn bộ sub
+	pattern, err = jwt.ParseSubjectFormat(`[0-9]+`)
+	require.NoError(t, err)
+	assert.True(t, pattern.MatchString("42"))
+	assert.False(t, pattern.MatchString("42abc"))
+
+	pattern, err = jwt.ParseSubjectFormat("")
+	require.NoError(t, err)
+	assert.Nil(t, pattern)
+
+	_, err = jwt.ParseSubjectFormat("[")
+	assert.Error(t, err)
+}
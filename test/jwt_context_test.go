@@ -0,0 +1,93 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTMiddlewarePopulatesPrincipal(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret", AccessTokenDuration: time.Hour})
+	token, err := manager.GenerateToken("user-1", "user@example.com", "moderator", nil)
+	require.NoError(t, err)
+
+	var principal jwt.Principal
+	var found bool
+	handler := manager.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, found = jwt.PrincipalFromContext(r.Context())
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, found)
+	assert.Equal(t, "user-1", principal.UserID)
+	assert.Equal(t, "user@example.com", principal.Email)
+	assert.Equal(t, "moderator", principal.Role)
+	assert.False(t, principal.IsImpersonated())
+	require.NotNil(t, principal.Claims)
+	assert.Equal(t, "user-1", principal.Claims.UserID)
+}
+
+func TestJWTContextHelpers(t *testing.T) {
+	_, ok := jwt.PrincipalFromContext(context.Background())
+	assert.False(t, ok)
+	_, ok = jwt.ClaimsFromContext(context.Background())
+	assert.False(t, ok)
+	assert.Empty(t, jwt.GetUserIDFromContext(context.Background()))
+
+	ctx := jwt.ContextWithClaims(context.Background(), &jwt.Claims{UserID: "user-1", Role: "user", Impersonator: "admin-1"})
+	principal, ok := jwt.PrincipalFromContext(ctx)
+	require.True(t, ok)
+	assert.True(t, principal.IsImpersonated())
+	claims, ok := jwt.ClaimsFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, "admin-1", claims.Impersonator)
+	assert.Equal(t, "user-1", jwt.GetUserIDFromContext(ctx))
+
+	// Context chỉ có claims (gắn trực tiếp) vẫn đọc được principal
+	legacy := context.WithValue(context.Background(), jwt.ClaimsContextKey, &jwt.Claims{UserID: "user-2"})
+	principal, ok = jwt.PrincipalFromContext(legacy)
+	require.True(t, ok)
+	assert.Equal(t, "user-2", principal.UserID)
+}
+
+func TestJWTRequireAnyRole(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name    string
+		claims  *jwt.Claims
+		handler http.Handler
+		want    int
+	}{
+		{"unauthenticated", nil, jwt.RequireAnyRole("admin")(ok), http.StatusUnauthorized},
+		{"wrong role", &jwt.Claims{UserID: "u", Role: "user"}, jwt.RequireAnyRole("admin", "moderator")(ok), http.StatusForbidden},
+		{"any role matches", &jwt.Claims{UserID: "u", Role: "moderator"}, jwt.RequireAnyRole("admin", "moderator")(ok), http.StatusOK},
+		{"single role", &jwt.Claims{UserID: "u", Role: "admin"}, jwt.RequireRole("admin")(ok), http.StatusOK},
+		{"single role mismatch", &jwt.Claims{UserID: "u", Role: "moderator"}, jwt.RequireRole("admin")(ok), http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.claims != nil {
+				req = req.WithContext(jwt.ContextWithClaims(req.Context(), tt.claims))
+			}
+			rec := httptest.NewRecorder()
+			tt.handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
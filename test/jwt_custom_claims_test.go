@@ -0,0 +1,70 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tenantClaims struct {
+	TenantID string   `json:"tenant_id"`
+	Scopes   []string `json:"scopes"`
+}
+
+func TestJWTTypedCustomClaims(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "custom-claims-secret"})
+
+	token, err := jwt.GenerateVersionedTokenWith(manager, "user-1", "user@example.com", "user", 3,
+		tenantClaims{TenantID: "acme", Scopes: []string{"billing:read"}})
+	require.NoError(t, err)
+
+	claims, tenant, err := jwt.VerifyTokenInto[tenantClaims](manager, token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, 3, claims.TokenVersion)
+	assert.Equal(t, tenantClaims{TenantID: "acme", Scopes: []string{"billing:read"}}, tenant)
+
+	// Token không có custom claims trả zero value
+	plain, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, tenant, err = jwt.VerifyTokenInto[tenantClaims](manager, plain)
+	require.NoError(t, err)
+	assert.Equal(t, tenantClaims{}, tenant)
+
+	// Custom claims khác kiểu là token không hợp lệ
+	_, _, err = jwt.VerifyTokenInto[[]string](manager, token)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidCustomClaims)
+
+	// Lỗi xác thực token vẫn trả như VerifyToken
+	_, _, err = jwt.VerifyTokenInto[tenantClaims](manager, token+"x")
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+}
+
+func TestJWTCustomClaimsFromContext(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "custom-claims-secret"})
+	token, err := jwt.GenerateTokenWith(manager, "user-1", "user@example.com", "user", tenantClaims{TenantID: "acme"})
+	require.NoError(t, err)
+
+	var (
+		tenant tenantClaims
+		ok     bool
+	)
+	handler := manager.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tenant, ok = jwt.GetCustomClaimsFromContext[tenantClaims](r.Context())
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, ok)
+	assert.Equal(t, "acme", tenant.TenantID)
+
+	_, ok = jwt.GetCustomClaimsFromContext[tenantClaims](req.Context())
+	assert.False(t, ok)
+}
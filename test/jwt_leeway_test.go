@@ -0,0 +1,69 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWTLeewayToleratesClockSkew(t *testing.T) {
+	issuedAt := time.Now().Truncate(time.Second)
+	// Node cấp token chạy nhanh hơn node verify 10 giây
+	issuer := jwt.NewManager(jwt.Config{SecretKey: "skew-secret", Clock: clock.NewFrozen(issuedAt.Add(10 * time.Second))})
+	verifierClock := clock.NewFrozen(issuedAt)
+	strict := jwt.NewManager(jwt.Config{SecretKey: "skew-secret", Clock: verifierClock})
+	lenient := jwt.NewManager(jwt.Config{SecretKey: "skew-secret", Clock: verifierClock, Leeway: 30 * time.Second})
+
+	pair, err := issuer.GenerateTokenPair(uuid.NewString(), "user@example.com", "user", nil)
+	require.NoError(t, err)
+
+	// nbf/iat ở tương lai theo đồng hồ node verify
+	_, err = strict.VerifyToken(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	_, err = strict.VerifyRefreshToken(pair.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken, "iat của refresh token ở tương lai")
+	_, err = lenient.VerifyToken(pair.AccessToken)
+	require.NoError(t, err)
+	_, err = lenient.VerifyRefreshToken(pair.RefreshToken)
+	require.NoError(t, err)
+
+	// Ngay sau exp: chỉ hợp lệ khi còn trong leeway
+	expiry, err := lenient.GetTokenExpiry(pair.AccessToken)
+	require.NoError(t, err)
+	verifierClock.Set(expiry.Add(5 * time.Second))
+	_, err = strict.VerifyToken(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrExpiredToken)
+	assert.True(t, strict.IsTokenExpired(pair.AccessToken))
+	_, err = lenient.VerifyToken(pair.AccessToken)
+	assert.NoError(t, err)
+	assert.False(t, lenient.IsTokenExpired(pair.AccessToken))
+
+	verifierClock.Set(expiry.Add(30 * time.Second))
+	_, err = lenient.VerifyToken(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrExpiredToken)
+	assert.True(t, lenient.IsTokenExpired(pair.AccessToken))
+}
+
+func TestOpaqueTokenLeeway(t *testing.T) {
+	now := clock.NewFrozen(time.Now())
+	store := jwt.NewOpaqueStore(cache.NewMockCache())
+	manager := jwt.NewManager(jwt.Config{SecretKey: "opaque-secret", OpaqueStore: store, Clock: now, Leeway: 10 * time.Second})
+
+	pair, err := manager.GenerateTokenPair(uuid.NewString(), "user@example.com", "user", nil)
+	require.NoError(t, err)
+
+	now.Set(pair.ExpiresAt.Add(5 * time.Second))
+	_, err = manager.VerifyToken(pair.AccessToken)
+	assert.NoError(t, err)
+
+	now.Set(pair.ExpiresAt.Add(10 * time.Second))
+	_, err = manager.VerifyToken(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrExpiredToken)
+}
@@ -0,0 +1,193 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unavailableCache giả lập Redis lỗi khi đọc
+type unavailableCache struct {
+	*cache.MockCache
+}
+
+func (c *unavailableCache) Get(ctx context.Context, key string) (string, error) {
+	return "", errors.New("connection refused")
+}
+
+func newOpaqueManager(store cache.Cache, c clock.Clock) *jwt.Manager {
+	return jwt.NewManager(jwt.Config{
+		SecretKey:   "opaque-secret",
+		OpaqueStore: jwt.NewOpaqueStore(store),
+		Clock:       c,
+	})
+}
+
+func TestOpaqueTokens(t *testing.T) {
+	now := clock.NewFrozen(time.Now())
+	manager := newOpaqueManager(cache.NewMockCache(), now)
+
+	pair, err := manager.GenerateVersionedTokenPair("user-1", "user@example.com", "user", 2, map[string]interface{}{"name": "Jane"})
+	require.NoError(t, err)
+	assert.True(t, jwt.IsOpaqueToken(pair.AccessToken))
+	assert.True(t, manager.IsOpaque(pair.RefreshToken))
+
+	claims, err := manager.VerifyToken(pair.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.Equal(t, 2, claims.TokenVersion)
+	assert.Equal(t, "Jane", claims.Metadata["name"])
+	assert.Equal(t, "user-1", manager.ExtractUserID(pair.AccessToken))
+
+	refresh, err := manager.VerifyRefreshTokenClaims(pair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", refresh.Subject)
+
+	// Access token và refresh token không dùng lẫn được
+	_, err = manager.VerifyToken(pair.RefreshToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+	_, err = manager.VerifyRefreshToken(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+
+	_, err = manager.VerifyToken("unknown-token")
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+
+	// Thu hồi có hiệu lực ngay
+	require.NoError(t, manager.RevokeOpaqueToken(pair.AccessToken))
+	_, err = manager.VerifyToken(pair.AccessToken)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+
+	// Hết hạn trả ErrExpiredToken để client refresh
+	other, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	now.Advance(16 * time.Minute)
+	_, err = manager.VerifyToken(other)
+	assert.ErrorIs(t, err, jwt.ErrExpiredToken)
+	assert.True(t, manager.IsTokenExpired(other))
+}
+
+func TestOpaqueModeAcceptsExistingJWT(t *testing.T) {
+	store := cache.NewMockCache()
+	legacy := jwt.NewManager(jwt.Config{SecretKey: "opaque-secret"})
+	token, err := legacy.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+
+	manager := newOpaqueManager(store, nil)
+	assert.False(t, manager.IsOpaque(token))
+	claims, err := manager.VerifyToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.UserID)
+	assert.ErrorIs(t, manager.RevokeOpaqueToken(token), jwt.ErrInvalidToken)
+}
+
+func TestOpaqueStoreUnavailable(t *testing.T) {
+	store := &unavailableCache{MockCache: cache.NewMockCache()}
+	manager := newOpaqueManager(store, nil)
+
+	token, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	_, err = manager.VerifyToken(token)
+	assert.ErrorIs(t, err, jwt.ErrTokenStoreUnavailable)
+
+	handler := manager.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler must not run")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func introspect(t *testing.T, introspector *jwt.Introspector, user, password string, form url.Values) (int, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/oauth/introspect", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if user != "" {
+		req.SetBasicAuth(user, password)
+	}
+	rec := httptest.NewRecorder()
+	introspector.Handler(rec, req)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec.Code, body
+}
+
+func TestTokenIntrospection(t *testing.T) {
+	store := cache.NewMockCache()
+	manager := newOpaqueManager(store, nil)
+
+	versions := map[string]int{"user-1": 1}
+	tokenVersions := jwt.NewTokenVersions(nil, func(ctx context.Context, userID string) (int, error) {
+		return versions[userID], nil
+	}, time.Minute)
+	blacklist := jwt.NewBlacklist(store)
+	blacklist.SetTokenVersions(tokenVersions)
+
+	clients, err := jwt.ParseIntrospectionClients("billing-api:s3cret, reports:other")
+	require.NoError(t, err)
+	introspector := jwt.NewIntrospector(manager, blacklist, clients)
+
+	pair, err := manager.GenerateVersionedTokenPair("user-1", "user@example.com", "user", 1,
+		map[string]interface{}{jwt.MetadataPermissions: []string{"users.view", "users.update"}})
+	require.NoError(t, err)
+
+	// Client credentials bắt buộc
+	code, body := introspect(t, introspector, "", "", url.Values{"token": {pair.AccessToken}})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	assert.Equal(t, "invalid_client", body["error"])
+	code, _ = introspect(t, introspector, "billing-api", "wrong", url.Values{"token": {pair.AccessToken}})
+	assert.Equal(t, http.StatusUnauthorized, code)
+	code, body = introspect(t, introspector, "billing-api", "s3cret", url.Values{})
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "invalid_request", body["error"])
+
+	code, body = introspect(t, introspector, "billing-api", "s3cret", url.Values{"token": {pair.AccessToken}})
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, true, body["active"])
+	assert.Equal(t, "Bearer", body["token_type"])
+	assert.Equal(t, "user-1", body["sub"])
+	assert.Equal(t, "user@example.com", body["username"])
+	assert.Equal(t, "users.view users.update", body["scope"])
+	assert.NotZero(t, body["exp"])
+
+	_, body = introspect(t, introspector, "reports", "other", url.Values{"token": {pair.RefreshToken}, "token_type_hint": {"refresh_token"}})
+	assert.Equal(t, true, body["active"])
+	assert.Equal(t, "refresh_token", body["token_type"])
+
+	_, body = introspect(t, introspector, "reports", "other", url.Values{"token": {"garbage"}})
+	assert.Equal(t, map[string]interface{}{"active": false}, body)
+
+	// Logout all (tăng token_version) thu hồi cả access và refresh token
+	versions["user-1"] = 2
+	_, body = introspect(t, introspector, "billing-api", "s3cret", url.Values{"token": {pair.AccessToken}})
+	assert.Equal(t, false, body["active"])
+	_, body = introspect(t, introspector, "billing-api", "s3cret", url.Values{"token": {pair.RefreshToken}})
+	assert.Equal(t, false, body["active"])
+
+	// JWT cũng introspect được, refresh JWT không bị coi là access token
+	jwtManager := jwt.NewManager(jwt.Config{SecretKey: "opaque-secret"})
+	jwtPair, err := jwtManager.GenerateVersionedTokenPair("user-1", "user@example.com", "user", 2, nil)
+	require.NoError(t, err)
+	_, body = introspect(t, introspector, "billing-api", "s3cret", url.Values{"token": {jwtPair.RefreshToken}})
+	assert.Equal(t, true, body["active"])
+	assert.Equal(t, "refresh_token", body["token_type"])
+
+	_, err = jwt.ParseIntrospectionClients("missing-secret")
+	assert.Error(t, err)
+}
@@ -0,0 +1,62 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefreshAccessTokenLoadsSubjectServerSide(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret", AccessTokenDuration: time.Hour})
+	ctx := context.Background()
+
+	pair, err := manager.GenerateVersionedTokenPair("user-1", "old@example.com", "admin", 1, nil)
+	require.NoError(t, err)
+
+	// Role bị hạ xuống user sau khi cấp token: token mới mang role hiện tại
+	subject := &jwt.RefreshSubject{
+		Email:        "new@example.com",
+		Role:         "user",
+		TokenVersion: 1,
+		Metadata:     map[string]interface{}{jwt.MetadataPermissions: []string{"users.view"}},
+	}
+	var loadedUserID string
+	loader := func(ctx context.Context, userID string) (*jwt.RefreshSubject, error) {
+		loadedUserID = userID
+		return subject, nil
+	}
+
+	refreshed, err := manager.RefreshAccessToken(ctx, pair.RefreshToken, loader)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", loadedUserID)
+
+	claims, err := manager.VerifyToken(refreshed.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user", claims.Role)
+	assert.Equal(t, "new@example.com", claims.Email)
+	assert.Equal(t, []interface{}{"users.view"}, claims.Metadata[jwt.MetadataPermissions])
+
+	// Logout all tăng token_version: refresh token cũ bị từ chối
+	subject.TokenVersion = 2
+	_, err = manager.RefreshAccessToken(ctx, pair.RefreshToken, loader)
+	assert.ErrorIs(t, err, jwt.ErrTokenRevoked)
+
+	subject.TokenVersion = 1
+	subject.Disabled = true
+	_, err = manager.RefreshAccessToken(ctx, pair.RefreshToken, loader)
+	assert.ErrorIs(t, err, jwt.ErrSubjectDisabled)
+
+	_, err = manager.RefreshAccessToken(ctx, pair.RefreshToken, func(ctx context.Context, userID string) (*jwt.RefreshSubject, error) {
+		return nil, jwt.ErrUserNotFound
+	})
+	assert.ErrorIs(t, err, jwt.ErrUserNotFound)
+
+	// Access token không dùng để refresh được
+	_, err = manager.RefreshAccessToken(ctx, pair.AccessToken, loader)
+	assert.Error(t, err)
+}
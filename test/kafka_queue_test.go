@@ -0,0 +1,83 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOffsetTrackerCommitsContiguousOffsets(t *testing.T) {
+	tracker := queue.NewOffsetTracker()
+	for offset := int64(10); offset <= 13; offset++ {
+		tracker.Track(0, offset)
+	}
+	tracker.Track(1, 5)
+
+	// 11 xong trước 10: chưa commit được
+	_, ok := tracker.Done(0, 11)
+	assert.False(t, ok)
+
+	offset, ok := tracker.Done(0, 10)
+	assert.True(t, ok)
+	assert.Equal(t, int64(11), offset)
+
+	_, ok = tracker.Done(0, 13)
+	assert.False(t, ok)
+	offset, ok = tracker.Done(0, 12)
+	assert.True(t, ok)
+	assert.Equal(t, int64(13), offset)
+
+	// Partition độc lập
+	offset, ok = tracker.Done(1, 5)
+	assert.True(t, ok)
+	assert.Equal(t, int64(5), offset)
+
+	// Offset không được track
+	_, ok = tracker.Done(2, 1)
+	assert.False(t, ok)
+}
+
+func TestOffsetTrackerResetsPartitionAfterRebalance(t *testing.T) {
+	tracker := queue.NewOffsetTracker()
+	tracker.Track(0, 20)
+	tracker.Track(0, 21)
+
+	// Partition được giao lại, đọc lại từ offset đã commit
+	tracker.Track(0, 20)
+
+	// Offset 21 của lần fetch trước không còn được theo dõi
+	_, ok := tracker.Done(0, 21)
+	assert.False(t, ok)
+
+	offset, ok := tracker.Done(0, 20)
+	assert.True(t, ok)
+	assert.Equal(t, int64(20), offset)
+}
+
+func TestKafkaQueueConfig(t *testing.T) {
+	t.Setenv("QUEUE_DRIVER", "kafka")
+	t.Setenv("KAFKA_BROKERS", "kafka-1:9092,kafka-2:9092")
+	t.Setenv("KAFKA_TOPIC_PREFIX", "events.")
+
+	cfg := config.LoadQueueConfig()
+	require.NoError(t, cfg.Validate())
+
+	queueConfig := cfg.ToQueueConfig()
+	assert.Equal(t, queue.QueueTypeKafka, queueConfig.Type)
+	require.NotNil(t, queueConfig.Kafka)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, queueConfig.Kafka.Brokers)
+	assert.Equal(t, "api-core-workers", queueConfig.Kafka.GroupID)
+	assert.Equal(t, "events.", queueConfig.Kafka.TopicPrefix)
+	assert.Equal(t, 6, queueConfig.Kafka.Partitions)
+	assert.Nil(t, queueConfig.Kafka.TLS)
+
+	t.Setenv("KAFKA_TOPIC_PARTITIONS", "0")
+	assert.Error(t, config.LoadQueueConfig().Validate())
+
+	t.Setenv("QUEUE_DRIVER", "nats")
+	assert.Error(t, config.LoadQueueConfig().Validate())
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/loadshed"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestShedder shedder chỉ theo dõi p99, đánh giá mỗi 10ms
+func newTestShedder(t *testing.T, maxAge time.Duration) *loadshed.Shedder {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	shedder := loadshed.NewShedder(loadshed.Config{
+		MaxP99Latency: 100 * time.Millisecond,
+		SampleMaxAge:  maxAge,
+		EvalInterval:  10 * time.Millisecond,
+		SevereFactor:  3,
+	})
+	shedder.Start(ctx)
+	return shedder
+}
+
+func TestLoadShedRecoversWhileShedding(t *testing.T) {
+	shedder := newTestShedder(t, 200*time.Millisecond)
+	var served atomic.Int64
+	handler := loadshed.Middleware(shedder)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+	}))
+
+	for i := 0; i < 20; i++ {
+		shedder.Observe(150 * time.Millisecond)
+	}
+	require.Eventually(t, func() bool { return shedder.Stats().Level == loadshed.LevelOverloaded }, time.Second, 5*time.Millisecond)
+
+	// Request low priority bị shed nên không có latency mới, level vẫn về Normal khi sample cũ hết hạn
+	assert.True(t, shedder.ShouldShed(loadshed.PriorityLow))
+	assert.Eventually(t, func() bool { return shedder.Stats().Level == loadshed.LevelNormal }, 2*time.Second, 5*time.Millisecond)
+	assert.False(t, shedder.ShouldShed(loadshed.PriorityLow))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(1), served.Load())
+}
+
+func TestLoadShedHysteresis(t *testing.T) {
+	shedder := newTestShedder(t, 150*time.Millisecond)
+	for i := 0; i < 20; i++ {
+		shedder.Observe(150 * time.Millisecond)
+	}
+	require.Eventually(t, func() bool { return shedder.Stats().Level == loadshed.LevelOverloaded }, time.Second, 5*time.Millisecond)
+
+	// Latency liên tục ở 90% ngưỡng: chưa dưới 80% nên vẫn overloaded
+	var latency atomic.Int64
+	latency.Store(int64(90 * time.Millisecond))
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(5 * time.Millisecond):
+				shedder.Observe(time.Duration(latency.Load()))
+			}
+		}
+	}()
+
+	time.Sleep(400 * time.Millisecond)
+	stats := shedder.Stats()
+	assert.Equal(t, 90*time.Millisecond, stats.P99Latency)
+	assert.Equal(t, loadshed.LevelOverloaded, stats.Level)
+
+	// Dưới 80% ngưỡng thì về Normal
+	latency.Store(int64(70 * time.Millisecond))
+	assert.Eventually(t, func() bool { return shedder.Stats().Level == loadshed.LevelNormal }, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestLoadShedDefaultCriticalRoutes(t *testing.T) {
+	shedder := config.CreateLoadShedder(config.LoadLoadShedConfig())
+	for _, path := range []string{"/api/v1/auth/login", "/ws", "/api/v1/health/database", "/healthz", "/readyz", "/status"} {
+		assert.Equal(t, loadshed.PriorityCritical, shedder.PriorityFor(path), path)
+	}
+	assert.Equal(t, loadshed.PriorityNormal, shedder.PriorityFor("/api/v1/users"))
+}
@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/loglevel"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/glebarez/sqlite"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// memoryLevelStore logger.LevelStore in-memory (RedisLevelStore dùng chung giữa các process)
+type memoryLevelStore struct {
+	mu     sync.Mutex
+	levels map[string]zerolog.Level
+}
+
+func (s *memoryLevelStore) Set(ctx context.Context, module string, level zerolog.Level, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.levels[module] = level
+	return nil
+}
+func (s *memoryLevelStore) Reset(ctx context.Context, module string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.levels, module)
+	return nil
+}
+func (s *memoryLevelStore) Load(ctx context.Context) (map[string]zerolog.Level, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	levels := make(map[string]zerolog.Level, len(s.levels))
+	for module, level := range s.levels {
+		levels[module] = level
+	}
+	return levels, nil
+}
+
+// initFileLogger Init logger ghi ra <dir>/app.log, trả về hàm đọc nội dung file
+func initFileLogger(t *testing.T, level string, modules map[string]string) func() string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, logger.Init(logger.Config{Level: level, ModuleLevels: modules, Output: "file", LogPath: dir}))
+	t.Cleanup(func() {
+		logger.ReplaceOverrides(nil)
+		_ = logger.Init(logger.Config{Level: "info", Output: "console"})
+	})
+	return func() string {
+		data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+		require.NoError(t, err)
+		return string(data)
+	}
+}
+
+func TestModuleLevels(t *testing.T) {
+	read := initFileLogger(t, "info", map[string]string{logger.ModuleCron: "debug", logger.ModuleSocket: "warn"})
+
+	logger.Module(logger.ModuleCron).Debugf("cron debug line")
+	logger.Module(logger.ModuleSocket).Infof("socket info line")
+	logger.Module(logger.ModuleSocket).Warnf("socket warn line")
+	logger.Module(logger.ModuleGORM).Debugf("gorm debug line")
+	logger.Debugf("app debug line")
+	logger.Infof("app info line")
+
+	content := read()
+	assert.Contains(t, content, "cron debug line")
+	assert.Contains(t, content, `"module":"cron"`)
+	assert.Contains(t, content, "socket warn line")
+	assert.Contains(t, content, "app info line")
+	assert.NotContains(t, content, "socket info line")
+	assert.NotContains(t, content, "gorm debug line") // Chưa cấu hình: theo LOG_LEVEL
+	assert.NotContains(t, content, "app debug line")
+
+	// Đổi lúc chạy rồi quay về level theo config
+	require.NoError(t, logger.SetLevel(logger.ModuleGORM, zerolog.DebugLevel))
+	logger.Module(logger.ModuleGORM).Debugf("gorm override line")
+	logger.ResetLevel(logger.ModuleGORM)
+	logger.Module(logger.ModuleGORM).Debugf("gorm after reset line")
+
+	content = read()
+	assert.Contains(t, content, "gorm override line")
+	assert.NotContains(t, content, "gorm after reset line")
+	assert.ErrorIs(t, logger.SetLevel("unknown", zerolog.DebugLevel), logger.ErrUnknownModule)
+}
+
+func TestGormLoggerLevels(t *testing.T) {
+	read := initFileLogger(t, "info", map[string]string{logger.ModuleGORM: "warn"})
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.NewGormLogger(time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec("CREATE TABLE items (id INTEGER PRIMARY KEY)").Error)
+	assert.Error(t, db.Exec("SELECT * FROM missing_table").Error)
+
+	content := read()
+	assert.Contains(t, content, "query failed")
+	assert.Contains(t, content, "missing_table")
+	assert.NotContains(t, content, "CREATE TABLE") // Câu SQL chỉ log ở debug
+
+	// Query chậm hơn SlowThreshold log ở warn
+	slow := logger.NewGormLogger(time.Nanosecond)
+	slow.Trace(context.Background(), time.Now().Add(-time.Second), func() (string, int64) { return "SELECT 1", 1 }, nil)
+	assert.Contains(t, read(), "slow query")
+
+	// Bật debug lúc chạy: log câu SQL
+	require.NoError(t, logger.SetLevel(logger.ModuleGORM, zerolog.DebugLevel))
+	require.NoError(t, db.Exec("INSERT INTO items (id) VALUES (1)").Error)
+	assert.Contains(t, read(), "INSERT INTO items")
+}
+
+func TestLogLevelAdminService(t *testing.T) {
+	initFileLogger(t, "info", nil)
+	ctx := context.Background()
+	store := &memoryLevelStore{levels: map[string]zerolog.Level{}}
+	service := loglevel.NewService(store)
+
+	resp := service.SetLevel(ctx, logger.ModuleCron, "debug", 30*time.Minute)
+	require.Equal(t, http.StatusOK, response.GetHTTPStatusCode(resp.Code))
+	assert.Equal(t, logger.ModuleLevel{Module: "cron", Level: "debug", Override: "debug"}, resp.Data)
+	assert.Equal(t, map[string]zerolog.Level{"cron": zerolog.DebugLevel}, store.levels)
+
+	// Process khác nhận level qua store
+	logger.ReplaceOverrides(nil)
+	assert.Equal(t, zerolog.InfoLevel, logger.GetLevel(logger.ModuleCron))
+	require.NoError(t, logger.SyncLevels(ctx, store))
+	assert.Equal(t, zerolog.DebugLevel, logger.GetLevel(logger.ModuleCron))
+
+	resp = service.Reset(ctx, logger.ModuleCron)
+	require.Equal(t, http.StatusOK, response.GetHTTPStatusCode(resp.Code))
+	assert.Equal(t, zerolog.InfoLevel, logger.GetLevel(logger.ModuleCron))
+	assert.Empty(t, store.levels)
+
+	resp = service.SetLevel(ctx, "billing", "debug", 0)
+	assert.Equal(t, response.CodeLogModuleNotFound, resp.Code)
+
+	// Không có Redis: chỉ xem được level
+	unavailable := loglevel.NewService(nil)
+	assert.Equal(t, response.CodeLogLevelStoreUnavailable, unavailable.SetLevel(ctx, logger.ModuleCron, "debug", 0).Code)
+	levels := unavailable.List(ctx).Data.([]logger.ModuleLevel)
+	assert.Len(t, levels, len(logger.Modules))
+}
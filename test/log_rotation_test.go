@@ -0,0 +1,103 @@
+package test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listLogFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRotatingWriterSizeRotation(t *testing.T) {
+	dir := t.TempDir()
+	w, err := logger.NewRotatingWriter(filepath.Join(dir, "app.log"), logger.RotateOptions{MaxSize: 20})
+	require.NoError(t, err)
+
+	for _, line := range []string{"first line 123\n", "second line 45\n", "third line 678\n"} {
+		_, err := w.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app.1.log", "app.2.log", "app.log"}, listLogFiles(t, dir))
+	first, _ := os.ReadFile(filepath.Join(dir, "app.1.log"))
+	current, _ := os.ReadFile(filepath.Join(dir, "app.log"))
+	assert.Equal(t, "first line 123\n", string(first))
+	assert.Equal(t, "third line 678\n", string(current))
+
+	// Mở lại: tiếp tục file hiện tại và đánh số backup sau số lớn nhất
+	w, err = logger.NewRotatingWriter(filepath.Join(dir, "app.log"), logger.RotateOptions{MaxSize: 20})
+	require.NoError(t, err)
+	_, err = w.Write([]byte("fourth line 90\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.Equal(t, []string{"app.1.log", "app.2.log", "app.3.log", "app.log"}, listLogFiles(t, dir))
+}
+
+func TestRotatingWriterCompressAndRetention(t *testing.T) {
+	dir := t.TempDir()
+	today := time.Now().Format("2006-01-02")
+
+	// Backup từ lần chạy trước: 1 file quá hạn, file của logger khác không bị động tới
+	old := filepath.Join(dir, "app-2020-01-01.log")
+	require.NoError(t, os.WriteFile(old, []byte("old\n"), 0644))
+	past := time.Now().Add(-60 * 24 * time.Hour)
+	require.NoError(t, os.Chtimes(old, past, past))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "request-2020-01-01.log"), []byte("other\n"), 0644))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "request-2020-01-01.log"), past, past))
+
+	w, err := logger.NewRotatingWriter(filepath.Join(dir, "app.log"), logger.RotateOptions{
+		Daily:      true,
+		MaxSize:    10,
+		MaxBackups: 2,
+		MaxAge:     30 * 24 * time.Hour,
+		Compress:   true,
+	})
+	require.NoError(t, err)
+	for _, line := range []string{"line one\n", "line two\n", "line three\n", "line four\n"} {
+		_, err := w.Write([]byte(line))
+		require.NoError(t, err)
+		time.Sleep(10 * time.Millisecond) // Thứ tự theo thời gian sửa đổi
+	}
+	require.NoError(t, w.Close())
+
+	// Giữ 2 backup mới nhất (đã nén), xóa backup quá hạn
+	assert.Equal(t, []string{
+		"app-" + today + ".2.log.gz",
+		"app-" + today + ".3.log.gz",
+		"app-" + today + ".log",
+		"request-2020-01-01.log",
+	}, listLogFiles(t, dir))
+
+	file, err := os.Open(filepath.Join(dir, "app-"+today+".3.log.gz"))
+	require.NoError(t, err)
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "line three\n", string(content))
+
+	current, _ := os.ReadFile(filepath.Join(dir, "app-"+today+".log"))
+	assert.True(t, strings.HasPrefix(string(current), "line four"))
+}
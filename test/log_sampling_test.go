@@ -0,0 +1,80 @@
+package test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSampleRules(t *testing.T) {
+	rules, err := logger.ParseSampleRules(" get /status=0.01, /api/v1/*=0.5 ,")
+	require.NoError(t, err)
+	assert.Equal(t, []logger.SampleRule{
+		{Method: "GET", Route: "/status", Rate: 0.01},
+		{Route: "/api/v1/*", Rate: 0.5},
+	}, rules)
+
+	for _, invalid := range []string{"/status", "/status=2", "/status=-0.1", "GET /a /b=1", "status=0.5"} {
+		_, err := logger.ParseSampleRules(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	cfg := config.LoadLoggerConfig()
+	cfg.SampleRoutes = "GET /status=abc"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestRequestLogSampling(t *testing.T) {
+	rules, err := logger.ParseSampleRules("/api/v1/*=0.5,GET /api/v1/users=0,/api/v1/users=1,GET /api/v1/users/*=0.2,/status=0")
+	require.NoError(t, err)
+	require.NoError(t, logger.Init(logger.Config{Level: "info", Output: "console", SampleRules: rules}))
+	t.Cleanup(func() { _ = logger.Init(logger.Config{Level: "info", Output: "console"}) })
+
+	// Khớp chính xác ưu tiên hơn prefix, có method ưu tiên hơn không có method
+	assert.Equal(t, 0.0, logger.SampleRate(http.MethodGet, "/api/v1/users"))
+	assert.Equal(t, 1.0, logger.SampleRate(http.MethodPost, "/api/v1/users"))
+	assert.Equal(t, 0.2, logger.SampleRate(http.MethodGet, "/api/v1/users/{id}"))
+	assert.Equal(t, 0.5, logger.SampleRate(http.MethodDelete, "/api/v1/users/{id}"))
+	assert.Equal(t, 1.0, logger.SampleRate(http.MethodGet, "/health"))
+
+	var buf bytes.Buffer
+	previous := logger.RequestLogger
+	logger.RequestLogger = zerolog.New(&buf)
+	defer func() { logger.RequestLogger = previous }()
+
+	r := chi.NewRouter()
+	r.Use(logger.Middleware())
+	r.Get("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	r.Get("/api/v1/users", func(w http.ResponseWriter, r *http.Request) {})
+	r.Post("/api/v1/users", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusCreated) })
+
+	do := func(method, path string) string {
+		buf.Reset()
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(method, path, nil))
+		return buf.String()
+	}
+
+	// Rate 0: request thành công không được ghi, lỗi luôn được ghi
+	assert.Empty(t, do(http.MethodGet, "/status"))
+	assert.Empty(t, do(http.MethodGet, "/api/v1/users"))
+	assert.Contains(t, do(http.MethodGet, "/status?fail=1"), `"status":503`)
+
+	// Không bị sample: ghi đủ, không có sample_rate
+	line := do(http.MethodPost, "/api/v1/users")
+	assert.Contains(t, line, `"status":201`)
+	assert.NotContains(t, line, "sample_rate")
+	assert.Contains(t, do(http.MethodGet, "/unknown"), `"status":404`)
+}
@@ -0,0 +1,240 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCloudWatch ghi lại các request CreateLogStream/PutLogEvents
+type fakeCloudWatch struct {
+	mu      sync.Mutex
+	streams []string
+	events  []string
+	batches int
+	block   chan struct{} // khác nil: PutLogEvents chờ tới khi đóng
+}
+
+func (f *fakeCloudWatch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		LogGroupName  string `json:"logGroupName"`
+		LogStreamName string `json:"logStreamName"`
+		LogEvents     []struct {
+			Timestamp int64  `json:"timestamp"`
+			Message   string `json:"message"`
+		} `json:"logEvents"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") || body.LogGroupName != "api-core" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	switch r.Header.Get("X-Amz-Target") {
+	case "Logs_20140328.CreateLogStream":
+		f.mu.Lock()
+		f.streams = append(f.streams, body.LogStreamName)
+		f.mu.Unlock()
+	case "Logs_20140328.PutLogEvents":
+		if f.block != nil {
+			<-f.block
+		}
+		f.mu.Lock()
+		f.batches++
+		for _, event := range body.LogEvents {
+			f.events = append(f.events, event.Message)
+		}
+		f.mu.Unlock()
+	}
+	w.Write([]byte("{}"))
+}
+
+func (f *fakeCloudWatch) snapshot() ([]string, []string, int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.streams...), append([]string(nil), f.events...), f.batches
+}
+
+func startFakeCloudWatch(t *testing.T, fake *fakeCloudWatch) logger.CloudWatchConfig {
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	return logger.CloudWatchConfig{
+		Region:          "ap-southeast-1",
+		LogGroup:        "api-core",
+		LogStream:       "web-1",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		Endpoint:        server.URL,
+	}
+}
+
+func TestCloudWatchWriterBatches(t *testing.T) {
+	fake := &fakeCloudWatch{}
+	cfg := startFakeCloudWatch(t, fake)
+
+	writer, err := logger.NewCloudWatchWriter(context.Background(), cfg, "request", logger.BatchOptions{BatchSize: 2, FlushInterval: time.Hour})
+	require.NoError(t, err)
+
+	log := zerolog.New(writer)
+	for i := 0; i < 5; i++ {
+		log.Info().Int("n", i).Msg("hello")
+	}
+	require.NoError(t, writer.Close(context.Background()))
+
+	streams, events, batches := fake.snapshot()
+	assert.Equal(t, []string{"web-1/request"}, streams)
+	require.Len(t, events, 5)
+	assert.JSONEq(t, `{"level":"info","n":0,"message":"hello"}`, events[0])
+	assert.Equal(t, 3, batches) // 2 + 2 + phần còn lại khi Close
+	assert.Zero(t, writer.Dropped())
+}
+
+func TestBatchWriterDropsWhenBufferFull(t *testing.T) {
+	fake := &fakeCloudWatch{block: make(chan struct{})}
+	cfg := startFakeCloudWatch(t, fake)
+
+	writer, err := logger.NewCloudWatchWriter(context.Background(), cfg, "apicore", logger.BatchOptions{BatchSize: 1, BufferSize: 2, FlushInterval: time.Hour})
+	require.NoError(t, err)
+
+	// Sink bị treo: Write vẫn trả về ngay, dòng vượt buffer bị bỏ
+	log := zerolog.New(writer)
+	start := time.Now()
+	for i := 0; i < 20; i++ {
+		log.Info().Int("n", i).Msg("burst")
+	}
+	assert.Less(t, time.Since(start), time.Second)
+	assert.GreaterOrEqual(t, writer.Dropped(), uint64(17))
+
+	close(fake.block)
+	require.NoError(t, writer.Close(context.Background()))
+	_, events, _ := fake.snapshot()
+	assert.Equal(t, 20, len(events)+int(writer.Dropped()))
+}
+
+func TestSyslogWriterUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	writer, err := logger.NewSyslogWriter(logger.SyslogConfig{Network: "udp", Address: conn.LocalAddr().String(), Tag: "api", Facility: "local3"}, "request", logger.BatchOptions{})
+	require.NoError(t, err)
+
+	log := zerolog.New(writer)
+	log.Warn().Msg("disk almost full")
+	require.NoError(t, writer.Flush(context.Background()))
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	message := string(buf[:n])
+
+	// local3 (19) * 8 + warning (4) = 156
+	assert.True(t, strings.HasPrefix(message, "<156>1 "), message)
+	fields := strings.SplitN(message, " ", 8)
+	require.Len(t, fields, 8)
+	assert.Equal(t, "api", fields[3])
+	assert.Equal(t, "request", fields[5])
+	assert.Equal(t, "-", fields[6])
+	assert.JSONEq(t, `{"level":"warn","message":"disk almost full"}`, fields[7])
+	require.NoError(t, writer.Close(context.Background()))
+}
+
+func TestSyslogWriterTCPFraming(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 10)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		for {
+			length, err := reader.ReadString(' ')
+			if err != nil {
+				return
+			}
+			n, _ := strconv.Atoi(strings.TrimSpace(length))
+			message := make([]byte, n)
+			if _, err := io.ReadFull(reader, message); err != nil {
+				return
+			}
+			received <- string(message)
+		}
+	}()
+
+	writer, err := logger.NewSyslogWriter(logger.SyslogConfig{Network: "tcp", Address: listener.Addr().String()}, "apicore", logger.BatchOptions{})
+	require.NoError(t, err)
+	log := zerolog.New(writer)
+	log.Error().Msg("first")
+	log.Info().Msg("second")
+	require.NoError(t, writer.Close(context.Background()))
+
+	for _, expected := range []string{`<131>1 `, `<134>1 `} {
+		select {
+		case message := <-received:
+			assert.True(t, strings.HasPrefix(message, expected), message)
+			assert.Contains(t, message, " apicore ")
+		case <-time.After(2 * time.Second):
+			t.Fatal("syslog message not received")
+		}
+	}
+}
+
+func TestLoggerInitWithSyslogOutput(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.NoError(t, logger.Init(logger.Config{
+		Level:  "info",
+		Output: "syslog",
+		Syslog: logger.SyslogConfig{Network: "udp", Address: conn.LocalAddr().String()},
+	}))
+	t.Cleanup(func() { _ = logger.Init(logger.Config{Level: "info", Output: "console"}) })
+
+	logger.Info("via syslog")
+	require.NoError(t, logger.Close(context.Background()))
+
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Contains(t, string(buf[:n]), `"message":"via syslog"`)
+}
+
+func TestLoggerConfigValidatesSinks(t *testing.T) {
+	cfg := config.LoadLoggerConfig()
+	cfg.Output = "console, cloudwatch"
+	assert.Error(t, cfg.Validate())
+	cfg.CloudWatch.Region, cfg.CloudWatch.LogGroup = "ap-southeast-1", "api-core"
+	assert.NoError(t, cfg.Validate())
+
+	cfg.Output = "syslog"
+	cfg.Syslog.Network = "http"
+	assert.Error(t, cfg.Validate())
+	cfg.Syslog.Network, cfg.Syslog.Facility = "tcp", "local9"
+	assert.Error(t, cfg.Validate())
+	cfg.Syslog.Facility = "daemon"
+	assert.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,137 @@
+package test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// linkMailer ghi lại link trong email magic link
+type linkMailer struct {
+	links chan string
+}
+
+func (m *linkMailer) Send(message *email.EmailMessage) error { return nil }
+
+func (m *linkMailer) SendTemplate(message *email.EmailMessage, templatePath string, data interface{}) error {
+	m.links <- data.(map[string]interface{})["LoginURL"].(string)
+	return nil
+}
+
+type magicLinkFixture struct {
+	db      *gorm.DB
+	service *auth.Service
+	mailer  *linkMailer
+}
+
+func newMagicLinkFixture(t *testing.T) *magicLinkFixture {
+	t.Helper()
+	db := newAuthTestDB(t)
+	mailer := &linkMailer{links: make(chan string, 10)}
+	magicLink := auth.NewMagicLink(auth.MagicLinkConfig{
+		Secret:    "magic-link-secret",
+		TTL:       15 * time.Minute,
+		VerifyURL: "https://app.example.com/login/magic",
+	}, mailer)
+	service := auth.NewService(
+		repository.NewUserRepository(db), nil,
+		jwt.NewManager(jwt.Config{SecretKey: "test-secret-key-min-32-chars-long"}),
+		nil, nil, cache.NewMockCache(), nil, magicLink,
+		repository.NewRoleRepository(db), nil, nil,
+	)
+	return &magicLinkFixture{db: db, service: service, mailer: mailer}
+}
+
+// token gửi magic link và lấy token trong email
+func (f *magicLinkFixture) token(t *testing.T, address string) string {
+	t.Helper()
+	require.Equal(t, response.CodeMagicLinkSent, f.service.SendMagicLink(context.Background(), address).Code)
+	select {
+	case link := <-f.mailer.links:
+		parsed, err := url.Parse(link)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(link, "https://app.example.com/login/magic?token="))
+		return parsed.Query().Get("token")
+	case <-time.After(2 * time.Second):
+		t.Fatal("magic link email not sent")
+		return ""
+	}
+}
+
+func TestMagicLinkSingleUse(t *testing.T) {
+	f := newMagicLinkFixture(t)
+	user := createAuthTestUser(t, f.db, "magic@example.com", true)
+	token := f.token(t, user.Email)
+
+	resp := f.service.VerifyMagicLink(context.Background(), token)
+	require.Equal(t, response.CodeLoginSuccess, resp.Code)
+	assert.Equal(t, user.ID, resp.Data.(*auth.LoginResponse).User.ID)
+
+	// Dùng lại link đã dùng
+	assert.Equal(t, response.CodeMagicLinkInvalid, f.service.VerifyMagicLink(context.Background(), token).Code)
+}
+
+func TestMagicLinkExpired(t *testing.T) {
+	f := newMagicLinkFixture(t)
+	user := createAuthTestUser(t, f.db, "late@example.com", true)
+	token := f.token(t, user.Email)
+
+	later := clock.NewOffset(clock.New())
+	later.Advance(16 * time.Minute)
+	ctx := clock.WithContext(context.Background(), later)
+	assert.Equal(t, response.CodeMagicLinkExpired, f.service.VerifyMagicLink(ctx, token).Code)
+}
+
+func TestMagicLinkWrongToken(t *testing.T) {
+	f := newMagicLinkFixture(t)
+	user := createAuthTestUser(t, f.db, "wrong@example.com", true)
+	token := f.token(t, user.Email)
+
+	// Sửa chữ ký, token không đúng định dạng
+	payload, _, _ := strings.Cut(token, ".")
+	for _, bad := range []string{payload + ".AAAA", "not-a-token", ""} {
+		assert.Equal(t, response.CodeMagicLinkInvalid, f.service.VerifyMagicLink(context.Background(), bad).Code, bad)
+	}
+
+	// Chữ ký hợp lệ nhưng nonce không do server cấp
+	other := auth.NewMagicLink(auth.MagicLinkConfig{Secret: "magic-link-secret"}, nil)
+	forged := other.Sign(user.ID, "forged-nonce", time.Now().Add(time.Minute))
+	assert.Equal(t, response.CodeMagicLinkInvalid, f.service.VerifyMagicLink(context.Background(), forged).Code)
+
+	// Token gốc vẫn dùng được
+	assert.Equal(t, response.CodeLoginSuccess, f.service.VerifyMagicLink(context.Background(), token).Code)
+}
+
+func TestMagicLinkInactiveUser(t *testing.T) {
+	f := newMagicLinkFixture(t)
+
+	// User bị khóa không nhận được link
+	disabled := createAuthTestUser(t, f.db, "disabled@example.com", false)
+	assert.Equal(t, response.CodeMagicLinkSent, f.service.SendMagicLink(context.Background(), disabled.Email).Code)
+	select {
+	case <-f.mailer.links:
+		t.Fatal("magic link sent to inactive user")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// User bị khóa sau khi link đã được gửi
+	user := createAuthTestUser(t, f.db, "locked@example.com", true)
+	token := f.token(t, user.Email)
+	require.NoError(t, f.db.Model(&model.User{}).Where("id = ?", user.ID).Update("is_active", false).Error)
+	assert.Equal(t, response.CodeAccountDisabled, f.service.VerifyMagicLink(context.Background(), token).Code)
+}
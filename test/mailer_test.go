@@ -0,0 +1,236 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/mailer"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingDriver driver ghi lại message thay vì gửi
+type recordingDriver struct {
+	mu       sync.Mutex
+	messages []*mailer.Message
+}
+
+func (d *recordingDriver) Name() string { return "recording" }
+
+func (d *recordingDriver) Send(ctx context.Context, message *mailer.Message) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.messages = append(d.messages, message)
+	return nil
+}
+
+// writeMailTemplates tạo thư mục template, path -> nội dung
+func writeMailTemplates(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0o644))
+	}
+	return dir
+}
+
+// fakeEmailTranslations bản dịch thay cho i18n.T
+func fakeEmailTranslations(lang, key string, args ...interface{}) string {
+	translations := map[string]map[string]string{
+		"en": {"emails.welcome.subject": "Welcome, {{.Name}}!", "emails.greeting": "Hello"},
+		"vi": {"emails.welcome.subject": "Chào mừng {{.Name}}!", "emails.greeting": "Xin chào"},
+	}
+	if message, ok := translations[lang][key]; ok {
+		return message
+	}
+	return key
+}
+
+func TestMailerTemplates(t *testing.T) {
+	dir := writeMailTemplates(t, map[string]string{
+		"welcome.html":    `<p>{{t "emails.greeting"}} {{.Name}}</p>`,
+		"vi/welcome.html": `<p lang="{{locale}}">{{t "emails.greeting"}} {{.Name}}!</p>`,
+		"receipt.mjml":    `<mjml><mj-body>Receipt {{.Total}}</mj-body></mjml>`,
+		"receipt.txt":     `{{define "subject"}}Receipt #{{.ID}}{{end}}Total: {{.Total}}`,
+		"README.md":       `ignored`,
+	})
+
+	var compiled []string
+	templates, err := mailer.LoadTemplates(context.Background(), mailer.TemplateConfig{
+		Dir:           dir,
+		DefaultLocale: "en",
+		Translate:     fakeEmailTranslations,
+		MJML: mailer.MJMLFunc(func(ctx context.Context, source string) (string, error) {
+			compiled = append(compiled, source)
+			return strings.NewReplacer("<mjml><mj-body>", "<html><body>", "</mj-body></mjml>", "</body></html>").Replace(source), nil
+		}),
+	})
+	require.NoError(t, err)
+	assert.Len(t, compiled, 1, "MJML chỉ biên dịch một lần lúc load")
+	assert.False(t, templates.Has("README"))
+
+	data := map[string]interface{}{"Name": "<An>", "ID": 42, "Total": "100$"}
+
+	t.Run("default locale", func(t *testing.T) {
+		rendered, err := templates.Render("welcome", "", data)
+		require.NoError(t, err)
+		assert.Equal(t, "Welcome, <An>!", rendered.Subject)
+		assert.Equal(t, "<p>Hello &lt;An&gt;</p>", rendered.HTML)
+	})
+
+	t.Run("region locale falls back to base locale", func(t *testing.T) {
+		rendered, err := templates.Render("welcome", "vi-VN", data)
+		require.NoError(t, err)
+		assert.Equal(t, "Chào mừng <An>!", rendered.Subject)
+		assert.Equal(t, `<p lang="vi-vn">Xin chào &lt;An&gt;!</p>`, rendered.HTML)
+	})
+
+	t.Run("missing locale uses default template", func(t *testing.T) {
+		rendered, err := templates.Render("welcome", "fr", data)
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Hello &lt;An&gt;</p>", rendered.HTML)
+	})
+
+	t.Run("mjml and text subject block", func(t *testing.T) {
+		rendered, err := templates.Render("receipt", "en", data)
+		require.NoError(t, err)
+		assert.Equal(t, "Receipt #42", rendered.Subject)
+		assert.Equal(t, "<html><body>Receipt 100$</body></html>", rendered.HTML)
+		assert.Equal(t, "Total: 100$", rendered.Text)
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		_, err := templates.Render("missing", "en", data)
+		assert.Error(t, err)
+	})
+
+	t.Run("mjml without compiler", func(t *testing.T) {
+		_, err := mailer.LoadTemplates(context.Background(), mailer.TemplateConfig{Dir: dir})
+		assert.Error(t, err)
+	})
+}
+
+func TestMailerSendTemplate(t *testing.T) {
+	dir := writeMailTemplates(t, map[string]string{
+		"magic_link.html": `<a href="{{.LoginURL}}">{{t "emails.greeting"}}</a>`,
+		"welcome.html":    `<p>{{.Name}}</p>`,
+	})
+	templates, err := mailer.LoadTemplates(context.Background(), mailer.TemplateConfig{
+		Dir: dir, DefaultLocale: "en", Translate: fakeEmailTranslations,
+	})
+	require.NoError(t, err)
+
+	driver := &recordingDriver{}
+	m := mailer.New(driver, templates, "noreply@apicore.com", "ApiCore")
+
+	err = m.SendTemplate(context.Background(), &mailer.Message{To: []string{"an@example.com"}}, "welcome", "vi", map[string]string{"Name": "An"})
+	require.NoError(t, err)
+
+	// email.EmailService adapter: dùng template đã load theo tên file, giữ subject do caller set
+	service := mailer.NewEmailService(m)
+	err = service.SendTemplate(&email.EmailMessage{
+		To:      []string{"an@example.com"},
+		Subject: "Your sign-in link",
+		Attachments: []email.Attachment{
+			{Filename: "guide.pdf", Content: []byte("pdf"), MimeType: "application/pdf"},
+		},
+	}, "internal/templates/emails/magic_link.html", map[string]string{"LoginURL": "https://apicore.com/login"})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, m.Send(context.Background(), &mailer.Message{Subject: "x"}), mailer.ErrNoRecipients)
+
+	require.Len(t, driver.messages, 2)
+	welcome := driver.messages[0]
+	assert.Equal(t, "noreply@apicore.com", welcome.From)
+	assert.Equal(t, "ApiCore", welcome.FromName)
+	assert.Equal(t, "Chào mừng An!", welcome.Subject)
+	assert.Equal(t, "<p>An</p>", welcome.HTML)
+
+	magic := driver.messages[1]
+	assert.Equal(t, "Your sign-in link", magic.Subject)
+	assert.Equal(t, `<a href="https://apicore.com/login">Hello</a>`, magic.HTML)
+	require.Len(t, magic.Attachments, 1)
+	assert.Equal(t, "application/pdf", magic.Attachments[0].ContentType)
+}
+
+func TestSESDriver(t *testing.T) {
+	var (
+		authorization string
+		request       struct {
+			FromEmailAddress string
+			Destination      struct{ ToAddresses, BccAddresses []string }
+			Content          struct{ Raw struct{ Data []byte } }
+		}
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/email/outbound-emails" {
+			http.NotFound(w, r)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &request))
+
+		if request.Destination.ToAddresses[0] == "rejected@example.com" {
+			w.Header().Set("X-Amzn-ErrorType", "MessageRejected:http://internal.amazon.com/coral/com.amazonaws.sesv2/")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"Email address is not verified."}`))
+			return
+		}
+		w.Write([]byte(`{"MessageId":"0100-abc"}`))
+	}))
+	defer server.Close()
+
+	driver, err := mailer.NewSESDriver(context.Background(), mailer.SESConfig{
+		Region:          "ap-southeast-1",
+		AccessKeyID:     "AKIDTEST",
+		SecretAccessKey: "secret",
+		Endpoint:        server.URL,
+	})
+	require.NoError(t, err)
+	m := mailer.New(driver, nil, "noreply@apicore.com", "ApiCore")
+
+	err = m.Send(context.Background(), &mailer.Message{
+		To:      []string{"an@example.com"},
+		BCC:     []string{"audit@apicore.com"},
+		Subject: "Hóa đơn",
+		HTML:    `<p>Xem file đính kèm <img src="cid:logo"></p>`,
+		Text:    "Xem file đính kèm",
+		Attachments: []mailer.Attachment{
+			{Filename: "invoice.pdf", Content: []byte("%PDF-1.4")},
+			{Filename: "logo.png", Content: []byte("png"), ContentID: "logo"},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDTEST/"), authorization)
+	assert.Contains(t, authorization, "/ap-southeast-1/ses/aws4_request")
+	assert.Equal(t, "noreply@apicore.com", request.FromEmailAddress)
+	assert.Equal(t, []string{"audit@apicore.com"}, request.Destination.BccAddresses)
+
+	raw := string(request.Content.Raw.Data)
+	assert.Contains(t, raw, "multipart/mixed")
+	assert.Contains(t, raw, `filename="invoice.pdf"`)
+	assert.Contains(t, raw, "Content-Type: application/pdf")
+	assert.Contains(t, raw, "Content-ID: <logo>")
+	assert.NotContains(t, raw, "audit@apicore.com", "Bcc không nằm trong header")
+
+	err = m.Send(context.Background(), &mailer.Message{To: []string{"rejected@example.com"}, Subject: "x", Text: "x"})
+	var sesErr *mailer.SESError
+	require.True(t, errors.As(err, &sesErr))
+	assert.Equal(t, http.StatusBadRequest, sesErr.StatusCode)
+	assert.Equal(t, "MessageRejected", sesErr.Type)
+	assert.Equal(t, "Email address is not verified.", sesErr.Message)
+}
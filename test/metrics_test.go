@@ -0,0 +1,295 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/cron"
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeMetrics gọi handler như Prometheus, openMetrics chọn Accept header
+func scrapeMetrics(t *testing.T, registry *metrics.Registry, openMetrics bool) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if openMetrics {
+		req.Header.Set("Accept", "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5")
+	}
+	rec := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	return rec.Body.String()
+}
+
+func TestMetricsExposition(t *testing.T) {
+	registry := metrics.NewRegistry()
+	runs := registry.NewCounter("jobs_runs", "Job runs.", "job", "status")
+	depth := registry.NewGauge("jobs_depth", "Queue depth.", "queue")
+	duration := registry.NewHistogram("jobs_duration_seconds", "Duration.", []float64{1, 0.1}, "job")
+
+	runs.Inc("cleanup", "success")
+	runs.Add(2, "cleanup", "failure")
+	depth.Set(7, `we"ird`)
+	duration.Observe(0.05, "cleanup")
+	duration.Observe(0.5, "cleanup")
+	duration.Observe(3, "cleanup")
+
+	body := scrapeMetrics(t, registry, true)
+	for _, line := range []string{
+		"# TYPE jobs_runs counter",
+		`jobs_runs_total{job="cleanup",status="failure"} 2`,
+		`jobs_runs_total{job="cleanup",status="success"} 1`,
+		`jobs_depth{queue="we\"ird"} 7`,
+		`jobs_duration_seconds_bucket{job="cleanup",le="0.1"} 1`,
+		`jobs_duration_seconds_bucket{job="cleanup",le="1"} 2`,
+		`jobs_duration_seconds_bucket{job="cleanup",le="+Inf"} 3`,
+		`jobs_duration_seconds_sum{job="cleanup"} 3.55`,
+		`jobs_duration_seconds_count{job="cleanup"} 3`,
+	} {
+		assert.Contains(t, body, line+"\n")
+	}
+	assert.True(t, strings.HasSuffix(body, "# EOF\n"))
+
+	// Prometheus text format: TYPE khai báo tên có _total, không có # EOF
+	body = scrapeMetrics(t, registry, false)
+	assert.Contains(t, body, "# TYPE jobs_runs_total counter\n")
+	assert.NotContains(t, body, "# EOF")
+
+	depth.Delete(`we"ird`)
+	assert.NotContains(t, scrapeMetrics(t, registry, false), "jobs_depth")
+
+	// Cùng tên, cùng loại dùng lại family; khác loại là lỗi lập trình
+	assert.NotPanics(t, func() { registry.NewCounter("jobs_runs", "Job runs.", "job", "status") })
+	assert.Panics(t, func() { registry.NewGauge("jobs_runs", "Job runs.", "job", "status") })
+}
+
+type failingJob struct{}
+
+func (j *failingJob) Name() string                  { return "failing" }
+func (j *failingJob) Schedule() string              { return "@every 1s" }
+func (j *failingJob) Timeout() time.Duration        { return time.Second }
+func (j *failingJob) RetryCount() int               { return 1 }
+func (j *failingJob) RetryDelay() time.Duration     { return 10 * time.Millisecond }
+func (j *failingJob) Run(ctx context.Context) error { return errors.New("boom") }
+
+func TestSchedulerMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{
+		EnableMetrics: true,
+		MetricsPrefix: "test_cron",
+		Metrics:       registry,
+	})
+	require.NoError(t, scheduler.AddJob(&countingJob{}))
+	require.NoError(t, scheduler.AddJob(&failingJob{}))
+
+	// Series khởi tạo = 0 ngay khi đăng ký job
+	body := scrapeMetrics(t, registry, true)
+	assert.Contains(t, body, `test_cron_job_runs_total{job="failing",status="failure"} 0`)
+	assert.Contains(t, body, "test_cron_leader 0\n")
+
+	require.NoError(t, scheduler.Start(context.Background()))
+	defer scheduler.Stop()
+
+	assert.Eventually(t, func() bool {
+		body := scrapeMetrics(t, registry, true)
+		return strings.Contains(body, `test_cron_job_runs_total{job="failing",status="failure"} 1`) &&
+			strings.Contains(body, `test_cron_job_runs_total{job="counting",status="success"} 1`)
+	}, 3*time.Second, 50*time.Millisecond)
+
+	body = scrapeMetrics(t, registry, true)
+	assert.Contains(t, body, `test_cron_job_retries_total{job="failing"} 1`)
+	assert.Contains(t, body, `test_cron_job_last_success_timestamp_seconds{job="counting"}`)
+	assert.NotContains(t, body, `test_cron_job_last_success_timestamp_seconds{job="failing"}`)
+	assert.Contains(t, body, `test_cron_job_last_run_timestamp_seconds{job="failing"}`)
+	assert.Contains(t, body, `test_cron_job_duration_seconds_count{job="failing"} 1`)
+	assert.Contains(t, body, "test_cron_leader 1\n")
+}
+
+func TestSchedulerSecondsSinceLastSuccess(t *testing.T) {
+	registry := metrics.NewRegistry()
+	frozen := clock.NewFrozen(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{
+		EnableMetrics: true,
+		MetricsPrefix: "test_cron",
+		Metrics:       registry,
+		Clock:         frozen,
+	})
+	nightly := &nightlyJob{}
+	require.NoError(t, scheduler.AddJob(nightly))
+	require.NoError(t, scheduler.AddJob(&failingJob{}))
+	require.NoError(t, scheduler.Start(context.Background()))
+	defer scheduler.Stop()
+
+	// Chưa thành công lần nào: tính từ lúc đăng ký job
+	frozen.Advance(2 * time.Hour)
+	body := scrapeMetrics(t, registry, true)
+	assert.Contains(t, body, `test_cron_job_seconds_since_last_success{job="nightly"} 7200`)
+	assert.Contains(t, body, `test_cron_job_seconds_since_last_success{job="failing"} 7200`)
+
+	require.NoError(t, scheduler.TriggerJob("nightly"))
+	require.Eventually(t, func() bool {
+		return strings.Contains(scrapeMetrics(t, registry, true), `test_cron_job_runs_total{job="nightly",status="success"} 1`)
+	}, time.Second, 10*time.Millisecond)
+
+	frozen.Advance(30 * time.Minute)
+	body = scrapeMetrics(t, registry, true)
+	assert.Contains(t, body, `test_cron_job_seconds_since_last_success{job="nightly"} 1800`)
+	assert.Contains(t, body, `test_cron_job_seconds_since_last_success{job="failing"} 9000`)
+
+	// Job đã gỡ không còn bị alert
+	require.NoError(t, scheduler.RemoveJob("failing"))
+	body = scrapeMetrics(t, registry, true)
+	assert.NotContains(t, body, `test_cron_job_seconds_since_last_success{job="failing"}`)
+	assert.Contains(t, body, `test_cron_job_runs_total{job="failing",status="failure"}`, "counter được giữ lại")
+}
+
+// agedQueue chanQueue có oldest message age
+type agedQueue struct {
+	*chanQueue
+	age time.Duration
+}
+
+func (q *agedQueue) OldestMessageAge(ctx context.Context) (time.Duration, error) {
+	return q.age, nil
+}
+
+func TestQueueMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	queueMetrics := queue.NewMetrics(registry, "test_queue")
+
+	mailer := &recordingEmailService{failures: 1}
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{
+		Concurrency: 1,
+		MaxRetries:  2,
+		RetryDelay:  10 * time.Millisecond,
+		Metrics:     queueMetrics,
+	})
+	manager.RegisterAllHandlers(workers.NewHandlers(workers.NewEmailHandler(mailer), workers.NewPushHandler(nil), workers.NewSMSHandler(nil)))
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	q, err := queues.GetQueue(workers.QueueEmails)
+	require.NoError(t, err)
+
+	require.NoError(t, q.Push(ctx, &queue.Message{ID: "bad", Data: []byte("not-json"), Timestamp: time.Now().Add(-2 * time.Second)}))
+	data, err := json.Marshal(email.EmailMessage{To: []string{"jane@example.com"}, Subject: "Welcome"})
+	require.NoError(t, err)
+	require.NoError(t, q.Push(ctx, &queue.Message{ID: "welcome", Data: data, Timestamp: time.Now()}))
+
+	assert.Eventually(t, func() bool {
+		return strings.Contains(scrapeMetrics(t, registry, true), `test_queue_messages_processed_total{queue="emails",status="success"} 1`)
+	}, 2*time.Second, 10*time.Millisecond)
+
+	body := scrapeMetrics(t, registry, true)
+	assert.Contains(t, body, `test_queue_messages_processed_total{queue="emails",status="rejected"} 1`)
+	assert.Contains(t, body, `test_queue_messages_processed_total{queue="emails",status="failure"} 0`)
+	assert.Contains(t, body, `test_queue_message_retries_total{queue="emails"} 1`)
+	assert.Contains(t, body, `test_queue_message_lag_seconds_count{queue="emails"} 2`)
+	assert.Contains(t, body, `test_queue_message_lag_seconds_bucket{queue="emails",le="1"} 1`)
+	assert.Contains(t, body, `test_queue_depth{queue="emails"} 0`)
+	// chanQueue không implement AgeReporter
+	assert.NotContains(t, body, "test_queue_oldest_message_age_seconds")
+
+	// Queue implement AgeReporter export oldest message age
+	stuck := &agedQueue{chanQueue: &chanQueue{name: "stuck", messages: make(chan *queue.Message, 1)}, age: 90 * time.Second}
+	stuck.messages <- &queue.Message{ID: "old"}
+	queueMetrics.Watch(stuck)
+
+	body = scrapeMetrics(t, registry, true)
+	assert.Contains(t, body, `test_queue_depth{queue="stuck"} 1`)
+	assert.Contains(t, body, `test_queue_oldest_message_age_seconds{queue="stuck"} 90`)
+}
+
+func TestHTTPMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	r := chi.NewRouter()
+	r.Use(metrics.NewHTTPMetrics(registry, "test_http").Middleware)
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	r.Post("/users", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	})
+	r.With(metrics.RequireToken("s3cret")).Get("/metrics", registry.Handler().ServeHTTP)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/users/1", nil),
+		httptest.NewRequest(http.MethodGet, "/users/2", nil),
+		httptest.NewRequest(http.MethodPost, "/users", nil),
+		httptest.NewRequest(http.MethodGet, "/random/path", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	// Endpoint metrics yêu cầu bearer token
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer s3cret")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	for _, line := range []string{
+		`test_http_requests_total{method="GET",route="/users/{id}",status="200"} 2`,
+		`test_http_requests_total{method="POST",route="/users",status="422"} 1`,
+		`test_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`test_http_requests_total{method="GET",route="/metrics",status="401"} 1`,
+		`test_http_request_duration_seconds_count{method="GET",route="/users/{id}",status="200"} 2`,
+		`test_http_requests_in_flight 1`,
+	} {
+		assert.Contains(t, body, line)
+	}
+	assert.NotContains(t, body, "/users/1", "route pattern, không phải URL")
+}
+
+func TestRuntimeAndDBMetrics(t *testing.T) {
+	registry := metrics.NewRegistry()
+	metrics.RegisterRuntime(registry)
+	db := setupOutboxDB(t)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	metrics.RegisterDBStats(registry, "test_db", sqlDB)
+	cache.RegisterMetrics(registry, "test_cache")
+
+	require.NoError(t, db.Exec("SELECT 1").Error)
+
+	body := scrapeMetrics(t, registry, false)
+	for _, line := range []string{
+		`go_info{version="` + runtime.Version() + `"} 1`,
+		"# TYPE go_goroutines gauge",
+		"# TYPE go_gc_cycles_total counter",
+		"# TYPE go_memstats_heap_alloc_bytes gauge",
+		"# TYPE process_start_time_seconds gauge",
+		`test_db_connections{state="idle"} 1`,
+		`test_db_connections{state="in_use"} 0`,
+		`test_db_max_open_connections 1`,
+		`test_db_wait_total 0`,
+		`test_cache_lookups_total{result="hit"}`,
+		`test_cache_lookups_total{result="miss"}`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/app/notification"
+	"github.com/anhnq996/go-api-core/internal/app/user"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func setupNotifications(t *testing.T) repository.NotificationRepository {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	// Schema tương đương migration 000022 (sqlite không có gen_random_uuid, id mặc định không có dấu "-",
+	// test cần tìm theo id thì tự gán ID)
+	require.NoError(t, db.Exec(`CREATE TABLE notifications (
+		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))), user_id TEXT NOT NULL, type TEXT NOT NULL,
+		title TEXT NOT NULL, body TEXT, data TEXT, read_at DATETIME, created_at DATETIME)`).Error)
+	return repository.NewNotificationRepository(db)
+}
+
+func TestSendNotificationToUserSavesToInbox(t *testing.T) {
+	ctx := context.Background()
+	inbox := setupNotifications(t)
+	userID := uuid.New()
+
+	// FCM chưa cấu hình: push lỗi nhưng thông báo vẫn vào inbox
+	svc := user.NewService(nil, nil, inbox, nil, nil, nil, nil)
+	require.Error(t, svc.SendNotificationToUser(ctx, userID, "Đơn hàng", "Đơn hàng đã giao", map[string]string{"type": "order_delivered", "order_id": "42"}))
+	require.Error(t, svc.SendNotificationToUser(ctx, userID, "Xin chào", "Bạn có thông báo mới", nil))
+
+	notifications, total, err := inbox.FindByUserID(ctx, userID, false, 1, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, total)
+	types := []string{notifications[0].Type, notifications[1].Type}
+	assert.ElementsMatch(t, []string{"order_delivered", model.NotificationTypeGeneral}, types)
+	for _, n := range notifications {
+		if n.Type == "order_delivered" {
+			assert.Equal(t, map[string]string{"type": "order_delivered", "order_id": "42"}, n.Data)
+			assert.Equal(t, "Đơn hàng đã giao", n.Body)
+		}
+		assert.Nil(t, n.ReadAt)
+	}
+}
+
+func TestNotificationCenter(t *testing.T) {
+	ctx := context.Background()
+	inbox := setupNotifications(t)
+	svc := notification.NewService(inbox)
+	alice, bob := uuid.New(), uuid.New()
+
+	var ids []uuid.UUID
+	for i := 0; i < 3; i++ {
+		n := &model.Notification{ID: uuid.New(), UserID: alice, Type: model.NotificationTypeGeneral, Title: "Hi"}
+		require.NoError(t, inbox.Create(ctx, n))
+		ids = append(ids, n.ID)
+	}
+	require.NoError(t, inbox.Create(ctx, &model.Notification{UserID: bob, Type: model.NotificationTypeGeneral, Title: "Hi"}))
+
+	unreadCount := func(userID uuid.UUID) int64 {
+		resp := svc.UnreadCount(ctx, userID)
+		require.Equal(t, response.CodeSuccess, resp.Code)
+		return resp.Data.(map[string]int64)["unread_count"]
+	}
+	assert.EqualValues(t, 3, unreadCount(alice))
+
+	resp := svc.List(ctx, alice, &notification.ListRequest{Page: 1, PerPage: 2})
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	assert.EqualValues(t, 3, resp.Meta.Total)
+	assert.Equal(t, 2, resp.Meta.TotalPages)
+	assert.Len(t, resp.Data.(map[string]interface{})["items"], 2)
+
+	// Chỉ đánh dấu được thông báo của chính mình
+	assert.Equal(t, response.CodeNotificationNotFound, svc.MarkRead(ctx, bob, ids[0]).Code)
+	resp = svc.MarkRead(ctx, alice, ids[0])
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	readAt := resp.Data.(*model.Notification).ReadAt
+	require.NotNil(t, readAt)
+	// Đánh dấu lại giữ thời điểm đọc đầu tiên
+	resp = svc.MarkRead(ctx, alice, ids[0])
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	assert.True(t, readAt.Equal(*resp.Data.(*model.Notification).ReadAt))
+	assert.EqualValues(t, 2, unreadCount(alice))
+
+	resp = svc.List(ctx, alice, &notification.ListRequest{Page: 1, PerPage: 10, UnreadOnly: true})
+	assert.EqualValues(t, 2, resp.Meta.Total)
+
+	resp = svc.MarkAllRead(ctx, alice)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	assert.EqualValues(t, 2, resp.Data.(map[string]int64)["updated"])
+	assert.EqualValues(t, 0, unreadCount(alice))
+	assert.EqualValues(t, 1, unreadCount(bob))
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/internal/app/user"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/internal/outbox"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/internal/workers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueueNotificationToUserDeliveredByWorker(t *testing.T) {
+	ctx := context.Background()
+	db := setupOutboxDB(t)
+	// Schema tương đương migration 000022
+	require.NoError(t, db.Exec(`CREATE TABLE notifications (
+		id TEXT PRIMARY KEY, user_id TEXT NOT NULL, type TEXT NOT NULL,
+		title TEXT NOT NULL, body TEXT, data TEXT, read_at DATETIME, created_at DATETIME)`).Error)
+	inbox := repository.NewNotificationRepository(db)
+	devices := setupDeviceTokens(t)
+	pusher := device.NewPusher(startFakeFCM(t), devices)
+	svc := user.NewService(nil, nil, inbox, nil, nil, pusher, nil)
+
+	userID := uuid.New()
+	for _, token := range []string{"alive", "dead"} {
+		require.NoError(t, devices.Register(ctx, &model.DeviceToken{UserID: userID, Token: token, Platform: model.DevicePlatformAndroid, LastSeen: time.Now()}))
+	}
+
+	require.NoError(t, svc.QueueNotificationToUser(ctx, userID, "Đơn hàng", "Đơn hàng đã giao", map[string]string{"type": "order_delivered"}))
+
+	// Thông báo vào inbox ngay, push nằm trong outbox cùng transaction
+	notifications, total, err := inbox.FindByUserID(ctx, userID, false, 1, 10)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+
+	manager := &listQueueManager{queues: map[string]*listQueue{}}
+	published, err := outbox.NewPublisher(db, manager, outbox.DefaultOptions()).PublishPending(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+
+	q, err := manager.GetQueue(device.QueueNotifications)
+	require.NoError(t, err)
+	message, err := q.Pop(ctx)
+	require.NoError(t, err)
+	job, err := device.PushJob.Decode(message)
+	require.NoError(t, err)
+	assert.Equal(t, userID, job.Payload.UserID)
+	assert.Equal(t, notifications[0].ID.String(), job.Payload.Data["notification_id"])
+
+	// Worker gửi tới mọi thiết bị, token UNREGISTERED bị xóa
+	handler := workers.NewPushHandler(pusher)
+	require.NoError(t, handler.Handle(ctx, message))
+	remaining, err := devices.FindByUserID(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "alive", remaining[0].Token)
+
+	// User không còn thiết bị: job hoàn tất, không retry
+	_, err = devices.DeleteByToken(ctx, userID, "alive")
+	require.NoError(t, err)
+	assert.NoError(t, handler.Handle(ctx, message))
+}
+
+func TestPushHandlerRetriesWhenNoTokenDelivered(t *testing.T) {
+	ctx := context.Background()
+	handler := workers.NewPushHandler(device.NewPusher(startFakeFCM(t), setupDeviceTokens(t)))
+
+	// Token bị FCM từ chối đã được xóa: không retry
+	message, err := device.PushJob.NewMessage(device.Push{UserID: uuid.New(), Tokens: []string{"dead"}, Title: "Hi"})
+	require.NoError(t, err)
+	assert.NoError(t, handler.Handle(ctx, message))
+
+	// FCM chưa cấu hình ở worker: bỏ qua job
+	assert.NoError(t, workers.NewPushHandler(device.NewPusher(nil, nil)).Handle(ctx, message))
+}
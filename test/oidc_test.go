@@ -0,0 +1,265 @@
+package test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/oidc"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeIssuer OIDC issuer giả lập: discovery, JWKS và token endpoint
+type fakeIssuer struct {
+	server *httptest.Server
+
+	mu        sync.Mutex
+	kid       string
+	key       *rsa.PrivateKey
+	claims    gojwt.MapClaims // Claims của ID token trả về ở token endpoint
+	jwksCalls int
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	issuer := &fakeIssuer{}
+	issuer.rotateKey(t, "key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]interface{}{
+			"issuer":                 issuer.server.URL,
+			"authorization_endpoint": issuer.server.URL + "/authorize",
+			"token_endpoint":         issuer.server.URL + "/token",
+			"jwks_uri":               issuer.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		defer issuer.mu.Unlock()
+		issuer.jwksCalls++
+		writeJSON(w, map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": issuer.kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(issuer.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(issuer.key.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		issuer.mu.Lock()
+		claims := issuer.claims
+		issuer.mu.Unlock()
+		writeJSON(w, map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+			"id_token":     issuer.sign(t, claims),
+		})
+	})
+
+	issuer.server = httptest.NewServer(mux)
+	t.Cleanup(issuer.server.Close)
+	return issuer
+}
+
+func (i *fakeIssuer) rotateKey(t *testing.T, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.kid = kid
+	i.key = key
+}
+
+func (i *fakeIssuer) sign(t *testing.T, claims gojwt.MapClaims) string {
+	t.Helper()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	token := gojwt.NewWithClaims(gojwt.SigningMethodRS256, claims)
+	token.Header["kid"] = i.kid
+	raw, err := token.SignedString(i.key)
+	require.NoError(t, err)
+	return raw
+}
+
+// idClaims claims hợp lệ cho client "api-core"
+func (i *fakeIssuer) idClaims(now time.Time, nonce string) gojwt.MapClaims {
+	return gojwt.MapClaims{
+		"iss":   i.server.URL,
+		"sub":   "idp-user-1",
+		"aud":   "api-core",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": nonce,
+		"email": "Jane@Example.com",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func newTestOIDCProvider(issuer *fakeIssuer, c clock.Clock) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		IssuerURL:    issuer.server.URL,
+		ClientID:     "api-core",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:3000/api/v1/auth/social/oidc/callback",
+		Clock:        c,
+	})
+}
+
+func TestOIDCVerifyIDToken(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	c := clock.NewFrozen(time.Now())
+	provider := newTestOIDCProvider(issuer, c)
+	ctx := context.Background()
+
+	t.Run("valid", func(t *testing.T) {
+		raw := issuer.sign(t, issuer.idClaims(c.Now(), "nonce-1"))
+		token, err := provider.VerifyIDToken(ctx, raw, "nonce-1")
+		require.NoError(t, err)
+		assert.Equal(t, "idp-user-1", token.Subject)
+		assert.Equal(t, issuer.server.URL, token.Issuer)
+	})
+
+	t.Run("nonce mismatch", func(t *testing.T) {
+		raw := issuer.sign(t, issuer.idClaims(c.Now(), "nonce-1"))
+		_, err := provider.VerifyIDToken(ctx, raw, "nonce-2")
+		assert.ErrorIs(t, err, oidc.ErrNonceMismatch)
+	})
+
+	t.Run("wrong audience", func(t *testing.T) {
+		claims := issuer.idClaims(c.Now(), "nonce-1")
+		claims["aud"] = "other-client"
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, claims), "nonce-1")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("wrong issuer", func(t *testing.T) {
+		claims := issuer.idClaims(c.Now(), "nonce-1")
+		claims["iss"] = "https://evil.example.com"
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, claims), "nonce-1")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		claims := issuer.idClaims(c.Now().Add(-10*time.Minute), "nonce-1")
+		_, err := provider.VerifyIDToken(ctx, issuer.sign(t, claims), "nonce-1")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		raw, err := gojwt.NewWithClaims(gojwt.SigningMethodNone, issuer.idClaims(c.Now(), "nonce-1")).
+			SignedString(gojwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+		_, err = provider.VerifyIDToken(ctx, raw, "nonce-1")
+		assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	})
+}
+
+func TestOIDCKeyRotation(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	c := clock.NewFrozen(time.Now())
+	provider := newTestOIDCProvider(issuer, c)
+	ctx := context.Background()
+
+	_, err := provider.VerifyIDToken(ctx, issuer.sign(t, issuer.idClaims(c.Now(), "")), "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, issuer.jwksCalls)
+
+	// Key đã cache thì không tải lại JWKS
+	_, err = provider.VerifyIDToken(ctx, issuer.sign(t, issuer.idClaims(c.Now(), "")), "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, issuer.jwksCalls)
+
+	// Issuer xoay key: kid mới được tải lại, nhưng không quá 1 lần/phút
+	issuer.rotateKey(t, "key-2")
+	c.Advance(2 * time.Minute)
+	_, err = provider.VerifyIDToken(ctx, issuer.sign(t, issuer.idClaims(c.Now(), "")), "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, issuer.jwksCalls)
+
+	issuer.rotateKey(t, "key-3")
+	_, err = provider.VerifyIDToken(ctx, issuer.sign(t, issuer.idClaims(c.Now(), "")), "")
+	assert.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	assert.Equal(t, 2, issuer.jwksCalls)
+}
+
+func TestOIDCRoleMapping(t *testing.T) {
+	mapping, err := oidc.ParseRoleMapping("realm-admin:admin, support:moderator, *:user")
+	require.NoError(t, err)
+
+	assert.Equal(t, "admin", mapping.Resolve([]string{"support", "realm-admin"}))
+	assert.Equal(t, "moderator", mapping.Resolve([]string{"support"}))
+	assert.Equal(t, "user", mapping.Resolve(nil))
+
+	empty, err := oidc.ParseRoleMapping("")
+	require.NoError(t, err)
+	assert.Equal(t, "", empty.Resolve([]string{"realm-admin"}))
+
+	_, err = oidc.ParseRoleMapping("realm-admin")
+	assert.Error(t, err)
+}
+
+func TestOIDCSocialProvider(t *testing.T) {
+	issuer := newFakeIssuer(t)
+	c := clock.NewFrozen(time.Now())
+	ctx := context.Background()
+
+	mapping, err := oidc.ParseRoleMapping("realm-admin:admin,*:user")
+	require.NoError(t, err)
+	provider := auth.NewOIDCProvider(auth.OIDCConfig{
+		Provider:    newTestOIDCProvider(issuer, c),
+		Claims:      oidc.ClaimMapping{Roles: "realm_access.roles"},
+		RoleMapping: mapping,
+		TrustEmail:  true,
+	})
+	assert.Equal(t, auth.ProviderOIDC, provider.Name())
+
+	authURL, err := provider.AuthCodeURL(ctx, "state-1")
+	require.NoError(t, err)
+	assert.Contains(t, authURL, issuer.server.URL+"/authorize")
+	assert.Contains(t, authURL, "nonce=")
+
+	// ID token phải mang nonce đã gửi trong AuthCodeURL
+	parsed, err := http.NewRequest(http.MethodGet, authURL, nil)
+	require.NoError(t, err)
+	nonce := parsed.URL.Query().Get("nonce")
+
+	claims := issuer.idClaims(c.Now(), nonce)
+	claims["realm_access"] = map[string]interface{}{"roles": []string{"offline_access", "realm-admin"}}
+	issuer.claims = claims
+
+	user, err := provider.FetchUser(ctx, "code-1", "state-1")
+	require.NoError(t, err)
+	assert.Equal(t, "idp-user-1", user.ID)
+	assert.Equal(t, "jane@example.com", user.Email)
+	assert.True(t, user.EmailVerified)
+	assert.Equal(t, "admin", user.Role)
+
+	// State khác thì nonce khác, ID token bị từ chối
+	_, err = provider.FetchUser(ctx, "code-1", "state-2")
+	assert.ErrorIs(t, err, auth.ErrSocialExchangeFailed)
+}
@@ -0,0 +1,190 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/internal/outbox"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// outboxOrder model ghi message vào outbox khi được lưu
+type outboxOrder struct {
+	outbox.Events `gorm:"-"`
+
+	ID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Status string
+}
+
+// brokerDownManager queue backend không kết nối được
+type brokerDownManager struct {
+	*listQueueManager
+}
+
+func (m *brokerDownManager) GetQueue(name string) (queue.Queue, error) {
+	return nil, errors.New("connection refused")
+}
+
+func setupOutboxDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+
+	require.NoError(t, model.RegisterIDGenerator(db, model.IDConfig{DefaultVersion: utils.UUIDv7}))
+	require.NoError(t, outbox.RegisterCallbacks(db))
+	// Schema tương đương migration 000019
+	for _, ddl := range []string{
+		`CREATE TABLE outbox_messages (id TEXT PRIMARY KEY, queue TEXT NOT NULL, message TEXT NOT NULL,
+			attempts INTEGER NOT NULL DEFAULT 0, last_error TEXT, available_at DATETIME NOT NULL, published_at DATETIME,
+			created_at DATETIME)`,
+		`CREATE TABLE outbox_orders (id TEXT PRIMARY KEY, status TEXT)`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db
+}
+
+func TestOutboxPublishesOnlyCommittedMessages(t *testing.T) {
+	db := setupOutboxDB(t)
+	ctx := context.Background()
+	manager := &listQueueManager{queues: map[string]*listQueue{}}
+	publisher := outbox.NewPublisher(db, manager, outbox.DefaultOptions())
+
+	// Transaction rollback: message không được ghi
+	err := db.Transaction(func(tx *gorm.DB) error {
+		require.NoError(t, outbox.Enqueue(tx, "emails", &queue.Message{ID: "rolled-back", Data: []byte(`{}`)}))
+		return errors.New("business write failed")
+	})
+	require.Error(t, err)
+
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return outbox.Enqueue(tx, "emails", &queue.Message{ID: "committed", Data: []byte(`{"to":["a@example.com"]}`)})
+	}))
+
+	published, err := publisher.PublishPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+
+	emails, _ := manager.GetQueue("emails")
+	message, _ := emails.Pop(ctx)
+	require.NotNil(t, message)
+	assert.Equal(t, "committed", message.ID)
+	assert.JSONEq(t, `{"to":["a@example.com"]}`, string(message.Data))
+
+	// Đã publish thì không publish lại
+	published, err = publisher.PublishPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+	pending, err := publisher.Pending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, pending)
+}
+
+func TestOutboxRetriesWhenBrokerIsDown(t *testing.T) {
+	db := setupOutboxDB(t)
+	ctx := context.Background()
+	require.NoError(t, outbox.Enqueue(db, "emails", &queue.Message{ID: "m1", Data: []byte(`{}`)}))
+
+	down := outbox.NewPublisher(db, &brokerDownManager{}, outbox.DefaultOptions())
+	published, err := down.PublishPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+
+	var row model.OutboxMessage
+	require.NoError(t, db.First(&row).Error)
+	assert.Equal(t, 1, row.Attempts)
+	require.NotNil(t, row.LastError)
+	assert.Contains(t, *row.LastError, "connection refused")
+	assert.True(t, row.AvailableAt.After(time.Now()), "message phải được hẹn publish lại")
+	assert.Nil(t, row.PublishedAt)
+
+	// Broker lên lại, message tới hạn được publish
+	require.NoError(t, db.Model(&row).Update("available_at", time.Now().Add(-time.Second)).Error)
+	manager := &listQueueManager{queues: map[string]*listQueue{}}
+	published, err = outbox.NewPublisher(db, manager, outbox.DefaultOptions()).PublishPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published)
+}
+
+func TestOutboxDelayedMessageWaitsUntilAvailable(t *testing.T) {
+	db := setupOutboxDB(t)
+	ctx := context.Background()
+	require.NoError(t, outbox.Enqueue(db, "emails", &queue.Message{ID: "later", Delay: time.Hour}))
+
+	manager := &listQueueManager{queues: map[string]*listQueue{}}
+	published, err := outbox.NewPublisher(db, manager, outbox.DefaultOptions()).PublishPending(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, published)
+
+	var row model.OutboxMessage
+	require.NoError(t, db.First(&row).Error)
+	assert.Zero(t, row.Message.Delay, "delay được thực hiện bằng available_at")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), row.AvailableAt, time.Minute)
+}
+
+func TestOutboxRecorderWritesInSameTransaction(t *testing.T) {
+	db := setupOutboxDB(t)
+
+	order := &outboxOrder{ID: uuid.New(), Status: "paid"}
+	order.Record("emails", &queue.Message{ID: "order-paid"})
+	require.NoError(t, db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(order).Error
+	}))
+
+	var rows []model.OutboxMessage
+	require.NoError(t, db.Find(&rows).Error)
+	require.Len(t, rows, 1)
+	assert.Equal(t, "emails", rows[0].Queue)
+	assert.Equal(t, "order-paid", rows[0].Message.ID)
+
+	// Message đã ghi không bị ghi lại ở lần lưu sau
+	require.NoError(t, db.Model(order).Update("status", "shipped").Error)
+	var count int64
+	require.NoError(t, db.Model(&model.OutboxMessage{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// Rollback: cả order và message đều không được ghi
+	failed := &outboxOrder{ID: uuid.New(), Status: "paid"}
+	failed.Record("emails", &queue.Message{ID: "rolled-back"})
+	require.Error(t, db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(failed).Error; err != nil {
+			return err
+		}
+		return errors.New("payment failed")
+	}))
+	require.NoError(t, db.Model(&model.OutboxMessage{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestOutboxTreatsDuplicateMessageAsPublished(t *testing.T) {
+	db := setupOutboxDB(t)
+	ctx := context.Background()
+	queues := &uniqueQueueManager{listQueueManager: &listQueueManager{queues: map[string]*listQueue{}}, queues: map[string]*uniqueQueue{}}
+	reports, _ := queues.GetQueue("reports")
+	require.NoError(t, reports.Push(ctx, (&queue.Message{ID: "direct"}).Unique("report.export:u1", time.Minute)))
+
+	require.NoError(t, outbox.Enqueue(db, "reports", (&queue.Message{ID: "from-outbox"}).Unique("report.export:u1", time.Minute)))
+	published, err := outbox.NewPublisher(db, queues, outbox.DefaultOptions()).PublishPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, published, "message trùng không được publish lại mãi")
+
+	var row model.OutboxMessage
+	require.NoError(t, db.First(&row).Error)
+	assert.NotNil(t, row.PublishedAt)
+	assert.Zero(t, row.Attempts)
+	size, _ := reports.Size(ctx)
+	assert.Equal(t, int64(1), size)
+}
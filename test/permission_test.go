@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPermission(t *testing.T) {
+	cases := []struct {
+		granted  string
+		required string
+		want     bool
+	}{
+		{"users.view", "users.view", true},
+		{"users.*", "users.view", true},
+		{"users.*", "users.export.csv", true},
+		{"users.*", "users.*", true},
+		{"*", "roles.manage", true},
+		{"*.view", "users.view", true},
+		{"*.view", "users.delete", false},
+		{"users.view", "users.*", false},
+		{"users.*", "users", false},
+		{"users.*", "roles.view", false},
+		{"users.view", "users.view.all", false},
+		{"", "users.view", false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.want, jwt.MatchPermission(c.granted, c.required), "%s vs %s", c.granted, c.required)
+	}
+}
+
+func TestPermissionCheckerRequire(t *testing.T) {
+	loads := 0
+	checker := jwt.NewPermissionChecker(cache.NewMockCache(), func(ctx context.Context, userID string) ([]string, error) {
+		loads++
+		return []string{"users.view"}, nil
+	}, 0)
+
+	serve := func(claims *jwt.Claims, mw func(http.Handler) http.Handler) int {
+		req := httptest.NewRequest(http.MethodGet, "/users", nil)
+		if claims != nil {
+			req = req.WithContext(context.WithValue(req.Context(), jwt.ClaimsContextKey, claims))
+		}
+		rec := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Permissions trong token
+	tokenClaims := &jwt.Claims{UserID: "u1", Metadata: map[string]interface{}{
+		jwt.MetadataPermissions: []interface{}{"users.*"},
+	}}
+	assert.Equal(t, http.StatusOK, serve(tokenClaims, checker.Require("users.delete")))
+	assert.Equal(t, http.StatusForbidden, serve(tokenClaims, checker.Require("roles.view")))
+	assert.Equal(t, http.StatusOK, serve(tokenClaims, checker.RequirePermission("users.view", "users.delete")))
+	assert.Equal(t, http.StatusForbidden, serve(tokenClaims, checker.RequirePermission("users.view", "roles.view")))
+	assert.Equal(t, 0, loads)
+
+	// Fallback sang loader, kết quả được cache
+	lookupClaims := &jwt.Claims{UserID: "u2"}
+	assert.Equal(t, http.StatusOK, serve(lookupClaims, checker.Require("users.view")))
+	assert.Equal(t, http.StatusForbidden, serve(lookupClaims, checker.Require("users.view", "users.delete")))
+	assert.Equal(t, http.StatusOK, serve(lookupClaims, checker.RequireAny("users.delete", "users.view")))
+	assert.Equal(t, 1, loads)
+
+	assert.Equal(t, http.StatusUnauthorized, serve(nil, checker.Require("users.view")))
+}
@@ -0,0 +1,242 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryS3 S3-compatible endpoint (path-style) lưu object trong bộ nhớ, nhận cả presigned PUT và POST form
+type memoryS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newMemoryS3(t *testing.T, bucket string) (*memoryS3, *httptest.Server) {
+	m := &memoryS3{objects: map[string][]byte{}, types: map[string]string{}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"+bucket), "/")
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			m.objects[key], m.types[key] = body, r.Header.Get("Content-Type")
+			w.Header().Set("ETag", `"etag"`)
+		case http.MethodPost:
+			require.NoError(t, r.ParseMultipartForm(1<<20))
+			file, _, err := r.FormFile("file")
+			require.NoError(t, err)
+			body, _ := io.ReadAll(file)
+			key = r.FormValue("key")
+			m.objects[key], m.types[key] = body, r.FormValue("Content-Type")
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodHead, http.MethodGet:
+			body, ok := m.objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", m.types[key])
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.Header().Set("ETag", `"etag"`)
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			if r.Method == http.MethodGet {
+				w.Write(body)
+			}
+		case http.MethodDelete:
+			delete(m.objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return m, server
+}
+
+func (m *memoryS3) has(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.objects[key]
+	return ok
+}
+
+func newPresignedUploadService(t *testing.T, configure ...func(*storage.Config)) (*upload.Service, *memoryS3) {
+	t.Helper()
+	s3, server := newMemoryS3(t, "uploads")
+
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "s3"
+	cfg.S3 = storage.S3Config{
+		Bucket:          "uploads",
+		Region:          "us-east-1",
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio-secret",
+		Endpoint:        strings.TrimPrefix(server.URL, "http://"),
+		ForcePathStyle:  true,
+		DisableSSL:      true,
+	}
+	cfg.Validation = storage.ValidationConfig{MaxFileSize: 1024 * 1024}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	for _, fn := range configure {
+		fn(&cfg)
+	}
+
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	return upload.NewService(manager, cache.NewMockCache(), upload.Config{Enabled: true, Expiration: 10 * time.Minute}), s3
+}
+
+func TestPresignedUploadPutAndConfirm(t *testing.T) {
+	svc, s3 := newPresignedUploadService(t)
+	ctx := context.Background()
+
+	resp := svc.Create(ctx, "user-1", upload.CreatePresignedUploadRequest{
+		Filename: "report.pdf", ContentType: "application/pdf", Size: int64(len(samplePDF)), Category: "document",
+	})
+	require.Equal(t, response.CodeCreated, resp.Code)
+	presigned := resp.Data.(upload.PresignedUploadResponse)
+	assert.Equal(t, http.MethodPut, presigned.Method)
+	assert.Contains(t, presigned.URL, "X-Amz-Signature=")
+	assert.Regexp(t, `^uploads/report_.+\.pdf$`, presigned.Path)
+	assert.Equal(t, "application/pdf", presigned.Headers["Content-Type"])
+
+	// Chưa upload
+	resp = svc.Confirm(ctx, "user-1", presigned.ID)
+	assert.Equal(t, response.CodeUploadIncomplete, resp.Code)
+	assert.Equal(t, http.StatusConflict, response.GetHTTPStatusCode(resp.Code))
+
+	// Client upload thẳng lên storage
+	req, err := http.NewRequest(presigned.Method, presigned.URL, bytes.NewReader(samplePDF))
+	require.NoError(t, err)
+	for name, value := range presigned.Headers {
+		req.Header.Set(name, value)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	// Upload của user khác
+	assert.Equal(t, response.CodeUploadNotFound, svc.Confirm(ctx, "user-2", presigned.ID).Code)
+
+	resp = svc.Confirm(ctx, "user-1", presigned.ID)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	result := resp.Data.(*storage.UploadResult)
+	assert.Equal(t, presigned.Path, result.Path)
+	assert.Equal(t, int64(len(samplePDF)), result.Size)
+	assert.True(t, s3.has(presigned.Path))
+
+	// Đã xác nhận thì không xác nhận lại được
+	assert.Equal(t, response.CodeUploadNotFound, svc.Confirm(ctx, "user-1", presigned.ID).Code)
+}
+
+func TestPresignedUploadRejectsMismatchedObject(t *testing.T) {
+	svc, s3 := newPresignedUploadService(t)
+	ctx := context.Background()
+
+	// Khai báo ảnh PNG nhưng upload PDF cùng kích thước
+	resp := svc.Create(ctx, "user-1", upload.CreatePresignedUploadRequest{
+		Filename: "avatar.png", ContentType: "image/png", Size: int64(len(samplePDF)), Category: "image",
+	})
+	require.Equal(t, response.CodeCreated, resp.Code)
+	presigned := resp.Data.(upload.PresignedUploadResponse)
+
+	req, err := http.NewRequest(http.MethodPut, presigned.URL, bytes.NewReader(samplePDF))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "image/png")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	resp = svc.Confirm(ctx, "user-1", presigned.ID)
+	assert.Equal(t, response.CodeUploadVerificationFailed, resp.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.GetHTTPStatusCode(resp.Code))
+	assert.False(t, s3.has(presigned.Path), "object không hợp lệ phải bị xóa")
+}
+
+func TestPresignedUploadPostPolicy(t *testing.T) {
+	svc, _ := newPresignedUploadService(t)
+	ctx := context.Background()
+
+	resp := svc.Create(ctx, "user-1", upload.CreatePresignedUploadRequest{
+		Filename: "report.pdf", ContentType: "application/pdf", Size: int64(len(samplePDF)), Category: "document", Method: "POST",
+	})
+	require.Equal(t, response.CodeCreated, resp.Code)
+	presigned := resp.Data.(upload.PresignedUploadResponse)
+	assert.Equal(t, http.MethodPost, presigned.Method)
+	assert.Equal(t, presigned.Path, presigned.Fields["key"])
+	assert.Equal(t, "application/pdf", presigned.Fields["Content-Type"])
+
+	policy, err := base64.StdEncoding.DecodeString(presigned.Fields["policy"])
+	require.NoError(t, err)
+	assert.Contains(t, string(policy), `["content-length-range",1,`+strconv.Itoa(len(samplePDF))+`]`)
+	assert.Contains(t, string(policy), `{"Content-Type":"application/pdf"}`)
+
+	// Form upload từ browser: fields trước, file sau cùng
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for name, value := range presigned.Fields {
+		require.NoError(t, form.WriteField(name, value))
+	}
+	part, err := form.CreateFormFile("file", "report.pdf")
+	require.NoError(t, err)
+	part.Write(samplePDF)
+	require.NoError(t, form.Close())
+
+	res, err := http.Post(presigned.URL, form.FormDataContentType(), &body)
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+
+	assert.Equal(t, response.CodeSuccess, svc.Confirm(ctx, "user-1", presigned.ID).Code)
+}
+
+func TestPresignedUploadValidation(t *testing.T) {
+	svc, _ := newPresignedUploadService(t)
+	ctx := context.Background()
+
+	resp := svc.Create(ctx, "user-1", upload.CreatePresignedUploadRequest{
+		Filename: "big.pdf", ContentType: "application/pdf", Size: 51 * 1024 * 1024, Category: "document", // Quá giới hạn 50MB của document
+	})
+	assert.Equal(t, response.CodeValidationFailed, resp.Code)
+
+	resp = svc.Create(ctx, "user-1", upload.CreatePresignedUploadRequest{
+		Filename: "run.exe", ContentType: "application/x-msdownload", Size: 10, Category: "document",
+	})
+	assert.Equal(t, response.CodeValidationFailed, resp.Code)
+
+	// Storage local không hỗ trợ upload trực tiếp
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: t.TempDir(), BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes = nil, nil
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	local := upload.NewService(manager, cache.NewMockCache(), upload.Config{Enabled: true})
+
+	resp = local.Create(ctx, "user-1", upload.CreatePresignedUploadRequest{
+		Filename: "report.pdf", ContentType: "application/pdf", Size: int64(len(samplePDF)), Category: "document",
+	})
+	assert.Equal(t, response.CodePresignedUploadUnsupported, resp.Code)
+	assert.Equal(t, response.CodeUploadNotFound, local.Confirm(ctx, "user-1", "missing").Code)
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPublicPackagesDoNotImportInternal pkg/ là public API, không được phụ thuộc code riêng của app (docs/sdk.md)
+func TestPublicPackagesDoNotImportInternal(t *testing.T) {
+	data, err := os.ReadFile("../go.mod")
+	require.NoError(t, err)
+	module, _, _ := strings.Cut(strings.TrimPrefix(string(data), "module "), "\n")
+	require.Equal(t, "github.com/anhnq996/go-api-core", module)
+
+	forbidden := []string{module + "/internal", module + "/cmd", module + "/database", module + "/test"}
+	err = filepath.WalkDir("../pkg", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") {
+			return err
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, parser.ImportsOnly)
+		if err != nil {
+			return err
+		}
+		for _, spec := range file.Imports {
+			importPath, _ := strconv.Unquote(spec.Path.Value)
+			for _, prefix := range forbidden {
+				assert.False(t, importPath == prefix || strings.HasPrefix(importPath, prefix+"/"), "%s imports %s", path, importPath)
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,192 @@
+package test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/queueadmin"
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryControl queue.Control in-memory (RedisControl dùng chung giữa các process)
+type memoryControl struct {
+	mu         sync.Mutex
+	paused     map[string]bool
+	registered []string
+}
+
+func newMemoryControl() *memoryControl {
+	return &memoryControl{paused: map[string]bool{}}
+}
+
+func (c *memoryControl) Pause(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.paused[name] = true
+	return nil
+}
+func (c *memoryControl) Resume(ctx context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.paused, name)
+	return nil
+}
+func (c *memoryControl) IsPaused(ctx context.Context, name string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.paused[name], nil
+}
+func (c *memoryControl) Register(ctx context.Context, names ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.registered = append(c.registered, names...)
+	return nil
+}
+func (c *memoryControl) Registered(ctx context.Context) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	names := slices.Clone(c.registered)
+	slices.Sort(names)
+	return slices.Compact(names), nil
+}
+
+func TestQueueAdminListsAndManagesQueues(t *testing.T) {
+	ctx := context.Background()
+	manager := &listQueueManager{queues: map[string]*listQueue{}}
+	control := newMemoryControl()
+	require.NoError(t, control.Register(ctx, "emails", "reports"))
+
+	emails, _ := manager.GetQueue("emails")
+	dead, _ := manager.GetQueue(queue.DeadQueueName("emails"))
+	require.NoError(t, emails.Push(ctx, &queue.Message{ID: "m1"}))
+	require.NoError(t, emails.Push(ctx, &queue.Message{ID: "m2"}))
+	require.NoError(t, dead.Push(ctx, &queue.Message{ID: "d1", RetryCount: 3, Headers: map[string]string{queue.HeaderDeadReason: "smtp down"}}))
+
+	svc := queueadmin.NewService(manager, control)
+
+	resp := svc.ListQueues(ctx)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	queues := resp.Data.([]queueadmin.QueueInfo)
+	require.Len(t, queues, 2, "dead queue không được liệt kê riêng")
+	assert.Equal(t, "emails", queues[0].Name)
+	assert.Equal(t, int64(2), queues[0].Size)
+	assert.Equal(t, int64(1), queues[0].DeadSize)
+	assert.Nil(t, queues[0].OldestMessageAgeSeconds, "queue không implement AgeReporter")
+	assert.Equal(t, "reports", queues[1].Name)
+
+	// Tên tùy ý không được tạo queue mới
+	resp = svc.GetQueue(ctx, "unknown")
+	assert.Equal(t, response.CodeQueueNotFound, resp.Code)
+	assert.Equal(t, 404, response.GetHTTPStatusCode(resp.Code))
+	assert.NotContains(t, manager.queues, "unknown")
+
+	resp = svc.SetPaused(ctx, "emails", true)
+	require.Equal(t, response.CodeUpdated, resp.Code)
+	assert.True(t, resp.Data.(*queueadmin.QueueInfo).Paused)
+	paused, _ := control.IsPaused(ctx, "emails")
+	assert.True(t, paused)
+
+	resp = svc.ListDead(ctx, "emails", 0, 10)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	messages := resp.Data.([]*queue.Message)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "smtp down", queue.DeadReason(messages[0]))
+
+	resp = svc.RequeueDead(ctx, "emails", nil)
+	require.Equal(t, response.CodeUpdated, resp.Code)
+	assert.Equal(t, 1, resp.Data.(queueadmin.CountResult).Count)
+	size, _ := emails.Size(ctx)
+	assert.Equal(t, int64(3), size)
+	size, _ = dead.Size(ctx)
+	assert.Zero(t, size)
+
+	resp = svc.Purge(ctx, "emails")
+	require.Equal(t, response.CodeDeleted, resp.Code)
+	assert.Equal(t, 3, resp.Data.(queueadmin.CountResult).Count)
+	size, _ = emails.Size(ctx)
+	assert.Zero(t, size)
+}
+
+func TestQueueAdminUnavailableWithoutBackend(t *testing.T) {
+	ctx := context.Background()
+	svc := queueadmin.NewService(nil, nil)
+
+	resp := svc.ListQueues(ctx)
+	assert.Equal(t, response.CodeQueueUnavailable, resp.Code)
+	assert.Equal(t, 503, response.GetHTTPStatusCode(resp.Code))
+
+	// Có queue backend nhưng không kết nối được Redis: xem được, không pause được
+	manager := &listedQueueManager{&listQueueManager{queues: map[string]*listQueue{}}}
+	emails, _ := manager.GetQueue("emails")
+	require.NoError(t, emails.Push(ctx, &queue.Message{ID: "m1"}))
+	svc = queueadmin.NewService(manager, nil)
+
+	resp = svc.GetQueue(ctx, "emails")
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	assert.Equal(t, int64(1), resp.Data.(*queueadmin.QueueInfo).Size)
+	assert.Equal(t, response.CodeQueueUnavailable, svc.SetPaused(ctx, "emails", true).Code)
+}
+
+// listedQueueManager listQueueManager liệt kê được queue như Redis backend
+type listedQueueManager struct {
+	*listQueueManager
+}
+
+func (m *listedQueueManager) ListQueues(ctx context.Context) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.queues))
+	for name := range m.queues {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func TestWorkerSkipsPausedQueue(t *testing.T) {
+	ctx := context.Background()
+	queues := &listQueueManager{queues: map[string]*listQueue{}}
+	control := newMemoryControl()
+	require.NoError(t, control.Pause(ctx, "reports"))
+
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, RetryDelay: time.Millisecond, Control: control})
+	handled := make(chan string, 1)
+	manager.RegisterHandler("report.export", func(ctx context.Context, message *queue.Message) error {
+		handled <- message.ID
+		return nil
+	}, workers.OnQueue("reports"))
+
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	registered, _ := control.Registered(ctx)
+	assert.Contains(t, registered, "reports", "worker ghi nhận queue cho admin API")
+
+	message, err := queue.NewJobMessage("report.export", "r1")
+	require.NoError(t, err)
+	reports, _ := queues.GetQueue("reports")
+	require.NoError(t, reports.Push(ctx, message))
+
+	select {
+	case <-handled:
+		t.Fatal("paused queue must not be consumed")
+	case <-time.After(300 * time.Millisecond):
+	}
+	size, _ := reports.Size(ctx)
+	assert.Equal(t, int64(1), size)
+
+	require.NoError(t, control.Resume(ctx, "reports"))
+	select {
+	case id := <-handled:
+		assert.Equal(t, message.ID, id)
+	case <-time.After(3 * time.Second):
+		t.Fatal("queue was not resumed")
+	}
+}
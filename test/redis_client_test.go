@@ -0,0 +1,70 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/redisclient"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisClientModes(t *testing.T) {
+	t.Setenv("REDIS_MODE", "sentinel")
+	t.Setenv("REDIS_ADDRS", "sentinel-1:26379, sentinel-2:26379")
+	t.Setenv("REDIS_SENTINEL_MASTER", "mymaster")
+	t.Setenv("REDIS_TLS", "true")
+	t.Setenv("REDIS_TLS_SERVER_NAME", "redis.internal")
+
+	cfg := config.GetDefaultCacheConfig()
+	require.NoError(t, cfg.Validate())
+	redisConfig := cfg.RedisConfig()
+	assert.Equal(t, []string{"sentinel-1:26379", "sentinel-2:26379"}, redisConfig.Addrs)
+	require.NotNil(t, redisConfig.TLS)
+	assert.Equal(t, "redis.internal", redisConfig.TLS.ServerName)
+
+	// Sentinel: failover client, vẫn là *redis.Client
+	client, err := config.NewRedisClient(cfg)
+	require.NoError(t, err)
+	defer client.Close()
+	assert.IsType(t, &redis.Client{}, client)
+	assert.False(t, redisclient.IsCluster(client))
+
+	// Queue dùng chung mode/TLS với cache, host/port theo QUEUE_*
+	t.Setenv("QUEUE_HOST", "queue-redis")
+	queueConfig := config.LoadQueueConfig()
+	require.NoError(t, queueConfig.Validate())
+	redisQueue := queueConfig.ToQueueConfig().Redis
+	require.NotNil(t, redisQueue)
+	assert.Equal(t, redisclient.ModeSentinel, redisQueue.Mode)
+	assert.Equal(t, "mymaster", redisQueue.MasterName)
+	assert.Equal(t, "queue-redis", redisQueue.Host)
+
+	t.Setenv("REDIS_SENTINEL_MASTER", "")
+	assert.Error(t, config.GetDefaultCacheConfig().Validate(), "sentinel thiếu master name")
+
+	t.Setenv("REDIS_MODE", "cluster")
+	cluster, err := config.NewRedisClient(config.GetDefaultCacheConfig())
+	require.NoError(t, err)
+	defer cluster.Close()
+	assert.True(t, redisclient.IsCluster(cluster))
+
+	t.Setenv("REDIS_DB", "2")
+	assert.Error(t, config.GetDefaultCacheConfig().Validate(), "cluster chỉ hỗ trợ DB 0")
+
+	t.Setenv("REDIS_MODE", "replica")
+	_, err = config.NewRedisClient(config.GetDefaultCacheConfig())
+	assert.Error(t, err)
+}
+
+func TestRedisClientStandaloneDefault(t *testing.T) {
+	client, err := redisclient.New(redisclient.Config{Host: "localhost", Port: "6379"})
+	require.NoError(t, err)
+	defer client.Close()
+
+	standalone, ok := client.(*redis.Client)
+	require.True(t, ok)
+	assert.Equal(t, "localhost:6379", standalone.Options().Addr)
+}
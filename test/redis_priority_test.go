@@ -0,0 +1,86 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errCommandRecorded = errors.New("command recorded")
+
+// commandRecorder hook ghi lại lệnh gửi tới Redis và không gửi đi (test không cần Redis server)
+type commandRecorder struct {
+	mu   sync.Mutex
+	args [][]interface{}
+}
+
+func (h *commandRecorder) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.args = append(h.args, cmd.Args())
+	return ctx, errCommandRecorded
+}
+
+func (h *commandRecorder) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	return nil
+}
+
+func (h *commandRecorder) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, cmd := range cmds {
+		h.args = append(h.args, cmd.Args())
+	}
+	return ctx, errCommandRecorded
+}
+
+func (h *commandRecorder) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	return nil
+}
+
+func (h *commandRecorder) last() []interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.args[len(h.args)-1]
+}
+
+func TestRedisQueuePriorityKeys(t *testing.T) {
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	defer client.Close()
+	recorder := &commandRecorder{}
+	client.AddHook(recorder)
+	emails := queue.NewRedisQueue(client, "emails", &queue.QueueConfig{})
+
+	for _, tc := range []struct {
+		priority int
+		key      string
+	}{
+		{0, "queue:emails"},
+		{-3, "queue:emails"},
+		{5, "{queue:emails}:priority:5"},
+		{100, fmt.Sprintf("{queue:emails}:priority:%d", queue.RedisMaxPriority)},
+	} {
+		err := emails.Push(ctx, &queue.Message{ID: "m1", Priority: tc.priority})
+		require.ErrorIs(t, err, errCommandRecorded)
+		assert.Equal(t, "lpush", recorder.last()[0])
+		assert.Equal(t, tc.key, recorder.last()[1], "priority %d", tc.priority)
+	}
+
+	// Các list được đọc theo thứ tự pop: priority cao nhất trước, list mặc định cuối
+	recorder.args = nil
+	_, err := emails.Size(ctx)
+	require.ErrorIs(t, err, errCommandRecorded)
+	require.Len(t, recorder.args, queue.RedisMaxPriority+1)
+	assert.Equal(t, []interface{}{"llen", fmt.Sprintf("{queue:emails}:priority:%d", queue.RedisMaxPriority)}, recorder.args[0])
+	assert.Equal(t, []interface{}{"llen", "{queue:emails}:priority:1"}, recorder.args[queue.RedisMaxPriority-1])
+	assert.Equal(t, []interface{}{"llen", "queue:emails"}, recorder.args[queue.RedisMaxPriority])
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readerFromRecorder recorder có ReadFrom như http.response (sendfile)
+type readerFromRecorder struct {
+	*httptest.ResponseRecorder
+	readFromCalls int
+}
+
+func (r *readerFromRecorder) ReadFrom(src io.Reader) (int64, error) {
+	r.readFromCalls++
+	return io.Copy(r.ResponseRecorder, src)
+}
+
+func TestRequestLogBodyCapture(t *testing.T) {
+	var buf bytes.Buffer
+	previous := logger.RequestLogger
+	logger.RequestLogger = zerolog.New(&buf)
+	defer func() { logger.RequestLogger = previous }()
+
+	export := bytes.Repeat([]byte("x"), 64*1024)
+	r := chi.NewRouter()
+	r.Use(logger.Middleware())
+	r.Get("/small", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	})
+	r.Get("/large", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for i := 0; i < 30; i++ {
+			w.Write([]byte(strings.Repeat("a", 1000)))
+		}
+	})
+	r.Get("/export", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		http.ServeContent(w, r, "users.xlsx", time.Time{}, bytes.NewReader(export))
+	})
+	r.Get("/csv", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Write([]byte("id,name\n1,secret-row\n"))
+	})
+	r.Get("/events", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 0; i < 3; i++ {
+			w.Write([]byte("data: tick\n\n"))
+			require.NoError(t, http.NewResponseController(w).Flush())
+		}
+	})
+
+	do := func(path string) (*readerFromRecorder, string) {
+		buf.Reset()
+		rec := &readerFromRecorder{ResponseRecorder: httptest.NewRecorder()}
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec, buf.String()
+	}
+
+	_, line := do("/small")
+	assert.Contains(t, line, `"response_body":"{\"ok\":true}"`)
+	assert.Contains(t, line, `"response_size":11`)
+
+	// Vượt giới hạn: chỉ log size
+	_, line = do("/large")
+	assert.NotContains(t, line, "response_body")
+	assert.Contains(t, line, `"response_size":30000`)
+
+	// File export: không giữ body, ServeContent dùng ReadFrom của writer gốc
+	rec, line := do("/export")
+	assert.Equal(t, len(export), rec.Body.Len())
+	assert.Equal(t, 1, rec.readFromCalls)
+	assert.NotContains(t, line, "response_body")
+	assert.Contains(t, line, `"response_size":65536`)
+
+	// Content-Type có tham số
+	_, line = do("/csv")
+	assert.NotContains(t, line, "secret-row")
+
+	// Stream: Flush tới writer gốc, không log body
+	rec, line = do("/events")
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, 3, strings.Count(rec.Body.String(), "data: tick"))
+	assert.NotContains(t, line, "response_body")
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type formatTestProfile struct {
+	FirstName string            `json:"first_name"`
+	Metadata  map[string]string `json:"metadata"`
+	UserID    int64             `json:"user_id"`
+}
+
+func newFormatTestServer(cfg response.FormatConfig, resp response.Response) http.Handler {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.JSON(w, response.GetHTTPStatusCode(resp.Code), resp)
+	})
+	return response.FormatMiddleware(cfg)(handler)
+}
+
+func serveFormat(t *testing.T, h http.Handler, path string, headers map[string]string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec, body
+}
+
+func TestResponseFormatDefaultIsUnchanged(t *testing.T) {
+	resp := response.SuccessResponseWithMeta("en", response.CodeSuccess,
+		formatTestProfile{FirstName: "An", UserID: 1}, response.NewMeta(2, 10, 35))
+	h := newFormatTestServer(response.FormatConfig{Default: response.DefaultFormat}, *resp)
+
+	_, body := serveFormat(t, h, "/api/v1/users", nil)
+	assert.Equal(t, true, body["success"])
+	assert.Equal(t, "An", body["data"].(map[string]interface{})["first_name"])
+	assert.Contains(t, body["meta"], "per_page")
+}
+
+func TestResponseFormatCamelCase(t *testing.T) {
+	resp := response.SuccessResponseWithMeta("en", response.CodeSuccess, formatTestProfile{
+		FirstName: "An",
+		Metadata:  map[string]string{"device_id": "d1"},
+		UserID:    9007199254740993, // > 2^53: không được mất chính xác khi encode lại
+	}, response.NewMeta(1, 10, 35))
+	h := newFormatTestServer(response.FormatConfig{Default: response.DefaultFormat, AllowHeaderOverride: true}, *resp)
+
+	rec, _ := serveFormat(t, h, "/api/v1/users", map[string]string{response.HeaderResponseCase: "camel"})
+	assert.Contains(t, rec.Body.String(), `"userId":9007199254740993`)
+
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	data := body["data"].(map[string]interface{})
+	assert.Equal(t, "An", data["firstName"])
+	assert.NotContains(t, data, "first_name")
+	assert.Equal(t, map[string]interface{}{"deviceId": "d1"}, data["metadata"])
+	assert.Contains(t, body["meta"], "totalPages")
+	assert.Contains(t, rec.Header().Get("Vary"), response.HeaderResponseCase)
+
+	// Header không hợp lệ bị bỏ qua
+	_, body = serveFormat(t, h, "/api/v1/users", map[string]string{response.HeaderResponseCase: "kebab"})
+	assert.Contains(t, body["data"], "first_name")
+}
+
+func TestResponseFormatBareEnvelope(t *testing.T) {
+	cfg := response.FormatConfig{
+		Default: response.DefaultFormat,
+		Routes: []response.RouteFormat{
+			{Prefix: "/api/v2", Format: response.Format{FieldCase: response.FieldCaseCamel, Envelope: response.EnvelopeBare}},
+		},
+	}
+
+	success := response.SuccessResponseWithMeta("en", response.CodeSuccess, formatTestProfile{FirstName: "An"}, response.NewMeta(2, 10, 35))
+	rec, body := serveFormat(t, newFormatTestServer(cfg, *success), "/api/v2/users", nil)
+	assert.Equal(t, "An", body["firstName"])
+	assert.NotContains(t, body, "success")
+	assert.Equal(t, "35", rec.Header().Get("X-Total-Count"))
+	assert.Equal(t, "2", rec.Header().Get("X-Page"))
+	assert.Equal(t, "4", rec.Header().Get("X-Total-Pages"))
+
+	failure := response.NotFoundResponse("en", response.CodeNotFound)
+	rec, body = serveFormat(t, newFormatTestServer(cfg, *failure), "/api/v2/users/1", map[string]string{response.HeaderResponseCase: "snake"})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, response.CodeNotFound, body["code"])
+	assert.Contains(t, body, "message")
+	assert.NotContains(t, body, "success")
+
+	// Route khác giữ format mặc định, header bị bỏ qua khi không cho phép override
+	_, body = serveFormat(t, newFormatTestServer(cfg, *success), "/api/v1/users", map[string]string{response.HeaderResponseEnvelope: "bare"})
+	assert.Equal(t, true, body["success"])
+}
+
+func TestParseResponseFormat(t *testing.T) {
+	format, err := response.ParseFormat("camel:bare")
+	require.NoError(t, err)
+	assert.Equal(t, response.Format{FieldCase: response.FieldCaseCamel, Envelope: response.EnvelopeBare}, format)
+
+	format, err = response.ParseFormat("camel")
+	require.NoError(t, err)
+	assert.Equal(t, response.EnvelopeStandard, format.Envelope)
+
+	_, err = response.ParseFormat("camel:jsonapi")
+	assert.Error(t, err)
+}
@@ -0,0 +1,205 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/resumable"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const resumablePDF = "%PDF-1.4\nresumable upload over a flaky network\n%%EOF"
+
+type resumableFixture struct {
+	root    string
+	manager *resumable.Manager
+	handler http.Handler
+	owner   string
+}
+
+func newResumableFixture(t *testing.T) *resumableFixture {
+	t.Helper()
+	root := t.TempDir()
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets = nil
+	cfg.Routes = nil
+
+	storageManager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	manager, err := resumable.NewFromConfig(storageManager, cache.NewMockCache(), storage.ResumableConfig{
+		MaxSize:         1024,
+		ExpirationHours: 1,
+		ChunkPrefix:     ".resumable",
+	})
+	require.NoError(t, err)
+
+	f := &resumableFixture{root: root, manager: manager, owner: "user-1"}
+	f.handler = resumable.NewHandler(manager, resumable.HandlerConfig{
+		BasePath: "/api/v1/uploads",
+		Owner:    func(r *http.Request) string { return r.Header.Get("X-Test-User") },
+	})
+	return f
+}
+
+func (f *resumableFixture) do(method, target string, body io.Reader, header map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, body)
+	req.Header.Set("Tus-Resumable", resumable.TusVersion)
+	req.Header.Set("X-Test-User", f.owner)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	f.handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func (f *resumableFixture) create(t *testing.T, length int, metadata map[string]string) string {
+	t.Helper()
+	rec := f.do(http.MethodPost, "/api/v1/uploads", nil, map[string]string{
+		"Upload-Length":   strconv.Itoa(length),
+		"Upload-Metadata": resumable.EncodeMetadata(metadata),
+	})
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	location := rec.Header().Get("Location")
+	require.True(t, strings.HasPrefix(location, "/api/v1/uploads/"))
+	return location
+}
+
+func (f *resumableFixture) patch(location string, offset int, chunk string) *httptest.ResponseRecorder {
+	return f.do(http.MethodPatch, location, strings.NewReader(chunk), map[string]string{
+		"Content-Type":  "application/offset+octet-stream",
+		"Upload-Offset": strconv.Itoa(offset),
+	})
+}
+
+var pdfMetadata = map[string]string{"filename": "report.pdf", "filetype": "application/pdf", "category": "document"}
+
+func TestResumableUploadResumesAfterInterruption(t *testing.T) {
+	f := newResumableFixture(t)
+	location := f.create(t, len(resumablePDF), pdfMetadata)
+
+	rec := f.patch(location, 0, resumablePDF[:10])
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("Upload-Offset"))
+	assert.NotEmpty(t, rec.Header().Get("Upload-Expires"))
+
+	// Client mất kết nối rồi hỏi lại offset
+	rec = f.do(http.MethodHead, location, nil, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "10", rec.Header().Get("Upload-Offset"))
+	assert.Equal(t, strconv.Itoa(len(resumablePDF)), rec.Header().Get("Upload-Length"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+
+	// Offset sai bị từ chối
+	assert.Equal(t, http.StatusConflict, f.patch(location, 5, resumablePDF[5:]).Code)
+
+	rec = f.patch(location, 10, resumablePDF[10:])
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	assert.Equal(t, strconv.Itoa(len(resumablePDF)), rec.Header().Get("Upload-Offset"))
+
+	// File hoàn chỉnh được lưu qua StorageManager, chunk tạm bị xóa
+	rec = f.do(http.MethodGet, location, nil, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Data struct {
+			Completed bool                 `json:"completed"`
+			File      storage.UploadResult `json:"file"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.True(t, body.Data.Completed)
+	content, err := os.ReadFile(filepath.Join(f.root, body.Data.File.Path))
+	require.NoError(t, err)
+	assert.Equal(t, resumablePDF, string(content))
+
+	chunks, _ := filepath.Glob(filepath.Join(f.root, ".resumable", "*", "*"))
+	assert.Empty(t, chunks)
+}
+
+func TestResumableUploadProtocolErrors(t *testing.T) {
+	f := newResumableFixture(t)
+
+	rec := f.do(http.MethodPost, "/api/v1/uploads", nil, map[string]string{"Upload-Length": "10", "Tus-Resumable": "0.2.2"})
+	assert.Equal(t, http.StatusPreconditionFailed, rec.Code)
+	assert.Equal(t, resumable.TusVersion, rec.Header().Get("Tus-Version"))
+
+	rec = f.do(http.MethodOptions, "/api/v1/uploads", nil, nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "1024", rec.Header().Get("Tus-Max-Size"))
+	assert.Contains(t, rec.Header().Get("Tus-Extension"), "termination")
+
+	// Quá Tus-Max-Size, hoặc không đúng loại file của category
+	assert.Equal(t, http.StatusRequestEntityTooLarge, f.do(http.MethodPost, "/api/v1/uploads", nil, map[string]string{"Upload-Length": "2048"}).Code)
+	rec = f.do(http.MethodPost, "/api/v1/uploads", nil, map[string]string{
+		"Upload-Length":   "10",
+		"Upload-Metadata": resumable.EncodeMetadata(map[string]string{"filename": "a.pdf", "filetype": "application/pdf", "category": "image"}),
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+	location := f.create(t, len(resumablePDF), pdfMetadata)
+	assert.Equal(t, http.StatusUnsupportedMediaType, f.do(http.MethodPatch, location, strings.NewReader("x"), map[string]string{"Upload-Offset": "0"}).Code)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, f.patch(location, 0, resumablePDF+"extra").Code)
+
+	// Upload của user khác không truy cập được
+	f.owner = "user-2"
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodHead, location, nil, nil).Code)
+	f.owner = "user-1"
+
+	// Termination
+	require.Equal(t, http.StatusNoContent, f.patch(location, 0, resumablePDF[:8]).Code)
+	assert.Equal(t, http.StatusNoContent, f.do(http.MethodDelete, location, nil, nil).Code)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodHead, location, nil, nil).Code)
+}
+
+func TestResumableUploadCleanupExpired(t *testing.T) {
+	f := newResumableFixture(t)
+	location := f.create(t, len(resumablePDF), pdfMetadata)
+	require.Equal(t, http.StatusNoContent, f.patch(location, 0, resumablePDF[:8]).Code)
+
+	chunks, _ := filepath.Glob(filepath.Join(f.root, ".resumable", "*", "*"))
+	require.Len(t, chunks, 1)
+
+	offset := clock.NewOffset(clock.New())
+	ctx := clock.WithContext(context.Background(), offset)
+
+	removed, err := f.manager.Cleanup(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+
+	// Bỏ dở quá thời hạn thì chunk và trạng thái bị xóa
+	offset.Advance(2 * time.Hour)
+	removed, err = f.manager.Cleanup(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	chunks, _ = filepath.Glob(filepath.Join(f.root, ".resumable", "*", "*"))
+	assert.Empty(t, chunks)
+	assert.Equal(t, http.StatusNotFound, f.do(http.MethodHead, location, nil, nil).Code)
+}
+
+func TestResumableMetadata(t *testing.T) {
+	metadata, err := resumable.ParseMetadata("filename d29ybGRfZG9taW5hdGlvbl9wbGFuLnBkZg==,is_confidential")
+	require.NoError(t, err)
+	assert.Equal(t, "world_domination_plan.pdf", metadata["filename"])
+	assert.Contains(t, metadata, "is_confidential")
+
+	_, err = resumable.ParseMetadata("filename !!!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,294 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoffDuration(t *testing.T) {
+	constant := queue.Backoff{Curve: queue.BackoffConstant, Delay: time.Second}
+	linear := queue.Backoff{Curve: queue.BackoffLinear, Delay: time.Second}
+	exponential := queue.Backoff{Curve: queue.BackoffExponential, Delay: time.Second, Max: 10 * time.Second}
+
+	for retry, want := range map[int][3]time.Duration{
+		1:   {time.Second, time.Second, time.Second},
+		2:   {time.Second, 2 * time.Second, 2 * time.Second},
+		3:   {time.Second, 3 * time.Second, 4 * time.Second},
+		5:   {time.Second, 5 * time.Second, 10 * time.Second},
+		100: {time.Second, 100 * time.Second, 10 * time.Second},
+	} {
+		assert.Equal(t, want[0], constant.Duration(retry), "constant retry %d", retry)
+		assert.Equal(t, want[1], linear.Duration(retry), "linear retry %d", retry)
+		assert.Equal(t, want[2], exponential.Duration(retry), "exponential retry %d", retry)
+	}
+
+	jitter := queue.Backoff{Curve: queue.BackoffExponential, Delay: time.Second, Jitter: true}
+	for i := 0; i < 100; i++ {
+		d := jitter.Duration(3)
+		assert.GreaterOrEqual(t, d, 2*time.Second)
+		assert.LessOrEqual(t, d, 4*time.Second)
+	}
+}
+
+func TestParseRetryTag(t *testing.T) {
+	policy, err := queue.ParseRetryTag("attempts=5, backoff=exponential, delay=2s, max=5m, jitter")
+	require.NoError(t, err)
+	assert.Equal(t, 5, policy.MaxAttempts)
+	assert.Equal(t, queue.Backoff{Curve: queue.BackoffExponential, Delay: 2 * time.Second, Max: 5 * time.Minute, Jitter: true}, policy.Backoff)
+
+	for _, tag := range []string{"attempts=0", "attempts=x", "backoff=fibonacci", "delay=soon", "jitter=maybe", "retries=3"} {
+		_, err := queue.ParseRetryTag(tag)
+		assert.Error(t, err, tag)
+	}
+}
+
+// scriptedHandler lỗi theo thứ tự errs, hết errs thì thành công
+type scriptedHandler struct {
+	mu       sync.Mutex
+	errs     []error
+	attempts int
+	done     chan struct{}
+}
+
+func newScriptedHandler(errs ...error) *scriptedHandler {
+	return &scriptedHandler{errs: errs, done: make(chan struct{}, 1)}
+}
+
+func (h *scriptedHandler) Handle(ctx context.Context, message *queue.Message) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.attempts++
+	if h.attempts <= len(h.errs) {
+		return h.errs[h.attempts-1]
+	}
+	return nil
+}
+
+func (h *scriptedHandler) OnError(ctx context.Context, message *queue.Message, err error) error {
+	return nil
+}
+
+func (h *scriptedHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.attempts
+}
+
+// taggedHandler khai báo policy bằng struct tag
+type taggedHandler struct {
+	_ struct{} `retry:"attempts=2,backoff=linear,delay=5ms"`
+	*scriptedHandler
+}
+
+// providerHandler khai báo policy bằng RetryPolicyProvider, chỉ retry lỗi tạm thời
+type providerHandler struct {
+	*scriptedHandler
+}
+
+var errTransient = errors.New("transient")
+
+func (h *providerHandler) RetryPolicy() queue.RetryPolicy {
+	return queue.RetryPolicy{
+		MaxAttempts: 4,
+		Backoff:     queue.Backoff{Delay: time.Millisecond},
+		Retryable:   func(err error) bool { return errors.Is(err, errTransient) },
+	}
+}
+
+func TestResolveRetryPolicy(t *testing.T) {
+	defaults := &queue.ConsumerOptions{MaxRetries: 3, RetryDelay: 10 * time.Millisecond}
+
+	// Mặc định giữ hành vi cũ: MaxRetries + 1 lần, delay cố định
+	policy, err := queue.ResolveRetryPolicy(newScriptedHandler(), defaults)
+	require.NoError(t, err)
+	assert.Equal(t, 4, policy.MaxAttempts)
+	assert.Equal(t, queue.Backoff{Curve: queue.BackoffConstant, Delay: 10 * time.Millisecond}, policy.Backoff)
+	assert.False(t, policy.Retryable(queue.Permanent(errors.New("bad payload"))))
+	assert.True(t, policy.Retryable(errors.New("timeout")))
+
+	policy, err = queue.ResolveRetryPolicy(&taggedHandler{scriptedHandler: newScriptedHandler()}, defaults)
+	require.NoError(t, err)
+	assert.Equal(t, 2, policy.MaxAttempts)
+	assert.Equal(t, queue.BackoffLinear, policy.Backoff.Curve)
+	assert.Equal(t, 5*time.Millisecond, policy.Backoff.Delay)
+
+	// Email handler chỉ khai báo curve, delay và số lần lấy từ WORKER_*
+	policy, err = queue.ResolveRetryPolicy(workers.NewEmailHandler(&recordingEmailService{}), defaults)
+	require.NoError(t, err)
+	assert.Equal(t, 4, policy.MaxAttempts)
+	assert.Equal(t, queue.BackoffExponential, policy.Backoff.Curve)
+	assert.Equal(t, 10*time.Millisecond, policy.Backoff.Delay)
+
+	policy, err = queue.ResolveRetryPolicy(&providerHandler{newScriptedHandler()}, defaults)
+	require.NoError(t, err)
+	assert.Equal(t, 4, policy.MaxAttempts)
+	assert.False(t, policy.Retryable(errors.New("other")))
+
+	// Policy đăng ký ưu tiên hơn policy handler khai báo
+	override := *defaults
+	override.RetryPolicy = &queue.RetryPolicy{MaxAttempts: 1}
+	policy, err = queue.ResolveRetryPolicy(&taggedHandler{scriptedHandler: newScriptedHandler()}, &override)
+	require.NoError(t, err)
+	assert.Equal(t, 1, policy.MaxAttempts)
+
+	type badHandler struct {
+		_ struct{} `retry:"backoff=random"`
+		*scriptedHandler
+	}
+	_, err = queue.ResolveRetryPolicy(&badHandler{scriptedHandler: newScriptedHandler()}, defaults)
+	assert.Error(t, err)
+}
+
+func TestWorkerManagerRetryPolicies(t *testing.T) {
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, MaxRetries: 5, RetryDelay: time.Millisecond})
+
+	tagged := &taggedHandler{scriptedHandler: newScriptedHandler(errTransient, errTransient, errTransient)}
+	provider := &providerHandler{newScriptedHandler(errTransient, errors.New("not found"), nil)}
+	override := newScriptedHandler(errTransient, errTransient, errTransient, errTransient)
+
+	manager.Register("tagged", tagged)
+	manager.Register("provider", provider)
+	manager.Register("override", override, workers.WithRetryPolicy(queue.RetryPolicy{MaxAttempts: 3}))
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	for _, name := range []string{"tagged", "provider", "override"} {
+		q, err := queues.GetQueue(name)
+		require.NoError(t, err)
+		require.NoError(t, q.Push(ctx, &queue.Message{ID: name}))
+	}
+
+	assert.Eventually(t, func() bool {
+		return tagged.count() == 2 && provider.count() == 2 && override.count() == 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Không retry thêm sau khi hết lượt hoặc gặp lỗi không retry được
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, 2, tagged.count())
+	assert.Equal(t, 2, provider.count())
+	assert.Equal(t, 3, override.count())
+}
+
+func TestMessageRetryPolicyOverrides(t *testing.T) {
+	base, err := queue.ResolveRetryPolicy(newScriptedHandler(), &queue.ConsumerOptions{
+		MaxRetries:   3,
+		RetryDelay:   time.Second,
+		RetryBackoff: queue.Backoff{Curve: queue.BackoffExponential, Max: time.Minute, Jitter: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, queue.Backoff{Curve: queue.BackoffExponential, Delay: time.Second, Max: time.Minute, Jitter: true}, base.Backoff)
+
+	policy, err := queue.MessageRetryPolicy(base, &queue.Message{MaxRetries: 7})
+	require.NoError(t, err)
+	assert.Equal(t, 8, policy.MaxAttempts)
+
+	policy, err = queue.MessageRetryPolicy(base, &queue.Message{Headers: map[string]string{
+		queue.HeaderRetry: "attempts=2,backoff=linear,delay=5s,jitter=false",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, policy.MaxAttempts)
+	assert.Equal(t, queue.Backoff{Curve: queue.BackoffLinear, Delay: 5 * time.Second, Max: time.Minute}, policy.Backoff)
+
+	policy, err = queue.MessageRetryPolicy(base, &queue.Message{Headers: map[string]string{queue.HeaderRetry: "backoff=random"}})
+	assert.Error(t, err)
+	assert.Equal(t, base.MaxAttempts, policy.MaxAttempts)
+}
+
+// delayQueue listQueue giữ delayed message riêng, release đưa chúng về queue
+type delayQueue struct {
+	*listQueue
+	mu      sync.Mutex
+	delayed []*queue.Message
+}
+
+func (q *delayQueue) Push(ctx context.Context, m *queue.Message) error {
+	if m.Delay > 0 {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		q.delayed = append(q.delayed, m)
+		return nil
+	}
+	return q.listQueue.Push(ctx, m)
+}
+
+func (q *delayQueue) release(ctx context.Context) []time.Duration {
+	q.mu.Lock()
+	delayed := q.delayed
+	q.delayed = nil
+	q.mu.Unlock()
+
+	delays := make([]time.Duration, 0, len(delayed))
+	for _, m := range delayed {
+		delays = append(delays, m.Delay)
+		_ = q.listQueue.Push(ctx, m)
+	}
+	return delays
+}
+
+func (q *delayQueue) pending() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.delayed)
+}
+
+func TestConsumerSchedulesDelayedRetries(t *testing.T) {
+	q := &delayQueue{listQueue: &listQueue{name: "webhooks"}}
+	handler := newScriptedHandler(errTransient, errTransient)
+	var mu sync.Mutex
+	var retryCounts []int
+	recorder := queue.NewDispatcher()
+	recorder.RegisterHandler("webhook", func(ctx context.Context, message *queue.Message) error {
+		mu.Lock()
+		retryCounts = append(retryCounts, message.RetryCount)
+		mu.Unlock()
+		if message.ID == "fast" {
+			return nil
+		}
+		return handler.Handle(ctx, message)
+	})
+
+	consumer := queue.NewConsumer(q, recorder, &queue.ConsumerOptions{
+		Concurrency:  1,
+		MaxRetries:   3,
+		RetryDelay:   time.Hour,
+		RetryBackoff: queue.Backoff{Curve: queue.BackoffExponential},
+		DelayedRetry: true,
+	})
+	ctx := context.Background()
+	require.NoError(t, consumer.Start(ctx))
+	defer consumer.Stop()
+
+	slow, _ := queue.NewJobMessage("webhook", "slow")
+	fast, _ := queue.NewJobMessage("webhook", "fast")
+	fast.ID = "fast"
+	require.NoError(t, q.Push(ctx, slow))
+	require.NoError(t, q.Push(ctx, fast))
+
+	// Backoff 1 giờ không giữ worker: message sau vẫn được xử lý ngay
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(retryCounts) == 2 && q.pending() == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []time.Duration{time.Hour}, q.release(ctx))
+
+	assert.Eventually(t, func() bool { return q.pending() == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []time.Duration{2 * time.Hour}, q.release(ctx))
+
+	assert.Eventually(t, func() bool { return handler.count() == 3 }, 2*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []int{0, 0, 1, 2}, retryCounts)
+}
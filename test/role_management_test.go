@@ -0,0 +1,202 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/role"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/internal/wire"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type roleManagementFixture struct {
+	db          *gorm.DB
+	service     *role.Service
+	permissions *jwt.PermissionChecker
+	jwtManager  *jwt.Manager
+}
+
+func newRoleManagementFixture(t *testing.T) *roleManagementFixture {
+	t.Helper()
+	db := newAuthTestDB(t)
+	userRepo := repository.NewUserRepository(db)
+	jwtManager := jwt.NewManager(jwt.Config{SecretKey: "test-secret-key-min-32-chars-long"})
+	permissions := wire.ProvidePermissionChecker(cache.NewMockCache(), userRepo, jwtManager)
+	return &roleManagementFixture{
+		db:          db,
+		service:     role.NewService(repository.NewRoleRepository(db), repository.NewPermissionRepository(db), userRepo, permissions),
+		permissions: permissions,
+		jwtManager:  jwtManager,
+	}
+}
+
+func (f *roleManagementFixture) createRole(t *testing.T, name string, permissionNames ...string) *model.Role {
+	t.Helper()
+	r := &model.Role{Name: name, DisplayName: name}
+	require.NoError(t, f.db.Create(r).Error)
+	for _, permissionName := range permissionNames {
+		permission := f.permission(t, permissionName)
+		require.NoError(t, f.db.Create(&model.RoleHasPermission{RoleID: r.ID, PermissionID: permission.ID}).Error)
+	}
+	return r
+}
+
+func (f *roleManagementFixture) permission(t *testing.T, name string) *model.Permission {
+	t.Helper()
+	permission := &model.Permission{}
+	if err := f.db.Where("name = ?", name).First(permission).Error; err == nil {
+		return permission
+	}
+	permission = &model.Permission{Name: name, DisplayName: name}
+	require.NoError(t, f.db.Create(permission).Error)
+	return permission
+}
+
+// issueToken cấp access token chứa permissions hiện tại của user, giống lúc login
+func (f *roleManagementFixture) issueToken(t *testing.T, user *model.User) *jwt.Claims {
+	t.Helper()
+	var granted []string
+	if user.RoleID != nil {
+		var err error
+		granted, err = repository.NewUserRepository(f.db).GetUserPermissions(context.Background(), *user.RoleID)
+		require.NoError(t, err)
+	}
+	token, err := f.jwtManager.GenerateToken(user.ID.String(), user.Email, "", map[string]interface{}{
+		jwt.MetadataPermissions: granted,
+	})
+	require.NoError(t, err)
+	claims, err := f.jwtManager.VerifyToken(token)
+	require.NoError(t, err)
+	return claims
+}
+
+func (f *roleManagementFixture) has(t *testing.T, claims *jwt.Claims, permission string) bool {
+	t.Helper()
+	granted, err := f.permissions.Permissions(context.Background(), claims)
+	require.NoError(t, err)
+	return jwt.HasPermission(granted, permission)
+}
+
+func (f *roleManagementFixture) assignRole(t *testing.T, user *model.User, r *model.Role) {
+	t.Helper()
+	require.NoError(t, f.db.Model(&model.User{}).Where("id = ?", user.ID).Update("role_id", r.ID).Error)
+	user.RoleID = &r.ID
+}
+
+func TestRoleSyncPermissionsAppliesToIssuedTokens(t *testing.T) {
+	f := newRoleManagementFixture(t)
+	editor := f.createRole(t, "editor", "posts.view", "posts.delete")
+	user := createAuthTestUser(t, f.db, "editor@example.com", true)
+	f.assignRole(t, user, editor)
+
+	claims := f.issueToken(t, user)
+	require.True(t, f.has(t, claims, "posts.delete"))
+
+	// Thu hồi posts.delete: token đã cấp không còn quyền ngay, không cần chờ token hết hạn
+	view := f.permission(t, "posts.view")
+	resp := f.service.SyncPermissions(context.Background(), editor.ID.String(), role.SyncPermissionsRequest{
+		PermissionIDs: []string{view.ID.String()},
+	})
+	require.Equal(t, response.CodeUpdated, resp.Code)
+	assert.False(t, f.has(t, claims, "posts.delete"))
+	assert.True(t, f.has(t, claims, "posts.view"))
+
+	// Cấp lại quyền: cache permissions của loader cũng được làm mới
+	del := f.permission(t, "posts.delete")
+	resp = f.service.SyncPermissions(context.Background(), editor.ID.String(), role.SyncPermissionsRequest{
+		PermissionIDs: []string{view.ID.String(), del.ID.String()},
+	})
+	require.Equal(t, response.CodeUpdated, resp.Code)
+	assert.True(t, f.has(t, claims, "posts.delete"))
+}
+
+func TestRoleAssignAndRemoveUserApplyImmediately(t *testing.T) {
+	f := newRoleManagementFixture(t)
+	viewer := f.createRole(t, "viewer", "reports.view")
+	manager := f.createRole(t, "manager", "reports.*")
+	user := createAuthTestUser(t, f.db, "member@example.com", true)
+	f.assignRole(t, user, viewer)
+
+	claims := f.issueToken(t, user)
+	require.False(t, f.has(t, claims, "reports.export"))
+
+	// Đổi role: token cũ nhận permissions của role mới
+	require.Equal(t, response.CodeUpdated, f.service.AssignUser(context.Background(), manager.ID.String(), user.ID.String()).Code)
+	assert.True(t, f.has(t, claims, "reports.export"))
+
+	// Gỡ role: token cũ mất toàn bộ permissions
+	require.Equal(t, response.CodeDeleted, f.service.RemoveUser(context.Background(), manager.ID.String(), user.ID.String()).Code)
+	assert.False(t, f.has(t, claims, "reports.view"))
+
+	// User khác không bị ảnh hưởng
+	other := createAuthTestUser(t, f.db, "other@example.com", true)
+	f.assignRole(t, other, viewer)
+	assert.True(t, f.has(t, f.issueToken(t, other), "reports.view"))
+}
+
+func TestRoleStaleClaimsOutliveLongAccessTokens(t *testing.T) {
+	cacheClient := cache.NewMockCache()
+	jwtManager := jwt.NewManager(jwt.Config{
+		SecretKey:           "test-secret-key-min-32-chars-long",
+		AccessTokenDuration: 72 * time.Hour,
+		Leeway:              time.Minute,
+	})
+	require.Equal(t, 72*time.Hour+time.Minute, jwtManager.MaxAccessTokenLifetime())
+
+	db := newAuthTestDB(t)
+	permissions := wire.ProvidePermissionChecker(cacheClient, repository.NewUserRepository(db), jwtManager)
+	userID := uuid.NewString()
+	require.NoError(t, permissions.Invalidate(context.Background(), userID))
+
+	// Đánh dấu permissions cũ phải còn cho tới khi access token cấp trước Invalidate hết hạn, không dừng ở 24h
+	ttl, err := cacheClient.TTL(context.Background(), "jwt:permissions:stale:"+userID)
+	require.NoError(t, err)
+	assert.Greater(t, ttl, 72*time.Hour)
+	assert.LessOrEqual(t, ttl, 72*time.Hour+time.Minute)
+
+	// Impersonation token dài hơn access token thì lấy theo impersonation token
+	impersonating := jwt.NewManager(jwt.Config{
+		SecretKey:                  "test-secret-key-min-32-chars-long",
+		AccessTokenDuration:        15 * time.Minute,
+		ImpersonationTokenDuration: 48 * time.Hour,
+	})
+	assert.Equal(t, 48*time.Hour, impersonating.MaxAccessTokenLifetime())
+}
+
+func TestRoleSystemRolesProtected(t *testing.T) {
+	f := newRoleManagementFixture(t)
+	for _, name := range model.SystemRoles {
+		system := f.createRole(t, name)
+
+		resp := f.service.Delete(context.Background(), system.ID.String())
+		assert.Equal(t, response.CodeRoleProtected, resp.Code, name)
+		assert.Equal(t, http.StatusConflict, response.GetHTTPStatusCode(resp.Code), name)
+
+		resp = f.service.Update(context.Background(), system.ID.String(), role.UpdateRoleRequest{Name: name + "-renamed"})
+		assert.Equal(t, response.CodeRoleProtected, resp.Code, name)
+
+		// Đổi display name vẫn được phép
+		resp = f.service.Update(context.Background(), system.ID.String(), role.UpdateRoleRequest{DisplayName: "System " + name})
+		assert.Equal(t, response.CodeUpdated, resp.Code, name)
+
+		var stored model.Role
+		require.NoError(t, f.db.First(&stored, "id = ?", system.ID).Error)
+		assert.Equal(t, name, stored.Name)
+	}
+
+	// Role tự tạo không có user vẫn xóa được
+	custom := f.createRole(t, "temporary")
+	assert.Equal(t, response.CodeDeleted, f.service.Delete(context.Background(), custom.ID.String()).Code)
+	assert.Equal(t, response.CodeRoleNotFound, f.service.Delete(context.Background(), uuid.NewString()).Code)
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/internal/routes"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	middlewarePkg "github.com/anhnq996/go-api-core/pkg/middleware"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteGroupPolicies(t *testing.T) {
+	cfg := config.LoadRoutesConfig()
+	require.NoError(t, cfg.Validate())
+	cfg.AdminRoles = []string{" admin ", ""}
+
+	policies := routes.NewPolicies(cfg)
+	assert.False(t, policies[routes.GroupPublic].RequireAuth)
+	assert.True(t, policies[routes.GroupAuthenticated].RequireAuth)
+	assert.True(t, policies[routes.GroupAdmin].RequireAuth)
+	assert.False(t, policies[routes.GroupInternal].RequireAuth)
+	assert.Equal(t, []string{"admin"}, policies[routes.GroupAdmin].Roles)
+	assert.Equal(t, logger.VerbosityBasic, policies[routes.GroupPublic].Verbosity)
+	assert.Equal(t, logger.VerbosityFull, policies[routes.GroupAuthenticated].Verbosity)
+
+	cfg.Internal.LogLevel = "verbose"
+	assert.Error(t, cfg.Validate())
+}
+
+func TestRouteGroupMiddlewares(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret", AccessTokenDuration: time.Hour})
+	c := &routes.Controllers{
+		JWTManager:   manager,
+		JWTBlacklist: jwt.NewBlacklist(cache.NewMockCache()),
+		Policies: routes.Policies{
+			routes.GroupPublic:        {Verbosity: logger.VerbosityNone},
+			routes.GroupAuthenticated: {RequireAuth: true, Verbosity: logger.VerbosityBasic},
+			routes.GroupAdmin:         {RequireAuth: true, Roles: []string{"admin"}},
+		},
+	}
+
+	var buf bytes.Buffer
+	previous := logger.RequestLogger
+	logger.RequestLogger = zerolog.New(&buf)
+	defer func() { logger.RequestLogger = previous }()
+
+	r := chi.NewRouter()
+	r.Use(logger.Middleware())
+	ok := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(`{"secret":"body"}`)) }
+	c.Group(r, routes.GroupPublic, func(r chi.Router) { r.Get("/public", ok) })
+	c.Group(r, routes.GroupAuthenticated, func(r chi.Router) { r.Get("/me", ok) })
+	c.Group(r, routes.GroupAdmin, func(r chi.Router) { r.Get("/admin", ok) })
+
+	userToken, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+	adminToken, err := manager.GenerateToken("admin-1", "admin@example.com", "admin", nil)
+	require.NoError(t, err)
+
+	do := func(path, token string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Public: không cần token, không log
+	assert.Equal(t, http.StatusOK, do("/public", ""))
+	assert.Empty(t, buf.String())
+
+	// Authenticated: cần token, log không có body
+	assert.Equal(t, http.StatusUnauthorized, do("/me", ""))
+	buf.Reset()
+	assert.Equal(t, http.StatusOK, do("/me", userToken))
+	assert.Contains(t, buf.String(), `"path":"/me"`)
+	assert.NotContains(t, buf.String(), "response_body")
+
+	// Admin: cần role admin, log đầy đủ
+	assert.Equal(t, http.StatusForbidden, do("/admin", userToken))
+	buf.Reset()
+	assert.Equal(t, http.StatusOK, do("/admin", adminToken))
+	assert.Contains(t, buf.String(), "response_body")
+}
+
+func TestRateLimitKeyByGroup(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Real-IP", "10.0.0.1")
+	assert.Equal(t, "group:public:ip:10.0.0.1", middlewarePkg.KeyByGroup("public")(req))
+
+	req = req.WithContext(jwt.ContextWithClaims(req.Context(), &jwt.Claims{UserID: "user-1"}))
+	assert.Equal(t, "group:admin:user:user-1", middlewarePkg.KeyByGroup("admin")(req))
+	assert.True(t, strings.HasPrefix(middlewarePkg.KeyByGroup("authenticated")(req), "group:authenticated:"))
+}
+
+func TestParseVerbosity(t *testing.T) {
+	assert.Equal(t, logger.VerbosityBasic, logger.ParseVerbosity("Basic"))
+	assert.Equal(t, logger.VerbosityNone, logger.ParseVerbosity("none"))
+	assert.Equal(t, logger.VerbosityFull, logger.ParseVerbosity("full"))
+	assert.Equal(t, logger.VerbosityFull, logger.ParseVerbosity(""))
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cron"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// regionalJob nightlyJob chạy theo giờ địa phương của region
+type regionalJob struct {
+	nightlyJob
+	name     string
+	timeZone string
+}
+
+func (j *regionalJob) Name() string     { return j.name }
+func (j *regionalJob) TimeZone() string { return j.timeZone }
+
+// jitteredJob countingJob với cửa sổ jitter
+type jitteredJob struct {
+	countingJob
+	jitter time.Duration
+}
+
+func (j *jitteredJob) Jitter() time.Duration { return j.jitter }
+
+func TestSchedulerPerJobTimeZone(t *testing.T) {
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{TimeZone: "UTC"})
+	require.NoError(t, scheduler.AddJob(&regionalJob{name: "report-vn", timeZone: "Asia/Ho_Chi_Minh"}))
+	require.NoError(t, scheduler.AddJob(&regionalJob{name: "report-us", timeZone: "America/New_York"}))
+	require.NoError(t, scheduler.AddJob(&nightlyJob{}))
+
+	err := scheduler.AddJob(&regionalJob{name: "report-mars", timeZone: "Mars/Olympus_Mons"})
+	assert.ErrorContains(t, err, "invalid timezone")
+
+	require.NoError(t, scheduler.Start(context.Background()))
+	defer scheduler.Stop()
+
+	for name, timeZone := range map[string]string{
+		"report-vn": "Asia/Ho_Chi_Minh",
+		"report-us": "America/New_York",
+		"nightly":   "UTC",
+	} {
+		status, err := scheduler.GetJobStatus(name)
+		require.NoError(t, err)
+		assert.Equal(t, timeZone, status.TimeZone)
+
+		// "0 3 * * *" là 3h sáng theo giờ địa phương của job
+		location, err := time.LoadLocation(timeZone)
+		require.NoError(t, err)
+		next := status.NextRun.In(location)
+		assert.Equal(t, 3, next.Hour(), name)
+		assert.Equal(t, 0, next.Minute(), name)
+		assert.WithinDuration(t, time.Now().Add(12*time.Hour), next, 12*time.Hour+time.Minute, name)
+	}
+}
+
+func TestSchedulerJitterDelaysScheduledRuns(t *testing.T) {
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{})
+	job := &jitteredJob{jitter: time.Hour}
+	require.NoError(t, scheduler.AddJob(job))
+	require.NoError(t, scheduler.Start(context.Background()))
+
+	// Lần chạy theo lịch (mỗi giây) chờ ngẫu nhiên tới 1 giờ
+	time.Sleep(2500 * time.Millisecond)
+	assert.Zero(t, job.runs.Load())
+
+	// TriggerJob không áp dụng jitter
+	require.NoError(t, scheduler.TriggerJob("counting"))
+	require.Eventually(t, func() bool { return job.runs.Load() == 1 }, time.Second, 10*time.Millisecond)
+
+	// Dừng scheduler thì các lần chạy đang chờ jitter bị bỏ
+	require.NoError(t, scheduler.Stop())
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int64(1), job.runs.Load())
+}
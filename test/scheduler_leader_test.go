@@ -0,0 +1,125 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cron"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeElection trạng thái leader dùng chung giữa các instance (thay cho Redis key)
+type fakeElection struct {
+	mu     sync.Mutex
+	holder string
+	err    error
+}
+
+type fakeElector struct {
+	election *fakeElection
+	id       string
+}
+
+func (e *fakeElector) Campaign(ctx context.Context) (bool, error) {
+	e.election.mu.Lock()
+	defer e.election.mu.Unlock()
+	if e.election.err != nil {
+		return false, e.election.err
+	}
+	if e.election.holder == "" {
+		e.election.holder = e.id
+	}
+	return e.election.holder == e.id, nil
+}
+
+func (e *fakeElector) Resign(ctx context.Context) error {
+	e.election.mu.Lock()
+	defer e.election.mu.Unlock()
+	if e.election.holder == e.id {
+		e.election.holder = ""
+	}
+	return nil
+}
+
+// countingJob đếm số lần chạy, không dùng lock
+type countingJob struct {
+	runs atomic.Int64
+}
+
+func (j *countingJob) Name() string              { return "counting" }
+func (j *countingJob) Schedule() string          { return "@every 1s" }
+func (j *countingJob) Timeout() time.Duration    { return time.Second }
+func (j *countingJob) RetryCount() int           { return 0 }
+func (j *countingJob) RetryDelay() time.Duration { return 0 }
+func (j *countingJob) Run(ctx context.Context) error {
+	j.runs.Add(1)
+	return nil
+}
+
+// strictLockManager đếm số lần job lấy lock theo job
+type strictLockManager struct {
+	cron.LockManager
+	acquired atomic.Int64
+}
+
+func (l *strictLockManager) AcquireLock(ctx context.Context, jobName string, ttl time.Duration) (bool, error) {
+	l.acquired.Add(1)
+	return l.LockManager.AcquireLock(ctx, jobName, ttl)
+}
+
+func newLeaderScheduler(t *testing.T, election *fakeElection, id string, locks cron.LockManager) (*cron.SchedulerImpl, *countingJob) {
+	t.Helper()
+	scheduler := cron.NewScheduler(locks, cron.Config{
+		LeaderElector:       &fakeElector{election: election, id: id},
+		LeaderRenewInterval: 20 * time.Millisecond,
+	})
+	job := &countingJob{}
+	require.NoError(t, scheduler.AddJob(job))
+	return scheduler, job
+}
+
+func TestSchedulerLeaderElection(t *testing.T) {
+	election := &fakeElection{}
+	locks := &strictLockManager{LockManager: cron.NewMemoryLockManager()}
+	ctx := context.Background()
+
+	first, firstJob := newLeaderScheduler(t, election, "instance-1", locks)
+	require.NoError(t, first.Start(ctx))
+	assert.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+
+	second, secondJob := newLeaderScheduler(t, election, "instance-2", locks)
+	require.NoError(t, second.Start(ctx))
+	defer second.Stop()
+
+	// Chỉ leader chạy job, không lấy lock theo job
+	assert.Eventually(t, func() bool { return firstJob.runs.Load() > 0 }, 3*time.Second, 20*time.Millisecond)
+	assert.False(t, second.IsLeader())
+	assert.Zero(t, secondJob.runs.Load())
+	assert.Zero(t, locks.acquired.Load())
+
+	// Leader dừng thì nhả quyền, instance còn lại tiếp quản
+	require.NoError(t, first.Stop())
+	assert.False(t, first.IsLeader())
+	assert.Eventually(t, second.IsLeader, time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return secondJob.runs.Load() > 0 }, 3*time.Second, 20*time.Millisecond)
+
+	// Không gia hạn được thì tự dừng cron loop
+	election.mu.Lock()
+	election.err = errors.New("redis unavailable")
+	election.mu.Unlock()
+	assert.Eventually(t, func() bool { return !second.IsLeader() }, time.Second, 10*time.Millisecond)
+}
+
+func TestSchedulerWithoutLeaderElection(t *testing.T) {
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{})
+	require.NoError(t, scheduler.Start(context.Background()))
+	assert.True(t, scheduler.IsLeader())
+	require.NoError(t, scheduler.Stop())
+	assert.False(t, scheduler.IsLeader())
+}
@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cron"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchedulerRemoveJobStopsFiring(t *testing.T) {
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{})
+	job := &countingJob{}
+	require.NoError(t, scheduler.AddJob(job))
+	require.NoError(t, scheduler.Start(context.Background()))
+	defer scheduler.Stop()
+
+	require.Eventually(t, func() bool { return job.runs.Load() >= 1 }, 3*time.Second, 20*time.Millisecond)
+	require.NoError(t, scheduler.RemoveJob("counting"))
+	runs := job.runs.Load()
+
+	time.Sleep(2200 * time.Millisecond)
+	assert.Equal(t, runs, job.runs.Load(), "job đã gỡ không được chạy nữa")
+	_, err := scheduler.GetJobStatus("counting")
+	assert.Error(t, err)
+	assert.ErrorIs(t, scheduler.RemoveJob("counting"), cron.ErrJobNotFound)
+
+	// Đăng ký lại cùng tên: chỉ 1 entry chạy
+	again := &countingJob{}
+	require.NoError(t, scheduler.AddJob(again))
+	require.Eventually(t, func() bool { return again.runs.Load() >= 1 }, 3*time.Second, 20*time.Millisecond)
+	assert.Equal(t, runs, job.runs.Load())
+}
+
+func TestSchedulerUpdateSchedule(t *testing.T) {
+	scheduler := cron.NewScheduler(cron.NewMemoryLockManager(), cron.Config{})
+	job := &nightlyJob{}
+	require.NoError(t, scheduler.AddJob(job))
+	require.NoError(t, scheduler.Start(context.Background()))
+	defer scheduler.Stop()
+
+	// Lịch không hợp lệ: giữ lịch cũ
+	require.Error(t, scheduler.UpdateSchedule("nightly", "not a schedule"))
+	status, err := scheduler.GetJobStatus("nightly")
+	require.NoError(t, err)
+	assert.Equal(t, "0 3 * * *", status.Schedule)
+
+	require.NoError(t, scheduler.UpdateSchedule("nightly", "@every 1s"))
+	require.Eventually(t, func() bool { return job.runs.Load() >= 2 }, 4*time.Second, 20*time.Millisecond)
+
+	status, err = scheduler.GetJobStatus("nightly")
+	require.NoError(t, err)
+	assert.Equal(t, "@every 1s", status.Schedule)
+	assert.WithinDuration(t, time.Now(), status.NextRun, 2*time.Second)
+	assert.GreaterOrEqual(t, status.RunCount, int64(1))
+
+	// Đổi về lịch cũ: trạng thái (số lần chạy) được giữ nguyên
+	require.NoError(t, scheduler.ReplaceJob(job))
+	runs := job.runs.Load()
+	time.Sleep(1500 * time.Millisecond)
+	assert.LessOrEqual(t, job.runs.Load(), runs+1, "tối đa 1 lần chạy đang diễn ra khi đổi lịch")
+	status, err = scheduler.GetJobStatus("nightly")
+	require.NoError(t, err)
+	assert.Equal(t, "0 3 * * *", status.Schedule)
+	assert.GreaterOrEqual(t, status.RunCount, int64(2))
+
+	assert.ErrorIs(t, scheduler.UpdateSchedule("missing", "@every 1s"), cron.ErrJobNotFound)
+	assert.ErrorIs(t, scheduler.ReplaceJob(&countingJob{}), cron.ErrJobNotFound)
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/securitylog"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func securityLogLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		lines = append(lines, entry)
+	}
+	return lines
+}
+
+func TestReasonFor(t *testing.T) {
+	reason, ok := securitylog.ReasonFor(response.CodeTokenExpired, http.StatusUnauthorized)
+	assert.True(t, ok)
+	assert.Equal(t, securitylog.ReasonTokenExpired, reason)
+
+	reason, ok = securitylog.ReasonFor("", http.StatusTooManyRequests)
+	assert.True(t, ok)
+	assert.Equal(t, securitylog.ReasonRateLimited, reason)
+
+	_, ok = securitylog.ReasonFor(response.CodeUserNotFound, http.StatusNotFound)
+	assert.False(t, ok)
+}
+
+func TestSecurityLogMiddlewareRecordsAuthFailures(t *testing.T) {
+	var buf bytes.Buffer
+	var alerts []securitylog.Alert
+	secLog := securitylog.New(securitylog.Config{
+		AlertThreshold: 3,
+		AlertWindow:    time.Minute,
+		OnAlert:        func(alert securitylog.Alert) { alerts = append(alerts, alert) },
+	}, zerolog.New(&buf))
+
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret", AccessTokenDuration: time.Hour})
+	userToken, err := manager.GenerateToken("user-1", "user@example.com", "user", nil)
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	r.Use(secLog.Middleware)
+	r.Group(func(r chi.Router) {
+		r.Use(manager.Middleware)
+		r.With(jwt.RequireAnyRole("admin")).Delete("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+	})
+	r.Get("/public", func(w http.ResponseWriter, r *http.Request) {
+		response.NotFound(w, "en", response.CodeUserNotFound)
+	})
+
+	call := func(target, token string) int {
+		method := http.MethodDelete
+		if target == "/public" {
+			method = http.MethodGet
+		}
+		req := httptest.NewRequest(method, target, nil)
+		req.RemoteAddr = "203.0.113.7:5000"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, call("/users/42", ""))
+	assert.Equal(t, http.StatusForbidden, call("/users/42", userToken))
+	assert.Equal(t, http.StatusNotFound, call("/public", ""), "lỗi không liên quan xác thực không được ghi")
+
+	lines := securityLogLines(t, &buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "security_event", lines[0]["event"])
+	assert.Equal(t, "token_missing", lines[0]["reason"])
+	assert.Equal(t, "/users/{id}", lines[0]["route"])
+	assert.Equal(t, "203.0.113.7", lines[0]["ip"])
+	assert.Equal(t, "", lines[0]["user"])
+	assert.Equal(t, "permission_denied", lines[1]["reason"])
+	assert.Equal(t, response.CodePermissionDenied, lines[1]["code"])
+	assert.Equal(t, "user-1", lines[1]["user"])
+	assert.Empty(t, alerts)
+
+	// Event thứ 3 của cùng IP trong cửa sổ: phát alert đúng một lần
+	call("/users/42", "invalid-token")
+	call("/users/42", "invalid-token")
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "ip:203.0.113.7", alerts[0].Key)
+	assert.Equal(t, 3, alerts[0].Count)
+	assert.Equal(t, securitylog.ReasonTokenInvalid, alerts[0].Last.Reason)
+
+	anomalies := 0
+	for _, line := range securityLogLines(t, &buf) {
+		if line["event"] == "security_anomaly" {
+			anomalies++
+		}
+	}
+	assert.Equal(t, 1, anomalies)
+}
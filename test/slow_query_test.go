@@ -0,0 +1,75 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/logger"
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type slowQueryItem struct {
+	ID    uint
+	Email string
+}
+
+func TestSlowQueryLogRedactsParams(t *testing.T) {
+	read := initFileLogger(t, "info", map[string]string{logger.ModuleGORM: "warn"})
+
+	// Ngưỡng 1ns: mọi câu lệnh đều là query chậm
+	gormLogger := logger.NewGormLogger(time.Nanosecond)
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: gormLogger})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&slowQueryItem{}))
+
+	require.NoError(t, db.Create(&slowQueryItem{Email: "secret@example.com"}).Error)
+	var item slowQueryItem
+	require.NoError(t, db.Where("email = ?", "secret@example.com").First(&item).Error)
+
+	content := read()
+	assert.Contains(t, content, "slow query")
+	assert.Contains(t, content, "slow_query_items")
+	assert.Contains(t, content, "email = ?")
+	assert.Contains(t, content, "slow_query_test.go")
+	assert.Contains(t, content, `"threshold_ms"`)
+	assert.NotContains(t, content, "secret@example.com")
+
+	// LogParams: SQL kèm giá trị (local)
+	gormLogger.LogParams = true
+	require.NoError(t, db.Where("email = ?", "visible@example.com").Find(&[]slowQueryItem{}).Error)
+	assert.Contains(t, read(), "visible@example.com")
+}
+
+func TestGORMQueryMetrics(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.NewGormLogger(time.Hour)})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&slowQueryItem{}))
+
+	registry := metrics.NewRegistry()
+	require.NoError(t, metrics.InstrumentGORM(db, registry, "app_db"))
+
+	require.NoError(t, db.Create(&slowQueryItem{Email: "a@example.com"}).Error)
+	require.NoError(t, db.Find(&[]slowQueryItem{}).Error)
+	require.NoError(t, db.Find(&[]slowQueryItem{}).Error)
+	assert.Error(t, db.Table("missing_table").Find(&[]slowQueryItem{}).Error)
+	assert.ErrorIs(t, db.Where("id = ?", 999).First(&slowQueryItem{}).Error, gorm.ErrRecordNotFound)
+	require.NoError(t, db.Exec("DELETE FROM slow_query_items WHERE id = ?", 999).Error)
+
+	body := scrapeMetrics(t, registry, false)
+	for _, line := range []string{
+		"# TYPE app_db_query_duration_seconds histogram",
+		`app_db_query_duration_seconds_count{table="slow_query_items",operation="create"} 1`,
+		`app_db_query_duration_seconds_count{table="slow_query_items",operation="query"} 3`,
+		`app_db_query_duration_seconds_count{table="unknown",operation="raw"} 1`,
+		`app_db_query_errors_total{table="missing_table",operation="query"} 1`,
+	} {
+		assert.Contains(t, body, line)
+	}
+	// Không tìm thấy record không tính là lỗi
+	assert.NotContains(t, body, `app_db_query_errors_total{table="slow_query_items"`)
+}
@@ -0,0 +1,182 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/sms"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		valid bool
+	}{
+		{"+84901234567", "+84901234567", true},
+		{"+84 90 123-4567", "+84901234567", true},
+		{"0084.90.123.4567", "+84901234567", true},
+		{"+1 (415) 555-2671", "+14155552671", true},
+		{"0901234567", "", false},
+		{"+0123456789", "", false},
+		{"+84abc", "", false},
+	}
+	for _, tt := range tests {
+		got, err := sms.NormalizePhone(tt.input)
+		if !tt.valid {
+			assert.ErrorIs(t, err, sms.ErrInvalidPhone, tt.input)
+			continue
+		}
+		require.NoError(t, err, tt.input)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestTwilioDriver(t *testing.T) {
+	var form url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "AC123" || pass != "token" || r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"code":20003,"message":"Authenticate","status":401}`))
+			return
+		}
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+
+		switch form.Get("To") {
+		case "+15005550001":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number.","status":400}`))
+		case "+15005550002":
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"code":20429,"message":"Too Many Requests","status":429}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"sid":"SM123","status":"queued"}`))
+		}
+	}))
+	defer server.Close()
+
+	driver, err := sms.NewTwilioDriver(sms.TwilioConfig{AccountSID: "AC123", AuthToken: "token", From: "+15005550006", Endpoint: server.URL})
+	require.NoError(t, err)
+
+	require.NoError(t, driver.Send(context.Background(), &sms.Message{To: "+84 90 123 4567", Body: "Mã của bạn: 123456"}))
+	assert.Equal(t, "+84901234567", form.Get("To"))
+	assert.Equal(t, "+15005550006", form.Get("From"))
+	assert.Equal(t, "Mã của bạn: 123456", form.Get("Body"))
+
+	err = driver.Send(context.Background(), &sms.Message{To: "+15005550001", Body: "x"})
+	var twilioErr *sms.TwilioError
+	require.True(t, errors.As(err, &twilioErr))
+	assert.Equal(t, 21211, twilioErr.Code)
+	assert.True(t, sms.IsPermanent(err))
+
+	err = driver.Send(context.Background(), &sms.Message{To: "+15005550002", Body: "x"})
+	require.Error(t, err)
+	assert.False(t, sms.IsPermanent(err), "rate limit được retry")
+
+	assert.True(t, sms.IsPermanent(driver.Send(context.Background(), &sms.Message{To: "123", Body: "x"})))
+	assert.ErrorIs(t, driver.Send(context.Background(), &sms.Message{To: "+84901234567", Body: " "}), sms.ErrEmptyBody)
+}
+
+func TestSNSDriver(t *testing.T) {
+	var (
+		authorization string
+		form          url.Values
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, r.ParseForm())
+		form = r.PostForm
+
+		switch form.Get("PhoneNumber") {
+		case "+15005550001":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>InvalidParameter</Code><Message>Invalid parameter: PhoneNumber</Message></Error></ErrorResponse>`))
+		case "+15005550002":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>Throttling</Code><Message>Rate exceeded</Message></Error></ErrorResponse>`))
+		default:
+			w.Write([]byte(`<PublishResponse><PublishResult><MessageId>abc</MessageId></PublishResult></PublishResponse>`))
+		}
+	}))
+	defer server.Close()
+
+	driver, err := sms.NewSNSDriver(context.Background(), sms.SNSConfig{
+		Region:          "ap-southeast-1",
+		AccessKeyID:     "AKIDTEST",
+		SecretAccessKey: "secret",
+		SenderID:        "ApiCore",
+		Endpoint:        server.URL,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, driver.Send(context.Background(), &sms.Message{To: "+84901234567", Body: "Mã của bạn: 123456"}))
+	assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDTEST/"), authorization)
+	assert.Contains(t, authorization, "/ap-southeast-1/sns/aws4_request")
+	assert.Equal(t, "Publish", form.Get("Action"))
+	assert.Equal(t, "+84901234567", form.Get("PhoneNumber"))
+	assert.Equal(t, "Mã của bạn: 123456", form.Get("Message"))
+	assert.Equal(t, "AWS.SNS.SMS.SMSType", form.Get("MessageAttributes.entry.1.Name"))
+	assert.Equal(t, "Transactional", form.Get("MessageAttributes.entry.1.Value.StringValue"))
+	assert.Equal(t, "AWS.SNS.SMS.SenderID", form.Get("MessageAttributes.entry.2.Name"))
+	assert.Equal(t, "ApiCore", form.Get("MessageAttributes.entry.2.Value.StringValue"))
+
+	err = driver.Send(context.Background(), &sms.Message{To: "+15005550001", Body: "x"})
+	var snsErr *sms.SNSError
+	require.True(t, errors.As(err, &snsErr))
+	assert.Equal(t, "InvalidParameter", snsErr.Code)
+	assert.True(t, sms.IsPermanent(err))
+
+	err = driver.Send(context.Background(), &sms.Message{To: "+15005550002", Body: "x"})
+	require.Error(t, err)
+	assert.False(t, sms.IsPermanent(err), "throttling được retry")
+}
+
+// recordingSMS sender ghi lại tin nhắn thay vì gửi
+type recordingSMS struct {
+	sent []sms.Message
+}
+
+func (s *recordingSMS) Name() string { return "recording" }
+
+func (s *recordingSMS) Send(ctx context.Context, message *sms.Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+	s.sent = append(s.sent, *message)
+	return nil
+}
+
+func TestSMSHandler(t *testing.T) {
+	ctx := context.Background()
+	sender := &recordingSMS{}
+	handler := workers.NewSMSHandler(sender)
+
+	msg, err := workers.SMSJob.NewMessage(sms.Message{To: "+84 901 234 567", Body: "Mã của bạn: 123456"})
+	require.NoError(t, err)
+	require.NoError(t, handler.Handle(ctx, msg))
+	require.Len(t, sender.sent, 1)
+	assert.Equal(t, "+84901234567", sender.sent[0].To)
+
+	// Số không hợp lệ: không retry
+	msg, err = workers.SMSJob.NewMessage(sms.Message{To: "0901", Body: "x"})
+	require.NoError(t, err)
+	err = handler.Handle(ctx, msg)
+	require.Error(t, err)
+	assert.True(t, queue.IsPermanent(err))
+
+	// Worker chưa cấu hình SMS: bỏ qua job
+	require.NoError(t, workers.NewSMSHandler(nil).Handle(ctx, msg))
+}
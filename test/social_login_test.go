@@ -0,0 +1,230 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/app/auth"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newAuthTestDB SQLite với schema tương đương migration của users, roles, permissions và social_accounts
+func newAuthTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, model.RegisterIDGenerator(db, model.IDConfig{DefaultVersion: utils.UUIDv7}))
+	for _, ddl := range []string{
+		`CREATE TABLE roles (id TEXT PRIMARY KEY, name TEXT NOT NULL UNIQUE, display_name TEXT NOT NULL,
+			description TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE permissions (id TEXT PRIMARY KEY, name TEXT NOT NULL UNIQUE, display_name TEXT NOT NULL,
+			description TEXT, module TEXT, created_at DATETIME, updated_at DATETIME)`,
+		`CREATE TABLE role_has_permissions (role_id TEXT NOT NULL, permission_id TEXT NOT NULL, created_at DATETIME,
+			PRIMARY KEY (role_id, permission_id))`,
+		`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL UNIQUE, password TEXT,
+			avatar TEXT, role_id TEXT, email_verified_at DATETIME, is_active BOOLEAN DEFAULT true, last_login_at DATETIME,
+			token_version INTEGER NOT NULL DEFAULT 0, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE social_accounts (id TEXT PRIMARY KEY, user_id TEXT NOT NULL, provider TEXT NOT NULL,
+			provider_user_id TEXT NOT NULL, email TEXT, created_at DATETIME, updated_at DATETIME,
+			UNIQUE (provider, provider_user_id))`,
+	} {
+		require.NoError(t, db.Exec(ddl).Error)
+	}
+	return db
+}
+
+// createAuthTestUser tạo user đã verify email
+func createAuthTestUser(t *testing.T, db *gorm.DB, email string, active bool) *model.User {
+	t.Helper()
+	now := utils.Now()
+	user := &model.User{Name: email, Email: email, EmailVerifiedAt: &now, IsActive: true}
+	require.NoError(t, repository.NewUserRepository(db).Create(context.Background(), user))
+	if !active {
+		require.NoError(t, db.Model(&model.User{}).Where("id = ?", user.ID).Update("is_active", false).Error)
+	}
+	return user
+}
+
+// fakeSocialProvider provider trả user theo authorization code
+type fakeSocialProvider struct {
+	users map[string]*auth.SocialUser
+}
+
+func (p *fakeSocialProvider) Name() string { return "fake" }
+
+func (p *fakeSocialProvider) AuthCodeURL(ctx context.Context, state string) (string, error) {
+	return "https://provider.test/authorize?state=" + url.QueryEscape(state), nil
+}
+
+func (p *fakeSocialProvider) FetchUser(ctx context.Context, code, state string) (*auth.SocialUser, error) {
+	user, ok := p.users[code]
+	if !ok {
+		return nil, auth.ErrSocialExchangeFailed
+	}
+	return user, nil
+}
+
+type socialLoginFixture struct {
+	db       *gorm.DB
+	service  *auth.Service
+	provider *fakeSocialProvider
+}
+
+func newSocialLoginFixture(t *testing.T) *socialLoginFixture {
+	t.Helper()
+	db := newAuthTestDB(t)
+	provider := &fakeSocialProvider{users: map[string]*auth.SocialUser{}}
+	service := auth.NewService(
+		repository.NewUserRepository(db),
+		repository.NewSocialAccountRepository(db),
+		jwt.NewManager(jwt.Config{SecretKey: "test-secret-key-min-32-chars-long"}),
+		nil, nil, cache.NewMockCache(),
+		auth.SocialProviders{"fake": provider},
+		nil, repository.NewRoleRepository(db), nil, nil,
+	)
+	return &socialLoginFixture{db: db, service: service, provider: provider}
+}
+
+// login chạy redirect rồi callback với cùng verifier
+func (f *socialLoginFixture) login(t *testing.T, code string) *response.Response {
+	t.Helper()
+	verifier := auth.NewSocialVerifier()
+	resp := f.service.SocialRedirect(context.Background(), "fake", auth.SocialChallenge(verifier))
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	state := resp.Data.(*auth.SocialRedirectResponse).State
+	return f.service.SocialLogin(context.Background(), "fake", code, state, verifier)
+}
+
+func (f *socialLoginFixture) accounts(t *testing.T, userID interface{}) int64 {
+	t.Helper()
+	var count int64
+	require.NoError(t, f.db.Model(&model.SocialAccount{}).Where("user_id = ?", userID).Count(&count).Error)
+	return count
+}
+
+func TestSocialLoginLinkedAccount(t *testing.T) {
+	f := newSocialLoginFixture(t)
+	user := createAuthTestUser(t, f.db, "linked@example.com", true)
+	require.NoError(t, f.db.Create(&model.SocialAccount{UserID: user.ID, Provider: "fake", ProviderUserID: "p-1"}).Error)
+
+	// Tài khoản đã liên kết: đăng nhập theo provider user id kể cả khi email bên provider đổi/chưa verify
+	f.provider.users["code"] = &auth.SocialUser{ID: "p-1", Email: "other@example.com"}
+	resp := f.login(t, "code")
+	require.Equal(t, response.CodeLoginSuccess, resp.Code)
+	assert.Equal(t, user.ID, resp.Data.(*auth.LoginResponse).User.ID)
+	assert.NotEmpty(t, resp.Data.(*auth.LoginResponse).AccessToken)
+}
+
+func TestSocialLoginLinksByVerifiedEmail(t *testing.T) {
+	f := newSocialLoginFixture(t)
+	user := createAuthTestUser(t, f.db, "existing@example.com", true)
+
+	f.provider.users["code"] = &auth.SocialUser{ID: "p-2", Email: "existing@example.com", EmailVerified: true}
+	resp := f.login(t, "code")
+	require.Equal(t, response.CodeLoginSuccess, resp.Code)
+	assert.Equal(t, user.ID, resp.Data.(*auth.LoginResponse).User.ID)
+	assert.Equal(t, int64(1), f.accounts(t, user.ID))
+
+	// Email mới: tạo user và liên kết
+	f.provider.users["new"] = &auth.SocialUser{ID: "p-3", Email: "new@example.com", EmailVerified: true, Name: "New"}
+	resp = f.login(t, "new")
+	require.Equal(t, response.CodeLoginSuccess, resp.Code)
+	created := resp.Data.(*auth.LoginResponse).User
+	assert.Equal(t, "new@example.com", created.Email)
+	assert.Equal(t, int64(1), f.accounts(t, created.ID))
+}
+
+func TestSocialLoginRejectsUnverifiedEmail(t *testing.T) {
+	f := newSocialLoginFixture(t)
+	user := createAuthTestUser(t, f.db, "victim@example.com", true)
+
+	// Email chưa verify bên provider không được chiếm tài khoản cùng email
+	f.provider.users["code"] = &auth.SocialUser{ID: "p-4", Email: "victim@example.com"}
+	resp := f.login(t, "code")
+	assert.Equal(t, response.CodeSocialEmailUnverified, resp.Code)
+	assert.Zero(t, f.accounts(t, user.ID))
+}
+
+func TestSocialLoginRejectsDisabledUser(t *testing.T) {
+	f := newSocialLoginFixture(t)
+
+	// Chưa liên kết: liên kết theo email bị chặn
+	unlinked := createAuthTestUser(t, f.db, "unlinked@example.com", false)
+	f.provider.users["unlinked"] = &auth.SocialUser{ID: "p-5", Email: "unlinked@example.com", EmailVerified: true}
+	assert.Equal(t, response.CodeAccountDisabled, f.login(t, "unlinked").Code)
+	assert.Zero(t, f.accounts(t, unlinked.ID))
+
+	// Đã liên kết trước khi bị khóa
+	linked := createAuthTestUser(t, f.db, "linked-disabled@example.com", false)
+	require.NoError(t, f.db.Create(&model.SocialAccount{UserID: linked.ID, Provider: "fake", ProviderUserID: "p-6"}).Error)
+	f.provider.users["linked"] = &auth.SocialUser{ID: "p-6", Email: "linked-disabled@example.com", EmailVerified: true}
+	assert.Equal(t, response.CodeAccountDisabled, f.login(t, "linked").Code)
+}
+
+func TestSocialLoginStateBoundToClient(t *testing.T) {
+	f := newSocialLoginFixture(t)
+	createAuthTestUser(t, f.db, "attacker@example.com", true)
+	f.provider.users["code"] = &auth.SocialUser{ID: "p-7", Email: "attacker@example.com", EmailVerified: true}
+
+	r := chi.NewRouter()
+	auth.RegisterPublicRoutes(r, auth.NewHandler(f.service))
+
+	// Kẻ tấn công bắt đầu luồng đăng nhập, trình duyệt của họ nhận cookie verifier
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/social/fake/redirect", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.True(t, cookies[0].HttpOnly)
+	state := url.QueryEscape(stateFromRedirect(t, rec))
+	callback := "/auth/social/fake/callback?code=code&state=" + state
+
+	// Nạn nhân mở link callback của kẻ tấn công: không có cookie nên bị từ chối, state bị hủy
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, callback, nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.CodeSocialStateInvalid)
+
+	// Luồng bình thường: cùng trình duyệt gửi lại cookie
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/social/fake/redirect", nil))
+	cookie := rec.Result().Cookies()[0]
+	req := httptest.NewRequest(http.MethodGet, "/auth/social/fake/callback?code=code&state="+url.QueryEscape(stateFromRedirect(t, rec)), nil)
+	req.AddCookie(cookie)
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), response.CodeLoginSuccess)
+
+	// PKCE: verifier sai bị từ chối
+	verifier := auth.NewSocialVerifier()
+	resp := f.service.SocialRedirect(context.Background(), "fake", auth.SocialChallenge(verifier))
+	state = resp.Data.(*auth.SocialRedirectResponse).State
+	assert.Equal(t, response.CodeSocialStateInvalid, f.service.SocialLogin(context.Background(), "fake", "code", state, auth.NewSocialVerifier()).Code)
+}
+
+// stateFromRedirect đọc state từ response của /redirect
+func stateFromRedirect(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Data auth.SocialRedirectResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.NotEmpty(t, body.Data.State)
+	return body.Data.State
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startSocketAPI(t *testing.T, hub *socket.Hub) *httptest.Server {
+	clients, err := socket.ParseAPIClients("cron:secret")
+	require.NoError(t, err)
+
+	r := chi.NewRouter()
+	socket.RegisterInternalRoutes(r, hub, clients)
+	server := httptest.NewServer(r)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func postSocketAPI(t *testing.T, server *httptest.Server, path, user, secret, body string) (int, map[string]interface{}) {
+	req, err := http.NewRequest(http.MethodPost, server.URL+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	if user != "" {
+		req.SetBasicAuth(user, secret)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var payload map[string]interface{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	return resp.StatusCode, payload
+}
+
+func TestSocketAPISendsToUserAndRoom(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{})
+	server := startSocketAPI(t, hub)
+
+	alice := dialSocket(t, url+"?user_id=alice")
+	bob := dialSocket(t, url+"?user_id=bob")
+	joinSocketRoom(t, bob, "room-1")
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	status, payload := postSocketAPI(t, server, "/internal/socket/users/alice/send", "cron", "secret",
+		`{"type": "notification", "data": {"title": "Report ready"}}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.EqualValues(t, 1, payload["data"].(map[string]interface{})["connections"])
+
+	message := readSocketMessage(t, alice)
+	assert.Equal(t, "notification", message.Type)
+	assert.Equal(t, "alice", message.UserID)
+	assert.Equal(t, map[string]interface{}{"title": "Report ready"}, message.Data)
+
+	status, payload = postSocketAPI(t, server, "/internal/socket/broadcast", "cron", "secret",
+		`{"type": "announcement", "room": "room-1", "data": "hello"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.EqualValues(t, 1, payload["data"].(map[string]interface{})["connections"])
+	message = readSocketMessage(t, bob)
+	assert.Equal(t, "announcement", message.Type)
+	assert.Equal(t, "room-1", message.Room)
+
+	// Broadcast không có room tới mọi client; alice không nhận event của room-1 trước đó
+	status, _ = postSocketAPI(t, server, "/internal/socket/broadcast", "cron", "secret", `{"type": "maintenance"}`)
+	require.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "maintenance", readSocketMessage(t, alice).Type)
+	assert.Equal(t, "maintenance", readSocketMessage(t, bob).Type)
+}
+
+func TestSocketAPIRejectsInvalidRequests(t *testing.T) {
+	hub, _ := startSocketServerWithConfig(t, socket.HubConfig{})
+	server := startSocketAPI(t, hub)
+
+	status, _ := postSocketAPI(t, server, "/internal/socket/broadcast", "", "", `{"type": "notification"}`)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = postSocketAPI(t, server, "/internal/socket/broadcast", "cron", "wrong", `{"type": "notification"}`)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, _ = postSocketAPI(t, server, "/internal/socket/broadcast", "cron", "secret", `not json`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = postSocketAPI(t, server, "/internal/socket/broadcast", "cron", "secret", `{"data": "missing type"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	status, _ = postSocketAPI(t, server, "/internal/socket/users/alice/send", "cron", "secret", `{"type": "presence"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+
+	_, err := socket.ParseAPIClients("cron")
+	assert.Error(t, err)
+}
+
+func TestSocketAPIDisabledWithoutClients(t *testing.T) {
+	hub, _ := startSocketServerWithConfig(t, socket.HubConfig{})
+	r := chi.NewRouter()
+	socket.RegisterInternalRoutes(r, hub, nil)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/internal/socket/broadcast", strings.NewReader(`{"type": "notification"}`)))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startAuthSocketServer(t *testing.T, manager *jwt.Manager, blacklist *jwt.Blacklist) (*socket.Hub, string) {
+	return startSocketServerWithConfig(t, socket.HubConfig{
+		Authenticator: socket.JWTAuthenticator(manager, blacklist),
+		ResumeStore:   socket.NewCacheResumeStore(cache.NewMockCache()),
+	})
+}
+
+// rejectedHandshake handshake bị từ chối, trả về response code
+func rejectedHandshake(t *testing.T, url string, header http.Header) (int, string) {
+	conn, resp, err := websocket.DefaultDialer.Dial(url, header)
+	if conn != nil {
+		conn.Close()
+	}
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	defer resp.Body.Close()
+
+	var body response.Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	return resp.StatusCode, body.Code
+}
+
+func TestSocketHandshakeAuthentication(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret"})
+	blacklist := jwt.NewBlacklist(cache.NewMockCache())
+	hub, url := startAuthSocketServer(t, manager, blacklist)
+
+	token, err := manager.GenerateToken("user-1", "user1@example.com", "user", nil)
+	require.NoError(t, err)
+
+	status, code := rejectedHandshake(t, url+"?user_id=user-1", nil)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, response.CodeTokenMissing, code)
+
+	_, code = rejectedHandshake(t, url+"?access_token=garbage", nil)
+	assert.Equal(t, response.CodeTokenInvalid, code)
+
+	// Authorization header, user_id trong query bị bỏ qua
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?user_id=admin", http.Header{"Authorization": {"Bearer " + token}})
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, conn, "room-1").Type)
+	client := hub.GetRoomClients("room-1")[0]
+	assert.Equal(t, "user-1", client.UserID)
+	assert.Equal(t, "user1@example.com", client.Principal.Email)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), client.ExpiresAt, 2*time.Second)
+
+	// Subprotocol (trình duyệt không gửi được Authorization header)
+	dialer := websocket.Dialer{Subprotocols: []string{socket.TokenSubprotocol, token}}
+	conn, resp, err := dialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	assert.Equal(t, socket.TokenSubprotocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+	assert.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, conn, "room-2").Type)
+
+	// Query parameter
+	conn = dialSocket(t, url+"?access_token="+token)
+	assert.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, conn, "room-3").Type)
+
+	// Token đã logout
+	require.NoError(t, blacklist.Add(token, time.Now().Add(time.Hour)))
+	_, code = rejectedHandshake(t, url+"?access_token="+token, nil)
+	assert.Equal(t, response.CodeTokenInvalid, code)
+}
+
+func TestSocketClosesWhenTokenExpires(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret", AccessTokenDuration: 2 * time.Second})
+	_, url := startAuthSocketServer(t, manager, jwt.NewBlacklist(cache.NewMockCache()))
+
+	token, err := manager.GenerateToken("user-1", "user1@example.com", "user", nil)
+	require.NoError(t, err)
+	conn := dialSocket(t, url+"?access_token="+token)
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(4*time.Second)))
+	var message socket.Message
+	require.NoError(t, conn.ReadJSON(&message))
+	assert.Equal(t, socket.MessageTypeTokenExpired, message.Type)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, socket.CloseTokenExpired), "unexpected error: %v", err)
+}
+
+func TestSocketResumeRequiresSameUser(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret"})
+	store := socket.NewCacheResumeStore(cache.NewMockCache())
+	_, url := startSocketServerWithConfig(t, socket.HubConfig{
+		Authenticator: socket.JWTAuthenticator(manager, jwt.NewBlacklist(cache.NewMockCache())),
+		ResumeStore:   store,
+	})
+	for _, token := range []string{"token-1", "token-2"} {
+		require.NoError(t, store.Save(context.Background(), token, socket.ResumeState{UserID: "user-1", Rooms: []string{"room-1"}}, time.Minute))
+	}
+
+	other, err := manager.GenerateToken("user-2", "user2@example.com", "user", nil)
+	require.NoError(t, err)
+	conn := dialSocket(t, url+"?resume_token=token-1&access_token="+other)
+	assert.Equal(t, socket.MessageTypeResumeFailed, readSocketMessage(t, conn).Type)
+
+	owner, err := manager.GenerateToken("user-1", "user1@example.com", "user", nil)
+	require.NoError(t, err)
+	conn = dialSocket(t, url+"?resume_token=token-2&access_token="+owner)
+	assert.Equal(t, socket.MessageTypeResumed, readSocketMessage(t, conn).Type)
+}
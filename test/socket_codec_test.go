@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeProtobuf(t *testing.T, conn *websocket.Conn, message socket.Message) {
+	frame, err := socket.ProtobufCodec{}.Encode(message)
+	require.NoError(t, err)
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, frame))
+}
+
+func readProtobuf(t *testing.T, conn *websocket.Conn) socket.Message {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	frameType, frame, err := conn.ReadMessage()
+	require.NoError(t, err)
+	require.Equal(t, websocket.BinaryMessage, frameType)
+	message, err := socket.ProtobufCodec{}.Decode(frame)
+	require.NoError(t, err)
+	return message
+}
+
+func TestProtobufCodecRoundTrip(t *testing.T) {
+	message := socket.Message{
+		Type:      socket.MessageTypeEvent,
+		Seq:       1234,
+		Event:     "chat.typing",
+		ID:        "7",
+		Data:      map[string]interface{}{"conversation_id": "550e8400-e29b-41d4-a716-446655440000", "typing": true, "count": 3},
+		Room:      "conversation:550e8400-e29b-41d4-a716-446655440000",
+		UserID:    "user-1",
+		Timestamp: 1760000000,
+		Metadata:  map[string]interface{}{"source": "mobile"},
+	}
+
+	frame, err := socket.ProtobufCodec{}.Encode(message)
+	require.NoError(t, err)
+	decoded, err := socket.ProtobufCodec{}.Decode(frame)
+	require.NoError(t, err)
+
+	// Data giải mã về kiểu JSON (số là float64), như khi client gửi JSON
+	message.Data = map[string]interface{}{"conversation_id": "550e8400-e29b-41d4-a716-446655440000", "typing": true, "count": float64(3)}
+	assert.Equal(t, message, decoded)
+
+	text, err := socket.JSONCodec{}.Encode(message)
+	require.NoError(t, err)
+	assert.Less(t, len(frame), len(text))
+
+	_, err = socket.ProtobufCodec{}.Decode([]byte{0x0a, 0x05, 'a'})
+	assert.Error(t, err)
+}
+
+func TestSocketProtobufAndJSONClientsShareRooms(t *testing.T) {
+	_, url := startSocketServerWithConfig(t, socket.HubConfig{Codecs: []socket.Codec{socket.ProtobufCodec{}}})
+
+	binary := dialSocket(t, url+"?user_id=alice&codec=protobuf")
+	text := dialSocket(t, url+"?user_id=bob")
+
+	writeProtobuf(t, binary, socket.Message{Type: socket.MessageTypeJoinRoom, Data: "room-1"})
+	joined := readProtobuf(t, binary)
+	assert.Equal(t, socket.MessageTypeJoined, joined.Type)
+	assert.Equal(t, map[string]interface{}{"room": "room-1", "members": float64(1)}, joined.Data)
+	joinSocketRoom(t, text, "room-1")
+
+	// Message của client protobuf tới client JSON và ngược lại
+	writeProtobuf(t, binary, socket.Message{Type: socket.MessageTypeRoomMessage, Room: "room-1", Data: map[string]interface{}{"text": "hi"}})
+	assert.Equal(t, map[string]interface{}{"text": "hi"}, readSocketMessage(t, text).Data)
+	assert.Equal(t, "alice", readProtobuf(t, binary).UserID)
+
+	require.NoError(t, text.WriteJSON(socket.Message{Type: socket.MessageTypeRoomMessage, Room: "room-1", Data: map[string]interface{}{"n": 12345678901234}}))
+	message := readProtobuf(t, binary)
+	assert.Equal(t, "bob", message.UserID)
+	assert.Equal(t, map[string]interface{}{"n": float64(12345678901234)}, message.Data)
+	readSocketMessage(t, text)
+}
+
+func TestSocketProtobufTypedEvent(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{Codecs: []socket.Codec{socket.ProtobufCodec{}}})
+	hub.On("greet", socket.Handle(func(ctx context.Context, client *socket.Client, payload greetPayload) *response.Response {
+		return response.SuccessResponse("en", response.CodeSuccess, "hello "+payload.Name)
+	}))
+
+	conn := dialSocket(t, url+"?user_id=user-1&codec=protobuf")
+	writeProtobuf(t, conn, socket.Message{Event: "greet", ID: "1", Data: map[string]interface{}{"name": "Ann"}})
+	reply := readProtobuf(t, conn)
+	assert.Equal(t, socket.MessageTypeReply, reply.Type)
+	assert.Equal(t, "1", reply.ID)
+	assert.Equal(t, "hello Ann", replyData(t, reply)["data"])
+
+	writeProtobuf(t, conn, socket.Message{Event: "greet", ID: "2", Data: map[string]interface{}{}})
+	reply = readProtobuf(t, conn)
+	assert.Equal(t, socket.MessageTypeError, reply.Type)
+	assert.Equal(t, response.CodeValidationFailed, replyData(t, reply)["code"])
+
+	// Frame không giải mã được trả INVALID_INPUT, connection vẫn mở
+	require.NoError(t, conn.WriteMessage(websocket.BinaryMessage, []byte{0xff}))
+	reply = readProtobuf(t, conn)
+	assert.Equal(t, socket.MessageTypeError, reply.Type)
+	assert.Equal(t, response.CodeInvalidInput, replyData(t, reply)["code"])
+}
+
+func TestSocketJSONEventKeepsNumberPrecision(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{})
+	hub.On("echo", func(ctx context.Context, client *socket.Client, event socket.Event) *response.Response {
+		return response.SuccessResponse("en", response.CodeSuccess, string(event.Data))
+	})
+
+	conn := dialSocket(t, url+"?user_id=user-1")
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"event": "echo", "data": {"id": 9007199254740993}}`)))
+	assert.Equal(t, `{"id":9007199254740993}`, replyData(t, readSocketMessage(t, conn))["data"])
+}
+
+func TestSocketRejectsUnsupportedCodec(t *testing.T) {
+	_, url := startSocketServerWithConfig(t, socket.HubConfig{})
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?user_id=alice&codec=protobuf", nil)
+	require.Error(t, err)
+	require.NotNil(t, resp)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
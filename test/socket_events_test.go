@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type greetPayload struct {
+	Name string `json:"name" validate:"required,max=20"`
+}
+
+func startEventSocketServer(t *testing.T) (*socket.Hub, string) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{})
+	hub.On("greet", socket.Handle(func(ctx context.Context, client *socket.Client, payload greetPayload) *response.Response {
+		client.Hub.EmitToRoom("lobby", "greeted", map[string]string{"name": payload.Name})
+		return response.SuccessResponse("en", response.CodeSuccess, "hello "+payload.Name+" from "+client.UserID)
+	}))
+	hub.On("crash", func(ctx context.Context, client *socket.Client, event socket.Event) *response.Response {
+		panic("boom")
+	})
+	return hub, url
+}
+
+// replyData payload envelope của frame reply/error
+func replyData(t *testing.T, message socket.Message) map[string]interface{} {
+	data, ok := message.Data.(map[string]interface{})
+	require.True(t, ok, "unexpected data: %#v", message.Data)
+	return data
+}
+
+func TestSocketTypedEventReply(t *testing.T) {
+	_, url := startEventSocketServer(t)
+	conn := dialSocket(t, url+"?user_id=user-1")
+	joinSocketRoom(t, conn, "lobby")
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"event": "greet", "id": "42", "data": map[string]string{"name": "Ann"}}))
+
+	// Event đẩy tới room đi qua hub, có thể tới trước hoặc sau reply
+	frames := map[string]socket.Message{}
+	for i := 0; i < 2; i++ {
+		message := readSocketMessage(t, conn)
+		frames[message.Type] = message
+	}
+
+	event := frames[socket.MessageTypeEvent]
+	assert.Equal(t, "greeted", event.Event)
+	assert.Equal(t, map[string]interface{}{"name": "Ann"}, event.Data)
+
+	reply := frames[socket.MessageTypeReply]
+	assert.Equal(t, socket.MessageTypeReply, reply.Type)
+	assert.Equal(t, "greet", reply.Event)
+	assert.Equal(t, "42", reply.ID)
+	data := replyData(t, reply)
+	assert.Equal(t, true, data["success"])
+	assert.Equal(t, "hello Ann from user-1", data["data"])
+}
+
+func TestSocketTypedEventErrors(t *testing.T) {
+	_, url := startEventSocketServer(t)
+	conn := dialSocket(t, url+"?user_id=user-1")
+
+	cases := []struct {
+		name  string
+		frame interface{}
+		code  string
+	}{
+		{"validation", map[string]interface{}{"event": "greet", "id": "1", "data": map[string]string{}}, response.CodeValidationFailed},
+		{"payload type", map[string]interface{}{"event": "greet", "id": "2", "data": "Ann"}, response.CodeInvalidInput},
+		{"unknown event", map[string]interface{}{"event": "missing", "id": "3"}, response.CodeNotFound},
+		{"handler panic", map[string]interface{}{"event": "crash", "id": "4"}, response.CodeInternalServerError},
+	}
+	for _, tc := range cases {
+		require.NoError(t, conn.WriteJSON(tc.frame), tc.name)
+		reply := readSocketMessage(t, conn)
+		assert.Equal(t, socket.MessageTypeError, reply.Type, tc.name)
+		assert.Equal(t, tc.frame.(map[string]interface{})["id"], reply.ID, tc.name)
+		data := replyData(t, reply)
+		assert.Equal(t, false, data["success"], tc.name)
+		assert.Equal(t, tc.code, data["code"], tc.name)
+		if tc.code == response.CodeValidationFailed {
+			assert.Contains(t, data["errors"], "name")
+		}
+	}
+
+	// Frame không phải JSON không đóng connection
+	require.NoError(t, conn.WriteMessage(1, []byte("not json")))
+	reply := readSocketMessage(t, conn)
+	assert.Equal(t, socket.MessageTypeError, reply.Type)
+	assert.Equal(t, response.CodeInvalidInput, replyData(t, reply)["code"])
+
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{"event": "greet", "data": map[string]string{"name": "Bo"}}))
+	assert.Equal(t, socket.MessageTypeReply, readSocketMessage(t, conn).Type)
+}
@@ -0,0 +1,65 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keepReading đọc connection ở background để client tự trả lời ping
+func keepReading(conn *websocket.Conn) {
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func TestSocketHeartbeatKeepsLiveConnection(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{PingInterval: 50 * time.Millisecond, PongWait: 200 * time.Millisecond})
+	conn := dialSocket(t, url+"?user_id=user-1")
+	keepReading(conn)
+
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, 1, hub.GetClientCount())
+
+	connections := hub.Connections()
+	require.Len(t, connections, 1)
+	assert.Equal(t, "user-1", connections[0].UserID)
+	assert.Positive(t, connections[0].RTT)
+	assert.WithinDuration(t, time.Now(), connections[0].LastSeen, 200*time.Millisecond)
+	assert.False(t, connections[0].Stale)
+	assert.Equal(t, socket.LivenessStats{Alive: 1}, hub.Liveness())
+}
+
+func TestSocketClosesDeadConnection(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{PingInterval: 50 * time.Millisecond, PongWait: 200 * time.Millisecond})
+
+	// Client không đọc nên không trả lời ping
+	dialSocket(t, url+"?user_id=user-1")
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 1 }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return hub.Liveness().Stale == 1 }, time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestSocketClosesIdleConnection(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{PingInterval: 50 * time.Millisecond, IdleTimeout: 300 * time.Millisecond})
+	conn := dialSocket(t, url+"?user_id=user-1")
+
+	// Message gửi lùi thời điểm idle
+	time.Sleep(150 * time.Millisecond)
+	joinSocketRoom(t, conn, "room-1")
+	started := time.Now()
+
+	err, _ := readUntilClosed(t, conn)
+	assert.True(t, websocket.IsCloseError(err, socket.CloseIdleTimeout), "unexpected error: %v", err)
+	assert.GreaterOrEqual(t, time.Since(started), 250*time.Millisecond)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readUntilClosed đọc đến khi connection bị đóng, trả về lỗi đóng và các message type nhận được
+func readUntilClosed(t *testing.T, conn *websocket.Conn) (error, []string) {
+	var types []string
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	for {
+		var message socket.Message
+		if err := conn.ReadJSON(&message); err != nil {
+			return err, types
+		}
+		types = append(types, message.Type)
+	}
+}
+
+func TestSocketClosesOnOversizedMessage(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{MaxMessageSize: 1024})
+	conn := dialSocket(t, url+"?user_id=user-1")
+	joinSocketRoom(t, conn, "room-1")
+
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: "room_message", Room: "room-1", Data: strings.Repeat("x", 2048)}))
+	err, _ := readUntilClosed(t, conn)
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "unexpected error: %v", err)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestSocketRateLimitsInboundMessages(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{MessagesPerSecond: 0.1, MessageBurst: 1})
+	conn := dialSocket(t, url+"?user_id=user-1")
+	joinSocketRoom(t, conn, "room-1")
+
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: "join_room", Data: "room-2"}))
+	frame := readSocketMessage(t, conn)
+	require.Equal(t, socket.MessageTypeRateLimited, frame.Type)
+	assert.EqualValues(t, 10000, frame.Data.(map[string]interface{})["retry_after_ms"])
+	assert.Len(t, hub.GetRoomClients("room-2"), 0)
+
+	// Ack không bị tính vào rate limit
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: socket.MessageTypeAck, Seq: 1}))
+
+	for i := 0; i < 9; i++ {
+		require.NoError(t, conn.WriteJSON(socket.Message{Type: "join_room", Data: "room-2"}))
+	}
+	// Các frame rate_limited còn trong send buffer có thể bị bỏ khi connection đóng
+	err, types := readUntilClosed(t, conn)
+	assert.True(t, websocket.IsCloseError(err, socket.CloseRateLimited), "unexpected error: %v", err)
+	assert.NotContains(t, types, socket.MessageTypeJoined)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestSocketDisconnectsSlowConsumer(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{SendBufferSize: 4})
+	conn := dialSocket(t, url+"?user_id=user-1")
+	slow := dialSocket(t, url+"?user_id=user-2")
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	// slow không đọc: socket buffer đầy, writePump bị chặn rồi send buffer đầy
+	payload := strings.Repeat("x", 64<<10)
+	for i := 0; i < 200 && hub.GetClientCount() == 2; i++ {
+		hub.BroadcastToUser("user-2", socket.Message{Type: "notification", Data: payload})
+	}
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	err, _ := readUntilClosed(t, slow)
+	assert.True(t, websocket.IsCloseError(err, socket.CloseSlowConsumer), "unexpected error: %v", err)
+
+	// Client khác không bị ảnh hưởng
+	hub.BroadcastToUser("user-1", socket.Message{Type: "notification", Data: "still here"})
+	assert.Equal(t, "still here", readSocketMessage(t, conn).Data)
+}
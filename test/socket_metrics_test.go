@@ -0,0 +1,134 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/metrics"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSocketStatsCountTraffic(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{})
+
+	alice := dialSocket(t, url+"?user_id=alice")
+	bob := dialSocket(t, url+"?user_id=bob")
+	joinSocketRoom(t, alice, "conversation:1")
+	joinSocketRoom(t, bob, "conversation:1")
+	joinSocketRoom(t, bob, "lobby")
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 2 }, time.Second, 10*time.Millisecond)
+
+	hub.BroadcastToRoom("conversation:1", socket.Message{Type: "announcement"})
+	assert.Equal(t, "announcement", readSocketMessage(t, alice).Type)
+	assert.Equal(t, "announcement", readSocketMessage(t, bob).Type)
+	hub.BroadcastToUser("alice", socket.Message{Type: "notification"})
+	assert.Equal(t, "notification", readSocketMessage(t, alice).Type)
+
+	stats := hub.Stats()
+	assert.Equal(t, 2, stats.Connections)
+	assert.Equal(t, 2, stats.OnlineUsers)
+	assert.Equal(t, 2, stats.Rooms)
+	assert.Equal(t, map[string]int{"conversation": 2, "other": 1}, stats.Channels)
+	assert.Equal(t, socket.RoomStats{Room: "conversation:1", Members: 2}, stats.TopRooms[0])
+	assert.EqualValues(t, 2, stats.Opened)
+	assert.EqualValues(t, 3, stats.Received)
+	// 3 phản hồi joined, broadcast room tới 2 client và 1 tới alice
+	assert.EqualValues(t, 6, stats.Delivered)
+	// Presence của mỗi kết nối cũng broadcast tới room presence:<user_id>
+	assert.GreaterOrEqual(t, stats.Broadcasts["room"], uint64(1))
+	assert.EqualValues(t, 1, stats.Broadcasts["user"])
+	assert.Greater(t, stats.Goroutines, 4)
+
+	// Client đóng bình thường: đếm theo lý do normal
+	require.NoError(t, bob.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+	require.Eventually(t, func() bool { return hub.Stats().Closed["normal"] == 1 }, 2*time.Second, 10*time.Millisecond)
+	stats = hub.Stats()
+	assert.Equal(t, 1, stats.Connections)
+	assert.Equal(t, map[string]int{"conversation": 1}, stats.Channels)
+}
+
+func TestSocketStatsCountRateLimitedAndOversized(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{MessagesPerSecond: 1, MessageBurst: 1, MaxMessageSize: 256})
+
+	conn := dialSocket(t, url+"?user_id=alice")
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: "ping"}))
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: "ping"}))
+	require.Eventually(t, func() bool { return hub.Stats().Dropped["rate_limited"] == 1 }, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"type":"`+strings.Repeat("x", 512)+`"}`)))
+	readUntilClosed(t, conn)
+	require.Eventually(t, func() bool { return hub.Stats().Closed["message_too_big"] == 1 }, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 0, hub.Stats().Connections)
+}
+
+func TestSocketMetricsExport(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{})
+	registry := metrics.NewRegistry()
+	socket.NewMetrics(registry, "socket").Watch(hub)
+
+	conn := dialSocket(t, url+"?user_id=alice")
+	joinSocketRoom(t, conn, "conversation:1")
+	hub.BroadcastToAll(socket.Message{Type: "maintenance"})
+	assert.Equal(t, "maintenance", readSocketMessage(t, conn).Type)
+
+	body := scrapeMetrics(t, registry, false)
+	for _, line := range []string{
+		"socket_connections 1",
+		"socket_rooms 1",
+		`socket_room_members{channel="conversation"} 1`,
+		`socket_connections_liveness{state="alive"} 1`,
+		"socket_connections_opened_total 1",
+		"socket_messages_received_total 1",
+		`socket_broadcasts_total{target="all"} 1`,
+		`socket_messages_dropped_total{reason="slow_consumer"} 0`,
+	} {
+		assert.Contains(t, body, line)
+	}
+	assert.Contains(t, body, "socket_goroutines ")
+
+	// Scrape lần hai chỉ cộng phần tăng thêm, room rỗng bị xóa khỏi channel
+	leave := socket.Message{Type: socket.MessageTypeLeaveRoom, Data: "conversation:1"}
+	require.NoError(t, conn.WriteJSON(leave))
+	readSocketMessage(t, conn)
+	require.Eventually(t, func() bool { return hub.Stats().Rooms == 0 }, time.Second, 10*time.Millisecond)
+	body = scrapeMetrics(t, registry, false)
+	assert.Contains(t, body, "socket_messages_received_total 2")
+	assert.Contains(t, body, `socket_broadcasts_total{target="all"} 1`)
+	assert.NotContains(t, body, `socket_room_members{channel="conversation"}`)
+}
+
+func TestSocketAPIMetrics(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{})
+	server := startSocketAPI(t, hub)
+
+	conn := dialSocket(t, url+"?user_id=alice")
+	joinSocketRoom(t, conn, "conversation:1")
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/internal/socket/metrics", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	req.SetBasicAuth("cron", "secret")
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var payload struct {
+		Data socket.HubStats `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&payload))
+	assert.Equal(t, 1, payload.Data.Connections)
+	assert.Equal(t, []socket.RoomStats{{Room: "conversation:1", Members: 1}}, payload.Data.TopRooms)
+	assert.EqualValues(t, 1, payload.Data.Opened)
+}
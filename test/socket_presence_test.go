@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readPresence(t *testing.T, conn *websocket.Conn) socket.PresenceData {
+	message := readSocketMessage(t, conn)
+	require.Equal(t, socket.MessageTypePresence, message.Type)
+	data := message.Data.(map[string]interface{})
+	return socket.PresenceData{UserID: data["user_id"].(string), Online: data["online"].(bool)}
+}
+
+func TestSocketPresenceAcrossInstances(t *testing.T) {
+	ctx := context.Background()
+	store := socket.NewMemoryPresenceStore()
+	hubA, urlA := startSocketServerWithConfig(t, socket.HubConfig{Presence: store, InstanceID: "instance-a"})
+	hubB, urlB := startSocketServerWithConfig(t, socket.HubConfig{Presence: store, InstanceID: "instance-b"})
+
+	watcher := dialSocket(t, urlB+"?user_id=watcher")
+	require.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, watcher, socket.PresenceRoom("alice")).Type)
+
+	// Kết nối đầu tiên của alice (instance A), watcher ở instance B nhận được event
+	first := dialSocket(t, urlA+"?user_id=alice")
+	assert.Equal(t, socket.PresenceData{UserID: "alice", Online: true}, readPresence(t, watcher))
+
+	online, err := hubB.IsOnline(ctx, "alice")
+	require.NoError(t, err)
+	assert.True(t, online)
+	users, err := hubB.ListOnline(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "watcher"}, users)
+
+	// Kết nối thứ 2 ở instance B rồi đóng kết nối ở A: alice vẫn online, không có event
+	second := dialSocket(t, urlB+"?user_id=alice")
+	require.Eventually(t, func() bool {
+		online, _ := store.Online(ctx, []string{"alice"})
+		users, _ := hubB.ListOnline(ctx)
+		return online["alice"] && len(users) == 2 && hubB.GetClientCount() == 2
+	}, time.Second, 10*time.Millisecond)
+	first.Close()
+	require.Eventually(t, func() bool {
+		elsewhere, _ := store.OnlineElsewhere(ctx, "instance-b", "alice")
+		return hubA.GetClientCount() == 0 && !elsewhere
+	}, time.Second, 10*time.Millisecond)
+	online, err = hubA.IsOnline(ctx, "alice")
+	require.NoError(t, err)
+	assert.True(t, online)
+
+	// Đóng kết nối cuối cùng: offline
+	second.Close()
+	assert.Equal(t, socket.PresenceData{UserID: "alice", Online: false}, readPresence(t, watcher))
+	hubB.BroadcastToRoom(socket.PresenceRoom("alice"), socket.Message{Type: "notification", Data: "end"})
+	assert.Equal(t, "end", readSocketMessage(t, watcher).Data, "chỉ 1 event online và 1 event offline")
+
+	online, err = hubA.IsOnline(ctx, "alice")
+	require.NoError(t, err)
+	assert.False(t, online)
+
+	// Instance shutdown: user của instance bị xóa khỏi store
+	require.NoError(t, hubB.Shutdown(ctx))
+	users, err = store.ListOnline(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, users)
+}
+
+func TestSocketPresenceSingleInstance(t *testing.T) {
+	ctx := context.Background()
+	hub, url := startSocketServer(t, nil)
+
+	watcher := dialSocket(t, url+"?user_id=watcher")
+	require.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, watcher, socket.PresenceRoom("bob")).Type)
+
+	bob := dialSocket(t, url+"?user_id=bob")
+	assert.Equal(t, socket.PresenceData{UserID: "bob", Online: true}, readPresence(t, watcher))
+
+	// Kết nối không có user_id không được tính
+	dialSocket(t, url)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 3 }, time.Second, 10*time.Millisecond)
+	users, err := hub.ListOnline(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob", "watcher"}, users)
+
+	bob.Close()
+	assert.Equal(t, socket.PresenceData{UserID: "bob", Online: false}, readPresence(t, watcher))
+	online, err := hub.IsOnline(ctx, "bob")
+	require.NoError(t, err)
+	assert.False(t, online)
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startReplaySocketServer(t *testing.T, window time.Duration) (*socket.Hub, string) {
+	return startSocketServerWithConfig(t, socket.HubConfig{ReplayBufferSize: 3, ReplayWindow: window})
+}
+
+// readSession đọc frame "session" đầu tiên của connection
+func readSession(t *testing.T, conn *websocket.Conn) string {
+	frame := readSocketMessage(t, conn)
+	require.Equal(t, socket.MessageTypeSession, frame.Type)
+	sessionID, _ := frame.Data.(map[string]interface{})["session_id"].(string)
+	require.NotEmpty(t, sessionID)
+	return sessionID
+}
+
+// dropSocket đóng TCP connection không gửi close frame (mất mạng)
+func dropSocket(conn *websocket.Conn) {
+	conn.UnderlyingConn().Close()
+}
+
+func TestSocketReplayAfterNetworkDrop(t *testing.T) {
+	hub, url := startReplaySocketServer(t, time.Minute)
+
+	conn := dialSocket(t, url+"?user_id=user-1")
+	sessionID := readSession(t, conn)
+	joined := joinSocketRoom(t, conn, "room-1")
+	assert.EqualValues(t, 1, joined.Seq)
+
+	hub.BroadcastToRoom("room-1", socket.Message{Type: "notification", Data: "one"})
+	message := readSocketMessage(t, conn)
+	require.EqualValues(t, 2, message.Seq)
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: socket.MessageTypeAck, Seq: message.Seq}))
+
+	dropSocket(conn)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 10*time.Millisecond)
+
+	// Session giữ room và nhận message trong lúc mất kết nối
+	assert.Len(t, hub.GetRoomClients("room-1"), 1)
+	hub.BroadcastToRoom("room-1", socket.Message{Type: "notification", Data: "two"})
+	hub.BroadcastToRoom("room-1", socket.Message{Type: "notification", Data: "three"})
+
+	resumed := dialSocket(t, url+"?user_id=user-1&session_id="+sessionID+"&last_seq=2")
+	frame := readSocketMessage(t, resumed)
+	require.Equal(t, socket.MessageTypeResumed, frame.Type)
+	data := frame.Data.(map[string]interface{})
+	assert.Equal(t, []interface{}{"room-1"}, data["rooms"])
+	assert.EqualValues(t, 2, data["replayed"])
+	assert.Equal(t, sessionID, data["session_id"])
+	assert.Nil(t, data["missed"])
+
+	for i, text := range []string{"two", "three"} {
+		message := readSocketMessage(t, resumed)
+		assert.EqualValues(t, 3+i, message.Seq)
+		assert.Equal(t, text, message.Data)
+	}
+
+	// Connection mới tiếp tục dãy seq của session
+	hub.BroadcastToRoom("room-1", socket.Message{Type: "notification", Data: "four"})
+	message = readSocketMessage(t, resumed)
+	assert.EqualValues(t, 5, message.Seq)
+	assert.Len(t, hub.GetRoomClients("room-1"), 1)
+}
+
+func TestSocketReplayReportsMissedMessages(t *testing.T) {
+	hub, url := startReplaySocketServer(t, time.Minute)
+
+	conn := dialSocket(t, url+"?user_id=user-1")
+	sessionID := readSession(t, conn)
+	joinSocketRoom(t, conn, "room-1")
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: socket.MessageTypeAck, Seq: 1}))
+	dropSocket(conn)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 10*time.Millisecond)
+
+	// Buffer chỉ giữ 3 message: seq 2 bị bỏ
+	for i := 0; i < 4; i++ {
+		hub.BroadcastToRoom("room-1", socket.Message{Type: "notification", Data: i})
+	}
+
+	resumed := dialSocket(t, url+"?user_id=user-1&session_id="+sessionID+"&last_seq=1")
+	frame := readSocketMessage(t, resumed)
+	require.Equal(t, socket.MessageTypeResumed, frame.Type)
+	data := frame.Data.(map[string]interface{})
+	assert.EqualValues(t, 3, data["replayed"])
+	assert.Equal(t, true, data["missed"])
+	assert.EqualValues(t, 3, readSocketMessage(t, resumed).Seq)
+}
+
+func TestSocketReplayRejectsOtherUserAndExpiredSession(t *testing.T) {
+	hub, url := startReplaySocketServer(t, 200*time.Millisecond)
+
+	conn := dialSocket(t, url+"?user_id=user-1")
+	sessionID := readSession(t, conn)
+	joinSocketRoom(t, conn, "room-1")
+	dropSocket(conn)
+	require.Eventually(t, func() bool { return hub.GetClientCount() == 0 }, time.Second, 10*time.Millisecond)
+
+	other := dialSocket(t, url+"?user_id=user-2&session_id="+sessionID)
+	assert.Equal(t, socket.MessageTypeResumeFailed, readSocketMessage(t, other).Type)
+	assert.NotEqual(t, sessionID, readSession(t, other))
+
+	// Hết ReplayWindow: session bị xóa khỏi room
+	require.Eventually(t, func() bool { return len(hub.GetRoomClients("room-1")) == 0 }, time.Second, 10*time.Millisecond)
+	late := dialSocket(t, url+"?user_id=user-1&session_id="+sessionID)
+	assert.Equal(t, socket.MessageTypeResumeFailed, readSocketMessage(t, late).Type)
+}
+
+func TestSocketNormalCloseEndsSession(t *testing.T) {
+	hub, url := startReplaySocketServer(t, time.Minute)
+
+	conn := dialSocket(t, url+"?user_id=user-1")
+	sessionID := readSession(t, conn)
+	joinSocketRoom(t, conn, "room-1")
+	require.NoError(t, conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+
+	require.Eventually(t, func() bool { return len(hub.GetRoomClients("room-1")) == 0 }, time.Second, 10*time.Millisecond)
+	again := dialSocket(t, url+"?user_id=user-1&session_id="+sessionID)
+	assert.Equal(t, socket.MessageTypeResumeFailed, readSocketMessage(t, again).Type)
+}
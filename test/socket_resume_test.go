@@ -0,0 +1,139 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func startSocketServer(t *testing.T, store socket.ResumeStore) (*socket.Hub, string) {
+	return startSocketServerWithConfig(t, socket.HubConfig{ResumeStore: store, ReconnectDelay: 250 * time.Millisecond})
+}
+
+func startSocketServerWithConfig(t *testing.T, config socket.HubConfig) (*socket.Hub, string) {
+	hub := socket.NewHubWithConfig(config)
+	go hub.Run()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		socket.ServeWS(hub, w, r)
+	}))
+	t.Cleanup(server.Close)
+	return hub, "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func dialSocket(t *testing.T, url string) *websocket.Conn {
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func readSocketMessage(t *testing.T, conn *websocket.Conn) socket.Message {
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var message socket.Message
+	require.NoError(t, conn.ReadJSON(&message))
+	return message
+}
+
+func TestSocketShutdownHandsOverSession(t *testing.T) {
+	store := socket.NewCacheResumeStore(cache.NewMockCache())
+	oldHub, oldURL := startSocketServer(t, store)
+	newHub, newURL := startSocketServer(t, store)
+
+	conn := dialSocket(t, oldURL+"?user_id=user-1")
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: "join_room", Data: "room-1"}))
+	require.Equal(t, socket.MessageTypeJoined, readSocketMessage(t, conn).Type)
+	require.Len(t, oldHub.GetRoomClients("room-1"), 1)
+
+	shutdownDone := make(chan error, 1)
+	go func() { shutdownDone <- oldHub.Shutdown(context.Background()) }()
+
+	frame := readSocketMessage(t, conn)
+	require.Equal(t, socket.MessageTypeReconnect, frame.Type)
+	data := frame.Data.(map[string]interface{})
+	token, _ := data["resume_token"].(string)
+	require.NotEmpty(t, token)
+	assert.EqualValues(t, 250, data["retry_after_ms"])
+
+	// Server đóng connection với 1012 (service restart)
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseServiceRestart), "unexpected error: %v", err)
+	require.NoError(t, <-shutdownDone)
+
+	resumed := dialSocket(t, newURL+"?resume_token="+token)
+	frame = readSocketMessage(t, resumed)
+	require.Equal(t, socket.MessageTypeResumed, frame.Type)
+	assert.Equal(t, []interface{}{"room-1"}, frame.Data.(map[string]interface{})["rooms"])
+
+	// Room và user được khôi phục trên instance mới
+	require.Eventually(t, func() bool { return len(newHub.GetRoomClients("room-1")) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "user-1", newHub.GetRoomClients("room-1")[0].UserID)
+	newHub.BroadcastToRoom("room-1", socket.Message{Type: "notification", Data: "hello"})
+	assert.Equal(t, "hello", readSocketMessage(t, resumed).Data)
+
+	// Token chỉ dùng được một lần
+	again := dialSocket(t, newURL+"?resume_token="+token)
+	assert.Equal(t, socket.MessageTypeResumeFailed, readSocketMessage(t, again).Type)
+}
+
+func TestSocketResumeReplaysUndeliveredEvents(t *testing.T) {
+	store := socket.NewCacheResumeStore(cache.NewMockCache())
+	_, url := startSocketServer(t, store)
+
+	require.NoError(t, store.Save(context.Background(), "token-1", socket.ResumeState{
+		UserID: "user-1",
+		Rooms:  []string{"room-1"},
+		Events: []socket.Message{{Type: "notification", Data: "first"}, {Type: "notification", Data: "second"}},
+	}, time.Minute))
+
+	conn := dialSocket(t, url+"?resume_token=token-1")
+	frame := readSocketMessage(t, conn)
+	require.Equal(t, socket.MessageTypeResumed, frame.Type)
+	assert.EqualValues(t, 2, frame.Data.(map[string]interface{})["replayed"])
+	assert.Equal(t, "first", readSocketMessage(t, conn).Data)
+	assert.Equal(t, "second", readSocketMessage(t, conn).Data)
+}
+
+func TestSocketResumeUnknownToken(t *testing.T) {
+	_, url := startSocketServer(t, socket.NewCacheResumeStore(cache.NewMockCache()))
+
+	conn := dialSocket(t, url+"?resume_token=missing")
+	assert.Equal(t, socket.MessageTypeResumeFailed, readSocketMessage(t, conn).Type)
+}
+
+// redisMissCache trả về redis.Nil khi không có key như redis cache thật (mock trả về cache.ErrCacheMiss)
+type redisMissCache struct {
+	cache.Cache
+}
+
+func (c redisMissCache) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.Cache.Get(ctx, key)
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return "", redis.Nil
+	}
+	return value, err
+}
+
+func TestSocketResumeStoreRedisMiss(t *testing.T) {
+	store := socket.NewCacheResumeStore(redisMissCache{cache.NewMockCache()})
+
+	state, err := store.Take(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	_, url := startSocketServer(t, store)
+	conn := dialSocket(t, url+"?resume_token=missing")
+	assert.Equal(t, socket.MessageTypeResumeFailed, readSocketMessage(t, conn).Type)
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/socket"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// vipOnly chỉ user "vip" được join room "vip", room "announcements" chỉ đọc
+func vipOnly(ctx context.Context, client *socket.Client, room string, action socket.RoomAction) error {
+	if room == "vip" && client.UserID != "vip" {
+		return errors.New("vip only")
+	}
+	if room == "announcements" && action == socket.RoomActionPublish {
+		return errors.New("read only")
+	}
+	return nil
+}
+
+func joinSocketRoom(t *testing.T, conn *websocket.Conn, room string) socket.Message {
+	require.NoError(t, conn.WriteJSON(socket.Message{Type: socket.MessageTypeJoinRoom, Data: room}))
+	return readSocketMessage(t, conn)
+}
+
+func TestSocketRoomMessagesStayInRoom(t *testing.T) {
+	_, url := startSocketServerWithConfig(t, socket.HubConfig{Authorizer: vipOnly})
+
+	alice := dialSocket(t, url+"?user_id=alice")
+	bob := dialSocket(t, url+"?user_id=bob")
+	carol := dialSocket(t, url+"?user_id=carol")
+
+	joined := joinSocketRoom(t, alice, "chat-1")
+	require.Equal(t, socket.MessageTypeJoined, joined.Type)
+	assert.Equal(t, "chat-1", joined.Room)
+	assert.EqualValues(t, 1, joined.Data.(map[string]interface{})["members"])
+	assert.EqualValues(t, 2, joinSocketRoom(t, bob, "chat-1").Data.(map[string]interface{})["members"])
+	require.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, carol, "chat-2").Type)
+
+	// user_id do server gán, client không giả mạo được
+	require.NoError(t, alice.WriteJSON(socket.Message{
+		Type:   socket.MessageTypeRoomMessage,
+		UserID: "bob",
+		Data:   map[string]interface{}{"room": "chat-1", "message": "hi"},
+	}))
+	for _, conn := range []*websocket.Conn{alice, bob} {
+		message := readSocketMessage(t, conn)
+		require.Equal(t, socket.MessageTypeRoomMessage, message.Type)
+		assert.Equal(t, "chat-1", message.Room)
+		assert.Equal(t, "alice", message.UserID)
+		assert.Equal(t, "hi", message.Data.(map[string]interface{})["message"])
+	}
+
+	// Client không ở trong room không gửi được
+	require.NoError(t, carol.WriteJSON(socket.Message{Type: socket.MessageTypeRoomMessage, Room: "chat-1", Data: "spam"}))
+	rejected := readSocketMessage(t, carol)
+	require.Equal(t, socket.MessageTypeRoomError, rejected.Type)
+	assert.Equal(t, "publish", rejected.Data.(map[string]interface{})["action"])
+	assert.Equal(t, socket.ErrNotInRoom.Error(), rejected.Data.(map[string]interface{})["error"])
+
+	// Broadcast từ client bị tắt mặc định
+	require.NoError(t, carol.WriteJSON(socket.Message{Type: socket.MessageTypeBroadcast, Data: "everyone"}))
+	rejected = readSocketMessage(t, carol)
+	require.Equal(t, socket.MessageTypeRoomError, rejected.Type)
+	assert.Equal(t, "broadcast", rejected.Data.(map[string]interface{})["action"])
+
+	// Rời room thì không nhận message của room nữa
+	require.NoError(t, bob.WriteJSON(socket.Message{Type: socket.MessageTypeLeaveRoom, Data: "chat-1"}))
+	left := readSocketMessage(t, bob)
+	require.Equal(t, socket.MessageTypeLeft, left.Type)
+	assert.EqualValues(t, 1, left.Data.(map[string]interface{})["members"])
+
+	require.NoError(t, alice.WriteJSON(socket.Message{Type: socket.MessageTypeRoomMessage, Room: "chat-1", Data: "again"}))
+	assert.Equal(t, "again", readSocketMessage(t, alice).Data)
+	for _, conn := range []*websocket.Conn{bob, carol} {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(200*time.Millisecond)))
+		var message socket.Message
+		assert.Error(t, conn.ReadJSON(&message), "không nhận message của room khác")
+	}
+}
+
+func TestSocketRoomAuthorizer(t *testing.T) {
+	hub, url := startSocketServerWithConfig(t, socket.HubConfig{Authorizer: vipOnly})
+
+	guest := dialSocket(t, url+"?user_id=guest")
+	vip := dialSocket(t, url+"?user_id=vip")
+
+	denied := joinSocketRoom(t, guest, "vip")
+	require.Equal(t, socket.MessageTypeRoomError, denied.Type)
+	assert.Equal(t, "join", denied.Data.(map[string]interface{})["action"])
+	assert.Contains(t, denied.Data.(map[string]interface{})["error"], "vip only")
+	require.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, vip, "vip").Type)
+	assert.Len(t, hub.GetRoomClients("vip"), 1)
+
+	// Room tên rỗng hoặc quá dài bị từ chối
+	assert.Equal(t, socket.MessageTypeRoomError, joinSocketRoom(t, guest, "").Type)
+
+	// Join được nhưng không được gửi
+	require.Equal(t, socket.MessageTypeJoined, joinSocketRoom(t, guest, "announcements").Type)
+	require.NoError(t, guest.WriteJSON(socket.Message{Type: socket.MessageTypeRoomMessage, Room: "announcements", Data: "hello"}))
+	rejected := readSocketMessage(t, guest)
+	require.Equal(t, socket.MessageTypeRoomError, rejected.Type)
+	assert.Equal(t, "publish", rejected.Data.(map[string]interface{})["action"])
+
+	// Server vẫn gửi vào room được
+	hub.BroadcastToRoom("announcements", socket.Message{Type: "notification", Data: "release"})
+	assert.Equal(t, "release", readSocketMessage(t, guest).Data)
+}
+
+func TestSocketResumeAuthorizesRoomsAgain(t *testing.T) {
+	store := socket.NewCacheResumeStore(cache.NewMockCache())
+	_, url := startSocketServerWithConfig(t, socket.HubConfig{ResumeStore: store, Authorizer: vipOnly})
+
+	require.NoError(t, store.Save(context.Background(), "token-1", socket.ResumeState{
+		UserID: "guest",
+		Rooms:  []string{"vip", "general"},
+	}, time.Minute))
+
+	conn := dialSocket(t, url+"?resume_token=token-1")
+	frame := readSocketMessage(t, conn)
+	require.Equal(t, socket.MessageTypeResumed, frame.Type)
+	assert.Equal(t, []interface{}{"general"}, frame.Data.(map[string]interface{})["rooms"])
+}
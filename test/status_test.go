@@ -0,0 +1,268 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/status"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var statusTestConfig = status.Config{
+	Enabled:         true,
+	CheckInterval:   time.Minute,
+	CheckTimeout:    time.Second,
+	DegradedLatency: 50 * time.Millisecond,
+	HistoryDays:     3,
+}
+
+// slotLockCache mock cache có Lock giống Redis SETNX (MockCache.Lock luôn thành công)
+type slotLockCache struct {
+	cache.Cache
+	held map[string]bool
+}
+
+func (c *slotLockCache) Lock(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	if c.held[key] {
+		return false, nil
+	}
+	c.held[key] = true
+	return true, nil
+}
+
+func setupStatusService(t *testing.T, checkers status.Checkers) (*status.Service, *gorm.DB, cache.Cache) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, model.RegisterIDGenerator(db, model.IDConfig{DefaultVersion: utils.UUIDv7}))
+	// Schema tương đương migration 000017 (SQLite không có gen_random_uuid, ID sinh ở callback)
+	require.NoError(t, db.Exec(`CREATE TABLE status_checks (
+		id INTEGER PRIMARY KEY AUTOINCREMENT, component TEXT NOT NULL, status TEXT NOT NULL,
+		latency_ms INTEGER NOT NULL DEFAULT 0, error TEXT, checked_at DATETIME NOT NULL)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE status_incidents (
+		id TEXT PRIMARY KEY, title TEXT NOT NULL, message TEXT NOT NULL DEFAULT '', impact TEXT NOT NULL,
+		status TEXT NOT NULL, components TEXT NOT NULL DEFAULT '[]', started_at DATETIME NOT NULL,
+		resolved_at DATETIME, created_at DATETIME, updated_at DATETIME)`).Error)
+
+	cacheClient := &slotLockCache{Cache: cache.NewMockCache(), held: map[string]bool{}}
+	svc := status.NewService(
+		repository.NewStatusCheckRepository(db),
+		repository.NewStatusIncidentRepository(db),
+		cacheClient,
+		checkers,
+		statusTestConfig,
+	)
+	return svc, db, cacheClient
+}
+
+func findComponent(t *testing.T, page status.Page, name string) status.ComponentStatus {
+	for _, component := range page.Components {
+		if component.Name == name {
+			return component
+		}
+	}
+	t.Fatalf("component %s not found", name)
+	return status.ComponentStatus{}
+}
+
+func TestStatusRecordChecksClassifiesResults(t *testing.T) {
+	now := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	ctx := clock.WithContext(context.Background(), clock.NewFrozen(now))
+
+	svc, db, _ := setupStatusService(t, status.Checkers{
+		{Component: status.ComponentDatabase, Check: func(ctx context.Context) error { return nil }},
+		{Component: status.ComponentCache, Check: func(ctx context.Context) error { return errors.New("connection refused") }},
+		{Component: status.ComponentStorage, Check: func(ctx context.Context) error {
+			time.Sleep(80 * time.Millisecond)
+			return nil
+		}},
+	})
+
+	require.NoError(t, svc.RecordChecks(ctx))
+
+	var checks []model.StatusCheck
+	require.NoError(t, db.Order("component").Find(&checks).Error)
+	require.Len(t, checks, 4)
+
+	byComponent := map[string]model.StatusCheck{}
+	for _, check := range checks {
+		byComponent[check.Component] = check
+	}
+	assert.Equal(t, model.ComponentStatusOperational, byComponent[status.ComponentAPI].Status)
+	assert.Equal(t, model.ComponentStatusOperational, byComponent[status.ComponentDatabase].Status)
+	assert.Equal(t, model.ComponentStatusDown, byComponent[status.ComponentCache].Status)
+	require.NotNil(t, byComponent[status.ComponentCache].Error)
+	assert.Equal(t, model.ComponentStatusDegraded, byComponent[status.ComponentStorage].Status)
+}
+
+func TestStatusRecordSlotRunsOncePerInterval(t *testing.T) {
+	c := clock.NewFrozen(time.Date(2024, 5, 3, 12, 0, 5, 0, time.UTC))
+	ctx := clock.WithContext(context.Background(), c)
+
+	var calls atomic.Int32
+	svc, _, _ := setupStatusService(t, status.Checkers{
+		{Component: status.ComponentDatabase, Check: func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		}},
+	})
+
+	recorded, err := svc.RecordSlot(ctx)
+	require.NoError(t, err)
+	assert.True(t, recorded)
+
+	// Instance khác trong cùng chu kỳ không check lại
+	c.Advance(30 * time.Second)
+	recorded, err = svc.RecordSlot(ctx)
+	require.NoError(t, err)
+	assert.False(t, recorded)
+
+	c.Advance(time.Minute)
+	recorded, err = svc.RecordSlot(ctx)
+	require.NoError(t, err)
+	assert.True(t, recorded)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestStatusPageAggregatesHistoryAndIncidents(t *testing.T) {
+	now := time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC)
+	c := clock.NewFrozen(now)
+	ctx := clock.WithContext(context.Background(), c)
+
+	svc, db, _ := setupStatusService(t, status.Checkers{
+		{Component: status.ComponentDatabase, Check: func(ctx context.Context) error { return nil }},
+	})
+
+	// Hôm qua: check mỗi phút từ 00:00 tới 23:59, database lỗi 2 giờ
+	yesterday := now.Truncate(24*time.Hour).AddDate(0, 0, -1)
+	var checks []model.StatusCheck
+	for i := 0; i < 24*60; i++ {
+		at := yesterday.Add(time.Duration(i) * time.Minute)
+		dbStatus := model.ComponentStatusOperational
+		if i < 120 {
+			dbStatus = model.ComponentStatusDown
+		}
+		checks = append(checks,
+			model.StatusCheck{Component: status.ComponentAPI, Status: model.ComponentStatusOperational, CheckedAt: at},
+			model.StatusCheck{Component: status.ComponentDatabase, Status: dbStatus, LatencyMs: 4, CheckedAt: at},
+		)
+	}
+	require.NoError(t, db.CreateInBatches(&checks, 500).Error)
+
+	resolvedAt := yesterday.Add(2 * time.Hour)
+	incident := model.StatusIncident{
+		Title:      "Database outage",
+		Impact:     model.IncidentImpactMajor,
+		Status:     model.IncidentStatusResolved,
+		Components: []string{status.ComponentDatabase},
+		StartedAt:  yesterday,
+		ResolvedAt: &resolvedAt,
+	}
+	require.NoError(t, db.Create(&incident).Error)
+
+	// Hôm nay mới check 1 lần
+	require.NoError(t, svc.RecordChecks(ctx))
+
+	resp := svc.GetStatus(ctx)
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	page := resp.Data.(*status.Page)
+
+	assert.Equal(t, model.ComponentStatusOperational, page.Status)
+	require.Len(t, page.Incidents, 1)
+
+	database := findComponent(t, *page, status.ComponentDatabase)
+	require.Len(t, database.History, 3)
+	assert.Equal(t, status.StatusUnknown, database.History[0].Status, "chưa có dữ liệu")
+	assert.Equal(t, model.ComponentStatusDown, database.History[1].Status)
+	require.NotNil(t, database.History[1].UptimePercent)
+	assert.InDelta(t, 91.667, *database.History[1].UptimePercent, 0.001)
+	assert.Equal(t, []string{incident.ID.String()}, []string{database.History[1].Incidents[0].String()})
+	assert.Equal(t, model.ComponentStatusOperational, database.History[2].Status)
+	assert.Empty(t, database.History[2].Incidents)
+
+	// API thiếu kết quả từ 00:00 tới 12:00 hôm nay: tính là downtime
+	api := findComponent(t, *page, status.ComponentAPI)
+	require.NotNil(t, api.History[1].UptimePercent)
+	assert.Equal(t, 100.0, *api.History[1].UptimePercent)
+	require.NotNil(t, api.History[2].UptimePercent)
+	assert.Less(t, *api.History[2].UptimePercent, 1.0)
+	assert.Empty(t, findComponent(t, *page, status.ComponentAPI).History[1].Incidents)
+}
+
+func TestStatusPageMarksStaleChecksUnknown(t *testing.T) {
+	c := clock.NewFrozen(time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC))
+	ctx := clock.WithContext(context.Background(), c)
+
+	svc, _, cacheClient := setupStatusService(t, status.Checkers{
+		{Component: status.ComponentDatabase, Check: func(ctx context.Context) error { return errors.New("timeout") }},
+	})
+	require.NoError(t, svc.RecordChecks(ctx))
+
+	page := svc.GetStatus(ctx).Data.(*status.Page)
+	assert.Equal(t, model.ComponentStatusDown, page.Status)
+
+	// Quá 3 chu kỳ không có kết quả mới
+	c.Advance(5 * time.Minute)
+	require.NoError(t, cacheClient.Del(ctx, "status:page"))
+	page = svc.GetStatus(ctx).Data.(*status.Page)
+	assert.Equal(t, status.StatusUnknown, page.Status)
+	assert.Equal(t, status.StatusUnknown, findComponent(t, *page, status.ComponentDatabase).Status)
+}
+
+func TestStatusIncidentLifecycle(t *testing.T) {
+	c := clock.NewFrozen(time.Date(2024, 5, 3, 12, 0, 0, 0, time.UTC))
+	ctx := clock.WithContext(context.Background(), c)
+
+	svc, _, _ := setupStatusService(t, nil)
+
+	// Page đã cache trước khi có sự cố
+	page := svc.GetStatus(ctx).Data.(*status.Page)
+	assert.Empty(t, page.Incidents)
+
+	resp := svc.CreateIncident(ctx, status.CreateIncidentRequest{
+		Title:  "Push notifications delayed",
+		Impact: model.IncidentImpactMinor,
+	})
+	require.Equal(t, response.CodeCreated, resp.Code)
+	incident := resp.Data.(model.StatusIncident)
+	assert.Equal(t, model.IncidentStatusInvestigating, incident.Status)
+	assert.Nil(t, incident.ResolvedAt)
+
+	page = svc.GetStatus(ctx).Data.(*status.Page)
+	require.Len(t, page.Incidents, 1, "tạo sự cố xóa cache status page")
+
+	c.Advance(time.Hour)
+	resolved := model.IncidentStatusResolved
+	resp = svc.UpdateIncident(ctx, incident.ID.String(), status.UpdateIncidentRequest{Status: &resolved})
+	require.Equal(t, response.CodeUpdated, resp.Code)
+	updated := resp.Data.(*model.StatusIncident)
+	require.NotNil(t, updated.ResolvedAt)
+	assert.True(t, updated.ResolvedAt.Equal(c.Now()))
+
+	// Mở lại sự cố thì xóa thời điểm kết thúc
+	monitoring := model.IncidentStatusMonitoring
+	resp = svc.UpdateIncident(ctx, incident.ID.String(), status.UpdateIncidentRequest{Status: &monitoring})
+	require.Equal(t, response.CodeUpdated, resp.Code)
+	page = svc.GetStatus(ctx).Data.(*status.Page)
+	require.Len(t, page.Incidents, 1)
+	assert.Nil(t, page.Incidents[0].ResolvedAt)
+	assert.Equal(t, model.IncidentStatusMonitoring, page.Incidents[0].Status)
+
+	resp = svc.DeleteIncident(ctx, incident.ID.String())
+	require.Equal(t, response.CodeDeleted, resp.Code)
+	resp = svc.UpdateIncident(ctx, incident.ID.String(), status.UpdateIncidentRequest{Status: &resolved})
+	assert.Equal(t, response.CodeIncidentNotFound, resp.Code)
+}
@@ -0,0 +1,97 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var samplePDF = []byte("%PDF-1.4\n%sample\n")
+
+func newBucketStorageConfig(t *testing.T) (storage.Config, string, string) {
+	defaultRoot, acmeRoot := t.TempDir(), t.TempDir()
+	return storage.Config{
+		Driver:     "local",
+		Local:      storage.LocalConfig{BasePath: defaultRoot, BaseURL: "/storages"},
+		Image:      storage.ImageConfig{Quality: 90},
+		Validation: storage.ValidationConfig{MaxFileSize: 1024 * 1024},
+		Buckets: []storage.BucketConfig{
+			{Name: "acme", Driver: "local", Local: storage.LocalConfig{BasePath: acmeRoot, BaseURL: "/acme"}, Prefix: "tenants/{tenant}/{category}"},
+			{Name: "archive", Driver: "local", Local: storage.LocalConfig{BasePath: defaultRoot, BaseURL: "/storages"}, Prefix: "archive"},
+		},
+		Routes: []string{"tenant:acme=acme", "category:document=archive"},
+	}, defaultRoot, acmeRoot
+}
+
+func TestParseStorageRoute(t *testing.T) {
+	route, err := storage.ParseRoute("tenant:acme+category:image=acme-media")
+	require.NoError(t, err)
+	assert.Equal(t, storage.Route{Tenant: "acme", Category: "image", Bucket: "acme-media"}, route)
+
+	for _, invalid := range []string{"acme", "tenant:acme=", "region:eu=eu", "tenant=acme"} {
+		_, err := storage.ParseRoute(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestStorageManagerRoutesUploadsByTenantAndCategory(t *testing.T) {
+	cfg, defaultRoot, acmeRoot := newBucketStorageConfig(t)
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+
+	options := &storage.UploadOptions{Category: "document", Path: "notes"}
+
+	// Không có tenant: route theo category
+	result, err := manager.UploadBytes(context.Background(), "a.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+	assert.Equal(t, "archive", result.Bucket)
+	assert.Regexp(t, `^archive/notes/a_.+\.pdf$`, result.Path)
+	assert.FileExists(t, filepath.Join(defaultRoot, result.Path))
+
+	// Tenant acme: luật tenant được chọn, prefix thay {tenant}/{category}
+	acmeCtx := storage.WithTenant(context.Background(), "acme")
+	result, err = manager.UploadBytes(acmeCtx, "b.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+	assert.Equal(t, "acme", result.Bucket)
+	assert.Regexp(t, `^tenants/acme/document/notes/b_.+\.pdf$`, result.Path)
+	assert.FileExists(t, filepath.Join(acmeRoot, result.Path))
+
+	// Thao tác theo path trong context tenant dùng bucket của tenant
+	exists, err := manager.FileExists(acmeCtx, result.Path)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = manager.FileExists(context.Background(), result.Path)
+	require.NoError(t, err)
+	assert.False(t, exists, "bucket mặc định không chứa file của tenant")
+
+	// WithBucket chỉ định bucket từ UploadResult
+	require.NoError(t, manager.DeleteFile(storage.WithBucket(context.Background(), result.Bucket), result.Path))
+	_, err = os.Stat(filepath.Join(acmeRoot, result.Path))
+	assert.True(t, os.IsNotExist(err))
+
+	_, err = manager.FileExists(storage.WithBucket(context.Background(), "missing"), result.Path)
+	assert.Error(t, err)
+}
+
+func TestStorageManagerRejectsInvalidBuckets(t *testing.T) {
+	cfg, _, _ := newBucketStorageConfig(t)
+	cfg.Routes = []string{"tenant:beta=beta"}
+	_, err := storage.NewStorageManager(cfg)
+	assert.ErrorContains(t, err, "unknown bucket beta")
+
+	cfg, _, _ = newBucketStorageConfig(t)
+	cfg.Buckets[0].Lifecycle.ExpireDays = 30
+	assert.ErrorContains(t, cfg.Validate(), "only supported by the s3 driver")
+
+	// Local driver không hỗ trợ lifecycle rule
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	assert.True(t, manager.HasLifecycles())
+	assert.Error(t, manager.ApplyLifecycles(context.Background()))
+}
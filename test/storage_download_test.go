@@ -0,0 +1,115 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/config"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var (
+	downloadMember   = uuid.MustParse("11111111-1111-4111-8111-111111111111")
+	downloadOutsider = uuid.MustParse("22222222-2222-4222-8222-222222222222")
+	downloadLeft     = uuid.MustParse("33333333-3333-4333-8333-333333333333")
+)
+
+// newDownloadServer FileServer của local storage với quyền tải file theo upload.Downloads,
+// owner của file lưu trong bảng storage_files
+func newDownloadServer(t *testing.T) (http.Handler, *storage.StorageManager) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	for _, stmt := range []string{
+		`CREATE TABLE users (id TEXT PRIMARY KEY, avatar TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE messages (id TEXT PRIMARY KEY, conversation_id TEXT, file_url TEXT, deleted_at DATETIME)`,
+		`CREATE TABLE conversation_participants (id TEXT PRIMARY KEY, conversation_id TEXT, user_id TEXT, left_at DATETIME, deleted_at DATETIME)`,
+		`CREATE TABLE storage_files (key TEXT PRIMARY KEY, owner_id TEXT NOT NULL, size INTEGER NOT NULL DEFAULT 0, created_at DATETIME)`,
+	} {
+		require.NoError(t, db.Exec(stmt).Error)
+	}
+	require.NoError(t, db.Exec(`INSERT INTO users (id, avatar) VALUES ('u1', 'avatars/me.png')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO messages (id, conversation_id, file_url) VALUES ('m1', 'c1', '/storages/chat/report.pdf')`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO messages (id, conversation_id, file_url, deleted_at) VALUES ('m2', 'c1', 'chat/deleted.pdf', CURRENT_TIMESTAMP)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO conversation_participants (id, conversation_id, user_id, left_at) VALUES ('p1', 'c1', ?, NULL), ('p2', 'c1', ?, CURRENT_TIMESTAMP)`,
+		downloadMember, downloadLeft).Error)
+
+	root := t.TempDir()
+	for _, key := range []string{"avatars/me.png", "chat/report.pdf", "chat/deleted.pdf", "2025/01/notes.txt"} {
+		writeStorageFile(t, root, key, time.Hour)
+	}
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	manager.SetFileOwnerStore(repository.NewStorageFileRepository(db))
+
+	downloads := upload.NewDownloads(manager, repository.NewFileReferenceRepository(db))
+	return storage.NewFileServer(storage.FileServerConfig{Root: root, Prefix: "/storages/", Authorize: downloads.Authorize}), manager
+}
+
+// download tải file với user đã xác thực (uuid.Nil: không đăng nhập)
+func download(server http.Handler, key string, userID uuid.UUID) int {
+	req := httptest.NewRequest(http.MethodGet, "/storages/"+key, nil)
+	if userID != uuid.Nil {
+		req = req.WithContext(context.WithValue(req.Context(), jwt.UserIDContextKey, userID.String()))
+	}
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestStorageDownloadPermissions(t *testing.T) {
+	server, _ := newDownloadServer(t)
+
+	// Avatar public
+	assert.Equal(t, http.StatusOK, download(server, "avatars/me.png", uuid.Nil))
+
+	// File đính kèm: chỉ thành viên còn trong conversation
+	assert.Equal(t, http.StatusUnauthorized, download(server, "chat/report.pdf", uuid.Nil))
+	assert.Equal(t, http.StatusOK, download(server, "chat/report.pdf", downloadMember))
+	assert.Equal(t, http.StatusForbidden, download(server, "chat/report.pdf", downloadOutsider))
+	assert.Equal(t, http.StatusForbidden, download(server, "chat/report.pdf", downloadLeft))
+
+	// Message đã xóa: không ai tải được
+	assert.Equal(t, http.StatusForbidden, download(server, "chat/deleted.pdf", downloadMember))
+
+	// File khác cần đăng nhập, file không xác định được owner thì không ai tải được
+	assert.Equal(t, http.StatusUnauthorized, download(server, "2025/01/notes.txt", uuid.Nil))
+	assert.Equal(t, http.StatusForbidden, download(server, "2025/01/notes.txt", downloadOutsider))
+	assert.Equal(t, http.StatusNotFound, download(server, "2025/01/missing.txt", downloadOutsider))
+}
+
+func TestStorageDownloadOwnerOnLocalStorage(t *testing.T) {
+	server, manager := newDownloadServer(t)
+
+	// User A upload, owner lưu phía server (local storage không có metadata)
+	ctx := storage.WithOwner(context.Background(), downloadMember.String())
+	result, err := manager.UploadBytes(ctx, "report.pdf", []byte("%PDF-1.4\n%private\n"), "application/pdf", &storage.UploadOptions{Category: "document", Path: "docs"})
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, download(server, result.Path, downloadMember))
+	assert.Equal(t, http.StatusForbidden, download(server, result.Path, downloadOutsider))
+	assert.Equal(t, http.StatusUnauthorized, download(server, result.Path, uuid.Nil))
+
+	// Xóa file thì xóa luôn owner
+	require.NoError(t, manager.DeleteFile(ctx, result.Path))
+	owner, err := manager.FileOwner(ctx, result.Path)
+	require.NoError(t, err)
+	assert.Empty(t, owner)
+}
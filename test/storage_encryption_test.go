@@ -0,0 +1,148 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/local"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encryptionKey master key ngẫu nhiên dạng "id:base64"
+func encryptionKey(t *testing.T, id string) string {
+	t.Helper()
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return id + ":" + base64.StdEncoding.EncodeToString(key)
+}
+
+func newEncryptedStorage(t *testing.T, root, keys string) *local.LocalStorage {
+	t.Helper()
+	cipher, err := local.ParseCipher(keys)
+	require.NoError(t, err)
+	s, err := local.NewEncryptedLocalStorage(root, "/storages", cipher)
+	require.NoError(t, err)
+	return s
+}
+
+func TestLocalStorageEncryptionRoundTrip(t *testing.T) {
+	root := t.TempDir()
+	s := newEncryptedStorage(t, root, encryptionKey(t, "k1"))
+	ctx := context.Background()
+	options := &interfaces.UploadOptions{ContentType: "application/octet-stream"}
+
+	const chunk = 64 * 1024
+	for _, size := range []int{0, 1, chunk - 1, chunk, chunk + 1, 3*chunk + 5} {
+		content := bytes.Repeat([]byte("secret-"), size/7+1)[:size]
+		key := filepath.ToSlash(filepath.Join("docs", "file", string(rune('a'+size%26))+".bin"))
+
+		info, err := s.UploadBytes(ctx, key, content, options)
+		require.NoError(t, err)
+		assert.Equal(t, int64(size), info.Size)
+
+		// Trên đĩa không còn nội dung gốc
+		raw, err := os.ReadFile(filepath.Join(root, key))
+		require.NoError(t, err)
+		assert.Greater(t, len(raw), size)
+		if size > 0 {
+			assert.NotContains(t, string(raw), "secret-")
+		}
+
+		downloaded, err := s.DownloadBytes(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, content, downloaded, "size %d", size)
+
+		stat, err := s.GetInfo(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, int64(size), stat.Size, "size %d", size)
+	}
+}
+
+func TestLocalStorageEncryptionKeysAndTampering(t *testing.T) {
+	root := t.TempDir()
+	ctx := context.Background()
+	oldKey, newKey := encryptionKey(t, "2024"), encryptionKey(t, "2025")
+
+	// File chưa mã hóa (lưu trước khi bật) vẫn đọc được
+	require.NoError(t, os.WriteFile(filepath.Join(root, "legacy.txt"), []byte("plain"), 0644))
+
+	_, err := newEncryptedStorage(t, root, oldKey).UploadBytes(ctx, "old.txt", []byte("old secret"), &interfaces.UploadOptions{})
+	require.NoError(t, err)
+
+	// Xoay vòng key: key mới mã hóa file mới, key cũ vẫn giải mã được file cũ
+	rotated := newEncryptedStorage(t, root, newKey+","+oldKey)
+	data, err := rotated.DownloadBytes(ctx, "old.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "old secret", string(data))
+	data, err = rotated.DownloadBytes(ctx, "legacy.txt")
+	require.NoError(t, err)
+	assert.Equal(t, "plain", string(data))
+
+	_, err = newEncryptedStorage(t, root, newKey).DownloadBytes(ctx, "old.txt")
+	assert.ErrorIs(t, err, local.ErrUnknownEncryptionKey)
+
+	// Nội dung bị sửa trên đĩa: không giải mã được
+	path := filepath.Join(root, "old.txt")
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	raw[len(raw)-1] ^= 0xff
+	require.NoError(t, os.WriteFile(path, raw, 0644))
+	_, err = rotated.DownloadBytes(ctx, "old.txt")
+	assert.Error(t, err)
+
+	_, err = local.ParseCipher("k1:" + base64.StdEncoding.EncodeToString([]byte("short")))
+	assert.Error(t, err)
+	cipher, err := local.ParseCipher("")
+	require.NoError(t, err)
+	assert.Nil(t, cipher)
+}
+
+func TestStorageFileServerDecryptsRanges(t *testing.T) {
+	root := t.TempDir()
+	keyFile := filepath.Join(t.TempDir(), "storage-keys")
+	require.NoError(t, os.WriteFile(keyFile, []byte(encryptionKey(t, "k1")+"\n"), 0600))
+
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages", EncryptionKeysFile: keyFile}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+
+	content := make([]byte, 200*1024)
+	for i := range content {
+		content[i] = byte(i % 251)
+	}
+	options := storage.GetDefaultUploadOptions("document")
+	options.Path = "reports"
+	result, err := manager.UploadBytes(context.Background(), "report.pdf", append([]byte("%PDF-1.4\n"), content...), "application/pdf", options)
+	require.NoError(t, err)
+
+	cipher, err := storage.NewLocalCipher(cfg.Local)
+	require.NoError(t, err)
+	server := storage.NewFileServer(storage.FileServerConfig{Root: root, Prefix: "/storages/", Cipher: cipher})
+
+	// Range qua ranh giới chunk 64KB
+	rec := serveStorage(server, http.MethodGet, "/storages/"+result.Path, http.Header{"Range": {"bytes=65530-65545"}})
+	require.Equal(t, http.StatusPartialContent, rec.Code)
+	body, _ := io.ReadAll(rec.Body)
+	assert.Equal(t, append([]byte("%PDF-1.4\n"), content...)[65530:65546], body)
+
+	rec = serveStorage(server, http.MethodGet, "/storages/"+result.Path, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, int64(len(content)+9), int64(rec.Body.Len()))
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+}
@@ -0,0 +1,181 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fingerprintedAvatar = "avatars/me_0b9f6c1e-4a7d-4c1b-9a53-5d2e0f6a7b8c.png"
+
+func newStorageRoot(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	for _, name := range []string{fingerprintedAvatar, "avatars/default.png", ".env"} {
+		full := filepath.Join(root, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte("image-bytes"), 0644))
+	}
+	return root
+}
+
+func serveStorage(handler http.Handler, method, target string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIsFingerprinted(t *testing.T) {
+	assert.True(t, storage.IsFingerprinted(fingerprintedAvatar))
+	assert.True(t, storage.IsFingerprinted("2025/01/file_0b9f6c1e-4a7d-4c1b-9a53-5d2e0f6a7b8c"))
+	assert.False(t, storage.IsFingerprinted("avatars/default.png"))
+	assert.False(t, storage.IsFingerprinted("0b9f6c1e-4a7d-4c1b-9a53-5d2e0f6a7b8c/default.png"))
+}
+
+func TestStorageFileServerCaching(t *testing.T) {
+	server := storage.NewFileServer(storage.FileServerConfig{
+		Root:            newStorageRoot(t),
+		Prefix:          "/storages/",
+		MaxAge:          time.Hour,
+		ImmutableMaxAge: 365 * 24 * time.Hour,
+	})
+
+	rec := serveStorage(server, http.MethodGet, "/storages/"+fingerprintedAvatar, nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image-bytes", rec.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "image/png", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "cross-origin", rec.Header().Get("Cross-Origin-Resource-Policy"))
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	// Revalidate bằng ETag
+	rec = serveStorage(server, http.MethodGet, "/storages/"+fingerprintedAvatar, http.Header{"If-None-Match": {etag}})
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	// File không có fingerprint cache theo MaxAge
+	rec = serveStorage(server, http.MethodHead, "/storages/avatars/default.png", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "public, max-age=3600", rec.Header().Get("Cache-Control"))
+
+	// Range request
+	rec = serveStorage(server, http.MethodGet, "/storages/avatars/default.png", http.Header{"Range": {"bytes=0-4"}})
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "image", rec.Body.String())
+}
+
+func TestStorageFileServerRejects(t *testing.T) {
+	server := storage.NewFileServer(storage.FileServerConfig{Root: newStorageRoot(t), Prefix: "/storages/"})
+
+	for _, target := range []string{
+		"/storages/",
+		"/storages/avatars",          // Không liệt kê thư mục
+		"/storages/.env",             // Dotfiles
+		"/storages/../storages/.env", // Path traversal
+		"/storages/avatars/missing.png",
+	} {
+		rec := serveStorage(server, http.MethodGet, target, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code, target)
+	}
+
+	rec := serveStorage(server, http.MethodPost, "/storages/avatars/default.png", nil)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// MaxAge = 0: luôn revalidate
+	rec = serveStorage(server, http.MethodGet, "/storages/avatars/default.png", nil)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+}
+
+func TestStorageFileServerRedirects(t *testing.T) {
+	cdn := storage.NewFileServer(storage.FileServerConfig{
+		Prefix:          "/storages/",
+		ImmutableMaxAge: 24 * time.Hour,
+		CDNURL:          "https://cdn.example.com/assets/",
+	})
+	rec := serveStorage(cdn, http.MethodGet, "/storages/"+fingerprintedAvatar, nil)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://cdn.example.com/assets/"+fingerprintedAvatar, rec.Header().Get("Location"))
+	assert.Equal(t, "public, max-age=86400, immutable", rec.Header().Get("Cache-Control"))
+
+	signed := storage.NewFileServer(storage.FileServerConfig{
+		Prefix:       "/storages/",
+		SignedURLTTL: 10 * time.Minute,
+		SignURL: func(ctx context.Context, key string, ttl time.Duration) (string, error) {
+			if key == "avatars/broken.png" {
+				return "", errors.New("signer unavailable")
+			}
+			return "https://bucket.s3.amazonaws.com/" + key + "?X-Amz-Expires=" + ttl.String(), nil
+		},
+	})
+	rec = serveStorage(signed, http.MethodGet, "/storages/avatars/default.png", nil)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://bucket.s3.amazonaws.com/avatars/default.png?X-Amz-Expires=10m0s", rec.Header().Get("Location"))
+	assert.Equal(t, "private, max-age=300", rec.Header().Get("Cache-Control"))
+
+	rec = serveStorage(signed, http.MethodGet, "/storages/avatars/broken.png", nil)
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+}
+
+func TestStorageFileServerAuthorize(t *testing.T) {
+	server := storage.NewFileServer(storage.FileServerConfig{
+		Root:   newStorageRoot(t),
+		Prefix: "/storages/",
+		MaxAge: time.Hour,
+		CDNURL: "https://cdn.example.com",
+		Authorize: func(r *http.Request, key string) (storage.FileAccess, error) {
+			switch {
+			case key == "avatars/default.png":
+				return storage.FileAccessPublic, nil
+			case key == "avatars/gone.png":
+				return storage.FileAccessPrivate, fmt.Errorf("%s: %w", key, fs.ErrNotExist)
+			case r.Header.Get("Authorization") == "":
+				return storage.FileAccessPrivate, storage.ErrDownloadUnauthorized
+			case r.Header.Get("Authorization") != "Bearer owner":
+				return storage.FileAccessPrivate, storage.ErrDownloadForbidden
+			}
+			return storage.FileAccessPrivate, nil
+		},
+	})
+
+	// File public vẫn qua CDN
+	rec := serveStorage(server, http.MethodGet, "/storages/avatars/default.png", nil)
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "public, max-age=3600", rec.Header().Get("Cache-Control"))
+
+	rec = serveStorage(server, http.MethodGet, "/storages/"+fingerprintedAvatar, nil)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+
+	rec = serveStorage(server, http.MethodGet, "/storages/"+fingerprintedAvatar, http.Header{"Authorization": {"Bearer other"}})
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = serveStorage(server, http.MethodGet, "/storages/avatars/gone.png", nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	// File private: phục vụ trực tiếp (không qua CDN public), chỉ cache ở client
+	rec = serveStorage(server, http.MethodGet, "/storages/"+fingerprintedAvatar, http.Header{
+		"Authorization": {"Bearer owner"},
+		"Range":         {"bytes=6-10"},
+	})
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "bytes", rec.Body.String())
+	assert.Equal(t, "private, max-age=3600", rec.Header().Get("Cache-Control"))
+	assert.Contains(t, rec.Header().Values("Vary"), "Authorization")
+}
@@ -0,0 +1,156 @@
+package test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/local"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const orphanFingerprint = "_0f8fad5b-d9cb-469f-a165-70867728950e"
+
+// writeStorageFile tạo file trong local storage với thời gian sửa đổi age trước
+func writeStorageFile(t *testing.T, root, key string, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(key))
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(key), 0644))
+	modified := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modified, modified))
+}
+
+// newOrphanStorage storage local và bảng users/messages tham chiếu file (SQLite)
+func newOrphanStorage(t *testing.T) (*storage.StorageManager, repository.FileReferenceRepository, string) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, avatar TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`CREATE TABLE messages (id TEXT PRIMARY KEY, file_url TEXT, deleted_at DATETIME)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO users (id, avatar) VALUES ('u1', ?), ('u2', NULL)`, "avatars/me"+orphanFingerprint+".jpg").Error)
+	// Message đã soft delete vẫn giữ file, lưu URL thay vì key
+	require.NoError(t, db.Exec(`INSERT INTO messages (id, file_url, deleted_at) VALUES ('m1', '/storages/chat/report.pdf', CURRENT_TIMESTAMP)`).Error)
+
+	root := t.TempDir()
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+
+	old := 48 * time.Hour
+	writeStorageFile(t, root, "avatars/me"+orphanFingerprint+".jpg", old)
+	writeStorageFile(t, root, "avatars/me"+orphanFingerprint+".webp", old) // Variant của avatar đang dùng
+	writeStorageFile(t, root, "avatars/old"+orphanFingerprint+".jpg", old)
+	writeStorageFile(t, root, "avatars/old"+orphanFingerprint+".webp", old)
+	writeStorageFile(t, root, "chat/report.pdf", old)
+	writeStorageFile(t, root, "chat/unused.pdf", old)
+	writeStorageFile(t, root, "chat/fresh.pdf", time.Minute) // Trong grace period
+	writeStorageFile(t, root, ".quarantine/2025-01-01/virus.pdf", old)
+	return manager, repository.NewFileReferenceRepository(db), root
+}
+
+func TestLocalStorageListPagination(t *testing.T) {
+	root := t.TempDir()
+	// Walk duyệt a/ trước a.txt dù "a/..." > "a.txt" khi so chuỗi
+	keys := []string{"a/b.txt", "a/c/d.txt", "a.txt", "b.txt", "c/e.txt"}
+	for _, key := range keys {
+		writeStorageFile(t, root, key, 0)
+	}
+	s, err := local.NewLocalStorage(root, "/storages")
+	require.NoError(t, err)
+
+	var listed []string
+	marker := ""
+	for pages := 0; pages < 10; pages++ {
+		page, err := s.List(context.Background(), &interfaces.ListOptions{MaxKeys: 2, Marker: marker})
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Files), 2)
+		for _, file := range page.Files {
+			listed = append(listed, file.Path)
+		}
+		if !page.IsTruncated {
+			break
+		}
+		marker = page.NextMarker
+	}
+	assert.Equal(t, keys, listed)
+
+	// Prefix chưa tồn tại
+	page, err := s.List(context.Background(), &interfaces.ListOptions{Prefix: "missing"})
+	require.NoError(t, err)
+	assert.Empty(t, page.Files)
+}
+
+func TestStorageCleanupOrphansDryRun(t *testing.T) {
+	manager, references, root := newOrphanStorage(t)
+	before := storedFiles(t, root)
+
+	report, err := manager.CleanupOrphans(context.Background(), storage.OrphanOptions{
+		GracePeriod:  24 * time.Hour,
+		DryRun:       true,
+		IsReferenced: references.Referenced,
+		BatchSize:    2,
+	})
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"avatars/old" + orphanFingerprint + ".jpg",
+		"avatars/old" + orphanFingerprint + ".webp",
+		"chat/unused.pdf",
+	}, report.Orphans)
+	assert.Equal(t, 6, report.Scanned)
+	assert.Zero(t, report.Removed)
+	assert.Equal(t, before, storedFiles(t, root))
+}
+
+func TestStorageCleanupOrphansDeleteAndArchive(t *testing.T) {
+	manager, references, root := newOrphanStorage(t)
+	ctx := context.Background()
+
+	// Archive: chuyển vào .orphans, lần quét sau không quét lại
+	report, err := manager.CleanupOrphans(ctx, storage.OrphanOptions{
+		Prefixes:      []string{"chat"},
+		GracePeriod:   24 * time.Hour,
+		ArchivePrefix: ".orphans",
+		IsReferenced:  references.Referenced,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"chat/unused.pdf"}, report.Orphans)
+	assert.Equal(t, 1, report.Removed)
+	assert.FileExists(t, filepath.Join(root, ".orphans", "chat", "unused.pdf"))
+	assert.NoFileExists(t, filepath.Join(root, "chat", "unused.pdf"))
+
+	// Xóa
+	report, err = manager.CleanupOrphans(ctx, storage.OrphanOptions{
+		GracePeriod:  24 * time.Hour,
+		IsReferenced: references.Referenced,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, report.Removed)
+	assert.Zero(t, report.Failed)
+	assert.ElementsMatch(t, []string{
+		".orphans/chat/unused.pdf",
+		".quarantine/2025-01-01/virus.pdf",
+		"avatars/me" + orphanFingerprint + ".jpg",
+		"avatars/me" + orphanFingerprint + ".webp",
+		"chat/fresh.pdf",
+		"chat/report.pdf",
+	}, storedFiles(t, root))
+
+	_, err = manager.CleanupOrphans(ctx, storage.OrphanOptions{})
+	assert.Error(t, err)
+}
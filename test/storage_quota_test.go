@@ -0,0 +1,116 @@
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/config"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/resumable"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newQuotaStorage storage local với quota mặc định, dung lượng lưu trong SQLite (schema như migration 000018)
+func newQuotaStorage(t *testing.T, quota int64) (*storage.StorageManager, *gorm.DB, string) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE storage_usage (owner_id TEXT PRIMARY KEY, used_bytes INTEGER NOT NULL DEFAULT 0,
+		file_count INTEGER NOT NULL DEFAULT 0, quota_bytes INTEGER, created_at DATETIME, updated_at DATETIME)`).Error)
+
+	root := t.TempDir()
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	cfg.QuotaBytes = quota
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	manager.SetUsageStore(repository.NewStorageUsageRepository(db))
+	return manager, db, root
+}
+
+func TestStorageQuotaEnforced(t *testing.T) {
+	size := int64(len(samplePDF))
+	manager, _, root := newQuotaStorage(t, 2*size)
+	ctx := storage.WithOwner(context.Background(), "user-1")
+	options := storage.GetDefaultUploadOptions("document")
+
+	first, err := manager.UploadBytes(ctx, "a.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+	_, err = manager.UploadBytes(ctx, "b.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+
+	// Vượt quota: không lưu file
+	_, err = manager.UploadBytes(ctx, "c.pdf", samplePDF, "application/pdf", options)
+	assert.ErrorIs(t, err, storage.ErrQuotaExceeded)
+	assert.ErrorIs(t, manager.CheckQuota(ctx, 1), storage.ErrQuotaExceeded)
+	assert.Len(t, storedFiles(t, root), 2)
+
+	usage, err := manager.Usage(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, &storage.Usage{Owner: "user-1", UsedBytes: 2 * size, FileCount: 2, QuotaBytes: 2 * size}, usage)
+	assert.Equal(t, int64(0), usage.RemainingBytes())
+
+	// Quota tính riêng từng user, upload không có owner không bị tính
+	_, err = manager.UploadBytes(storage.WithOwner(context.Background(), "user-2"), "a.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+	_, err = manager.UploadBytes(context.Background(), "system.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+
+	// Xóa file trả lại dung lượng
+	require.NoError(t, manager.DeleteFile(ctx, first.Path))
+	usage, err = manager.Usage(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, size, usage.UsedBytes)
+	assert.Equal(t, int64(1), usage.FileCount)
+	_, err = manager.UploadBytes(ctx, "c.pdf", samplePDF, "application/pdf", options)
+	assert.NoError(t, err)
+}
+
+func TestStorageQuotaPerOwnerOverride(t *testing.T) {
+	manager, db, _ := newQuotaStorage(t, 0)
+	ctx := storage.WithOwner(context.Background(), "user-1")
+	options := storage.GetDefaultUploadOptions("document")
+
+	// Quota mặc định 0: không giới hạn nhưng vẫn tính dung lượng
+	_, err := manager.UploadBytes(ctx, "a.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+	usage, err := manager.Usage(ctx, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(-1), usage.RemainingBytes())
+
+	// Quota riêng của user ưu tiên hơn quota mặc định
+	require.NoError(t, db.Exec("UPDATE storage_usage SET quota_bytes = ? WHERE owner_id = ?", len(samplePDF)+10, "user-1").Error)
+	_, err = manager.UploadBytes(ctx, "b.pdf", samplePDF, "application/pdf", options)
+	assert.ErrorIs(t, err, storage.ErrQuotaExceeded)
+
+	// Resumable upload bị từ chối ngay khi tạo
+	validate := resumable.StorageValidate(manager)
+	err = validate(context.Background(), &resumable.Upload{Owner: "user-1", Length: 11, Metadata: map[string]string{"filename": "big.pdf", "filetype": "application/pdf", "category": "document"}})
+	assert.ErrorIs(t, err, storage.ErrQuotaExceeded)
+}
+
+func TestStorageUsageEndpoint(t *testing.T) {
+	size := int64(len(samplePDF))
+	manager, _, _ := newQuotaStorage(t, 10*size)
+	_, err := manager.UploadBytes(storage.WithOwner(context.Background(), "user-1"), "a.pdf", samplePDF, "application/pdf", storage.GetDefaultUploadOptions("document"))
+	require.NoError(t, err)
+
+	svc := upload.NewService(manager, cache.NewMockCache(), upload.Config{})
+	resp := svc.Usage(context.Background(), "user-1")
+	require.Equal(t, response.CodeSuccess, resp.Code)
+	assert.Equal(t, upload.UsageResponse{UsedBytes: size, FileCount: 1, QuotaBytes: 10 * size, RemainingBytes: 9 * size}, resp.Data)
+
+	resp = svc.Usage(context.Background(), "user-2")
+	assert.Equal(t, upload.UsageResponse{QuotaBytes: 10 * size, RemainingBytes: 10 * size}, resp.Data)
+}
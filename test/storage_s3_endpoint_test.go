@@ -0,0 +1,84 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/pkg/storage/aws"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Server giả lập endpoint S3-compatible (MinIO), ghi lại path của request
+func fakeS3Server(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var paths []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+
+		w.Header().Set("ETag", `"abc"`)
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Length", "0")
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), paths...)
+	}
+}
+
+func TestS3StorageCustomEndpointPathStyle(t *testing.T) {
+	server, requests := fakeS3Server(t)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	s3Storage, err := aws.NewS3Storage(aws.S3Config{
+		Bucket:          "uploads",
+		Region:          "us-east-1",
+		AccessKeyID:     "minio",
+		SecretAccessKey: "minio-secret",
+		Endpoint:        host,
+		ForcePathStyle:  true,
+		DisableSSL:      true,
+	})
+	require.NoError(t, err)
+
+	_, err = s3Storage.UploadBytes(context.Background(), "docs/a.pdf", samplePDF, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"PUT /uploads/docs/a.pdf", "HEAD /uploads/docs/a.pdf"}, requests())
+
+	url, err := s3Storage.GetURL(context.Background(), "docs/a.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/uploads/docs/a.pdf", url)
+}
+
+func TestS3StorageCustomEndpointVirtualHostURL(t *testing.T) {
+	s3Storage, err := aws.NewS3Storage(aws.S3Config{
+		Bucket:          "media",
+		Region:          "sgp1",
+		AccessKeyID:     "key",
+		SecretAccessKey: "secret",
+		Endpoint:        "sgp1.digitaloceanspaces.com",
+	})
+	require.NoError(t, err)
+
+	url, err := s3Storage.GetURL(context.Background(), "a.png")
+	require.NoError(t, err)
+	assert.Equal(t, "https://media.sgp1.digitaloceanspaces.com/a.png", url)
+}
+
+func TestS3StorageInvalidEndpoint(t *testing.T) {
+	_, err := aws.NewS3Storage(aws.S3Config{Bucket: "b", Region: "us-east-1", Endpoint: "http://"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,282 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/upload"
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/storage/interfaces"
+	"github.com/anhnq996/go-api-core/pkg/storage/scanner"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eicar chuỗi test chuẩn mà mọi antivirus nhận là malware
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// serveFake chạy server TCP giả lập trên loopback, mỗi kết nối do handle xử lý
+func serveFake(t *testing.T, handle func(conn net.Conn)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handle(conn)
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+// fakeClamd trả FOUND khi nội dung chứa chuỗi EICAR
+func fakeClamd(t *testing.T) string {
+	return serveFake(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		if command, err := r.ReadString(0); err != nil || command != "zINSTREAM\x00" {
+			return
+		}
+		var content bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&content, r, int64(size)); err != nil {
+				return
+			}
+		}
+		if strings.Contains(content.String(), "EICAR-STANDARD-ANTIVIRUS-TEST-FILE") {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+			return
+		}
+		conn.Write([]byte("stream: OK\x00"))
+	})
+}
+
+// fakeICAP RESPMOD: 204 khi sạch, 200 + X-Infection-Found khi chứa chuỗi EICAR
+func fakeICAP(t *testing.T) string {
+	return serveFake(t, func(conn net.Conn) {
+		r := bufio.NewReader(conn)
+		tp := textproto.NewReader(r)
+		if line, err := tp.ReadLine(); err != nil || !strings.HasPrefix(line, "RESPMOD icap://") {
+			return
+		}
+		header, err := tp.ReadMIMEHeader()
+		if err != nil || header.Get("Allow") != "204" {
+			return
+		}
+		// req-hdr và res-hdr được đóng gói, mỗi phần kết thúc bằng dòng trống
+		for blank := 0; blank < 2; {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			if line == "" {
+				blank++
+			}
+		}
+		body, err := io.ReadAll(httputil.NewChunkedReader(r))
+		if err != nil {
+			return
+		}
+		if bytes.Contains(body, []byte("EICAR-STANDARD-ANTIVIRUS-TEST-FILE")) {
+			fmt.Fprint(conn, "ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=EICAR-Test-File;\r\nEncapsulated: res-hdr=0, null-body=19\r\n\r\nHTTP/1.1 403 Forbidden\r\n\r\n")
+			return
+		}
+		fmt.Fprint(conn, "ICAP/1.0 204 No Content\r\nEncapsulated: null-body=0\r\n\r\n")
+	})
+}
+
+func TestClamAVScanner(t *testing.T) {
+	clamav, err := scanner.NewClamAV("tcp://"+fakeClamd(t), time.Second)
+	require.NoError(t, err)
+
+	result, err := clamav.Scan(context.Background(), "doc.pdf", bytes.NewReader(samplePDF))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	// Nội dung lớn hơn một chunk INSTREAM
+	infected := append(bytes.Repeat([]byte("a"), 200*1024), eicar...)
+	result, err = clamav.Scan(context.Background(), "eicar.txt", bytes.NewReader(infected))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+
+	_, err = scanner.NewClamAV("", time.Second)
+	assert.Error(t, err)
+}
+
+func TestICAPScanner(t *testing.T) {
+	icap, err := scanner.NewICAP("icap://"+fakeICAP(t)+"/avscan", time.Second)
+	require.NoError(t, err)
+
+	result, err := icap.Scan(context.Background(), "doc.pdf", bytes.NewReader(samplePDF))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = icap.Scan(context.Background(), "eicar.txt", strings.NewReader(eicar))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "EICAR-Test-File", result.Signature)
+
+	_, err = scanner.NewICAP("http://icap:1344/avscan", time.Second)
+	assert.Error(t, err)
+}
+
+// newScanStorage storage local với scanner ClamAV giả lập
+func newScanStorage(t *testing.T, scan storage.ScanConfig) (*storage.StorageManager, string) {
+	t.Helper()
+	root := t.TempDir()
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	cfg.Scan = scan
+	require.NoError(t, cfg.Validate())
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+	return manager, root
+}
+
+// storedFiles các file trong storage (path tương đối)
+func storedFiles(t *testing.T, root string) []string {
+	t.Helper()
+	var files []string
+	require.NoError(t, filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(root, path)
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return err
+	}))
+	return files
+}
+
+func TestStorageScanReject(t *testing.T) {
+	manager, root := newScanStorage(t, storage.ScanConfig{Driver: "clamav", Address: fakeClamd(t), Policy: "reject", TimeoutSeconds: 5})
+	ctx := context.Background()
+	options := storage.GetDefaultUploadOptions("document")
+
+	result, err := manager.UploadBytes(ctx, "doc.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{result.Path}, storedFiles(t, root))
+
+	// File nhiễm không được lưu
+	infected := append(append([]byte{}, samplePDF...), eicar...)
+	_, err = manager.UploadBytes(ctx, "eicar.pdf", infected, "application/pdf", options)
+	assert.ErrorIs(t, err, storage.ErrFileInfected)
+	assert.Contains(t, err.Error(), "Eicar-Test-Signature")
+	_, err = manager.UploadReader(ctx, "eicar.pdf", bytes.NewReader(infected), int64(len(infected)), "application/pdf", options)
+	assert.ErrorIs(t, err, storage.ErrFileInfected)
+	assert.Equal(t, []string{result.Path}, storedFiles(t, root))
+}
+
+func TestStorageScanQuarantine(t *testing.T) {
+	manager, root := newScanStorage(t, storage.ScanConfig{Driver: "icap", Address: "icap://" + fakeICAP(t) + "/avscan", Policy: "quarantine", QuarantinePrefix: ".quarantine", TimeoutSeconds: 5})
+	options := storage.GetDefaultUploadOptions("document")
+
+	infected := append(append([]byte{}, samplePDF...), eicar...)
+	_, err := manager.UploadReader(context.Background(), "eicar.pdf", bytes.NewReader(infected), int64(len(infected)), "application/pdf", options)
+	assert.ErrorIs(t, err, storage.ErrFileInfected)
+
+	// File nhiễm chỉ nằm trong .quarantine (dotfile, FileServer không phục vụ)
+	files := storedFiles(t, root)
+	require.Len(t, files, 1)
+	assert.Regexp(t, `^\.quarantine/\d{4}-\d{2}-\d{2}/.+_eicar\.pdf$`, files[0])
+	data, err := os.ReadFile(filepath.Join(root, files[0]))
+	require.NoError(t, err)
+	assert.Equal(t, infected, data)
+}
+
+// brokenScanner scanner không kết nối được
+type brokenScanner struct{}
+
+func (brokenScanner) Scan(context.Context, string, io.Reader) (*interfaces.ScanResult, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestStorageScanFailure(t *testing.T) {
+	options := storage.GetDefaultUploadOptions("document")
+
+	// Mặc định fail-closed: không quét được thì từ chối
+	manager, root := newScanStorage(t, storage.ScanConfig{})
+	manager.SetScanner(brokenScanner{})
+	_, err := manager.UploadBytes(context.Background(), "doc.pdf", samplePDF, "application/pdf", options)
+	assert.ErrorIs(t, err, storage.ErrScanFailed)
+	assert.Empty(t, storedFiles(t, root))
+
+	manager, root = newScanStorage(t, storage.ScanConfig{FailOpen: true})
+	manager.SetScanner(brokenScanner{})
+	result, err := manager.UploadBytes(context.Background(), "doc.pdf", samplePDF, "application/pdf", options)
+	require.NoError(t, err)
+	assert.Equal(t, []string{result.Path}, storedFiles(t, root))
+}
+
+func TestValidateStorageScanConfig(t *testing.T) {
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Scan = storage.ScanConfig{Driver: "clamav", Policy: "reject", TimeoutSeconds: 30}
+	assert.Error(t, cfg.Validate(), "missing address")
+	cfg.Scan.Address = "tcp://clamav:3310"
+	assert.NoError(t, cfg.Validate())
+	cfg.Scan.Policy = "delete"
+	assert.Error(t, cfg.Validate())
+	cfg.Scan = storage.ScanConfig{Driver: "virustotal", Address: "x"}
+	assert.Error(t, cfg.Validate())
+}
+
+func TestPresignedUploadRejectsInfectedObject(t *testing.T) {
+	address := fakeClamd(t)
+	svc, s3 := newPresignedUploadService(t, func(cfg *storage.Config) {
+		cfg.Scan = storage.ScanConfig{Driver: "clamav", Address: address, Policy: "reject", TimeoutSeconds: 5}
+	})
+	ctx := context.Background()
+
+	infected := append(append([]byte{}, samplePDF...), eicar...)
+	resp := svc.Create(ctx, "user-1", upload.CreatePresignedUploadRequest{
+		Filename: "report.pdf", ContentType: "application/pdf", Size: int64(len(infected)), Category: "document",
+	})
+	require.Equal(t, response.CodeCreated, resp.Code)
+	presigned := resp.Data.(upload.PresignedUploadResponse)
+
+	req, err := http.NewRequest(presigned.Method, presigned.URL, bytes.NewReader(infected))
+	require.NoError(t, err)
+	for name, value := range presigned.Headers {
+		req.Header.Set(name, value)
+	}
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	res.Body.Close()
+
+	resp = svc.Confirm(ctx, "user-1", presigned.ID)
+	assert.Equal(t, response.CodeFileInfected, resp.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, response.GetHTTPStatusCode(resp.Code))
+	assert.False(t, s3.has(presigned.Path), "object nhiễm malware phải bị xóa")
+}
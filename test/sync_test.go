@@ -0,0 +1,134 @@
+package test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	syncapp "github.com/anhnq996/go-api-core/internal/app/sync"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/clock"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSyncRepository lưu thay đổi trong bộ nhớ, lọc giống syncRepository
+type fakeSyncRepository struct {
+	changes map[string][]repository.SyncChange
+}
+
+func (f *fakeSyncRepository) add(entity string, changedAt time.Time) uuid.UUID {
+	id := uuid.New()
+	f.changes[entity] = append(f.changes[entity], repository.SyncChange{Entity: entity, ID: id, ChangedAt: changedAt})
+	sort.Slice(f.changes[entity], func(i, j int) bool {
+		a, b := f.changes[entity][i], f.changes[entity][j]
+		if a.ChangedAt.Equal(b.ChangedAt) {
+			return a.ID.String() < b.ID.String()
+		}
+		return a.ChangedAt.Before(b.ChangedAt)
+	})
+	return id
+}
+
+func (f *fakeSyncRepository) FindChanges(ctx context.Context, entity string, userID uuid.UUID, after repository.SyncKey, until time.Time, limit int) ([]repository.SyncChange, error) {
+	var result []repository.SyncChange
+	for _, c := range f.changes[entity] {
+		if c.ChangedAt.After(until) {
+			continue
+		}
+		if after.ID == uuid.Nil {
+			if !c.ChangedAt.After(after.ChangedAt) {
+				continue
+			}
+		} else if c.ChangedAt.Before(after.ChangedAt) || (c.ChangedAt.Equal(after.ChangedAt) && c.ID.String() <= after.ID.String()) {
+			continue
+		}
+		result = append(result, c)
+		if len(result) == limit {
+			break
+		}
+	}
+	return result, nil
+}
+
+func pullAll(t *testing.T, svc *syncapp.Service, ctx context.Context, cursor string, limit int) ([]repository.SyncChange, string, int) {
+	var all []repository.SyncChange
+	pages := 0
+	for {
+		resp := svc.Pull(ctx, uuid.New(), cursor, limit)
+		require.Equal(t, response.CodeSuccess, resp.Code)
+		page := resp.Data.(syncapp.PullResponse)
+		all = append(all, page.Changes...)
+		cursor = page.NextCursor
+		pages++
+		if !page.HasMore {
+			return all, cursor, pages
+		}
+	}
+}
+
+func TestSyncPullPaginatesAcrossEntities(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := clock.NewFrozen(now)
+	ctx := clock.WithContext(context.Background(), c)
+
+	repo := &fakeSyncRepository{changes: map[string][]repository.SyncChange{}}
+	repo.add(repository.SyncEntityProfile, now.Add(-time.Hour))
+	for i := 0; i < 3; i++ {
+		repo.add(repository.SyncEntityConversation, now.Add(-time.Duration(30-i)*time.Minute))
+	}
+	// Cùng changed_at: phân trang theo id, không trùng không sót
+	for i := 0; i < 5; i++ {
+		repo.add(repository.SyncEntityMessage, now.Add(-10*time.Minute))
+	}
+	svc := syncapp.NewService(repo)
+
+	changes, cursor, pages := pullAll(t, svc, ctx, "", 2)
+	require.Len(t, changes, 9)
+	assert.Equal(t, 5, pages)
+	assert.Equal(t, repository.SyncEntityProfile, changes[0].Entity)
+	assert.Equal(t, repository.SyncEntityMessage, changes[8].Entity)
+
+	seen := map[uuid.UUID]bool{}
+	for _, ch := range changes {
+		assert.False(t, seen[ch.ID], "duplicate change %s", ch.ID)
+		seen[ch.ID] = true
+	}
+
+	// Thay đổi mới sau lần sync: chỉ nhận phần chênh lệch
+	c.Advance(time.Minute)
+	newID := repo.add(repository.SyncEntityMessage, c.Now().Add(-30*time.Second))
+	changes, _, _ = pullAll(t, svc, ctx, cursor, 2)
+	require.Len(t, changes, 1)
+	assert.Equal(t, newID, changes[0].ID)
+}
+
+func TestSyncPullSettleDelay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c := clock.NewFrozen(now)
+	ctx := clock.WithContext(context.Background(), c)
+
+	repo := &fakeSyncRepository{changes: map[string][]repository.SyncChange{}}
+	// Bản ghi quá mới (có thể transaction khác chưa commit xong) để lần sync sau
+	recent := repo.add(repository.SyncEntityFriendship, now.Add(-time.Second))
+	svc := syncapp.NewService(repo)
+
+	changes, cursor, _ := pullAll(t, svc, ctx, "", 10)
+	assert.Empty(t, changes)
+
+	c.Advance(5 * time.Second)
+	changes, _, _ = pullAll(t, svc, ctx, cursor, 10)
+	require.Len(t, changes, 1)
+	assert.Equal(t, recent, changes[0].ID)
+}
+
+func TestSyncPullInvalidCursor(t *testing.T) {
+	svc := syncapp.NewService(&fakeSyncRepository{changes: map[string][]repository.SyncChange{}})
+
+	resp := svc.Pull(context.Background(), uuid.New(), "not-a-cursor!", 10)
+	assert.Equal(t, response.CodeSyncCursorInvalid, resp.Code)
+}
@@ -0,0 +1,165 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/telemetry"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSink ghi lại report đã gửi, err != nil thì Send trả lỗi
+type recordingSink struct {
+	mu      sync.Mutex
+	reports []telemetry.Report
+	err     error
+}
+
+func (s *recordingSink) Send(ctx context.Context, report telemetry.Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reports = append(s.reports, report)
+	return s.err
+}
+
+func (s *recordingSink) sent() []telemetry.Report {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]telemetry.Report(nil), s.reports...)
+}
+
+func TestTelemetryOptOutDropsEvents(t *testing.T) {
+	previous := telemetry.Global
+	t.Cleanup(func() { telemetry.Init(previous) })
+
+	// Opt-out: Global nil, Track không panic và không ghi nhận gì
+	telemetry.Init(nil)
+	assert.NotPanics(t, func() { telemetry.Track("excel.export") })
+
+	sink := &recordingSink{}
+	collector := telemetry.NewCollector(telemetry.Config{Environment: "test"}, sink)
+	require.NoError(t, collector.Flush(context.Background()))
+	assert.Empty(t, sink.sent())
+
+	// Bật lại: chỉ event sau khi Init được đếm
+	telemetry.Init(collector)
+	telemetry.Track("excel.export")
+	telemetry.Track("excel.export")
+	telemetry.Init(nil)
+	telemetry.Track("excel.export")
+
+	require.NoError(t, collector.Flush(context.Background()))
+	reports := sink.sent()
+	require.Len(t, reports, 1)
+	assert.Equal(t, map[string]int64{"excel.export": 2}, reports[0].Features)
+}
+
+func TestTelemetryFlushSnapshotsAndResets(t *testing.T) {
+	sink := &recordingSink{}
+	collector := telemetry.NewCollector(telemetry.Config{
+		Environment: "test",
+		Version:     "1.2.3",
+		Flags:       map[string]bool{"storage_s3": true},
+	}, sink)
+
+	collector.Track("chat.message")
+	collector.Track("chat.message")
+	collector.TrackEndpoint("GET /api/v1/users/{id}")
+	require.NoError(t, collector.Flush(context.Background()))
+
+	// Counters đã reset: không có gì để gửi
+	require.NoError(t, collector.Flush(context.Background()))
+	require.Len(t, sink.sent(), 1)
+
+	collector.Track("fcm.send")
+	collector.SetFlag("fcm", true)
+	require.NoError(t, collector.Flush(context.Background()))
+
+	reports := sink.sent()
+	require.Len(t, reports, 2)
+	first, second := reports[0], reports[1]
+	assert.Equal(t, map[string]int64{"chat.message": 2}, first.Features)
+	assert.Equal(t, map[string]int64{"GET /api/v1/users/{id}": 1}, first.Endpoints)
+	assert.Equal(t, map[string]bool{"storage_s3": true}, first.Flags)
+	assert.Equal(t, "1.2.3", first.Version)
+
+	assert.Equal(t, map[string]int64{"fcm.send": 1}, second.Features)
+	assert.Empty(t, second.Endpoints)
+	assert.Equal(t, map[string]bool{"storage_s3": true, "fcm": true}, second.Flags)
+
+	// Chu kỳ sau bắt đầu khi chu kỳ trước kết thúc, instance ID ổn định
+	assert.False(t, second.PeriodStart.Before(first.PeriodEnd))
+	assert.False(t, second.PeriodEnd.Before(second.PeriodStart))
+	assert.Equal(t, first.InstanceID, second.InstanceID)
+
+	// Lỗi của sink được trả về cho caller
+	sink.err = errors.New("loki unavailable")
+	collector.Track("chat.message")
+	assert.Error(t, collector.Flush(context.Background()))
+}
+
+func TestTelemetryStartFlushesOnStop(t *testing.T) {
+	sink := &recordingSink{err: errors.New("loki unavailable")}
+	collector := telemetry.NewCollector(telemetry.Config{FlushInterval: 20 * time.Millisecond}, sink)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	collector.Start(ctx)
+	collector.Track("excel.export")
+
+	// Flush lỗi không dừng vòng lặp, chu kỳ sau vẫn được gửi
+	require.Eventually(t, func() bool { return len(sink.sent()) == 1 }, time.Second, 5*time.Millisecond)
+	collector.Track("excel.import")
+	cancel()
+	require.Eventually(t, func() bool { return len(sink.sent()) == 2 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, map[string]int64{"excel.import": 1}, sink.sent()[1].Features)
+}
+
+func TestTelemetryMiddlewareRecordsRoutePattern(t *testing.T) {
+	sink := &recordingSink{}
+	collector := telemetry.NewCollector(telemetry.Config{}, sink)
+
+	r := chi.NewRouter()
+	r.Use(telemetry.Middleware(collector))
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Get("/users/{id}", ok)
+		r.Delete("/users/{id}", ok)
+		r.Get("/users/{id}/files/*", ok)
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/api/v1/users/0191b8e4-7d2a-7c3e-9f10-3a5b6c7d8e9f?email=alice@example.com", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v1/users/98765", nil),
+		httptest.NewRequest(http.MethodDelete, "/api/v1/users/98765", nil),
+		httptest.NewRequest(http.MethodGet, "/api/v1/users/98765/files/private/report.pdf", nil),
+		httptest.NewRequest(http.MethodGet, "/secret-admin-path/123", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	require.NoError(t, collector.Flush(context.Background()))
+	reports := sink.sent()
+	require.Len(t, reports, 1)
+	assert.Equal(t, map[string]int64{
+		"GET /api/v1/users/{id}":         2,
+		"DELETE /api/v1/users/{id}":      1,
+		"GET /api/v1/users/{id}/files/*": 1,
+		"GET unmatched":                  1,
+	}, reports[0].Endpoints)
+
+	// Report không chứa ID, email hay path thực tế
+	raw, err := json.Marshal(reports[0])
+	require.NoError(t, err)
+	for _, leaked := range []string{"0191b8e4", "98765", "alice", "report.pdf", "secret-admin-path"} {
+		assert.NotContains(t, string(raw), leaked)
+	}
+}
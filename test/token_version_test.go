@@ -0,0 +1,145 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/response"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTokenVersionDB token_version của user trong database
+type fakeTokenVersionDB struct {
+	mu       sync.Mutex
+	versions map[string]int
+	loads    int
+	err      error
+}
+
+func (db *fakeTokenVersionDB) load(ctx context.Context, userID string) (int, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+	db.loads++
+	if db.err != nil {
+		return 0, db.err
+	}
+	version, ok := db.versions[userID]
+	if !ok {
+		return 0, jwt.ErrUserNotFound
+	}
+	return version, nil
+}
+
+// bump giống IncrementTokenVersion + TokenVersions.Set trong auth service
+func (db *fakeTokenVersionDB) bump(t *testing.T, versions *jwt.TokenVersions, userID string) int {
+	t.Helper()
+	db.mu.Lock()
+	db.versions[userID]++
+	version := db.versions[userID]
+	db.mu.Unlock()
+	require.NoError(t, versions.Set(context.Background(), userID, version))
+	return version
+}
+
+func TestTokenVersionsIsStale(t *testing.T) {
+	db := &fakeTokenVersionDB{versions: map[string]int{"user-1": 0, "admin-1": 2}}
+	versions := jwt.NewTokenVersions(cache.NewMockCache(), db.load, time.Minute)
+	ctx := context.Background()
+
+	stale, err := versions.IsStale(ctx, &jwt.Claims{UserID: "user-1"})
+	require.NoError(t, err)
+	assert.False(t, stale)
+
+	// Version được cache, không đọc lại database
+	_, err = versions.IsStale(ctx, &jwt.Claims{UserID: "user-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, db.loads)
+
+	// Logout all: token cũ bị thu hồi, token cấp sau đó vẫn hợp lệ
+	db.bump(t, versions, "user-1")
+	stale, err = versions.IsStale(ctx, &jwt.Claims{UserID: "user-1", TokenVersion: 0})
+	require.NoError(t, err)
+	assert.True(t, stale)
+	stale, err = versions.IsStale(ctx, &jwt.Claims{UserID: "user-1", TokenVersion: 1})
+	require.NoError(t, err)
+	assert.False(t, stale)
+
+	// Impersonation token bị thu hồi khi version của admin tăng
+	impersonated := &jwt.Claims{UserID: "user-1", TokenVersion: 1, Impersonator: "admin-1", ImpersonatorTokenVersion: 2}
+	stale, err = versions.IsStale(ctx, impersonated)
+	require.NoError(t, err)
+	assert.False(t, stale)
+	db.bump(t, versions, "admin-1")
+	stale, err = versions.IsStale(ctx, impersonated)
+	require.NoError(t, err)
+	assert.True(t, stale)
+
+	// User đã bị xóa
+	stale, err = versions.IsStale(ctx, &jwt.Claims{UserID: "deleted-user"})
+	require.NoError(t, err)
+	assert.True(t, stale)
+
+	// Lỗi database không bị coi là token hợp lệ
+	db.err = errors.New("connection refused")
+	_, err = versions.IsStale(ctx, &jwt.Claims{UserID: "user-2"})
+	assert.Error(t, err)
+}
+
+func TestTokenVersionMiddleware(t *testing.T) {
+	manager := jwt.NewManager(jwt.Config{SecretKey: "test-secret"})
+	db := &fakeTokenVersionDB{versions: map[string]int{"user-1": 0}}
+	versions := jwt.NewTokenVersions(cache.NewMockCache(), db.load, time.Minute)
+	blacklist := jwt.NewBlacklist(cache.NewMockCache())
+	blacklist.SetTokenVersions(versions)
+
+	serve := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/me", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		manager.MiddlewareWithBlacklist(blacklist)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})).ServeHTTP(rec, req)
+		return rec
+	}
+
+	oldPair, err := manager.GenerateVersionedTokenPair("user-1", "user@example.com", "user", 0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serve(oldPair.AccessToken).Code)
+
+	version := db.bump(t, versions, "user-1")
+
+	rec := serve(oldPair.AccessToken)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), response.CodeTokenRevoked)
+
+	// Đăng nhập lại sau logout all vẫn được (AddUserTokens chặn cả token mới)
+	newPair, err := manager.GenerateVersionedTokenPair("user-1", "user@example.com", "user", version, nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, serve(newPair.AccessToken).Code)
+
+	// Refresh token mang token_version để service so sánh với version trong database
+	refreshClaims, err := manager.VerifyRefreshTokenClaims(oldPair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", refreshClaims.Subject)
+	assert.Equal(t, 0, refreshClaims.TokenVersion)
+	refreshClaims, err = manager.VerifyRefreshTokenClaims(newPair.RefreshToken)
+	require.NoError(t, err)
+	assert.Equal(t, version, refreshClaims.TokenVersion)
+
+	// Không đọc được version: 503 thay vì cho qua
+	db.err = errors.New("connection refused")
+	require.NoError(t, versions.Set(context.Background(), "user-1", version))
+	assert.Equal(t, http.StatusOK, serve(newPair.AccessToken).Code)
+	cacheless := jwt.NewTokenVersions(nil, db.load, time.Minute)
+	blacklist.SetTokenVersions(cacheless)
+	assert.Equal(t, http.StatusServiceUnavailable, serve(newPair.AccessToken).Code)
+}
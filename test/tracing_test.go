@@ -0,0 +1,161 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/outbox"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+	"github.com/anhnq996/go-api-core/pkg/tracing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+// setupTracing ghi span vào bộ nhớ, khôi phục provider toàn cục sau test
+func setupTracing(t *testing.T) *tracetest.InMemoryExporter {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return exporter
+}
+
+func findSpan(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	exporter := setupTracing(t)
+
+	r := chi.NewRouter()
+	r.Use(tracing.Middleware)
+	r.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "users.load")
+		span.End()
+		w.WriteHeader(http.StatusOK)
+	})
+	r.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	spans := exporter.GetSpans()
+	server := findSpan(spans, "GET /users/{id}")
+	require.NotNil(t, server, "span đặt tên theo route pattern")
+	assert.Equal(t, server.SpanContext.TraceID().String(), rec.Header().Get(tracing.HeaderTraceID))
+	child := findSpan(spans, "users.load")
+	require.NotNil(t, child)
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+
+	// Tiếp tục trace của bên gọi (traceparent)
+	exporter.Reset()
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	server = findSpan(exporter.GetSpans(), "GET /fail")
+	require.NotNil(t, server)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.Equal(t, codes.Error, server.Status.Code, "5xx đánh dấu lỗi")
+}
+
+func TestTracingGORM(t *testing.T) {
+	exporter := setupTracing(t)
+	db := setupOutboxDB(t)
+	require.NoError(t, tracing.InstrumentGORM(db))
+
+	ctx, parent := tracing.Start(context.Background(), "request")
+	var count int64
+	require.NoError(t, db.WithContext(ctx).Table("outbox_orders").Where("status = ?", "paid").Count(&count).Error)
+	var row struct{ ID string }
+	assert.ErrorIs(t, db.WithContext(ctx).Table("outbox_orders").First(&row).Error, gorm.ErrRecordNotFound)
+	parent.End()
+
+	var querySpans []tracetest.SpanStub
+	for _, span := range exporter.GetSpans() {
+		if span.Name == "gorm.query" {
+			querySpans = append(querySpans, span)
+		}
+	}
+	require.Len(t, querySpans, 2)
+	for _, span := range querySpans {
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+		assert.NotEqual(t, codes.Error, span.Status.Code, "không tìm thấy bản ghi không phải lỗi")
+	}
+	var statement string
+	for _, attr := range querySpans[0].Attributes {
+		if attr.Key == "db.query.text" {
+			statement = attr.Value.AsString()
+		}
+	}
+	assert.Contains(t, statement, "outbox_orders")
+}
+
+func TestTracingPropagatesThroughOutbox(t *testing.T) {
+	exporter := setupTracing(t)
+	db := setupOutboxDB(t)
+	manager := &listQueueManager{queues: map[string]*listQueue{}}
+	publisher := outbox.NewPublisher(db, manager, outbox.DefaultOptions())
+
+	ctx, request := tracing.Start(context.Background(), "request")
+	message, err := queue.NewJobMessage("report.build", map[string]string{"id": "1"})
+	require.NoError(t, err)
+	require.NoError(t, db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return outbox.Enqueue(tx, "reports", message)
+	}))
+	request.End()
+	assert.Empty(t, message.Headers["traceparent"], "message của caller không bị sửa")
+
+	published, err := publisher.PublishPending(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+
+	q, _ := manager.GetQueue("reports")
+	handled := make(chan string, 1)
+	dispatcher := queue.NewDispatcher()
+	dispatcher.RegisterHandler("report.build", func(ctx context.Context, message *queue.Message) error {
+		handled <- tracing.TraceID(ctx)
+		return nil
+	})
+	consumer := queue.NewConsumer(q, dispatcher, &queue.ConsumerOptions{Concurrency: 1})
+	require.NoError(t, consumer.Start(context.Background()))
+	defer consumer.Stop()
+
+	select {
+	case traceID := <-handled:
+		assert.Equal(t, request.SpanContext().TraceID().String(), traceID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("message was not consumed")
+	}
+	assert.Eventually(t, func() bool {
+		span := findSpan(exporter.GetSpans(), "queue.process reports report.build")
+		return span != nil && span.Parent.SpanID() == request.SpanContext().SpanID()
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportReportV1 struct {
+	UserID string `json:"user_id"`
+}
+
+type exportReportV2 struct {
+	UserID string `json:"user_id"`
+	Format string `json:"format"`
+}
+
+func exportReportJob() *queue.JobType[exportReportV2] {
+	return queue.DefineJob[exportReportV2]("report.export", 2).
+		Decoder(1, func(data []byte) (exportReportV2, error) {
+			var v1 exportReportV1
+			err := json.Unmarshal(data, &v1)
+			return exportReportV2{UserID: v1.UserID, Format: "csv"}, err
+		})
+}
+
+func TestTypedJobDecodesCurrentAndOldVersions(t *testing.T) {
+	job := exportReportJob()
+
+	current, err := job.NewMessage(exportReportV2{UserID: "u1", Format: "xlsx"})
+	require.NoError(t, err)
+	assert.Equal(t, "report.export", current.Headers[queue.HeaderJobType])
+	assert.Equal(t, "2", current.Headers[queue.HeaderJobVersion])
+
+	decoded, err := job.Decode(current)
+	require.NoError(t, err)
+	assert.Equal(t, 2, decoded.Version)
+	assert.Equal(t, exportReportV2{UserID: "u1", Format: "xlsx"}, decoded.Payload)
+
+	// Message đẩy trước khi có version (không có header) là v1
+	old, err := queue.NewJobMessage("report.export", exportReportV1{UserID: "u2"})
+	require.NoError(t, err)
+	decoded, err = job.Decode(old)
+	require.NoError(t, err)
+	assert.Equal(t, 1, decoded.Version)
+	assert.Equal(t, exportReportV2{UserID: "u2", Format: "csv"}, decoded.Payload)
+}
+
+func TestTypedJobRejectsUnsupportedVersions(t *testing.T) {
+	job := queue.DefineJob[exportReportV2]("report.export", 3)
+	message, err := job.NewMessage(exportReportV2{UserID: "u1"})
+	require.NoError(t, err)
+
+	// Version cũ không có decoder: không retry
+	message.Headers[queue.HeaderJobVersion] = "1"
+	_, err = job.Decode(message)
+	assert.ErrorIs(t, err, queue.ErrUnsupportedJobVersion)
+	assert.True(t, queue.IsPermanent(err))
+
+	// Version mới hơn worker: retry để worker bản mới xử lý
+	message.Headers[queue.HeaderJobVersion] = "4"
+	_, err = job.Decode(message)
+	assert.ErrorIs(t, err, queue.ErrUnsupportedJobVersion)
+	assert.False(t, queue.IsPermanent(err))
+
+	// Payload hỏng
+	message.Headers[queue.HeaderJobVersion] = "3"
+	message.Data = []byte(`{"user_id":`)
+	_, err = job.Decode(message)
+	assert.True(t, queue.IsPermanent(err))
+
+	assert.Panics(t, func() { job.Decoder(3, func([]byte) (exportReportV2, error) { return exportReportV2{}, nil }) })
+}
+
+func TestWorkerManagerRegisterJob(t *testing.T) {
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, RetryDelay: time.Millisecond})
+
+	job := exportReportJob()
+	received := make(chan exportReportV2, 1)
+	workers.RegisterJob(manager, job, func(ctx context.Context, job *queue.TypedJob[exportReportV2]) error {
+		received <- job.Payload
+		return nil
+	}, workers.OnQueue("reports"))
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	message, err := job.NewMessage(exportReportV2{UserID: "u1", Format: "pdf"})
+	require.NoError(t, err)
+	require.NoError(t, queues.queues["reports"].Push(ctx, message))
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, exportReportV2{UserID: "u1", Format: "pdf"}, payload)
+	case <-time.After(2 * time.Second):
+		t.Fatal("job was not handled")
+	}
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uniqueQueue listQueue bỏ message trùng UniqueKey như RedisQueue (SETNX)
+type uniqueQueue struct {
+	*listQueue
+	mu   sync.Mutex
+	keys map[string]bool
+}
+
+func (q *uniqueQueue) Push(ctx context.Context, m *queue.Message) error {
+	if m.UniqueKey != "" {
+		q.mu.Lock()
+		seen := q.keys[m.UniqueKey]
+		q.keys[m.UniqueKey] = true
+		q.mu.Unlock()
+		if seen {
+			return fmt.Errorf("%w: %s", queue.ErrDuplicateMessage, m.UniqueKey)
+		}
+	}
+	return q.listQueue.Push(ctx, m)
+}
+
+type uniqueQueueManager struct {
+	*listQueueManager
+	mu     sync.Mutex
+	queues map[string]*uniqueQueue
+}
+
+func (m *uniqueQueueManager) GetQueue(name string) (queue.Queue, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if q, ok := m.queues[name]; ok {
+		return q, nil
+	}
+	list, _ := m.listQueueManager.GetQueue(name)
+	q := &uniqueQueue{listQueue: list.(*listQueue), keys: map[string]bool{}}
+	m.queues[name] = q
+	return q, nil
+}
+
+func (m *uniqueQueueManager) CreateQueue(ctx context.Context, name string, options *queue.QueueOptions) (queue.Queue, error) {
+	return m.GetQueue(name)
+}
+
+func TestUniqueMessageDropsDuplicates(t *testing.T) {
+	ctx := context.Background()
+	queues := &uniqueQueueManager{listQueueManager: &listQueueManager{queues: map[string]*listQueue{}}, queues: map[string]*uniqueQueue{}}
+	reports, _ := queues.GetQueue("reports")
+
+	first, err := queue.NewJobMessage("report.export", "u1")
+	require.NoError(t, err)
+	require.NoError(t, reports.Push(ctx, first.Unique("report.export:u1", time.Minute)))
+	assert.Equal(t, "report.export:u1", first.UniqueKey)
+	assert.Equal(t, time.Minute, first.UniqueFor)
+
+	second, err := queue.NewJobMessage("report.export", "u1")
+	require.NoError(t, err)
+	err = reports.Push(ctx, second.Unique("report.export:u1", time.Minute))
+	assert.ErrorIs(t, err, queue.ErrDuplicateMessage)
+	size, _ := reports.Size(ctx)
+	assert.Equal(t, int64(1), size)
+}
+
+func TestUniqueMessageRetryAndRequeueAreNotDuplicates(t *testing.T) {
+	ctx := context.Background()
+	queues := &uniqueQueueManager{listQueueManager: &listQueueManager{queues: map[string]*listQueue{}}, queues: map[string]*uniqueQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{
+		Concurrency:  1,
+		MaxRetries:   1,
+		RetryDelay:   time.Millisecond,
+		DelayedRetry: true,
+	})
+
+	var mu sync.Mutex
+	attempts := 0
+	manager.RegisterHandler("report.export", func(ctx context.Context, message *queue.Message) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		return errors.New("storage unavailable")
+	}, workers.OnQueue("reports"))
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	message, err := queue.NewJobMessage("report.export", "u1")
+	require.NoError(t, err)
+	reports, _ := queues.GetQueue("reports")
+	require.NoError(t, reports.Push(ctx, message.Unique("report.export:u1", time.Minute)))
+
+	// Retry (delayed) được đẩy lại queue, không bị bỏ vì trùng key với chính nó
+	dead := queue.NewDeadLetterManager(queues)
+	require.Eventually(t, func() bool {
+		count, _ := dead.Count(ctx, "reports")
+		return count == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 2, attempts)
+	mu.Unlock()
+
+	messages, err := dead.List(ctx, "reports", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Empty(t, messages[0].UniqueKey)
+
+	// Requeue từ dead queue là thao tác chủ động, không bị coi là trùng
+	moved, err := dead.Requeue(ctx, "reports")
+	require.NoError(t, err)
+	assert.Equal(t, 1, moved)
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return attempts == 4
+	}, 2*time.Second, 10*time.Millisecond)
+}
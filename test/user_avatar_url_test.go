@@ -0,0 +1,139 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/internal/app/user"
+	"github.com/anhnq996/go-api-core/internal/config"
+	model "github.com/anhnq996/go-api-core/internal/models"
+	repository "github.com/anhnq996/go-api-core/internal/repositories"
+	"github.com/anhnq996/go-api-core/pkg/cache"
+	"github.com/anhnq996/go-api-core/pkg/response"
+	"github.com/anhnq996/go-api-core/pkg/safehttp"
+	"github.com/anhnq996/go-api-core/pkg/storage"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func samplePNG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 640, 480))
+	for x := 0; x < 640; x++ {
+		img.Set(x, x%480, color.RGBA{R: 200, A: 255})
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func newAvatarURLFixture(t *testing.T) (*user.Service, *gorm.DB, string, *httptest.Server) {
+	t.Helper()
+	avatar := samplePNG(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/avatar.png":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(avatar)
+		case "/cdn/avatar": // CDN không gửi đúng Content-Type
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write(avatar)
+		case "/fake.png": // Khai báo ảnh nhưng nội dung là PDF
+			w.Header().Set("Content-Type", "image/png")
+			w.Write(samplePDF)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, model.RegisterIDGenerator(db, model.IDConfig{DefaultVersion: utils.UUIDv7}))
+	require.NoError(t, db.Exec(`CREATE TABLE users (id TEXT PRIMARY KEY, name TEXT NOT NULL, email TEXT NOT NULL UNIQUE, password TEXT,
+		avatar TEXT, role_id TEXT, email_verified_at DATETIME, is_active BOOLEAN DEFAULT true, last_login_at DATETIME,
+		token_version INTEGER NOT NULL DEFAULT 0, created_at DATETIME, updated_at DATETIME, deleted_at DATETIME)`).Error)
+
+	root := t.TempDir()
+	cfg := config.GetDefaultStorageConfig()
+	cfg.Driver = "local"
+	cfg.Local = storage.LocalConfig{BasePath: root, BaseURL: "/storages"}
+	cfg.Buckets, cfg.Routes, cfg.Prefix = nil, nil, ""
+	manager, err := storage.NewStorageManager(cfg)
+	require.NoError(t, err)
+
+	svc := user.NewService(repository.NewUserRepository(db), nil, nil, cache.NewMockCache(), manager, nil, newLoopbackFetcher(t, server, safehttp.Config{}))
+	return svc, db, root, server
+}
+
+func storedAvatar(t *testing.T, db *gorm.DB, email string) string {
+	t.Helper()
+	var u model.User
+	require.NoError(t, db.Where("email = ?", email).First(&u).Error)
+	require.NotNil(t, u.Avatar)
+	return *u.Avatar
+}
+
+func TestUserCreateWithAvatarURL(t *testing.T) {
+	svc, db, root, server := newAvatarURLFixture(t)
+	ctx := context.Background()
+
+	for i, path := range []string{"/avatar.png", "/cdn/avatar"} {
+		avatarURL := server.URL + path
+		email := []string{"a@example.com", "b@example.com"}[i]
+
+		resp := svc.Create(ctx, model.User{Name: "Avatar", Email: email, Avatar: &avatarURL}, nil)
+		require.Equal(t, response.CodeCreated, resp.Code, path)
+
+		stored := storedAvatar(t, db, email)
+		assert.Regexp(t, `^avatars/avatar_.+\.png$`, stored)
+		data, err := os.ReadFile(filepath.Join(root, stored))
+		require.NoError(t, err)
+		img, _, err := image.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.LessOrEqual(t, img.Width, 300, "avatar được resize như upload thường")
+	}
+}
+
+func TestUserAvatarURLRejected(t *testing.T) {
+	svc, db, _, server := newAvatarURLFixture(t)
+	ctx := context.Background()
+
+	cases := map[string]string{
+		server.URL + "/fake.png":                   response.CodeInvalidFileType,
+		server.URL + "/missing.png":                response.CodeURLFetchFailed,
+		"http://169.254.169.254/latest/meta-data/": response.CodeURLNotAllowed,
+	}
+	for avatarURL, code := range cases {
+		avatarURL := avatarURL
+		resp := svc.Create(ctx, model.User{Name: "Avatar", Email: "c@example.com", Avatar: &avatarURL}, nil)
+		assert.Equal(t, code, resp.Code, avatarURL)
+	}
+	var count int64
+	db.Model(&model.User{}).Count(&count)
+	assert.Zero(t, count, "avatar lỗi thì không tạo user")
+
+	// Update: avatar mới thay avatar cũ
+	first := server.URL + "/avatar.png"
+	require.Equal(t, response.CodeCreated, svc.Create(ctx, model.User{Name: "Avatar", Email: "d@example.com", Avatar: &first}, nil).Code)
+	old := storedAvatar(t, db, "d@example.com")
+
+	var u model.User
+	require.NoError(t, db.Where("email = ?", "d@example.com").First(&u).Error)
+	second := server.URL + "/cdn/avatar"
+	require.Equal(t, response.CodeUpdated, svc.Update(ctx, u.ID.String(), model.User{Avatar: &second}, nil).Code)
+	assert.NotEqual(t, old, storedAvatar(t, db, "d@example.com"))
+}
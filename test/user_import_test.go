@@ -0,0 +1,60 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anhnq996/go-api-core/database/importers"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirebaseScryptPassword(t *testing.T) {
+	// Test vector từ github.com/firebase/scrypt
+	hash := utils.EncodeFirebaseScryptHash(
+		"lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ==",
+		"42xEC+ixf3L2lw==",
+		"jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA==",
+		"Bw==",
+		8, 14,
+	)
+
+	assert.True(t, utils.CheckPassword("user1password", hash), "Firebase password should match")
+	assert.False(t, utils.CheckPassword("wrongpassword", hash), "Wrong password should not match")
+	assert.True(t, utils.NeedsRehash(hash), "Firebase hash should be rehashed after login")
+}
+
+func TestFirebaseSourceParseJSON(t *testing.T) {
+	data := `{"users":[{"localId":"uid1","email":"A@Example.com","emailVerified":true,"passwordHash":"aGFzaA==","salt":"c2FsdA==","displayName":"Alice","createdAt":"1600000000000"}]}`
+
+	records, rowErrors, err := importers.NewFirebaseSource("json").Parse(strings.NewReader(data))
+	require.NoError(t, err)
+	assert.Empty(t, rowErrors)
+	require.Len(t, records, 1)
+	assert.Equal(t, "uid1", records[0].ExternalID)
+	assert.Equal(t, "Alice", records[0].Name)
+	assert.True(t, records[0].EmailVerified)
+	require.NotNil(t, records[0].Password)
+	assert.Equal(t, importers.AlgorithmFirebaseScrypt, records[0].Password.Algorithm)
+	require.NotNil(t, records[0].CreatedAt)
+	assert.Equal(t, int64(1600000000000), records[0].CreatedAt.UnixMilli())
+}
+
+func TestAuth0SourceParseNDJSON(t *testing.T) {
+	data := `{"user_id":"auth0|1","email":"bob@example.com","email_verified":false,"name":"bob@example.com","nickname":"bob","passwordHash":"$2b$10$abcdefghijklmnopqrstuv"}
+not-json
+{"user_id":"google-oauth2|2","email":"carol@example.com","name":"Carol","blocked":true}`
+
+	records, rowErrors, err := importers.NewAuth0Source().Parse(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, rowErrors, 1)
+	assert.Equal(t, 2, rowErrors[0].Row)
+	require.Len(t, records, 2)
+	assert.Equal(t, "bob", records[0].Name)
+	require.NotNil(t, records[0].Password)
+	assert.Equal(t, importers.AlgorithmBcrypt, records[0].Password.Algorithm)
+	assert.Nil(t, records[1].Password)
+	assert.True(t, records[1].Disabled)
+}
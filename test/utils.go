@@ -10,7 +10,7 @@ import (
 	"testing"
 	"time"
 
-	"api-core/pkg/jwt"
+	"github.com/anhnq996/go-api-core/pkg/jwt"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -123,7 +123,7 @@ func AssertResponseContains(t *testing.T, w *httptest.ResponseRecorder, expected
 
 // CreateTestContext creates a test context with user ID
 func CreateTestContext(userID string) context.Context {
-	return context.WithValue(context.Background(), jwt.UserIDContextKey, userID)
+	return jwt.ContextWithClaims(context.Background(), &jwt.Claims{UserID: userID})
 }
 
 // WaitForCondition waits for a condition to be true
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	model "github.com/anhnq996/go-api-core/internal/models"
+	"github.com/anhnq996/go-api-core/pkg/utils"
+
+	"github.com/glebarez/sqlite"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type sortableIDRecord struct {
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name string
+}
+
+func (sortableIDRecord) IDVersion() int { return utils.UUIDv7 }
+
+type randomIDRecord struct {
+	ID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Name string
+}
+
+func setupIDGeneratorDB(t *testing.T, cfg model.IDConfig) *gorm.DB {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	require.NoError(t, err)
+	require.NoError(t, model.RegisterIDGenerator(db, cfg))
+	require.NoError(t, db.AutoMigrate(&sortableIDRecord{}, &randomIDRecord{}))
+	return db
+}
+
+func TestUUIDv7Helpers(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := utils.NewUUIDv7()
+
+	assert.True(t, utils.IsUUIDv7(id))
+	createdAt, ok := utils.UUIDTime(id)
+	require.True(t, ok)
+	assert.False(t, createdAt.Before(before))
+
+	_, ok = utils.UUIDTime(uuid.New())
+	assert.False(t, ok, "v4 không chứa timestamp")
+	assert.Equal(t, uuid.Version(4), utils.NewUUID(utils.UUIDv4).Version())
+}
+
+func TestIDGeneratorPerModel(t *testing.T) {
+	db := setupIDGeneratorDB(t, model.IDConfig{})
+
+	// Model khai báo IDVersion() = 7
+	records := []sortableIDRecord{{Name: "a"}, {Name: "b"}}
+	require.NoError(t, db.Create(&records).Error)
+	for _, r := range records {
+		assert.True(t, utils.IsUUIDv7(r.ID))
+	}
+
+	// ID gán sẵn (v4 cũ) được giữ nguyên
+	legacyID := uuid.New()
+	legacy := sortableIDRecord{ID: legacyID, Name: "legacy"}
+	require.NoError(t, db.Create(&legacy).Error)
+	assert.Equal(t, legacyID, legacy.ID)
+
+	// Model không khai báo để database sinh ID như cũ
+	random := randomIDRecord{Name: "c"}
+	require.NoError(t, db.Create(&random).Error)
+	assert.Equal(t, uuid.Nil, random.ID)
+}
+
+func TestIDGeneratorTableOverride(t *testing.T) {
+	db := setupIDGeneratorDB(t, model.IDConfig{
+		DefaultVersion: utils.UUIDv7,
+		TableVersions:  map[string]int{"sortable_id_records": utils.UUIDv4},
+	})
+
+	random := randomIDRecord{Name: "a"}
+	require.NoError(t, db.Create(&random).Error)
+	assert.True(t, utils.IsUUIDv7(random.ID), "default version áp dụng cho model không khai báo")
+
+	sortable := sortableIDRecord{Name: "b"}
+	require.NoError(t, db.Create(&sortable).Error)
+	assert.Equal(t, uuid.Nil, sortable.ID, "override theo bảng ưu tiên hơn IDVersion() của model")
+}
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkerRateLimitAndMaxInFlight(t *testing.T) {
+	ctx := context.Background()
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 4, RetryDelay: time.Millisecond})
+
+	var mu sync.Mutex
+	var inFlight, maxSeen int
+	var handledAt []time.Time
+	manager.RegisterHandler("email.send", func(ctx context.Context, message *queue.Message) error {
+		mu.Lock()
+		inFlight++
+		maxSeen = max(maxSeen, inFlight)
+		handledAt = append(handledAt, time.Now())
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil
+	}, workers.OnQueue("emails"), workers.WithRateLimit(20, 1), workers.WithMaxInFlight(2))
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	start := time.Now()
+	for i := 0; i < 6; i++ {
+		message, err := queue.NewJobMessage("email.send", i)
+		require.NoError(t, err)
+		require.NoError(t, queues.queues["emails"].Push(ctx, message))
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handledAt) == 6
+	}, 3*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.LessOrEqual(t, maxSeen, 2, "không vượt max in-flight dù concurrency 4")
+	// 20 message/giây, burst 1: 6 message cần ít nhất 5 khoảng 50ms
+	assert.GreaterOrEqual(t, handledAt[5].Sub(start), 240*time.Millisecond)
+}
+
+func TestWorkerSharedLimiter(t *testing.T) {
+	ctx := context.Background()
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 2, RetryDelay: time.Millisecond})
+
+	// 2 queue gọi cùng provider: tổng in-flight tối đa 1
+	limiter := queue.NewLimiter(0, 0, 1)
+	var mu sync.Mutex
+	var inFlight, maxSeen, handled int
+	handler := func(ctx context.Context, message *queue.Message) error {
+		mu.Lock()
+		inFlight++
+		maxSeen = max(maxSeen, inFlight)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		inFlight--
+		handled++
+		mu.Unlock()
+		return nil
+	}
+	manager.RegisterHandler("push.send", handler, workers.OnQueue("push"), workers.WithLimiter(limiter))
+	manager.RegisterHandler("push.broadcast", handler, workers.OnQueue("push-bulk"), workers.WithLimiter(limiter))
+	require.NoError(t, manager.Start(ctx))
+	defer manager.Stop()
+
+	for i := 0; i < 3; i++ {
+		single, _ := queue.NewJobMessage("push.send", i)
+		bulk, _ := queue.NewJobMessage("push.broadcast", i)
+		require.NoError(t, queues.queues["push"].Push(ctx, single))
+		require.NoError(t, queues.queues["push-bulk"].Push(ctx, bulk))
+	}
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return handled == 6
+	}, 5*time.Second, 10*time.Millisecond)
+	mu.Lock()
+	assert.Equal(t, 1, maxSeen)
+	mu.Unlock()
+	assert.Equal(t, "rate unlimited, in-flight 1", limiter.String())
+}
+
+func TestLoadQueueRateLimit(t *testing.T) {
+	t.Setenv("WORKER_QUEUE_RATE_LIMIT", "emails:10, reports:0.5,bad,zero:0")
+	t.Setenv("WORKER_QUEUE_MAX_IN_FLIGHT", "emails:2,bad:x")
+	cfg := config.LoadQueueConfig()
+	assert.Equal(t, map[string]float64{"emails": 10, "reports": 0.5}, cfg.QueueRateLimit)
+	assert.Equal(t, map[string]int{"emails": 2}, cfg.QueueMaxInFlight)
+}
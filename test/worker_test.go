@@ -0,0 +1,329 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anhnq996/go-api-core/internal/app/device"
+	"github.com/anhnq996/go-api-core/internal/config"
+	"github.com/anhnq996/go-api-core/internal/workers"
+	"github.com/anhnq996/go-api-core/pkg/email"
+	"github.com/anhnq996/go-api-core/pkg/queue"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadAppRole(t *testing.T) {
+	t.Setenv("APP_ROLE", "")
+	role, err := config.LoadAppRole()
+	require.NoError(t, err)
+	assert.Equal(t, config.AppRoleAll, role)
+	assert.True(t, role.RunsAPI() && role.RunsWorker() && role.RunsScheduler())
+
+	t.Setenv("APP_ROLE", " Worker ")
+	role, err = config.LoadAppRole()
+	require.NoError(t, err)
+	assert.Equal(t, config.AppRoleWorker, role)
+	assert.False(t, role.RunsAPI())
+	assert.True(t, role.RunsWorker())
+	assert.False(t, role.RunsScheduler())
+
+	t.Setenv("APP_ROLE", "scheduler")
+	role, err = config.LoadAppRole()
+	require.NoError(t, err)
+	assert.True(t, role.RunsScheduler())
+	assert.False(t, role.RunsAPI() || role.RunsWorker())
+
+	t.Setenv("APP_ROLE", "cron")
+	_, err = config.LoadAppRole()
+	assert.Error(t, err)
+}
+
+// chanQueue queue in-memory cho test
+type chanQueue struct {
+	name     string
+	messages chan *queue.Message
+}
+
+func (q *chanQueue) Push(ctx context.Context, m *queue.Message) error { q.messages <- m; return nil }
+func (q *chanQueue) PushBatch(ctx context.Context, messages []*queue.Message) error {
+	for _, m := range messages {
+		q.messages <- m
+	}
+	return nil
+}
+func (q *chanQueue) PopBatch(ctx context.Context, max int) ([]*queue.Message, error) {
+	var messages []*queue.Message
+	for len(messages) < max {
+		select {
+		case m := <-q.messages:
+			messages = append(messages, m)
+		default:
+			return messages, nil
+		}
+	}
+	return messages, nil
+}
+func (q *chanQueue) Pop(ctx context.Context) (*queue.Message, error) {
+	return q.PopWithTimeout(ctx, time.Second)
+}
+func (q *chanQueue) PopWithTimeout(ctx context.Context, timeout time.Duration) (*queue.Message, error) {
+	select {
+	case m := <-q.messages:
+		return m, nil
+	case <-time.After(timeout):
+		return nil, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+func (q *chanQueue) Peek(ctx context.Context) (*queue.Message, error) { return nil, nil }
+func (q *chanQueue) Size(ctx context.Context) (int64, error)          { return int64(len(q.messages)), nil }
+func (q *chanQueue) Clear(ctx context.Context) error                  { return nil }
+func (q *chanQueue) Close() error                                     { return nil }
+func (q *chanQueue) GetName() string                                  { return q.name }
+
+type chanQueueManager struct {
+	queues map[string]*chanQueue
+	closed bool
+}
+
+func (m *chanQueueManager) CreateQueue(ctx context.Context, name string, options *queue.QueueOptions) (queue.Queue, error) {
+	if q, ok := m.queues[name]; ok {
+		return q, nil
+	}
+	q := &chanQueue{name: name, messages: make(chan *queue.Message, 10)}
+	m.queues[name] = q
+	return q, nil
+}
+func (m *chanQueueManager) GetQueue(name string) (queue.Queue, error)          { return m.queues[name], nil }
+func (m *chanQueueManager) DeleteQueue(ctx context.Context, name string) error { return nil }
+func (m *chanQueueManager) ListQueues(ctx context.Context) ([]string, error)   { return nil, nil }
+func (m *chanQueueManager) Close() error                                       { m.closed = true; return nil }
+
+// recordingEmailService ghi lại email đã gửi, lỗi ở failures lần gửi đầu
+type recordingEmailService struct {
+	mu       sync.Mutex
+	sent     []*email.EmailMessage
+	attempts int
+	failures int
+}
+
+func (s *recordingEmailService) Send(message *email.EmailMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("smtp unavailable")
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func (s *recordingEmailService) SendTemplate(message *email.EmailMessage, templatePath string, data interface{}) error {
+	return s.Send(message)
+}
+
+func (s *recordingEmailService) snapshot() (int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sent), s.attempts
+}
+
+func TestWorkerManagerEmailQueue(t *testing.T) {
+	mailer := &recordingEmailService{failures: 1}
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, MaxRetries: 2, RetryDelay: 10 * time.Millisecond})
+	manager.RegisterAllHandlers(workers.NewHandlers(workers.NewEmailHandler(mailer), workers.NewPushHandler(nil), workers.NewSMSHandler(nil)))
+	assert.Equal(t, []string{workers.QueueEmails, device.QueueNotifications, workers.QueueSMS}, manager.Queues())
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+
+	q, err := queues.GetQueue(workers.QueueEmails)
+	require.NoError(t, err)
+
+	// Message không hợp lệ bị bỏ qua, không retry
+	require.NoError(t, q.Push(ctx, &queue.Message{ID: "bad", Data: []byte("not-json")}))
+
+	// Lỗi SMTP tạm thời được retry
+	data, err := json.Marshal(email.EmailMessage{To: []string{"jane@example.com"}, Subject: "Welcome"})
+	require.NoError(t, err)
+	require.NoError(t, q.Push(ctx, &queue.Message{ID: "welcome", Data: data}))
+
+	assert.Eventually(t, func() bool {
+		sent, _ := mailer.snapshot()
+		return sent == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, manager.Stop())
+	assert.True(t, queues.closed)
+
+	sent, attempts := mailer.snapshot()
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "Welcome", mailer.sent[0].Subject)
+}
+
+func TestWorkerManagerDispatchesJobTypes(t *testing.T) {
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, MaxRetries: 3, RetryDelay: time.Millisecond})
+
+	var mu sync.Mutex
+	var handled []string
+	record := func(name string) queue.HandlerFunc {
+		return func(ctx context.Context, message *queue.Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			handled = append(handled, name+":"+string(message.Data))
+			return nil
+		}
+	}
+	var panics int
+	manager.RegisterHandler("email.send", record("email"), workers.OnQueue("mail"))
+	manager.RegisterHandler("report.export", record("report"))
+	manager.RegisterHandler("report.crash", func(ctx context.Context, message *queue.Message) error {
+		mu.Lock()
+		panics++
+		mu.Unlock()
+		panic("boom")
+	})
+	assert.ElementsMatch(t, []string{"mail", workers.DefaultQueue}, manager.Queues())
+	assert.Panics(t, func() { manager.RegisterHandler("email.send", record("dup"), workers.OnQueue("mail")) })
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+
+	push := func(queueName, jobType string, payload interface{}) {
+		message, err := queue.NewJobMessage(jobType, payload)
+		require.NoError(t, err)
+		require.NoError(t, queues.queues[queueName].Push(ctx, message))
+	}
+	// Panic và loại job lạ không làm chết worker, không retry
+	push(workers.DefaultQueue, "report.crash", 1)
+	push(workers.DefaultQueue, "unknown", 2)
+	push(workers.DefaultQueue, "report.export", 3)
+	push("mail", "email.send", "hi")
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(handled) == 2
+	}, 2*time.Second, 10*time.Millisecond)
+	require.NoError(t, manager.Stop())
+
+	assert.ElementsMatch(t, []string{"report:3", `email:"hi"`}, handled)
+	assert.Equal(t, 1, panics)
+}
+
+func TestDispatcherRejectsUnknownJobType(t *testing.T) {
+	dispatcher := queue.NewDispatcher()
+	dispatcher.RegisterHandler("b", func(ctx context.Context, message *queue.Message) error { return nil })
+	dispatcher.RegisterHandler("a", func(ctx context.Context, message *queue.Message) error { return nil })
+	assert.Equal(t, []string{"a", "b"}, dispatcher.JobTypes())
+
+	err := dispatcher.Handle(context.Background(), &queue.Message{Headers: map[string]string{queue.HeaderJobType: "c"}})
+	assert.ErrorIs(t, err, queue.ErrUnknownJobType)
+	assert.True(t, queue.IsPermanent(err))
+}
+
+func TestWorkerManagerConcurrencyAndGracefulStop(t *testing.T) {
+	queues := &chanQueueManager{queues: map[string]*chanQueue{}}
+	manager := workers.NewWorkerManager(queues, &queue.ConsumerOptions{Concurrency: 1, MaxRetries: 1, HandlerTimeout: time.Second})
+
+	var mu sync.Mutex
+	var running, peak, done int
+	release := make(chan struct{})
+	slow := func(ctx context.Context, message *queue.Message) error {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		select {
+		case <-release:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		mu.Lock()
+		running--
+		done++
+		mu.Unlock()
+		return nil
+	}
+	manager.RegisterHandler("slow", slow, workers.OnQueue("reports"), workers.WithConcurrency(2))
+	manager.RegisterHandler("idle", slow, workers.OnQueue("idle"))
+	manager.SetConcurrency("reports", 3)
+	manager.Only("reports")
+
+	ctx := context.Background()
+	require.NoError(t, manager.Start(ctx))
+	assert.Equal(t, []string{"reports"}, manager.RunningQueues())
+
+	for i := 0; i < 3; i++ {
+		message, err := queue.NewJobMessage("slow", i)
+		require.NoError(t, err)
+		require.NoError(t, queues.queues["reports"].Push(ctx, message))
+	}
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return peak == 3
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Stop chờ message đang xử lý hoàn tất thay vì hủy context của handler
+	stopped := make(chan error)
+	go func() { stopped <- manager.Stop() }()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	require.NoError(t, <-stopped)
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, done)
+
+	unknown := workers.NewWorkerManager(&chanQueueManager{queues: map[string]*chanQueue{}}, nil)
+	unknown.RegisterHandler("slow", slow)
+	unknown.Only("reports")
+	assert.Error(t, unknown.Start(ctx))
+}
+
+func TestLoadQueueConcurrency(t *testing.T) {
+	t.Setenv("WORKER_QUEUE_CONCURRENCY", "emails:8, default:2,bad,zero:0")
+	cfg := config.LoadQueueConfig()
+	assert.Equal(t, map[string]int{"emails": 8, "default": 2}, cfg.QueueConcurrency)
+	assert.Equal(t, 30*time.Second, cfg.HandlerTimeout)
+	assert.Equal(t, cfg.HandlerTimeout, cfg.ToConsumerOptions().HandlerTimeout)
+}
+
+func TestLoadQueueRetryBackoff(t *testing.T) {
+	cfg := config.LoadQueueConfig()
+	require.NoError(t, cfg.Validate())
+	options := cfg.ToConsumerOptions()
+	assert.Equal(t, queue.Backoff{Curve: queue.BackoffExponential, Max: 10 * time.Minute, Jitter: true}, options.RetryBackoff)
+	assert.True(t, options.DelayedRetry)
+
+	t.Setenv("WORKER_RETRY_BACKOFF", "fibonacci")
+	assert.Error(t, config.LoadQueueConfig().Validate())
+}
+
+func TestLoadQueueRabbitMQDelay(t *testing.T) {
+	t.Setenv("QUEUE_DRIVER", "rabbitmq")
+	t.Setenv("QUEUE_PORT", "5672")
+
+	cfg := config.LoadQueueConfig()
+	require.NoError(t, cfg.Validate())
+	assert.Equal(t, queue.RabbitMQDelayAuto, cfg.ToQueueConfig().RabbitMQDelay)
+
+	t.Setenv("QUEUE_RABBITMQ_DELAY", "ttl")
+	require.NoError(t, config.LoadQueueConfig().Validate())
+
+	t.Setenv("QUEUE_RABBITMQ_DELAY", "sleep")
+	assert.Error(t, config.LoadQueueConfig().Validate())
+}